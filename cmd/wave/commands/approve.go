@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/spf13/cobra"
+)
+
+const approvalsDBPath = ".agents/state.db"
+
+// ApproveOptions holds options for the approve command.
+type ApproveOptions struct {
+	RunID  string
+	StepID string
+	Reject bool
+	Reason string
+}
+
+// NewApproveCmd creates the approve command.
+func NewApproveCmd() *cobra.Command {
+	var opts ApproveOptions
+
+	cmd := &cobra.Command{
+		Use:   "approve <run-id> <step-id>",
+		Short: "Approve or reject a pipeline paused at an approval gate",
+		Long: `Decide an approval gate a running pipeline is waiting at.
+
+An approval gate without choices pauses its run in the waiting_approval
+state until it is decided here. Approving lets the run continue; rejecting
+fails the gate step. A gate nobody decides before its timeout is rejected
+automatically.`,
+		Example: `  wave approve impl-issue-20261016-101500-ab12 review-plan
+  wave approve impl-issue-20261016-101500-ab12 review-plan --reject --reason "plan skips the migration"`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			opts.RunID, opts.StepID = args[0], args[1]
+			return runApprove(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Reject, "reject", false, "Reject the gate instead of approving it")
+	cmd.Flags().StringVar(&opts.Reason, "reason", "", "Note recorded with the decision")
+
+	return cmd
+}
+
+func runApprove(opts ApproveOptions) error {
+	if _, err := os.Stat(approvalsDBPath); os.IsNotExist(err) {
+		return NewCLIError(CodeStateDBError,
+			fmt.Sprintf("state database not found: %s", approvalsDBPath),
+			"Run wave approve from the project the pipeline runs in")
+	}
+	store, err := state.NewStateStore(approvalsDBPath)
+	if err != nil {
+		return NewCLIError(CodeStateDBError,
+			fmt.Sprintf("failed to open state database: %s", err),
+			"Check .agents/state.db file permissions").WithCause(err)
+	}
+	defer store.Close()
+
+	rec, err := store.GetApproval(opts.RunID, opts.StepID)
+	if err != nil {
+		return NewCLIError(CodeInternalError, err.Error(), "").WithCause(err)
+	}
+	if rec == nil {
+		return NewCLIError(CodeRunNotFound,
+			fmt.Sprintf("step %q of run %s has no approval gate waiting", opts.StepID, opts.RunID),
+			"Check the run id and step id with 'wave status "+opts.RunID+"'")
+	}
+
+	decidedBy := strings.TrimSpace(os.Getenv("USER"))
+	if decidedBy == "" {
+		decidedBy = "cli"
+	}
+	err = store.DecideApproval(opts.RunID, opts.StepID, !opts.Reject, decidedBy, strings.TrimSpace(opts.Reason))
+	if errors.Is(err, state.ErrApprovalNotWaiting) {
+		if rec, _ = store.GetApproval(opts.RunID, opts.StepID); rec != nil {
+			return NewCLIError(CodeValidationFailed,
+				fmt.Sprintf("step %q of run %s was already %s by %s", opts.StepID, opts.RunID, rec.Status, rec.DecidedBy), "")
+		}
+	}
+	if err != nil {
+		return NewCLIError(CodeInternalError, err.Error(), "").WithCause(err)
+	}
+
+	if opts.Reject {
+		fmt.Printf("Rejected step %s of run %s; the gate step will fail\n", opts.StepID, opts.RunID)
+	} else {
+		fmt.Printf("Approved step %s of run %s; the run resumes shortly\n", opts.StepID, opts.RunID)
+	}
+	return nil
+}
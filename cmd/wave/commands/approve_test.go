@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunApprove(t *testing.T) {
+	h := newProposalsTestHelper(t)
+	t.Setenv("USER", "alice")
+
+	store := h.openStore()
+	runID, err := store.CreateRun("gated", "test")
+	require.NoError(t, err)
+	require.NoError(t, store.RequestApproval(runID, "review", "Ship it?", time.Now().Add(time.Hour)))
+	store.Close()
+
+	err = runApprove(ApproveOptions{RunID: runID, StepID: "missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `step "missing" of run `+runID+" has no approval gate waiting")
+
+	require.NoError(t, runApprove(ApproveOptions{RunID: runID, StepID: "review", Reject: true, Reason: "not yet"}))
+
+	store = h.openStore()
+	rec, err := store.GetApproval(runID, "review")
+	store.Close()
+	require.NoError(t, err)
+	assert.Equal(t, state.ApprovalRejected, rec.Status)
+	assert.Equal(t, "alice", rec.DecidedBy)
+	assert.Equal(t, "not yet", rec.Reason)
+
+	err = runApprove(ApproveOptions{RunID: runID, StepID: "review"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "was already rejected by alice")
+}
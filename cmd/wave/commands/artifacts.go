@@ -62,7 +62,8 @@ With a run-id argument, shows artifacts from that specific run.
 
 Use --step to filter artifacts to a specific step.
 Use --export to copy artifacts to a specified directory.
-Use --format json for machine-readable output.`,
+Use --format json for machine-readable output.
+Use 'wave artifacts show <artifact>' for one artifact's type, checksum and preview.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
@@ -73,6 +74,8 @@ Use --format json for machine-readable output.`,
 		},
 	}
 
+	cmd.AddCommand(newArtifactsShowCmd())
+
 	cmd.Flags().StringVar(&opts.Step, "step", "", "Filter to specific step ID")
 	cmd.Flags().StringVar(&opts.Export, "export", "", "Export artifacts to specified directory")
 	cmd.Flags().StringVar(&opts.Format, "format", "table", "Output format (table, json)")
@@ -0,0 +1,201 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/pipeline"
+	"github.com/recinq/wave/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// ArtifactsShowOptions holds options for the artifacts show command.
+type ArtifactsShowOptions struct {
+	RunID  string
+	Step   string
+	Format string
+}
+
+// ArtifactShowOutput is the detail view of a single artifact.
+type ArtifactShowOutput struct {
+	RunID     string         `json:"run_id"`
+	Step      string         `json:"step"`
+	Name      string         `json:"name"`
+	Type      string         `json:"type"`
+	Path      string         `json:"path"`
+	Size      int64          `json:"size_bytes"`
+	Exists    bool           `json:"exists"`
+	MimeType  string         `json:"mime_type,omitempty"`
+	Encoding  string         `json:"encoding,omitempty"`
+	Preview   string         `json:"preview,omitempty"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	IndexedAt *time.Time     `json:"indexed_at,omitempty"`
+}
+
+// newArtifactsShowCmd creates the artifacts show subcommand.
+func newArtifactsShowCmd() *cobra.Command {
+	var opts ArtifactsShowOptions
+
+	cmd := &cobra.Command{
+		Use:   "show <artifact>",
+		Short: "Show an artifact's type, checksum and preview",
+		Long: `Show the metadata indexed for an artifact when it was registered: MIME
+type, encoding, SHA-256 checksum, size, and a preview (leading lines, top-level
+JSON keys, or image dimensions). The artifact file is not opened.
+
+Without --run, the most recent run is used. Use --step when several steps
+produce an artifact with the same name.`,
+		Example: `  wave artifacts show plan
+  wave artifacts show plan --run impl-issue-20260101-120000 --step navigate
+  wave artifacts show plan --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Format = ResolveFormat(cmd, opts.Format)
+			return runArtifactsShow(args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.RunID, "run", "", "Run ID (default: most recent run)")
+	cmd.Flags().StringVar(&opts.Step, "step", "", "Step that produced the artifact")
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "Output format (text, json)")
+
+	return cmd
+}
+
+func runArtifactsShow(name string, opts ArtifactsShowOptions) error {
+	dbPath := ".agents/state.db"
+	if _, err := os.Stat(dbPath); err != nil {
+		return NewCLIError(CodeStateDBError, "no state database found", "Run 'wave run' to create it")
+	}
+	store, err := state.NewReadOnlyStateStore(dbPath)
+	if err != nil {
+		return NewCLIError(CodeStateDBError, fmt.Sprintf("failed to open state database: %s", err), "Check .agents/state.db file permissions").WithCause(err)
+	}
+	defer store.Close()
+
+	out, err := buildArtifactShow(store, name, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.Format == "json" {
+		jsonBytes, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to marshal JSON: %s", err), "This is an internal serialization error").WithCause(err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+	printArtifactShow(out)
+	return nil
+}
+
+// buildArtifactShow looks up the artifact and its indexed metadata. When the
+// artifact was registered more than once (step retries), the latest
+// registration wins.
+func buildArtifactShow(store state.StateStore, name string, opts ArtifactsShowOptions) (*ArtifactShowOutput, error) {
+	runID := opts.RunID
+	if runID == "" {
+		runs, err := store.ListRuns(state.ListRunsOptions{Limit: 1})
+		if err != nil || len(runs) == 0 {
+			return nil, NewCLIError(CodeRunNotFound, "no runs found", "Run a pipeline first with 'wave run'")
+		}
+		runID = runs[0].RunID
+	}
+
+	records, err := store.GetArtifacts(runID, opts.Step)
+	if err != nil {
+		return nil, NewCLIError(CodeStateDBError, fmt.Sprintf("failed to query artifacts: %s", err), "The state database may need migration -- try 'wave migrate up'").WithCause(err)
+	}
+	var found *state.ArtifactRecord
+	for i := range records {
+		if records[i].Name == name {
+			found = &records[i]
+		}
+	}
+	if found == nil {
+		return nil, NewCLIError(CodeInvalidArgs,
+			fmt.Sprintf("artifact '%s' not found in run %s", name, runID),
+			"Run 'wave artifacts "+runID+"' to list its artifacts")
+	}
+
+	_, statErr := os.Stat(found.Path)
+	out := &ArtifactShowOutput{
+		RunID:  runID,
+		Step:   found.StepID,
+		Name:   found.Name,
+		Type:   found.Type,
+		Path:   found.Path,
+		Size:   found.SizeBytes,
+		Exists: statErr == nil,
+	}
+	if meta, err := store.GetArtifactMetadata(found.ID); err == nil && meta != nil {
+		out.MimeType = meta.MimeType
+		out.Encoding = meta.Encoding
+		out.Preview = meta.PreviewText
+		indexedAt := meta.IndexedAt
+		out.IndexedAt = &indexedAt
+		if meta.MetadataJSON != "" {
+			_ = json.Unmarshal([]byte(meta.MetadataJSON), &out.Metadata)
+		}
+	}
+	return out, nil
+}
+
+func printArtifactShow(a *ArtifactShowOutput) {
+	fmt.Printf("Artifact:  %s (step %s, run %s)\n", a.Name, a.Step, a.RunID)
+	status := ""
+	if !a.Exists {
+		status = " [missing]"
+	}
+	fmt.Printf("Path:      %s%s\n", a.Path, status)
+	if a.Type != "" {
+		fmt.Printf("Type:      %s\n", a.Type)
+	}
+	fmt.Printf("Size:      %s\n", formatSize(a.Size))
+
+	if a.IndexedAt == nil {
+		fmt.Println("\nNot indexed (registered before artifact indexing was available)")
+		return
+	}
+	fmt.Printf("MIME:      %s\n", a.MimeType)
+	if a.Encoding != "" {
+		fmt.Printf("Encoding:  %s\n", a.Encoding)
+	}
+	if sum, ok := a.Metadata["sha256"].(string); ok {
+		fmt.Printf("SHA-256:   %s\n", sum)
+	}
+	if keys, ok := a.Metadata["json_keys"].([]any); ok && len(keys) > 0 {
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = fmt.Sprint(k)
+		}
+		fmt.Printf("JSON keys: %s\n", strings.Join(names, ", "))
+	}
+	if img, ok := a.Metadata["image"].(map[string]any); ok {
+		fmt.Printf("Image:     %vx%v\n", img["width"], img["height"])
+	}
+	if a.Type == pipeline.ArtifactTypeDirectory {
+		fmt.Printf("Contents:  %d files, %s uncompressed\n", metadataInt(a.Metadata, "files"), formatSize(metadataInt(a.Metadata, "bytes")))
+	}
+	if truncated, _ := a.Metadata["truncated"].(bool); truncated {
+		kept := metadataInt(a.Metadata, "head_bytes") + metadataInt(a.Metadata, "tail_bytes")
+		fmt.Printf("Truncated: %d of %d stdout bytes kept\n", kept, metadataInt(a.Metadata, "original_bytes"))
+	}
+
+	if a.Preview != "" {
+		fmt.Println("\nPreview:")
+		for _, line := range strings.Split(a.Preview, "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+}
+
+// metadataInt reads a numeric metadata field decoded from JSON.
+func metadataInt(m map[string]any, key string) int64 {
+	v, _ := m[key].(float64)
+	return int64(v)
+}
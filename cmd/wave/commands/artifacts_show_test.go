@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildArtifactShow(t *testing.T) {
+	dir := t.TempDir()
+	store, err := state.NewStateStore(filepath.Join(dir, "state.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	artifactPath := filepath.Join(dir, "plan.json")
+	require.NoError(t, os.WriteFile(artifactPath, []byte(`{"steps": []}`), 0644))
+
+	runID, err := store.CreateRun("impl-issue", "input")
+	require.NoError(t, err)
+	require.NoError(t, store.RegisterArtifact(runID, "navigate", "plan", artifactPath, "json", 13))
+	records, err := store.GetArtifacts(runID, "navigate")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.NoError(t, store.SaveArtifactMetadata(records[0].ID, runID, "navigate",
+		`{"steps": []}`, "application/json", "utf-8", `{"sha256":"abc","json_keys":["steps"]}`))
+
+	t.Run("latest run by default", func(t *testing.T) {
+		out, err := buildArtifactShow(store, "plan", ArtifactsShowOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, runID, out.RunID)
+		assert.Equal(t, "navigate", out.Step)
+		assert.True(t, out.Exists)
+		assert.Equal(t, "application/json", out.MimeType)
+		assert.Equal(t, `{"steps": []}`, out.Preview)
+		assert.Equal(t, "abc", out.Metadata["sha256"])
+		require.NotNil(t, out.IndexedAt)
+	})
+
+	t.Run("unknown artifact", func(t *testing.T) {
+		_, err := buildArtifactShow(store, "missing", ArtifactsShowOptions{RunID: runID})
+		var cliErr *CLIError
+		require.ErrorAs(t, err, &cliErr)
+		assert.Equal(t, CodeInvalidArgs, cliErr.Code)
+	})
+
+	t.Run("wrong step", func(t *testing.T) {
+		_, err := buildArtifactShow(store, "plan", ArtifactsShowOptions{RunID: runID, Step: "implement"})
+		assert.Error(t, err)
+	})
+}
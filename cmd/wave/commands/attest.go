@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/recinq/wave/internal/attest"
+	"github.com/spf13/cobra"
+)
+
+// AttestOptions holds options shared by the attest subcommands.
+type AttestOptions struct {
+	Key      string
+	Format   string
+	Manifest string
+}
+
+// AttestVerifyOutput is the JSON output of attest verify.
+type AttestVerifyOutput struct {
+	Path       string   `json:"path"`
+	RunID      string   `json:"run_id"`
+	Pipeline   string   `json:"pipeline"`
+	Status     string   `json:"status"`
+	Signed     bool     `json:"signed"`
+	KeyIDs     []string `json:"key_ids,omitempty"`
+	Verified   bool     `json:"verified"`
+	Subjects   int      `json:"subjects"`
+	Byproducts int      `json:"byproducts"`
+}
+
+// NewAttestCmd creates the attest parent command with subcommands.
+func NewAttestCmd() *cobra.Command {
+	var opts AttestOptions
+
+	cmd := &cobra.Command{
+		Use:   "attest",
+		Short: "Inspect and verify run provenance attestations",
+		Long: `Inspect and verify the provenance attestations written for pipeline runs.
+
+With runtime.attestation.enabled, every top-level run writes an in-toto
+statement with a SLSA v1 provenance predicate to
+<attestation dir>/<run-id>.intoto.json, wrapped in a DSSE envelope. It
+records the digests of wave.yaml, the pipeline YAML and persona prompts,
+the adapter, adapter version and model of every step, the run input, and
+the branches, files, PRs and issues the run produced.
+
+Set runtime.attestation.signing_key to an ed25519 private key to sign it:
+
+  openssl genpkey -algorithm ed25519 -out .agents/attest.key
+  openssl pkey -in .agents/attest.key -pubout -out attest.pub
+
+Subcommands:
+  show     Print the decoded statement
+  verify   Check the envelope and, with --key, its signature`,
+	}
+
+	cmd.PersistentFlags().StringVar(&opts.Manifest, "manifest", "wave.yaml", "Path to manifest file")
+	cmd.AddCommand(newAttestShowCmd(&opts))
+	cmd.AddCommand(newAttestVerifyCmd(&opts))
+
+	return cmd
+}
+
+func newAttestShowCmd(opts *AttestOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <run-id|file>",
+		Short: "Print the decoded attestation statement",
+		Example: `  wave attest show impl-issue-20260101-120000-a1b2
+  wave attest show .agents/attestations/impl-issue-20260101-120000-a1b2.intoto.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAttestShow(args[0], *opts)
+		},
+	}
+}
+
+func newAttestVerifyCmd(opts *AttestOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <run-id|file>",
+		Short: "Verify an attestation envelope and its signature",
+		Example: `  wave attest verify impl-issue-20260101-120000-a1b2 --key attest.pub
+  wave attest verify attestation.intoto.json --key attest.pub --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Format = ResolveFormat(cmd, opts.Format)
+			return runAttestVerify(args[0], *opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Key, "key", "", "ed25519 public key (PEM) to verify the signature against")
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "Output format (text, json)")
+	return cmd
+}
+
+// resolveAttestation maps a run ID to its attestation file; an existing
+// file path is used as is.
+func resolveAttestation(ref, manifestPath string) string {
+	if info, err := os.Stat(ref); err == nil && !info.IsDir() {
+		return ref
+	}
+	dir := ".agents/attestations"
+	if m, err := loadManifestStrict(manifestPath); err == nil {
+		dir = m.Runtime.Attestation.GetDir()
+	}
+	return attest.Path(dir, filepath.Base(ref))
+}
+
+func loadAttestation(ref, manifestPath string) (string, *attest.Envelope, *attest.Statement, error) {
+	path := resolveAttestation(ref, manifestPath)
+	env, err := attest.Read(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil, nil, NewCLIError(CodeRunNotFound, fmt.Sprintf("no attestation found for %s", ref),
+				"Enable runtime.attestation in wave.yaml; attestations are written when a run finishes").WithCause(err)
+		}
+		return "", nil, nil, NewCLIError(CodeValidationFailed, err.Error(), "The file is not a DSSE attestation envelope").WithCause(err)
+	}
+	stmt, err := env.Statement()
+	if err != nil {
+		return "", nil, nil, NewCLIError(CodeValidationFailed, fmt.Sprintf("%s: %s", path, err), "The attestation payload is not an in-toto statement").WithCause(err)
+	}
+	return path, env, stmt, nil
+}
+
+func runAttestShow(ref string, opts AttestOptions) error {
+	_, _, stmt, err := loadAttestation(ref, opts.Manifest)
+	if err != nil {
+		return err
+	}
+	jsonBytes, err := json.MarshalIndent(stmt, "", "  ")
+	if err != nil {
+		return NewCLIError(CodeInternalError, fmt.Sprintf("failed to marshal JSON: %s", err), "This is an internal serialization error").WithCause(err)
+	}
+	fmt.Println(string(jsonBytes))
+	return nil
+}
+
+func runAttestVerify(ref string, opts AttestOptions) error {
+	path, env, stmt, err := loadAttestation(ref, opts.Manifest)
+	if err != nil {
+		return err
+	}
+
+	out := AttestVerifyOutput{
+		Path:       path,
+		RunID:      stmt.Predicate.RunDetails.Metadata.InvocationID,
+		Pipeline:   stmt.Predicate.BuildDefinition.ExternalParameters.Pipeline,
+		Status:     stmt.Predicate.BuildDefinition.InternalParameters.Status,
+		Signed:     len(env.Signatures) > 0,
+		Subjects:   len(stmt.Subject),
+		Byproducts: len(stmt.Predicate.RunDetails.Byproducts),
+	}
+	for _, s := range env.Signatures {
+		out.KeyIDs = append(out.KeyIDs, s.KeyID)
+	}
+
+	var verifyErr error
+	if opts.Key != "" {
+		pub, err := attest.LoadPublicKey(opts.Key)
+		if err != nil {
+			return NewCLIError(CodeInvalidArgs, err.Error(), "Pass a PEM ed25519 public key, e.g. from 'openssl pkey -pubout'").WithCause(err)
+		}
+		verifyErr = env.Verify(pub)
+		out.Verified = verifyErr == nil
+	}
+
+	if opts.Format == "json" {
+		jsonBytes, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to marshal JSON: %s", err), "This is an internal serialization error").WithCause(err)
+		}
+		fmt.Println(string(jsonBytes))
+	} else {
+		fmt.Printf("%s\n", path)
+		fmt.Printf("  run:       %s (%s, %s)\n", out.RunID, out.Pipeline, out.Status)
+		fmt.Printf("  subjects:  %d, byproducts: %d\n", out.Subjects, out.Byproducts)
+		switch {
+		case !out.Signed:
+			fmt.Println("  signature: none")
+		case opts.Key == "":
+			fmt.Printf("  signature: present (%d), not checked — pass --key to verify\n", len(env.Signatures))
+		case out.Verified:
+			fmt.Println("  signature: valid")
+		default:
+			fmt.Println("  signature: INVALID")
+		}
+	}
+
+	if verifyErr != nil {
+		return NewCLIError(CodeValidationFailed, fmt.Sprintf("attestation %s failed verification: %s", path, verifyErr),
+			"Check that --key is the public half of runtime.attestation.signing_key").WithCause(verifyErr)
+	}
+	return nil
+}
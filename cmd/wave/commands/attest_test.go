@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recinq/wave/internal/attest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSignedAttestation writes a signed attestation for run-1 under
+// .agents/attestations in dir and returns the public key path.
+func writeSignedAttestation(t *testing.T, dir string) string {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	pubPath := filepath.Join(dir, "attest.pub")
+	require.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0644))
+
+	env, err := attest.NewEnvelope(attest.NewStatement(attest.Run{RunID: "run-1", Pipeline: "impl-issue", Status: "completed"}))
+	require.NoError(t, err)
+	require.NoError(t, env.Sign(priv))
+	_, err = attest.Write(filepath.Join(dir, ".agents", "attestations"), "run-1", env)
+	require.NoError(t, err)
+	return pubPath
+}
+
+func TestRunAttestVerify(t *testing.T) {
+	dir := t.TempDir()
+	pubPath := writeSignedAttestation(t, dir)
+	origDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() { _ = os.Chdir(origDir) }()
+
+	out := captureOutput(t, func() {
+		require.NoError(t, runAttestVerify("run-1", AttestOptions{Key: pubPath, Format: "json", Manifest: "wave.yaml"}))
+	})
+	var got AttestVerifyOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	assert.True(t, got.Signed)
+	assert.True(t, got.Verified)
+	assert.Equal(t, "impl-issue", got.Pipeline)
+	assert.Len(t, got.KeyIDs, 1)
+
+	text := captureOutput(t, func() {
+		require.NoError(t, runAttestVerify(filepath.Join(".agents", "attestations", "run-1.intoto.json"), AttestOptions{Manifest: "wave.yaml"}))
+	})
+	assert.Contains(t, text, "pass --key to verify")
+
+	otherPub := writeSignedAttestation(t, t.TempDir())
+	var cliErr *CLIError
+	captureOutput(t, func() {
+		err = runAttestVerify("run-1", AttestOptions{Key: otherPub, Manifest: "wave.yaml"})
+	})
+	require.ErrorAs(t, err, &cliErr)
+	assert.Equal(t, CodeValidationFailed, cliErr.Code)
+
+	err = runAttestShow("run-2", AttestOptions{Manifest: "wave.yaml"})
+	require.ErrorAs(t, err, &cliErr)
+	assert.Equal(t, CodeRunNotFound, cliErr.Code)
+}
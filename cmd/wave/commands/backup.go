@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// BackupOptions holds options for the backup command.
+type BackupOptions struct {
+	To     string
+	Format string
+}
+
+// BackupReport is the result of `wave backup`.
+type BackupReport struct {
+	Source      string   `json:"source"`
+	Path        string   `json:"path"`
+	Size        int64    `json:"size"`
+	IntegrityOK bool     `json:"integrity_ok"`
+	Problems    []string `json:"problems,omitempty"`
+}
+
+// NewBackupCmd creates the backup command.
+func NewBackupCmd() *cobra.Command {
+	var opts BackupOptions
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the state database and check its integrity",
+		Long: `Write a consistent copy of .agents/state.db while runs may still be
+using it, and run PRAGMA integrity_check on the database and the copy.
+
+Without --to the copy goes to .agents/backups/state-<timestamp>.db. The
+command exits non-zero when the live database fails its integrity check;
+the copy is still kept so nothing recoverable is lost.
+
+A backup is also taken automatically before schema migrations are applied
+to an existing database (see WAVE_SKIP_MIGRATION_BACKUP).`,
+		Example: `  wave backup
+  wave backup --to /mnt/backups/wave-state.db
+  wave backup --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Format = ResolveFormat(cmd, opts.Format)
+			return runBackup(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.To, "to", "", "Backup file path (default .agents/backups/state-<timestamp>.db)")
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "Output format (text, json)")
+
+	return cmd
+}
+
+func runBackup(opts BackupOptions) error {
+	dbPath := getDbPath()
+	if _, err := os.Stat(dbPath); err != nil {
+		return NewCLIError(CodeStateDBError, "no state database at "+dbPath, "Run 'wave run' to create it").WithCause(err)
+	}
+
+	dest := opts.To
+	if dest == "" {
+		dest = filepath.Join(filepath.Dir(dbPath), "backups", "state-"+time.Now().UTC().Format("20060102T150405Z")+".db")
+	}
+
+	result, err := state.Backup(dbPath, dest)
+	if err != nil {
+		return NewCLIError(CodeStateDBError, fmt.Sprintf("backup failed: %s", err), "Pass --to with a path that does not exist yet on a writable disk").WithCause(err)
+	}
+
+	report := BackupReport{
+		Source:      dbPath,
+		Path:        result.Path,
+		Size:        result.Size,
+		IntegrityOK: len(result.SourceProblems) == 0,
+		Problems:    result.SourceProblems,
+	}
+
+	if opts.Format == "json" {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to marshal JSON: %s", err), "This is an internal serialization error").WithCause(err)
+		}
+		fmt.Println(string(jsonBytes))
+	} else {
+		fmt.Printf("Backed up %s to %s (%s)\n", report.Source, report.Path, formatSize(report.Size))
+		if report.IntegrityOK {
+			fmt.Println("Integrity check: ok")
+		} else {
+			fmt.Printf("Integrity check: %d problem(s)\n", len(report.Problems))
+			for _, p := range report.Problems {
+				fmt.Printf("  %s\n", p)
+			}
+		}
+	}
+
+	if !report.IntegrityOK {
+		return NewCLIError(CodeStateDBError, "state database failed integrity check",
+			fmt.Sprintf("The copy at %s holds what could be read; restore an earlier backup if it is incomplete", report.Path))
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunBackup(t *testing.T) {
+	orig, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(orig) })
+
+	err = runBackup(BackupOptions{Format: "text"})
+	var cliErr *CLIError
+	require.ErrorAs(t, err, &cliErr)
+	assert.Equal(t, CodeStateDBError, cliErr.Code)
+
+	require.NoError(t, os.MkdirAll(".agents", 0o755))
+	store, err := state.NewStateStore(".agents/state.db")
+	require.NoError(t, err)
+	store.Close()
+
+	out := captureOutput(t, func() {
+		require.NoError(t, runBackup(BackupOptions{Format: "json"}))
+	})
+	var report BackupReport
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	assert.True(t, report.IntegrityOK)
+	assert.Equal(t, filepath.Join(".agents", "backups"), filepath.Dir(report.Path))
+	assert.FileExists(t, report.Path)
+
+	dest := filepath.Join(t.TempDir(), "wave.db")
+	out = captureOutput(t, func() {
+		require.NoError(t, runBackup(BackupOptions{To: dest, Format: "text"}))
+	})
+	assert.Contains(t, out, "to "+dest)
+	assert.Contains(t, out, "Integrity check: ok")
+}
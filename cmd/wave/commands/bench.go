@@ -14,11 +14,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// NewBenchCmd creates the bench command group for SWE-bench benchmarking.
+// NewBenchCmd creates the bench command group for SWE-bench and model
+// benchmarking.
 func NewBenchCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "bench",
-		Short: "Run and analyze SWE-bench benchmarks",
+		Short: "Run and analyze SWE-bench and model benchmarks",
 		Long: `Run Wave pipelines against SWE-bench benchmark tasks and generate
 pass/fail reports. Useful for measuring pipeline quality and comparing
 different pipeline configurations.
@@ -27,17 +28,20 @@ Subcommands:
   run      Execute a pipeline against benchmark tasks
   report   Generate a summary from benchmark results
   list     List available benchmark datasets
-  compare  Compare two benchmark result files`,
+  compare  Compare two benchmark result files
+  models   Rank adapters/models on a benchmark pipeline`,
 		Example: `  wave bench run --dataset swe-bench-lite.jsonl --pipeline bench-solve
   wave bench report --results results.json
   wave bench compare --base baseline.json --compare wave-run.json
-  wave bench list`,
+  wave bench list
+  wave bench models --model claude:haiku --model claude:sonnet --runs 5`,
 	}
 
 	cmd.AddCommand(newBenchRunCmd())
 	cmd.AddCommand(newBenchReportCmd())
 	cmd.AddCommand(newBenchListCmd())
 	cmd.AddCommand(newBenchCompareCmd())
+	cmd.AddCommand(newBenchModelsCmd())
 
 	return cmd
 }
@@ -346,6 +350,128 @@ Identifies tasks that improved, regressed, or stayed the same.`,
 	return cmd
 }
 
+func newBenchModelsCmd() *cobra.Command {
+	var (
+		pipeline   string
+		input      string
+		models     []string
+		runs       int
+		label      string
+		timeout    int
+		outputPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "models",
+		Short: "Rank adapters/models on a benchmark pipeline",
+		Long: `Run a benchmark pipeline N times per adapter/model and print a ranked
+comparison of pass rate, contract pass rate, duration, tokens, and cost.
+
+Each run is a regular pipeline run with the model forced onto every step, so
+its metrics land in the state database like any other run. Runs are tagged
+bench:models, model:<model>, adapter:<adapter> and label:<label>.
+
+Targets are given as adapter:model, or a bare model for each persona's own
+adapter. Without --model, every default_model and tier_models entry of the
+manifest's adapters is benchmarked.`,
+		Example: `  wave bench models
+  wave bench models --model claude:haiku --model claude:sonnet --runs 5
+  wave bench models --pipeline impl-issue --input "fix the flaky test" --model opencode:openai/gpt-4o
+  wave bench models --results-path .agents/bench/models.json --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+
+			if runs < 1 {
+				return NewCLIError(CodeInvalidArgs, "--runs must be at least 1", "Use --runs 3 or higher for a stable comparison")
+			}
+
+			manifestPath, _ := cmd.Root().PersistentFlags().GetString("manifest")
+			if manifestPath == "" {
+				manifestPath = "wave.yaml"
+			}
+
+			var targets []bench.ModelTarget
+			for _, s := range models {
+				t, err := bench.ParseModelTarget(s)
+				if err != nil {
+					return NewCLIError(CodeInvalidArgs, err.Error(), "Use --model <model> or --model <adapter>:<model>")
+				}
+				targets = append(targets, t)
+			}
+			if len(targets) == 0 {
+				m, err := loadManifestStrict(manifestPath)
+				if err != nil {
+					return err
+				}
+				targets = bench.ConfiguredModelTargets(m)
+				if len(targets) == 0 {
+					return NewCLIError(CodeInvalidArgs, "no models configured in the manifest", "Pass targets with --model <adapter>:<model>, or set default_model/tier_models on your adapters")
+				}
+			}
+
+			store := buildStateStore()
+			if store == nil {
+				return NewCLIError(CodeStateDBError, "state database unavailable", "Benchmark metrics are read from .agents/state.db; check that it is writable")
+			}
+			defer store.Close()
+
+			cfg := bench.ModelRunConfig{
+				Pipeline: pipeline,
+				Input:    input,
+				Runs:     runs,
+				Targets:  targets,
+				RunLabel: label,
+				Manifest: manifestPath,
+			}
+			if timeout > 0 {
+				cfg.RunTimeout = time.Duration(timeout) * time.Second
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			report, err := bench.RunModelBenchmark(ctx, cfg, bench.NewModelSubprocessRunner(store))
+			if err != nil && report == nil {
+				return NewCLIError(CodeInternalError, fmt.Sprintf("model benchmark failed: %s", err), "Check adapter availability and the benchmark pipeline").WithCause(err)
+			}
+
+			if outputPath != "" {
+				if err := writeJSONFile(report, outputPath); err != nil {
+					return NewCLIError(CodeInternalError, fmt.Sprintf("write results: %s", err), "Check write permissions for the output path").WithCause(err)
+				}
+				fmt.Fprintf(os.Stderr, "Results written to %s\n", outputPath)
+			}
+
+			format := ResolveFormat(cmd, "text")
+			outputCfg := GetOutputConfig(cmd)
+			if outputCfg.Format == OutputFormatJSON {
+				format = "json"
+			}
+
+			switch format {
+			case "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			default:
+				renderModelReportText(report)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&pipeline, "pipeline", bench.DefaultModelPipeline, "Benchmark pipeline to run for each model")
+	cmd.Flags().StringVar(&input, "input", "benchmark", "Input passed to every run")
+	cmd.Flags().StringArrayVar(&models, "model", nil, "Model target as adapter:model or model (repeatable; default: models configured in the manifest)")
+	cmd.Flags().IntVar(&runs, "runs", 3, "Runs per model")
+	cmd.Flags().StringVar(&label, "label", "", "Human-readable label for this benchmark")
+	cmd.Flags().IntVar(&timeout, "timeout", 0, "Per-run timeout in seconds (0 = no limit)")
+	cmd.Flags().StringVar(&outputPath, "results-path", "", "Path to write JSON results file")
+
+	return cmd
+}
+
 func loadReportFile(path string) (*bench.BenchReport, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -360,10 +486,14 @@ func loadReportFile(path string) (*bench.BenchReport, error) {
 }
 
 func writeReportFile(report *bench.BenchReport, path string) error {
+	return writeJSONFile(report, path)
+}
+
+func writeJSONFile(v any, path string) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(report, "", "  ")
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -452,6 +582,31 @@ func renderCompareReportText(cr *bench.CompareReport) {
 	}
 }
 
+func renderModelReportText(report *bench.ModelReport) {
+	fmt.Fprintln(os.Stdout)
+	fmt.Fprintf(os.Stdout, "Model Benchmark: %s (%d runs per model)\n", report.Pipeline, report.RunsPerTarget)
+	if report.RunLabel != "" {
+		fmt.Fprintf(os.Stdout, "Label: %s\n", report.RunLabel)
+	}
+	fmt.Fprintln(os.Stdout, "─────────────────────────────────────")
+	fmt.Fprintf(os.Stdout, "%-4s %-32s %7s %9s %10s %10s %9s\n", "RANK", "MODEL", "PASS", "CONTRACT", "AVG TIME", "AVG TOKENS", "AVG COST")
+	for _, s := range report.Scores {
+		contract := "-"
+		if s.ContractRuns > 0 {
+			contract = fmt.Sprintf("%.0f%%", s.ContractPassRate*100)
+		}
+		target := bench.ModelTarget{Adapter: s.Adapter, Model: s.Model}
+		avgTime := (time.Duration(s.AvgDurationMs) * time.Millisecond).Round(100 * time.Millisecond)
+		fmt.Fprintf(os.Stdout, "%-4d %-32s %6.0f%% %9s %10s %10d %9s\n",
+			s.Rank, target, s.PassRate*100, contract, avgTime, s.AvgTokens, fmt.Sprintf("$%.4f", s.AvgCostDollars))
+	}
+	if len(report.Scores) > 0 && report.Scores[0].Passed > 0 {
+		best := report.Scores[0]
+		fmt.Fprintln(os.Stdout)
+		fmt.Fprintf(os.Stdout, "Top ranked: %s\n", bench.ModelTarget{Adapter: best.Adapter, Model: best.Model})
+	}
+}
+
 func describeRef(ref bench.ReportRef) string {
 	if ref.RunLabel != "" {
 		return ref.RunLabel
@@ -22,7 +22,7 @@ func TestNewBenchCmd_Structure(t *testing.T) {
 		subs[sub.Use] = true
 	}
 
-	for _, want := range []string{"run", "report", "list", "compare"} {
+	for _, want := range []string{"run", "report", "list", "compare", "models"} {
 		if !subs[want] {
 			t.Errorf("missing subcommand %q", want)
 		}
@@ -69,6 +69,39 @@ func TestBenchRunCmd_Validation(t *testing.T) {
 	}
 }
 
+func TestBenchModelsCmd_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "zero runs",
+			args:    []string{"models", "--model", "haiku", "--runs", "0"},
+			wantErr: "--runs must be at least 1",
+		},
+		{
+			name:    "empty adapter",
+			args:    []string{"models", "--model", ":haiku"},
+			wantErr: "invalid model target",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewBenchCmd()
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if got := err.Error(); !strings.Contains(got, tt.wantErr) {
+				t.Errorf("error = %q, want to contain %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestBenchRunCmd_ClaudeModeNoRequirePipeline(t *testing.T) {
 	// Claude mode should not require --pipeline, but will fail on dataset load
 	cmd := NewBenchCmd()
@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/spf13/cobra"
+)
+
+const cacheDBPath = ".agents/state.db"
+
+// CacheOptions holds the filters shared by the cache subcommands.
+type CacheOptions struct {
+	Pipeline string
+	Step     string
+	Expired  bool
+	Format   string
+}
+
+// NewCacheCmd creates the `wave cache` parent command.
+func NewCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and clear the step cache",
+		Long: `Inspect and clear the step cache.
+
+Steps with 'cache: true' store their adapter result and output artifact
+files in the state database. A rerun whose prompt, persona, model,
+injected artifacts and workspace are unchanged replays them instead of
+calling the adapter.`,
+	}
+
+	cmd.AddCommand(newCacheListCmd())
+	cmd.AddCommand(newCacheClearCmd())
+
+	return cmd
+}
+
+func newCacheListCmd() *cobra.Command {
+	var opts CacheOptions
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List cached step results",
+		Example: `  wave cache list
+  wave cache list --pipeline audit --step scan --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			opts.Format = ResolveFormat(cmd, opts.Format)
+			return runCacheList(opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Pipeline, "pipeline", "", "Only entries of this pipeline")
+	cmd.Flags().StringVar(&opts.Step, "step", "", "Only entries of this step ID")
+	cmd.Flags().BoolVar(&opts.Expired, "expired", false, "Only expired entries")
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "Output format: text, json")
+	return cmd
+}
+
+func newCacheClearCmd() *cobra.Command {
+	var opts CacheOptions
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove cached step results",
+		Long: `Remove cached step results so the next run calls the adapter again.
+Without filters the whole cache is cleared.`,
+		Example: `  wave cache clear
+  wave cache clear --pipeline audit --step scan
+  wave cache clear --expired`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runCacheClear(opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Pipeline, "pipeline", "", "Only entries of this pipeline")
+	cmd.Flags().StringVar(&opts.Step, "step", "", "Only entries of this step ID")
+	cmd.Flags().BoolVar(&opts.Expired, "expired", false, "Only expired entries")
+	return cmd
+}
+
+func (o CacheOptions) filter(now time.Time) state.AdapterCacheFilter {
+	f := state.AdapterCacheFilter{PipelineName: o.Pipeline, StepID: o.Step}
+	if o.Expired {
+		f.ExpiredBy = now
+	}
+	return f
+}
+
+func openCacheStore() (state.StateStore, error) {
+	store, err := state.NewStateStore(cacheDBPath)
+	if err != nil {
+		return nil, NewCLIError(CodeStateDBError,
+			fmt.Sprintf("failed to open state database: %s", err),
+			"Check .agents/state.db file permissions").WithCause(err)
+	}
+	return store, nil
+}
+
+func runCacheList(opts CacheOptions) error {
+	now := time.Now()
+	var entries []state.AdapterCacheEntry
+	if _, err := os.Stat(cacheDBPath); err == nil {
+		store, err := openCacheStore()
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+
+		entries, err = store.ListAdapterCacheEntries(opts.filter(now))
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("list cache entries: %s", err), "").WithCause(err)
+		}
+	}
+
+	if opts.Format == "json" {
+		out := make([]map[string]any, 0, len(entries))
+		for _, e := range entries {
+			out = append(out, map[string]any{
+				"key":        e.Key,
+				"pipeline":   e.PipelineName,
+				"step":       e.StepID,
+				"persona":    e.Persona,
+				"model":      e.Model,
+				"tokens":     e.TokensIn + e.TokensOut,
+				"files":      cacheFileNames(e),
+				"size":       cacheEntrySize(e),
+				"created_at": e.CreatedAt,
+				"expires_at": e.ExpiresAt,
+				"expired":    !e.ExpiresAt.After(now),
+			})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No cached step results")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tSTEP\tPERSONA\tMODEL\tTOKENS\tFILES\tSIZE\tEXPIRES")
+	for _, e := range entries {
+		step := e.StepID
+		if e.PipelineName != "" {
+			step = e.PipelineName + "/" + step
+		}
+		expires := "expired"
+		if e.ExpiresAt.After(now) {
+			expires = "in " + formatElapsed(e.ExpiresAt.Sub(now))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n", e.Key[:min(12, len(e.Key))], step, e.Persona, e.Model,
+			formatTokens(e.TokensIn+e.TokensOut), len(e.Files), formatSize(cacheEntrySize(e)), expires)
+	}
+	return tw.Flush()
+}
+
+func runCacheClear(opts CacheOptions) error {
+	if _, err := os.Stat(cacheDBPath); os.IsNotExist(err) {
+		fmt.Println("Removed 0 cached step results")
+		return nil
+	}
+	store, err := openCacheStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	n, err := store.DeleteAdapterCacheEntries(opts.filter(time.Now()))
+	if err != nil {
+		return NewCLIError(CodeInternalError, fmt.Sprintf("clear cache: %s", err), "").WithCause(err)
+	}
+	fmt.Printf("Removed %d cached step results\n", n)
+	return nil
+}
+
+// cacheFileNames returns the output files replayed with e, sorted.
+func cacheFileNames(e state.AdapterCacheEntry) []string {
+	names := make([]string, 0, len(e.Files))
+	for name := range e.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// cacheEntrySize is the stored size of e's output and files in bytes.
+func cacheEntrySize(e state.AdapterCacheEntry) int64 {
+	size := int64(len(e.Stdout) + len(e.ResultContent))
+	for _, data := range e.Files {
+		size += int64(len(data))
+	}
+	return size
+}
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCacheClear(t *testing.T) {
+	chdirKBTemp(t)
+	require.NoError(t, runCacheList(CacheOptions{Format: "text"}), "listing without a database is fine")
+	require.NoError(t, runCacheClear(CacheOptions{}))
+
+	require.NoError(t, os.MkdirAll(".agents", 0o755))
+	store, err := state.NewStateStore(cacheDBPath)
+	require.NoError(t, err)
+	now := time.Now()
+	require.NoError(t, store.PutAdapterCacheEntry(state.AdapterCacheEntry{Key: "fresh", PipelineName: "audit", StepID: "scan",
+		Files: map[string][]byte{"out/b.md": []byte("b"), "out/a.md": []byte("a")}, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}))
+	require.NoError(t, store.PutAdapterCacheEntry(state.AdapterCacheEntry{Key: "stale", PipelineName: "audit", StepID: "scan",
+		CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(time.Minute)}))
+	store.Close()
+
+	require.NoError(t, runCacheList(CacheOptions{Format: "json"}))
+	require.NoError(t, runCacheClear(CacheOptions{Pipeline: "review"}))
+
+	store, err = state.NewStateStore(cacheDBPath)
+	require.NoError(t, err)
+	defer store.Close()
+	entries, err := store.ListAdapterCacheEntries(state.AdapterCacheFilter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "clearing another pipeline keeps the entries")
+	assert.Equal(t, []string{"out/a.md", "out/b.md"}, cacheFileNames(entries[0]))
+	assert.Equal(t, int64(2), cacheEntrySize(entries[0]))
+
+	require.NoError(t, runCacheClear(CacheOptions{Pipeline: "audit", Step: "scan"}))
+	entries, err = store.ListAdapterCacheEntries(state.AdapterCacheFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
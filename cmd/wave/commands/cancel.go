@@ -2,13 +2,14 @@ package commands
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
-	"syscall"
 	"time"
 
+	"github.com/recinq/wave/internal/procutil"
 	"github.com/recinq/wave/internal/state"
 	"github.com/spf13/cobra"
 )
@@ -206,30 +207,30 @@ func forceKillRun(runID string) error {
 		return NewCLIError(CodeInternalError, fmt.Sprintf("invalid pid in %s: %s", pidFile, err), "The PID file may be corrupted").WithCause(err)
 	}
 
-	// Send SIGTERM to the process group
-	if err := syscall.Kill(-pid, syscall.SIGTERM); err != nil {
+	// Ask the process group to exit (SIGTERM on Unix)
+	if err := procutil.TerminateGroup(pid); err != nil {
 		// Process may have already exited
-		if err == syscall.ESRCH {
+		if errors.Is(err, os.ErrProcessDone) {
 			_ = os.Remove(pidFile)
 			return nil
 		}
-		return NewCLIError(CodeInternalError, fmt.Sprintf("failed to send SIGTERM: %s", err), "The process may have already exited").WithCause(err)
+		return NewCLIError(CodeInternalError, fmt.Sprintf("failed to terminate process: %s", err), "The process may have already exited").WithCause(err)
 	}
 
 	// Wait up to 5 seconds for graceful termination
 	for i := 0; i < 50; i++ {
 		time.Sleep(100 * time.Millisecond)
 		// Check if process is still running
-		if err := syscall.Kill(-pid, 0); err == syscall.ESRCH {
+		if !procutil.GroupAlive(pid) {
 			_ = os.Remove(pidFile)
 			return nil
 		}
 	}
 
-	// Process still running after 5 seconds - send SIGKILL
-	if err := syscall.Kill(-pid, syscall.SIGKILL); err != nil {
-		if err != syscall.ESRCH {
-			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to send SIGKILL: %s", err), "The process may require manual termination").WithCause(err)
+	// Process still running after 5 seconds - kill it
+	if err := procutil.KillGroup(pid); err != nil {
+		if !errors.Is(err, os.ErrProcessDone) {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to kill process: %s", err), "The process may require manual termination").WithCause(err)
 		}
 	}
 
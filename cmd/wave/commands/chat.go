@@ -27,6 +27,9 @@ type ChatOptions struct {
 	Continue string // --continue <step-id>: continue work in step's workspace
 	Rewrite  string // --rewrite <step-id>: re-execute step with new prompt
 	Extend   string // --extend <step-id>: add instructions to step
+	// Conversation mode: each message becomes a tracked step
+	Persona  string // --persona <name>: start a conversation with this persona
+	Contract string // --contract <schema>: JSON schema every reply must match
 }
 
 // NewChatCmd creates the chat command.
@@ -58,7 +61,12 @@ Without arguments, opens the most recent completed run.
   Manipulate (read-write):
     wave chat --continue <step>          # resume work in step workspace
     wave chat --extend <step>            # add instructions to a step
-    wave chat --rewrite <step>           # re-execute with new prompt`,
+    wave chat --rewrite <step>           # re-execute with new prompt
+
+  Converse (new run, one step per message):
+    wave chat --persona craftsman        # talk to a persona in a persistent workspace
+    wave chat --persona navigator --contract answer.schema.json
+    echo "summarize the README" | wave chat --persona navigator`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
@@ -81,12 +89,24 @@ Without arguments, opens the most recent completed run.
 	cmd.Flags().StringVar(&opts.Rewrite, "rewrite", "", "Re-execute a step with modified prompt")
 	cmd.Flags().StringVar(&opts.Extend, "extend", "", "Add supplementary instructions to a step")
 
+	// Conversation mode flags
+	cmd.Flags().StringVar(&opts.Persona, "persona", "", "Start a conversation with a persona; each message runs as a tracked step")
+	cmd.Flags().StringVar(&opts.Contract, "contract", "", "JSON schema every reply must match (requires --persona)")
+
 	return cmd
 }
 
 func runChat(opts ChatOptions) error {
 	dbPath := ".agents/state.db"
 
+	if opts.Persona != "" {
+		return runConversation(opts, nil, os.Stdin, os.Stdout)
+	}
+	if opts.Contract != "" {
+		return NewCLIError(CodeFlagConflict, "--contract requires --persona",
+			"Add --persona <name> to start a conversation")
+	}
+
 	// --list: show recent runs and exit
 	if opts.List {
 		return listRecentRunsForChat(dbPath)
@@ -203,10 +223,11 @@ func runChat(opts ChatOptions) error {
 		StepFilter:   opts.Step,
 		ArtifactName: opts.Artifact,
 	}
-	wsPath, err := pipeline.PrepareChatWorkspace(chatCtx, wsOpts)
+	wsPath, restoreInstructions, err := pipeline.PrepareChatWorkspace(chatCtx, wsOpts)
 	if err != nil {
 		return NewCLIError(CodeInternalError, fmt.Sprintf("failed to prepare chat workspace: %s", err), "Check workspace directory permissions").WithCause(err)
 	}
+	defer restoreInstructions()
 
 	// Print session header
 	elapsed := ""
@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/pipeline"
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/workspace"
+)
+
+// conversationExitCommands end a conversation when sent as a message.
+var conversationExitCommands = map[string]bool{"/exit": true, "/quit": true}
+
+// runConversation talks to opts.Persona, running each message read from in
+// as a tracked step of one run. Replies go to out; prompts and the run
+// summary go to stderr.
+func runConversation(opts ChatOptions, runner adapter.AdapterRunner, in io.Reader, out io.Writer) error {
+	if opts.RunID != "" {
+		return NewCLIError(CodeFlagConflict, "--persona starts a new conversation and cannot be combined with a run id",
+			"Drop the run id to start a conversation, or drop --persona to analyze the run")
+	}
+	if err := checkContractSchema(opts.Contract); err != nil {
+		return err
+	}
+	m, err := loadManifestStrict(opts.Manifest)
+	if err != nil {
+		return err
+	}
+	if runner == nil {
+		runner = adapter.ResolveAdapter(conversationAdapter(m, opts.Persona))
+	}
+
+	store, err := state.NewStateStore(".agents/state.db")
+	if err != nil {
+		return NewCLIError(CodeStateDBError, fmt.Sprintf("failed to open state database: %s", err),
+			"Check .agents/state.db file permissions").WithCause(err)
+	}
+	defer store.Close()
+	state.SetRunIDFormatter(store, pipeline.RunIDFormatter(m.Runtime))
+
+	runID, err := store.CreateRun("chat", "conversation with "+opts.Persona)
+	if err != nil {
+		return NewCLIError(CodeStateDBError, fmt.Sprintf("failed to create run: %s", err), "").WithCause(err)
+	}
+
+	wsRoot := m.Runtime.WorkspaceRoot
+	if wsRoot == "" {
+		wsRoot = ".agents/workspaces"
+	}
+	emitter := CreateEmitter(OutputConfig{Format: OutputFormatQuiet}, runID, "chat", nil, m)
+	defer emitter.Cleanup()
+	execOpts := []pipeline.ExecutorOption{
+		pipeline.WithEmitter(emitter.Emitter),
+		pipeline.WithStateStore(store),
+		pipeline.WithRunID(runID),
+	}
+	if wsManager, err := workspace.NewWorkspaceManager(wsRoot); err == nil {
+		execOpts = append(execOpts, pipeline.WithWorkspaceManager(wsManager))
+	}
+	if opts.Model != "" {
+		execOpts = append(execOpts, pipeline.WithModelOverride(opts.Model))
+	}
+	executor := pipeline.NewDefaultPipelineExecutor(runner, execOpts...)
+
+	conv, err := pipeline.NewConversation(executor, pipeline.SingleStepOptions{
+		Persona:        opts.Persona,
+		ContractSchema: opts.Contract,
+		Manifest:       m,
+	})
+	if err != nil {
+		_ = store.UpdateRunStatus(runID, "failed", err.Error(), 0)
+		return NewCLIError(CodeInvalidArgs, err.Error(), "Check the persona name in wave.yaml").WithCause(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt)
+	defer signal.Stop(sigChan)
+	go func() {
+		select {
+		case <-sigChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "\n  Wave Chat — conversation with %s\n", opts.Persona)
+	fmt.Fprintf(os.Stderr, "  Run:      %s\n", runID)
+	fmt.Fprintf(os.Stderr, "  Each message runs as a step; /exit ends the conversation.\n\n")
+
+	_ = store.UpdateRunStatus(runID, "running", "", 0)
+	messages := bufio.NewScanner(in)
+	messages.Buffer(make([]byte, 64*1024), maxRunInputBytes)
+	next := strings.TrimSpace(opts.Prompt)
+	for ctx.Err() == nil {
+		if next == "" {
+			fmt.Fprintf(os.Stderr, "you> ")
+			if !messages.Scan() {
+				break
+			}
+			next = strings.TrimSpace(messages.Text())
+			if next == "" {
+				continue
+			}
+		}
+		message := next
+		next = ""
+		if conversationExitCommands[message] {
+			break
+		}
+
+		reply, err := conv.Send(ctx, message)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "turn-%d failed: %s\n\n", conv.Turns(), err)
+			continue
+		}
+		fmt.Fprintf(out, "%s\n\n", strings.TrimSpace(reply))
+	}
+
+	tokens := executor.GetTotalTokens()
+	if ctx.Err() != nil {
+		_ = store.UpdateRunStatus(runID, "cancelled", "conversation interrupted", tokens)
+	} else {
+		_ = store.UpdateRunStatus(runID, "completed", "", tokens)
+	}
+	fmt.Fprintf(os.Stderr, "\n  %d turns, %s tokens — inspect with 'wave logs %s'\n", conv.Turns(), formatTokens(tokens), runID)
+	if ws := conv.Workspace(); ws != "" {
+		fmt.Fprintf(os.Stderr, "  Workspace: %s\n", ws)
+	}
+	return nil
+}
+
+// conversationAdapter returns the adapter of persona, or the manifest's
+// first adapter when the persona names none.
+func conversationAdapter(m *manifest.Manifest, persona string) string {
+	if p := m.GetPersona(persona); p != nil && p.Adapter != "" {
+		return p.Adapter
+	}
+	for name := range m.Adapters {
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunConversation_TracksEachMessageAsStep(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestManifest(t, tmpDir, []string{"navigator"})
+
+	oldWd, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() { _ = os.Chdir(oldWd) }()
+	require.NoError(t, os.Chdir(tmpDir))
+	require.NoError(t, os.MkdirAll(".agents", 0755))
+
+	runner := adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`), adaptertest.WithTokensUsed(300))
+	var out bytes.Buffer
+	opts := ChatOptions{Manifest: "wave.yaml", Persona: "navigator", Prompt: "where is the config loaded?"}
+	in := strings.NewReader("\nlist the adapters\n/exit\nnever sent\n")
+	require.NoError(t, runConversation(opts, runner, in, &out))
+	assert.NotEmpty(t, out.String())
+
+	store, err := state.NewStateStore(".agents/state.db")
+	require.NoError(t, err)
+	defer store.Close()
+	runs, err := store.ListRuns(state.ListRunsOptions{PipelineName: "chat"})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, "completed", runs[0].Status)
+	assert.Equal(t, 600, runs[0].TotalTokens)
+
+	steps, err := store.GetStepStates(runs[0].RunID)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(steps))
+	for _, s := range steps {
+		ids = append(ids, s.StepID)
+		assert.Equal(t, state.StateCompleted, s.State)
+	}
+	assert.ElementsMatch(t, []string{"turn-1", "turn-2"}, ids)
+}
+
+func TestRunConversation_FlagConflicts(t *testing.T) {
+	err := runConversation(ChatOptions{Persona: "navigator", RunID: "run-1"}, nil, strings.NewReader(""), &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be combined with a run id")
+
+	err = runChat(ChatOptions{Contract: "answer.schema.json"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--contract requires --persona")
+}
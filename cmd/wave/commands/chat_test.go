@@ -12,7 +12,7 @@ func TestNewChatCmd(t *testing.T) {
 	}
 
 	// Verify flags exist
-	flags := []string{"step", "artifact", "manifest", "model", "prompt", "list", "resume"}
+	flags := []string{"step", "artifact", "manifest", "model", "prompt", "list", "resume", "persona", "contract"}
 	for _, flag := range flags {
 		if cmd.Flags().Lookup(flag) == nil {
 			t.Errorf("missing flag: %s", flag)
@@ -36,7 +36,9 @@ Use --keep-last N to retain the N most recent workspaces.
 Use --dry-run to preview what would be deleted without actually removing anything.
 Use --older-than to remove workspaces older than a specified duration (e.g., "7d", "24h", "1h30m").
 Use --status to only clean workspaces for pipelines with a given status (completed, failed).
-Use --quiet to suppress output for scripting (clean exit when nothing to clean).`,
+Use --quiet to suppress output for scripting (clean exit when nothing to clean).
+Workspaces of runs pinned with 'wave pin' are always kept; with pinned runs,
+--all also keeps .agents/state.db so the pins survive.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runClean(opts)
 		},
@@ -124,6 +126,7 @@ func runClean(opts CleanOptions) error {
 
 	targets := []string{}
 	wsDir := ".agents/workspaces"
+	pinned := pinnedRunIDs()
 
 	// Get workspaces with matching status (if status filter is provided)
 	var statusWorkspaces map[string]bool
@@ -156,6 +159,10 @@ func runClean(opts CleanOptions) error {
 					continue
 				}
 
+				if pinned[ws.Name] {
+					continue
+				}
+
 				// Apply status filter
 				if opts.Status != "" && len(statusWorkspaces) > 0 {
 					if !statusWorkspaces[ws.Name] {
@@ -198,6 +205,19 @@ func runClean(opts CleanOptions) error {
 			}
 
 			// When using filters, we only affect workspaces, not state.db or traces
+		} else if opts.All && len(pinned) > 0 {
+			// Remove everything but the pinned runs: their workspaces and
+			// the state database recording them.
+			workspaces, _ := workspace.ListWorkspacesSortedByTime(wsDir)
+			for _, ws := range workspaces {
+				if !pinned[ws.Name] {
+					targets = append(targets, ws.Path)
+				}
+			}
+			targets = append(targets, ".agents/traces", ".agents/retros")
+			if !opts.Quiet {
+				fmt.Fprintf(os.Stderr, "Keeping %d pinned run(s) and .agents/state.db (see 'wave pin')\n", len(pinned))
+			}
 		} else if opts.All {
 			// Default behavior: remove everything
 			targets = append(targets,
@@ -212,6 +232,12 @@ func runClean(opts CleanOptions) error {
 		if strings.Contains(opts.Pipeline, "..") || filepath.IsAbs(opts.Pipeline) || strings.ContainsAny(opts.Pipeline, `/\`) {
 			return NewCLIError(CodeSecurityViolation, fmt.Sprintf("invalid pipeline name: %s", opts.Pipeline), "Pipeline names must not contain path separators or '..' sequences")
 		}
+		if pinned[opts.Pipeline] {
+			if !opts.Quiet {
+				fmt.Fprintf(os.Stderr, "Run %s is pinned; unpin it with 'wave pin --remove %s' to clean it\n", opts.Pipeline, opts.Pipeline)
+			}
+			return nil
+		}
 		targets = append(targets,
 			filepath.Join(".agents", "workspaces", opts.Pipeline),
 		)
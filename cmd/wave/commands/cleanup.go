@@ -28,7 +28,8 @@ func NewCleanupCmd() *cobra.Command {
 
 Uses 'git worktree list' to find all worktrees under .agents/workspaces/,
 cross-references with the state store to identify active runs, and removes
-worktrees not associated with any running pipeline.
+worktrees not associated with any running pipeline. Worktrees of runs
+pinned with 'wave pin' are kept.
 
 Use --dry-run to preview what would be removed without deleting anything.
 Use --force to skip the confirmation prompt.`,
@@ -72,7 +73,8 @@ func listGitWorktrees() ([]worktreeEntry, error) {
 			continue
 		}
 		if strings.HasPrefix(line, "worktree ") {
-			current.Path = strings.TrimPrefix(line, "worktree ")
+			// git prints forward slashes on Windows; match filepath.Abs output
+			current.Path = filepath.FromSlash(strings.TrimPrefix(line, "worktree "))
 		}
 		if strings.HasPrefix(line, "branch ") {
 			current.Branch = strings.TrimPrefix(line, "branch refs/heads/")
@@ -136,11 +138,25 @@ func runCleanup(opts CleanupOptions) error {
 		}
 	}
 
-	// Identify orphaned worktrees (not associated with active runs).
+	// Worktrees of pinned runs live under .agents/workspaces/<run-id>/
+	// and are kept like active ones.
+	pinned := make(map[string]bool)
+	for _, run := range loadPinnedRuns() {
+		pinned[run.RunID] = true
+		if run.BranchName != "" {
+			activeBranches[run.BranchName] = true
+		}
+	}
+
+	// Identify orphaned worktrees (not associated with active or pinned runs).
 	var orphaned []worktreeEntry
 	for _, wt := range waveWorktrees {
 		if wt.Branch != "" && activeBranches[wt.Branch] {
-			continue // active run — keep
+			continue // active or pinned run — keep
+		}
+		rel, _ := filepath.Rel(absWsDir, wt.Path)
+		if runDir, _, _ := strings.Cut(filepath.ToSlash(rel), "/"); pinned[runDir] {
+			continue
 		}
 		orphaned = append(orphaned, wt)
 	}
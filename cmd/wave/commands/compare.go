@@ -0,0 +1,216 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// CompareOptions holds options for the compare command.
+type CompareOptions struct {
+	Format string
+}
+
+// CompareRun summarises one side of a run comparison.
+type CompareRun struct {
+	RunID      string `json:"run_id"`
+	Pipeline   string `json:"pipeline"`
+	Status     string `json:"status"`
+	Tokens     int    `json:"tokens"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// DefinitionDiff compares one definition's digest across two runs.
+type DefinitionDiff struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	DigestA string `json:"digest_a,omitempty"`
+	DigestB string `json:"digest_b,omitempty"`
+	// Change is "unchanged", "changed", "only_a" or "only_b".
+	Change string `json:"change"`
+}
+
+// CompareOutput is the JSON output of the compare command.
+type CompareOutput struct {
+	RunA        CompareRun       `json:"run_a"`
+	RunB        CompareRun       `json:"run_b"`
+	Definitions []DefinitionDiff `json:"definitions"`
+}
+
+// NewCompareCmd creates the compare command.
+func NewCompareCmd() *cobra.Command {
+	var opts CompareOptions
+
+	cmd := &cobra.Command{
+		Use:   "compare <run-a> <run-b>",
+		Short: "Compare two runs and the definitions they ran with",
+		Long: `Compare two pipeline runs side by side: status, tokens and duration, plus
+the content hashes of the definitions each run started from — wave.yaml,
+the pipeline YAML, and every persona system prompt a step used.
+
+A changed digest means the definition was edited between the runs, which
+helps tell a prompt change apart from model or input variance. Runs
+started before provenance was recorded show no definitions.`,
+		Example: `  wave compare impl-issue-20260301-101500-ab12 impl-issue-20260302-093000-cd34
+  wave compare <run-a> <run-b> --format json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Format = ResolveFormat(cmd, opts.Format)
+			return runCompare(args[0], args[1], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "Output format (text, json)")
+
+	return cmd
+}
+
+func runCompare(runA, runB string, opts CompareOptions) error {
+	dbPath := ".agents/state.db"
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return NewCLIError(CodeRunNotFound, "no runs found (state database does not exist)", "Run a pipeline first with 'wave run'")
+	}
+
+	store, err := state.NewReadOnlyStateStore(dbPath)
+	if err != nil {
+		return NewCLIError(CodeStateDBError, fmt.Sprintf("failed to open state database: %s", err), "Check .agents/state.db file permissions or run 'wave run' to create it").WithCause(err)
+	}
+	defer store.Close()
+
+	a, defsA, err := loadCompareRun(store, runA)
+	if err != nil {
+		return err
+	}
+	b, defsB, err := loadCompareRun(store, runB)
+	if err != nil {
+		return err
+	}
+
+	output := CompareOutput{RunA: a, RunB: b, Definitions: diffDefinitions(defsA, defsB)}
+	if opts.Format == "json" {
+		jsonBytes, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to marshal JSON: %s", err), "This is an internal serialization error").WithCause(err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+	printCompare(output)
+	return nil
+}
+
+func loadCompareRun(store state.StateStore, runID string) (CompareRun, []state.DefinitionDigest, error) {
+	exists, err := store.RunExists(runID)
+	if err != nil {
+		return CompareRun{}, nil, NewCLIError(CodeInternalError, fmt.Sprintf("failed to verify run: %s", err), "The state database may be corrupted -- try 'wave migrate validate'").WithCause(err)
+	}
+	if !exists {
+		return CompareRun{}, nil, NewCLIError(CodeRunNotFound, fmt.Sprintf("run not found: %s", runID), "Use 'wave status --all' to list available runs")
+	}
+	run, err := store.GetRun(runID)
+	if err != nil {
+		return CompareRun{}, nil, NewCLIError(CodeStateDBError, fmt.Sprintf("failed to load run %s: %s", runID, err), "The state database may need migration -- try 'wave migrate up'").WithCause(err)
+	}
+	defs, err := store.GetRunProvenance(runID)
+	if err != nil {
+		return CompareRun{}, nil, NewCLIError(CodeStateDBError, fmt.Sprintf("failed to load provenance for %s: %s", runID, err), "The state database may need migration -- try 'wave migrate up'").WithCause(err)
+	}
+
+	out := CompareRun{
+		RunID:    run.RunID,
+		Pipeline: run.PipelineName,
+		Status:   run.Status,
+		Tokens:   run.TotalTokens,
+		Error:    run.ErrorMessage,
+	}
+	if run.CompletedAt != nil {
+		out.DurationMs = run.CompletedAt.Sub(run.StartedAt).Milliseconds()
+	}
+	return out, defs, nil
+}
+
+// diffDefinitions pairs digests by kind and name, ordered by kind then name.
+func diffDefinitions(a, b []state.DefinitionDigest) []DefinitionDiff {
+	type key struct{ kind, name string }
+	byKey := make(map[key]*DefinitionDiff)
+	var keys []key
+	get := func(d state.DefinitionDigest) *DefinitionDiff {
+		k := key{d.Kind, d.Name}
+		if diff, ok := byKey[k]; ok {
+			return diff
+		}
+		diff := &DefinitionDiff{Kind: d.Kind, Name: d.Name}
+		byKey[k] = diff
+		keys = append(keys, k)
+		return diff
+	}
+	for _, d := range a {
+		get(d).DigestA = d.Digest
+	}
+	for _, d := range b {
+		get(d).DigestB = d.Digest
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].kind != keys[j].kind {
+			return keys[i].kind < keys[j].kind
+		}
+		return keys[i].name < keys[j].name
+	})
+	diffs := make([]DefinitionDiff, 0, len(keys))
+	for _, k := range keys {
+		diff := byKey[k]
+		switch {
+		case diff.DigestB == "":
+			diff.Change = "only_a"
+		case diff.DigestA == "":
+			diff.Change = "only_b"
+		case diff.DigestA == diff.DigestB:
+			diff.Change = "unchanged"
+		default:
+			diff.Change = "changed"
+		}
+		diffs = append(diffs, *diff)
+	}
+	return diffs
+}
+
+func printCompare(out CompareOutput) {
+	duration := func(r CompareRun) string {
+		if r.DurationMs == 0 {
+			return "-"
+		}
+		return formatElapsed(time.Duration(r.DurationMs) * time.Millisecond)
+	}
+	fmt.Printf("%-10s %-40s %s\n", "", out.RunA.RunID, out.RunB.RunID)
+	fmt.Printf("%-10s %-40s %s\n", "pipeline", out.RunA.Pipeline, out.RunB.Pipeline)
+	fmt.Printf("%-10s %-40s %s\n", "status", out.RunA.Status, out.RunB.Status)
+	fmt.Printf("%-10s %-40s %s\n", "tokens", formatTokens(out.RunA.Tokens), formatTokens(out.RunB.Tokens))
+	fmt.Printf("%-10s %-40s %s\n", "duration", duration(out.RunA), duration(out.RunB))
+
+	fmt.Println()
+	if len(out.Definitions) == 0 {
+		fmt.Println("No definition provenance recorded for either run")
+		return
+	}
+	fmt.Println("Definitions:")
+	for _, d := range out.Definitions {
+		fmt.Printf("  %-9s %-24s %-12s  %-12s  %s\n", d.Kind, d.Name, shortDigest(d.DigestA), shortDigest(d.DigestB), d.Change)
+	}
+}
+
+func shortDigest(digest string) string {
+	if digest == "" {
+		return "-"
+	}
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+	return digest
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffDefinitions(t *testing.T) {
+	a := []state.DefinitionDigest{
+		{Kind: state.DefinitionManifest, Name: "wave.yaml", Digest: "m1"},
+		{Kind: state.DefinitionPersona, Name: "craftsman", Digest: "c1"},
+		{Kind: state.DefinitionPersona, Name: "navigator", Digest: "n1"},
+	}
+	b := []state.DefinitionDigest{
+		{Kind: state.DefinitionManifest, Name: "wave.yaml", Digest: "m1"},
+		{Kind: state.DefinitionPersona, Name: "navigator", Digest: "n2"},
+		{Kind: state.DefinitionPersona, Name: "reviewer", Digest: "r1"},
+	}
+
+	assert.Equal(t, []DefinitionDiff{
+		{Kind: "manifest", Name: "wave.yaml", DigestA: "m1", DigestB: "m1", Change: "unchanged"},
+		{Kind: "persona", Name: "craftsman", DigestA: "c1", Change: "only_a"},
+		{Kind: "persona", Name: "navigator", DigestA: "n1", DigestB: "n2", Change: "changed"},
+		{Kind: "persona", Name: "reviewer", DigestB: "r1", Change: "only_b"},
+	}, diffDefinitions(a, b))
+}
+
+func TestRunCompare(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".agents"), 0755))
+	store, err := state.NewStateStore(filepath.Join(tmpDir, ".agents", "state.db"))
+	require.NoError(t, err)
+
+	start := time.Now().Add(-time.Hour)
+	done := start.Add(90 * time.Second)
+	for _, r := range []state.SeedRunOptions{
+		{RunID: "run-a", PipelineName: "impl-issue", Status: "completed", TotalTokens: 12000, StartedAt: start, CompletedAt: &done},
+		{RunID: "run-b", PipelineName: "impl-issue", Status: "failed", TotalTokens: 30000, StartedAt: start},
+	} {
+		require.NoError(t, state.SeedRun(store, r))
+	}
+	require.NoError(t, store.SaveRunProvenance("run-a", []state.DefinitionDigest{{Kind: state.DefinitionPersona, Name: "navigator", Digest: "aaaaaaaaaaaaaaaa"}}))
+	require.NoError(t, store.SaveRunProvenance("run-b", []state.DefinitionDigest{{Kind: state.DefinitionPersona, Name: "navigator", Digest: "bbbbbbbbbbbbbbbb"}}))
+	require.NoError(t, store.Close())
+
+	origDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() { _ = os.Chdir(origDir) }()
+
+	out := captureOutput(t, func() {
+		require.NoError(t, runCompare("run-a", "run-b", CompareOptions{Format: "json"}))
+	})
+	var got CompareOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	assert.Equal(t, "completed", got.RunA.Status)
+	assert.Equal(t, int64(90000), got.RunA.DurationMs)
+	assert.Equal(t, 30000, got.RunB.Tokens)
+	require.Len(t, got.Definitions, 1)
+	assert.Equal(t, "changed", got.Definitions[0].Change)
+
+	text := captureOutput(t, func() {
+		require.NoError(t, runCompare("run-a", "run-b", CompareOptions{Format: "text"}))
+	})
+	assert.Contains(t, text, "aaaaaaaaaaaa  bbbbbbbbbbbb  changed")
+	assert.Contains(t, text, "1m30s")
+
+	err = runCompare("run-a", "missing", CompareOptions{})
+	var cliErr *CLIError
+	require.ErrorAs(t, err, &cliErr)
+	assert.Equal(t, CodeRunNotFound, cliErr.Code)
+}
@@ -32,6 +32,7 @@ func NewComposeCmd() *cobra.Command {
 	var parallelFlag bool
 	var failFastFlag bool
 	var maxConcurrentFlag int
+	var runFlag string
 
 	cmd := &cobra.Command{
 		Use:   "compose [pipelines...]",
@@ -132,10 +133,10 @@ Use --validate-only to check compatibility without executing.`,
 				plan := pipeline.BuildExecutionPlan(composeEntries, args)
 				plan.FailFast = failFastFlag
 				plan.MaxConcurrent = maxConcurrentFlag
-				return runComposePlan(seq, plan, inputFlag, manifestFlag, mockFlag, outputCfg, debug)
+				return runComposePlan(seq, plan, inputFlag, manifestFlag, runFlag, mockFlag, outputCfg, debug)
 			}
 
-			return runCompose(seq, inputFlag, manifestFlag, mockFlag, outputCfg, debug)
+			return runCompose(seq, inputFlag, manifestFlag, runFlag, mockFlag, outputCfg, debug)
 		},
 	}
 
@@ -146,6 +147,7 @@ Use --validate-only to check compatibility without executing.`,
 	cmd.Flags().BoolVar(&parallelFlag, "parallel", false, "Enable parallel execution (use -- to separate stages)")
 	cmd.Flags().BoolVar(&failFastFlag, "fail-fast", true, "Stop on first failure (default true)")
 	cmd.Flags().IntVar(&maxConcurrentFlag, "max-concurrent", 0, "Max concurrent pipelines per parallel stage (0 = unlimited)")
+	cmd.Flags().StringVar(&runFlag, "run", "", "Group run ID to record the pipeline runs under (created when omitted)")
 
 	return cmd
 }
@@ -221,7 +223,8 @@ type composeRuntime struct {
 	cancel      context.CancelFunc
 	manifest    manifest.Manifest
 	seqExecutor *pipeline.SequenceExecutor
-	store       interface{ Close() error }
+	store       state.StateStore
+	groupRunID  string
 }
 
 // setupComposeRuntime constructs the manifest, adapter, state store, event
@@ -260,6 +263,8 @@ func setupComposeRuntime(manifestPath string, mock bool, outputCfg OutputConfig,
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "warning: state persistence disabled: %v\n", err)
 		store = nil
+	} else {
+		state.SetRunIDFormatter(store, pipeline.RunIDFormatter(m.Runtime))
 	}
 
 	emitter := event.NewNDJSONEmitter()
@@ -299,13 +304,57 @@ func setupComposeRuntime(manifestPath string, mock bool, outputCfg OutputConfig,
 		cancel:      cancel,
 		manifest:    m,
 		seqExecutor: pipeline.NewSequenceExecutor(newExecutor, baseOpts, eventEmitter, store),
-	}
-	if store != nil {
-		rt.store = store
+		store:       store,
 	}
 	return rt, nil
 }
 
+// startGroupRun records the sequence as a single group run named
+// "compose:<a>+<b>" and makes every pipeline run a child of it, so the
+// sequence shows up as one run tree. runID reuses a group run the caller
+// already created (the TUI passes it via --run); an unknown ID is replaced
+// by a fresh group run.
+func (rt *composeRuntime) startGroupRun(runID string, names []string, input string) {
+	if rt.store == nil {
+		return
+	}
+	if runID != "" {
+		if exists, err := rt.store.RunExists(runID); err != nil || !exists {
+			runID = ""
+		}
+	}
+	if runID == "" {
+		id, err := rt.store.CreateRun("compose:"+strings.Join(names, "+"), input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to create group run: %v\n", err)
+			return
+		}
+		runID = id
+	}
+	rt.groupRunID = runID
+	_ = rt.store.UpdateRunStatus(runID, "running", "", 0)
+	rt.seqExecutor.SetParentRunID(runID)
+}
+
+// finishGroupRun records the sequence outcome on the group run.
+func (rt *composeRuntime) finishGroupRun(result *pipeline.SequenceResult, execErr error) {
+	if rt.store == nil || rt.groupRunID == "" {
+		return
+	}
+	status := "completed"
+	switch {
+	case rt.ctx.Err() != nil:
+		status = "cancelled"
+	case execErr != nil:
+		status = "failed"
+	}
+	tokens := 0
+	if result != nil {
+		tokens = result.TotalTokens
+	}
+	_ = rt.store.UpdateRunStatus(rt.groupRunID, status, "", tokens)
+}
+
 func (rt *composeRuntime) close() {
 	if rt == nil {
 		return
@@ -342,7 +391,7 @@ func printPipelineSummary(seqResult *pipeline.SequenceResult, footer string, ela
 }
 
 // runComposePlan executes a pipeline execution plan with parallel stage support.
-func runComposePlan(_ tui.Sequence, plan pipeline.ExecutionPlan, input string, manifestPath string, mock bool, outputCfg OutputConfig, debug bool) error {
+func runComposePlan(_ tui.Sequence, plan pipeline.ExecutionPlan, input string, manifestPath string, groupRunID string, mock bool, outputCfg OutputConfig, debug bool) error {
 	rt, err := setupComposeRuntime(manifestPath, mock, outputCfg, debug)
 	if err != nil {
 		return err
@@ -350,11 +399,13 @@ func runComposePlan(_ tui.Sequence, plan pipeline.ExecutionPlan, input string, m
 	defer rt.close()
 
 	// Describe the plan
+	var allNames []string
 	for i, stage := range plan.Stages {
 		names := make([]string, len(stage.Pipelines))
 		for j, p := range stage.Pipelines {
 			names[j] = p.Metadata.Name
 		}
+		allNames = append(allNames, names...)
 		mode := "sequential"
 		if stage.Parallel {
 			mode = "parallel"
@@ -363,9 +414,11 @@ func runComposePlan(_ tui.Sequence, plan pipeline.ExecutionPlan, input string, m
 	}
 	fmt.Fprintln(os.Stderr)
 
+	rt.startGroupRun(groupRunID, allNames, input)
 	startTime := time.Now()
 	seqResult, execErr := rt.seqExecutor.ExecutePlan(rt.ctx, plan, &rt.manifest, input)
 	elapsed := time.Since(startTime)
+	rt.finishGroupRun(seqResult, execErr)
 
 	if execErr != nil {
 		printPipelineSummary(seqResult, "Plan completed", elapsed)
@@ -376,7 +429,7 @@ func runComposePlan(_ tui.Sequence, plan pipeline.ExecutionPlan, input string, m
 }
 
 // runCompose executes a validated pipeline sequence using SequenceExecutor.
-func runCompose(seq tui.Sequence, input string, manifestPath string, mock bool, outputCfg OutputConfig, debug bool) error {
+func runCompose(seq tui.Sequence, input string, manifestPath string, groupRunID string, mock bool, outputCfg OutputConfig, debug bool) error {
 	rt, err := setupComposeRuntime(manifestPath, mock, outputCfg, debug)
 	if err != nil {
 		return err
@@ -392,9 +445,11 @@ func runCompose(seq tui.Sequence, input string, manifestPath string, mock bool,
 
 	fmt.Fprintf(os.Stderr, "Executing sequence: %s\n\n", formatSequenceArrow(pipelineNames))
 
+	rt.startGroupRun(groupRunID, pipelineNames, input)
 	startTime := time.Now()
 	seqResult, execErr := rt.seqExecutor.Execute(rt.ctx, pipelines, &rt.manifest, input)
 	elapsed := time.Since(startTime)
+	rt.finishGroupRun(seqResult, execErr)
 
 	if execErr != nil {
 		printPipelineSummary(seqResult, "Sequence completed", elapsed)
@@ -2,10 +2,14 @@ package commands
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/recinq/wave/internal/pipeline"
+	"github.com/recinq/wave/internal/state"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -300,3 +304,55 @@ func TestComposeCmd_ExecutionModeCompatible(t *testing.T) {
 		"output should mention the first pipeline")
 }
 
+
+func TestComposeRuntime_GroupRun(t *testing.T) {
+	store, err := state.NewStateStore(filepath.Join(t.TempDir(), "state.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	newRuntime := func() *composeRuntime {
+		return &composeRuntime{
+			ctx:         context.Background(),
+			seqExecutor: pipeline.NewSequenceExecutor(nil, nil, nil, store),
+			store:       store,
+		}
+	}
+
+	t.Run("creates group run", func(t *testing.T) {
+		rt := newRuntime()
+		rt.startGroupRun("", []string{"pipeline-a", "pipeline-b"}, "build X")
+		require.NotEmpty(t, rt.groupRunID)
+
+		run, err := store.GetRun(rt.groupRunID)
+		require.NoError(t, err)
+		assert.Equal(t, "compose:pipeline-a+pipeline-b", run.PipelineName)
+		assert.Equal(t, "running", run.Status)
+
+		rt.finishGroupRun(&pipeline.SequenceResult{TotalTokens: 1200}, nil)
+		run, err = store.GetRun(rt.groupRunID)
+		require.NoError(t, err)
+		assert.Equal(t, "completed", run.Status)
+		assert.Equal(t, 1200, run.TotalTokens)
+	})
+
+	t.Run("reuses existing group run", func(t *testing.T) {
+		existing, err := store.CreateRun("compose:pipeline-a+pipeline-b", "")
+		require.NoError(t, err)
+
+		rt := newRuntime()
+		rt.startGroupRun(existing, []string{"pipeline-a", "pipeline-b"}, "")
+		assert.Equal(t, existing, rt.groupRunID)
+
+		rt.finishGroupRun(nil, errors.New("boom"))
+		run, err := store.GetRun(existing)
+		require.NoError(t, err)
+		assert.Equal(t, "failed", run.Status)
+	})
+
+	t.Run("replaces unknown group run", func(t *testing.T) {
+		rt := newRuntime()
+		rt.startGroupRun("compose-missing", []string{"pipeline-a"}, "")
+		assert.NotEqual(t, "compose-missing", rt.groupRunID)
+		assert.NotEmpty(t, rt.groupRunID)
+	})
+}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
@@ -28,6 +29,12 @@ type DoOptions struct {
 	Model      string
 	Adapter    string
 	Detach     bool
+	// AdHoc runs the task as a single step with Persona, reading the
+	// prompt from PromptFile when set and validating the result against
+	// the JSON schema at Contract when set.
+	AdHoc      bool
+	PromptFile string
+	Contract   string
 }
 
 func NewDoCmd() *cobra.Command {
@@ -39,19 +46,28 @@ func NewDoCmd() *cobra.Command {
 		Long: `Generate and run a minimal navigate→execute pipeline for a one-off task.
 The task description is passed as arguments.
 
+With --adhoc the task runs as a single step with the --persona (default
+craftsman), skipping navigation. The prompt can be read from --prompt-file,
+and --contract validates the step's JSON result against a schema, retrying
+the step until it matches. The run is tracked like any other pipeline run.
+
 For dynamically generated multi-step pipelines, use 'wave meta' instead.
 
 Examples:
   wave do "fix the login bug"
   wave do "add input validation to the form"
-  wave do "refactor the database queries" --persona craftsman`,
-		Args: cobra.MinimumNArgs(1),
+  wave do "refactor the database queries" --persona craftsman
+  wave do --adhoc --persona craftsman --prompt-file task.md --contract schema.json`,
+		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.Output = GetOutputConfig(cmd)
 			if err := ValidateOutputFormat(opts.Output.Format); err != nil {
 				return err
 			}
-			input := strings.Join(args, " ")
+			input, err := resolveDoInput(args, opts)
+			if err != nil {
+				return err
+			}
 			return runDo(input, opts)
 		},
 	}
@@ -64,10 +80,61 @@ Examples:
 	cmd.Flags().BoolVar(&opts.NoClassify, "no-classify", false, "Bypass task classification and use ad-hoc pipeline")
 	cmd.Flags().StringVar(&opts.Adapter, "adapter", "", "Override adapter (claude, opencode, gemini, codex)")
 	cmd.Flags().BoolVar(&opts.Detach, "detach", false, "Run in background as detached process")
+	cmd.Flags().BoolVar(&opts.AdHoc, "adhoc", false, "Run the task as a single step with --persona, without navigation")
+	cmd.Flags().StringVar(&opts.PromptFile, "prompt-file", "", "Read the task prompt from a file (requires --adhoc)")
+	cmd.Flags().StringVar(&opts.Contract, "contract", "", "JSON schema the task's result must match (requires --adhoc)")
 
 	return cmd
 }
 
+// resolveDoInput returns the task description, from the arguments or from
+// --prompt-file, and checks the --adhoc flags.
+func resolveDoInput(args []string, opts DoOptions) (string, error) {
+	if !opts.AdHoc && (opts.PromptFile != "" || opts.Contract != "") {
+		return "", NewCLIError(CodeFlagConflict, "--prompt-file and --contract require --adhoc",
+			"Add --adhoc to run the task as a single step")
+	}
+	if err := checkContractSchema(opts.Contract); err != nil {
+		return "", err
+	}
+	if opts.PromptFile == "" {
+		if len(args) == 0 {
+			return "", NewCLIError(CodeInvalidArgs, "a task description is required",
+				"Pass the task as arguments, or use --adhoc --prompt-file <file>")
+		}
+		return strings.Join(args, " "), nil
+	}
+	if len(args) > 0 {
+		return "", NewCLIError(CodeFlagConflict, "--prompt-file cannot be combined with a task description argument",
+			"Drop the arguments, or drop --prompt-file")
+	}
+	f, err := os.Open(opts.PromptFile)
+	if err != nil {
+		return "", NewCLIError(CodeInvalidArgs, fmt.Sprintf("cannot read --prompt-file: %s", err),
+			"Check the file path").WithCause(err)
+	}
+	defer f.Close()
+	return readLimitedInput(f, opts.PromptFile)
+}
+
+// checkContractSchema checks that the --contract schema, when given, is a
+// readable JSON file.
+func checkContractSchema(path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return NewCLIError(CodeInvalidArgs, fmt.Sprintf("cannot read --contract: %s", err),
+			"Check the schema file path").WithCause(err)
+	}
+	if !json.Valid(data) {
+		return NewCLIError(CodeInvalidArgs, fmt.Sprintf("--contract %s is not valid JSON", path),
+			"Pass a JSON schema file")
+	}
+	return nil
+}
+
 func runDo(input string, opts DoOptions) error {
 	// Gate on onboarding completion
 	if err := checkOnboarding(""); err != nil {
@@ -82,7 +149,7 @@ func runDo(input string, opts DoOptions) error {
 
 	// Classification: when not bypassed and no explicit persona, classify input
 	// to select the best pipeline from the manifest.
-	useClassification := !opts.NoClassify && opts.Persona == ""
+	useClassification := !opts.AdHoc && !opts.NoClassify && opts.Persona == ""
 
 	var profile classify.TaskProfile
 	var pipelineCfg classify.PipelineConfig
@@ -107,6 +174,21 @@ func runDo(input string, opts DoOptions) error {
 	if classifiedPipeline != nil {
 		p = classifiedPipeline
 		pipelineLabel = pipelineCfg.Name
+	} else if opts.AdHoc {
+		persona := opts.Persona
+		if persona == "" {
+			persona = "craftsman"
+		}
+		generated, err := pipeline.GenerateSingleStepPipeline(pipeline.SingleStepOptions{
+			Persona:        persona,
+			ContractSchema: opts.Contract,
+			Manifest:       &m,
+		})
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to generate pipeline: %s", err), "Check manifest personas").WithCause(err)
+		}
+		p = generated
+		pipelineLabel = "adhoc"
 	} else {
 		// Fallback: generate the ad-hoc navigate→execute pipeline
 		executePersona := opts.Persona
@@ -141,7 +223,11 @@ func runDo(input string, opts DoOptions) error {
 			}
 			fmt.Printf("\n")
 		}
-		fmt.Printf("Ad-hoc pipeline: navigate → execute\n")
+		stepIDs := make([]string, len(p.Steps))
+		for i, step := range p.Steps {
+			stepIDs[i] = step.ID
+		}
+		fmt.Printf("Ad-hoc pipeline: %s\n", strings.Join(stepIDs, " → "))
 		fmt.Printf("  Input: %s\n", input)
 		fmt.Printf("  Steps:\n")
 		for i, step := range p.Steps {
@@ -156,6 +242,7 @@ func runDo(input string, opts DoOptions) error {
 	if s, err := state.NewStateStore(".agents/state.db"); err == nil {
 		store = s
 		defer store.Close()
+		state.SetRunIDFormatter(store, pipeline.RunIDFormatter(m.Runtime))
 	}
 
 	// Execute the pipeline
@@ -201,8 +288,14 @@ func runDo(input string, opts DoOptions) error {
 	if opts.Model != "" {
 		execOpts = append(execOpts, pipeline.WithModelOverride(opts.Model))
 	}
+	// Register the run so it is listed and tracked like a `wave run`.
+	var runID string
 	if store != nil {
 		execOpts = append(execOpts, pipeline.WithStateStore(store))
+		if id, err := store.CreateRun(p.Metadata.Name, input); err == nil {
+			runID = id
+			execOpts = append(execOpts, pipeline.WithRunID(runID))
+		}
 	}
 	if useClassification {
 		execOpts = append(execOpts, pipeline.WithTaskComplexity(string(profile.Complexity)))
@@ -216,8 +309,23 @@ func runDo(input string, opts DoOptions) error {
 	defer execCancel()
 
 	pipelineStart := time.Now()
+	if runID != "" {
+		_ = store.UpdateRunStatus(runID, "running", "", 0)
+	}
 
-	if err := executor.Execute(execCtx, p, &m, input); err != nil {
+	execErr := executor.Execute(execCtx, p, &m, input)
+	if runID != "" {
+		tokens := executor.GetTotalTokens()
+		switch {
+		case ctx.Err() != nil:
+			_ = store.UpdateRunStatus(runID, "cancelled", "pipeline cancelled", tokens)
+		case execErr != nil:
+			_ = store.UpdateRunStatus(runID, "failed", execErr.Error(), tokens)
+		default:
+			_ = store.UpdateRunStatus(runID, "completed", "", tokens)
+		}
+	}
+	if err := execErr; err != nil {
 		if store != nil && useClassification {
 			elapsed := time.Since(pipelineStart)
 			recordOrchestrationDecision(store, executor.GetRunID(), input, profile, pipelineCfg, "failed", executor.GetTotalTokens(), elapsed.Milliseconds())
@@ -4,9 +4,9 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
-	"strings"
 	"testing"
 
+	"github.com/recinq/wave/internal/state"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -380,11 +380,67 @@ func TestDoCommand_MultiWordTaskDescription(t *testing.T) {
 func TestDoCommand_EmptyInput(t *testing.T) {
 	cmd := NewDoCmd()
 
-	// Cobra should reject empty args due to MinimumNArgs(1)
+	// Without --prompt-file a task description argument is required
 	cmd.SetArgs([]string{})
 	err := cmd.Execute()
 	require.Error(t, err)
-	assert.True(t, strings.Contains(err.Error(), "at least") || strings.Contains(err.Error(), "requires"))
+	assert.Contains(t, err.Error(), "a task description is required")
+}
+
+func TestResolveDoInput(t *testing.T) {
+	dir := t.TempDir()
+	promptFile := filepath.Join(dir, "task.md")
+	require.NoError(t, os.WriteFile(promptFile, []byte("Rename the config loader\n"), 0644))
+	schema := filepath.Join(dir, "schema.json")
+	require.NoError(t, os.WriteFile(schema, []byte(`{"type": "object"}`), 0644))
+	badSchema := filepath.Join(dir, "bad.json")
+	require.NoError(t, os.WriteFile(badSchema, []byte(`{"type":`), 0644))
+
+	input, err := resolveDoInput(nil, DoOptions{AdHoc: true, PromptFile: promptFile, Contract: schema})
+	require.NoError(t, err)
+	assert.Equal(t, "Rename the config loader", input)
+
+	input, err = resolveDoInput([]string{"fix", "the", "bug"}, DoOptions{AdHoc: true})
+	require.NoError(t, err)
+	assert.Equal(t, "fix the bug", input)
+
+	for name, tc := range map[string]struct {
+		args []string
+		opts DoOptions
+		want string
+	}{
+		"prompt file without adhoc": {opts: DoOptions{PromptFile: promptFile}, want: "require --adhoc"},
+		"contract without adhoc":    {args: []string{"task"}, opts: DoOptions{Contract: schema}, want: "require --adhoc"},
+		"prompt file and args":      {args: []string{"task"}, opts: DoOptions{AdHoc: true, PromptFile: promptFile}, want: "cannot be combined"},
+		"missing contract":          {args: []string{"task"}, opts: DoOptions{AdHoc: true, Contract: filepath.Join(dir, "none.json")}, want: "cannot read --contract"},
+		"invalid contract":          {args: []string{"task"}, opts: DoOptions{AdHoc: true, Contract: badSchema}, want: "is not valid JSON"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := resolveDoInput(tc.args, tc.opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+}
+
+func TestDoCommand_AdHocDryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestManifest(t, tmpDir, []string{"navigator", "craftsman"})
+
+	oldWd, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() { _ = os.Chdir(oldWd) }()
+	require.NoError(t, os.Chdir(tmpDir))
+
+	opts := DoOptions{Manifest: "wave.yaml", DryRun: true, Mock: true, AdHoc: true, Contract: "schema.json"}
+	output := captureOutput(t, func() {
+		require.NoError(t, runDo("rename the config loader", opts))
+	})
+
+	assert.Contains(t, output, "Ad-hoc pipeline: task\n")
+	assert.Contains(t, output, "1. task (persona: craftsman)")
+	assert.NotContains(t, output, "Classification:")
+	assert.NotContains(t, output, "navigate")
 }
 
 // TestDoCommand_DryRunWithClassification verifies that --dry-run without
@@ -515,3 +571,32 @@ func TestDoCommand_FallbackWhenPipelineNotFound(t *testing.T) {
 	assert.Contains(t, output, "Ad-hoc pipeline")
 	assert.Contains(t, output, "navigate")
 }
+
+func TestDoCommand_AdHocRunIsTracked(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestManifest(t, tmpDir, []string{"navigator", "craftsman"})
+
+	oldWd, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() { _ = os.Chdir(oldWd) }()
+	require.NoError(t, os.Chdir(tmpDir))
+	require.NoError(t, os.MkdirAll(".agents", 0755))
+
+	opts := DoOptions{Manifest: "wave.yaml", Mock: true, AdHoc: true, Output: OutputConfig{Format: OutputFormatQuiet}}
+	require.NoError(t, runDo("rename the config loader", opts))
+
+	store, err := state.NewStateStore(".agents/state.db")
+	require.NoError(t, err)
+	defer store.Close()
+	runs, err := store.ListRuns(state.ListRunsOptions{PipelineName: "adhoc"})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, "completed", runs[0].Status)
+	assert.Equal(t, "rename the config loader", runs[0].Input)
+
+	steps, err := store.GetStepStates(runs[0].RunID)
+	require.NoError(t, err)
+	require.Len(t, steps, 1)
+	assert.Equal(t, "task", steps[0].StepID)
+	assert.Equal(t, state.StateCompleted, steps[0].State)
+}
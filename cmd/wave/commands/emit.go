@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/recinq/wave/internal/hooks"
+	"github.com/recinq/wave/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// EmitOptions holds options for the emit command.
+type EmitOptions struct {
+	Fixture string
+	To      []string
+	DryRun  bool
+	Format  string
+}
+
+// emitWebhookTarget selects webhooks with the raw JSON payload format.
+const emitWebhookTarget = "webhook"
+
+// NewEmitCmd creates the emit command.
+func NewEmitCmd() *cobra.Command {
+	var opts EmitOptions
+
+	cmd := &cobra.Command{
+		Use:   "emit",
+		Short: "Replay a fixture event stream through configured webhooks",
+		Long: `Replay recorded lifecycle events through the webhooks registered in the
+dashboard, so a Slack, Discord, Teams or JSON webhook can be checked
+without a real pipeline run.
+
+The fixture is JSONL: one hook event per line, with the same fields the
+json webhook format posts (type, pipeline_id, step_id, error, gate, ...).
+Each event goes to every selected webhook whose event and step filters
+match it, one delivery at a time.
+
+--to selects webhooks by name or by format (slack, discord, teams, or
+webhook for the raw JSON format). Without --to every active webhook is
+used; a webhook selected by name is used even when inactive. Deliveries
+are not recorded in the webhook delivery log.`,
+		Example: `  wave emit --fixture run.jsonl
+  wave emit --fixture run.jsonl --to slack,webhook
+  wave emit --fixture run.jsonl --to release-channel --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Format = ResolveFormat(cmd, opts.Format)
+			return runEmit(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Fixture, "fixture", "", "JSONL file of hook events to replay (required)")
+	cmd.Flags().StringSliceVar(&opts.To, "to", nil, "Webhook names or formats to deliver to (default: all active webhooks)")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Render payloads without sending them")
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "Output format (text, json)")
+
+	return cmd
+}
+
+func runEmit(ctx context.Context, opts EmitOptions) error {
+	if opts.Fixture == "" {
+		return NewCLIError(CodeInvalidArgs, "--fixture is required", "Specify a JSONL event file with --fixture <path>")
+	}
+
+	f, err := os.Open(opts.Fixture)
+	if err != nil {
+		return NewCLIError(CodeDatasetError, fmt.Sprintf("open fixture: %s", err), "Check that the fixture file exists and is readable").WithCause(err)
+	}
+	events, err := hooks.LoadEventFixture(f)
+	_ = f.Close()
+	if err != nil {
+		return NewCLIError(CodeDatasetError, fmt.Sprintf("parse fixture %s: %s", opts.Fixture, err), "Each line must be a JSON hook event with a valid type").WithCause(err)
+	}
+	if len(events) == 0 {
+		return NewCLIError(CodeDatasetError, fmt.Sprintf("fixture %s contains no events", opts.Fixture), "Add one JSON hook event per line")
+	}
+
+	dbPath := ".agents/state.db"
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return NewCLIError(CodeStateDBError, "no webhooks configured (state database does not exist)", "Register webhooks in the dashboard with 'wave serve'")
+	}
+	store, err := state.NewReadOnlyStateStore(dbPath)
+	if err != nil {
+		return NewCLIError(CodeStateDBError, fmt.Sprintf("failed to open state database: %s", err), "Check .agents/state.db file permissions").WithCause(err)
+	}
+	webhooks, err := store.ListWebhooks()
+	_ = store.Close()
+	if err != nil {
+		return NewCLIError(CodeStateDBError, fmt.Sprintf("failed to list webhooks: %s", err), "The state database may need migration -- try 'wave migrate up'").WithCause(err)
+	}
+
+	selected, err := selectEmitWebhooks(webhooks, opts.To)
+	if err != nil {
+		return err
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	results := hooks.NewWebhookRunner(selected, nil).Replay(ctx, events, opts.DryRun)
+
+	if opts.Format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		renderEmitResults(results, len(events), opts.DryRun)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return NewCLIError(CodeInternalError, fmt.Sprintf("%d of %d deliveries failed", failed, len(results)), "Check the webhook URLs, templates and receiving services")
+	}
+	return nil
+}
+
+// selectEmitWebhooks converts the stored webhooks selected by targets.
+// Each target names a webhook or a format; with no targets every active
+// webhook is selected.
+func selectEmitWebhooks(webhooks []*state.Webhook, targets []string) ([]hooks.WebhookRecord, error) {
+	var records []hooks.WebhookRecord
+	matched := make(map[string]bool, len(targets))
+	for _, wh := range webhooks {
+		format := wh.Format
+		if format == "" || format == hooks.WebhookFormatJSON {
+			format = emitWebhookTarget
+		}
+		selected := len(targets) == 0 && wh.Active
+		for _, t := range targets {
+			byFormat := emitTargetFormat(t) == format && wh.Active
+			if strings.TrimSpace(t) == wh.Name || byFormat {
+				matched[t] = true
+				selected = true
+			}
+		}
+		if selected {
+			records = append(records, hooks.WebhookRecord{
+				ID:       wh.ID,
+				Name:     wh.Name,
+				URL:      wh.URL,
+				Events:   wh.Events,
+				Matcher:  wh.Matcher,
+				Headers:  wh.Headers,
+				Secret:   wh.Secret,
+				Format:   wh.Format,
+				Template: wh.Template,
+				Active:   true,
+			})
+		}
+	}
+
+	var unmatched []string
+	for _, t := range targets {
+		if !matched[t] {
+			unmatched = append(unmatched, strings.TrimSpace(t))
+		}
+	}
+	if len(unmatched) > 0 {
+		names := make([]string, 0, len(webhooks))
+		for _, wh := range webhooks {
+			names = append(names, wh.Name)
+		}
+		sort.Strings(names)
+		hint := "Register webhooks in the dashboard with 'wave serve'"
+		if len(names) > 0 {
+			hint = "Configured webhooks: " + strings.Join(names, ", ")
+		}
+		return nil, NewCLIError(CodeInvalidArgs, fmt.Sprintf("no webhook matches --to %s", strings.Join(unmatched, ",")), hint)
+	}
+	if len(records) == 0 {
+		return nil, NewCLIError(CodeInvalidArgs, "no active webhooks configured", "Register webhooks in the dashboard with 'wave serve', or select one by name with --to")
+	}
+	return records, nil
+}
+
+// emitTargetFormat normalizes a --to entry naming a format; "json" is an
+// alias of "webhook".
+func emitTargetFormat(target string) string {
+	target = strings.TrimSpace(target)
+	if target == hooks.WebhookFormatJSON {
+		return emitWebhookTarget
+	}
+	return target
+}
+
+func renderEmitResults(results []hooks.WebhookReplayResult, events int, dryRun bool) {
+	if len(results) == 0 {
+		fmt.Fprintf(os.Stdout, "Replayed %d events: no webhook subscribes to them\n", events)
+		return
+	}
+	for _, r := range results {
+		target := r.Webhook
+		if r.StepID != "" {
+			target += "  " + string(r.Event) + " / " + r.StepID
+		} else {
+			target += "  " + string(r.Event)
+		}
+		switch {
+		case r.Error != "":
+			fmt.Fprintf(os.Stdout, "✗ %s  %s\n", target, r.Error)
+		case dryRun:
+			fmt.Fprintf(os.Stdout, "• %s\n  %s\n", target, r.Payload)
+		default:
+			fmt.Fprintf(os.Stdout, "✓ %s  HTTP %d (%dms)\n", target, r.StatusCode, r.ResponseTimeMs)
+		}
+	}
+	verb := "Delivered"
+	if dryRun {
+		verb = "Rendered"
+	}
+	fmt.Fprintf(os.Stdout, "\n%s %d deliveries for %d events\n", verb, len(results), events)
+}
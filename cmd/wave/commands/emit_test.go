@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/recinq/wave/internal/state"
+)
+
+func TestSelectEmitWebhooks(t *testing.T) {
+	webhooks := []*state.Webhook{
+		{ID: 1, Name: "release-channel", Format: "slack", Active: true},
+		{ID: 2, Name: "audit", Format: "", Active: true},
+		{ID: 3, Name: "old-slack", Format: "slack", Active: false},
+		{ID: 4, Name: "ops", Format: "discord", Active: true},
+	}
+
+	names := func(t *testing.T, targets []string) []string {
+		t.Helper()
+		records, err := selectEmitWebhooks(webhooks, targets)
+		if err != nil {
+			t.Fatalf("selectEmitWebhooks(%v) error = %v", targets, err)
+		}
+		var got []string
+		for _, r := range records {
+			got = append(got, r.Name)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name    string
+		targets []string
+		want    string
+	}{
+		{"default selects active webhooks", nil, "release-channel audit ops"},
+		{"by format skips inactive", []string{"slack"}, "release-channel"},
+		{"webhook selects json format", []string{"webhook"}, "audit"},
+		{"json is an alias of webhook", []string{"json"}, "audit"},
+		{"by name includes inactive", []string{"old-slack", "discord"}, "old-slack ops"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(names(t, tt.targets), " "); got != tt.want {
+				t.Errorf("selected = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	_, err := selectEmitWebhooks(webhooks, []string{"teams"})
+	if err == nil || !strings.Contains(err.Error(), "teams") {
+		t.Errorf("expected unmatched target error, got %v", err)
+	}
+	_, err = selectEmitWebhooks(webhooks[2:3], nil)
+	if err == nil {
+		t.Error("expected an error when no webhook is active")
+	}
+}
@@ -13,6 +13,7 @@ import (
 // Error code constants for machine-parseable error classification.
 const (
 	CodePipelineNotFound       = "pipeline_not_found"
+	CodePipelineSunset         = "pipeline_sunset"
 	CodeManifestMissing        = "manifest_missing"
 	CodeManifestInvalid        = "manifest_invalid"
 	CodeContractViolation      = "contract_violation"
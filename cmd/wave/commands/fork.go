@@ -85,10 +85,12 @@ func runFork(opts ForkOptions) error {
 			"Use 'wave list runs' to see available run IDs")
 	}
 
-	// Load manifest for pipeline info
-	if _, err := loadManifestStrict(opts.Manifest); err != nil {
+	// Load manifest for pipeline info and run naming
+	m, err := loadManifestStrict(opts.Manifest)
+	if err != nil {
 		return err
 	}
+	state.SetRunIDFormatter(store, pipeline.RunIDFormatter(m.Runtime))
 
 	// Load pipeline
 	p, err := pipeline.LoadByName(run.PipelineName)
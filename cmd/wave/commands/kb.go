@@ -0,0 +1,303 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/recinq/wave/internal/pipeline"
+	"github.com/recinq/wave/internal/state"
+	"github.com/spf13/cobra"
+)
+
+const kbDBPath = ".agents/state.db"
+
+// maxKBPatternLen caps the signature derived from a recorded failure so the
+// pattern stays specific without dragging along an entire stack trace.
+const maxKBPatternLen = 200
+
+// KBAddOptions holds options for `wave kb add`.
+type KBAddOptions struct {
+	Pattern  string
+	Category string
+	Pipeline string
+	Step     string
+	Note     string
+	FromRun  string
+}
+
+// NewKBCmd creates the `wave kb` parent command.
+func NewKBCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kb",
+		Short: "Manage the error knowledge base",
+		Long: `Manage the per-project error knowledge base.
+
+Each entry pairs a failure signature with remediation notes. When a step
+fails and is retried, entries whose signature matches the failure are
+injected into the retry prompt under "Known Remediations".
+
+Entries can be written by hand or seeded from a recorded failure with
+--from-run, which copies the step's normalized error signature, failure
+category, and pipeline name.`,
+	}
+
+	cmd.AddCommand(newKBAddCmd())
+	cmd.AddCommand(newKBListCmd())
+	cmd.AddCommand(newKBRemoveCmd())
+
+	return cmd
+}
+
+func newKBAddCmd() *cobra.Command {
+	var opts KBAddOptions
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a failure signature and its remediation",
+		Example: `  wave kb add --pattern "go: module lookup disabled" --note "Run 'go mod download' before building"
+  wave kb add --category rate_limit --pattern "429" --note "Reduce parallelism for this pipeline"
+  wave kb add --from-run impl-issue-20260328-143022 --step implement --note "Regenerate mocks first"`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runKBAdd(opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Pattern, "pattern", "", "Error substring identifying the failure (matched case-insensitively)")
+	cmd.Flags().StringVar(&opts.Category, "category", "", "Restrict to a failure category (rate_limit, timeout, ...)")
+	cmd.Flags().StringVar(&opts.Pipeline, "pipeline", "", "Restrict to a pipeline")
+	cmd.Flags().StringVar(&opts.Step, "step", "", "Restrict to a step ID")
+	cmd.Flags().StringVar(&opts.Note, "note", "", "Remediation notes injected into the retry prompt (required)")
+	cmd.Flags().StringVar(&opts.FromRun, "from-run", "", "Seed pattern, category, and pipeline from this run's failed --step")
+	return cmd
+}
+
+func newKBListCmd() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List knowledge base entries",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			format = ResolveFormat(cmd, format)
+			return runKBList(format)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json")
+	return cmd
+}
+
+func newKBRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <id>",
+		Short: "Remove a knowledge base entry",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runKBRemove(args[0])
+		},
+	}
+}
+
+func openKBStore() (state.StateStore, error) {
+	if err := os.MkdirAll(filepath.Dir(kbDBPath), 0755); err != nil {
+		return nil, NewCLIError(CodeStateDBError,
+			fmt.Sprintf("failed to create %s: %s", filepath.Dir(kbDBPath), err),
+			"Check directory permissions").WithCause(err)
+	}
+	store, err := state.NewStateStore(kbDBPath)
+	if err != nil {
+		return nil, NewCLIError(CodeStateDBError,
+			fmt.Sprintf("failed to open state database: %s", err),
+			"Check .agents/state.db file permissions").WithCause(err)
+	}
+	return store, nil
+}
+
+func runKBAdd(opts KBAddOptions) error {
+	if strings.TrimSpace(opts.Note) == "" {
+		return NewCLIError(CodeInvalidArgs, "--note is required", "Describe how to resolve the failure")
+	}
+	// Entries match on the triage category alone, so failure classes such
+	// as contract_failure would be stored but never match.
+	if opts.Category != "" && !pipeline.IsFailureCategory(opts.Category) {
+		return NewCLIError(CodeInvalidArgs,
+			fmt.Sprintf("unknown failure category %q", opts.Category),
+			"Use a category reported by 'wave triage' (rate_limit, timeout, contract_violation, ...)")
+	}
+
+	store, err := openKBStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	entry := state.KnowledgeEntry{
+		Pattern:      opts.Pattern,
+		Category:     opts.Category,
+		PipelineName: opts.Pipeline,
+		StepID:       opts.Step,
+		Remediation:  strings.TrimSpace(opts.Note),
+		Source:       state.KnowledgeSourceManual,
+	}
+
+	if opts.FromRun != "" {
+		if err := seedKBEntryFromRun(store, opts.FromRun, &entry); err != nil {
+			return err
+		}
+	}
+
+	if strings.TrimSpace(entry.Pattern) == "" {
+		return NewCLIError(CodeInvalidArgs, "--pattern or --from-run is required",
+			"Pass an error substring with --pattern, or seed it from a failed run with --from-run and --step")
+	}
+
+	id, err := store.AddKnowledgeEntry(entry)
+	if err != nil {
+		return NewCLIError(CodeInternalError, fmt.Sprintf("add knowledge entry: %s", err), "").WithCause(err)
+	}
+	fmt.Printf("Added knowledge entry #%d\n", id)
+	return nil
+}
+
+// seedKBEntryFromRun fills the entry's signature, category, and pipeline from
+// the recorded failure of entry.StepID in runID. Explicit flags win over the
+// recorded values.
+func seedKBEntryFromRun(store state.StateStore, runID string, entry *state.KnowledgeEntry) error {
+	if entry.StepID == "" {
+		return NewCLIError(CodeInvalidArgs, "--from-run requires --step", "Name the failed step whose error should be recorded")
+	}
+
+	steps, err := store.GetStepStates(runID)
+	if err != nil {
+		return NewCLIError(CodeInternalError, fmt.Sprintf("load step states: %s", err), "").WithCause(err)
+	}
+	var failed *state.StepStateRecord
+	for i := range steps {
+		if steps[i].StepID == entry.StepID {
+			failed = &steps[i]
+			break
+		}
+	}
+	if failed == nil || failed.ErrorMessage == "" {
+		return NewCLIError(CodeRunNotFound,
+			fmt.Sprintf("no recorded failure for step %q in run %s", entry.StepID, runID),
+			"Run 'wave triage' to find failed runs and steps")
+	}
+
+	if entry.Pattern == "" {
+		entry.Pattern = kbSignatureFromError(failed.ErrorMessage)
+	}
+	if entry.Category == "" {
+		entry.Category = failed.FailureCategory
+	}
+	if entry.PipelineName == "" {
+		if run, err := store.GetRun(runID); err == nil && run != nil {
+			entry.PipelineName = run.PipelineName
+		}
+	}
+	entry.Source = state.KnowledgeSourceTriage
+	return nil
+}
+
+// kbSignatureFromError reduces a recorded error message to a reusable
+// signature: the first line, normalized and length-capped.
+func kbSignatureFromError(errMsg string) string {
+	line, _, _ := strings.Cut(errMsg, "\n")
+	sig := strings.TrimSpace(pipeline.NormalizeErrorSignature(line))
+	if len(sig) > maxKBPatternLen {
+		n := maxKBPatternLen
+		for n > 0 && !utf8.RuneStart(sig[n]) {
+			n--
+		}
+		sig = sig[:n]
+	}
+	return sig
+}
+
+func runKBList(format string) error {
+	var entries []state.KnowledgeEntry
+	if _, err := os.Stat(kbDBPath); err == nil {
+		store, err := openKBStore()
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+
+		entries, err = store.ListKnowledgeEntries()
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("list knowledge entries: %s", err), "").WithCause(err)
+		}
+	}
+
+	if format == "json" {
+		out := make([]map[string]any, 0, len(entries))
+		for _, e := range entries {
+			out = append(out, map[string]any{
+				"id":          e.ID,
+				"pattern":     e.Pattern,
+				"category":    e.Category,
+				"pipeline":    e.PipelineName,
+				"step":        e.StepID,
+				"remediation": e.Remediation,
+				"source":      e.Source,
+				"created_at":  e.CreatedAt,
+			})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No knowledge base entries")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSCOPE\tPATTERN\tREMEDIATION")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", e.ID, kbScope(e), truncate(e.Pattern, 40), truncate(e.Remediation, 60))
+	}
+	return tw.Flush()
+}
+
+// kbScope renders the optional category/pipeline/step filters of an entry.
+func kbScope(e state.KnowledgeEntry) string {
+	var parts []string
+	if e.Category != "" {
+		parts = append(parts, e.Category)
+	}
+	if e.PipelineName != "" {
+		parts = append(parts, e.PipelineName)
+	}
+	if e.StepID != "" {
+		parts = append(parts, e.StepID)
+	}
+	if len(parts) == 0 {
+		return "*"
+	}
+	return strings.Join(parts, "/")
+}
+
+func runKBRemove(raw string) error {
+	id, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil || id <= 0 {
+		return NewCLIError(CodeInvalidArgs,
+			fmt.Sprintf("invalid knowledge entry id: %q", raw),
+			"Pass a positive integer id (see 'wave kb list')")
+	}
+	store, err := openKBStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if err := store.DeleteKnowledgeEntry(id); err != nil {
+		return NewCLIError(CodeRunNotFound, err.Error(), "Run 'wave kb list' to see available entries").WithCause(err)
+	}
+	fmt.Printf("Removed knowledge entry #%d\n", id)
+	return nil
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chdirKBTemp moves the test into a fresh tempdir so the kb commands operate
+// on their own .agents/state.db.
+func chdirKBTemp(t *testing.T) {
+	t.Helper()
+	orig, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(orig) })
+}
+
+func TestKBSignatureFromError(t *testing.T) {
+	sig := kbSignatureFromError("2026-03-28T14:30:22Z Go: Module Lookup Disabled\ngoroutine 1 [running]")
+	assert.Equal(t, "go: module lookup disabled", sig)
+}
+
+func TestKBSignatureFromError_RuneBoundary(t *testing.T) {
+	sig := kbSignatureFromError(strings.Repeat("a", maxKBPatternLen-1) + "ééé")
+	assert.True(t, utf8.ValidString(sig), "signature must not split a character")
+	assert.Equal(t, strings.Repeat("a", maxKBPatternLen-1), sig)
+}
+
+func TestKBScope(t *testing.T) {
+	assert.Equal(t, "*", kbScope(state.KnowledgeEntry{}))
+	assert.Equal(t, "timeout/impl-issue/implement", kbScope(state.KnowledgeEntry{
+		Category: "timeout", PipelineName: "impl-issue", StepID: "implement",
+	}))
+}
+
+func TestRunKBAdd_Validation(t *testing.T) {
+	chdirKBTemp(t)
+
+	assert.Error(t, runKBAdd(KBAddOptions{Pattern: "boom"}), "note is required")
+	assert.Error(t, runKBAdd(KBAddOptions{Note: "fix"}), "pattern or from-run is required")
+	assert.Error(t, runKBAdd(KBAddOptions{Pattern: "boom", Note: "fix", Category: "bogus"}))
+	assert.Error(t, runKBAdd(KBAddOptions{Pattern: "boom", Note: "fix", Category: "contract_failed"}), "aliases never match a triage category")
+	assert.Error(t, runKBAdd(KBAddOptions{Pattern: "boom", Note: "fix", Category: "transient"}), "failure classes never match a triage category")
+	assert.Error(t, runKBAdd(KBAddOptions{Note: "fix", FromRun: "run-1"}), "from-run requires step")
+}
+
+func TestRunKBAdd_FromRun(t *testing.T) {
+	chdirKBTemp(t)
+	require.NoError(t, os.MkdirAll(".agents", 0o755))
+
+	store, err := state.NewStateStore(kbDBPath)
+	require.NoError(t, err)
+	runID, err := store.CreateRun("impl-issue", "")
+	require.NoError(t, err)
+	require.NoError(t, store.SavePipelineState(runID, "failed", ""))
+	require.NoError(t, store.SaveStepState(runID, "implement", state.StateRunning, ""))
+	require.NoError(t, store.SaveStepFailureCategory(runID, "implement", "sandbox_denial"))
+	require.NoError(t, store.SaveStepState(runID, "implement", state.StateFailed, "Permission denied: /etc/hosts\nmore detail"))
+	store.Close()
+
+	require.NoError(t, runKBAdd(KBAddOptions{FromRun: runID, Step: "implement", Note: "Add /etc/hosts to allowed paths"}))
+
+	store, err = state.NewStateStore(kbDBPath)
+	require.NoError(t, err)
+	defer store.Close()
+	entries, err := store.ListKnowledgeEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "permission denied: /etc/hosts", entries[0].Pattern)
+	assert.Equal(t, "sandbox_denial", entries[0].Category)
+	assert.Equal(t, "impl-issue", entries[0].PipelineName)
+	assert.Equal(t, "implement", entries[0].StepID)
+	assert.Equal(t, state.KnowledgeSourceTriage, entries[0].Source)
+
+	require.NoError(t, runKBRemove("1"))
+	assert.Error(t, runKBRemove("1"))
+	assert.Error(t, runKBRemove("abc"))
+}
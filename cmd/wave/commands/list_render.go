@@ -3,6 +3,7 @@ package commands
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/recinq/wave/internal/display"
 	"github.com/recinq/wave/internal/listing"
@@ -20,8 +21,15 @@ func renderPipelinesTable(pipelines []listing.PipelineInfo) {
 
 	for _, p := range pipelines {
 		stepBadge := f.Muted(fmt.Sprintf("[%d steps]", len(p.Steps)))
+		if p.CostUSD > 0 {
+			stepBadge += " " + f.Muted(display.FormatCost(p.CostUSD)+" spent")
+		}
 		fmt.Printf("\n  %s %s\n", f.Primary(p.Name), stepBadge)
 
+		if p.Deprecated != nil {
+			fmt.Printf("    %s\n", f.Warning(p.Deprecated.Notice(p.Name, time.Now())))
+		}
+
 		if p.Description != "" {
 			fmt.Printf("    %s\n", f.Muted(p.Description))
 		}
@@ -160,11 +168,21 @@ func renderRunsTable(runs []listing.RunInfo) {
 		statusWidth   = 12
 		startedWidth  = 20
 		durationWidth = 10
+		costWidth     = 9
 		indent        = 2
 		gaps          = 8 // 4 gaps x 2 chars each
 	)
 
+	// The COST column only appears once some listed run has a recorded cost.
+	var totalCost float64
+	for _, run := range runs {
+		totalCost += run.CostUSD
+	}
+
 	fixedWidth := indent + statusWidth + startedWidth + durationWidth + gaps
+	if totalCost > 0 {
+		fixedWidth += costWidth + 2
+	}
 	remaining := termWidth - fixedWidth
 	if remaining < 20 {
 		remaining = 20
@@ -179,12 +197,16 @@ func renderRunsTable(runs []listing.RunInfo) {
 		pipelineWidth = 8
 	}
 
+	lastHeader := "DURATION"
+	if totalCost > 0 {
+		lastHeader = fmt.Sprintf("%-*s  COST", durationWidth, "DURATION")
+	}
 	fmt.Printf("  %s  %s  %s  %s  %s\n",
 		f.Muted(fmt.Sprintf("%-*s", runIDWidth, "RUN_ID")),
 		f.Muted(fmt.Sprintf("%-*s", pipelineWidth, "PIPELINE")),
 		f.Muted(fmt.Sprintf("%-*s", statusWidth, "STATUS")),
 		f.Muted(fmt.Sprintf("%-*s", startedWidth, "STARTED")),
-		f.Muted("DURATION"),
+		f.Muted(lastHeader),
 	)
 
 	for _, run := range runs {
@@ -212,9 +234,26 @@ func renderRunsTable(runs []listing.RunInfo) {
 			statusStr = f.Muted(fmt.Sprintf("%-*s", statusWidth, run.Status))
 		}
 
+		last := f.Muted(run.Duration)
+		if totalCost > 0 {
+			costStr := "-"
+			if run.CostUSD > 0 {
+				costStr = display.FormatCost(run.CostUSD)
+			}
+			last = f.Muted(fmt.Sprintf("%-*s", durationWidth, run.Duration)) + "  " + costStr
+		}
+
+		if run.Pinned {
+			last += "  " + f.Primary("pinned")
+		}
+
 		fmt.Printf("  %-*s  %-*s  %s  %-*s  %s\n",
 			runIDWidth, runID, pipelineWidth, pipeline, statusStr,
-			startedWidth, run.StartedAt, f.Muted(run.Duration))
+			startedWidth, run.StartedAt, last)
+	}
+
+	if totalCost > 0 {
+		fmt.Printf("\n  %s\n", f.Muted(fmt.Sprintf("Total: %s across %d runs", display.FormatCost(totalCost), len(runs))))
 	}
 
 	fmt.Println()
@@ -1168,6 +1168,43 @@ func TestListRunsCmd_WithDatabase(t *testing.T) {
 	assert.Contains(t, stdout, "completed")
 }
 
+// Test that list runs shows each run's cost and the total
+func TestListRunsCmd_Cost(t *testing.T) {
+	h := newListTestHelper(t)
+	h.chdir()
+	defer h.restore()
+
+	t.Setenv("COLUMNS", "140")
+
+	require.NoError(t, os.MkdirAll(".agents", 0755))
+	store, err := state.NewStateStore(filepath.Join(".agents", "state.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	now := time.Now()
+	for _, id := range []string{"run-priced", "run-free"} {
+		require.NoError(t, state.SeedRun(store, state.SeedRunOptions{
+			RunID:        id,
+			PipelineName: "test-pipeline",
+			Status:       "completed",
+			StartedAt:    now.Add(-time.Hour),
+			CompletedAt:  &now,
+		}))
+	}
+	require.NoError(t, store.RecordStepCost(&state.StepCostRecord{RunID: "run-priced", StepID: "a", PipelineName: "test-pipeline", CostUSD: 1.25}))
+	require.NoError(t, store.RecordStepCost(&state.StepCostRecord{RunID: "run-priced", StepID: "b", PipelineName: "test-pipeline", CostUSD: 0.5}))
+
+	stdout, _, err := executeListRunsCmd()
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "COST")
+	assert.Contains(t, stdout, "$1.75")
+	assert.Contains(t, stdout, "Total: $1.75 across 2 runs")
+
+	stdout, _, err = executeListRunsCmd("--format", "json")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, `"cost_usd": 1.75`)
+}
+
 // Test list runs with status filter on database
 func TestListRunsCmd_StatusFilter(t *testing.T) {
 	h := newListTestHelper(t)
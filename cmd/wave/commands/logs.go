@@ -23,20 +23,22 @@ type logsStore interface {
 	GetRunStatus(runID string) (string, error)
 	GetEvents(runID string, opts state.EventQueryOptions) ([]state.LogRecord, error)
 	GetEventAggregateStats(runID string) (*state.EventAggregateStats, error)
+	GetContractResults(runID string, stepID string) ([]state.ContractResultRecord, error)
 }
 
 // LogsOptions holds options for the logs command.
 type LogsOptions struct {
-	RunID    string // Specific run (from args, default: most recent)
-	Step     string // Filter by step ID
-	Errors   bool   // Only show errors
-	Follow   bool   // Stream logs in real-time
-	Tail     int    // Show last N lines
-	Since    string // Filter by time (e.g., "10m", "1h")
-	Level    string // Log level: all, info, error
-	Format   string // text, json
-	Manifest string
-	Trace    bool // Show structured debug trace events
+	RunID     string // Specific run (from args, default: most recent)
+	Step      string // Filter by step ID
+	Errors    bool   // Only show errors
+	Follow    bool   // Stream logs in real-time
+	Tail      int    // Show last N lines
+	Since     string // Filter by time (e.g., "10m", "1h")
+	Level     string // Log level: all, info, error
+	Format    string // text, json
+	Manifest  string
+	Trace     bool // Show structured debug trace events
+	Contracts bool // Show structured contract validation results
 }
 
 // LogsOutput represents the JSON output for logs command.
@@ -75,7 +77,8 @@ With a run-id argument, shows logs for that specific run.`,
   wave logs --since 10m            # Show logs from last 10 minutes
   wave logs --follow               # Stream logs in real-time
   wave logs --format json          # Output as JSON for scripting
-  wave logs --trace                # Show debug trace events (requires --debug run)`,
+  wave logs --trace                # Show debug trace events (requires --debug run)
+  wave logs --contracts            # Show contract results with each violation`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
@@ -95,6 +98,7 @@ With a run-id argument, shows logs for that specific run.`,
 	cmd.Flags().StringVar(&opts.Format, "format", "text", "Output format (text, json)")
 	cmd.Flags().StringVar(&opts.Manifest, "manifest", "wave.yaml", "Path to manifest file")
 	cmd.Flags().BoolVar(&opts.Trace, "trace", false, "Show structured debug trace events (requires a --debug run)")
+	cmd.Flags().BoolVar(&opts.Contracts, "contracts", false, "Show structured contract validation results instead of events")
 
 	return cmd
 }
@@ -160,6 +164,10 @@ func runLogs(opts LogsOptions) error {
 		opts.Level = "error"
 	}
 
+	if opts.Contracts {
+		return runLogsContracts(store, runID, opts)
+	}
+
 	if opts.Follow {
 		return runLogsFollow(store, runID, opts)
 	}
@@ -238,6 +246,82 @@ func runLogsOnce(store logsStore, runID string, opts LogsOptions) error {
 	return nil
 }
 
+// ContractsOutput represents the JSON output for logs --contracts.
+type ContractsOutput struct {
+	RunID     string                       `json:"run_id"`
+	Contracts []state.ContractResultRecord `json:"contracts"`
+}
+
+// contractOutputTailLines is how many lines of command output --contracts
+// prints for a failed contract in text mode.
+const contractOutputTailLines = 20
+
+// runLogsContracts displays the structured contract validation results of
+// a run, including every violation of a failed contract.
+func runLogsContracts(store logsStore, runID string, opts LogsOptions) error {
+	results, err := store.GetContractResults(runID, opts.Step)
+	if err != nil {
+		return NewCLIError(CodeInternalError, fmt.Sprintf("failed to query contract results: %s", err), "The state database may be corrupted -- try 'wave migrate validate'").WithCause(err)
+	}
+	if opts.Level == "error" {
+		failed := results[:0]
+		for _, r := range results {
+			if !r.Passed {
+				failed = append(failed, r)
+			}
+		}
+		results = failed
+	}
+
+	if opts.Format == "json" {
+		if results == nil {
+			results = []state.ContractResultRecord{}
+		}
+		jsonBytes, err := json.MarshalIndent(ContractsOutput{RunID: runID, Contracts: results}, "", "  ")
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to marshal JSON: %s", err), "This is an internal serialization error").WithCause(err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No contract results found for run: %s\n", runID)
+		return nil
+	}
+	for _, r := range results {
+		status := "PASS"
+		if !r.Passed {
+			status = "FAIL"
+		}
+		label := r.ContractType
+		if r.Name != "" && r.Name != r.ContractType {
+			label += " (" + r.Name + ")"
+		}
+		fmt.Printf("[%s] %s  %s  %s\n", status, r.StepID, label, formatDuration(time.Duration(r.DurationMs)*time.Millisecond))
+		if r.Message != "" {
+			fmt.Printf("       %s\n", r.Message)
+		}
+		for _, v := range r.Violations {
+			fmt.Printf("       - %s\n", v)
+		}
+		if !r.Passed && r.Output != "" {
+			lines := strings.Split(strings.TrimRight(r.Output, "\n"), "\n")
+			if len(lines) > contractOutputTailLines {
+				lines = lines[len(lines)-contractOutputTailLines:]
+			}
+			fmt.Println("       output:")
+			for _, line := range lines {
+				fmt.Printf("         %s\n", line)
+			}
+		}
+		if r.ArtifactPath != "" {
+			fmt.Printf("       artifact: %s\n", r.ArtifactPath)
+		}
+	}
+	return nil
+}
+
 // runLogsFollow streams logs in real-time.
 func runLogsFollow(store logsStore, runID string, opts LogsOptions) error {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -475,3 +475,35 @@ func TestLogsCmd_CombinedFilters(t *testing.T) {
 	assert.NotContains(t, stdout, "Step1")
 	assert.NotContains(t, stdout, "start")
 }
+
+// TestLogsCmd_Contracts tests --contracts output of structured contract results.
+func TestLogsCmd_Contracts(t *testing.T) {
+	h := newLogsTestHelper(t)
+	h.chdir()
+	defer h.restore()
+
+	h.createRun("test-run", "test-pipeline", "failed", time.Now())
+	require.NoError(t, h.store.SaveContractResult(&state.ContractResultRecord{
+		RunID: "test-run", StepID: "plan", ContractType: "json_schema", Name: "plan.schema.json",
+		Passed: true, DurationMs: 12,
+	}))
+	require.NoError(t, h.store.SaveContractResult(&state.ContractResultRecord{
+		RunID: "test-run", StepID: "implement", ContractType: "json_schema", Name: "impl.schema.json",
+		Message:    "artifact does not match schema",
+		Violations: []string{"at '/summary': missing property 'summary'"},
+	}))
+
+	stdout, _, err := executeLogsCmd("--contracts")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "[PASS] plan")
+	assert.Contains(t, stdout, "[FAIL] implement")
+	assert.Contains(t, stdout, "missing property 'summary'")
+
+	stdout, _, err = executeLogsCmd("--contracts", "--errors", "--format", "json")
+	require.NoError(t, err)
+	var out ContractsOutput
+	require.NoError(t, json.Unmarshal([]byte(stdout), &out))
+	require.Len(t, out.Contracts, 1)
+	assert.Equal(t, "implement", out.Contracts[0].StepID)
+	assert.Equal(t, []string{"at '/summary': missing property 'summary'"}, out.Contracts[0].Violations)
+}
@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// MigrateConfigOptions holds options for the migrate-config command.
+type MigrateConfigOptions struct {
+	Manifest string
+	DryRun   bool
+}
+
+// configMigration upgrades one document kind by one apiVersion step.
+type configMigration struct {
+	Kind  string
+	From  string
+	To    string
+	Apply func(root *yaml.Node) []fixChange
+}
+
+// configMigrations are chained From → To until manifest.CurrentAPIVersion.
+// A breaking schema change adds a version to manifest.Compatibility and one
+// entry here per affected document kind.
+var configMigrations = []configMigration{
+	{Kind: manifest.KindManifest, From: "v0", To: "v1", Apply: migrateDeprecatedManifestFields},
+	{Kind: manifest.KindPipeline, From: "v0", To: "v1", Apply: migratePipelineV0},
+}
+
+// NewMigrateConfigCmd creates the migrate-config command.
+func NewMigrateConfigCmd() *cobra.Command {
+	var opts MigrateConfigOptions
+
+	cmd := &cobra.Command{
+		Use:   "migrate-config [file...]",
+		Short: "Upgrade wave.yaml and pipelines to the current apiVersion",
+		Long: `Upgrade configuration documents written for an older schema version.
+
+wave.yaml and pipeline files declare an apiVersion. Documents with a
+version this release no longer loads are rejected with a pointer to this
+command, which rewrites them across each breaking schema change and sets
+apiVersion to the current version. Documents without an apiVersion are
+treated as current and left alone.
+
+Without arguments the manifest and every pipeline in .agents/pipelines/
+are migrated.`,
+		Example: `  wave migrate-config
+  wave migrate-config --dry-run
+  wave migrate-config .agents/pipelines/old.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrateConfig(args, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Manifest, "manifest", "wave.yaml", "Path to manifest file")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Show the changes without writing them")
+
+	return cmd
+}
+
+func runMigrateConfig(files []string, opts MigrateConfigOptions) error {
+	explicit := len(files) > 0
+	if !explicit {
+		files = append(files, opts.Manifest)
+		pipelineDir := filepath.Join(filepath.Dir(opts.Manifest), ".agents", "pipelines")
+		entries, _ := os.ReadDir(pipelineDir)
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".yaml") {
+				files = append(files, filepath.Join(pipelineDir, entry.Name()))
+			}
+		}
+	}
+
+	verb := "Migrated"
+	if opts.DryRun {
+		verb = "Would migrate"
+	}
+	var problems []string
+	migrated := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			if !explicit && os.IsNotExist(err) {
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("%s: %s", file, err))
+			continue
+		}
+		kind, version := manifest.DocumentVersion(data)
+		if _, ok := manifest.Compatibility[kind]; !ok {
+			if explicit {
+				problems = append(problems, fmt.Sprintf("%s: not a WaveManifest or WavePipeline document", file))
+			}
+			continue
+		}
+		if version == "" || manifest.Compatibility[kind][version] == manifest.VersionCurrent {
+			continue
+		}
+		if manifest.Compatibility[kind][version] != manifest.VersionMigratable {
+			problems = append(problems, fmt.Sprintf("%s: %s", file, manifest.CheckAPIVersion(kind, version)))
+			continue
+		}
+
+		changes, err := rewriteYAMLFile(file, opts.DryRun, func(root *yaml.Node, _ []byte) []fixChange {
+			return migrateDocument(root, kind, version)
+		})
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", file, err))
+			continue
+		}
+		migrated++
+		fmt.Printf("%s %s (%s → %s):\n", verb, file, version, manifest.CurrentAPIVersion)
+		for _, c := range changes {
+			fmt.Printf("  %s\n", c)
+		}
+	}
+
+	if len(problems) > 0 {
+		fmt.Printf("✗ Migration failed:\n")
+		for _, p := range problems {
+			fmt.Printf("  - %s\n", p)
+		}
+		return NewCLIError(CodeValidationFailed, fmt.Sprintf("%d document(s) could not be migrated", len(problems)),
+			"Fix the issues listed above and re-run 'wave migrate-config'")
+	}
+	if migrated == 0 {
+		fmt.Printf("✓ All configuration is at apiVersion %s\n", manifest.CurrentAPIVersion)
+	}
+	return nil
+}
+
+// migrateDocument applies the migration chain for kind from version up to
+// the current apiVersion and records the new version.
+func migrateDocument(root *yaml.Node, kind, version string) []fixChange {
+	var changes []fixChange
+	for version != manifest.CurrentAPIVersion {
+		next := ""
+		for _, m := range configMigrations {
+			if m.Kind == kind && m.From == version {
+				changes = append(changes, m.Apply(root)...)
+				next = m.To
+				break
+			}
+		}
+		if next == "" {
+			break
+		}
+		version = next
+	}
+
+	if v := yamlMapValue(root, "apiVersion"); v != nil {
+		changes = append(changes, fixChange{Op: "~", Key: "apiVersion", Old: v.Value, New: version})
+		v.Value = version
+	} else {
+		root.Content = append([]*yaml.Node{yamlScalar("apiVersion"), yamlScalar(version)}, root.Content...)
+		changes = append(changes, fixChange{Op: "+", Key: "apiVersion", New: version})
+	}
+	return changes
+}
+
+// migratePipelineV0 upgrades a pre-versioning pipeline: pipeline_outputs get
+// the "string" type they used to default to, and contract on_failure: retry
+// becomes on_failure: fail with the retries moved to the step's retry
+// policy.
+func migratePipelineV0(root *yaml.Node) []fixChange {
+	var changes []fixChange
+
+	if outs := yamlMapValue(root, "pipeline_outputs"); outs != nil && outs.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(outs.Content); i += 2 {
+			out := outs.Content[i+1]
+			t := yamlMapValue(out, "type")
+			if t != nil && t.Value != "" {
+				continue
+			}
+			if t != nil {
+				t.Value = "string"
+			} else {
+				yamlMapSet(out, "type", yamlScalar("string"))
+			}
+			changes = append(changes, fixChange{Op: "+", Key: fmt.Sprintf("pipeline_outputs[%s].type", outs.Content[i].Value), New: "string", Note: "was implicit"})
+		}
+	}
+
+	steps := yamlMapValue(root, "steps")
+	if steps == nil || steps.Kind != yaml.SequenceNode {
+		return changes
+	}
+	for _, step := range steps.Content {
+		id := ""
+		if n := yamlMapValue(step, "id"); n != nil {
+			id = n.Value
+		}
+		handover := yamlMapValue(step, "handover")
+		type keyedContract struct {
+			key  string
+			node *yaml.Node
+		}
+		var contracts []keyedContract
+		if c := yamlMapValue(handover, "contract"); c != nil {
+			contracts = append(contracts, keyedContract{"handover.contract", c})
+		}
+		if list := yamlMapValue(handover, "contracts"); list != nil && list.Kind == yaml.SequenceNode {
+			for i, c := range list.Content {
+				contracts = append(contracts, keyedContract{fmt.Sprintf("handover.contracts[%d]", i), c})
+			}
+		}
+
+		for _, c := range contracts {
+			onFailure := yamlMapValue(c.node, "on_failure")
+			if onFailure == nil || onFailure.Value != "retry" {
+				continue
+			}
+			onFailure.Value = "fail"
+			changes = append(changes, fixChange{Op: "~", Key: fmt.Sprintf("steps[%s].%s.on_failure", id, c.key), Old: "retry", New: "fail"})
+
+			maxRetries := yamlMapValue(c.node, "max_retries")
+			n := 0
+			if maxRetries != nil {
+				n, _ = strconv.Atoi(maxRetries.Value)
+			}
+			retry := yamlMapValue(step, "retry")
+			if n <= 0 || yamlMapValue(retry, "max_attempts") != nil {
+				continue
+			}
+			if retry == nil {
+				retry = &yaml.Node{Kind: yaml.MappingNode}
+				yamlMapSet(step, "retry", retry)
+			}
+			attempts := strconv.Itoa(n + 1)
+			yamlMapSet(retry, "max_attempts", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: attempts})
+			changes = append(changes, fixChange{Op: "+", Key: fmt.Sprintf("steps[%s].retry.max_attempts", id), New: attempts, Note: "from contract max_retries"})
+		}
+	}
+	return changes
+}
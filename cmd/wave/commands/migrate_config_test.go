@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"os"
+	"testing"
+
+	"github.com/recinq/wave/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const v0Pipeline = `apiVersion: v0
+kind: WavePipeline
+metadata:
+  name: old
+steps:
+  - id: implement
+    persona: craftsman
+    exec:
+      type: prompt
+      source: "implement"
+    output_artifacts:
+      - name: result
+        path: result.json
+    handover:
+      contract:
+        type: json_schema
+        source: result.json
+        schema_path: .agents/contracts/result.schema.json
+        on_failure: retry
+        max_retries: 2
+pipeline_outputs:
+  result:
+    step: implement
+    artifact: result
+`
+
+func TestRunMigrateConfig_Pipeline(t *testing.T) {
+	h := newTestHelper(t)
+	h.chdir()
+	defer h.restore()
+
+	h.writeFile("wave.yaml", "apiVersion: v1\nkind: WaveManifest\n")
+	h.writeFile(".agents/pipelines/old.yaml", v0Pipeline)
+
+	// Before migration the loader points at the command.
+	_, err := (&pipeline.YAMLPipelineLoader{}).Load(".agents/pipelines/old.yaml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "wave migrate-config")
+
+	out := captureOutput(t, func() {
+		require.NoError(t, runMigrateConfig(nil, MigrateConfigOptions{Manifest: "wave.yaml", DryRun: true}))
+	})
+	assert.Contains(t, out, "Would migrate .agents/pipelines/old.yaml (v0 → v1):")
+	data, err := os.ReadFile(".agents/pipelines/old.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, v0Pipeline, string(data), "dry run must not write")
+
+	out = captureOutput(t, func() {
+		require.NoError(t, runMigrateConfig(nil, MigrateConfigOptions{Manifest: "wave.yaml"}))
+	})
+	assert.Contains(t, out, "~ steps[implement].handover.contract.on_failure: retry → fail")
+	assert.Contains(t, out, "+ steps[implement].retry.max_attempts: 3")
+	assert.Contains(t, out, "+ pipeline_outputs[result].type: string")
+	assert.Contains(t, out, "~ apiVersion: v0 → v1")
+
+	p, err := (&pipeline.YAMLPipelineLoader{}).Load(".agents/pipelines/old.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", p.APIVersion)
+	assert.Equal(t, 3, p.Steps[0].Retry.MaxAttempts)
+	assert.Equal(t, "fail", p.Steps[0].Handover.Contract.OnFailure)
+	assert.Equal(t, "string", p.PipelineOutputs["result"].Type)
+
+	out = captureOutput(t, func() {
+		require.NoError(t, runMigrateConfig(nil, MigrateConfigOptions{Manifest: "wave.yaml"}))
+	})
+	assert.Contains(t, out, "All configuration is at apiVersion v1")
+}
+
+func TestRunMigrateConfig_UnknownVersion(t *testing.T) {
+	h := newTestHelper(t)
+	h.chdir()
+	defer h.restore()
+
+	h.writeFile("wave.yaml", "apiVersion: v7\nkind: WaveManifest\n")
+
+	var err error
+	out := captureOutput(t, func() {
+		err = runMigrateConfig(nil, MigrateConfigOptions{Manifest: "wave.yaml"})
+	})
+	require.Error(t, err)
+	assert.Contains(t, out, `unknown WaveManifest apiVersion "v7"`)
+}
@@ -0,0 +1,392 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/display"
+	"github.com/recinq/wave/internal/metrics"
+	"github.com/recinq/wave/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// opsRecentErrors is how many of a run's most recent error events the
+// run detail view shows.
+const opsRecentErrors = 3
+
+// OpsShowOptions holds options for `wave ops show`.
+type OpsShowOptions struct {
+	RunID  string
+	Format string // table, json
+}
+
+// OpsRunReport is everything recorded about one run, gathered in one view.
+type OpsRunReport struct {
+	Run          StatusRunInfo    `json:"run"`
+	Steps        []OpsStepInfo    `json:"steps"`
+	Events       OpsEventSummary  `json:"events"`
+	Artifacts    []OpsArtifact    `json:"artifacts"`
+	Deliverables []OpsDeliverable `json:"deliverables"`
+	Performance  []OpsStepMetric  `json:"performance"`
+}
+
+// OpsStepInfo is the recorded state of one step.
+type OpsStepInfo struct {
+	StepID          string  `json:"step_id"`
+	State           string  `json:"state"`
+	Duration        string  `json:"duration,omitempty"`
+	DurationMs      int64   `json:"duration_ms,omitempty"`
+	Retries         int     `json:"retries"`
+	Visits          int     `json:"visits,omitempty"`
+	CostUSD         float64 `json:"cost_usd,omitempty"`
+	FailureCategory string  `json:"failure_category,omitempty"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// OpsEventSummary condenses a run's event log.
+type OpsEventSummary struct {
+	Total        int            `json:"total"`
+	ByState      map[string]int `json:"by_state,omitempty"`
+	RecentErrors []OpsEvent     `json:"recent_errors,omitempty"`
+}
+
+// OpsEvent is one event from the log.
+type OpsEvent struct {
+	Time    string `json:"time"`
+	StepID  string `json:"step_id,omitempty"`
+	State   string `json:"state"`
+	Message string `json:"message,omitempty"`
+}
+
+// OpsArtifact is an artifact a step produced.
+type OpsArtifact struct {
+	StepID    string `json:"step_id"`
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	Type      string `json:"type,omitempty"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// OpsDeliverable is an outcome a step delivered (PR, branch, deployment, ...).
+type OpsDeliverable struct {
+	StepID string `json:"step_id,omitempty"`
+	Type   string `json:"type"`
+	Label  string `json:"label,omitempty"`
+	Value  string `json:"value"`
+}
+
+// OpsStepMetric is the performance recorded for one step execution.
+type OpsStepMetric struct {
+	StepID             string `json:"step_id"`
+	Persona            string `json:"persona,omitempty"`
+	DurationMs         int64  `json:"duration_ms"`
+	TokensUsed         int    `json:"tokens_used"`
+	FilesModified      int    `json:"files_modified"`
+	ArtifactsGenerated int    `json:"artifacts_generated"`
+	Success            bool   `json:"success"`
+}
+
+// opsStore is the store surface the run detail view reads.
+type opsStore interface {
+	statusStore
+	GetStepStates(pipelineID string) ([]state.StepStateRecord, error)
+	GetStepCosts(runID string) ([]state.StepCostRecord, error)
+	GetEvents(runID string, opts state.EventQueryOptions) ([]state.LogRecord, error)
+	GetArtifacts(runID string, stepID string) ([]state.ArtifactRecord, error)
+	GetOutcomes(runID string) ([]state.OutcomeRecord, error)
+}
+
+// performanceSource returns the performance metrics recorded for a run.
+type performanceSource interface {
+	GetPerformanceMetrics(runID string, stepID string) ([]metrics.PerformanceMetricRecord, error)
+}
+
+// NewOpsCmd creates the `wave ops` parent command.
+func NewOpsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ops",
+		Short: "Inspect pipeline runs",
+		Long: `Inspect pipeline runs from the records in the state database.
+
+'wave ops show' gathers what 'wave status', 'wave logs', 'wave artifacts'
+and the performance metrics record about a run into one view.`,
+	}
+
+	cmd.AddCommand(newOpsShowCmd())
+
+	return cmd
+}
+
+func newOpsShowCmd() *cobra.Command {
+	var opts OpsShowOptions
+	cmd := &cobra.Command{
+		Use:   "show <run-id>",
+		Short: "Show everything recorded about a run",
+		Long: `Show a run's record, its steps with durations and retries, a summary
+of its event log, its artifacts and deliverables, per-step performance
+metrics and the environment snapshot taken when it started.`,
+		Example: `  wave ops show impl-issue-20260328-143022
+  wave ops show impl-issue-20260328-143022 --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.RunID = args[0]
+			opts.Format = ResolveFormat(cmd, opts.Format)
+			return runOpsShow(opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Format, "format", "table", "Output format (table, json)")
+	return cmd
+}
+
+func runOpsShow(opts OpsShowOptions) error {
+	dbPath := ".agents/state.db"
+
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return NewCLIError(CodeStateDBError, "state database not found", "Run 'wave run' to create the state database")
+	}
+
+	store, err := state.NewStateStore(dbPath)
+	if err != nil {
+		return NewCLIError(CodeStateDBError, fmt.Sprintf("failed to open state database: %s", err), "Check .agents/state.db file permissions").WithCause(err)
+	}
+	defer store.Close()
+
+	report, err := buildOpsRunReport(store, metrics.NewStore(state.UnderlyingDB(store)), opts.RunID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return NewCLIError(CodeRunNotFound, fmt.Sprintf("run not found: %s", opts.RunID), "Run 'wave list runs' to see recorded runs").WithCause(err)
+		}
+		return err
+	}
+
+	if opts.Format == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to marshal JSON: %s", err), "This is an internal serialization error").WithCause(err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	renderOpsRunReport(report)
+	return nil
+}
+
+// buildOpsRunReport gathers the run's records. Only the run record itself is
+// required; sections whose records cannot be read are left empty.
+func buildOpsRunReport(store opsStore, perf performanceSource, runID string) (*OpsRunReport, error) {
+	record, err := store.GetRun(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &OpsRunReport{
+		Run:          statusRunTree(store, record, make(map[string]bool)),
+		Steps:        []OpsStepInfo{},
+		Artifacts:    []OpsArtifact{},
+		Deliverables: []OpsDeliverable{},
+		Performance:  []OpsStepMetric{},
+	}
+	if env, err := store.GetRunEnvironment(runID); err == nil {
+		report.Run.Environment = env
+	}
+
+	stepCosts := make(map[string]float64)
+	if costs, err := store.GetStepCosts(runID); err == nil {
+		for _, c := range costs {
+			stepCosts[c.StepID] += c.CostUSD
+			report.Run.CostUSD += c.CostUSD
+		}
+	}
+
+	if steps, err := store.GetStepStates(runID); err == nil {
+		for _, s := range steps {
+			info := OpsStepInfo{
+				StepID:          s.StepID,
+				State:           string(s.State),
+				Retries:         s.RetryCount,
+				Visits:          s.VisitCount,
+				CostUSD:         stepCosts[s.StepID],
+				FailureCategory: s.FailureCategory,
+				Error:           s.ErrorMessage,
+			}
+			if s.StartedAt != nil {
+				end := time.Now()
+				if s.CompletedAt != nil {
+					end = *s.CompletedAt
+				}
+				info.DurationMs = end.Sub(*s.StartedAt).Milliseconds()
+				info.Duration = formatElapsed(end.Sub(*s.StartedAt))
+			}
+			report.Steps = append(report.Steps, info)
+		}
+	}
+
+	if events, err := store.GetEvents(runID, state.EventQueryOptions{}); err == nil {
+		report.Events.Total = len(events)
+		report.Events.ByState = make(map[string]int)
+		for _, ev := range events {
+			report.Events.ByState[ev.State]++
+		}
+	}
+	if errs, err := store.GetEvents(runID, state.EventQueryOptions{ErrorsOnly: true, TailLimit: opsRecentErrors}); err == nil {
+		for _, ev := range errs {
+			report.Events.RecentErrors = append(report.Events.RecentErrors, OpsEvent{
+				Time:    ev.Timestamp.Format("15:04:05"),
+				StepID:  ev.StepID,
+				State:   ev.State,
+				Message: ev.Message,
+			})
+		}
+	}
+
+	if artifacts, err := store.GetArtifacts(runID, ""); err == nil {
+		for _, a := range artifacts {
+			report.Artifacts = append(report.Artifacts, OpsArtifact{
+				StepID:    a.StepID,
+				Name:      a.Name,
+				Path:      a.Path,
+				Type:      a.Type,
+				SizeBytes: a.SizeBytes,
+			})
+		}
+	}
+
+	if outcomes, err := store.GetOutcomes(runID); err == nil {
+		for _, o := range outcomes {
+			report.Deliverables = append(report.Deliverables, OpsDeliverable{
+				StepID: o.StepID,
+				Type:   string(o.Type),
+				Label:  o.Label,
+				Value:  o.Value,
+			})
+		}
+	}
+
+	if metricRecords, err := perf.GetPerformanceMetrics(runID, ""); err == nil {
+		for _, m := range metricRecords {
+			report.Performance = append(report.Performance, OpsStepMetric{
+				StepID:             m.StepID,
+				Persona:            m.Persona,
+				DurationMs:         m.DurationMs,
+				TokensUsed:         m.TokensUsed,
+				FilesModified:      m.FilesModified,
+				ArtifactsGenerated: m.ArtifactsGenerated,
+				Success:            m.Success,
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// renderOpsRunReport prints the run detail view.
+func renderOpsRunReport(r *OpsRunReport) {
+	run := r.Run
+	reset := conditionalColor("\033[0m")
+
+	fmt.Printf("Run ID:     %s\n", run.RunID)
+	fmt.Printf("Pipeline:   %s\n", run.Pipeline)
+	fmt.Printf("Status:     %s%s%s\n", statusColor(run.Status), run.Status, reset)
+	fmt.Printf("Started:    %s\n", run.StartedAt)
+	if run.CompletedAt != "" {
+		fmt.Printf("Completed:  %s\n", run.CompletedAt)
+	}
+	fmt.Printf("Elapsed:    %s\n", run.Elapsed)
+	fmt.Printf("Tokens:     %s\n", run.TokensStr)
+	if run.CostUSD > 0 {
+		fmt.Printf("Cost:       %s\n", display.FormatCost(run.CostUSD))
+	}
+	if run.Input != "" {
+		fmt.Printf("Input:      %s\n", truncateString(run.Input, 100))
+	}
+	if run.Error != "" {
+		fmt.Printf("Error:      %s\n", run.Error)
+	}
+	if run.ParentRunID != "" {
+		fmt.Printf("Parent:     %s\n", run.ParentRunID)
+	}
+	if run.Environment != nil {
+		printRunEnvironment(run.Environment)
+	}
+
+	fmt.Println()
+	fmt.Println("Steps:")
+	if len(r.Steps) == 0 {
+		fmt.Println("  (none recorded)")
+	}
+	for _, s := range r.Steps {
+		detail := s.Duration
+		if s.Retries > 0 {
+			detail += fmt.Sprintf(", %d retries", s.Retries)
+		}
+		if s.CostUSD > 0 {
+			detail += ", " + display.FormatCost(s.CostUSD)
+		}
+		fmt.Printf("  %-20s %s%-10s%s %s\n", s.StepID, statusColor(s.State), s.State, reset, strings.TrimPrefix(detail, ", "))
+		if s.Error != "" {
+			msg := s.Error
+			if s.FailureCategory != "" {
+				msg = s.FailureCategory + ": " + msg
+			}
+			fmt.Printf("  %-20s ↳ %s\n", "", truncateString(msg, 100))
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Events:     %d", r.Events.Total)
+	if len(r.Events.ByState) > 0 {
+		states := make([]string, 0, len(r.Events.ByState))
+		for s := range r.Events.ByState {
+			states = append(states, s)
+		}
+		sort.Strings(states)
+		parts := make([]string, len(states))
+		for i, s := range states {
+			parts[i] = fmt.Sprintf("%s %d", s, r.Events.ByState[s])
+		}
+		fmt.Printf(" (%s)", strings.Join(parts, ", "))
+	}
+	fmt.Println()
+	for _, ev := range r.Events.RecentErrors {
+		fmt.Printf("  %s  %-20s %s\n", ev.Time, ev.StepID, truncateString(ev.Message, 80))
+	}
+
+	if len(r.Artifacts) > 0 {
+		fmt.Println()
+		fmt.Println("Artifacts:")
+		for _, a := range r.Artifacts {
+			fmt.Printf("  %-20s %-24s %8s  %s\n", a.StepID, a.Name, formatSize(a.SizeBytes), a.Path)
+		}
+	}
+
+	if len(r.Deliverables) > 0 {
+		fmt.Println()
+		fmt.Println("Deliverables:")
+		for _, d := range r.Deliverables {
+			label := d.Label
+			if label == "" {
+				label = d.Type
+			}
+			fmt.Printf("  %-12s %-24s %s\n", d.Type, label, d.Value)
+		}
+	}
+
+	if len(r.Performance) > 0 {
+		fmt.Println()
+		fmt.Println("Performance:")
+		for _, m := range r.Performance {
+			result := "ok"
+			if !m.Success {
+				result = "failed"
+			}
+			fmt.Printf("  %-20s %8s  %6s tokens  %d files  %d artifacts  %s\n", m.StepID,
+				formatElapsed(time.Duration(m.DurationMs)*time.Millisecond), formatTokens(m.TokensUsed),
+				m.FilesModified, m.ArtifactsGenerated, result)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/metrics"
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// executeOpsCmd runs `wave ops` with args and returns what it printed.
+func executeOpsCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewOpsCmd()
+	cmd.SetArgs(args)
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := cmd.Execute()
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	return buf.String(), err
+}
+
+func TestOpsShow(t *testing.T) {
+	h := newStatusTestHelper(t)
+	h.chdir()
+	defer h.restore()
+
+	started := time.Now().Add(-5 * time.Minute)
+	h.createRun("ops-run", "impl-issue", "failed", "implement", 42000, started, nil)
+	store := h.store
+	require.NoError(t, store.SavePipelineState("ops-run", "failed", ""))
+	require.NoError(t, store.SaveStepState("ops-run", "plan", state.StateCompleted, ""))
+	require.NoError(t, store.SaveStepState("ops-run", "implement", state.StateFailed, "contract validation failed"))
+	require.NoError(t, store.LogEvent("ops-run", "plan", "completed", "navigator", "plan done", 1000, 0, "", "", ""))
+	require.NoError(t, store.LogEvent("ops-run", "implement", "failed", "craftsman", "contract validation failed", 0, 0, "", "", ""))
+	require.NoError(t, store.RegisterArtifact("ops-run", "plan", "plan", ".agents/output/plan.md", "markdown", 2048))
+	require.NoError(t, store.RecordOutcome("ops-run", "plan", "branch", "Feature branch", "feat/ops", "", nil))
+	require.NoError(t, store.RecordStepCost(&state.StepCostRecord{RunID: "ops-run", StepID: "plan", PipelineName: "impl-issue", CostUSD: 0.25}))
+	require.NoError(t, store.RecordStepCost(&state.StepCostRecord{RunID: "ops-run", StepID: "implement", PipelineName: "impl-issue", CostUSD: 0.5}))
+	require.NoError(t, metrics.NewStore(state.UnderlyingDB(store)).RecordPerformanceMetric(&metrics.PerformanceMetricRecord{
+		RunID: "ops-run", StepID: "plan", PipelineName: "impl-issue", StartedAt: started, DurationMs: 45000, TokensUsed: 1000, Success: true,
+	}))
+
+	out, err := executeOpsCmd(t, "show", "ops-run", "--format", "json")
+	require.NoError(t, err)
+	var report OpsRunReport
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+
+	assert.Equal(t, "ops-run", report.Run.RunID)
+	assert.InDelta(t, 0.75, report.Run.CostUSD, 1e-9)
+	require.Len(t, report.Steps, 2)
+	steps := map[string]OpsStepInfo{}
+	for _, s := range report.Steps {
+		steps[s.StepID] = s
+	}
+	assert.Equal(t, "failed", steps["implement"].State)
+	assert.Equal(t, "contract validation failed", steps["implement"].Error)
+	assert.InDelta(t, 0.25, steps["plan"].CostUSD, 1e-9)
+	assert.Equal(t, 2, report.Events.Total)
+	assert.Equal(t, 1, report.Events.ByState["failed"])
+	require.Len(t, report.Events.RecentErrors, 1)
+	assert.Equal(t, "implement", report.Events.RecentErrors[0].StepID)
+	require.Len(t, report.Artifacts, 1)
+	assert.Equal(t, int64(2048), report.Artifacts[0].SizeBytes)
+	require.Len(t, report.Deliverables, 1)
+	assert.Equal(t, "feat/ops", report.Deliverables[0].Value)
+	require.Len(t, report.Performance, 1)
+	assert.Equal(t, int64(45000), report.Performance[0].DurationMs)
+
+	out, err = executeOpsCmd(t, "show", "ops-run")
+	require.NoError(t, err)
+	for _, want := range []string{"Run ID:     ops-run", "Cost:       $0.75", "Steps:", "contract validation failed",
+		"Events:     2 (completed 1, failed 1)", "Artifacts:", ".agents/output/plan.md", "Deliverables:", "feat/ops", "Performance:"} {
+		assert.Contains(t, out, want)
+	}
+}
+
+func TestOpsShow_RunNotFound(t *testing.T) {
+	h := newStatusTestHelper(t)
+	h.chdir()
+	defer h.restore()
+
+	_, err := executeOpsCmd(t, "show", "missing-run")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "run not found: missing-run")
+}
@@ -169,7 +169,7 @@ type EmitterResult struct {
 // Modes:
 //   - json:  NDJSON to stdout, no progress display
 //   - text:  Plain text progress to stderr, no stdout
-//   - quiet: Only final result to stderr, no stdout
+//   - quiet: Only final result to stderr, no stdout (no tool-activity feed)
 //   - auto:  BubbleTea TUI if TTY, plain text if pipe
 func CreateEmitter(cfg OutputConfig, pipelineID, pipelineName string, steps []pipeline.Step, m *manifest.Manifest) EmitterResult {
 	switch cfg.Format {
@@ -180,7 +180,7 @@ func CreateEmitter(cfg OutputConfig, pipelineID, pipelineName string, steps []pi
 		}
 
 	case OutputFormatText:
-		progress := display.NewBasicProgressDisplayWithVerbose(cfg.Verbose)
+		progress := display.NewBasicProgressDisplayWithActivity(cfg.Verbose, true)
 		throttled := display.NewThrottledProgressEmitter(progress)
 		return EmitterResult{
 			Emitter:  event.NewProgressOnlyEmitter(throttled),
@@ -252,7 +252,7 @@ func createAutoEmitter(cfg OutputConfig, pipelineID, pipelineName string, steps
 	}
 
 	// Non-TTY: plain text to stderr
-	progress := display.NewBasicProgressDisplayWithVerbose(cfg.Verbose)
+	progress := display.NewBasicProgressDisplayWithActivity(cfg.Verbose, true)
 	throttled := display.NewThrottledProgressEmitter(progress)
 	return EmitterResult{
 		Emitter:  event.NewProgressOnlyEmitter(throttled),
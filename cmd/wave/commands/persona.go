@@ -21,11 +21,13 @@ func NewPersonaCmd() *cobra.Command {
 
 Subcommands:
   create    Scaffold a new persona from a built-in template
-  list      List available personas`,
+  list      List available personas
+  eval      Run an eval suite against a persona`,
 	}
 
 	cmd.AddCommand(newPersonaCreateCmd())
 	cmd.AddCommand(newPersonaListCmd())
+	cmd.AddCommand(newPersonaEvalCmd())
 
 	return cmd
 }
@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/personaeval"
+	"github.com/recinq/wave/internal/pipeline"
+	"github.com/recinq/wave/internal/skill"
+	"github.com/recinq/wave/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// PersonaEvalOptions holds options for the persona eval command.
+type PersonaEvalOptions struct {
+	Suite    string
+	Cases    []string
+	Adapter  string
+	Model    string
+	Mock     bool
+	Format   string
+	Manifest string
+}
+
+func newPersonaEvalCmd() *cobra.Command {
+	var opts PersonaEvalOptions
+
+	cmd := &cobra.Command{
+		Use:   "eval <name>",
+		Short: "Run an eval suite against a persona",
+		Long: `Run each case of an eval suite through the persona and check the responses
+against the suite's assertions. Use it to regression-test persona prompt changes.
+
+A suite is a YAML file:
+
+  persona: reviewer
+  defaults:
+    max_duration: 2m        # wall-clock limit per case
+    max_tokens: 20000       # token limit per case
+  cases:
+    - name: flags-sql-injection
+      prompt: Review fixtures/handler.go and report security issues.
+      fixtures: fixtures/sqli       # mounted read-only at /fixtures
+      assert:
+        contains: ["SQL injection"] # case-insensitive
+        not_contains: ["LGTM"]
+    - name: structured-verdict
+      prompt: Review the diff and answer with a JSON verdict.
+      assert:
+        json_schema: schemas/review.schema.json
+
+Paths are relative to the suite file. Each case runs as a single-step
+pipeline, so the persona's permissions, sandbox and env apply. The command
+exits non-zero when any case fails.`,
+		Example: `  wave persona eval reviewer --suite evals/reviewer.yaml
+  wave persona eval reviewer --suite evals/reviewer.yaml --case flags-sql-injection
+  wave persona eval reviewer --suite evals/reviewer.yaml --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Format = ResolveFormat(cmd, opts.Format)
+			return runPersonaEval(args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Suite, "suite", "", "Path to the eval suite YAML (required)")
+	cmd.Flags().StringSliceVar(&opts.Cases, "case", nil, "Only run the named case (repeatable)")
+	cmd.Flags().StringVar(&opts.Adapter, "adapter", "", "Override adapter")
+	cmd.Flags().StringVar(&opts.Model, "model", "", "Override model")
+	cmd.Flags().BoolVar(&opts.Mock, "mock", false, "Use mock adapter (for testing)")
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "Output format (text, json)")
+	cmd.Flags().StringVar(&opts.Manifest, "manifest", "wave.yaml", "Path to manifest file")
+	_ = cmd.MarkFlagRequired("suite")
+
+	return cmd
+}
+
+func runPersonaEval(name string, opts PersonaEvalOptions) error {
+	m, err := loadManifestStrict(opts.Manifest)
+	if err != nil {
+		return err
+	}
+	if m.GetPersona(name) == nil {
+		return NewCLIError(CodeInvalidArgs, fmt.Sprintf("persona %q not found in manifest", name), "Run 'wave list personas' to see configured personas")
+	}
+
+	suite, err := personaeval.LoadSuite(opts.Suite)
+	if err != nil {
+		return NewCLIError(CodeValidationFailed, err.Error(), "Check the suite file against 'wave persona eval --help'").WithCause(err)
+	}
+	if suite.Persona != "" && suite.Persona != name {
+		return NewCLIError(CodeInvalidArgs,
+			fmt.Sprintf("suite %s is for persona %q, not %q", opts.Suite, suite.Persona, name),
+			fmt.Sprintf("Run 'wave persona eval %s --suite %s'", suite.Persona, opts.Suite))
+	}
+	for _, c := range opts.Cases {
+		if !suiteHasCase(suite, c) {
+			return NewCLIError(CodeInvalidArgs, fmt.Sprintf("case %q not found in suite", c), "Check the case names in "+opts.Suite)
+		}
+	}
+
+	var runner adapter.AdapterRunner
+	if opts.Mock {
+		runner = adaptertest.NewMockAdapter()
+	} else {
+		adapterName := opts.Adapter
+		if adapterName == "" {
+			for n := range m.Adapters {
+				adapterName = n
+				break
+			}
+		}
+		runner = adapter.ResolveAdapter(adapterName)
+	}
+
+	wsRoot := m.Runtime.WorkspaceRoot
+	if wsRoot == "" {
+		wsRoot = ".agents/workspaces"
+	}
+	execOpts := []pipeline.ExecutorOption{
+		pipeline.WithSkillStore(skill.NewDirectoryStore(skill.DefaultSources()...)),
+	}
+	if wsManager, err := workspace.NewWorkspaceManager(wsRoot); err == nil {
+		execOpts = append(execOpts, pipeline.WithWorkspaceManager(wsManager))
+	}
+	if opts.Model != "" {
+		execOpts = append(execOpts, pipeline.WithModelOverride(opts.Model))
+	}
+	if opts.Adapter != "" {
+		execOpts = append(execOpts, pipeline.WithAdapterOverride(opts.Adapter))
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	text := opts.Format != "json"
+	report := personaeval.Run(ctx, suite, name,
+		&personaeval.PipelineRunner{Manifest: m, Adapter: runner, Options: execOpts},
+		personaeval.RunOptions{
+			Only: opts.Cases,
+			OnCase: func(r personaeval.CaseResult) {
+				if text {
+					printEvalCase(r)
+				}
+			},
+		})
+
+	if text {
+		fmt.Printf("\n%d passed, %d failed\n", report.Passed, report.Failed)
+	} else {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to marshal JSON: %s", err), "This is an internal serialization error").WithCause(err)
+		}
+		fmt.Println(string(jsonBytes))
+	}
+
+	if !report.OK() {
+		return NewCLIError(CodeValidationFailed,
+			fmt.Sprintf("%d of %d eval cases failed for persona %s", report.Failed, report.Passed+report.Failed, name),
+			"Inspect the failures above; rerun one case with --case <name>")
+	}
+	return nil
+}
+
+func suiteHasCase(s *personaeval.Suite, name string) bool {
+	for _, c := range s.Cases {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func printEvalCase(r personaeval.CaseResult) {
+	status := "PASS"
+	if !r.Passed {
+		status = "FAIL"
+	}
+	elapsed := time.Duration(r.DurationMs) * time.Millisecond
+	fmt.Printf("%s  %-40s %6s  %s tokens\n", status, r.Name, elapsed.Round(100*time.Millisecond), formatTokens(r.Tokens))
+	if r.Error != "" {
+		fmt.Printf("      error: %s\n", strings.TrimSpace(r.Error))
+	}
+	for _, f := range r.Failures {
+		fmt.Printf("      - %s\n", f)
+	}
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupPersonaEvalProject copies the valid test manifest into a temp
+// project and writes suite as evals/suite.yaml.
+func setupPersonaEvalProject(t *testing.T, suite string) (manifestPath, suitePath string) {
+	t.Helper()
+	dir := t.TempDir()
+	src := filepath.Join("testdata", "valid")
+	for _, rel := range []string{"wave.yaml", "personas/navigator.md", "personas/craftsman.md"} {
+		data, err := os.ReadFile(filepath.Join(src, rel))
+		require.NoError(t, err)
+		dst := filepath.Join(dir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0755))
+		require.NoError(t, os.WriteFile(dst, data, 0644))
+	}
+	suitePath = filepath.Join(dir, "evals", "suite.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(suitePath), 0755))
+	require.NoError(t, os.WriteFile(suitePath, []byte(suite), 0644))
+
+	origDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(origDir) })
+	return filepath.Join(dir, "wave.yaml"), suitePath
+}
+
+func TestRunPersonaEval_Mock(t *testing.T) {
+	manifestPath, suitePath := setupPersonaEvalProject(t, `persona: navigator
+cases:
+  - name: answers
+    prompt: Summarise the repository layout.
+    assert:
+      max_duration: 1m
+  - name: impossible
+    prompt: Summarise the repository layout.
+    assert:
+      contains: ["a phrase the mock never says"]
+`)
+	opts := PersonaEvalOptions{Suite: suitePath, Mock: true, Format: "json", Manifest: manifestPath}
+
+	err := runPersonaEval("navigator", opts)
+	var cliErr *CLIError
+	require.ErrorAs(t, err, &cliErr)
+	assert.Equal(t, CodeValidationFailed, cliErr.Code)
+	assert.Contains(t, cliErr.Message, "1 of 2 eval cases failed")
+
+	opts.Cases = []string{"answers"}
+	assert.NoError(t, runPersonaEval("navigator", opts))
+}
+
+func TestRunPersonaEval_Errors(t *testing.T) {
+	manifestPath, suitePath := setupPersonaEvalProject(t, "persona: navigator\ncases:\n  - name: a\n    prompt: p\n")
+
+	var cliErr *CLIError
+	err := runPersonaEval("ghost", PersonaEvalOptions{Suite: suitePath, Manifest: manifestPath})
+	require.ErrorAs(t, err, &cliErr)
+	assert.Contains(t, cliErr.Message, `persona "ghost" not found`)
+
+	err = runPersonaEval("craftsman", PersonaEvalOptions{Suite: suitePath, Manifest: manifestPath})
+	require.ErrorAs(t, err, &cliErr)
+	assert.Contains(t, cliErr.Message, `is for persona "navigator"`)
+
+	err = runPersonaEval("navigator", PersonaEvalOptions{Suite: suitePath, Manifest: manifestPath, Cases: []string{"b"}})
+	require.ErrorAs(t, err, &cliErr)
+	assert.Contains(t, cliErr.Message, `case "b" not found`)
+
+	err = runPersonaEval("navigator", PersonaEvalOptions{Suite: filepath.Join(filepath.Dir(suitePath), "missing.yaml"), Manifest: manifestPath})
+	require.ErrorAs(t, err, &cliErr)
+	assert.Equal(t, CodeValidationFailed, cliErr.Code)
+}
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/spf13/cobra"
+)
+
+const pinDBPath = ".agents/state.db"
+
+// PinOptions holds options for the pin command.
+type PinOptions struct {
+	RunIDs []string
+	Remove bool
+}
+
+// NewPinCmd creates the pin command.
+func NewPinCmd() *cobra.Command {
+	var opts PinOptions
+
+	cmd := &cobra.Command{
+		Use:   "pin [run-id...]",
+		Short: "Protect runs from cleanup",
+		Long: `Pin runs so they are kept as references for audits and comparisons.
+
+Pinned runs and their workspaces are skipped by 'wave clean', 'wave cleanup'
+and the runtime.workspace_cleanup policy, and are marked in 'wave list runs'.
+Without arguments, lists the pinned runs.`,
+		Example: `  wave pin impl-issue-20261016-101500-ab12
+  wave pin --remove impl-issue-20261016-101500-ab12
+  wave pin`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			opts.RunIDs = args
+			return runPin(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Remove, "remove", false, "Unpin the runs so cleanup can remove them again")
+
+	return cmd
+}
+
+func runPin(opts PinOptions) error {
+	if len(opts.RunIDs) == 0 {
+		if opts.Remove {
+			return NewCLIError(CodeInvalidArgs, "--remove needs the run ids to unpin", "List pinned runs with 'wave pin'")
+		}
+		return listPinnedRuns()
+	}
+
+	if _, err := os.Stat(pinDBPath); os.IsNotExist(err) {
+		return NewCLIError(CodeStateDBError,
+			fmt.Sprintf("state database not found: %s", pinDBPath),
+			"Run wave pin from the project the runs belong to")
+	}
+	store, err := state.NewStateStore(pinDBPath)
+	if err != nil {
+		return NewCLIError(CodeStateDBError,
+			fmt.Sprintf("failed to open state database: %s", err),
+			"Check .agents/state.db file permissions").WithCause(err)
+	}
+	defer store.Close()
+
+	for _, runID := range opts.RunIDs {
+		if exists, err := store.RunExists(runID); err != nil {
+			return NewCLIError(CodeStateDBError, err.Error(), "").WithCause(err)
+		} else if !exists {
+			return NewCLIError(CodeRunNotFound, fmt.Sprintf("run not found: %s", runID),
+				"List runs with 'wave list runs'")
+		}
+		if err := store.SetRunPinned(runID, !opts.Remove); err != nil {
+			return NewCLIError(CodeInternalError, err.Error(), "").WithCause(err)
+		}
+		if opts.Remove {
+			fmt.Printf("Unpinned %s\n", runID)
+		} else {
+			fmt.Printf("Pinned %s; cleanup will keep it and its workspace\n", runID)
+		}
+	}
+	return nil
+}
+
+func listPinnedRuns() error {
+	runs := loadPinnedRuns()
+	if len(runs) == 0 {
+		fmt.Println("No pinned runs")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RUN_ID\tPIPELINE\tSTATUS\tSTARTED")
+	for _, r := range runs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.RunID, r.PipelineName, r.Status, r.StartedAt.Format("2006-01-02 15:04:05"))
+	}
+	return tw.Flush()
+}
+
+// loadPinnedRuns returns the pinned runs, newest first. A missing or
+// unreadable state database pins nothing.
+func loadPinnedRuns() []state.RunRecord {
+	if _, err := os.Stat(pinDBPath); err != nil {
+		return nil
+	}
+	store, err := state.NewReadOnlyStateStore(pinDBPath)
+	if err != nil {
+		return nil
+	}
+	defer store.Close()
+	runs, err := store.ListRuns(state.ListRunsOptions{PinnedOnly: true})
+	if err != nil {
+		return nil
+	}
+	return runs
+}
+
+// pinnedRunIDs returns the IDs of the pinned runs, which name their
+// workspaces under .agents/workspaces.
+func pinnedRunIDs() map[string]bool {
+	ids := make(map[string]bool)
+	for _, r := range loadPinnedRuns() {
+		ids[r.RunID] = true
+	}
+	return ids
+}
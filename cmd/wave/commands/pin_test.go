@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createPinTestRuns records two runs in a fresh state database and returns
+// their IDs.
+func createPinTestRuns(t *testing.T) (string, string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(".agents", 0o755))
+	store, err := state.NewStateStore(pinDBPath)
+	require.NoError(t, err)
+	defer store.Close()
+	first, err := store.CreateRun("audit", "")
+	require.NoError(t, err)
+	second, err := store.CreateRun("audit", "")
+	require.NoError(t, err)
+	return first, second
+}
+
+func TestRunPin(t *testing.T) {
+	chdirKBTemp(t)
+	assert.Error(t, runPin(PinOptions{RunIDs: []string{"missing"}}), "no state database")
+	reference, _ := createPinTestRuns(t)
+
+	require.NoError(t, runPin(PinOptions{RunIDs: []string{reference}}))
+	assert.Equal(t, map[string]bool{reference: true}, pinnedRunIDs())
+	require.NoError(t, runPin(PinOptions{}), "listing pinned runs")
+
+	err := runPin(PinOptions{RunIDs: []string{"missing"}})
+	var cliErr *CLIError
+	require.ErrorAs(t, err, &cliErr)
+	assert.Equal(t, CodeRunNotFound, cliErr.Code)
+	assert.Error(t, runPin(PinOptions{Remove: true}), "--remove needs run ids")
+
+	require.NoError(t, runPin(PinOptions{RunIDs: []string{reference}, Remove: true}))
+	assert.Empty(t, pinnedRunIDs())
+}
+
+func TestCleanKeepsPinnedRuns(t *testing.T) {
+	env := newCleanTestEnv(t)
+	defer env.cleanup()
+	reference, other := createPinTestRuns(t)
+	require.NoError(t, runPin(PinOptions{RunIDs: []string{reference}}))
+	env.createWorkspace(reference, time.Now().Add(-2*time.Hour))
+	env.createWorkspace(other, time.Now().Add(-1*time.Hour))
+
+	out, err := executeCleanCmdCapturingStdout("--pipeline", reference, "--force")
+	require.NoError(t, err)
+	assert.Contains(t, out, "pinned")
+	assert.True(t, env.workspaceExists(reference))
+
+	_, err = executeCleanCmdCapturingStdout("--older-than", "1m", "--force")
+	require.NoError(t, err)
+	assert.True(t, env.workspaceExists(reference))
+	assert.False(t, env.workspaceExists(other))
+
+	_, err = executeCleanCmdCapturingStdout("--all", "--force")
+	require.NoError(t, err)
+	assert.True(t, env.workspaceExists(reference))
+	assert.FileExists(t, pinDBPath, "the state database holding the pins is kept")
+}
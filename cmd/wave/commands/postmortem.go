@@ -203,6 +203,18 @@ func classifyFailure(errMsg string, contractErrors []string) failurePattern {
 		}
 	}
 
+	if containsAny(lower, "exceeded max_turns", "exceeded max_tool_calls") {
+		return failurePattern{
+			name:      "step_limit",
+			diagnosis: "Step limit — the agent took more turns or tool calls than the step allows, usually while looping on a failing command",
+			suggestions: []string{
+				"Check the step's adapter log for the repeated command",
+				"Narrow the step prompt or fix the failing command it retries",
+				"Raise max_turns or max_tool_calls in the step definition if the work is legitimately long",
+			},
+		}
+	}
+
 	if len(contractErrors) > 0 || containsAny(lower, "contract", "validation failed", "schema", "json schema") {
 		suggestions := []string{
 			"Check the contract schema in the pipeline definition",
@@ -0,0 +1,230 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/recinq/wave/internal/cost"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/pipeline"
+	"github.com/spf13/cobra"
+)
+
+// PromptOptions holds options for the prompt command.
+type PromptOptions struct {
+	Step     string
+	Tokens   bool
+	Manifest string
+	Format   string
+}
+
+// PromptPart is one measured component of a step's prompt.
+type PromptPart struct {
+	Name   string `json:"name"`
+	Source string `json:"source,omitempty"`
+	Bytes  int    `json:"bytes"`
+	Tokens int    `json:"tokens"`
+}
+
+// PromptTokenReport is the token breakdown of a single step's prompt.
+type PromptTokenReport struct {
+	Step          string       `json:"step"`
+	Persona       string       `json:"persona"`
+	Adapter       string       `json:"adapter"`
+	Model         string       `json:"model,omitempty"`
+	Tokenizer     string       `json:"tokenizer"`
+	Parts         []PromptPart `json:"parts"`
+	TotalTokens   int          `json:"total_tokens"`
+	ContextWindow int          `json:"context_window"`
+}
+
+// NewPromptCmd creates the prompt command.
+func NewPromptCmd() *cobra.Command {
+	var opts PromptOptions
+
+	cmd := &cobra.Command{
+		Use:   "prompt <pipeline>",
+		Short: "Show the prompts a pipeline's steps send to the model",
+		Long: `Show the static prompt components of each step in a pipeline: the
+persona system prompt, the step prompt, and any contract schema.
+
+With --tokens, each component is measured with the tokenizer of the step's
+adapter and model instead of being printed, and the total is compared
+against the model's context window. Runtime additions (injected artifacts,
+retry context) are not included.`,
+		Example: `  wave prompt impl-issue                       # Print every step's prompt
+  wave prompt impl-issue --step implement      # Print one step's prompt
+  wave prompt impl-issue --tokens              # Token breakdown per step
+  wave prompt impl-issue --tokens --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Format = ResolveFormat(cmd, opts.Format)
+			return runPrompt(args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Step, "step", "", "Only show this step")
+	cmd.Flags().BoolVar(&opts.Tokens, "tokens", false, "Show a per-component token breakdown instead of the prompt text")
+	cmd.Flags().StringVar(&opts.Manifest, "manifest", "wave.yaml", "Path to manifest file")
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "Output format (text, json)")
+
+	return cmd
+}
+
+func runPrompt(pipelineName string, opts PromptOptions) error {
+	m, err := loadManifestStrict(opts.Manifest)
+	if err != nil {
+		return err
+	}
+	p, err := pipeline.LoadByName(pipelineName)
+	if err != nil {
+		return NewCLIError(CodePipelineNotFound,
+			fmt.Sprintf("pipeline '%s' not found", pipelineName),
+			"Run 'wave list pipelines' to see available pipelines").WithCause(err)
+	}
+
+	var steps []pipeline.Step
+	found := false
+	for _, step := range p.Steps {
+		if opts.Step != "" && step.ID != opts.Step {
+			continue
+		}
+		found = true
+		if isCompositionStep(step) || (step.Exec.Type != "" && step.Exec.Type != "prompt") {
+			continue
+		}
+		steps = append(steps, step)
+	}
+	if opts.Step != "" && !found {
+		return NewCLIError(CodeInvalidArgs,
+			fmt.Sprintf("step '%s' not found in pipeline '%s'", opts.Step, pipelineName),
+			"Run 'wave run "+pipelineName+" --dry-run' to list its steps")
+	}
+
+	if opts.Tokens {
+		reports := make([]PromptTokenReport, 0, len(steps))
+		for _, step := range steps {
+			reports = append(reports, buildPromptTokenReport(step, m))
+		}
+		if opts.Format == "json" {
+			return printPromptJSON(reports)
+		}
+		printPromptTokenReports(reports)
+		return nil
+	}
+
+	if opts.Format == "json" {
+		out := make([]map[string]any, 0, len(steps))
+		for _, step := range steps {
+			parts := make(map[string]string)
+			for _, part := range collectPromptParts(step, m) {
+				parts[part.name] += part.text
+			}
+			out = append(out, map[string]any{"step": step.ID, "persona": step.Persona, "parts": parts})
+		}
+		return printPromptJSON(out)
+	}
+	for _, step := range steps {
+		fmt.Printf("=== %s ===\n", step.ID)
+		for _, part := range collectPromptParts(step, m) {
+			fmt.Printf("--- %s (%s) ---\n%s\n\n", part.name, part.source, strings.TrimRight(part.text, "\n"))
+		}
+	}
+	return nil
+}
+
+func printPromptJSON(v any) error {
+	jsonBytes, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return NewCLIError(CodeInternalError, fmt.Sprintf("failed to marshal JSON: %s", err), "This is an internal serialization error").WithCause(err)
+	}
+	fmt.Println(string(jsonBytes))
+	return nil
+}
+
+// promptPart is an unmeasured prompt component.
+type promptPart struct {
+	name   string
+	source string
+	text   string
+}
+
+// collectPromptParts gathers the static prompt components of a step. Missing
+// or unreadable files are skipped — `wave validate` reports those.
+func collectPromptParts(step pipeline.Step, m *manifest.Manifest) []promptPart {
+	var parts []promptPart
+	if persona := resolvePersonaForStep(step, m); persona != nil && persona.SystemPromptFile != "" {
+		path := persona.GetSystemPromptPath(m.RootDir)
+		if data, err := os.ReadFile(path); err == nil {
+			parts = append(parts, promptPart{name: "system_prompt", source: path, text: string(data)})
+		}
+	}
+	if text, err := readStepPrompt(step); err == nil && text != "" {
+		source := "exec.source"
+		if step.Exec.Source == "" {
+			source = step.Exec.SourcePath
+		}
+		parts = append(parts, promptPart{name: "step_prompt", source: source, text: text})
+	}
+	for _, c := range step.Handover.EffectiveContracts() {
+		switch {
+		case c.Schema != "":
+			parts = append(parts, promptPart{name: "contract_schema", source: c.Type, text: c.Schema})
+		case c.SchemaPath != "" && !strings.Contains(c.SchemaPath, "{{"):
+			if data, err := os.ReadFile(c.SchemaPath); err == nil {
+				parts = append(parts, promptPart{name: "contract_schema", source: c.SchemaPath, text: string(data)})
+			}
+		}
+	}
+	return parts
+}
+
+// buildPromptTokenReport measures a step's prompt components with the
+// tokenizer of its resolved adapter and model.
+func buildPromptTokenReport(step pipeline.Step, m *manifest.Manifest) PromptTokenReport {
+	report := PromptTokenReport{Step: step.ID, Persona: step.Persona, Parts: []PromptPart{}}
+	if persona := resolvePersonaForStep(step, m); persona != nil {
+		report.Adapter = persona.Adapter
+		report.Model = persona.Model
+	}
+	if step.Adapter != "" {
+		report.Adapter = step.Adapter
+	}
+	if step.Model != "" {
+		report.Model = step.Model
+	}
+
+	tok := cost.TokenizerFor(report.Adapter, report.Model)
+	report.Tokenizer = tok.Name()
+	report.ContextWindow = cost.LookupContextWindow(report.Model)
+	for _, part := range collectPromptParts(step, m) {
+		n := tok.Count(part.text)
+		report.Parts = append(report.Parts, PromptPart{Name: part.name, Source: part.source, Bytes: len(part.text), Tokens: n})
+		report.TotalTokens += n
+	}
+	return report
+}
+
+func printPromptTokenReports(reports []PromptTokenReport) {
+	if len(reports) == 0 {
+		fmt.Println("No prompt steps")
+		return
+	}
+	for i, r := range reports {
+		if i > 0 {
+			fmt.Println()
+		}
+		model := r.Model
+		if model == "" {
+			model = "(adapter default)"
+		}
+		fmt.Printf("%s  persona=%s adapter=%s model=%s tokenizer=%s\n", r.Step, r.Persona, r.Adapter, model, r.Tokenizer)
+		for _, part := range r.Parts {
+			fmt.Printf("  %-16s %8d tokens %9d bytes  %s\n", part.Name, part.Tokens, part.Bytes, part.Source)
+		}
+		pct := float64(r.TotalTokens) / float64(r.ContextWindow) * 100
+		fmt.Printf("  %-16s %8d tokens  (%.1f%% of %d context window)\n", "total", r.TotalTokens, pct, r.ContextWindow)
+	}
+}
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recinq/wave/internal/contract"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPromptTokenReport(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "craftsman.md"), []byte("You write careful code."), 0o644))
+	schemaPath := filepath.Join(root, "out.schema.json")
+	require.NoError(t, os.WriteFile(schemaPath, []byte(`{"type":"object"}`), 0o644))
+
+	m := &manifest.Manifest{
+		RootDir: root,
+		Personas: map[string]manifest.Persona{
+			"craftsman": {Adapter: "claude", SystemPromptFile: "craftsman.md", Model: "claude-sonnet-4"},
+		},
+	}
+	step := pipeline.Step{
+		ID:      "implement",
+		Persona: "craftsman",
+		Exec:    pipeline.ExecConfig{Source: "Implement the feature"},
+		Handover: pipeline.HandoverConfig{
+			Contract: contract.ContractConfig{Type: "json_schema", SchemaPath: schemaPath},
+		},
+	}
+
+	report := buildPromptTokenReport(step, m)
+
+	assert.Equal(t, "claude", report.Adapter)
+	assert.Equal(t, "claude-sonnet-4", report.Model)
+	assert.Equal(t, "claude", report.Tokenizer)
+	assert.Equal(t, 200_000, report.ContextWindow)
+	require.Len(t, report.Parts, 3)
+	assert.Equal(t, "system_prompt", report.Parts[0].Name)
+	assert.Equal(t, "step_prompt", report.Parts[1].Name)
+	assert.Equal(t, "contract_schema", report.Parts[2].Name)
+
+	sum := 0
+	for _, p := range report.Parts {
+		assert.Positive(t, p.Tokens, p.Name)
+		sum += p.Tokens
+	}
+	assert.Equal(t, sum, report.TotalTokens)
+}
+
+func TestBuildPromptTokenReport_StepOverridesAdapterAndModel(t *testing.T) {
+	m := &manifest.Manifest{
+		Personas: map[string]manifest.Persona{
+			"craftsman": {Adapter: "claude"},
+		},
+	}
+	step := pipeline.Step{
+		ID:      "implement",
+		Persona: "craftsman",
+		Adapter: "codex",
+		Model:   "gpt-4o",
+		Exec:    pipeline.ExecConfig{Source: "Implement the feature"},
+	}
+
+	report := buildPromptTokenReport(step, m)
+
+	assert.Equal(t, "codex", report.Adapter)
+	assert.Equal(t, "openai", report.Tokenizer)
+	assert.Equal(t, 128_000, report.ContextWindow)
+	require.Len(t, report.Parts, 1)
+	assert.Equal(t, "exec.source", report.Parts[0].Source)
+}
@@ -0,0 +1,723 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/cost"
+	"github.com/recinq/wave/internal/hooks"
+	"github.com/recinq/wave/internal/metrics"
+	"github.com/recinq/wave/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// ReportOptions holds options for the report command.
+type ReportOptions struct {
+	Since    string
+	Pipeline string
+	Format   string
+	Post     []string
+	DryRun   bool
+}
+
+// reportTopN bounds the slowest-step and flakiest-contract tables.
+const reportTopN = 10
+
+// reportDailyMaxDays is the longest window reported day by day; longer
+// windows are bucketed by week.
+const reportDailyMaxDays = 31
+
+// TrendReport is the historical digest emitted by `wave report`. Prev*
+// fields cover the window of the same length just before From, so the
+// digest can show whether things got better or worse.
+type TrendReport struct {
+	Since          string               `json:"since,omitempty"`
+	Pipeline       string               `json:"pipeline,omitempty"`
+	From           time.Time            `json:"from"`
+	To             time.Time            `json:"to"`
+	Runs           ReportRunSummary     `json:"runs"`
+	Pipelines      []ReportPipelineStat `json:"pipelines"`
+	Failures       []TriageCount        `json:"failure_categories"`
+	Tokens         ReportTokenSummary   `json:"tokens"`
+	SlowestSteps   []ReportStepStat     `json:"slowest_steps"`
+	FlakyContracts []ReportContractStat `json:"flaky_contracts"`
+}
+
+// ReportRunSummary counts the top-level runs started in the window.
+type ReportRunSummary struct {
+	Total           int     `json:"total"`
+	Completed       int     `json:"completed"`
+	Failed          int     `json:"failed"`
+	Cancelled       int     `json:"cancelled"`
+	SuccessRate     float64 `json:"success_rate"`
+	PrevTotal       int     `json:"prev_total"`
+	PrevSuccessRate float64 `json:"prev_success_rate"`
+}
+
+// ReportPipelineStat summarizes one pipeline's runs in the window.
+type ReportPipelineStat struct {
+	Pipeline      string `json:"pipeline"`
+	Runs          int    `json:"runs"`
+	Failed        int    `json:"failed"`
+	AvgDurationMs int64  `json:"avg_duration_ms"`
+	Tokens        int    `json:"tokens"`
+}
+
+// ReportTokenSummary is the token usage and estimated cost of the window,
+// with one bucket per day (or week, for long windows).
+type ReportTokenSummary struct {
+	Total       int                `json:"total"`
+	EstCostUSD  float64            `json:"est_cost_usd"`
+	PrevTotal   int                `json:"prev_total"`
+	PrevCostUSD float64            `json:"prev_est_cost_usd"`
+	Bucket      string             `json:"bucket"`
+	Buckets     []ReportTokenPoint `json:"buckets"`
+}
+
+// ReportTokenPoint is the usage of the runs started in one bucket.
+type ReportTokenPoint struct {
+	Start      time.Time `json:"start"`
+	Runs       int       `json:"runs"`
+	Tokens     int       `json:"tokens"`
+	EstCostUSD float64   `json:"est_cost_usd"`
+}
+
+// ReportStepStat is the recorded duration of one pipeline/step pair.
+type ReportStepStat struct {
+	Pipeline      string `json:"pipeline"`
+	Step          string `json:"step"`
+	Runs          int    `json:"runs"`
+	AvgDurationMs int64  `json:"avg_duration_ms"`
+	MaxDurationMs int64  `json:"max_duration_ms"`
+}
+
+// ReportContractStat counts the outcomes of one contract. A contract is
+// flaky when it both passed and failed in the window.
+type ReportContractStat struct {
+	Pipeline string  `json:"pipeline"`
+	Step     string  `json:"step"`
+	Contract string  `json:"contract"`
+	Passed   int     `json:"passed"`
+	Failed   int     `json:"failed"`
+	FailRate float64 `json:"fail_rate"`
+}
+
+// reportData is everything buildTrendReport aggregates, as read from the
+// state database.
+type reportData struct {
+	Runs      []state.RunRecord
+	Failures  []state.StepFailureRecord
+	Metrics   []metrics.PerformanceMetricRecord
+	Contracts []state.ContractResultRecord
+}
+
+// NewReportCmd creates the report command.
+func NewReportCmd() *cobra.Command {
+	var opts ReportOptions
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Summarize run history as a trend digest",
+		Long: `Summarize recent pipeline runs as a digest suitable for a team channel.
+
+The report covers the runs started in the --since window: success rate and
+per-pipeline counts, failure categories (see 'wave triage'), token usage
+and estimated cost per day, the slowest steps, and the flakiest contracts
+(contracts that both passed and failed). Run and token figures are compared
+with the window of the same length before it.
+
+--post delivers the markdown digest through the webhooks registered in the
+dashboard, selected by name or format as with 'wave emit'. Costs are
+estimated from token counts at Claude Sonnet pricing.`,
+		Example: `  wave report                          # Last 7 days as markdown
+  wave report --since 30d --format html > digest.html
+  wave report --pipeline impl-issue    # Restrict to one pipeline
+  wave report --post slack             # Post the digest to Slack webhooks`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Format = ResolveFormat(cmd, opts.Format)
+			return runReport(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Since, "since", "7d", "Report window (e.g. 7d, 24h); empty for all history")
+	cmd.Flags().StringVar(&opts.Pipeline, "pipeline", "", "Filter by pipeline name")
+	cmd.Flags().StringVar(&opts.Format, "format", "markdown", "Output format (markdown, html, json)")
+	cmd.Flags().StringSliceVar(&opts.Post, "post", nil, "Webhook names or formats to post the markdown digest to")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "With --post, render payloads without sending them")
+
+	return cmd
+}
+
+func runReport(ctx context.Context, opts ReportOptions) error {
+	format := opts.Format
+	if format == "table" || format == "md" {
+		format = "markdown"
+	}
+	if format != "markdown" && format != "html" && format != "json" {
+		return NewCLIError(CodeInvalidArgs, fmt.Sprintf("unsupported report format %q", opts.Format), "Use --format markdown, html or json")
+	}
+
+	now := time.Now()
+	var window time.Duration
+	if opts.Since != "" {
+		d, err := parseSinceDuration(opts.Since)
+		if err != nil || d <= 0 {
+			return NewCLIError(CodeInvalidArgs, fmt.Sprintf("invalid --since value %q", opts.Since),
+				"Use a duration like '7d', '24h', or '30m'.").WithCause(err)
+		}
+		window = d
+	}
+
+	dbPath := ".agents/state.db"
+	var data reportData
+	var webhooks []*state.Webhook
+	if _, err := os.Stat(dbPath); err == nil {
+		store, err := state.NewReadOnlyStateStore(dbPath)
+		if err != nil {
+			return NewCLIError(CodeStateDBError, fmt.Sprintf("failed to open state database: %s", err), "Check .agents/state.db file permissions or run 'wave run' to create it").WithCause(err)
+		}
+		defer store.Close()
+
+		data, err = loadReportData(store, now, window)
+		if err != nil {
+			return NewCLIError(CodeStateDBError, fmt.Sprintf("failed to query run history: %s", err), "The state database may need migration -- try 'wave migrate up'").WithCause(err)
+		}
+		if len(opts.Post) > 0 {
+			if webhooks, err = store.ListWebhooks(); err != nil {
+				return NewCLIError(CodeStateDBError, fmt.Sprintf("failed to list webhooks: %s", err), "The state database may need migration -- try 'wave migrate up'").WithCause(err)
+			}
+		}
+	}
+
+	report := buildTrendReport(data, opts.Pipeline, now, window)
+	report.Since = opts.Since
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to marshal JSON: %s", err), "This is an internal serialization error").WithCause(err)
+		}
+	case "html":
+		if err := renderReportHTML(os.Stdout, report); err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to render HTML report: %s", err), "This is an internal template error").WithCause(err)
+		}
+	default:
+		fmt.Print(renderReportMarkdown(report))
+	}
+
+	if len(opts.Post) == 0 {
+		return nil
+	}
+	return postReport(ctx, webhooks, opts, renderReportMarkdown(report))
+}
+
+// loadReportData reads the runs, failures, step metrics and contract
+// results of the report window and, when the window is bounded, of the
+// window before it.
+func loadReportData(store state.StateStore, now time.Time, window time.Duration) (reportData, error) {
+	var since time.Time
+	if window > 0 {
+		since = now.Add(-2 * window)
+	}
+
+	var data reportData
+	var err error
+	runOpts := state.ListRunsOptions{TopLevelOnly: true}
+	if !since.IsZero() {
+		runOpts.SinceUnix = since.Unix()
+	}
+	if data.Runs, err = store.ListRuns(runOpts); err != nil {
+		return data, err
+	}
+	if data.Failures, err = store.ListStepFailures(since); err != nil {
+		return data, err
+	}
+	if data.Contracts, err = store.ListContractResults(since); err != nil {
+		return data, err
+	}
+	mstore := metrics.NewStore(state.UnderlyingDB(store))
+	if data.Metrics, err = mstore.GetRecentPerformanceHistory(metrics.PerformanceQueryOptions{Since: since}); err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// buildTrendReport aggregates data into the digest for the window ending
+// at now. A zero window covers all history, starting at the first run.
+func buildTrendReport(data reportData, pipeline string, now time.Time, window time.Duration) TrendReport {
+	report := TrendReport{
+		Pipeline:       pipeline,
+		To:             now,
+		Pipelines:      []ReportPipelineStat{},
+		Failures:       []TriageCount{},
+		SlowestSteps:   []ReportStepStat{},
+		FlakyContracts: []ReportContractStat{},
+	}
+	keep := func(name string) bool { return pipeline == "" || name == pipeline }
+
+	if window > 0 {
+		report.From = now.Add(-window)
+	} else {
+		report.From = now
+		for _, r := range data.Runs {
+			if keep(r.PipelineName) && r.StartedAt.Before(report.From) {
+				report.From = r.StartedAt
+			}
+		}
+	}
+	prevFrom := report.From.Add(-window)
+	inWindow := func(t time.Time) bool { return !t.Before(report.From) && !t.After(now) }
+	inPrev := func(t time.Time) bool { return window > 0 && !t.Before(prevFrom) && t.Before(report.From) }
+
+	report.Tokens.Bucket = "day"
+	if report.To.Sub(report.From) > reportDailyMaxDays*24*time.Hour {
+		report.Tokens.Bucket = "week"
+	}
+	report.Tokens.Buckets = reportBuckets(report.From, report.To, report.Tokens.Bucket)
+
+	var prevCompleted int
+	pipelineIndex := make(map[string]int)
+	durations := make(map[string][]int64)
+	for _, r := range data.Runs {
+		if !keep(r.PipelineName) {
+			continue
+		}
+		if inPrev(r.StartedAt) {
+			report.Runs.PrevTotal++
+			report.Tokens.PrevTotal += r.TotalTokens
+			if isSuccessfulRun(r.Status) {
+				prevCompleted++
+			}
+			continue
+		}
+		if !inWindow(r.StartedAt) {
+			continue
+		}
+
+		report.Runs.Total++
+		report.Tokens.Total += r.TotalTokens
+		switch {
+		case isSuccessfulRun(r.Status):
+			report.Runs.Completed++
+		case r.Status == "failed":
+			report.Runs.Failed++
+		case r.Status == "cancelled":
+			report.Runs.Cancelled++
+		}
+
+		idx, ok := pipelineIndex[r.PipelineName]
+		if !ok {
+			idx = len(report.Pipelines)
+			pipelineIndex[r.PipelineName] = idx
+			report.Pipelines = append(report.Pipelines, ReportPipelineStat{Pipeline: r.PipelineName})
+		}
+		report.Pipelines[idx].Runs++
+		report.Pipelines[idx].Tokens += r.TotalTokens
+		if r.Status == "failed" {
+			report.Pipelines[idx].Failed++
+		}
+		if r.CompletedAt != nil {
+			durations[r.PipelineName] = append(durations[r.PipelineName], r.CompletedAt.Sub(r.StartedAt).Milliseconds())
+		}
+
+		b := &report.Tokens.Buckets[bucketIndex(report.Tokens.Buckets, r.StartedAt)]
+		b.Runs++
+		b.Tokens += r.TotalTokens
+	}
+
+	report.Runs.SuccessRate = ratio(report.Runs.Completed, report.Runs.Total)
+	report.Runs.PrevSuccessRate = ratio(prevCompleted, report.Runs.PrevTotal)
+	report.Tokens.EstCostUSD = estimateTokenCost(report.Tokens.Total)
+	report.Tokens.PrevCostUSD = estimateTokenCost(report.Tokens.PrevTotal)
+	for i := range report.Tokens.Buckets {
+		report.Tokens.Buckets[i].EstCostUSD = estimateTokenCost(report.Tokens.Buckets[i].Tokens)
+	}
+	for i := range report.Pipelines {
+		report.Pipelines[i].AvgDurationMs = average(durations[report.Pipelines[i].Pipeline])
+	}
+	sort.SliceStable(report.Pipelines, func(i, j int) bool {
+		if report.Pipelines[i].Runs != report.Pipelines[j].Runs {
+			return report.Pipelines[i].Runs > report.Pipelines[j].Runs
+		}
+		return report.Pipelines[i].Pipeline < report.Pipelines[j].Pipeline
+	})
+
+	categories := make(map[string]int)
+	for _, f := range data.Failures {
+		if keep(f.PipelineName) && inWindow(f.FailedAt) {
+			categories[f.FailureCategory]++
+		}
+	}
+	report.Failures = sortedTriageCounts(categories)
+
+	report.SlowestSteps = slowestSteps(data.Metrics, keep, inWindow)
+	report.FlakyContracts = flakyContracts(data.Contracts, keep, inWindow)
+
+	return report
+}
+
+// slowestSteps averages the recorded step durations per pipeline/step and
+// returns the reportTopN slowest.
+func slowestSteps(records []metrics.PerformanceMetricRecord, keep func(string) bool, inWindow func(time.Time) bool) []ReportStepStat {
+	type stepKey struct{ pipeline, step string }
+	totals := make(map[stepKey]int64)
+	stats := make(map[stepKey]*ReportStepStat)
+	for _, m := range records {
+		if !keep(m.PipelineName) || !inWindow(m.StartedAt) || m.DurationMs <= 0 {
+			continue
+		}
+		key := stepKey{m.PipelineName, m.StepID}
+		s, ok := stats[key]
+		if !ok {
+			s = &ReportStepStat{Pipeline: m.PipelineName, Step: m.StepID}
+			stats[key] = s
+		}
+		s.Runs++
+		totals[key] += m.DurationMs
+		if m.DurationMs > s.MaxDurationMs {
+			s.MaxDurationMs = m.DurationMs
+		}
+	}
+
+	out := make([]ReportStepStat, 0, len(stats))
+	for key, s := range stats {
+		s.AvgDurationMs = totals[key] / int64(s.Runs)
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].AvgDurationMs != out[j].AvgDurationMs {
+			return out[i].AvgDurationMs > out[j].AvgDurationMs
+		}
+		if out[i].Pipeline != out[j].Pipeline {
+			return out[i].Pipeline < out[j].Pipeline
+		}
+		return out[i].Step < out[j].Step
+	})
+	if len(out) > reportTopN {
+		out = out[:reportTopN]
+	}
+	return out
+}
+
+// flakyContracts counts contract outcomes per pipeline, step and contract
+// and returns the reportTopN with the most failures among those that also
+// passed. Contracts that always fail are broken rather than flaky and are
+// left to 'wave triage'.
+func flakyContracts(records []state.ContractResultRecord, keep func(string) bool, inWindow func(time.Time) bool) []ReportContractStat {
+	type contractKey struct{ pipeline, step, contract string }
+	stats := make(map[contractKey]*ReportContractStat)
+	for _, c := range records {
+		if !keep(c.PipelineName) || !inWindow(c.CreatedAt) {
+			continue
+		}
+		contract := c.ContractType
+		if c.Name != "" && c.Name != c.ContractType {
+			contract += ":" + c.Name
+		}
+		key := contractKey{c.PipelineName, c.StepID, contract}
+		s, ok := stats[key]
+		if !ok {
+			s = &ReportContractStat{Pipeline: c.PipelineName, Step: c.StepID, Contract: contract}
+			stats[key] = s
+		}
+		if c.Passed {
+			s.Passed++
+		} else {
+			s.Failed++
+		}
+	}
+
+	out := make([]ReportContractStat, 0, len(stats))
+	for _, s := range stats {
+		if s.Passed == 0 || s.Failed == 0 {
+			continue
+		}
+		s.FailRate = ratio(s.Failed, s.Passed+s.Failed)
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Failed != out[j].Failed {
+			return out[i].Failed > out[j].Failed
+		}
+		if out[i].FailRate != out[j].FailRate {
+			return out[i].FailRate > out[j].FailRate
+		}
+		if out[i].Pipeline != out[j].Pipeline {
+			return out[i].Pipeline < out[j].Pipeline
+		}
+		if out[i].Step != out[j].Step {
+			return out[i].Step < out[j].Step
+		}
+		return out[i].Contract < out[j].Contract
+	})
+	if len(out) > reportTopN {
+		out = out[:reportTopN]
+	}
+	return out
+}
+
+// reportBuckets returns consecutive day or week buckets covering from..to,
+// aligned to local midnight (weeks start on Monday).
+func reportBuckets(from, to time.Time, bucket string) []ReportTokenPoint {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	step := 1
+	if bucket == "week" {
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+		step = 7
+	}
+	var buckets []ReportTokenPoint
+	for t := start; !t.After(to); t = t.AddDate(0, 0, step) {
+		buckets = append(buckets, ReportTokenPoint{Start: t})
+	}
+	return buckets
+}
+
+// bucketIndex returns the index of the last bucket starting at or before t.
+func bucketIndex(buckets []ReportTokenPoint, t time.Time) int {
+	i := sort.Search(len(buckets), func(i int) bool { return buckets[i].Start.After(t) })
+	if i == 0 {
+		return 0
+	}
+	return i - 1
+}
+
+// isSuccessfulRun reports whether a run status counts as a success.
+func isSuccessfulRun(status string) bool {
+	return status == "completed" || status == "completed_empty"
+}
+
+// estimateTokenCost estimates the USD cost of a token count. Runs record a
+// single total, so it assumes an 80/20 input/output split at Claude Sonnet
+// pricing, like the dashboard's analytics page.
+func estimateTokenCost(tokens int) float64 {
+	input := tokens * 4 / 5
+	return cost.ComputeCost("claude-sonnet", input, tokens-input)
+}
+
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+func average(values []int64) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum int64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / int64(len(values))
+}
+
+// reportTitle is the heading shared by the markdown and HTML renderings.
+func reportTitle(r TrendReport) string {
+	title := "Wave report"
+	if r.Pipeline != "" {
+		title += ": " + r.Pipeline
+	}
+	return fmt.Sprintf("%s (%s – %s)", title, r.From.Format("2006-01-02"), r.To.Format("2006-01-02"))
+}
+
+// trendDelta formats the change from prev to cur, e.g. "+12%"; empty when
+// there is nothing to compare with.
+func trendDelta(cur, prev float64) string {
+	if prev == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%+.0f%%", (cur-prev)/prev*100)
+}
+
+// renderReportMarkdown renders the digest as GitHub-flavoured markdown.
+func renderReportMarkdown(r TrendReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", reportTitle(r))
+
+	if r.Runs.Total == 0 {
+		b.WriteString("No runs in this period.\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "**Runs:** %d", r.Runs.Total)
+	if d := trendDelta(float64(r.Runs.Total), float64(r.Runs.PrevTotal)); d != "" {
+		fmt.Fprintf(&b, " (%s)", d)
+	}
+	fmt.Fprintf(&b, " — %d completed, %d failed, %d cancelled  \n", r.Runs.Completed, r.Runs.Failed, r.Runs.Cancelled)
+	fmt.Fprintf(&b, "**Success rate:** %.0f%%", r.Runs.SuccessRate*100)
+	if r.Runs.PrevTotal > 0 {
+		fmt.Fprintf(&b, " (previously %.0f%%)", r.Runs.PrevSuccessRate*100)
+	}
+	fmt.Fprintf(&b, "  \n**Tokens:** %d", r.Tokens.Total)
+	if d := trendDelta(float64(r.Tokens.Total), float64(r.Tokens.PrevTotal)); d != "" {
+		fmt.Fprintf(&b, " (%s)", d)
+	}
+	fmt.Fprintf(&b, " — est. $%.2f\n", r.Tokens.EstCostUSD)
+
+	b.WriteString("\n## Pipelines\n\n| Pipeline | Runs | Failed | Avg duration | Tokens |\n|---|---:|---:|---:|---:|\n")
+	for _, p := range r.Pipelines {
+		fmt.Fprintf(&b, "| %s | %d | %d | %s | %d |\n", p.Pipeline, p.Runs, p.Failed, formatDurationMs(p.AvgDurationMs), p.Tokens)
+	}
+
+	if len(r.Failures) > 0 {
+		b.WriteString("\n## Failure categories\n\n| Category | Count |\n|---|---:|\n")
+		for _, c := range r.Failures {
+			fmt.Fprintf(&b, "| %s | %d |\n", c.Category, c.Count)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n## Tokens per %s\n\n| %s | Runs | Tokens | Est. cost |\n|---|---:|---:|---:|\n", r.Tokens.Bucket, capitalize(r.Tokens.Bucket))
+	for _, p := range r.Tokens.Buckets {
+		fmt.Fprintf(&b, "| %s | %d | %d | $%.2f |\n", p.Start.Format("2006-01-02"), p.Runs, p.Tokens, p.EstCostUSD)
+	}
+
+	if len(r.SlowestSteps) > 0 {
+		b.WriteString("\n## Slowest steps\n\n| Pipeline | Step | Runs | Avg | Max |\n|---|---|---:|---:|---:|\n")
+		for _, s := range r.SlowestSteps {
+			fmt.Fprintf(&b, "| %s | %s | %d | %s | %s |\n", s.Pipeline, s.Step, s.Runs, formatDurationMs(s.AvgDurationMs), formatDurationMs(s.MaxDurationMs))
+		}
+	}
+
+	if len(r.FlakyContracts) > 0 {
+		b.WriteString("\n## Flakiest contracts\n\n| Pipeline | Step | Contract | Passed | Failed | Fail rate |\n|---|---|---|---:|---:|---:|\n")
+		for _, c := range r.FlakyContracts {
+			fmt.Fprintf(&b, "| %s | %s | %s | %d | %d | %.0f%% |\n", c.Pipeline, c.Step, c.Contract, c.Passed, c.Failed, c.FailRate*100)
+		}
+	}
+
+	return b.String()
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+var reportHTMLTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": formatDurationMs,
+	"pct":      func(f float64) string { return fmt.Sprintf("%.0f%%", f*100) },
+	"usd":      func(f float64) string { return fmt.Sprintf("$%.2f", f) },
+	"date":     func(t time.Time) string { return t.Format("2006-01-02") },
+	"delta":    func(cur, prev int) string { return trendDelta(float64(cur), float64(prev)) },
+	"title":    reportTitle,
+	"cap":      capitalize,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{title .}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 2rem; color: #1f2328; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #d0d7de; padding: 0.3rem 0.7rem; text-align: left; }
+td.num, th.num { text-align: right; }
+th { background: #f6f8fa; }
+</style>
+</head>
+<body>
+<h1>{{title .}}</h1>
+{{- if eq .Runs.Total 0}}
+<p>No runs in this period.</p>
+{{- else}}
+<p>
+<strong>Runs:</strong> {{.Runs.Total}}{{with delta .Runs.Total .Runs.PrevTotal}} ({{.}}){{end}} — {{.Runs.Completed}} completed, {{.Runs.Failed}} failed, {{.Runs.Cancelled}} cancelled<br>
+<strong>Success rate:</strong> {{pct .Runs.SuccessRate}}{{if .Runs.PrevTotal}} (previously {{pct .Runs.PrevSuccessRate}}){{end}}<br>
+<strong>Tokens:</strong> {{.Tokens.Total}}{{with delta .Tokens.Total .Tokens.PrevTotal}} ({{.}}){{end}} — est. {{usd .Tokens.EstCostUSD}}
+</p>
+<h2>Pipelines</h2>
+<table>
+<tr><th>Pipeline</th><th class="num">Runs</th><th class="num">Failed</th><th class="num">Avg duration</th><th class="num">Tokens</th></tr>
+{{- range .Pipelines}}
+<tr><td>{{.Pipeline}}</td><td class="num">{{.Runs}}</td><td class="num">{{.Failed}}</td><td class="num">{{duration .AvgDurationMs}}</td><td class="num">{{.Tokens}}</td></tr>
+{{- end}}
+</table>
+{{- if .Failures}}
+<h2>Failure categories</h2>
+<table>
+<tr><th>Category</th><th class="num">Count</th></tr>
+{{- range .Failures}}
+<tr><td>{{.Category}}</td><td class="num">{{.Count}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<h2>Tokens per {{.Tokens.Bucket}}</h2>
+<table>
+<tr><th>{{cap .Tokens.Bucket}}</th><th class="num">Runs</th><th class="num">Tokens</th><th class="num">Est. cost</th></tr>
+{{- range .Tokens.Buckets}}
+<tr><td>{{date .Start}}</td><td class="num">{{.Runs}}</td><td class="num">{{.Tokens}}</td><td class="num">{{usd .EstCostUSD}}</td></tr>
+{{- end}}
+</table>
+{{- if .SlowestSteps}}
+<h2>Slowest steps</h2>
+<table>
+<tr><th>Pipeline</th><th>Step</th><th class="num">Runs</th><th class="num">Avg</th><th class="num">Max</th></tr>
+{{- range .SlowestSteps}}
+<tr><td>{{.Pipeline}}</td><td>{{.Step}}</td><td class="num">{{.Runs}}</td><td class="num">{{duration .AvgDurationMs}}</td><td class="num">{{duration .MaxDurationMs}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .FlakyContracts}}
+<h2>Flakiest contracts</h2>
+<table>
+<tr><th>Pipeline</th><th>Step</th><th>Contract</th><th class="num">Passed</th><th class="num">Failed</th><th class="num">Fail rate</th></tr>
+{{- range .FlakyContracts}}
+<tr><td>{{.Pipeline}}</td><td>{{.Step}}</td><td>{{.Contract}}</td><td class="num">{{.Passed}}</td><td class="num">{{.Failed}}</td><td class="num">{{pct .FailRate}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// renderReportHTML renders the digest as a self-contained HTML page.
+func renderReportHTML(w io.Writer, r TrendReport) error {
+	return reportHTMLTemplate.Execute(w, r)
+}
+
+// postReport delivers the markdown digest to the webhooks selected by
+// opts.Post and prints one line per delivery to stderr.
+func postReport(ctx context.Context, webhooks []*state.Webhook, opts ReportOptions, digest string) error {
+	selected, err := selectEmitWebhooks(webhooks, opts.Post)
+	if err != nil {
+		return err
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	results := hooks.NewWebhookRunner(selected, nil).Post(ctx, digest, opts.DryRun)
+	failed := 0
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			failed++
+			fmt.Fprintf(os.Stderr, "✗ %s  %s\n", r.Webhook, r.Error)
+		case opts.DryRun:
+			fmt.Fprintf(os.Stderr, "• %s\n  %s\n", r.Webhook, r.Payload)
+		default:
+			fmt.Fprintf(os.Stderr, "✓ %s  HTTP %d (%dms)\n", r.Webhook, r.StatusCode, r.ResponseTimeMs)
+		}
+	}
+	if failed > 0 {
+		return NewCLIError(CodeInternalError, fmt.Sprintf("%d of %d report deliveries failed", failed, len(results)), "Check the webhook URLs and receiving services")
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/metrics"
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTrendReport(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	done := func(start time.Time, d time.Duration) *time.Time { t := start.Add(d); return &t }
+
+	runs := []state.RunRecord{
+		{RunID: "r1", PipelineName: "impl-issue", Status: "completed", TotalTokens: 1000, StartedAt: now.Add(-time.Hour), CompletedAt: done(now.Add(-time.Hour), 2*time.Minute)},
+		{RunID: "r2", PipelineName: "impl-issue", Status: "failed", TotalTokens: 500, StartedAt: now.Add(-2 * day), CompletedAt: done(now.Add(-2*day), 4*time.Minute)},
+		{RunID: "r3", PipelineName: "audit", Status: "completed_empty", TotalTokens: 100, StartedAt: now.Add(-3 * day)},
+		// Previous window.
+		{RunID: "p1", PipelineName: "impl-issue", Status: "failed", TotalTokens: 800, StartedAt: now.Add(-10 * day)},
+	}
+	data := reportData{
+		Runs: runs,
+		Failures: []state.StepFailureRecord{
+			{PipelineName: "impl-issue", StepID: "implement", FailureCategory: "timeout", FailedAt: now.Add(-2 * day)},
+			{PipelineName: "impl-issue", StepID: "implement", FailureCategory: "timeout", FailedAt: now.Add(-10 * day)},
+		},
+		Metrics: []metrics.PerformanceMetricRecord{
+			{PipelineName: "impl-issue", StepID: "implement", DurationMs: 120000, StartedAt: now.Add(-time.Hour)},
+			{PipelineName: "impl-issue", StepID: "implement", DurationMs: 240000, StartedAt: now.Add(-2 * day)},
+			{PipelineName: "impl-issue", StepID: "plan", DurationMs: 30000, StartedAt: now.Add(-time.Hour)},
+		},
+		Contracts: []state.ContractResultRecord{
+			{PipelineName: "impl-issue", StepID: "plan", ContractType: "json_schema", Name: "plan.schema.json", Passed: false, CreatedAt: now.Add(-time.Hour)},
+			{PipelineName: "impl-issue", StepID: "plan", ContractType: "json_schema", Name: "plan.schema.json", Passed: true, CreatedAt: now.Add(-time.Hour)},
+			{PipelineName: "impl-issue", StepID: "test", ContractType: "test_suite", Name: "test_suite", Passed: false, CreatedAt: now.Add(-time.Hour)},
+		},
+	}
+
+	report := buildTrendReport(data, "", now, 7*day)
+
+	assert.Equal(t, ReportRunSummary{Total: 3, Completed: 2, Failed: 1, SuccessRate: 2.0 / 3, PrevTotal: 1}, report.Runs)
+	require.Len(t, report.Pipelines, 2)
+	assert.Equal(t, ReportPipelineStat{Pipeline: "impl-issue", Runs: 2, Failed: 1, AvgDurationMs: 180000, Tokens: 1500}, report.Pipelines[0])
+	assert.Equal(t, []TriageCount{{Category: "timeout", Count: 1}}, report.Failures)
+
+	assert.Equal(t, 1600, report.Tokens.Total)
+	assert.Equal(t, 800, report.Tokens.PrevTotal)
+	assert.Equal(t, "day", report.Tokens.Bucket)
+	require.Len(t, report.Tokens.Buckets, 8)
+	assert.Equal(t, 1000, report.Tokens.Buckets[7].Tokens)
+	assert.InDelta(t, estimateTokenCost(1600), report.Tokens.EstCostUSD, 1e-9)
+
+	require.Len(t, report.SlowestSteps, 2)
+	assert.Equal(t, ReportStepStat{Pipeline: "impl-issue", Step: "implement", Runs: 2, AvgDurationMs: 180000, MaxDurationMs: 240000}, report.SlowestSteps[0])
+
+	// Only the contract that both passed and failed is flaky.
+	require.Len(t, report.FlakyContracts, 1)
+	assert.Equal(t, "json_schema:plan.schema.json", report.FlakyContracts[0].Contract)
+	assert.Equal(t, 0.5, report.FlakyContracts[0].FailRate)
+
+	filtered := buildTrendReport(data, "audit", now, 7*day)
+	assert.Equal(t, 1, filtered.Runs.Total)
+	assert.Empty(t, filtered.Failures)
+	assert.Empty(t, filtered.FlakyContracts)
+}
+
+func TestBuildTrendReport_AllHistory(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	first := now.AddDate(0, -3, 0)
+	data := reportData{Runs: []state.RunRecord{
+		{RunID: "r1", PipelineName: "impl-issue", Status: "completed", StartedAt: first},
+		{RunID: "r2", PipelineName: "impl-issue", Status: "completed", StartedAt: now},
+	}}
+
+	report := buildTrendReport(data, "", now, 0)
+
+	assert.Equal(t, first, report.From)
+	assert.Equal(t, 2, report.Runs.Total)
+	assert.Zero(t, report.Runs.PrevTotal)
+	assert.Equal(t, "week", report.Tokens.Bucket)
+	assert.Equal(t, time.Monday, report.Tokens.Buckets[0].Start.Weekday())
+	assert.Equal(t, 1, report.Tokens.Buckets[0].Runs)
+	assert.Equal(t, 1, report.Tokens.Buckets[len(report.Tokens.Buckets)-1].Runs)
+}
+
+func TestRenderReport(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	data := reportData{
+		Runs: []state.RunRecord{
+			{RunID: "r1", PipelineName: "impl-issue", Status: "completed", TotalTokens: 2000, StartedAt: now.Add(-time.Hour)},
+			{RunID: "p1", PipelineName: "impl-issue", Status: "completed", TotalTokens: 1000, StartedAt: now.Add(-8 * 24 * time.Hour)},
+		},
+		Contracts: []state.ContractResultRecord{
+			{PipelineName: "impl-issue", StepID: "plan", ContractType: "json_schema", Passed: false, CreatedAt: now},
+			{PipelineName: "impl-issue", StepID: "plan", ContractType: "json_schema", Passed: true, CreatedAt: now},
+		},
+	}
+	report := buildTrendReport(data, "", now, 7*24*time.Hour)
+
+	md := renderReportMarkdown(report)
+	assert.Contains(t, md, "# Wave report (2026-03-07 – 2026-03-14)")
+	assert.Contains(t, md, "**Tokens:** 2000 (+100%)")
+	assert.Contains(t, md, "| impl-issue | 1 | 0 |")
+	assert.Contains(t, md, "## Flakiest contracts")
+	assert.NotContains(t, md, "## Failure categories")
+
+	var html bytes.Buffer
+	require.NoError(t, renderReportHTML(&html, report))
+	assert.Contains(t, html.String(), "<h2>Flakiest contracts</h2>")
+	assert.Contains(t, html.String(), "<td>json_schema</td>")
+
+	empty := renderReportMarkdown(buildTrendReport(reportData{}, "", now, 24*time.Hour))
+	assert.Contains(t, empty, "No runs in this period.")
+}
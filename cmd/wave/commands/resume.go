@@ -124,18 +124,15 @@ func runResume(opts ResumeOptions, debug bool) error {
 			"Nothing to resume — start a fresh run with 'wave run "+run.PipelineName+"'")
 	}
 
-	// If no --from-step was specified, auto-detect the failed step.
-	fromStep := opts.FromStep
-	if fromStep == "" {
-		fromStep, err = detectFailedStep(store, run)
-		if err != nil {
+	// A run still marked running is only resumable once its process is gone,
+	// e.g. after a crash or a reboot. Record it as failed like the reaper would.
+	if run.Status == "running" {
+		if !state.RunOrphaned(*run) {
 			return NewCLIError(CodeInvalidArgs,
-				fmt.Sprintf("could not determine which step to resume from: %v", err),
-				"Specify the step explicitly with --from-step <step>")
-		}
-		if opts.Output.Format != OutputFormatJSON {
-			fmt.Fprintf(os.Stderr, "  Auto-detected failed step: %s\n", fromStep)
+				fmt.Sprintf("run %q is still running", opts.RunID),
+				"Wait for it to finish, or stop it with 'wave cancel "+opts.RunID+"'")
 		}
+		_ = store.UpdateRunStatus(run.RunID, "failed", run.CurrentStep, run.TotalTokens)
 	}
 
 	// Load manifest.
@@ -144,6 +141,7 @@ func runResume(opts ResumeOptions, debug bool) error {
 		return err
 	}
 	m := *mp
+	state.SetRunIDFormatter(store, pipeline.RunIDFormatter(m.Runtime))
 
 	// Load the pipeline that was used in the original run.
 	p, err := pipeline.LoadByName(run.PipelineName)
@@ -153,6 +151,20 @@ func runResume(opts ResumeOptions, debug bool) error {
 			"Run 'wave list pipelines' to see available pipelines")
 	}
 
+	// If no --from-step was specified, auto-detect the step to resume from.
+	fromStep := opts.FromStep
+	if fromStep == "" {
+		fromStep, err = detectResumeStep(store, run, p)
+		if err != nil {
+			return NewCLIError(CodeInvalidArgs,
+				fmt.Sprintf("could not determine which step to resume from: %v", err),
+				"Specify the step explicitly with --from-step <step>")
+		}
+		if opts.Output.Format != OutputFormatJSON {
+			fmt.Fprintf(os.Stderr, "  Auto-detected resume step: %s\n", fromStep)
+		}
+	}
+
 	// Resolve adapter.
 	var runner adapter.AdapterRunner
 	if opts.Mock {
@@ -173,7 +185,7 @@ func runResume(opts ResumeOptions, debug bool) error {
 	resumeRunID, err := store.CreateRun(run.PipelineName, run.Input)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to create resume run record: %v\n", err)
-		resumeRunID = pipeline.GenerateRunID(run.PipelineName, m.Runtime.PipelineIDHashLength)
+		resumeRunID = pipeline.NewRunID(m.Runtime, run.PipelineName)
 	}
 
 	// Link the resume run back to the failed parent so operators can navigate
@@ -337,13 +349,8 @@ func runResume(opts ResumeOptions, debug bool) error {
 
 	if opts.Output.Format == OutputFormatAuto || opts.Output.Format == OutputFormatText {
 		totalTokens := executor.GetTotalTokens()
-		if totalTokens > 0 {
-			fmt.Fprintf(os.Stderr, "\n  ✓ Pipeline '%s' completed successfully (%.1fs, %s tokens)\n",
-				p.Metadata.Name, elapsed.Seconds(), display.FormatTokenCount(totalTokens))
-		} else {
-			fmt.Fprintf(os.Stderr, "\n  ✓ Pipeline '%s' completed successfully (%.1fs)\n",
-				p.Metadata.Name, elapsed.Seconds())
-		}
+		fmt.Fprintf(os.Stderr, "\n  ✓ Pipeline '%s' completed successfully (%.1fs%s)\n",
+			p.Metadata.Name, elapsed.Seconds(), runUsage(totalTokens, executor.GetTotalCost()))
 	}
 
 	if opts.Output.Format == OutputFormatJSON {
@@ -363,6 +370,45 @@ func runResume(opts ResumeOptions, debug bool) error {
 	return nil
 }
 
+// resumeStepReader is the narrow surface detectResumeStep needs: the run's
+// events and the checkpoints of the steps it completed.
+type resumeStepReader interface {
+	failedStepReader
+	GetCheckpoints(runID string) ([]state.CheckpointRecord, error)
+}
+
+// detectResumeStep picks the step a run resumes from. It is the failed step
+// unless a checkpoint shows that step completed after all, in which case —
+// and when the run's process exited without recording a failure — it is the
+// first step of p the run has no checkpoint for.
+func detectResumeStep(store resumeStepReader, run *state.RunRecord, p *pipeline.Pipeline) (string, error) {
+	checkpoints, _ := store.GetCheckpoints(run.RunID)
+	done := make(map[string]bool, len(checkpoints))
+	for _, cp := range checkpoints {
+		done[cp.StepID] = true
+	}
+
+	stepID, err := detectFailedStep(store, run)
+	if len(done) == 0 || (err == nil && !done[stepID] && hasPipelineStep(p, stepID)) {
+		return stepID, err
+	}
+	for _, step := range p.Steps {
+		if !done[step.ID] && !step.ReworkOnly {
+			return step.ID, nil
+		}
+	}
+	return "", fmt.Errorf("every step of run %q has a checkpoint; specify --from-step explicitly", run.RunID)
+}
+
+func hasPipelineStep(p *pipeline.Pipeline, stepID string) bool {
+	for _, step := range p.Steps {
+		if step.ID == stepID {
+			return true
+		}
+	}
+	return false
+}
+
 // failedStepReader is the narrow surface detectFailedStep needs: scan
 // recent events to find the last "failed" entry.
 type failedStepReader interface {
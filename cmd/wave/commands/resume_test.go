@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/recinq/wave/internal/pipeline"
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResumeStepReader serves a run's events and checkpoints from memory.
+type fakeResumeStepReader struct {
+	events      []state.LogRecord
+	checkpoints []state.CheckpointRecord
+}
+
+func (f *fakeResumeStepReader) GetEvents(string, state.EventQueryOptions) ([]state.LogRecord, error) {
+	return f.events, nil
+}
+
+func (f *fakeResumeStepReader) GetCheckpoints(string) ([]state.CheckpointRecord, error) {
+	return f.checkpoints, nil
+}
+
+func TestDetectResumeStep(t *testing.T) {
+	p := &pipeline.Pipeline{Steps: []pipeline.Step{{ID: "plan"}, {ID: "fix", ReworkOnly: true}, {ID: "build"}, {ID: "ship"}}}
+	run := &state.RunRecord{RunID: "run-1"}
+	done := func(ids ...string) []state.CheckpointRecord {
+		var cps []state.CheckpointRecord
+		for _, id := range ids {
+			cps = append(cps, state.CheckpointRecord{RunID: "run-1", StepID: id})
+		}
+		return cps
+	}
+
+	tests := []struct {
+		name   string
+		reader *fakeResumeStepReader
+		want   string
+	}{
+		{
+			name:   "failed step",
+			reader: &fakeResumeStepReader{events: []state.LogRecord{{State: "failed", StepID: "build"}}, checkpoints: done("plan")},
+			want:   "build",
+		},
+		{
+			name:   "process exited without a failure",
+			reader: &fakeResumeStepReader{checkpoints: done("plan")},
+			want:   "build",
+		},
+		{
+			name:   "failed step completed on retry",
+			reader: &fakeResumeStepReader{events: []state.LogRecord{{State: "failed", StepID: "plan"}}, checkpoints: done("plan", "build")},
+			want:   "ship",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := detectResumeStep(tt.reader, run, p)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("no checkpoints and no failure", func(t *testing.T) {
+		_, err := detectResumeStep(&fakeResumeStepReader{}, run, p)
+		assert.Error(t, err)
+	})
+	t.Run("every step checkpointed", func(t *testing.T) {
+		_, err := detectResumeStep(&fakeResumeStepReader{checkpoints: done("plan", "build", "ship")}, run, p)
+		assert.Error(t, err)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/huh"
@@ -27,6 +28,11 @@ type RunOptions = config.RuntimeConfig
 
 func NewRunCmd() *cobra.Command {
 	var opts RunOptions
+	var vars []string
+	var skipSteps []string
+	var artifacts []string
+	var inputFile string
+	var templateInputs inputTemplateFlags
 
 	cmd := &cobra.Command{
 		Use:   "run [pipeline] [input]",
@@ -40,7 +46,17 @@ including any per-persona model pinning in wave.yaml.
 
 The --adapter flag selects the LLM backend (claude, opencode, gemini, codex).
 Model formats vary by adapter: claude uses "haiku"/"opus", opencode uses
-"provider/model", gemini uses "gemini-2.0-pro", codex uses "gpt-4o".`,
+"provider/model", gemini uses "gemini-2.0-pro", codex uses "gpt-4o".
+
+Pipelines may declare input_templates, named shortcuts that build the input
+from a single value: with "issue: Fix GitHub issue #{{ value }}" declared,
+"wave run fix --issue 123" runs with "Fix GitHub issue #123". Template
+flags are listed under "Input templates" in --help.
+
+Long input can be read from a file with --input-file, or piped with "-" as
+the input ("cat issue.md | wave run fix -"). Either is limited to 10000
+bytes of text, the input sanitizer's max_input_length; longer input is
+refused rather than truncated.`,
 		Example: `  wave run ops-pr-review "Review the authentication changes"
   wave run --pipeline impl-speckit --input "add user auth"
   wave run impl-issue --dry-run
@@ -48,12 +64,21 @@ Model formats vary by adapter: claude uses "haiku"/"opus", opencode uses
   wave run my-pipeline --model haiku
   wave run my-pipeline --adapter opencode --model openai/gpt-4o
   wave run my-pipeline --preserve-workspace
+  wave run deploy --var service=api --var target_dir=services/api
+  wave run fix --issue 123                               # input template
+  cat issue.md | wave run fix -                          # input from stdin
+  wave run fix --input-file spec.md
+  wave run impl-issue --deterministic --seed ci-42 "fix login bug"
   wave run --steps clarify,plan impl-speckit
   wave run -x implement,create-pr impl-speckit
   wave run --from-step clarify -x create-pr impl-speckit
+  wave run release --skip-step publish --only-steps analyze,plan
+  wave run release --only-steps publish --artifact build:dist=./dist.tar.gz
   wave run --detach impl-issue "fix login bug"         # detach: run in background`,
 		Args: cobra.MaximumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.TemplateInputs = templateInputs.values(cmd)
+
 			// Handle positional arguments
 			if len(args) >= 1 && opts.Pipeline == "" {
 				opts.Pipeline = args[0]
@@ -61,6 +86,13 @@ Model formats vary by adapter: claude uses "haiku"/"opus", opencode uses
 			if len(args) >= 2 && opts.Input == "" {
 				opts.Input = args[1]
 			}
+			if len(args) == 1 && opts.Pipeline == stdinInputArg {
+				// `wave run -`: the pipeline is routed from the piped input.
+				opts.Pipeline, opts.Input = "", stdinInputArg
+			}
+			if err := readRunInput(&opts, inputFile, os.Stdin, isInteractive()); err != nil {
+				return err
+			}
 
 			opts.Output = GetOutputConfig(cmd)
 			debug, _ := cmd.Flags().GetBool("debug")
@@ -119,6 +151,34 @@ Model formats vary by adapter: claude uses "haiku"/"opus", opencode uses
 				return err
 			}
 
+			parsedVars, err := pipeline.ParseVarAssignments(vars)
+			if err != nil {
+				return err
+			}
+			opts.Vars = parsedVars
+
+			if len(skipSteps) > 0 {
+				skip := strings.Join(skipSteps, ",")
+				if opts.Exclude != "" {
+					skip = opts.Exclude + "," + skip
+				}
+				opts.Exclude = skip
+			}
+			opts.Artifacts, err = pipeline.ParseArtifactOverrides(artifacts)
+			if err != nil {
+				return err
+			}
+
+			if opts.Seed != "" && !opts.Deterministic {
+				return fmt.Errorf("--seed requires --deterministic")
+			}
+			if opts.Deterministic && opts.Continuous {
+				return fmt.Errorf("--deterministic cannot be combined with --continuous")
+			}
+			if opts.MaxParallel < 0 {
+				return fmt.Errorf("--max-parallel must not be negative")
+			}
+
 			cmd.SilenceUsage = true
 			cmd.SilenceErrors = true
 			return runRun(opts, debug)
@@ -126,7 +186,9 @@ Model formats vary by adapter: claude uses "haiku"/"opus", opencode uses
 	}
 
 	cmd.Flags().StringVar(&opts.Pipeline, "pipeline", "", "Pipeline name to run")
-	cmd.Flags().StringVar(&opts.Input, "input", "", "Input data for the pipeline")
+	cmd.Flags().StringVar(&opts.Input, "input", "", "Input data for the pipeline (\"-\" reads stdin)")
+	cmd.Flags().StringVar(&inputFile, "input-file", "", "Read the input from a file")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Override a pipeline var as key=value (repeatable)")
 	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Show what would be executed without running")
 	cmd.Flags().StringVar(&opts.FromStep, "from-step", "", "Start execution from specific step")
 	cmd.Flags().BoolVar(&opts.Force, "force", false, "Skip validation checks when using --from-step")
@@ -140,6 +202,9 @@ Model formats vary by adapter: claude uses "haiku"/"opus", opencode uses
 	cmd.Flags().BoolVar(&opts.PreserveWorkspace, "preserve-workspace", false, "Preserve workspace from previous run (for debugging)")
 	cmd.Flags().StringVar(&opts.Steps, "steps", "", "Run only the named steps (comma-separated)")
 	cmd.Flags().StringVarP(&opts.Exclude, "exclude", "x", "", "Skip the named steps (comma-separated)")
+	cmd.Flags().StringVar(&opts.Steps, "only-steps", "", "Alias for --steps")
+	cmd.Flags().StringSliceVar(&skipSteps, "skip-step", nil, "Skip a step (repeatable; combines with --steps)")
+	cmd.Flags().StringArrayVar(&artifacts, "artifact", nil, "Provide a skipped step's artifact as step:artifact=path (repeatable)")
 	cmd.Flags().BoolVar(&opts.Continuous, "continuous", false, "Run pipeline in continuous mode, iterating over work items from --source")
 	cmd.Flags().StringVar(&opts.Source, "source", "", "Work item source URI (e.g., github:label=bug, file:queue.txt)")
 	cmd.Flags().IntVar(&opts.MaxIterations, "max-iterations", 0, "Maximum number of iterations (0 = unlimited)")
@@ -148,10 +213,19 @@ Model formats vary by adapter: claude uses "haiku"/"opus", opencode uses
 	cmd.Flags().BoolVar(&opts.Detach, "detach", false, "Run pipeline as a detached background process")
 	cmd.Flags().BoolVar(&opts.AutoApprove, "auto-approve", false, "Auto-approve all approval gates using default choices (required for --detach with gates)")
 	cmd.Flags().BoolVar(&opts.NoRetro, "no-retro", false, "Skip retrospective generation for this run")
+	cmd.Flags().BoolVar(&opts.Deterministic, "deterministic", false, "Reproducible run: seeded run IDs and branch names, pinned template time, temperature 0")
+	cmd.Flags().StringVar(&opts.Seed, "seed", "", "Seed for --deterministic run IDs (default empty)")
+	cmd.Flags().BoolVar(&opts.NoAdapterCache, "no-adapter-cache", false, "Call the adapter for steps with cache: instead of replaying cached results")
+	cmd.Flags().IntVar(&opts.MaxParallel, "max-parallel", 0, "Maximum ready steps to run at once (0 = runtime.max_parallel_steps, else unlimited)")
+	cmd.Flags().StringVar(&opts.Priority, "priority", "", "Queue priority of a detached run while all workers are busy: interactive (default), scheduled or batch")
+	cmd.Flags().BoolVar(&opts.IfNotAlreadySucceeded, "if-not-already-succeeded", false, "Skip if a run with the same pipeline definition and input already succeeded or is in progress")
+
+	// Registered last so template names never shadow the flags above.
+	templateInputs = registerInputTemplateFlags(cmd, pipelinesDir())
 
 	// Group flags by tier for organized --help output
-	essentialFlags := []string{"pipeline", "input", "model", "adapter"}
-	executionFlags := []string{"from-step", "force", "dry-run", "timeout", "steps", "exclude", "on-failure", "detach"}
+	essentialFlags := []string{"pipeline", "input", "input-file", "var", "model", "adapter"}
+	executionFlags := []string{"from-step", "force", "dry-run", "timeout", "steps", "exclude", "on-failure", "detach", "if-not-already-succeeded", "deterministic", "seed", "no-adapter-cache", "max-parallel", "priority"}
 	continuousFlags := []string{"continuous", "source", "max-iterations", "delay"}
 	devDebugFlags := []string{"mock", "preserve-workspace", "auto-approve", "no-retro", "force-model", "run", "manifest"}
 
@@ -182,6 +256,9 @@ Model formats vary by adapter: claude uses "haiku"/"opus", opencode uses
 		printFlagGroup("Execution", executionFlags)
 		printFlagGroup("Continuous", continuousFlags)
 		printFlagGroup("Dev/Debug", devDebugFlags)
+		if len(templateInputs) > 0 {
+			printFlagGroup("Input templates", templateInputs.names())
+		}
 
 		// Print inherited persistent flags so parent flags (--verbose, --debug, etc.) appear
 		parentFlags := c.InheritedFlags()
@@ -212,13 +289,37 @@ func runRun(opts RunOptions, debug bool) error {
 		return nil
 	}
 
+	if err := checkPipelineDeprecation(p, opts.DryRun, time.Now()); err != nil {
+		return err
+	}
+
 	if opts.DryRun {
 		return performDryRun(p, &m, stepFilter)
 	}
 
+	if opts.IfNotAlreadySucceeded && skipDuplicateRun(opts, p) {
+		return nil
+	}
+
+	if opts.Priority != "" {
+		if err := state.ValidateRunPriority(opts.Priority); err != nil {
+			return NewCLIError(CodeInvalidArgs, err.Error(), "Use --priority interactive, scheduled or batch")
+		}
+		if !opts.Detach {
+			return NewCLIError(CodeInvalidArgs,
+				"--priority only applies to detached runs",
+				"Add --detach; foreground runs start without queueing for a worker slot")
+		}
+	}
+
 	// Detached mode: re-exec ourselves as a detached subprocess and return immediately.
 	// This reuses the same pattern as the TUI's pipeline_launcher.go.
 	if opts.Detach {
+		if opts.Deterministic {
+			return NewCLIError(CodeInvalidArgs,
+				"--deterministic cannot be combined with --detach",
+				"Remove --detach to run deterministically in the foreground")
+		}
 		// Validate: if pipeline has approval gates with choices, --auto-approve is required
 		if !opts.AutoApprove && p.HasApprovalGates() {
 			return NewCLIError(CodeInvalidArgs,
@@ -233,11 +334,15 @@ func runRun(opts RunOptions, debug bool) error {
 	store := buildStateStore()
 	if store != nil {
 		defer store.Close()
+		state.SetRunIDFormatter(store, pipeline.RunIDFormatter(m.Runtime))
 	}
 
 	autoRecoverResumeInput(&opts, store, p)
 
 	runID := resolveOrGenerateRunID(opts, store, p, &m)
+	if store != nil {
+		_ = store.SetRunIdempotencyKey(runID, pipeline.IdempotencyKey(p, opts.Input))
+	}
 
 	res, err := buildExecutor(opts, &m, p, store, stepFilter, runID, debug)
 	if err != nil {
@@ -250,7 +355,7 @@ func runRun(opts RunOptions, debug bool) error {
 	execOpts := res.execOpts
 
 	if opts.Continuous {
-		return runContinuous(ctx, opts, &m, p, store, adapterRunner, emitter, execOpts)
+		return runContinuous(ctx, opts, &m, p, store, runID, adapterRunner, emitter, execOpts)
 	}
 
 	pipelineStart := time.Now()
@@ -292,6 +397,25 @@ func runRun(opts RunOptions, debug bool) error {
 	return nil
 }
 
+// checkPipelineDeprecation warns about a deprecated pipeline and refuses to
+// run it once its sunset date has passed. Dry runs only warn.
+func checkPipelineDeprecation(p *pipeline.Pipeline, dryRun bool, now time.Time) error {
+	d := p.Metadata.Deprecated
+	if d == nil {
+		return nil
+	}
+	notice := d.Notice(p.Metadata.Name, now)
+	if d.Sunsetted(now) && !dryRun {
+		hint := "Choose another pipeline with 'wave list pipelines'"
+		if d.Replacement != "" {
+			hint = fmt.Sprintf("Run 'wave run %s' instead", d.Replacement)
+		}
+		return NewCLIError(CodePipelineSunset, notice, hint)
+	}
+	fmt.Fprintf(os.Stderr, "warning: %s\n", notice)
+	return nil
+}
+
 // runDetached spawns a new `wave run` subprocess that is fully detached from
 // the current process session via internal/runner. The subprocess inherits
 // all flags except --detach and runs the pipeline in its own session group.
@@ -308,6 +432,7 @@ func runDetached(opts RunOptions, p *pipeline.Pipeline, m *manifest.Manifest) er
 		return fmt.Errorf("detach requires state store: %w", err)
 	}
 	defer store.Close()
+	state.SetRunIDFormatter(store, pipeline.RunIDFormatter(m.Runtime))
 
 	maxWorkers := 5
 	if m != nil && m.Runtime.MaxConcurrentWorkers > 0 {
@@ -324,6 +449,7 @@ func runDetached(opts RunOptions, p *pipeline.Pipeline, m *manifest.Manifest) er
 	if err != nil {
 		return err
 	}
+	_ = store.SetRunIdempotencyKey(runID, pipeline.IdempotencyKey(p, opts.Input))
 
 	fmt.Fprintf(os.Stderr, "  Pipeline '%s' launched (detached)\n", p.Metadata.Name)
 	fmt.Fprintf(os.Stderr, "  Run ID:  %s\n", runID)
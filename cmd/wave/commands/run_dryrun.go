@@ -3,7 +3,7 @@ package commands
 // performDryRun renders the execution plan for a pipeline without running
 // any steps. Each step prints its persona/sub-pipeline, dependencies,
 // adapter and tool permissions, mounted directories, memory strategy,
-// inject/output artifacts, and contract policy. When a step filter is
+// inject/output artifacts, static prompt token estimate, and contract policy. When a step filter is
 // active, [RUN]/[SKIP] markers and downstream artifact warnings are shown.
 // Finally, dry-run composition validation is delegated to
 // pipeline.NewDryRunValidator and printed to stderr; an error is returned
@@ -69,6 +69,10 @@ func performDryRun(p *pipeline.Pipeline, m *manifest.Manifest, filter *pipeline.
 
 		if len(step.Memory.InjectArtifacts) > 0 {
 			for _, art := range step.Memory.InjectArtifacts {
+				if art.FromPipeline != "" {
+					fmt.Fprintf(os.Stderr, "     Inject: %s (latest completed run of %s) as %s\n", art.Artifact, art.FromPipeline, art.As)
+					continue
+				}
 				fmt.Fprintf(os.Stderr, "     Inject: %s:%s as %s\n", art.Step, art.Artifact, art.As)
 			}
 		}
@@ -79,6 +83,13 @@ func performDryRun(p *pipeline.Pipeline, m *manifest.Manifest, filter *pipeline.
 			}
 		}
 
+		if persona != nil && !isCompositionStep(step) {
+			est := buildPromptTokenReport(step, m)
+			if est.TotalTokens > 0 {
+				fmt.Fprintf(os.Stderr, "     Prompt estimate: %d tokens (%s tokenizer)\n", est.TotalTokens, est.Tokenizer)
+			}
+		}
+
 		if step.Handover.Contract.Type != "" {
 			fmt.Fprintf(os.Stderr, "     Contract: %s", step.Handover.Contract.Type)
 			if step.Handover.Contract.OnFailure != "" {
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"github.com/recinq/wave/internal/security"
+)
+
+// stdinInputArg is the input argument that reads the input from stdin.
+const stdinInputArg = "-"
+
+// maxRunInputBytes caps input read from stdin or --input-file at the input
+// sanitizer's max_input_length. The sanitizer truncates longer input without
+// telling anyone, so it is refused here instead.
+var maxRunInputBytes = security.DefaultSecurityConfig().Sanitization.MaxInputLength
+
+// readRunInput replaces the run's input with the contents of inputFile, or
+// of stdin when the input is "-". A terminal stdin is refused so that
+// `wave run fix -` does not silently wait for typing.
+func readRunInput(opts *RunOptions, inputFile string, stdin io.Reader, stdinIsTerminal bool) error {
+	switch {
+	case inputFile != "":
+		if opts.Input != "" {
+			return NewCLIError(CodeFlagConflict, "--input-file cannot be combined with an input argument",
+				"Drop the input argument, or drop --input-file")
+		}
+		f, err := os.Open(inputFile)
+		if err != nil {
+			return NewCLIError(CodeInvalidArgs, fmt.Sprintf("cannot read --input-file: %s", err),
+				"Check the file path").WithCause(err)
+		}
+		defer f.Close()
+		input, err := readLimitedInput(f, inputFile)
+		if err != nil {
+			return err
+		}
+		opts.Input = input
+	case opts.Input == stdinInputArg:
+		if stdinIsTerminal {
+			return NewCLIError(CodeInvalidArgs, "input '-' reads from stdin, but nothing is piped",
+				"Pipe the input, e.g. cat issue.md | wave run <pipeline> -")
+		}
+		input, err := readLimitedInput(stdin, "stdin")
+		if err != nil {
+			return err
+		}
+		opts.Input = input
+	}
+	return nil
+}
+
+// readLimitedInput reads r, named source in errors, refusing input larger
+// than maxRunInputBytes and input that is not text.
+func readLimitedInput(r io.Reader, source string) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(maxRunInputBytes)+1))
+	if err != nil {
+		return "", NewCLIError(CodeInvalidArgs, fmt.Sprintf("cannot read input from %s: %s", source, err), "").WithCause(err)
+	}
+	if len(data) > maxRunInputBytes {
+		return "", NewCLIError(CodeInvalidArgs, fmt.Sprintf("input from %s exceeds %d bytes", source, maxRunInputBytes),
+			"Shorten the input, or put the details in a file the pipeline reads")
+	}
+	if bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		return "", NewCLIError(CodeInvalidArgs, fmt.Sprintf("input from %s is not UTF-8 text", source), "")
+	}
+	input := string(bytes.TrimSpace(data))
+	if input == "" {
+		return "", NewCLIError(CodeInvalidArgs, fmt.Sprintf("input from %s is empty", source), "")
+	}
+	return input, nil
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRunInput(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "spec.md")
+	require.NoError(t, os.WriteFile(spec, []byte("# Fix login\n\nUsers are logged out after 5 minutes.\n"), 0644))
+
+	t.Run("file", func(t *testing.T) {
+		opts := RunOptions{}
+		require.NoError(t, readRunInput(&opts, spec, nil, true))
+		assert.Equal(t, "# Fix login\n\nUsers are logged out after 5 minutes.", opts.Input)
+	})
+
+	t.Run("file with inline input", func(t *testing.T) {
+		opts := RunOptions{Input: "fix it"}
+		err := readRunInput(&opts, spec, nil, true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--input-file cannot be combined")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := readRunInput(&RunOptions{}, filepath.Join(dir, "missing.md"), nil, true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot read --input-file")
+	})
+
+	t.Run("stdin", func(t *testing.T) {
+		opts := RunOptions{Input: "-"}
+		require.NoError(t, readRunInput(&opts, "", strings.NewReader("fix the flaky test\n"), false))
+		assert.Equal(t, "fix the flaky test", opts.Input)
+	})
+
+	t.Run("stdin is a terminal", func(t *testing.T) {
+		err := readRunInput(&RunOptions{Input: "-"}, "", strings.NewReader(""), true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "nothing is piped")
+	})
+
+	t.Run("inline input is kept", func(t *testing.T) {
+		opts := RunOptions{Input: "fix it"}
+		require.NoError(t, readRunInput(&opts, "", nil, true))
+		assert.Equal(t, "fix it", opts.Input)
+	})
+
+	t.Run("accepts input at the sanitizer limit", func(t *testing.T) {
+		opts := RunOptions{Input: "-"}
+		require.NoError(t, readRunInput(&opts, "", strings.NewReader(strings.Repeat("é", maxRunInputBytes/2)), false))
+		assert.Len(t, opts.Input, maxRunInputBytes, "input the sanitizer keeps whole is not cut")
+	})
+
+	for name, data := range map[string]string{
+		"exceeds 10000 bytes": strings.Repeat("a", maxRunInputBytes+1),
+		"is not UTF-8 text":   "binary\x00data",
+		"is empty":            " \n\t",
+	} {
+		t.Run("rejects input that "+name, func(t *testing.T) {
+			err := readRunInput(&RunOptions{Input: "-"}, "", strings.NewReader(data), false)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "input from stdin "+name)
+		})
+	}
+}
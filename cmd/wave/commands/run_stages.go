@@ -132,6 +132,10 @@ func loadManifestAndPipeline(opts *RunOptions, debug *bool) (*pipeline.Pipeline,
 		}
 	}
 
+	if err := applyInputTemplate(opts, p); err != nil {
+		return nil, m, nil, false, err
+	}
+
 	// Warn on input/pipeline mismatch (non-blocking)
 	if opts.Input != "" {
 		if mismatch := suggest.CheckInputPipelineMismatch(opts.Input, opts.Pipeline); mismatch != nil {
@@ -141,13 +145,24 @@ func loadManifestAndPipeline(opts *RunOptions, debug *bool) (*pipeline.Pipeline,
 
 	// Parse and validate step filter flags
 	stepFilter := pipeline.ParseStepFilter(opts.Steps, opts.Exclude)
+	if stepFilter == nil && len(opts.Artifacts) > 0 {
+		return nil, m, nil, false, fmt.Errorf("--artifact provides artifacts for skipped steps; use it with --steps, --exclude or --skip-step")
+	}
 	if stepFilter != nil {
+		stepFilter.Artifacts = opts.Artifacts
 		if err := stepFilter.Validate(p); err != nil {
 			return nil, m, nil, false, err
 		}
 		if err := stepFilter.ValidateCombinations(opts.FromStep); err != nil {
 			return nil, m, nil, false, err
 		}
+		steps := make([]*pipeline.Step, len(p.Steps))
+		for i := range p.Steps {
+			steps[i] = &p.Steps[i]
+		}
+		if err := stepFilter.ValidateArtifacts(stepFilter.Apply(steps), p); err != nil {
+			return nil, m, nil, false, err
+		}
 	}
 
 	return p, m, stepFilter, false, nil
@@ -199,17 +214,68 @@ func autoRecoverResumeInput(opts *RunOptions, store state.StateStore, p *pipelin
 // set (detach subprocess or TUI launch) it is reused verbatim; otherwise the
 // state store mints a new ID via CreateRun so the run shows up in the
 // dashboard. Falls back to GenerateRunID when the store is unavailable.
+// --deterministic runs use the seeded ID and get no dashboard record.
 func resolveOrGenerateRunID(opts RunOptions, store state.StateStore, p *pipeline.Pipeline, m *manifest.Manifest) string {
+	if opts.Deterministic && opts.RunID == "" {
+		// Store-minted IDs embed the wall clock; a replay must not.
+		return pipeline.DeterministicRunID(p.Metadata.Name, opts.Seed, m.Runtime.PipelineIDHashLength)
+	}
 	runID, resolveIDErr := resolveRunID(opts.RunID, store, p.Metadata.Name, opts.Input)
 	if resolveIDErr != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to create run record: %v\n", resolveIDErr)
 	}
 	if runID == "" {
-		runID = pipeline.GenerateRunID(p.Metadata.Name, m.Runtime.PipelineIDHashLength)
+		runID = pipeline.NewRunID(m.Runtime, p.Metadata.Name)
 	}
 	return runID
 }
 
+// findDuplicateRun returns the most recent run other than excludeRunID
+// recorded with the same idempotency key that either completed or is still
+// pending or running. Failed and cancelled runs do not count, so a retry
+// after a failure still goes ahead.
+func findDuplicateRun(store state.StateStore, key, excludeRunID string) (*state.RunRecord, error) {
+	runs, err := store.ListRuns(state.ListRunsOptions{IdempotencyKey: key})
+	if err != nil {
+		return nil, err
+	}
+	for i := range runs {
+		run := &runs[i]
+		if run.RunID == excludeRunID {
+			continue
+		}
+		switch run.Status {
+		case "completed", "pending", "running":
+			return run, nil
+		}
+	}
+	return nil, nil
+}
+
+// skipDuplicateRun implements --if-not-already-succeeded: it reports true,
+// after telling the user which run it matched, when a run with the same
+// pipeline definition and input already succeeded or is in flight. A state
+// store that cannot be opened or queried never blocks the run.
+func skipDuplicateRun(opts RunOptions, p *pipeline.Pipeline) bool {
+	store := buildStateStore()
+	if store == nil {
+		return false
+	}
+	defer store.Close()
+
+	dup, err := findDuplicateRun(store, pipeline.IdempotencyKey(p, opts.Input), opts.RunID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: duplicate-run check failed: %v\n", err)
+		return false
+	}
+	if dup == nil {
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "  Pipeline '%s' already %s for this input — skipping\n", p.Metadata.Name, dup.Status)
+	fmt.Fprintf(os.Stderr, "  Run ID:  %s\n", dup.RunID)
+	return true
+}
+
 // runResources bundles every component the runRun execution path needs after
 // the executor is wired (executor, emitter, adapter runner, workspace root,
 // raw execOpts so continuous mode can clone them per iteration). Close runs
@@ -489,9 +555,11 @@ func runOnce(ctx context.Context, res *runResources, opts RunOptions) (*pipeline
 
 // runContinuous drives the --continuous batch loop. Each work item from the
 // configured source spawns a fresh executor that clones execOpts and pins a
-// new run ID, so iterations stay independent in the dashboard. Returns
-// non-nil when the loop itself fails or any iteration fails (with a count).
-func runContinuous(ctx context.Context, opts RunOptions, m *manifest.Manifest, p *pipeline.Pipeline, store state.StateStore, runner adapter.AdapterRunner, emitter event.EventEmitter, execOpts []pipeline.ExecutorOption) error {
+// new run ID. Iteration runs are recorded as loop_iteration children of
+// parentRunID so the batch shows up as one run tree, and parentRunID gets
+// the batch outcome. Returns non-nil when the loop itself fails or any
+// iteration fails (with a count).
+func runContinuous(ctx context.Context, opts RunOptions, m *manifest.Manifest, p *pipeline.Pipeline, store state.StateStore, parentRunID string, runner adapter.AdapterRunner, emitter event.EventEmitter, execOpts []pipeline.ExecutorOption) error {
 	// Parse source URI
 	srcCfg, err := continuous.ParseSourceURI(opts.Source)
 	if err != nil {
@@ -508,6 +576,11 @@ func runContinuous(ctx context.Context, opts RunOptions, m *manifest.Manifest, p
 		return fmt.Errorf("invalid --delay %q: %w", opts.Delay, err)
 	}
 
+	var iteration, totalTokens int
+	if store != nil {
+		_ = store.UpdateRunStatus(parentRunID, "running", "", 0)
+	}
+
 	contRunner := &continuous.Runner{
 		Source:        src,
 		PipelineName:  p.Metadata.Name,
@@ -521,9 +594,15 @@ func runContinuous(ctx context.Context, opts RunOptions, m *manifest.Manifest, p
 				var iterRunID string
 				if store != nil {
 					iterRunID, _ = store.CreateRun(p.Metadata.Name, execInput)
+					if iterRunID != "" {
+						index := iteration
+						_ = store.SetParentRun(iterRunID, parentRunID, "")
+						_ = store.SetRunComposition(iterRunID, state.RunKindLoopIteration, "", "", &index, nil)
+					}
 				}
+				iteration++
 				if iterRunID == "" {
-					iterRunID = pipeline.GenerateRunID(p.Metadata.Name, m.Runtime.PipelineIDHashLength)
+					iterRunID = pipeline.NewRunID(m.Runtime, p.Metadata.Name)
 				}
 
 				// Create a fresh executor for this iteration
@@ -537,6 +616,7 @@ func runContinuous(ctx context.Context, opts RunOptions, m *manifest.Manifest, p
 				// Update run status
 				if store != nil {
 					tokens := iterExecutor.GetTotalTokens()
+					totalTokens += tokens
 					if execErr != nil {
 						if updateErr := store.UpdateRunStatus(iterRunID, "failed", execErr.Error(), tokens); updateErr != nil {
 							fmt.Fprintf(os.Stderr, "warning: failed to update run status: %v\n", updateErr)
@@ -554,6 +634,16 @@ func runContinuous(ctx context.Context, opts RunOptions, m *manifest.Manifest, p
 	}
 
 	summary, contErr := contRunner.Run(ctx)
+	if store != nil {
+		status := "completed"
+		switch {
+		case ctx.Err() != nil:
+			status = "cancelled"
+		case contErr != nil || summary.HasFailures():
+			status = "failed"
+		}
+		_ = store.UpdateRunStatus(parentRunID, status, "", totalTokens)
+	}
 	if contErr != nil {
 		return fmt.Errorf("continuous run failed: %w", contErr)
 	}
@@ -682,17 +772,25 @@ func printRejectionSummary(opts RunOptions, p *pipeline.Pipeline, rejectionErr *
 	fmt.Fprintf(os.Stderr, "    This is not a runtime failure — the pipeline declared the work non-actionable by design.\n\n")
 }
 
+// runUsage renders a run's token count and estimated cost for the
+// completion line, e.g. ", 45.2k tokens, $0.42". Zero values are left out.
+func runUsage(tokens int, costUSD float64) string {
+	var s string
+	if tokens > 0 {
+		s += ", " + display.FormatTokenCount(tokens) + " tokens"
+	}
+	if costUSD > 0 {
+		s += ", " + display.FormatCost(costUSD)
+	}
+	return s
+}
+
 func printSummary(opts RunOptions, executor *pipeline.DefaultPipelineExecutor, p *pipeline.Pipeline, runID string, elapsed time.Duration, emitter event.EventEmitter) {
 	// Show human summary only in auto/text modes — json and quiet stay clean
 	if opts.Output.Format == OutputFormatAuto || opts.Output.Format == OutputFormatText {
 		totalTokens := executor.GetTotalTokens()
-		if totalTokens > 0 {
-			fmt.Fprintf(os.Stderr, "\n  ✓ Pipeline '%s' completed successfully (%.1fs, %s tokens)\n",
-				p.Metadata.Name, elapsed.Seconds(), display.FormatTokenCount(totalTokens))
-		} else {
-			fmt.Fprintf(os.Stderr, "\n  ✓ Pipeline '%s' completed successfully (%.1fs)\n",
-				p.Metadata.Name, elapsed.Seconds())
-		}
+		fmt.Fprintf(os.Stderr, "\n  ✓ Pipeline '%s' completed successfully (%.1fs%s)\n",
+			p.Metadata.Name, elapsed.Seconds(), runUsage(totalTokens, executor.GetTotalCost()))
 		// Build structured outcome summary from outcome tracker
 		tracker := executor.GetOutcomeTracker()
 		outcome := display.BuildOutcome(tracker, p.Metadata.Name, runID, true, elapsed, totalTokens, "", nil)
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/recinq/wave/internal/pipeline"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// globalFlagNames are the root command's persistent flags. They are not yet
+// visible when wave run's flags are built, and a local flag of the same name
+// would silently shadow them, so templates may not use these names.
+var globalFlagNames = map[string]bool{
+	"manifest": true, "debug": true, "output": true, "verbose": true,
+	"no-tui": true, "json": true, "quiet": true, "no-color": true,
+	"help": true, "version": true,
+}
+
+// inputTemplateFlags maps each input template name declared by a pipeline on
+// disk to its `wave run` flag value.
+type inputTemplateFlags map[string]*string
+
+// values returns the template flags set on the command line.
+func (f inputTemplateFlags) values(cmd *cobra.Command) map[string]string {
+	var set map[string]string
+	for name, value := range f {
+		if cmd.Flags().Changed(name) {
+			if set == nil {
+				set = make(map[string]string)
+			}
+			set[name] = *value
+		}
+	}
+	return set
+}
+
+func (f inputTemplateFlags) names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// registerInputTemplateFlags adds a string flag for every input template
+// declared by the pipelines in dir. Which pipeline a flag applies to is only
+// known once the run's pipeline is loaded, so applyInputTemplate rejects
+// templates the selected pipeline does not declare. Names taken by another
+// flag are skipped. Unreadable pipeline files are ignored here; loading the
+// pipeline reports them.
+func registerInputTemplateFlags(cmd *cobra.Command, dir string) inputTemplateFlags {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.yaml"))
+
+	owners := make(map[string][]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var p struct {
+			Metadata struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+			InputTemplates map[string]string `yaml:"input_templates"`
+		}
+		if yaml.Unmarshal(data, &p) != nil {
+			continue
+		}
+		for name := range p.InputTemplates {
+			owners[name] = append(owners[name], p.Metadata.Name)
+		}
+	}
+	if len(owners) == 0 {
+		return nil
+	}
+
+	flags := make(inputTemplateFlags)
+	for name, pipelines := range owners {
+		if globalFlagNames[name] || cmd.Flags().Lookup(name) != nil || !pipeline.IsInputTemplateName(name) {
+			continue
+		}
+		sort.Strings(pipelines)
+		flags[name] = cmd.Flags().String(name, "", fmt.Sprintf("Input template (pipelines: %s)", strings.Join(pipelines, ", ")))
+	}
+	return flags
+}
+
+// applyInputTemplate sets opts.Input from the input template selected on the
+// command line, if any.
+func applyInputTemplate(opts *RunOptions, p *pipeline.Pipeline) error {
+	if len(opts.TemplateInputs) == 0 {
+		return nil
+	}
+
+	var names []string
+	for name := range opts.TemplateInputs {
+		if _, ok := p.InputTemplates[name]; !ok {
+			hint := fmt.Sprintf("Pipeline '%s' declares no input templates", p.Metadata.Name)
+			if len(p.InputTemplates) > 0 {
+				hint = fmt.Sprintf("Input templates of '%s': --%s", p.Metadata.Name, strings.Join(p.InputTemplateNames(), ", --"))
+			}
+			return NewCLIError(CodeInvalidArgs, fmt.Sprintf("pipeline '%s' has no input template --%s", p.Metadata.Name, name), hint)
+		}
+		names = append(names, name)
+	}
+	if len(names) > 1 {
+		return NewCLIError(CodeFlagConflict, "only one input template can be used per run",
+			"Pick one of --"+strings.Join(p.InputTemplateNames(), ", --"))
+	}
+	if opts.Input != "" {
+		return NewCLIError(CodeFlagConflict, fmt.Sprintf("--%s builds the input, so no other input can be given", names[0]),
+			fmt.Sprintf("Drop the input argument, or drop --%s", names[0]))
+	}
+
+	opts.Input, _ = p.ExpandInputTemplate(names[0], opts.TemplateInputs[names[0]])
+	return nil
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recinq/wave/internal/pipeline"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterInputTemplateFlags(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(body), 0644))
+	}
+	write("fix.yaml", "metadata:\n  name: fix\ninput_templates:\n  issue: \"Fix #{{ value }}\"\n  verbose: \"{{ value }}\"\n  force: \"{{ value }}\"\n")
+	write("review.yaml", "metadata:\n  name: review\ninput_templates:\n  issue: \"Review #{{ value }}\"\n  pr: \"PR {{ value }}\"\n")
+	write("broken.yaml", "metadata: [\n")
+
+	cmd := &cobra.Command{Use: "run", RunE: func(*cobra.Command, []string) error { return nil }}
+	cmd.Flags().Bool("force", false, "")
+	flags := registerInputTemplateFlags(cmd, dir)
+
+	// --verbose is a global flag and --force a run flag, so both keep their meaning.
+	assert.Equal(t, []string{"issue", "pr"}, flags.names())
+	assert.Equal(t, "Input template (pipelines: fix, review)", cmd.Flags().Lookup("issue").Usage)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--issue", "123"}))
+	assert.Equal(t, map[string]string{"issue": "123"}, flags.values(cmd))
+
+	assert.Nil(t, registerInputTemplateFlags(&cobra.Command{Use: "run"}, t.TempDir()))
+}
+
+func TestApplyInputTemplate(t *testing.T) {
+	p := &pipeline.Pipeline{
+		Metadata:       pipeline.PipelineMetadata{Name: "fix"},
+		InputTemplates: map[string]string{"issue": "Fix GitHub issue #{{ value }}", "pr": "Fix PR {{ value }}"},
+	}
+
+	opts := RunOptions{TemplateInputs: map[string]string{"issue": "123"}}
+	require.NoError(t, applyInputTemplate(&opts, p))
+	assert.Equal(t, "Fix GitHub issue #123", opts.Input)
+
+	var cliErr *CLIError
+	opts = RunOptions{TemplateInputs: map[string]string{"branch": "main"}}
+	require.ErrorAs(t, applyInputTemplate(&opts, p), &cliErr)
+	assert.Equal(t, CodeInvalidArgs, cliErr.Code)
+	assert.Equal(t, "Input templates of 'fix': --issue, --pr", cliErr.Suggestion)
+
+	opts = RunOptions{TemplateInputs: map[string]string{"issue": "1", "pr": "2"}}
+	require.ErrorAs(t, applyInputTemplate(&opts, p), &cliErr)
+	assert.Equal(t, CodeFlagConflict, cliErr.Code)
+
+	opts = RunOptions{Input: "fix it", TemplateInputs: map[string]string{"issue": "1"}}
+	require.ErrorAs(t, applyInputTemplate(&opts, p), &cliErr)
+	assert.Equal(t, CodeFlagConflict, cliErr.Code)
+
+	opts = RunOptions{Input: "fix it"}
+	require.NoError(t, applyInputTemplate(&opts, p))
+	assert.Equal(t, "fix it", opts.Input)
+}
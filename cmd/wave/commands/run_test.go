@@ -15,6 +15,7 @@ import (
 	"github.com/recinq/wave/internal/event"
 	"github.com/recinq/wave/internal/manifest"
 	"github.com/recinq/wave/internal/pipeline"
+	"github.com/recinq/wave/internal/state"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -563,3 +564,76 @@ func TestNewRunCmdFlags(t *testing.T) {
 	assert.NotNil(t, modelFlag, "model flag should exist")
 	assert.Equal(t, "", modelFlag.DefValue, "model flag default should be empty")
 }
+
+func TestFindDuplicateRun(t *testing.T) {
+	store, err := state.NewStateStore(filepath.Join(t.TempDir(), "state.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	start := time.Now().Add(-time.Hour)
+	for i, r := range []state.SeedRunOptions{
+		{RunID: "failed-run", Status: "failed"},
+		{RunID: "done-run", Status: "completed"},
+		{RunID: "cancelled-run", Status: "cancelled"},
+		{RunID: "other-input", Status: "completed"},
+	} {
+		r.PipelineName = "impl-issue"
+		r.StartedAt = start.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, state.SeedRun(store, r))
+	}
+	for _, id := range []string{"failed-run", "done-run", "cancelled-run"} {
+		require.NoError(t, store.SetRunIdempotencyKey(id, "key-42"))
+	}
+	require.NoError(t, store.SetRunIdempotencyKey("other-input", "key-43"))
+
+	dup, err := findDuplicateRun(store, "key-42", "")
+	require.NoError(t, err)
+	require.NotNil(t, dup)
+	assert.Equal(t, "done-run", dup.RunID)
+
+	// The current run never counts as its own duplicate.
+	dup, err = findDuplicateRun(store, "key-42", "done-run")
+	require.NoError(t, err)
+	assert.Nil(t, dup)
+
+	// In-flight runs are duplicates too.
+	require.NoError(t, state.SeedRun(store, state.SeedRunOptions{RunID: "running-run", PipelineName: "impl-issue", Status: "running", StartedAt: time.Now()}))
+	require.NoError(t, store.SetRunIdempotencyKey("running-run", "key-43"))
+	dup, err = findDuplicateRun(store, "key-43", "")
+	require.NoError(t, err)
+	require.NotNil(t, dup)
+	assert.Equal(t, "running-run", dup.RunID)
+
+	dup, err = findDuplicateRun(store, "key-unknown", "")
+	require.NoError(t, err)
+	assert.Nil(t, dup)
+}
+
+func TestCheckPipelineDeprecation(t *testing.T) {
+	p := &pipeline.Pipeline{Metadata: pipeline.PipelineMetadata{
+		Name:       "fix",
+		Deprecated: &pipeline.Deprecation{Replacement: "impl-issue", Sunset: "2026-12-01"},
+	}}
+	before := time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local)
+	after := time.Date(2026, 12, 2, 0, 0, 0, 0, time.Local)
+
+	stderr, err := captureStderr(func() error { return checkPipelineDeprecation(p, false, before) })
+	require.NoError(t, err)
+	assert.Contains(t, stderr, `warning: pipeline "fix" is deprecated; use "impl-issue" instead`)
+
+	_, err = captureStderr(func() error { return checkPipelineDeprecation(p, false, after) })
+	var cliErr *CLIError
+	require.ErrorAs(t, err, &cliErr)
+	assert.Equal(t, CodePipelineSunset, cliErr.Code)
+	assert.Equal(t, "Run 'wave run impl-issue' instead", cliErr.Suggestion)
+
+	// Dry runs of a retired pipeline still work, with the notice.
+	stderr, err = captureStderr(func() error { return checkPipelineDeprecation(p, true, after) })
+	require.NoError(t, err)
+	assert.Contains(t, stderr, "was retired on 2026-12-01")
+
+	p.Metadata.Deprecated = nil
+	stderr, err = captureStderr(func() error { return checkPipelineDeprecation(p, false, after) })
+	require.NoError(t, err)
+	assert.Empty(t, stderr)
+}
@@ -0,0 +1,262 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/recinq/wave/internal/contract"
+	"github.com/recinq/wave/internal/listing"
+	"github.com/recinq/wave/internal/pipeline"
+	"github.com/spf13/cobra"
+)
+
+// SchemaListEntry is one registered schema in `wave schemas list` output.
+type SchemaListEntry struct {
+	Name     string        `json:"name"`
+	Versions []int         `json:"versions"`
+	UsedBy   []SchemaUsage `json:"used_by"`
+}
+
+// SchemaUsage is a contract that references a registered schema.
+type SchemaUsage struct {
+	Pipeline string `json:"pipeline"`
+	Step     string `json:"step"`
+	Ref      string `json:"ref"`
+}
+
+// SchemaDiffOutput is the JSON output of `wave schemas diff`.
+type SchemaDiffOutput struct {
+	Old     string                  `json:"old"`
+	New     string                  `json:"new"`
+	Changes []contract.SchemaChange `json:"changes"`
+}
+
+// NewSchemasCmd creates the `wave schemas` parent command.
+func NewSchemasCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schemas",
+		Short: "Inspect the shared contract schema registry",
+		Long: `Inspect the shared contract schema registry.
+
+Schemas shared by several pipelines live in .agents/schemas/<name>/ as
+v1.schema.json, v2.schema.json, ... A contract references one with
+schema_ref: <name>@v<N> instead of copying the file, so pipelines pinned
+to the same version validate against the same schema. An unpinned
+schema_ref: <name> follows the latest version.`,
+	}
+
+	cmd.AddCommand(newSchemasListCmd())
+	cmd.AddCommand(newSchemasDiffCmd())
+
+	return cmd
+}
+
+func newSchemasListCmd() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List registered schemas, their versions and the pipelines using them",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			format = ResolveFormat(cmd, format)
+			return runSchemasList(format)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json")
+	return cmd
+}
+
+func newSchemasDiffCmd() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "diff <name> | <name@vA> <name@vB>",
+		Short: "Show the changes between two versions of a schema",
+		Long: `Show the changes between two versions of a registered schema.
+
+With a single name, compares the two latest versions. Changes are listed
+by JSON pointer as added (+), removed (-) or changed (~).`,
+		Example: `  wave schemas diff issue-content
+  wave schemas diff issue-content@v1 issue-content@v3`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format = ResolveFormat(cmd, format)
+			return runSchemasDiff(args, format)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json")
+	return cmd
+}
+
+func runSchemasList(format string) error {
+	schemas, err := contract.ListRegisteredSchemas(contract.SchemaRegistryDir)
+	if err != nil {
+		return NewCLIError(CodeInternalError, fmt.Sprintf("failed to read schema registry: %s", err), "Check "+contract.SchemaRegistryDir+" permissions").WithCause(err)
+	}
+
+	usage := make(map[string][]SchemaUsage)
+	for _, p := range pipeline.ScanPipelinesDir(listing.DefaultPipelineDir) {
+		for _, step := range p.Steps {
+			for _, c := range step.Handover.EffectiveContracts() {
+				ref, err := contract.ParseSchemaRef(c.SchemaRef)
+				if c.SchemaRef == "" || err != nil {
+					continue
+				}
+				usage[ref.Name] = append(usage[ref.Name], SchemaUsage{Pipeline: p.Metadata.Name, Step: step.ID, Ref: c.SchemaRef})
+			}
+		}
+	}
+
+	entries := make([]SchemaListEntry, 0, len(schemas))
+	for _, s := range schemas {
+		used := usage[s.Name]
+		if used == nil {
+			used = []SchemaUsage{}
+		}
+		sort.Slice(used, func(i, j int) bool {
+			if used[i].Pipeline != used[j].Pipeline {
+				return used[i].Pipeline < used[j].Pipeline
+			}
+			return used[i].Step < used[j].Step
+		})
+		entries = append(entries, SchemaListEntry{Name: s.Name, Versions: s.Versions, UsedBy: used})
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("No schemas registered in %s\n", contract.SchemaRegistryDir)
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVERSIONS\tUSED BY")
+	for _, e := range entries {
+		versions := make([]string, len(e.Versions))
+		for i, v := range e.Versions {
+			versions[i] = fmt.Sprintf("v%d", v)
+		}
+		users := make([]string, len(e.UsedBy))
+		for i, u := range e.UsedBy {
+			users[i] = fmt.Sprintf("%s/%s (%s)", u.Pipeline, u.Step, schemaRefVersion(u.Ref))
+		}
+		usedBy := strings.Join(users, ", ")
+		if usedBy == "" {
+			usedBy = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", e.Name, strings.Join(versions, ", "), usedBy)
+	}
+	return tw.Flush()
+}
+
+// schemaRefVersion renders the version part of a reference for display.
+func schemaRefVersion(ref string) string {
+	if _, version, ok := strings.Cut(ref, "@"); ok {
+		return version
+	}
+	return "latest"
+}
+
+func runSchemasDiff(args []string, format string) error {
+	oldRef, newRef, err := schemaDiffRefs(args)
+	if err != nil {
+		return err
+	}
+	oldData, err := readRegisteredSchema(oldRef)
+	if err != nil {
+		return err
+	}
+	newData, err := readRegisteredSchema(newRef)
+	if err != nil {
+		return err
+	}
+	changes, err := contract.DiffSchemas(oldData, newData)
+	if err != nil {
+		return NewCLIError(CodeInvalidArgs, fmt.Sprintf("failed to compare schemas: %s", err), "Check that both versions are valid JSON").WithCause(err)
+	}
+
+	if format == "json" {
+		if changes == nil {
+			changes = []contract.SchemaChange{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(SchemaDiffOutput{Old: oldRef.String(), New: newRef.String(), Changes: changes})
+	}
+
+	fmt.Printf("%s -> %s\n", oldRef, newRef)
+	if len(changes) == 0 {
+		fmt.Println("  no changes")
+		return nil
+	}
+	for _, c := range changes {
+		switch c.Kind {
+		case "added":
+			fmt.Printf("  + %s: %s\n", c.Path, c.New)
+		case "removed":
+			fmt.Printf("  - %s: %s\n", c.Path, c.Old)
+		default:
+			fmt.Printf("  ~ %s: %s -> %s\n", c.Path, c.Old, c.New)
+		}
+	}
+	return nil
+}
+
+// schemaDiffRefs resolves the diff arguments to two pinned references. A
+// single name compares its two latest versions.
+func schemaDiffRefs(args []string) (contract.SchemaRef, contract.SchemaRef, error) {
+	refs := make([]contract.SchemaRef, len(args))
+	for i, arg := range args {
+		ref, err := contract.ParseSchemaRef(arg)
+		if err != nil {
+			return contract.SchemaRef{}, contract.SchemaRef{}, NewCLIError(CodeInvalidArgs, err.Error(), "Use <name> or <name>@v<N>")
+		}
+		refs[i] = ref
+	}
+
+	if len(refs) == 1 {
+		versions, err := contract.SchemaVersions(contract.SchemaRegistryDir, refs[0].Name)
+		if err != nil {
+			return contract.SchemaRef{}, contract.SchemaRef{}, NewCLIError(CodeInternalError, fmt.Sprintf("failed to read schema registry: %s", err), "").WithCause(err)
+		}
+		if len(versions) < 2 {
+			return contract.SchemaRef{}, contract.SchemaRef{}, NewCLIError(CodeInvalidArgs,
+				fmt.Sprintf("schema %q has fewer than two versions", refs[0].Name),
+				"Run 'wave schemas list' to see registered versions")
+		}
+		n := len(versions)
+		return contract.SchemaRef{Name: refs[0].Name, Version: versions[n-2]}, contract.SchemaRef{Name: refs[0].Name, Version: versions[n-1]}, nil
+	}
+
+	for i := range refs {
+		if refs[i].Version == 0 {
+			versions, err := contract.SchemaVersions(contract.SchemaRegistryDir, refs[i].Name)
+			if err != nil || len(versions) == 0 {
+				return contract.SchemaRef{}, contract.SchemaRef{}, NewCLIError(CodeInvalidArgs,
+					fmt.Sprintf("schema %q is not registered", refs[i].Name),
+					"Run 'wave schemas list' to see registered schemas")
+			}
+			refs[i].Version = versions[len(versions)-1]
+		}
+	}
+	return refs[0], refs[1], nil
+}
+
+// readRegisteredSchema reads the file of a pinned reference.
+func readRegisteredSchema(ref contract.SchemaRef) ([]byte, error) {
+	path, err := contract.ResolveSchemaRef(contract.SchemaRegistryDir, ref.String())
+	if err != nil {
+		return nil, NewCLIError(CodeInvalidArgs, err.Error(), "Run 'wave schemas list' to see registered versions")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, NewCLIError(CodeInternalError, fmt.Sprintf("failed to read %s: %s", path, err), "").WithCause(err)
+	}
+	return data, nil
+}
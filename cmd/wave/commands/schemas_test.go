@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recinq/wave/internal/contract"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSchemaRegistry creates a registry with two versions of issue-content
+// and a pipeline pinned to v1 in a fresh working directory.
+func setupSchemaRegistry(t *testing.T) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	for v, content := range map[int]string{
+		1: `{"type":"object","required":["title"]}`,
+		2: `{"type":"object","required":["title","body"]}`,
+	} {
+		path := contract.SchemaVersionPath(contract.SchemaRegistryDir, "issue-content", v)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	require.NoError(t, os.MkdirAll(".agents/pipelines", 0o755))
+	require.NoError(t, os.WriteFile(".agents/pipelines/triage.yaml", []byte(`kind: WavePipeline
+metadata:
+  name: triage
+steps:
+  - id: fetch
+    persona: navigator
+    handover:
+      contract:
+        type: json_schema
+        schema_ref: issue-content@v1
+`), 0o644))
+}
+
+func TestRunSchemasList(t *testing.T) {
+	setupSchemaRegistry(t)
+
+	out := captureOutput(t, func() {
+		require.NoError(t, runSchemasList("text"))
+	})
+	assert.Contains(t, out, "issue-content")
+	assert.Contains(t, out, "v1, v2")
+	assert.Contains(t, out, "triage/fetch (v1)")
+}
+
+func TestRunSchemasDiff(t *testing.T) {
+	setupSchemaRegistry(t)
+
+	out := captureOutput(t, func() {
+		require.NoError(t, runSchemasDiff([]string{"issue-content"}, "text"))
+	})
+	assert.Contains(t, out, "issue-content@v1 -> issue-content@v2")
+	assert.Contains(t, out, `~ /required: ["title"] -> ["title","body"]`)
+
+	assert.Error(t, runSchemasDiff([]string{"issue-content@v1", "issue-content@v5"}, "text"))
+	assert.Error(t, runSchemasDiff([]string{"missing"}, "text"))
+}
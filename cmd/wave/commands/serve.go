@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/recinq/wave/internal/adapter"
 	"github.com/recinq/wave/internal/manifest"
 	"github.com/recinq/wave/internal/webui"
 	"github.com/spf13/cobra"
@@ -25,6 +27,7 @@ func NewServeCmd() *cobra.Command {
 		authMode      string
 		tlsCert       string
 		tlsKey        string
+		noReload      bool
 	)
 
 	cmd := &cobra.Command{
@@ -42,7 +45,22 @@ Authentication modes:
   none    - No authentication (default for localhost)
   bearer  - Bearer token authentication (default for non-localhost)
   jwt     - JWT token authentication (requires WAVE_JWT_SECRET)
-  mtls    - Mutual TLS client certificate authentication`,
+  oidc    - OpenID Connect ID tokens (requires server.auth.oidc in wave.yaml)
+  mtls    - Mutual TLS client certificate authentication
+
+Callers hold one of the roles viewer, operator, approver or admin. Viewers
+read runs, operators also start and cancel them, approvers also resolve
+approval gates and proposals, and admins also use the admin console.
+Role-bound API tokens are configured under server.auth.tokens; the --token
+token grants admin. server.rate_limit caps mutating requests per caller, and
+every start, cancel and approval is recorded in the state DB audit trail,
+exportable from GET /api/admin/audit/export.
+
+The server watches the manifest and .agents/pipelines/ and reloads them
+when they change, or on SIGHUP. Edits are validated first: an invalid
+manifest or pipeline is logged and the previous configuration stays in
+effect. Running pipelines keep the configuration they started with.
+Server settings (bind, auth, TLS) still require a restart.`,
 		Example: `  wave serve
   wave serve --port 9090
   wave serve --bind 0.0.0.0 --token mysecret
@@ -64,6 +82,9 @@ Authentication modes:
 
 			// Build config from manifest defaults, then override with CLI flags
 			cfg := buildServerConfig(m, port, bind, token, dbPath, maxConcurrent, authMode, tlsCert, tlsKey, cmd)
+			if !noReload {
+				cfg.ManifestPath = manifestPath
+			}
 
 			srv, err := webui.NewServer(cfg)
 			if err != nil {
@@ -80,9 +101,10 @@ Authentication modes:
 	cmd.Flags().StringVar(&dbPath, "db", "", "Path to state database (default: .agents/state.db)")
 	cmd.Flags().StringVar(&manifestPath, "manifest", "wave.yaml", "Path to manifest file")
 	cmd.Flags().IntVar(&maxConcurrent, "max-concurrent", 0, "Maximum concurrent pipeline runs (default: 5)")
-	cmd.Flags().StringVar(&authMode, "auth-mode", "", "Authentication mode: none, bearer, jwt, mtls")
+	cmd.Flags().StringVar(&authMode, "auth-mode", "", "Authentication mode: none, bearer, jwt, oidc, mtls")
 	cmd.Flags().StringVar(&tlsCert, "tls-cert", "", "Path to TLS certificate file")
 	cmd.Flags().StringVar(&tlsKey, "tls-key", "", "Path to TLS key file")
+	cmd.Flags().BoolVar(&noReload, "no-reload", false, "Do not reload the manifest and pipelines when they change")
 
 	return cmd
 }
@@ -120,6 +142,24 @@ func buildServerConfig(m *manifest.Manifest, port int, bind, token, dbPath strin
 		if sc.Auth.JWTSecret != "" {
 			cfg.JWTSecret = expandEnvVars(sc.Auth.JWTSecret)
 		}
+		if sc.Auth.SlackSigningSecret != "" {
+			cfg.SlackSigningSecret = expandEnvVars(sc.Auth.SlackSigningSecret)
+		}
+		for _, t := range sc.Auth.Tokens {
+			cfg.APITokens = append(cfg.APITokens, webui.APIToken{Name: t.Name, Token: expandEnvVars(t.Token), Role: webui.Role(t.Role), RateLimit: t.RateLimit})
+		}
+		if sc.Auth.OIDC != nil {
+			cfg.OIDC = &webui.OIDCConfig{Issuer: sc.Auth.OIDC.Issuer, Audience: sc.Auth.OIDC.Audience, RoleClaim: sc.Auth.OIDC.RoleClaim}
+		}
+		cfg.DefaultRole = webui.Role(sc.Auth.DefaultRole)
+		cfg.RateLimit = webui.RateLimitConfig{RequestsPerMinute: sc.RateLimit.RequestsPerMinute, Burst: sc.RateLimit.Burst}
+		cfg.PublicStatus = sc.PublicStatus
+		if sc.WarmPool.Enabled {
+			cfg.WarmPool = &adapter.WarmPoolConfig{
+				MaxIdle:     sc.WarmPool.MaxIdle,
+				IdleTimeout: time.Duration(sc.WarmPool.IdleTimeoutMinutes) * time.Minute,
+			}
+		}
 		if sc.TLS.Cert != "" && !cmd.Flags().Changed("tls-cert") {
 			tlsCert = sc.TLS.Cert
 		}
@@ -154,6 +194,10 @@ func buildServerConfig(m *manifest.Manifest, port int, bind, token, dbPath strin
 		}
 	}
 
+	if cfg.SlackSigningSecret == "" {
+		cfg.SlackSigningSecret = os.Getenv("WAVE_SLACK_SIGNING_SECRET")
+	}
+
 	// Resolve token
 	cfg.Token = resolveToken(token, cfg.Bind)
 
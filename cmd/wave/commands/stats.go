@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/pipeline"
+	"github.com/recinq/wave/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// StatsOptions holds options for the stats command.
+type StatsOptions struct {
+	Since     string
+	Pipeline  string
+	FlakyOnly bool
+	Manifest  string
+	Format    string
+}
+
+// StepStatsReport is the step reliability summary emitted by `wave stats`.
+type StepStatsReport struct {
+	Since string          `json:"since"`
+	Steps []StepStatsItem `json:"steps"`
+}
+
+// StepStatsItem is the reliability of one pipeline step.
+type StepStatsItem struct {
+	Pipeline    string    `json:"pipeline"`
+	Step        string    `json:"step"`
+	Runs        int       `json:"runs"`
+	FailedRuns  int       `json:"failed_runs"`
+	RetriedRuns int       `json:"retried_runs"`
+	FailureRate float64   `json:"failure_rate"`
+	RetryRate   float64   `json:"retry_rate"`
+	Flaky       bool      `json:"flaky"`
+	LastRunAt   time.Time `json:"last_run_at"`
+}
+
+// NewStatsCmd creates the stats command.
+func NewStatsCmd() *cobra.Command {
+	var opts StatsOptions
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show step reliability and flag flaky steps",
+		Long: `Show how often each pipeline step failed or needed a retry.
+
+A step is flagged flaky when it ran at least runtime.flaky_steps.min_runs
+times in the window and failed in at least failure_rate of those runs, or
+needed a retry in at least retry_rate of them (defaults: 5 runs, 20%, 30%,
+over 14 days). With runtime.flaky_steps.action set, runs label flaky steps
+in their output ("warn") or also give them more attempts ("retry").`,
+		Example: `  wave stats                          # All steps, flaky first
+  wave stats --flaky                  # Only flaky steps
+  wave stats --pipeline impl-issue --since 30d
+  wave stats --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Format = ResolveFormat(cmd, opts.Format)
+			return runStats(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Since, "since", "", "History window (e.g. 7d, 24h); default runtime.flaky_steps.window_days")
+	cmd.Flags().StringVar(&opts.Pipeline, "pipeline", "", "Filter by pipeline name")
+	cmd.Flags().BoolVar(&opts.FlakyOnly, "flaky", false, "Only show flaky steps")
+	cmd.Flags().StringVar(&opts.Manifest, "manifest", "wave.yaml", "Manifest providing runtime.flaky_steps thresholds")
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "Output format (text, json)")
+
+	return cmd
+}
+
+func runStats(opts StatsOptions) error {
+	var cfg manifest.FlakyStepsConfig
+	if _, err := os.Stat(opts.Manifest); err == nil {
+		m, err := loadManifestStrict(opts.Manifest)
+		if err != nil {
+			return err
+		}
+		cfg = m.Runtime.FlakySteps
+	}
+
+	window := cfg.Window()
+	since := opts.Since
+	if since == "" {
+		since = fmt.Sprintf("%dd", int(window.Hours()/24))
+	} else {
+		d, err := parseSinceDuration(since)
+		if err != nil {
+			return NewCLIError(CodeInvalidArgs, "invalid --since value: "+err.Error(),
+				"Use a duration like '7d', '24h', or '30m'.").WithCause(err)
+		}
+		window = d
+	}
+
+	dbPath := ".agents/state.db"
+	var records []state.StepReliabilityRecord
+	if _, err := os.Stat(dbPath); err == nil {
+		store, err := state.NewReadOnlyStateStore(dbPath)
+		if err != nil {
+			return NewCLIError(CodeStateDBError, fmt.Sprintf("failed to open state database: %s", err), "Check .agents/state.db file permissions or run 'wave run' to create it").WithCause(err)
+		}
+		defer store.Close()
+
+		records, err = store.ListStepReliability(opts.Pipeline, time.Now().Add(-window))
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to query step history: %s", err), "The state database may need migration -- try 'wave migrate up'").WithCause(err)
+		}
+	}
+
+	report := StepStatsReport{Since: since, Steps: []StepStatsItem{}}
+	for _, s := range pipeline.AssessStepReliability(records, cfg) {
+		if opts.FlakyOnly && !s.Flaky {
+			continue
+		}
+		report.Steps = append(report.Steps, StepStatsItem{
+			Pipeline:    s.PipelineName,
+			Step:        s.StepID,
+			Runs:        s.Runs,
+			FailedRuns:  s.FailedRuns,
+			RetriedRuns: s.RetriedRuns,
+			FailureRate: s.FailureRate,
+			RetryRate:   s.RetryRate,
+			Flaky:       s.Flaky,
+			LastRunAt:   s.LastRunAt,
+		})
+	}
+
+	if opts.Format == "json" {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to marshal JSON: %s", err), "This is an internal serialization error").WithCause(err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	printStepStats(report, opts.FlakyOnly)
+	return nil
+}
+
+func printStepStats(report StepStatsReport, flakyOnly bool) {
+	if len(report.Steps) == 0 {
+		if flakyOnly {
+			fmt.Printf("No flaky steps in the last %s\n", report.Since)
+		} else {
+			fmt.Printf("No step attempts recorded in the last %s\n", report.Since)
+		}
+		return
+	}
+
+	flaky := 0
+	for _, s := range report.Steps {
+		if s.Flaky {
+			flaky++
+		}
+	}
+	fmt.Printf("Step reliability over the last %s (%d flaky)\n\n", report.Since, flaky)
+	fmt.Printf("  %-25s %-20s %5s %8s %8s  %s\n", "PIPELINE", "STEP", "RUNS", "FAILED", "RETRIED", "")
+	fmt.Println("  " + strings.Repeat("-", 80))
+	for _, s := range report.Steps {
+		label := ""
+		if s.Flaky {
+			label = "flaky"
+		}
+		fmt.Printf("  %-25s %-20s %5d %7.0f%% %7.0f%%  %s\n",
+			truncate(s.Pipeline, 25), truncate(s.Step, 20), s.Runs, s.FailureRate*100, s.RetryRate*100, label)
+	}
+}
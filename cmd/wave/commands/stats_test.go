@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunStats(t *testing.T) {
+	orig, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(orig) })
+	require.NoError(t, os.MkdirAll(".agents", 0o755))
+
+	store, err := state.NewStateStore(".agents/state.db")
+	require.NoError(t, err)
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		runID, err := store.CreateRun("impl-issue", "")
+		require.NoError(t, err)
+		// implement needs a retry in two of the five runs (40%).
+		attempt := 1
+		if i < 2 {
+			require.NoError(t, store.RecordStepAttempt(&state.StepAttemptRecord{RunID: runID, StepID: "implement", Attempt: 1, State: "failed", StartedAt: now}))
+			attempt = 2
+		}
+		require.NoError(t, store.RecordStepAttempt(&state.StepAttemptRecord{RunID: runID, StepID: "implement", Attempt: attempt, State: "succeeded", StartedAt: now}))
+		require.NoError(t, store.RecordStepAttempt(&state.StepAttemptRecord{RunID: runID, StepID: "plan", Attempt: 1, State: "succeeded", StartedAt: now}))
+	}
+	store.Close()
+
+	out := captureOutput(t, func() {
+		require.NoError(t, runStats(StatsOptions{Manifest: "wave.yaml", Format: "json", FlakyOnly: true}))
+	})
+	var report StepStatsReport
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	assert.Equal(t, "14d", report.Since)
+	require.Len(t, report.Steps, 1)
+	assert.Equal(t, "implement", report.Steps[0].Step)
+	assert.Equal(t, 5, report.Steps[0].Runs)
+	assert.Equal(t, 2, report.Steps[0].RetriedRuns)
+	assert.True(t, report.Steps[0].Flaky)
+
+	out = captureOutput(t, func() {
+		require.NoError(t, runStats(StatsOptions{Manifest: "wave.yaml", Format: "text", Since: "1d"}))
+	})
+	assert.Contains(t, out, "Step reliability over the last 1d (1 flaky)")
+	assert.Contains(t, out, "plan")
+
+	assert.Error(t, runStats(StatsOptions{Manifest: "wave.yaml", Since: "soon"}))
+}
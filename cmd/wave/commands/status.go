@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/recinq/wave/internal/display"
 	"github.com/recinq/wave/internal/state"
@@ -39,6 +41,19 @@ type StatusRunInfo struct {
 	CompletedAt string `json:"completed_at,omitempty"`
 	Input       string `json:"input,omitempty"`
 	Error       string `json:"error,omitempty"`
+	// ParentRunID, ParentStepID and RunKind place the run in a run tree
+	// (sub-pipeline, compose or continuous children). Children holds the
+	// runs started under this one.
+	ParentRunID  string          `json:"parent_run_id,omitempty"`
+	ParentStepID string          `json:"parent_step_id,omitempty"`
+	RunKind      string          `json:"run_kind,omitempty"`
+	Children     []StatusRunInfo `json:"children,omitempty"`
+	// CostUSD is the run's estimated cost and Environment the snapshot
+	// recorded when it started. Only the single-run view loads them.
+	CostUSD     float64               `json:"cost_usd,omitempty"`
+	Environment *state.RunEnvironment `json:"environment,omitempty"`
+	// BudgetExceeded reports the token budgets the run crossed.
+	BudgetExceeded string `json:"budget_exceeded,omitempty"`
 }
 
 // conditionalColor returns the ANSI color code if NO_COLOR is not set,
@@ -63,6 +78,9 @@ Without arguments, shows currently running pipelines.
 With --all, shows recent pipelines (default 10).
 With a run-id argument, shows detailed status for that specific run.
 
+Runs started by another run (sub-pipelines, compose sequences, continuous
+iterations) are shown nested under their parent run.
+
 Examples:
   wave status                    # Show running pipelines
   wave status --all              # Show all recent pipelines
@@ -118,15 +136,18 @@ func runStatus(opts StatusOptions) error {
 // runRecordToStatusInfo converts a state.RunRecord to a StatusRunInfo for display.
 func runRecordToStatusInfo(r *state.RunRecord) StatusRunInfo {
 	info := StatusRunInfo{
-		RunID:       r.RunID,
-		Pipeline:    r.PipelineName,
-		Status:      r.Status,
-		CurrentStep: r.CurrentStep,
-		Tokens:      r.TotalTokens,
-		TokensStr:   formatTokens(r.TotalTokens),
-		StartedAt:   r.StartedAt.Format("2006-01-02 15:04:05"),
-		Input:       r.Input,
-		Error:       r.ErrorMessage,
+		RunID:        r.RunID,
+		Pipeline:     r.PipelineName,
+		Status:       r.Status,
+		CurrentStep:  r.CurrentStep,
+		Tokens:       r.TotalTokens,
+		TokensStr:    formatTokens(r.TotalTokens),
+		StartedAt:    r.StartedAt.Format("2006-01-02 15:04:05"),
+		Input:        r.Input,
+		Error:        r.ErrorMessage,
+		ParentRunID:  r.ParentRunID,
+		ParentStepID: r.ParentStepID,
+		RunKind:      r.RunKind,
 	}
 
 	if r.CompletedAt != nil {
@@ -142,12 +163,16 @@ func runRecordToStatusInfo(r *state.RunRecord) StatusRunInfo {
 }
 
 // statusStore is the narrow surface the status command needs:
-// per-run lookups and the running/recent run listings used by the
+// per-run lookups, child-run lookups for run trees, the run environment
+// snapshot and cost, and the running/recent run listings used by the
 // table/JSON output paths.
 type statusStore interface {
 	GetRun(runID string) (*state.RunRecord, error)
+	GetRunEnvironment(runID string) (*state.RunEnvironment, error)
+	GetRunCosts(runIDs []string) (map[string]float64, error)
 	GetRunningRuns() ([]state.RunRecord, error)
 	ListRuns(opts state.ListRunsOptions) ([]state.RunRecord, error)
+	GetChildRuns(parentRunID string) ([]state.RunRecord, error)
 	UpdateRunStatus(runID string, status string, currentStep string, tokens int) error
 }
 
@@ -166,7 +191,14 @@ func showRunDetails(store statusStore, opts StatusOptions) error {
 		return err
 	}
 
-	run := runRecordToStatusInfo(record)
+	run := statusRunTree(store, record, make(map[string]bool))
+	run.BudgetExceeded = record.BudgetExceeded
+	if env, err := store.GetRunEnvironment(opts.RunID); err == nil {
+		run.Environment = env
+	}
+	if costs, err := store.GetRunCosts([]string{opts.RunID}); err == nil {
+		run.CostUSD = costs[opts.RunID]
+	}
 
 	if opts.Format == "json" {
 		output := StatusOutput{Runs: []StatusRunInfo{run}}
@@ -191,6 +223,12 @@ func showRunDetails(store statusStore, opts StatusOptions) error {
 	}
 	fmt.Printf("Elapsed:    %s\n", run.Elapsed)
 	fmt.Printf("Tokens:     %s\n", run.TokensStr)
+	if run.CostUSD > 0 {
+		fmt.Printf("Cost:       %s\n", display.FormatCost(run.CostUSD))
+	}
+	if run.BudgetExceeded != "" {
+		fmt.Printf("Budget:     exceeded (%s)\n", run.BudgetExceeded)
+	}
 	if run.Input != "" {
 		// Truncate long input
 		input := run.Input
@@ -202,10 +240,112 @@ func showRunDetails(store statusStore, opts StatusOptions) error {
 	if run.Error != "" {
 		fmt.Printf("Error:      %s\n", run.Error)
 	}
+	if run.ParentRunID != "" {
+		parent := run.ParentRunID
+		if run.ParentStepID != "" {
+			parent += " (step " + run.ParentStepID + ")"
+		}
+		fmt.Printf("Parent:     %s\n", parent)
+	}
+	if run.Environment != nil {
+		printRunEnvironment(run.Environment)
+	}
+	if len(run.Children) > 0 {
+		fmt.Println("Child runs:")
+		for _, row := range flattenRunTree(run.Children, 1) {
+			child := row.run
+			detail := child.Status
+			if child.ParentStepID != "" {
+				detail += ", step " + child.ParentStepID
+			}
+			fmt.Printf("  %s%s  %s  %s%s%s  %s  %s\n", runTreePrefix(row.depth), child.RunID, child.Pipeline,
+				statusColor(child.Status), detail, conditionalColor("\033[0m"), child.Elapsed, child.TokensStr)
+		}
+	}
 
 	return nil
 }
 
+// printRunEnvironment prints the environment snapshot recorded at run start.
+func printRunEnvironment(env *state.RunEnvironment) {
+	fmt.Println("Environment:")
+	wave := env.WaveVersion
+	if env.WaveRevision != "" {
+		wave += " (" + env.WaveRevision + ")"
+	}
+	if wave != "" {
+		fmt.Printf("  Wave:     %s\n", wave)
+	}
+	if env.GitSHA != "" {
+		git := env.GitSHA
+		if env.GitBranch != "" {
+			git += " on " + env.GitBranch
+		}
+		if env.GitDirty {
+			git += " (dirty)"
+		}
+		fmt.Printf("  Git:      %s\n", git)
+	}
+	fmt.Printf("  Platform: %s/%s\n", env.OS, env.Arch)
+	if env.ManifestDigest != "" {
+		fmt.Printf("  Manifest: sha256:%s\n", env.ManifestDigest)
+	}
+	names := make([]string, 0, len(env.AdapterVersions))
+	for name := range env.AdapterVersions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		version := env.AdapterVersions[name]
+		if version == "" {
+			version = "unavailable"
+		}
+		fmt.Printf("  %-9s %s\n", name+":", version)
+	}
+}
+
+// statusRunTree converts r to a StatusRunInfo with the runs started under
+// it attached, depth-first. seen guards against parent cycles.
+func statusRunTree(store statusStore, r *state.RunRecord, seen map[string]bool) StatusRunInfo {
+	info := runRecordToStatusInfo(r)
+	seen[r.RunID] = true
+	children, err := store.GetChildRuns(r.RunID)
+	if err != nil {
+		return info
+	}
+	for i := range children {
+		if seen[children[i].RunID] {
+			continue
+		}
+		info.Children = append(info.Children, statusRunTree(store, &children[i], seen))
+	}
+	return info
+}
+
+// runTreeRow is one line of a rendered run tree.
+type runTreeRow struct {
+	run   StatusRunInfo
+	depth int
+}
+
+// flattenRunTree lists runs and their children depth-first for rendering.
+func flattenRunTree(runs []StatusRunInfo, depth int) []runTreeRow {
+	var rows []runTreeRow
+	for _, r := range runs {
+		rows = append(rows, runTreeRow{run: r, depth: depth})
+		rows = append(rows, flattenRunTree(r.Children, depth+1)...)
+	}
+	return rows
+}
+
+// runTreePrefix indents a child run under its parent.
+func runTreePrefix(depth int) string {
+	if depth == 0 {
+		return ""
+	}
+	return strings.Repeat("  ", depth-1) + "└ "
+}
+
 // showRunningRuns shows currently running pipelines.
 func showRunningRuns(store statusStore, opts StatusOptions) error {
 	records, err := store.GetRunningRuns()
@@ -237,9 +377,10 @@ func showRunningRuns(store statusStore, opts StatusOptions) error {
 	return outputRuns(runs, opts)
 }
 
-// showAllRuns shows recent pipelines.
+// showAllRuns shows recent top-level pipelines with the runs started under
+// them nested beneath.
 func showAllRuns(store statusStore, opts StatusOptions, limit int) error {
-	records, err := store.ListRuns(state.ListRunsOptions{Limit: limit})
+	records, err := store.ListRuns(state.ListRunsOptions{Limit: limit, TopLevelOnly: true})
 	if err != nil {
 		return err
 	}
@@ -253,9 +394,10 @@ func showAllRuns(store statusStore, opts StatusOptions, limit int) error {
 		return nil
 	}
 
+	seen := make(map[string]bool)
 	runs := make([]StatusRunInfo, len(records))
 	for i := range records {
-		runs[i] = runRecordToStatusInfo(&records[i])
+		runs[i] = statusRunTree(store, &records[i], seen)
 	}
 
 	return outputRuns(runs, opts)
@@ -310,11 +452,15 @@ func outputRuns(runs []StatusRunInfo, opts StatusOptions) error {
 		statusWidth, "STATUS", stepWidth, "STEP",
 		elapsedWidth, "ELAPSED", "TOKENS")
 
-	for _, run := range runs {
+	for _, row := range flattenRunTree(runs, 0) {
+		run := row.run
+		prefix := runTreePrefix(row.depth)
+		idWidth := runIDWidth - utf8.RuneCountInString(prefix)
 		runID := run.RunID
-		if len(runID) > runIDWidth && runIDWidth > 3 {
-			runID = runID[:runIDWidth-3] + "..."
+		if len(runID) > idWidth && idWidth > 3 {
+			runID = runID[:idWidth-3] + "..."
 		}
+		runID = prefix + runID
 		pipeline := run.Pipeline
 		if len(pipeline) > pipelineWidth && pipelineWidth > 3 {
 			pipeline = pipeline[:pipelineWidth-3] + "..."
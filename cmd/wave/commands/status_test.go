@@ -228,6 +228,115 @@ func TestStatusCmd_SpecificRunID(t *testing.T) {
 	assert.Contains(t, stdout, "fix bug in auth")
 }
 
+// TestStatusCmd_RunTree tests that child runs are nested under their parent.
+func TestStatusCmd_RunTree(t *testing.T) {
+	h := newStatusTestHelper(t)
+	h.chdir()
+	defer h.restore()
+
+	now := time.Now()
+	completed := now.Add(-1 * time.Minute)
+	h.createRun("compose-001", "compose:pipeline-a+pipeline-b", "completed", "", 8000, now.Add(-5*time.Minute), &completed)
+	h.createRun("child-a", "pipeline-a", "completed", "", 5000, now.Add(-4*time.Minute), &completed)
+	h.createRun("child-b", "pipeline-b", "failed", "", 3000, now.Add(-3*time.Minute), &completed)
+	h.createRun("grandchild", "audit", "completed", "", 1000, now.Add(-2*time.Minute), &completed)
+	require.NoError(t, h.store.SetParentRun("child-a", "compose-001", ""))
+	require.NoError(t, h.store.SetParentRun("child-b", "compose-001", ""))
+	require.NoError(t, h.store.SetParentRun("grandchild", "child-b", "audit"))
+
+	// --all lists only the top-level run, with its tree nested beneath.
+	stdout, _, err := executeStatusCmd("--all", "--format", "json")
+	require.NoError(t, err)
+	var output StatusOutput
+	require.NoError(t, json.Unmarshal([]byte(stdout), &output))
+	require.Len(t, output.Runs, 1)
+	root := output.Runs[0]
+	assert.Equal(t, "compose-001", root.RunID)
+	require.Len(t, root.Children, 2)
+	assert.Equal(t, "child-a", root.Children[0].RunID)
+	assert.Equal(t, "compose-001", root.Children[0].ParentRunID)
+	require.Len(t, root.Children[1].Children, 1)
+	assert.Equal(t, "grandchild", root.Children[1].Children[0].RunID)
+	assert.Equal(t, "audit", root.Children[1].Children[0].ParentStepID)
+
+	stdout, _, err = executeStatusCmd("--all")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "└ child-a")
+	assert.Contains(t, stdout, "  └ grandchild")
+
+	// A child run shows its parent and its own children.
+	stdout, _, err = executeStatusCmd("child-b")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Parent:     compose-001")
+	assert.Contains(t, stdout, "Child runs:")
+	assert.Contains(t, stdout, "└ grandchild")
+}
+
+// TestStatusCmd_Environment tests that the run's environment snapshot is shown.
+func TestStatusCmd_Environment(t *testing.T) {
+	h := newStatusTestHelper(t)
+	h.chdir()
+	defer h.restore()
+
+	h.createRun("env-run", "my-pipeline", "failed", "", 0, time.Now().Add(-time.Minute), nil)
+	require.NoError(t, h.store.SetRunEnvironment("env-run", &state.RunEnvironment{
+		CapturedAt:      time.Now(),
+		WaveVersion:     "v0.40.0",
+		GitSHA:          "0123456789abcdef",
+		GitBranch:       "main",
+		GitDirty:        true,
+		OS:              "linux",
+		Arch:            "amd64",
+		AdapterVersions: map[string]string{"claude": "2.1.0 (Claude Code)", "codex": ""},
+		ManifestDigest:  "abc123",
+	}))
+
+	stdout, _, err := executeStatusCmd("env-run")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Environment:")
+	assert.Contains(t, stdout, "Wave:     v0.40.0")
+	assert.Contains(t, stdout, "Git:      0123456789abcdef on main (dirty)")
+	assert.Contains(t, stdout, "Platform: linux/amd64")
+	assert.Contains(t, stdout, "Manifest: sha256:abc123")
+	assert.Contains(t, stdout, "claude:   2.1.0 (Claude Code)")
+	assert.Contains(t, stdout, "codex:    unavailable")
+
+	stdout, _, err = executeStatusCmd("env-run", "--format", "json")
+	require.NoError(t, err)
+	var output StatusOutput
+	require.NoError(t, json.Unmarshal([]byte(stdout), &output))
+	require.Len(t, output.Runs, 1)
+	require.NotNil(t, output.Runs[0].Environment)
+	assert.Equal(t, "main", output.Runs[0].Environment.GitBranch)
+
+	// Runs without a snapshot show no environment block.
+	h.createRun("old-run", "my-pipeline", "completed", "", 0, time.Now().Add(-time.Hour), nil)
+	stdout, _, err = executeStatusCmd("old-run")
+	require.NoError(t, err)
+	assert.NotContains(t, stdout, "Environment:")
+}
+
+// TestStatusCmd_Cost tests that the run's recorded cost is shown.
+func TestStatusCmd_Cost(t *testing.T) {
+	h := newStatusTestHelper(t)
+	h.chdir()
+	defer h.restore()
+
+	h.createRun("priced-run", "my-pipeline", "completed", "", 12000, time.Now().Add(-time.Minute), nil)
+	require.NoError(t, h.store.RecordStepCost(&state.StepCostRecord{RunID: "priced-run", StepID: "plan", PipelineName: "my-pipeline", CostUSD: 0.42}))
+
+	stdout, _, err := executeStatusCmd("priced-run")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Cost:       $0.42")
+
+	stdout, _, err = executeStatusCmd("priced-run", "--format", "json")
+	require.NoError(t, err)
+	var output StatusOutput
+	require.NoError(t, json.Unmarshal([]byte(stdout), &output))
+	require.Len(t, output.Runs, 1)
+	assert.InDelta(t, 0.42, output.Runs[0].CostUSD, 1e-9)
+}
+
 // TestStatusCmd_SpecificRunIDNotFound tests when specific run ID is not found.
 func TestStatusCmd_SpecificRunIDNotFound(t *testing.T) {
 	h := newStatusTestHelper(t)
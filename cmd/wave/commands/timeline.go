@@ -0,0 +1,490 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// TimelineOptions holds options for the timeline command.
+type TimelineOptions struct {
+	Format string
+	To     string
+}
+
+// Timeline span kinds.
+const (
+	spanStep     = "step"
+	spanAttempt  = "attempt"
+	spanContract = "contract"
+	spanRetry    = "retry"
+)
+
+// TimelineSpan is one interval of a run's timeline. Retry markers are
+// instants (End equals Start).
+type TimelineSpan struct {
+	Step  string
+	Kind  string
+	Name  string
+	State string
+	Start time.Time
+	End   time.Time
+}
+
+// RunTimeline is the wall-clock layout of a run: one track per step,
+// ordered by start, holding the step span and its attempts, contract
+// validations and retries.
+type RunTimeline struct {
+	RunID    string
+	Pipeline string
+	Status   string
+	Start    time.Time
+	End      time.Time
+	Steps    []string
+	Spans    []TimelineSpan
+}
+
+// NewTimelineCmd creates the timeline command.
+func NewTimelineCmd() *cobra.Command {
+	var opts TimelineOptions
+
+	cmd := &cobra.Command{
+		Use:   "timeline <run-id>",
+		Short: "Export a run's step timings as a trace or Gantt chart",
+		Long: `Export where a run's wall-clock time went: every step, each of its
+attempts, contract validation and retries, with parallel branches on
+separate tracks.
+
+The trace format is Chrome trace event JSON, which opens in
+https://ui.perfetto.dev or chrome://tracing. The svg format is a
+self-contained Gantt chart. Timings come from the state database, so
+event-derived edges are accurate to the second.`,
+		Example: `  wave timeline impl-issue-20260301-101500-ab12 --to trace.json
+  wave timeline <run-id> --format svg --to timeline.svg`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTimeline(args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Format, "format", "trace", "Output format (trace, svg)")
+	cmd.Flags().StringVar(&opts.To, "to", "", "Write to a file instead of stdout")
+
+	return cmd
+}
+
+func runTimeline(runID string, opts TimelineOptions) error {
+	if opts.Format != "trace" && opts.Format != "svg" {
+		return NewCLIError(CodeInvalidArgs, fmt.Sprintf("unknown format %q", opts.Format), "Use --format trace or --format svg")
+	}
+
+	dbPath := getDbPath()
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return NewCLIError(CodeRunNotFound, "no runs found (state database does not exist)", "Run a pipeline first with 'wave run'")
+	}
+	store, err := state.NewReadOnlyStateStore(dbPath)
+	if err != nil {
+		return NewCLIError(CodeStateDBError, fmt.Sprintf("failed to open state database: %s", err), "Check .agents/state.db file permissions or run 'wave run' to create it").WithCause(err)
+	}
+	defer store.Close()
+
+	tl, err := loadRunTimeline(store, runID)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if opts.Format == "svg" {
+		writeTimelineSVG(&buf, tl)
+	} else if err := writeTimelineTrace(&buf, tl); err != nil {
+		return NewCLIError(CodeInternalError, fmt.Sprintf("failed to marshal trace: %s", err), "This is an internal serialization error").WithCause(err)
+	}
+
+	if opts.To == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(opts.To, buf.Bytes(), 0o644); err != nil {
+		return NewCLIError(CodeInternalError, fmt.Sprintf("failed to write %s: %s", opts.To, err), "Check that the directory exists and is writable").WithCause(err)
+	}
+	fmt.Printf("Wrote %s timeline of %s (%d steps, %s) to %s\n", opts.Format, tl.RunID, len(tl.Steps), formatDuration(tl.End.Sub(tl.Start)), opts.To)
+	if opts.Format == "trace" {
+		fmt.Println("Open it in https://ui.perfetto.dev or chrome://tracing")
+	}
+	return nil
+}
+
+func loadRunTimeline(store state.StateStore, runID string) (*RunTimeline, error) {
+	exists, err := store.RunExists(runID)
+	if err != nil {
+		return nil, NewCLIError(CodeInternalError, fmt.Sprintf("failed to verify run: %s", err), "The state database may be corrupted -- try 'wave migrate validate'").WithCause(err)
+	}
+	if !exists {
+		return nil, NewCLIError(CodeRunNotFound, fmt.Sprintf("run not found: %s", runID), "Use 'wave status --all' to list available runs")
+	}
+	run, err := store.GetRun(runID)
+	if err != nil {
+		return nil, NewCLIError(CodeStateDBError, fmt.Sprintf("failed to load run %s: %s", runID, err), "The state database may need migration -- try 'wave migrate up'").WithCause(err)
+	}
+	events, err := store.GetEvents(runID, state.EventQueryOptions{})
+	if err != nil {
+		return nil, NewCLIError(CodeStateDBError, fmt.Sprintf("failed to load events for %s: %s", runID, err), "The state database may need migration -- try 'wave migrate up'").WithCause(err)
+	}
+
+	var attempts []state.StepAttemptRecord
+	seen := make(map[string]bool)
+	for _, ev := range events {
+		if ev.StepID == "" || seen[ev.StepID] {
+			continue
+		}
+		seen[ev.StepID] = true
+		recs, err := store.GetStepAttempts(runID, ev.StepID)
+		if err != nil {
+			return nil, NewCLIError(CodeStateDBError, fmt.Sprintf("failed to load attempts for step %s: %s", ev.StepID, err), "The state database may need migration -- try 'wave migrate up'").WithCause(err)
+		}
+		attempts = append(attempts, recs...)
+	}
+	return buildRunTimeline(run, events, attempts), nil
+}
+
+// buildRunTimeline lays out a run from its events and step attempts. A step
+// spans from its first start to its last event or attempt; each attempt is
+// the terminal record of its number, or the start record of an attempt that
+// never finished, which runs to the end of the step.
+func buildRunTimeline(run *state.RunRecord, events []state.LogRecord, attempts []state.StepAttemptRecord) *RunTimeline {
+	tl := &RunTimeline{RunID: run.RunID, Pipeline: run.PipelineName, Status: run.Status, Start: run.StartedAt}
+
+	type stepTimes struct {
+		start, end time.Time
+		state      string
+		contracts  []TimelineSpan
+		retries    []TimelineSpan
+		openCheck  time.Time
+	}
+	steps := make(map[string]*stepTimes)
+	var order []string
+	get := func(id string) *stepTimes {
+		st, ok := steps[id]
+		if !ok {
+			st = &stepTimes{}
+			steps[id] = st
+			order = append(order, id)
+		}
+		return st
+	}
+	widen := func(st *stepTimes, start, end time.Time) {
+		if st.start.IsZero() || start.Before(st.start) {
+			st.start = start
+		}
+		if end.After(st.end) {
+			st.end = end
+		}
+	}
+
+	for _, ev := range events {
+		if ev.StepID == "" {
+			continue
+		}
+		st := get(ev.StepID)
+		widen(st, ev.Timestamp, ev.Timestamp)
+		switch ev.State {
+		case event.StateCompleted, event.StateFailed, event.StateSkipped:
+			st.state = ev.State
+		case event.StateRetrying:
+			st.retries = append(st.retries, TimelineSpan{Step: ev.StepID, Kind: spanRetry, Name: "retry", State: ev.Message, Start: ev.Timestamp, End: ev.Timestamp})
+		case "validating":
+			if st.openCheck.IsZero() {
+				st.openCheck = ev.Timestamp
+			}
+		case "contract_passed", "contract_failed":
+			if !st.openCheck.IsZero() {
+				result := "passed"
+				if ev.State == "contract_failed" {
+					result = "failed"
+				}
+				st.contracts = append(st.contracts, TimelineSpan{Step: ev.StepID, Kind: spanContract, Name: "contract validation", State: result, Start: st.openCheck, End: ev.Timestamp})
+				st.openCheck = time.Time{}
+			}
+		}
+	}
+
+	// Keep the terminal record of each attempt, else its start record.
+	type attemptKey struct {
+		step    string
+		attempt int
+	}
+	latest := make(map[attemptKey]state.StepAttemptRecord)
+	var attemptOrder []attemptKey
+	for _, a := range attempts {
+		k := attemptKey{a.StepID, a.Attempt}
+		prev, ok := latest[k]
+		if !ok {
+			attemptOrder = append(attemptOrder, k)
+		}
+		if !ok || prev.CompletedAt == nil {
+			latest[k] = a
+		}
+	}
+	attemptSpans := make(map[string][]TimelineSpan)
+	for _, k := range attemptOrder {
+		a := latest[k]
+		end := a.StartedAt
+		switch {
+		case a.DurationMs > 0:
+			end = a.StartedAt.Add(time.Duration(a.DurationMs) * time.Millisecond)
+		case a.CompletedAt != nil:
+			end = *a.CompletedAt
+		}
+		st := get(k.step)
+		widen(st, a.StartedAt, end)
+		attemptSpans[k.step] = append(attemptSpans[k.step], TimelineSpan{
+			Step: k.step, Kind: spanAttempt, Name: fmt.Sprintf("attempt %d", a.Attempt), State: a.State, Start: a.StartedAt, End: end,
+		})
+	}
+
+	for _, id := range order {
+		st := steps[id]
+		if !st.openCheck.IsZero() {
+			st.contracts = append(st.contracts, TimelineSpan{Step: id, Kind: spanContract, Name: "contract validation", State: "unfinished", Start: st.openCheck, End: st.end})
+		}
+		attemptsOf := attemptSpans[id]
+		for i := range attemptsOf {
+			if attemptsOf[i].State == event.StateRunning {
+				attemptsOf[i].End = st.end
+			}
+		}
+		stepState := st.state
+		if stepState == "" && len(attemptsOf) > 0 {
+			stepState = attemptsOf[len(attemptsOf)-1].State
+		}
+		tl.Spans = append(tl.Spans, TimelineSpan{Step: id, Kind: spanStep, Name: id, State: stepState, Start: st.start, End: st.end})
+		tl.Spans = append(tl.Spans, attemptsOf...)
+		for _, c := range st.contracts {
+			tl.Spans = append(tl.Spans, nestContract(c, attemptsOf))
+		}
+		tl.Spans = append(tl.Spans, st.retries...)
+
+		if tl.Start.IsZero() || st.start.Before(tl.Start) {
+			tl.Start = st.start
+		}
+		if st.end.After(tl.End) {
+			tl.End = st.end
+		}
+	}
+	if run.CompletedAt != nil && run.CompletedAt.After(tl.End) {
+		tl.End = *run.CompletedAt
+	}
+	if tl.End.Before(tl.Start) {
+		tl.End = tl.Start
+	}
+
+	tl.Steps = order
+	sort.SliceStable(tl.Steps, func(i, j int) bool {
+		return steps[tl.Steps[i]].start.Before(steps[tl.Steps[j]].start)
+	})
+	return tl
+}
+
+// nestContract clips a contract validation span to the attempt it started
+// in, or to the next attempt's start, so trace viewers nest it under the
+// step without overlapping an attempt's edge. Event timestamps are coarser
+// than attempt durations, which is what makes the spans disagree.
+func nestContract(c TimelineSpan, attempts []TimelineSpan) TimelineSpan {
+	for _, a := range attempts {
+		if !c.Start.Before(a.Start) && c.Start.Before(a.End) {
+			if c.End.After(a.End) {
+				c.End = a.End
+			}
+			return c
+		}
+		if a.Start.After(c.Start) && c.End.After(a.Start) {
+			c.End = a.Start
+			return c
+		}
+	}
+	return c
+}
+
+// traceEvent is one entry of the Chrome trace event format.
+type traceEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"`
+	Ts   int64          `json:"ts"`
+	Dur  *int64         `json:"dur,omitempty"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	S    string         `json:"s,omitempty"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// writeTimelineTrace writes tl as Chrome trace event JSON: the run on
+// thread 0 and each step on its own thread, in start order.
+func writeTimelineTrace(w io.Writer, tl *RunTimeline) error {
+	micros := func(t time.Time) int64 { return t.Sub(tl.Start).Microseconds() }
+	span := func(name, cat string, tid int, start, end time.Time, args map[string]any) traceEvent {
+		dur := end.Sub(start).Microseconds()
+		return traceEvent{Name: name, Cat: cat, Ph: "X", Ts: micros(start), Dur: &dur, Pid: 1, Tid: tid, Args: args}
+	}
+	thread := func(tid int, name string) []traceEvent {
+		return []traceEvent{
+			{Name: "thread_name", Ph: "M", Pid: 1, Tid: tid, Args: map[string]any{"name": name}},
+			{Name: "thread_sort_index", Ph: "M", Pid: 1, Tid: tid, Args: map[string]any{"sort_index": tid}},
+		}
+	}
+
+	events := []traceEvent{{Name: "process_name", Ph: "M", Pid: 1, Args: map[string]any{"name": fmt.Sprintf("%s (%s)", tl.Pipeline, tl.RunID)}}}
+	events = append(events, thread(0, "run")...)
+	events = append(events, span(tl.Pipeline, "run", 0, tl.Start, tl.End, map[string]any{"status": tl.Status}))
+
+	tids := make(map[string]int, len(tl.Steps))
+	for i, id := range tl.Steps {
+		tids[id] = i + 1
+		events = append(events, thread(i+1, id)...)
+	}
+	for _, s := range tl.Spans {
+		args := map[string]any{}
+		if s.State != "" {
+			args["state"] = s.State
+		}
+		if s.Kind == spanRetry {
+			events = append(events, traceEvent{Name: s.Name, Cat: s.Kind, Ph: "i", Ts: micros(s.Start), Pid: 1, Tid: tids[s.Step], S: "t", Args: args})
+			continue
+		}
+		events = append(events, span(s.Name, s.Kind, tids[s.Step], s.Start, s.End, args))
+	}
+
+	data, err := json.MarshalIndent(map[string]any{"traceEvents": events, "displayTimeUnit": "ms"}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Gantt chart layout, in pixels.
+const (
+	ganttWidth  = 1200
+	ganttLabelW = 220
+	ganttPadR   = 40
+	ganttTop    = 56
+	ganttRowH   = 28
+	ganttBarH   = 16
+)
+
+// ganttTicks are the axis intervals, the first giving at most ten ticks wins.
+var ganttTicks = []time.Duration{
+	time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second, 15 * time.Second, 30 * time.Second,
+	time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 6 * time.Hour,
+}
+
+func ganttColor(kind, state string) string {
+	switch {
+	case kind == spanContract:
+		return "#8e24aa"
+	case kind == spanRetry:
+		return "#fb8c00"
+	case state == event.StateFailed:
+		return "#e53935"
+	case state == "succeeded" || state == event.StateCompleted:
+		return "#43a047"
+	case state == event.StateSkipped:
+		return "#9e9e9e"
+	default:
+		return "#1e88e5"
+	}
+}
+
+// writeTimelineSVG writes tl as a Gantt chart: a row per step with the step
+// span as a pale bar, attempts coloured by outcome on top, contract
+// validation as a band along the bottom and retries as ticks.
+func writeTimelineSVG(w io.Writer, tl *RunTimeline) {
+	total := tl.End.Sub(tl.Start)
+	chartW := float64(ganttWidth - ganttLabelW - ganttPadR)
+	x := func(t time.Time) float64 {
+		if total <= 0 {
+			return ganttLabelW
+		}
+		return ganttLabelW + float64(t.Sub(tl.Start))/float64(total)*chartW
+	}
+	width := func(start, end time.Time) float64 {
+		if wpx := x(end) - x(start); wpx >= 1 {
+			return wpx
+		}
+		return 1
+	}
+	height := ganttTop + (len(tl.Steps)+1)*ganttRowH + 40
+	rows := make(map[string]int, len(tl.Steps))
+	for i, id := range tl.Steps {
+		rows[id] = i + 1
+	}
+	rowY := func(row int) int { return ganttTop + row*ganttRowH }
+
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="12">`+"\n", ganttWidth, height)
+	fmt.Fprintf(w, `<rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n")
+	fmt.Fprintf(w, `<text x="10" y="20" font-size="14" font-weight="bold">%s · %s · %s · %s</text>`+"\n",
+		html.EscapeString(tl.Pipeline), html.EscapeString(tl.RunID), html.EscapeString(tl.Status), formatDuration(total))
+
+	tick := ganttTicks[len(ganttTicks)-1]
+	for _, d := range ganttTicks {
+		if total/d <= 10 {
+			tick = d
+			break
+		}
+	}
+	bottom := rowY(len(tl.Steps) + 1)
+	for t := time.Duration(0); t <= total; t += tick {
+		tx := x(tl.Start.Add(t))
+		fmt.Fprintf(w, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="#e0e0e0"/>`+"\n", tx, ganttTop-6, tx, bottom)
+		fmt.Fprintf(w, `<text x="%.1f" y="%d" text-anchor="middle" fill="#616161">%s</text>`+"\n", tx, ganttTop-10, formatDuration(t))
+	}
+
+	fmt.Fprintf(w, `<text x="10" y="%d" font-weight="bold">run</text>`+"\n", rowY(0)+ganttRowH/2+4)
+	fmt.Fprintf(w, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s" opacity="0.35"><title>%s: %s</title></rect>`+"\n",
+		x(tl.Start), rowY(0)+(ganttRowH-ganttBarH)/2, width(tl.Start, tl.End), ganttBarH, ganttColor(spanStep, tl.Status), html.EscapeString(tl.Pipeline), formatDuration(total))
+
+	for _, s := range tl.Spans {
+		y := rowY(rows[s.Step])
+		barY := y + (ganttRowH-ganttBarH)/2
+		tip := html.EscapeString(fmt.Sprintf("%s %s: %s, %s", s.Step, s.Name, s.State, formatDuration(s.End.Sub(s.Start))))
+		switch s.Kind {
+		case spanStep:
+			fmt.Fprintf(w, `<text x="10" y="%d">%s <tspan fill="#757575">%s</tspan></text>`+"\n", y+ganttRowH/2+4, html.EscapeString(s.Step), formatDuration(s.End.Sub(s.Start)))
+			fmt.Fprintf(w, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s" opacity="0.25"><title>%s</title></rect>`+"\n",
+				x(s.Start), barY, width(s.Start, s.End), ganttBarH, ganttColor(s.Kind, s.State), tip)
+		case spanAttempt:
+			fmt.Fprintf(w, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s" stroke="#ffffff"><title>%s</title></rect>`+"\n",
+				x(s.Start), barY, width(s.Start, s.End), ganttBarH, ganttColor(s.Kind, s.State), tip)
+		case spanContract:
+			fmt.Fprintf(w, `<rect x="%.1f" y="%d" width="%.1f" height="5" fill="%s"><title>%s</title></rect>`+"\n",
+				x(s.Start), barY+ganttBarH-5, width(s.Start, s.End), ganttColor(s.Kind, s.State), tip)
+		case spanRetry:
+			fmt.Fprintf(w, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="%s" stroke-width="2"><title>%s</title></line>`+"\n",
+				x(s.Start), y+2, x(s.Start), y+ganttRowH-2, ganttColor(s.Kind, s.State), tip)
+		}
+	}
+
+	legend := []struct{ label, color string }{
+		{"succeeded", ganttColor(spanAttempt, "succeeded")},
+		{"failed", ganttColor(spanAttempt, event.StateFailed)},
+		{"running", ganttColor(spanAttempt, event.StateRunning)},
+		{"contract validation", ganttColor(spanContract, "")},
+		{"retry", ganttColor(spanRetry, "")},
+	}
+	lx := 10
+	for _, l := range legend {
+		fmt.Fprintf(w, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/><text x="%d" y="%d">%s</text>`+"\n", lx, bottom+14, l.color, lx+16, bottom+24, l.label)
+		lx += 28 + 7*len(l.label)
+	}
+	fmt.Fprintln(w, "</svg>")
+}
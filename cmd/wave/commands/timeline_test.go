@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildRunTimeline(t *testing.T) {
+	t0 := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time { return t0.Add(time.Duration(sec) * time.Second) }
+	done := at(100)
+	run := &state.RunRecord{RunID: "run-1", PipelineName: "impl", Status: "completed", StartedAt: t0, CompletedAt: &done}
+	events := []state.LogRecord{
+		{Timestamp: at(0), StepID: "plan", State: "running"},
+		{Timestamp: at(10), StepID: "plan", State: "completed"},
+		{Timestamp: at(10), StepID: "implement", State: "running"},
+		{Timestamp: at(11), StepID: "lint", State: "running"},
+		{Timestamp: at(20), StepID: "lint", State: "completed"},
+		{Timestamp: at(40), StepID: "implement", State: "retrying", Message: "attempt 2/3"},
+		{Timestamp: at(80), StepID: "implement", State: "validating"},
+		{Timestamp: at(95), StepID: "implement", State: "contract_passed"},
+		{Timestamp: at(95), StepID: "implement", State: "completed"},
+	}
+	a1End, a2End := at(40), at(90)
+	attempts := []state.StepAttemptRecord{
+		{StepID: "implement", Attempt: 1, State: "running", StartedAt: at(10)},
+		{StepID: "implement", Attempt: 1, State: "failed", StartedAt: at(10), CompletedAt: &a1End, DurationMs: 30_000},
+		{StepID: "implement", Attempt: 2, State: "running", StartedAt: at(41)},
+		{StepID: "implement", Attempt: 2, State: "succeeded", StartedAt: at(41), CompletedAt: &a2End, DurationMs: 49_000},
+	}
+
+	tl := buildRunTimeline(run, events, attempts)
+	assert.Equal(t, []string{"plan", "implement", "lint"}, tl.Steps)
+	assert.Equal(t, t0, tl.Start)
+	assert.Equal(t, done, tl.End)
+
+	byKind := map[string][]TimelineSpan{}
+	for _, s := range tl.Spans {
+		if s.Step == "implement" {
+			byKind[s.Kind] = append(byKind[s.Kind], s)
+		}
+	}
+	require.Len(t, byKind[spanStep], 1)
+	assert.Equal(t, at(10), byKind[spanStep][0].Start)
+	assert.Equal(t, at(95), byKind[spanStep][0].End)
+	assert.Equal(t, "completed", byKind[spanStep][0].State)
+
+	require.Len(t, byKind[spanAttempt], 2, "one span per attempt, not per record")
+	assert.Equal(t, "failed", byKind[spanAttempt][0].State)
+	assert.Equal(t, at(40), byKind[spanAttempt][0].End)
+	assert.Equal(t, at(90), byKind[spanAttempt][1].End)
+
+	require.Len(t, byKind[spanContract], 1)
+	assert.Equal(t, at(80), byKind[spanContract][0].Start)
+	assert.Equal(t, at(90), byKind[spanContract][0].End, "clipped to the attempt it started in")
+	assert.Equal(t, "passed", byKind[spanContract][0].State)
+
+	require.Len(t, byKind[spanRetry], 1)
+	assert.Equal(t, at(40), byKind[spanRetry][0].Start)
+
+	var trace struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}
+	var buf bytes.Buffer
+	require.NoError(t, writeTimelineTrace(&buf, tl))
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &trace))
+	var sawStep, sawRetry bool
+	for _, ev := range trace.TraceEvents {
+		if ev.Name == "implement" && ev.Ph == "X" {
+			sawStep = true
+			assert.Equal(t, int64(10_000_000), ev.Ts)
+			assert.Equal(t, int64(85_000_000), *ev.Dur)
+			assert.Equal(t, 2, ev.Tid)
+		}
+		if ev.Cat == spanRetry {
+			sawRetry = true
+			assert.Equal(t, "i", ev.Ph)
+		}
+	}
+	assert.True(t, sawStep)
+	assert.True(t, sawRetry)
+
+	buf.Reset()
+	writeTimelineSVG(&buf, tl)
+	assert.Contains(t, buf.String(), "<svg")
+	assert.Contains(t, buf.String(), "implement attempt 1: failed")
+}
+
+func TestRunTimeline(t *testing.T) {
+	orig, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(orig) })
+	require.NoError(t, os.MkdirAll(".agents", 0o755))
+
+	store, err := state.NewStateStore(".agents/state.db")
+	require.NoError(t, err)
+	runID, err := store.CreateRun("impl-issue", "")
+	require.NoError(t, err)
+	require.NoError(t, store.LogEvent(runID, "plan", "running", "navigator", "", 0, 0, "", "", ""))
+	require.NoError(t, store.RecordStepAttempt(&state.StepAttemptRecord{RunID: runID, StepID: "plan", Attempt: 1, State: "succeeded", StartedAt: time.Now(), DurationMs: 1500}))
+	require.NoError(t, store.LogEvent(runID, "plan", "completed", "navigator", "", 0, 0, "", "", ""))
+	store.Close()
+
+	out := filepath.Join(t.TempDir(), "run.svg")
+	captureOutput(t, func() {
+		require.NoError(t, runTimeline(runID, TimelineOptions{Format: "svg", To: out}))
+	})
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "plan attempt 1: succeeded")
+
+	assert.Error(t, runTimeline(runID, TimelineOptions{Format: "png"}))
+	assert.Error(t, runTimeline("missing-run", TimelineOptions{Format: "trace"}))
+}
@@ -0,0 +1,236 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// TriageOptions holds options for the triage command.
+type TriageOptions struct {
+	Since    string
+	Pipeline string
+	Format   string
+}
+
+// TriageReport is the aggregated failure summary emitted by `wave triage`.
+type TriageReport struct {
+	Since      string             `json:"since,omitempty"`
+	Total      int                `json:"total"`
+	Categories []TriageCount      `json:"categories"`
+	Steps      []TriageStepReport `json:"steps"`
+}
+
+// TriageCount is a failure count for a single category.
+type TriageCount struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// TriageStepReport summarizes failures for one pipeline/step pair.
+type TriageStepReport struct {
+	Pipeline   string        `json:"pipeline"`
+	Step       string        `json:"step"`
+	Total      int           `json:"total"`
+	Categories []TriageCount `json:"categories"`
+	LastError  string        `json:"last_error,omitempty"`
+	LastFailed string        `json:"last_failed,omitempty"`
+}
+
+// NewTriageCmd creates the triage command.
+func NewTriageCmd() *cobra.Command {
+	var opts TriageOptions
+
+	cmd := &cobra.Command{
+		Use:   "triage",
+		Short: "Summarize step failures by category",
+		Long: `Summarize terminal step failures by failure category.
+
+Every step that exhausts its retries is classified into a category
+(rate_limit, contract_violation, timeout, adapter_crash, sandbox_denial,
+missing_artifact, context_exhausted, step_limit, canceled, unknown). The
+triage report aggregates those categories per pipeline and step so
+reliability work can target the most frequent root causes first.`,
+		Example: `  wave triage                         # Failures from the last 7 days
+  wave triage --since 24h             # Failures from the last day
+  wave triage --pipeline impl-issue   # Restrict to one pipeline
+  wave triage --format json           # Output as JSON`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Format = ResolveFormat(cmd, opts.Format)
+			return runTriage(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Since, "since", "7d", "Only include failures newer than this duration (e.g. 7d, 24h); empty for all history")
+	cmd.Flags().StringVar(&opts.Pipeline, "pipeline", "", "Filter by pipeline name")
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "Output format (text, json)")
+
+	return cmd
+}
+
+func runTriage(opts TriageOptions) error {
+	dbPath := ".agents/state.db"
+
+	var since time.Time
+	if opts.Since != "" {
+		d, err := parseSinceDuration(opts.Since)
+		if err != nil {
+			return NewCLIError(CodeInvalidArgs, "invalid --since value: "+err.Error(),
+				"Use a duration like '7d', '24h', or '30m'.").WithCause(err)
+		}
+		since = time.Now().Add(-d)
+	}
+
+	var records []state.StepFailureRecord
+	if _, err := os.Stat(dbPath); err == nil {
+		store, err := state.NewReadOnlyStateStore(dbPath)
+		if err != nil {
+			return NewCLIError(CodeStateDBError, fmt.Sprintf("failed to open state database: %s", err), "Check .agents/state.db file permissions or run 'wave run' to create it").WithCause(err)
+		}
+		defer store.Close()
+
+		records, err = store.ListStepFailures(since)
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to query step failures: %s", err), "The state database may need migration -- try 'wave migrate up'").WithCause(err)
+		}
+	}
+
+	if opts.Pipeline != "" {
+		filtered := records[:0]
+		for _, r := range records {
+			if r.PipelineName == opts.Pipeline {
+				filtered = append(filtered, r)
+			}
+		}
+		records = filtered
+	}
+
+	report := buildTriageReport(records)
+	report.Since = opts.Since
+
+	if opts.Format == "json" {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to marshal JSON: %s", err), "This is an internal serialization error").WithCause(err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	printTriageReport(report)
+	return nil
+}
+
+// buildTriageReport aggregates failure records into per-category and
+// per-pipeline/step counts. Records are expected newest first (as returned
+// by ListStepFailures) so the first record seen for a step is its latest.
+func buildTriageReport(records []state.StepFailureRecord) TriageReport {
+	report := TriageReport{
+		Total:      len(records),
+		Categories: []TriageCount{},
+		Steps:      []TriageStepReport{},
+	}
+
+	totals := make(map[string]int)
+	type stepKey struct{ pipeline, step string }
+	stepCounts := make(map[stepKey]map[string]int)
+	stepIndex := make(map[stepKey]int)
+
+	for _, r := range records {
+		totals[r.FailureCategory]++
+
+		key := stepKey{r.PipelineName, r.StepID}
+		idx, ok := stepIndex[key]
+		if !ok {
+			idx = len(report.Steps)
+			stepIndex[key] = idx
+			stepCounts[key] = make(map[string]int)
+			report.Steps = append(report.Steps, TriageStepReport{
+				Pipeline:   r.PipelineName,
+				Step:       r.StepID,
+				LastError:  r.ErrorMessage,
+				LastFailed: r.FailedAt.Format(time.RFC3339),
+			})
+		}
+		report.Steps[idx].Total++
+		stepCounts[key][r.FailureCategory]++
+	}
+
+	report.Categories = sortedTriageCounts(totals)
+	for i := range report.Steps {
+		key := stepKey{report.Steps[i].Pipeline, report.Steps[i].Step}
+		report.Steps[i].Categories = sortedTriageCounts(stepCounts[key])
+	}
+	sort.SliceStable(report.Steps, func(i, j int) bool {
+		if report.Steps[i].Total != report.Steps[j].Total {
+			return report.Steps[i].Total > report.Steps[j].Total
+		}
+		if report.Steps[i].Pipeline != report.Steps[j].Pipeline {
+			return report.Steps[i].Pipeline < report.Steps[j].Pipeline
+		}
+		return report.Steps[i].Step < report.Steps[j].Step
+	})
+
+	return report
+}
+
+// sortedTriageCounts flattens a category→count map ordered by descending
+// count, then category name for stable output.
+func sortedTriageCounts(counts map[string]int) []TriageCount {
+	out := make([]TriageCount, 0, len(counts))
+	for category, count := range counts {
+		out = append(out, TriageCount{Category: category, Count: count})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Category < out[j].Category
+	})
+	return out
+}
+
+func printTriageReport(report TriageReport) {
+	if report.Total == 0 {
+		if report.Since != "" {
+			fmt.Printf("No step failures in the last %s\n", report.Since)
+		} else {
+			fmt.Println("No step failures recorded")
+		}
+		return
+	}
+
+	if report.Since != "" {
+		fmt.Printf("Step failures in the last %s: %d\n\n", report.Since, report.Total)
+	} else {
+		fmt.Printf("Step failures: %d\n\n", report.Total)
+	}
+
+	fmt.Println("By category:")
+	for _, c := range report.Categories {
+		pct := float64(c.Count) / float64(report.Total) * 100
+		fmt.Printf("  %-20s %5d (%5.1f%%)\n", c.Category, c.Count, pct)
+	}
+
+	fmt.Println("\nBy pipeline/step:")
+	fmt.Printf("  %-25s %-20s %5s  %s\n", "PIPELINE", "STEP", "COUNT", "CATEGORIES")
+	fmt.Println("  " + strings.Repeat("-", 80))
+	for _, s := range report.Steps {
+		parts := make([]string, 0, len(s.Categories))
+		for _, c := range s.Categories {
+			parts = append(parts, fmt.Sprintf("%s=%d", c.Category, c.Count))
+		}
+		pipeline := s.Pipeline
+		if pipeline == "" {
+			pipeline = "-"
+		}
+		fmt.Printf("  %-25s %-20s %5d  %s\n", truncate(pipeline, 25), truncate(s.Step, 20), s.Total, strings.Join(parts, ", "))
+	}
+}
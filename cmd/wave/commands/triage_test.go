@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTriageReport(t *testing.T) {
+	now := time.Now()
+	records := []state.StepFailureRecord{
+		{RunID: "r3", PipelineName: "impl-issue", StepID: "implement", FailureCategory: "timeout", ErrorMessage: "latest", FailedAt: now},
+		{RunID: "r2", PipelineName: "impl-issue", StepID: "implement", FailureCategory: "contract_violation", FailedAt: now.Add(-time.Hour)},
+		{RunID: "r1", PipelineName: "impl-issue", StepID: "implement", FailureCategory: "timeout", FailedAt: now.Add(-2 * time.Hour)},
+		{RunID: "r0", PipelineName: "audit-security", StepID: "scan", FailureCategory: "rate_limit", FailedAt: now.Add(-3 * time.Hour)},
+	}
+
+	report := buildTriageReport(records)
+
+	assert.Equal(t, 4, report.Total)
+	require.Len(t, report.Categories, 3)
+	assert.Equal(t, TriageCount{Category: "timeout", Count: 2}, report.Categories[0])
+	assert.Equal(t, TriageCount{Category: "contract_violation", Count: 1}, report.Categories[1])
+	assert.Equal(t, TriageCount{Category: "rate_limit", Count: 1}, report.Categories[2])
+
+	require.Len(t, report.Steps, 2)
+	assert.Equal(t, "impl-issue", report.Steps[0].Pipeline)
+	assert.Equal(t, "implement", report.Steps[0].Step)
+	assert.Equal(t, 3, report.Steps[0].Total)
+	assert.Equal(t, "latest", report.Steps[0].LastError)
+	assert.Equal(t, "audit-security", report.Steps[1].Pipeline)
+	assert.Equal(t, 1, report.Steps[1].Total)
+}
+
+func TestBuildTriageReport_Empty(t *testing.T) {
+	report := buildTriageReport(nil)
+	assert.Zero(t, report.Total)
+	assert.NotNil(t, report.Categories)
+	assert.NotNil(t, report.Steps)
+}
@@ -19,6 +19,7 @@ type ValidateOptions struct {
 	All             bool
 	Verbose         bool
 	PromptToolsWarn bool // Downgrade prompt/tool permission mismatches to warnings.
+	Fix             bool // Apply safe fixes before validating.
 }
 
 func NewValidateCmd() *cobra.Command {
@@ -28,7 +29,12 @@ func NewValidateCmd() *cobra.Command {
 		Use:   "validate",
 		Short: "Validate Wave configuration",
 		Long: `Validate the wave.yaml manifest and project structure.
-Checks manifest syntax, references, and system dependencies.`,
+Checks manifest syntax, references, and system dependencies.
+
+With --fix, safe fixes are applied first and summarised per file: missing
+dependency edges for artifact references, normalised output artifact paths,
+artifact types inferred from file extensions, and deprecated manifest fields
+migrated to their replacements.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.Verbose, _ = cmd.Root().PersistentFlags().GetBool("verbose")
 			return runValidate(opts)
@@ -40,6 +46,7 @@ Checks manifest syntax, references, and system dependencies.`,
 	cmd.Flags().BoolVar(&opts.All, "all", false, "Validate all pipelines in .agents/pipelines/")
 	cmd.Flags().BoolVar(&opts.PromptToolsWarn, "prompt-tools-warn", false,
 		"Downgrade prompt/tool permission mismatches to warnings (honours WAVE_PROMPT_TOOLS_WARN env)")
+	cmd.Flags().BoolVar(&opts.Fix, "fix", false, "Apply safe fixes to the manifest and pipelines before validating")
 
 	return cmd
 }
@@ -49,6 +56,12 @@ func runValidate(opts ValidateOptions) error {
 		fmt.Printf("Validating manifest: %s\n", opts.ManifestPath)
 	}
 
+	if opts.Fix {
+		if err := runValidateFix(opts); err != nil {
+			return err
+		}
+	}
+
 	mp, err := loadManifestStrict(opts.ManifestPath)
 	if err != nil {
 		return err
@@ -132,7 +145,10 @@ func runValidate(opts ValidateOptions) error {
 				continue
 			}
 			name := strings.TrimSuffix(entry.Name(), ".yaml")
-			structErrs, findings := validatePipelineWithPromptTools(name, &m, forgeInfo)
+			structErrs, findings, warnings := validatePipelineWithPromptTools(name, &m, forgeInfo)
+			for _, w := range warnings {
+				fmt.Printf("⚠ Warning: %s: %s\n", name, w)
+			}
 			if len(structErrs) > 0 {
 				for _, e := range structErrs {
 					allErrs = append(allErrs, fmt.Sprintf("%s: %s", name, e))
@@ -165,7 +181,10 @@ func runValidate(opts ValidateOptions) error {
 			return NewCLIError(CodeValidationFailed, fmt.Sprintf("%d pipeline issue(s) found", len(allErrs)), "Fix the issues listed above and re-run 'wave validate --all'")
 		}
 	} else if opts.Pipeline != "" {
-		structErrs, findings := validatePipelineWithPromptTools(opts.Pipeline, &m, forgeInfo)
+		structErrs, findings, warnings := validatePipelineWithPromptTools(opts.Pipeline, &m, forgeInfo)
+		for _, w := range warnings {
+			fmt.Printf("⚠ Warning: %s\n", w)
+		}
 		if len(findings) > 0 {
 			label := "✗ Pipeline '%s' prompt/tool mismatches:\n"
 			if warnPromptTools {
@@ -199,24 +218,27 @@ func runValidate(opts ValidateOptions) error {
 
 // validatePipelineWithPromptTools runs both the structural pipeline validator
 // and the prompt/tool permission check in a single pipeline file read. It
-// returns the structural error list (which is fatal) and the prompt/tool
-// findings (which the caller renders separately, optionally as warnings).
-func validatePipelineWithPromptTools(pipelineName string, m *manifest.Manifest, fi forge.ForgeInfo) ([]string, []promptToolFinding) {
+// returns the structural error list (which is fatal), the prompt/tool
+// findings (which the caller renders separately, optionally as warnings)
+// and non-fatal DAG warnings such as unconsumed output artifacts.
+func validatePipelineWithPromptTools(pipelineName string, m *manifest.Manifest, fi forge.ForgeInfo) ([]string, []promptToolFinding, []string) {
 	structErrs := validatePipelineFull(pipelineName, m, fi)
 	pipelinePath := filepath.Join(".agents", "pipelines", pipelineName+".yaml")
 	pipelineData, err := os.ReadFile(pipelinePath)
 	if err != nil {
 		// Structural validator already reports the read failure; nothing to scan.
-		return structErrs, nil
+		return structErrs, nil, nil
 	}
 	loader := &pipeline.YAMLPipelineLoader{}
 	pParsed, err := loader.Unmarshal(pipelineData)
 	if err != nil {
 		// Same — YAML errors surfaced by the structural pass.
-		return structErrs, nil
+		return structErrs, nil, nil
 	}
 	findings := validatePromptToolPermissions(pipelineName, pParsed, m)
-	return structErrs, findings
+	dag := &pipeline.DAGValidator{}
+	_ = dag.Validate(pParsed) // errors are reported by the structural pass
+	return structErrs, findings, dag.Warnings
 }
 
 func validateManifestStructure(m *manifest.Manifest) []string {
@@ -289,9 +311,10 @@ func validateAdapterBinaries(m *manifest.Manifest, verbose bool) []string {
 }
 
 // isCompositionStep returns true if the step is an orchestration primitive
-// (sub-pipeline, branch, gate, loop) that does not require a persona.
+// (sub-pipeline, branch, gate, loop) or built-in step type that does not
+// require a persona.
 func isCompositionStep(step pipeline.Step) bool {
-	if step.Type == pipeline.StepTypeCommand || step.Type == pipeline.StepTypeConditional {
+	if step.Type == pipeline.StepTypeCommand || step.Type == pipeline.StepTypeConditional || step.Type == pipeline.StepTypeTestImpact {
 		return true
 	}
 	return step.SubPipeline != "" || step.Branch != nil || step.Gate != nil || step.Loop != nil || step.Aggregate != nil
@@ -339,6 +362,12 @@ func validatePipelineFull(pipelineName string, m *manifest.Manifest, fi forge.Fo
 
 	var errs []string
 
+	// Structural checks the executor would otherwise hit mid-run: cycles,
+	// unreachable steps and artifact reads from non-dependencies.
+	if err := (&pipeline.DAGValidator{}).Validate(&p); err != nil {
+		errs = append(errs, err.Error())
+	}
+
 	// First pass: collect all step IDs and check for duplicates.
 	// This must happen before dependency validation so that YAML ordering
 	// does not produce false positives (the executor topologically sorts at runtime).
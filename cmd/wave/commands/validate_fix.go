@@ -0,0 +1,282 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/recinq/wave/internal/pipeline"
+	"gopkg.in/yaml.v3"
+)
+
+// fixChange is one edit applied by 'wave validate --fix', printed as a line
+// of the summary diff.
+type fixChange struct {
+	Op   string // "+" added, "-" removed, "~" changed
+	Key  string // e.g. steps[build].dependencies
+	Old  string
+	New  string
+	Note string
+}
+
+func (c fixChange) String() string {
+	var s string
+	switch c.Op {
+	case "-":
+		s = fmt.Sprintf("- %s: %s", c.Key, c.Old)
+	case "~":
+		s = fmt.Sprintf("~ %s: %s → %s", c.Key, c.Old, c.New)
+	default:
+		s = fmt.Sprintf("+ %s: %s", c.Key, c.New)
+	}
+	if c.Note != "" {
+		s += "  (" + c.Note + ")"
+	}
+	return s
+}
+
+// artifactTypeByExt maps output artifact file extensions to the artifact
+// type --fix fills in when none is declared.
+var artifactTypeByExt = map[string]string{
+	".json":     "json",
+	".md":       "markdown",
+	".markdown": "markdown",
+	".txt":      "text",
+	".log":      "text",
+}
+
+// runValidateFix applies the safe fixes to the manifest and the pipelines
+// about to be validated, rewriting only files that change, and prints what
+// changed per file.
+func runValidateFix(opts ValidateOptions) error {
+	targets := []string{opts.ManifestPath}
+	if opts.Pipeline != "" {
+		targets = append(targets, filepath.Join(".agents", "pipelines", strings.TrimSuffix(opts.Pipeline, ".yaml")+".yaml"))
+	} else {
+		pipelineDir := filepath.Join(filepath.Dir(opts.ManifestPath), ".agents", "pipelines")
+		entries, _ := os.ReadDir(pipelineDir)
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".yaml") {
+				targets = append(targets, filepath.Join(pipelineDir, entry.Name()))
+			}
+		}
+	}
+
+	fixed := 0
+	for i, target := range targets {
+		fix := fixPipelineFile
+		if i == 0 {
+			fix = fixManifestFile
+		}
+		changes, err := fix(target)
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to fix %s: %s", target, err), "Fix the file by hand and re-run 'wave validate'").WithCause(err)
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		fixed++
+		fmt.Printf("Fixed %s:\n", target)
+		for _, c := range changes {
+			fmt.Printf("  %s\n", c)
+		}
+	}
+	if fixed == 0 {
+		fmt.Printf("✓ Nothing to fix\n")
+	}
+	return nil
+}
+
+// fixManifestFile migrates deprecated manifest fields in place.
+func fixManifestFile(manifestPath string) ([]fixChange, error) {
+	return rewriteYAMLFile(manifestPath, false, func(root *yaml.Node, _ []byte) []fixChange {
+		return migrateDeprecatedManifestFields(root)
+	})
+}
+
+// migrateDeprecatedManifestFields moves runtime.default_timeout_minutes to
+// runtime.timeouts.step_default_minutes. The legacy field wins at runtime,
+// so its value replaces any existing step_default_minutes.
+func migrateDeprecatedManifestFields(root *yaml.Node) []fixChange {
+	runtime := yamlMapValue(root, "runtime")
+	legacy := yamlMapValue(runtime, "default_timeout_minutes")
+	if legacy == nil {
+		return nil
+	}
+	changes := []fixChange{{Op: "-", Key: "runtime.default_timeout_minutes", Old: legacy.Value, Note: "deprecated"}}
+	yamlMapDelete(runtime, "default_timeout_minutes")
+
+	timeouts := yamlMapValue(runtime, "timeouts")
+	if timeouts == nil {
+		timeouts = &yaml.Node{Kind: yaml.MappingNode}
+		yamlMapSet(runtime, "timeouts", timeouts)
+	}
+	if cur := yamlMapValue(timeouts, "step_default_minutes"); cur != nil {
+		if cur.Value != legacy.Value {
+			changes = append(changes, fixChange{Op: "~", Key: "runtime.timeouts.step_default_minutes", Old: cur.Value, New: legacy.Value})
+			cur.Value = legacy.Value
+		}
+		return changes
+	}
+	yamlMapSet(timeouts, "step_default_minutes", &yaml.Node{Kind: yaml.ScalarNode, Tag: legacy.Tag, Value: legacy.Value})
+	return append(changes, fixChange{Op: "+", Key: "runtime.timeouts.step_default_minutes", New: legacy.Value})
+}
+
+// fixPipelineFile adds missing dependency edges and normalises output
+// artifact declarations in a pipeline file.
+func fixPipelineFile(pipelinePath string) ([]fixChange, error) {
+	return rewriteYAMLFile(pipelinePath, false, func(root *yaml.Node, data []byte) []fixChange {
+		var changes []fixChange
+		steps := yamlMapValue(root, "steps")
+		if steps == nil || steps.Kind != yaml.SequenceNode {
+			return nil
+		}
+
+		// Dependency edges come from the parsed pipeline so template and
+		// subset_from references are found the same way validation finds them.
+		var missing map[string][]string
+		if p, err := (&pipeline.YAMLPipelineLoader{}).Unmarshal(data); err == nil {
+			missing = pipeline.MissingDependencies(p)
+		}
+
+		for _, step := range steps.Content {
+			idNode := yamlMapValue(step, "id")
+			if idNode == nil {
+				continue
+			}
+			id := idNode.Value
+			if deps := missing[id]; len(deps) > 0 {
+				list := yamlMapValue(step, "dependencies")
+				if list == nil || list.Kind != yaml.SequenceNode {
+					list = &yaml.Node{Kind: yaml.SequenceNode}
+					yamlMapSet(step, "dependencies", list)
+				}
+				for _, dep := range deps {
+					list.Content = append(list.Content, yamlScalar(dep))
+					changes = append(changes, fixChange{Op: "+", Key: fmt.Sprintf("steps[%s].dependencies", id), New: dep, Note: "artifact is read from this step"})
+				}
+			}
+
+			artifacts := yamlMapValue(step, "output_artifacts")
+			if artifacts == nil || artifacts.Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, art := range artifacts.Content {
+				changes = append(changes, fixOutputArtifact(id, art)...)
+			}
+		}
+		return changes
+	})
+}
+
+// fixOutputArtifact cleans a file artifact's path and fills its type from
+// the file extension when none is declared.
+func fixOutputArtifact(stepID string, art *yaml.Node) []fixChange {
+	pathNode := yamlMapValue(art, "path")
+	if pathNode == nil || pathNode.Value == "" || strings.Contains(pathNode.Value, "{{") {
+		return nil
+	}
+	if src := yamlMapValue(art, "source"); src != nil && src.Value == "stdout" {
+		return nil
+	}
+	name := pathNode.Value
+	if n := yamlMapValue(art, "name"); n != nil {
+		name = n.Value
+	}
+	key := fmt.Sprintf("steps[%s].output_artifacts[%s]", stepID, name)
+
+	var changes []fixChange
+	if clean := path.Clean(filepath.ToSlash(strings.TrimSpace(pathNode.Value))); clean != pathNode.Value {
+		changes = append(changes, fixChange{Op: "~", Key: key + ".path", Old: pathNode.Value, New: clean})
+		pathNode.Value = clean
+	}
+	if t := yamlMapValue(art, "type"); t == nil || t.Value == "" {
+		if typ, ok := artifactTypeByExt[strings.ToLower(path.Ext(pathNode.Value))]; ok {
+			if t != nil {
+				t.Value = typ
+			} else {
+				yamlMapSet(art, "type", yamlScalar(typ))
+			}
+			changes = append(changes, fixChange{Op: "+", Key: key + ".type", New: typ})
+		}
+	}
+	return changes
+}
+
+// rewriteYAMLFile applies fix to the document in path and, unless dryRun,
+// writes it back when fix reports changes. Comments and key order survive
+// the round trip.
+func rewriteYAMLFile(filePath string, dryRun bool, fix func(root *yaml.Node, data []byte) []fixChange) ([]fixChange, error) {
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		// Leave unparsable files to validation, which reports them properly.
+		return nil, nil
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	changes := fix(doc.Content[0], data)
+	if len(changes) == 0 || dryRun {
+		return changes, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filePath, buf.Bytes(), info.Mode().Perm()); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// yamlMapValue returns the value node for key in mapping m, or nil.
+func yamlMapValue(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlMapSet appends key: value to mapping m.
+func yamlMapSet(m *yaml.Node, key string, value *yaml.Node) {
+	m.Content = append(m.Content, yamlScalar(key), value)
+}
+
+// yamlMapDelete removes key from mapping m.
+func yamlMapDelete(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func yamlScalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
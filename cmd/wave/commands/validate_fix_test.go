@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"os"
+	"testing"
+
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRunValidateFix_Pipeline(t *testing.T) {
+	h := newTestHelper(t)
+	h.chdir()
+	defer h.restore()
+
+	h.writeFile("wave.yaml", "apiVersion: v1\nkind: WaveManifest\n")
+	h.writeFile(".agents/pipelines/test.yaml", `kind: WavePipeline
+metadata:
+  name: test
+steps:
+  # produces the plan
+  - id: plan
+    persona: navigator
+    output_artifacts:
+      - name: plan
+        path: ./out//plan.md
+      - name: raw
+        source: stdout
+  - id: build
+    persona: craftsman
+    memory:
+      inject_artifacts:
+        - step: plan
+          artifact: plan
+          as: plan
+`)
+
+	out := captureOutput(t, func() {
+		require.NoError(t, runValidateFix(ValidateOptions{ManifestPath: "wave.yaml"}))
+	})
+	assert.Contains(t, out, "Fixed .agents/pipelines/test.yaml:")
+	assert.Contains(t, out, "+ steps[build].dependencies: plan")
+	assert.Contains(t, out, "~ steps[plan].output_artifacts[plan].path: ./out//plan.md → out/plan.md")
+	assert.Contains(t, out, "+ steps[plan].output_artifacts[plan].type: markdown")
+
+	data, err := os.ReadFile(".agents/pipelines/test.yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# produces the plan", "comments should survive the rewrite")
+	p, err := (&pipeline.YAMLPipelineLoader{}).Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"plan"}, p.Steps[1].Dependencies)
+	assert.Equal(t, "out/plan.md", p.Steps[0].OutputArtifacts[0].Path)
+	assert.Equal(t, "markdown", p.Steps[0].OutputArtifacts[0].Type)
+	assert.Empty(t, p.Steps[0].OutputArtifacts[1].Type, "stdout artifacts are left alone")
+
+	// A second run finds nothing left to fix.
+	out = captureOutput(t, func() {
+		require.NoError(t, runValidateFix(ValidateOptions{ManifestPath: "wave.yaml"}))
+	})
+	assert.Contains(t, out, "Nothing to fix")
+}
+
+func TestRunValidateFix_ManifestTimeout(t *testing.T) {
+	h := newTestHelper(t)
+	h.chdir()
+	defer h.restore()
+
+	h.writeFile("wave.yaml", `apiVersion: v1
+kind: WaveManifest
+runtime:
+  workspace_root: .agents/workspaces
+  default_timeout_minutes: 45
+`)
+
+	out := captureOutput(t, func() {
+		require.NoError(t, runValidateFix(ValidateOptions{ManifestPath: "wave.yaml"}))
+	})
+	assert.Contains(t, out, "- runtime.default_timeout_minutes: 45")
+	assert.Contains(t, out, "+ runtime.timeouts.step_default_minutes: 45")
+
+	data, err := os.ReadFile("wave.yaml")
+	require.NoError(t, err)
+	var m manifest.Manifest
+	require.NoError(t, yaml.Unmarshal(data, &m))
+	assert.Zero(t, m.Runtime.DefaultTimeoutMin)
+	assert.Equal(t, 45, m.Runtime.Timeouts.StepDefaultMin)
+}
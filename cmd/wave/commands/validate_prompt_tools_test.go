@@ -671,7 +671,7 @@ func TestValidatePipelineWithPromptTools_GracefullyHandlesMissingFile(t *testing
 			"navigator": {Adapter: "claude"},
 		},
 	}
-	structErrs, findings := validatePipelineWithPromptTools(
+	structErrs, findings, _ := validatePipelineWithPromptTools(
 		"missing-pipeline", m, forge.ForgeInfo{Type: forge.ForgeGitHub},
 	)
 	assert.NotEmpty(t, structErrs, "structural pass should report missing file")
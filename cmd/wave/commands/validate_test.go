@@ -1018,6 +1018,49 @@ steps:
 		assert.True(t, found, "should report missing persona, got: %v", errs)
 	})
 
+	t.Run("inject from non-dependency", func(t *testing.T) {
+		h := newTestHelper(t)
+		h.chdir()
+		defer h.restore()
+
+		m := &manifest.Manifest{
+			Personas: map[string]manifest.Persona{
+				"navigator": {Adapter: "claude"},
+			},
+		}
+		h.writeFile(".agents/pipelines/test.yaml", `kind: WavePipeline
+metadata:
+  name: test
+steps:
+  - id: plan
+    persona: navigator
+    exec:
+      type: prompt
+      source: "plan"
+    output_artifacts:
+      - name: plan
+        path: plan.md
+  - id: build
+    persona: navigator
+    memory:
+      inject_artifacts:
+        - step: plan
+          artifact: plan
+          as: plan
+    exec:
+      type: prompt
+      source: "build"
+`)
+		errs := validatePipelineFull("test", m, fi)
+		found := false
+		for _, e := range errs {
+			if strings.Contains(e, "not one of its dependencies") {
+				found = true
+			}
+		}
+		assert.True(t, found, "should report missing dependency edge, got: %v", errs)
+	})
+
 	t.Run("composition step without persona is valid", func(t *testing.T) {
 		h := newTestHelper(t)
 		h.chdir()
@@ -0,0 +1,343 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// WorkspaceOptions holds options shared by the workspace subcommands.
+type WorkspaceOptions struct {
+	Format   string
+	Manifest string
+}
+
+// WorkspaceEntry is one entry of a workspace listing.
+type WorkspaceEntry struct {
+	Path    string    `json:"path"`
+	Kind    string    `json:"kind"` // step, worktree, matrix, dir, file
+	Size    int64     `json:"size_bytes"`
+	Files   int       `json:"files,omitempty"`
+	Items   []string  `json:"items,omitempty"` // matrix item subdirectories
+	ModTime time.Time `json:"modified"`
+}
+
+// WorkspaceListOutput is the JSON output of workspace ls.
+type WorkspaceListOutput struct {
+	RunID   string           `json:"run_id"`
+	Root    string           `json:"root"`
+	Entries []WorkspaceEntry `json:"entries"`
+}
+
+// matrixItemDir matches the per-item subdirectories created by matrix
+// (worker_<n>) and concurrency (agent_<n>) steps.
+var matrixItemDir = regexp.MustCompile(`^(worker|agent)_\d+$`)
+
+// NewWorkspaceCmd creates the workspace parent command with subcommands.
+func NewWorkspaceCmd() *cobra.Command {
+	var opts WorkspaceOptions
+
+	cmd := &cobra.Command{
+		Use:   "workspace",
+		Short: "Browse run workspaces",
+		Long: `Browse the files pipeline runs left in their workspaces without knowing the
+<workspace_root>/<run-id>/<step>/ layout.
+
+Subcommands:
+  ls     List a run's step workspaces, or the files of one step
+  cat    Print a file from a step workspace
+
+Matrix and concurrency steps keep one subdirectory per item (worker_<n>,
+agent_<n>). Address an item by appending it to the step, e.g. build/worker_1.
+Worktree workspaces appear as __wt_<branch>.`,
+	}
+
+	cmd.PersistentFlags().StringVar(&opts.Manifest, "manifest", "wave.yaml", "Path to manifest file")
+	cmd.AddCommand(newWorkspaceLsCmd(&opts))
+	cmd.AddCommand(newWorkspaceCatCmd(&opts))
+
+	return cmd
+}
+
+func newWorkspaceLsCmd(opts *WorkspaceOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ls <run-id> [step]",
+		Short: "List a run's workspaces or the files of one step",
+		Example: `  wave workspace ls impl-issue-20260101-120000-a1b2
+  wave workspace ls impl-issue-20260101-120000-a1b2 implement
+  wave workspace ls impl-issue-20260101-120000-a1b2 build/worker_1 --format json`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Format = ResolveFormat(cmd, opts.Format)
+			step := ""
+			if len(args) > 1 {
+				step = args[1]
+			}
+			return runWorkspaceLs(args[0], step, *opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "Output format (text, json)")
+	return cmd
+}
+
+func newWorkspaceCatCmd(opts *WorkspaceOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "cat <run-id> <step> <path>",
+		Short: "Print a file from a step workspace",
+		Example: `  wave workspace cat impl-issue-20260101-120000-a1b2 plan .agents/output/plan.json
+  wave workspace cat impl-issue-20260101-120000-a1b2 build/worker_1 result.md`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWorkspaceCat(args[0], args[1], args[2], *opts, os.Stdout)
+		},
+	}
+}
+
+func runWorkspaceLs(runID, step string, opts WorkspaceOptions) error {
+	runDir, err := resolveRunWorkspace(workspaceRootFor(opts.Manifest), runID)
+	if err != nil {
+		return err
+	}
+	dir := runDir
+	if step != "" {
+		if dir, err = workspaceSubpath(runDir, step); err != nil {
+			return err
+		}
+	}
+
+	var entries []WorkspaceEntry
+	if step == "" {
+		entries, err = listRunWorkspace(runDir)
+	} else {
+		entries, err = listStepWorkspace(dir)
+	}
+	if err != nil {
+		return NewCLIError(CodeInternalError, fmt.Sprintf("failed to read workspace: %s", err), "Check the workspace directory permissions").WithCause(err)
+	}
+
+	if opts.Format == "json" {
+		out := WorkspaceListOutput{RunID: filepath.Base(runDir), Root: dir, Entries: entries}
+		if out.Entries == nil {
+			out.Entries = []WorkspaceEntry{}
+		}
+		jsonBytes, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return NewCLIError(CodeInternalError, fmt.Sprintf("failed to marshal JSON: %s", err), "This is an internal serialization error").WithCause(err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	fmt.Printf("Workspace: %s\n\n", dir)
+	if len(entries) == 0 {
+		fmt.Println("(empty)")
+		return nil
+	}
+	for _, e := range entries {
+		detail := formatSize(e.Size)
+		switch e.Kind {
+		case "step", "worktree", "dir":
+			detail = fmt.Sprintf("%d files, %s", e.Files, formatSize(e.Size))
+		case "matrix":
+			detail = fmt.Sprintf("%d items, %d files, %s", len(e.Items), e.Files, formatSize(e.Size))
+		}
+		fmt.Printf("  %-9s %-40s %s\n", e.Kind, e.Path, detail)
+		for _, item := range e.Items {
+			fmt.Printf("  %-9s   %s/%s\n", "", e.Path, item)
+		}
+	}
+	return nil
+}
+
+func runWorkspaceCat(runID, step, path string, opts WorkspaceOptions, w io.Writer) error {
+	runDir, err := resolveRunWorkspace(workspaceRootFor(opts.Manifest), runID)
+	if err != nil {
+		return err
+	}
+	stepDir, err := workspaceSubpath(runDir, step)
+	if err != nil {
+		return err
+	}
+	file, err := workspaceSubpath(stepDir, path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(file)
+	if err == nil && info.IsDir() {
+		return NewCLIError(CodeInvalidArgs,
+			fmt.Sprintf("%s is a directory", path),
+			fmt.Sprintf("Run 'wave workspace ls %s %s' to list its files", runID, step))
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return NewCLIError(CodeInvalidArgs, fmt.Sprintf("cannot read %s: %s", path, err), "Check the path is relative to the step workspace").WithCause(err)
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// workspaceRootFor returns the manifest's workspace root, falling back to the
+// default when the manifest is missing or does not set one.
+func workspaceRootFor(manifestPath string) string {
+	if m, err := loadManifestStrict(manifestPath); err == nil && m.Runtime.WorkspaceRoot != "" {
+		return m.Runtime.WorkspaceRoot
+	}
+	return ".agents/workspaces"
+}
+
+// resolveRunWorkspace returns the workspace directory of runID. An exact
+// directory name wins; otherwise a unique prefix match is accepted.
+func resolveRunWorkspace(wsRoot, runID string) (string, error) {
+	if info, err := os.Stat(filepath.Join(wsRoot, runID)); err == nil && info.IsDir() && filepath.Base(runID) == runID {
+		return filepath.Join(wsRoot, runID), nil
+	}
+	entries, _ := os.ReadDir(wsRoot)
+	var matches []string
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), runID) {
+			matches = append(matches, e.Name())
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return filepath.Join(wsRoot, matches[0]), nil
+	case 0:
+		return "", NewCLIError(CodeRunNotFound,
+			fmt.Sprintf("no workspace for run %s in %s", runID, wsRoot),
+			"The workspace may have been cleaned — run 'wave list runs' to see recent runs")
+	default:
+		return "", NewCLIError(CodeInvalidArgs,
+			fmt.Sprintf("run ID %s is ambiguous: matches %s", runID, strings.Join(matches, ", ")),
+			"Use the full run ID")
+	}
+}
+
+// workspaceSubpath joins rel onto base, rejecting paths that escape base.
+func workspaceSubpath(base, rel string) (string, error) {
+	p := filepath.Join(base, filepath.FromSlash(rel))
+	if r, err := filepath.Rel(base, p); err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return "", NewCLIError(CodeInvalidArgs,
+			fmt.Sprintf("path %s is outside the workspace", rel),
+			"Use a path relative to the run or step workspace")
+	}
+	if _, err := os.Stat(p); err != nil {
+		return "", NewCLIError(CodeInvalidArgs,
+			fmt.Sprintf("%s not found in %s", rel, base),
+			"Run 'wave workspace ls' to list available steps and files").WithCause(err)
+	}
+	return p, nil
+}
+
+// listRunWorkspace lists the top-level directories of a run workspace with
+// their file counts and sizes. Steps holding per-item subdirectories are
+// reported as matrix steps.
+func listRunWorkspace(runDir string) ([]WorkspaceEntry, error) {
+	dirEntries, err := os.ReadDir(runDir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []WorkspaceEntry
+	for _, de := range dirEntries {
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		entry := WorkspaceEntry{Path: de.Name(), Kind: "file", Size: info.Size(), ModTime: info.ModTime()}
+		if de.IsDir() {
+			entry.Kind = "step"
+			switch {
+			case strings.HasPrefix(de.Name(), "__wt_"):
+				entry.Kind = "worktree"
+			case strings.HasPrefix(de.Name(), "."):
+				entry.Kind = "dir"
+			}
+			entry.Files, entry.Size = dirStats(filepath.Join(runDir, de.Name()))
+			if entry.Kind == "step" {
+				entry.Items = matrixItems(filepath.Join(runDir, de.Name()))
+				if len(entry.Items) > 0 {
+					entry.Kind = "matrix"
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// listStepWorkspace lists every file under dir, relative to dir. Git
+// metadata directories are skipped.
+func listStepWorkspace(dir string) ([]WorkspaceEntry, error) {
+	var entries []WorkspaceEntry
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		entries = append(entries, WorkspaceEntry{Path: filepath.ToSlash(rel), Kind: "file", Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	return entries, err
+}
+
+// matrixItems returns the sorted per-item subdirectories of a step workspace.
+func matrixItems(stepDir string) []string {
+	dirEntries, err := os.ReadDir(stepDir)
+	if err != nil {
+		return nil
+	}
+	var items []string
+	for _, de := range dirEntries {
+		if de.IsDir() && matrixItemDir.MatchString(de.Name()) {
+			items = append(items, de.Name())
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if len(items[i]) != len(items[j]) {
+			return len(items[i]) < len(items[j])
+		}
+		return items[i] < items[j]
+	})
+	return items
+}
+
+// dirStats counts the files under dir and their total size, skipping git
+// metadata.
+func dirStats(dir string) (files int, size int64) {
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			files++
+			size += info.Size()
+		}
+		return nil
+	})
+	return files, size
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupWorkspaceTree creates a run workspace with a plain step, a matrix step
+// and a worktree, and a manifest pointing at it.
+func setupWorkspaceTree(t *testing.T) (wsRoot, manifestPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	wsRoot = filepath.Join(dir, "workspaces")
+	files := map[string]string{
+		"impl-issue-20260101-a1b2/plan/.agents/output/plan.json": `{"steps": []}`,
+		"impl-issue-20260101-a1b2/build/worker_0/result.md":      "item 0",
+		"impl-issue-20260101-a1b2/build/worker_1/result.md":      "item 1",
+		"impl-issue-20260101-a1b2/__wt_feat/main.go":             "package main",
+		"impl-issue-20260101-a1b2/__wt_feat/.git":                "gitdir: elsewhere",
+		"impl-issue-20260102-c3d4/plan/notes.txt":                "other run",
+	}
+	for rel, content := range files {
+		p := filepath.Join(wsRoot, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	}
+	manifestPath = filepath.Join(dir, "wave.yaml")
+	require.NoError(t, os.WriteFile(manifestPath, []byte("runtime:\n  workspace_root: "+wsRoot+"\n"), 0644))
+	return wsRoot, manifestPath
+}
+
+func TestResolveRunWorkspace(t *testing.T) {
+	wsRoot, _ := setupWorkspaceTree(t)
+
+	dir, err := resolveRunWorkspace(wsRoot, "impl-issue-20260101-a1b2")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(wsRoot, "impl-issue-20260101-a1b2"), dir)
+
+	dir, err = resolveRunWorkspace(wsRoot, "impl-issue-20260102")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(wsRoot, "impl-issue-20260102-c3d4"), dir)
+
+	var cliErr *CLIError
+	_, err = resolveRunWorkspace(wsRoot, "impl-issue")
+	require.ErrorAs(t, err, &cliErr)
+	assert.Equal(t, CodeInvalidArgs, cliErr.Code, "ambiguous prefix")
+
+	_, err = resolveRunWorkspace(wsRoot, "missing-run")
+	require.ErrorAs(t, err, &cliErr)
+	assert.Equal(t, CodeRunNotFound, cliErr.Code)
+}
+
+func TestListRunWorkspace(t *testing.T) {
+	wsRoot, _ := setupWorkspaceTree(t)
+
+	entries, err := listRunWorkspace(filepath.Join(wsRoot, "impl-issue-20260101-a1b2"))
+	require.NoError(t, err)
+	kinds := map[string]WorkspaceEntry{}
+	for _, e := range entries {
+		kinds[e.Path] = e
+	}
+	assert.Equal(t, "step", kinds["plan"].Kind)
+	assert.Equal(t, "matrix", kinds["build"].Kind)
+	assert.Equal(t, []string{"worker_0", "worker_1"}, kinds["build"].Items)
+	assert.Equal(t, 2, kinds["build"].Files)
+	assert.Equal(t, "worktree", kinds["__wt_feat"].Kind)
+}
+
+func TestListStepWorkspace(t *testing.T) {
+	wsRoot, _ := setupWorkspaceTree(t)
+	runDir := filepath.Join(wsRoot, "impl-issue-20260101-a1b2")
+
+	entries, err := listStepWorkspace(filepath.Join(runDir, "build"))
+	require.NoError(t, err)
+	var paths []string
+	for _, e := range entries {
+		paths = append(paths, e.Path)
+	}
+	assert.Equal(t, []string{"worker_0/result.md", "worker_1/result.md"}, paths)
+}
+
+func TestRunWorkspaceCat(t *testing.T) {
+	_, manifestPath := setupWorkspaceTree(t)
+	opts := WorkspaceOptions{Manifest: manifestPath}
+
+	var buf bytes.Buffer
+	require.NoError(t, runWorkspaceCat("impl-issue-20260101-a1b2", "build/worker_1", "result.md", opts, &buf))
+	assert.Equal(t, "item 1", buf.String())
+
+	buf.Reset()
+	require.NoError(t, runWorkspaceCat("impl-issue-20260101", "plan", ".agents/output/plan.json", opts, &buf))
+	assert.Equal(t, `{"steps": []}`, buf.String())
+
+	var cliErr *CLIError
+	err := runWorkspaceCat("impl-issue-20260101-a1b2", "plan", "../../impl-issue-20260102-c3d4/plan/notes.txt", opts, &buf)
+	require.ErrorAs(t, err, &cliErr)
+	assert.Contains(t, cliErr.Message, "outside the workspace")
+
+	err = runWorkspaceCat("impl-issue-20260101-a1b2", "build", "worker_0", opts, &buf)
+	require.ErrorAs(t, err, &cliErr)
+	assert.Contains(t, cliErr.Message, "is a directory")
+}
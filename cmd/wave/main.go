@@ -10,6 +10,7 @@ import (
 	"github.com/recinq/wave/cmd/wave/commands"
 	"github.com/recinq/wave/internal/doctor"
 	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/pipeline"
 	"github.com/recinq/wave/internal/state"
 	"github.com/recinq/wave/internal/suggest"
 	"github.com/recinq/wave/internal/tui"
@@ -77,6 +78,9 @@ var rootCmd = &cobra.Command{
 			if err == nil {
 				deps.Store = store
 				defer store.Close()
+				if deps.Manifest != nil {
+					state.SetRunIDFormatter(store, pipeline.RunIDFormatter(deps.Manifest.Runtime))
+				}
 			}
 
 			// Determine pipelines directory (default .agents/pipelines)
@@ -144,11 +148,17 @@ func init() {
 	rootCmd.AddCommand(commands.NewCleanCmd())
 	rootCmd.AddCommand(commands.NewListCmd())
 	rootCmd.AddCommand(commands.NewStatusCmd())
+	rootCmd.AddCommand(commands.NewOpsCmd())
 	rootCmd.AddCommand(commands.NewLogsCmd())
 	rootCmd.AddCommand(commands.NewCancelCmd())
+	rootCmd.AddCommand(commands.NewApproveCmd())
+	rootCmd.AddCommand(commands.NewPinCmd())
 	rootCmd.AddCommand(commands.NewReapCmd())
 	rootCmd.AddCommand(commands.NewArtifactsCmd())
+	rootCmd.AddCommand(commands.NewWorkspaceCmd())
 	rootCmd.AddCommand(commands.NewMigrateCmd())
+	rootCmd.AddCommand(commands.NewMigrateConfigCmd())
+	rootCmd.AddCommand(commands.NewBackupCmd())
 	rootCmd.AddCommand(commands.NewServeCmd())
 	rootCmd.AddCommand(commands.NewReapCmd())
 	rootCmd.AddCommand(commands.NewChatCmd())
@@ -159,10 +169,21 @@ func init() {
 	rootCmd.AddCommand(commands.NewPostmortemCmd())
 	rootCmd.AddCommand(commands.NewAgentCmd())
 	rootCmd.AddCommand(commands.NewBenchCmd())
+	rootCmd.AddCommand(commands.NewEmitCmd())
 	rootCmd.AddCommand(commands.NewForkCmd())
 	rootCmd.AddCommand(commands.NewRewindCmd())
 	rootCmd.AddCommand(commands.NewRetroCmd())
 	rootCmd.AddCommand(commands.NewDecisionsCmd())
+	rootCmd.AddCommand(commands.NewCompareCmd())
+	rootCmd.AddCommand(commands.NewAttestCmd())
+	rootCmd.AddCommand(commands.NewTriageCmd())
+	rootCmd.AddCommand(commands.NewReportCmd())
+	rootCmd.AddCommand(commands.NewStatsCmd())
+	rootCmd.AddCommand(commands.NewTimelineCmd())
+	rootCmd.AddCommand(commands.NewKBCmd())
+	rootCmd.AddCommand(commands.NewCacheCmd())
+	rootCmd.AddCommand(commands.NewSchemasCmd())
+	rootCmd.AddCommand(commands.NewPromptCmd())
 	rootCmd.AddCommand(commands.NewPipelineCmd())
 	rootCmd.AddCommand(commands.NewPersonaCmd())
 	rootCmd.AddCommand(commands.NewCleanupCmd())
@@ -12,6 +12,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/recinq/wave/internal/procutil"
 	"github.com/recinq/wave/internal/sandbox"
 	"github.com/recinq/wave/internal/timeouts"
 )
@@ -25,10 +26,17 @@ type StreamEvent struct {
 	Type      string // "tool_use", "tool_result", "text", "result", "system"
 	ToolName  string // e.g. "Read", "Write", "Bash", "Glob", "Grep"
 	ToolInput string // summary of input (file path, command, pattern)
+	// ToolPaths lists every file path the tool call targets, untruncated,
+	// for checks that must not rely on the ToolInput display summary.
+	ToolPaths []string
 	Content   string // text content or result summary
 	TokensIn  int    // cumulative input tokens
 	TokensOut int    // cumulative output tokens
 	Subtype   string // result event subtype: "success", "error_max_turns", "error_during_execution"
+	// MessageID identifies the model message whose usage TokensIn/TokensOut
+	// report, when the adapter reports usage per message rather than
+	// cumulatively. Events of one message repeat the same usage.
+	MessageID string
 }
 
 // SkillRef holds metadata about a resolved skill for CLAUDE.md injection.
@@ -47,13 +55,23 @@ type AdapterRunConfig struct {
 	Timeout       time.Duration // 0 means no timeout; callers set per use case
 	ProcessGrace  time.Duration // SIGTERM→SIGKILL grace period; 0 uses timeouts.ProcessGrace
 	StdoutDrain   time.Duration // post-cancel stdout drain wait; 0 uses timeouts.StdoutDrain
-	Env           []string
+	Env           []string      // Explicit KEY=VALUE entries (runtime, persona and step env maps)
 	Temperature   float64
 	AllowedTools  []string
 	DenyTools     []string
 	OutputFormat  string
 	Debug         bool
-	Model         string // Model to use; tier names (cheapest, balanced, strongest) or literal IDs (e.g., "claude-opus-4-5-20251101")
+	Model         string   // Model to use; tier names (cheapest, balanced, strongest) or literal IDs (e.g., "claude-opus-4-5-20251101")
+	ExtraArgs     []string // Validated adapter_options flags, appended to the adapter's own arguments
+
+	// TemperatureSet marks Temperature as explicit. Unset, a zero
+	// Temperature means the adapter's default sampling temperature.
+	TemperatureSet bool
+
+	// WarmRetry reports that a failed run is retried in the same
+	// workspace. Only then does an adapter with a warm pool start a
+	// process for the next attempt.
+	WarmRetry bool
 
 	// Sandbox configuration derived from manifest
 	SandboxEnabled bool     // Master switch from runtime.sandbox.enabled
@@ -72,6 +90,11 @@ type AdapterRunConfig struct {
 	// OnStreamEvent is called for each real-time event during Claude Code execution.
 	// If nil, streaming events are silently ignored.
 	OnStreamEvent func(StreamEvent)
+
+	// RawLog receives the adapter process's stdout and stderr exactly as
+	// produced, before any parsing. It must be safe for concurrent writes.
+	// Write errors are ignored. If nil, nothing is captured.
+	RawLog io.Writer
 }
 
 type AdapterResult struct {
@@ -82,8 +105,20 @@ type AdapterResult struct {
 	TokensOut     int // Output tokens (completion)
 	Artifacts     []string
 	ResultContent string // Extracted content from the adapter response
-	FailureReason string // Classification: "timeout", "context_exhaustion", "general_error"
+	FailureReason string // Classification: one of the FailureReason* constants
 	Subtype       string // Result event subtype from Claude Code NDJSON
+	// Transcript is the normalized conversation, for adapters that can
+	// reconstruct one from their output. Nil otherwise.
+	Transcript *Transcript
+	// TokensEstimated is set when the CLI reported no usage and the token
+	// counts were estimated by NormalizeUsage.
+	TokensEstimated bool
+	// CacheHit is set when a CachingRunner replayed a stored result
+	// instead of running the adapter. Token counts are zero.
+	CacheHit bool
+	// ServedBy names the fallback adapter that produced the result when a
+	// FallbackRunner failed over. Empty when the primary served it.
+	ServedBy string
 }
 
 type ProcessGroupRunner struct{}
@@ -101,7 +136,7 @@ func (r *ProcessGroupRunner) Run(ctx context.Context, cfg AdapterRunConfig) (*Ad
 	}
 	defer cancel()
 
-	args := strings.Fields(cfg.Prompt)
+	args := append(append([]string{}, cfg.ExtraArgs...), strings.Fields(cfg.Prompt)...)
 	cmd := exec.CommandContext(ctx, cfg.Adapter, args...)
 	cmd.Dir = cfg.WorkspacePath
 
@@ -118,6 +153,7 @@ func (r *ProcessGroupRunner) Run(ctx context.Context, cfg AdapterRunConfig) (*Ad
 			DockerImage:    cfg.DockerImage,
 			AllowedDomains: cfg.AllowedDomains,
 			EnvPassthrough: cfg.EnvPassthrough,
+			Env:            cfg.Env,
 			WorkspacePath:  cfg.WorkspacePath,
 			Debug:          cfg.Debug,
 		}
@@ -130,10 +166,8 @@ func (r *ProcessGroupRunner) Run(ctx context.Context, cfg AdapterRunConfig) (*Ad
 	mergedEnv := append(os.Environ(), cfg.Env...)
 	cmd.Env = mergedEnv
 
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true,
-		Pgid:    0,
-	}
+	procutil.SetProcessGroup(cmd)
+	cmd.Stderr = rawLog(cfg)
 
 	stdoutPipe, err := cmd.StdoutPipe()
 	if err != nil {
@@ -147,7 +181,7 @@ func (r *ProcessGroupRunner) Run(ctx context.Context, cfg AdapterRunConfig) (*Ad
 	var stdoutBuf bytes.Buffer
 	copyDone := make(chan error, 1)
 	go func() {
-		_, err := io.Copy(&stdoutBuf, stdoutPipe)
+		_, err := io.Copy(&stdoutBuf, teeRawLog(stdoutPipe, cfg))
 		copyDone <- err
 	}()
 
@@ -161,11 +195,13 @@ func (r *ProcessGroupRunner) Run(ctx context.Context, cfg AdapterRunConfig) (*Ad
 			return nil, fmt.Errorf("failed to read stdout: %w", err)
 		}
 		if err := cmd.Wait(); err != nil {
+			exitCode := exitCodeFromError(err)
 			return &AdapterResult{
-				ExitCode:   exitCodeFromError(err),
-				Stdout:     bytes.NewReader(stdoutBuf.Bytes()),
-				TokensUsed: 0,
-				Artifacts:  nil,
+				ExitCode:      exitCode,
+				Stdout:        bytes.NewReader(stdoutBuf.Bytes()),
+				TokensUsed:    0,
+				Artifacts:     nil,
+				FailureReason: classifyExit(exitCode, stdoutBuf.Bytes(), false),
 			}, nil
 		}
 	}
@@ -173,33 +209,56 @@ func (r *ProcessGroupRunner) Run(ctx context.Context, cfg AdapterRunConfig) (*Ad
 	var result AdapterResult
 	result.ExitCode = 0
 	result.Stdout = bytes.NewReader(stdoutBuf.Bytes())
-	result.TokensUsed = estimateTokens(stdoutBuf.String())
-
 	parseArtifacts(stdoutBuf.Bytes(), &result.Artifacts)
+	NormalizeUsage(cfg.Adapter, cfg, stdoutBuf.Bytes(), &result)
 
 	return &result, nil
 }
 
-// killProcessGroup sends SIGTERM to the process group, then SIGKILL after the
-// grace period if the process hasn't exited. It does NOT call process.Wait() —
+// killProcessGroup asks the process group to exit (SIGTERM on Unix), then
+// kills it after the grace period if the process hasn't exited. It does NOT call process.Wait() —
 // callers must call cmd.Wait() themselves to avoid "wait: no child processes"
 // errors from double-waiting.
 func killProcessGroup(process *os.Process, grace time.Duration) {
 	if grace <= 0 {
 		grace = timeouts.ProcessGrace
 	}
-	// Send SIGTERM first for graceful shutdown
-	_ = syscall.Kill(-process.Pid, syscall.SIGTERM)
+	// Ask the group to exit first for graceful shutdown
+	_ = procutil.TerminateGroup(process.Pid)
 
 	// Schedule a forced kill after the grace period. The caller's cmd.Wait()
 	// will reap the process; if it hasn't exited by then, SIGKILL finishes it.
 	go func() {
 		time.Sleep(grace)
-		// SIGKILL is harmless if the process already exited
-		_ = syscall.Kill(-process.Pid, syscall.SIGKILL)
+		// Killing is harmless if the process already exited
+		_ = procutil.KillGroup(process.Pid)
 	}()
 }
 
+// rawLog returns cfg.RawLog wrapped so a failing log (full disk, closed
+// file) can never interrupt the adapter, or nil when capture is off.
+func rawLog(cfg AdapterRunConfig) io.Writer {
+	if cfg.RawLog == nil {
+		return nil
+	}
+	return bestEffortWriter{cfg.RawLog}
+}
+
+// teeRawLog copies everything read from r into the raw log, if any.
+func teeRawLog(r io.Reader, cfg AdapterRunConfig) io.Reader {
+	if w := rawLog(cfg); w != nil {
+		return io.TeeReader(r, w)
+	}
+	return r
+}
+
+type bestEffortWriter struct{ w io.Writer }
+
+func (b bestEffortWriter) Write(p []byte) (int, error) {
+	_, _ = b.w.Write(p)
+	return len(p), nil
+}
+
 func exitCodeFromError(err error) int {
 	if exitErr, ok := err.(*exec.ExitError); ok {
 		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
@@ -213,19 +272,6 @@ func estimateTokens(text string) int {
 	return len(text) / 4
 }
 
-func InstructionFilename(adapterName string) string {
-	switch strings.ToLower(adapterName) {
-	case "claude":
-		return "CLAUDE.md"
-	case "gemini":
-		return "GEMINI.md"
-	case "opencode", "codex":
-		return "AGENTS.md"
-	default:
-		return "AGENTS.md"
-	}
-}
-
 func parseArtifacts(data []byte, artifacts *[]string) {
 	var parsed map[string]interface{}
 	if err := json.Unmarshal(data, &parsed); err != nil {
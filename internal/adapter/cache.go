@@ -0,0 +1,268 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CachedResult is the part of an AdapterResult worth replaying.
+type CachedResult struct {
+	Stdout        []byte
+	ResultContent string
+	Subtype       string
+	TokensIn      int
+	TokensOut     int
+	Persona       string
+	Model         string
+	// Files holds the output files the agent wrote, keyed by
+	// workspace-relative slash path, restored when the result is replayed.
+	Files map[string][]byte
+}
+
+// ResultCache stores adapter results by cache key. Implementations decide
+// expiry; Get reports false for missing or expired entries.
+type ResultCache interface {
+	Get(key string) (*CachedResult, bool)
+	Put(key string, r CachedResult)
+}
+
+// CachingRunner wraps an AdapterRunner and replays the stored result when
+// the same prompt is sent to the same adapter and model against an
+// unchanged workspace. Only temperature-0 runs in a git workspace are
+// cached, and only successful results are stored.
+//
+// A replayed result carries the agent's output but not its side effects:
+// only the files named as outputs with WithFiles are recreated. Callers
+// should enable caching only for steps whose product is their output.
+type CachingRunner struct {
+	inner   AdapterRunner
+	cache   ResultCache
+	inputs  []string
+	outputs []string
+}
+
+// NewCachingRunner creates a CachingRunner wrapping inner.
+func NewCachingRunner(inner AdapterRunner, cache ResultCache) *CachingRunner {
+	return &CachingRunner{inner: inner, cache: cache}
+}
+
+// WithFiles names workspace-relative files or directories the workspace
+// fingerprint may not see, typically because git ignores them. The
+// contents of inputs become part of the cache key; outputs the agent wrote
+// are stored with the result and written back when it is replayed.
+func (c *CachingRunner) WithFiles(inputs, outputs []string) *CachingRunner {
+	c.inputs = inputs
+	c.outputs = outputs
+	return c
+}
+
+// Run returns the cached result for cfg when there is one, and otherwise
+// runs the wrapped adapter and caches its result.
+func (c *CachingRunner) Run(ctx context.Context, cfg AdapterRunConfig) (*AdapterResult, error) {
+	key, ok := CacheKey(cfg)
+	if ok && len(c.inputs) > 0 {
+		key, ok = keyWithFiles(key, cfg.WorkspacePath, c.inputs)
+	}
+	if !ok {
+		return c.inner.Run(ctx, cfg)
+	}
+	// A hit whose files cannot be restored is treated as a miss.
+	if hit, found := c.cache.Get(key); found && restoreFiles(cfg.WorkspacePath, hit.Files) == nil {
+		return &AdapterResult{
+			Stdout:        bytes.NewReader(hit.Stdout),
+			ResultContent: hit.ResultContent,
+			Subtype:       hit.Subtype,
+			CacheHit:      true,
+		}, nil
+	}
+
+	result, err := c.inner.Run(ctx, cfg)
+	if err != nil || result == nil || result.ExitCode != 0 || result.FailureReason != "" {
+		return result, err
+	}
+	var stdout []byte
+	if result.Stdout != nil {
+		stdout, err = io.ReadAll(result.Stdout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read adapter output: %w", err)
+		}
+	}
+	result.Stdout = bytes.NewReader(stdout)
+	c.cache.Put(key, CachedResult{
+		Stdout:        stdout,
+		ResultContent: result.ResultContent,
+		Subtype:       result.Subtype,
+		TokensIn:      result.TokensIn,
+		TokensOut:     result.TokensOut,
+		Persona:       cfg.Persona,
+		Model:         cfg.Model,
+		Files:         collectFiles(cfg.WorkspacePath, c.outputs),
+	})
+	return result, nil
+}
+
+// keyWithFiles extends key with the contents of paths under dir. A path
+// that does not exist contributes its absence. It reports false when a
+// file cannot be read.
+func keyWithFiles(key, dir string, paths []string) (string, bool) {
+	h := sha256.New()
+	h.Write([]byte(key))
+	for _, p := range paths {
+		fmt.Fprintf(h, "%d:%s", len(p), p)
+		err := filepath.WalkDir(filepath.Join(dir, p), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(dir, path)
+			fmt.Fprintf(h, "%d:%s%d:", len(rel), rel, len(data))
+			h.Write(data)
+			return nil
+		})
+		if errors.Is(err, fs.ErrNotExist) {
+			h.Write([]byte("-"))
+			continue
+		}
+		if err != nil {
+			return "", false
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// collectFiles reads the regular files at or below paths under dir, keyed
+// by slash path relative to dir. Missing and unreadable paths are skipped.
+func collectFiles(dir string, paths []string) map[string][]byte {
+	var files map[string][]byte
+	for _, p := range paths {
+		_ = filepath.WalkDir(filepath.Join(dir, p), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return nil
+			}
+			if files == nil {
+				files = make(map[string][]byte)
+			}
+			files[filepath.ToSlash(rel)] = data
+			return nil
+		})
+	}
+	return files
+}
+
+// restoreFiles writes files collected by collectFiles back under dir.
+func restoreFiles(dir string, files map[string][]byte) error {
+	for name, data := range files {
+		rel := filepath.FromSlash(name)
+		if !filepath.IsLocal(rel) {
+			return fmt.Errorf("cached file %q escapes the workspace", name)
+		}
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CacheKey returns the cache key for cfg: a hash of everything that shapes
+// the model's answer, plus a fingerprint of the workspace contents. It
+// reports false when cfg is not cacheable, because the temperature is not
+// an explicit zero or the workspace is not a git checkout. A zero
+// Temperature that was never set samples at the adapter's default.
+func CacheKey(cfg AdapterRunConfig) (string, bool) {
+	if !cfg.TemperatureSet || cfg.Temperature != 0 {
+		return "", false
+	}
+	fingerprint, ok := workspaceFingerprint(cfg.WorkspacePath)
+	if !ok {
+		return "", false
+	}
+	skills := make([]string, len(cfg.ResolvedSkills))
+	for i, s := range cfg.ResolvedSkills {
+		skills[i] = s.Name
+	}
+	h := sha256.New()
+	for _, part := range []string{
+		cfg.Adapter,
+		cfg.Model,
+		cfg.Persona,
+		cfg.SystemPrompt,
+		cfg.Prompt,
+		cfg.OutputFormat,
+		strings.Join(cfg.AllowedTools, "\n"),
+		strings.Join(cfg.DenyTools, "\n"),
+		strings.Join(skills, "\n"),
+		strings.Join(cfg.ExtraArgs, "\n"),
+		fingerprint,
+	} {
+		// Length-prefix each part so adjacent fields cannot run together.
+		fmt.Fprintf(h, "%d:%s", len(part), part)
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// workspaceFingerprint hashes the commit, uncommitted changes and untracked
+// files of the git checkout at dir.
+func workspaceFingerprint(dir string) (string, bool) {
+	if dir == "" {
+		return "", false
+	}
+	git := func(args ...string) ([]byte, error) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		return cmd.Output()
+	}
+	head, err := git("rev-parse", "HEAD")
+	if err != nil {
+		return "", false
+	}
+	diff, err := git("diff", "HEAD", "--binary")
+	if err != nil {
+		return "", false
+	}
+	untracked, err := git("ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return "", false
+	}
+
+	h := sha256.New()
+	h.Write(head)
+	h.Write(diff)
+	for _, name := range bytes.Split(untracked, []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		h.Write(name)
+		data, err := os.ReadFile(filepath.Join(dir, string(name)))
+		if err != nil {
+			return "", false
+		}
+		fmt.Fprintf(h, "%d:", len(data))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
@@ -0,0 +1,152 @@
+package adapter
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapCache is an in-memory ResultCache.
+type mapCache map[string]CachedResult
+
+func (m mapCache) Get(key string) (*CachedResult, bool) {
+	r, ok := m[key]
+	return &r, ok
+}
+
+func (m mapCache) Put(key string, r CachedResult) { m[key] = r }
+
+// initCacheRepo creates a git repository with one committed file.
+func initCacheRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "test"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		require.NoError(t, cmd.Run())
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644))
+	for _, args := range [][]string{{"add", "."}, {"commit", "-q", "-m", "init"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		require.NoError(t, cmd.Run())
+	}
+	return dir
+}
+
+func TestCacheKey(t *testing.T) {
+	dir := initCacheRepo(t)
+	cfg := AdapterRunConfig{Adapter: "claude", Model: "m", Prompt: "analyze", WorkspacePath: dir, TemperatureSet: true}
+
+	key, ok := CacheKey(cfg)
+	require.True(t, ok)
+	again, _ := CacheKey(cfg)
+	assert.Equal(t, key, again)
+
+	other := cfg
+	other.Prompt = "analyze again"
+	otherKey, _ := CacheKey(other)
+	assert.NotEqual(t, key, otherKey, "prompt is part of the key")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main // edited\n"), 0o644))
+	edited, _ := CacheKey(cfg)
+	assert.NotEqual(t, key, edited, "uncommitted edits change the key")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.go"), []byte("package main\n"), 0o644))
+	untracked, _ := CacheKey(cfg)
+	assert.NotEqual(t, edited, untracked, "untracked files change the key")
+
+	warm := cfg
+	warm.Temperature = 0.7
+	_, ok = CacheKey(warm)
+	assert.False(t, ok, "non-zero temperature is not cacheable")
+
+	unset := cfg
+	unset.TemperatureSet = false
+	_, ok = CacheKey(unset)
+	assert.False(t, ok, "a temperature never set samples at the adapter default")
+
+	noGit := cfg
+	noGit.WorkspacePath = t.TempDir()
+	_, ok = CacheKey(noGit)
+	assert.False(t, ok, "a workspace outside git is not cacheable")
+}
+
+func TestCachingRunner(t *testing.T) {
+	dir := initCacheRepo(t)
+	cfg := AdapterRunConfig{Adapter: "claude", Prompt: "analyze", WorkspacePath: dir, TemperatureSet: true}
+	inner := &successRunner{}
+	runner := NewCachingRunner(inner, mapCache{})
+
+	first, err := runner.Run(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.False(t, first.CacheHit)
+
+	second, err := runner.Run(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.True(t, second.CacheHit)
+	assert.Equal(t, "success", second.ResultContent)
+	assert.Equal(t, 1, inner.callCount, "second run is served from the cache")
+
+	failing := &failingRunner{failureReason: FailureReasonRateLimit}
+	runner = NewCachingRunner(failing, mapCache{})
+	for i := 0; i < 2; i++ {
+		res, err := runner.Run(context.Background(), cfg)
+		require.NoError(t, err)
+		assert.False(t, res.CacheHit)
+	}
+	assert.Equal(t, 2, failing.callCount, "failed results are not cached")
+}
+
+// writingRunner writes report.md into the workspace on every run.
+type writingRunner struct{ callCount int }
+
+func (r *writingRunner) Run(_ context.Context, cfg AdapterRunConfig) (*AdapterResult, error) {
+	r.callCount++
+	path := filepath.Join(cfg.WorkspacePath, ".agents", "output", "report.md")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return &AdapterResult{ResultContent: "done"}, os.WriteFile(path, []byte("report"), 0o644)
+}
+
+func TestCachingRunner_Files(t *testing.T) {
+	dir := initCacheRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte(".agents/\n"), 0o644))
+	input := filepath.Join(dir, ".agents", "artifacts", "plan")
+	require.NoError(t, os.MkdirAll(filepath.Dir(input), 0o755))
+	require.NoError(t, os.WriteFile(input, []byte("v1"), 0o644))
+
+	cfg := AdapterRunConfig{Adapter: "claude", Prompt: "report", WorkspacePath: dir, TemperatureSet: true}
+	inner := &writingRunner{}
+	cache := mapCache{}
+	run := func() *AdapterResult {
+		runner := NewCachingRunner(inner, cache).WithFiles([]string{".agents/artifacts/plan"}, []string{".agents/output/report.md"})
+		res, err := runner.Run(context.Background(), cfg)
+		require.NoError(t, err)
+		return res
+	}
+
+	assert.False(t, run().CacheHit)
+	output := filepath.Join(dir, ".agents", "output", "report.md")
+	require.NoError(t, os.Remove(output))
+
+	assert.True(t, run().CacheHit)
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "report", string(data), "output files are replayed")
+	assert.Equal(t, 1, inner.callCount)
+
+	require.NoError(t, os.WriteFile(input, []byte("v2"), 0o644))
+	assert.False(t, run().CacheHit, "a changed injected artifact misses the cache")
+	assert.Equal(t, 2, inner.callCount)
+}
@@ -11,16 +11,19 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/recinq/wave/internal/defaults/embedfs"
 	"github.com/recinq/wave/internal/persona"
+	"github.com/recinq/wave/internal/procutil"
 	"github.com/recinq/wave/internal/timeouts"
 )
 
 type ClaudeAdapter struct {
 	claudePath string
+	// warmPool, when set, supplies pre-started claude processes. See
+	// AdapterRegistry.SetWarmPool.
+	warmPool *WarmPool
 }
 
 func NewClaudeAdapter() *ClaudeAdapter {
@@ -49,7 +52,7 @@ type NetworkSettings struct {
 	AllowedDomains []string `json:"allowedDomains,omitempty"`
 }
 
-func (a *ClaudeAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*AdapterResult, error) {
+func (a *ClaudeAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (res *AdapterResult, runErr error) {
 	var cancel context.CancelFunc
 	if cfg.Timeout > 0 {
 		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
@@ -67,39 +70,38 @@ func (a *ClaudeAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapter
 		return nil, fmt.Errorf("failed to prepare workspace: %w", err)
 	}
 
-	args := a.buildArgs()
-	cmd := exec.CommandContext(ctx, a.claudePath, args...)
-	cmd.Dir = workspacePath
-	if cfg.Prompt != "" {
-		cmd.Stdin = strings.NewReader(cfg.Prompt)
-	}
+	args := append(a.buildArgs(), cfg.ExtraArgs...)
 
 	if cfg.Debug {
 		fmt.Printf("[DEBUG] Claude command: %s %s\n", a.claudePath, shelljoinArgs(args))
 		fmt.Printf("[DEBUG] Working directory: %s\n", workspacePath)
 	}
 
-	cmd.Env = a.buildEnvironment(cfg)
-
-	// Set up process group for clean timeout kill
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true,
-		Pgid:    0,
+	spec := WarmSpec{
+		Path:        a.claudePath,
+		Args:        args,
+		Dir:         workspacePath,
+		Env:         a.buildEnvironment(cfg),
+		Fingerprint: func() string { return fingerprintFiles(workspacePath, claudeStartupFiles...) },
 	}
-
-	stdoutPipe, err := cmd.StdoutPipe()
+	proc, err := a.start(ctx, cfg, spec)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
-	}
-
-	stderrPipe, err := cmd.StderrPipe()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
-	}
-
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("failed to start claude: %w", err)
+		return nil, err
+	}
+	if a.warmPool != nil && cfg.WarmRetry {
+		// Only the step's retry reuses this workspace, so only a failed
+		// attempt leaves a process warm for it. A cancelled run is not
+		// retried.
+		defer func() {
+			if ctx.Err() == context.Canceled {
+				return
+			}
+			if runErr != nil || res.ExitCode != 0 || res.FailureReason != "" {
+				a.warmPool.replenish(spec)
+			}
+		}()
 	}
+	stdoutPipe, stderrPipe := proc.stdout, proc.stderr
 
 	var stderrBuf bytes.Buffer
 	var stdoutBuf bytes.Buffer
@@ -107,13 +109,17 @@ func (a *ClaudeAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapter
 	stderrDone := make(chan error, 1)
 
 	go func() {
-		_, err := io.Copy(&stderrBuf, stderrPipe)
+		var dst io.Writer = &stderrBuf
+		if w := rawLog(cfg); w != nil {
+			dst = io.MultiWriter(&stderrBuf, w)
+		}
+		_, err := io.Copy(dst, stderrPipe)
 		stderrDone <- err
 	}()
 
 	// Stream stdout line-by-line, parsing NDJSON events in real-time
 	go func() {
-		scanner := bufio.NewScanner(stdoutPipe)
+		scanner := bufio.NewScanner(teeRawLog(stdoutPipe, cfg))
 		scanner.Buffer(make([]byte, 0, 1024*1024), 10*1024*1024) // 10MB max line
 		for scanner.Scan() {
 			line := scanner.Bytes()
@@ -133,9 +139,7 @@ func (a *ClaudeAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapter
 	// Wait for both streams to finish or context cancellation
 	select {
 	case <-ctx.Done():
-		if cmd.Process != nil {
-			killProcessGroup(cmd.Process, cfg.ProcessGrace)
-		}
+		killProcessGroup(proc.process, cfg.ProcessGrace)
 		// Wait briefly for stdout to drain so we can capture diagnostic data
 		drainTimeout := cfg.StdoutDrain
 		if drainTimeout <= 0 {
@@ -145,7 +149,7 @@ func (a *ClaudeAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapter
 		case <-stdoutDone:
 		case <-time.After(drainTimeout):
 		}
-		_ = cmd.Wait()
+		_ = proc.wait()
 
 		// Parse buffered output for token usage and subtype even on timeout
 		parsed := a.parseOutput(stdoutBuf.Bytes())
@@ -160,7 +164,7 @@ func (a *ClaudeAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapter
 	// Wait for stderr to finish too
 	<-stderrDone
 
-	cmdErr := cmd.Wait()
+	cmdErr := proc.wait()
 	result := &AdapterResult{
 		ExitCode: 0,
 		Stdout:   bytes.NewReader(stdoutBuf.Bytes()),
@@ -177,9 +181,9 @@ func (a *ClaudeAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapter
 	result.Artifacts = parsed.Artifacts
 	result.Subtype = parsed.Subtype
 
-	// Classify failure for non-zero exit codes with context exhaustion indicators
+	// Classify failure for non-zero exit codes and error result subtypes
 	if result.ExitCode != 0 || parsed.Subtype == "error_max_turns" || parsed.Subtype == "error_during_execution" {
-		result.FailureReason = ClassifyFailure(parsed.Subtype, parsed.ResultContent, nil)
+		result.FailureReason = classifyRunFailure(parsed, result.ExitCode, stdoutBuf.Bytes())
 	}
 
 	// NOTE: Do NOT re-scan ResultContent for rate limit strings on exit code 0.
@@ -191,6 +195,8 @@ func (a *ClaudeAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapter
 	// not the JSON artifact. Artifact validation is handled by the contract
 	// validator which reads the actual file. Skip format validation here.
 	result.ResultContent = parsed.ResultContent
+	result.Transcript = ParseClaudeTranscript(stdoutBuf.Bytes())
+	NormalizeUsage("claude", cfg, stdoutBuf.Bytes(), result)
 
 	if cfg.Debug {
 		fmt.Printf("[DEBUG] Claude exit code: %d\n", result.ExitCode)
@@ -205,6 +211,68 @@ func (a *ClaudeAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapter
 	return result, nil
 }
 
+// claudeStartupFiles are the workspace files claude reads when it starts,
+// before the prompt arrives. A warm process only serves a step whose
+// copies of them are unchanged.
+var claudeStartupFiles = []string{".claude", "CLAUDE.md", ".mcp.json"}
+
+// claudeProcess is a started claude process: cold-started for the step or
+// taken from the warm pool.
+type claudeProcess struct {
+	process *os.Process
+	stdout  io.Reader
+	stderr  io.Reader
+	wait    func() error
+}
+
+// start launches claude for spec and feeds it cfg.Prompt on stdin, using a
+// warm process when the pool has one for spec.
+func (a *ClaudeAdapter) start(ctx context.Context, cfg AdapterRunConfig, spec WarmSpec) (*claudeProcess, error) {
+	if a.warmPool != nil {
+		if warm := a.warmPool.take(spec); warm != nil {
+			go func() {
+				_, _ = io.WriteString(warm.stdin, cfg.Prompt)
+				_ = warm.stdin.Close()
+			}()
+			if cfg.Debug {
+				fmt.Printf("[DEBUG] Using warm claude process %d\n", warm.process.Pid)
+			}
+			wait := func() error {
+				err := warm.wait()
+				_ = warm.stdout.Close()
+				_ = warm.stderr.Close()
+				return err
+			}
+			return &claudeProcess{process: warm.process, stdout: warm.stdout, stderr: warm.stderr, wait: wait}, nil
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, spec.Path, spec.Args...)
+	cmd.Dir = spec.Dir
+	if cfg.Prompt != "" {
+		cmd.Stdin = strings.NewReader(cfg.Prompt)
+	}
+	cmd.Env = spec.Env
+
+	// Set up process group for clean timeout kill
+	procutil.SetProcessGroup(cmd)
+
+	stdoutPipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	stderrPipe, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start claude: %w", err)
+	}
+	return &claudeProcess{process: cmd.Process, stdout: stdoutPipe, stderr: stderrPipe, wait: cmd.Wait}, nil
+}
+
 // agentFilePath is the workspace-relative path for the self-contained agent .md
 // file. It lives inside .claude/ so it stays out of the project root and is
 // excluded by the standard .gitignore rule.
@@ -457,6 +525,10 @@ type parseOutputResult struct {
 	Artifacts     []string
 	ResultContent string
 	Subtype       string // Result event subtype: "success", "error_max_turns", "error_during_execution"
+	// FailureReason is set when the output carried a structured provider
+	// error; DeniedTools lists tool calls the CLI refused.
+	FailureReason string
+	DeniedTools   []string
 }
 
 func (a *ClaudeAdapter) parseOutput(data []byte) parseOutputResult {
@@ -467,6 +539,7 @@ func (a *ClaudeAdapter) parseOutput(data []byte) parseOutputResult {
 	var artifacts []string
 	var resultContent string
 	var subtype string
+	var deniedTools []string
 
 	lines := bytes.Split(data, []byte("\n"))
 	for _, line := range lines {
@@ -493,6 +566,9 @@ func (a *ClaudeAdapter) parseOutput(data []byte) parseOutputResult {
 				CacheReadInputTokens     int `json:"cache_read_input_tokens"`
 				CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
 			} `json:"usage"`
+			PermissionDenials []struct {
+				ToolName string `json:"tool_name"`
+			} `json:"permission_denials"`
 			Message struct {
 				Usage struct {
 					InputTokens              int `json:"input_tokens"`
@@ -518,6 +594,9 @@ func (a *ClaudeAdapter) parseOutput(data []byte) parseOutputResult {
 			resultTokensOut = obj.Usage.OutputTokens
 			resultContent = obj.Result
 			subtype = obj.Subtype
+			for _, d := range obj.PermissionDenials {
+				deniedTools = append(deniedTools, d.ToolName)
+			}
 		case "assistant":
 			// Take the last assistant event's usage (not sum), since each turn's
 			// input_tokens already includes the full conversation history.
@@ -527,15 +606,13 @@ func (a *ClaudeAdapter) parseOutput(data []byte) parseOutputResult {
 		}
 	}
 
-	// Prefer result-level usage (cumulative total from Claude Code),
-	// fall back to accumulated assistant-event tokens, then byte estimate
+	// Prefer result-level usage (cumulative total from Claude Code), fall
+	// back to accumulated assistant-event tokens. NormalizeUsage estimates
+	// runs that reported neither.
 	tokens := resultTokens
 	if tokens == 0 {
 		tokens = assistantTokens
 	}
-	if tokens == 0 {
-		tokens = len(data) / 4
-	}
 
 	// Try to extract JSON from markdown code blocks if result looks like markdown
 	if strings.Contains(resultContent, "```json") {
@@ -551,6 +628,7 @@ func (a *ClaudeAdapter) parseOutput(data []byte) parseOutputResult {
 		Artifacts:     artifacts,
 		ResultContent: resultContent,
 		Subtype:       subtype,
+		DeniedTools:   deniedTools,
 	}
 }
 
@@ -608,6 +686,7 @@ func parseStreamLine(line []byte) (StreamEvent, bool) {
 func parseAssistantEvent(obj map[string]json.RawMessage) (StreamEvent, bool) {
 	var msg struct {
 		Message struct {
+			ID      string `json:"id"`
 			Content []struct {
 				Type  string          `json:"type"`
 				Name  string          `json:"name,omitempty"`
@@ -637,8 +716,10 @@ func parseAssistantEvent(obj map[string]json.RawMessage) (StreamEvent, bool) {
 				Type:      "tool_use",
 				ToolName:  block.Name,
 				ToolInput: target,
+				ToolPaths: extractToolPaths(block.Input),
 				TokensIn:  totalIn,
 				TokensOut: u.OutputTokens,
+				MessageID: msg.Message.ID,
 			}, true
 		case "text":
 			if block.Text == "" {
@@ -650,8 +731,11 @@ func parseAssistantEvent(obj map[string]json.RawMessage) (StreamEvent, bool) {
 				text = text[:200]
 			}
 			return StreamEvent{
-				Type:    "text",
-				Content: text,
+				Type:      "text",
+				Content:   text,
+				TokensIn:  totalIn,
+				TokensOut: u.OutputTokens,
+				MessageID: msg.Message.ID,
 			}, true
 		}
 	}
@@ -711,6 +795,37 @@ func extractToolTarget(toolName string, input json.RawMessage) string {
 	}
 }
 
+// toolPathFields are the argument names adapters use for a tool's file path.
+var toolPathFields = []string{"file_path", "filePath", "absolute_path", "notebook_path", "path", "dir_path"}
+
+// extractToolPaths returns the file paths in a tool call's JSON arguments.
+func extractToolPaths(input json.RawMessage) []string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(input, &fields); err != nil {
+		return nil
+	}
+	var paths []string
+	for _, field := range toolPathFields {
+		if val := jsonString(fields[field]); val != "" {
+			paths = append(paths, val)
+		}
+	}
+	return paths
+}
+
+// toolPathsFromInput returns the file paths in a tool input that adapters
+// report as a plain string: JSON arguments or a bare path.
+func toolPathsFromInput(input string) []string {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil
+	}
+	if strings.HasPrefix(input, "{") {
+		return extractToolPaths(json.RawMessage(input))
+	}
+	return []string{input}
+}
+
 // jsonString extracts a string from a json.RawMessage, stripping quotes.
 func jsonString(raw json.RawMessage) string {
 	var s string
@@ -1674,22 +1674,21 @@ func TestParseOutputFallbackChain(t *testing.T) {
 		}
 	})
 
-	t.Run("falls back to byte estimate when both are zero", func(t *testing.T) {
-		// A result event with all-zero tokens and no assistant event
+	t.Run("reports nothing when both are zero", func(t *testing.T) {
+		// A result event with all-zero tokens and no assistant event;
+		// NormalizeUsage estimates the run.
 		data := []byte(`{"type":"result","subtype":"success","result":"done","usage":{"input_tokens":0,"output_tokens":0}}` + "\n")
 		parsed := adapter.parseOutput(data)
-		expected := len(data) / 4
-		if parsed.Tokens != expected {
-			t.Errorf("Tokens = %d, want %d (byte estimate fallback)", parsed.Tokens, expected)
+		if parsed.Tokens != 0 {
+			t.Errorf("Tokens = %d, want 0", parsed.Tokens)
 		}
 	})
 
-	t.Run("falls back to byte estimate with no events", func(t *testing.T) {
+	t.Run("reports nothing with no events", func(t *testing.T) {
 		data := []byte("some raw output with no JSON\n")
 		parsed := adapter.parseOutput(data)
-		expected := len(data) / 4
-		if parsed.Tokens != expected {
-			t.Errorf("Tokens = %d, want %d (byte estimate for non-JSON)", parsed.Tokens, expected)
+		if parsed.Tokens != 0 {
+			t.Errorf("Tokens = %d, want 0", parsed.Tokens)
 		}
 	})
 }
@@ -1714,6 +1713,19 @@ func TestParseOutput_ReturnsTokensInOut(t *testing.T) {
 	}
 }
 
+func TestParseOutput_PermissionDenials(t *testing.T) {
+	a := &ClaudeAdapter{}
+	data := []byte(`{"type":"result","subtype":"error_during_execution","result":"could not run the tests","permission_denials":[{"tool_name":"Bash","tool_use_id":"t1"}]}` + "\n")
+	parsed := a.parseOutput(data)
+
+	if len(parsed.DeniedTools) != 1 || parsed.DeniedTools[0] != "Bash" {
+		t.Errorf("DeniedTools = %v, want [Bash]", parsed.DeniedTools)
+	}
+	if got := classifyRunFailure(parsed, 1, data); got != FailureReasonToolDenied {
+		t.Errorf("classifyRunFailure() = %q, want %q", got, FailureReasonToolDenied)
+	}
+}
+
 func TestBuildSkillSection(t *testing.T) {
 	t.Run("empty skills returns empty string", func(t *testing.T) {
 		result := buildSkillSection(nil)
@@ -6,26 +6,50 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"os/exec"
 	"path/filepath"
-	"syscall"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/recinq/wave/internal/procutil"
 )
 
-// CodexAdapter runs the OpenAI Codex CLI (codex) as a subprocess.
+// CodexAdapter runs the OpenAI Codex CLI (codex exec) as a subprocess.
+//
+// Codex has no per-tool permission list, so AllowedTools and DenyTools are
+// mapped onto its sandbox: a persona that may not write files or run shell
+// commands runs read-only, and network access is granted only when web
+// tools are allowed. The exact lists are also written into AGENTS.md as
+// restrictions, as the Claude adapter does in CLAUDE.md.
 type CodexAdapter struct {
 	codexPath string
 }
 
 // NewCodexAdapter creates a CodexAdapter, locating the codex binary on PATH.
 func NewCodexAdapter() *CodexAdapter {
-	path := "codex"
-	if p, err := exec.LookPath("codex"); err == nil {
+	return NewCodexAdapterWithBinary("")
+}
+
+// NewCodexAdapterWithBinary constructs a CodexAdapter that invokes the given
+// binary. An empty name defaults to "codex".
+func NewCodexAdapterWithBinary(binary string) *CodexAdapter {
+	if binary == "" {
+		binary = "codex"
+	}
+	path := binary
+	if p, err := exec.LookPath(binary); err == nil {
 		path = p
 	}
 	return &CodexAdapter{codexPath: path}
 }
 
+// isCodexBinary reports whether binary names the Codex CLI, so an adapter
+// declared as `binary: codex` under any name runs on the Codex adapter.
+func isCodexBinary(binary string) bool {
+	return strings.TrimSuffix(filepath.Base(binary), ".exe") == "codex"
+}
+
 func (a *CodexAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*AdapterResult, error) {
 	var cancel context.CancelFunc
 	if cfg.Timeout > 0 {
@@ -45,15 +69,18 @@ func (a *CodexAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*AdapterR
 		return nil, fmt.Errorf("provision codex skills: %w", err)
 	}
 
-	if err := a.prepareWorkspace(workspacePath, cfg); err != nil {
+	restoreInstructions, err := a.prepareWorkspace(workspacePath, cfg)
+	if err != nil {
 		return nil, fmt.Errorf("failed to prepare codex workspace: %w", err)
 	}
+	defer restoreInstructions()
 
 	args := a.buildArgs(cfg)
 	cmd := exec.CommandContext(ctx, a.codexPath, args...)
 	cmd.Dir = workspacePath
 	cmd.Env = BuildCuratedEnvironment(cfg)
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Pgid: 0}
+	procutil.SetProcessGroup(cmd)
+	cmd.Stderr = rawLog(cfg)
 
 	stdoutPipe, err := cmd.StdoutPipe()
 	if err != nil {
@@ -68,7 +95,7 @@ func (a *CodexAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*AdapterR
 	stdoutDone := make(chan error, 1)
 
 	go func() {
-		scanner := bufio.NewScanner(stdoutPipe)
+		scanner := bufio.NewScanner(teeRawLog(stdoutPipe, cfg))
 		scanner.Buffer(make([]byte, 0, 1024*1024), 10*1024*1024)
 		for scanner.Scan() {
 			line := scanner.Bytes()
@@ -101,85 +128,189 @@ func (a *CodexAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*AdapterR
 	result := a.parseOutput(stdoutBuf.String())
 	if cmdErr != nil {
 		result.ExitCode = exitCodeFromError(cmdErr)
-		if result.FailureReason == "" {
-			result.FailureReason = classifyCodexFailure(result.ExitCode)
+		if result.FailureReason == "" || result.FailureReason == FailureReasonGeneralError {
+			result.FailureReason = classifyExit(result.ExitCode, stdoutBuf.Bytes(), true)
 		}
 	}
 	result.Stdout = bytes.NewReader(stdoutBuf.Bytes())
+	NormalizeUsage("codex", cfg, stdoutBuf.Bytes(), result)
 
 	return result, nil
 }
 
-func (a *CodexAdapter) prepareWorkspace(workspacePath string, cfg AdapterRunConfig) error {
-	// Write system prompt as AGENTS.md for Codex
-	if cfg.SystemPrompt != "" {
-		promptPath := filepath.Join(workspacePath, "AGENTS.md")
-		if err := os.WriteFile(promptPath, []byte(cfg.SystemPrompt), 0644); err != nil {
-			return fmt.Errorf("failed to write AGENTS.md: %w", err)
-		}
+// prepareWorkspace writes the system prompt and tool restrictions as
+// AGENTS.md for Codex. The returned function restores the workspace's own
+// AGENTS.md.
+func (a *CodexAdapter) prepareWorkspace(workspacePath string, cfg AdapterRunConfig) (func(), error) {
+	content := cfg.SystemPrompt + buildRestrictionSection(cfg)
+	if content == "" {
+		return func() {}, nil
 	}
-	return nil
+	return WriteInstructions(workspacePath, InstructionFilename("codex"), []byte(content))
 }
 
 func (a *CodexAdapter) buildArgs(cfg AdapterRunConfig) []string {
-	args := []string{"--full-auto"}
-
-	if cfg.Model != "" {
+	args := []string{"exec", "--json", "--skip-git-repo-check"}
+
+	// adapter_options may pick the sandbox explicitly.
+	if !slices.Contains(cfg.ExtraArgs, "--sandbox") {
+		sandbox := codexSandboxMode(cfg)
+		args = append(args, "--sandbox", sandbox)
+		if sandbox == codexSandboxWorkspaceWrite && (codexToolPermitted(cfg, "WebFetch") || codexToolPermitted(cfg, "WebSearch")) {
+			args = append(args, "-c", "sandbox_workspace_write.network_access=true")
+		}
+	}
+	if cfg.Model != "" && cfg.Model != "default" {
 		args = append(args, "--model", cfg.Model)
 	}
+	if cfg.Temperature != 0 || cfg.TemperatureSet {
+		args = append(args, "-c", "temperature="+strconv.FormatFloat(cfg.Temperature, 'f', -1, 64))
+	}
+	args = append(args, cfg.ExtraArgs...)
 
 	if cfg.Prompt != "" {
-		args = append(args, cfg.Prompt)
+		args = append(args, "--", cfg.Prompt)
 	}
 
 	return args
 }
 
+// Codex sandbox modes.
+const (
+	codexSandboxReadOnly       = "read-only"
+	codexSandboxWorkspaceWrite = "workspace-write"
+)
+
+// codexWriteTools are the tools whose use needs a writable workspace.
+var codexWriteTools = []string{"Write", "Edit", "MultiEdit", "NotebookEdit", "Bash"}
+
+// codexSandboxMode returns the sandbox a persona's tool permissions allow:
+// workspace-write when it may write files or run commands, read-only
+// otherwise.
+func codexSandboxMode(cfg AdapterRunConfig) string {
+	for _, tool := range codexWriteTools {
+		if codexToolPermitted(cfg, tool) {
+			return codexSandboxWorkspaceWrite
+		}
+	}
+	return codexSandboxReadOnly
+}
+
+// codexToolPermitted reports whether some use of tool is permitted: no deny
+// pattern blocks the tool outright, and the allow list is empty or names
+// the tool, with or without an argument pattern.
+func codexToolPermitted(cfg AdapterRunConfig, tool string) bool {
+	for _, pattern := range cfg.DenyTools {
+		if name, arg := parseToolPattern(pattern); (arg == "" || arg == "*") && matchGlob(name, tool) {
+			return false
+		}
+	}
+	if len(cfg.AllowedTools) == 0 {
+		return true
+	}
+	for _, pattern := range cfg.AllowedTools {
+		if name, _ := parseToolPattern(pattern); matchGlob(name, tool) {
+			return true
+		}
+	}
+	return false
+}
+
+// codexEvent is one line of `codex exec --json` output. Turn events carry
+// usage and errors; item events carry the agent's messages and actions.
+// The type, content and usage fields at the top level are the event shape
+// of older Codex releases.
+type codexEvent struct {
+	Type    string      `json:"type"`
+	Item    *codexItem  `json:"item"`
+	Usage   *codexUsage `json:"usage"`
+	Message string      `json:"message"`
+	Error   *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+
+	Content   string `json:"content"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// codexItem is a thread item: an agent message, a command, a file change,
+// an MCP tool call or a web search.
+type codexItem struct {
+	Type    string `json:"type"`
+	Text    string `json:"text"`
+	Command string `json:"command"`
+	Changes []struct {
+		Path string `json:"path"`
+	} `json:"changes"`
+	Server string `json:"server"`
+	Tool   string `json:"tool"`
+	Query  string `json:"query"`
+}
+
+type codexUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
 func (a *CodexAdapter) parseOutput(output string) *AdapterResult {
 	result := &AdapterResult{}
 
-	lines := bytes.Split([]byte(output), []byte("\n"))
-	for _, line := range lines {
+	for _, line := range bytes.Split([]byte(output), []byte("\n")) {
 		line = bytes.TrimSpace(line)
 		if len(line) == 0 {
 			continue
 		}
-		var obj map[string]json.RawMessage
-		if err := json.Unmarshal(line, &obj); err != nil {
+		var evt codexEvent
+		if err := json.Unmarshal(line, &evt); err != nil {
 			continue
 		}
 
-		var eventType string
-		if raw, ok := obj["type"]; ok {
-			_ = json.Unmarshal(raw, &eventType)
-		}
-
-		if eventType == "result" || eventType == "message" {
-			var resultEvt struct {
-				Usage struct {
-					InputTokens  int `json:"input_tokens"`
-					OutputTokens int `json:"output_tokens"`
-				} `json:"usage"`
-				Content string `json:"content"`
+		switch evt.Type {
+		case "turn.completed":
+			// Usage is reported per turn.
+			if evt.Usage != nil {
+				result.TokensIn += evt.Usage.InputTokens
+				result.TokensOut += evt.Usage.OutputTokens
+			}
+		case "item.completed":
+			if evt.Item != nil && evt.Item.Type == "agent_message" && evt.Item.Text != "" {
+				result.ResultContent = evt.Item.Text
 			}
-			if err := json.Unmarshal(line, &resultEvt); err == nil {
-				if resultEvt.Usage.InputTokens > 0 {
-					result.TokensIn = resultEvt.Usage.InputTokens
+		case "turn.failed", "error":
+			if msg := evt.errorMessage(); msg != "" {
+				result.FailureReason = ClassifyFailure("", msg, nil)
+				if result.ResultContent == "" {
+					result.ResultContent = msg
 				}
-				if resultEvt.Usage.OutputTokens > 0 {
-					result.TokensOut = resultEvt.Usage.OutputTokens
+			}
+		case "result", "message":
+			if evt.Usage != nil {
+				if evt.Usage.InputTokens > 0 {
+					result.TokensIn = evt.Usage.InputTokens
 				}
-				result.TokensUsed = result.TokensIn + result.TokensOut
-				if resultEvt.Content != "" {
-					result.ResultContent = resultEvt.Content
+				if evt.Usage.OutputTokens > 0 {
+					result.TokensOut = evt.Usage.OutputTokens
 				}
 			}
+			if evt.Content != "" {
+				result.ResultContent = evt.Content
+			}
 		}
 	}
+	result.TokensUsed = result.TokensIn + result.TokensOut
 
 	return result
 }
 
+// errorMessage returns the message of a turn.failed or error event.
+func (e codexEvent) errorMessage() string {
+	if e.Error != nil && e.Error.Message != "" {
+		return e.Error.Message
+	}
+	return e.Message
+}
+
 // parseCodexStreamLine parses a single NDJSON line from Codex output.
 func parseCodexStreamLine(line []byte) (StreamEvent, bool) {
 	line = bytes.TrimSpace(line)
@@ -187,71 +318,83 @@ func parseCodexStreamLine(line []byte) (StreamEvent, bool) {
 		return StreamEvent{}, false
 	}
 
-	var obj map[string]json.RawMessage
-	if err := json.Unmarshal(line, &obj); err != nil {
+	var evt codexEvent
+	if err := json.Unmarshal(line, &evt); err != nil {
 		return StreamEvent{}, false
 	}
 
-	var eventType string
-	if raw, ok := obj["type"]; ok {
-		if err := json.Unmarshal(raw, &eventType); err != nil {
+	switch evt.Type {
+	case "item.started", "item.completed":
+		if evt.Item == nil {
 			return StreamEvent{}, false
 		}
+		return codexItemStreamEvent(evt.Type, evt.Item)
+	case "turn.completed":
+		if evt.Usage != nil {
+			return StreamEvent{Type: "result", TokensIn: evt.Usage.InputTokens, TokensOut: evt.Usage.OutputTokens}, true
+		}
+	case "turn.failed", "error":
+		if msg := evt.errorMessage(); msg != "" {
+			return StreamEvent{Type: "result", Subtype: "error_during_execution", Content: truncateStreamText(msg, 200)}, true
+		}
+	case "function_call":
+		if evt.Name != "" {
+			return StreamEvent{Type: "tool_use", ToolName: evt.Name, ToolInput: truncateStreamText(evt.Arguments, 100), ToolPaths: toolPathsFromInput(evt.Arguments)}, true
+		}
+	case "message":
+		if evt.Content != "" {
+			return StreamEvent{Type: "text", Content: truncateStreamText(evt.Content, 200)}, true
+		}
+	case "result":
+		res := StreamEvent{Type: "result", Content: evt.Content}
+		if evt.Usage != nil {
+			res.TokensIn = evt.Usage.InputTokens
+			res.TokensOut = evt.Usage.OutputTokens
+		}
+		return res, true
 	}
 
-	switch eventType {
-	case "function_call":
-		var fc struct {
-			Name      string `json:"name"`
-			Arguments string `json:"arguments"`
+	return StreamEvent{}, false
+}
+
+// codexItemStreamEvent maps a thread item to a stream event, using the tool
+// names the other adapters report so progress displays read the same.
+// Actions are reported when they start, messages when they complete.
+func codexItemStreamEvent(eventType string, item *codexItem) (StreamEvent, bool) {
+	started := eventType == "item.started"
+	switch item.Type {
+	case "command_execution":
+		if started {
+			return StreamEvent{Type: "tool_use", ToolName: "Bash", ToolInput: truncateStreamText(item.Command, 100)}, true
 		}
-		if err := json.Unmarshal(line, &fc); err == nil && fc.Name != "" {
-			input := fc.Arguments
-			if len(input) > 100 {
-				input = input[:100]
-			}
-			return StreamEvent{Type: "tool_use", ToolName: fc.Name, ToolInput: input}, true
+	case "mcp_tool_call":
+		if started {
+			return StreamEvent{Type: "tool_use", ToolName: item.Server + "." + item.Tool}, true
 		}
-	case "message":
-		var msg struct {
-			Content string `json:"content"`
+	case "web_search":
+		if started {
+			return StreamEvent{Type: "tool_use", ToolName: "WebSearch", ToolInput: truncateStreamText(item.Query, 100)}, true
 		}
-		if err := json.Unmarshal(line, &msg); err == nil && msg.Content != "" {
-			text := msg.Content
-			if len(text) > 200 {
-				text = text[:200]
+	case "file_change":
+		if !started {
+			paths := make([]string, 0, len(item.Changes))
+			for _, c := range item.Changes {
+				paths = append(paths, c.Path)
 			}
-			return StreamEvent{Type: "text", Content: text}, true
+			return StreamEvent{Type: "tool_use", ToolName: "Edit", ToolInput: truncateStreamText(strings.Join(paths, " "), 100), ToolPaths: paths}, true
 		}
-	case "result":
-		var res struct {
-			Usage struct {
-				InputTokens  int `json:"input_tokens"`
-				OutputTokens int `json:"output_tokens"`
-			} `json:"usage"`
-			Content string `json:"content"`
-		}
-		if err := json.Unmarshal(line, &res); err == nil {
-			return StreamEvent{
-				Type:      "result",
-				TokensIn:  res.Usage.InputTokens,
-				TokensOut: res.Usage.OutputTokens,
-				Content:   res.Content,
-			}, true
+	case "agent_message":
+		if !started && item.Text != "" {
+			return StreamEvent{Type: "text", Content: truncateStreamText(item.Text, 200)}, true
 		}
 	}
-
 	return StreamEvent{}, false
 }
 
-// classifyCodexFailure maps Codex exit codes to failure reasons.
-func classifyCodexFailure(exitCode int) string {
-	switch exitCode {
-	case 124, 137:
-		return "timeout"
-	case 1:
-		return "general_error"
-	default:
-		return "general_error"
+// truncateStreamText shortens s to at most n bytes for a stream event.
+func truncateStreamText(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
 	}
+	return s
 }
@@ -1,13 +1,19 @@
 package adapter
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCodexAdapter_BuildArgs(t *testing.T) {
 	a := NewCodexAdapter()
+	base := []string{"exec", "--json", "--skip-git-repo-check"}
+	args := func(rest ...string) []string { return append(append([]string(nil), base...), rest...) }
 
 	tests := []struct {
 		name string
@@ -17,17 +23,42 @@ func TestCodexAdapter_BuildArgs(t *testing.T) {
 		{
 			name: "basic prompt",
 			cfg:  AdapterRunConfig{Prompt: "implement the feature"},
-			want: []string{"--full-auto", "implement the feature"},
+			want: args("--sandbox", "workspace-write", "-c", "sandbox_workspace_write.network_access=true", "--", "implement the feature"),
 		},
 		{
-			name: "with model",
-			cfg:  AdapterRunConfig{Prompt: "fix the bug", Model: "gpt-4o"},
-			want: []string{"--full-auto", "--model", "gpt-4o", "fix the bug"},
+			name: "with model and temperature",
+			cfg:  AdapterRunConfig{Prompt: "fix the bug", Model: "gpt-5-codex", Temperature: 0.2, AllowedTools: []string{"Read", "Edit"}},
+			want: args("--sandbox", "workspace-write", "--model", "gpt-5-codex", "-c", "temperature=0.2", "--", "fix the bug"),
+		},
+		{
+			name: "explicit zero temperature",
+			cfg:  AdapterRunConfig{Prompt: "fix the bug", TemperatureSet: true},
+			want: args("--sandbox", "workspace-write", "-c", "sandbox_workspace_write.network_access=true", "-c", "temperature=0", "--", "fix the bug"),
+		},
+		{
+			name: "read-only persona",
+			cfg:  AdapterRunConfig{Prompt: "review", AllowedTools: []string{"Read", "Glob", "Grep", "WebFetch"}},
+			want: args("--sandbox", "read-only", "--", "review"),
+		},
+		{
+			name: "denied writes",
+			cfg:  AdapterRunConfig{Prompt: "review", DenyTools: []string{"Write", "Edit", "MultiEdit", "NotebookEdit", "Bash"}},
+			want: args("--sandbox", "read-only", "--", "review"),
+		},
+		{
+			name: "scoped writes still need a writable workspace",
+			cfg:  AdapterRunConfig{Prompt: "plan", AllowedTools: []string{"Read", "Write(.agents/output/*)"}, DenyTools: []string{"Bash(rm *)"}},
+			want: args("--sandbox", "workspace-write", "--", "plan"),
 		},
 		{
 			name: "no prompt",
-			cfg:  AdapterRunConfig{},
-			want: []string{"--full-auto"},
+			cfg:  AdapterRunConfig{Model: "default", AllowedTools: []string{"Read"}},
+			want: args("--sandbox", "read-only"),
+		},
+		{
+			name: "adapter options choose the sandbox",
+			cfg:  AdapterRunConfig{Prompt: "fix the bug", AllowedTools: []string{"Read"}, ExtraArgs: []string{"--sandbox", "danger-full-access"}},
+			want: args("--sandbox", "danger-full-access", "--", "fix the bug"),
 		},
 	}
 
@@ -64,6 +95,20 @@ func TestCodexAdapter_ParseOutput(t *testing.T) {
 			name:   "non-json output",
 			output: "plain text output",
 		},
+		{
+			name: "exec json events",
+			output: `{"type":"thread.started","thread_id":"t1"}
+{"type":"turn.started"}
+{"type":"item.completed","item":{"id":"item_0","type":"agent_message","text":"Looking at the tests."}}
+{"type":"item.started","item":{"id":"item_1","type":"command_execution","command":"go test ./...","status":"in_progress"}}
+{"type":"turn.completed","usage":{"input_tokens":1200,"cached_input_tokens":800,"output_tokens":300}}
+{"type":"turn.started"}
+{"type":"item.completed","item":{"id":"item_2","type":"agent_message","text":"All tests pass."}}
+{"type":"turn.completed","usage":{"input_tokens":400,"output_tokens":100}}`,
+			wantIn:      1600,
+			wantOut:     400,
+			wantContent: "All tests pass.",
+		},
 	}
 
 	for _, tt := range tests {
@@ -103,6 +148,47 @@ func TestParseCodexStreamLine(t *testing.T) {
 			wantOK:  true,
 			wantEvt: StreamEvent{Type: "result", TokensIn: 100, TokensOut: 50, Content: "ok"},
 		},
+		{
+			name:    "command started",
+			line:    `{"type":"item.started","item":{"type":"command_execution","command":"bash -lc 'go test ./...'"}}`,
+			wantOK:  true,
+			wantEvt: StreamEvent{Type: "tool_use", ToolName: "Bash"},
+		},
+		{
+			name:   "command completed is not repeated",
+			line:   `{"type":"item.completed","item":{"type":"command_execution","command":"ls","exit_code":0}}`,
+			wantOK: false,
+		},
+		{
+			name:    "file change",
+			line:    `{"type":"item.completed","item":{"type":"file_change","changes":[{"path":"main.go","kind":"update"}]}}`,
+			wantOK:  true,
+			wantEvt: StreamEvent{Type: "tool_use", ToolName: "Edit"},
+		},
+		{
+			name:    "multi-path file change keeps every path",
+			line:    `{"type":"item.completed","item":{"type":"file_change","changes":[{"path":"src/a.go","kind":"update"},{"path":"/etc/passwd","kind":"update"}]}}`,
+			wantOK:  true,
+			wantEvt: StreamEvent{Type: "tool_use", ToolName: "Edit", ToolPaths: []string{"src/a.go", "/etc/passwd"}},
+		},
+		{
+			name:    "mcp tool call",
+			line:    `{"type":"item.started","item":{"type":"mcp_tool_call","server":"github","tool":"get_issue"}}`,
+			wantOK:  true,
+			wantEvt: StreamEvent{Type: "tool_use", ToolName: "github.get_issue"},
+		},
+		{
+			name:    "agent message",
+			line:    `{"type":"item.completed","item":{"type":"agent_message","text":"done"}}`,
+			wantOK:  true,
+			wantEvt: StreamEvent{Type: "text"},
+		},
+		{
+			name:    "turn usage",
+			line:    `{"type":"turn.completed","usage":{"input_tokens":10,"output_tokens":5}}`,
+			wantOK:  true,
+			wantEvt: StreamEvent{Type: "result"},
+		},
 		{
 			name:   "empty line",
 			line:   "",
@@ -127,18 +213,14 @@ func TestParseCodexStreamLine(t *testing.T) {
 			if ok {
 				assert.Equal(t, tt.wantEvt.Type, evt.Type)
 				assert.Equal(t, tt.wantEvt.ToolName, evt.ToolName)
+				if tt.wantEvt.ToolPaths != nil {
+					assert.Equal(t, tt.wantEvt.ToolPaths, evt.ToolPaths)
+				}
 			}
 		})
 	}
 }
 
-func TestClassifyCodexFailure(t *testing.T) {
-	assert.Equal(t, "timeout", classifyCodexFailure(124))
-	assert.Equal(t, "timeout", classifyCodexFailure(137))
-	assert.Equal(t, "general_error", classifyCodexFailure(1))
-	assert.Equal(t, "general_error", classifyCodexFailure(255))
-}
-
 func TestCodexAdapter_PrepareWorkspace(t *testing.T) {
 	a := NewCodexAdapter()
 	tmpDir := t.TempDir()
@@ -147,6 +229,44 @@ func TestCodexAdapter_PrepareWorkspace(t *testing.T) {
 		SystemPrompt: "You are a helpful assistant",
 	}
 
-	err := a.prepareWorkspace(tmpDir, cfg)
+	restore, err := a.prepareWorkspace(tmpDir, cfg)
 	assert.NoError(t, err)
+	restore()
+}
+
+func TestCodexAdapter_ParseOutput_TurnFailed(t *testing.T) {
+	result := NewCodexAdapter().parseOutput(`{"type":"turn.started"}
+{"type":"turn.failed","error":{"message":"stream error: rate limit exceeded"}}`)
+	assert.Equal(t, FailureReasonRateLimit, result.FailureReason)
+	assert.Equal(t, "stream error: rate limit exceeded", result.ResultContent)
+}
+
+func TestCodexAdapter_PrepareWorkspace_Restrictions(t *testing.T) {
+	tmpDir := t.TempDir()
+	restore, err := NewCodexAdapter().prepareWorkspace(tmpDir, AdapterRunConfig{
+		SystemPrompt: "You are a reviewer",
+		DenyTools:    []string{"Bash(git push*)"},
+	})
+	require.NoError(t, err)
+	t.Cleanup(restore)
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "AGENTS.md"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "You are a reviewer"))
+	assert.Contains(t, string(data), "- `Bash(git push*)`")
+}
+
+func TestResolveAdapterWithBinary_Codex(t *testing.T) {
+	assert.IsType(t, &CodexAdapter{}, ResolveAdapterWithBinary("codex", ""))
+	assert.IsType(t, &CodexAdapter{}, ResolveAdapterWithBinary("openai", "codex"))
+	assert.IsType(t, &CodexAdapter{}, ResolveAdapterWithBinary("openai", "/opt/bin/codex"))
+	assert.IsType(t, &ProcessGroupRunner{}, ResolveAdapterWithBinary("openai", "openai-cli"))
+
+	r := NewAdapterRegistry(nil)
+	r.SetBinary("openai", "codex")
+	runner, err := r.ResolveStrict("openai")
+	require.NoError(t, err)
+	assert.IsType(t, &CodexAdapter{}, runner)
+	_, err = r.ResolveStrict("anthropic")
+	assert.ErrorIs(t, err, ErrUnknownAdapter)
 }
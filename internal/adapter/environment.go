@@ -3,6 +3,8 @@ package adapter
 import (
 	"os"
 	"strings"
+
+	"github.com/recinq/wave/internal/procutil"
 )
 
 // ProviderModel represents a parsed provider/model identifier.
@@ -55,7 +57,8 @@ func ParseProviderModel(model string) ProviderModel {
 }
 
 // BuildCuratedEnvironment constructs a curated environment for adapter subprocesses.
-// It includes base variables (HOME, PATH, TERM, TMPDIR), explicitly allowed
+// It includes base variables (HOME, PATH, TERM, TMPDIR, plus the Windows
+// system variables from procutil.PlatformEnv), explicitly allowed
 // passthrough variables from the manifest, and step-specific env vars.
 func BuildCuratedEnvironment(cfg AdapterRunConfig) []string {
 	env := []string{
@@ -64,6 +67,7 @@ func BuildCuratedEnvironment(cfg AdapterRunConfig) []string {
 		"TERM=" + getenvDefault("TERM", "xterm-256color"),
 		"TMPDIR=/tmp",
 	}
+	env = append(env, procutil.PlatformEnv()...)
 
 	// Add explicitly allowed env vars from manifest
 	for _, key := range cfg.EnvPassthrough {
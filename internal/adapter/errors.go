@@ -1,7 +1,9 @@
 package adapter
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -9,20 +11,40 @@ import (
 
 // ErrUnknownAdapter is returned when a registry is asked to strictly resolve
 // an adapter name that has no override registered and is not a built-in
-// adapter (claude, codex, gemini, opencode, browser). It allows callers
+// adapter (claude, codex, gemini, opencode, browser, ollama). It allows callers
 // such as FallbackRunner to surface a typed error rather than silently
 // falling back to a generic ProcessGroupRunner — which would attempt to
 // exec the adapter name as a binary.
 var ErrUnknownAdapter = errors.New("unknown adapter")
 
-// Failure reason constants for error classification.
+// Failure reasons classify why an adapter run failed. Adapters set them on
+// AdapterResult.FailureReason and StepError from their CLI's output, so the
+// executor can pick failure classes and retry behavior without matching on
+// error text.
 const (
 	FailureReasonTimeout           = "timeout"
-	FailureReasonContextExhaustion = "context_exhaustion"
+	FailureReasonContextExhaustion = "context_exhaustion" // context window overflow or turn limit
 	FailureReasonRateLimit         = "rate_limit"
+	FailureReasonQuota             = "quota"       // usage quota or credit exhausted
+	FailureReasonAuth              = "auth"        // missing or rejected credentials
+	FailureReasonToolDenied        = "tool_denied" // the CLI refused a tool call the run needed
+	FailureReasonCrash             = "crash"       // killed by a signal or exited without output
+	FailureReasonParseError        = "parse_error" // output the adapter could not parse
 	FailureReasonGeneralError      = "general_error"
 )
 
+// IsTerminalFailure reports whether a run that failed for reason produced
+// an error message rather than work product, so its result must not be
+// treated as step output.
+func IsTerminalFailure(reason string) bool {
+	switch reason {
+	case FailureReasonRateLimit, FailureReasonQuota, FailureReasonAuth:
+		return true
+	default:
+		return false
+	}
+}
+
 // StepError is a structured error type that carries diagnostic data
 // from adapter execution failures. It enables the executor to extract
 // token usage, failure classification, and remediation suggestions
@@ -60,10 +82,24 @@ func NewStepError(reason string, cause error, tokensUsed int, subtype string) *S
 	}
 }
 
+// failureMessagePatterns are the error messages CLIs print for failures
+// they report only as text. Patterns are specific enough not to match an
+// agent's prose about the same topics (e.g. a review of rate limiting).
+var failureMessagePatterns = []struct {
+	reason   string
+	patterns []string
+}{
+	{FailureReasonContextExhaustion, []string{"prompt is too long", "context_length_exceeded", "maximum context length"}},
+	{FailureReasonQuota, []string{"insufficient_quota", "exceeded your current quota", "quota exceeded", "credit balance is too low"}},
+	{FailureReasonRateLimit, []string{"you've hit your limit", "rate limit exceeded", "rate limit reached", "rate limited", "too many requests"}},
+	{FailureReasonAuth, []string{"invalid api key", "invalid x-api-key", "api key not valid", "authentication_error", "authentication failed", "please run /login", "401 unauthorized"}},
+}
+
 // ClassifyFailure determines the failure reason from the result subtype,
-// result content, and context error. This implements three-way classification:
+// the result or error message, and the context error:
 //   - timeout: Go context deadline was exceeded
-//   - context_exhaustion: Claude Code ran out of context window
+//   - context_exhaustion: the CLI hit its turn limit or context window
+//   - quota, rate_limit, auth: the provider rejected the request
 //   - general_error: any other failure
 func ClassifyFailure(subtype string, resultContent string, ctxErr error) string {
 	if ctxErr == context.DeadlineExceeded {
@@ -72,16 +108,78 @@ func ClassifyFailure(subtype string, resultContent string, ctxErr error) string
 	if subtype == "error_max_turns" {
 		return FailureReasonContextExhaustion
 	}
-	if strings.Contains(strings.ToLower(resultContent), "prompt is too long") {
-		return FailureReasonContextExhaustion
-	}
 	lowerContent := strings.ToLower(resultContent)
-	if strings.Contains(lowerContent, "you've hit your limit") ||
-		strings.Contains(lowerContent, "rate limit exceeded") ||
-		strings.Contains(lowerContent, "rate limit reached") ||
-		strings.Contains(lowerContent, "rate limited") ||
-		strings.Contains(lowerContent, "too many requests") {
+	for _, c := range failureMessagePatterns {
+		for _, p := range c.patterns {
+			if strings.Contains(lowerContent, p) {
+				return c.reason
+			}
+		}
+	}
+	return FailureReasonGeneralError
+}
+
+// classifyAPIError maps a structured provider error, its type or name and
+// the HTTP status when known (0 otherwise), onto a failure reason. Returns
+// "" when neither identifies one.
+func classifyAPIError(errType string, status int) string {
+	t := strings.ToLower(errType)
+	switch {
+	case strings.Contains(t, "quota") || strings.Contains(t, "billing") || strings.Contains(t, "credit"):
+		return FailureReasonQuota
+	case status == 401 || status == 403 || strings.Contains(t, "auth") || strings.Contains(t, "permission"):
+		return FailureReasonAuth
+	case status == 429 || strings.Contains(t, "rate_limit") || strings.Contains(t, "ratelimit"):
 		return FailureReasonRateLimit
+	case status == 413 || strings.Contains(t, "context_length") || strings.Contains(t, "too_large") || strings.Contains(t, "turnlimit"):
+		return FailureReasonContextExhaustion
+	}
+	return ""
+}
+
+// classifyExit classifies a non-zero exit whose output named no failure.
+// Exit codes 124 and 137 are timeouts; a signal or an exit with no output
+// is a crash. For adapters that stream JSON events (structured), output
+// with no parseable event is a parse error.
+func classifyExit(exitCode int, stdout []byte, structured bool) string {
+	switch {
+	case exitCode == 124 || exitCode == 137:
+		return FailureReasonTimeout
+	case exitCode < 0 || exitCode > 128 || len(bytes.TrimSpace(stdout)) == 0:
+		return FailureReasonCrash
+	case structured && !hasJSONEvent(stdout):
+		return FailureReasonParseError
+	}
+	return FailureReasonGeneralError
+}
+
+// hasJSONEvent reports whether any line of stdout is a JSON object.
+func hasJSONEvent(stdout []byte) bool {
+	for _, line := range bytes.Split(stdout, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 && line[0] == '{' && json.Valid(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyRunFailure settles the failure reason of a failed run of an
+// adapter that parses into parseOutputResult. A provider error the parser
+// read wins, then the message and subtype, then denied tool calls, then
+// the exit itself.
+func classifyRunFailure(p parseOutputResult, exitCode int, stdout []byte) string {
+	if p.FailureReason != "" {
+		return p.FailureReason
+	}
+	if reason := ClassifyFailure(p.Subtype, p.ResultContent, nil); reason != FailureReasonGeneralError {
+		return reason
+	}
+	if len(p.DeniedTools) > 0 {
+		return FailureReasonToolDenied
+	}
+	if exitCode != 0 {
+		return classifyExit(exitCode, stdout, true)
 	}
 	return FailureReasonGeneralError
 }
@@ -96,6 +194,16 @@ func remediationFor(reason string) string {
 		return "The context window was exhausted. Consider breaking the task into smaller steps or adjusting relay compaction thresholds (relay.token_threshold_percent)."
 	case FailureReasonRateLimit:
 		return "API rate limit reached. Wait for the limit to reset and retry."
+	case FailureReasonQuota:
+		return "The provider's usage quota or credit is exhausted. Top up the account or switch the persona to another adapter."
+	case FailureReasonAuth:
+		return "The provider rejected the adapter's credentials. Log in to the CLI or check its API key environment variable and runtime.sandbox.env_passthrough."
+	case FailureReasonToolDenied:
+		return "The adapter denied a tool call the step needed. Check the persona's permissions.allowed_tools and permissions.deny."
+	case FailureReasonCrash:
+		return "The adapter process crashed. Check the raw adapter log and that the CLI runs on its own."
+	case FailureReasonParseError:
+		return "The adapter's output could not be parsed. Check that the CLI version supports Wave's output format flags."
 	case FailureReasonGeneralError:
 		return "Check the adapter output and logs for details."
 	default:
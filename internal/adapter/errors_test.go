@@ -85,6 +85,21 @@ func TestClassifyFailure(t *testing.T) {
 			resultContent: "No rate limiting on chat endpoints (cost amplification risk)",
 			want:          FailureReasonGeneralError,
 		},
+		{
+			name:          "exhausted quota returns quota",
+			resultContent: "You exceeded your current quota, please check your plan and billing details (insufficient_quota)",
+			want:          FailureReasonQuota,
+		},
+		{
+			name:          "invalid api key returns auth",
+			resultContent: "Invalid API key · Please run /login",
+			want:          FailureReasonAuth,
+		},
+		{
+			name:          "security review mentioning authentication is not auth",
+			resultContent: "The login handler does not rate-limit authentication attempts",
+			want:          FailureReasonGeneralError,
+		},
 		{
 			name:   "context canceled is not treated as timeout",
 			ctxErr: context.Canceled,
@@ -103,6 +118,73 @@ func TestClassifyFailure(t *testing.T) {
 	}
 }
 
+func TestClassifyAPIError(t *testing.T) {
+	tests := []struct {
+		errType string
+		status  int
+		want    string
+	}{
+		{"ProviderAuthError", 0, FailureReasonAuth},
+		{"APIError", 401, FailureReasonAuth},
+		{"insufficient_quota", 429, FailureReasonQuota},
+		{"APIError", 429, FailureReasonRateLimit},
+		{"FatalTurnLimitedError", 0, FailureReasonContextExhaustion},
+		{"APIError", 500, ""},
+	}
+	for _, tt := range tests {
+		if got := classifyAPIError(tt.errType, tt.status); got != tt.want {
+			t.Errorf("classifyAPIError(%q, %d) = %q, want %q", tt.errType, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyExit(t *testing.T) {
+	events := []byte(`{"type":"turn.started"}` + "\n")
+	tests := []struct {
+		name       string
+		exitCode   int
+		stdout     []byte
+		structured bool
+		want       string
+	}{
+		{"timeout exit code", 124, events, true, FailureReasonTimeout},
+		{"killed by SIGKILL", 137, events, true, FailureReasonTimeout},
+		{"killed by another signal", -1, events, true, FailureReasonCrash},
+		{"no output", 1, nil, true, FailureReasonCrash},
+		{"unparseable output", 1, []byte("panic: boom"), true, FailureReasonParseError},
+		{"plain output of an unstructured CLI", 1, []byte("error: boom"), false, FailureReasonGeneralError},
+		{"events without a named failure", 1, events, true, FailureReasonGeneralError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyExit(tt.exitCode, tt.stdout, tt.structured); got != tt.want {
+				t.Errorf("classifyExit() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyRunFailure(t *testing.T) {
+	stdout := []byte(`{"type":"result","subtype":"error_during_execution"}` + "\n")
+	tests := []struct {
+		name   string
+		parsed parseOutputResult
+		want   string
+	}{
+		{"provider error wins", parseOutputResult{FailureReason: FailureReasonAuth, Subtype: "error_max_turns"}, FailureReasonAuth},
+		{"message before denials", parseOutputResult{ResultContent: "Rate limit reached", DeniedTools: []string{"Bash"}}, FailureReasonRateLimit},
+		{"denied tool calls", parseOutputResult{Subtype: "error_during_execution", DeniedTools: []string{"Bash"}}, FailureReasonToolDenied},
+		{"falls back to the exit", parseOutputResult{Subtype: "error_during_execution"}, FailureReasonGeneralError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyRunFailure(tt.parsed, 1, stdout); got != tt.want {
+				t.Errorf("classifyRunFailure() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestStepErrorInterface(t *testing.T) {
 	cause := fmt.Errorf("underlying error")
 	se := NewStepError(FailureReasonTimeout, cause, 50000, "error_during_execution")
@@ -3,11 +3,22 @@ package adapter
 import (
 	"context"
 	"fmt"
+	"sync"
+)
+
+// Reasons a FallbackRunner moves on to the next adapter, as passed to
+// FallbackOptions.OnFallback.
+const (
+	FallbackReasonRateLimit     = "rate_limit"
+	FallbackReasonRepeatedExits = "repeated_exits"
+	FallbackReasonError         = "error"
 )
 
 // FallbackRunner wraps a primary AdapterRunner with a fallback chain.
 // When the primary fails with a rate_limit failure, it tries each
 // fallback adapter in order. Max attempts equals len(chain) + 1.
+// FallbackOptions.ExitThreshold also fails over from an adapter that
+// keeps exiting non-zero.
 //
 // The fallback chain is resolved through a Resolver, not the concrete
 // *AdapterRegistry, so tests can substitute a one-method fake.
@@ -15,49 +26,109 @@ type FallbackRunner struct {
 	primary  AdapterRunner
 	chain    []string // fallback adapter names in order
 	registry Resolver // for resolving fallback adapter names
+	opts     FallbackOptions
+}
+
+// FallbackOptions extends a FallbackRunner beyond failing over on rate
+// limits.
+type FallbackOptions struct {
+	// PrimaryName is the primary adapter's name, for Exits and OnFallback.
+	PrimaryName string
+	// ExitThreshold, when positive, also fails over from an adapter once
+	// it has exited non-zero this many times in a row. Below the threshold
+	// a non-zero exit is returned as is, since the work may be done anyway.
+	// An adapter already at the threshold is skipped, unless it is the
+	// last one left.
+	ExitThreshold int
+	// Exits counts the consecutive non-zero exits. Sharing one between the
+	// runners of a step's attempts makes its retries add up; nil counts
+	// within this runner only.
+	Exits *ExitCounter
+	// Configure adapts the run config for a fallback adapter, e.g. its
+	// model. It receives the primary's config without ExtraArgs.
+	Configure func(name string, cfg AdapterRunConfig) AdapterRunConfig
+	// OnFallback is called before a fallback adapter runs, with the
+	// adapter given up on and why.
+	OnFallback func(from, to, reason string)
+}
+
+// ExitCounter counts consecutive non-zero exits per adapter. The zero
+// value is ready to use, and it is safe for concurrent use.
+type ExitCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// record counts result's exit against name: a non-zero exit adds one, a
+// clean exit resets the count. A nil result (a hard error) counts nothing.
+func (c *ExitCounter) record(name string, result *AdapterResult) {
+	if result == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if result.ExitCode == 0 {
+		delete(c.counts, name)
+		return
+	}
+	if c.counts == nil {
+		c.counts = make(map[string]int)
+	}
+	c.counts[name]++
+}
+
+func (c *ExitCounter) count(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[name]
 }
 
 // NewFallbackRunner creates a FallbackRunner wrapping the primary runner
 // with the given fallback chain. The resolver is used to look up fallback
 // adapter names; *AdapterRegistry satisfies Resolver.
 func NewFallbackRunner(primary AdapterRunner, chain []string, registry Resolver) *FallbackRunner {
+	return NewFallbackRunnerWithOptions(primary, chain, registry, FallbackOptions{})
+}
+
+// NewFallbackRunnerWithOptions creates a FallbackRunner configured by opts.
+func NewFallbackRunnerWithOptions(primary AdapterRunner, chain []string, registry Resolver, opts FallbackOptions) *FallbackRunner {
+	if opts.Exits == nil {
+		opts.Exits = &ExitCounter{}
+	}
 	return &FallbackRunner{
 		primary:  primary,
 		chain:    chain,
 		registry: registry,
+		opts:     opts,
 	}
 }
 
-// Run executes the primary adapter first. On rate_limit failure, tries
-// each fallback adapter in chain order. Returns the first successful
-// result or the last error if all attempts fail.
+// Run executes the primary adapter first. On rate_limit failure (or
+// repeated non-zero exits, see FallbackOptions.ExitThreshold), tries each
+// fallback adapter in chain order. Returns the first successful result,
+// with ServedBy set when a fallback produced it, or the last error if all
+// attempts fail.
 func (f *FallbackRunner) Run(ctx context.Context, cfg AdapterRunConfig) (*AdapterResult, error) {
-	// Try primary adapter first
-	result, err := f.primary.Run(ctx, cfg)
-	if err == nil && !isFallbackTrigger(result) {
-		return result, nil
-	}
+	var result *AdapterResult
+	var err error
 
-	// Only fallback on rate_limit
-	if err != nil && result == nil {
-		// Hard error with no result — check if context cancelled
-		return nil, err
-	}
-	if result != nil && !isFallbackTrigger(result) {
-		return result, err
+	// Try primary adapter first, unless it keeps failing
+	from, reason := f.opts.PrimaryName, FallbackReasonRepeatedExits
+	if len(f.chain) == 0 || !f.exhausted(from) {
+		result, err = f.primary.Run(ctx, cfg)
+		f.opts.Exits.record(from, result)
+		reason = f.failoverReason(from, result)
+		if reason == "" {
+			// Success, a failure that is not a fallback trigger, or a hard
+			// error with no result
+			return result, err
+		}
 	}
 
 	// Try fallback chain
-	var lastErr error
-	var lastResult *AdapterResult
-	if err != nil {
-		lastErr = err
-	}
-	if result != nil {
-		lastResult = result
-	}
+	lastErr, lastResult := err, result
 
-	for _, fallbackName := range f.chain {
+	for i, fallbackName := range f.chain {
 		select {
 		case <-ctx.Done():
 			return lastResult, fmt.Errorf("fallback chain cancelled: %w", ctx.Err())
@@ -76,11 +147,36 @@ func (f *FallbackRunner) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapte
 			lastErr = fmt.Errorf("%w: %q (registry returned nil)", ErrUnknownAdapter, fallbackName)
 			continue
 		}
-		result, err = runner.Run(ctx, cfg)
-		if err == nil && !isFallbackTrigger(result) {
+		if i < len(f.chain)-1 && f.exhausted(fallbackName) {
+			from, reason = fallbackName, FallbackReasonRepeatedExits
+			continue
+		}
+
+		// ExtraArgs were validated against the primary adapter's options and
+		// would not mean the same thing, or anything, to another CLI.
+		fallbackCfg := cfg
+		fallbackCfg.ExtraArgs = nil
+		if f.opts.Configure != nil {
+			fallbackCfg = f.opts.Configure(fallbackName, fallbackCfg)
+		}
+		if f.opts.OnFallback != nil {
+			f.opts.OnFallback(from, fallbackName, reason)
+		}
+
+		result, err = runner.Run(ctx, fallbackCfg)
+		f.opts.Exits.record(fallbackName, result)
+		if result != nil {
+			result.ServedBy = fallbackName
+		}
+		reason = f.failoverReason(fallbackName, result)
+		if err == nil && reason == "" {
 			return result, nil
 		}
 
+		from = fallbackName
+		if reason == "" {
+			reason = FallbackReasonError
+		}
 		if err != nil {
 			lastErr = err
 		}
@@ -95,11 +191,28 @@ func (f *FallbackRunner) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapte
 	return lastResult, fmt.Errorf("all fallback adapters exhausted")
 }
 
+// failoverReason returns why name's result calls for the next adapter, or
+// "" when it does not.
+func (f *FallbackRunner) failoverReason(name string, result *AdapterResult) string {
+	switch {
+	case isFallbackTrigger(result):
+		return FallbackReasonRateLimit
+	case result != nil && result.ExitCode != 0 && f.exhausted(name):
+		return FallbackReasonRepeatedExits
+	}
+	return ""
+}
+
+// exhausted reports whether name has reached the exit threshold.
+func (f *FallbackRunner) exhausted(name string) bool {
+	return f.opts.ExitThreshold > 0 && f.opts.Exits.count(name) >= f.opts.ExitThreshold
+}
+
 // isFallbackTrigger returns true if the result indicates a rate limit
 // failure that should trigger fallback to the next provider.
 func isFallbackTrigger(result *AdapterResult) bool {
 	if result == nil {
 		return false
 	}
-	return result.FailureReason == "rate_limit"
+	return result.FailureReason == FailureReasonRateLimit
 }
@@ -73,6 +73,31 @@ func TestFallbackRunner_RateLimitTriggersFallback(t *testing.T) {
 	assert.Equal(t, 1, fallback.callCount)
 }
 
+// recordingRunner remembers the config it was run with.
+type recordingRunner struct {
+	cfg AdapterRunConfig
+}
+
+func (r *recordingRunner) Run(_ context.Context, cfg AdapterRunConfig) (*AdapterResult, error) {
+	r.cfg = cfg
+	return &AdapterResult{ResultContent: "success"}, nil
+}
+
+func TestFallbackRunner_DropsPrimaryAdapterOptions(t *testing.T) {
+	primary := &failingRunner{failureReason: "rate_limit"}
+	fallback := &recordingRunner{}
+
+	registry := NewAdapterRegistry(nil)
+	registry.RegisterOverride("codex", fallback)
+
+	fr := NewFallbackRunner(primary, []string{"codex"}, registry)
+	_, err := fr.Run(context.Background(), AdapterRunConfig{Prompt: "p", ExtraArgs: []string{"--max-turns", "5"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "p", fallback.cfg.Prompt)
+	assert.Nil(t, fallback.cfg.ExtraArgs)
+}
+
 func TestFallbackRunner_ContextExhaustionDoesNotTriggerFallback(t *testing.T) {
 	primary := &failingRunner{failureReason: "context_exhaustion"}
 	fallback := &successRunner{}
@@ -232,3 +257,96 @@ func TestIsFallbackTrigger(t *testing.T) {
 	assert.False(t, isFallbackTrigger(&AdapterResult{FailureReason: ""}))
 	assert.False(t, isFallbackTrigger(nil))
 }
+
+// exitRunner returns a result with each exit code in turn.
+type exitRunner struct {
+	codes     []int
+	callCount int
+}
+
+func (r *exitRunner) Run(_ context.Context, _ AdapterRunConfig) (*AdapterResult, error) {
+	code := r.codes[r.callCount%len(r.codes)]
+	r.callCount++
+	return &AdapterResult{ExitCode: code, ResultContent: fmt.Sprintf("exit %d", code)}, nil
+}
+
+func TestFallbackRunner_RepeatedExitsTriggerFallback(t *testing.T) {
+	primary := &exitRunner{codes: []int{1}}
+	codex := &recordingRunner{}
+	registry := NewAdapterRegistry(nil)
+	registry.RegisterOverride("codex", codex)
+
+	type fallback struct{ from, to, reason string }
+	var fallbacks []fallback
+	exits := &ExitCounter{}
+	newRunner := func() *FallbackRunner {
+		return NewFallbackRunnerWithOptions(primary, []string{"codex"}, registry, FallbackOptions{
+			PrimaryName:   "claude",
+			ExitThreshold: 2,
+			Exits:         exits,
+			Configure: func(name string, cfg AdapterRunConfig) AdapterRunConfig {
+				cfg.Adapter, cfg.Model = name, "gpt-5-codex"
+				return cfg
+			},
+			OnFallback: func(from, to, reason string) { fallbacks = append(fallbacks, fallback{from, to, reason}) },
+		})
+	}
+
+	// The first non-zero exit is returned so contracts can judge the work.
+	result, err := newRunner().Run(context.Background(), AdapterRunConfig{Adapter: "claude", Model: "opus"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ExitCode)
+	assert.Empty(t, result.ServedBy)
+	assert.Empty(t, fallbacks)
+
+	// The retry exits non-zero again, so codex takes over.
+	result, err = newRunner().Run(context.Background(), AdapterRunConfig{Adapter: "claude", Model: "opus"})
+	require.NoError(t, err)
+	assert.Equal(t, "codex", result.ServedBy)
+	assert.Equal(t, "gpt-5-codex", codex.cfg.Model)
+	assert.Equal(t, []fallback{{"claude", "codex", FallbackReasonRepeatedExits}}, fallbacks)
+	assert.Equal(t, 2, primary.callCount)
+
+	// From then on the primary is skipped.
+	result, err = newRunner().Run(context.Background(), AdapterRunConfig{Adapter: "claude"})
+	require.NoError(t, err)
+	assert.Equal(t, "codex", result.ServedBy)
+	assert.Equal(t, 2, primary.callCount)
+}
+
+func TestFallbackRunner_CleanExitResetsCount(t *testing.T) {
+	primary := &exitRunner{codes: []int{1, 0, 1}}
+	codex := &successRunner{}
+	registry := NewAdapterRegistry(nil)
+	registry.RegisterOverride("codex", codex)
+
+	fr := NewFallbackRunnerWithOptions(primary, []string{"codex"}, registry, FallbackOptions{
+		PrimaryName:   "claude",
+		ExitThreshold: 2,
+	})
+	for i := 0; i < 3; i++ {
+		_, err := fr.Run(context.Background(), AdapterRunConfig{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, primary.callCount)
+	assert.Equal(t, 0, codex.callCount, "exits separated by a clean run are not repeated")
+}
+
+func TestFallbackRunner_LastAdapterIsNeverSkipped(t *testing.T) {
+	primary := &exitRunner{codes: []int{1}}
+	codex := &exitRunner{codes: []int{1}}
+	registry := NewAdapterRegistry(nil)
+	registry.RegisterOverride("codex", codex)
+
+	fr := NewFallbackRunnerWithOptions(primary, []string{"codex"}, registry, FallbackOptions{
+		PrimaryName:   "claude",
+		ExitThreshold: 1,
+	})
+	for i := 0; i < 2; i++ {
+		result, err := fr.Run(context.Background(), AdapterRunConfig{})
+		require.Error(t, err)
+		assert.Equal(t, "codex", result.ServedBy)
+	}
+	assert.Equal(t, 1, primary.callCount)
+	assert.Equal(t, 2, codex.callCount)
+}
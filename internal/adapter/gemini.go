@@ -6,10 +6,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"os/exec"
-	"path/filepath"
-	"syscall"
+	"strings"
+
+	"github.com/recinq/wave/internal/procutil"
 )
 
 // GeminiAdapter runs the Google Gemini CLI as a subprocess.
@@ -45,9 +45,11 @@ func (a *GeminiAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapter
 		return nil, fmt.Errorf("provision gemini skills: %w", err)
 	}
 
-	if err := a.prepareWorkspace(workspacePath, cfg); err != nil {
+	restoreInstructions, err := a.prepareWorkspace(workspacePath, cfg)
+	if err != nil {
 		return nil, fmt.Errorf("failed to prepare gemini workspace: %w", err)
 	}
+	defer restoreInstructions()
 
 	args := a.buildArgs(cfg)
 	cmd := exec.CommandContext(ctx, a.geminiPath, args...)
@@ -59,7 +61,8 @@ func (a *GeminiAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapter
 	}
 
 	cmd.Env = BuildCuratedEnvironment(cfg)
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Pgid: 0}
+	procutil.SetProcessGroup(cmd)
+	cmd.Stderr = rawLog(cfg)
 
 	stdoutPipe, err := cmd.StdoutPipe()
 	if err != nil {
@@ -74,7 +77,7 @@ func (a *GeminiAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapter
 	stdoutDone := make(chan error, 1)
 
 	go func() {
-		scanner := bufio.NewScanner(stdoutPipe)
+		scanner := bufio.NewScanner(teeRawLog(stdoutPipe, cfg))
 		scanner.Buffer(make([]byte, 0, 1024*1024), 10*1024*1024)
 		for scanner.Scan() {
 			line := scanner.Bytes()
@@ -108,23 +111,22 @@ func (a *GeminiAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapter
 	if cmdErr != nil {
 		result.ExitCode = exitCodeFromError(cmdErr)
 		if result.FailureReason == "" {
-			result.FailureReason = classifyGeminiFailure(result.ExitCode)
+			result.FailureReason = classifyExit(result.ExitCode, stdoutBuf.Bytes(), true)
 		}
 	}
 	result.Stdout = bytes.NewReader(stdoutBuf.Bytes())
+	NormalizeUsage("gemini", cfg, stdoutBuf.Bytes(), result)
 
 	return result, nil
 }
 
-func (a *GeminiAdapter) prepareWorkspace(workspacePath string, cfg AdapterRunConfig) error {
-	// Write system prompt as GEMINI.md for context
-	if cfg.SystemPrompt != "" {
-		promptPath := filepath.Join(workspacePath, "GEMINI.md")
-		if err := os.WriteFile(promptPath, []byte(cfg.SystemPrompt), 0644); err != nil {
-			return fmt.Errorf("failed to write GEMINI.md: %w", err)
-		}
+// prepareWorkspace writes the system prompt as GEMINI.md for context. The
+// returned function restores the workspace's own GEMINI.md.
+func (a *GeminiAdapter) prepareWorkspace(workspacePath string, cfg AdapterRunConfig) (func(), error) {
+	if cfg.SystemPrompt == "" {
+		return func() {}, nil
 	}
-	return nil
+	return WriteInstructions(workspacePath, InstructionFilename("gemini"), []byte(cfg.SystemPrompt))
 }
 
 func (a *GeminiAdapter) buildArgs(cfg AdapterRunConfig) []string {
@@ -136,6 +138,7 @@ func (a *GeminiAdapter) buildArgs(cfg AdapterRunConfig) []string {
 
 	args = append(args, "--yolo")
 	args = append(args, "--output-format", "stream-json")
+	args = append(args, cfg.ExtraArgs...)
 
 	if cfg.Prompt != "" {
 		args = append(args, "-p", cfg.Prompt)
@@ -144,17 +147,98 @@ func (a *GeminiAdapter) buildArgs(cfg AdapterRunConfig) []string {
 	return args
 }
 
+// geminiEvent is one line of `gemini --output-format stream-json`:
+//
+//	{"type":"init","session_id":"...","model":"gemini-2.5-pro"}
+//	{"type":"message","role":"assistant","content":"...","delta":true}
+//	{"type":"tool_use","tool_name":"read_file","tool_id":"t1","parameters":{"file_path":"go.mod"}}
+//	{"type":"tool_result","tool_id":"t1","status":"success","output":"..."}
+//	{"type":"error","severity":"warning","message":"..."}
+//	{"type":"result","status":"success","stats":{"input_tokens":120,"output_tokens":40}}
+//
+// The name/input, text and usage fields are the shapes emitted by earlier
+// CLI releases, still accepted.
+type geminiEvent struct {
+	Type       string          `json:"type"`
+	Model      string          `json:"model"`
+	Role       string          `json:"role"`
+	Content    string          `json:"content"`
+	ToolName   string          `json:"tool_name"`
+	Parameters json.RawMessage `json:"parameters"`
+	Status     string          `json:"status"`
+	Output     string          `json:"output"`
+	Message    string          `json:"message"`
+	Error      *geminiError    `json:"error"`
+	Stats      *geminiUsage    `json:"stats"`
+
+	Name  string       `json:"name"`
+	Input string       `json:"input"`
+	Usage *geminiUsage `json:"usage"`
+}
+
+type geminiError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type geminiUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// usage returns the token counts of a result event.
+func (e *geminiEvent) usage() *geminiUsage {
+	if e.Stats != nil {
+		return e.Stats
+	}
+	return e.Usage
+}
+
+// geminiToolNames maps Gemini CLI built-in tools to the tool names the
+// executor and dashboard use for Claude, so stream_activity reads the same
+// for every adapter.
+var geminiToolNames = map[string]string{
+	"read_file":           "Read",
+	"read_many_files":     "Read",
+	"write_file":          "Write",
+	"replace":             "Edit",
+	"run_shell_command":   "Bash",
+	"glob":                "Glob",
+	"search_file_content": "Grep",
+	"list_directory":      "LS",
+	"web_fetch":           "WebFetch",
+	"google_web_search":   "WebSearch",
+}
+
+// geminiToolTarget summarises a Gemini tool call's parameters as the file
+// path, command, pattern or query it acts on.
+func geminiToolTarget(params json.RawMessage) string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(params, &fields); err != nil {
+		return ""
+	}
+	for _, field := range []string{"file_path", "absolute_path", "command", "pattern", "query", "url", "dir_path", "path", "prompt"} {
+		if val := jsonString(fields[field]); val != "" {
+			return val
+		}
+	}
+	return ""
+}
+
 func (a *GeminiAdapter) parseOutput(output string) *AdapterResult {
 	result := &AdapterResult{}
 
+	// The result is the assistant's text after its last tool call.
+	var reply strings.Builder
+	var errMsg string
 	lines := bytes.Split([]byte(output), []byte("\n"))
 	for _, line := range lines {
 		line = bytes.TrimSpace(line)
 		if len(line) == 0 {
 			continue
 		}
-		var obj map[string]json.RawMessage
-		if err := json.Unmarshal(line, &obj); err != nil {
+		var evt geminiEvent
+		if err := json.Unmarshal(line, &evt); err != nil {
 			// Gemini may output plain text — capture it as result content
 			if result.ResultContent == "" {
 				result.ResultContent = string(line)
@@ -162,42 +246,37 @@ func (a *GeminiAdapter) parseOutput(output string) *AdapterResult {
 			continue
 		}
 
-		var eventType string
-		if raw, ok := obj["type"]; ok {
-			_ = json.Unmarshal(raw, &eventType)
-		}
-
-		if eventType == "result" {
-			var resultEvt struct {
-				Usage struct {
-					InputTokens  int `json:"input_tokens"`
-					OutputTokens int `json:"output_tokens"`
-				} `json:"usage"`
-				Content string `json:"content"`
+		switch evt.Type {
+		case "message":
+			if evt.Role == "assistant" {
+				reply.WriteString(evt.Content)
 			}
-			if err := json.Unmarshal(line, &resultEvt); err == nil {
-				result.TokensIn = resultEvt.Usage.InputTokens
-				result.TokensOut = resultEvt.Usage.OutputTokens
+		case "tool_use":
+			reply.Reset()
+		case "result":
+			if u := evt.usage(); u != nil {
+				result.TokensIn = u.InputTokens
+				result.TokensOut = u.OutputTokens
 				result.TokensUsed = result.TokensIn + result.TokensOut
-				if resultEvt.Content != "" {
-					result.ResultContent = resultEvt.Content
-				}
-
-				var errEvt struct {
-					Status string `json:"status"`
-					Error  struct {
-						Type    string `json:"type"`
-						Message string `json:"message"`
-					} `json:"error"`
-				}
-				if err := json.Unmarshal(line, &errEvt); err == nil {
-					if errEvt.Status == "error" && errEvt.Error.Message != "" {
-						result.FailureReason = "adapter error: " + errEvt.Error.Message
-					}
+			}
+			if evt.Content != "" {
+				reply.Reset()
+				reply.WriteString(evt.Content)
+			}
+			if evt.Status == "error" && evt.Error != nil {
+				errMsg = evt.Error.Message
+				result.FailureReason = classifyAPIError(evt.Error.Type, 0)
+				if result.FailureReason == "" {
+					result.FailureReason = ClassifyFailure("", errMsg, nil)
 				}
 			}
 		}
 	}
+	if text := strings.TrimSpace(reply.String()); text != "" {
+		result.ResultContent = text
+	} else if errMsg != "" {
+		result.ResultContent = errMsg
+	}
 
 	return result
 }
@@ -209,80 +288,58 @@ func parseGeminiStreamLine(line []byte) (StreamEvent, bool) {
 		return StreamEvent{}, false
 	}
 
-	var obj map[string]json.RawMessage
-	if err := json.Unmarshal(line, &obj); err != nil {
+	var evt geminiEvent
+	if err := json.Unmarshal(line, &evt); err != nil {
 		return StreamEvent{}, false
 	}
 
-	var eventType string
-	if raw, ok := obj["type"]; ok {
-		if err := json.Unmarshal(raw, &eventType); err != nil {
-			return StreamEvent{}, false
+	switch evt.Type {
+	case "init":
+		if evt.Model != "" {
+			return StreamEvent{Type: "system", Content: "model: " + evt.Model}, true
 		}
-	}
-
-	switch eventType {
 	case "tool_use":
-		var tool struct {
-			Name  string `json:"name"`
-			Input string `json:"input"`
-		}
-		if err := json.Unmarshal(line, &tool); err == nil && tool.Name != "" {
-			input := tool.Input
-			if len(input) > 100 {
-				input = input[:100]
+		if evt.ToolName != "" {
+			name := evt.ToolName
+			if mapped, ok := geminiToolNames[name]; ok {
+				name = mapped
 			}
-			return StreamEvent{Type: "tool_use", ToolName: tool.Name, ToolInput: input}, true
+			return StreamEvent{Type: "tool_use", ToolName: name, ToolInput: truncateStreamText(geminiToolTarget(evt.Parameters), 100), ToolPaths: extractToolPaths(evt.Parameters)}, true
+		}
+		if evt.Name != "" {
+			return StreamEvent{Type: "tool_use", ToolName: evt.Name, ToolInput: truncateStreamText(evt.Input, 100), ToolPaths: toolPathsFromInput(evt.Input)}, true
+		}
+	case "tool_result":
+		if evt.Status == "error" && evt.Error != nil {
+			return StreamEvent{Type: "tool_result", Subtype: "error", Content: truncateStreamText(evt.Error.Message, 200)}, true
+		}
+		return StreamEvent{Type: "tool_result", Content: truncateStreamText(evt.Output, 200)}, true
+	case "message":
+		if evt.Role == "assistant" && evt.Content != "" {
+			return StreamEvent{Type: "text", Content: truncateStreamText(evt.Content, 200)}, true
 		}
 	case "text":
-		var text struct {
-			Content string `json:"content"`
+		if evt.Content != "" {
+			return StreamEvent{Type: "text", Content: truncateStreamText(evt.Content, 200)}, true
 		}
-		if err := json.Unmarshal(line, &text); err == nil && text.Content != "" {
-			content := text.Content
-			if len(content) > 200 {
-				content = content[:200]
-			}
-			return StreamEvent{Type: "text", Content: content}, true
+	case "error":
+		if evt.Message != "" {
+			return StreamEvent{Type: "system", Content: truncateStreamText(evt.Message, 200)}, true
 		}
 	case "result":
-		var errEvt struct {
-			Status string `json:"status"`
-			Error  struct {
-				Message string `json:"message"`
-			} `json:"error"`
+		if evt.Status == "error" && evt.Error != nil && evt.Error.Message != "" {
+			return StreamEvent{Type: "result", Subtype: "error_during_execution", Content: truncateStreamText(evt.Error.Message, 200)}, true
 		}
-		if err := json.Unmarshal(line, &errEvt); err == nil && errEvt.Status == "error" && errEvt.Error.Message != "" {
-			return StreamEvent{Type: "system", Content: errEvt.Error.Message}, true
+		res := StreamEvent{Type: "result", Content: evt.Content}
+		if evt.Status == "success" {
+			res.Subtype = "success"
 		}
-		var res struct {
-			Usage struct {
-				InputTokens  int `json:"input_tokens"`
-				OutputTokens int `json:"output_tokens"`
-			} `json:"usage"`
-			Content string `json:"content"`
-		}
-		if err := json.Unmarshal(line, &res); err == nil {
-			return StreamEvent{
-				Type:      "result",
-				TokensIn:  res.Usage.InputTokens,
-				TokensOut: res.Usage.OutputTokens,
-				Content:   res.Content,
-			}, true
+		if u := evt.usage(); u != nil {
+			res.TokensIn = u.InputTokens
+			res.TokensOut = u.OutputTokens
 		}
+		return res, true
 	}
 
 	return StreamEvent{}, false
 }
-
-// classifyGeminiFailure maps Gemini exit codes to failure reasons.
-func classifyGeminiFailure(exitCode int) string {
-	switch exitCode {
-	case 124, 137:
-		return "timeout"
-	case 1:
-		return "general_error"
-	default:
-		return "general_error"
-	}
-}
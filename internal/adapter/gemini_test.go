@@ -1,6 +1,7 @@
 package adapter
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -65,6 +66,20 @@ func TestGeminiAdapter_ParseOutput(t *testing.T) {
 			output:      "plain text response",
 			wantContent: "plain text response",
 		},
+		{
+			name: "stream-json run",
+			output: `{"type":"init","session_id":"s1","model":"gemini-2.5-pro"}
+{"type":"message","role":"user","content":"fix the bug"}
+{"type":"message","role":"assistant","content":"Reading the code.","delta":true}
+{"type":"tool_use","tool_name":"read_file","tool_id":"t1","parameters":{"file_path":"main.go"}}
+{"type":"tool_result","tool_id":"t1","status":"success","output":"package main"}
+{"type":"message","role":"assistant","content":"Fixed ","delta":true}
+{"type":"message","role":"assistant","content":"the bug.","delta":true}
+{"type":"result","status":"success","stats":{"total_tokens":170,"input_tokens":120,"output_tokens":50,"duration_ms":900,"tool_calls":1}}`,
+			wantIn:      120,
+			wantOut:     50,
+			wantContent: "Fixed the bug.",
+		},
 	}
 
 	for _, tt := range tests {
@@ -79,6 +94,16 @@ func TestGeminiAdapter_ParseOutput(t *testing.T) {
 	}
 }
 
+func TestGeminiAdapter_ParseOutput_Error(t *testing.T) {
+	result := NewGeminiAdapter().parseOutput(`{"type":"result","status":"error","error":{"type":"FatalTurnLimitedError","message":"turn limit reached"},"stats":{"input_tokens":10,"output_tokens":0}}`)
+	assert.Equal(t, FailureReasonContextExhaustion, result.FailureReason)
+	assert.Equal(t, "turn limit reached", result.ResultContent)
+	assert.Equal(t, 10, result.TokensIn)
+
+	result = NewGeminiAdapter().parseOutput(`{"type":"result","status":"error","error":{"type":"FatalAuthenticationError","message":"no credentials"}}`)
+	assert.Equal(t, FailureReasonAuth, result.FailureReason)
+}
+
 func TestParseGeminiStreamLine(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -90,7 +115,7 @@ func TestParseGeminiStreamLine(t *testing.T) {
 			name:    "tool_use event",
 			line:    `{"type":"tool_use","name":"WriteFile","input":"/tmp/bar"}`,
 			wantOK:  true,
-			wantEvt: StreamEvent{Type: "tool_use", ToolName: "WriteFile", ToolInput: "/tmp/bar"},
+			wantEvt: StreamEvent{Type: "tool_use", ToolName: "WriteFile", ToolInput: "/tmp/bar", ToolPaths: []string{"/tmp/bar"}},
 		},
 		{
 			name:    "text event",
@@ -104,6 +129,59 @@ func TestParseGeminiStreamLine(t *testing.T) {
 			wantOK:  true,
 			wantEvt: StreamEvent{Type: "result", TokensIn: 200, TokensOut: 80, Content: "done"},
 		},
+		{
+			name:    "stream-json tool_use maps to executor tool names",
+			line:    `{"type":"tool_use","tool_name":"run_shell_command","tool_id":"t1","parameters":{"command":"go test ./..."}}`,
+			wantOK:  true,
+			wantEvt: StreamEvent{Type: "tool_use", ToolName: "Bash", ToolInput: "go test ./..."},
+		},
+		{
+			name:    "stream-json tool_use keeps the full file path",
+			line:    `{"type":"tool_use","tool_name":"write_file","tool_id":"t3","parameters":{"file_path":"/work/` + strings.Repeat("d", 120) + `/../../etc/passwd"}}`,
+			wantOK:  true,
+			wantEvt: StreamEvent{Type: "tool_use", ToolName: "Write", ToolInput: ("/work/" + strings.Repeat("d", 120))[:100], ToolPaths: []string{"/work/" + strings.Repeat("d", 120) + "/../../etc/passwd"}},
+		},
+		{
+			name:    "stream-json tool_use keeps unknown tool names",
+			line:    `{"type":"tool_use","tool_name":"github__create_issue","tool_id":"t2","parameters":{"title":"x"}}`,
+			wantOK:  true,
+			wantEvt: StreamEvent{Type: "tool_use", ToolName: "github__create_issue"},
+		},
+		{
+			name:    "stream-json tool_result error",
+			line:    `{"type":"tool_result","tool_id":"t1","status":"error","error":{"type":"FILE_NOT_FOUND","message":"no such file"}}`,
+			wantOK:  true,
+			wantEvt: StreamEvent{Type: "tool_result", Subtype: "error", Content: "no such file"},
+		},
+		{
+			name:    "stream-json assistant message",
+			line:    `{"type":"message","role":"assistant","content":"Looking at main.go","delta":true}`,
+			wantOK:  true,
+			wantEvt: StreamEvent{Type: "text", Content: "Looking at main.go"},
+		},
+		{
+			name:   "stream-json user message",
+			line:   `{"type":"message","role":"user","content":"fix the bug"}`,
+			wantOK: false,
+		},
+		{
+			name:    "stream-json init",
+			line:    `{"type":"init","session_id":"s1","model":"gemini-2.5-pro"}`,
+			wantOK:  true,
+			wantEvt: StreamEvent{Type: "system", Content: "model: gemini-2.5-pro"},
+		},
+		{
+			name:    "stream-json result",
+			line:    `{"type":"result","status":"success","stats":{"input_tokens":120,"output_tokens":50}}`,
+			wantOK:  true,
+			wantEvt: StreamEvent{Type: "result", Subtype: "success", TokensIn: 120, TokensOut: 50},
+		},
+		{
+			name:    "stream-json failed result",
+			line:    `{"type":"result","status":"error","error":{"type":"FatalTurnLimitedError","message":"turn limit reached"}}`,
+			wantOK:  true,
+			wantEvt: StreamEvent{Type: "result", Subtype: "error_during_execution", Content: "turn limit reached"},
+		},
 		{
 			name:   "empty line",
 			line:   "",
@@ -121,20 +199,12 @@ func TestParseGeminiStreamLine(t *testing.T) {
 			evt, ok := parseGeminiStreamLine([]byte(tt.line))
 			assert.Equal(t, tt.wantOK, ok)
 			if ok {
-				assert.Equal(t, tt.wantEvt.Type, evt.Type)
-				assert.Equal(t, tt.wantEvt.ToolName, evt.ToolName)
+				assert.Equal(t, tt.wantEvt, evt)
 			}
 		})
 	}
 }
 
-func TestClassifyGeminiFailure(t *testing.T) {
-	assert.Equal(t, "timeout", classifyGeminiFailure(124))
-	assert.Equal(t, "timeout", classifyGeminiFailure(137))
-	assert.Equal(t, "general_error", classifyGeminiFailure(1))
-	assert.Equal(t, "general_error", classifyGeminiFailure(255))
-}
-
 func TestGeminiAdapter_PrepareWorkspace(t *testing.T) {
 	a := NewGeminiAdapter()
 	tmpDir := t.TempDir()
@@ -143,6 +213,7 @@ func TestGeminiAdapter_PrepareWorkspace(t *testing.T) {
 		SystemPrompt: "You are a helpful assistant",
 	}
 
-	err := a.prepareWorkspace(tmpDir, cfg)
+	restore, err := a.prepareWorkspace(tmpDir, cfg)
 	assert.NoError(t, err)
+	restore()
 }
@@ -370,6 +370,8 @@ func (a *GitHubAdapter) formatResult(data interface{}) (*AdapterResult, error) {
 		TokensUsed:    estimateTokens(string(jsonData)),
 		Artifacts:     []string{},
 	}
+	// Forge API calls use no model; the count only sizes the response.
+	result.TokensEstimated = true
 
 	return result, nil
 }
@@ -0,0 +1,268 @@
+package adapter
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// instructionFiles maps an adapter to the instruction file it reads from
+// the root of its working directory.
+var instructionFiles = map[string]string{
+	"claude":   "CLAUDE.md",
+	"gemini":   "GEMINI.md",
+	"opencode": "AGENTS.md",
+	"codex":    "AGENTS.md",
+	"cursor":   ".cursorrules",
+}
+
+// InstructionFilename returns the instruction file the named adapter reads,
+// defaulting to AGENTS.md.
+func InstructionFilename(adapterName string) string {
+	if name, ok := instructionFiles[strings.ToLower(adapterName)]; ok {
+		return name
+	}
+	return "AGENTS.md"
+}
+
+// instructionExcludeMarker precedes the info/exclude entry that keeps an
+// untracked instruction file out of "git add -A" while Wave manages it. The
+// exclude file is shared by every worktree of a repository, so each entry
+// is keyed by the worktree that owns it: the pattern stays in effect while
+// any worktree still holds an entry for it.
+const instructionExcludeMarker = "# wave: managed agent instructions, removed when the step ends"
+
+// instructionOverlay is an instruction file Wave manages for the length of
+// an adapter run. The generated content and a copy of the repository's own
+// file live in a temp overlay directory; the file in the workspace is the
+// generated content followed by the repository's own instructions.
+type instructionOverlay struct {
+	path     string // instruction file in the workspace
+	dir      string // temp overlay directory
+	original string // overlay copy of the repository's own file, "" when it had none
+	repo     string // git top level of the workspace, "" outside a repository
+	rel      string // path of the file relative to repo
+	skipBit  bool   // skip-worktree was set on the tracked file
+	excluded bool   // an info/exclude entry covers the untracked file
+	refs     int
+}
+
+var (
+	overlayMu      sync.Mutex
+	activeOverlays = make(map[string]*instructionOverlay)
+)
+
+// WriteInstructions installs content as the instruction file name in
+// workspacePath and returns the function that undoes it. Until then the
+// file cannot be staged: a tracked file gets git's skip-worktree bit and
+// an untracked one an info/exclude entry. A file the repository already
+// has is kept, appended under "Project Instructions", and restored
+// byte-for-byte by the returned function, which also removes a file the
+// repository did not have. Edits an agent makes to the file are discarded.
+// Steps sharing a workspace share the overlay; the last one restores it.
+func WriteInstructions(workspacePath, name string, content []byte) (func(), error) {
+	path, err := filepath.Abs(filepath.Join(workspacePath, name))
+	if err != nil {
+		return nil, err
+	}
+
+	overlayMu.Lock()
+	defer overlayMu.Unlock()
+
+	o, ok := activeOverlays[path]
+	if !ok {
+		if o, err = newInstructionOverlay(path); err != nil {
+			return nil, fmt.Errorf("failed to prepare %s overlay: %w", name, err)
+		}
+	}
+	if err := o.write(content); err != nil {
+		if !ok {
+			o.restore()
+		}
+		return nil, fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	o.refs++
+	activeOverlays[path] = o
+
+	var once sync.Once
+	return func() { once.Do(o.release) }, nil
+}
+
+// newInstructionOverlay records the repository's own copy of path, if
+// any, and keeps path out of the index.
+func newInstructionOverlay(path string) (*instructionOverlay, error) {
+	dir, err := os.MkdirTemp("", "wave-instructions-")
+	if err != nil {
+		return nil, err
+	}
+	o := &instructionOverlay{path: path, dir: dir}
+	o.repo, o.rel = gitLocation(path)
+
+	own, err := readOptional(path)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+	if o.repo != "" {
+		switch out, _ := o.git("ls-files", "-v", "--", o.rel); {
+		case bytes.HasPrefix(out, []byte("S ")):
+			// Left over from a run that never restored: the index holds
+			// the repository's file.
+			o.skipBit = true
+			if idx, err := o.git("show", ":"+o.rel); err == nil {
+				own = idx
+			}
+		case len(out) > 0:
+			if _, err := o.git("update-index", "--skip-worktree", "--", o.rel); err == nil {
+				o.skipBit = true
+			}
+		default:
+			// Entries of other worktrees are theirs to remove; only this
+			// worktree's own entry marks its file as a leftover.
+			exclude := o.excludePath()
+			data, _ := os.ReadFile(exclude)
+			if bytes.Contains(data, []byte(o.excludeEntry())) {
+				own = nil // left over from a run that never restored
+			} else if err := appendFile(exclude, o.excludeEntry()); err != nil {
+				_ = os.RemoveAll(dir)
+				return nil, err
+			}
+			o.excluded = true
+		}
+	}
+
+	if own != nil {
+		o.original = filepath.Join(dir, filepath.Base(path)+".orig")
+		if err := os.WriteFile(o.original, own, 0644); err != nil {
+			o.restore()
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// write generates content into the overlay and installs it, followed by
+// the repository's own instructions, into the workspace.
+func (o *instructionOverlay) write(content []byte) error {
+	generated := filepath.Join(o.dir, filepath.Base(o.path))
+	if err := os.WriteFile(generated, content, 0644); err != nil {
+		return err
+	}
+	if o.original != "" {
+		own, err := os.ReadFile(o.original)
+		if err != nil {
+			return err
+		}
+		content = append(append(bytes.TrimRight(content, "\n"), "\n\n## Project Instructions\n\n"...), own...)
+	}
+	return os.WriteFile(o.path, content, 0644)
+}
+
+// release drops one reference and restores the workspace after the last.
+func (o *instructionOverlay) release() {
+	overlayMu.Lock()
+	defer overlayMu.Unlock()
+	if o.refs--; o.refs > 0 {
+		return
+	}
+	delete(activeOverlays, o.path)
+	o.restore()
+}
+
+// restore puts the repository's own file back, or removes Wave's, and
+// undoes the git settings that kept it out of the index.
+func (o *instructionOverlay) restore() {
+	if o.original != "" {
+		if own, err := os.ReadFile(o.original); err == nil {
+			_ = os.WriteFile(o.path, own, 0644)
+		}
+	} else {
+		_ = os.Remove(o.path)
+	}
+	if o.skipBit {
+		_, _ = o.git("update-index", "--no-skip-worktree", "--", o.rel)
+	}
+	if o.excluded {
+		exclude := o.excludePath()
+		if data, err := os.ReadFile(exclude); err == nil {
+			_ = os.WriteFile(exclude, bytes.Replace(data, []byte(o.excludeEntry()), nil, 1), 0644)
+		}
+	}
+	_ = os.RemoveAll(o.dir)
+}
+
+func (o *instructionOverlay) git(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", o.repo}, args...)...)
+	return cmd.Output()
+}
+
+// excludePath returns the repository's info/exclude file, shared by all of
+// its worktrees.
+func (o *instructionOverlay) excludePath() string {
+	out, err := o.git("rev-parse", "--git-path", "info/exclude")
+	p := strings.TrimSpace(string(out))
+	if err != nil || p == "" {
+		return filepath.Join(o.repo, ".git", "info", "exclude")
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(o.repo, p)
+	}
+	return p
+}
+
+// excludeEntry returns this worktree's info/exclude entry for the file.
+func (o *instructionOverlay) excludeEntry() string {
+	return instructionExcludeMarker + " (" + o.repo + ")\n/" + o.rel + "\n"
+}
+
+// gitLocation returns the git top level containing path and path relative
+// to it, with forward slashes. Both are empty outside a repository.
+func gitLocation(path string) (repo, rel string) {
+	cmd := exec.Command("git", "-C", filepath.Dir(path), "rev-parse", "--show-toplevel")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", ""
+	}
+	repo = strings.TrimSpace(string(out))
+	if resolved, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		path = filepath.Join(resolved, filepath.Base(path))
+	}
+	r, err := filepath.Rel(repo, path)
+	if err != nil || strings.HasPrefix(r, "..") {
+		return "", ""
+	}
+	return repo, filepath.ToSlash(r)
+}
+
+// readOptional reads path, returning nil without error when it does not
+// exist.
+func readOptional(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// appendFile appends s to path on a line of its own, creating the file.
+func appendFile(path, s string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if data, err := os.ReadFile(path); err == nil && len(data) > 0 && data[len(data)-1] != '\n' {
+		s = "\n" + s
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(s); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,170 @@
+package adapter
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gitOut runs git in dir and returns its trimmed output.
+func gitOut(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+func TestInstructionFilename(t *testing.T) {
+	assert.Equal(t, "CLAUDE.md", InstructionFilename("claude"))
+	assert.Equal(t, "GEMINI.md", InstructionFilename("Gemini"))
+	assert.Equal(t, "AGENTS.md", InstructionFilename("codex"))
+	assert.Equal(t, "AGENTS.md", InstructionFilename("opencode"))
+	assert.Equal(t, ".cursorrules", InstructionFilename("cursor"))
+	assert.Equal(t, "AGENTS.md", InstructionFilename("custom"))
+}
+
+func TestWriteInstructions_OutsideRepository(t *testing.T) {
+	dir := t.TempDir()
+	restore, err := WriteInstructions(dir, "AGENTS.md", []byte("You are a reviewer"))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "AGENTS.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "You are a reviewer", string(data))
+
+	restore()
+	assert.NoFileExists(t, filepath.Join(dir, "AGENTS.md"))
+	restore() // idempotent
+}
+
+func TestWriteInstructions_TrackedFile(t *testing.T) {
+	dir := initCacheRepo(t)
+	own := "# Project\n\nUse tabs.\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "AGENTS.md"), []byte(own), 0o644))
+	gitOut(t, dir, "add", "AGENTS.md")
+	gitOut(t, dir, "commit", "-q", "-m", "instructions")
+
+	restore, err := WriteInstructions(dir, "AGENTS.md", []byte("You are a reviewer\n"))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "AGENTS.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "You are a reviewer\n\n## Project Instructions\n\n"+own, string(data))
+
+	gitOut(t, dir, "add", "-A")
+	assert.Empty(t, gitOut(t, dir, "status", "--porcelain"), "managed file must not be staged")
+
+	restore()
+	data, err = os.ReadFile(filepath.Join(dir, "AGENTS.md"))
+	require.NoError(t, err)
+	assert.Equal(t, own, string(data))
+	assert.Equal(t, "H AGENTS.md", gitOut(t, dir, "ls-files", "-v", "AGENTS.md"), "skip-worktree bit is cleared")
+}
+
+func TestWriteInstructions_UntrackedFile(t *testing.T) {
+	dir := initCacheRepo(t)
+
+	restore, err := WriteInstructions(dir, "GEMINI.md", []byte("You are a reviewer"))
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "GEMINI.md"))
+
+	gitOut(t, dir, "add", "-A")
+	assert.Empty(t, gitOut(t, dir, "status", "--porcelain"), "managed file must not be staged")
+
+	restore()
+	assert.NoFileExists(t, filepath.Join(dir, "GEMINI.md"))
+	exclude, err := os.ReadFile(filepath.Join(dir, ".git", "info", "exclude"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(exclude), "GEMINI.md")
+}
+
+func TestWriteInstructions_UntrackedOwnFileRestored(t *testing.T) {
+	dir := initCacheRepo(t)
+	own := "local notes"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".cursorrules"), []byte(own), 0o644))
+
+	restore, err := WriteInstructions(dir, ".cursorrules", []byte("rules"))
+	require.NoError(t, err)
+	restore()
+
+	data, err := os.ReadFile(filepath.Join(dir, ".cursorrules"))
+	require.NoError(t, err)
+	assert.Equal(t, own, string(data))
+	assert.Equal(t, "?? .cursorrules", gitOut(t, dir, "status", "--porcelain"))
+}
+
+func TestWriteInstructions_SharedWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	first, err := WriteInstructions(dir, "AGENTS.md", []byte("first"))
+	require.NoError(t, err)
+	second, err := WriteInstructions(dir, "AGENTS.md", []byte("second"))
+	require.NoError(t, err)
+
+	first()
+	data, err := os.ReadFile(filepath.Join(dir, "AGENTS.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data), "file stays while a step still uses it")
+
+	second()
+	assert.NoFileExists(t, filepath.Join(dir, "AGENTS.md"))
+}
+
+func TestWriteInstructions_LeftoverSkipWorktree(t *testing.T) {
+	dir := initCacheRepo(t)
+	own := "# Project\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "AGENTS.md"), []byte(own), 0o644))
+	gitOut(t, dir, "add", "AGENTS.md")
+	gitOut(t, dir, "commit", "-q", "-m", "instructions")
+
+	// A run that never restored left its file behind the skip-worktree bit.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "AGENTS.md"), []byte("stale persona"), 0o644))
+	gitOut(t, dir, "update-index", "--skip-worktree", "--", "AGENTS.md")
+
+	restore, err := WriteInstructions(dir, "AGENTS.md", []byte("persona"))
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, "AGENTS.md"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "stale persona")
+
+	restore()
+	data, err = os.ReadFile(filepath.Join(dir, "AGENTS.md"))
+	require.NoError(t, err)
+	assert.Equal(t, own, string(data))
+	assert.Empty(t, gitOut(t, dir, "status", "--porcelain"))
+}
+
+func TestWriteInstructions_WorktreesShareExclude(t *testing.T) {
+	repo := initCacheRepo(t)
+	other := filepath.Join(t.TempDir(), "other")
+	gitOut(t, repo, "worktree", "add", "-q", "--detach", other)
+
+	first, err := WriteInstructions(repo, "AGENTS.md", []byte("first"))
+	require.NoError(t, err)
+	second, err := WriteInstructions(other, "AGENTS.md", []byte("second"))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(other, "AGENTS.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+
+	second()
+	assert.NoFileExists(t, filepath.Join(other, "AGENTS.md"))
+	data, err = os.ReadFile(filepath.Join(repo, "AGENTS.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(data), "another worktree's overlay must not remove this one's file")
+	gitOut(t, repo, "add", "-A")
+	assert.Empty(t, gitOut(t, repo, "status", "--porcelain"), "file stays excluded while its overlay holds it")
+
+	first()
+	assert.NoFileExists(t, filepath.Join(repo, "AGENTS.md"))
+	exclude, err := os.ReadFile(filepath.Join(repo, ".git", "info", "exclude"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(exclude), "AGENTS.md")
+}
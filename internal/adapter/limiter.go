@@ -0,0 +1,139 @@
+package adapter
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// AdapterLimits bounds how hard Wave drives one adapter, so parallel steps
+// and matrix workers do not trip the provider's own rate limits.
+type AdapterLimits struct {
+	MaxConcurrent     int // adapter processes running at once; 0 is unlimited
+	RequestsPerMinute int // adapter invocations started per minute; 0 is unlimited
+}
+
+// Limiter enforces AdapterLimits per adapter name. One Limiter is shared by
+// every runner resolved through the same AdapterRegistry, so the limits hold
+// across concurrently executing steps, matrix workers and child pipelines.
+// It is safe for concurrent use.
+type Limiter struct {
+	now    func() time.Time
+	limits map[string]*adapterLimit
+}
+
+// adapterLimit is one adapter's concurrency slots and start-rate bucket.
+type adapterLimit struct {
+	slots chan struct{} // nil when concurrency is unlimited
+
+	mu       sync.Mutex
+	tokens   float64
+	capacity float64
+	perSec   float64 // zero when the start rate is unlimited
+	last     time.Time
+}
+
+// NewLimiter creates a Limiter for the given per-adapter limits. Adapters
+// without limits, or with only zero limits, are not throttled.
+func NewLimiter(limits map[string]AdapterLimits) *Limiter {
+	l := &Limiter{now: time.Now, limits: make(map[string]*adapterLimit)}
+	for name, lim := range limits {
+		if lim.MaxConcurrent <= 0 && lim.RequestsPerMinute <= 0 {
+			continue
+		}
+		al := &adapterLimit{}
+		if lim.MaxConcurrent > 0 {
+			al.slots = make(chan struct{}, lim.MaxConcurrent)
+		}
+		if lim.RequestsPerMinute > 0 {
+			al.capacity = float64(lim.RequestsPerMinute)
+			al.tokens = al.capacity
+			al.perSec = float64(lim.RequestsPerMinute) / 60
+		}
+		l.limits[name] = al
+	}
+	return l
+}
+
+// Limits reports whether name has any limit configured.
+func (l *Limiter) Limits(name string) bool {
+	if l == nil {
+		return false
+	}
+	_, ok := l.limits[name]
+	return ok
+}
+
+// Acquire blocks until name may start another invocation: a concurrency
+// slot is free and the start rate allows it. The returned release frees
+// the slot and must be called once the invocation ends. Acquire returns
+// ctx's error if ctx ends first.
+func (l *Limiter) Acquire(ctx context.Context, name string) (release func(), err error) {
+	al, ok := l.limits[name]
+	if !ok {
+		return func() {}, nil
+	}
+
+	release = func() {}
+	if al.slots != nil {
+		select {
+		case al.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		var once sync.Once
+		release = func() { once.Do(func() { <-al.slots }) }
+	}
+
+	for {
+		wait := al.take(l.now())
+		if wait <= 0 {
+			return release, nil
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			release()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// take removes a token from the start-rate bucket. When the bucket is empty
+// it returns how long until the next token is available.
+func (al *adapterLimit) take(now time.Time) time.Duration {
+	if al.perSec == 0 {
+		return 0
+	}
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	if !al.last.IsZero() {
+		al.tokens = math.Min(al.capacity, al.tokens+now.Sub(al.last).Seconds()*al.perSec)
+	}
+	al.last = now
+	if al.tokens >= 1 {
+		al.tokens--
+		return 0
+	}
+	return time.Duration(math.Ceil((1 - al.tokens) / al.perSec * float64(time.Second)))
+}
+
+// limitedRunner runs an adapter within its Limiter budget.
+type limitedRunner struct {
+	name    string
+	runner  AdapterRunner
+	limiter *Limiter
+}
+
+// Run waits for the adapter's budget, then runs it.
+func (r *limitedRunner) Run(ctx context.Context, cfg AdapterRunConfig) (*AdapterResult, error) {
+	release, err := r.limiter.Acquire(ctx, r.name)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return r.runner.Run(ctx, cfg)
+}
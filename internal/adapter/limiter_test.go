@@ -0,0 +1,119 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gatedRunner blocks each Run until release is closed and tracks how many
+// runs were in flight at once.
+type gatedRunner struct {
+	release  chan struct{}
+	started  chan struct{}
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func (g *gatedRunner) Run(ctx context.Context, _ AdapterRunConfig) (*AdapterResult, error) {
+	n := g.inFlight.Add(1)
+	defer g.inFlight.Add(-1)
+	for {
+		p := g.peak.Load()
+		if n <= p || g.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	g.started <- struct{}{}
+	<-g.release
+	return &AdapterResult{}, nil
+}
+
+func TestLimiter_MaxConcurrent(t *testing.T) {
+	gated := &gatedRunner{release: make(chan struct{}), started: make(chan struct{}, 8)}
+	registry := NewSingleRunnerRegistry(gated)
+	registry.SetLimiter(NewLimiter(map[string]AdapterLimits{"claude": {MaxConcurrent: 2}}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = registry.Resolve("claude").Run(context.Background(), AdapterRunConfig{})
+		}()
+	}
+	<-gated.started
+	<-gated.started
+	select {
+	case <-gated.started:
+		t.Fatal("a third claude process started while two were running")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(gated.release)
+	wg.Wait()
+
+	if peak := gated.peak.Load(); peak != 2 {
+		t.Errorf("peak concurrency = %d, want 2", peak)
+	}
+}
+
+func TestLimiter_UnlimitedAdapterIsNotWrapped(t *testing.T) {
+	plain := &gatedRunner{}
+	registry := NewSingleRunnerRegistry(plain)
+	registry.SetLimiter(NewLimiter(map[string]AdapterLimits{"claude": {MaxConcurrent: 1}, "codex": {}}))
+
+	if got := registry.Resolve("codex"); got != AdapterRunner(plain) {
+		t.Errorf("Resolve(codex) = %T, want the plain runner", got)
+	}
+	if got, _ := registry.ResolveStrict("claude"); got == AdapterRunner(plain) {
+		t.Error("ResolveStrict(claude) returned the runner without its limit")
+	}
+}
+
+func TestLimiter_RequestsPerMinute(t *testing.T) {
+	l := NewLimiter(map[string]AdapterLimits{"claude": {RequestsPerMinute: 2}})
+	now := time.Unix(0, 0)
+	l.now = func() time.Time { return now }
+	al := l.limits["claude"]
+
+	if al.take(now) != 0 || al.take(now) != 0 {
+		t.Fatal("the first two starts in a minute should not wait")
+	}
+	if wait := al.take(now); wait != 30*time.Second {
+		t.Errorf("third start waits %v, want 30s", wait)
+	}
+	now = now.Add(30 * time.Second)
+	if wait := al.take(now); wait != 0 {
+		t.Errorf("start after 30s waits %v, want none", wait)
+	}
+}
+
+func TestLimiter_AcquireHonoursContext(t *testing.T) {
+	l := NewLimiter(map[string]AdapterLimits{"claude": {MaxConcurrent: 1, RequestsPerMinute: 1}})
+
+	release, err := l.Acquire(context.Background(), "claude")
+	if err != nil {
+		t.Fatalf("first Acquire: %v", err)
+	}
+
+	// Waiting for a slot.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx, "claude"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Acquire with no free slot = %v, want deadline exceeded", err)
+	}
+
+	// Waiting for the start rate: the slot must be handed back on cancel.
+	release()
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel2()
+	if _, err := l.Acquire(ctx2, "claude"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Acquire over the start rate = %v, want deadline exceeded", err)
+	}
+	if n := len(l.limits["claude"].slots); n != 0 {
+		t.Errorf("%d slots held after a cancelled Acquire, want 0", n)
+	}
+}
@@ -0,0 +1,347 @@
+package adapter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/recinq/wave/internal/cost"
+	"github.com/recinq/wave/internal/httpx"
+)
+
+// DefaultOllamaHost is the address of a local Ollama server when
+// OLLAMA_HOST is not set.
+const DefaultOllamaHost = "http://127.0.0.1:11434"
+
+// maxOllamaInlinedArtifacts caps the bytes of injected artifacts inlined
+// into the prompt. Local models have small context windows; what does not
+// fit is listed by path only.
+const maxOllamaInlinedArtifacts = 256 * 1024
+
+// ollamaHTTPClient talks to the Ollama server. Generation on local
+// hardware can take many minutes, so the step timeout (applied through the
+// request context) bounds the call rather than the client timeout. Requests
+// are sent once: retrying a half-streamed generation would duplicate work.
+var ollamaHTTPClient = httpx.New(httpx.Config{
+	Timeout:       24 * time.Hour,
+	RetryBaseWait: time.Second,
+})
+
+// ollamaTierNames are the model tiers the executor resolves through the
+// adapter's tier_models. One reaching the adapter means no mapping exists.
+var ollamaTierNames = map[string]bool{"cheapest": true, "balanced": true, "strongest": true}
+
+// OllamaAdapter runs a prompt against a model served by a local Ollama
+// server through its chat API, so pipelines can run without network
+// access. The model has no tools: it cannot read or write the workspace,
+// so injected artifacts are inlined into the prompt and the reply becomes
+// the step's output.
+type OllamaAdapter struct{}
+
+// NewOllamaAdapter creates an OllamaAdapter.
+func NewOllamaAdapter() *OllamaAdapter {
+	return &OllamaAdapter{}
+}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Options  map[string]any  `json:"options,omitempty"`
+}
+
+// ollamaChatChunk is one NDJSON line of a streamed /api/chat response.
+// The final chunk has Done set and carries the token counts.
+type ollamaChatChunk struct {
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	DoneReason      string        `json:"done_reason"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+	Error           string        `json:"error"`
+}
+
+func (a *OllamaAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*AdapterResult, error) {
+	var cancel context.CancelFunc
+	if cfg.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	if err := validateOllamaModel(cfg.Model); err != nil {
+		return nil, err
+	}
+
+	messages := buildOllamaMessages(cfg)
+	body, err := json.Marshal(ollamaChatRequest{
+		Model:    cfg.Model,
+		Messages: messages,
+		Stream:   true,
+		Options:  ollamaOptions(cfg),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ollama request: %w", err)
+	}
+
+	host := ollamaHost(cfg.Env)
+	endpoint := host + "/api/chat"
+	if cfg.Debug {
+		fmt.Printf("[DEBUG] Ollama request: POST %s model=%s\n", endpoint, cfg.Model)
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ollama request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ollamaHTTPClient.Do(ctx, req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to reach ollama at %s (is `ollama serve` running?): %w", host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(teeRawLog(resp.Body, cfg))
+		return nil, ollamaStatusError(resp.StatusCode, buf.Bytes(), cfg.Model)
+	}
+
+	result, err := readOllamaStream(teeRawLog(resp.Body, cfg), cfg.OnStreamEvent)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	// Ollama omits prompt_eval_count when the prompt was cached, so each
+	// missing side is estimated on its own.
+	if result.TokensIn == 0 || result.TokensOut == 0 {
+		tok := cost.TokenizerFor("ollama", cfg.Model)
+		if result.TokensIn == 0 {
+			for _, m := range messages {
+				result.TokensIn += tok.Count(m.Content)
+			}
+		}
+		if result.TokensOut == 0 {
+			result.TokensOut = tok.Count(result.ResultContent)
+		}
+		result.TokensEstimated = true
+	}
+	result.TokensUsed = result.TokensIn + result.TokensOut
+	if cfg.OnStreamEvent != nil {
+		cfg.OnStreamEvent(StreamEvent{Type: "result", TokensIn: result.TokensIn, TokensOut: result.TokensOut, Subtype: result.Subtype})
+	}
+	return result, nil
+}
+
+// validateOllamaModel rejects a step without a concrete model: Ollama has
+// no default model, and tier names must be mapped to local models.
+func validateOllamaModel(model string) error {
+	switch {
+	case model == "" || model == "default":
+		return errors.New("ollama adapter requires a model: set model on the persona or step, or default_model on the adapter")
+	case ollamaTierNames[model]:
+		return fmt.Errorf("ollama adapter cannot use model tier %q: map it to a local model in the adapter's tier_models", model)
+	}
+	return nil
+}
+
+// ollamaHost returns the Ollama server address: OLLAMA_HOST from the step
+// environment, else from the process environment, else DefaultOllamaHost.
+// Like the ollama CLI, it accepts a bare host:port.
+func ollamaHost(env []string) string {
+	host := ""
+	for _, kv := range env {
+		if v, ok := strings.CutPrefix(kv, "OLLAMA_HOST="); ok {
+			host = v
+		}
+	}
+	if host == "" {
+		host = os.Getenv("OLLAMA_HOST")
+	}
+	if host == "" {
+		return DefaultOllamaHost
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	return strings.TrimRight(host, "/")
+}
+
+func ollamaOptions(cfg AdapterRunConfig) map[string]any {
+	if cfg.Temperature == 0 && !cfg.TemperatureSet {
+		return nil
+	}
+	return map[string]any{"temperature": cfg.Temperature}
+}
+
+// buildOllamaMessages assembles the system and user messages. The system
+// prompt is the persona's, followed by a note that the model must answer
+// with the output itself since it cannot use tools.
+func buildOllamaMessages(cfg AdapterRunConfig) []ollamaMessage {
+	var system strings.Builder
+	if cfg.SystemPrompt != "" {
+		system.WriteString(strings.TrimSpace(cfg.SystemPrompt))
+		system.WriteString("\n\n")
+	}
+	system.WriteString("You are running without tools: you cannot read or write files or run commands. " +
+		"Any files you need are included in the request. " +
+		"Reply with the complete content of the requested output and nothing else.")
+
+	user := cfg.Prompt
+	if inlined := inlineOllamaArtifacts(cfg.WorkspacePath); inlined != "" {
+		user = strings.TrimRight(user, "\n") + "\n\n" + inlined
+	}
+	return []ollamaMessage{
+		{Role: "system", Content: system.String()},
+		{Role: "user", Content: user},
+	}
+}
+
+// inlineOllamaArtifacts renders the text files under the workspace's
+// .agents/artifacts directory, where injected artifacts land, so the model
+// can see what the prompt refers to by path. Binary files are skipped and
+// the total is capped at maxOllamaInlinedArtifacts.
+func inlineOllamaArtifacts(workspacePath string) string {
+	if workspacePath == "" {
+		return ""
+	}
+	root := filepath.Join(workspacePath, ".agents", "artifacts")
+	var b strings.Builder
+	var omitted []string
+	budget := maxOllamaInlinedArtifacts
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(workspacePath, path)
+		rel = filepath.ToSlash(rel)
+		data, err := os.ReadFile(path)
+		if err != nil || !utf8.Valid(data) || bytes.IndexByte(data, 0) >= 0 {
+			return nil
+		}
+		if len(data) > budget {
+			omitted = append(omitted, rel)
+			return nil
+		}
+		budget -= len(data)
+		fmt.Fprintf(&b, "<file path=%q>\n%s\n</file>\n", rel, strings.TrimRight(string(data), "\n"))
+		return nil
+	})
+	if b.Len() == 0 && len(omitted) == 0 {
+		return ""
+	}
+	var out strings.Builder
+	out.WriteString("## Injected files\n\n")
+	out.WriteString(b.String())
+	if len(omitted) > 0 {
+		fmt.Fprintf(&out, "\nNot included (too large): %s\n", strings.Join(omitted, ", "))
+	}
+	return out.String()
+}
+
+// readOllamaStream reads a streamed /api/chat response, accumulating the
+// reply and emitting it as text events a line (or a few hundred
+// characters) at a time.
+func readOllamaStream(r io.Reader, onEvent func(StreamEvent)) (*AdapterResult, error) {
+	var raw, reply, pending bytes.Buffer
+	flush := func() {
+		if onEvent != nil && strings.TrimSpace(pending.String()) != "" {
+			onEvent(StreamEvent{Type: "text", Content: truncateStreamText(strings.TrimSpace(pending.String()), 200)})
+		}
+		pending.Reset()
+	}
+
+	result := &AdapterResult{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	done := false
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		raw.Write(line)
+		raw.WriteByte('\n')
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var chunk ollamaChatChunk
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			continue
+		}
+		if chunk.Error != "" {
+			return nil, fmt.Errorf("ollama: %s", chunk.Error)
+		}
+		reply.WriteString(chunk.Message.Content)
+		pending.WriteString(chunk.Message.Content)
+		if strings.Contains(chunk.Message.Content, "\n") || pending.Len() >= 200 {
+			flush()
+		}
+		if chunk.Done {
+			done = true
+			result.TokensIn = chunk.PromptEvalCount
+			result.TokensOut = chunk.EvalCount
+			if chunk.DoneReason == "length" {
+				// The reply hit the model's context or num_predict limit
+				// and is cut off.
+				result.ExitCode = 1
+				result.FailureReason = FailureReasonContextExhaustion
+				result.Subtype = "error_during_execution"
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ollama response: %w", err)
+	}
+	if !done {
+		return nil, errors.New("ollama response ended before the reply was complete")
+	}
+	flush()
+
+	result.ResultContent = reply.String()
+	result.Stdout = bytes.NewReader(raw.Bytes())
+	if result.Subtype == "" {
+		result.Subtype = "success"
+	}
+	return result, nil
+}
+
+// ollamaStatusError turns a non-200 response into an error carrying the
+// server's message. A 404 means the model has not been pulled; statuses
+// that name a failure reason (auth, rate limit) return a StepError.
+func ollamaStatusError(status int, body []byte, model string) error {
+	msg := strings.TrimSpace(string(body))
+	var parsed struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &parsed) == nil && parsed.Error != "" {
+		msg = parsed.Error
+	}
+	if status == http.StatusNotFound {
+		return fmt.Errorf("ollama: %s (run `ollama pull %s`)", msg, model)
+	}
+	err := fmt.Errorf("ollama returned HTTP %d: %s", status, msg)
+	if reason := classifyAPIError("", status); reason != "" {
+		return NewStepError(reason, err, 0, "")
+	}
+	return err
+}
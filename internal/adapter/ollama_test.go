@@ -0,0 +1,160 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ollamaServer(t *testing.T, handler func(w http.ResponseWriter, req ollamaChatRequest)) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path)
+		var req ollamaChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		handler(w, req)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestOllamaAdapter_Run(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace, ".agents", "artifacts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, ".agents", "artifacts", "plan.md"), []byte("# Plan\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, ".agents", "artifacts", "logo.png"), []byte{0x89, 'P', 'N', 'G', 0}, 0644))
+
+	var got ollamaChatRequest
+	host := ollamaServer(t, func(w http.ResponseWriter, req ollamaChatRequest) {
+		got = req
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"Looks "}}` + "\n" +
+			`{"message":{"role":"assistant","content":"good.\n"}}` + "\n" +
+			`{"message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":120,"eval_count":4}` + "\n"))
+	})
+
+	var events []StreamEvent
+	result, err := NewOllamaAdapter().Run(context.Background(), AdapterRunConfig{
+		WorkspacePath: workspace,
+		Prompt:        "Review .agents/artifacts/plan.md",
+		SystemPrompt:  "You are a reviewer.",
+		Model:         "qwen2.5-coder:14b",
+		Temperature:   0.2,
+		Env:           []string{"OLLAMA_HOST=" + host},
+		OnStreamEvent: func(e StreamEvent) { events = append(events, e) },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "qwen2.5-coder:14b", got.Model)
+	assert.True(t, got.Stream)
+	assert.Equal(t, 0.2, got.Options["temperature"])
+	require.Len(t, got.Messages, 2)
+	assert.Equal(t, "system", got.Messages[0].Role)
+	assert.Contains(t, got.Messages[0].Content, "You are a reviewer.")
+	assert.Contains(t, got.Messages[0].Content, "without tools")
+	assert.Contains(t, got.Messages[1].Content, "Review .agents/artifacts/plan.md")
+	assert.Contains(t, got.Messages[1].Content, "<file path=\".agents/artifacts/plan.md\">\n# Plan\n</file>")
+	assert.NotContains(t, got.Messages[1].Content, "logo.png")
+
+	assert.Equal(t, 0, result.ExitCode)
+	assert.Equal(t, "Looks good.\n", result.ResultContent)
+	assert.Equal(t, 120, result.TokensIn)
+	assert.Equal(t, 4, result.TokensOut)
+	assert.Equal(t, 124, result.TokensUsed)
+	assert.Equal(t, []StreamEvent{
+		{Type: "text", Content: "Looks good."},
+		{Type: "result", TokensIn: 120, TokensOut: 4, Subtype: "success"},
+	}, events)
+}
+
+func TestOllamaAdapter_EstimatesTokensWhenUnreported(t *testing.T) {
+	host := ollamaServer(t, func(w http.ResponseWriter, req ollamaChatRequest) {
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"12345678"},"done":true}` + "\n"))
+	})
+	result, err := NewOllamaAdapter().Run(context.Background(), AdapterRunConfig{
+		Prompt: "prompt",
+		Model:  "llama3.2",
+		Env:    []string{"OLLAMA_HOST=" + host},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.TokensOut, "digits are counted in pairs by the generic tokenizer")
+	assert.Greater(t, result.TokensIn, 0)
+	assert.True(t, result.TokensEstimated)
+}
+
+func TestOllamaAdapter_TruncatedReply(t *testing.T) {
+	host := ollamaServer(t, func(w http.ResponseWriter, req ollamaChatRequest) {
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"partial"},"done":true,"done_reason":"length","eval_count":2048}` + "\n"))
+	})
+	result, err := NewOllamaAdapter().Run(context.Background(), AdapterRunConfig{
+		Model: "llama3.2",
+		Env:   []string{"OLLAMA_HOST=" + host},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ExitCode)
+	assert.Equal(t, FailureReasonContextExhaustion, result.FailureReason)
+	assert.Equal(t, "partial", result.ResultContent)
+}
+
+func TestOllamaAdapter_Errors(t *testing.T) {
+	t.Run("missing model", func(t *testing.T) {
+		_, err := NewOllamaAdapter().Run(context.Background(), AdapterRunConfig{})
+		assert.ErrorContains(t, err, "requires a model")
+	})
+
+	t.Run("unmapped tier", func(t *testing.T) {
+		_, err := NewOllamaAdapter().Run(context.Background(), AdapterRunConfig{Model: "strongest"})
+		assert.ErrorContains(t, err, "tier_models")
+	})
+
+	t.Run("model not pulled", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"model \"llama3.2\" not found, try pulling it first"}`))
+		}))
+		defer srv.Close()
+		_, err := NewOllamaAdapter().Run(context.Background(), AdapterRunConfig{
+			Model: "llama3.2",
+			Env:   []string{"OLLAMA_HOST=" + srv.URL},
+		})
+		assert.EqualError(t, err, "ollama: model \"llama3.2\" not found, try pulling it first (run `ollama pull llama3.2`)")
+	})
+
+	t.Run("error mid-stream", func(t *testing.T) {
+		host := ollamaServer(t, func(w http.ResponseWriter, req ollamaChatRequest) {
+			_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"a"}}` + "\n" + `{"error":"out of memory"}` + "\n"))
+		})
+		_, err := NewOllamaAdapter().Run(context.Background(), AdapterRunConfig{
+			Model: "llama3.2",
+			Env:   []string{"OLLAMA_HOST=" + host},
+		})
+		assert.EqualError(t, err, "ollama: out of memory")
+	})
+
+	t.Run("server not running", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		host := srv.URL
+		srv.Close()
+		_, err := NewOllamaAdapter().Run(context.Background(), AdapterRunConfig{
+			Model: "llama3.2",
+			Env:   []string{"OLLAMA_HOST=" + host},
+		})
+		assert.ErrorContains(t, err, "is `ollama serve` running?")
+	})
+}
+
+func TestOllamaHost(t *testing.T) {
+	t.Setenv("OLLAMA_HOST", "")
+	assert.Equal(t, DefaultOllamaHost, ollamaHost(nil))
+	assert.Equal(t, "http://gpu-box:11434", ollamaHost([]string{"OLLAMA_HOST=gpu-box:11434"}))
+	assert.Equal(t, "https://ollama.internal", ollamaHost([]string{"OLLAMA_HOST=https://ollama.internal/"}))
+
+	t.Setenv("OLLAMA_HOST", "127.0.0.1:9999")
+	assert.Equal(t, "http://127.0.0.1:9999", ollamaHost(nil))
+}
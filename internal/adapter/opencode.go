@@ -45,18 +45,21 @@ func (a *OpenCodeAdapter) Run(ctx context.Context, cfg AdapterRunConfig) (*Adapt
 		return nil, fmt.Errorf("provision opencode skills: %w", err)
 	}
 
-	if err := a.prepareWorkspace(workspacePath, cfg); err != nil {
+	restoreInstructions, err := a.prepareWorkspace(workspacePath, cfg)
+	if err != nil {
 		return nil, fmt.Errorf("failed to prepare workspace: %w", err)
 	}
+	defer restoreInstructions()
 
 	args := a.buildArgs(cfg)
 	return runSubprocess(ctx, a.opencodePath, args, workspacePath, cfg, parseOpenCodeStreamLine, a.parseOutput)
 }
 
 func (a *OpenCodeAdapter) parseOutput(data []byte) parseOutputResult {
-	var tokens int
+	var tokens, tokensIn, tokensOut int
 	var resultContent string
 	var subtype string
+	var failureReason string
 
 	lines := bytes.Split(data, []byte("\n"))
 	for _, line := range lines {
@@ -87,6 +90,8 @@ func (a *OpenCodeAdapter) parseOutput(data []byte) parseOutputResult {
 			}
 			if err := json.Unmarshal(line, &evt); err == nil {
 				tokens = evt.Part.Tokens.Total
+				tokensIn = evt.Part.Tokens.Input
+				tokensOut = evt.Part.Tokens.Output
 			}
 		}
 
@@ -104,6 +109,8 @@ func (a *OpenCodeAdapter) parseOutput(data []byte) parseOutputResult {
 				usageTokens := evt.Usage.InputTokens + evt.Usage.OutputTokens
 				if usageTokens > tokens {
 					tokens = usageTokens
+					tokensIn = evt.Usage.InputTokens
+					tokensOut = evt.Usage.OutputTokens
 				}
 				subtype = evt.Subtype
 				resultContent = evt.Result
@@ -113,6 +120,30 @@ func (a *OpenCodeAdapter) parseOutput(data []byte) parseOutputResult {
 			}
 		}
 
+		// Session errors name the provider error and, for API errors, the
+		// HTTP status: {"type":"error","error":{"name":"APIError","data":{...}}}
+		if eventType == "error" {
+			var evt struct {
+				Error struct {
+					Name string `json:"name"`
+					Data struct {
+						Message    string `json:"message"`
+						StatusCode int    `json:"statusCode"`
+					} `json:"data"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(line, &evt); err == nil {
+				msg := evt.Error.Data.Message
+				failureReason = classifyAPIError(evt.Error.Name, evt.Error.Data.StatusCode)
+				if reason := ClassifyFailure("", msg, nil); failureReason == "" && reason != FailureReasonGeneralError {
+					failureReason = reason
+				}
+				if resultContent == "" {
+					resultContent = msg
+				}
+			}
+		}
+
 		if eventType == "text" && resultContent == "" {
 			var evt struct {
 				Part struct {
@@ -135,14 +166,13 @@ func (a *OpenCodeAdapter) parseOutput(data []byte) parseOutputResult {
 		}
 	}
 
-	if tokens == 0 {
-		tokens = len(data) / 4
-	}
-
 	return parseOutputResult{
 		Tokens:        tokens,
+		TokensIn:      tokensIn,
+		TokensOut:     tokensOut,
 		ResultContent: resultContent,
 		Subtype:       subtype,
+		FailureReason: failureReason,
 	}
 }
 
@@ -192,7 +222,7 @@ func parseOpenCodeStreamLine(line []byte) (StreamEvent, bool) {
 							if len(input) > 100 {
 								input = input[:100]
 							}
-							return StreamEvent{Type: "tool_use", ToolName: toolName, ToolInput: input}, true
+							return StreamEvent{Type: "tool_use", ToolName: toolName, ToolInput: input, ToolPaths: toolPathsFromInput(evt.Part.Input)}, true
 						}
 					}
 					return StreamEvent{}, false
@@ -255,6 +285,7 @@ func parseOpenCodeStreamLine(line []byte) (StreamEvent, bool) {
 				Type:      "tool_use",
 				ToolName:  toolEvt.Tool,
 				ToolInput: target,
+				ToolPaths: extractToolPaths(toolEvt.Input),
 			}, true
 		}
 	}
@@ -262,21 +293,20 @@ func parseOpenCodeStreamLine(line []byte) (StreamEvent, bool) {
 	return StreamEvent{}, false
 }
 
-func (a *OpenCodeAdapter) prepareWorkspace(workspacePath string, cfg AdapterRunConfig) error {
-	if cfg.SystemPrompt != "" {
-		promptPath := filepath.Join(workspacePath, "AGENTS.md")
-		if err := os.WriteFile(promptPath, []byte(cfg.SystemPrompt), 0644); err != nil {
-			return fmt.Errorf("failed to write AGENTS.md: %w", err)
-		}
-	} else {
+// prepareWorkspace writes the system prompt, or the persona file when there
+// is none, as AGENTS.md. The returned function restores the workspace's own
+// AGENTS.md.
+func (a *OpenCodeAdapter) prepareWorkspace(workspacePath string, cfg AdapterRunConfig) (func(), error) {
+	content := []byte(cfg.SystemPrompt)
+	if cfg.SystemPrompt == "" {
 		personaPath := filepath.Join(".agents", "personas", cfg.Persona+".md")
-		if data, err := os.ReadFile(personaPath); err == nil {
-			promptPath := filepath.Join(workspacePath, "AGENTS.md")
-			_ = os.WriteFile(promptPath, data, 0644)
+		data, err := os.ReadFile(personaPath)
+		if err != nil {
+			return func() {}, nil
 		}
+		content = data
 	}
-
-	return nil
+	return WriteInstructions(workspacePath, InstructionFilename("opencode"), content)
 }
 
 func (a *OpenCodeAdapter) buildArgs(cfg AdapterRunConfig) []string {
@@ -287,6 +317,7 @@ func (a *OpenCodeAdapter) buildArgs(cfg AdapterRunConfig) []string {
 	}
 
 	args = append(args, "--format", "json")
+	args = append(args, cfg.ExtraArgs...)
 
 	if cfg.Prompt != "" {
 		args = append(args, "--", cfg.Prompt)
@@ -300,9 +331,10 @@ func ResolveAdapter(adapterName string) AdapterRunner {
 }
 
 // ResolveAdapterWithBinary is like ResolveAdapter but threads the manifest's
-// `binary:` field through to adapters that support binary overrides (currently
-// opencode). Other adapters ignore the binary argument. An empty binary
-// defaults to the adapter's built-in name.
+// `binary:` field through to adapters that support binary overrides
+// (opencode and codex). Other adapters ignore the binary argument. An empty
+// binary defaults to the adapter's built-in name. An adapter with another
+// name whose binary is codex runs on the Codex adapter.
 func ResolveAdapterWithBinary(adapterName, binary string) AdapterRunner {
 	name := strings.ToLower(adapterName)
 	switch {
@@ -310,12 +342,14 @@ func ResolveAdapterWithBinary(adapterName, binary string) AdapterRunner {
 		return NewClaudeAdapter()
 	case name == "opencode" || strings.HasPrefix(name, "opencode-"):
 		return NewOpenCodeAdapterWithBinary(binary)
-	case name == "codex":
-		return NewCodexAdapter()
+	case name == "codex" || (!isKnownAdapterName(name) && isCodexBinary(binary)):
+		return NewCodexAdapterWithBinary(binary)
 	case name == "gemini":
 		return NewGeminiAdapter()
 	case name == "browser":
 		return NewBrowserAdapter()
+	case name == "ollama":
+		return NewOllamaAdapter()
 	default:
 		return NewProcessGroupRunner()
 	}
@@ -59,9 +59,11 @@ func TestOpenCodePrepareWorkspace_NoConfigFile(t *testing.T) {
 		Temperature: 0.7,
 	}
 
-	if err := a.prepareWorkspace(tmpDir, cfg); err != nil {
+	restore, err := a.prepareWorkspace(tmpDir, cfg)
+	if err != nil {
 		t.Fatalf("prepareWorkspace returned unexpected error: %v", err)
 	}
+	t.Cleanup(restore)
 
 	configPath := filepath.Join(tmpDir, ".opencode", "config.json")
 	if _, err := os.Stat(configPath); err == nil {
@@ -76,9 +78,11 @@ func TestOpenCodePrepareWorkspace_SystemPromptWritesAgentsMd(t *testing.T) {
 		SystemPrompt: "You are a helpful assistant.",
 	}
 
-	if err := a.prepareWorkspace(tmpDir, cfg); err != nil {
+	restore, err := a.prepareWorkspace(tmpDir, cfg)
+	if err != nil {
 		t.Fatalf("prepareWorkspace returned unexpected error: %v", err)
 	}
+	t.Cleanup(restore)
 
 	agentsPath := filepath.Join(tmpDir, "AGENTS.md")
 	data, err := os.ReadFile(agentsPath)
@@ -96,9 +100,11 @@ func TestOpenCodePrepareWorkspace_NoSettingsDir(t *testing.T) {
 	a := NewOpenCodeAdapter()
 	cfg := AdapterRunConfig{}
 
-	if err := a.prepareWorkspace(tmpDir, cfg); err != nil {
+	restore, err := a.prepareWorkspace(tmpDir, cfg)
+	if err != nil {
 		t.Fatalf("prepareWorkspace returned unexpected error: %v", err)
 	}
+	t.Cleanup(restore)
 
 	settingsDir := filepath.Join(tmpDir, ".opencode")
 	if _, err := os.Stat(settingsDir); err == nil {
@@ -385,3 +391,45 @@ func TestOpenCodeRun_FullOutputCapturedForArtifactExtraction(t *testing.T) {
 		t.Errorf("result.Stdout does not contain expected content\ngot:  %q\nwant: %q", got, wantContent)
 	}
 }
+
+// TestOpenCodeRun_RawLogCapturesStdoutAndStderr verifies that RawLog receives
+// unparsed stdout, including lines the stream parser rejects, plus stderr.
+func TestOpenCodeRun_RawLogCapturesStdoutAndStderr(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	fakePath := writeFakeOpencode(t,
+		`printf 'not json\n{"type":"result","usage":{"input_tokens":1,"output_tokens":1},"content":"ok","subtype":"success"}\n'; echo 'boom on stderr' >&2`)
+
+	a := &OpenCodeAdapter{opencodePath: fakePath}
+
+	var raw syncBuffer
+	if _, err := a.Run(t.Context(), AdapterRunConfig{WorkspacePath: t.TempDir(), RawLog: &raw}); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	got := raw.String()
+	for _, want := range []string{"not json\n", `"content":"ok"`, "boom on stderr\n"} {
+		if !bytes.Contains([]byte(got), []byte(want)) {
+			t.Errorf("raw log missing %q\ngot: %q", want, got)
+		}
+	}
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
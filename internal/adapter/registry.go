@@ -14,6 +14,7 @@ var builtinAdapterNames = map[string]struct{}{
 	"gemini":   {},
 	"opencode": {},
 	"browser":  {},
+	"ollama":   {},
 }
 
 // isKnownAdapterName reports whether the given name corresponds to a built-in
@@ -46,6 +47,8 @@ type AdapterRegistry struct {
 	overrides     map[string]AdapterRunner // test-injected runners
 	binaries      map[string]string        // adapter name → manifest binary override
 	defaultRunner AdapterRunner            // fallback for all names when set
+	warmPool      *WarmPool                // pre-started processes for adapters that support them
+	limiter       *Limiter                 // per-adapter concurrency and start-rate limits
 }
 
 // NewAdapterRegistry creates a registry with optional fallback chain configuration.
@@ -59,7 +62,8 @@ func NewAdapterRegistry(fallbacks map[string][]string) *AdapterRegistry {
 
 // SetBinary records the binary override for an adapter name as declared in the
 // manifest. It is honored when resolving the adapter via Resolve, allowing
-// forks like `opencode-patched` to be invoked without symlink hacks.
+// forks like `opencode-patched` to be invoked without symlink hacks and any
+// adapter name to run on the Codex adapter with `binary: codex`.
 func (r *AdapterRegistry) SetBinary(adapterName, binary string) {
 	if r.binaries == nil {
 		r.binaries = make(map[string]string)
@@ -67,6 +71,38 @@ func (r *AdapterRegistry) SetBinary(adapterName, binary string) {
 	r.binaries[adapterName] = binary
 }
 
+// SetWarmPool makes the built-in adapters that support warm processes
+// (currently claude) take them from pool. Long-lived callers such as
+// `wave serve` share one pool across runs.
+func (r *AdapterRegistry) SetWarmPool(pool *WarmPool) {
+	r.warmPool = pool
+}
+
+// withWarmPool attaches the registry's warm pool to a built-in runner that
+// supports it.
+func (r *AdapterRegistry) withWarmPool(runner AdapterRunner) AdapterRunner {
+	if c, ok := runner.(*ClaudeAdapter); ok && r.warmPool != nil {
+		c.warmPool = r.warmPool
+	}
+	return runner
+}
+
+// SetLimiter throttles the runners the registry resolves by the limiter's
+// per-adapter limits. Executors share their registry with child pipelines
+// and matrix workers, so the limits hold across all of them.
+func (r *AdapterRegistry) SetLimiter(l *Limiter) {
+	r.limiter = l
+}
+
+// withLimits wraps runner so it waits for name's budget in the registry's
+// limiter. Adapters without limits are returned as is.
+func (r *AdapterRegistry) withLimits(name string, runner AdapterRunner) AdapterRunner {
+	if !r.limiter.Limits(name) {
+		return runner
+	}
+	return &limitedRunner{name: name, runner: runner, limiter: r.limiter}
+}
+
 // NewSingleRunnerRegistry creates a registry that always returns the given runner.
 // Used for backward compatibility in tests and simple configurations.
 func NewSingleRunnerRegistry(runner AdapterRunner) *AdapterRegistry {
@@ -83,6 +119,10 @@ func NewSingleRunnerRegistry(runner AdapterRunner) *AdapterRegistry {
 // ProcessGroupRunner that exec's the name as a binary. Callers that want
 // to distinguish "registered" from "unknown" should use ResolveStrict.
 func (r *AdapterRegistry) Resolve(adapterName string) AdapterRunner {
+	return r.withLimits(adapterName, r.resolve(adapterName))
+}
+
+func (r *AdapterRegistry) resolve(adapterName string) AdapterRunner {
 	if runner, ok := r.overrides[adapterName]; ok {
 		return runner
 	}
@@ -93,14 +133,15 @@ func (r *AdapterRegistry) Resolve(adapterName string) AdapterRunner {
 	if r.binaries != nil {
 		binary = r.binaries[adapterName]
 	}
-	return ResolveAdapterWithBinary(adapterName, binary)
+	return r.withWarmPool(ResolveAdapterWithBinary(adapterName, binary))
 }
 
 // ResolveStrict is like Resolve but returns ErrUnknownAdapter wrapped with
 // the adapter name when the name has no override registered, no default
-// runner is configured, and the name is not a built-in adapter. This is
-// the preferred API for callers (e.g. FallbackRunner) that should refuse
-// silently exec'ing an arbitrary binary on a typo'd fallback chain entry.
+// runner is configured, and the name is neither a built-in adapter nor
+// bound to the codex binary. This is the preferred API for callers (e.g.
+// FallbackRunner) that should refuse silently exec'ing an arbitrary binary
+// on a typo'd fallback chain entry.
 func (r *AdapterRegistry) ResolveStrict(adapterName string) (AdapterRunner, error) {
 	if r == nil {
 		return nil, fmt.Errorf("%w: nil registry", ErrUnknownAdapter)
@@ -109,23 +150,23 @@ func (r *AdapterRegistry) ResolveStrict(adapterName string) (AdapterRunner, erro
 		if runner == nil {
 			return nil, fmt.Errorf("%w: %q (override registered as nil)", ErrUnknownAdapter, adapterName)
 		}
-		return runner, nil
+		return r.withLimits(adapterName, runner), nil
 	}
 	if r.defaultRunner != nil {
-		return r.defaultRunner, nil
-	}
-	if !isKnownAdapterName(adapterName) {
-		return nil, fmt.Errorf("%w: %q", ErrUnknownAdapter, adapterName)
+		return r.withLimits(adapterName, r.defaultRunner), nil
 	}
 	binary := ""
 	if r.binaries != nil {
 		binary = r.binaries[adapterName]
 	}
+	if !isKnownAdapterName(adapterName) && !isCodexBinary(binary) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAdapter, adapterName)
+	}
 	runner := ResolveAdapterWithBinary(adapterName, binary)
 	if runner == nil {
 		return nil, fmt.Errorf("%w: %q (resolver returned nil)", ErrUnknownAdapter, adapterName)
 	}
-	return runner, nil
+	return r.withLimits(adapterName, r.withWarmPool(runner)), nil
 }
 
 // ResolveWithFallback returns the primary runner wrapped in a FallbackRunner
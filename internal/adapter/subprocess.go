@@ -7,9 +7,9 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
-	"syscall"
 	"time"
 
+	"github.com/recinq/wave/internal/procutil"
 	"github.com/recinq/wave/internal/timeouts"
 )
 
@@ -43,10 +43,8 @@ func runSubprocess(
 	cmd.Dir = workspacePath
 	cmd.Env = BuildCuratedEnvironment(cfg)
 
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true,
-		Pgid:    0,
-	}
+	procutil.SetProcessGroup(cmd)
+	cmd.Stderr = rawLog(cfg)
 
 	stdoutPipe, err := cmd.StdoutPipe()
 	if err != nil {
@@ -66,7 +64,7 @@ func runSubprocess(
 	stdoutDone := make(chan error, 1)
 
 	go func() {
-		scanner := bufio.NewScanner(stdoutPipe)
+		scanner := bufio.NewScanner(teeRawLog(stdoutPipe, cfg))
 		scanner.Buffer(make([]byte, 0, 1024*1024), 10*1024*1024)
 		for scanner.Scan() {
 			line := scanner.Bytes()
@@ -124,9 +122,10 @@ func runSubprocess(
 	result.Artifacts = parsed.Artifacts
 	result.Subtype = parsed.Subtype
 	result.ResultContent = parsed.ResultContent
+	NormalizeUsage(binaryName, cfg, stdoutBuf.Bytes(), result)
 
 	if result.ExitCode != 0 || parsed.Subtype == "error_max_turns" || parsed.Subtype == "error_during_execution" {
-		result.FailureReason = ClassifyFailure(parsed.Subtype, parsed.ResultContent, nil)
+		result.FailureReason = classifyRunFailure(parsed, result.ExitCode, stdoutBuf.Bytes())
 	}
 
 	if cfg.Debug {
@@ -0,0 +1,101 @@
+package adapter
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/recinq/wave/internal/cost"
+)
+
+// TokenUsage is the token accounting of one adapter run in the shape shared
+// by all adapters. In counts prompt tokens (cache writes included), Out
+// counts completion tokens, and Total is at least In+Out: it also carries
+// tokens a CLI reports only in aggregate, such as Claude's cache reads.
+type TokenUsage struct {
+	In    int
+	Out   int
+	Total int
+	// Estimated is set when the CLI reported no usage and the counts come
+	// from tokenizing the prompt and the response.
+	Estimated bool
+}
+
+// reported reports whether the CLI reported any usage.
+func (u TokenUsage) reported() bool {
+	return u.In > 0 || u.Out > 0 || u.Total > 0
+}
+
+// UsageExtractor reads the token usage a CLI reported in its raw stdout,
+// returning the zero TokenUsage when the output carries none.
+type UsageExtractor func(stdout []byte) TokenUsage
+
+// usageExtractors maps adapter names to the extractor for their CLI's output
+// format. Adapters without one (custom CLIs) are always estimated.
+var usageExtractors = map[string]UsageExtractor{
+	"claude": func(stdout []byte) TokenUsage {
+		p := (&ClaudeAdapter{}).parseOutput(stdout)
+		return TokenUsage{In: p.TokensIn, Out: p.TokensOut, Total: p.Tokens}
+	},
+	"codex": func(stdout []byte) TokenUsage {
+		r := (&CodexAdapter{}).parseOutput(string(stdout))
+		return TokenUsage{In: r.TokensIn, Out: r.TokensOut, Total: r.TokensUsed}
+	},
+	"gemini": func(stdout []byte) TokenUsage {
+		r := (&GeminiAdapter{}).parseOutput(string(stdout))
+		return TokenUsage{In: r.TokensIn, Out: r.TokensOut, Total: r.TokensUsed}
+	},
+	"opencode": func(stdout []byte) TokenUsage {
+		p := (&OpenCodeAdapter{}).parseOutput(stdout)
+		return TokenUsage{In: p.TokensIn, Out: p.TokensOut, Total: p.Tokens}
+	},
+}
+
+// usageExtractorFor returns the extractor for an adapter name or binary
+// path. Opencode forks (opencode-*) share opencode's.
+func usageExtractorFor(adapterName string) UsageExtractor {
+	name := strings.ToLower(filepath.Base(adapterName))
+	if strings.HasPrefix(name, "opencode-") {
+		name = "opencode"
+	}
+	return usageExtractors[name]
+}
+
+// estimateUsage counts a run's tokens with the tokenizer of the adapter's
+// model family: the system prompt and prompt as input, response as output.
+func estimateUsage(adapterName string, cfg AdapterRunConfig, response string) TokenUsage {
+	tok := cost.TokenizerFor(filepath.Base(adapterName), cfg.Model)
+	in := tok.Count(cfg.SystemPrompt) + tok.Count(cfg.Prompt)
+	out := tok.Count(response)
+	return TokenUsage{In: in, Out: out, Total: in + out, Estimated: true}
+}
+
+// NormalizeUsage settles result's token fields so they compare across
+// adapters. Usage the adapter already parsed is kept, otherwise the
+// adapter's extractor reads it from stdout; Total is raised to at least
+// In+Out. When the CLI reported nothing, both sides are estimated with the
+// tokenizer and TokensEstimated is set. Cache hits are left at zero.
+func NormalizeUsage(adapterName string, cfg AdapterRunConfig, stdout []byte, result *AdapterResult) {
+	if result == nil || result.CacheHit {
+		return
+	}
+	u := TokenUsage{In: result.TokensIn, Out: result.TokensOut, Total: result.TokensUsed}
+	if !u.reported() {
+		if extract := usageExtractorFor(adapterName); extract != nil {
+			u = extract(stdout)
+		}
+	}
+	if !u.reported() {
+		response := result.ResultContent
+		if response == "" {
+			response = string(stdout)
+		}
+		u = estimateUsage(adapterName, cfg, response)
+	}
+	if u.Total < u.In+u.Out {
+		u.Total = u.In + u.Out
+	}
+	result.TokensIn = u.In
+	result.TokensOut = u.Out
+	result.TokensUsed = u.Total
+	result.TokensEstimated = u.Estimated
+}
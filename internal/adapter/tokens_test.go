@@ -0,0 +1,55 @@
+package adapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeUsage(t *testing.T) {
+	cfg := AdapterRunConfig{SystemPrompt: "You are a reviewer.", Prompt: "Review the diff in src/main.go", Model: "claude-sonnet-4"}
+
+	t.Run("keeps parsed usage and raises the total", func(t *testing.T) {
+		result := &AdapterResult{TokensIn: 100, TokensOut: 40}
+		NormalizeUsage("codex", cfg, nil, result)
+		assert.Equal(t, TokenUsage{In: 100, Out: 40, Total: 140}, usageOf(result))
+	})
+
+	t.Run("keeps aggregate-only totals such as cache reads", func(t *testing.T) {
+		result := &AdapterResult{TokensUsed: 900, TokensIn: 100, TokensOut: 40}
+		NormalizeUsage("claude", cfg, nil, result)
+		assert.Equal(t, TokenUsage{In: 100, Out: 40, Total: 900}, usageOf(result))
+	})
+
+	t.Run("extracts usage from stdout", func(t *testing.T) {
+		stdout := []byte(`{"type":"turn.completed","usage":{"input_tokens":300,"output_tokens":25}}` + "\n")
+		result := &AdapterResult{}
+		NormalizeUsage("/usr/local/bin/codex", cfg, stdout, result)
+		assert.Equal(t, TokenUsage{In: 300, Out: 25, Total: 325}, usageOf(result))
+
+		stdout = []byte(`{"type":"step_finish","part":{"tokens":{"total":70,"input":50,"output":20}}}` + "\n")
+		result = &AdapterResult{}
+		NormalizeUsage("opencode-patched", cfg, stdout, result)
+		assert.Equal(t, TokenUsage{In: 50, Out: 20, Total: 70}, usageOf(result))
+	})
+
+	t.Run("estimates unreported usage", func(t *testing.T) {
+		result := &AdapterResult{ResultContent: "Looks good to me."}
+		NormalizeUsage("my-cli", cfg, []byte("raw output"), result)
+		want := estimateUsage("my-cli", cfg, "Looks good to me.")
+		assert.Equal(t, want, usageOf(result))
+		assert.True(t, want.Estimated)
+		assert.Positive(t, want.In)
+		assert.Positive(t, want.Out)
+	})
+
+	t.Run("leaves cache hits alone", func(t *testing.T) {
+		result := &AdapterResult{CacheHit: true}
+		NormalizeUsage("claude", cfg, nil, result)
+		assert.Equal(t, TokenUsage{}, usageOf(result))
+	})
+}
+
+func usageOf(r *AdapterResult) TokenUsage {
+	return TokenUsage{In: r.TokensIn, Out: r.TokensOut, Total: r.TokensUsed, Estimated: r.TokensEstimated}
+}
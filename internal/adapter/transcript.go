@@ -0,0 +1,225 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// TranscriptVersion is bumped whenever the Transcript JSON shape changes
+// incompatibly, so audit tooling can tell old artifacts apart.
+const TranscriptVersion = 1
+
+// Transcript is a normalized record of one adapter conversation: every
+// assistant and tool turn in order, with per-turn token usage. Adapters that
+// can reconstruct it set AdapterResult.Transcript.
+type Transcript struct {
+	Version   int              `json:"version"`
+	Adapter   string           `json:"adapter"`
+	Model     string           `json:"model,omitempty"`
+	SessionID string           `json:"session_id,omitempty"`
+	Turns     []TranscriptTurn `json:"turns"`
+	Result    *TranscriptEnd   `json:"result,omitempty"`
+}
+
+// TranscriptTurn is one message. Assistant turns carry the model's text and
+// tool calls; user turns carry tool results.
+type TranscriptTurn struct {
+	Index  int               `json:"index"`
+	Role   string            `json:"role"` // "assistant" or "user"
+	Blocks []TranscriptBlock `json:"blocks"`
+	Usage  *TranscriptUsage  `json:"usage,omitempty"`
+}
+
+// TranscriptBlock is one content block of a turn.
+type TranscriptBlock struct {
+	Type      string          `json:"type"` // "text", "thinking", "tool_use", "tool_result"
+	Text      string          `json:"text,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	ToolName  string          `json:"tool_name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
+}
+
+// TranscriptUsage is the token usage reported for a turn.
+type TranscriptUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+}
+
+// TranscriptEnd summarises how the conversation finished.
+type TranscriptEnd struct {
+	Subtype    string           `json:"subtype,omitempty"`
+	IsError    bool             `json:"is_error,omitempty"`
+	NumTurns   int              `json:"num_turns,omitempty"`
+	DurationMs int64            `json:"duration_ms,omitempty"`
+	CostUSD    float64          `json:"cost_usd,omitempty"`
+	Usage      *TranscriptUsage `json:"usage,omitempty"`
+	Content    string           `json:"content,omitempty"`
+}
+
+// ParseClaudeTranscript builds a Transcript from Claude Code stream-json
+// output. Claude Code emits one assistant event per content block, all
+// repeating the message id and usage; those are folded into a single turn.
+// Unparsable lines are skipped. It returns nil when the output holds no
+// conversation at all.
+func ParseClaudeTranscript(data []byte) *Transcript {
+	t := &Transcript{Version: TranscriptVersion, Adapter: "claude", Turns: []TranscriptTurn{}}
+	lastMessageID := ""
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var evt struct {
+			Type       string           `json:"type"`
+			Subtype    string           `json:"subtype"`
+			SessionID  string           `json:"session_id"`
+			Model      string           `json:"model"`
+			IsError    bool             `json:"is_error"`
+			NumTurns   int              `json:"num_turns"`
+			DurationMs int64            `json:"duration_ms"`
+			CostUSD    float64          `json:"total_cost_usd"`
+			Result     string           `json:"result"`
+			Usage      *TranscriptUsage `json:"usage"`
+			Message    struct {
+				ID      string           `json:"id"`
+				Model   string           `json:"model"`
+				Content json.RawMessage  `json:"content"`
+				Usage   *TranscriptUsage `json:"usage"`
+			} `json:"message"`
+		}
+		if err := json.Unmarshal(line, &evt); err != nil {
+			continue
+		}
+
+		switch evt.Type {
+		case "system":
+			if evt.SessionID != "" {
+				t.SessionID = evt.SessionID
+			}
+			if evt.Model != "" {
+				t.Model = evt.Model
+			}
+		case "assistant", "user":
+			blocks := parseTranscriptBlocks(evt.Message.Content)
+			if len(blocks) == 0 {
+				continue
+			}
+			if evt.Type == "assistant" && evt.Message.Model != "" {
+				t.Model = evt.Message.Model
+			}
+			n := len(t.Turns)
+			if evt.Type == "assistant" && evt.Message.ID != "" && evt.Message.ID == lastMessageID && n > 0 {
+				t.Turns[n-1].Blocks = append(t.Turns[n-1].Blocks, blocks...)
+				if evt.Message.Usage != nil {
+					t.Turns[n-1].Usage = evt.Message.Usage
+				}
+				continue
+			}
+			lastMessageID = ""
+			if evt.Type == "assistant" {
+				lastMessageID = evt.Message.ID
+			}
+			turn := TranscriptTurn{Index: n, Role: evt.Type, Blocks: blocks}
+			if evt.Type == "assistant" {
+				turn.Usage = evt.Message.Usage
+			}
+			t.Turns = append(t.Turns, turn)
+		case "result":
+			t.Result = &TranscriptEnd{
+				Subtype:    evt.Subtype,
+				IsError:    evt.IsError,
+				NumTurns:   evt.NumTurns,
+				DurationMs: evt.DurationMs,
+				CostUSD:    evt.CostUSD,
+				Usage:      evt.Usage,
+				Content:    evt.Result,
+			}
+			if evt.SessionID != "" {
+				t.SessionID = evt.SessionID
+			}
+		}
+	}
+
+	if len(t.Turns) == 0 && t.Result == nil {
+		return nil
+	}
+	return t
+}
+
+// parseTranscriptBlocks normalizes a message's content, which is either a
+// plain string or a list of typed blocks.
+func parseTranscriptBlocks(raw json.RawMessage) []TranscriptBlock {
+	if len(raw) == 0 {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		if text == "" {
+			return nil
+		}
+		return []TranscriptBlock{{Type: "text", Text: text}}
+	}
+
+	var items []struct {
+		Type      string          `json:"type"`
+		Text      string          `json:"text"`
+		Thinking  string          `json:"thinking"`
+		ID        string          `json:"id"`
+		Name      string          `json:"name"`
+		Input     json.RawMessage `json:"input"`
+		ToolUseID string          `json:"tool_use_id"`
+		Content   json.RawMessage `json:"content"`
+		IsError   bool            `json:"is_error"`
+	}
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil
+	}
+	var blocks []TranscriptBlock
+	for _, it := range items {
+		switch it.Type {
+		case "text":
+			blocks = append(blocks, TranscriptBlock{Type: "text", Text: it.Text})
+		case "thinking":
+			blocks = append(blocks, TranscriptBlock{Type: "thinking", Text: it.Thinking})
+		case "tool_use":
+			blocks = append(blocks, TranscriptBlock{Type: "tool_use", ToolUseID: it.ID, ToolName: it.Name, Input: it.Input})
+		case "tool_result":
+			blocks = append(blocks, TranscriptBlock{Type: "tool_result", ToolUseID: it.ToolUseID, Text: toolResultText(it.Content), IsError: it.IsError})
+		}
+	}
+	return blocks
+}
+
+// toolResultText flattens a tool_result's content (a string or a list of
+// text blocks) into plain text. Non-text blocks such as images are noted by
+// type only.
+func toolResultText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return string(raw)
+	}
+	var out []string
+	for _, p := range parts {
+		if p.Type == "text" {
+			out = append(out, p.Text)
+		} else {
+			out = append(out, "["+p.Type+"]")
+		}
+	}
+	return strings.Join(out, "\n")
+}
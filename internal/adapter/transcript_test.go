@@ -0,0 +1,73 @@
+package adapter
+
+import (
+	"strings"
+	"testing"
+)
+
+const testClaudeStream = `{"type":"system","subtype":"init","session_id":"sess-1","model":"claude-sonnet-4"}
+{"type":"assistant","message":{"id":"msg_1","model":"claude-sonnet-4","content":[{"type":"text","text":"Reading the file."}],"usage":{"input_tokens":120,"output_tokens":4}}}
+{"type":"assistant","message":{"id":"msg_1","model":"claude-sonnet-4","content":[{"type":"tool_use","id":"tu_1","name":"Read","input":{"file_path":"main.go"}}],"usage":{"input_tokens":120,"output_tokens":30}}}
+{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"tu_1","content":[{"type":"text","text":"package main"}]}]}}
+not json
+{"type":"assistant","message":{"id":"msg_2","content":[{"type":"tool_use","id":"tu_2","name":"Bash","input":{"command":"false"}}],"usage":{"input_tokens":160,"output_tokens":12,"cache_read_input_tokens":100}}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"tu_2","content":"exit status 1","is_error":true}]}}
+{"type":"assistant","message":{"id":"msg_3","content":[{"type":"text","text":"Done."}],"usage":{"input_tokens":180,"output_tokens":2}}}
+{"type":"result","subtype":"success","is_error":false,"num_turns":3,"duration_ms":4200,"total_cost_usd":0.012,"result":"Done.","usage":{"input_tokens":460,"output_tokens":44}}
+`
+
+func TestParseClaudeTranscript(t *testing.T) {
+	tr := ParseClaudeTranscript([]byte(testClaudeStream))
+	if tr == nil {
+		t.Fatal("expected a transcript")
+	}
+	if tr.Adapter != "claude" || tr.SessionID != "sess-1" || tr.Model != "claude-sonnet-4" {
+		t.Errorf("header = %q/%q/%q", tr.Adapter, tr.SessionID, tr.Model)
+	}
+	if len(tr.Turns) != 5 {
+		t.Fatalf("expected 5 turns, got %d: %+v", len(tr.Turns), tr.Turns)
+	}
+
+	first := tr.Turns[0]
+	if first.Role != "assistant" || len(first.Blocks) != 2 {
+		t.Fatalf("blocks of one message must fold into one turn, got %+v", first)
+	}
+	if first.Blocks[1].Type != "tool_use" || first.Blocks[1].ToolName != "Read" || first.Blocks[1].ToolUseID != "tu_1" {
+		t.Errorf("tool_use block = %+v", first.Blocks[1])
+	}
+	if !strings.Contains(string(first.Blocks[1].Input), "main.go") {
+		t.Errorf("tool input = %s", first.Blocks[1].Input)
+	}
+	if first.Usage == nil || first.Usage.OutputTokens != 30 {
+		t.Errorf("turn usage must come from the last event of the message, got %+v", first.Usage)
+	}
+
+	read := tr.Turns[1].Blocks[0]
+	if tr.Turns[1].Role != "user" || read.Type != "tool_result" || read.Text != "package main" || read.IsError {
+		t.Errorf("tool_result = %+v", read)
+	}
+	failed := tr.Turns[3].Blocks[0]
+	if failed.Text != "exit status 1" || !failed.IsError {
+		t.Errorf("error tool_result = %+v", failed)
+	}
+	if tr.Turns[2].Usage.CacheReadInputTokens != 100 {
+		t.Errorf("cache tokens = %+v", tr.Turns[2].Usage)
+	}
+	for i, turn := range tr.Turns {
+		if turn.Index != i {
+			t.Errorf("turn %d has index %d", i, turn.Index)
+		}
+	}
+
+	if tr.Result == nil || tr.Result.Subtype != "success" || tr.Result.NumTurns != 3 || tr.Result.Usage.OutputTokens != 44 || tr.Result.Content != "Done." {
+		t.Errorf("result = %+v", tr.Result)
+	}
+}
+
+func TestParseClaudeTranscript_NoConversation(t *testing.T) {
+	for _, in := range []string{"", "plain text output\n", `{"type":"system","session_id":"s"}`} {
+		if tr := ParseClaudeTranscript([]byte(in)); tr != nil {
+			t.Errorf("ParseClaudeTranscript(%q) = %+v, want nil", in, tr)
+		}
+	}
+}
@@ -0,0 +1,394 @@
+package adapter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/recinq/wave/internal/procutil"
+)
+
+// WarmPoolConfig configures a WarmPool. Zero values yield the defaults:
+// one warm process per launch signature, at most four in total, reaped
+// after ten idle minutes and health-checked every 30 seconds.
+type WarmPoolConfig struct {
+	PerKey         int           // idle processes kept per launch signature
+	MaxIdle        int           // idle processes kept across all signatures
+	IdleTimeout    time.Duration // an idle process nobody took is reaped after this
+	HealthInterval time.Duration // how often idle processes are checked
+}
+
+// WarmPool keeps adapter CLI processes started ahead of use, so a step
+// whose launch matches a warm process skips the CLI's cold start (runtime
+// boot, authentication, settings load). It suits long-lived processes such
+// as `wave serve`.
+//
+// A warm process is started with everything but the prompt and waits on
+// stdin for it. It only serves a step with the same binary, arguments,
+// working directory and environment, and whose startup files (see
+// WarmSpec.Fingerprint) are unchanged, so it never runs with stale
+// settings. Since the working directory is a step's workspace, only that
+// step's retry can use it: adapters warm a replacement only after an
+// attempt that fails and will be retried (AdapterRunConfig.WarmRetry).
+// Idle processes are reaped after IdleTimeout, when they exit or when
+// their working directory is removed.
+type WarmPool struct {
+	cfg   WarmPoolConfig
+	start func(WarmSpec) (*warmProcess, error)
+
+	mu       sync.Mutex
+	idle     map[string][]*warmProcess
+	spawning map[string]int
+	closed   bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// WarmSpec is how an adapter launches its CLI for a step.
+type WarmSpec struct {
+	Path string
+	Args []string
+	Dir  string
+	Env  []string
+	// Fingerprint digests the workspace files the CLI reads at startup.
+	// Nil means the CLI reads none.
+	Fingerprint func() string
+}
+
+// warmProcess is a started CLI process with its stdio pipes. The pool
+// reaps it with Wait as soon as it starts, so exited reports an exit even
+// while nobody has taken the process.
+type warmProcess struct {
+	process  *os.Process
+	dir      string
+	stdin    io.WriteCloser
+	stdout   io.ReadCloser
+	stderr   io.ReadCloser
+	exited   chan struct{}
+	waitErr  error
+	warmedAt time.Time
+}
+
+// NewWarmPool creates a WarmPool and starts its health checks. Close it
+// to stop them and kill the idle processes.
+func NewWarmPool(cfg WarmPoolConfig) *WarmPool {
+	if cfg.PerKey <= 0 {
+		cfg.PerKey = 1
+	}
+	if cfg.MaxIdle <= 0 {
+		cfg.MaxIdle = 4
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = 10 * time.Minute
+	}
+	if cfg.HealthInterval <= 0 {
+		cfg.HealthInterval = 30 * time.Second
+	}
+	p := &WarmPool{
+		cfg:      cfg,
+		start:    startWarmProcess,
+		idle:     make(map[string][]*warmProcess),
+		spawning: make(map[string]int),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go p.healthLoop()
+	return p
+}
+
+// Close stops the health checks and kills every idle process.
+func (p *WarmPool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	var all []*warmProcess
+	for key, procs := range p.idle {
+		all = append(all, procs...)
+		delete(p.idle, key)
+	}
+	p.mu.Unlock()
+
+	close(p.stop)
+	<-p.done
+	for _, proc := range all {
+		proc.discard()
+	}
+}
+
+// take hands out a healthy warm process for spec, or nil when there is
+// none and the caller must cold-start.
+func (p *WarmPool) take(spec WarmSpec) *warmProcess {
+	key := spec.key()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.idle[key]) > 0 {
+		proc := p.idle[key][0]
+		p.idle[key] = p.idle[key][1:]
+		if len(p.idle[key]) == 0 {
+			delete(p.idle, key)
+		}
+		if proc.healthy() {
+			return proc
+		}
+		go proc.discard()
+	}
+	return nil
+}
+
+// replenish warms processes for spec in the background until PerKey are
+// idle, evicting the longest-idle processes of other signatures to stay
+// within MaxIdle.
+func (p *WarmPool) replenish(spec WarmSpec) {
+	key := spec.key()
+	p.mu.Lock()
+	want := p.cfg.PerKey - len(p.idle[key]) - p.spawning[key]
+	if p.closed || want <= 0 {
+		p.mu.Unlock()
+		return
+	}
+	p.spawning[key] += want
+	p.mu.Unlock()
+
+	for i := 0; i < want; i++ {
+		go func() {
+			proc, err := p.start(spec)
+			current := ""
+			if err == nil {
+				current = spec.key()
+			}
+			p.mu.Lock()
+			p.spawning[key]--
+			if p.spawning[key] == 0 {
+				delete(p.spawning, key)
+			}
+			if err != nil {
+				p.mu.Unlock()
+				return
+			}
+			// A step may have changed the startup files while the process
+			// started; it would then hold stale settings under this key.
+			if p.closed || current != key {
+				p.mu.Unlock()
+				proc.discard()
+				return
+			}
+			p.idle[key] = append(p.idle[key], proc)
+			evicted := p.evictLocked()
+			p.mu.Unlock()
+			for _, e := range evicted {
+				e.discard()
+			}
+		}()
+	}
+}
+
+// evictLocked removes the longest-idle processes beyond MaxIdle.
+func (p *WarmPool) evictLocked() []*warmProcess {
+	var evicted []*warmProcess
+	for p.countLocked() > p.cfg.MaxIdle {
+		oldestKey, oldestAt := "", time.Time{}
+		for key, procs := range p.idle {
+			if oldestKey == "" || procs[0].warmedAt.Before(oldestAt) {
+				oldestKey, oldestAt = key, procs[0].warmedAt
+			}
+		}
+		evicted = append(evicted, p.idle[oldestKey][0])
+		p.idle[oldestKey] = p.idle[oldestKey][1:]
+		if len(p.idle[oldestKey]) == 0 {
+			delete(p.idle, oldestKey)
+		}
+	}
+	return evicted
+}
+
+func (p *WarmPool) countLocked() int {
+	n := 0
+	for _, procs := range p.idle {
+		n += len(procs)
+	}
+	return n
+}
+
+func (p *WarmPool) healthLoop() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.cfg.HealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case now := <-ticker.C:
+			p.reap(now)
+		}
+	}
+}
+
+// reap drops idle processes that timed out or failed their health check.
+func (p *WarmPool) reap(now time.Time) {
+	var dropped []*warmProcess
+	p.mu.Lock()
+	for key, procs := range p.idle {
+		kept := procs[:0]
+		for _, proc := range procs {
+			if now.Sub(proc.warmedAt) >= p.cfg.IdleTimeout || !proc.healthy() {
+				dropped = append(dropped, proc)
+				continue
+			}
+			kept = append(kept, proc)
+		}
+		if len(kept) == 0 {
+			delete(p.idle, key)
+		} else {
+			p.idle[key] = kept
+		}
+	}
+	p.mu.Unlock()
+	for _, proc := range dropped {
+		proc.discard()
+	}
+}
+
+// key identifies the launches a warm process can serve.
+func (s WarmSpec) key() string {
+	h := sha256.New()
+	write := func(v string) {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	write(s.Path)
+	for _, a := range s.Args {
+		write(a)
+	}
+	write(s.Dir)
+	env := append([]string(nil), s.Env...)
+	sort.Strings(env)
+	for _, e := range env {
+		write(e)
+	}
+	if s.Fingerprint != nil {
+		write(s.Fingerprint())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fingerprintFiles digests the named files and directory trees under dir.
+// Missing paths digest as absent, so creating one changes the result.
+func fingerprintFiles(dir string, paths ...string) string {
+	h := sha256.New()
+	for _, rel := range paths {
+		root := filepath.Join(dir, rel)
+		if _, err := os.Stat(root); err != nil {
+			h.Write([]byte("absent:" + rel + "\x00"))
+			continue
+		}
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			name, _ := filepath.Rel(dir, path)
+			h.Write([]byte(name + "\x00"))
+			sum := sha256.Sum256(data)
+			h.Write(sum[:])
+			return nil
+		})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// startWarmProcess starts spec's process in its own process group with
+// stdio on os pipes, which (unlike exec's StdoutPipe) stay readable after
+// Wait returns.
+func startWarmProcess(spec WarmSpec) (*warmProcess, error) {
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		closeFiles(stdinR, stdinW)
+		return nil, err
+	}
+	stderrR, stderrW, err := os.Pipe()
+	if err != nil {
+		closeFiles(stdinR, stdinW, stdoutR, stdoutW)
+		return nil, err
+	}
+
+	cmd := exec.Command(spec.Path, spec.Args...)
+	cmd.Dir = spec.Dir
+	cmd.Env = spec.Env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdinR, stdoutW, stderrW
+	procutil.SetProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		closeFiles(stdinR, stdinW, stdoutR, stdoutW, stderrR, stderrW)
+		return nil, err
+	}
+	closeFiles(stdinR, stdoutW, stderrW)
+
+	proc := &warmProcess{
+		process:  cmd.Process,
+		dir:      spec.Dir,
+		stdin:    stdinW,
+		stdout:   stdoutR,
+		stderr:   stderrR,
+		exited:   make(chan struct{}),
+		warmedAt: time.Now(),
+	}
+	go func() {
+		proc.waitErr = cmd.Wait()
+		close(proc.exited)
+	}()
+	return proc, nil
+}
+
+func closeFiles(files ...*os.File) {
+	for _, f := range files {
+		_ = f.Close()
+	}
+}
+
+// healthy reports whether the process is still running in an existing
+// working directory.
+func (w *warmProcess) healthy() bool {
+	select {
+	case <-w.exited:
+		return false
+	default:
+	}
+	if w.dir != "" {
+		if _, err := os.Stat(w.dir); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// wait blocks until the process exits and returns its exit error.
+func (w *warmProcess) wait() error {
+	<-w.exited
+	return w.waitErr
+}
+
+// discard kills the process group and releases the pipes.
+func (w *warmProcess) discard() {
+	killProcessGroup(w.process, time.Second)
+	_ = w.stdin.Close()
+	<-w.exited
+	_ = w.stdout.Close()
+	_ = w.stderr.Close()
+}
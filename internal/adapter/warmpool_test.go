@@ -0,0 +1,175 @@
+package adapter
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// catSpec launches cat, which waits on stdin like a warm adapter CLI.
+func catSpec(t *testing.T) WarmSpec {
+	t.Helper()
+	return WarmSpec{Path: "/bin/cat", Dir: t.TempDir(), Env: []string{"A=1"}}
+}
+
+// waitIdle waits until the pool holds n idle processes for spec.
+func waitIdle(t *testing.T, pool *WarmPool, spec WarmSpec, n int) []*warmProcess {
+	t.Helper()
+	key := spec.key()
+	var procs []*warmProcess
+	require.Eventually(t, func() bool {
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+		procs = append([]*warmProcess(nil), pool.idle[key]...)
+		return len(procs) == n
+	}, 5*time.Second, 10*time.Millisecond)
+	return procs
+}
+
+func TestWarmPool_TakeAndReplenish(t *testing.T) {
+	pool := NewWarmPool(WarmPoolConfig{})
+	defer pool.Close()
+	spec := catSpec(t)
+
+	assert.Nil(t, pool.take(spec), "an empty pool has nothing to hand out")
+
+	pool.replenish(spec)
+	warmed := waitIdle(t, pool, spec, 1)[0]
+	pool.replenish(spec) // already warm: no second process
+
+	other := spec
+	other.Env = []string{"A=2"}
+	assert.Nil(t, pool.take(other), "a process only serves its own launch signature")
+
+	proc := pool.take(spec)
+	require.NotNil(t, proc)
+	assert.Same(t, warmed, proc)
+	_, err := io.WriteString(proc.stdin, "hello")
+	require.NoError(t, err)
+	require.NoError(t, proc.stdin.Close())
+	out, err := io.ReadAll(proc.stdout)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(out))
+	assert.NoError(t, proc.wait())
+}
+
+func TestWarmPool_FingerprintGuardsStartupFiles(t *testing.T) {
+	pool := NewWarmPool(WarmPoolConfig{})
+	defer pool.Close()
+	spec := catSpec(t)
+	spec.Fingerprint = func() string { return fingerprintFiles(spec.Dir, "settings.json") }
+	settings := filepath.Join(spec.Dir, "settings.json")
+	require.NoError(t, os.WriteFile(settings, []byte(`{"a":1}`), 0644))
+
+	pool.replenish(spec)
+	waitIdle(t, pool, spec, 1)
+
+	require.NoError(t, os.WriteFile(settings, []byte(`{"a":2}`), 0644))
+	assert.Nil(t, pool.take(spec), "a process started with other settings must not be reused")
+}
+
+func TestWarmPool_ReapsIdleAndDeadProcesses(t *testing.T) {
+	pool := NewWarmPool(WarmPoolConfig{IdleTimeout: time.Minute, HealthInterval: time.Hour})
+	defer pool.Close()
+
+	stale, dead := catSpec(t), catSpec(t)
+	pool.replenish(stale)
+	pool.replenish(dead)
+	staleProc := waitIdle(t, pool, stale, 1)[0]
+	deadProc := waitIdle(t, pool, dead, 1)[0]
+
+	require.NoError(t, deadProc.process.Kill())
+	<-deadProc.exited
+	pool.reap(staleProc.warmedAt.Add(time.Minute))
+
+	assert.Nil(t, pool.take(stale))
+	assert.Nil(t, pool.take(dead))
+	select {
+	case <-staleProc.exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reaped process was not killed")
+	}
+}
+
+func TestWarmPool_EvictsLongestIdleBeyondMaxIdle(t *testing.T) {
+	pool := NewWarmPool(WarmPoolConfig{MaxIdle: 1})
+	defer pool.Close()
+
+	first, second := catSpec(t), catSpec(t)
+	pool.replenish(first)
+	waitIdle(t, pool, first, 1)
+	pool.replenish(second)
+	waitIdle(t, pool, second, 1)
+
+	assert.Nil(t, pool.take(first))
+	assert.NotNil(t, pool.take(second))
+}
+
+func TestWarmPool_CloseKillsIdleProcesses(t *testing.T) {
+	pool := NewWarmPool(WarmPoolConfig{})
+	spec := catSpec(t)
+	pool.replenish(spec)
+	proc := waitIdle(t, pool, spec, 1)[0]
+
+	pool.Close()
+	assert.False(t, proc.healthy())
+	pool.replenish(spec)
+	assert.Nil(t, pool.take(spec))
+}
+
+func TestClaudeAdapter_UsesWarmProcess(t *testing.T) {
+	// The fake claude reports its PID and the prompt it read from stdin,
+	// and exits 1 when the prompt is "fail".
+	dir := t.TempDir()
+	claude := filepath.Join(dir, "claude")
+	script := "#!/bin/sh\nprompt=$(cat)\nprintf '{\"type\":\"result\",\"subtype\":\"success\",\"result\":\"%s %s\"}\\n' \"$$\" \"$prompt\"\n[ \"$prompt\" != fail ]\n"
+	require.NoError(t, os.WriteFile(claude, []byte(script), 0755))
+
+	pool := NewWarmPool(WarmPoolConfig{})
+	defer pool.Close()
+	registry := NewAdapterRegistry(nil)
+	registry.SetWarmPool(pool)
+	runner := registry.Resolve("claude").(*ClaudeAdapter)
+	runner.claudePath = claude
+
+	cfg := AdapterRunConfig{WorkspacePath: t.TempDir(), Prompt: "first", Timeout: 10 * time.Second, WarmRetry: true}
+	spec := WarmSpec{
+		Path:        claude,
+		Args:        runner.buildArgs(),
+		Dir:         cfg.WorkspacePath,
+		Env:         runner.buildEnvironment(cfg),
+		Fingerprint: func() string { return fingerprintFiles(cfg.WorkspacePath, claudeStartupFiles...) },
+	}
+
+	// A successful attempt is not retried, so it leaves nothing warm.
+	result, err := runner.Run(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Contains(t, result.ResultContent, " first")
+	assert.Nil(t, pool.take(spec))
+
+	// A failed attempt on the last try leaves nothing warm either.
+	cfg.Prompt, cfg.WarmRetry = "fail", false
+	result, err = runner.Run(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ExitCode)
+	assert.Nil(t, pool.take(spec))
+
+	// A failed attempt that is retried warms a process for the retry.
+	cfg.WarmRetry = true
+	result, err = runner.Run(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ExitCode)
+	warm := waitIdle(t, pool, spec, 1)[0]
+
+	cfg.Prompt = "second"
+	result, err = runner.Run(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(warm.process.Pid)+" second", result.ResultContent)
+}
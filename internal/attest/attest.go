@@ -0,0 +1,251 @@
+// Package attest builds provenance attestations for pipeline runs.
+//
+// An attestation is an in-toto Statement (https://in-toto.io/Statement/v1)
+// carrying a SLSA v1 provenance predicate: the digests of the definitions
+// the run started from (wave.yaml, pipeline YAML, persona prompts), the
+// adapter and model each step ran on, the run input, and the deliverables
+// it produced. Branches and files become subjects with content digests;
+// PRs, issues and URLs are recorded as byproducts. Statements are wrapped
+// in a DSSE envelope and optionally signed with an ed25519 key.
+package attest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/git"
+	"github.com/recinq/wave/internal/state"
+)
+
+// Type URIs used in the statement.
+const (
+	StatementType = "https://in-toto.io/Statement/v1"
+	PredicateType = "https://slsa.dev/provenance/v1"
+	BuildType     = "https://github.com/re-cinq/wave/run/v1"
+	BuilderID     = "https://github.com/re-cinq/wave"
+)
+
+// Statement is an in-toto v1 statement with a SLSA provenance predicate.
+type Statement struct {
+	Type          string     `json:"_type"`
+	Subject       []Subject  `json:"subject"`
+	PredicateType string     `json:"predicateType"`
+	Predicate     Provenance `json:"predicate"`
+}
+
+// Subject is an artifact the run produced, identified by digest.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Provenance is the SLSA v1 provenance predicate.
+type Provenance struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition describes what the run was asked to do.
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   ExternalParameters   `json:"externalParameters"`
+	InternalParameters   InternalParameters   `json:"internalParameters"`
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies,omitempty"`
+}
+
+// ExternalParameters are the caller-controlled inputs of the run.
+type ExternalParameters struct {
+	Pipeline string `json:"pipeline"`
+	Input    string `json:"input"`
+}
+
+// InternalParameters record how the run executed.
+type InternalParameters struct {
+	Status string    `json:"status"`
+	Steps  []StepRun `json:"steps,omitempty"`
+}
+
+// StepRun is the adapter and model a step last ran on.
+type StepRun struct {
+	ID             string `json:"id"`
+	Persona        string `json:"persona,omitempty"`
+	Adapter        string `json:"adapter,omitempty"`
+	AdapterVersion string `json:"adapterVersion,omitempty"`
+	Model          string `json:"model,omitempty"`
+}
+
+// ResourceDescriptor references a definition or deliverable.
+type ResourceDescriptor struct {
+	Name        string            `json:"name"`
+	URI         string            `json:"uri,omitempty"`
+	Digest      map[string]string `json:"digest,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// RunDetails describe who ran the build and when.
+type RunDetails struct {
+	Builder    Builder              `json:"builder"`
+	Metadata   Metadata             `json:"metadata"`
+	Byproducts []ResourceDescriptor `json:"byproducts,omitempty"`
+}
+
+// Builder identifies the Wave build that executed the run.
+type Builder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+// Metadata holds the run ID and timing.
+type Metadata struct {
+	InvocationID string    `json:"invocationId"`
+	StartedOn    time.Time `json:"startedOn"`
+	FinishedOn   time.Time `json:"finishedOn"`
+}
+
+// Run is everything the executor knows about a finished run.
+type Run struct {
+	RunID        string
+	Pipeline     string
+	Input        string
+	Status       string
+	StartedAt    time.Time
+	FinishedAt   time.Time
+	Definitions  []state.DefinitionDigest
+	Steps        []StepRun
+	Deliverables []*state.OutcomeRecord
+}
+
+// NewStatement builds the attestation statement for a run. Branch
+// deliverables are resolved to their current commit and file deliverables
+// are hashed; ones that cannot be resolved are kept as byproducts so the
+// attestation still lists them.
+func NewStatement(r Run) *Statement {
+	s := &Statement{
+		Type:          StatementType,
+		Subject:       []Subject{},
+		PredicateType: PredicateType,
+		Predicate: Provenance{
+			BuildDefinition: BuildDefinition{
+				BuildType:          BuildType,
+				ExternalParameters: ExternalParameters{Pipeline: r.Pipeline, Input: r.Input},
+				InternalParameters: InternalParameters{Status: r.Status, Steps: r.Steps},
+			},
+			RunDetails: RunDetails{
+				Builder: Builder{ID: BuilderID, Version: BuilderVersion()},
+				Metadata: Metadata{
+					InvocationID: r.RunID,
+					StartedOn:    r.StartedAt.UTC(),
+					FinishedOn:   r.FinishedAt.UTC(),
+				},
+			},
+		},
+	}
+
+	for _, d := range r.Definitions {
+		s.Predicate.BuildDefinition.ResolvedDependencies = append(s.Predicate.BuildDefinition.ResolvedDependencies, ResourceDescriptor{
+			Name:        d.Kind + ":" + d.Name,
+			URI:         d.Path,
+			Digest:      map[string]string{"sha256": d.Digest},
+			Annotations: map[string]string{"kind": d.Kind},
+		})
+	}
+
+	for _, o := range r.Deliverables {
+		if subject, ok := subjectFor(o); ok {
+			s.Subject = append(s.Subject, subject)
+			continue
+		}
+		annotations := map[string]string{"type": string(o.Type)}
+		if o.StepID != "" {
+			annotations["step"] = o.StepID
+		}
+		s.Predicate.RunDetails.Byproducts = append(s.Predicate.RunDetails.Byproducts, ResourceDescriptor{
+			Name:        string(o.Type) + ":" + o.Label,
+			URI:         o.Value,
+			Annotations: annotations,
+		})
+	}
+	return s
+}
+
+// subjectFor resolves a deliverable to a digest-identified subject.
+func subjectFor(o *state.OutcomeRecord) (Subject, bool) {
+	switch o.Type {
+	case state.OutcomeTypeBranch:
+		sha, err := git.ResolveRef("refs/heads/" + o.Label)
+		if err != nil || sha == "" {
+			return Subject{}, false
+		}
+		return Subject{Name: "branch:" + o.Label, Digest: map[string]string{"gitCommit": sha}}, true
+	case state.OutcomeTypeFile, state.OutcomeTypeArtifact:
+		sum, err := fileDigest(o.Value)
+		if err != nil {
+			return Subject{}, false
+		}
+		return Subject{Name: o.Value, Digest: map[string]string{"sha256": sum}}, true
+	}
+	return Subject{}, false
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", os.ErrInvalid
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// BuilderVersion reports the Wave module version and VCS revision baked
+// into the binary, when available.
+func BuilderVersion() map[string]string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	v := map[string]string{"wave": info.Main.Version}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			v["revision"] = setting.Value
+		}
+	}
+	return v
+}
+
+// versionProbeTimeout bounds `<binary> --version` so a hung CLI cannot
+// stall run finalization.
+const versionProbeTimeout = 5 * time.Second
+
+// AdapterVersion returns the first line of `<binary> --version`, or "" when
+// the binary is missing or does not answer in time.
+func AdapterVersion(binary string) string {
+	if binary == "" {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), versionProbeTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, binary, "--version").Output()
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(line)
+}
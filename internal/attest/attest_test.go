@@ -0,0 +1,81 @@
+package attest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/git"
+	"github.com/recinq/wave/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStatement(t *testing.T) {
+	prev := git.SetRunner(func(args ...string) ([]byte, error) {
+		if args[len(args)-1] == "refs/heads/feat/login^{commit}" {
+			return []byte("0123456789abcdef0123456789abcdef01234567\n"), nil
+		}
+		return nil, errors.New("unknown ref")
+	})
+	t.Cleanup(func() { git.SetRunner(prev) })
+
+	report := filepath.Join(t.TempDir(), "report.md")
+	require.NoError(t, os.WriteFile(report, []byte("done"), 0644))
+	sum := sha256.Sum256([]byte("done"))
+
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	s := NewStatement(Run{
+		RunID:      "impl-issue-20260301-100000-ab12",
+		Pipeline:   "impl-issue",
+		Input:      "https://github.com/o/r/issues/1",
+		Status:     "completed",
+		StartedAt:  start,
+		FinishedAt: start.Add(time.Minute),
+		Definitions: []state.DefinitionDigest{
+			{Kind: state.DefinitionManifest, Name: "wave.yaml", Digest: "aaa"},
+		},
+		Steps: []StepRun{{ID: "implement", Persona: "craftsman", Adapter: "claude", Model: "sonnet"}},
+		Deliverables: []*state.OutcomeRecord{
+			{Type: state.OutcomeTypeBranch, Label: "feat/login", StepID: "implement"},
+			{Type: state.OutcomeTypeBranch, Label: "gone"},
+			{Type: state.OutcomeTypeFile, Label: "report", Value: report},
+			{Type: state.OutcomeTypePR, Label: "PR #7", Value: "https://github.com/o/r/pull/7", StepID: "publish"},
+		},
+	})
+
+	assert.Equal(t, StatementType, s.Type)
+	assert.Equal(t, PredicateType, s.PredicateType)
+	assert.Equal(t, []Subject{
+		{Name: "branch:feat/login", Digest: map[string]string{"gitCommit": "0123456789abcdef0123456789abcdef01234567"}},
+		{Name: report, Digest: map[string]string{"sha256": hex.EncodeToString(sum[:])}},
+	}, s.Subject)
+
+	bd := s.Predicate.BuildDefinition
+	assert.Equal(t, ExternalParameters{Pipeline: "impl-issue", Input: "https://github.com/o/r/issues/1"}, bd.ExternalParameters)
+	assert.Equal(t, "completed", bd.InternalParameters.Status)
+	assert.Equal(t, "sonnet", bd.InternalParameters.Steps[0].Model)
+	require.Len(t, bd.ResolvedDependencies, 1)
+	assert.Equal(t, "manifest:wave.yaml", bd.ResolvedDependencies[0].Name)
+	assert.Equal(t, "aaa", bd.ResolvedDependencies[0].Digest["sha256"])
+
+	rd := s.Predicate.RunDetails
+	assert.Equal(t, "impl-issue-20260301-100000-ab12", rd.Metadata.InvocationID)
+	assert.Equal(t, start.Add(time.Minute), rd.Metadata.FinishedOn)
+	require.Len(t, rd.Byproducts, 2)
+	assert.Equal(t, "branch:gone", rd.Byproducts[0].Name)
+	assert.Equal(t, ResourceDescriptor{
+		Name:        "pr:PR #7",
+		URI:         "https://github.com/o/r/pull/7",
+		Annotations: map[string]string{"type": "pr", "step": "publish"},
+	}, rd.Byproducts[1])
+}
+
+func TestAdapterVersion(t *testing.T) {
+	assert.Empty(t, AdapterVersion(""))
+	assert.Empty(t, AdapterVersion("wave-no-such-binary-xyz"))
+}
@@ -0,0 +1,207 @@
+package attest
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// PayloadType is the DSSE payload type for in-toto statements.
+const PayloadType = "application/vnd.in-toto+json"
+
+// Envelope is a DSSE envelope (https://github.com/secure-systems-lab/dsse).
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is one DSSE signature.
+type Signature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   string `json:"sig"`
+}
+
+// ErrUnsigned is returned by Verify for an envelope with no signatures.
+var ErrUnsigned = errors.New("attestation is not signed")
+
+// NewEnvelope wraps a statement in an unsigned envelope.
+func NewEnvelope(s *Statement) (*Envelope, error) {
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal statement: %w", err)
+	}
+	return &Envelope{
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{},
+	}, nil
+}
+
+// Sign appends an ed25519 signature over the envelope's payload.
+func (e *Envelope) Sign(key ed25519.PrivateKey) error {
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return fmt.Errorf("invalid payload encoding: %w", err)
+	}
+	keyID, err := KeyID(key.Public().(ed25519.PublicKey))
+	if err != nil {
+		return err
+	}
+	sig := ed25519.Sign(key, pae(e.PayloadType, payload))
+	e.Signatures = append(e.Signatures, Signature{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)})
+	return nil
+}
+
+// Verify checks that at least one signature was made by key.
+func (e *Envelope) Verify(key ed25519.PublicKey) error {
+	if len(e.Signatures) == 0 {
+		return ErrUnsigned
+	}
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return fmt.Errorf("invalid payload encoding: %w", err)
+	}
+	msg := pae(e.PayloadType, payload)
+	for _, s := range e.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			continue
+		}
+		if ed25519.Verify(key, msg, sig) {
+			return nil
+		}
+	}
+	return errors.New("no signature matches the given key")
+}
+
+// Statement decodes the envelope's payload.
+func (e *Envelope) Statement() (*Statement, error) {
+	if e.PayloadType != PayloadType {
+		return nil, fmt.Errorf("unexpected payload type %q", e.PayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload encoding: %w", err)
+	}
+	var s Statement
+	if err := json.Unmarshal(payload, &s); err != nil {
+		return nil, fmt.Errorf("invalid statement: %w", err)
+	}
+	return &s, nil
+}
+
+// pae is the DSSE pre-authentication encoding that signatures cover.
+func pae(payloadType string, payload []byte) []byte {
+	out := "DSSEv1 " + strconv.Itoa(len(payloadType)) + " " + payloadType + " " + strconv.Itoa(len(payload)) + " "
+	return append([]byte(out), payload...)
+}
+
+// KeyID identifies a public key by the SHA-256 of its PKIX encoding.
+func KeyID(pub ed25519.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// LoadPrivateKey reads a PEM-encoded PKCS#8 ed25519 private key, as
+// written by `openssl genpkey -algorithm ed25519`.
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", path, err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an ed25519 private key", path)
+	}
+	return edKey, nil
+}
+
+// LoadPublicKey reads a PEM-encoded ed25519 public key. A private key file
+// is accepted too; its public half is returned.
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type == "PRIVATE KEY" {
+		priv, err := LoadPrivateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return priv.Public().(ed25519.PublicKey), nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an ed25519 public key", path)
+	}
+	return edKey, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not PEM encoded", path)
+	}
+	return block, nil
+}
+
+// Path returns the attestation file path for a run within dir.
+func Path(dir, runID string) string {
+	return filepath.Join(dir, runID+".intoto.json")
+}
+
+// Write stores the envelope as <dir>/<run-id>.intoto.json and returns the
+// path.
+func Write(dir, runID string, env *Envelope) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create attestation directory: %w", err)
+	}
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal attestation: %w", err)
+	}
+	path := Path(dir, runID)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write attestation: %w", err)
+	}
+	return path, nil
+}
+
+// Read loads an envelope written by Write.
+func Read(path string) (*Envelope, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attestation: %w", err)
+	}
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("invalid attestation %s: %w", path, err)
+	}
+	return &env, nil
+}
@@ -0,0 +1,92 @@
+package attest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKeyPair writes a PEM ed25519 key pair the way openssl does and
+// returns the private and public key paths.
+func writeKeyPair(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	privPath := filepath.Join(dir, "attest.key")
+	pubPath := filepath.Join(dir, "attest.pub")
+	require.NoError(t, os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0600))
+	require.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0644))
+	return privPath, pubPath
+}
+
+func TestEnvelopeSignVerify(t *testing.T) {
+	dir := t.TempDir()
+	privPath, pubPath := writeKeyPair(t, dir)
+	priv, err := LoadPrivateKey(privPath)
+	require.NoError(t, err)
+	pub, err := LoadPublicKey(pubPath)
+	require.NoError(t, err)
+
+	env, err := NewEnvelope(NewStatement(Run{RunID: "run-1", Pipeline: "p", Status: "completed"}))
+	require.NoError(t, err)
+	assert.ErrorIs(t, env.Verify(pub), ErrUnsigned)
+
+	require.NoError(t, env.Sign(priv))
+	keyID, err := KeyID(pub)
+	require.NoError(t, err)
+	assert.Equal(t, keyID, env.Signatures[0].KeyID)
+
+	path, err := Write(dir, "run-1", env)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "run-1.intoto.json"), path)
+
+	loaded, err := Read(path)
+	require.NoError(t, err)
+	assert.NoError(t, loaded.Verify(pub))
+	stmt, err := loaded.Statement()
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", stmt.Predicate.RunDetails.Metadata.InvocationID)
+
+	// The private key file also yields the public key.
+	fromPriv, err := LoadPublicKey(privPath)
+	require.NoError(t, err)
+	assert.Equal(t, pub, fromPriv)
+
+	_, otherPub := writeKeyPair(t, t.TempDir())
+	other, err := LoadPublicKey(otherPub)
+	require.NoError(t, err)
+	assert.Error(t, loaded.Verify(other))
+
+	tampered, err := NewEnvelope(NewStatement(Run{RunID: "run-1", Pipeline: "p", Status: "failed"}))
+	require.NoError(t, err)
+	tampered.Signatures = loaded.Signatures
+	assert.Error(t, tampered.Verify(pub))
+}
+
+func TestLoadKeyErrors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "key.txt")
+	require.NoError(t, os.WriteFile(notPEM, []byte("hello"), 0600))
+
+	_, err := LoadPrivateKey(notPEM)
+	assert.ErrorContains(t, err, "not PEM encoded")
+	_, err = LoadPublicKey(filepath.Join(dir, "missing.pub"))
+	assert.ErrorContains(t, err, "failed to read key")
+}
+
+func TestPAE(t *testing.T) {
+	assert.Equal(t, "DSSEv1 29 http://example.com/HelloWorld 11 hello world",
+		string(pae("http://example.com/HelloWorld", []byte("hello world"))))
+}
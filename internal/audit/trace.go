@@ -19,6 +19,7 @@ const (
 	TraceArtifactPreserved = "artifact_preserved"
 	TraceThreadInject      = "thread_inject" // Thread transcript prepended to step prompt
 	TraceThreadAppend      = "thread_append" // Step output appended to thread transcript
+	TraceStepEnv           = "step_env"      // Env passed to the adapter process (values redacted)
 )
 
 // TraceEvent represents a single structured trace event written as NDJSON.
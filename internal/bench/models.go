@@ -0,0 +1,370 @@
+package bench
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/state"
+)
+
+// DefaultModelPipeline is the pipeline a model benchmark runs when none is
+// given: a minimal two-step pipeline guarded by contracts.
+const DefaultModelPipeline = "ops-hello-world"
+
+// ModelBenchTag marks runs started by a model benchmark. Each run is also
+// tagged with its adapter, model and label so the metrics recorded for it
+// (performance_metric, pipeline_eval) can be grouped later.
+const ModelBenchTag = "bench:models"
+
+// ModelTarget is one adapter/model pairing measured by a model benchmark.
+// An empty Adapter runs each step on its persona's configured adapter.
+type ModelTarget struct {
+	Adapter string `json:"adapter,omitempty"`
+	Model   string `json:"model"`
+}
+
+// String renders the target as "adapter/model", or just the model when no
+// adapter is pinned.
+func (t ModelTarget) String() string {
+	if t.Adapter == "" {
+		return t.Model
+	}
+	return t.Adapter + "/" + t.Model
+}
+
+// ParseModelTarget parses "adapter:model" or a bare model name. The model
+// may itself contain '/' (e.g. "opencode:openai/gpt-4o").
+func ParseModelTarget(s string) (ModelTarget, error) {
+	s = strings.TrimSpace(s)
+	adapterName, model, ok := strings.Cut(s, ":")
+	if !ok {
+		adapterName, model = "", s
+	}
+	if model == "" || (ok && adapterName == "") {
+		return ModelTarget{}, fmt.Errorf("invalid model target %q: expected model or adapter:model", s)
+	}
+	return ModelTarget{Adapter: adapterName, Model: model}, nil
+}
+
+// ConfiguredModelTargets returns every model wave.yaml names for an
+// adapter, through default_model or tier_models, sorted and deduplicated.
+func ConfiguredModelTargets(m *manifest.Manifest) []ModelTarget {
+	seen := make(map[ModelTarget]bool)
+	var targets []ModelTarget
+	add := func(adapterName, model string) {
+		t := ModelTarget{Adapter: adapterName, Model: model}
+		if model != "" && !seen[t] {
+			seen[t] = true
+			targets = append(targets, t)
+		}
+	}
+	for name, a := range m.Adapters {
+		add(name, a.DefaultModel)
+		for _, model := range a.TierModels {
+			add(name, model)
+		}
+	}
+	slices.SortFunc(targets, func(a, b ModelTarget) int {
+		return cmp.Or(cmp.Compare(a.Adapter, b.Adapter), cmp.Compare(a.Model, b.Model))
+	})
+	return targets
+}
+
+// ModelRunConfig holds the configuration for a model benchmark.
+type ModelRunConfig struct {
+	// Pipeline is run once per target and repetition.
+	Pipeline string
+	// Input is passed to every run.
+	Input string
+	// Runs is the number of repetitions per target. Defaults to 1.
+	Runs int
+	// Targets are the adapter/model pairings to compare.
+	Targets []ModelTarget
+	// RunLabel is a human-readable label for this benchmark.
+	RunLabel string
+	// RunTimeout bounds each run. Zero means no timeout.
+	RunTimeout time.Duration
+	// Manifest is the manifest path passed to each run.
+	Manifest string
+}
+
+// ModelSample records one pipeline run of a model benchmark.
+type ModelSample struct {
+	Adapter      string      `json:"adapter,omitempty"`
+	Model        string      `json:"model"`
+	Repetition   int         `json:"repetition"`
+	RunID        string      `json:"run_id,omitempty"`
+	Status       BenchStatus `json:"status"`
+	DurationMs   int64       `json:"duration_ms"`
+	TokensUsed   int         `json:"tokens_used"`
+	CostDollars  float64     `json:"cost_dollars"`
+	ContractPass *bool       `json:"contract_pass,omitempty"`
+	Error        string      `json:"error,omitempty"`
+}
+
+// ModelScore aggregates the samples of one target.
+type ModelScore struct {
+	Rank    int    `json:"rank"`
+	Adapter string `json:"adapter,omitempty"`
+	Model   string `json:"model"`
+	Runs    int    `json:"runs"`
+	Passed  int    `json:"passed"`
+	// PassRate is the fraction of runs that completed.
+	PassRate float64 `json:"pass_rate"`
+	// ContractPassRate is the fraction of runs with a contract verdict
+	// whose contracts all passed; ContractRuns counts those runs.
+	ContractRuns     int     `json:"contract_runs"`
+	ContractPassRate float64 `json:"contract_pass_rate"`
+	AvgDurationMs    int64   `json:"avg_duration_ms"`
+	AvgTokens        int     `json:"avg_tokens"`
+	AvgCostDollars   float64 `json:"avg_cost_dollars"`
+}
+
+// ModelReport aggregates a model benchmark. Scores are ranked best first.
+type ModelReport struct {
+	Pipeline      string        `json:"pipeline"`
+	Input         string        `json:"input,omitempty"`
+	RunLabel      string        `json:"run_label,omitempty"`
+	RunsPerTarget int           `json:"runs_per_target"`
+	Scores        []ModelScore  `json:"scores"`
+	Samples       []ModelSample `json:"samples"`
+	StartedAt     time.Time     `json:"started_at"`
+	CompletedAt   time.Time     `json:"completed_at"`
+	DurationMs    int64         `json:"duration_ms"`
+}
+
+// Rank recalculates Scores from Samples, ordered by pass rate, then
+// contract pass rate, then lower average cost, then lower average duration.
+func (r *ModelReport) Rank() {
+	byTarget := make(map[ModelTarget]*ModelScore)
+	var order []ModelTarget
+	for _, s := range r.Samples {
+		t := ModelTarget{Adapter: s.Adapter, Model: s.Model}
+		score, ok := byTarget[t]
+		if !ok {
+			score = &ModelScore{Adapter: s.Adapter, Model: s.Model}
+			byTarget[t] = score
+			order = append(order, t)
+		}
+		score.Runs++
+		if s.Status == StatusPass {
+			score.Passed++
+		}
+		if s.ContractPass != nil {
+			score.ContractRuns++
+			if *s.ContractPass {
+				score.ContractPassRate++
+			}
+		}
+		score.AvgDurationMs += s.DurationMs
+		score.AvgTokens += s.TokensUsed
+		score.AvgCostDollars += s.CostDollars
+	}
+
+	r.Scores = make([]ModelScore, 0, len(order))
+	for _, t := range order {
+		score := byTarget[t]
+		score.PassRate = float64(score.Passed) / float64(score.Runs)
+		if score.ContractRuns > 0 {
+			score.ContractPassRate /= float64(score.ContractRuns)
+		}
+		score.AvgDurationMs /= int64(score.Runs)
+		score.AvgTokens /= score.Runs
+		score.AvgCostDollars /= float64(score.Runs)
+		r.Scores = append(r.Scores, *score)
+	}
+	slices.SortStableFunc(r.Scores, func(a, b ModelScore) int {
+		return cmp.Or(
+			cmp.Compare(b.PassRate, a.PassRate),
+			cmp.Compare(b.ContractPassRate, a.ContractPassRate),
+			cmp.Compare(a.AvgCostDollars, b.AvgCostDollars),
+			cmp.Compare(a.AvgDurationMs, b.AvgDurationMs),
+		)
+	})
+	for i := range r.Scores {
+		r.Scores[i].Rank = i + 1
+	}
+}
+
+// modelRunner executes a single pipeline run for a target. This enables
+// testing without subprocess execution.
+type modelRunner interface {
+	RunModel(ctx context.Context, target ModelTarget, cfg ModelRunConfig) (*ModelSample, error)
+}
+
+// RunModelBenchmark runs cfg.Pipeline cfg.Runs times per target and returns
+// a ranked report. Runs are sequential, since concurrent runs of one
+// pipeline would contend for the same workspaces, and targets are
+// interleaved per repetition so drift in provider latency spreads evenly.
+func RunModelBenchmark(ctx context.Context, cfg ModelRunConfig, runner modelRunner) (*ModelReport, error) {
+	if cfg.Pipeline == "" {
+		return nil, fmt.Errorf("pipeline name is required")
+	}
+	if len(cfg.Targets) == 0 {
+		return nil, fmt.Errorf("at least one model target is required")
+	}
+	runs := cfg.Runs
+	if runs < 1 {
+		runs = 1
+	}
+
+	report := &ModelReport{
+		Pipeline:      cfg.Pipeline,
+		Input:         cfg.Input,
+		RunLabel:      cfg.RunLabel,
+		RunsPerTarget: runs,
+		StartedAt:     time.Now(),
+	}
+
+	total := runs * len(cfg.Targets)
+	n := 0
+	for rep := 1; rep <= runs && ctx.Err() == nil; rep++ {
+		for _, target := range cfg.Targets {
+			if ctx.Err() != nil {
+				break
+			}
+			n++
+			fmt.Fprintf(os.Stderr, "[%d/%d] Running %s with %s (run %d)...\n", n, total, cfg.Pipeline, target, rep)
+
+			runCtx := ctx
+			var cancel context.CancelFunc
+			if cfg.RunTimeout > 0 {
+				runCtx, cancel = context.WithTimeout(ctx, cfg.RunTimeout)
+			}
+			sample, err := runner.RunModel(runCtx, target, cfg)
+			if cancel != nil {
+				cancel()
+			}
+			if err != nil {
+				sample = &ModelSample{Status: StatusError, Error: err.Error()}
+			}
+			sample.Adapter = target.Adapter
+			sample.Model = target.Model
+			sample.Repetition = rep
+			report.Samples = append(report.Samples, *sample)
+		}
+	}
+
+	report.CompletedAt = time.Now()
+	report.DurationMs = time.Since(report.StartedAt).Milliseconds()
+	report.Rank()
+	return report, ctx.Err()
+}
+
+// ModelRunStore is the state store surface a model benchmark reads its
+// metrics from.
+type ModelRunStore interface {
+	CreateRun(pipelineName string, input string) (string, error)
+	SetRunTags(runID string, tags []string) error
+	GetRun(runID string) (*state.RunRecord, error)
+	GetEvalsForPipeline(pipelineName string, limit int) ([]state.PipelineEvalRecord, error)
+}
+
+// evalLookback bounds how many recent pipeline_eval rows are searched for
+// a finished run.
+const evalLookback = 50
+
+// modelSubprocessRunner runs each sample as a `wave run` subprocess with the
+// target's model forced onto every step, then reads the run's tokens,
+// status, cost and contract verdict back from the state store.
+type modelSubprocessRunner struct {
+	store   ModelRunStore
+	waveBin string
+}
+
+// NewModelSubprocessRunner creates a runner that records its runs in store.
+func NewModelSubprocessRunner(store ModelRunStore) *modelSubprocessRunner {
+	waveBin, err := os.Executable()
+	if err != nil {
+		waveBin = "wave"
+	}
+	return &modelSubprocessRunner{store: store, waveBin: waveBin}
+}
+
+// RunModel runs the benchmark pipeline once for target.
+func (s *modelSubprocessRunner) RunModel(ctx context.Context, target ModelTarget, cfg ModelRunConfig) (*ModelSample, error) {
+	runID, err := s.store.CreateRun(cfg.Pipeline, cfg.Input)
+	if err != nil {
+		return nil, fmt.Errorf("create run: %w", err)
+	}
+	_ = s.store.SetRunTags(runID, modelRunTags(target, cfg.RunLabel))
+
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, s.waveBin, modelRunArgs(target, cfg, runID)...)
+	output, runErr := cmd.CombinedOutput()
+	sample := &ModelSample{RunID: runID, DurationMs: time.Since(start).Milliseconds(), Status: StatusPass}
+
+	run, err := s.store.GetRun(runID)
+	if err != nil {
+		return nil, fmt.Errorf("read run %s: %w", runID, err)
+	}
+	sample.TokensUsed = run.TotalTokens
+	if run.CompletedAt != nil {
+		sample.DurationMs = run.CompletedAt.Sub(run.StartedAt).Milliseconds()
+	}
+	if runErr != nil || run.Status != "completed" {
+		sample.Status = StatusFail
+		sample.Error = run.ErrorMessage
+		if sample.Error == "" {
+			sample.Error = lastLine(string(output))
+		}
+		if sample.Error == "" && runErr != nil {
+			sample.Error = runErr.Error()
+		}
+	}
+
+	if evals, err := s.store.GetEvalsForPipeline(cfg.Pipeline, evalLookback); err == nil {
+		for _, ev := range evals {
+			if ev.RunID != runID {
+				continue
+			}
+			sample.ContractPass = ev.ContractPass
+			if ev.CostDollars != nil {
+				sample.CostDollars = *ev.CostDollars
+			}
+			break
+		}
+	}
+	return sample, nil
+}
+
+// modelRunArgs builds the `wave run` argv for one sample.
+func modelRunArgs(target ModelTarget, cfg ModelRunConfig, runID string) []string {
+	args := []string{"run", "--pipeline", cfg.Pipeline, "--run", runID,
+		"--model", target.Model, "--force-model", "--auto-approve", "--no-retro", "--output", "quiet"}
+	if cfg.Input != "" {
+		args = append(args, "--input", cfg.Input)
+	}
+	if target.Adapter != "" {
+		args = append(args, "--adapter", target.Adapter)
+	}
+	if cfg.Manifest != "" {
+		args = append(args, "--manifest", cfg.Manifest)
+	}
+	return args
+}
+
+// modelRunTags returns the tags recorded on a benchmark run.
+func modelRunTags(target ModelTarget, label string) []string {
+	tags := []string{ModelBenchTag, "model:" + target.Model}
+	if target.Adapter != "" {
+		tags = append(tags, "adapter:"+target.Adapter)
+	}
+	if label != "" {
+		tags = append(tags, "label:"+label)
+	}
+	return tags
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,214 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/state"
+)
+
+func TestParseModelTarget(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ModelTarget
+		wantErr bool
+	}{
+		{in: "haiku", want: ModelTarget{Model: "haiku"}},
+		{in: "claude:opus", want: ModelTarget{Adapter: "claude", Model: "opus"}},
+		{in: "opencode:openai/gpt-4o", want: ModelTarget{Adapter: "opencode", Model: "openai/gpt-4o"}},
+		{in: "", wantErr: true},
+		{in: ":haiku", wantErr: true},
+		{in: "claude:", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseModelTarget(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseModelTarget(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseModelTarget(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestConfiguredModelTargets(t *testing.T) {
+	m := &manifest.Manifest{Adapters: map[string]manifest.Adapter{
+		"claude": {DefaultModel: "sonnet", TierModels: map[string]string{
+			"cheapest": "haiku", "balanced": "sonnet", "strongest": "opus",
+		}},
+		"codex":  {DefaultModel: "gpt-4o"},
+		"gemini": {},
+	}}
+	want := []ModelTarget{
+		{Adapter: "claude", Model: "haiku"},
+		{Adapter: "claude", Model: "opus"},
+		{Adapter: "claude", Model: "sonnet"},
+		{Adapter: "codex", Model: "gpt-4o"},
+	}
+	if got := ConfiguredModelTargets(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("ConfiguredModelTargets() = %v, want %v", got, want)
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestModelReportRank(t *testing.T) {
+	report := &ModelReport{Samples: []ModelSample{
+		// opus: always passes, but costly.
+		{Model: "opus", Status: StatusPass, DurationMs: 3000, TokensUsed: 900, CostDollars: 0.30, ContractPass: boolPtr(true)},
+		{Model: "opus", Status: StatusPass, DurationMs: 1000, TokensUsed: 1100, CostDollars: 0.10, ContractPass: boolPtr(true)},
+		// haiku: always passes and is cheaper.
+		{Model: "haiku", Status: StatusPass, DurationMs: 1000, TokensUsed: 500, CostDollars: 0.01, ContractPass: boolPtr(true)},
+		{Model: "haiku", Status: StatusPass, DurationMs: 1000, TokensUsed: 500, CostDollars: 0.01, ContractPass: boolPtr(true)},
+		// gpt: fails one of two runs.
+		{Adapter: "codex", Model: "gpt-4o", Status: StatusPass, CostDollars: 0.001, ContractPass: boolPtr(true)},
+		{Adapter: "codex", Model: "gpt-4o", Status: StatusFail, ContractPass: boolPtr(false)},
+	}}
+	report.Rank()
+
+	var order []string
+	for _, s := range report.Scores {
+		order = append(order, s.Model)
+	}
+	if want := []string{"haiku", "opus", "gpt-4o"}; !slices.Equal(order, want) {
+		t.Fatalf("rank order = %v, want %v", order, want)
+	}
+
+	opus := report.Scores[1]
+	if opus.Rank != 2 || opus.Runs != 2 || opus.AvgDurationMs != 2000 || opus.AvgTokens != 1000 {
+		t.Errorf("opus score = %+v", opus)
+	}
+	if opus.AvgCostDollars < 0.199 || opus.AvgCostDollars > 0.201 {
+		t.Errorf("opus AvgCostDollars = %f, want 0.2", opus.AvgCostDollars)
+	}
+	gpt := report.Scores[2]
+	if gpt.PassRate != 0.5 || gpt.ContractRuns != 2 || gpt.ContractPassRate != 0.5 {
+		t.Errorf("gpt score = %+v", gpt)
+	}
+}
+
+type mockModelRunner struct {
+	calls []string
+	fail  map[string]error
+}
+
+func (m *mockModelRunner) RunModel(_ context.Context, target ModelTarget, _ ModelRunConfig) (*ModelSample, error) {
+	m.calls = append(m.calls, target.String())
+	if err := m.fail[target.String()]; err != nil {
+		return nil, err
+	}
+	return &ModelSample{RunID: "run-" + target.Model, Status: StatusPass, DurationMs: 10}, nil
+}
+
+func TestRunModelBenchmark(t *testing.T) {
+	runner := &mockModelRunner{fail: map[string]error{"claude/opus": errors.New("adapter not installed")}}
+	cfg := ModelRunConfig{
+		Pipeline: "ops-hello-world",
+		Runs:     2,
+		Targets:  []ModelTarget{{Adapter: "claude", Model: "haiku"}, {Adapter: "claude", Model: "opus"}},
+	}
+
+	report, err := RunModelBenchmark(context.Background(), cfg, runner)
+	if err != nil {
+		t.Fatalf("RunModelBenchmark() error = %v", err)
+	}
+
+	// Targets are interleaved per repetition.
+	if want := []string{"claude/haiku", "claude/opus", "claude/haiku", "claude/opus"}; !slices.Equal(runner.calls, want) {
+		t.Errorf("run order = %v, want %v", runner.calls, want)
+	}
+	if len(report.Samples) != 4 || report.Samples[3].Repetition != 2 || report.Samples[3].Model != "opus" {
+		t.Errorf("unexpected samples: %+v", report.Samples)
+	}
+	if report.Samples[1].Status != StatusError || report.Samples[1].Error != "adapter not installed" {
+		t.Errorf("runner error should be recorded as an error sample: %+v", report.Samples[1])
+	}
+	if len(report.Scores) != 2 || report.Scores[0].Model != "haiku" || report.Scores[1].PassRate != 0 {
+		t.Errorf("unexpected scores: %+v", report.Scores)
+	}
+
+	if _, err := RunModelBenchmark(context.Background(), ModelRunConfig{Pipeline: "p"}, runner); err == nil {
+		t.Error("expected an error without targets")
+	}
+}
+
+type fakeModelRunStore struct {
+	run   state.RunRecord
+	evals []state.PipelineEvalRecord
+	tags  []string
+}
+
+func (f *fakeModelRunStore) CreateRun(pipelineName, input string) (string, error) {
+	f.run.RunID = pipelineName + "-1"
+	return f.run.RunID, nil
+}
+
+func (f *fakeModelRunStore) SetRunTags(_ string, tags []string) error {
+	f.tags = tags
+	return nil
+}
+
+func (f *fakeModelRunStore) GetRun(string) (*state.RunRecord, error) {
+	run := f.run
+	return &run, nil
+}
+
+func (f *fakeModelRunStore) GetEvalsForPipeline(string, int) ([]state.PipelineEvalRecord, error) {
+	return f.evals, nil
+}
+
+func TestModelSubprocessRunner_ReadsRunMetrics(t *testing.T) {
+	started := time.Unix(1000, 0)
+	completed := started.Add(4 * time.Second)
+	cost := 0.25
+	store := &fakeModelRunStore{
+		run: state.RunRecord{Status: "completed", TotalTokens: 1234, StartedAt: started, CompletedAt: &completed},
+		evals: []state.PipelineEvalRecord{
+			{RunID: "other-run", ContractPass: boolPtr(false)},
+			{RunID: "p-1", ContractPass: boolPtr(true), CostDollars: &cost},
+		},
+	}
+	runner := &modelSubprocessRunner{store: store, waveBin: "true"}
+
+	sample, err := runner.RunModel(context.Background(), ModelTarget{Adapter: "claude", Model: "haiku"}, ModelRunConfig{Pipeline: "p", RunLabel: "nightly"})
+	if err != nil {
+		t.Fatalf("RunModel() error = %v", err)
+	}
+	if sample.RunID != "p-1" || sample.Status != StatusPass || sample.TokensUsed != 1234 || sample.DurationMs != 4000 {
+		t.Errorf("unexpected sample: %+v", sample)
+	}
+	if sample.ContractPass == nil || !*sample.ContractPass || sample.CostDollars != 0.25 {
+		t.Errorf("eval metrics not read: %+v", sample)
+	}
+	if want := []string{ModelBenchTag, "model:haiku", "adapter:claude", "label:nightly"}; !slices.Equal(store.tags, want) {
+		t.Errorf("tags = %v, want %v", store.tags, want)
+	}
+
+	store.run.Status = "failed"
+	store.run.ErrorMessage = "step greet failed"
+	runner.waveBin = "false"
+	sample, err = runner.RunModel(context.Background(), ModelTarget{Model: "haiku"}, ModelRunConfig{Pipeline: "p"})
+	if err != nil {
+		t.Fatalf("RunModel() error = %v", err)
+	}
+	if sample.Status != StatusFail || sample.Error != "step greet failed" {
+		t.Errorf("failed run should be a fail sample: %+v", sample)
+	}
+}
+
+func TestModelRunArgs(t *testing.T) {
+	got := modelRunArgs(ModelTarget{Adapter: "opencode", Model: "openai/gpt-4o"},
+		ModelRunConfig{Pipeline: "ops-hello-world", Input: "benchmark", Manifest: "wave.yaml"}, "run-1")
+	want := []string{"run", "--pipeline", "ops-hello-world", "--run", "run-1",
+		"--model", "openai/gpt-4o", "--force-model", "--auto-approve", "--no-retro", "--output", "quiet",
+		"--input", "benchmark", "--adapter", "opencode", "--manifest", "wave.yaml"}
+	if !slices.Equal(got, want) {
+		t.Errorf("modelRunArgs() = %v, want %v", got, want)
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/recinq/wave/internal/procutil"
 )
 
 // RunMode controls whether tasks run through Wave pipelines or standalone Claude.
@@ -200,7 +202,7 @@ func (s *subprocessRunner) runClaudeDirect(ctx context.Context, task BenchTask,
 // runTestCommand executes a shell command in the task directory.
 // Returns nil on success, or the combined output on failure.
 func (s *subprocessRunner) runTestCommand(ctx context.Context, dir, command string) []byte {
-	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd := procutil.ShellCommand(ctx, command)
 	cmd.Dir = dir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -9,6 +9,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+
+	"github.com/recinq/wave/internal/procutil"
 )
 
 // RunCmdFunc executes a command by name with arguments and returns an error
@@ -84,7 +86,8 @@ func Skill(runCmd RunCmdFunc, check string) SkillStatus {
 	if runCmd == nil {
 		runCmd = DefaultRunCmd
 	}
-	if err := runCmd("sh", "-c", check); err != nil {
+	sh := procutil.ShellArgs(check)
+	if err := runCmd(sh[0], sh[1:]...); err != nil {
 		return SkillStatus{HasCheck: true}
 	}
 	return SkillStatus{HasCheck: true, Installed: true}
@@ -119,5 +122,6 @@ func SkillInstalledWithToolBin(runCmd RunCmdFunc, runCmdEnv RunCmdEnvFunc, check
 	}
 	toolBin := filepath.Join(home, ".local", "bin")
 	enhancedPath := toolBin + string(os.PathListSeparator) + os.Getenv("PATH")
-	return runCmdEnv([]string{"PATH=" + enhancedPath}, "sh", "-c", check) == nil
+	sh := procutil.ShellArgs(check)
+	return runCmdEnv([]string{"PATH=" + enhancedPath}, sh[0], sh[1:]...) == nil
 }
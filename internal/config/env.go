@@ -132,7 +132,7 @@ func SubprocessHomePath() (home, path string) {
 	return env.Home, env.Path
 }
 
-// MigrationEnv groups the four WAVE_MIGRATION_* environment variables that
+// MigrationEnv groups the WAVE_*MIGRATION* environment variables that
 // configure the schema migration subsystem. A nil pointer field means the
 // corresponding env var was unset and the consumer should keep its default.
 //
@@ -144,12 +144,13 @@ type MigrationEnv struct {
 	Enabled              *bool
 	AutoMigrate          *bool
 	SkipValidation       *bool
+	SkipBackup           *bool
 	MaxVersion           *int
 	MaxVersionParseError error // non-nil when WAVE_MAX_MIGRATION_VERSION was set but failed to parse
 	MaxVersionRawValue   string
 }
 
-// LoadMigrationEnv reads the four WAVE_MIGRATION_* environment variables and
+// LoadMigrationEnv reads the WAVE_*MIGRATION* environment variables and
 // returns a MigrationEnv with each field populated only when its underlying
 // env var was set to a non-empty value. The version int parser surfaces an
 // explicit error rather than silently dropping malformed values.
@@ -167,6 +168,10 @@ func LoadMigrationEnv() MigrationEnv {
 		b := parseBoolish(v)
 		out.SkipValidation = &b
 	}
+	if v := os.Getenv("WAVE_SKIP_MIGRATION_BACKUP"); v != "" {
+		b := parseBoolish(v)
+		out.SkipBackup = &b
+	}
 	if v := os.Getenv("WAVE_MAX_MIGRATION_VERSION"); v != "" {
 		out.MaxVersionRawValue = v
 		n, err := strconv.Atoi(v)
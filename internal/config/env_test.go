@@ -125,13 +125,14 @@ func TestLoadMigrationEnv_AllUnset(t *testing.T) {
 		"WAVE_MIGRATION_ENABLED",
 		"WAVE_AUTO_MIGRATE",
 		"WAVE_SKIP_MIGRATION_VALIDATION",
+		"WAVE_SKIP_MIGRATION_BACKUP",
 		"WAVE_MAX_MIGRATION_VERSION",
 	} {
 		_ = os.Unsetenv(k)
 	}
 
 	got := LoadMigrationEnv()
-	if got.Enabled != nil || got.AutoMigrate != nil || got.SkipValidation != nil || got.MaxVersion != nil {
+	if got.Enabled != nil || got.AutoMigrate != nil || got.SkipValidation != nil || got.SkipBackup != nil || got.MaxVersion != nil {
 		t.Errorf("LoadMigrationEnv() with all unset returned non-nil pointers: %+v", got)
 	}
 	if got.MaxVersionParseError != nil {
@@ -143,6 +144,7 @@ func TestLoadMigrationEnv_AllSet(t *testing.T) {
 	t.Setenv("WAVE_MIGRATION_ENABLED", "false")
 	t.Setenv("WAVE_AUTO_MIGRATE", "yes")
 	t.Setenv("WAVE_SKIP_MIGRATION_VALIDATION", "1")
+	t.Setenv("WAVE_SKIP_MIGRATION_BACKUP", "true")
 	t.Setenv("WAVE_MAX_MIGRATION_VERSION", "7")
 
 	got := LoadMigrationEnv()
@@ -155,6 +157,9 @@ func TestLoadMigrationEnv_AllSet(t *testing.T) {
 	if got.SkipValidation == nil || *got.SkipValidation != true {
 		t.Errorf("SkipValidation = %v, want true", got.SkipValidation)
 	}
+	if got.SkipBackup == nil || *got.SkipBackup != true {
+		t.Errorf("SkipBackup = %v, want true", got.SkipBackup)
+	}
 	if got.MaxVersion == nil || *got.MaxVersion != 7 {
 		t.Errorf("MaxVersion = %v, want 7", got.MaxVersion)
 	}
@@ -59,4 +59,30 @@ type RuntimeConfig struct {
 	AutoApprove       bool   // --auto-approve flag for skipping approval gates
 	NoRetro           bool   // --no-retro flag to skip retrospective generation
 	ForceModel        bool   // --force-model overrides all step/persona model tiers
+	// Artifacts provides the artifacts of steps skipped by Steps/Exclude,
+	// keyed "<step>:<artifact>" with absolute paths (--artifact).
+	Artifacts map[string]string
+	// IfNotAlreadySucceeded skips the run when one with the same pipeline
+	// definition and input already succeeded or is in flight
+	// (--if-not-already-succeeded).
+	IfNotAlreadySucceeded bool
+	// Vars overrides values in the pipeline's vars block (--var key=value).
+	Vars map[string]string
+	// TemplateInputs holds values for the pipeline's input_templates,
+	// passed as --<template> value, keyed by template name.
+	TemplateInputs map[string]string
+	// Deterministic pins run IDs, template time and temperature so replays
+	// reproduce the same identifiers (--deterministic); Seed varies the IDs
+	// (--seed).
+	Deterministic bool
+	Seed          string
+	// NoAdapterCache bypasses the adapter response cache for steps that
+	// opt into it (--no-adapter-cache).
+	NoAdapterCache bool
+	// MaxParallel caps how many ready steps run at once, overriding
+	// runtime.max_parallel_steps (--max-parallel). 0 keeps the manifest limit.
+	MaxParallel int
+	// Priority is the class a detached run queues in while every worker
+	// slot is busy: interactive (default), scheduled or batch (--priority).
+	Priority string
 }
@@ -14,6 +14,7 @@ type ContractConfig struct {
 	Source      string   `json:"source,omitempty"        yaml:"source,omitempty"`
 	Schema      string   `json:"schema,omitempty"        yaml:"schema,omitempty"`
 	SchemaPath  string   `json:"schemaPath,omitempty"    yaml:"schema_path,omitempty"`
+	SchemaRef   string   `json:"schemaRef,omitempty"     yaml:"schema_ref,omitempty"` // Registry schema "name@vN"; resolved into SchemaPath at load time
 	Validate    bool     `json:"validate,omitempty"      yaml:"validate,omitempty"`
 	Command     string   `json:"command,omitempty"       yaml:"command,omitempty"`
 	CommandArgs []string `json:"commandArgs,omitempty"   yaml:"command_args,omitempty"`
@@ -31,10 +32,13 @@ type ContractConfig struct {
 	DisableWrapperDetection bool `json:"disable_wrapper_detection,omitempty" yaml:"disable_wrapper_detection,omitempty"` // Disable error wrapper detection (default: false, detection enabled)
 	DebugMode               bool `json:"debug_mode,omitempty"                yaml:"debug_mode,omitempty"`                // Enable debug logging for wrapper detection
 
+	// JSON repair settings
+	Repair *JSONRepairConfig `json:"repair,omitempty" yaml:"repair,omitempty"` // Repair malformed artifact JSON before failing (json_schema only)
+
 	// LLM judge settings
 	Model     string   `json:"model,omitempty"     yaml:"model,omitempty"`     // LLM model for judge evaluation; accepts tier names (cheapest, balanced, strongest) or literal model IDs
 	Criteria  []string `json:"criteria,omitempty"  yaml:"criteria,omitempty"`  // Evaluation criteria for LLM judge
-	Threshold float64  `json:"threshold,omitempty" yaml:"threshold,omitempty"` // Pass threshold (0.0-1.0), default 1.0
+	Threshold float64  `json:"threshold,omitempty" yaml:"threshold,omitempty"` // Pass threshold (0.0-1.0), default 1.0 (llm_judge) or 0.8 (coverage)
 
 	// Convergence tracking for rework loops
 	ConvergenceWindow         int     `json:"convergence_window,omitempty"          yaml:"convergence_window,omitempty"`          // Number of rounds to compare for stall detection (default 3)
@@ -66,6 +70,19 @@ type ContractConfig struct {
 	// test_count_baseline contract fields — post-commit defense-in-depth alongside test_diff.
 	BaseRef string `json:"base_ref,omitempty" yaml:"base_ref,omitempty"` // Git ref to compare HEAD against (default HEAD~1)
 
+	// coverage contract fields — Command runs the tests, Source names the report, Threshold is the minimum coverage of changed files.
+	// ChangedFiles is populated by the executor from the step's change summary, not from YAML; nil falls back to git diff HEAD.
+	ChangedFiles []string `json:"changedFiles,omitempty" yaml:"-"`
+
+	// dependency_policy contract fields — glob patterns (case-insensitive) over package names and SPDX license IDs
+	// of dependencies added to go.mod or package.json. Deny wins; a non-empty allow list rejects everything else.
+	// DiffBase is populated by the executor with the workspace tree before the step ran; BaseRef overrides it.
+	AllowPackages []string `json:"allow_packages,omitempty" yaml:"allow_packages,omitempty"`
+	DenyPackages  []string `json:"deny_packages,omitempty"  yaml:"deny_packages,omitempty"`
+	AllowLicenses []string `json:"allow_licenses,omitempty" yaml:"allow_licenses,omitempty"`
+	DenyLicenses  []string `json:"deny_licenses,omitempty"  yaml:"deny_licenses,omitempty"`
+	DiffBase      string   `json:"diffBase,omitempty" yaml:"-"`
+
 	// event_contains contract fields — validated by executor (needs event store access)
 	Events []EventPattern `json:"events,omitempty" yaml:"events,omitempty"` // Expected event patterns to match against the step's event log
 
@@ -83,6 +100,9 @@ type ValidationError struct {
 	Retryable    bool
 	Attempt      int
 	MaxRetries   int
+	// Output is the stdout of a command-based check (test_suite). It is
+	// kept for result artifacts and not included in Error().
+	Output string
 }
 
 func (e *ValidationError) Error() string {
@@ -135,6 +155,10 @@ func NewValidator(cfg ContractConfig) ContractValidator {
 		return &testDiffValidator{}
 	case "test_count_baseline":
 		return &testCountBaselineValidator{}
+	case "coverage":
+		return &coverageValidator{}
+	case "dependency_policy":
+		return &dependencyPolicyValidator{}
 	case "agent_review":
 		// agent_review requires an adapter runner — NewValidator returns nil.
 		// The executor uses ValidateWithRunner() instead for this type.
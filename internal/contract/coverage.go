@@ -0,0 +1,413 @@
+package contract
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// coverageValidator runs a coverage command and fails when the line
+// coverage of the files the step changed falls below Threshold. Only changed
+// files that appear in the report count: tests, docs and other files the
+// report does not instrument are ignored, and a step that changed none of
+// the covered files passes.
+//
+// The report format is detected from its content: Go cover profiles
+// ("mode: set"), lcov ("SF:" records) and Cobertura XML.
+type coverageValidator struct{}
+
+const defaultCoverageThreshold = 0.8
+
+// defaultCoverageReports are the report paths tried, relative to the
+// command's working directory, when no source is configured: what
+// `go test -coverprofile`, jest/vitest, c8 and pytest-cov write.
+var defaultCoverageReports = []string{
+	"coverage.out",
+	"cover.out",
+	"coverage/lcov.info",
+	"lcov.info",
+	"coverage.xml",
+	"coverage/cobertura-coverage.xml",
+}
+
+// fileCoverage counts the covered and coverable lines (statements, for Go)
+// of one file.
+type fileCoverage struct {
+	covered int
+	total   int
+}
+
+func (v *coverageValidator) Validate(cfg ContractConfig, workspacePath string) error {
+	parts := strings.Fields(cfg.Command)
+	if len(parts) == 0 {
+		return &ValidationError{
+			ContractType: "coverage",
+			Message:      "no coverage command configured",
+			Details:      []string{"set project.coverage_command in wave.yaml or the contract's 'command'"},
+			Retryable:    false,
+		}
+	}
+	if strings.Contains(cfg.Command, "{{") {
+		return &ValidationError{
+			ContractType: "coverage",
+			Message:      "unresolved template variable in contract command — configure project section in wave.yaml",
+			Details:      []string{fmt.Sprintf("command: %s", cfg.Command)},
+			Retryable:    false,
+		}
+	}
+	threshold := cfg.Threshold
+	if threshold <= 0 {
+		threshold = defaultCoverageThreshold
+	}
+
+	contractDir := cfg.Dir
+	if contractDir == "" {
+		contractDir = "project_root"
+	}
+	dir, err := resolveContractDir(contractDir, workspacePath)
+	if err != nil {
+		return &ValidationError{
+			ContractType: "coverage",
+			Message:      fmt.Sprintf("failed to resolve working directory: %v", err),
+			Retryable:    false,
+		}
+	}
+
+	changed := cfg.ChangedFiles
+	if changed == nil {
+		changed = gitChangedFiles(workspacePath)
+	}
+
+	start := time.Now()
+	cmd := exec.Command(parts[0], parts[1:]...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok {
+			return &ValidationError{
+				ContractType: "coverage",
+				Message:      fmt.Sprintf("coverage command failed (exit code %d)", exitError.ExitCode()),
+				Details:      extractTestSuiteDetails(parts[0], parts[1:], stdout.String(), stderr.String()),
+				Retryable:    true,
+				Output:       stdout.String(),
+			}
+		}
+		return &ValidationError{
+			ContractType: "coverage",
+			Message:      "coverage command execution failed",
+			Details:      []string{err.Error(), fmt.Sprintf("command: %s", cfg.Command), fmt.Sprintf("working directory: %s", dir)},
+			Retryable:    false,
+		}
+	}
+
+	reportPath, err := findCoverageReport(cfg.Source, dir, start)
+	if err != nil {
+		return &ValidationError{
+			ContractType: "coverage",
+			Message:      err.Error(),
+			Details:      []string{fmt.Sprintf("command: %s", cfg.Command), "set 'source' to the report the command writes"},
+			Retryable:    false,
+		}
+	}
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		return &ValidationError{ContractType: "coverage", Message: fmt.Sprintf("failed to read coverage report: %v", err)}
+	}
+	report, err := parseCoverageReport(data)
+	if err != nil {
+		return &ValidationError{
+			ContractType: "coverage",
+			Message:      fmt.Sprintf("failed to parse coverage report %s: %v", reportPath, err),
+			Retryable:    false,
+		}
+	}
+
+	files := changedFileCoverage(report, changed, cfg.Exclude)
+	var covered, total int
+	for _, fc := range files {
+		covered += fc.coverage.covered
+		total += fc.coverage.total
+	}
+	if total == 0 {
+		return nil
+	}
+	ratio := float64(covered) / float64(total)
+	if ratio >= threshold {
+		return nil
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].coverage.ratio() < files[j].coverage.ratio()
+	})
+	details := make([]string, 0, len(files))
+	for _, fc := range files {
+		details = append(details, fmt.Sprintf("%s: %.1f%% (%d/%d lines)", fc.path, 100*fc.coverage.ratio(), fc.coverage.covered, fc.coverage.total))
+	}
+	return &ValidationError{
+		ContractType: "coverage",
+		Message:      fmt.Sprintf("coverage of changed files is %.1f%% (%d/%d lines), below the %.1f%% threshold", 100*ratio, covered, total, 100*threshold),
+		Details:      details,
+		Retryable:    true,
+		Output:       stdout.String(),
+	}
+}
+
+func (c fileCoverage) ratio() float64 {
+	if c.total == 0 {
+		return 1
+	}
+	return float64(c.covered) / float64(c.total)
+}
+
+// findCoverageReport returns the report the coverage command wrote: source
+// when set, else the first of defaultCoverageReports present in dir. A
+// report older than the command run is stale and rejected.
+func findCoverageReport(source, dir string, since time.Time) (string, error) {
+	candidates := defaultCoverageReports
+	if source != "" {
+		candidates = []string{source}
+	}
+	for _, c := range candidates {
+		path := c
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, c)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		// File systems with coarse timestamps can date a fresh write just
+		// before the command started.
+		if info.ModTime().Before(since.Add(-2 * time.Second)) {
+			return "", fmt.Errorf("coverage report %s was not updated by the coverage command", c)
+		}
+		return path, nil
+	}
+	if source != "" {
+		return "", fmt.Errorf("coverage report %s not found", source)
+	}
+	return "", fmt.Errorf("no coverage report found (looked for %s)", strings.Join(defaultCoverageReports, ", "))
+}
+
+// parseCoverageReport parses a Go cover profile, lcov tracefile or
+// Cobertura XML report into per-file coverage keyed by the path the report
+// records.
+func parseCoverageReport(data []byte) (map[string]fileCoverage, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		return parseGoCoverProfile(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return parseCobertura(trimmed)
+	case bytes.Contains(trimmed, []byte("SF:")):
+		return parseLcov(trimmed)
+	}
+	return nil, fmt.Errorf("unrecognized format (expected Go cover profile, lcov or Cobertura XML)")
+}
+
+// parseGoCoverProfile parses "file:start.col,end.col statements count"
+// blocks. A block listed more than once (-coverpkg across packages) is
+// covered if any listing has a non-zero count.
+func parseGoCoverProfile(data []byte) (map[string]fileCoverage, error) {
+	type block struct {
+		statements int
+		covered    bool
+	}
+	blocks := make(map[string]map[string]*block)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		colon := strings.LastIndex(line, ":")
+		if len(fields) != 3 || colon < 0 {
+			return nil, fmt.Errorf("malformed cover profile line %q", line)
+		}
+		file, pos := line[:colon], strings.Fields(line[colon+1:])[0]
+		statements, err1 := strconv.Atoi(fields[1])
+		count, err2 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("malformed cover profile line %q", line)
+		}
+		if blocks[file] == nil {
+			blocks[file] = make(map[string]*block)
+		}
+		b := blocks[file][pos]
+		if b == nil {
+			b = &block{statements: statements}
+			blocks[file][pos] = b
+		}
+		b.covered = b.covered || count > 0
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	report := make(map[string]fileCoverage, len(blocks))
+	for file, fileBlocks := range blocks {
+		var fc fileCoverage
+		for _, b := range fileBlocks {
+			fc.total += b.statements
+			if b.covered {
+				fc.covered += b.statements
+			}
+		}
+		report[file] = fc
+	}
+	return report, nil
+}
+
+// parseLcov parses the SF/DA records of an lcov tracefile.
+func parseLcov(data []byte) (map[string]fileCoverage, error) {
+	lines := make(map[string]map[int]bool)
+	var file string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			file = strings.TrimPrefix(line, "SF:")
+			if lines[file] == nil {
+				lines[file] = make(map[int]bool)
+			}
+		case strings.HasPrefix(line, "DA:") && file != "":
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 {
+				continue
+			}
+			n, err1 := strconv.Atoi(fields[0])
+			hits, err2 := strconv.Atoi(fields[1])
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			lines[file][n] = lines[file][n] || hits > 0
+		case line == "end_of_record":
+			file = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lineCoverage(lines), nil
+}
+
+// parseCobertura parses the <class filename> line hits of a Cobertura XML
+// report. Filenames are relative to the report's first <source>, which is
+// prefixed so relative and absolute changed paths both match.
+func parseCobertura(data []byte) (map[string]fileCoverage, error) {
+	var doc struct {
+		Sources []string `xml:"sources>source"`
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number int `xml:"number,attr"`
+				Hits   int `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"packages>package>classes>class"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	lines := make(map[string]map[int]bool)
+	for _, class := range doc.Classes {
+		file := class.Filename
+		if len(doc.Sources) > 0 && !filepath.IsAbs(file) {
+			file = filepath.ToSlash(filepath.Join(strings.TrimSpace(doc.Sources[0]), file))
+		}
+		if lines[file] == nil {
+			lines[file] = make(map[int]bool)
+		}
+		for _, l := range class.Lines {
+			lines[file][l.Number] = lines[file][l.Number] || l.Hits > 0
+		}
+	}
+	return lineCoverage(lines), nil
+}
+
+func lineCoverage(lines map[string]map[int]bool) map[string]fileCoverage {
+	report := make(map[string]fileCoverage, len(lines))
+	for file, hits := range lines {
+		fc := fileCoverage{total: len(hits)}
+		for _, hit := range hits {
+			if hit {
+				fc.covered++
+			}
+		}
+		report[file] = fc
+	}
+	return report
+}
+
+type changedCoverage struct {
+	path     string
+	coverage fileCoverage
+}
+
+// changedFileCoverage returns the coverage of each changed file found in
+// the report. Report paths are matched by suffix: Go profiles record import
+// paths and lcov and Cobertura often absolute ones, while changed files are
+// relative to the repository root.
+func changedFileCoverage(report map[string]fileCoverage, changed, exclude []string) []changedCoverage {
+	var files []changedCoverage
+	for _, path := range changed {
+		if path == "" {
+			continue
+		}
+		excluded := false
+		for _, pattern := range exclude {
+			if matchExclude(pattern, path) {
+				excluded = true
+				break
+			}
+		}
+		if excluded {
+			continue
+		}
+		for reportPath, fc := range report {
+			reportPath = filepath.ToSlash(reportPath)
+			if reportPath == path || strings.HasSuffix(reportPath, "/"+path) {
+				files = append(files, changedCoverage{path: path, coverage: fc})
+				break
+			}
+		}
+	}
+	return files
+}
+
+// gitChangedFiles lists the files changed in workspacePath relative to HEAD,
+// staged, unstaged and untracked. Used when the executor has no change
+// summary for the step. Errors yield no files.
+func gitChangedFiles(workspacePath string) []string {
+	var files []string
+	for _, args := range [][]string{
+		{"diff", "--name-only", "--diff-filter=d", "HEAD"},
+		{"ls-files", "--others", "--exclude-standard"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = workspacePath
+		out, err := cmd.Output()
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				files = append(files, line)
+			}
+		}
+	}
+	return files
+}
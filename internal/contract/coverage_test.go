@@ -0,0 +1,206 @@
+package contract
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCoverageReport_GoCoverProfile(t *testing.T) {
+	report, err := parseCoverageReport([]byte(`mode: set
+github.com/acme/app/internal/api/handler.go:10.2,12.3 2 1
+github.com/acme/app/internal/api/handler.go:14.2,16.3 3 0
+github.com/acme/app/internal/api/handler.go:14.2,16.3 3 1
+github.com/acme/app/internal/api/routes.go:5.2,6.3 4 0
+`))
+	require.NoError(t, err)
+	assert.Equal(t, fileCoverage{covered: 5, total: 5}, report["github.com/acme/app/internal/api/handler.go"])
+	assert.Equal(t, fileCoverage{covered: 0, total: 4}, report["github.com/acme/app/internal/api/routes.go"])
+}
+
+func TestParseCoverageReport_Lcov(t *testing.T) {
+	report, err := parseCoverageReport([]byte(`TN:
+SF:/home/ci/app/src/api.ts
+DA:1,1
+DA:2,0
+DA:3,4
+LF:3
+LH:2
+end_of_record
+SF:/home/ci/app/src/util.ts
+DA:1,0
+end_of_record
+`))
+	require.NoError(t, err)
+	assert.Equal(t, fileCoverage{covered: 2, total: 3}, report["/home/ci/app/src/api.ts"])
+	assert.Equal(t, fileCoverage{covered: 0, total: 1}, report["/home/ci/app/src/util.ts"])
+}
+
+func TestParseCoverageReport_Cobertura(t *testing.T) {
+	report, err := parseCoverageReport([]byte(`<?xml version="1.0" ?>
+<coverage line-rate="0.5">
+  <sources><source>/home/ci/app</source></sources>
+  <packages>
+    <package name="pkg">
+      <classes>
+        <class name="models.py" filename="pkg/models.py">
+          <lines>
+            <line number="1" hits="1"/>
+            <line number="2" hits="0"/>
+            <line number="3" hits="0"/>
+            <line number="4" hits="2"/>
+          </lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>
+`))
+	require.NoError(t, err)
+	assert.Equal(t, fileCoverage{covered: 2, total: 4}, report["/home/ci/app/pkg/models.py"])
+}
+
+func TestParseCoverageReport_Unrecognized(t *testing.T) {
+	_, err := parseCoverageReport([]byte("PASS\nok  \tgithub.com/acme/app\n"))
+	assert.Error(t, err)
+}
+
+func TestChangedFileCoverage(t *testing.T) {
+	report := map[string]fileCoverage{
+		"github.com/acme/app/internal/api/handler.go": {covered: 1, total: 2},
+		"/home/ci/app/internal/api/routes.go":         {covered: 3, total: 3},
+		"github.com/acme/app/gen/api.pb.go":           {covered: 0, total: 50},
+	}
+	files := changedFileCoverage(report,
+		[]string{"internal/api/handler.go", "internal/api/routes.go", "gen/api.pb.go", "README.md", "api/routes.go"},
+		[]string{"gen/**"})
+
+	got := make(map[string]fileCoverage)
+	for _, f := range files {
+		got[f.path] = f.coverage
+	}
+	assert.Equal(t, map[string]fileCoverage{
+		"internal/api/handler.go": {covered: 1, total: 2},
+		"internal/api/routes.go":  {covered: 3, total: 3},
+		// "api/routes.go" is a suffix of a report path too.
+		"api/routes.go": {covered: 3, total: 3},
+	}, got)
+}
+
+// writeCoverageScript writes a script that writes report to path in dir.
+func writeCoverageScript(t *testing.T, dir, path, report string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "report.txt"), []byte(report), 0o644))
+	script := "mkdir -p \"$(dirname " + path + ")\"\ncp report.txt " + path + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cover.sh"), []byte(script), 0o755))
+}
+
+func TestCoverageValidator(t *testing.T) {
+	const profile = `mode: set
+example.com/app/calc.go:3.1,4.2 3 1
+example.com/app/calc.go:6.1,7.2 1 0
+example.com/app/other.go:3.1,4.2 5 0
+`
+	tests := []struct {
+		name      string
+		cfg       ContractConfig
+		wantErr   string
+		wantRetry bool
+	}{
+		{
+			name: "changed files above threshold",
+			cfg:  ContractConfig{ChangedFiles: []string{"calc.go"}, Threshold: 0.75},
+		},
+		{
+			name:      "changed files below threshold",
+			cfg:       ContractConfig{ChangedFiles: []string{"calc.go", "other.go"}, Threshold: 0.5},
+			wantErr:   "coverage of changed files is 33.3% (3/9 lines), below the 50.0% threshold",
+			wantRetry: true,
+		},
+		{
+			name:    "default threshold",
+			cfg:     ContractConfig{ChangedFiles: []string{"calc.go"}},
+			wantErr: "below the 80.0% threshold",
+		},
+		{
+			name: "excluded files do not count",
+			cfg:  ContractConfig{ChangedFiles: []string{"calc.go", "other.go"}, Exclude: []string{"other.go"}, Threshold: 0.75},
+		},
+		{
+			name: "no changed file in report",
+			cfg:  ContractConfig{ChangedFiles: []string{"README.md"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeCoverageScript(t, dir, "coverage.out", profile)
+			cfg := tt.cfg
+			cfg.Type = "coverage"
+			cfg.Command = "sh cover.sh"
+			cfg.Dir = dir
+
+			err := (&coverageValidator{}).Validate(cfg, dir)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			var vErr *ValidationError
+			require.ErrorAs(t, err, &vErr)
+			assert.Equal(t, "coverage", vErr.ContractType)
+			if tt.wantRetry {
+				assert.True(t, vErr.Retryable)
+			}
+		})
+	}
+}
+
+func TestCoverageValidator_Reports(t *testing.T) {
+	t.Run("explicit source", func(t *testing.T) {
+		dir := t.TempDir()
+		writeCoverageScript(t, dir, "out/lcov.info", "SF:"+filepath.Join(dir, "src", "a.js")+"\nDA:1,1\nend_of_record\n")
+		err := (&coverageValidator{}).Validate(ContractConfig{
+			Type: "coverage", Command: "sh cover.sh", Dir: dir, Source: "out/lcov.info", ChangedFiles: []string{"src/a.js"},
+		}, dir)
+		assert.NoError(t, err)
+	})
+
+	t.Run("stale report", func(t *testing.T) {
+		dir := t.TempDir()
+		stale := filepath.Join(dir, "coverage.out")
+		require.NoError(t, os.WriteFile(stale, []byte("mode: set\n"), 0o644))
+		old := time.Now().Add(-time.Hour)
+		require.NoError(t, os.Chtimes(stale, old, old))
+		err := (&coverageValidator{}).Validate(ContractConfig{Type: "coverage", Command: "true", Dir: dir, ChangedFiles: []string{}}, dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "was not updated by the coverage command")
+	})
+
+	t.Run("no report", func(t *testing.T) {
+		dir := t.TempDir()
+		err := (&coverageValidator{}).Validate(ContractConfig{Type: "coverage", Command: "true", Dir: dir, ChangedFiles: []string{}}, dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no coverage report found")
+	})
+
+	t.Run("no command", func(t *testing.T) {
+		err := (&coverageValidator{}).Validate(ContractConfig{Type: "coverage"}, t.TempDir())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no coverage command configured")
+	})
+
+	t.Run("failing command", func(t *testing.T) {
+		dir := t.TempDir()
+		err := (&coverageValidator{}).Validate(ContractConfig{Type: "coverage", Command: "false", Dir: dir}, dir)
+		var vErr *ValidationError
+		require.ErrorAs(t, err, &vErr)
+		assert.True(t, vErr.Retryable)
+		assert.Contains(t, vErr.Message, "coverage command failed")
+	})
+}
@@ -0,0 +1,374 @@
+package contract
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// dependencyPolicyValidator blocks dependencies the step added to go.mod or
+// package.json when their package or license is denied, or not allowed when
+// an allowlist is configured. A dependency is checked when it is new or its
+// version changed relative to the base: the workspace before the step ran
+// when the executor recorded one, else BaseRef, else HEAD.
+//
+// Licenses are read offline: the LICENSE file of the module in the Go
+// module cache, and the "license" field of node_modules/<name>/package.json.
+// A dependency whose license cannot be found passes a denylist but fails an
+// allowlist.
+type dependencyPolicyValidator struct{}
+
+// addedDependency is a dependency new or updated in a manifest.
+type addedDependency struct {
+	manifest string
+	name     string
+	version  string
+	license  string
+}
+
+func (v *dependencyPolicyValidator) Validate(cfg ContractConfig, workspacePath string) error {
+	for _, patterns := range [][]string{cfg.AllowPackages, cfg.DenyPackages, cfg.AllowLicenses, cfg.DenyLicenses} {
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return &ValidationError{
+					ContractType: "dependency_policy",
+					Message:      fmt.Sprintf("invalid pattern %q: %v", p, err),
+					Retryable:    false,
+				}
+			}
+		}
+	}
+
+	base := cfg.BaseRef
+	if base == "" {
+		base = cfg.DiffBase
+	}
+	if base == "" {
+		base = "HEAD"
+	}
+	changed := cfg.ChangedFiles
+	if changed == nil {
+		changed = gitChangedFiles(workspacePath)
+	}
+
+	var deps []addedDependency
+	for _, file := range changed {
+		var parse func([]byte) map[string]string
+		switch path.Base(file) {
+		case "go.mod":
+			parse = parseGoModRequires
+		case "package.json":
+			parse = parsePackageJSONDependencies
+		default:
+			continue
+		}
+		current, err := os.ReadFile(filepath.Join(workspacePath, filepath.FromSlash(file)))
+		if err != nil {
+			continue
+		}
+		before := parse(gitShowFile(workspacePath, base, file))
+		for name, version := range parse(current) {
+			if old, ok := before[name]; ok && old == version {
+				continue
+			}
+			dep := addedDependency{manifest: file, name: name, version: version}
+			if path.Base(file) == "go.mod" {
+				dep.license = goModuleLicense(name, version)
+			} else {
+				dep.license = npmPackageLicense(filepath.Join(workspacePath, filepath.FromSlash(path.Dir(file))), name)
+			}
+			deps = append(deps, dep)
+		}
+	}
+
+	var violations []string
+	for _, dep := range deps {
+		if reason := dependencyViolation(cfg, dep); reason != "" {
+			violations = append(violations, fmt.Sprintf("%s %s (%s): %s", dep.name, dep.version, dep.manifest, reason))
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	sort.Strings(violations)
+	return &ValidationError{
+		ContractType: "dependency_policy",
+		Message:      fmt.Sprintf("%d added dependenc%s violate the dependency policy", len(violations), pluralY(len(violations))),
+		Details:      violations,
+		Retryable:    true,
+	}
+}
+
+func pluralY(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
+
+// dependencyViolation returns why dep breaks the policy, or "".
+func dependencyViolation(cfg ContractConfig, dep addedDependency) string {
+	if matchAny(cfg.DenyPackages, dep.name) {
+		return "package is denied"
+	}
+	if len(cfg.AllowPackages) > 0 && !matchAny(cfg.AllowPackages, dep.name) {
+		return "package is not allowed"
+	}
+	if len(cfg.DenyLicenses) == 0 && len(cfg.AllowLicenses) == 0 {
+		return ""
+	}
+	ids, anyOf := parseLicenseExpression(dep.license)
+	if len(ids) == 0 {
+		if len(cfg.AllowLicenses) > 0 {
+			return "license unknown (not in the module cache or node_modules)"
+		}
+		return ""
+	}
+	acceptable := func(id string) bool {
+		if matchAny(cfg.DenyLicenses, id) {
+			return false
+		}
+		return len(cfg.AllowLicenses) == 0 || matchAny(cfg.AllowLicenses, id)
+	}
+	ok := !anyOf
+	for _, id := range ids {
+		if anyOf && acceptable(id) {
+			ok = true
+		}
+		if !anyOf && !acceptable(id) {
+			ok = false
+		}
+	}
+	if ok {
+		return ""
+	}
+	if matchAnyID(cfg.DenyLicenses, ids) {
+		return fmt.Sprintf("license %s is denied", dep.license)
+	}
+	return fmt.Sprintf("license %s is not allowed", dep.license)
+}
+
+func matchAnyID(patterns, ids []string) bool {
+	for _, id := range ids {
+		if matchAny(patterns, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAny reports whether name matches any glob pattern, case-insensitively.
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.ToLower(p), strings.ToLower(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLicenseExpression splits an SPDX expression into its license IDs.
+// anyOf is true for "A OR B", where satisfying one alternative suffices.
+func parseLicenseExpression(expr string) (ids []string, anyOf bool) {
+	fields := strings.FieldsFunc(expr, func(r rune) bool { return r == '(' || r == ')' || unicode.IsSpace(r) })
+	for _, f := range fields {
+		switch strings.ToUpper(f) {
+		case "OR", "/":
+			anyOf = true
+		case "AND", "WITH":
+		default:
+			ids = append(ids, f)
+		}
+	}
+	return ids, anyOf
+}
+
+// gitShowFile returns file at rev in the repository at dir, or nil when it
+// did not exist there.
+func gitShowFile(dir, rev, file string) []byte {
+	cmd := exec.Command("git", "show", rev+":"+file)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// parseGoModRequires returns the module → version map of the require
+// directives of a go.mod file.
+func parseGoModRequires(data []byte) map[string]string {
+	requires := make(map[string]string)
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inBlock && fields[0] == ")":
+			inBlock = false
+			continue
+		case inBlock:
+		case fields[0] == "require" && len(fields) > 1 && fields[1] == "(":
+			inBlock = true
+			continue
+		case fields[0] == "require":
+			fields = fields[1:]
+		default:
+			continue
+		}
+		if len(fields) >= 2 {
+			requires[strings.Trim(fields[0], `"`)] = fields[1]
+		}
+	}
+	return requires
+}
+
+// parsePackageJSONDependencies returns the name → version range map of all
+// dependency sections of a package.json file.
+func parsePackageJSONDependencies(data []byte) map[string]string {
+	var pkg map[string]json.RawMessage
+	deps := make(map[string]string)
+	if json.Unmarshal(data, &pkg) != nil {
+		return deps
+	}
+	for _, section := range []string{"dependencies", "devDependencies", "optionalDependencies", "peerDependencies"} {
+		var m map[string]string
+		if raw, ok := pkg[section]; ok && json.Unmarshal(raw, &m) == nil {
+			for name, version := range m {
+				deps[name] = version
+			}
+		}
+	}
+	return deps
+}
+
+// goModuleLicense identifies the license of module@version from its
+// LICENSE file in the module cache, or returns "".
+func goModuleLicense(module, version string) string {
+	modCache := os.Getenv("GOMODCACHE")
+	if modCache == "" {
+		gopath := os.Getenv("GOPATH")
+		if gopath == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return ""
+			}
+			gopath = filepath.Join(home, "go")
+		}
+		modCache = filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+	}
+	dir := filepath.Join(modCache, filepath.FromSlash(escapeModulePath(module))+"@"+version)
+	for _, name := range []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "COPYING", "LICENCE"} {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			return identifyLicense(data)
+		}
+	}
+	return ""
+}
+
+// escapeModulePath applies the module cache's case encoding: an upper-case
+// letter becomes '!' followed by its lower-case form.
+func escapeModulePath(module string) string {
+	var sb strings.Builder
+	for _, r := range module {
+		if unicode.IsUpper(r) {
+			sb.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// npmPackageLicense returns the license field of an installed npm package,
+// or "".
+func npmPackageLicense(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "node_modules", filepath.FromSlash(name), "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		License json.RawMessage `json:"license"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return ""
+	}
+	var license string
+	if json.Unmarshal(pkg.License, &license) == nil {
+		return license
+	}
+	// Legacy form: {"type": "MIT", "url": "..."}.
+	var legacy struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(pkg.License, &legacy)
+	return legacy.Type
+}
+
+var spdxIdentifierRe = regexp.MustCompile(`SPDX-License-Identifier:\s*([^\s*]+(?:\s+(?:OR|AND|WITH)\s+[^\s*]+)*)`)
+
+// licenseSignatures identify a license from its text, checked in order:
+// the GPL family's names contain each other.
+var licenseSignatures = []struct {
+	id    string
+	match func(text string) bool
+}{
+	{"AGPL-3.0", containsFold("GNU AFFERO GENERAL PUBLIC LICENSE")},
+	{"LGPL-3.0", allFold("GNU LESSER GENERAL PUBLIC LICENSE", "Version 3")},
+	{"LGPL-2.1", containsFold("GNU LESSER GENERAL PUBLIC LICENSE")},
+	{"LGPL-2.0", containsFold("GNU LIBRARY GENERAL PUBLIC LICENSE")},
+	{"GPL-3.0", allFold("GNU GENERAL PUBLIC LICENSE", "Version 3")},
+	{"GPL-2.0", containsFold("GNU GENERAL PUBLIC LICENSE")},
+	{"MPL-2.0", containsFold("Mozilla Public License")},
+	{"Apache-2.0", containsFold("Apache License")},
+	{"MIT", containsFold("Permission is hereby granted, free of charge")},
+	{"BSD-3-Clause", allFold("Redistribution and use in source and binary forms", "Neither the name")},
+	{"BSD-2-Clause", containsFold("Redistribution and use in source and binary forms")},
+	{"ISC", containsFold("Permission to use, copy, modify, and/or distribute this software")},
+	{"Unlicense", containsFold("This is free and unencumbered software released into the public domain")},
+}
+
+// identifyLicense returns the SPDX ID of a license text, or "".
+func identifyLicense(data []byte) string {
+	text := string(data)
+	if m := spdxIdentifierRe.FindStringSubmatch(text); m != nil {
+		return m[1]
+	}
+	for _, sig := range licenseSignatures {
+		if sig.match(text) {
+			return sig.id
+		}
+	}
+	return ""
+}
+
+func containsFold(s string) func(string) bool {
+	s = strings.ToLower(s)
+	return func(text string) bool { return strings.Contains(strings.ToLower(text), s) }
+}
+
+func allFold(subs ...string) func(string) bool {
+	return func(text string) bool {
+		for _, s := range subs {
+			if !containsFold(s)(text) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,166 @@
+package contract
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGoModRequires(t *testing.T) {
+	requires := parseGoModRequires([]byte(`module example.com/app
+
+go 1.22
+
+require github.com/single/dep v1.0.0
+
+require (
+	github.com/spf13/cobra v1.8.0
+	golang.org/x/text v0.14.0 // indirect
+	// github.com/commented/out v1.0.0
+)
+
+replace github.com/spf13/cobra => ../cobra
+`))
+	assert.Equal(t, map[string]string{
+		"github.com/single/dep":  "v1.0.0",
+		"github.com/spf13/cobra": "v1.8.0",
+		"golang.org/x/text":      "v0.14.0",
+	}, requires)
+}
+
+func TestParsePackageJSONDependencies(t *testing.T) {
+	deps := parsePackageJSONDependencies([]byte(`{
+  "name": "web",
+  "dependencies": {"react": "^18.2.0"},
+  "devDependencies": {"vitest": "^1.0.0"},
+  "peerDependencies": {"react-dom": "^18.0.0"}
+}`))
+	assert.Equal(t, map[string]string{"react": "^18.2.0", "vitest": "^1.0.0", "react-dom": "^18.0.0"}, deps)
+	assert.Empty(t, parsePackageJSONDependencies([]byte("not json")))
+}
+
+func TestIdentifyLicense(t *testing.T) {
+	tests := map[string]string{
+		"GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007":                       "GPL-3.0",
+		"GNU GENERAL PUBLIC LICENSE\nVersion 2, June 1991":                          "GPL-2.0",
+		"GNU LESSER GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007":                "LGPL-3.0",
+		"GNU AFFERO GENERAL PUBLIC LICENSE\nVersion 3, 19 November 2007":            "AGPL-3.0",
+		"Apache License\nVersion 2.0, January 2004":                                 "Apache-2.0",
+		"MIT License\n\nPermission is hereby granted, free of charge, to":           "MIT",
+		"Redistribution and use in source and binary forms ... Neither the name of": "BSD-3-Clause",
+		"// SPDX-License-Identifier: MIT OR Apache-2.0\n":                           "MIT OR Apache-2.0",
+		"All rights reserved.": "",
+	}
+	for text, want := range tests {
+		assert.Equal(t, want, identifyLicense([]byte(text)), text)
+	}
+}
+
+func TestDependencyViolation(t *testing.T) {
+	dep := func(license string) addedDependency {
+		return addedDependency{name: "github.com/acme/lib", license: license}
+	}
+	deny := ContractConfig{DenyLicenses: []string{"GPL-*", "AGPL-*"}}
+	allow := ContractConfig{AllowLicenses: []string{"MIT", "Apache-2.0", "BSD-*"}}
+
+	assert.Equal(t, "license GPL-3.0 is denied", dependencyViolation(deny, dep("GPL-3.0")))
+	assert.Empty(t, dependencyViolation(deny, dep("LGPL-3.0")))
+	assert.Empty(t, dependencyViolation(deny, dep("mit")))
+	assert.Empty(t, dependencyViolation(deny, dep("")), "unknown license passes a denylist")
+
+	assert.Empty(t, dependencyViolation(allow, dep("BSD-3-Clause")))
+	assert.Equal(t, "license MPL-2.0 is not allowed", dependencyViolation(allow, dep("MPL-2.0")))
+	assert.Contains(t, dependencyViolation(allow, dep("")), "license unknown")
+
+	assert.Empty(t, dependencyViolation(deny, dep("GPL-2.0 OR MIT")), "one acceptable alternative suffices")
+	assert.NotEmpty(t, dependencyViolation(deny, dep("MIT AND GPL-2.0")), "every conjunct must be acceptable")
+
+	assert.Equal(t, "package is denied",
+		dependencyViolation(ContractConfig{DenyPackages: []string{"github.com/acme/*"}}, dep("MIT")))
+	assert.Equal(t, "package is not allowed",
+		dependencyViolation(ContractConfig{AllowPackages: []string{"golang.org/x/*"}}, dep("MIT")))
+}
+
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.email=t@example.com", "-c", "user.name=t"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestDependencyPolicyValidator(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	modCache := t.TempDir()
+	t.Setenv("GOMODCACHE", modCache)
+	writeTestFile(t, filepath.Join(modCache, "github.com", "!burnt!sushi", "toml@v1.3.2", "LICENSE"),
+		"The MIT License\n\nPermission is hereby granted, free of charge, to any person")
+	writeTestFile(t, filepath.Join(modCache, "github.com", "acme", "gpl@v0.1.0", "COPYING"),
+		"GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007")
+
+	dir := t.TempDir()
+	gitRun(t, dir, "init", "-q")
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.22\n\nrequire github.com/acme/gpl v0.0.9\n")
+	writeTestFile(t, filepath.Join(dir, "web", "package.json"), `{"dependencies": {"left-pad": "1.3.0"}}`)
+	gitRun(t, dir, "add", "-A")
+	gitRun(t, dir, "commit", "-q", "-m", "init")
+
+	writeTestFile(t, filepath.Join(dir, "go.mod"),
+		"module example.com/app\n\ngo 1.22\n\nrequire (\n\tgithub.com/BurntSushi/toml v1.3.2\n\tgithub.com/acme/gpl v0.1.0\n)\n")
+	writeTestFile(t, filepath.Join(dir, "web", "package.json"), `{"dependencies": {"left-pad": "1.3.0", "copyleft": "^2.0.0"}}`)
+	writeTestFile(t, filepath.Join(dir, "web", "node_modules", "copyleft", "package.json"), `{"name": "copyleft", "license": "AGPL-3.0-only"}`)
+
+	t.Run("denied licenses", func(t *testing.T) {
+		err := (&dependencyPolicyValidator{}).Validate(ContractConfig{
+			Type:         "dependency_policy",
+			DenyLicenses: []string{"GPL-*", "AGPL-*"},
+		}, dir)
+		var vErr *ValidationError
+		require.ErrorAs(t, err, &vErr)
+		assert.True(t, vErr.Retryable)
+		assert.Contains(t, vErr.Message, "2 added dependencies violate the dependency policy")
+		assert.Equal(t, []string{
+			"copyleft ^2.0.0 (web/package.json): license AGPL-3.0-only is denied",
+			"github.com/acme/gpl v0.1.0 (go.mod): license GPL-3.0 is denied",
+		}, vErr.Details)
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		err := (&dependencyPolicyValidator{}).Validate(ContractConfig{
+			Type:          "dependency_policy",
+			AllowLicenses: []string{"MIT"},
+			ChangedFiles:  []string{"go.mod"},
+		}, dir)
+		var vErr *ValidationError
+		require.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{"github.com/acme/gpl v0.1.0 (go.mod): license GPL-3.0 is not allowed"}, vErr.Details)
+	})
+
+	t.Run("unchanged manifests are not checked", func(t *testing.T) {
+		err := (&dependencyPolicyValidator{}).Validate(ContractConfig{
+			Type:         "dependency_policy",
+			DenyLicenses: []string{"*"},
+			ChangedFiles: []string{"main.go"},
+		}, dir)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		err := (&dependencyPolicyValidator{}).Validate(ContractConfig{Type: "dependency_policy", DenyPackages: []string{"["}}, dir)
+		var vErr *ValidationError
+		require.ErrorAs(t, err, &vErr)
+		assert.False(t, vErr.Retryable)
+	})
+}
@@ -0,0 +1,356 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// JSONRepairConfig enables a repair pass before a json_schema contract
+// fails on malformed artifact JSON. The artifact is first run through a
+// tolerant parser; when that fails and Persona is set, the persona is asked
+// to fix the document given the schema and the parse error.
+type JSONRepairConfig struct {
+	Persona     string `json:"persona,omitempty"     yaml:"persona,omitempty"`      // Persona for the repair prompt; empty runs the parser only
+	MaxAttempts int    `json:"maxAttempts,omitempty" yaml:"max_attempts,omitempty"` // Persona repair attempts (default 1)
+	Timeout     string `json:"timeout,omitempty"     yaml:"timeout,omitempty"`      // Per-attempt timeout (default 2m)
+}
+
+// IsMalformedJSON reports whether err is a json_schema failure caused by an
+// artifact that is not parseable JSON, as opposed to a schema mismatch.
+func IsMalformedJSON(err error) bool {
+	var ve *ValidationError
+	if !errors.As(err, &ve) || ve.ContractType != "json_schema" {
+		return false
+	}
+	return strings.HasPrefix(ve.Message, msgArtifactParseFailed) || strings.HasPrefix(ve.Message, msgArtifactIncomplete)
+}
+
+// RepairJSON rewrites malformed JSON the way a lenient reader would
+// interpret it: comments and trailing commas are dropped, missing commas and
+// colons inserted, single-quoted strings and unquoted keys quoted, Python
+// literals converted, raw newlines in strings escaped, and a truncated
+// document closed. Values are never invented or reordered. It returns the
+// repaired document pretty-printed and the fixes applied.
+func RepairJSON(input []byte) ([]byte, []string, error) {
+	r := &jsonRepairer{in: []rune(string(bytes.TrimPrefix(input, utf8BOM)))}
+	r.skipSpace()
+	if r.eof() {
+		return nil, nil, fmt.Errorf("empty document")
+	}
+	if err := r.value(); err != nil {
+		return nil, nil, err
+	}
+	r.skipSpace()
+	if !r.eof() {
+		r.fix("removed_trailing_content")
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(r.out.String()), "", "  "); err != nil {
+		return nil, nil, fmt.Errorf("repair produced invalid JSON: %w", err)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), r.fixes, nil
+}
+
+// jsonRepairer is a recursive-descent reader that writes valid JSON for
+// each value it can read.
+type jsonRepairer struct {
+	in    []rune
+	pos   int
+	out   strings.Builder
+	fixes []string
+	depth int
+}
+
+const maxRepairDepth = 512
+
+func (r *jsonRepairer) fix(name string) {
+	for _, f := range r.fixes {
+		if f == name {
+			return
+		}
+	}
+	r.fixes = append(r.fixes, name)
+}
+
+func (r *jsonRepairer) eof() bool { return r.pos >= len(r.in) }
+
+func (r *jsonRepairer) peek() rune {
+	if r.eof() {
+		return 0
+	}
+	return r.in[r.pos]
+}
+
+// skipSpace skips whitespace and // or /* */ comments.
+func (r *jsonRepairer) skipSpace() {
+	for !r.eof() {
+		c := r.peek()
+		switch {
+		case unicode.IsSpace(c):
+			r.pos++
+		case c == '/' && r.pos+1 < len(r.in) && r.in[r.pos+1] == '/':
+			for !r.eof() && r.peek() != '\n' {
+				r.pos++
+			}
+			r.fix("removed_comments")
+		case c == '/' && r.pos+1 < len(r.in) && r.in[r.pos+1] == '*':
+			r.pos += 2
+			for !r.eof() && (r.peek() != '*' || r.pos+1 >= len(r.in) || r.in[r.pos+1] != '/') {
+				r.pos++
+			}
+			r.pos += 2
+			r.fix("removed_comments")
+		default:
+			return
+		}
+	}
+}
+
+func (r *jsonRepairer) value() error {
+	r.skipSpace()
+	if r.eof() {
+		r.out.WriteString("null")
+		r.fix("closed_truncated_document")
+		return nil
+	}
+	switch c := r.peek(); {
+	case c == '{':
+		return r.container('{', '}')
+	case c == '[':
+		return r.container('[', ']')
+	case c == '"' || c == '\'':
+		r.str()
+		return nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		return r.number()
+	case isIdentStart(c):
+		return r.literal()
+	default:
+		return fmt.Errorf("unexpected character %q at offset %d", c, r.pos)
+	}
+}
+
+// container reads an object or array, depending on open.
+func (r *jsonRepairer) container(open, closing rune) error {
+	r.depth++
+	if r.depth > maxRepairDepth {
+		return fmt.Errorf("document nested deeper than %d levels", maxRepairDepth)
+	}
+	defer func() { r.depth-- }()
+
+	r.pos++
+	r.out.WriteRune(open)
+	hasElement, sawComma := false, false
+	for {
+		r.skipSpace()
+		if r.eof() {
+			r.out.WriteRune(closing)
+			r.fix("closed_truncated_document")
+			return nil
+		}
+		switch c := r.peek(); {
+		case c == closing:
+			r.pos++
+			if sawComma {
+				r.fix("removed_trailing_commas")
+			}
+			r.out.WriteRune(closing)
+			return nil
+		case c == ']' || c == '}':
+			// Mismatched closer: close this container and leave the
+			// closer to the enclosing one.
+			r.out.WriteRune(closing)
+			r.fix("fixed_mismatched_brackets")
+			return nil
+		case c == ',':
+			r.pos++
+			if !hasElement || sawComma {
+				r.fix("removed_extra_commas")
+			}
+			sawComma = true
+			continue
+		}
+
+		if hasElement {
+			if !sawComma {
+				r.fix("added_missing_commas")
+			}
+			r.out.WriteByte(',')
+		}
+		sawComma = false
+		hasElement = true
+
+		if open == '{' {
+			if err := r.key(); err != nil {
+				return err
+			}
+			r.skipSpace()
+			if r.peek() == ':' {
+				r.pos++
+			} else {
+				r.fix("added_missing_colons")
+			}
+			r.out.WriteByte(':')
+		}
+		if err := r.value(); err != nil {
+			return err
+		}
+	}
+}
+
+// key reads an object key, quoting a bare identifier.
+func (r *jsonRepairer) key() error {
+	c := r.peek()
+	if c == '"' || c == '\'' {
+		r.str()
+		return nil
+	}
+	start := r.pos
+	for !r.eof() && (isIdentStart(r.peek()) || unicode.IsDigit(r.peek()) || r.peek() == '-') {
+		r.pos++
+	}
+	if r.pos == start {
+		return fmt.Errorf("expected object key at offset %d, found %q", r.pos, c)
+	}
+	writeJSONString(&r.out, string(r.in[start:r.pos]))
+	r.fix("quoted_unquoted_keys")
+	return nil
+}
+
+// str reads a double- or single-quoted string.
+func (r *jsonRepairer) str() {
+	quote := r.peek()
+	if quote == '\'' {
+		r.fix("converted_single_quotes")
+	}
+	r.pos++
+	var sb strings.Builder
+	for {
+		if r.eof() {
+			r.fix("closed_truncated_document")
+			break
+		}
+		c := r.peek()
+		r.pos++
+		if c == quote {
+			break
+		}
+		if c == '\\' && !r.eof() {
+			esc := r.peek()
+			r.pos++
+			switch esc {
+			case '"', '\\', '/':
+				sb.WriteRune(esc)
+			case '\'':
+				sb.WriteRune('\'')
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if code, ok := r.hex4(r.pos); ok {
+					r.pos += 4
+					if utf16.IsSurrogate(code) && r.pos+6 <= len(r.in) && r.in[r.pos] == '\\' && r.in[r.pos+1] == 'u' {
+						if low, ok := r.hex4(r.pos + 2); ok {
+							if pair := utf16.DecodeRune(code, low); pair != unicode.ReplacementChar {
+								code = pair
+								r.pos += 6
+							}
+						}
+					}
+					sb.WriteRune(code)
+					continue
+				}
+				sb.WriteString(`\u`)
+			default:
+				sb.WriteRune(esc)
+			}
+			continue
+		}
+		if c == '\n' || c == '\r' || c == '\t' {
+			r.fix("escaped_control_characters")
+		}
+		sb.WriteRune(c)
+	}
+	writeJSONString(&r.out, sb.String())
+}
+
+func (r *jsonRepairer) number() error {
+	start := r.pos
+	for !r.eof() && strings.ContainsRune("+-0123456789.eE", r.peek()) {
+		r.pos++
+	}
+	num := string(r.in[start:r.pos])
+	if json.Valid([]byte(num)) {
+		r.out.WriteString(num)
+		return nil
+	}
+	trimmed := strings.TrimRight(num, ".eE+-")
+	if trimmed != "" && trimmed != "-" && json.Valid([]byte(trimmed)) {
+		r.out.WriteString(trimmed)
+		r.fix("fixed_truncated_numbers")
+		return nil
+	}
+	return fmt.Errorf("invalid number %q at offset %d", num, start)
+}
+
+// literal reads true/false/null, accepting Python spellings.
+func (r *jsonRepairer) literal() error {
+	start := r.pos
+	for !r.eof() && (isIdentStart(r.peek()) || unicode.IsDigit(r.peek())) {
+		r.pos++
+	}
+	word := string(r.in[start:r.pos])
+	switch word {
+	case "true", "false", "null":
+		r.out.WriteString(word)
+	case "True", "False":
+		r.out.WriteString(strings.ToLower(word))
+		r.fix("converted_python_literals")
+	case "None":
+		r.out.WriteString("null")
+		r.fix("converted_python_literals")
+	default:
+		return fmt.Errorf("unexpected token %q at offset %d", word, start)
+	}
+	return nil
+}
+
+// hex4 decodes the four hex digits at offset i.
+func (r *jsonRepairer) hex4(i int) (rune, bool) {
+	if i+4 > len(r.in) {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(string(r.in[i:i+4]), 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return rune(n), true
+}
+
+func isIdentStart(c rune) bool {
+	return c == '_' || c == '$' || unicode.IsLetter(c)
+}
+
+// writeJSONString writes s as a JSON string literal without HTML escaping.
+func writeJSONString(sb *strings.Builder, s string) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	sb.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
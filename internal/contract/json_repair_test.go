@@ -0,0 +1,52 @@
+package contract
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		fix   string
+	}{
+		{"trailing comma", `{"a": 1, "b": [1, 2,],}`, `{"a":1,"b":[1,2]}`, "removed_trailing_commas"},
+		{"missing comma", "{\"a\": 1\n \"b\": 2}", `{"a":1,"b":2}`, "added_missing_commas"},
+		{"single quotes", `{'a': 'it\'s'}`, `{"a":"it's"}`, "converted_single_quotes"},
+		{"unquoted keys", `{title: "x", item_count: 2}`, `{"title":"x","item_count":2}`, "quoted_unquoted_keys"},
+		{"python literals", `{"ok": True, "err": None}`, `{"ok":true,"err":null}`, "converted_python_literals"},
+		{"comments", "{\n  // note\n  \"a\": 1 /* inline */\n}", `{"a":1}`, "removed_comments"},
+		{"raw newline in string", "{\"a\": \"line1\nline2\"}", `{"a":"line1\nline2"}`, "escaped_control_characters"},
+		{"truncated", `{"items": [{"id": 1}, {"id": 2, "name": "tw`, `{"items":[{"id":1},{"id":2,"name":"tw"}]}`, "closed_truncated_document"},
+		{"mismatched bracket", `{"a": [1, 2}`, `{"a":[1,2]}`, "fixed_mismatched_brackets"},
+		{"surrogate pair escape", `{"e": "\ud83d\ude00",}`, `{"e":"😀"}`, "removed_trailing_commas"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fixes, err := RepairJSON([]byte(tt.input))
+			require.NoError(t, err)
+			var gotVal, wantVal any
+			require.NoError(t, json.Unmarshal(got, &gotVal))
+			require.NoError(t, json.Unmarshal([]byte(tt.want), &wantVal))
+			assert.Equal(t, wantVal, gotVal)
+			assert.Contains(t, fixes, tt.fix)
+		})
+	}
+
+	for _, bad := range []string{"", "not json at all", `{"a": @}`} {
+		_, _, err := RepairJSON([]byte(bad))
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestIsMalformedJSON(t *testing.T) {
+	assert.True(t, IsMalformedJSON(&ValidationError{ContractType: "json_schema", Message: msgArtifactParseFailed + " after recovery attempts"}))
+	assert.True(t, IsMalformedJSON(&ValidationError{ContractType: "json_schema", Message: msgArtifactIncomplete}))
+	assert.False(t, IsMalformedJSON(&ValidationError{ContractType: "json_schema", Message: "artifact does not match schema"}))
+	assert.False(t, IsMalformedJSON(&ValidationError{ContractType: "test_suite", Message: msgArtifactParseFailed}))
+}
@@ -11,6 +11,13 @@ import (
 	"github.com/santhosh-tekuri/jsonschema/v6"
 )
 
+// Messages of json_schema failures caused by unparseable artifact JSON;
+// IsMalformedJSON matches on them.
+const (
+	msgArtifactParseFailed = "failed to parse artifact JSON"
+	msgArtifactIncomplete  = "recovered JSON is structurally incomplete"
+)
+
 type jsonSchemaValidator struct{}
 
 func (v *jsonSchemaValidator) Validate(cfg ContractConfig, workspacePath string) error {
@@ -131,11 +138,7 @@ func (v *jsonSchemaValidator) Validate(cfg ContractConfig, workspacePath string)
 
 	// Enhanced JSON recovery with progressive validation
 	// Default to allowing recovery for better reliability
-	allowRecovery := cfg.AllowRecovery
-	if !cfg.AllowRecovery && cfg.RecoveryLevel == "" {
-		// If recovery settings are not explicitly configured, enable progressive recovery by default
-		allowRecovery = true
-	}
+	allowRecovery := RecoveryEnabled(cfg)
 
 	var artifact interface{}
 	var recoveryResult *RecoveryResult
@@ -169,7 +172,7 @@ func (v *jsonSchemaValidator) Validate(cfg ContractConfig, workspacePath string)
 
 			return &ValidationError{
 				ContractType: "json_schema",
-				Message:      "failed to parse artifact JSON after recovery attempts",
+				Message:      msgArtifactParseFailed + " after recovery attempts",
 				Details:      details,
 				Retryable:    true,
 			}
@@ -188,7 +191,7 @@ func (v *jsonSchemaValidator) Validate(cfg ContractConfig, workspacePath string)
 			}
 			return &ValidationError{
 				ContractType: "json_schema",
-				Message:      "recovered JSON is structurally incomplete",
+				Message:      msgArtifactIncomplete,
 				Details:      details,
 				Retryable:    true,
 			}
@@ -201,7 +204,7 @@ func (v *jsonSchemaValidator) Validate(cfg ContractConfig, workspacePath string)
 		if err := json.Unmarshal(data, &artifact); err != nil {
 			return &ValidationError{
 				ContractType: "json_schema",
-				Message:      "failed to parse artifact JSON",
+				Message:      msgArtifactParseFailed,
 				Details:      []string{fmt.Sprintf("file: %s", pathfmt.FileURI(artifactPath)), err.Error()},
 				Retryable:    true,
 			}
@@ -0,0 +1,51 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// utf8BOM is stripped before an artifact is checked for valid JSON.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// RecoveryEnabled reports whether a json_schema contract may recover
+// malformed artifact JSON. Recovery is on unless allow_recovery is
+// explicitly false alongside a recovery_level.
+func RecoveryEnabled(cfg ContractConfig) bool {
+	return cfg.AllowRecovery || cfg.RecoveryLevel == ""
+}
+
+// NormalizeJSONArtifact extracts the JSON document from an artifact that
+// wraps it in a markdown code fence or surrounding prose, the most common
+// way a persona's otherwise valid output fails a json_schema contract. It
+// returns the document pretty-printed, the fixes applied, and true when
+// the artifact was rewritten. Only lossless extraction is attempted: an
+// artifact that is already valid JSON, or whose JSON is itself malformed,
+// is returned unchanged with false.
+func NormalizeJSONArtifact(data []byte) ([]byte, []string, bool) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, utf8BOM))
+	if len(trimmed) == 0 || json.Valid(trimmed) {
+		return data, nil, false
+	}
+
+	p := NewJSONRecoveryParser(ConservativeRecovery)
+	for _, extract := range []func(string) (string, []string, []string){
+		p.extractFromMarkdown,
+		p.extractFromAIExplanation,
+		p.extractJSONFromText,
+	} {
+		extracted, fixes, _ := extract(string(trimmed))
+		candidate := strings.TrimSpace(extracted)
+		if candidate == "" || candidate == string(trimmed) || !json.Valid([]byte(candidate)) || !isStructurallyComplete(candidate) {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(candidate), "", "  "); err != nil {
+			continue
+		}
+		buf.WriteByte('\n')
+		return buf.Bytes(), append(fixes, "pretty_printed"), true
+	}
+	return data, nil, false
+}
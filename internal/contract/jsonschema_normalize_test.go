@@ -0,0 +1,42 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeJSONArtifact(t *testing.T) {
+	want := "{\n  \"title\": \"Fix login\",\n  \"labels\": [\n    \"bug\"\n  ]\n}\n"
+	tests := []struct {
+		name    string
+		input   string
+		changed bool
+	}{
+		{"code fence", "```json\n{\"title\":\"Fix login\",\"labels\":[\"bug\"]}\n```\n", true},
+		{"fence with prose", "Here is the result:\n\n```json\n{\"title\": \"Fix login\", \"labels\": [\"bug\"]}\n```\n\nLet me know if you need changes.", true},
+		{"trailing prose", "{\"title\":\"Fix login\",\"labels\":[\"bug\"]}\n\nThe issue is ready.", true},
+		{"already valid", `{"title":"Fix login","labels":["bug"]}`, false},
+		{"malformed inside fence", "```json\n{\"title\": \"Fix login\",}\n```", false},
+		{"no json", "I could not complete the task.", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fixes, changed := NormalizeJSONArtifact([]byte(tt.input))
+			assert.Equal(t, tt.changed, changed)
+			if !tt.changed {
+				assert.Equal(t, tt.input, string(got))
+				assert.Empty(t, fixes)
+				return
+			}
+			assert.Equal(t, want, string(got))
+			assert.Contains(t, fixes, "pretty_printed")
+		})
+	}
+}
+
+func TestRecoveryEnabled(t *testing.T) {
+	assert.True(t, RecoveryEnabled(ContractConfig{}))
+	assert.True(t, RecoveryEnabled(ContractConfig{AllowRecovery: true, RecoveryLevel: "conservative"}))
+	assert.False(t, RecoveryEnabled(ContractConfig{RecoveryLevel: "conservative"}))
+}
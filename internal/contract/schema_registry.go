@@ -0,0 +1,208 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SchemaRegistryDir is the project directory holding versioned contract
+// schemas shared across pipelines. Version N of schema "name" lives at
+// <SchemaRegistryDir>/<name>/v<N>.schema.json and is referenced from a
+// contract as schema_ref: name@vN.
+const SchemaRegistryDir = ".agents/schemas"
+
+var (
+	schemaRefNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	schemaVersionFile    = regexp.MustCompile(`^v([1-9][0-9]*)\.schema\.json$`)
+)
+
+// SchemaRef names a registered schema and, when pinned, one of its versions.
+type SchemaRef struct {
+	Name    string
+	Version int // 0 means unpinned: the latest registered version
+}
+
+// ParseSchemaRef parses "name" or "name@vN".
+func ParseSchemaRef(ref string) (SchemaRef, error) {
+	name, version, pinned := strings.Cut(ref, "@")
+	if !schemaRefNamePattern.MatchString(name) {
+		return SchemaRef{}, fmt.Errorf("invalid schema_ref %q: name must be lowercase letters, digits, '-' or '_'", ref)
+	}
+	if !pinned {
+		return SchemaRef{Name: name}, nil
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(version, "v"))
+	if !strings.HasPrefix(version, "v") || err != nil || n < 1 {
+		return SchemaRef{}, fmt.Errorf("invalid schema_ref %q: version must be v1, v2, ...", ref)
+	}
+	return SchemaRef{Name: name, Version: n}, nil
+}
+
+// String returns the reference in name@vN form, or the bare name when unpinned.
+func (r SchemaRef) String() string {
+	if r.Version == 0 {
+		return r.Name
+	}
+	return fmt.Sprintf("%s@v%d", r.Name, r.Version)
+}
+
+// SchemaVersionPath returns the file holding version of schema name in dir.
+func SchemaVersionPath(dir, name string, version int) string {
+	return filepath.Join(dir, name, fmt.Sprintf("v%d.schema.json", version))
+}
+
+// SchemaVersions returns the registered versions of schema name in dir in
+// ascending order, or nil when the schema is not registered.
+func SchemaVersions(dir, name string) ([]int, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var versions []int
+	for _, e := range entries {
+		m := schemaVersionFile.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		versions = append(versions, n)
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+// ResolveSchemaRef returns the schema file a reference points to. An
+// unpinned reference resolves to the latest registered version.
+func ResolveSchemaRef(dir, ref string) (string, error) {
+	r, err := ParseSchemaRef(ref)
+	if err != nil {
+		return "", err
+	}
+	versions, err := SchemaVersions(dir, r.Name)
+	if err != nil {
+		return "", fmt.Errorf("schema_ref %q: %w", ref, err)
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("schema_ref %q: no schema %q registered in %s", ref, r.Name, dir)
+	}
+	if r.Version == 0 {
+		return SchemaVersionPath(dir, r.Name, versions[len(versions)-1]), nil
+	}
+	for _, v := range versions {
+		if v == r.Version {
+			return SchemaVersionPath(dir, r.Name, v), nil
+		}
+	}
+	return "", fmt.Errorf("schema_ref %q: version v%d not registered (have %s)", ref, r.Version, formatSchemaVersions(versions))
+}
+
+// RegisteredSchema is one schema in the registry with its versions.
+type RegisteredSchema struct {
+	Name     string `json:"name"`
+	Versions []int  `json:"versions"`
+}
+
+// ListRegisteredSchemas returns the schemas registered in dir, sorted by
+// name. Subdirectories without a v<N>.schema.json file are ignored.
+func ListRegisteredSchemas(dir string) ([]RegisteredSchema, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var schemas []RegisteredSchema
+	for _, e := range entries {
+		if !e.IsDir() || !schemaRefNamePattern.MatchString(e.Name()) {
+			continue
+		}
+		versions, err := SchemaVersions(dir, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) > 0 {
+			schemas = append(schemas, RegisteredSchema{Name: e.Name(), Versions: versions})
+		}
+	}
+	return schemas, nil
+}
+
+func formatSchemaVersions(versions []int) string {
+	parts := make([]string, len(versions))
+	for i, v := range versions {
+		parts[i] = fmt.Sprintf("v%d", v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SchemaChange is one difference between two schema versions, addressed by
+// JSON pointer. Old is empty for additions and New for removals.
+type SchemaChange struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"` // "added", "removed" or "changed"
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// DiffSchemas compares two JSON schema documents structurally. Objects are
+// compared key by key; arrays and scalars are compared as whole values.
+// Changes are ordered by path.
+func DiffSchemas(oldDoc, newDoc []byte) ([]SchemaChange, error) {
+	var a, b any
+	if err := json.Unmarshal(oldDoc, &a); err != nil {
+		return nil, fmt.Errorf("old schema: %w", err)
+	}
+	if err := json.Unmarshal(newDoc, &b); err != nil {
+		return nil, fmt.Errorf("new schema: %w", err)
+	}
+	var changes []SchemaChange
+	diffSchemaValues("", a, b, &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+func diffSchemaValues(path string, a, b any, changes *[]SchemaChange) {
+	objA, okA := a.(map[string]any)
+	objB, okB := b.(map[string]any)
+	if okA && okB {
+		for k, va := range objA {
+			p := path + "/" + escapeJSONPointer(k)
+			if vb, ok := objB[k]; ok {
+				diffSchemaValues(p, va, vb, changes)
+			} else {
+				*changes = append(*changes, SchemaChange{Path: p, Kind: "removed", Old: compactJSON(va)})
+			}
+		}
+		for k, vb := range objB {
+			if _, ok := objA[k]; !ok {
+				*changes = append(*changes, SchemaChange{Path: path + "/" + escapeJSONPointer(k), Kind: "added", New: compactJSON(vb)})
+			}
+		}
+		return
+	}
+	if oldVal, newVal := compactJSON(a), compactJSON(b); oldVal != newVal {
+		if path == "" {
+			path = "/"
+		}
+		*changes = append(*changes, SchemaChange{Path: path, Kind: "changed", Old: oldVal, New: newVal})
+	}
+}
+
+func escapeJSONPointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func compactJSON(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
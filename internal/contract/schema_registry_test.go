@@ -0,0 +1,78 @@
+package contract
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRegistrySchema(t *testing.T, dir, name string, version int, content string) {
+	t.Helper()
+	path := SchemaVersionPath(dir, name, version)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestParseSchemaRef(t *testing.T) {
+	ref, err := ParseSchemaRef("issue-content@v2")
+	require.NoError(t, err)
+	assert.Equal(t, SchemaRef{Name: "issue-content", Version: 2}, ref)
+	assert.Equal(t, "issue-content@v2", ref.String())
+
+	ref, err = ParseSchemaRef("issue-content")
+	require.NoError(t, err)
+	assert.Equal(t, 0, ref.Version)
+
+	for _, bad := range []string{"", "Issue", "issue@2", "issue@v0", "issue@vx", "../issue@v1"} {
+		_, err := ParseSchemaRef(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestResolveSchemaRef(t *testing.T) {
+	dir := t.TempDir()
+	writeRegistrySchema(t, dir, "issue-content", 1, `{}`)
+	writeRegistrySchema(t, dir, "issue-content", 2, `{}`)
+	writeRegistrySchema(t, dir, "issue-content", 10, `{}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "issue-content", "notes.md"), nil, 0o644))
+
+	path, err := ResolveSchemaRef(dir, "issue-content@v2")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "issue-content", "v2.schema.json"), path)
+
+	path, err = ResolveSchemaRef(dir, "issue-content")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "issue-content", "v10.schema.json"), path, "unpinned resolves to the latest version")
+
+	_, err = ResolveSchemaRef(dir, "issue-content@v3")
+	assert.ErrorContains(t, err, "have v1, v2, v10")
+	_, err = ResolveSchemaRef(dir, "pr-content@v1")
+	assert.ErrorContains(t, err, "no schema")
+
+	schemas, err := ListRegisteredSchemas(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []RegisteredSchema{{Name: "issue-content", Versions: []int{1, 2, 10}}}, schemas)
+}
+
+func TestDiffSchemas(t *testing.T) {
+	v1 := `{"type":"object","required":["title"],"properties":{"title":{"type":"string"},"body":{"type":"string","maxLength":100}}}`
+	v2 := `{"type":"object","required":["title","labels"],"properties":{"title":{"type":"string"},"body":{"type":"string"},"labels":{"type":"array"}}}`
+
+	changes, err := DiffSchemas([]byte(v1), []byte(v2))
+	require.NoError(t, err)
+	assert.Equal(t, []SchemaChange{
+		{Path: "/properties/body/maxLength", Kind: "removed", Old: "100"},
+		{Path: "/properties/labels", Kind: "added", New: `{"type":"array"}`},
+		{Path: "/required", Kind: "changed", Old: `["title"]`, New: `["title","labels"]`},
+	}, changes)
+
+	changes, err = DiffSchemas([]byte(v1), []byte(v1))
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	_, err = DiffSchemas([]byte(`{`), []byte(v1))
+	assert.Error(t, err)
+}
@@ -93,6 +93,7 @@ func (v *testSuiteValidator) Validate(cfg ContractConfig, workspacePath string)
 				Message:      fmt.Sprintf("test suite failed (exit code %d)", exitError.ExitCode()),
 				Details:      details,
 				Retryable:    true,
+				Output:       stdout.String(),
 			}
 		}
 		return &ValidationError{
@@ -147,7 +148,7 @@ func resolveContractDir(dir, workspacePath string) (string, error) {
 		cmd := exec.Command("git", "rev-parse", "--show-toplevel")
 		cmd.Dir = workspacePath
 		if out, err := cmd.Output(); err == nil {
-			return strings.TrimSpace(string(out)), nil
+			return filepath.FromSlash(strings.TrimSpace(string(out))), nil
 		}
 		// Last resort: use process CWD
 		if cwd, err := os.Getwd(); err == nil {
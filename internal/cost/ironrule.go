@@ -21,6 +21,7 @@ var ModelContextWindow = map[string]int{
 const DefaultContextWindow = 200_000
 
 // EstimateTokens estimates token count from byte length using the 4 bytes/token heuristic.
+// Prefer a Tokenizer (see TokenizerFor) when the text itself is available.
 func EstimateTokens(byteLen int) int {
 	return byteLen / 4
 }
@@ -50,8 +51,12 @@ const (
 // CheckIronRule validates that the estimated prompt size fits within the model's context window.
 // Returns OK, Warning (>80%), or Fail (>95%).
 func CheckIronRule(model string, promptBytes int) (IronRuleStatus, string) {
+	return CheckIronRuleTokens(model, EstimateTokens(promptBytes))
+}
+
+// CheckIronRuleTokens is CheckIronRule for a prompt already measured in tokens.
+func CheckIronRuleTokens(model string, estimatedTokens int) (IronRuleStatus, string) {
 	contextWindow := LookupContextWindow(model)
-	estimatedTokens := EstimateTokens(promptBytes)
 
 	ratio := float64(estimatedTokens) / float64(contextWindow)
 
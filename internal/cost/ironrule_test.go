@@ -101,3 +101,15 @@ func TestCheckIronRule(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckIronRuleTokens(t *testing.T) {
+	if status, _ := CheckIronRuleTokens("claude-sonnet", 100_000); status != IronRuleOK {
+		t.Errorf("100k tokens on sonnet: got %d, want OK", status)
+	}
+	if status, _ := CheckIronRuleTokens("claude-sonnet", 170_000); status != IronRuleWarning {
+		t.Errorf("170k tokens on sonnet: got %d, want Warning", status)
+	}
+	if status, msg := CheckIronRuleTokens("claude-sonnet", 195_000); status != IronRuleFail || msg == "" {
+		t.Errorf("195k tokens on sonnet: got %d (%q), want Fail", status, msg)
+	}
+}
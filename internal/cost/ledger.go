@@ -30,26 +30,46 @@ var DefaultPricing = map[string]ModelPricing{
 	"gemini-2.5-flash": {InputPerMillion: 0.15, OutputPerMillion: 0.6},
 }
 
-// LookupPricing returns the pricing for a model name, matching by prefix.
-// Returns zero pricing if no match found.
-func LookupPricing(model string) ModelPricing {
+// Pricing maps model names, or model name prefixes, to their prices.
+type Pricing map[string]ModelPricing
+
+// NewPricing returns DefaultPricing with overrides laid over it, so a
+// manifest can price models Wave does not know and correct stale prices.
+func NewPricing(overrides map[string]ModelPricing) Pricing {
+	p := make(Pricing, len(DefaultPricing)+len(overrides))
+	for model, price := range DefaultPricing {
+		p[model] = price
+	}
+	for model, price := range overrides {
+		p[strings.ToLower(model)] = price
+	}
+	return p
+}
+
+// Lookup returns the pricing for a model name: an exact match, else the
+// longest matching prefix ("claude-opus" matches "claude-opus-4-6", and
+// "gpt-4o-mini-2024" matches "gpt-4o-mini" rather than "gpt-4o"). Returns
+// zero pricing if nothing matches.
+func (p Pricing) Lookup(model string) ModelPricing {
 	model = strings.ToLower(model)
-	// Exact match first
-	if p, ok := DefaultPricing[model]; ok {
-		return p
+	if price, ok := p[model]; ok {
+		return price
 	}
-	// Prefix match (e.g. "claude-opus-4-6" matches "claude-opus")
-	for prefix, p := range DefaultPricing {
-		if strings.HasPrefix(model, prefix) {
-			return p
+	var best string
+	for prefix := range p {
+		if len(prefix) > len(best) && strings.HasPrefix(model, prefix) {
+			best = prefix
 		}
 	}
-	return ModelPricing{}
+	if best == "" {
+		return ModelPricing{}
+	}
+	return p[best]
 }
 
-// ComputeCost calculates the USD cost for a given token usage.
-func ComputeCost(model string, inputTokens, outputTokens int) float64 {
-	pricing := LookupPricing(model)
+// Cost calculates the USD cost of the given token usage of model.
+func (p Pricing) Cost(model string, inputTokens, outputTokens int) float64 {
+	pricing := p.Lookup(model)
 	if pricing.InputPerMillion == 0 && pricing.OutputPerMillion == 0 {
 		return 0
 	}
@@ -58,6 +78,18 @@ func ComputeCost(model string, inputTokens, outputTokens int) float64 {
 	return inputCost + outputCost
 }
 
+// LookupPricing returns the default pricing for a model name, matching by
+// prefix. Returns zero pricing if no match found.
+func LookupPricing(model string) ModelPricing {
+	return Pricing(DefaultPricing).Lookup(model)
+}
+
+// ComputeCost calculates the USD cost for a given token usage at default
+// pricing.
+func ComputeCost(model string, inputTokens, outputTokens int) float64 {
+	return Pricing(DefaultPricing).Cost(model, inputTokens, outputTokens)
+}
+
 // Entry represents a single cost ledger entry for a step execution.
 type Entry struct {
 	RunID        string
@@ -72,6 +104,7 @@ type Entry struct {
 // Ledger tracks cumulative costs for a pipeline run.
 type Ledger struct {
 	mu            sync.Mutex
+	pricing       Pricing
 	entries       []Entry
 	totalCost     float64
 	budgetCeiling float64 // 0 = unlimited
@@ -81,12 +114,25 @@ type Ledger struct {
 
 // NewLedger creates a new cost ledger with optional budget ceiling and warning threshold.
 func NewLedger(budgetCeiling, warnAt float64) *Ledger {
+	return NewLedgerWithPricing(budgetCeiling, warnAt, DefaultPricing)
+}
+
+// NewLedgerWithPricing creates a cost ledger that prices usage with the
+// given table instead of DefaultPricing.
+func NewLedgerWithPricing(budgetCeiling, warnAt float64, pricing Pricing) *Ledger {
 	return &Ledger{
+		pricing:       pricing,
 		budgetCeiling: budgetCeiling,
 		warnAt:        warnAt,
 	}
 }
 
+// Cost prices the given token usage of model with the ledger's pricing,
+// without recording it.
+func (l *Ledger) Cost(model string, inputTokens, outputTokens int) float64 {
+	return l.pricing.Cost(model, inputTokens, outputTokens)
+}
+
 // BudgetStatus represents the result of a budget check.
 type BudgetStatus int
 
@@ -98,7 +144,7 @@ const (
 
 // Record adds a cost entry and returns the budget status.
 func (l *Ledger) Record(runID, stepID, model string, inputTokens, outputTokens, totalTokens int) (Entry, BudgetStatus) {
-	cost := ComputeCost(model, inputTokens, outputTokens)
+	cost := l.Cost(model, inputTokens, outputTokens)
 	entry := Entry{
 		RunID:        runID,
 		StepID:       stepID,
@@ -125,6 +171,26 @@ func (l *Ledger) Record(runID, stepID, model string, inputTokens, outputTokens,
 	return entry, BudgetOK
 }
 
+// Project returns the run's total cost, and its budget status, if a step
+// still in progress were recorded now with the given usage. Nothing is
+// recorded. A warning is reported at most once per ledger, shared with
+// Record, so a step that crossed warn_at mid-run does not warn again when it
+// completes.
+func (l *Ledger) Project(model string, inputTokens, outputTokens int) (float64, BudgetStatus) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	total := l.totalCost + l.Cost(model, inputTokens, outputTokens)
+	if l.budgetCeiling > 0 && total >= l.budgetCeiling {
+		return total, BudgetExceeded
+	}
+	if l.warnAt > 0 && total >= l.warnAt && !l.warned {
+		l.warned = true
+		return total, BudgetWarning
+	}
+	return total, BudgetOK
+}
+
 // TotalCost returns the cumulative cost across all entries.
 func (l *Ledger) TotalCost() float64 {
 	l.mu.Lock()
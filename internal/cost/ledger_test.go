@@ -137,6 +137,29 @@ func TestLedger_BudgetWarning(t *testing.T) {
 	}
 }
 
+func TestLedger_Project(t *testing.T) {
+	l := NewLedger(1.0, 0.5)
+	l.Record("run-1", "step-1", "claude-sonnet", 100_000, 0, 100_000) // $0.30
+
+	total, status := l.Project("claude-sonnet", 10_000, 0) // +$0.03
+	if status != BudgetOK || total < 0.329 || total > 0.331 {
+		t.Errorf("Project() = $%.4f, %d; want $0.33, BudgetOK", total, status)
+	}
+	if _, status = l.Project("claude-sonnet", 100_000, 0); status != BudgetWarning {
+		t.Errorf("expected BudgetWarning crossing warn_at, got %d", status)
+	}
+	if len(l.Entries()) != 1 || l.TotalCost() > 0.301 {
+		t.Error("Project must not record anything")
+	}
+	// The warning is shared with Record.
+	if _, status = l.Record("run-1", "step-2", "claude-sonnet", 100_000, 0, 100_000); status != BudgetOK {
+		t.Errorf("expected no second warning from Record, got %d", status)
+	}
+	if _, status = l.Project("claude-sonnet", 200_000, 0); status != BudgetExceeded {
+		t.Errorf("expected BudgetExceeded, got %d", status)
+	}
+}
+
 func TestLedger_Entries(t *testing.T) {
 	l := NewLedger(0, 0)
 	l.Record("run-1", "step-1", "claude-opus", 1000, 100, 1100)
@@ -174,3 +197,30 @@ func TestLedger_EmptySummary(t *testing.T) {
 		t.Errorf("expected empty summary, got: %s", summary)
 	}
 }
+
+func TestPricingOverrides(t *testing.T) {
+	p := NewPricing(map[string]ModelPricing{
+		"Claude-Sonnet": {InputPerMillion: 4.0, OutputPerMillion: 20.0}, // corrects a default
+		"qwen3":         {InputPerMillion: 0.1, OutputPerMillion: 0.2},  // unknown to Wave
+	})
+
+	if got := p.Lookup("claude-sonnet-4-6"); got.InputPerMillion != 4.0 {
+		t.Errorf("overridden claude-sonnet input price = %v, want 4", got.InputPerMillion)
+	}
+	if got := p.Lookup("qwen3:32b"); got.OutputPerMillion != 0.2 {
+		t.Errorf("qwen3 output price = %v, want 0.2", got.OutputPerMillion)
+	}
+	if got := p.Lookup("claude-opus-4-6"); got.InputPerMillion != 15.0 {
+		t.Errorf("default claude-opus input price = %v, want 15", got.InputPerMillion)
+	}
+	// The longest prefix wins over a shorter one that also matches.
+	if got := p.Lookup("gpt-4o-mini-2024-07-18"); got.InputPerMillion != 0.15 {
+		t.Errorf("gpt-4o-mini snapshot input price = %v, want 0.15", got.InputPerMillion)
+	}
+
+	l := NewLedgerWithPricing(0, 0, p)
+	entry, _ := l.Record("run", "step", "qwen3:32b", 1_000_000, 1_000_000, 2_000_000)
+	if diff := entry.Cost - 0.3; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("qwen3 entry cost = %v, want 0.3", entry.Cost)
+	}
+}
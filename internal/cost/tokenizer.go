@@ -0,0 +1,164 @@
+package cost
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenizer measures text in model tokens. Implementations approximate the
+// byte-pair encodings used by each model family closely enough for budget,
+// context-window, and compaction decisions without shipping vocabularies.
+type Tokenizer interface {
+	// Name identifies the tokenizer family (claude, openai, gemini, generic).
+	Name() string
+	// Count returns the number of tokens text encodes to.
+	Count(text string) int
+}
+
+// Tokenizer families.
+const (
+	TokenizerClaude  = "claude"
+	TokenizerOpenAI  = "openai"
+	TokenizerGemini  = "gemini"
+	TokenizerGeneric = "generic"
+)
+
+// bpeApproxTokenizer approximates a BPE tokenizer by pre-splitting text the
+// way BPE pre-tokenizers do (words, digit groups, punctuation runs,
+// whitespace runs) and charging each piece by its length.
+type bpeApproxTokenizer struct {
+	name string
+	// wordChars is the average number of letters one token covers within a
+	// Latin-script word.
+	wordChars int
+	// digitChars is the maximum digits merged into one token.
+	digitChars int
+}
+
+var tokenizers = map[string]*bpeApproxTokenizer{
+	TokenizerClaude:  {name: TokenizerClaude, wordChars: 5, digitChars: 1},
+	TokenizerOpenAI:  {name: TokenizerOpenAI, wordChars: 6, digitChars: 3},
+	TokenizerGemini:  {name: TokenizerGemini, wordChars: 6, digitChars: 1},
+	TokenizerGeneric: {name: TokenizerGeneric, wordChars: 5, digitChars: 2},
+}
+
+// tokenizerModelPrefixes maps model-name prefixes to tokenizer families.
+// Provider-qualified names ("anthropic/claude-sonnet-4") are matched on the
+// part after the slash.
+var tokenizerModelPrefixes = []struct {
+	prefix string
+	family string
+}{
+	{"claude", TokenizerClaude},
+	{"gpt", TokenizerOpenAI},
+	{"o1", TokenizerOpenAI},
+	{"o3", TokenizerOpenAI},
+	{"o4", TokenizerOpenAI},
+	{"codex", TokenizerOpenAI},
+	{"gemini", TokenizerGemini},
+}
+
+// tokenizerAdapters maps adapter names to the tokenizer family of the models
+// they run by default, used when the model is a tier name or unset.
+var tokenizerAdapters = map[string]string{
+	"claude": TokenizerClaude,
+	"codex":  TokenizerOpenAI,
+	"gemini": TokenizerGemini,
+}
+
+// TokenizerFor returns the tokenizer for a model, falling back to the
+// adapter's default model family and then to a generic approximation.
+func TokenizerFor(adapterName, model string) Tokenizer {
+	name := strings.ToLower(model)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	for _, p := range tokenizerModelPrefixes {
+		if strings.HasPrefix(name, p.prefix) {
+			return tokenizers[p.family]
+		}
+	}
+	if family, ok := tokenizerAdapters[strings.ToLower(adapterName)]; ok {
+		return tokenizers[family]
+	}
+	return tokenizers[TokenizerGeneric]
+}
+
+// CountTokens counts the tokens of text for the given adapter and model.
+func CountTokens(adapterName, model, text string) int {
+	return TokenizerFor(adapterName, model).Count(text)
+}
+
+func (t *bpeApproxTokenizer) Name() string { return t.name }
+
+func (t *bpeApproxTokenizer) Count(text string) int {
+	runes := []rune(text)
+	tokens := 0
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		j := i + 1
+		switch {
+		case isIdeographic(r):
+			// CJK, kana and hangul encode at roughly one token per character.
+			tokens++
+		case unicode.IsDigit(r):
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			tokens += ceilDiv(j-i, t.digitChars)
+		case unicode.IsLetter(r):
+			weight := letterWeight(r)
+			for j < len(runes) && unicode.IsLetter(runes[j]) && !isIdeographic(runes[j]) {
+				weight += letterWeight(runes[j])
+				j++
+			}
+			tokens += ceilDiv(weight, t.wordChars)
+		case r == ' ':
+			// A single space is merged into the following word; longer runs
+			// (indentation, alignment) become one token of their own.
+			for j < len(runes) && runes[j] == ' ' {
+				j++
+			}
+			if j-i > 1 {
+				tokens++
+			}
+		case unicode.IsSpace(r):
+			for j < len(runes) && unicode.IsSpace(runes[j]) {
+				j++
+			}
+			tokens++
+		default:
+			// Punctuation and symbols merge into pairs ("},", "//", ":=").
+			for j < len(runes) && isSymbol(runes[j]) {
+				j++
+			}
+			tokens += ceilDiv(j-i, 2)
+		}
+		i = j
+	}
+	return tokens
+}
+
+// letterWeight charges non-Latin letters double: Cyrillic, Greek, Arabic and
+// similar scripts split into roughly twice as many tokens as ASCII words.
+func letterWeight(r rune) int {
+	if r < unicode.MaxASCII || unicode.Is(unicode.Latin, r) {
+		return 1
+	}
+	return 2
+}
+
+func isIdeographic(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+func isSymbol(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+}
+
+func ceilDiv(n, d int) int {
+	if d <= 1 {
+		return n
+	}
+	return (n + d - 1) / d
+}
@@ -0,0 +1,73 @@
+package cost
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenizerFor(t *testing.T) {
+	tests := []struct {
+		adapter string
+		model   string
+		want    string
+	}{
+		{"claude", "claude-sonnet-4-5", TokenizerClaude},
+		{"opencode", "anthropic/claude-opus-4", TokenizerClaude},
+		{"opencode", "openai/gpt-4o", TokenizerOpenAI},
+		{"codex", "o4-mini", TokenizerOpenAI},
+		{"gemini", "gemini-2.5-pro", TokenizerGemini},
+		{"claude", "balanced", TokenizerClaude},
+		{"codex", "", TokenizerOpenAI},
+		{"", "", TokenizerGeneric},
+		{"custom", "local-llama", TokenizerGeneric},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.adapter+"/"+tt.model, func(t *testing.T) {
+			if got := TokenizerFor(tt.adapter, tt.model).Name(); got != tt.want {
+				t.Errorf("TokenizerFor(%q, %q) = %q, want %q", tt.adapter, tt.model, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizerCount(t *testing.T) {
+	tok := TokenizerFor("claude", "")
+
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{"empty", "", 0},
+		{"short words", "the cat sat", 3},
+		{"long word splits", "implementation", 3},
+		{"digits", "2026", 4},
+		{"punctuation pairs", `{"a":1}`, 5},
+		{"newline", "a\nb", 3},
+		{"indentation", "    x", 2},
+		{"cjk per character", "日本語", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tok.Count(tt.text); got != tt.want {
+				t.Errorf("Count(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizerCount_DiffersFromByteHeuristic(t *testing.T) {
+	// Multi-byte scripts are far denser in tokens than bytes/4 suggests.
+	cjk := strings.Repeat("日本語のテキスト", 100)
+	if got, bytes := CountTokens("claude", "", cjk), EstimateTokens(len(cjk)); got <= bytes {
+		t.Errorf("CJK tokens = %d, want more than byte estimate %d", got, bytes)
+	}
+
+	// OpenAI tokenizers merge digit runs, Claude's does not.
+	digits := "1234567890"
+	if claude, openai := CountTokens("claude", "", digits), CountTokens("codex", "", digits); claude <= openai {
+		t.Errorf("claude digits = %d, openai digits = %d, want claude > openai", claude, openai)
+	}
+}
@@ -374,7 +374,11 @@ func (m *ProgressModel) renderCurrentStep() string {
 				stepStart := time.Unix(0, m.ctx.CurrentStepStart)
 				stepElapsed = time.Since(stepStart)
 			}
-			stepLine += fmt.Sprintf(" (%s)", formatElapsed(stepElapsed))
+			if tokens := m.ctx.StepTokens[stepID]; tokens > 0 {
+				stepLine += fmt.Sprintf(" (%s, %s tokens)", formatElapsed(stepElapsed), FormatTokenCount(tokens))
+			} else {
+				stepLine += fmt.Sprintf(" (%s)", formatElapsed(stepElapsed))
+			}
 
 			if m.ctx.CurrentAction != "" && stepID == m.ctx.CurrentStepID {
 				stepLine += fmt.Sprintf(" • %s", m.ctx.CurrentAction)
@@ -307,10 +307,23 @@ func (btpd *BubbleTeaProgressDisplay) updateFromEvent(evt event.Event) {
 		step.Progress = evt.Progress
 	}
 
-	// Capture tool activity for verbose mode (per-step).
+	// Running token counts arrive as step_progress while the step executes.
+	if evt.State == "step_progress" && step.State == StateRunning {
+		if evt.TokensUsed > 0 {
+			btpd.stepTokens[evt.StepID] = evt.TokensUsed
+		}
+		if evt.TokensIn > 0 {
+			btpd.stepTokensIn[evt.StepID] = evt.TokensIn
+		}
+		if evt.TokensOut > 0 {
+			btpd.stepTokensOut[evt.StepID] = evt.TokensOut
+		}
+	}
+
+	// Capture tool activity (per-step) for the live feed under each running step.
 	// Guard: drop stream_activity for steps that are already completed or not yet started.
 	// This prevents phantom activity from shared-worktree steps leaking to wrong steps.
-	if btpd.verbose && evt.State == "stream_activity" && evt.ToolName != "" {
+	if evt.State == "stream_activity" && evt.ToolName != "" {
 		if step.State == StateRunning {
 			btpd.stepToolActivity[evt.StepID] = [2]string{evt.ToolName, evt.ToolTarget}
 			btpd.lastToolName = evt.ToolName
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/recinq/wave/internal/event"
 	"github.com/recinq/wave/internal/humanize"
@@ -50,6 +51,10 @@ type EventLineOpts struct {
 	// BasicVerbose gates emission of stream_activity lines in the basic-CLI
 	// profile.
 	BasicVerbose bool
+
+	// BasicActivity enables the compact "persona ▸ Tool target" feed for
+	// stream_activity when BasicVerbose is off.
+	BasicActivity bool
 }
 
 // LiveTUIProfile returns options for the live-TUI / stored-event renderer.
@@ -233,7 +238,8 @@ func eventLineLive(evt event.Event, opts EventLineOpts) string {
 // Returns emit=false for events the basic profile suppresses entirely:
 //   - started/running with no persona (no contextual line)
 //   - step_progress with no action
-//   - non-verbose stream_activity, or stream_activity for non-running steps
+//   - stream_activity when neither verbose nor the activity feed is on, or
+//     for non-running steps
 //   - contract_passed/failed/soft_failure (handover metadata; rendered later by
 //     renderHandoverMetadata, not as an inline event line)
 //   - any state not handled by the original switch (e.g. eta_updated)
@@ -245,6 +251,9 @@ func eventLineBasicCLI(evt event.Event, opts EventLineOpts) (string, bool) {
 		if evt.State == "warning" && evt.Message != "" {
 			return fmt.Sprintf("[%s] ⚠ %s", timestamp, evt.Message), true
 		}
+		if evt.State == event.StateLockWaiting {
+			return fmt.Sprintf("[%s] ⏸ %s", timestamp, evt.Message), true
+		}
 		return "", false
 	}
 
@@ -285,31 +294,50 @@ func eventLineBasicCLI(evt event.Event, opts EventLineOpts) (string, bool) {
 	case "warning":
 		return fmt.Sprintf("[%s] ⚠ %s %s", timestamp, evt.StepID, evt.Message), true
 
+	case event.StateLockWaiting:
+		return fmt.Sprintf("[%s] ⏸ %s %s", timestamp, evt.StepID, evt.Message), true
+
 	case "validating", "contract_validating":
 		return fmt.Sprintf("[%s]   %s validating contract", timestamp, evt.StepID), true
 
 	case "stream_activity":
-		if !opts.BasicVerbose || evt.ToolName == "" {
+		if evt.ToolName == "" {
 			return "", false
 		}
-		// Compute available width: total - prefix overhead
-		// Format: "[HH:MM:SS]   %-20s %s → " = 10 + 3 + 20 + 1 + len(toolName) + 3
 		width := 80
 		if opts.BasicTermInfo != nil {
 			width = opts.BasicTermInfo.GetWidth()
 		}
-		overhead := 37 + len(evt.ToolName)
-		maxTarget := width - overhead
-		if maxTarget < 20 {
-			maxTarget = 20
-		}
-		target := evt.ToolTarget
-		if len(target) > maxTarget {
-			target = target[:maxTarget-3] + "..."
+		if !opts.BasicVerbose {
+			if !opts.BasicActivity {
+				return "", false
+			}
+			// Compact feed: "[HH:MM:SS]   persona ▸ Tool target"
+			who := evt.Persona
+			if who == "" {
+				who = evt.StepID
+			}
+			target := truncateTarget(evt.ToolTarget, width-(18+len(who)+len(evt.ToolName)))
+			return strings.TrimRight(fmt.Sprintf("[%s]   %s ▸ %s %s", timestamp, who, evt.ToolName, target), " "), true
 		}
+		// Compute available width: total - prefix overhead
+		// Format: "[HH:MM:SS]   %-20s %s → " = 10 + 3 + 20 + 1 + len(toolName) + 3
+		target := truncateTarget(evt.ToolTarget, width-(37+len(evt.ToolName)))
 		return fmt.Sprintf("[%s]   %-20s %s → %s", timestamp, evt.StepID, evt.ToolName, target), true
 
 	default:
 		return "", false
 	}
 }
+
+// truncateTarget shortens a tool target to maxLen runes (at least 20) with a
+// trailing ellipsis, cutting on a rune boundary.
+func truncateTarget(target string, maxLen int) string {
+	if maxLen < 20 {
+		maxLen = 20
+	}
+	if utf8.RuneCountInString(target) > maxLen {
+		return string([]rune(target)[:maxLen-3]) + "..."
+	}
+	return target
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/recinq/wave/internal/event"
 )
@@ -65,6 +66,20 @@ func TestEventLine_LiveTUI_StreamActivity_Truncation(t *testing.T) {
 	}
 }
 
+func TestTruncateTarget_RuneBoundary(t *testing.T) {
+	long := strings.Repeat("é", 30)
+	got := truncateTarget(long, 20)
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncated target is not valid UTF-8: %q", got)
+	}
+	if want := strings.Repeat("é", 17) + "..."; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+	if short := strings.Repeat("é", 20); truncateTarget(short, 20) != short {
+		t.Errorf("target of 20 runes was truncated")
+	}
+}
+
 func TestEventLine_LiveTUI_StreamActivity_StoredFallback(t *testing.T) {
 	// LogRecord adapter sets Message and leaves ToolName/Target empty.
 	evt := event.Event{StepID: "s", State: event.StateStreamActivity, Message: "Read /a/b"}
@@ -124,6 +139,18 @@ func TestEventLine_BasicCLI_PipelineWarning(t *testing.T) {
 	}
 }
 
+func TestEventLine_BasicCLI_LockWaiting(t *testing.T) {
+	msg := `waiting for lock "db" held by run r1 step migrate (position 1 in queue)`
+	line, emit := EventLine(event.Event{State: event.StateLockWaiting, Message: msg}, BasicCLIProfile("12:00:00", nil, false))
+	if !emit || line != "[12:00:00] ⏸ "+msg {
+		t.Errorf("pipeline lock wait: got %q emit=%v", line, emit)
+	}
+	line, emit = EventLine(event.Event{StepID: "migrate", State: event.StateLockWaiting, Message: msg}, BasicCLIProfile("12:00:00", nil, false))
+	if !emit || line != "[12:00:00] ⏸ migrate "+msg {
+		t.Errorf("step lock wait: got %q emit=%v", line, emit)
+	}
+}
+
 func TestEventLine_BasicCLI_PipelineLevelOtherSuppressed(t *testing.T) {
 	evt := event.Event{State: "completed"} // no StepID, no warning
 	_, emit := EventLine(evt, BasicCLIProfile("00:00:00", nil, false))
@@ -166,6 +193,28 @@ func TestEventLine_BasicCLI_StreamActivity_VerboseFalse_Suppressed(t *testing.T)
 	}
 }
 
+func TestEventLine_BasicCLI_StreamActivity_Feed(t *testing.T) {
+	ti := &TerminalInfo{capabilities: &TerminalCapabilities{Width: 60}}
+	opts := BasicCLIProfile("12:00:00", ti, false)
+	opts.BasicActivity = true
+
+	evt := event.Event{StepID: "s", State: "stream_activity", Persona: "craftsman", ToolName: "Edit", ToolTarget: "internal/foo.go"}
+	got, emit := EventLine(evt, opts)
+	if !emit || got != "[12:00:00]   craftsman ▸ Edit internal/foo.go" {
+		t.Errorf("got %q emit=%v", got, emit)
+	}
+
+	evt = event.Event{StepID: "s", State: "stream_activity", ToolName: "Bash", ToolTarget: strings.Repeat("a", 100)}
+	got, _ = EventLine(evt, opts)
+	if !strings.HasPrefix(got, "[12:00:00]   s ▸ Bash aaa") || !strings.HasSuffix(got, "...") || len(got) > 60+len("▸")-1 {
+		t.Errorf("expected persona fallback to step ID and width truncation, got %q (%d bytes)", got, len(got))
+	}
+
+	if _, emit := EventLine(event.Event{StepID: "s", State: "stream_activity"}, opts); emit {
+		t.Error("expected no feed line without a tool name")
+	}
+}
+
 func TestEventLine_BasicCLI_StreamActivity_Verbose(t *testing.T) {
 	evt := event.Event{StepID: "s", State: "stream_activity", ToolName: "Read", ToolTarget: "/a/b"}
 	ti := &TerminalInfo{capabilities: &TerminalCapabilities{Width: 120}}
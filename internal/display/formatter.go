@@ -422,3 +422,12 @@ func FormatTokenCount(tokens int) string {
 	return fmt.Sprintf("%.1fB", float64(tokens)/1_000_000_000.0)
 }
 
+// FormatCost formats an estimated USD cost, keeping sub-cent amounts
+// visible.
+func FormatCost(usd float64) string {
+	if usd > 0 && usd < 0.01 {
+		return fmt.Sprintf("$%.4f", usd)
+	}
+	return fmt.Sprintf("$%.2f", usd)
+}
+
@@ -35,6 +35,23 @@ func TestFormatTokenCount(t *testing.T) {
 	}
 }
 
+func TestFormatCost(t *testing.T) {
+	tests := []struct {
+		usd  float64
+		want string
+	}{
+		{0, "$0.00"},
+		{0.0042, "$0.0042"},
+		{0.01, "$0.01"},
+		{12.345, "$12.35"},
+	}
+	for _, tt := range tests {
+		if got := FormatCost(tt.usd); got != tt.want {
+			t.Errorf("FormatCost(%v) = %q, want %q", tt.usd, got, tt.want)
+		}
+	}
+}
+
 // =============================================================================
 // Benchmarks
 // =============================================================================
@@ -417,8 +417,16 @@ func (pd *ProgressDisplay) EmitProgress(ev event.Event) error {
 			step.UpdateState(StateRunning)
 			step.Message = ev.Message
 		case "step_progress":
-			step.Progress = ev.Progress
-			step.CurrentAction = ev.CurrentAction
+			// Token-only updates carry no progress or action.
+			if ev.Progress > 0 {
+				step.Progress = ev.Progress
+			}
+			if ev.CurrentAction != "" {
+				step.CurrentAction = ev.CurrentAction
+			}
+			if ev.TokensUsed > 0 {
+				step.TokensUsed = ev.TokensUsed
+			}
 		case "warning":
 			step.Message = ev.Message
 		case "validating", "contract_validating":
@@ -616,6 +624,7 @@ type BasicProgressDisplay struct {
 	mu           sync.Mutex
 	writer       io.Writer
 	verbose      bool
+	activity     bool // compact tool-activity feed when not verbose
 	termInfo     *TerminalInfo
 	handoverInfo map[string]*HandoverInfo // Per-step handover metadata
 	stepOrder    []string                 // Ordered list of step IDs (for target lookup)
@@ -644,6 +653,15 @@ func NewBasicProgressDisplayWithVerbose(verbose bool) *BasicProgressDisplay {
 	}
 }
 
+// NewBasicProgressDisplayWithActivity creates a progress display that also
+// prints a compact live feed of tool calls ("craftsman ▸ Edit foo.go") when
+// not verbose. Verbose mode keeps its detailed per-step activity lines.
+func NewBasicProgressDisplayWithActivity(verbose, activity bool) *BasicProgressDisplay {
+	bpd := NewBasicProgressDisplayWithVerbose(verbose)
+	bpd.activity = activity
+	return bpd
+}
+
 // EmitProgress outputs simple text-based progress updates.
 //
 // State-tracking side effects (stepStates, stepOrder, handoverInfo) are kept
@@ -665,7 +683,9 @@ func (bpd *BasicProgressDisplay) EmitProgress(ev event.Event) error {
 		return nil
 	}
 
-	if line, emit := EventLine(ev, BasicCLIProfile(timestamp, bpd.termInfo, bpd.verbose)); emit {
+	opts := BasicCLIProfile(timestamp, bpd.termInfo, bpd.verbose)
+	opts.BasicActivity = bpd.activity
+	if line, emit := EventLine(ev, opts); emit {
 		fmt.Fprintln(bpd.writer, line)
 	}
 
@@ -807,3 +807,26 @@ func TestBasicProgressDisplay_StreamActivityGuard(t *testing.T) {
 		})
 	}
 }
+
+func TestBasicProgressDisplay_ActivityFeed(t *testing.T) {
+	for _, activity := range []bool{true, false} {
+		var buf bytes.Buffer
+		bpd := NewBasicProgressDisplayWithActivity(false, activity)
+		bpd.writer = &buf
+		bpd.stepStates["implement"] = "running"
+
+		_ = bpd.EmitProgress(event.Event{
+			Timestamp:  time.Now(),
+			StepID:     "implement",
+			State:      "stream_activity",
+			Persona:    "craftsman",
+			ToolName:   "Edit",
+			ToolTarget: "internal/foo.go",
+		})
+
+		got := strings.Contains(buf.String(), "craftsman ▸ Edit internal/foo.go")
+		if got != activity {
+			t.Errorf("activity=%v: feed line present = %v, output %q", activity, got, buf.String())
+		}
+	}
+}
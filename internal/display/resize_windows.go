@@ -0,0 +1,9 @@
+//go:build windows
+
+package display
+
+import "os"
+
+// notifyResize is a no-op: Windows consoles have no resize signal, so
+// resize callbacks never fire and the size detected at start is kept.
+func notifyResize(ch chan os.Signal) {}
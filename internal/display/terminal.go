@@ -6,7 +6,6 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
 
 	"golang.org/x/term"
 )
@@ -377,8 +376,8 @@ func (rh *ResizeHandler) Start() {
 	rh.running = true
 	rh.mu.Unlock()
 
-	// Register for SIGWINCH (window change signal)
-	signal.Notify(rh.signalChan, syscall.SIGWINCH)
+	// Register for window change notifications (SIGWINCH; none on Windows)
+	notifyResize(rh.signalChan)
 
 	go rh.handleResizeEvents()
 }
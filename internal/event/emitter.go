@@ -26,6 +26,10 @@ type Event struct {
 	TokensIn   int       `json:"tokens_in,omitempty"`  // Input tokens (prompt + cache creation)
 	TokensOut  int       `json:"tokens_out,omitempty"` // Output tokens (completion)
 
+	// TokensEstimated is set when the adapter reported no usage and the
+	// token counts are a tokenizer estimate.
+	TokensEstimated bool `json:"tokens_estimated,omitempty"`
+
 	// Progress tracking fields (optional, for enhanced visualization)
 	Progress        int     `json:"progress,omitempty"`         // 0-100 percentage for step progress
 	CurrentAction   string  `json:"current_action,omitempty"`   // Current action being performed
@@ -36,9 +40,10 @@ type Event struct {
 	CompactionStats *string `json:"compaction_stats,omitempty"` // Compaction statistics (JSON)
 
 	// Error classification fields (context exhaustion handling)
-	FailureReason string `json:"failure_reason,omitempty"` // "timeout", "context_exhaustion", "general_error"
+	FailureReason string `json:"failure_reason,omitempty"` // Adapter failure reason: "rate_limit", "auth", "crash", ...
 	FailureClass  string `json:"failure_class,omitempty"`  // Pipeline-level failure classification (transient, deterministic, etc.)
 	Remediation   string `json:"remediation,omitempty"`    // Actionable suggestion for the user
+	LogPath       string `json:"log_path,omitempty"`       // Raw adapter stdout/stderr of the failed step
 
 	// Stream event fields (real-time Claude Code activity)
 	ToolName   string `json:"tool_name,omitempty"`   // Tool being used (Read, Write, Bash, etc.)
@@ -117,6 +122,9 @@ const (
 	StateRetrying       = "retrying"
 	StateSkipped        = "skipped"
 	StateReworking      = "reworking"
+	// StateWaitingApproval marks an approval gate step paused until
+	// `wave approve` decides it or its timeout rejects it.
+	StateWaitingApproval = "waiting_approval"
 	// StateRejected is a terminal state distinct from StateFailed. It signals
 	// that a step (or run) was halted by an *intentional design rejection*: a
 	// contract with on_failure: rejected fired because the upstream
@@ -154,6 +162,7 @@ const (
 	StateBranchEvaluated    = "branch_evaluated"    // Branch condition resolved
 	StateGateWaiting        = "gate_waiting"        // Gate step blocking
 	StateGateResolved       = "gate_resolved"       // Gate condition met
+	StateLockWaiting        = "lock_waiting"        // Step or run queued for a resource lock
 	StateLoopIteration      = "loop_iteration"      // Loop iteration started
 	StateLoopCompleted      = "loop_completed"      // Loop terminated
 	StateAggregateCompleted = "aggregate_completed" // Aggregation finished
@@ -0,0 +1,160 @@
+package fileutil
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ArchiveExt is the file extension of archives written by ArchiveDir.
+const ArchiveExt = ".tar.zst"
+
+// ArchiveStats summarizes the contents of a directory archive.
+type ArchiveStats struct {
+	Files        int   `json:"files"`
+	Bytes        int64 `json:"bytes"`         // total uncompressed file bytes
+	ArchiveBytes int64 `json:"archive_bytes"` // size of the .tar.zst on disk
+}
+
+// ArchiveDir writes the regular files and directories under src to dest as a
+// zstd-compressed tarball. Entry names are relative to src. Symlinks and
+// other special files are skipped.
+func ArchiveDir(src, dest string) (ArchiveStats, error) {
+	var stats ArchiveStats
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return stats, err
+	}
+	out, err := os.Create(dest)
+	if err != nil {
+		return stats, err
+	}
+	defer out.Close()
+
+	zw, err := zstd.NewWriter(out)
+	if err != nil {
+		return stats, err
+	}
+	tw := tar.NewWriter(zw)
+
+	walkErr := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		n, err := io.Copy(tw, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		stats.Files++
+		stats.Bytes += n
+		return nil
+	})
+	if walkErr != nil {
+		tw.Close()
+		zw.Close()
+		return stats, walkErr
+	}
+	if err := tw.Close(); err != nil {
+		zw.Close()
+		return stats, err
+	}
+	if err := zw.Close(); err != nil {
+		return stats, err
+	}
+	if info, err := out.Stat(); err == nil {
+		stats.ArchiveBytes = info.Size()
+	}
+	return stats, out.Close()
+}
+
+// ExtractArchive unpacks a tarball written by ArchiveDir into dest. Entries
+// that would escape dest are rejected.
+func ExtractArchive(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	zr, err := zstd.NewReader(in)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+	root := filepath.Clean(dest) + string(os.PathSeparator)
+
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(target+string(os.PathSeparator), root) {
+			return fmt.Errorf("archive entry %q escapes destination", hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, hdr.FileInfo().Mode().Perm())
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package fileutil
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestArchiveDir_RoundTrip(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "src")
+	if err := os.MkdirAll(filepath.Join(src, "sub", "empty"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "a.txt"), []byte("alpha"), 0644); err != nil {
+		t.Fatalf("write a: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "b.bin"), []byte{0, 1, 2, 3}, 0600); err != nil {
+		t.Fatalf("write b: %v", err)
+	}
+
+	archive := filepath.Join(tmp, "out", "src"+ArchiveExt)
+	stats, err := ArchiveDir(src, archive)
+	if err != nil {
+		t.Fatalf("ArchiveDir: %v", err)
+	}
+	if stats.Files != 2 || stats.Bytes != 9 {
+		t.Errorf("stats: got %+v, want 2 files / 9 bytes", stats)
+	}
+	if info, err := os.Stat(archive); err != nil || info.Size() != stats.ArchiveBytes {
+		t.Errorf("archive size: stat=%v err=%v, stats.ArchiveBytes=%d", info, err, stats.ArchiveBytes)
+	}
+
+	dest := filepath.Join(tmp, "dest")
+	if err := ExtractArchive(archive, dest); err != nil {
+		t.Fatalf("ExtractArchive: %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dest, "a.txt")); string(got) != "alpha" {
+		t.Errorf("a.txt: got %q", got)
+	}
+	info, err := os.Stat(filepath.Join(dest, "sub", "b.bin"))
+	if err != nil {
+		t.Fatalf("stat b.bin: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("b.bin mode: got %v want 0600", info.Mode().Perm())
+	}
+	if info, err := os.Stat(filepath.Join(dest, "sub", "empty")); err != nil || !info.IsDir() {
+		t.Errorf("empty dir not restored: %v", err)
+	}
+}
+
+func TestExtractArchive_RejectsEscape(t *testing.T) {
+	tmp := t.TempDir()
+	archive := filepath.Join(tmp, "evil"+ArchiveExt)
+
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	zw, _ := zstd.NewWriter(f)
+	tw := tar.NewWriter(zw)
+	body := "pwned"
+	if err := tw.WriteHeader(&tar.Header{Name: "../escape.txt", Mode: 0644, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatalf("header: %v", err)
+	}
+	_, _ = tw.Write([]byte(body))
+	tw.Close()
+	zw.Close()
+	f.Close()
+
+	err = ExtractArchive(archive, filepath.Join(tmp, "dest"))
+	if err == nil || !strings.Contains(err.Error(), "escapes destination") {
+		t.Fatalf("expected escape error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "escape.txt")); !os.IsNotExist(err) {
+		t.Error("entry was written outside destination")
+	}
+}
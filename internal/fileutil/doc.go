@@ -1,3 +1,4 @@
 // Package fileutil provides file and directory copy helpers that auto-create
-// parent directories and recurse into subdirectories.
+// parent directories and recurse into subdirectories, and tar.zst archiving
+// of directory trees.
 package fileutil
@@ -20,5 +20,9 @@ type Client interface {
 	// CreatePullRequestReview submits a review on a pull request.
 	// event must be one of "APPROVE", "REQUEST_CHANGES", or "COMMENT".
 	CreatePullRequestReview(ctx context.Context, owner, repo string, number int, event, body string) error
+	// CreateIssue opens an issue. Labels are matched by name; labels the
+	// repository does not define may be dropped or rejected by the forge.
+	CreateIssue(ctx context.Context, owner, repo string, req CreateIssueRequest) (*Issue, error)
+	CreateIssueComment(ctx context.Context, owner, repo string, number int, body string) error
 	ForgeType() ForgeType
 }
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -425,6 +426,50 @@ func (g *GiteaClient) CreatePullRequestReview(ctx context.Context, owner, repo s
 	return g.do(ctx, http.MethodPost, path, strings.NewReader(string(b)), nil)
 }
 
+func (g *GiteaClient) CreateIssue(ctx context.Context, owner, repo string, req CreateIssueRequest) (*Issue, error) {
+	// Gitea takes label IDs, not names; resolve them and drop the names the
+	// repository does not define.
+	var labelIDs []int64
+	if len(req.Labels) > 0 {
+		var raw []struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+		}
+		if err := g.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/labels?limit=100", owner, repo), nil, &raw); err != nil {
+			return nil, err
+		}
+		for _, l := range raw {
+			if slices.Contains(req.Labels, l.Name) {
+				labelIDs = append(labelIDs, l.ID)
+			}
+		}
+	}
+	payload := struct {
+		Title  string  `json:"title"`
+		Body   string  `json:"body,omitempty"`
+		Labels []int64 `json:"labels,omitempty"`
+	}{Title: req.Title, Body: req.Body, Labels: labelIDs}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var gi giteaIssue
+	path := fmt.Sprintf("/repos/%s/%s/issues", owner, repo)
+	if err := g.do(ctx, http.MethodPost, path, strings.NewReader(string(b)), &gi); err != nil {
+		return nil, err
+	}
+	return convertGiteaIssue(&gi), nil
+}
+
+func (g *GiteaClient) CreateIssueComment(ctx context.Context, owner, repo string, number int, body string) error {
+	b, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", owner, repo, number)
+	return g.do(ctx, http.MethodPost, path, strings.NewReader(string(b)), nil)
+}
+
 func mapReviewEvent(canonical string) string {
 	switch strings.ToUpper(canonical) {
 	case "APPROVE", "APPROVED":
@@ -149,6 +149,35 @@ func TestGiteaClient_CreatePullRequestReview_BodyShape(t *testing.T) {
 	assert.Equal(t, "lgtm", receivedPayload["body"])
 }
 
+func TestGiteaClient_CreateIssue_ResolvesLabelIDs(t *testing.T) {
+	var receivedPayload struct {
+		Title  string  `json:"title"`
+		Labels []int64 `json:"labels"`
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repos/owner/repo/labels", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id": 3, "name": "bug"}, {"id": 8, "name": "wave-failure"}]`))
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/issues", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		_ = json.NewDecoder(r.Body).Decode(&receivedPayload)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number": 12, "title": "t", "state": "open", "user": {"login": "wave"}}`))
+	})
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := giteaClientForTest(t, srv)
+	got, err := c.CreateIssue(context.Background(), "owner", "repo", CreateIssueRequest{
+		Title:  "t",
+		Labels: []string{"wave-failure", "missing"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 12, got.Number)
+	assert.Equal(t, "t", receivedPayload.Title)
+	assert.Equal(t, []int64{8}, receivedPayload.Labels, "unknown label names must be dropped")
+}
+
 func TestGiteaClient_NonOKReturnsError(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/v1/repos/owner/repo/issues/9", func(w http.ResponseWriter, r *http.Request) {
@@ -126,6 +126,23 @@ func (g *GitHubClient) CreatePullRequestReview(ctx context.Context, owner, repo
 	return g.client.CreatePullRequestReview(ctx, owner, repo, number, event, body)
 }
 
+func (g *GitHubClient) CreateIssue(ctx context.Context, owner, repo string, req CreateIssueRequest) (*Issue, error) {
+	gi, err := g.client.CreateIssue(ctx, owner, repo, github.CreateIssueRequest{
+		Title:  req.Title,
+		Body:   req.Body,
+		Labels: req.Labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return convertGitHubIssue(gi), nil
+}
+
+func (g *GitHubClient) CreateIssueComment(ctx context.Context, owner, repo string, number int, body string) error {
+	_, err := g.client.CreateIssueComment(ctx, owner, repo, number, body)
+	return err
+}
+
 func (g *GitHubClient) ListIssueComments(ctx context.Context, owner, repo string, number int, limit int) ([]*Comment, error) {
 	ghComments, err := g.client.ListIssueComments(ctx, owner, repo, number, limit)
 	if err != nil {
@@ -50,3 +50,11 @@ func (u *UnsupportedClient) ListIssueComments(_ context.Context, _, _ string, _
 func (u *UnsupportedClient) CreatePullRequestReview(_ context.Context, _, _ string, _ int, _, _ string) error {
 	return fmt.Errorf("%w: %s", ErrNotSupported, u.forgeType)
 }
+
+func (u *UnsupportedClient) CreateIssue(_ context.Context, _, _ string, _ CreateIssueRequest) (*Issue, error) {
+	return nil, fmt.Errorf("%w: %s", ErrNotSupported, u.forgeType)
+}
+
+func (u *UnsupportedClient) CreateIssueComment(_ context.Context, _, _ string, _ int, _ string) error {
+	return fmt.Errorf("%w: %s", ErrNotSupported, u.forgeType)
+}
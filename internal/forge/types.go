@@ -50,6 +50,13 @@ type PullRequest struct {
 	HTMLURL      string
 }
 
+// CreateIssueRequest describes an issue to open.
+type CreateIssueRequest struct {
+	Title  string
+	Body   string
+	Labels []string
+}
+
 // ListIssuesOptions configures issue listing.
 type ListIssuesOptions struct {
 	State   string // "open", "closed", "all"
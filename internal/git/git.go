@@ -105,3 +105,13 @@ func VerifyRef(ref string) (bool, error) {
 	}
 	return true, nil
 }
+
+// ResolveRef returns the full commit hash a ref points to
+// (`git rev-parse --verify <ref>^{commit}`).
+func ResolveRef(ref string) (string, error) {
+	out, err := run("rev-parse", "--verify", ref+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
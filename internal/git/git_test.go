@@ -201,3 +201,19 @@ func TestSetRunnerNilRestoresDefault(t *testing.T) {
 		t.Errorf("SetRunner(nil) did not restore default runner")
 	}
 }
+
+func TestResolveRef(t *testing.T) {
+	r, calls := newStubRunner([]stubResponse{{out: []byte("0123456789abcdef0123456789abcdef01234567\n")}})
+	withRunner(t, r)
+
+	sha, err := ResolveRef("refs/heads/feature/x")
+	if err != nil {
+		t.Fatalf("ResolveRef: %v", err)
+	}
+	if sha != "0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("got %q", sha)
+	}
+	if (*calls)[0][2] != "refs/heads/feature/x^{commit}" {
+		t.Errorf("unexpected args: %v", *calls)
+	}
+}
@@ -228,6 +228,24 @@ func (c *Client) ListIssues(ctx context.Context, owner, repo string, opts ListIs
 	return issues, nil
 }
 
+// CreateIssue opens a new issue
+func (c *Client) CreateIssue(ctx context.Context, owner, repo string, req CreateIssueRequest) (*Issue, error) {
+	path := fmt.Sprintf("/repos/%s/%s/issues", owner, repo)
+
+	resp, err := c.doRequest(ctx, http.MethodPost, path, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var issue Issue
+	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
+		return nil, fmt.Errorf("failed to decode created issue: %w", err)
+	}
+
+	return &issue, nil
+}
+
 // UpdateIssue updates an existing issue
 func (c *Client) UpdateIssue(ctx context.Context, owner, repo string, number int, update IssueUpdate) (*Issue, error) {
 	path := fmt.Sprintf("/repos/%s/%s/issues/%d", owner, repo, number)
@@ -479,6 +497,108 @@ func (c *Client) CreateBranch(ctx context.Context, owner, repo, branchName, from
 	return &newRef, nil
 }
 
+// GetReleaseByTag retrieves the release for a tag. A missing release is an
+// *APIError with StatusCode 404.
+func (c *Client) GetReleaseByTag(ctx context.Context, owner, repo, tag string) (*Release, error) {
+	path := fmt.Sprintf("/repos/%s/%s/releases/tags/%s", owner, repo, url.PathEscape(tag))
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+
+	return &release, nil
+}
+
+// CreateRelease creates a new release
+func (c *Client) CreateRelease(ctx context.Context, owner, repo string, req CreateReleaseRequest) (*Release, error) {
+	path := fmt.Sprintf("/repos/%s/%s/releases", owner, repo)
+
+	resp, err := c.doRequest(ctx, http.MethodPost, path, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+
+	return &release, nil
+}
+
+// DeleteReleaseAsset deletes a release asset
+func (c *Client) DeleteReleaseAsset(ctx context.Context, owner, repo string, assetID int64) error {
+	path := fmt.Sprintf("/repos/%s/%s/releases/assets/%d", owner, repo, assetID)
+
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// UploadReleaseAsset uploads data as the asset name of release. Uploads go
+// to the release's upload_url host rather than the API base URL, so they
+// are sent once, without doRequest's retries.
+func (c *Client) UploadReleaseAsset(ctx context.Context, release *Release, name, contentType string, data []byte) (*ReleaseAsset, error) {
+	uploadURL := release.UploadURL
+	if i := strings.Index(uploadURL, "{"); i >= 0 {
+		uploadURL = uploadURL[:i]
+	}
+	if uploadURL == "" {
+		return nil, fmt.Errorf("release %d has no upload URL", release.ID)
+	}
+	uploadURL += "?name=" + url.QueryEscape(name)
+
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.ContentLength = int64(len(data))
+
+	resp, err := c.httpClient.Do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	c.rateLimiter.Update(resp.Header)
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		apiErr := &APIError{}
+		if json.Unmarshal(bodyBytes, apiErr) != nil {
+			apiErr.Message = string(bodyBytes)
+		}
+		apiErr.StatusCode = resp.StatusCode
+		return nil, apiErr
+	}
+
+	var asset ReleaseAsset
+	if err := json.NewDecoder(resp.Body).Decode(&asset); err != nil {
+		return nil, fmt.Errorf("failed to decode release asset: %w", err)
+	}
+
+	return &asset, nil
+}
+
 // GetRateLimit retrieves the current rate limit status
 func (c *Client) GetRateLimit(ctx context.Context) (*RateLimitStatus, error) {
 	path := "/rate_limit"
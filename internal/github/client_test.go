@@ -168,6 +168,49 @@ func TestClient_CreatePullRequest(t *testing.T) {
 	assert.Equal(t, "Test PR", pr.Title)
 }
 
+func TestClient_Releases(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/repos/owner/repo/releases/tags/v1.0.0":
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "Not Found"})
+		case r.Method == "POST" && r.URL.Path == "/repos/owner/repo/releases":
+			var req CreateReleaseRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			assert.Equal(t, "v1.0.0", req.TagName)
+			_ = json.NewEncoder(w).Encode(Release{ID: 1, TagName: req.TagName, UploadURL: server.URL + "/upload/1/assets{?name,label}"})
+		case r.Method == "POST" && r.URL.Path == "/upload/1/assets":
+			assert.Equal(t, "app.tar.gz", r.URL.Query().Get("name"))
+			assert.Equal(t, "application/gzip", r.Header.Get("Content-Type"))
+			_ = json.NewEncoder(w).Encode(ReleaseAsset{ID: 2, Name: "app.tar.gz", BrowserDownloadURL: "https://example.com/app.tar.gz"})
+		case r.Method == "DELETE" && r.URL.Path == "/repos/owner/repo/releases/assets/2":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{BaseURL: server.URL})
+	ctx := context.Background()
+
+	_, err := client.GetReleaseByTag(ctx, "owner", "repo", "v1.0.0")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+
+	release, err := client.CreateRelease(ctx, "owner", "repo", CreateReleaseRequest{TagName: "v1.0.0"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), release.ID)
+
+	asset, err := client.UploadReleaseAsset(ctx, release, "app.tar.gz", "application/gzip", []byte("data"))
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/app.tar.gz", asset.BrowserDownloadURL)
+
+	require.NoError(t, client.DeleteReleaseAsset(ctx, "owner", "repo", asset.ID))
+}
+
 func TestClient_RateLimitHandling(t *testing.T) {
 	callCount := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -175,6 +175,13 @@ type IssueUpdate struct {
 	Milestone *int      `json:"milestone,omitempty"`
 }
 
+// CreateIssueRequest represents a request to create an issue
+type CreateIssueRequest struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+}
+
 // CreatePullRequestRequest represents a request to create a pull request
 type CreatePullRequestRequest struct {
 	Title               string `json:"title"`
@@ -206,6 +213,36 @@ type PullRequestCommitAuthor struct {
 	Date  time.Time `json:"date"`
 }
 
+// Release represents a GitHub release.
+type Release struct {
+	ID         int64           `json:"id"`
+	TagName    string          `json:"tag_name"`
+	Name       string          `json:"name"`
+	Draft      bool            `json:"draft"`
+	Prerelease bool            `json:"prerelease"`
+	HTMLURL    string          `json:"html_url"`
+	UploadURL  string          `json:"upload_url"` // RFC 6570 template, e.g. ".../assets{?name,label}"
+	Assets     []*ReleaseAsset `json:"assets"`
+}
+
+// ReleaseAsset represents a file attached to a GitHub release.
+type ReleaseAsset struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// CreateReleaseRequest represents a request to create a release.
+type CreateReleaseRequest struct {
+	TagName         string `json:"tag_name"`
+	TargetCommitish string `json:"target_commitish,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Body            string `json:"body,omitempty"`
+	Draft           bool   `json:"draft,omitempty"`
+	Prerelease      bool   `json:"prerelease,omitempty"`
+}
+
 // RateLimitStatus represents GitHub API rate limit information
 type RateLimitStatus struct {
 	Limit     int   `json:"limit"`
@@ -3,7 +3,8 @@ package hooks
 import (
 	"bytes"
 	"context"
-	"os/exec"
+
+	"github.com/recinq/wave/internal/procutil"
 )
 
 // executeCommand runs a shell command and interprets its exit code.
@@ -15,7 +16,7 @@ func executeCommand(ctx context.Context, hook *LifecycleHookDef, evt HookEvent)
 
 	// The shell itself handles variable expansion — no need for os.ExpandEnv
 	// which would incorrectly expand WAVE_HOOK_* vars in the parent process.
-	cmd := exec.CommandContext(ctx, "sh", "-c", hook.Command)
+	cmd := procutil.ShellCommand(ctx, hook.Command)
 
 	// Curated environment: only base system vars + WAVE_HOOK_* variables.
 	// The full host environment is NOT inherited to prevent sandbox bypass.
@@ -1,6 +1,9 @@
 package hooks
 
-import "github.com/recinq/wave/internal/config"
+import (
+	"github.com/recinq/wave/internal/config"
+	"github.com/recinq/wave/internal/procutil"
+)
 
 // buildHookEnv constructs a curated environment for hook subprocesses.
 // Only WAVE_HOOK_* variables and base system variables (HOME, PATH, TERM, TMPDIR)
@@ -8,7 +11,7 @@ import "github.com/recinq/wave/internal/config"
 // sandbox bypass via environment leakage.
 func buildHookEnv(evt HookEvent) []string {
 	env := config.FromEnv()
-	return []string{
+	vars := []string{
 		"HOME=" + env.Home,
 		"PATH=" + env.Path,
 		"TERM=" + env.TermOr("xterm-256color"),
@@ -18,4 +21,6 @@ func buildHookEnv(evt HookEvent) []string {
 		"WAVE_HOOK_STEP_ID=" + evt.StepID,
 		"WAVE_HOOK_WORKSPACE=" + evt.Workspace,
 	}
+	// Windows programs also need SYSTEMROOT and friends; none on Unix.
+	return append(vars, procutil.PlatformEnv()...)
 }
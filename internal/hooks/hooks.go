@@ -16,6 +16,7 @@ const (
 	EventContractValidated EventType = "contract_validated"
 	EventArtifactCreated   EventType = "artifact_created"
 	EventWorkspaceCreated  EventType = "workspace_created"
+	EventGateRequested     EventType = "gate_requested"
 )
 
 // ValidEventTypes is the set of all valid lifecycle event types.
@@ -30,6 +31,7 @@ var ValidEventTypes = map[EventType]bool{
 	EventContractValidated: true,
 	EventArtifactCreated:   true,
 	EventWorkspaceCreated:  true,
+	EventGateRequested:     true,
 }
 
 // HookType represents the execution type of a hook.
@@ -121,6 +123,22 @@ type HookEvent struct {
 	Workspace  string    `json:"workspace,omitempty"`
 	Artifacts  []string  `json:"artifacts,omitempty"`
 	Error      string    `json:"error,omitempty"`
+
+	// Gate carries the pending decision for gate_requested events.
+	Gate *GateRequest `json:"gate,omitempty"`
+}
+
+// GateRequest describes an approval gate waiting for a human decision.
+type GateRequest struct {
+	Message string       `json:"message,omitempty"`
+	Choices []GateChoice `json:"choices"`
+}
+
+// GateChoice is one option of a pending gate. Target "_fail" aborts the run.
+type GateChoice struct {
+	Key    string `json:"key"`
+	Label  string `json:"label"`
+	Target string `json:"target,omitempty"`
 }
 
 // HookDecision represents the decision from a hook execution.
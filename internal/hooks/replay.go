@@ -0,0 +1,123 @@
+package hooks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxFixtureLineBytes bounds a single fixture line; gate events with long
+// messages still fit comfortably.
+const maxFixtureLineBytes = 1 << 20
+
+// LoadEventFixture reads a JSONL stream of HookEvents, one per line, as
+// recorded from a pipeline run. Blank lines are skipped; unknown event
+// types are rejected with their line number so a typo in a hand-written
+// fixture is caught before anything is sent.
+func LoadEventFixture(r io.Reader) ([]HookEvent, error) {
+	var events []HookEvent
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFixtureLineBytes)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var evt HookEvent
+		if err := json.Unmarshal([]byte(text), &evt); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if !ValidEventTypes[evt.Type] {
+			return nil, fmt.Errorf("line %d: unknown event type %q", line, evt.Type)
+		}
+		events = append(events, evt)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// WebhookReplayResult is the outcome of replaying one event to one webhook.
+type WebhookReplayResult struct {
+	Webhook        string    `json:"webhook"`
+	Format         string    `json:"format,omitempty"`
+	Event          EventType `json:"event"`
+	StepID         string    `json:"step_id,omitempty"`
+	StatusCode     int       `json:"status_code,omitempty"`
+	ResponseTimeMs int64     `json:"response_time_ms,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	// Payload is the rendered request body; set only on dry runs.
+	Payload string `json:"payload,omitempty"`
+}
+
+// Replay delivers events, in order, to every webhook that FireWebhooks
+// would select, and returns one result per delivery. Unlike FireWebhooks it
+// delivers synchronously, includes inactive webhooks, ignores the rate
+// limit and records nothing in the delivery log, so a configuration can be
+// checked without a pipeline run. With dryRun, payloads are rendered but
+// not sent.
+func (r *WebhookRunner) Replay(ctx context.Context, events []HookEvent, dryRun bool) []WebhookReplayResult {
+	var results []WebhookReplayResult
+	for _, evt := range events {
+		for i := range r.webhooks {
+			wh := &r.webhooks[i]
+			if !r.matchesEvent(*wh, evt) {
+				continue
+			}
+			if evt.StepID != "" && r.matchers[i] != nil && !r.matchers[i].MatchString(evt.StepID) {
+				continue
+			}
+			if ctx.Err() != nil {
+				return results
+			}
+
+			res := WebhookReplayResult{Webhook: wh.Name, Format: wh.Format, Event: evt.Type, StepID: evt.StepID}
+			if dryRun {
+				payload, err := WebhookPayload(wh.Format, wh.Template, evt)
+				if err != nil {
+					res.Error = fmt.Sprintf("payload error: %s", err)
+				} else {
+					res.Payload = string(payload)
+				}
+			} else {
+				res.StatusCode, res.ResponseTimeMs, res.Error = r.send(ctx, wh, evt)
+			}
+			results = append(results, res)
+		}
+	}
+	return results
+}
+
+// Post delivers a pre-rendered text message, such as a `wave report`
+// digest, to every webhook, ignoring event and step filters. Like Replay it
+// delivers synchronously and records nothing in the delivery log. With
+// dryRun, payloads are rendered but not sent.
+func (r *WebhookRunner) Post(ctx context.Context, text string, dryRun bool) []WebhookReplayResult {
+	var results []WebhookReplayResult
+	for i := range r.webhooks {
+		wh := &r.webhooks[i]
+		if ctx.Err() != nil {
+			return results
+		}
+		res := WebhookReplayResult{Webhook: wh.Name, Format: wh.Format, Event: EventReport}
+		payload, err := MessagePayload(wh.Format, text)
+		switch {
+		case err != nil:
+			res.Error = fmt.Sprintf("payload error: %s", err)
+		case dryRun:
+			res.Payload = string(payload)
+		default:
+			if err := urlValidator(wh.URL); err != nil {
+				res.Error = fmt.Sprintf("SSRF blocked: %s", err)
+			} else {
+				res.StatusCode, res.ResponseTimeMs, res.Error = r.post(ctx, wh, payload)
+			}
+		}
+		results = append(results, res)
+	}
+	return results
+}
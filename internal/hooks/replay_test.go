@@ -0,0 +1,144 @@
+package hooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLoadEventFixture(t *testing.T) {
+	fixture := `{"type":"run_start","pipeline_id":"impl-issue-1","input":"fix login"}
+
+{"type":"step_failed","pipeline_id":"impl-issue-1","step_id":"implement","error":"tests failed"}
+{"type":"run_failed","pipeline_id":"impl-issue-1","error":"step implement failed"}
+`
+	events, err := LoadEventFixture(strings.NewReader(fixture))
+	if err != nil {
+		t.Fatalf("LoadEventFixture() error = %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	if events[1].Type != EventStepFailed || events[1].StepID != "implement" || events[1].Error != "tests failed" {
+		t.Errorf("unexpected event: %+v", events[1])
+	}
+
+	for name, bad := range map[string]string{
+		"unknown type": `{"type":"run_finished","pipeline_id":"p"}`,
+		"invalid json": `{"type":`,
+	} {
+		_, err := LoadEventFixture(strings.NewReader(`{"type":"run_start"}` + "\n" + bad))
+		if err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("%s: expected a line 2 error, got %v", name, err)
+		}
+	}
+}
+
+func TestWebhookRunner_Replay(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	origValidator := urlValidator
+	urlValidator = func(string) error { return nil }
+	defer func() { urlValidator = origValidator }()
+
+	webhooks := []WebhookRecord{
+		{ID: 1, Name: "slack", URL: srv.URL, Format: WebhookFormatSlack, Events: []string{"run_failed"}},
+		{ID: 2, Name: "audit", URL: srv.URL + "/broken", Matcher: "^impl"},
+	}
+	events := []HookEvent{
+		{Type: EventStepFailed, PipelineID: "run-1", StepID: "plan"},
+		{Type: EventStepFailed, PipelineID: "run-1", StepID: "implement"},
+		{Type: EventRunFailed, PipelineID: "run-1", Error: "boom"},
+	}
+	runner := NewWebhookRunner(webhooks, nil)
+
+	results := runner.Replay(context.Background(), events, false)
+	// plan is filtered out by audit's matcher and slack's event filter.
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Webhook+":"+string(r.Event))
+	}
+	// Order follows events, then webhooks.
+	want := "audit:step_failed slack:run_failed audit:run_failed"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("deliveries = %v, want %s", got, want)
+	}
+	for _, r := range results {
+		switch r.Webhook {
+		case "slack":
+			if r.StatusCode != http.StatusOK || r.Error != "" {
+				t.Errorf("slack delivery = %+v", r)
+			}
+		case "audit":
+			if r.StatusCode != http.StatusNotFound || r.Error != "HTTP 404" {
+				t.Errorf("audit delivery = %+v", r)
+			}
+		}
+	}
+	if len(bodies) != 3 {
+		t.Errorf("server received %d requests, want 3", len(bodies))
+	}
+
+	// A dry run renders payloads without sending anything.
+	bodies = nil
+	results = runner.Replay(context.Background(), events[2:], true)
+	if len(bodies) != 0 {
+		t.Errorf("dry run sent %d requests", len(bodies))
+	}
+	if len(results) != 2 || !strings.Contains(results[0].Payload+results[1].Payload, "boom") {
+		t.Errorf("dry run results = %+v", results)
+	}
+}
+
+func TestWebhookRunner_Post(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	origValidator := urlValidator
+	urlValidator = func(string) error { return nil }
+	defer func() { urlValidator = origValidator }()
+
+	// Event filters do not apply to posted messages.
+	webhooks := []WebhookRecord{
+		{ID: 1, Name: "slack", URL: srv.URL, Format: WebhookFormatSlack, Events: []string{"run_failed"}},
+		{ID: 2, Name: "audit", URL: srv.URL},
+	}
+	results := NewWebhookRunner(webhooks, nil).Post(context.Background(), "# Weekly digest", false)
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	for _, r := range results {
+		if r.Event != EventReport || r.StatusCode != http.StatusOK || r.Error != "" {
+			t.Errorf("delivery = %+v", r)
+		}
+	}
+	want := []string{`{"text":"# Weekly digest"}`, `{"type":"report","text":"# Weekly digest"}`}
+	if strings.Join(bodies, "\n") != strings.Join(want, "\n") {
+		t.Errorf("bodies = %v, want %v", bodies, want)
+	}
+}
@@ -0,0 +1,252 @@
+package hooks
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event severities routes can select on. Failures are errors, events
+// waiting on or retrying work are warnings, everything else is info.
+const (
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// EventSeverity returns the severity of an event type.
+func EventSeverity(t EventType) string {
+	switch t {
+	case EventRunFailed, EventStepFailed:
+		return SeverityError
+	case EventStepRetrying, EventGateRequested:
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
+
+// NotificationConfig routes lifecycle events to webhooks by name
+// (runtime.notifications). A webhook named as a sink by any route receives
+// only the events its routes select, whatever its own event subscription;
+// webhooks no route names keep their subscriptions.
+type NotificationConfig struct {
+	Routes []NotificationRoute `yaml:"routes,omitempty"`
+}
+
+// NotificationRoute sends the events matching all of its filters to its
+// sinks. An empty filter matches everything.
+type NotificationRoute struct {
+	Name string `yaml:"name"`
+	// Pipelines are glob patterns matched against the pipeline name.
+	Pipelines []string    `yaml:"pipelines,omitempty"`
+	Events    []EventType `yaml:"events,omitempty"`
+	Severity  []string    `yaml:"severity,omitempty"`
+	// Tags match when the run carries any of them.
+	Tags []string `yaml:"tags,omitempty"`
+	// Sinks are the names of the webhooks the events are delivered to.
+	Sinks      []string    `yaml:"sinks"`
+	QuietHours *QuietHours `yaml:"quiet_hours,omitempty"`
+	// DedupWindow drops repeats of the same event type for the same
+	// pipeline and step within the window, across runs (e.g. "1h").
+	DedupWindow string `yaml:"dedup_window,omitempty"`
+}
+
+// QuietHours mutes a route between Start and End ("HH:MM", wrapping past
+// midnight when End is earlier) in Timezone, the local zone when empty.
+type QuietHours struct {
+	Start    string `yaml:"start"`
+	End      string `yaml:"end"`
+	Timezone string `yaml:"timezone,omitempty"`
+}
+
+// ValidateNotificationRoute checks a route's filters, sinks, quiet hours and
+// dedup window.
+func ValidateNotificationRoute(r NotificationRoute) error {
+	if r.Name == "" {
+		return fmt.Errorf("route name is required")
+	}
+	if len(r.Sinks) == 0 {
+		return fmt.Errorf("route %q has no sinks", r.Name)
+	}
+	for _, p := range r.Pipelines {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("route %q: invalid pipeline pattern %q", r.Name, p)
+		}
+	}
+	for _, e := range r.Events {
+		if !ValidEventTypes[e] {
+			return fmt.Errorf("route %q: unknown event %q", r.Name, e)
+		}
+	}
+	for _, s := range r.Severity {
+		if s != SeverityInfo && s != SeverityWarning && s != SeverityError {
+			return fmt.Errorf("route %q: unknown severity %q (valid: info, warning, error)", r.Name, s)
+		}
+	}
+	if r.QuietHours != nil {
+		if _, _, _, err := r.QuietHours.parse(); err != nil {
+			return fmt.Errorf("route %q: quiet_hours: %w", r.Name, err)
+		}
+	}
+	if r.DedupWindow != "" {
+		if d, err := time.ParseDuration(r.DedupWindow); err != nil || d <= 0 {
+			return fmt.Errorf("route %q: invalid dedup_window %q", r.Name, r.DedupWindow)
+		}
+	}
+	return nil
+}
+
+// parse returns the start and end as minutes after midnight and the zone.
+func (q *QuietHours) parse() (int, int, *time.Location, error) {
+	start, err := clockMinutes(q.Start)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	end, err := clockMinutes(q.End)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	loc := time.Local
+	if q.Timezone != "" {
+		if loc, err = time.LoadLocation(q.Timezone); err != nil {
+			return 0, 0, nil, fmt.Errorf("unknown timezone %q", q.Timezone)
+		}
+	}
+	return start, end, loc, nil
+}
+
+// Active reports whether t falls within the quiet hours.
+func (q *QuietHours) Active(t time.Time) bool {
+	start, end, loc, err := q.parse()
+	if err != nil || start == end {
+		return false
+	}
+	t = t.In(loc)
+	now := t.Hour()*60 + t.Minute()
+	if start < end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+func clockMinutes(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// NotificationDedupStore persists dedup claims so repeats are suppressed
+// across runs. ClaimNotification reports whether key was not claimed within
+// window, claiming it if so.
+type NotificationDedupStore interface {
+	ClaimNotification(key string, window time.Duration) (bool, error)
+}
+
+// RouteSubject is the run whose events a Router routes.
+type RouteSubject struct {
+	Pipeline string
+	Tags     []string
+}
+
+// Router selects the webhooks an event is delivered to under the
+// configured notification routes.
+type Router struct {
+	routes  []NotificationRoute
+	subject RouteSubject
+	dedup   NotificationDedupStore
+	now     func() time.Time
+
+	// claims dedups in memory when there is no store.
+	mu     sync.Mutex
+	claims map[string]time.Time
+}
+
+// NewRouter creates a router for the run described by subject. A nil dedup
+// store dedups within this process only.
+func NewRouter(cfg NotificationConfig, subject RouteSubject, dedup NotificationDedupStore) *Router {
+	return &Router{
+		routes:  cfg.Routes,
+		subject: subject,
+		dedup:   dedup,
+		now:     time.Now,
+		claims:  make(map[string]time.Time),
+	}
+}
+
+// Routes reports whether any route names sink, which puts its deliveries
+// under the router's control.
+func (r *Router) Routes(sink string) bool {
+	for _, route := range r.routes {
+		if slices.Contains(route.Sinks, sink) {
+			return true
+		}
+	}
+	return false
+}
+
+// Deliver reports whether evt goes to sink: a route naming sink matches
+// evt, is outside its quiet hours and has not sent the same notification
+// within its dedup window.
+func (r *Router) Deliver(sink string, evt HookEvent) bool {
+	for _, route := range r.routes {
+		if !slices.Contains(route.Sinks, sink) || !r.matches(route, evt) {
+			continue
+		}
+		if route.QuietHours != nil && route.QuietHours.Active(r.now()) {
+			continue
+		}
+		if r.claim(route, sink, evt) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Router) matches(route NotificationRoute, evt HookEvent) bool {
+	if len(route.Events) > 0 && !slices.Contains(route.Events, evt.Type) {
+		return false
+	}
+	if len(route.Severity) > 0 && !slices.Contains(route.Severity, EventSeverity(evt.Type)) {
+		return false
+	}
+	if len(route.Pipelines) > 0 && !slices.ContainsFunc(route.Pipelines, func(p string) bool {
+		ok, _ := path.Match(p, r.subject.Pipeline)
+		return ok
+	}) {
+		return false
+	}
+	if len(route.Tags) > 0 && !slices.ContainsFunc(route.Tags, func(t string) bool {
+		return slices.Contains(r.subject.Tags, t)
+	}) {
+		return false
+	}
+	return true
+}
+
+// claim applies the route's dedup window. Store errors deliver rather than
+// drop the notification.
+func (r *Router) claim(route NotificationRoute, sink string, evt HookEvent) bool {
+	window, err := time.ParseDuration(route.DedupWindow)
+	if route.DedupWindow == "" || err != nil || window <= 0 {
+		return true
+	}
+	key := strings.Join([]string{route.Name, sink, string(evt.Type), r.subject.Pipeline, evt.StepID}, "\x00")
+	if r.dedup != nil {
+		ok, err := r.dedup.ClaimNotification(key, window)
+		return ok || err != nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	if last, ok := r.claims[key]; ok && now.Sub(last) < window {
+		return false
+	}
+	r.claims[key] = now
+	return true
+}
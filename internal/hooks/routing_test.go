@@ -0,0 +1,103 @@
+package hooks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRouter_Deliver(t *testing.T) {
+	cfg := NotificationConfig{Routes: []NotificationRoute{
+		{Name: "page", Pipelines: []string{"impl-*"}, Severity: []string{SeverityError}, Tags: []string{"payments"}, Sinks: []string{"oncall"}},
+		{Name: "feed", Events: []EventType{EventRunCompleted}, Sinks: []string{"feed"}},
+	}}
+	router := NewRouter(cfg, RouteSubject{Pipeline: "impl-issue", Tags: []string{"payments", "nightly"}}, nil)
+
+	tests := []struct {
+		sink string
+		evt  EventType
+		want bool
+	}{
+		{"oncall", EventStepFailed, true},
+		{"oncall", EventRunFailed, true},
+		{"oncall", EventRunCompleted, false},
+		{"feed", EventRunCompleted, true},
+		{"feed", EventRunFailed, false},
+	}
+	for _, tt := range tests {
+		if got := router.Deliver(tt.sink, HookEvent{Type: tt.evt, PipelineID: "run-1"}); got != tt.want {
+			t.Errorf("Deliver(%s, %s) = %v, want %v", tt.sink, tt.evt, got, tt.want)
+		}
+	}
+
+	if !router.Routes("oncall") || router.Routes("audit") {
+		t.Error("only sinks named by a route are routed")
+	}
+
+	other := NewRouter(cfg, RouteSubject{Pipeline: "impl-issue", Tags: []string{"search"}}, nil)
+	if other.Deliver("oncall", HookEvent{Type: EventRunFailed}) {
+		t.Error("a run without a route tag should not page")
+	}
+	other = NewRouter(cfg, RouteSubject{Pipeline: "audit-deps", Tags: []string{"payments"}}, nil)
+	if other.Deliver("oncall", HookEvent{Type: EventRunFailed}) {
+		t.Error("a pipeline outside the route pattern should not page")
+	}
+}
+
+func TestRouter_QuietHoursAndDedup(t *testing.T) {
+	cfg := NotificationConfig{Routes: []NotificationRoute{{
+		Name:        "feed",
+		Sinks:       []string{"feed"},
+		QuietHours:  &QuietHours{Start: "22:00", End: "07:00", Timezone: "UTC"},
+		DedupWindow: "1h",
+	}}}
+	router := NewRouter(cfg, RouteSubject{Pipeline: "nightly"}, nil)
+	now := time.Date(2026, 10, 16, 23, 30, 0, 0, time.UTC)
+	router.now = func() time.Time { return now }
+
+	evt := HookEvent{Type: EventStepFailed, StepID: "build"}
+	if router.Deliver("feed", evt) {
+		t.Error("quiet hours should mute the route")
+	}
+
+	now = time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	if !router.Deliver("feed", evt) {
+		t.Fatal("the first notification after quiet hours should be delivered")
+	}
+	if router.Deliver("feed", evt) {
+		t.Error("a repeat within the dedup window should be dropped")
+	}
+	if !router.Deliver("feed", HookEvent{Type: EventStepFailed, StepID: "test"}) {
+		t.Error("another step is not a repeat")
+	}
+
+	now = now.Add(61 * time.Minute)
+	if !router.Deliver("feed", evt) {
+		t.Error("the repeat should be delivered once the window has passed")
+	}
+}
+
+func TestValidateNotificationRoute(t *testing.T) {
+	valid := NotificationRoute{Name: "r", Sinks: []string{"s"}}
+	if err := ValidateNotificationRoute(valid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := map[string]func(r *NotificationRoute){
+		"no sinks":       func(r *NotificationRoute) { r.Sinks = nil },
+		"bad event":      func(r *NotificationRoute) { r.Events = []EventType{"run_exploded"} },
+		"bad severity":   func(r *NotificationRoute) { r.Severity = []string{"critical"} },
+		"bad pattern":    func(r *NotificationRoute) { r.Pipelines = []string{"impl-["} },
+		"bad quiet time": func(r *NotificationRoute) { r.QuietHours = &QuietHours{Start: "25:00", End: "07:00"} },
+		"bad timezone": func(r *NotificationRoute) {
+			r.QuietHours = &QuietHours{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"}
+		},
+		"bad dedup": func(r *NotificationRoute) { r.DedupWindow = "soon" },
+	}
+	for name, mutate := range tests {
+		r := valid
+		mutate(&r)
+		if err := ValidateNotificationRoute(r); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
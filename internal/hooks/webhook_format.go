@@ -0,0 +1,215 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Webhook payload formats. WebhookFormatJSON posts the raw HookEvent; the
+// chat formats render the webhook's message template and wrap the text in
+// the body each service's incoming-webhook API expects.
+const (
+	WebhookFormatJSON    = "json"
+	WebhookFormatSlack   = "slack"
+	WebhookFormatDiscord = "discord"
+	WebhookFormatTeams   = "teams"
+)
+
+// DefaultWebhookTemplate is the message used by chat formats when a
+// webhook has no template of its own.
+const DefaultWebhookTemplate = `{{.Title}}: {{.PipelineID}}{{if .StepID}} / {{.StepID}}{{end}}{{if .Error}} — {{.Error}}{{end}}`
+
+// discordContentLimit is the maximum length of a Discord message.
+const discordContentLimit = 2000
+
+// SlackGateActionID is the action_id of the decision buttons attached to
+// Slack gate_requested messages. Clicks are delivered by Slack to the
+// dashboard's /api/slack/interactions endpoint.
+const SlackGateActionID = "wave_gate"
+
+// SlackGateAction is the value carried by a Slack gate button.
+type SlackGateAction struct {
+	RunID  string `json:"run_id"`
+	StepID string `json:"step_id"`
+	Choice string `json:"choice"`
+}
+
+// WebhookMessage is the data a webhook message template is executed with:
+// every HookEvent field plus a human-readable title for the event type.
+type WebhookMessage struct {
+	HookEvent
+	Title string
+}
+
+// webhookFormatter wraps a rendered message in a service-specific body.
+type webhookFormatter func(text string, evt HookEvent) any
+
+// webhookFormatters maps each chat format to its body builder. Adding a
+// sink is one entry here; event selection and templating are shared.
+var webhookFormatters = map[string]webhookFormatter{
+	WebhookFormatSlack: slackMessage,
+	WebhookFormatDiscord: func(text string, _ HookEvent) any {
+		if len([]rune(text)) > discordContentLimit {
+			text = string([]rune(text)[:discordContentLimit-1]) + "…"
+		}
+		return map[string]string{"username": "Wave", "content": text}
+	},
+	WebhookFormatTeams: teamsCard,
+}
+
+// EventReport is the event type of text messages posted with
+// WebhookRunner.Post, such as `wave report` digests. It is not a lifecycle
+// event, so webhooks cannot subscribe to it.
+const EventReport EventType = "report"
+
+// ReportPayload is the JSON-format body of a message posted with
+// WebhookRunner.Post.
+type ReportPayload struct {
+	Type EventType `json:"type"`
+	Text string    `json:"text"`
+}
+
+// MessagePayload builds the request body for posting a pre-rendered text
+// message in the given format. Chat formats wrap the text as they wrap a
+// rendered template; the JSON format posts a ReportPayload.
+func MessagePayload(format, text string) ([]byte, error) {
+	if format == "" || format == WebhookFormatJSON {
+		return json.Marshal(ReportPayload{Type: EventReport, Text: text})
+	}
+	formatter, ok := webhookFormatters[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown webhook format %q", format)
+	}
+	return json.Marshal(formatter(text, HookEvent{Type: EventReport}))
+}
+
+// ValidateWebhookFormat checks that format is known and tmpl parses.
+func ValidateWebhookFormat(format, tmpl string) error {
+	if format != "" && format != WebhookFormatJSON {
+		if _, ok := webhookFormatters[format]; !ok {
+			return fmt.Errorf("unknown webhook format %q (valid: json, slack, discord, teams)", format)
+		}
+	}
+	if tmpl != "" {
+		if _, err := template.New("webhook").Parse(tmpl); err != nil {
+			return fmt.Errorf("invalid webhook template: %w", err)
+		}
+	}
+	return nil
+}
+
+// WebhookPayload builds the request body for a delivery of evt in the
+// given format. An empty format is the raw JSON event.
+func WebhookPayload(format, tmpl string, evt HookEvent) ([]byte, error) {
+	if format == "" || format == WebhookFormatJSON {
+		return json.Marshal(evt)
+	}
+	formatter, ok := webhookFormatters[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown webhook format %q", format)
+	}
+	text, err := RenderWebhookMessage(tmpl, evt)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(formatter(text, evt))
+}
+
+// RenderWebhookMessage executes tmpl (or DefaultWebhookTemplate) for evt.
+func RenderWebhookMessage(tmpl string, evt HookEvent) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultWebhookTemplate
+	}
+	t, err := template.New("webhook").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, WebhookMessage{HookEvent: evt, Title: eventTitle(evt.Type)}); err != nil {
+		return "", fmt.Errorf("failed to render webhook template: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// eventTitle turns "run_completed" into "Run completed".
+func eventTitle(t EventType) string {
+	s := strings.ReplaceAll(string(t), "_", " ")
+	if s == "" {
+		return "Event"
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// teamsCard wraps the message in a Microsoft Teams adaptive card with the
+// run, step and event as facts. Failure events are highlighted; messages
+// without a run, such as report digests, carry no facts.
+func teamsCard(text string, evt HookEvent) any {
+	heading := map[string]any{"type": "TextBlock", "text": text, "wrap": true, "weight": "Bolder"}
+	if strings.HasSuffix(string(evt.Type), "_failed") {
+		heading["color"] = "Attention"
+	}
+	body := []map[string]any{heading}
+	if evt.PipelineID != "" {
+		facts := []map[string]string{{"title": "Run", "value": evt.PipelineID}}
+		if evt.StepID != "" {
+			facts = append(facts, map[string]string{"title": "Step", "value": evt.StepID})
+		}
+		facts = append(facts, map[string]string{"title": "Event", "value": string(evt.Type)})
+		body = append(body, map[string]any{"type": "FactSet", "facts": facts})
+	}
+
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]any{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body":    body,
+			},
+		}},
+	}
+}
+
+// slackMessage posts the text as is, except for gate_requested events,
+// which get Block Kit buttons for each choice so the gate can be resolved
+// from Slack.
+func slackMessage(text string, evt HookEvent) any {
+	if evt.Type != EventGateRequested || evt.Gate == nil || len(evt.Gate.Choices) == 0 {
+		return map[string]string{"text": text}
+	}
+	section := text
+	if evt.Gate.Message != "" {
+		section += "\n" + evt.Gate.Message
+	}
+	buttons := make([]map[string]any, 0, len(evt.Gate.Choices))
+	primary := false
+	for _, c := range evt.Gate.Choices {
+		value, _ := json.Marshal(SlackGateAction{RunID: evt.PipelineID, StepID: evt.StepID, Choice: c.Key})
+		button := map[string]any{
+			"type":      "button",
+			"action_id": SlackGateActionID + ":" + c.Key,
+			"text":      map[string]string{"type": "plain_text", "text": c.Label},
+			"value":     string(value),
+		}
+		switch {
+		case c.Target == "_fail":
+			button["style"] = "danger"
+		case !primary:
+			button["style"] = "primary"
+			primary = true
+		}
+		buttons = append(buttons, button)
+	}
+	return map[string]any{
+		"text": text,
+		"blocks": []map[string]any{
+			{"type": "section", "text": map[string]string{"type": "mrkdwn", "text": section}},
+			{"type": "actions", "block_id": SlackGateActionID, "elements": buttons},
+		},
+	}
+}
@@ -0,0 +1,182 @@
+package hooks
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWebhookPayload_JSON(t *testing.T) {
+	evt := HookEvent{Type: EventRunCompleted, PipelineID: "run-1"}
+	for _, format := range []string{"", WebhookFormatJSON} {
+		body, err := WebhookPayload(format, "ignored", evt)
+		if err != nil {
+			t.Fatalf("format %q: %v", format, err)
+		}
+		var got HookEvent
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("format %q: %v", format, err)
+		}
+		if got.Type != evt.Type || got.PipelineID != evt.PipelineID {
+			t.Errorf("format %q: got %+v, want raw event", format, got)
+		}
+	}
+}
+
+func TestWebhookPayload_ChatFormats(t *testing.T) {
+	evt := HookEvent{Type: EventStepFailed, PipelineID: "impl-issue-1", StepID: "implement", Error: "contract failed"}
+	want := "Step failed: impl-issue-1 / implement — contract failed"
+
+	body, err := WebhookPayload(WebhookFormatSlack, "", evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var slack map[string]string
+	if err := json.Unmarshal(body, &slack); err != nil {
+		t.Fatal(err)
+	}
+	if slack["text"] != want {
+		t.Errorf("slack text = %q, want %q", slack["text"], want)
+	}
+
+	body, err = WebhookPayload(WebhookFormatDiscord, "", evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var discord map[string]string
+	if err := json.Unmarshal(body, &discord); err != nil {
+		t.Fatal(err)
+	}
+	if discord["content"] != want || discord["username"] != "Wave" {
+		t.Errorf("discord body = %v", discord)
+	}
+
+	body, err = WebhookPayload(WebhookFormatTeams, "", evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var teams struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string `json:"type"`
+				Body []struct {
+					Type  string `json:"type"`
+					Text  string `json:"text"`
+					Color string `json:"color"`
+					Facts []struct {
+						Title string `json:"title"`
+						Value string `json:"value"`
+					} `json:"facts"`
+				} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(body, &teams); err != nil {
+		t.Fatal(err)
+	}
+	if teams.Type != "message" || len(teams.Attachments) != 1 {
+		t.Fatalf("teams body = %s", body)
+	}
+	card := teams.Attachments[0]
+	if card.ContentType != "application/vnd.microsoft.card.adaptive" || card.Content.Type != "AdaptiveCard" {
+		t.Errorf("unexpected card envelope: %s", body)
+	}
+	if len(card.Content.Body) != 2 || card.Content.Body[0].Text != want || card.Content.Body[0].Color != "Attention" {
+		t.Errorf("unexpected card heading: %s", body)
+	}
+	if facts := card.Content.Body[1].Facts; len(facts) != 3 || facts[1].Value != "implement" {
+		t.Errorf("unexpected card facts: %s", body)
+	}
+}
+
+func TestWebhookPayload_CustomTemplate(t *testing.T) {
+	evt := HookEvent{Type: EventRunCompleted, PipelineID: "run-7", Artifacts: []string{"pr.json"}}
+	body, err := WebhookPayload(WebhookFormatSlack, ":white_check_mark: {{.PipelineID}} ({{len .Artifacts}} artifacts)", evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), ":white_check_mark: run-7 (1 artifacts)") {
+		t.Errorf("body = %s", body)
+	}
+}
+
+func TestWebhookPayload_DiscordTruncates(t *testing.T) {
+	evt := HookEvent{Type: EventRunFailed, PipelineID: "run-1", Error: strings.Repeat("x", 3000)}
+	body, err := WebhookPayload(WebhookFormatDiscord, "", evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var discord map[string]string
+	if err := json.Unmarshal(body, &discord); err != nil {
+		t.Fatal(err)
+	}
+	if n := len([]rune(discord["content"])); n != discordContentLimit {
+		t.Errorf("content length = %d, want %d", n, discordContentLimit)
+	}
+}
+
+func TestValidateWebhookFormat(t *testing.T) {
+	for _, format := range []string{"", "json", "slack", "discord", "teams"} {
+		if err := ValidateWebhookFormat(format, ""); err != nil {
+			t.Errorf("format %q: unexpected error %v", format, err)
+		}
+	}
+	if err := ValidateWebhookFormat("irc", ""); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	if err := ValidateWebhookFormat("slack", "{{.PipelineID"); err == nil {
+		t.Error("expected error for unparsable template")
+	}
+	if _, err := WebhookPayload("slack", "{{.Missing}}", HookEvent{}); err == nil {
+		t.Error("expected error for template referencing an unknown field")
+	}
+}
+
+func TestWebhookPayload_SlackGateButtons(t *testing.T) {
+	evt := HookEvent{
+		Type:       EventGateRequested,
+		PipelineID: "run-1",
+		StepID:     "review",
+		Gate: &GateRequest{
+			Message: "Ship it?",
+			Choices: []GateChoice{
+				{Key: "approve", Label: "Approve", Target: "publish"},
+				{Key: "reject", Label: "Reject", Target: "_fail"},
+			},
+		},
+	}
+	body, err := WebhookPayload(WebhookFormatSlack, "", evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Type     string `json:"type"`
+			Elements []struct {
+				ActionID string `json:"action_id"`
+				Value    string `json:"value"`
+				Style    string `json:"style"`
+			} `json:"elements"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Text != "Gate requested: run-1 / review" || len(msg.Blocks) != 2 {
+		t.Fatalf("unexpected message: %s", body)
+	}
+	buttons := msg.Blocks[1].Elements
+	if len(buttons) != 2 || buttons[0].Style != "primary" || buttons[1].Style != "danger" {
+		t.Fatalf("unexpected buttons: %s", body)
+	}
+	var action SlackGateAction
+	if err := json.Unmarshal([]byte(buttons[1].Value), &action); err != nil {
+		t.Fatal(err)
+	}
+	if action != (SlackGateAction{RunID: "run-1", StepID: "review", Choice: "reject"}) {
+		t.Errorf("unexpected action value: %+v", action)
+	}
+}
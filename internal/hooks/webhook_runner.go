@@ -6,7 +6,6 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -27,7 +26,11 @@ type WebhookRecord struct {
 	Matcher string
 	Headers map[string]string
 	Secret  string
-	Active  bool
+	// Format selects the payload: "json" (default) posts the raw event,
+	// "slack", "discord" and "teams" post Template rendered for that service.
+	Format   string
+	Template string
+	Active   bool
 }
 
 // WebhookDeliveryRecord mirrors state.WebhookDelivery for recording delivery results.
@@ -53,6 +56,7 @@ type WebhookRunner struct {
 	client      *http.Client
 	store       WebhookStore
 	rateLimiter *webhookRateLimiter
+	router      *Router
 }
 
 // webhookRateLimiter tracks delivery counts per webhook per minute window.
@@ -105,18 +109,32 @@ func NewWebhookRunner(webhooks []WebhookRecord, store WebhookStore) *WebhookRunn
 	}
 }
 
-// FireWebhooks sends the event to all matching webhooks.
+// SetRouter puts the webhooks named by its notification routes under
+// router's control.
+func (r *WebhookRunner) SetRouter(router *Router) {
+	r.router = router
+}
+
+// FireWebhooks sends the event to all matching webhooks. Webhooks named by a
+// notification route get the events the router selects instead of their
+// own subscription.
 // Non-blocking — failures are recorded but don't stop execution.
 func (r *WebhookRunner) FireWebhooks(ctx context.Context, evt HookEvent) {
 	for i, wh := range r.webhooks {
 		if !wh.Active {
 			continue
 		}
-		if !r.matchesEvent(wh, evt) {
-			continue
-		}
-		if evt.StepID != "" && r.matchers[i] != nil && !r.matchers[i].MatchString(evt.StepID) {
-			continue
+		if r.router != nil && r.router.Routes(wh.Name) {
+			if !r.router.Deliver(wh.Name, evt) {
+				continue
+			}
+		} else {
+			if !r.matchesEvent(wh, evt) {
+				continue
+			}
+			if evt.StepID != "" && r.matchers[i] != nil && !r.matchers[i].MatchString(evt.StepID) {
+				continue
+			}
 		}
 
 		if !r.rateLimiter.allow(wh.ID) {
@@ -141,22 +159,31 @@ func (r *WebhookRunner) matchesEvent(wh WebhookRecord, evt HookEvent) bool {
 }
 
 func (r *WebhookRunner) deliver(ctx context.Context, wh *WebhookRecord, evt HookEvent) {
+	statusCode, elapsed, errMsg := r.send(ctx, wh, evt)
+	r.recordDelivery(wh, evt, statusCode, elapsed, errMsg)
+}
+
+// send posts evt to wh and returns the response status, the round-trip time
+// in milliseconds, and an error message (empty on success).
+func (r *WebhookRunner) send(ctx context.Context, wh *WebhookRecord, evt HookEvent) (int, int64, string) {
 	// SSRF protection: validate webhook URL before delivery
 	if err := urlValidator(wh.URL); err != nil {
-		r.recordDelivery(wh, evt, 0, 0, fmt.Sprintf("SSRF blocked: %s", err))
-		return
+		return 0, 0, fmt.Sprintf("SSRF blocked: %s", err)
 	}
 
-	payload, err := json.Marshal(evt)
+	payload, err := WebhookPayload(wh.Format, wh.Template, evt)
 	if err != nil {
-		r.recordDelivery(wh, evt, 0, 0, fmt.Sprintf("marshal error: %s", err))
-		return
+		return 0, 0, fmt.Sprintf("payload error: %s", err)
 	}
+	return r.post(ctx, wh, payload)
+}
 
+// post sends an already rendered payload to wh with its custom headers and
+// HMAC signature. The URL must have passed urlValidator.
+func (r *WebhookRunner) post(ctx context.Context, wh *WebhookRecord, payload []byte) (int, int64, string) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(payload))
 	if err != nil {
-		r.recordDelivery(wh, evt, 0, 0, fmt.Sprintf("request error: %s", err))
-		return
+		return 0, 0, fmt.Sprintf("request error: %s", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -180,8 +207,7 @@ func (r *WebhookRunner) deliver(ctx context.Context, wh *WebhookRecord, evt Hook
 	elapsed := time.Since(start).Milliseconds()
 
 	if err != nil {
-		r.recordDelivery(wh, evt, 0, elapsed, err.Error())
-		return
+		return 0, elapsed, err.Error()
 	}
 	defer resp.Body.Close()
 	_, _ = io.Copy(io.Discard, resp.Body)
@@ -190,7 +216,7 @@ func (r *WebhookRunner) deliver(ctx context.Context, wh *WebhookRecord, evt Hook
 	if resp.StatusCode >= 400 {
 		errMsg = fmt.Sprintf("HTTP %d", resp.StatusCode)
 	}
-	r.recordDelivery(wh, evt, resp.StatusCode, elapsed, errMsg)
+	return resp.StatusCode, elapsed, errMsg
 }
 
 func (r *WebhookRunner) recordDelivery(wh *WebhookRecord, evt HookEvent, statusCode int, elapsed int64, errMsg string) {
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/recinq/wave/internal/pipeline"
+	"github.com/recinq/wave/internal/state"
 )
 
 // DefaultPipelineDir is where Wave stores pipeline YAML files for a project.
@@ -60,12 +61,37 @@ func ListPipelines() ([]PipelineInfo, error) {
 			Description: p.Metadata.Description,
 			StepCount:   len(p.Steps),
 			Steps:       stepIDs,
+			Deprecated:  p.Metadata.Deprecated,
 		})
 	}
 
+	if costs := pipelineCosts(); costs != nil {
+		for i := range pipelines {
+			pipelines[i].CostUSD = costs[pipelines[i].Name]
+		}
+	}
+
 	return pipelines, nil
 }
 
+// pipelineCosts returns the recorded spend per pipeline from the state
+// database, or nil when there is no database or it predates cost tracking.
+func pipelineCosts() map[string]float64 {
+	if _, err := os.Stat(DefaultStateDBPath); err != nil {
+		return nil
+	}
+	store, err := state.NewReadOnlyStateStore(DefaultStateDBPath)
+	if err != nil {
+		return nil
+	}
+	defer store.Close()
+	costs, err := store.GetPipelineCosts()
+	if err != nil {
+		return nil
+	}
+	return costs
+}
+
 // ExtractPipelineName strips the run ID suffix from a workspace directory name
 // by walking back through dash-separated segments and matching against pipeline
 // files on disk. e.g. "adr-0718471d" -> "adr".
@@ -75,9 +75,21 @@ func listRunsFromDB(dbPath string, opts RunsOptions) ([]RunInfo, error) {
 			StartedAt:  r.StartedAt.Format("2006-01-02 15:04:05"),
 			Duration:   duration,
 			DurationMs: durationMs,
+			Pinned:     r.Pinned,
 		})
 	}
 
+	// Costs are optional: a database from before cost tracking has none.
+	ids := make([]string, len(runs))
+	for i := range runs {
+		ids[i] = runs[i].RunID
+	}
+	if costs, err := store.GetRunCosts(ids); err == nil {
+		for i := range runs {
+			runs[i].CostUSD = costs[runs[i].RunID]
+		}
+	}
+
 	return runs, nil
 }
 
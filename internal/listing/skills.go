@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/recinq/wave/internal/procutil"
 )
 
 // CollectSkillsFromPipelines scans all pipeline YAML files and returns a merged
@@ -122,7 +124,8 @@ func ListSkills(skills map[string]PipelineSkillConfig) []SkillInfo {
 
 		installed := false
 		if skill.Check != "" {
-			cmd := exec.Command("sh", "-c", skill.Check)
+			sh := procutil.ShellArgs(skill.Check)
+			cmd := exec.Command(sh[0], sh[1:]...)
 			if err := cmd.Run(); err == nil {
 				installed = true
 			}
@@ -6,12 +6,18 @@
 // only handles cobra wiring, flag parsing and table rendering.
 package listing
 
+import "github.com/recinq/wave/internal/pipeline"
+
 // PipelineInfo describes a pipeline declared on disk.
 type PipelineInfo struct {
 	Name        string   `json:"name"`
 	Description string   `json:"description"`
 	StepCount   int      `json:"step_count"`
 	Steps       []string `json:"steps"`
+	// Deprecated is set for pipelines with metadata.deprecated.
+	Deprecated *pipeline.Deprecation `json:"deprecated,omitempty"`
+	// CostUSD is the estimated cost of all the pipeline's recorded runs.
+	CostUSD float64 `json:"cost_usd,omitempty"`
 }
 
 // PersonaInfo describes a persona declared in the manifest.
@@ -42,6 +48,10 @@ type RunInfo struct {
 	StartedAt  string `json:"started_at"`
 	Duration   string `json:"duration"`
 	DurationMs int64  `json:"duration_ms,omitempty"`
+	// CostUSD is the run's estimated cost, summed over its steps.
+	CostUSD float64 `json:"cost_usd,omitempty"`
+	// Pinned runs are kept by cleanup (wave pin).
+	Pinned bool `json:"pinned,omitempty"`
 }
 
 // ContractInfo holds information about a contract schema.
@@ -0,0 +1,215 @@
+package manifest
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Adapter option types.
+const (
+	OptionTypeString = "string"
+	OptionTypeInt    = "int"
+	OptionTypeBool   = "bool"
+)
+
+// AdapterOption declares a CLI flag an adapter accepts through
+// adapter_options on a persona or step.
+type AdapterOption struct {
+	// Type is string (default), int or bool. A bool option set to true is
+	// passed as a bare --flag and omitted when false.
+	Type string `yaml:"type,omitempty"`
+	// Values restricts a string option to a fixed set.
+	Values      []string `yaml:"values,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+}
+
+// adapterOptionName matches option names, which are flag names without the
+// leading dashes.
+var adapterOptionName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// builtinAdapterOptions are the flags the built-in adapters accept without a
+// declaration in wave.yaml. adapters.<name>.options adds to these.
+var builtinAdapterOptions = map[string]map[string]AdapterOption{
+	"claude": {
+		"max-turns":         {Type: OptionTypeInt, Description: "Maximum agentic turns"},
+		"permission-mode":   {Values: []string{"acceptEdits", "bypassPermissions", "default", "plan"}, Description: "Permission mode for the session"},
+		"fallback-model":    {Description: "Model to use when the primary model is overloaded"},
+		"add-dir":           {Description: "Additional directory the agent may access"},
+		"mcp-config":        {Description: "MCP server configuration file or JSON"},
+		"strict-mcp-config": {Type: OptionTypeBool, Description: "Only use MCP servers from --mcp-config"},
+	},
+	"codex": {
+		"sandbox": {Values: []string{"read-only", "workspace-write", "danger-full-access"}, Description: "Sandbox policy for shell commands"},
+		"profile": {Description: "Configuration profile from config.toml"},
+	},
+	"gemini": {
+		"include-directories": {Description: "Comma-separated extra directories in the workspace"},
+		"sandbox":             {Type: OptionTypeBool, Description: "Run tools in the Gemini sandbox"},
+		"checkpointing":       {Type: OptionTypeBool, Description: "Checkpoint file edits"},
+	},
+	"opencode": {
+		"agent": {Description: "OpenCode agent to run"},
+	},
+}
+
+// reservedAdapterOptions are the flags Wave sets itself for each built-in
+// adapter. They cannot be declared or passed as adapter options.
+var reservedAdapterOptions = map[string][]string{
+	"claude":   {"agent", "dangerously-skip-permissions", "model", "no-session-persistence", "output-format", "print", "p", "verbose"},
+	"codex":    {"full-auto", "json", "model", "skip-git-repo-check"},
+	"gemini":   {"approval-mode", "model", "output-format", "prompt", "p", "yolo"},
+	"opencode": {"format", "model"},
+}
+
+// builtinAdapterKey maps an adapter name to the built-in adapter whose
+// options apply. OpenCode forks (opencode-*) share opencode's, and an
+// adapter of another name whose binary is codex runs on, and shares the
+// options of, the Codex adapter.
+func builtinAdapterKey(name, binary string) string {
+	lc := strings.ToLower(name)
+	if strings.HasPrefix(lc, "opencode-") {
+		return "opencode"
+	}
+	if _, ok := builtinAdapterOptions[lc]; !ok && strings.TrimSuffix(filepath.Base(binary), ".exe") == "codex" {
+		return "codex"
+	}
+	return lc
+}
+
+// AdapterOptions returns the options the named adapter accepts: the
+// built-in ones, overridden and extended by adapters.<name>.options.
+func (m *Manifest) AdapterOptions(adapterName string) map[string]AdapterOption {
+	specs := maps.Clone(builtinAdapterOptions[builtinAdapterKey(adapterName, m.Adapters[adapterName].Binary)])
+	if specs == nil {
+		specs = make(map[string]AdapterOption)
+	}
+	maps.Copy(specs, m.Adapters[adapterName].Options)
+	return specs
+}
+
+// ValidateAdapterOptions checks opts against the options the named adapter
+// accepts.
+func (m *Manifest) ValidateAdapterOptions(adapterName string, opts map[string]string) error {
+	specs := m.AdapterOptions(adapterName)
+	for _, name := range slices.Sorted(maps.Keys(opts)) {
+		spec, ok := specs[name]
+		if !ok {
+			known := slices.Sorted(maps.Keys(specs))
+			if len(known) == 0 {
+				return fmt.Errorf("adapter %q declares no options; declare %q under adapters.%s.options", adapterName, name, adapterName)
+			}
+			return fmt.Errorf("unknown option %q for adapter %q (known: %s)", name, adapterName, strings.Join(known, ", "))
+		}
+		if err := spec.check(opts[name]); err != nil {
+			return fmt.Errorf("option %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// AdapterOptionArgs renders opts as CLI arguments, sorted by option name.
+// opts must have passed ValidateAdapterOptions.
+func (m *Manifest) AdapterOptionArgs(adapterName string, opts map[string]string) []string {
+	specs := m.AdapterOptions(adapterName)
+	var args []string
+	for _, name := range slices.Sorted(maps.Keys(opts)) {
+		value := opts[name]
+		if specs[name].Type == OptionTypeBool {
+			if b, _ := strconv.ParseBool(value); b {
+				args = append(args, "--"+name)
+			}
+			continue
+		}
+		args = append(args, "--"+name, value)
+	}
+	return args
+}
+
+func (o AdapterOption) check(value string) error {
+	switch o.Type {
+	case "", OptionTypeString:
+		if len(o.Values) > 0 && !slices.Contains(o.Values, value) {
+			return fmt.Errorf("%q is not one of %s", value, strings.Join(o.Values, ", "))
+		}
+	case OptionTypeInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+	case OptionTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+	}
+	return nil
+}
+
+// validateAdapterOptionSpecs checks the options declared under
+// adapters.<name>.options.
+func validateAdapterOptionSpecs(adapterName, binary string, specs map[string]AdapterOption, filePath string) []error {
+	var errs []error
+	reserved := reservedAdapterOptions[builtinAdapterKey(adapterName, binary)]
+	for _, name := range slices.Sorted(maps.Keys(specs)) {
+		field := fmt.Sprintf("adapters.%s.options.%s", adapterName, name)
+		switch spec := specs[name]; {
+		case !adapterOptionName.MatchString(name):
+			errs = append(errs, &ValidationError{
+				File:       filePath,
+				Field:      field,
+				Reason:     "invalid option name",
+				Suggestion: "Use the flag name without leading dashes (e.g., 'max-turns')",
+			})
+		case slices.Contains(reserved, name):
+			errs = append(errs, &ValidationError{
+				File:       filePath,
+				Field:      field,
+				Reason:     fmt.Sprintf("--%s is set by Wave and cannot be overridden", name),
+				Suggestion: "Remove the option; use the persona or step fields Wave provides instead",
+			})
+		case spec.Type != "" && spec.Type != OptionTypeString && spec.Type != OptionTypeInt && spec.Type != OptionTypeBool:
+			errs = append(errs, &ValidationError{
+				File:       filePath,
+				Field:      field + ".type",
+				Reason:     fmt.Sprintf("unknown type %q", spec.Type),
+				Suggestion: "Use 'string', 'int' or 'bool'",
+			})
+		case len(spec.Values) > 0 && spec.Type != "" && spec.Type != OptionTypeString:
+			errs = append(errs, &ValidationError{
+				File:       filePath,
+				Field:      field + ".values",
+				Reason:     "only string options can restrict their values",
+				Suggestion: "Remove 'values' or set 'type: string'",
+			})
+		}
+	}
+	return errs
+}
+
+// validatePersonaAdapterOptions checks each persona's adapter_options
+// against the options its adapter accepts. Personas with an undefined
+// adapter are reported by validatePersonasListWithFile.
+func validatePersonaAdapterOptions(m *Manifest, filePath string) []error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(m.Personas)) {
+		persona := m.Personas[name]
+		if len(persona.AdapterOptions) == 0 {
+			continue
+		}
+		if _, ok := m.Adapters[persona.Adapter]; !ok {
+			continue
+		}
+		if err := m.ValidateAdapterOptions(persona.Adapter, persona.AdapterOptions); err != nil {
+			errs = append(errs, &ValidationError{
+				File:       filePath,
+				Field:      fmt.Sprintf("personas.%s.adapter_options", name),
+				Reason:     err.Error(),
+				Suggestion: fmt.Sprintf("Declare custom flags under adapters.%s.options", persona.Adapter),
+			})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,78 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAdapterOptions(t *testing.T) {
+	m := &Manifest{Adapters: map[string]Adapter{
+		"claude": {Binary: "claude", Mode: "headless", Options: map[string]AdapterOption{
+			"betas": {Description: "Beta headers"},
+		}},
+		"custom": {Binary: "my-agent", Mode: "headless"},
+		"openai": {Binary: "/usr/local/bin/codex", Mode: "headless"},
+	}}
+
+	opts := map[string]string{"max-turns": "20", "permission-mode": "plan", "strict-mcp-config": "true", "betas": "x"}
+	if err := m.ValidateAdapterOptions("claude", opts); err != nil {
+		t.Fatalf("ValidateAdapterOptions: %v", err)
+	}
+	want := []string{"--betas", "x", "--max-turns", "20", "--permission-mode", "plan", "--strict-mcp-config"}
+	if got := m.AdapterOptionArgs("claude", opts); !reflect.DeepEqual(got, want) {
+		t.Errorf("AdapterOptionArgs = %v, want %v", got, want)
+	}
+	if got := m.AdapterOptionArgs("claude", map[string]string{"strict-mcp-config": "false"}); got != nil {
+		t.Errorf("false bool option rendered as %v", got)
+	}
+
+	for opts, wantErr := range map[string]string{
+		"max-turns=many":       `option "max-turns": "many" is not an integer`,
+		"permission-mode=yolo": `"yolo" is not one of acceptEdits, bypassPermissions, default, plan`,
+		"output-format=text":   `unknown option "output-format" for adapter "claude"`,
+	} {
+		name, value, _ := strings.Cut(opts, "=")
+		err := m.ValidateAdapterOptions("claude", map[string]string{name: value})
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("%s: got %v, want error containing %q", opts, err, wantErr)
+		}
+	}
+
+	err := m.ValidateAdapterOptions("custom", map[string]string{"turns": "3"})
+	if err == nil || !strings.Contains(err.Error(), "declare \"turns\" under adapters.custom.options") {
+		t.Errorf("custom adapter without options: got %v", err)
+	}
+	if _, ok := m.AdapterOptions("opencode-patched")["agent"]; !ok {
+		t.Error("opencode forks should accept opencode's options")
+	}
+	if _, ok := m.AdapterOptions("openai")["sandbox"]; !ok {
+		t.Error("adapters running the codex binary should accept codex's options")
+	}
+}
+
+func TestValidateAdapterOptionsInManifest(t *testing.T) {
+	m := &Manifest{
+		Metadata: Metadata{Name: "test"},
+		Runtime:  Runtime{WorkspaceRoot: ".agents/workspaces"},
+		Adapters: map[string]Adapter{
+			"claude": {Binary: "claude", Mode: "headless", Options: map[string]AdapterOption{
+				"model":  {},
+				"budget": {Type: "float"},
+			}},
+		},
+		Personas: map[string]Persona{
+			"navigator": {Adapter: "claude", SystemPromptFile: "parser.go", AdapterOptions: map[string]string{"max-turns": "ten"}},
+		},
+	}
+
+	errs := Validate(m, ".")
+	var fields []string
+	for _, err := range errs {
+		fields = append(fields, err.(*ValidationError).Field)
+	}
+	want := []string{"adapters.claude.options.budget.type", "adapters.claude.options.model", "personas.navigator.adapter_options"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("error fields = %v, want %v (errors: %v)", fields, want, errs)
+	}
+}
@@ -0,0 +1,99 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document kinds that carry an apiVersion.
+const (
+	KindManifest = "WaveManifest"
+	KindPipeline = "WavePipeline"
+)
+
+// CurrentAPIVersion is the schema version this release reads and writes for
+// both wave.yaml and pipeline documents.
+const CurrentAPIVersion = "v1"
+
+// VersionSupport describes how this release treats a document's apiVersion.
+type VersionSupport string
+
+const (
+	// VersionCurrent documents load as is.
+	VersionCurrent VersionSupport = "current"
+	// VersionMigratable documents are rejected at load time, but
+	// 'wave migrate-config' can upgrade them to CurrentAPIVersion.
+	VersionMigratable VersionSupport = "migratable"
+)
+
+// Compatibility is the compatibility matrix enforced at load time: for each
+// document kind, the apiVersions this release knows and how each is handled.
+// Versions missing from the matrix (typically written by a newer Wave) are
+// rejected. An empty apiVersion is treated as current.
+//
+// v0 is the pre-versioning schema: pipelines with untyped pipeline_outputs
+// and contract on_failure: retry, manifests with runtime.default_timeout_minutes.
+var Compatibility = map[string]map[string]VersionSupport{
+	KindManifest: {"v0": VersionMigratable, "v1": VersionCurrent},
+	KindPipeline: {"v0": VersionMigratable, "v1": VersionCurrent},
+}
+
+// DocumentVersion reads the kind and apiVersion of a YAML document without
+// decoding the rest, so the version can be checked before a strict decode
+// trips over fields from another schema version. Unparsable documents
+// return empty strings; the caller's decode reports the syntax error.
+func DocumentVersion(data []byte) (kind, version string) {
+	var header struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return "", ""
+	}
+	return header.Kind, strings.TrimSpace(header.APIVersion)
+}
+
+// CheckAPIVersion validates version against the compatibility matrix for
+// kind. It returns nil for current versions and an actionable
+// *ValidationError otherwise.
+func CheckAPIVersion(kind, version string) error {
+	if version == "" {
+		return nil
+	}
+	versions, ok := Compatibility[kind]
+	if !ok {
+		return nil
+	}
+	switch versions[version] {
+	case VersionCurrent:
+		return nil
+	case VersionMigratable:
+		return &ValidationError{
+			Field:      "apiVersion",
+			Reason:     fmt.Sprintf("%s apiVersion %q is no longer supported (current: %s)", kind, version, CurrentAPIVersion),
+			Suggestion: fmt.Sprintf("Run 'wave migrate-config' to upgrade the document to %s", CurrentAPIVersion),
+		}
+	default:
+		return &ValidationError{
+			Field:      "apiVersion",
+			Reason:     fmt.Sprintf("unknown %s apiVersion %q (this release supports: %s)", kind, version, supportedVersions(versions)),
+			Suggestion: fmt.Sprintf("Upgrade Wave if the document was written by a newer release, or set apiVersion to %s", CurrentAPIVersion),
+		}
+	}
+}
+
+// supportedVersions renders a matrix row, e.g. "v0 (migratable), v1".
+func supportedVersions(versions map[string]VersionSupport) string {
+	names := make([]string, 0, len(versions))
+	for v, support := range versions {
+		if support != VersionCurrent {
+			v += " (" + string(support) + ")"
+		}
+		names = append(names, v)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,63 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckAPIVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		version string
+		wantErr string
+	}{
+		{"empty is current", KindManifest, "", ""},
+		{"current", KindPipeline, CurrentAPIVersion, ""},
+		{"migratable", KindPipeline, "v0", "wave migrate-config"},
+		{"unknown", KindManifest, "v9", "unknown WaveManifest apiVersion \"v9\" (this release supports: v0 (migratable), v1)"},
+		{"unversioned kind", "WaveSomethingElse", "v9", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckAPIVersion(tt.kind, tt.version)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_RejectsMigratableAPIVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wave.yaml")
+	// default_timeout_minutes is still accepted in v1; the version alone
+	// must be what rejects the document.
+	content := `apiVersion: v0
+kind: WaveManifest
+metadata:
+  name: old
+runtime:
+  workspace_root: .agents/workspaces
+  default_timeout_minutes: 30
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewLoader().Load(path)
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expected *ValidationError, got %T: %v", err, err)
+	}
+	if verr.File != path || verr.Field != "apiVersion" || !strings.Contains(verr.Suggestion, "wave migrate-config") {
+		t.Errorf("unexpected error: %+v", verr)
+	}
+}
@@ -0,0 +1,74 @@
+package manifest
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultFailureIssueLabel labels tracking issues filed for repeated step
+// failures when runtime.failure_issues.labels is unset.
+const DefaultFailureIssueLabel = "wave-failure"
+
+// FailureIssuesConfig files tracking issues for repeated step failures
+// (runtime.failure_issues). When a step of a pipeline has terminally failed
+// Threshold times within the last WindowHours, the run opens an issue in the
+// configured forge — or comments on the one still open — with the failures
+// aggregated by triage category.
+type FailureIssuesConfig struct {
+	Enabled     bool     `yaml:"enabled,omitempty"`
+	Threshold   int      `yaml:"threshold,omitempty"`    // Failures that file an issue (default 3)
+	WindowHours int      `yaml:"window_hours,omitempty"` // History considered (default 24)
+	Labels      []string `yaml:"labels,omitempty"`       // Labels of the tracking issue (default [wave-failure])
+	// Repo is the "owner/name" the issues are filed in; defaults to the
+	// repository detected from the git remotes.
+	Repo string `yaml:"repo,omitempty"`
+}
+
+// Window returns the history considered when counting failures.
+func (c FailureIssuesConfig) Window() time.Duration {
+	hours := c.WindowHours
+	if hours <= 0 {
+		hours = 24
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// EffectiveThreshold returns Threshold, defaulting to 3.
+func (c FailureIssuesConfig) EffectiveThreshold() int {
+	if c.Threshold > 0 {
+		return c.Threshold
+	}
+	return 3
+}
+
+// EffectiveLabels returns Labels, defaulting to DefaultFailureIssueLabel.
+func (c FailureIssuesConfig) EffectiveLabels() []string {
+	if len(c.Labels) > 0 {
+		return c.Labels
+	}
+	return []string{DefaultFailureIssueLabel}
+}
+
+// validateFailureIssues checks the runtime.failure_issues thresholds and repo.
+func validateFailureIssues(c *FailureIssuesConfig, filePath string) []error {
+	var errs []error
+	if c.Threshold < 0 || c.WindowHours < 0 {
+		errs = append(errs, &ValidationError{
+			File:       filePath,
+			Field:      "runtime.failure_issues",
+			Reason:     "threshold and window_hours must not be negative",
+			Suggestion: "Omit a field to use its default",
+		})
+	}
+	if c.Repo != "" {
+		if owner, name, ok := strings.Cut(c.Repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			errs = append(errs, &ValidationError{
+				File:       filePath,
+				Field:      "runtime.failure_issues.repo",
+				Reason:     "must be owner/name",
+				Suggestion: "Use a repository slug such as 're-cinq/wave', or omit it to use the git remote",
+			})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,47 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFailureIssuesConfig_Defaults(t *testing.T) {
+	var c FailureIssuesConfig
+	if c.Window() != 24*time.Hour || c.EffectiveThreshold() != 3 {
+		t.Errorf("unexpected defaults: window=%s threshold=%d", c.Window(), c.EffectiveThreshold())
+	}
+	if !reflect.DeepEqual(c.EffectiveLabels(), []string{DefaultFailureIssueLabel}) {
+		t.Errorf("default labels = %v", c.EffectiveLabels())
+	}
+	c = FailureIssuesConfig{WindowHours: 6, Threshold: 5, Labels: []string{"ci"}}
+	if c.Window() != 6*time.Hour || c.EffectiveThreshold() != 5 || !reflect.DeepEqual(c.EffectiveLabels(), []string{"ci"}) {
+		t.Errorf("configured values not used: %+v", c)
+	}
+}
+
+func TestValidateFailureIssues(t *testing.T) {
+	tests := []struct {
+		config  FailureIssuesConfig
+		wantErr string
+	}{
+		{FailureIssuesConfig{}, ""},
+		{FailureIssuesConfig{Enabled: true, Threshold: 2, Repo: "re-cinq/wave"}, ""},
+		{FailureIssuesConfig{Threshold: -1}, "must not be negative"},
+		{FailureIssuesConfig{Repo: "wave"}, "runtime.failure_issues.repo"},
+		{FailureIssuesConfig{Repo: "a/b/c"}, "runtime.failure_issues.repo"},
+	}
+	for _, tt := range tests {
+		errs := validateFailureIssues(&tt.config, "wave.yaml")
+		if tt.wantErr == "" {
+			if len(errs) != 0 {
+				t.Errorf("%+v: unexpected errors %v", tt.config, errs)
+			}
+			continue
+		}
+		if len(errs) == 0 || !strings.Contains(errs[0].Error(), tt.wantErr) {
+			t.Errorf("%+v: errors %v, want %q", tt.config, errs, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package manifest
+
+import (
+	"fmt"
+	"time"
+)
+
+// Actions a run applies to steps flagged flaky (runtime.flaky_steps.action).
+const (
+	FlakyActionWarn  = "warn"
+	FlakyActionRetry = "retry"
+)
+
+// FlakyStepsConfig controls flaky-step detection (runtime.flaky_steps). A
+// step is flaky when, over the runs of its pipeline in the last WindowDays,
+// it ran at least MinRuns times and its failure rate or retry rate reached
+// the threshold. Flaky steps are always listed by `wave stats`; Action
+// decides what a run does with them.
+type FlakyStepsConfig struct {
+	WindowDays  int     `yaml:"window_days,omitempty"`  // History considered (default 14)
+	MinRuns     int     `yaml:"min_runs,omitempty"`     // Runs needed before a step is judged (default 5)
+	FailureRate float64 `yaml:"failure_rate,omitempty"` // Share of runs the step failed in (default 0.2)
+	RetryRate   float64 `yaml:"retry_rate,omitempty"`   // Share of runs the step needed a retry in (default 0.3)
+	// Action is "" to only report flaky steps, "warn" to label them in run
+	// output, or "retry" to also raise their attempts to MaxAttempts.
+	Action      string `yaml:"action,omitempty"`
+	MaxAttempts int    `yaml:"max_attempts,omitempty"` // Attempts for flaky steps with action "retry" (default 3)
+}
+
+// Window returns the history considered for detection.
+func (c FlakyStepsConfig) Window() time.Duration {
+	days := c.WindowDays
+	if days <= 0 {
+		days = 14
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// EffectiveMinRuns returns MinRuns, defaulting to 5.
+func (c FlakyStepsConfig) EffectiveMinRuns() int {
+	if c.MinRuns > 0 {
+		return c.MinRuns
+	}
+	return 5
+}
+
+// EffectiveFailureRate returns FailureRate, defaulting to 0.2.
+func (c FlakyStepsConfig) EffectiveFailureRate() float64 {
+	if c.FailureRate > 0 {
+		return c.FailureRate
+	}
+	return 0.2
+}
+
+// EffectiveRetryRate returns RetryRate, defaulting to 0.3.
+func (c FlakyStepsConfig) EffectiveRetryRate() float64 {
+	if c.RetryRate > 0 {
+		return c.RetryRate
+	}
+	return 0.3
+}
+
+// EffectiveMaxAttempts returns MaxAttempts, defaulting to 3.
+func (c FlakyStepsConfig) EffectiveMaxAttempts() int {
+	if c.MaxAttempts > 0 {
+		return c.MaxAttempts
+	}
+	return 3
+}
+
+// validateFlakySteps checks the runtime.flaky_steps thresholds and action.
+func validateFlakySteps(c *FlakyStepsConfig, filePath string) []error {
+	var errs []error
+	switch c.Action {
+	case "", FlakyActionWarn, FlakyActionRetry:
+	default:
+		errs = append(errs, &ValidationError{
+			File:       filePath,
+			Field:      "runtime.flaky_steps.action",
+			Reason:     fmt.Sprintf("unknown action %q", c.Action),
+			Suggestion: "Use 'warn' or 'retry', or omit it to only report flaky steps",
+		})
+	}
+	rates := []struct {
+		field string
+		value float64
+	}{{"failure_rate", c.FailureRate}, {"retry_rate", c.RetryRate}}
+	for _, r := range rates {
+		if r.value < 0 || r.value > 1 {
+			errs = append(errs, &ValidationError{
+				File:       filePath,
+				Field:      "runtime.flaky_steps." + r.field,
+				Reason:     fmt.Sprintf("%g is not a rate between 0 and 1", r.value),
+				Suggestion: "Use a fraction such as 0.2 for 20% of runs",
+			})
+		}
+	}
+	if c.WindowDays < 0 || c.MinRuns < 0 || c.MaxAttempts < 0 {
+		errs = append(errs, &ValidationError{
+			File:       filePath,
+			Field:      "runtime.flaky_steps",
+			Reason:     "window_days, min_runs and max_attempts must not be negative",
+			Suggestion: "Omit a field to use its default",
+		})
+	}
+	return errs
+}
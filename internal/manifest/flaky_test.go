@@ -0,0 +1,46 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlakyStepsConfig_Defaults(t *testing.T) {
+	var c FlakyStepsConfig
+	if c.Window() != 14*24*time.Hour || c.EffectiveMinRuns() != 5 || c.EffectiveMaxAttempts() != 3 {
+		t.Errorf("unexpected defaults: window=%s min_runs=%d max_attempts=%d", c.Window(), c.EffectiveMinRuns(), c.EffectiveMaxAttempts())
+	}
+	if c.EffectiveFailureRate() != 0.2 || c.EffectiveRetryRate() != 0.3 {
+		t.Errorf("unexpected default rates: %g %g", c.EffectiveFailureRate(), c.EffectiveRetryRate())
+	}
+	c = FlakyStepsConfig{WindowDays: 3, MinRuns: 2, FailureRate: 0.5, RetryRate: 0.6, MaxAttempts: 4}
+	if c.Window() != 3*24*time.Hour || c.EffectiveMinRuns() != 2 || c.EffectiveFailureRate() != 0.5 || c.EffectiveRetryRate() != 0.6 || c.EffectiveMaxAttempts() != 4 {
+		t.Errorf("configured values not used: %+v", c)
+	}
+}
+
+func TestValidateFlakySteps(t *testing.T) {
+	tests := []struct {
+		config  FlakyStepsConfig
+		wantErr string
+	}{
+		{FlakyStepsConfig{}, ""},
+		{FlakyStepsConfig{Action: FlakyActionRetry, MaxAttempts: 4, FailureRate: 0.1}, ""},
+		{FlakyStepsConfig{Action: "quarantine"}, "unknown action"},
+		{FlakyStepsConfig{RetryRate: 30}, "runtime.flaky_steps.retry_rate"},
+		{FlakyStepsConfig{MinRuns: -1}, "must not be negative"},
+	}
+	for _, tt := range tests {
+		errs := validateFlakySteps(&tt.config, "wave.yaml")
+		if tt.wantErr == "" {
+			if len(errs) != 0 {
+				t.Errorf("%+v: unexpected errors %v", tt.config, errs)
+			}
+			continue
+		}
+		if len(errs) == 0 || !strings.Contains(errs[0].Error(), tt.wantErr) {
+			t.Errorf("%+v: errors %v, want %q", tt.config, errs, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// NamingConfig overrides how run IDs and worktree branches are named
+// (runtime.naming). Empty fields keep the built-in formats.
+type NamingConfig struct {
+	// RunID is a template for new run IDs. It supports {{ pipeline }},
+	// {{ date }} and {{ hash }}, and must contain {{ hash }} so that runs
+	// started in the same second stay distinct.
+	RunID string `yaml:"run_id,omitempty"`
+	// DateLayout is the Go time layout for {{ date }} (default
+	// "20060102-150405").
+	DateLayout string `yaml:"date_layout,omitempty"`
+	// Branch is a template for the branch of a worktree step that sets
+	// neither workspace.branch nor workspace.base. It supports the usual
+	// pipeline placeholders, e.g. "feat/{{ vars.ticket }}-{{ pipeline_id }}".
+	Branch string `yaml:"branch,omitempty"`
+}
+
+// DefaultRunIDDateLayout is the {{ date }} layout when DateLayout is unset.
+const DefaultRunIDDateLayout = "20060102-150405"
+
+// runIDTokenPattern matches a placeholder in a run_id template.
+var runIDTokenPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_.]+)\s*\}\}`)
+
+// FormatRunID renders the RunID template for pipeline, with hash as the
+// random part and t as the creation time.
+func (n NamingConfig) FormatRunID(pipeline, hash string, t time.Time) string {
+	layout := n.DateLayout
+	if layout == "" {
+		layout = DefaultRunIDDateLayout
+	}
+	return runIDTokenPattern.ReplaceAllStringFunc(n.RunID, func(tok string) string {
+		switch runIDTokenPattern.FindStringSubmatch(tok)[1] {
+		case "pipeline":
+			return pipeline
+		case "date":
+			return t.Format(layout)
+		case "hash":
+			return hash
+		}
+		return tok
+	})
+}
+
+// validateNaming checks the runtime.naming templates.
+func validateNaming(n *NamingConfig, filePath string) []error {
+	if n.RunID == "" {
+		return nil
+	}
+	var errs []error
+	hasHash := false
+	for _, m := range runIDTokenPattern.FindAllStringSubmatch(n.RunID, -1) {
+		switch m[1] {
+		case "hash":
+			hasHash = true
+		case "pipeline", "date":
+		default:
+			errs = append(errs, &ValidationError{
+				File:       filePath,
+				Field:      "runtime.naming.run_id",
+				Reason:     fmt.Sprintf("unknown placeholder %q", m[0]),
+				Suggestion: "Use {{ pipeline }}, {{ date }} and {{ hash }}",
+			})
+		}
+	}
+	if !hasHash {
+		errs = append(errs, &ValidationError{
+			File:       filePath,
+			Field:      "runtime.naming.run_id",
+			Reason:     "must contain {{ hash }}",
+			Suggestion: "Add {{ hash }} so runs started in the same second get distinct IDs, e.g. '{{ pipeline }}-{{ date }}-{{ hash }}'",
+		})
+	}
+	// Run IDs name workspace directories; keep them to one path segment.
+	if strings.ContainsAny(n.RunID, `/\`) || strings.ContainsAny(n.DateLayout, `/\`) {
+		errs = append(errs, &ValidationError{
+			File:       filePath,
+			Field:      "runtime.naming.run_id",
+			Reason:     "must not contain path separators",
+			Suggestion: "Use '-' or '_' to separate the parts of the run ID",
+		})
+	}
+	return errs
+}
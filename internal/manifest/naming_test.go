@@ -0,0 +1,46 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNamingConfig_FormatRunID(t *testing.T) {
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	n := NamingConfig{RunID: "acme-{{ pipeline }}-{{date}}-{{ hash }}"}
+	if got, want := n.FormatRunID("impl-issue", "ab12", at), "acme-impl-issue-20260304-050607-ab12"; got != want {
+		t.Errorf("FormatRunID = %q, want %q", got, want)
+	}
+	n.DateLayout = "060102"
+	if got, want := n.FormatRunID("impl-issue", "ab12", at), "acme-impl-issue-260304-ab12"; got != want {
+		t.Errorf("FormatRunID with layout = %q, want %q", got, want)
+	}
+}
+
+func TestValidateNaming(t *testing.T) {
+	tests := []struct {
+		naming  NamingConfig
+		wantErr string
+	}{
+		{NamingConfig{}, ""},
+		{NamingConfig{Branch: "feat/{{ pipeline_id }}"}, ""},
+		{NamingConfig{RunID: "{{ pipeline }}-{{ hash }}"}, ""},
+		{NamingConfig{RunID: "{{ pipeline }}-{{ date }}"}, "must contain {{ hash }}"},
+		{NamingConfig{RunID: "{{ ticket }}-{{ hash }}"}, "unknown placeholder"},
+		{NamingConfig{RunID: "team/{{ hash }}"}, "path separators"},
+		{NamingConfig{RunID: "{{ date }}-{{ hash }}", DateLayout: "2006/01/02"}, "path separators"},
+	}
+	for _, tt := range tests {
+		errs := validateNaming(&tt.naming, "wave.yaml")
+		if tt.wantErr == "" {
+			if len(errs) != 0 {
+				t.Errorf("%+v: unexpected errors %v", tt.naming, errs)
+			}
+			continue
+		}
+		if len(errs) == 0 || !strings.Contains(errs[0].Error(), tt.wantErr) {
+			t.Errorf("%+v: errors %v, want %q", tt.naming, errs, tt.wantErr)
+		}
+	}
+}
@@ -2,11 +2,14 @@ package manifest
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
@@ -110,6 +113,14 @@ func (l *yamlLoader) Load(path string) (*Manifest, error) {
 		return nil, fmt.Errorf("failed to read manifest file: %w", err)
 	}
 
+	if kind, version := DocumentVersion(data); kind == "" || kind == KindManifest {
+		if err := CheckAPIVersion(KindManifest, version); err != nil {
+			verr := err.(*ValidationError)
+			verr.File = path
+			return nil, verr
+		}
+	}
+
 	var manifest Manifest
 	decoder := yaml.NewDecoder(bytes.NewReader(data))
 	decoder.KnownFields(true)
@@ -120,6 +131,7 @@ func (l *yamlLoader) Load(path string) (*Manifest, error) {
 
 	manifestPath := filepath.Dir(path)
 	manifest.RootDir = manifestPath
+	manifest.Digest = Digest(data)
 	if errs := ValidateWithFile(&manifest, manifestPath, path); len(errs) > 0 {
 		return nil, errs[0]
 	}
@@ -195,6 +207,13 @@ func ValidateWithFile(m *Manifest, basePath, filePath string) []error {
 		errs = append(errs, fallbackErrs...)
 	}
 
+	errs = append(errs, validatePersonaAdapterOptions(m, filePath)...)
+	errs = append(errs, validateEnv("runtime.env", m.Runtime.Env, filePath)...)
+	errs = append(errs, validateNaming(&m.Runtime.Naming, filePath)...)
+	errs = append(errs, validateFlakySteps(&m.Runtime.FlakySteps, filePath)...)
+	errs = append(errs, validateFailureIssues(&m.Runtime.FailureIssues, filePath)...)
+	errs = append(errs, validateNotifications(&m.Runtime.Notifications, filePath)...)
+
 	return errs
 }
 
@@ -247,6 +266,35 @@ func validateFallbackNames(fallbacks map[string][]string, filePath string) []err
 	return errs
 }
 
+// envKeyPattern matches portable environment variable names.
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidEnvKey reports whether key is a valid environment variable name.
+func ValidEnvKey(key string) bool {
+	return envKeyPattern.MatchString(key)
+}
+
+// validateEnv checks the keys of an env map declared at field.
+func validateEnv(field string, env map[string]string, filePath string) []error {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var errs []error
+	for _, k := range keys {
+		if !ValidEnvKey(k) {
+			errs = append(errs, &ValidationError{
+				File:       filePath,
+				Field:      field,
+				Reason:     fmt.Sprintf("invalid environment variable name %q", k),
+				Suggestion: "Use letters, digits and underscores, not starting with a digit (e.g., 'HTTPS_PROXY')",
+			})
+		}
+	}
+	return errs
+}
+
 func validateMetadata(m *Metadata, _ string) *ValidationError {
 	if strings.TrimSpace(m.Name) == "" {
 		return &ValidationError{
@@ -266,6 +314,39 @@ func validateRuntime(r *Runtime, _ string) *ValidationError {
 			Suggestion: "Set 'workspace_root' to a directory path like '.agents/workspaces'",
 		}
 	}
+	if r.MaxParallelSteps < 0 {
+		return &ValidationError{
+			Field:      "runtime.max_parallel_steps",
+			Reason:     "must not be negative",
+			Suggestion: "Set 'max_parallel_steps' to 0 (unlimited) or a positive number of steps",
+		}
+	}
+	switch r.Artifacts.StdoutOverflow {
+	case "", "fail", "truncate":
+	default:
+		return &ValidationError{
+			Field:      "runtime.artifacts.stdout_overflow",
+			Reason:     fmt.Sprintf("unknown policy %q", r.Artifacts.StdoutOverflow),
+			Suggestion: "Use 'fail' (default) or 'truncate'",
+		}
+	}
+	policies := []struct {
+		field, value, suggestion string
+		allowed                  []string
+	}{
+		{"on_start", r.WorkspaceCleanup.OnStart, "Use 'clean' (default) or 'reuse'", []string{"clean", "reuse"}},
+		{"on_success", r.WorkspaceCleanup.OnSuccess, "Use 'keep' (default) or 'remove'", []string{"keep", "remove"}},
+		{"on_failure", r.WorkspaceCleanup.OnFailure, "Use 'keep' (default) or 'remove'", []string{"keep", "remove"}},
+	}
+	for _, p := range policies {
+		if p.value != "" && !slices.Contains(p.allowed, p.value) {
+			return &ValidationError{
+				Field:      "runtime.workspace_cleanup." + p.field,
+				Reason:     fmt.Sprintf("unknown policy %q", p.value),
+				Suggestion: p.suggestion,
+			}
+		}
+	}
 	return nil
 }
 
@@ -327,6 +408,23 @@ func validateAdaptersWithFile(adapters map[string]Adapter, _, filePath string) [
 				Suggestion: "Set 'mode' to 'headless' for non-interactive execution",
 			})
 		}
+		if adapter.MaxConcurrent < 0 {
+			errs = append(errs, &ValidationError{
+				File:       filePath,
+				Field:      fmt.Sprintf("adapters.%s.max_concurrent", name),
+				Reason:     "must not be negative",
+				Suggestion: "Set 'max_concurrent' to 0 (unlimited) or a positive number of processes",
+			})
+		}
+		if adapter.RequestsPerMinute < 0 {
+			errs = append(errs, &ValidationError{
+				File:       filePath,
+				Field:      fmt.Sprintf("adapters.%s.requests_per_minute", name),
+				Reason:     "must not be negative",
+				Suggestion: "Set 'requests_per_minute' to 0 (unlimited) or a positive number of starts",
+			})
+		}
+		errs = append(errs, validateAdapterOptionSpecs(name, adapter.Binary, adapter.Options, filePath)...)
 	}
 	return errs
 }
@@ -363,6 +461,29 @@ func validatePersonasListWithFile(personas map[string]Persona, adapters map[stri
 			})
 		}
 
+		seen := map[string]bool{persona.Adapter: true}
+		for i, fallback := range persona.AdapterFallbacks {
+			field := fmt.Sprintf("personas.%s.adapter[%d]", name, i+1)
+			_, known := adapters[fallback]
+			switch {
+			case seen[fallback]:
+				errs = append(errs, &ValidationError{
+					File:       filePath,
+					Field:      field,
+					Reason:     fmt.Sprintf("adapter '%s' appears twice in the failover list", fallback),
+					Suggestion: "List each adapter once, in the order to try them",
+				})
+			case !known:
+				errs = append(errs, &ValidationError{
+					File:       filePath,
+					Field:      field,
+					Reason:     fmt.Sprintf("adapter '%s' not found in adapters map", fallback),
+					Suggestion: fmt.Sprintf("Available adapters: %v", availableAdapters),
+				})
+			}
+			seen[fallback] = true
+		}
+
 		if strings.TrimSpace(persona.SystemPromptFile) == "" {
 			errs = append(errs, &ValidationError{
 				File:       filePath,
@@ -385,6 +506,21 @@ func validatePersonasListWithFile(personas map[string]Persona, adapters map[stri
 			}
 		}
 
+		errs = append(errs, validateEnv(fmt.Sprintf("personas.%s.env", name), persona.Env, filePath)...)
+
+		if persona.Filesystem != nil {
+			for i, root := range persona.Filesystem.Roots {
+				if strings.TrimSpace(root) == "" {
+					errs = append(errs, &ValidationError{
+						File:       filePath,
+						Field:      fmt.Sprintf("personas.%s.filesystem.roots[%d]", name, i),
+						Reason:     "root is empty",
+						Suggestion: "Remove the entry or set it to a directory path",
+					})
+				}
+			}
+		}
+
 		// Validate token_scopes syntax
 		if len(persona.TokenScopes) > 0 {
 			if scopeErrs := scope.ValidateScopes(persona.TokenScopes); len(scopeErrs) > 0 {
@@ -416,9 +552,16 @@ func UnmarshalStrict(data []byte) (*Manifest, error) {
 	if err := decoder.Decode(&m); err != nil {
 		return nil, err
 	}
+	m.Digest = Digest(data)
 	return &m, nil
 }
 
+// Digest returns the hex-encoded SHA-256 of a definition file's contents.
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
 // SkillStore is a minimal interface for skill existence checks during manifest validation.
 type SkillStore interface {
 	Read(name string) (interface{}, error)
@@ -561,6 +704,35 @@ func validateHooks(hks []hooks.LifecycleHookDef, filePath string) []error {
 	return errs
 }
 
+// validateNotifications checks the runtime.notifications routes. Route
+// names must be unique, as they key the dedup window.
+func validateNotifications(c *hooks.NotificationConfig, filePath string) []error {
+	var errs []error
+	seen := make(map[string]bool, len(c.Routes))
+	for i, r := range c.Routes {
+		field := fmt.Sprintf("runtime.notifications.routes[%d]", i)
+		if err := hooks.ValidateNotificationRoute(r); err != nil {
+			errs = append(errs, &ValidationError{
+				File:       filePath,
+				Field:      field,
+				Reason:     err.Error(),
+				Suggestion: "Name each route and list the webhook names it sends to under 'sinks'",
+			})
+			continue
+		}
+		if seen[r.Name] {
+			errs = append(errs, &ValidationError{
+				File:       filePath,
+				Field:      field + ".name",
+				Reason:     fmt.Sprintf("duplicate route name %q", r.Name),
+				Suggestion: "Each route must have a unique name",
+			})
+		}
+		seen[r.Name] = true
+	}
+	return errs
+}
+
 // validateFallbacks checks runtime.fallbacks configuration for consistency.
 func validateFallbacks(m *Manifest) []error {
 	if len(m.Runtime.Fallbacks) == 0 {
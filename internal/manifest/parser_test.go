@@ -5,9 +5,11 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/recinq/wave/internal/hooks"
 	"gopkg.in/yaml.v3"
 )
 
@@ -194,6 +196,69 @@ runtime: {}
 	}
 }
 
+func TestValidateStdoutOverflow(t *testing.T) {
+	for policy, wantErr := range map[string]bool{"": false, "fail": false, "truncate": false, "drop": true} {
+		r := &Runtime{WorkspaceRoot: ".agents/workspaces", Artifacts: RuntimeArtifactsConfig{StdoutOverflow: policy}}
+		if err := validateRuntime(r, ""); (err != nil) != wantErr {
+			t.Errorf("stdout_overflow %q: got err %v, wantErr %v", policy, err, wantErr)
+		}
+	}
+}
+
+func TestValidateWorkspaceCleanup(t *testing.T) {
+	tests := []struct {
+		cfg     WorkspaceCleanupConfig
+		wantErr string
+	}{
+		{WorkspaceCleanupConfig{}, ""},
+		{WorkspaceCleanupConfig{OnStart: "reuse", OnSuccess: "remove", OnFailure: "keep"}, ""},
+		{WorkspaceCleanupConfig{OnStart: "wipe"}, "runtime.workspace_cleanup.on_start"},
+		{WorkspaceCleanupConfig{OnSuccess: "delete"}, "runtime.workspace_cleanup.on_success"},
+		{WorkspaceCleanupConfig{OnFailure: "clean"}, "runtime.workspace_cleanup.on_failure"},
+	}
+	for _, tt := range tests {
+		r := &Runtime{WorkspaceRoot: ".agents/workspaces", WorkspaceCleanup: tt.cfg}
+		err := validateRuntime(r, "")
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%+v: unexpected error %v", tt.cfg, err)
+			}
+			continue
+		}
+		if err == nil || err.Field != tt.wantErr {
+			t.Errorf("%+v: got err %v, want field %s", tt.cfg, err, tt.wantErr)
+		}
+	}
+
+	c := WorkspaceCleanupConfig{OnSuccess: "remove"}
+	if !c.RemoveAfter(true) || c.RemoveAfter(false) || c.ReuseOnStart() {
+		t.Errorf("unexpected policy decisions for %+v", c)
+	}
+}
+
+func TestValidateEnvKeys(t *testing.T) {
+	m := &Manifest{
+		Metadata: Metadata{Name: "test"},
+		Runtime: Runtime{
+			WorkspaceRoot: ".agents/workspaces",
+			Env:           map[string]string{"HTTPS_PROXY": "http://proxy:3128", "1BAD": "x"},
+		},
+	}
+	errs := Validate(m, t.TempDir())
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "runtime.env") || !strings.Contains(errs[0].Error(), "1BAD") {
+		t.Errorf("unexpected error: %v", errs[0])
+	}
+
+	for key, want := range map[string]bool{"LANG": true, "_X1": true, "my_flag": true, "A-B": false, "": false, "9A": false} {
+		if got := ValidEnvKey(key); got != want {
+			t.Errorf("ValidEnvKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
 func TestValidateEmptyAdapterBinary(t *testing.T) {
 	tmpDir := t.TempDir()
 	manifestPath := filepath.Join(tmpDir, "wave.yaml")
@@ -1317,3 +1382,47 @@ runtime:
 		}
 	})
 }
+
+func TestValidateAdapterLimits(t *testing.T) {
+	adapters := map[string]Adapter{
+		"claude": {Binary: "claude", Mode: "headless", MaxConcurrent: 3, RequestsPerMinute: 20},
+		"codex":  {Binary: "codex", Mode: "headless", MaxConcurrent: -1, RequestsPerMinute: -5},
+	}
+	var fields []string
+	for _, err := range validateAdaptersWithFile(adapters, ".", "wave.yaml") {
+		fields = append(fields, err.(*ValidationError).Field)
+	}
+	sort.Strings(fields)
+	want := []string{"adapters.codex.max_concurrent", "adapters.codex.requests_per_minute"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("error fields = %v, want %v", fields, want)
+	}
+}
+
+func TestValidateRuntimeMaxParallelSteps(t *testing.T) {
+	r := Runtime{WorkspaceRoot: ".agents/workspaces", MaxParallelSteps: -1}
+	err := validateRuntime(&r, ".")
+	if err == nil || err.Field != "runtime.max_parallel_steps" {
+		t.Fatalf("validateRuntime() = %v, want runtime.max_parallel_steps error", err)
+	}
+	r.MaxParallelSteps = 4
+	if err := validateRuntime(&r, "."); err != nil {
+		t.Errorf("validateRuntime() = %v, want nil", err)
+	}
+}
+
+func TestValidateNotifications(t *testing.T) {
+	c := hooks.NotificationConfig{Routes: []hooks.NotificationRoute{
+		{Name: "page", Severity: []string{hooks.SeverityError}, Sinks: []string{"oncall"}},
+		{Name: "page", Sinks: []string{"feed"}},
+		{Name: "feed", DedupWindow: "soon", Sinks: []string{"feed"}},
+	}}
+	var fields []string
+	for _, err := range validateNotifications(&c, ".") {
+		fields = append(fields, err.(*ValidationError).Field)
+	}
+	want := []string{"runtime.notifications.routes[1].name", "runtime.notifications.routes[2]"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("error fields = %v, want %v", fields, want)
+	}
+}
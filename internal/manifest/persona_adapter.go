@@ -0,0 +1,156 @@
+package manifest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AdapterChain returns the persona's adapters in failover order: Adapter
+// followed by AdapterFallbacks.
+func (p Persona) AdapterChain() []string {
+	return append([]string{p.Adapter}, p.AdapterFallbacks...)
+}
+
+// UnmarshalYAML accepts the persona's adapter as a name or as a failover
+// list (`adapter: [claude, codex, ollama]`).
+//
+// A custom unmarshaler is decoded by a fresh decoder that no longer rejects
+// unknown fields, so the persona's keys are checked here to keep wave.yaml
+// as strict as the rest of the manifest.
+func (p *Persona) UnmarshalYAML(node *yaml.Node) error {
+	type plain Persona
+	if node.Kind != yaml.MappingNode {
+		return node.Decode((*plain)(p))
+	}
+	if unknown := unknownYAMLFields(node, reflect.TypeOf(Persona{})); len(unknown) > 0 {
+		return &yaml.TypeError{Errors: unknown}
+	}
+
+	var fallbacks []string
+	mapping := *node
+	mapping.Content = append([]*yaml.Node(nil), node.Content...)
+	temperatureSet := false
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		value := mapping.Content[i+1]
+		if mapping.Content[i].Value == "temperature" {
+			temperatureSet = true
+		}
+		if mapping.Content[i].Value != "adapter" || value.Kind != yaml.SequenceNode {
+			continue
+		}
+		var chain []string
+		if err := value.Decode(&chain); err != nil {
+			return err
+		}
+		primary := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Line: value.Line, Column: value.Column}
+		if len(chain) > 0 {
+			primary.Value, fallbacks = chain[0], chain[1:]
+		}
+		mapping.Content[i+1] = primary
+	}
+
+	if err := mapping.Decode((*plain)(p)); err != nil {
+		return err
+	}
+	p.AdapterFallbacks = fallbacks
+	p.TemperatureSet = temperatureSet
+	return nil
+}
+
+// MarshalYAML writes the adapter back as a list when the persona has
+// fallbacks.
+func (p Persona) MarshalYAML() (interface{}, error) {
+	type plain Persona
+	if len(p.AdapterFallbacks) == 0 {
+		return plain(p), nil
+	}
+	var node yaml.Node
+	if err := node.Encode(plain(p)); err != nil {
+		return nil, err
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "adapter" {
+			var chain yaml.Node
+			if err := chain.Encode(p.AdapterChain()); err != nil {
+				return nil, err
+			}
+			chain.Style = yaml.FlowStyle
+			node.Content[i+1] = &chain
+		}
+	}
+	return &node, nil
+}
+
+// unknownYAMLFields reports the mapping keys under node that have no field
+// in t, recursing into nested structs, slices and maps, in the format of
+// yaml.v3's KnownFields errors.
+func unknownYAMLFields(node *yaml.Node, t reflect.Type) []string {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var errs []string
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		fields := yamlFieldTypes(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			field, ok := fields[key.Value]
+			if !ok {
+				errs = append(errs, fmt.Sprintf("line %d: field %s not found in type %s", key.Line, key.Value, t))
+				continue
+			}
+			errs = append(errs, unknownYAMLFields(node.Content[i+1], field)...)
+		}
+	case reflect.Slice, reflect.Array:
+		if node.Kind == yaml.SequenceNode {
+			for _, item := range node.Content {
+				errs = append(errs, unknownYAMLFields(item, t.Elem())...)
+			}
+		}
+	case reflect.Map:
+		if node.Kind == yaml.MappingNode {
+			for i := 1; i < len(node.Content); i += 2 {
+				errs = append(errs, unknownYAMLFields(node.Content[i], t.Elem())...)
+			}
+		}
+	}
+	return errs
+}
+
+// yamlFieldTypes maps the YAML keys of struct type t to their field types,
+// following yaml.v3's naming: the tag name, else the lowercased field name.
+func yamlFieldTypes(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(opts, "inline") && f.Type.Kind() == reflect.Struct {
+			for k, v := range yamlFieldTypes(f.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
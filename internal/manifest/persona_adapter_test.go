@@ -0,0 +1,129 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestPersonaAdapterList(t *testing.T) {
+	m, err := UnmarshalStrict([]byte(`apiVersion: v1
+kind: WaveManifest
+metadata:
+  name: test
+personas:
+  implementer:
+    adapter: [claude, codex, ollama]
+    system_prompt_file: implementer.md
+  reviewer:
+    adapter: claude
+    system_prompt_file: reviewer.md
+`))
+	if err != nil {
+		t.Fatalf("UnmarshalStrict: %v", err)
+	}
+
+	impl := m.Personas["implementer"]
+	if impl.Adapter != "claude" {
+		t.Errorf("Adapter = %q, want claude", impl.Adapter)
+	}
+	if want := []string{"codex", "ollama"}; !reflect.DeepEqual(impl.AdapterFallbacks, want) {
+		t.Errorf("AdapterFallbacks = %v, want %v", impl.AdapterFallbacks, want)
+	}
+	if impl.SystemPromptFile != "implementer.md" {
+		t.Errorf("SystemPromptFile = %q, want implementer.md", impl.SystemPromptFile)
+	}
+	if rev := m.Personas["reviewer"]; rev.Adapter != "claude" || rev.AdapterFallbacks != nil {
+		t.Errorf("reviewer = %q %v, want a single claude adapter", rev.Adapter, rev.AdapterFallbacks)
+	}
+
+	out, err := yaml.Marshal(impl)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.Contains(string(out), "adapter: [claude, codex, ollama]") {
+		t.Errorf("marshalled persona lost its failover list:\n%s", out)
+	}
+	var back Persona
+	if err := yaml.Unmarshal(out, &back); err != nil {
+		t.Fatalf("Unmarshal round trip: %v", err)
+	}
+	if !reflect.DeepEqual(back.AdapterChain(), impl.AdapterChain()) {
+		t.Errorf("round trip chain = %v, want %v", back.AdapterChain(), impl.AdapterChain())
+	}
+}
+
+func TestPersonaTemperatureSet(t *testing.T) {
+	m, err := UnmarshalStrict([]byte(`apiVersion: v1
+kind: WaveManifest
+metadata:
+  name: test
+personas:
+  pinned:
+    adapter: claude
+    temperature: 0
+  defaulted:
+    adapter: claude
+`))
+	if err != nil {
+		t.Fatalf("UnmarshalStrict: %v", err)
+	}
+	if p := m.Personas["pinned"]; !p.TemperatureSet || p.Temperature != 0 {
+		t.Errorf("pinned = %v set=%v, want an explicit 0", p.Temperature, p.TemperatureSet)
+	}
+	if p := m.Personas["defaulted"]; p.TemperatureSet {
+		t.Error("defaulted persona reports an explicit temperature")
+	}
+}
+
+func TestPersonaUnknownFieldsStayRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		persona string
+		want    string
+	}{
+		{"persona key", "    adapter: claude\n    temprature: 0.1\n", "line 8: field temprature not found in type manifest.Persona"},
+		{"nested key", "    adapter: [claude, codex]\n    permissions:\n      allowed_tool: [Read]\n", "line 9: field allowed_tool not found in type manifest.Permissions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := UnmarshalStrict([]byte("apiVersion: v1\nkind: WaveManifest\nmetadata:\n  name: test\n" +
+				"personas:\n  p:\n" + tt.persona))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePersonaAdapterFallbacks(t *testing.T) {
+	adapters := map[string]Adapter{"claude": {Binary: "claude"}, "codex": {Binary: "codex"}}
+	tests := []struct {
+		name      string
+		fallbacks []string
+		want      string
+	}{
+		{"known fallback", []string{"codex"}, ""},
+		{"unknown fallback", []string{"ollama"}, "personas.p.adapter[1]: adapter 'ollama' not found"},
+		{"repeats primary", []string{"codex", "claude"}, "personas.p.adapter[2]: adapter 'claude' appears twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			personas := map[string]Persona{"p": {Adapter: "claude", AdapterFallbacks: tt.fallbacks, SystemPromptFile: "p.md"}}
+			var got []string
+			for _, err := range validatePersonasListWithFile(personas, adapters, t.TempDir(), "") {
+				if !strings.Contains(err.Error(), "system_prompt_file") {
+					got = append(got, err.Error())
+				}
+			}
+			switch {
+			case tt.want == "" && len(got) > 0:
+				t.Errorf("unexpected errors: %v", got)
+			case tt.want != "" && (len(got) != 1 || !strings.Contains(got[0], tt.want)):
+				t.Errorf("errors = %v, want one containing %q", got, tt.want)
+			}
+		})
+	}
+}
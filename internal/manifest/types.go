@@ -25,6 +25,10 @@ type Project struct {
 	LintCommand         string                   `yaml:"lint_command,omitempty"`
 	BuildCommand        string                   `yaml:"build_command,omitempty"`
 	FormatCommand       string                   `yaml:"format_command,omitempty"`
+	TypecheckCommand    string                   `yaml:"typecheck_command,omitempty"`
+	RunCommand          string                   `yaml:"run_command,omitempty"`
+	CoverageCommand     string                   `yaml:"coverage_command,omitempty"`
+	PackageManager      string                   `yaml:"package_manager,omitempty"`
 	SourceGlob          string                   `yaml:"source_glob,omitempty"`
 	TestFilePattern     []string                 `yaml:"test_file_pattern,omitempty"` // test_diff / test_count_baseline pathspecs (#1583, #1584)
 	TestFuncPattern     string                   `yaml:"test_func_pattern,omitempty"` // test_diff / test_count_baseline regex (#1583, #1584)
@@ -47,6 +51,9 @@ type Manifest struct {
 
 	// RootDir is the directory containing wave.yaml. Set by the loader.
 	RootDir string `yaml:"-"`
+	// Digest is the SHA-256 of the raw wave.yaml bytes. Set by the loader
+	// and recorded per run as definition provenance.
+	Digest string `yaml:"-"`
 }
 
 // EvolutionYAML is the operator-facing override for the Phase 3.3 trigger
@@ -84,10 +91,24 @@ type Adapter struct {
 	// Tiers: "cheapest" (cost-optimized), "balanced" (quality/cost), "strongest" (capability-optimized).
 	// If not set, falls back to routing.complexity_map, then adapter default_model.
 	TierModels map[string]string `yaml:"tier_models,omitempty"`
+	// Options declares CLI flags personas and steps may pass through
+	// adapter_options, in addition to the adapter's built-in ones.
+	Options map[string]AdapterOption `yaml:"options,omitempty"`
+	// MaxConcurrent caps how many of the adapter's processes run at once
+	// across a run's parallel steps and matrix workers. 0 is unlimited.
+	MaxConcurrent int `yaml:"max_concurrent,omitempty"`
+	// RequestsPerMinute caps how often the adapter is started across a run.
+	// Steps over the limit wait for the next slot. 0 is unlimited.
+	RequestsPerMinute int `yaml:"requests_per_minute,omitempty"`
 }
 
 type Persona struct {
-	Adapter          string          `yaml:"adapter"`
+	// Adapter is the persona's adapter. In YAML it may be a list, whose
+	// first entry lands here and the rest in AdapterFallbacks.
+	Adapter string `yaml:"adapter"`
+	// AdapterFallbacks are the adapters a step fails over to, in order, when
+	// Adapter is rate limited or keeps exiting non-zero.
+	AdapterFallbacks []string        `yaml:"-"`
 	Description      string          `yaml:"description,omitempty"`
 	SystemPromptFile string          `yaml:"system_prompt_file"`
 	Temperature      float64         `yaml:"temperature,omitempty"`
@@ -97,12 +118,36 @@ type Persona struct {
 	Sandbox          *PersonaSandbox `yaml:"sandbox,omitempty"`
 	Skills           []string        `yaml:"skills,omitempty"`
 	TokenScopes      []string        `yaml:"token_scopes,omitempty"`
+	// Filesystem limits the paths the persona's file tools may touch to the
+	// step workspace, its mounts and the listed roots. Nil means unscoped.
+	Filesystem *PersonaFilesystem `yaml:"filesystem,omitempty"`
+	// Env is set in the adapter process for every step using this persona.
+	// Overrides runtime.env; overridden by step env.
+	Env map[string]string `yaml:"env,omitempty"`
+	// AdapterOptions are extra CLI flags for the persona's adapter, keyed by
+	// flag name without dashes. Steps on another adapter do not get them.
+	AdapterOptions map[string]string `yaml:"adapter_options,omitempty"`
+	// TemperatureSet reports that temperature was written in the manifest,
+	// so a zero Temperature asks for temperature 0 rather than the
+	// adapter's default.
+	TemperatureSet bool `yaml:"-"`
 }
 
 type PersonaSandbox struct {
 	AllowedDomains []string `yaml:"allowed_domains,omitempty"`
 }
 
+// PersonaFilesystem is the path policy for a persona. Tool calls are checked
+// against it as they stream from the adapter.
+type PersonaFilesystem struct {
+	// Roots are extra directories the persona may read and write, relative
+	// to the project root unless absolute.
+	Roots []string `yaml:"roots,omitempty"`
+	// Strict kills the step on the first violation instead of emitting a
+	// warning.
+	Strict bool `yaml:"strict,omitempty"`
+}
+
 type Permissions struct {
 	AllowedTools []string `yaml:"allowed_tools,omitempty"`
 	Deny         []string `yaml:"deny,omitempty"`
@@ -163,6 +208,7 @@ type Runtime struct {
 	DefaultTimeoutMin    int                    `yaml:"default_timeout_minutes,omitempty"`
 	PipelineIDHashLength int                    `yaml:"pipeline_id_hash_length,omitempty"`
 	MaxConcurrency       int                    `yaml:"max_concurrency,omitempty"`
+	MaxParallelSteps     int                    `yaml:"max_parallel_steps,omitempty"` // Ready steps of a run executed at once. 0 = unlimited.
 	Timeouts             Timeouts               `yaml:"timeouts,omitempty"`
 	Relay                RelayConfig            `yaml:"relay,omitempty"`
 	Audit                AuditConfig            `yaml:"audit,omitempty"`
@@ -170,23 +216,49 @@ type Runtime struct {
 	Routing              RoutingConfig          `yaml:"routing,omitempty"`
 	Sandbox              RuntimeSandbox         `yaml:"sandbox,omitempty"`
 	Artifacts            RuntimeArtifactsConfig `yaml:"artifacts,omitempty"`
+	WorkspaceCleanup     WorkspaceCleanupConfig `yaml:"workspace_cleanup,omitempty"`
+	Attestation          AttestationConfig      `yaml:"attestation,omitempty"`
+	AdapterLogs          AdapterLogsConfig      `yaml:"adapter_logs,omitempty"`
 	CircuitBreaker       CircuitBreakerConfig   `yaml:"circuit_breaker,omitempty"`
+	FlakySteps           FlakyStepsConfig       `yaml:"flaky_steps,omitempty"`
+	FailureIssues        FailureIssuesConfig    `yaml:"failure_issues,omitempty"`
 	Retros               RetrosConfig           `yaml:"retros,omitempty"`
 	Cost                 CostConfig             `yaml:"cost,omitempty"`
 	Fallbacks            map[string][]string    `yaml:"fallbacks,omitempty"`     // Adapter fallback chains (e.g., anthropic: [openai, gemini])
 	StallTimeout         string                 `yaml:"stall_timeout,omitempty"` // Duration string (e.g. "30m", "1800s"). 0 or empty = disabled.
+	// Env is set in every adapter process (proxy settings, locale, feature
+	// flags). Persona and step env maps override keys defined here.
+	Env map[string]string `yaml:"env,omitempty"`
+	// Naming overrides the run ID format and default worktree branch name.
+	Naming NamingConfig `yaml:"naming,omitempty"`
+	// Notifications routes lifecycle events to webhooks by pipeline, event,
+	// severity and run tag, with quiet hours and dedup windows.
+	Notifications hooks.NotificationConfig `yaml:"notifications,omitempty"`
 }
 
 // CostConfig holds cost tracking and budget enforcement settings.
 type CostConfig struct {
-	// Enabled activates cost tracking for pipeline runs.
+	// Enabled is accepted for compatibility. Every run is priced, and its
+	// step costs are recorded in the state store.
 	Enabled bool `yaml:"enabled,omitempty"`
 	// BudgetCeiling is the maximum cost in USD per pipeline run. 0 = unlimited.
 	BudgetCeiling float64 `yaml:"budget_ceiling,omitempty"`
 	// WarnAt is the cost threshold (USD) at which to emit a warning. 0 = disabled.
 	WarnAt float64 `yaml:"warn_at,omitempty"`
+	// BurnRateWarn is the spend rate (USD per minute) of a single running
+	// step at which to emit a warning. 0 = disabled.
+	BurnRateWarn float64 `yaml:"burn_rate_warn,omitempty"`
 	// Currency is the display currency (default: "USD").
 	Currency string `yaml:"currency,omitempty"`
+	// Pricing prices models by name or name prefix, overriding and
+	// extending Wave's built-in prices.
+	Pricing map[string]ModelPrice `yaml:"pricing,omitempty"`
+}
+
+// ModelPrice is the USD price of a model per million tokens.
+type ModelPrice struct {
+	InputPerMillion  float64 `yaml:"input_per_million"`
+	OutputPerMillion float64 `yaml:"output_per_million"`
 }
 
 // GetMaxConcurrency returns the configured maximum step concurrency, defaulting to 10.
@@ -197,9 +269,84 @@ func (r *Runtime) GetMaxConcurrency() int {
 	return 10
 }
 
+// WorkspaceCleanupConfig controls when a run's workspace tree
+// (<workspace_root>/<run-id>) is removed.
+type WorkspaceCleanupConfig struct {
+	OnStart   string `yaml:"on_start,omitempty"`   // "clean" (default) or "reuse"
+	OnSuccess string `yaml:"on_success,omitempty"` // "keep" (default) or "remove"
+	OnFailure string `yaml:"on_failure,omitempty"` // "keep" (default) or "remove"
+}
+
+// ReuseOnStart reports whether an existing workspace is kept at run start.
+func (c *WorkspaceCleanupConfig) ReuseOnStart() bool {
+	return c.OnStart == "reuse"
+}
+
+// RemoveAfter reports whether the workspace is removed once a run finishes
+// with the given outcome.
+func (c *WorkspaceCleanupConfig) RemoveAfter(succeeded bool) bool {
+	if succeeded {
+		return c.OnSuccess == "remove"
+	}
+	return c.OnFailure == "remove"
+}
+
+// AttestationConfig controls the provenance attestation written when a
+// run finishes.
+type AttestationConfig struct {
+	// Enabled writes an in-toto attestation for every top-level run.
+	Enabled bool `yaml:"enabled,omitempty"`
+	// SigningKey is a PEM-encoded ed25519 private key (PKCS#8) used to sign
+	// the attestation. Empty writes it unsigned.
+	SigningKey string `yaml:"signing_key,omitempty"`
+	// Dir is where attestations are written (default: ".agents/attestations").
+	Dir string `yaml:"dir,omitempty"`
+}
+
+// GetDir returns the configured attestation directory or the default.
+func (c *AttestationConfig) GetDir() string {
+	if c.Dir != "" {
+		return c.Dir
+	}
+	return ".agents/attestations"
+}
+
+// AdapterLogsConfig controls the raw adapter stdout/stderr capture kept for
+// every step at <dir>/<run-id>/<step-id>.log.
+type AdapterLogsConfig struct {
+	Dir        string `yaml:"dir,omitempty"`         // Base directory (default: ".agents/logs")
+	MaxSizeMB  int    `yaml:"max_size_mb,omitempty"` // Rotate a step log past this size (default: 10)
+	MaxBackups int    `yaml:"max_backups,omitempty"` // Rotated files kept per step (default: 3)
+}
+
+// GetDir returns the configured adapter log directory or the default.
+func (c *AdapterLogsConfig) GetDir() string {
+	if c.Dir != "" {
+		return c.Dir
+	}
+	return ".agents/logs"
+}
+
+// GetMaxSize returns the rotation threshold in bytes (default: 10MB).
+func (c *AdapterLogsConfig) GetMaxSize() int64 {
+	if c.MaxSizeMB > 0 {
+		return int64(c.MaxSizeMB) * 1024 * 1024
+	}
+	return 10 * 1024 * 1024
+}
+
+// GetMaxBackups returns how many rotated files are kept per step (default: 3).
+func (c *AdapterLogsConfig) GetMaxBackups() int {
+	if c.MaxBackups > 0 {
+		return c.MaxBackups
+	}
+	return 3
+}
+
 // RuntimeArtifactsConfig holds global configuration for artifact handling.
 type RuntimeArtifactsConfig struct {
 	MaxStdoutSize      int64  `yaml:"max_stdout_size,omitempty"`      // Max bytes to capture from stdout (default: 10MB)
+	StdoutOverflow     string `yaml:"stdout_overflow,omitempty"`      // "fail" (default) or "truncate" when stdout exceeds max_stdout_size
 	DefaultArtifactDir string `yaml:"default_artifact_dir,omitempty"` // Base directory for artifacts (default: ".agents/artifacts")
 }
 
@@ -370,6 +517,18 @@ func (p *Project) ProjectVars() map[string]string {
 	if p.FormatCommand != "" {
 		vars["project.format_command"] = p.FormatCommand
 	}
+	if p.TypecheckCommand != "" {
+		vars["project.typecheck_command"] = p.TypecheckCommand
+	}
+	if p.RunCommand != "" {
+		vars["project.run_command"] = p.RunCommand
+	}
+	if p.CoverageCommand != "" {
+		vars["project.coverage_command"] = p.CoverageCommand
+	}
+	if p.PackageManager != "" {
+		vars["project.package_manager"] = p.PackageManager
+	}
 	if p.SourceGlob != "" {
 		vars["project.source_glob"] = p.SourceGlob
 	}
@@ -441,12 +600,64 @@ type ServerConfig struct {
 	MaxConcurrent int              `yaml:"max_concurrent,omitempty"`
 	Auth          ServerAuthConfig `yaml:"auth,omitempty"`
 	TLS           ServerTLSConfig  `yaml:"tls,omitempty"`
+	// RateLimit bounds how fast each caller may start, cancel or approve
+	// runs. Tokens may override it.
+	RateLimit ServerRateLimitConfig `yaml:"rate_limit,omitempty"`
+	// PublicStatus serves pipeline status badges and /api/status without
+	// authentication.
+	PublicStatus bool `yaml:"public_status,omitempty"`
+	// WarmPool keeps an adapter process started for a failed step's retry
+	// so it skips the adapter CLI's cold start.
+	WarmPool ServerWarmPoolConfig `yaml:"warm_pool,omitempty"`
+}
+
+// ServerWarmPoolConfig configures the server's pool of warm adapter
+// processes. Enabling it runs pipelines inside the server process.
+type ServerWarmPoolConfig struct {
+	Enabled            bool `yaml:"enabled,omitempty"`
+	MaxIdle            int  `yaml:"max_idle,omitempty"`             // warm processes kept in total (default 4)
+	IdleTimeoutMinutes int  `yaml:"idle_timeout_minutes,omitempty"` // reap unused warm processes after this (default 10)
+}
+
+// ServerRateLimitConfig limits mutating API requests per caller.
+type ServerRateLimitConfig struct {
+	RequestsPerMinute int `yaml:"requests_per_minute,omitempty"` // 0 disables the limit
+	Burst             int `yaml:"burst,omitempty"`               // defaults to requests_per_minute
 }
 
 // ServerAuthConfig holds authentication configuration for server mode.
 type ServerAuthConfig struct {
-	Mode      string `yaml:"mode,omitempty"`       // "jwt", "mtls", "bearer", "none"
+	Mode      string `yaml:"mode,omitempty"`       // "jwt", "oidc", "mtls", "bearer", "none"
 	JWTSecret string `yaml:"jwt_secret,omitempty"` // supports ${ENV_VAR} expansion
+	// SlackSigningSecret verifies Slack interactivity callbacks (gate
+	// buttons). Supports ${ENV_VAR} expansion.
+	SlackSigningSecret string `yaml:"slack_signing_secret,omitempty"`
+	// Tokens are API tokens, each bound to a role, accepted in every mode
+	// that reads bearer tokens.
+	Tokens []ServerAPIToken `yaml:"tokens,omitempty"`
+	// OIDC configures the OpenID Connect provider for mode "oidc".
+	OIDC *ServerOIDCConfig `yaml:"oidc,omitempty"`
+	// DefaultRole is granted to jwt and oidc identities whose token names
+	// no role: viewer, operator, approver or admin.
+	DefaultRole string `yaml:"default_role,omitempty"`
+}
+
+// ServerAPIToken is a named API token for the dashboard server.
+type ServerAPIToken struct {
+	Name  string `yaml:"name"`
+	Token string `yaml:"token"` // supports ${ENV_VAR} expansion
+	Role  string `yaml:"role"`  // viewer, operator, approver, admin
+	// RateLimit is this token's limit on mutating requests per minute,
+	// overriding server.rate_limit. 0 uses the server limit.
+	RateLimit int `yaml:"rate_limit,omitempty"`
+}
+
+// ServerOIDCConfig identifies the OpenID Connect provider whose ID tokens
+// the dashboard server accepts.
+type ServerOIDCConfig struct {
+	Issuer    string `yaml:"issuer"`
+	Audience  string `yaml:"audience,omitempty"`   // expected aud claim, usually the client ID
+	RoleClaim string `yaml:"role_claim,omitempty"` // claim holding the role(s); default "roles"
 }
 
 // ServerTLSConfig holds TLS configuration for server mode.
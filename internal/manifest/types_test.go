@@ -23,14 +23,18 @@ func TestProjectVars(t *testing.T) {
 		{
 			name: "all fields populated",
 			project: &Project{
-				Language:      "go",
-				Flavour:       "go",
-				TestCommand:   "go test ./...",
-				LintCommand:   "golangci-lint run ./...",
-				BuildCommand:  "go build ./...",
-				FormatCommand: "gofmt -l .",
-				SourceGlob:    "*.go",
-				Skill:         "golang",
+				Language:         "go",
+				Flavour:          "go",
+				TestCommand:      "go test ./...",
+				LintCommand:      "golangci-lint run ./...",
+				BuildCommand:     "go build ./...",
+				FormatCommand:    "gofmt -l .",
+				TypecheckCommand: "go vet ./...",
+				RunCommand:       "go run ./cmd/app",
+				CoverageCommand:  "go test -cover ./...",
+				PackageManager:   "go",
+				SourceGlob:       "*.go",
+				Skill:            "golang",
 			},
 			expected: map[string]string{
 				"project.language":              "go",
@@ -40,6 +44,10 @@ func TestProjectVars(t *testing.T) {
 				"project.lint_command":          "golangci-lint run ./...",
 				"project.build_command":         "go build ./...",
 				"project.format_command":        "gofmt -l .",
+				"project.typecheck_command":     "go vet ./...",
+				"project.run_command":           "go run ./cmd/app",
+				"project.coverage_command":      "go test -cover ./...",
+				"project.package_manager":       "go",
 				"project.source_glob":           "*.go",
 				"project.skill":                 "golang",
 			},
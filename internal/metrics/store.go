@@ -40,8 +40,8 @@ func (s *Store) RecordPerformanceMetric(metric *PerformanceMetricRecord) error {
 	query := `INSERT INTO performance_metric (
 	              run_id, step_id, pipeline_name, persona, started_at, completed_at,
 	              duration_ms, tokens_used, files_modified, artifacts_generated,
-	              memory_bytes, success, error_message
-	          ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	              memory_bytes, success, error_message, tokens_estimated
+	          ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	result, err := s.db.Exec(
 		query,
@@ -58,6 +58,7 @@ func (s *Store) RecordPerformanceMetric(metric *PerformanceMetricRecord) error {
 		metric.MemoryBytes,
 		metric.Success,
 		metric.ErrorMessage,
+		metric.TokensEstimated,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to record performance metric: %w", err)
@@ -74,7 +75,7 @@ func (s *Store) RecordPerformanceMetric(metric *PerformanceMetricRecord) error {
 func (s *Store) GetPerformanceMetrics(runID string, stepID string) ([]PerformanceMetricRecord, error) {
 	query := `SELECT id, run_id, step_id, pipeline_name, persona, started_at, completed_at,
 	                 duration_ms, tokens_used, files_modified, artifacts_generated,
-	                 memory_bytes, success, error_message
+	                 memory_bytes, success, error_message, tokens_estimated
 	          FROM performance_metric
 	          WHERE run_id = ?`
 	args := []any{runID}
@@ -122,6 +123,7 @@ func (s *Store) GetStepPerformanceStats(pipelineName string, stepID string, sinc
 	              AVG(files_modified) as avg_files,
 	              AVG(artifacts_generated) as avg_artifacts,
 	              MAX(started_at) as last_run,
+	              SUM(tokens_estimated) as estimated_runs,
 	              persona
 	          FROM performance_metric
 	          WHERE pipeline_name = ? AND step_id = ? AND started_at >= ?
@@ -145,6 +147,7 @@ func (s *Store) GetStepPerformanceStats(pipelineName string, stepID string, sinc
 		&avgFiles,
 		&avgArtifacts,
 		&lastRun,
+		&stats.EstimatedRuns,
 		&persona,
 	)
 	if err != nil {
@@ -194,7 +197,7 @@ func (s *Store) GetStepPerformanceStats(pipelineName string, stepID string, sinc
 func (s *Store) GetRecentPerformanceHistory(opts PerformanceQueryOptions) ([]PerformanceMetricRecord, error) {
 	query := `SELECT id, run_id, step_id, pipeline_name, persona, started_at, completed_at,
 	                 duration_ms, tokens_used, files_modified, artifacts_generated,
-	                 memory_bytes, success, error_message
+	                 memory_bytes, success, error_message, tokens_estimated
 	          FROM performance_metric
 	          WHERE 1=1`
 	args := []any{}
@@ -287,6 +290,7 @@ func scanMetricRow(rows *sql.Rows) (PerformanceMetricRecord, error) {
 		&memoryBytes,
 		&metric.Success,
 		&errorMessage,
+		&metric.TokensEstimated,
 	)
 	if err != nil {
 		return metric, fmt.Errorf("failed to scan performance metric: %w", err)
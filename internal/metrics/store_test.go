@@ -36,7 +36,8 @@ func setupTestStore(t *testing.T) (*Store, func()) {
 			artifacts_generated INTEGER,
 			memory_bytes INTEGER,
 			success INTEGER NOT NULL,
-			error_message TEXT
+			error_message TEXT,
+			tokens_estimated INTEGER NOT NULL DEFAULT 0
 		)`
 	_, err = db.Exec(createPerformanceMetric)
 	require.NoError(t, err)
@@ -81,6 +82,7 @@ func TestRecordPerformanceMetric(t *testing.T) {
 			ArtifactsGenerated: 2,
 			MemoryBytes:        1024000,
 			Success:            true,
+			TokensEstimated:    true,
 		}
 
 		err := store.RecordPerformanceMetric(metric)
@@ -106,6 +108,7 @@ func TestRecordPerformanceMetric(t *testing.T) {
 		assert.Equal(t, int64(1024000), m.MemoryBytes)
 		assert.True(t, m.Success)
 		assert.Empty(t, m.ErrorMessage)
+		assert.True(t, m.TokensEstimated)
 	})
 
 	t.Run("filter by stepID", func(t *testing.T) {
@@ -188,6 +191,7 @@ func TestGetStepPerformanceStats(t *testing.T) {
 				FilesModified:      i + 1,
 				ArtifactsGenerated: 1,
 				Success:            successes[i],
+				TokensEstimated:    i == 0,
 			}))
 		}
 
@@ -205,6 +209,7 @@ func TestGetStepPerformanceStats(t *testing.T) {
 		assert.Equal(t, int64(3000), stats.MaxDurationMs)
 		assert.Equal(t, 200, stats.AvgTokensUsed)
 		assert.Equal(t, 600, stats.TotalTokensUsed)
+		assert.Equal(t, 1, stats.EstimatedRuns)
 		assert.True(t, stats.TokenBurnRate > 0)
 	})
 
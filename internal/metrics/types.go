@@ -26,6 +26,9 @@ type PerformanceMetricRecord struct {
 	MemoryBytes        int64
 	Success            bool
 	ErrorMessage       string
+	// TokensEstimated is set when the adapter reported no usage and
+	// TokensUsed is a tokenizer estimate.
+	TokensEstimated bool
 }
 
 // PerformanceQueryOptions specifies filters for performance queries.
@@ -53,6 +56,8 @@ type StepPerformanceStats struct {
 	AvgArtifacts     int
 	LastRunAt        time.Time
 	TokenBurnRate    float64 // tokens per second
+	// EstimatedRuns counts the runs whose token counts were estimated.
+	EstimatedRuns int
 }
 
 // RetrospectiveRecord holds metadata for a stored retrospective.
@@ -7,14 +7,18 @@ import (
 
 // FlavourInfo holds detected project flavour metadata.
 type FlavourInfo struct {
-	Flavour       string
-	Language      string
-	TestCommand   string
-	LintCommand   string
-	BuildCommand  string
-	FormatCommand string
-	SourceGlob    string
-	Skill         string
+	Flavour          string
+	Language         string
+	TestCommand      string
+	LintCommand      string
+	BuildCommand     string
+	FormatCommand    string
+	TypecheckCommand string
+	RunCommand       string
+	CoverageCommand  string
+	PackageManager   string
+	SourceGlob       string
+	Skill            string
 }
 
 // FlavourRule defines detection criteria for a project flavour.
@@ -34,182 +38,232 @@ var flavourRules = []FlavourRule{
 	{
 		Markers: []string{"build.gradle.kts", "*.kt"},
 		Info: FlavourInfo{
-			Flavour:       "kotlin",
-			Language:      "kotlin",
-			TestCommand:   "gradle test",
-			LintCommand:   "gradle detekt",
-			BuildCommand:  "gradle build",
-			FormatCommand: "gradle ktlintCheck",
-			SourceGlob:    "*.kt",
-			Skill:         "kotlin",
+			Flavour:          "kotlin",
+			Language:         "kotlin",
+			TestCommand:      "gradle test",
+			LintCommand:      "gradle detekt",
+			BuildCommand:     "gradle build",
+			FormatCommand:    "gradle ktlintCheck",
+			TypecheckCommand: "gradle compileKotlin",
+			RunCommand:       "gradle run",
+			CoverageCommand:  "gradle jacocoTestReport",
+			PackageManager:   "gradle",
+			SourceGlob:       "*.kt",
+			Skill:            "kotlin",
 		},
 	},
 	// Flutter (pubspec.yaml with .metadata marker)
 	{
 		Markers: []string{"pubspec.yaml", ".metadata"},
 		Info: FlavourInfo{
-			Flavour:       "flutter",
-			Language:      "dart",
-			TestCommand:   "flutter test",
-			LintCommand:   "dart analyze",
-			BuildCommand:  "flutter build",
-			FormatCommand: "dart format --set-exit-if-changed .",
-			SourceGlob:    "*.dart",
-			Skill:         "dart",
+			Flavour:          "flutter",
+			Language:         "dart",
+			TestCommand:      "flutter test",
+			LintCommand:      "dart analyze",
+			BuildCommand:     "flutter build",
+			FormatCommand:    "dart format --set-exit-if-changed .",
+			TypecheckCommand: "flutter analyze",
+			RunCommand:       "flutter run",
+			CoverageCommand:  "flutter test --coverage",
+			PackageManager:   "pub",
+			SourceGlob:       "*.dart",
+			Skill:            "dart",
 		},
 	},
 	// Go
 	{
 		Markers: []string{"go.mod"},
 		Info: FlavourInfo{
-			Flavour:       "go",
-			Language:      "go",
-			TestCommand:   "go test ./...",
-			LintCommand:   "go vet ./...",
-			BuildCommand:  "go build ./...",
-			FormatCommand: "gofmt -l .",
-			SourceGlob:    "*.go",
-			Skill:         "golang",
+			Flavour:          "go",
+			Language:         "go",
+			TestCommand:      "go test ./...",
+			LintCommand:      "go vet ./...",
+			BuildCommand:     "go build ./...",
+			FormatCommand:    "gofmt -l .",
+			TypecheckCommand: "go vet ./...",
+			RunCommand:       "go run .",
+			CoverageCommand:  "go test -coverprofile=coverage.out ./...",
+			PackageManager:   "go",
+			SourceGlob:       "*.go",
+			Skill:            "golang",
 		},
 	},
 	// Rust
 	{
 		Markers: []string{"Cargo.toml"},
 		Info: FlavourInfo{
-			Flavour:       "rust",
-			Language:      "rust",
-			TestCommand:   "cargo test",
-			LintCommand:   "cargo clippy -- -D warnings",
-			BuildCommand:  "cargo build",
-			FormatCommand: "cargo fmt -- --check",
-			SourceGlob:    "*.rs",
-			Skill:         "rust",
+			Flavour:          "rust",
+			Language:         "rust",
+			TestCommand:      "cargo test",
+			LintCommand:      "cargo clippy -- -D warnings",
+			BuildCommand:     "cargo build",
+			FormatCommand:    "cargo fmt -- --check",
+			TypecheckCommand: "cargo check",
+			RunCommand:       "cargo run",
+			CoverageCommand:  "cargo llvm-cov",
+			PackageManager:   "cargo",
+			SourceGlob:       "*.rs",
+			Skill:            "rust",
 		},
 	},
 	// Deno (deno.json) — before Node/Bun: Deno projects may also have package.json for npm compat
 	{
 		Markers: []string{"deno.json"},
 		Info: FlavourInfo{
-			Flavour:       "deno",
-			Language:      "typescript",
-			TestCommand:   "deno test",
-			LintCommand:   "deno lint",
-			BuildCommand:  "deno compile",
-			FormatCommand: "deno fmt --check",
-			SourceGlob:    "*.{ts,tsx}",
-			Skill:         "typescript",
+			Flavour:          "deno",
+			Language:         "typescript",
+			TestCommand:      "deno test",
+			LintCommand:      "deno lint",
+			BuildCommand:     "deno compile",
+			FormatCommand:    "deno fmt --check",
+			TypecheckCommand: "deno check .",
+			RunCommand:       "deno task start",
+			CoverageCommand:  "deno test --coverage",
+			PackageManager:   "deno",
+			SourceGlob:       "*.{ts,tsx}",
+			Skill:            "typescript",
 		},
 	},
 	// Deno (deno.jsonc) — before Node/Bun: Deno projects may also have package.json for npm compat
 	{
 		Markers: []string{"deno.jsonc"},
 		Info: FlavourInfo{
-			Flavour:       "deno",
-			Language:      "typescript",
-			TestCommand:   "deno test",
-			LintCommand:   "deno lint",
-			BuildCommand:  "deno compile",
-			FormatCommand: "deno fmt --check",
-			SourceGlob:    "*.{ts,tsx}",
-			Skill:         "typescript",
+			Flavour:          "deno",
+			Language:         "typescript",
+			TestCommand:      "deno test",
+			LintCommand:      "deno lint",
+			BuildCommand:     "deno compile",
+			FormatCommand:    "deno fmt --check",
+			TypecheckCommand: "deno check .",
+			RunCommand:       "deno task start",
+			CoverageCommand:  "deno test --coverage",
+			PackageManager:   "deno",
+			SourceGlob:       "*.{ts,tsx}",
+			Skill:            "typescript",
 		},
 	},
 	// Bun (bun.lock)
 	{
 		Markers: []string{"package.json", "bun.lock"},
 		Info: FlavourInfo{
-			Flavour:       "bun",
-			Language:      "javascript",
-			TestCommand:   "bun test",
-			LintCommand:   "bun lint",
-			BuildCommand:  "bun run build",
-			FormatCommand: "bun format",
-			SourceGlob:    "*.{js,jsx,ts,tsx}",
-			Skill:         "javascript",
+			Flavour:          "bun",
+			Language:         "javascript",
+			TestCommand:      "bun test",
+			LintCommand:      "bun lint",
+			BuildCommand:     "bun run build",
+			FormatCommand:    "bun format",
+			TypecheckCommand: "bunx tsc --noEmit",
+			RunCommand:       "bun run start",
+			CoverageCommand:  "bun test --coverage",
+			PackageManager:   "bun",
+			SourceGlob:       "*.{js,jsx,ts,tsx}",
+			Skill:            "javascript",
 		},
 	},
 	// Bun (bun.lockb)
 	{
 		Markers: []string{"package.json", "bun.lockb"},
 		Info: FlavourInfo{
-			Flavour:       "bun",
-			Language:      "javascript",
-			TestCommand:   "bun test",
-			LintCommand:   "bun lint",
-			BuildCommand:  "bun run build",
-			FormatCommand: "bun format",
-			SourceGlob:    "*.{js,jsx,ts,tsx}",
-			Skill:         "javascript",
+			Flavour:          "bun",
+			Language:         "javascript",
+			TestCommand:      "bun test",
+			LintCommand:      "bun lint",
+			BuildCommand:     "bun run build",
+			FormatCommand:    "bun format",
+			TypecheckCommand: "bunx tsc --noEmit",
+			RunCommand:       "bun run start",
+			CoverageCommand:  "bun test --coverage",
+			PackageManager:   "bun",
+			SourceGlob:       "*.{js,jsx,ts,tsx}",
+			Skill:            "javascript",
 		},
 	},
 	// Node pnpm
 	{
 		Markers: []string{"package.json", "pnpm-lock.yaml"},
 		Info: FlavourInfo{
-			Flavour:       "node-pnpm",
-			Language:      "javascript",
-			TestCommand:   "pnpm test",
-			LintCommand:   "pnpm lint",
-			BuildCommand:  "pnpm build",
-			FormatCommand: "pnpm format",
-			SourceGlob:    "*.{js,jsx,ts,tsx}",
-			Skill:         "javascript",
+			Flavour:          "node-pnpm",
+			Language:         "javascript",
+			TestCommand:      "pnpm test",
+			LintCommand:      "pnpm lint",
+			BuildCommand:     "pnpm build",
+			FormatCommand:    "pnpm format",
+			TypecheckCommand: "pnpm exec tsc --noEmit",
+			RunCommand:       "pnpm start",
+			CoverageCommand:  "pnpm test -- --coverage",
+			PackageManager:   "pnpm",
+			SourceGlob:       "*.{js,jsx,ts,tsx}",
+			Skill:            "javascript",
 		},
 	},
 	// Node yarn
 	{
 		Markers: []string{"package.json", "yarn.lock"},
 		Info: FlavourInfo{
-			Flavour:       "node-yarn",
-			Language:      "javascript",
-			TestCommand:   "yarn test",
-			LintCommand:   "yarn lint",
-			BuildCommand:  "yarn build",
-			FormatCommand: "yarn format",
-			SourceGlob:    "*.{js,jsx,ts,tsx}",
-			Skill:         "javascript",
+			Flavour:          "node-yarn",
+			Language:         "javascript",
+			TestCommand:      "yarn test",
+			LintCommand:      "yarn lint",
+			BuildCommand:     "yarn build",
+			FormatCommand:    "yarn format",
+			TypecheckCommand: "yarn tsc --noEmit",
+			RunCommand:       "yarn start",
+			CoverageCommand:  "yarn test --coverage",
+			PackageManager:   "yarn",
+			SourceGlob:       "*.{js,jsx,ts,tsx}",
+			Skill:            "javascript",
 		},
 	},
 	// Node (generic)
 	{
 		Markers: []string{"package.json"},
 		Info: FlavourInfo{
-			Flavour:       "node",
-			Language:      "javascript",
-			TestCommand:   "npm test",
-			LintCommand:   "npm run lint",
-			BuildCommand:  "npm run build",
-			FormatCommand: "npm run format",
-			SourceGlob:    "*.{js,jsx,ts,tsx}",
-			Skill:         "javascript",
+			Flavour:          "node",
+			Language:         "javascript",
+			TestCommand:      "npm test",
+			LintCommand:      "npm run lint",
+			BuildCommand:     "npm run build",
+			FormatCommand:    "npm run format",
+			TypecheckCommand: "npx tsc --noEmit",
+			RunCommand:       "npm start",
+			CoverageCommand:  "npm test -- --coverage",
+			PackageManager:   "npm",
+			SourceGlob:       "*.{js,jsx,ts,tsx}",
+			Skill:            "javascript",
 		},
 	},
 	// Python (pyproject.toml)
 	{
 		Markers: []string{"pyproject.toml"},
 		Info: FlavourInfo{
-			Flavour:       "python",
-			Language:      "python",
-			TestCommand:   "pytest",
-			LintCommand:   "ruff check .",
-			BuildCommand:  "",
-			FormatCommand: "ruff format --check .",
-			SourceGlob:    "*.py",
-			Skill:         "python",
+			Flavour:          "python",
+			Language:         "python",
+			TestCommand:      "pytest",
+			LintCommand:      "ruff check .",
+			BuildCommand:     "",
+			FormatCommand:    "ruff format --check .",
+			TypecheckCommand: "mypy .",
+			CoverageCommand:  "pytest --cov --cov-report=xml",
+			PackageManager:   "pip",
+			SourceGlob:       "*.py",
+			Skill:            "python",
 		},
 	},
 	// Python legacy (setup.py)
 	{
 		Markers: []string{"setup.py"},
 		Info: FlavourInfo{
-			Flavour:       "python-legacy",
-			Language:      "python",
-			TestCommand:   "python -m pytest",
-			LintCommand:   "flake8",
-			BuildCommand:  "python setup.py build",
-			FormatCommand: "black --check .",
-			SourceGlob:    "*.py",
-			Skill:         "python",
+			Flavour:          "python-legacy",
+			Language:         "python",
+			TestCommand:      "python -m pytest",
+			LintCommand:      "flake8",
+			BuildCommand:     "python setup.py build",
+			FormatCommand:    "black --check .",
+			TypecheckCommand: "mypy .",
+			CoverageCommand:  "python -m pytest --cov --cov-report=xml",
+			PackageManager:   "pip",
+			SourceGlob:       "*.py",
+			Skill:            "python",
 		},
 	},
 	// Python legacy (requirements.txt, no setup.py)
@@ -217,112 +271,143 @@ var flavourRules = []FlavourRule{
 		Markers:  []string{"requirements.txt"},
 		Excludes: []string{"setup.py"},
 		Info: FlavourInfo{
-			Flavour:       "python-legacy",
-			Language:      "python",
-			TestCommand:   "python -m pytest",
-			LintCommand:   "flake8",
-			BuildCommand:  "",
-			FormatCommand: "black --check .",
-			SourceGlob:    "*.py",
-			Skill:         "python",
+			Flavour:          "python-legacy",
+			Language:         "python",
+			TestCommand:      "python -m pytest",
+			LintCommand:      "flake8",
+			BuildCommand:     "",
+			FormatCommand:    "black --check .",
+			TypecheckCommand: "mypy .",
+			CoverageCommand:  "python -m pytest --cov --cov-report=xml",
+			PackageManager:   "pip",
+			SourceGlob:       "*.py",
+			Skill:            "python",
 		},
 	},
 	// C# (.csproj glob)
 	{
 		Markers: []string{"*.csproj"},
 		Info: FlavourInfo{
-			Flavour:       "csharp",
-			Language:      "csharp",
-			TestCommand:   "dotnet test",
-			LintCommand:   "dotnet format --verify-no-changes",
-			BuildCommand:  "dotnet build",
-			FormatCommand: "dotnet format",
-			SourceGlob:    "*.cs",
-			Skill:         "csharp",
+			Flavour:          "csharp",
+			Language:         "csharp",
+			TestCommand:      "dotnet test",
+			LintCommand:      "dotnet format --verify-no-changes",
+			BuildCommand:     "dotnet build",
+			FormatCommand:    "dotnet format",
+			TypecheckCommand: "dotnet build",
+			RunCommand:       "dotnet run",
+			CoverageCommand:  "dotnet test --collect:\"XPlat Code Coverage\"",
+			PackageManager:   "dotnet",
+			SourceGlob:       "*.cs",
+			Skill:            "csharp",
 		},
 	},
 	// C# (.sln glob)
 	{
 		Markers: []string{"*.sln"},
 		Info: FlavourInfo{
-			Flavour:       "csharp",
-			Language:      "csharp",
-			TestCommand:   "dotnet test",
-			LintCommand:   "dotnet format --verify-no-changes",
-			BuildCommand:  "dotnet build",
-			FormatCommand: "dotnet format",
-			SourceGlob:    "*.cs",
-			Skill:         "csharp",
+			Flavour:          "csharp",
+			Language:         "csharp",
+			TestCommand:      "dotnet test",
+			LintCommand:      "dotnet format --verify-no-changes",
+			BuildCommand:     "dotnet build",
+			FormatCommand:    "dotnet format",
+			TypecheckCommand: "dotnet build",
+			RunCommand:       "dotnet run",
+			CoverageCommand:  "dotnet test --collect:\"XPlat Code Coverage\"",
+			PackageManager:   "dotnet",
+			SourceGlob:       "*.cs",
+			Skill:            "csharp",
 		},
 	},
 	// Java Maven
 	{
 		Markers: []string{"pom.xml"},
 		Info: FlavourInfo{
-			Flavour:       "java-maven",
-			Language:      "java",
-			TestCommand:   "mvn test",
-			LintCommand:   "",
-			BuildCommand:  "mvn package",
-			FormatCommand: "",
-			SourceGlob:    "*.java",
-			Skill:         "java",
+			Flavour:          "java-maven",
+			Language:         "java",
+			TestCommand:      "mvn test",
+			LintCommand:      "",
+			BuildCommand:     "mvn package",
+			FormatCommand:    "",
+			TypecheckCommand: "mvn compile",
+			RunCommand:       "mvn exec:java",
+			CoverageCommand:  "mvn test jacoco:report",
+			PackageManager:   "maven",
+			SourceGlob:       "*.java",
+			Skill:            "java",
 		},
 	},
 	// Java Gradle (build.gradle)
 	{
 		Markers: []string{"build.gradle"},
 		Info: FlavourInfo{
-			Flavour:       "java-gradle",
-			Language:      "java",
-			TestCommand:   "gradle test",
-			LintCommand:   "",
-			BuildCommand:  "gradle build",
-			FormatCommand: "",
-			SourceGlob:    "*.java",
-			Skill:         "java",
+			Flavour:          "java-gradle",
+			Language:         "java",
+			TestCommand:      "gradle test",
+			LintCommand:      "",
+			BuildCommand:     "gradle build",
+			FormatCommand:    "",
+			TypecheckCommand: "gradle compileJava",
+			RunCommand:       "gradle run",
+			CoverageCommand:  "gradle jacocoTestReport",
+			PackageManager:   "gradle",
+			SourceGlob:       "*.java",
+			Skill:            "java",
 		},
 	},
 	// Java Gradle (build.gradle.kts, fallback after kotlin rule above)
 	{
 		Markers: []string{"build.gradle.kts"},
 		Info: FlavourInfo{
-			Flavour:       "java-gradle",
-			Language:      "java",
-			TestCommand:   "gradle test",
-			LintCommand:   "",
-			BuildCommand:  "gradle build",
-			FormatCommand: "",
-			SourceGlob:    "*.java",
-			Skill:         "java",
+			Flavour:          "java-gradle",
+			Language:         "java",
+			TestCommand:      "gradle test",
+			LintCommand:      "",
+			BuildCommand:     "gradle build",
+			FormatCommand:    "",
+			TypecheckCommand: "gradle compileJava",
+			RunCommand:       "gradle run",
+			CoverageCommand:  "gradle jacocoTestReport",
+			PackageManager:   "gradle",
+			SourceGlob:       "*.java",
+			Skill:            "java",
 		},
 	},
 	// Elixir
 	{
 		Markers: []string{"mix.exs"},
 		Info: FlavourInfo{
-			Flavour:       "elixir",
-			Language:      "elixir",
-			TestCommand:   "mix test",
-			LintCommand:   "mix credo",
-			BuildCommand:  "mix compile",
-			FormatCommand: "mix format --check-formatted",
-			SourceGlob:    "*.ex",
-			Skill:         "elixir",
+			Flavour:          "elixir",
+			Language:         "elixir",
+			TestCommand:      "mix test",
+			LintCommand:      "mix credo",
+			BuildCommand:     "mix compile",
+			FormatCommand:    "mix format --check-formatted",
+			TypecheckCommand: "mix dialyzer",
+			RunCommand:       "mix run",
+			CoverageCommand:  "mix test --cover",
+			PackageManager:   "mix",
+			SourceGlob:       "*.ex",
+			Skill:            "elixir",
 		},
 	},
 	// Dart (pubspec.yaml, no .metadata — flutter already matched above)
 	{
 		Markers: []string{"pubspec.yaml"},
 		Info: FlavourInfo{
-			Flavour:       "dart",
-			Language:      "dart",
-			TestCommand:   "dart test",
-			LintCommand:   "dart analyze",
-			BuildCommand:  "dart compile exe",
-			FormatCommand: "dart format --set-exit-if-changed .",
-			SourceGlob:    "*.dart",
-			Skill:         "dart",
+			Flavour:          "dart",
+			Language:         "dart",
+			TestCommand:      "dart test",
+			LintCommand:      "dart analyze",
+			BuildCommand:     "dart compile exe",
+			FormatCommand:    "dart format --set-exit-if-changed .",
+			TypecheckCommand: "dart analyze",
+			RunCommand:       "dart run",
+			CoverageCommand:  "dart test --coverage=coverage",
+			PackageManager:   "pub",
+			SourceGlob:       "*.dart",
+			Skill:            "dart",
 		},
 	},
 	// C++ CMake
@@ -343,126 +428,154 @@ var flavourRules = []FlavourRule{
 	{
 		Markers: []string{"composer.json"},
 		Info: FlavourInfo{
-			Flavour:       "php",
-			Language:      "php",
-			TestCommand:   "vendor/bin/phpunit",
-			LintCommand:   "vendor/bin/phpstan analyse",
-			BuildCommand:  "",
-			FormatCommand: "vendor/bin/php-cs-fixer fix --dry-run",
-			SourceGlob:    "*.php",
-			Skill:         "php",
+			Flavour:          "php",
+			Language:         "php",
+			TestCommand:      "vendor/bin/phpunit",
+			LintCommand:      "vendor/bin/phpstan analyse",
+			BuildCommand:     "",
+			FormatCommand:    "vendor/bin/php-cs-fixer fix --dry-run",
+			TypecheckCommand: "vendor/bin/phpstan analyse",
+			CoverageCommand:  "vendor/bin/phpunit --coverage-text",
+			PackageManager:   "composer",
+			SourceGlob:       "*.php",
+			Skill:            "php",
 		},
 	},
 	// Ruby
 	{
 		Markers: []string{"Gemfile"},
 		Info: FlavourInfo{
-			Flavour:       "ruby",
-			Language:      "ruby",
-			TestCommand:   "bundle exec rspec",
-			LintCommand:   "bundle exec rubocop",
-			BuildCommand:  "",
-			FormatCommand: "bundle exec rubocop -a",
-			SourceGlob:    "*.rb",
-			Skill:         "ruby",
+			Flavour:        "ruby",
+			Language:       "ruby",
+			TestCommand:    "bundle exec rspec",
+			LintCommand:    "bundle exec rubocop",
+			BuildCommand:   "",
+			FormatCommand:  "bundle exec rubocop -a",
+			PackageManager: "bundler",
+			SourceGlob:     "*.rb",
+			Skill:          "ruby",
 		},
 	},
 	// Swift
 	{
 		Markers: []string{"Package.swift"},
 		Info: FlavourInfo{
-			Flavour:       "swift",
-			Language:      "swift",
-			TestCommand:   "swift test",
-			LintCommand:   "swiftlint",
-			BuildCommand:  "swift build",
-			FormatCommand: "swift format",
-			SourceGlob:    "*.swift",
-			Skill:         "swift",
+			Flavour:          "swift",
+			Language:         "swift",
+			TestCommand:      "swift test",
+			LintCommand:      "swiftlint",
+			BuildCommand:     "swift build",
+			FormatCommand:    "swift format",
+			TypecheckCommand: "swift build",
+			RunCommand:       "swift run",
+			CoverageCommand:  "swift test --enable-code-coverage",
+			PackageManager:   "swiftpm",
+			SourceGlob:       "*.swift",
+			Skill:            "swift",
 		},
 	},
 	// Zig (build.zig)
 	{
 		Markers: []string{"build.zig"},
 		Info: FlavourInfo{
-			Flavour:       "zig",
-			Language:      "zig",
-			TestCommand:   "zig build test",
-			LintCommand:   "",
-			BuildCommand:  "zig build",
-			FormatCommand: "zig fmt",
-			SourceGlob:    "*.zig",
-			Skill:         "zig",
+			Flavour:        "zig",
+			Language:       "zig",
+			TestCommand:    "zig build test",
+			LintCommand:    "",
+			BuildCommand:   "zig build",
+			FormatCommand:  "zig fmt",
+			RunCommand:     "zig build run",
+			PackageManager: "zig",
+			SourceGlob:     "*.zig",
+			Skill:          "zig",
 		},
 	},
 	// Zig (zig.zon)
 	{
 		Markers: []string{"zig.zon"},
 		Info: FlavourInfo{
-			Flavour:       "zig",
-			Language:      "zig",
-			TestCommand:   "zig build test",
-			LintCommand:   "",
-			BuildCommand:  "zig build",
-			FormatCommand: "zig fmt",
-			SourceGlob:    "*.zig",
-			Skill:         "zig",
+			Flavour:        "zig",
+			Language:       "zig",
+			TestCommand:    "zig build test",
+			LintCommand:    "",
+			BuildCommand:   "zig build",
+			FormatCommand:  "zig fmt",
+			RunCommand:     "zig build run",
+			PackageManager: "zig",
+			SourceGlob:     "*.zig",
+			Skill:          "zig",
 		},
 	},
 	// Scala
 	{
 		Markers: []string{"build.sbt"},
 		Info: FlavourInfo{
-			Flavour:       "scala",
-			Language:      "scala",
-			TestCommand:   "sbt test",
-			LintCommand:   "",
-			BuildCommand:  "sbt compile",
-			FormatCommand: "sbt scalafmtCheck",
-			SourceGlob:    "*.scala",
-			Skill:         "scala",
+			Flavour:          "scala",
+			Language:         "scala",
+			TestCommand:      "sbt test",
+			LintCommand:      "",
+			BuildCommand:     "sbt compile",
+			FormatCommand:    "sbt scalafmtCheck",
+			TypecheckCommand: "sbt compile",
+			RunCommand:       "sbt run",
+			CoverageCommand:  "sbt coverage test coverageReport",
+			PackageManager:   "sbt",
+			SourceGlob:       "*.scala",
+			Skill:            "scala",
 		},
 	},
 	// Haskell (cabal glob)
 	{
 		Markers: []string{"*.cabal"},
 		Info: FlavourInfo{
-			Flavour:       "haskell",
-			Language:      "haskell",
-			TestCommand:   "cabal test",
-			LintCommand:   "hlint .",
-			BuildCommand:  "cabal build",
-			FormatCommand: "ormolu --check",
-			SourceGlob:    "*.hs",
-			Skill:         "haskell",
+			Flavour:          "haskell",
+			Language:         "haskell",
+			TestCommand:      "cabal test",
+			LintCommand:      "hlint .",
+			BuildCommand:     "cabal build",
+			FormatCommand:    "ormolu --check",
+			TypecheckCommand: "cabal build",
+			RunCommand:       "cabal run",
+			CoverageCommand:  "cabal test --enable-coverage",
+			PackageManager:   "cabal",
+			SourceGlob:       "*.hs",
+			Skill:            "haskell",
 		},
 	},
 	// Haskell (cabal.project)
 	{
 		Markers: []string{"cabal.project"},
 		Info: FlavourInfo{
-			Flavour:       "haskell",
-			Language:      "haskell",
-			TestCommand:   "cabal test",
-			LintCommand:   "hlint .",
-			BuildCommand:  "cabal build",
-			FormatCommand: "ormolu --check",
-			SourceGlob:    "*.hs",
-			Skill:         "haskell",
+			Flavour:          "haskell",
+			Language:         "haskell",
+			TestCommand:      "cabal test",
+			LintCommand:      "hlint .",
+			BuildCommand:     "cabal build",
+			FormatCommand:    "ormolu --check",
+			TypecheckCommand: "cabal build",
+			RunCommand:       "cabal run",
+			CoverageCommand:  "cabal test --enable-coverage",
+			PackageManager:   "cabal",
+			SourceGlob:       "*.hs",
+			Skill:            "haskell",
 		},
 	},
 	// Haskell Stack
 	{
 		Markers: []string{"stack.yaml"},
 		Info: FlavourInfo{
-			Flavour:       "haskell-stack",
-			Language:      "haskell",
-			TestCommand:   "stack test",
-			LintCommand:   "hlint .",
-			BuildCommand:  "stack build",
-			FormatCommand: "ormolu --check",
-			SourceGlob:    "*.hs",
-			Skill:         "haskell",
+			Flavour:          "haskell-stack",
+			Language:         "haskell",
+			TestCommand:      "stack test",
+			LintCommand:      "hlint .",
+			BuildCommand:     "stack build",
+			FormatCommand:    "ormolu --check",
+			TypecheckCommand: "stack build",
+			RunCommand:       "stack run",
+			CoverageCommand:  "stack test --coverage",
+			PackageManager:   "stack",
+			SourceGlob:       "*.hs",
+			Skill:            "haskell",
 		},
 	},
 	// TypeScript standalone (no package.json)
@@ -470,14 +583,15 @@ var flavourRules = []FlavourRule{
 		Markers:  []string{"tsconfig.json"},
 		Excludes: []string{"package.json"},
 		Info: FlavourInfo{
-			Flavour:       "typescript-standalone",
-			Language:      "typescript",
-			TestCommand:   "tsc --noEmit",
-			LintCommand:   "eslint .",
-			BuildCommand:  "tsc",
-			FormatCommand: "prettier --check .",
-			SourceGlob:    "*.{ts,tsx}",
-			Skill:         "typescript",
+			Flavour:          "typescript-standalone",
+			Language:         "typescript",
+			TestCommand:      "tsc --noEmit",
+			LintCommand:      "eslint .",
+			BuildCommand:     "tsc",
+			FormatCommand:    "prettier --check .",
+			TypecheckCommand: "tsc --noEmit",
+			SourceGlob:       "*.{ts,tsx}",
+			Skill:            "typescript",
 		},
 	},
 	// Docker compose (multi-service project)
@@ -488,6 +602,7 @@ var flavourRules = []FlavourRule{
 			Language:     "",
 			TestCommand:  "docker compose run --rm test",
 			BuildCommand: "docker compose build",
+			RunCommand:   "docker compose up",
 			SourceGlob:   "",
 		},
 	},
@@ -498,6 +613,7 @@ var flavourRules = []FlavourRule{
 			Language:     "",
 			TestCommand:  "docker compose run --rm test",
 			BuildCommand: "docker compose build",
+			RunCommand:   "docker compose up",
 			SourceGlob:   "",
 		},
 	},
@@ -508,6 +624,7 @@ var flavourRules = []FlavourRule{
 			Language:     "",
 			TestCommand:  "docker compose run --rm test",
 			BuildCommand: "docker compose build",
+			RunCommand:   "docker compose up",
 			SourceGlob:   "",
 		},
 	},
@@ -518,6 +635,7 @@ var flavourRules = []FlavourRule{
 			Language:     "",
 			TestCommand:  "docker compose run --rm test",
 			BuildCommand: "docker compose build",
+			RunCommand:   "docker compose up",
 			SourceGlob:   "",
 		},
 	},
@@ -532,6 +650,7 @@ var flavourRules = []FlavourRule{
 			LintCommand:   "make lint",
 			BuildCommand:  "make build",
 			FormatCommand: "make format",
+			RunCommand:    "make run",
 			SourceGlob:    "",
 		},
 	},
@@ -558,9 +677,20 @@ func containsGlob(s string) bool {
 	return false
 }
 
+// pythonPackageManagers maps lock files to the Python package manager that
+// writes them. First match wins; pip is the fallback.
+var pythonPackageManagers = []struct{ marker, name string }{
+	{"uv.lock", "uv"},
+	{"poetry.lock", "poetry"},
+	{"pdm.lock", "pdm"},
+	{"Pipfile", "pipenv"},
+}
+
 // DetectFlavour inspects dir and returns the first matching FlavourInfo,
 // or nil if no rule matches. For node-related flavours the language field
-// is upgraded to "typescript" when a tsconfig.json is present.
+// is upgraded to "typescript" when a tsconfig.json is present; without one
+// there is nothing to typecheck. Python projects get the package manager
+// whose lock file is present.
 func DetectFlavour(dir string) *FlavourInfo {
 	for _, rule := range flavourRules {
 		if matchesRule(dir, rule) {
@@ -571,6 +701,15 @@ func DetectFlavour(dir string) *FlavourInfo {
 			case "node", "node-yarn", "node-pnpm", "bun":
 				if markerExists(dir, "tsconfig.json") {
 					info.Language = "typescript"
+				} else {
+					info.TypecheckCommand = ""
+				}
+			case "python", "python-legacy":
+				for _, pm := range pythonPackageManagers {
+					if markerExists(dir, pm.marker) {
+						info.PackageManager = pm.name
+						break
+					}
 				}
 			}
 
@@ -533,3 +533,76 @@ func TestDetectFlavour(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectFlavour_CommandCatalog(t *testing.T) {
+	tests := []struct {
+		name          string
+		files         []string
+		wantTypecheck string
+		wantRun       string
+		wantCoverage  string
+		wantPM        string
+	}{
+		{
+			name:          "go",
+			files:         []string{"go.mod"},
+			wantTypecheck: "go vet ./...",
+			wantRun:       "go run .",
+			wantCoverage:  "go test -coverprofile=coverage.out ./...",
+			wantPM:        "go",
+		},
+		{
+			name:          "typescript on pnpm",
+			files:         []string{"package.json", "pnpm-lock.yaml", "tsconfig.json"},
+			wantTypecheck: "pnpm exec tsc --noEmit",
+			wantRun:       "pnpm start",
+			wantCoverage:  "pnpm test -- --coverage",
+			wantPM:        "pnpm",
+		},
+		{
+			name:         "plain javascript has nothing to typecheck",
+			files:        []string{"package.json"},
+			wantRun:      "npm start",
+			wantCoverage: "npm test -- --coverage",
+			wantPM:       "npm",
+		},
+		{
+			name:          "python with uv",
+			files:         []string{"pyproject.toml", "uv.lock"},
+			wantTypecheck: "mypy .",
+			wantCoverage:  "pytest --cov --cov-report=xml",
+			wantPM:        "uv",
+		},
+		{
+			name:          "python with poetry",
+			files:         []string{"pyproject.toml", "poetry.lock"},
+			wantTypecheck: "mypy .",
+			wantCoverage:  "pytest --cov --cov-report=xml",
+			wantPM:        "poetry",
+		},
+		{
+			name:          "python without lock file",
+			files:         []string{"pyproject.toml"},
+			wantTypecheck: "mypy .",
+			wantCoverage:  "pytest --cov --cov-report=xml",
+			wantPM:        "pip",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tc.files {
+				createFile(t, filepath.Join(dir, f))
+			}
+
+			got := DetectFlavour(dir)
+
+			require.NotNil(t, got)
+			assert.Equal(t, tc.wantTypecheck, got.TypecheckCommand)
+			assert.Equal(t, tc.wantRun, got.RunCommand)
+			assert.Equal(t, tc.wantCoverage, got.CoverageCommand)
+			assert.Equal(t, tc.wantPM, got.PackageManager)
+		})
+	}
+}
@@ -253,6 +253,18 @@ func FlavourToProjectMap(fi *FlavourInfo) map[string]interface{} {
 	if fi.FormatCommand != "" {
 		m["format_command"] = fi.FormatCommand
 	}
+	if fi.TypecheckCommand != "" {
+		m["typecheck_command"] = fi.TypecheckCommand
+	}
+	if fi.RunCommand != "" {
+		m["run_command"] = fi.RunCommand
+	}
+	if fi.CoverageCommand != "" {
+		m["coverage_command"] = fi.CoverageCommand
+	}
+	if fi.PackageManager != "" {
+		m["package_manager"] = fi.PackageManager
+	}
 	if fi.SourceGlob != "" {
 		m["source_glob"] = fi.SourceGlob
 	}
@@ -0,0 +1,95 @@
+package personaeval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Output is what a persona produced for one case.
+type Output struct {
+	Text     string
+	Tokens   int
+	Duration time.Duration
+}
+
+// Check applies a to out and returns one message per failed assertion.
+// Schema paths are resolved by the caller.
+func Check(a Assertions, out Output) []string {
+	var failures []string
+	lower := strings.ToLower(out.Text)
+	for _, want := range a.Contains {
+		if !strings.Contains(lower, strings.ToLower(want)) {
+			failures = append(failures, fmt.Sprintf("does not mention %q", want))
+		}
+	}
+	for _, unwanted := range a.NotContains {
+		if strings.Contains(lower, strings.ToLower(unwanted)) {
+			failures = append(failures, fmt.Sprintf("mentions %q", unwanted))
+		}
+	}
+
+	if a.JSON || a.JSONSchema != "" {
+		var doc any
+		if err := json.Unmarshal([]byte(stripCodeFence(out.Text)), &doc); err != nil {
+			failures = append(failures, fmt.Sprintf("response is not valid JSON: %v", err))
+		} else if a.JSONSchema != "" {
+			if err := validateSchema(a.JSONSchema, doc); err != nil {
+				failures = append(failures, err.Error())
+			}
+		}
+	}
+
+	if a.MaxDuration != "" {
+		if limit, err := time.ParseDuration(a.MaxDuration); err == nil && out.Duration > limit {
+			failures = append(failures, fmt.Sprintf("took %s, limit %s", out.Duration.Round(time.Millisecond), limit))
+		}
+	}
+	if a.MaxTokens > 0 && out.Tokens > a.MaxTokens {
+		failures = append(failures, fmt.Sprintf("used %d tokens, limit %d", out.Tokens, a.MaxTokens))
+	}
+	return failures
+}
+
+// stripCodeFence removes a single Markdown code fence wrapping text.
+func stripCodeFence(text string) string {
+	t := strings.TrimSpace(text)
+	if !strings.HasPrefix(t, "```") || !strings.HasSuffix(t, "```") || len(t) < 6 {
+		return t
+	}
+	t = strings.TrimSuffix(t, "```")
+	if nl := strings.IndexByte(t, '\n'); nl >= 0 {
+		t = t[nl+1:]
+	} else {
+		t = strings.TrimPrefix(t, "```")
+	}
+	return strings.TrimSpace(t)
+}
+
+// validateSchema validates doc against the JSON Schema file at path.
+func validateSchema(path string, doc any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read schema %s: %w", path, err)
+	}
+	var schemaDoc any
+	if err := json.Unmarshal(data, &schemaDoc); err != nil {
+		return fmt.Errorf("failed to parse schema %s: %w", path, err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(path, schemaDoc); err != nil {
+		return fmt.Errorf("failed to load schema %s: %w", path, err)
+	}
+	schema, err := compiler.Compile(path)
+	if err != nil {
+		return fmt.Errorf("failed to compile schema %s: %w", path, err)
+	}
+	if err := schema.Validate(doc); err != nil {
+		return fmt.Errorf("response does not match schema: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package personaeval
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	schema := filepath.Join("testdata", "review.schema.json")
+	tests := []struct {
+		name    string
+		a       Assertions
+		out     Output
+		wantErr []string
+	}{
+		{
+			name: "mentions case-insensitively",
+			a:    Assertions{Contains: []string{"sql injection"}, NotContains: []string{"LGTM"}},
+			out:  Output{Text: "Found a SQL Injection in handler.go"},
+		},
+		{
+			name:    "missing and unwanted mentions",
+			a:       Assertions{Contains: []string{"XSS"}, NotContains: []string{"lgtm"}},
+			out:     Output{Text: "LGTM"},
+			wantErr: []string{`does not mention "XSS"`, `mentions "lgtm"`},
+		},
+		{
+			name: "fenced JSON matches schema",
+			a:    Assertions{JSONSchema: schema},
+			out:  Output{Text: "```json\n{\"verdict\": \"approve\", \"findings\": []}\n```"},
+		},
+		{
+			name:    "JSON violates schema",
+			a:       Assertions{JSONSchema: schema},
+			out:     Output{Text: `{"verdict": "maybe", "findings": []}`},
+			wantErr: []string{"response does not match schema"},
+		},
+		{
+			name:    "not JSON",
+			a:       Assertions{JSON: true},
+			out:     Output{Text: "Looks fine to me"},
+			wantErr: []string{"response is not valid JSON"},
+		},
+		{
+			name:    "over limits",
+			a:       Assertions{MaxDuration: "1s", MaxTokens: 100},
+			out:     Output{Duration: 2 * time.Second, Tokens: 150},
+			wantErr: []string{"took 2s, limit 1s", "used 150 tokens, limit 100"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := Check(tt.a, tt.out)
+			assert.Len(t, failures, len(tt.wantErr), "failures: %v", failures)
+			for i, want := range tt.wantErr {
+				if i < len(failures) {
+					assert.Contains(t, failures[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package personaeval runs eval suites against a persona: each case sends a
+// prompt (optionally with read-only fixtures) through the persona and checks
+// the response against assertions, so persona prompt changes can be
+// regression-tested.
+package personaeval
@@ -0,0 +1,151 @@
+package personaeval
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/pipeline"
+)
+
+// CaseRunner sends one case's prompt to a persona. fixtures is the case's
+// fixture directory resolved against the suite file, or "" when it has none.
+type CaseRunner interface {
+	RunCase(ctx context.Context, persona string, c Case, fixtures string) (Output, error)
+}
+
+// CaseResult is the outcome of one case.
+type CaseResult struct {
+	Name       string   `json:"name"`
+	Passed     bool     `json:"passed"`
+	Failures   []string `json:"failures,omitempty"`
+	Error      string   `json:"error,omitempty"`
+	Tokens     int      `json:"tokens"`
+	DurationMs int64    `json:"duration_ms"`
+}
+
+// Report is the outcome of a suite run.
+type Report struct {
+	Persona string       `json:"persona"`
+	Passed  int          `json:"passed"`
+	Failed  int          `json:"failed"`
+	Cases   []CaseResult `json:"cases"`
+}
+
+// OK reports whether every case passed.
+func (r *Report) OK() bool { return r.Failed == 0 }
+
+// RunOptions tunes a suite run.
+type RunOptions struct {
+	// Only restricts the run to the named cases.
+	Only []string
+	// OnCase is called after each case completes.
+	OnCase func(CaseResult)
+}
+
+// Run executes the suite's cases in order. A case whose run fails is
+// reported as failed; the remaining cases still run unless ctx is cancelled.
+func Run(ctx context.Context, s *Suite, persona string, runner CaseRunner, opts RunOptions) *Report {
+	report := &Report{Persona: persona, Cases: []CaseResult{}}
+	selected := make(map[string]bool, len(opts.Only))
+	for _, name := range opts.Only {
+		selected[name] = true
+	}
+	for _, c := range s.Cases {
+		if len(selected) > 0 && !selected[c.Name] {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		result := CaseResult{Name: c.Name}
+		out, err := runner.RunCase(ctx, persona, c, s.Path(c.Fixtures))
+		result.Tokens = out.Tokens
+		result.DurationMs = out.Duration.Milliseconds()
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			a := s.AssertionsFor(c)
+			a.JSONSchema = s.Path(a.JSONSchema)
+			result.Failures = Check(a, out)
+			result.Passed = len(result.Failures) == 0
+		}
+		if result.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Cases = append(report.Cases, result)
+		if opts.OnCase != nil {
+			opts.OnCase(result)
+		}
+	}
+	return report
+}
+
+// responseArtifact is the output artifact each eval step writes its
+// response to.
+const responseArtifact = "response"
+
+// PipelineRunner runs each case as a single-step pipeline through the
+// regular executor, so the persona's system prompt, permissions, sandbox
+// and env apply exactly as in a pipeline run.
+type PipelineRunner struct {
+	Manifest *manifest.Manifest
+	Adapter  adapter.AdapterRunner
+	// Options are passed to every executor (emitter, workspace manager,
+	// model override, ...). Fixtures require a workspace manager.
+	Options []pipeline.ExecutorOption
+}
+
+// RunCase implements CaseRunner.
+func (r *PipelineRunner) RunCase(ctx context.Context, persona string, c Case, fixtures string) (Output, error) {
+	step := pipeline.Step{
+		ID:      "eval",
+		Persona: persona,
+		Memory:  pipeline.MemoryConfig{Strategy: "fresh"},
+		Exec:    pipeline.ExecConfig{Type: "prompt", Source: c.Prompt},
+		OutputArtifacts: []pipeline.ArtifactDef{
+			{Name: responseArtifact, Path: ".agents/output/eval-response.md"},
+		},
+	}
+	if fixtures != "" {
+		abs, err := filepath.Abs(fixtures)
+		if err != nil {
+			return Output{}, fmt.Errorf("failed to resolve fixtures: %w", err)
+		}
+		step.Workspace.Mount = []pipeline.Mount{{Source: abs, Target: "/fixtures", Mode: "readonly"}}
+	}
+	p := &pipeline.Pipeline{
+		Kind:     "WavePipeline",
+		Metadata: pipeline.PipelineMetadata{Name: "persona-eval", Description: fmt.Sprintf("Eval case %q for persona %s", c.Name, persona)},
+		Steps:    []pipeline.Step{step},
+	}
+
+	executor := pipeline.NewDefaultPipelineExecutor(r.Adapter, r.Options...)
+	start := time.Now()
+	err := executor.Execute(ctx, p, r.Manifest, c.Prompt)
+	out := Output{Tokens: executor.GetTotalTokens(), Duration: time.Since(start)}
+	if err != nil {
+		return out, err
+	}
+
+	execution := executor.LastExecution()
+	if execution == nil {
+		return out, fmt.Errorf("no execution recorded")
+	}
+	path := execution.ArtifactPaths[step.ID+":"+responseArtifact]
+	if path == "" {
+		return out, fmt.Errorf("persona produced no response")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return out, fmt.Errorf("failed to read response: %w", err)
+	}
+	out.Text = string(data)
+	return out, nil
+}
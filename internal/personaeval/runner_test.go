@@ -0,0 +1,79 @@
+package personaeval
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRunner returns canned outputs keyed by case name.
+type fakeRunner struct {
+	outputs  map[string]Output
+	fixtures map[string]string
+}
+
+func (f *fakeRunner) RunCase(_ context.Context, _ string, c Case, fixtures string) (Output, error) {
+	if f.fixtures == nil {
+		f.fixtures = map[string]string{}
+	}
+	f.fixtures[c.Name] = fixtures
+	out, ok := f.outputs[c.Name]
+	if !ok {
+		return Output{}, errors.New("adapter crashed")
+	}
+	return out, nil
+}
+
+func TestRun(t *testing.T) {
+	s, err := LoadSuite(filepath.Join("testdata", "reviewer.yaml"))
+	require.NoError(t, err)
+
+	runner := &fakeRunner{outputs: map[string]Output{
+		"flags-sql-injection": {Text: "SQL injection via string concatenation", Tokens: 1200, Duration: time.Second},
+	}}
+	var seen []string
+	report := Run(context.Background(), s, "reviewer", runner, RunOptions{
+		OnCase: func(r CaseResult) { seen = append(seen, r.Name) },
+	})
+
+	require.Len(t, report.Cases, 2)
+	assert.True(t, report.Cases[0].Passed)
+	assert.Equal(t, 1200, report.Cases[0].Tokens)
+	assert.False(t, report.Cases[1].Passed)
+	assert.Equal(t, "adapter crashed", report.Cases[1].Error)
+	assert.Equal(t, 1, report.Passed)
+	assert.Equal(t, 1, report.Failed)
+	assert.False(t, report.OK())
+	assert.Equal(t, []string{"flags-sql-injection", "structured-verdict"}, seen)
+	assert.Equal(t, filepath.Join("testdata", "fixtures", "sqli"), runner.fixtures["flags-sql-injection"])
+
+	only := Run(context.Background(), s, "reviewer", runner, RunOptions{Only: []string{"flags-sql-injection"}})
+	require.Len(t, only.Cases, 1)
+	assert.True(t, only.OK())
+}
+
+func TestPipelineRunner(t *testing.T) {
+	m := testutil.CreateTestManifest(t.TempDir())
+	mock := adaptertest.NewMockAdapter(
+		adaptertest.WithStdoutJSON(`{"verdict": "approve", "findings": []}`),
+		adaptertest.WithTokensUsed(321),
+	)
+	runner := &PipelineRunner{Manifest: m, Adapter: mock}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	out, err := runner.RunCase(ctx, "navigator", Case{Name: "verdict", Prompt: "Review the diff"}, "")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"verdict": "approve", "findings": []}`, out.Text)
+	assert.Equal(t, 321, out.Tokens)
+
+	failures := Check(Assertions{JSONSchema: filepath.Join("testdata", "review.schema.json")}, out)
+	assert.Empty(t, failures)
+}
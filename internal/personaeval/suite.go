@@ -0,0 +1,139 @@
+package personaeval
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Suite is a set of eval cases for one persona.
+type Suite struct {
+	// Persona is the persona the suite was written for. Optional; when set
+	// it must match the persona being evaluated.
+	Persona string `yaml:"persona,omitempty"`
+	// Defaults are assertions applied to every case.
+	Defaults Assertions `yaml:"defaults,omitempty"`
+	Cases    []Case     `yaml:"cases"`
+
+	// Dir is the directory of the suite file. Fixture and schema paths are
+	// resolved against it.
+	Dir string `yaml:"-"`
+}
+
+// Case is a single prompt sent to the persona.
+type Case struct {
+	Name   string `yaml:"name"`
+	Prompt string `yaml:"prompt"`
+	// Fixtures is a directory mounted read-only into the persona's
+	// workspace at /fixtures.
+	Fixtures string     `yaml:"fixtures,omitempty"`
+	Assert   Assertions `yaml:"assert,omitempty"`
+}
+
+// Assertions are the checks applied to a persona response.
+type Assertions struct {
+	// Contains lists strings the response must mention (case-insensitive).
+	Contains []string `yaml:"contains,omitempty"`
+	// NotContains lists strings the response must not mention (case-insensitive).
+	NotContains []string `yaml:"not_contains,omitempty"`
+	// JSON requires the response to be valid JSON. A single fenced code
+	// block around the JSON is accepted.
+	JSON bool `yaml:"json,omitempty"`
+	// JSONSchema is a JSON Schema file the response must validate against.
+	// Implies JSON.
+	JSONSchema string `yaml:"json_schema,omitempty"`
+	// MaxDuration caps the wall-clock time of the case (e.g. "90s").
+	MaxDuration string `yaml:"max_duration,omitempty"`
+	// MaxTokens caps the tokens used by the case.
+	MaxTokens int `yaml:"max_tokens,omitempty"`
+}
+
+// LoadSuite reads and validates the suite file at path.
+func LoadSuite(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suite: %w", err)
+	}
+	var s Suite
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to parse suite %s: %w", path, err)
+	}
+	s.Dir = filepath.Dir(path)
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid suite %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Validate checks that the suite has uniquely named cases with prompts and
+// well-formed assertions.
+func (s *Suite) Validate() error {
+	if len(s.Cases) == 0 {
+		return fmt.Errorf("suite has no cases")
+	}
+	if err := s.Defaults.validate(); err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+	seen := make(map[string]bool, len(s.Cases))
+	for i, c := range s.Cases {
+		if c.Name == "" {
+			return fmt.Errorf("case %d: name is required", i+1)
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("case %q: duplicate name", c.Name)
+		}
+		seen[c.Name] = true
+		if c.Prompt == "" {
+			return fmt.Errorf("case %q: prompt is required", c.Name)
+		}
+		if err := c.Assert.validate(); err != nil {
+			return fmt.Errorf("case %q: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+// Path resolves a suite-relative path.
+func (s *Suite) Path(p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(s.Dir, p)
+}
+
+// AssertionsFor returns the suite defaults overlaid with the case's own
+// assertions. String lists are combined; scalar limits set on the case win.
+func (s *Suite) AssertionsFor(c Case) Assertions {
+	a := s.Defaults
+	a.Contains = append(append([]string(nil), a.Contains...), c.Assert.Contains...)
+	a.NotContains = append(append([]string(nil), a.NotContains...), c.Assert.NotContains...)
+	a.JSON = a.JSON || c.Assert.JSON
+	if c.Assert.JSONSchema != "" {
+		a.JSONSchema = c.Assert.JSONSchema
+	}
+	if c.Assert.MaxDuration != "" {
+		a.MaxDuration = c.Assert.MaxDuration
+	}
+	if c.Assert.MaxTokens > 0 {
+		a.MaxTokens = c.Assert.MaxTokens
+	}
+	return a
+}
+
+func (a Assertions) validate() error {
+	if a.MaxDuration != "" {
+		if d, err := time.ParseDuration(a.MaxDuration); err != nil || d <= 0 {
+			return fmt.Errorf("max_duration %q: must be a positive duration like \"90s\"", a.MaxDuration)
+		}
+	}
+	if a.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens must not be negative")
+	}
+	return nil
+}
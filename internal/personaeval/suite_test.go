@@ -0,0 +1,53 @@
+package personaeval
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSuite(t *testing.T) {
+	s, err := LoadSuite(filepath.Join("testdata", "reviewer.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "reviewer", s.Persona)
+	require.Len(t, s.Cases, 2)
+	assert.Equal(t, filepath.Join("testdata", "fixtures", "sqli"), s.Path(s.Cases[0].Fixtures))
+
+	a := s.AssertionsFor(s.Cases[1])
+	assert.Equal(t, "2m", a.MaxDuration, "default applies")
+	assert.Equal(t, 5000, a.MaxTokens, "case limit wins")
+	assert.Equal(t, "review.schema.json", a.JSONSchema)
+}
+
+func TestLoadSuite_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no cases":       "persona: reviewer\n",
+		"unknown field":  "cases:\n  - name: a\n    prompt: p\n    asert: {}\n",
+		"missing name":   "cases:\n  - prompt: p\n",
+		"missing prompt": "cases:\n  - name: a\n",
+		"duplicate name": "cases:\n  - name: a\n    prompt: p\n  - name: a\n    prompt: q\n",
+		"bad duration":   "cases:\n  - name: a\n    prompt: p\n    assert:\n      max_duration: soon\n",
+		"negative limit": "defaults:\n  max_tokens: -1\ncases:\n  - name: a\n    prompt: p\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "suite.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			_, err := LoadSuite(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAssertionsFor_CombinesLists(t *testing.T) {
+	s := &Suite{Defaults: Assertions{Contains: []string{"a"}, NotContains: []string{"x"}, JSON: true}}
+	c := Case{Assert: Assertions{Contains: []string{"b"}}}
+	a := s.AssertionsFor(c)
+	assert.Equal(t, []string{"a", "b"}, a.Contains)
+	assert.Equal(t, []string{"x"}, a.NotContains)
+	assert.True(t, a.JSON)
+	assert.Equal(t, []string{"a"}, s.Defaults.Contains, "defaults are not mutated")
+}
@@ -0,0 +1,173 @@
+package pipeline
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/state"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultAdapterCacheTTL is how long a cached adapter result stays valid
+// when the step does not set cache.ttl.
+const defaultAdapterCacheTTL = 24 * time.Hour
+
+// StepCacheConfig opts a step into the adapter response cache. A rerun
+// whose prompt, persona, model, injected artifacts and workspace contents
+// are identical to a cached run replays the stored result and output
+// artifact files instead of calling the adapter. Only temperature-0 runs
+// are cached. Other files the agent wrote are not replayed, so the cache
+// suits analysis steps whose product is their output artifacts.
+//
+// `cache: true` enables the cache with the default TTL; `cache: false`
+// keeps it off, as omitting the block does.
+type StepCacheConfig struct {
+	TTL      string `yaml:"ttl,omitempty"` // Go duration; default 24h
+	Disabled bool   `yaml:"-"`             // set by `cache: false`
+}
+
+// UnmarshalYAML accepts a boolean as well as the ttl block.
+func (c *StepCacheConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var enabled bool
+		if err := node.Decode(&enabled); err != nil {
+			return fmt.Errorf("line %d: cache must be true, false or a block with ttl", node.Line)
+		}
+		*c = StepCacheConfig{Disabled: !enabled}
+		return nil
+	}
+	// Decoding through a custom unmarshaler drops the strict field check.
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if key := node.Content[i]; key.Value != "ttl" {
+			return fmt.Errorf("line %d: field %s not found in type pipeline.StepCacheConfig", key.Line, key.Value)
+		}
+	}
+	type plain StepCacheConfig
+	return node.Decode((*plain)(c))
+}
+
+// MarshalYAML writes a disabled cache back as `cache: false`.
+func (c StepCacheConfig) MarshalYAML() (interface{}, error) {
+	if c.Disabled {
+		return false, nil
+	}
+	type plain StepCacheConfig
+	return plain(c), nil
+}
+
+// enabled reports whether c opts the step into the cache.
+func (c *StepCacheConfig) enabled() bool {
+	return c != nil && !c.Disabled
+}
+
+// ttl returns the configured TTL, or the default.
+func (c *StepCacheConfig) ttl() time.Duration {
+	if c.TTL == "" {
+		return defaultAdapterCacheTTL
+	}
+	d, err := time.ParseDuration(c.TTL)
+	if err != nil {
+		return defaultAdapterCacheTTL
+	}
+	return d
+}
+
+// WithNoAdapterCache bypasses the adapter response cache for the run (CLI
+// --no-adapter-cache): cached steps call their adapter and nothing is stored.
+func WithNoAdapterCache() ExecutorOption {
+	return func(ex *DefaultPipelineExecutor) {
+		ex.noAdapterCache = true
+	}
+}
+
+// cachedStepRunner wraps runner in the adapter cache when step opts in.
+// Injected artifacts are part of the cache key and the step's file output
+// artifacts are replayed with the result: both usually live under
+// .agents/, which git and thus the workspace fingerprint ignore.
+func (e *DefaultPipelineExecutor) cachedStepRunner(execution *PipelineExecution, step *Step, runner adapter.AdapterRunner) adapter.AdapterRunner {
+	if !step.Cache.enabled() || e.noAdapterCache || e.store == nil {
+		return runner
+	}
+	var inputs, outputs []string
+	for _, ref := range step.Memory.InjectArtifacts {
+		name := ref.As
+		if name == "" {
+			name = ref.Artifact
+		}
+		inputs = append(inputs, filepath.Join(".agents", "artifacts", name))
+	}
+	for _, art := range step.OutputArtifacts {
+		if !art.IsStdoutArtifact() {
+			outputs = append(outputs, execution.Context.ResolveArtifactPath(art))
+		}
+	}
+	cache := &stateResultCache{
+		store:    e.store,
+		ttl:      step.Cache.ttl(),
+		pipeline: execution.Status.PipelineName,
+		stepID:   step.ID,
+	}
+	return adapter.NewCachingRunner(runner, cache).WithFiles(inputs, outputs)
+}
+
+// stateResultCache adapts the state store to adapter.ResultCache. Store
+// errors degrade to cache misses; the cache must never fail a step.
+type stateResultCache struct {
+	store    state.AdapterCacheStore
+	ttl      time.Duration
+	pipeline string
+	stepID   string
+}
+
+func (c *stateResultCache) Get(key string) (*adapter.CachedResult, bool) {
+	entry, err := c.store.GetAdapterCacheEntry(key, time.Now())
+	if err != nil || entry == nil {
+		return nil, false
+	}
+	return &adapter.CachedResult{
+		Stdout:        entry.Stdout,
+		ResultContent: entry.ResultContent,
+		Subtype:       entry.Subtype,
+		TokensIn:      entry.TokensIn,
+		TokensOut:     entry.TokensOut,
+		Files:         entry.Files,
+	}, true
+}
+
+func (c *stateResultCache) Put(key string, r adapter.CachedResult) {
+	now := time.Now()
+	_ = c.store.PutAdapterCacheEntry(state.AdapterCacheEntry{
+		Key:           key,
+		PipelineName:  c.pipeline,
+		StepID:        c.stepID,
+		Persona:       r.Persona,
+		Model:         r.Model,
+		Stdout:        r.Stdout,
+		ResultContent: r.ResultContent,
+		Subtype:       r.Subtype,
+		TokensIn:      r.TokensIn,
+		TokensOut:     r.TokensOut,
+		Files:         r.Files,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(c.ttl),
+	})
+}
+
+// validateStepCaches checks cache TTLs.
+func validateStepCaches(p *Pipeline) error {
+	for _, step := range p.Steps {
+		if !step.Cache.enabled() || step.Cache.TTL == "" {
+			continue
+		}
+		d, err := time.ParseDuration(step.Cache.TTL)
+		if err != nil {
+			return fmt.Errorf("step %q: invalid cache.ttl %q: %w", step.ID, step.Cache.TTL, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("step %q: cache.ttl must be positive, got %q", step.ID, step.Cache.TTL)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateStepCaches(t *testing.T) {
+	for ttl, wantErr := range map[string]bool{"": false, "6h": false, "soon": true, "-1h": true} {
+		p := &Pipeline{Steps: []Step{{ID: "scan", Cache: &StepCacheConfig{TTL: ttl}}}}
+		if err := validateStepCaches(p); (err != nil) != wantErr {
+			t.Errorf("ttl %q: err = %v, want error %v", ttl, err, wantErr)
+		}
+	}
+	if got := (&StepCacheConfig{}).ttl(); got != defaultAdapterCacheTTL {
+		t.Errorf("default ttl = %v", got)
+	}
+	if got := (&StepCacheConfig{TTL: "6h"}).ttl(); got != 6*time.Hour {
+		t.Errorf("ttl = %v, want 6h", got)
+	}
+}
+
+func TestStepCacheConfig_YAML(t *testing.T) {
+	var steps []Step
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- id: on
+  cache: true
+- id: off
+  cache: false
+- id: ttl
+  cache:
+    ttl: 6h
+- id: none
+`), &steps))
+	require.Len(t, steps, 4)
+	assert.True(t, steps[0].Cache.enabled())
+	assert.Equal(t, defaultAdapterCacheTTL, steps[0].Cache.ttl())
+	assert.False(t, steps[1].Cache.enabled())
+	assert.True(t, steps[2].Cache.enabled())
+	assert.Equal(t, 6*time.Hour, steps[2].Cache.ttl())
+	assert.False(t, steps[3].Cache.enabled())
+
+	var bad Step
+	assert.Error(t, yaml.Unmarshal([]byte("cache: sometimes"), &bad))
+	assert.Error(t, yaml.Unmarshal([]byte("cache:\n  tll: 6h"), &bad))
+
+	out, err := yaml.Marshal(steps[1].Cache)
+	require.NoError(t, err)
+	assert.Equal(t, "false\n", string(out))
+}
@@ -0,0 +1,81 @@
+package pipeline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/manifest"
+)
+
+// personaAdapterExitThreshold is how many consecutive non-zero exits of an
+// adapter in a persona's failover list make steps move on to the next one.
+const personaAdapterExitThreshold = 2
+
+// personaFailoverRunner wraps primary so the step fails over along the
+// persona's adapter list (`adapter: [claude, codex, ollama]`) when an
+// adapter is rate limited or keeps exiting non-zero. Exits are counted
+// across the run, so an adapter that failed the step's earlier attempts,
+// or other steps, is skipped. Each failover emits an adapter_fallback
+// event naming the adapter that takes over.
+func (e *DefaultPipelineExecutor) personaFailoverRunner(execution *PipelineExecution, step *Step, persona *manifest.Persona, personaName string, primary adapter.AdapterRunner) adapter.AdapterRunner {
+	return adapter.NewFallbackRunnerWithOptions(primary, persona.AdapterFallbacks, e.registry, adapter.FallbackOptions{
+		PrimaryName:   persona.Adapter,
+		ExitThreshold: personaAdapterExitThreshold,
+		Exits:         &execution.adapterExits,
+		Configure: func(name string, cfg adapter.AdapterRunConfig) adapter.AdapterRunConfig {
+			cfg.Adapter = name
+			cfg.Model = e.failoverModel(execution, step, persona, name)
+			if def := execution.Manifest.GetAdapter(name); def != nil {
+				cfg.OutputFormat = def.OutputFormat
+			}
+			return cfg
+		},
+		OnFallback: func(from, to, reason string) {
+			e.emit(event.Event{
+				Timestamp:  time.Now(),
+				PipelineID: execution.Status.ID,
+				StepID:     step.ID,
+				State:      "adapter_fallback",
+				Persona:    personaName,
+				Adapter:    to,
+				Model:      e.failoverModel(execution, step, persona, to),
+				Message:    fmt.Sprintf("adapter %s %s; falling back to %s", from, failoverReasonText(reason), to),
+			})
+		},
+	})
+}
+
+// failoverModel is the model a step runs when it fails over to adapterName.
+// A model tier maps through that adapter's tier_models; a literal model
+// belongs to the persona's own adapter, so the fallback runs its
+// default_model instead.
+func (e *DefaultPipelineExecutor) failoverModel(execution *PipelineExecution, step *Step, persona *manifest.Persona, adapterName string) string {
+	def := execution.Manifest.GetAdapter(adapterName)
+	if def == nil {
+		return ""
+	}
+	configured := e.modelOverride
+	if configured == "" {
+		configured = step.Model
+	}
+	if configured == "" {
+		configured = persona.Model
+	}
+	if model := def.TierModels[configured]; model != "" {
+		return model
+	}
+	return def.DefaultModel
+}
+
+func failoverReasonText(reason string) string {
+	switch reason {
+	case adapter.FallbackReasonRateLimit:
+		return "was rate limited"
+	case adapter.FallbackReasonRepeatedExits:
+		return fmt.Sprintf("exited non-zero %d times in a row", personaAdapterExitThreshold)
+	default:
+		return "failed"
+	}
+}
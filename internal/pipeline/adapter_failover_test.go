@@ -0,0 +1,90 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedAdapter returns a fixed result and records the configs it ran.
+type scriptedAdapter struct {
+	mu     sync.Mutex
+	result adapter.AdapterResult
+	runs   []adapter.AdapterRunConfig
+}
+
+func (a *scriptedAdapter) Run(_ context.Context, cfg adapter.AdapterRunConfig) (*adapter.AdapterResult, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.runs = append(a.runs, cfg)
+	result := a.result
+	result.Stdout = strings.NewReader(result.ResultContent)
+	return &result, nil
+}
+
+func TestPersonaAdapterFailover(t *testing.T) {
+	claude := &scriptedAdapter{result: adapter.AdapterResult{ExitCode: 1, FailureReason: adapter.FailureReasonRateLimit}}
+	codex := &scriptedAdapter{result: adapter.AdapterResult{ResultContent: "done", TokensUsed: 10}}
+	registry := adapter.NewAdapterRegistry(nil)
+	registry.RegisterOverride("claude", claude)
+	registry.RegisterOverride("codex", codex)
+
+	collector := testutil.NewEventCollector()
+	executor := NewDefaultPipelineExecutor(nil, WithEmitter(collector), WithRegistry(registry))
+
+	m := testutil.CreateTestManifest(t.TempDir())
+	m.Adapters["codex"] = manifest.Adapter{Binary: "codex", TierModels: map[string]string{"strongest": "gpt-5-codex"}}
+	craftsman := m.Personas["craftsman"]
+	craftsman.Model = "strongest"
+	craftsman.AdapterFallbacks = []string{"codex"}
+	m.Personas["craftsman"] = craftsman
+
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "failover-test"},
+		Steps:    []Step{{ID: "work", Persona: "craftsman", Exec: ExecConfig{Source: "do work"}}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, executor.Execute(ctx, p, m, "test"))
+
+	require.Len(t, claude.runs, 1)
+	require.Len(t, codex.runs, 1)
+	assert.Equal(t, "codex", codex.runs[0].Adapter)
+	assert.Equal(t, "gpt-5-codex", codex.runs[0].Model)
+
+	var fallback bool
+	for _, e := range collector.GetEventsByStep("work") {
+		if e.State == "adapter_fallback" {
+			fallback = true
+			assert.Equal(t, "codex", e.Adapter)
+			assert.Equal(t, "gpt-5-codex", e.Model)
+			assert.Equal(t, "adapter claude was rate limited; falling back to codex", e.Message)
+		}
+	}
+	assert.True(t, fallback, "failover must emit an adapter_fallback event")
+}
+
+func TestFailoverModel(t *testing.T) {
+	m := &manifest.Manifest{Adapters: map[string]manifest.Adapter{
+		"ollama": {TierModels: map[string]string{"cheapest": "llama3.2"}, DefaultModel: "qwen2.5-coder:14b"},
+	}}
+	execution := &PipelineExecution{Manifest: m}
+	e := NewDefaultPipelineExecutor(nil)
+
+	tier := &manifest.Persona{Adapter: "claude", Model: "cheapest"}
+	assert.Equal(t, "llama3.2", e.failoverModel(execution, &Step{}, tier, "ollama"))
+
+	literal := &manifest.Persona{Adapter: "claude", Model: "claude-opus-4"}
+	assert.Equal(t, "qwen2.5-coder:14b", e.failoverModel(execution, &Step{}, literal, "ollama"),
+		"a literal model of the primary adapter must not reach the fallback")
+	assert.Equal(t, "qwen2.5-coder:14b", e.failoverModel(execution, &Step{Model: "balanced"}, tier, "ollama"))
+	assert.Empty(t, e.failoverModel(execution, &Step{}, tier, "missing"))
+}
@@ -0,0 +1,164 @@
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/manifest"
+)
+
+// adapterLogPath is where the raw adapter output of a step is kept:
+// <runtime.adapter_logs.dir>/<run-id>/<step-id>.log. Matrix workers and
+// concurrency agents of a step each get their own log,
+// <step-id>.<worker>.log, so parallel transcripts don't interleave.
+func adapterLogPath(m *manifest.Manifest, runID, stepID, worker string) string {
+	dir := ".agents/logs"
+	if m != nil {
+		dir = m.Runtime.AdapterLogs.GetDir()
+	}
+	name := strings.ReplaceAll(stepID, string(filepath.Separator), "_")
+	if worker != "" {
+		name += "." + worker
+	}
+	return filepath.Join(dir, runID, name+".log")
+}
+
+// adapterLogRef returns the step's raw adapter log for failure events, or ""
+// when the step produced none (command steps, adapters without a process).
+func adapterLogRef(execution *PipelineExecution, stepID string) string {
+	path := adapterLogPath(execution.Manifest, execution.Status.ID, stepID, execution.adapterLogWorker)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// rotatingLog captures one adapter run. The file is only created once the
+// adapter writes something, so steps whose adapter never spawns a process
+// leave no empty logs behind. Attempts append to the same file, each under
+// its own header; once the file outgrows maxBytes it is rolled over to
+// <path>.1 … <path>.<maxBackups>. Writes are serialised because adapters copy
+// stdout and stderr from separate goroutines.
+type rotatingLog struct {
+	mu         sync.Mutex
+	path       string
+	header     string
+	maxBytes   int64
+	maxBackups int
+	f          *os.File
+	size       int64
+	created    bool
+	err        error // sticky: capture stops after the first I/O failure
+}
+
+func newAdapterLog(execution *PipelineExecution, step *Step, res *stepRunResources) *rotatingLog {
+	cfg := execution.Manifest.Runtime.AdapterLogs
+	return &rotatingLog{
+		path: adapterLogPath(execution.Manifest, res.pipelineID, step.ID, execution.adapterLogWorker),
+		header: fmt.Sprintf("=== wave: step %s started %s (adapter %s, persona %s, model %s) ===\n",
+			step.ID, time.Now().Format(time.RFC3339), res.resolvedAdapterName, res.resolvedPersona, res.resolvedModel),
+		maxBytes:   cfg.GetMaxSize(),
+		maxBackups: cfg.GetMaxBackups(),
+	}
+}
+
+func (l *rotatingLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return 0, l.err
+	}
+	if l.f == nil {
+		if l.err = l.open(); l.err != nil {
+			return 0, l.err
+		}
+		// Start a new attempt in a fresh file if earlier ones filled it.
+		if l.size >= l.maxBytes {
+			if l.err = l.rotate(); l.err != nil {
+				return 0, l.err
+			}
+		}
+		if l.err = l.write([]byte(l.header)); l.err != nil {
+			return 0, l.err
+		}
+	}
+	if l.size > 0 && l.size+int64(len(p)) > l.maxBytes {
+		if l.err = l.rotate(); l.err != nil {
+			return 0, l.err
+		}
+	}
+	if l.err = l.write(p); l.err != nil {
+		return 0, l.err
+	}
+	return len(p), nil
+}
+
+// finish records how the adapter run ended and closes the file. It does
+// nothing when the adapter never wrote output.
+func (l *rotatingLog) finish(result *adapter.AdapterResult, runErr error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return
+	}
+	switch {
+	case runErr != nil:
+		_ = l.write([]byte(fmt.Sprintf("\n=== wave: adapter failed: %v ===\n", runErr)))
+	case result != nil:
+		_ = l.write([]byte(fmt.Sprintf("\n=== wave: adapter exited with code %d ===\n", result.ExitCode)))
+	}
+	_ = l.f.Close()
+	l.f = nil
+	l.err = os.ErrClosed
+}
+
+// written returns the log path if the adapter produced output, else "".
+func (l *rotatingLog) written() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.created {
+		return ""
+	}
+	return l.path
+}
+
+func (l *rotatingLog) open() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.f, l.size, l.created = f, info.Size(), true
+	return nil
+}
+
+func (l *rotatingLog) write(p []byte) error {
+	n, err := l.f.Write(p)
+	l.size += int64(n)
+	return err
+}
+
+func (l *rotatingLog) rotate() error {
+	if err := l.f.Close(); err != nil {
+		return err
+	}
+	for i := l.maxBackups; i > 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", l.path, i-1), fmt.Sprintf("%s.%d", l.path, i))
+	}
+	if err := os.Rename(l.path, l.path+".1"); err != nil {
+		return err
+	}
+	return l.open()
+}
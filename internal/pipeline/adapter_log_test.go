@@ -0,0 +1,148 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingLog_CreatedLazily(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "step.log")
+	l := &rotatingLog{path: path, header: "hdr\n", maxBytes: 1024, maxBackups: 2}
+	l.finish(&adapter.AdapterResult{}, nil)
+
+	assert.Empty(t, l.written())
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "no output must leave no log file")
+}
+
+func TestRotatingLog_Rotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "step.log")
+	l := &rotatingLog{path: path, header: "hdr\n", maxBytes: 16, maxBackups: 2}
+	for _, chunk := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		n, err := l.Write([]byte(chunk))
+		require.NoError(t, err)
+		require.Equal(t, len(chunk), n)
+	}
+	l.finish(&adapter.AdapterResult{ExitCode: 3}, nil)
+	assert.Equal(t, path, l.written())
+
+	read := func(p string) string {
+		data, err := os.ReadFile(p)
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, "dddddddd\n\n=== wave: adapter exited with code 3 ===\n", read(path))
+	assert.Equal(t, "cccccccc\n", read(path+".1"))
+	assert.Equal(t, "bbbbbbbb\n", read(path+".2"))
+	_, err := os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err), "only max_backups rotated files are kept")
+
+	// A later attempt appends under its own header.
+	l2 := &rotatingLog{path: path, header: "hdr2\n", maxBytes: 1024, maxBackups: 2}
+	_, err = l2.Write([]byte("retry\n"))
+	require.NoError(t, err)
+	l2.finish(nil, errors.New("killed"))
+	assert.True(t, strings.HasSuffix(read(path), "hdr2\nretry\n\n=== wave: adapter failed: killed ===\n"), read(path))
+}
+
+// rawLogAdapter writes raw output to cfg.RawLog the way a real adapter
+// subprocess would, then exits non-zero.
+type rawLogAdapter struct{}
+
+func (rawLogAdapter) Run(_ context.Context, cfg adapter.AdapterRunConfig) (*adapter.AdapterResult, error) {
+	if cfg.RawLog != nil {
+		_, _ = cfg.RawLog.Write([]byte("{\"type\":\"result\"\nnot json at all\n"))
+	}
+	return &adapter.AdapterResult{ExitCode: 1, Stdout: strings.NewReader("")}, nil
+}
+
+func TestAdapterLog_ReferencedFromFailureEvent(t *testing.T) {
+	collector := testutil.NewEventCollector()
+	executor := NewDefaultPipelineExecutor(rawLogAdapter{}, WithEmitter(collector))
+
+	tmp := t.TempDir()
+	m := testutil.CreateTestManifest(tmp)
+	m.Runtime.AdapterLogs.Dir = filepath.Join(tmp, "logs")
+
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "adapter-log-test"},
+		Steps: []Step{{
+			ID: "work", Persona: "craftsman", Exec: ExecConfig{Source: "do work"},
+			Handover: HandoverConfig{Contract: ContractConfig{Type: "json_schema", Schema: `{"type":"object","required":["x"]}`, Source: "out.json"}},
+		}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.Error(t, executor.Execute(ctx, p, m, "test"))
+
+	var warned, failed string
+	for _, e := range collector.GetEventsByStep("work") {
+		switch e.State {
+		case "warning":
+			if e.LogPath != "" {
+				warned = e.LogPath
+			}
+		case stateFailed:
+			failed = e.LogPath
+		}
+	}
+	require.NotEmpty(t, failed, "failure event must reference the adapter log")
+	assert.Equal(t, failed, warned, "non-zero exit warning must reference the same log")
+	assert.True(t, strings.HasPrefix(failed, m.Runtime.AdapterLogs.Dir), failed)
+	assert.Equal(t, "work.log", filepath.Base(failed))
+
+	data, err := os.ReadFile(failed)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "=== wave: step work started")
+	assert.Contains(t, string(data), "not json at all\n")
+	assert.Contains(t, string(data), "=== wave: adapter exited with code 1 ===")
+}
+
+func TestAdapterLog_PerConcurrencyAgent(t *testing.T) {
+	collector := testutil.NewEventCollector()
+	executor := NewDefaultPipelineExecutor(rawLogAdapter{}, WithEmitter(collector))
+
+	tmp := t.TempDir()
+	m := testutil.CreateTestManifest(tmp)
+	m.Runtime.AdapterLogs.Dir = filepath.Join(tmp, "logs")
+
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "adapter-log-agents"},
+		Steps: []Step{{
+			ID: "work", Persona: "craftsman", Concurrency: 3, Exec: ExecConfig{Source: "do work"},
+			Handover: HandoverConfig{Contract: ContractConfig{Type: "json_schema", Schema: `{"type":"object","required":["x"]}`, Source: "out.json"}},
+		}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.Error(t, executor.Execute(ctx, p, m, "test"))
+
+	var logs []string
+	for _, e := range collector.GetEventsByStep("work") {
+		if e.State == "concurrency_agent_failed" {
+			require.NotEmpty(t, e.LogPath, "agent failure must reference its own log")
+			logs = append(logs, e.LogPath)
+		}
+	}
+	require.Len(t, logs, 3, "every agent fails")
+	seen := map[string]bool{}
+	for _, path := range logs {
+		assert.Regexp(t, `^work\.agent_\d\.log$`, filepath.Base(path))
+		assert.False(t, seen[path], "agents must not share %s", path)
+		seen[path] = true
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(string(data), "=== wave: step work started"), "one transcript per log")
+	}
+}
@@ -0,0 +1,34 @@
+package pipeline
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+
+	"github.com/recinq/wave/internal/manifest"
+)
+
+// resolveAdapterOptions merges the persona's and the step's adapter_options
+// (step wins per key), validates them against the options adapterName
+// accepts and renders them as CLI arguments. The persona's options only
+// apply when the step runs on the persona's own adapter, since another
+// adapter would not understand them. The step's max_turns becomes the
+// max-turns option when the adapter enforces it natively.
+func resolveAdapterOptions(m *manifest.Manifest, adapterName string, persona *manifest.Persona, step *Step) ([]string, error) {
+	merged := make(map[string]string)
+	if persona != nil && persona.Adapter == adapterName {
+		maps.Copy(merged, persona.AdapterOptions)
+	}
+	maps.Copy(merged, step.AdapterOptions)
+	if step.MaxTurns > 0 && nativeMaxTurns(m, adapterName) {
+		merged["max-turns"] = strconv.Itoa(step.MaxTurns)
+	}
+	if len(merged) == 0 {
+		return nil, nil
+	}
+
+	if err := m.ValidateAdapterOptions(adapterName, merged); err != nil {
+		return nil, fmt.Errorf("step %q: adapter_options: %w", step.ID, err)
+	}
+	return m.AdapterOptionArgs(adapterName, merged), nil
+}
@@ -0,0 +1,36 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAdapterOptions(t *testing.T) {
+	m := &manifest.Manifest{Adapters: map[string]manifest.Adapter{
+		"claude": {Binary: "claude"},
+		"codex":  {Binary: "codex"},
+	}}
+	persona := &manifest.Persona{Adapter: "claude", AdapterOptions: map[string]string{"max-turns": "10", "permission-mode": "plan"}}
+	step := &Step{ID: "implement", AdapterOptions: map[string]string{"max-turns": "30"}}
+
+	args, err := resolveAdapterOptions(m, "claude", persona, step)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--max-turns", "30", "--permission-mode", "plan"}, args)
+
+	// The persona's claude options do not follow the step to codex.
+	step.AdapterOptions = map[string]string{"sandbox": "read-only"}
+	args, err = resolveAdapterOptions(m, "codex", persona, step)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--sandbox", "read-only"}, args)
+
+	step.AdapterOptions = map[string]string{"max-turns": "30"}
+	_, err = resolveAdapterOptions(m, "codex", persona, step)
+	assert.ErrorContains(t, err, `step "implement": adapter_options: unknown option "max-turns" for adapter "codex"`)
+
+	args, err = resolveAdapterOptions(m, "codex", nil, &Step{ID: "plain"})
+	require.NoError(t, err)
+	assert.Nil(t, args)
+}
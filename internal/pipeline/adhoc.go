@@ -142,3 +142,76 @@ func injectArtifacts(pipeline *Pipeline) {
 		}
 	}
 }
+
+// SingleStepOptions describes a one-off task run as a single-step pipeline.
+type SingleStepOptions struct {
+	Persona string
+	// ContractSchema is the path of a JSON schema the step's result must
+	// match. When set, the step writes its result to
+	// .agents/output/result.json and is retried until it validates.
+	ContractSchema string
+	Manifest       *manifest.Manifest
+}
+
+// singleStepResultPath is where a single-step pipeline with a contract
+// writes its result.
+const singleStepResultPath = ".agents/output/result.json"
+
+// GenerateSingleStepPipeline builds a pipeline with one prompt step that
+// runs the run input as its prompt. It goes through the executor like any
+// other pipeline, so the task gets the persona's permissions, events, state
+// tracking and, with a contract schema, output validation.
+func GenerateSingleStepPipeline(opts SingleStepOptions) (*Pipeline, error) {
+	if opts.Manifest == nil {
+		return nil, fmt.Errorf("manifest is required")
+	}
+	if opts.Persona == "" {
+		return nil, fmt.Errorf("persona is required")
+	}
+	if opts.Manifest.GetPersona(opts.Persona) == nil {
+		return nil, fmt.Errorf("persona %q not found in manifest", opts.Persona)
+	}
+
+	step := Step{
+		ID:      "task",
+		Persona: opts.Persona,
+		Memory: MemoryConfig{
+			Strategy: "fresh",
+		},
+		Workspace: WorkspaceConfig{
+			Root: "./",
+			Mount: []Mount{
+				{Source: "./", Target: "/src", Mode: "readwrite"},
+			},
+		},
+		Exec: ExecConfig{
+			Type:   "prompt",
+			Source: "{{ input }}",
+		},
+	}
+	if opts.ContractSchema != "" {
+		step.Exec.Source += fmt.Sprintf("\n\nWrite your result to %s as JSON matching the schema in %s.", singleStepResultPath, opts.ContractSchema)
+		step.OutputArtifacts = []ArtifactDef{
+			{Name: "result", Path: singleStepResultPath, Type: "json"},
+		}
+		step.Handover.Contract = ContractConfig{
+			Type:       "json_schema",
+			SchemaPath: opts.ContractSchema,
+			Source:     singleStepResultPath,
+			OnFailure:  OnFailureRetry,
+			MaxRetries: 2,
+		}
+	}
+
+	return &Pipeline{
+		Kind: "WavePipeline",
+		Metadata: PipelineMetadata{
+			Name:        "adhoc",
+			Description: "Ad-hoc single-step task",
+		},
+		Input: InputConfig{
+			Source: "cli",
+		},
+		Steps: []Step{step},
+	}, nil
+}
@@ -553,3 +553,30 @@ func TestGenerateAdHocPipeline_ValidDAG(t *testing.T) {
 	}
 	assert.Less(t, navIndex, execIndex)
 }
+
+func TestGenerateSingleStepPipeline(t *testing.T) {
+	m := createAdhocTestManifest([]string{"craftsman"})
+
+	p, err := GenerateSingleStepPipeline(SingleStepOptions{Persona: "craftsman", Manifest: m})
+	require.NoError(t, err)
+	require.Len(t, p.Steps, 1)
+	step := p.Steps[0]
+	assert.Equal(t, "task", step.ID)
+	assert.Equal(t, "craftsman", step.Persona)
+	assert.Equal(t, "{{ input }}", step.Exec.Source)
+	assert.Empty(t, step.OutputArtifacts)
+	assert.Empty(t, step.Handover.Contract.Type)
+	require.NoError(t, (&DAGValidator{}).ValidateDAG(p))
+
+	p, err = GenerateSingleStepPipeline(SingleStepOptions{Persona: "craftsman", ContractSchema: "schema.json", Manifest: m})
+	require.NoError(t, err)
+	step = p.Steps[0]
+	assert.Contains(t, step.Exec.Source, "Write your result to .agents/output/result.json")
+	require.Len(t, step.OutputArtifacts, 1)
+	assert.Equal(t, "json_schema", step.Handover.Contract.Type)
+	assert.Equal(t, "schema.json", step.Handover.Contract.SchemaPath)
+	assert.Equal(t, ".agents/output/result.json", step.Handover.Contract.Source)
+
+	_, err = GenerateSingleStepPipeline(SingleStepOptions{Persona: "ghost", Manifest: m})
+	assert.ErrorContains(t, err, `persona "ghost" not found`)
+}
@@ -0,0 +1,105 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/state"
+)
+
+// approvalPollInterval is how often a gate waiting for `wave approve`
+// re-reads its decision. A var so tests can shorten it.
+var approvalPollInterval = 2 * time.Second
+
+// approvalTimeoutDecider is recorded as the decider of approvals rejected
+// because nobody decided before the gate timed out.
+const approvalTimeoutDecider = "timeout"
+
+// awaitApproval persists the gate as waiting_approval and polls the state
+// store until `wave approve` decides it. A gate nobody decides within
+// timeout is rejected automatically.
+func (g *GateExecutor) awaitApproval(ctx context.Context, gate *GateConfig, timeout time.Duration) (*GateDecision, error) {
+	runID, stepID := gate.RuntimeRunID, gate.RuntimeStepID
+	prompt := gate.Prompt
+	if prompt == "" {
+		prompt = gate.Message
+	}
+	deadline := time.Now().Add(timeout)
+	if err := g.store.RequestApproval(runID, stepID, prompt, deadline); err != nil {
+		return nil, err
+	}
+	_ = g.store.SaveStepState(runID, stepID, state.StateWaitingApproval, "")
+	g.emit(event.Event{
+		Timestamp:  time.Now(),
+		PipelineID: runID,
+		StepID:     stepID,
+		State:      event.StateWaitingApproval,
+		Message:    fmt.Sprintf("waiting for approval (timeout %s): wave approve %s %s", timeout, runID, stepID),
+	})
+
+	for {
+		rec, err := g.store.GetApproval(runID, stepID)
+		if err != nil {
+			return nil, err
+		}
+		if rec != nil && rec.Status != state.ApprovalWaiting {
+			return g.approvalDecided(rec)
+		}
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			reason := fmt.Sprintf("no decision within %s", timeout)
+			err := g.store.DecideApproval(runID, stepID, false, approvalTimeoutDecider, reason)
+			if errors.Is(err, state.ErrApprovalNotWaiting) {
+				// Decided between the last poll and the deadline.
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			return g.approvalDecided(&state.ApprovalRecord{StepID: stepID, Status: state.ApprovalRejected,
+				DecidedBy: approvalTimeoutDecider, Reason: reason})
+		}
+		if wait > approvalPollInterval {
+			wait = approvalPollInterval
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// approvalDecided turns a decided approval into the gate's outcome.
+func (g *GateExecutor) approvalDecided(rec *state.ApprovalRecord) (*GateDecision, error) {
+	by := rec.DecidedBy
+	if by == "" {
+		by = "unknown"
+	}
+	if rec.Status != state.ApprovalApproved {
+		g.emit(event.Event{
+			Timestamp: time.Now(),
+			StepID:    rec.StepID,
+			State:     event.StateGateResolved,
+			Message:   fmt.Sprintf("gate rejected by %s", by),
+		})
+		return nil, &approvalRejectedError{StepID: rec.StepID, By: by, Reason: rec.Reason}
+	}
+	g.emit(event.Event{
+		Timestamp: time.Now(),
+		StepID:    rec.StepID,
+		State:     event.StateGateResolved,
+		Message:   fmt.Sprintf("gate approved by %s", by),
+	})
+	return &GateDecision{
+		Choice:    state.ApprovalApproved,
+		Label:     "Approved",
+		Text:      rec.Reason,
+		Timestamp: time.Now(),
+		Approver:  rec.DecidedBy,
+	}, nil
+}
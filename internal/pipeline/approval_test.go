@@ -0,0 +1,82 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGateExecutor_AwaitsApproval(t *testing.T) {
+	defer func(d time.Duration) { approvalPollInterval = d }(approvalPollInterval)
+	approvalPollInterval = 10 * time.Millisecond
+
+	newGate := func(t *testing.T, timeout string) (state.StateStore, *GateConfig) {
+		store, err := state.NewStateStore(filepath.Join(t.TempDir(), "state.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { store.Close() })
+		runID, err := store.CreateRun("gated", "test")
+		require.NoError(t, err)
+		require.NoError(t, store.SavePipelineState(runID, "running", "test"))
+		return store, &GateConfig{Type: "approval", Prompt: "Ship it?", Timeout: timeout,
+			RuntimeRunID: runID, RuntimeStepID: "review"}
+	}
+	// decideWhenWaiting decides the gate once the executor has asked for it.
+	decideWhenWaiting := func(store state.StateStore, gate *GateConfig, approved bool) {
+		go func() {
+			for {
+				if rec, _ := store.GetApproval(gate.RuntimeRunID, gate.RuntimeStepID); rec != nil {
+					_ = store.DecideApproval(gate.RuntimeRunID, gate.RuntimeStepID, approved, "alice", "looks right")
+					return
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+		}()
+	}
+	execute := func(store state.StateStore, gate *GateConfig) (*GateDecision, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return NewGateExecutor(testutil.NewEventCollector(), store, nil).ExecuteWithDecision(ctx, gate, nil)
+	}
+
+	t.Run("approved", func(t *testing.T) {
+		store, gate := newGate(t, "1m")
+		decideWhenWaiting(store, gate, true)
+		decision, err := execute(store, gate)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", decision.Approver)
+		assert.Equal(t, "looks right", decision.Text)
+
+		steps, err := store.GetStepStates(gate.RuntimeRunID)
+		require.NoError(t, err)
+		require.Len(t, steps, 1)
+		assert.Equal(t, state.StateWaitingApproval, steps[0].State, "the executor records the wait; it owns the step's later states")
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		store, gate := newGate(t, "1m")
+		decideWhenWaiting(store, gate, false)
+		_, err := execute(store, gate)
+		var rejected *approvalRejectedError
+		require.True(t, errors.As(err, &rejected), "got %v", err)
+		assert.Equal(t, `gate "review" rejected by alice: looks right`, err.Error())
+	})
+
+	t.Run("timeout rejects", func(t *testing.T) {
+		store, gate := newGate(t, "50ms")
+		_, err := execute(store, gate)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `gate "review" rejected by timeout: no decision within 50ms`)
+
+		rec, err := store.GetApproval(gate.RuntimeRunID, "review")
+		require.NoError(t, err)
+		assert.Equal(t, state.ApprovalRejected, rec.Status)
+		assert.Equal(t, "timeout", rec.DecidedBy)
+	})
+}
@@ -0,0 +1,201 @@
+package pipeline
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"image"
+	_ "image/gif"  // register GIF decoder for preview dimensions
+	_ "image/jpeg" // register JPEG decoder for preview dimensions
+	_ "image/png"  // register PNG decoder for preview dimensions
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/recinq/wave/internal/fileutil"
+)
+
+const (
+	// artifactPreviewLines is the number of leading lines kept as a text preview.
+	artifactPreviewLines = 20
+	// artifactPreviewBytes caps the preview regardless of line count.
+	artifactPreviewBytes = 4096
+	// artifactSniffBytes is how much of the file is read for MIME detection
+	// and preview extraction; the checksum always covers the whole file.
+	artifactSniffBytes = 64 * 1024
+	// artifactMaxJSONKeys caps the number of top-level JSON keys recorded.
+	artifactMaxJSONKeys = 50
+)
+
+// ArtifactIndex is the index computed for an artifact at registration time.
+// Fields is stored as artifact_metadata.metadata_json and is merged with any
+// metadata added later (truncation, archive stats).
+type ArtifactIndex struct {
+	PreviewText string
+	MimeType    string
+	Encoding    string
+	Fields      map[string]any
+}
+
+// IndexArtifact detects the MIME type of the file at path, extracts a
+// preview (leading lines, top-level JSON keys, image dimensions) and
+// computes its SHA-256 checksum. Previews are omitted for binary and
+// directory artifacts.
+func IndexArtifact(path, artifactType string) (ArtifactIndex, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ArtifactIndex{}, err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	head := make([]byte, artifactSniffBytes)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return ArtifactIndex{}, err
+	}
+	head = head[:n]
+	hash.Write(head)
+	rest, err := io.Copy(hash, f)
+	if err != nil {
+		return ArtifactIndex{}, err
+	}
+	// complete reports whether head holds the entire file.
+	complete := rest == 0
+
+	idx := ArtifactIndex{
+		MimeType: detectArtifactMimeType(path, head),
+		Fields:   map[string]any{"sha256": hex.EncodeToString(hash.Sum(nil))},
+	}
+
+	opaque := artifactType == ArtifactTypeBinary || artifactType == ArtifactTypeDirectory
+	text := utf8.Valid(trimPartialRune(head, complete))
+	if text && !opaque {
+		idx.Encoding = "utf-8"
+		idx.PreviewText = previewLines(head)
+		if idx.MimeType == "application/json" && complete {
+			if keys := jsonTopLevelKeys(head); keys != nil {
+				idx.Fields["json_keys"] = keys
+			}
+		}
+	} else {
+		idx.Encoding = "binary"
+	}
+
+	if strings.HasPrefix(idx.MimeType, "image/") {
+		if cfg, _, err := image.DecodeConfig(bytes.NewReader(head)); err == nil {
+			idx.Fields["image"] = map[string]int{"width": cfg.Width, "height": cfg.Height}
+		}
+	}
+	return idx, nil
+}
+
+// detectArtifactMimeType sniffs content and refines generic text results with
+// the file extension, since http.DetectContentType reports JSON, YAML and
+// Markdown as text/plain.
+func detectArtifactMimeType(path string, head []byte) string {
+	if strings.HasSuffix(path, fileutil.ArchiveExt) {
+		return "application/zstd"
+	}
+	sniffed := http.DetectContentType(head)
+	if !strings.HasPrefix(sniffed, "text/plain") {
+		return strings.SplitN(sniffed, ";", 2)[0]
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "application/json"
+	case ".yaml", ".yml":
+		return "application/x-yaml"
+	case ".md":
+		return "text/markdown"
+	}
+	trimmed := bytes.TrimSpace(head)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+		return "application/json"
+	}
+	return "text/plain"
+}
+
+// previewLines returns the first artifactPreviewLines lines of data, capped
+// at artifactPreviewBytes.
+func previewLines(data []byte) string {
+	var sb strings.Builder
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, artifactPreviewBytes), artifactSniffBytes)
+	for lines := 0; lines < artifactPreviewLines && scanner.Scan(); lines++ {
+		line := scanner.Text()
+		if sb.Len()+len(line) >= artifactPreviewBytes {
+			remaining := artifactPreviewBytes - sb.Len()
+			for remaining > 0 && !utf8.ValidString(line[:remaining]) {
+				remaining--
+			}
+			sb.WriteString(line[:remaining])
+			break
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// jsonTopLevelKeys returns the sorted keys of a JSON object, or nil when data
+// is not an object.
+func jsonTopLevelKeys(data []byte) []string {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if len(keys) > artifactMaxJSONKeys {
+		keys = keys[:artifactMaxJSONKeys]
+	}
+	return keys
+}
+
+// trimPartialRune drops a rune cut off by the sniff window so a truncated
+// UTF-8 sequence is not mistaken for binary content.
+func trimPartialRune(data []byte, complete bool) []byte {
+	if complete {
+		return data
+	}
+	for i := 0; i < utf8.UTFMax && len(data) > 0; i++ {
+		if r, _ := utf8.DecodeLastRune(data); r != utf8.RuneError {
+			break
+		}
+		data = data[:len(data)-1]
+	}
+	return data
+}
+
+// registerArtifact records an artifact in the state store and indexes it,
+// returning the artifact's SHA-256 ("" when it could not be indexed).
+// Best-effort: store and indexing errors are ignored.
+func (e *DefaultPipelineExecutor) registerArtifact(runID string, step *Step, name, path, artifactType string, size int64) string {
+	if e.store == nil {
+		return ""
+	}
+	if err := e.store.RegisterArtifact(runID, step.ID, name, path, artifactType, size); err != nil {
+		return ""
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return ""
+	}
+	idx, err := IndexArtifact(path, artifactType)
+	if err != nil {
+		return ""
+	}
+	e.mergeArtifactMetadata(runID, step, map[string]bool{name: true}, idx.PreviewText, idx.MimeType, idx.Encoding, idx.Fields)
+	sum, _ := idx.Fields["sha256"].(string)
+	return sum
+}
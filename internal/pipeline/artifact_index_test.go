@@ -0,0 +1,99 @@
+package pipeline
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeIndexFixture(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+func TestIndexArtifact_JSON(t *testing.T) {
+	content := []byte(`{"zeta": 1, "alpha": {"nested": true}}`)
+	path := writeIndexFixture(t, "out", content)
+
+	idx, err := IndexArtifact(path, "json")
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", idx.MimeType)
+	assert.Equal(t, "utf-8", idx.Encoding)
+	assert.Equal(t, []string{"alpha", "zeta"}, idx.Fields["json_keys"])
+	sum := sha256.Sum256(content)
+	assert.Equal(t, hex.EncodeToString(sum[:]), idx.Fields["sha256"])
+}
+
+func TestIndexArtifact_TextPreview(t *testing.T) {
+	var lines []string
+	for i := 0; i < 50; i++ {
+		lines = append(lines, "line")
+	}
+	path := writeIndexFixture(t, "notes.md", []byte(strings.Join(lines, "\n")))
+
+	idx, err := IndexArtifact(path, "markdown")
+	require.NoError(t, err)
+	assert.Equal(t, "text/markdown", idx.MimeType)
+	assert.Equal(t, artifactPreviewLines, strings.Count(idx.PreviewText, "line"))
+	assert.NotContains(t, idx.Fields, "json_keys")
+}
+
+func TestIndexArtifact_ImageDimensions(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 32, 16))))
+	path := writeIndexFixture(t, "chart.png", buf.Bytes())
+
+	idx, err := IndexArtifact(path, ArtifactTypeBinary)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", idx.MimeType)
+	assert.Equal(t, "binary", idx.Encoding)
+	assert.Empty(t, idx.PreviewText)
+	assert.Equal(t, map[string]int{"width": 32, "height": 16}, idx.Fields["image"])
+}
+
+func TestIndexArtifact_OpaqueTextHasNoPreview(t *testing.T) {
+	path := writeIndexFixture(t, "blob", []byte("looks like text"))
+
+	idx, err := IndexArtifact(path, ArtifactTypeBinary)
+	require.NoError(t, err)
+	assert.Empty(t, idx.PreviewText)
+	assert.Equal(t, "binary", idx.Encoding)
+}
+
+func TestDetectArtifactMimeType(t *testing.T) {
+	tests := []struct {
+		path string
+		data string
+		want string
+	}{
+		{"a.yaml", "key: value\n", "application/x-yaml"},
+		{"a.txt", "plain words", "text/plain"},
+		{"stdout", `[1, 2, 3]`, "application/json"},
+		{"stdout", `{not json`, "text/plain"},
+		{"dist.tar.zst", "\x28\xb5\x2f\xfd", "application/zstd"},
+		{"a.bin", "\x00\x01\x02", "application/octet-stream"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.path+"/"+tc.want, func(t *testing.T) {
+			assert.Equal(t, tc.want, detectArtifactMimeType(tc.path, []byte(tc.data)))
+		})
+	}
+}
+
+func TestTrimPartialRune(t *testing.T) {
+	data := []byte("héllo é")
+	cut := data[:len(data)-1] // split the final two-byte rune
+	assert.Equal(t, []byte("héllo "), trimPartialRune(cut, false))
+	assert.Equal(t, cut, trimPartialRune(cut, true))
+}
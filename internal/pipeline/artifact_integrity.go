@@ -0,0 +1,96 @@
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ArtifactIntegrityError reports that an artifact no longer matches the
+// SHA-256 recorded when it was registered.
+type ArtifactIntegrityError struct {
+	Step     string
+	Artifact string
+	Path     string
+	Expected string
+	Actual   string
+}
+
+func (e *ArtifactIntegrityError) Error() string {
+	return fmt.Sprintf("artifact '%s' from step '%s' modified/corrupted since registration: %s has sha256 %s, expected %s",
+		e.Artifact, e.Step, e.Path, e.Actual, e.Expected)
+}
+
+// fileSHA256 returns the hex-encoded SHA-256 of the file at path.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// recordArtifactChecksum remembers the checksum an artifact had when it was
+// registered so injection into later steps can detect modification.
+func recordArtifactChecksum(execution *PipelineExecution, key, sum string) {
+	if sum == "" {
+		return
+	}
+	execution.mu.Lock()
+	defer execution.mu.Unlock()
+	if execution.ArtifactChecksums == nil {
+		execution.ArtifactChecksums = make(map[string]string)
+	}
+	execution.ArtifactChecksums[key] = sum
+}
+
+// copyArtifactChecksums copies the registration checksums of parent into
+// worker so isolated worker executions verify inherited artifacts too.
+func copyArtifactChecksums(worker, parent *PipelineExecution) {
+	parent.mu.Lock()
+	defer parent.mu.Unlock()
+	for k, v := range parent.ArtifactChecksums {
+		if worker.ArtifactChecksums == nil {
+			worker.ArtifactChecksums = make(map[string]string)
+		}
+		worker.ArtifactChecksums[k] = v
+	}
+}
+
+// verifyArtifactChecksum checks the file at path against the checksum
+// recorded for key at registration. Artifacts without a recorded checksum
+// (unregistered, resumed from a prior run, or directories whose archiving
+// failed) are not verified.
+func verifyArtifactChecksum(execution *PipelineExecution, ref ArtifactRef, path string) error {
+	key := ref.Step + ":" + ref.Artifact
+	execution.mu.Lock()
+	expected := execution.ArtifactChecksums[key]
+	execution.mu.Unlock()
+	return checkArtifactSHA256(ref, path, expected)
+}
+
+// checkArtifactSHA256 compares the file at path with expected. An empty
+// expected checksum or a directory path is not verified.
+func checkArtifactSHA256(ref ArtifactRef, path, expected string) error {
+	if expected == "" {
+		return nil
+	}
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return nil
+	}
+	actual, err := fileSHA256(path)
+	if err != nil {
+		return fmt.Errorf("failed to checksum artifact '%s': %w", ref.Artifact, err)
+	}
+	if actual != expected {
+		return &ArtifactIntegrityError{Step: ref.Step, Artifact: ref.Artifact, Path: path, Expected: expected, Actual: actual}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package pipeline
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectArtifacts_VerifiesChecksum(t *testing.T) {
+	tmpDir := t.TempDir()
+	artifactPath := filepath.Join(tmpDir, "plan.json")
+	require.NoError(t, os.WriteFile(artifactPath, []byte(`{"plan": 1}`), 0644))
+	sum, err := fileSHA256(artifactPath)
+	require.NoError(t, err)
+
+	executor := NewDefaultPipelineExecutor(nil, WithEmitter(testutil.NewEventCollector()))
+	newExecution := func() *PipelineExecution {
+		execution := &PipelineExecution{
+			Pipeline:      &Pipeline{Metadata: PipelineMetadata{Name: "integrity"}},
+			States:        make(map[string]string),
+			Results:       make(map[string]map[string]interface{}),
+			ArtifactPaths: map[string]string{"plan:plan": artifactPath},
+			Context:       NewPipelineContext("run-1", "integrity", "implement"),
+			Status:        &PipelineStatus{ID: "run-1"},
+		}
+		recordArtifactChecksum(execution, "plan:plan", sum)
+		return execution
+	}
+	step := &Step{
+		ID: "implement",
+		Memory: MemoryConfig{
+			InjectArtifacts: []ArtifactRef{{Step: "plan", Artifact: "plan"}},
+		},
+	}
+
+	t.Run("unchanged artifact is injected", func(t *testing.T) {
+		ws := filepath.Join(tmpDir, "ws-ok")
+		require.NoError(t, executor.injectArtifacts(newExecution(), step, ws))
+		assert.FileExists(t, filepath.Join(ws, ".agents", "artifacts", "plan"))
+	})
+
+	t.Run("modified artifact fails injection", func(t *testing.T) {
+		execution := newExecution()
+		require.NoError(t, os.WriteFile(artifactPath, []byte(`{"plan": 2}`), 0644))
+
+		err := executor.injectArtifacts(execution, step, filepath.Join(tmpDir, "ws-bad"))
+		var integrityErr *ArtifactIntegrityError
+		require.True(t, errors.As(err, &integrityErr), "expected ArtifactIntegrityError, got %v", err)
+		assert.Equal(t, sum, integrityErr.Expected)
+		assert.Contains(t, err.Error(), "modified/corrupted since registration")
+		assert.Equal(t, FailureClassDeterministic, ClassifyStepFailure(err, nil, nil))
+	})
+
+	t.Run("artifact without checksum is not verified", func(t *testing.T) {
+		execution := newExecution()
+		execution.ArtifactChecksums = nil
+		require.NoError(t, executor.injectArtifacts(execution, step, filepath.Join(tmpDir, "ws-unverified")))
+	})
+}
+
+func TestCopyArtifactChecksums(t *testing.T) {
+	parent := &PipelineExecution{}
+	recordArtifactChecksum(parent, "a:out", "abc")
+	recordArtifactChecksum(parent, "a:none", "")
+
+	worker := &PipelineExecution{}
+	copyArtifactChecksums(worker, parent)
+	assert.Equal(t, map[string]string{"a:out": "abc"}, worker.ArtifactChecksums)
+}
@@ -0,0 +1,155 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/recinq/wave/internal/fileutil"
+)
+
+// Artifact types with non-text content. Both are stored and injected
+// byte-for-byte and never rendered as text previews.
+const (
+	ArtifactTypeBinary    = "binary"
+	ArtifactTypeDirectory = "directory"
+)
+
+// IsOpaque reports whether the artifact's content must not be treated as
+// text (no stdout fallback, no previews).
+func (a *ArtifactDef) IsOpaque() bool {
+	return a.Type == ArtifactTypeBinary || a.Type == ArtifactTypeDirectory
+}
+
+// opaqueArtifactMetadata is stored in artifact_metadata.metadata_json for
+// binary and directory artifacts.
+type opaqueArtifactMetadata struct {
+	Kind              string `json:"kind"`
+	Bytes             int64  `json:"bytes"`
+	Files             int    `json:"files,omitempty"`
+	ArchiveBytes      int64  `json:"archive_bytes,omitempty"`
+	PreviewSuppressed bool   `json:"preview_suppressed"`
+}
+
+// archiveDirectoryArtifact packs a directory artifact into
+// <archiveDir>/<name>.tar.zst so later steps can inject it even after the
+// workspace copy has been modified.
+func archiveDirectoryArtifact(srcDir, archiveDir, name string) (string, opaqueArtifactMetadata, error) {
+	archivePath := filepath.Join(archiveDir, name+fileutil.ArchiveExt)
+	stats, err := fileutil.ArchiveDir(srcDir, archivePath)
+	if err != nil {
+		_ = os.Remove(archivePath)
+		return "", opaqueArtifactMetadata{}, err
+	}
+	return archivePath, opaqueArtifactMetadata{
+		Kind:              ArtifactTypeDirectory,
+		Bytes:             stats.Bytes,
+		Files:             stats.Files,
+		ArchiveBytes:      stats.ArchiveBytes,
+		PreviewSuppressed: true,
+	}, nil
+}
+
+// checkArtifactKind stats the artifact at path and verifies it matches the
+// declared type: directory artifacts (or their archives) must be
+// directories, any other declared type must be a file. Untyped artifacts
+// accept either.
+func checkArtifactKind(ref ArtifactRef, declared, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &ArtifactNotFoundError{Step: ref.Step, Artifact: ref.Artifact, Path: path}
+		}
+		return fmt.Errorf("failed to stat artifact '%s': %w", ref.Artifact, err)
+	}
+	if declared == "" {
+		return nil
+	}
+	found := "file"
+	if info.IsDir() || strings.HasSuffix(path, fileutil.ArchiveExt) {
+		found = ArtifactTypeDirectory
+	}
+	if (declared == ArtifactTypeDirectory) != (found == ArtifactTypeDirectory) {
+		return &ArtifactKindMismatchError{Step: ref.Step, Artifact: ref.Artifact, Path: path, Declared: declared, Found: found}
+	}
+	return nil
+}
+
+// materializeArtifact places the artifact at src into dest for injection.
+// Directory archives are extracted and plain directories copied, replacing
+// anything already at dest; files are copied byte-for-byte.
+func materializeArtifact(src, dest string) error {
+	if strings.HasSuffix(src, fileutil.ArchiveExt) {
+		if err := os.RemoveAll(dest); err != nil {
+			return err
+		}
+		return fileutil.ExtractArchive(src, dest)
+	}
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		if err := os.RemoveAll(dest); err != nil {
+			return err
+		}
+		return fileutil.CopyPath(src, dest)
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dest, data, 0644)
+}
+
+// mergeArtifactMetadata attaches metadata to the named artifacts the step
+// has registered. Fields of metadata are merged into any metadata_json
+// already stored, and empty preview/MIME/encoding values keep the stored
+// ones, so registration-time indexing and later annotations (truncation,
+// archive stats) coexist. Best-effort: store errors are ignored.
+func (e *DefaultPipelineExecutor) mergeArtifactMetadata(runID string, step *Step, names map[string]bool, previewText, mimeType, encoding string, metadata any) {
+	if e.store == nil || len(names) == 0 {
+		return
+	}
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return
+	}
+	records, err := e.store.GetArtifacts(runID, step.ID)
+	if err != nil {
+		return
+	}
+	for _, rec := range records {
+		if !names[rec.Name] {
+			continue
+		}
+		merged := make(map[string]any)
+		preview, mime, enc := previewText, mimeType, encoding
+		if existing, err := e.store.GetArtifactMetadata(rec.ID); err == nil && existing != nil {
+			_ = json.Unmarshal([]byte(existing.MetadataJSON), &merged)
+			if preview == "" {
+				preview = existing.PreviewText
+			}
+			if mime == "" {
+				mime = existing.MimeType
+			}
+			if enc == "" {
+				enc = existing.Encoding
+			}
+		}
+		for k, v := range fields {
+			merged[k] = v
+		}
+		out, err := json.Marshal(merged)
+		if err != nil {
+			continue
+		}
+		_ = e.store.SaveArtifactMetadata(rec.ID, runID, step.ID, preview, mime, enc, string(out))
+	}
+}
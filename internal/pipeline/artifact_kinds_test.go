@@ -0,0 +1,217 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/fileutil"
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dirArtifactAdapter writes a dist/ tree in the "build" step and records what
+// the "consume" step sees in its injected artifacts.
+type dirArtifactAdapter struct {
+	mu            sync.Mutex
+	injected      map[string]string
+	consumePrompt string
+}
+
+func (a *dirArtifactAdapter) Run(_ context.Context, cfg adapter.AdapterRunConfig) (*adapter.AdapterResult, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch filepath.Base(cfg.WorkspacePath) {
+	case "build":
+		dist := filepath.Join(cfg.WorkspacePath, "dist")
+		if err := os.MkdirAll(filepath.Join(dist, "assets"), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dist, "index.html"), []byte("<html></html>"), 0644); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dist, "assets", "logo.bin"), []byte{0, 1, 2}, 0644); err != nil {
+			return nil, err
+		}
+	case "consume":
+		a.consumePrompt = cfg.Prompt
+		a.injected = map[string]string{}
+		root := filepath.Join(cfg.WorkspacePath, ".agents", "artifacts", "site")
+		_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			rel, _ := filepath.Rel(root, path)
+			data, _ := os.ReadFile(path)
+			a.injected[filepath.ToSlash(rel)] = string(data)
+			return nil
+		})
+	}
+	return &adapter.AdapterResult{ExitCode: 0, Stdout: strings.NewReader("done"), TokensUsed: 10}, nil
+}
+
+func TestDirectoryArtifact_ArchivedAndInjected(t *testing.T) {
+	collector := testutil.NewEventCollector()
+	mock := &dirArtifactAdapter{}
+	store := &artifactMetadataStore{MockStateStore: testutil.NewMockStateStore(), metadata: map[int64]*state.ArtifactMetadataRecord{}}
+
+	executor := NewDefaultPipelineExecutor(mock,
+		WithEmitter(collector),
+		WithStateStore(store),
+	)
+
+	tmpDir := t.TempDir()
+	m := testutil.CreateTestManifest(tmpDir)
+
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "dir-artifact-test"},
+		Steps: []Step{
+			{
+				ID:      "build",
+				Persona: "navigator",
+				Exec:    ExecConfig{Source: "build the site"},
+				OutputArtifacts: []ArtifactDef{
+					{Name: "dist", Path: "dist", Type: ArtifactTypeDirectory},
+				},
+			},
+			{
+				ID:           "consume",
+				Persona:      "navigator",
+				Dependencies: []string{"build"},
+				Exec:         ExecConfig{Source: "inspect the site"},
+				Memory: MemoryConfig{
+					InjectArtifacts: []ArtifactRef{{Step: "build", Artifact: "dist", As: "site"}},
+				},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	require.NoError(t, executor.Execute(ctx, p, m, "test"))
+
+	pipelineID := collector.GetPipelineID()
+	archive := filepath.Join(tmpDir, pipelineID, "build", ".agents", "artifacts", "build", "dist"+fileutil.ArchiveExt)
+	assert.FileExists(t, archive)
+
+	assert.Equal(t, map[string]string{
+		"index.html":      "<html></html>",
+		"assets/logo.bin": "\x00\x01\x02",
+	}, mock.injected)
+	assert.Contains(t, mock.consumePrompt, "artifact `dist`) — directory")
+
+	rec := store.metadataFor("dist")
+	require.NotNil(t, rec)
+	var meta opaqueArtifactMetadata
+	require.NoError(t, json.Unmarshal([]byte(rec.MetadataJSON), &meta))
+	assert.Equal(t, "application/zstd", rec.MimeType)
+	assert.Empty(t, rec.PreviewText)
+	assert.Equal(t, ArtifactTypeDirectory, meta.Kind)
+	assert.Equal(t, 2, meta.Files)
+	assert.Equal(t, int64(16), meta.Bytes)
+	assert.True(t, meta.PreviewSuppressed)
+}
+
+func TestMaterializeArtifact_File(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "blob")
+	require.NoError(t, os.WriteFile(src, []byte{0xff, 0x00}, 0644))
+
+	dest := filepath.Join(tmp, "out")
+	require.NoError(t, materializeArtifact(src, dest))
+	got, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0x00}, got)
+}
+
+func TestCheckArtifactKind(t *testing.T) {
+	tmp := t.TempDir()
+	file := filepath.Join(tmp, "plan.json")
+	require.NoError(t, os.WriteFile(file, []byte("{}"), 0644))
+	dir := filepath.Join(tmp, "dist")
+	require.NoError(t, os.Mkdir(dir, 0755))
+	archive := filepath.Join(tmp, "dist"+fileutil.ArchiveExt)
+	require.NoError(t, os.WriteFile(archive, nil, 0644))
+
+	ref := ArtifactRef{Step: "build", Artifact: "out"}
+	tests := []struct {
+		name      string
+		declared  string
+		path      string
+		wantFound string // "" means no mismatch
+	}{
+		{"file declared json", "json", file, ""},
+		{"directory declared directory", ArtifactTypeDirectory, dir, ""},
+		{"archive declared directory", ArtifactTypeDirectory, archive, ""},
+		{"untyped directory", "", dir, ""},
+		{"untyped file", "", file, ""},
+		{"directory declared json", "json", dir, ArtifactTypeDirectory},
+		{"file declared directory", ArtifactTypeDirectory, file, "file"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkArtifactKind(ref, tc.declared, tc.path)
+			if tc.wantFound == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var mismatch *ArtifactKindMismatchError
+			require.True(t, errors.As(err, &mismatch), "expected ArtifactKindMismatchError, got %v", err)
+			assert.Equal(t, tc.declared, mismatch.Declared)
+			assert.Equal(t, tc.wantFound, mismatch.Found)
+			assert.Contains(t, err.Error(), `declared as type "`+tc.declared+`"`)
+		})
+	}
+
+	t.Run("missing path", func(t *testing.T) {
+		err := checkArtifactKind(ref, "json", filepath.Join(tmp, "gone.json"))
+		var notFound *ArtifactNotFoundError
+		require.True(t, errors.As(err, &notFound), "expected ArtifactNotFoundError, got %v", err)
+		assert.Equal(t, FailureCategoryMissingArtifact, CategorizeStepFailure(err, nil))
+	})
+}
+
+func TestInjectArtifacts_DirectoryDeclaredAsFile(t *testing.T) {
+	tmp := t.TempDir()
+	dir := filepath.Join(tmp, "report")
+	require.NoError(t, os.Mkdir(dir, 0755))
+
+	executor := NewDefaultPipelineExecutor(nil, WithEmitter(testutil.NewEventCollector()))
+	execution := &PipelineExecution{
+		Pipeline: &Pipeline{Steps: []Step{{
+			ID:              "analyze",
+			OutputArtifacts: []ArtifactDef{{Name: "report", Path: "report", Type: "markdown"}},
+		}}},
+		States:        make(map[string]string),
+		Results:       make(map[string]map[string]interface{}),
+		ArtifactPaths: map[string]string{"analyze:report": dir},
+		Context:       NewPipelineContext("run-1", "kinds", "summarize"),
+		Status:        &PipelineStatus{ID: "run-1"},
+	}
+	step := &Step{
+		ID:     "summarize",
+		Memory: MemoryConfig{InjectArtifacts: []ArtifactRef{{Step: "analyze", Artifact: "report"}}},
+	}
+
+	err := executor.injectArtifacts(execution, step, filepath.Join(tmp, "ws"))
+	var mismatch *ArtifactKindMismatchError
+	require.True(t, errors.As(err, &mismatch), "expected ArtifactKindMismatchError, got %v", err)
+	assert.Equal(t, "markdown", mismatch.Declared)
+	assert.Equal(t, ArtifactTypeDirectory, mismatch.Found)
+
+	// An optional artifact that vanished from disk is skipped, not an error.
+	execution.ArtifactPaths["analyze:report"] = filepath.Join(tmp, "missing.md")
+	step.Memory.InjectArtifacts[0].Optional = true
+	require.NoError(t, executor.injectArtifacts(execution, step, filepath.Join(tmp, "ws")))
+}
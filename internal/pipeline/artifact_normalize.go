@@ -0,0 +1,72 @@
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/contract"
+	"github.com/recinq/wave/internal/event"
+)
+
+// jsonContractTarget reports whether a json_schema contract of step that
+// allows recovery validates the output artifact art written to artPath.
+func jsonContractTarget(execution *PipelineExecution, step *Step, art ArtifactDef, workspacePath, artPath string) bool {
+	for _, c := range step.Handover.EffectiveContracts() {
+		if c.Type != "json_schema" || !contract.RecoveryEnabled(c) {
+			continue
+		}
+		if c.Source == "" {
+			if len(step.OutputArtifacts) > 0 && step.OutputArtifacts[0].Name == art.Name {
+				return true
+			}
+			continue
+		}
+		source := c.Source
+		if execution.Context != nil {
+			source = execution.Context.ResolveContractSource(c)
+		}
+		if filepath.Clean(filepath.Join(workspacePath, source)) == filepath.Clean(artPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeJSONArtifact rewrites an artifact validated by a json_schema
+// contract when the persona wrapped valid JSON in a markdown code fence or
+// prose, so validation and downstream steps see the bare document. It runs
+// before the artifact is archived and checksummed, and records what it
+// stripped as an artifact_normalized event.
+func (e *DefaultPipelineExecutor) normalizeJSONArtifact(execution *PipelineExecution, step *Step, art ArtifactDef, workspacePath, artPath string) {
+	if art.IsOpaque() || !jsonContractTarget(execution, step, art, workspacePath, artPath) {
+		return
+	}
+	data, err := os.ReadFile(artPath)
+	if err != nil {
+		return
+	}
+	normalized, fixes, changed := contract.NormalizeJSONArtifact(data)
+	if !changed {
+		return
+	}
+	if err := os.WriteFile(artPath, normalized, 0644); err != nil {
+		return
+	}
+	e.emit(event.Event{
+		Timestamp:  time.Now(),
+		PipelineID: execution.Status.ID,
+		StepID:     step.ID,
+		State:      "artifact_normalized",
+		Message:    fmt.Sprintf("normalized artifact %s for json_schema contract (%s)", art.Name, strings.Join(fixes, ", ")),
+	})
+	e.trace("artifact_normalized", step.ID, 0, map[string]string{
+		"artifact":      art.Name,
+		"path":          artPath,
+		"fixes":         strings.Join(fixes, ","),
+		"original_size": fmt.Sprintf("%d", len(data)),
+		"size":          fmt.Sprintf("%d", len(normalized)),
+	})
+}
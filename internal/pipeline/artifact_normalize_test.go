@@ -0,0 +1,64 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeJSONArtifact(t *testing.T) {
+	ws := t.TempDir()
+	artPath := filepath.Join(ws, ".agents", "output", "issue.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(artPath), 0o755))
+	fenced := "Here is the issue:\n```json\n{\"title\": \"Fix login\"}\n```\n"
+
+	art := ArtifactDef{Name: "issue", Path: ".agents/output/issue.json"}
+	execution := &PipelineExecution{Status: &PipelineStatus{ID: "run-1"}, Context: NewPipelineContext("run-1", "p", "s")}
+
+	t.Run("contract on the artifact", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(artPath, []byte(fenced), 0o644))
+		collector := testutil.NewEventCollector()
+		executor := NewDefaultPipelineExecutor(nil, WithEmitter(collector))
+		step := &Step{
+			ID:              "fetch",
+			OutputArtifacts: []ArtifactDef{art},
+			Handover:        HandoverConfig{Contract: ContractConfig{Type: "json_schema", Source: ".agents/output/issue.json"}},
+		}
+
+		executor.normalizeJSONArtifact(execution, step, art, ws, artPath)
+
+		data, err := os.ReadFile(artPath)
+		require.NoError(t, err)
+		assert.Equal(t, "{\n  \"title\": \"Fix login\"\n}\n", string(data))
+		var recorded bool
+		for _, evt := range collector.GetEvents() {
+			if evt.State == "artifact_normalized" && evt.StepID == "fetch" {
+				recorded = true
+				assert.Contains(t, evt.Message, "extracted_from_markdown_code_block")
+			}
+		}
+		assert.True(t, recorded, "expected an artifact_normalized event")
+	})
+
+	for name, c := range map[string]ContractConfig{
+		"non-json contract":  {Type: "test_suite", Command: "true"},
+		"recovery disabled":  {Type: "json_schema", RecoveryLevel: "conservative"},
+		"different artifact": {Type: "json_schema", Source: ".agents/output/other.json"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(artPath, []byte(fenced), 0o644))
+			executor := NewDefaultPipelineExecutor(nil)
+			step := &Step{ID: "fetch", OutputArtifacts: []ArtifactDef{art}, Handover: HandoverConfig{Contract: c}}
+
+			executor.normalizeJSONArtifact(execution, step, art, ws, artPath)
+
+			data, err := os.ReadFile(artPath)
+			require.NoError(t, err)
+			assert.Equal(t, fenced, string(data))
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package pipeline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/recinq/wave/internal/attest"
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/state"
+)
+
+// writeAttestation writes the run's provenance attestation when
+// runtime.attestation is enabled. It runs before workspace cleanup so file
+// deliverables can still be hashed. Nested runs are covered by their
+// parent's attestation. Failures are reported as warnings; they never fail
+// the run.
+func (e *DefaultPipelineExecutor) writeAttestation(execution *PipelineExecution, succeeded bool) {
+	if e.nestedRun || execution.Manifest == nil || !execution.Manifest.Runtime.Attestation.Enabled {
+		return
+	}
+	cfg := execution.Manifest.Runtime.Attestation
+	pipelineID := execution.Status.ID
+
+	warn := func(msg string) {
+		e.emit(event.Event{
+			Timestamp:  time.Now(),
+			PipelineID: pipelineID,
+			State:      "warning",
+			Message:    msg,
+		})
+	}
+
+	status := execution.Status.State
+	if !succeeded {
+		status = stateFailed
+	}
+	finished := time.Now()
+	if execution.Status.CompletedAt != nil {
+		finished = *execution.Status.CompletedAt
+	}
+
+	run := attest.Run{
+		RunID:       pipelineID,
+		Pipeline:    execution.Status.PipelineName,
+		Input:       execution.Input,
+		Status:      status,
+		StartedAt:   execution.Status.StartedAt,
+		FinishedAt:  finished,
+		Definitions: DefinitionDigests(execution.Pipeline, execution.Manifest),
+		Steps:       e.attestedSteps(execution),
+	}
+	if e.outcomeTracker != nil {
+		run.Deliverables = e.outcomeTracker.GetAll()
+	}
+
+	env, err := attest.NewEnvelope(attest.NewStatement(run))
+	if err != nil {
+		warn(fmt.Sprintf("failed to build attestation: %v", err))
+		return
+	}
+	if cfg.SigningKey != "" {
+		key, err := attest.LoadPrivateKey(cfg.SigningKey)
+		if err == nil {
+			err = env.Sign(key)
+		}
+		if err != nil {
+			warn(fmt.Sprintf("attestation left unsigned: %v", err))
+		}
+	}
+
+	path, err := attest.Write(cfg.GetDir(), pipelineID, env)
+	if err != nil {
+		warn(err.Error())
+		return
+	}
+	if e.outcomeTracker != nil {
+		e.outcomeTracker.Add(&state.OutcomeRecord{
+			Type:        state.OutcomeTypeArtifact,
+			Label:       "attestation",
+			Value:       path,
+			Description: "Run provenance attestation",
+		})
+	}
+	e.emit(event.Event{
+		Timestamp:  time.Now(),
+		PipelineID: pipelineID,
+		State:      "attested",
+		Message:    "attestation written to " + path,
+	})
+}
+
+// attestedSteps lists the dispatched steps in pipeline order with their
+// adapter versions. Each adapter binary is probed once.
+func (e *DefaultPipelineExecutor) attestedSteps(execution *PipelineExecution) []attest.StepRun {
+	execution.mu.Lock()
+	dispatched := make(map[string]StepAdapter, len(execution.StepAdapters))
+	for id, a := range execution.StepAdapters {
+		dispatched[id] = a
+	}
+	execution.mu.Unlock()
+
+	versions := make(map[string]string)
+	var steps []attest.StepRun
+	for _, step := range execution.Pipeline.Steps {
+		a, ok := dispatched[step.ID]
+		if !ok {
+			continue
+		}
+		version, probed := versions[a.Adapter]
+		if !probed {
+			if def := execution.Manifest.GetAdapter(a.Adapter); def != nil {
+				version = attest.AdapterVersion(def.Binary)
+			}
+			versions[a.Adapter] = version
+		}
+		steps = append(steps, attest.StepRun{
+			ID:             step.ID,
+			Persona:        a.Persona,
+			Adapter:        a.Adapter,
+			AdapterVersion: version,
+			Model:          a.Model,
+		})
+	}
+	return steps
+}
@@ -0,0 +1,73 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/attest"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runWithAttestation(t *testing.T, cfg manifest.AttestationConfig) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	executor := NewDefaultPipelineExecutor(
+		adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`)),
+		WithEmitter(testutil.NewEventCollector()),
+		WithRunID("attested-run"),
+	)
+
+	m := testutil.CreateTestManifest(tmpDir)
+	// Keep the version probe away from any real adapter CLI on the host.
+	m.Adapters["claude"] = manifest.Adapter{Binary: "wave-test-no-such-binary", Mode: "headless"}
+	m.Digest = "manifest-digest"
+	cfg.Dir = filepath.Join(tmpDir, "attestations")
+	m.Runtime.Attestation = cfg
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "attested"},
+		Steps:    []Step{{ID: "step1", Persona: "navigator", Exec: ExecConfig{Source: "test"}}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, executor.Execute(ctx, p, m, "do the thing"))
+	return attest.Path(cfg.Dir, "attested-run")
+}
+
+func TestWriteAttestation(t *testing.T) {
+	path := runWithAttestation(t, manifest.AttestationConfig{Enabled: true})
+
+	env, err := attest.Read(path)
+	require.NoError(t, err)
+	assert.Empty(t, env.Signatures)
+	stmt, err := env.Statement()
+	require.NoError(t, err)
+
+	bd := stmt.Predicate.BuildDefinition
+	assert.Equal(t, attest.ExternalParameters{Pipeline: "attested", Input: "do the thing"}, bd.ExternalParameters)
+	assert.Equal(t, stateCompleted, bd.InternalParameters.Status)
+	assert.Equal(t, []attest.StepRun{{ID: "step1", Persona: "navigator", Adapter: "claude"}}, bd.InternalParameters.Steps)
+	require.Len(t, bd.ResolvedDependencies, 1)
+	assert.Equal(t, "manifest-digest", bd.ResolvedDependencies[0].Digest["sha256"])
+	assert.Equal(t, "attested-run", stmt.Predicate.RunDetails.Metadata.InvocationID)
+}
+
+func TestWriteAttestation_Disabled(t *testing.T) {
+	path := runWithAttestation(t, manifest.AttestationConfig{})
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWriteAttestation_BadKeyLeavesUnsigned(t *testing.T) {
+	path := runWithAttestation(t, manifest.AttestationConfig{Enabled: true, SigningKey: "/nonexistent/attest.key"})
+	env, err := attest.Read(path)
+	require.NoError(t, err)
+	assert.Empty(t, env.Signatures)
+}
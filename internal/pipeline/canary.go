@@ -0,0 +1,86 @@
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/state"
+)
+
+// CanaryConfig runs a step for only a fraction of runs. It is meant for
+// costly validation steps, such as LLM-judge reviews, that are worth
+// sampling but not paying for on every run.
+type CanaryConfig struct {
+	// Sample is the fraction of runs, in (0, 1], that execute the step.
+	Sample float64 `yaml:"sample"`
+}
+
+// canarySampled reports whether step runs in run runID. The decision is a
+// pure function of the run ID and step ID, so a resumed run makes the same
+// choice and different canary steps are sampled independently.
+func canarySampled(runID string, step *Step) bool {
+	if step.Canary == nil || step.Canary.Sample >= 1 {
+		return true
+	}
+	sum := sha256.Sum256([]byte(runID + "\x00" + step.ID))
+	draw := float64(binary.BigEndian.Uint64(sum[:8])) / math.MaxUint64
+	return draw < step.Canary.Sample
+}
+
+// skipCanaryStep records step as skipped by sampling.
+func (e *DefaultPipelineExecutor) skipCanaryStep(execution *PipelineExecution, step *Step) {
+	e.skipStepByDesign(execution, step, fmt.Sprintf("canary: not sampled for this run (sample %.2f)", step.Canary.Sample))
+}
+
+// skipStepByDesign records step as skipped because the pipeline chose not
+// to run it, by canary sampling or a when: condition. Unlike a failure
+// skip, dependents still run; see skipDependentSteps.
+func (e *DefaultPipelineExecutor) skipStepByDesign(execution *PipelineExecution, step *Step, reason string) {
+	execution.mu.Lock()
+	execution.States[step.ID] = stateSkipped
+	if execution.SkippedByDesign == nil {
+		execution.SkippedByDesign = make(map[string]bool)
+	}
+	execution.SkippedByDesign[step.ID] = true
+	execution.mu.Unlock()
+	if e.store != nil {
+		_ = e.store.SaveStepState(execution.Status.ID, step.ID, state.StateSkipped, reason)
+	}
+	e.emit(event.Event{
+		Timestamp:  time.Now(),
+		PipelineID: execution.Status.ID,
+		StepID:     step.ID,
+		State:      event.StateSkipped,
+		Message:    "skipped: " + reason,
+	})
+}
+
+// validateCanarySteps checks canary sample rates and that nothing depends
+// on a canary step's artifacts without marking them optional.
+func validateCanarySteps(p *Pipeline) error {
+	canaries := make(map[string]bool)
+	for _, step := range p.Steps {
+		if step.Canary == nil {
+			continue
+		}
+		if step.Canary.Sample <= 0 || step.Canary.Sample > 1 {
+			return fmt.Errorf("step %q: canary.sample must be in (0, 1], got %g", step.ID, step.Canary.Sample)
+		}
+		canaries[step.ID] = true
+	}
+	if len(canaries) == 0 {
+		return nil
+	}
+	for _, step := range p.Steps {
+		for _, ref := range step.Memory.InjectArtifacts {
+			if ref.Pipeline == "" && ref.FromPipeline == "" && canaries[ref.Step] && !ref.Optional {
+				return fmt.Errorf("step %q injects artifact %q from canary step %q: mark the injection optional, since the canary step does not run every time", step.ID, ref.Artifact, ref.Step)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/testutil"
+)
+
+func TestCanarySampled(t *testing.T) {
+	step := &Step{ID: "judge", Canary: &CanaryConfig{Sample: 0.25}}
+
+	sampled := 0
+	const runs = 4000
+	for i := 0; i < runs; i++ {
+		runID := fmt.Sprintf("impl-issue-%d", i)
+		got := canarySampled(runID, step)
+		if got != canarySampled(runID, step) {
+			t.Fatalf("canarySampled(%q) is not deterministic", runID)
+		}
+		if got {
+			sampled++
+		}
+	}
+	if rate := float64(sampled) / runs; rate < 0.2 || rate > 0.3 {
+		t.Errorf("sample rate = %.3f, want about 0.25", rate)
+	}
+
+	if !canarySampled("run-1", &Step{ID: "plain"}) {
+		t.Error("step without canary must always run")
+	}
+	if !canarySampled("run-1", &Step{ID: "full", Canary: &CanaryConfig{Sample: 1}}) {
+		t.Error("sample 1 must always run")
+	}
+}
+
+func TestValidateCanarySteps(t *testing.T) {
+	judge := Step{ID: "judge", Persona: "reviewer", Canary: &CanaryConfig{Sample: 0.1}}
+	consumer := func(optional bool) Step {
+		return Step{ID: "report", Persona: "writer", Dependencies: []string{"judge"},
+			Memory: MemoryConfig{InjectArtifacts: []ArtifactRef{{Step: "judge", Artifact: "verdict", As: "verdict", Optional: optional}}}}
+	}
+
+	if err := validateCanarySteps(&Pipeline{Steps: []Step{judge, consumer(true)}}); err != nil {
+		t.Errorf("optional injection: unexpected error %v", err)
+	}
+	err := validateCanarySteps(&Pipeline{Steps: []Step{judge, consumer(false)}})
+	if err == nil || !strings.Contains(err.Error(), "canary step \"judge\"") {
+		t.Errorf("required injection: error = %v", err)
+	}
+
+	for _, sample := range []float64{0, -0.5, 1.5} {
+		bad := Step{ID: "judge", Canary: &CanaryConfig{Sample: sample}}
+		if err := validateCanarySteps(&Pipeline{Steps: []Step{bad}}); err == nil {
+			t.Errorf("sample %g: expected an error", sample)
+		}
+	}
+}
+
+func TestExecute_CanarySkipDoesNotCascade(t *testing.T) {
+	collector := testutil.NewEventCollector()
+	executor := NewDefaultPipelineExecutor(
+		adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`)),
+		WithEmitter(collector),
+	)
+	m := testutil.CreateTestManifest(t.TempDir())
+
+	// A sample this small is never drawn in practice.
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "canary-test"},
+		Steps: []Step{
+			{ID: "judge", Persona: "navigator", Exec: ExecConfig{Source: "judge"}, Canary: &CanaryConfig{Sample: 1e-12}},
+			{ID: "report", Persona: "navigator", Exec: ExecConfig{Source: "report"}, Dependencies: []string{"judge"}},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := executor.Execute(ctx, p, m, "input"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var judgeSkipped, reportCompleted bool
+	for _, evt := range collector.GetEvents() {
+		switch {
+		case evt.StepID == "judge" && evt.State == stateSkipped:
+			judgeSkipped = strings.Contains(evt.Message, "canary")
+		case evt.StepID == "report" && evt.State == stateCompleted:
+			reportCompleted = true
+		case evt.StepID == "report" && evt.State == stateSkipped:
+			t.Error("dependent of a canary step was skipped")
+		}
+	}
+	if !judgeSkipped {
+		t.Error("expected a canary skip event for judge")
+	}
+	if !reportCompleted {
+		t.Error("expected report to complete")
+	}
+}
@@ -0,0 +1,279 @@
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/audit"
+	"github.com/recinq/wave/internal/state"
+)
+
+// ChangesArtifactName is the artifact under which a step's filesystem change
+// summary is registered ("<step-id>:changes").
+const ChangesArtifactName = "changes"
+
+// isStepRecordArtifact reports whether an artifact key names one of the
+// records Wave writes for every step (transcript, change summary) rather
+// than an output the step declared.
+func isStepRecordArtifact(key string) bool {
+	return strings.HasSuffix(key, ":"+TranscriptArtifactName) || strings.HasSuffix(key, ":"+ChangesArtifactName)
+}
+
+// changeSummaryExcludes are workspace paths Wave and the adapters write
+// around the agent run: artifacts, provisioned skills, adapter settings and
+// the system prompt files codex, opencode and gemini read.
+var changeSummaryExcludes = []string{
+	".agents/artifacts", ".agents/output", ".agents/skills",
+	".claude", ".wave-skill-commands", "AGENTS.md", "GEMINI.md",
+}
+
+// workspaceSnapshot records the content of a step workspace as a git tree,
+// so the files an agent changed can be diffed after it exits whether or not
+// it committed them. Every workspace is a git repository: worktrees by
+// construction, and directory workspaces are git-initialized. The tree is
+// staged through a private index, leaving the workspace's own index, HEAD
+// and branches untouched; the objects it writes are unreferenced and
+// pruned by git gc.
+type workspaceSnapshot struct {
+	dir   string
+	index string
+	tree  string
+}
+
+// snapshotWorkspace records the current content of dir. It returns nil when
+// dir is not a git repository or git fails; the step then records no change
+// summary.
+func snapshotWorkspace(dir string) *workspaceSnapshot {
+	if dir == "" {
+		return nil
+	}
+	tmp, err := os.MkdirTemp("", "wave-changes-")
+	if err != nil {
+		return nil
+	}
+	s := &workspaceSnapshot{dir: dir, index: filepath.Join(tmp, "index")}
+
+	// Seed the private index from the workspace's own so files that match
+	// it are not rehashed. Without one, git starts from an empty index.
+	if gitIndex, err := s.git("rev-parse", "--git-path", "index"); err == nil {
+		gitIndex = strings.TrimSpace(gitIndex)
+		if !filepath.IsAbs(gitIndex) {
+			gitIndex = filepath.Join(dir, gitIndex)
+		}
+		if data, err := os.ReadFile(gitIndex); err == nil {
+			_ = os.WriteFile(s.index, data, 0600)
+		}
+	}
+
+	if s.tree, err = s.writeTree(); err != nil {
+		s.close()
+		return nil
+	}
+	return s
+}
+
+// close removes the private index.
+func (s *workspaceSnapshot) close() {
+	os.RemoveAll(filepath.Dir(s.index))
+}
+
+// changes diffs the workspace against the snapshot and releases it. A nil
+// snapshot yields nil.
+func (s *workspaceSnapshot) changes() *state.StepChangeRecord {
+	if s == nil {
+		return nil
+	}
+	defer s.close()
+
+	after, err := s.writeTree()
+	if err != nil {
+		return nil
+	}
+	nameStatus, err := s.git("diff-tree", "-r", "-z", "--no-renames", "--name-status", s.tree, after)
+	if err != nil {
+		return nil
+	}
+	numstat, err := s.git("diff-tree", "-r", "-z", "--no-renames", "--numstat", s.tree, after)
+	if err != nil {
+		return nil
+	}
+	rec := parseChangeSummary(nameStatus, numstat)
+	rec.BaseTree = s.tree
+	return rec
+}
+
+// writeTree stages the workspace into the private index and returns the
+// resulting tree. Ignored files and changeSummaryExcludes are left out.
+func (s *workspaceSnapshot) writeTree() (string, error) {
+	args := []string{"add", "--all", "--", "."}
+	for _, p := range changeSummaryExcludes {
+		args = append(args, ":(top,exclude)"+p)
+	}
+	if _, err := s.git(args...); err != nil {
+		return "", err
+	}
+	tree, err := s.git("write-tree")
+	return strings.TrimSpace(tree), err
+}
+
+func (s *workspaceSnapshot) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = s.dir
+	cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+s.index)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// parseChangeSummary builds a change record from the NUL-separated
+// --name-status and --numstat output of git diff-tree.
+func parseChangeSummary(nameStatus, numstat string) *state.StepChangeRecord {
+	rec := &state.StepChangeRecord{Files: []state.FileChange{}}
+	index := make(map[string]int)
+
+	fields := strings.Split(strings.TrimSuffix(nameStatus, "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		change := state.FileChange{Path: fields[i+1]}
+		switch fields[i] {
+		case "A":
+			change.Status = state.FileAdded
+			rec.FilesAdded++
+		case "D":
+			change.Status = state.FileDeleted
+			rec.FilesDeleted++
+		default:
+			change.Status = state.FileModified
+			rec.FilesModified++
+		}
+		index[change.Path] = len(rec.Files)
+		rec.Files = append(rec.Files, change)
+	}
+
+	// numstat -z: "<insertions>\t<deletions>\t<path>\0", "-" for binary files.
+	for _, line := range strings.Split(strings.TrimSuffix(numstat, "\x00"), "\x00") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		i, ok := index[parts[2]]
+		if !ok {
+			continue
+		}
+		if parts[0] == "-" {
+			rec.Files[i].Binary = true
+			continue
+		}
+		rec.Files[i].Insertions, _ = strconv.Atoi(parts[0])
+		rec.Files[i].Deletions, _ = strconv.Atoi(parts[1])
+		rec.Insertions += rec.Files[i].Insertions
+		rec.Deletions += rec.Files[i].Deletions
+	}
+	return rec
+}
+
+// recordStepChanges persists a step attempt's change summary to
+// <artifact-dir>/<step-id>/changes.json, registered like any other step
+// artifact, and as a step_change row. A step that declares its own
+// "changes" output keeps it. Best-effort; errors are traced and ignored.
+func (e *DefaultPipelineExecutor) recordStepChanges(execution *PipelineExecution, step *Step, workspacePath string, rec *state.StepChangeRecord) {
+	if rec == nil {
+		return
+	}
+	rec.RunID = execution.Status.ID
+	rec.StepID = step.ID
+	rec.CreatedAt = time.Now()
+
+	declared := false
+	for _, art := range step.OutputArtifacts {
+		if art.Name == ChangesArtifactName {
+			declared = true
+			break
+		}
+	}
+	if !declared {
+		e.writeChangesArtifact(execution, step, workspacePath, rec)
+	}
+	if e.store != nil {
+		_ = e.store.SaveStepChanges(rec)
+	}
+}
+
+func (e *DefaultPipelineExecutor) writeChangesArtifact(execution *PipelineExecution, step *Step, workspacePath string, rec *state.StepChangeRecord) {
+	rec.ArtifactPath = stepRecordArtifactPath(execution, workspacePath, step.ID, ChangesArtifactName)
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil || !e.writeStepRecordArtifact(execution, step, ChangesArtifactName, rec.ArtifactPath, data) {
+		rec.ArtifactPath = ""
+	}
+}
+
+// loadStepChanges reads the change summary a step recorded, or nil when it
+// has none: command and composition steps, workspaces git could not
+// snapshot, or a step that declared its own "changes" output.
+func loadStepChanges(execution *PipelineExecution, stepID string) *state.StepChangeRecord {
+	execution.mu.Lock()
+	path := execution.ArtifactPaths[stepID+":"+ChangesArtifactName]
+	execution.mu.Unlock()
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var rec state.StepChangeRecord
+	if json.Unmarshal(data, &rec) != nil {
+		return nil
+	}
+	return &rec
+}
+
+// stepRecordArtifactPath returns where the step record artifact name of a
+// step is written: <artifact-dir>/<step-id>/<name>.json in its workspace.
+func stepRecordArtifactPath(execution *PipelineExecution, workspacePath, stepID, name string) string {
+	return filepath.Join(workspacePath, execution.Manifest.Runtime.Artifacts.GetDefaultArtifactDir(), stepID, name+".json")
+}
+
+// writeStepRecordArtifact writes a JSON step record to path and registers
+// it as "<step-id>:<name>". Failures are traced; it reports whether the
+// artifact was written.
+func (e *DefaultPipelineExecutor) writeStepRecordArtifact(execution *PipelineExecution, step *Step, name, path string, data []byte) bool {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		e.trace(audit.TraceArtifactWrite, step.ID, 0, map[string]string{
+			"artifact": name,
+			"path":     path,
+			"error":    err.Error(),
+		})
+		return false
+	}
+
+	key := step.ID + ":" + name
+	execution.mu.Lock()
+	execution.ArtifactPaths[key] = path
+	execution.mu.Unlock()
+	e.trace(audit.TraceArtifactWrite, step.ID, 0, map[string]string{
+		"artifact": name,
+		"path":     path,
+		"size":     fmt.Sprintf("%d", len(data)),
+	})
+
+	if e.store != nil {
+		sum := e.registerArtifact(execution.Status.ID, step, name, path, "json", int64(len(data)))
+		recordArtifactChecksum(execution, key, sum)
+	}
+	return true
+}
@@ -0,0 +1,193 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/metrics"
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func writeWorkspaceFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestWorkspaceSnapshot_DirectoryWorkspace(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	gitRun(t, dir, "init", "-q")
+	writeWorkspaceFile(t, filepath.Join(dir, "keep.txt"), "a\nb\nc\n")
+	writeWorkspaceFile(t, filepath.Join(dir, "gone.txt"), "x\ny\n")
+	writeWorkspaceFile(t, filepath.Join(dir, "src", "main.go"), "package main\n")
+
+	snap := snapshotWorkspace(dir)
+	require.NotNil(t, snap)
+
+	writeWorkspaceFile(t, filepath.Join(dir, "keep.txt"), "a\nB\nc\nd\n")
+	require.NoError(t, os.Remove(filepath.Join(dir, "gone.txt")))
+	writeWorkspaceFile(t, filepath.Join(dir, "src", "new.go"), "package main\n\nfunc f() {}\n")
+	writeWorkspaceFile(t, filepath.Join(dir, "logo.png"), "\x89PNG\x00\x01\x02")
+	// Wave's own bookkeeping is not the agent's work.
+	writeWorkspaceFile(t, filepath.Join(dir, ".agents", "output", "plan.json"), "{}")
+	writeWorkspaceFile(t, filepath.Join(dir, ".claude", "settings.json"), "{}")
+
+	rec := snap.changes()
+	require.NotNil(t, rec)
+	assert.Equal(t, 2, rec.FilesAdded)
+	assert.Equal(t, 1, rec.FilesModified)
+	assert.Equal(t, 1, rec.FilesDeleted)
+	assert.Equal(t, 4, rec.FilesChanged())
+	assert.Equal(t, 5, rec.Insertions)
+	assert.Equal(t, 3, rec.Deletions)
+
+	byPath := make(map[string]state.FileChange)
+	for _, f := range rec.Files {
+		byPath[f.Path] = f
+	}
+	assert.Equal(t, state.FileChange{Path: "keep.txt", Status: state.FileModified, Insertions: 2, Deletions: 1}, byPath["keep.txt"])
+	assert.Equal(t, state.FileChange{Path: "gone.txt", Status: state.FileDeleted, Deletions: 2}, byPath["gone.txt"])
+	assert.Equal(t, state.FileChange{Path: "src/new.go", Status: state.FileAdded, Insertions: 3}, byPath["src/new.go"])
+	assert.Equal(t, state.FileChange{Path: "logo.png", Status: state.FileAdded, Binary: true}, byPath["logo.png"])
+
+	_, err := os.Stat(snap.index)
+	assert.True(t, os.IsNotExist(err), "private index should be removed")
+}
+
+func TestWorkspaceSnapshot_CommittedAndUncommittedChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	gitRun(t, dir, "init", "-q")
+	writeWorkspaceFile(t, filepath.Join(dir, ".gitignore"), "build/\n")
+	writeWorkspaceFile(t, filepath.Join(dir, "README.md"), "hello\n")
+	gitRun(t, dir, "add", "-A")
+	gitRun(t, dir, "commit", "-q", "-m", "init")
+
+	snap := snapshotWorkspace(dir)
+	require.NotNil(t, snap)
+
+	writeWorkspaceFile(t, filepath.Join(dir, "README.md"), "hello\nworld\n")
+	gitRun(t, dir, "commit", "-q", "-am", "agent commit")
+	writeWorkspaceFile(t, filepath.Join(dir, "notes.md"), "draft\n")
+	writeWorkspaceFile(t, filepath.Join(dir, "build", "out.bin"), "ignored")
+
+	rec := snap.changes()
+	require.NotNil(t, rec)
+	assert.Equal(t, 1, rec.FilesAdded)
+	assert.Equal(t, 1, rec.FilesModified)
+	assert.Equal(t, 2, rec.Insertions)
+	assert.Zero(t, rec.Deletions)
+
+	// The workspace's own index is untouched: notes.md is still untracked.
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "?? notes.md\n", string(out))
+}
+
+func TestWorkspaceSnapshot_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+	assert.Nil(t, snapshotWorkspace(dir))
+
+	var snap *workspaceSnapshot
+	assert.Nil(t, snap.changes())
+}
+
+// fileWritingAdapter writes files into the step workspace before returning
+// the mock result, standing in for an agent that edits code.
+type fileWritingAdapter struct {
+	*adaptertest.MockAdapter
+	files map[string]string
+}
+
+func (a *fileWritingAdapter) Run(ctx context.Context, cfg adapter.AdapterRunConfig) (*adapter.AdapterResult, error) {
+	for name, content := range a.files {
+		path := filepath.Join(cfg.WorkspacePath, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return nil, err
+		}
+	}
+	return a.MockAdapter.Run(ctx, cfg)
+}
+
+func TestExecute_RecordsStepChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tmpDir := t.TempDir()
+	store, err := state.NewStateStore(filepath.Join(tmpDir, "state.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	runID, err := store.CreateRun("changes", "test")
+	require.NoError(t, err)
+
+	executor := NewDefaultPipelineExecutor(
+		&fileWritingAdapter{
+			MockAdapter: adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`)),
+			files:       map[string]string{"main.go": "package main\n\nfunc main() {}\n", "docs/README.md": "# Hi\n"},
+		},
+		WithEmitter(testutil.NewEventCollector()),
+		WithStateStore(store),
+		WithRunID(runID),
+	)
+	m := testutil.CreateTestManifest(tmpDir)
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "changes"},
+		Steps:    []Step{{ID: "implement", Persona: "navigator", Exec: ExecConfig{Source: "write code"}}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, executor.Execute(ctx, p, m, "test"))
+
+	records, err := store.GetStepChanges(runID, "implement")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	rec := records[0]
+	assert.Equal(t, 2, rec.FilesAdded)
+	assert.Equal(t, 4, rec.Insertions)
+
+	data, err := os.ReadFile(rec.ArtifactPath)
+	require.NoError(t, err)
+	var artifact state.StepChangeRecord
+	require.NoError(t, json.Unmarshal(data, &artifact))
+	assert.Equal(t, rec.Files, artifact.Files)
+	assert.Equal(t, rec.ArtifactPath, executor.LastExecution().ArtifactPaths["implement:"+ChangesArtifactName])
+
+	perf, err := metrics.NewStore(state.UnderlyingDB(store)).GetPerformanceMetrics(runID, "implement")
+	require.NoError(t, err)
+	require.Len(t, perf, 1)
+	assert.Equal(t, 2, perf[0].FilesModified)
+}
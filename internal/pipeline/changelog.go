@@ -0,0 +1,273 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ChangelogStepID is the ID of the step generated from a pipeline's
+// changelog block.
+const ChangelogStepID = "changelog"
+
+// changelogOutputPath is where the changelog step writes its result,
+// registered as the "changelog:changelog" artifact.
+const changelogOutputPath = ".agents/output/changelog.json"
+
+// unresolvedChangelogVarRe matches {{ changelog.* }} placeholders left
+// unresolved because the changelog step has not run or produced no value.
+var unresolvedChangelogVarRe = regexp.MustCompile(`\{\{\s*changelog\.\w+\s*\}\}`)
+
+// ChangelogConfig is the top-level changelog section. It adds a step that
+// has a persona describe the run's changes as a Conventional Commits
+// message, a changelog entry and a pull request body, and exposes them as
+// template variables:
+//
+//	changelog:
+//	  persona: summarizer
+//	  before: [create-pr]
+//
+// The step runs after From (default: the steps that end the pipeline) and
+// before the Before steps, whose prompts and scripts can use
+// {{ changelog.commit_message }} and {{ changelog.pr_body }}. Without
+// Before it is the last step of the run.
+type ChangelogConfig struct {
+	Persona string   `yaml:"persona"`
+	Model   string   `yaml:"model,omitempty"`
+	From    []string `yaml:"from,omitempty"`   // Steps whose changes are described; default the last steps not in Before
+	Before  []string `yaml:"before,omitempty"` // Steps that consume the generated message
+}
+
+// changelogPrompt instructs the changelog persona. The step's injected
+// artifacts are <step>-changes (each step's change summary) and
+// <step>-<artifact> (the outputs of the From steps).
+const changelogPrompt = `Describe the changes this pipeline run made as a commit message, a changelog entry and a pull request description.
+
+The change summaries of the run's steps (files added, modified and deleted, with line counts) and the outputs of the steps that made the changes are in .agents/artifacts/. When the workspace is a git checkout of the changed code, read the diff with git for detail.
+
+Pipeline input: {{ input }}
+
+Write ` + changelogOutputPath + ` with these fields:
+- type: the Conventional Commits type: feat, fix, docs, refactor, perf, test, build, ci, chore, style or revert
+- scope: the area of the code the change touches, lowercase; empty when it spans the project
+- subject: imperative summary without the type prefix or a trailing period, at most 60 characters
+- body: what changed and why, wrapped at 72 columns; empty when the subject says it all
+- breaking: true when existing users must change something, with the migration described in body
+- changelog_entry: one sentence for the project's changelog, written for its users
+- pr_body: a Markdown pull request description: a short summary paragraph, then the notable changes as bullets
+
+Describe only changes that the artifacts or the diff show.`
+
+// changelogSchema is the JSON schema the changelog step's output must match.
+const changelogSchema = `{
+  "type": "object",
+  "required": ["type", "subject", "changelog_entry", "pr_body"],
+  "properties": {
+    "type": {"enum": ["feat", "fix", "docs", "refactor", "perf", "test", "build", "ci", "chore", "style", "revert"]},
+    "scope": {"type": "string"},
+    "subject": {"type": "string", "minLength": 1},
+    "body": {"type": "string"},
+    "breaking": {"type": "boolean"},
+    "changelog_entry": {"type": "string", "minLength": 1},
+    "pr_body": {"type": "string", "minLength": 1}
+  }
+}`
+
+// changelogResult is the content of the changelog step's output.
+type changelogResult struct {
+	Type           string `json:"type"`
+	Scope          string `json:"scope"`
+	Subject        string `json:"subject"`
+	Body           string `json:"body"`
+	Breaking       bool   `json:"breaking"`
+	ChangelogEntry string `json:"changelog_entry"`
+	PRBody         string `json:"pr_body"`
+}
+
+// header returns the Conventional Commits header, "type(scope)!: subject".
+func (r changelogResult) header() string {
+	h := r.Type
+	if r.Scope != "" {
+		h += "(" + r.Scope + ")"
+	}
+	if r.Breaking {
+		h += "!"
+	}
+	return h + ": " + strings.TrimSuffix(strings.TrimSpace(r.Subject), ".")
+}
+
+// commitMessage returns the full commit message: header, then body.
+func (r changelogResult) commitMessage() string {
+	msg := r.header()
+	if body := strings.TrimSpace(r.Body); body != "" {
+		msg += "\n\n" + body
+	}
+	return msg
+}
+
+// applyChangelog appends the step generated from the pipeline's changelog
+// block and makes its Before steps depend on it. It runs at load time, so
+// the executor only ever sees plain steps.
+func applyChangelog(p *Pipeline) error {
+	cfg := p.Changelog
+	if cfg == nil {
+		return nil
+	}
+	if cfg.Persona == "" {
+		return fmt.Errorf("changelog: persona is required")
+	}
+	if isGraphPipeline(p) {
+		return fmt.Errorf("changelog: not supported in graph pipelines (steps with edges or conditional steps)")
+	}
+	byID := make(map[string]*Step, len(p.Steps))
+	for i := range p.Steps {
+		byID[p.Steps[i].ID] = &p.Steps[i]
+	}
+	if _, taken := byID[ChangelogStepID]; taken {
+		return fmt.Errorf("changelog: step id %q is reserved for the generated changelog step", ChangelogStepID)
+	}
+	for _, id := range append(append([]string(nil), cfg.Before...), cfg.From...) {
+		if byID[id] == nil {
+			return fmt.Errorf("changelog: references non-existent step %q", id)
+		}
+	}
+
+	// Steps downstream of a Before step run after the changelog and
+	// cannot contribute to it.
+	after := make(map[string]bool)
+	for _, id := range cfg.Before {
+		after[id] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, s := range p.Steps {
+			if after[s.ID] {
+				continue
+			}
+			for _, dep := range s.Dependencies {
+				if after[dep] {
+					after[s.ID] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	from := cfg.From
+	if len(from) == 0 {
+		hasDependent := make(map[string]bool)
+		for _, s := range p.Steps {
+			if !after[s.ID] {
+				for _, dep := range s.Dependencies {
+					hasDependent[dep] = true
+				}
+			}
+		}
+		for _, s := range p.Steps {
+			if !after[s.ID] && !s.ReworkOnly && !hasDependent[s.ID] {
+				from = append(from, s.ID)
+			}
+		}
+		if len(from) == 0 {
+			return fmt.Errorf("changelog: no steps run before %v", cfg.Before)
+		}
+	}
+	for _, id := range from {
+		if after[id] {
+			return fmt.Errorf("changelog: step %q runs after a before step and cannot be described", id)
+		}
+	}
+
+	// Inject the change summaries of every step the changelog describes and
+	// the declared outputs of the From steps.
+	described := make(map[string]bool)
+	var visit func(id string)
+	visit = func(id string) {
+		if described[id] {
+			return
+		}
+		described[id] = true
+		for _, dep := range byID[id].Dependencies {
+			if byID[dep] != nil {
+				visit(dep)
+			}
+		}
+	}
+	for _, id := range from {
+		visit(id)
+	}
+	var refs []ArtifactRef
+	for _, s := range p.Steps {
+		if !described[s.ID] || s.Persona == "" || s.Type != "" || s.IsCompositionStep() {
+			continue
+		}
+		refs = append(refs, ArtifactRef{Step: s.ID, Artifact: ChangesArtifactName, As: s.ID + "-" + ChangesArtifactName, Optional: true})
+	}
+	var workspace WorkspaceConfig
+	for _, id := range from {
+		s := byID[id]
+		for _, out := range s.OutputArtifacts {
+			refs = append(refs, ArtifactRef{Step: id, Artifact: out.Name, As: id + "-" + out.Name, Optional: true})
+		}
+		// Share the worktree the changes were made in, so the persona can
+		// read the diff.
+		if workspace.Ref == "" {
+			switch {
+			case s.Workspace.Ref != "":
+				workspace.Ref = s.Workspace.Ref
+			case s.Workspace.Type == "worktree":
+				workspace.Ref = id
+			}
+		}
+	}
+	for _, id := range cfg.Before {
+		byID[id].Dependencies = append(byID[id].Dependencies, ChangelogStepID)
+	}
+	p.Steps = append(p.Steps, Step{
+		ID:           ChangelogStepID,
+		Persona:      cfg.Persona,
+		Model:        cfg.Model,
+		Dependencies: append([]string(nil), from...),
+		Memory:       MemoryConfig{Strategy: "fresh", InjectArtifacts: refs},
+		Workspace:    workspace,
+		Exec:         ExecConfig{Type: "prompt", Source: changelogPrompt},
+		OutputArtifacts: []ArtifactDef{
+			{Name: ChangelogStepID, Path: changelogOutputPath, Type: "json", Required: true},
+		},
+		Handover: HandoverConfig{Contract: ContractConfig{
+			Type:      "json_schema",
+			Source:    changelogOutputPath,
+			Schema:    changelogSchema,
+			OnFailure: OnFailureFail,
+		}},
+		Retry: RetryConfig{MaxAttempts: 2},
+	})
+	return nil
+}
+
+// exposeChangelog reads the output of the generated changelog step and
+// sets the {{ changelog.* }} template variables from it.
+func (e *DefaultPipelineExecutor) exposeChangelog(execution *PipelineExecution, step *Step) {
+	if execution.Pipeline == nil || execution.Pipeline.Changelog == nil || step.ID != ChangelogStepID || execution.Context == nil {
+		return
+	}
+	execution.mu.Lock()
+	path := execution.ArtifactPaths[ChangelogStepID+":"+ChangelogStepID]
+	execution.mu.Unlock()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var r changelogResult
+	if json.Unmarshal(data, &r) != nil {
+		return
+	}
+	execution.Context.SetCustomVariable("changelog.type", r.Type)
+	execution.Context.SetCustomVariable("changelog.subject", r.header())
+	execution.Context.SetCustomVariable("changelog.commit_message", r.commitMessage())
+	execution.Context.SetCustomVariable("changelog.entry", strings.TrimSpace(r.ChangelogEntry))
+	execution.Context.SetCustomVariable("changelog.pr_body", strings.TrimSpace(r.PRBody))
+}
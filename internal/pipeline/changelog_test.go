@@ -0,0 +1,130 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYAMLPipelineLoader_Changelog(t *testing.T) {
+	p, err := (&YAMLPipelineLoader{}).Unmarshal([]byte(`kind: WavePipeline
+metadata:
+  name: ship
+changelog:
+  persona: summarizer
+  model: cheapest
+  before: [create-pr]
+steps:
+  - id: plan
+    persona: navigator
+    exec: {type: prompt, source: plan}
+  - id: implement
+    persona: craftsman
+    dependencies: [plan]
+    workspace: {type: worktree, branch: feature}
+    output_artifacts:
+      - {name: notes, path: .agents/output/notes.md}
+    exec: {type: prompt, source: implement}
+  - id: create-pr
+    persona: craftsman
+    dependencies: [implement]
+    workspace: {ref: implement}
+    exec: {type: prompt, source: "gh pr create --body '{{ changelog.pr_body }}'"}
+  - id: notify
+    type: command
+    dependencies: [create-pr]
+    script: echo done
+`))
+	require.NoError(t, err)
+	require.NoError(t, (&DAGValidator{}).ValidateDAG(p))
+
+	require.Len(t, p.Steps, 5)
+	step := p.Steps[4]
+	assert.Equal(t, ChangelogStepID, step.ID)
+	assert.Equal(t, "summarizer", step.Persona)
+	assert.Equal(t, "cheapest", step.Model)
+	assert.Equal(t, []string{"implement"}, step.Dependencies, "the last step before create-pr")
+	assert.Equal(t, "implement", step.Workspace.Ref)
+	assert.Equal(t, []ArtifactRef{
+		{Step: "plan", Artifact: "changes", As: "plan-changes", Optional: true},
+		{Step: "implement", Artifact: "changes", As: "implement-changes", Optional: true},
+		{Step: "implement", Artifact: "notes", As: "implement-notes", Optional: true},
+	}, step.Memory.InjectArtifacts)
+	assert.Equal(t, "json_schema", step.Handover.Contract.Type)
+	assert.Equal(t, []string{"implement", ChangelogStepID}, p.Steps[2].Dependencies)
+	assert.Equal(t, []string{"create-pr"}, p.Steps[3].Dependencies)
+}
+
+func TestApplyChangelog(t *testing.T) {
+	steps := func() []Step {
+		return []Step{
+			{ID: "a", Persona: "craftsman"},
+			{ID: "b", Persona: "craftsman"},
+			{ID: "c", Persona: "craftsman", Dependencies: []string{"a"}},
+		}
+	}
+
+	t.Run("runs last by default", func(t *testing.T) {
+		p := &Pipeline{Steps: steps(), Changelog: &ChangelogConfig{Persona: "summarizer"}}
+		require.NoError(t, applyChangelog(p))
+		assert.Equal(t, []string{"b", "c"}, p.Steps[3].Dependencies)
+	})
+
+	t.Run("explicit from", func(t *testing.T) {
+		p := &Pipeline{Steps: steps(), Changelog: &ChangelogConfig{Persona: "summarizer", From: []string{"a"}}}
+		require.NoError(t, applyChangelog(p))
+		assert.Equal(t, []string{"a"}, p.Steps[3].Dependencies)
+		assert.Len(t, p.Steps[3].Memory.InjectArtifacts, 1)
+	})
+
+	errs := map[string]*ChangelogConfig{
+		"persona is required":               {},
+		`non-existent step "missing"`:       {Persona: "s", Before: []string{"missing"}},
+		`step "c" runs after a before step`: {Persona: "s", From: []string{"c"}, Before: []string{"a"}},
+	}
+	for want, cfg := range errs {
+		p := &Pipeline{Steps: steps(), Changelog: cfg}
+		err := applyChangelog(p)
+		require.Error(t, err, want)
+		assert.Contains(t, err.Error(), want)
+	}
+
+	p := &Pipeline{Steps: append(steps(), Step{ID: ChangelogStepID}), Changelog: &ChangelogConfig{Persona: "s"}}
+	assert.ErrorContains(t, applyChangelog(p), "reserved")
+}
+
+func TestExposeChangelog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changelog.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+  "type": "feat",
+  "scope": "pipeline",
+  "subject": "add changelog generation.",
+  "body": "Generate commit messages once per run.",
+  "breaking": true,
+  "changelog_entry": "Pipelines can generate their commit message.",
+  "pr_body": "## Summary\n\nAdds changelog generation."
+}`), 0644))
+
+	ctx := NewPipelineContext("run-1", "ship", ChangelogStepID)
+	execution := &PipelineExecution{
+		Pipeline:      &Pipeline{Changelog: &ChangelogConfig{Persona: "summarizer"}},
+		ArtifactPaths: map[string]string{"changelog:changelog": path},
+		Context:       ctx,
+	}
+	executor := NewDefaultPipelineExecutor(nil)
+	executor.exposeChangelog(execution, &Step{ID: ChangelogStepID})
+
+	assert.Equal(t, "feat(pipeline)!: add changelog generation", ctx.ResolvePlaceholders("{{ changelog.subject }}"))
+	assert.Equal(t, "feat(pipeline)!: add changelog generation\n\nGenerate commit messages once per run.",
+		ctx.ResolvePlaceholders("{{ changelog.commit_message }}"))
+	assert.Equal(t, "Pipelines can generate their commit message.", ctx.ResolvePlaceholders("{{changelog.entry}}"))
+	assert.Equal(t, "## Summary\n\nAdds changelog generation.", ctx.ResolvePlaceholders("{{ changelog.pr_body }}"))
+}
+
+func TestResolvePlaceholders_StripsUnresolvedChangelog(t *testing.T) {
+	ctx := NewPipelineContext("run-1", "ship", "create-pr")
+	assert.Equal(t, "body: ", ctx.ResolvePlaceholders("body: {{ changelog.pr_body }}"))
+}
@@ -37,28 +37,29 @@ func (opts ChatWorkspaceOptions) effectiveMode() ChatMode {
 }
 
 // PrepareChatWorkspace creates a workspace directory with CLAUDE.md and settings.json
-// for an interactive wave chat session. Returns the workspace path.
-func PrepareChatWorkspace(ctx *ChatContext, opts ChatWorkspaceOptions) (string, error) {
+// for an interactive wave chat session. Returns the workspace path and the
+// function that removes CLAUDE.md again once the session ends.
+func PrepareChatWorkspace(ctx *ChatContext, opts ChatWorkspaceOptions) (string, func(), error) {
 	// 1. Create chat workspace directory
 	wsDir := filepath.Join(ctx.ProjectRoot, ".agents", "chat", ctx.Run.RunID)
 	if err := os.MkdirAll(wsDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create chat workspace: %w", err)
+		return "", nil, fmt.Errorf("failed to create chat workspace: %w", err)
 	}
 
 	mode := opts.effectiveMode()
 
 	// 2. Build and write CLAUDE.md
 	claudeMd := buildChatClaudeMd(ctx, mode, opts.StepFilter, opts.ArtifactName)
-	instructionFile := adapter.InstructionFilename("claude")
-	mdPath := filepath.Join(wsDir, instructionFile)
-	if err := os.WriteFile(mdPath, []byte(claudeMd), 0644); err != nil {
-		return "", fmt.Errorf("failed to write %s: %w", instructionFile, err)
+	restore, err := adapter.WriteInstructions(wsDir, adapter.InstructionFilename("claude"), []byte(claudeMd))
+	if err != nil {
+		return "", nil, err
 	}
 
 	// 3. Build and write .claude/settings.json
 	settingsDir := filepath.Join(wsDir, ".claude")
 	if err := os.MkdirAll(settingsDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create .claude directory: %w", err)
+		restore()
+		return "", nil, fmt.Errorf("failed to create .claude directory: %w", err)
 	}
 
 	model := opts.Model
@@ -109,22 +110,25 @@ func PrepareChatWorkspace(ctx *ChatContext, opts ChatWorkspaceOptions) (string,
 
 	settingsData, err := json.MarshalIndent(settings, "", "  ")
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal settings: %w", err)
+		restore()
+		return "", nil, fmt.Errorf("failed to marshal settings: %w", err)
 	}
 
 	settingsPath := filepath.Join(settingsDir, "settings.json")
 	if err := os.WriteFile(settingsPath, settingsData, 0644); err != nil {
-		return "", fmt.Errorf("failed to write settings.json: %w", err)
+		restore()
+		return "", nil, fmt.Errorf("failed to write settings.json: %w", err)
 	}
 
 	// 4. Provision slash commands for manipulate mode
 	if mode == ChatModeManipulate {
 		if err := provisionSlashCommands(wsDir); err != nil {
-			return "", fmt.Errorf("failed to provision slash commands: %w", err)
+			restore()
+			return "", nil, fmt.Errorf("failed to provision slash commands: %w", err)
 		}
 	}
 
-	return wsDir, nil
+	return wsDir, restore, nil
 }
 
 type chatSettings struct {
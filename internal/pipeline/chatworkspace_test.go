@@ -50,10 +50,11 @@ func TestPrepareChatWorkspace(t *testing.T) {
 		ProjectRoot: tmpDir,
 	}
 
-	wsPath, err := PrepareChatWorkspace(ctx, ChatWorkspaceOptions{Model: "sonnet"})
+	wsPath, restore, err := PrepareChatWorkspace(ctx, ChatWorkspaceOptions{Model: "sonnet"})
 	if err != nil {
 		t.Fatalf("PrepareChatWorkspace failed: %v", err)
 	}
+	defer restore()
 
 	// Verify workspace directory was created
 	expectedDir := filepath.Join(tmpDir, ".agents", "chat", "test-run-001")
@@ -146,6 +147,12 @@ func TestPrepareChatWorkspace(t *testing.T) {
 	if !containsStr(denyStrs, "Edit") {
 		t.Error("deny list missing Edit")
 	}
+
+	// The session's instructions are removed once it ends
+	restore()
+	if _, err := os.Stat(filepath.Join(wsPath, adapter.InstructionFilename("claude"))); !os.IsNotExist(err) {
+		t.Errorf("expected CLAUDE.md to be removed by restore, got stat error: %v", err)
+	}
 }
 
 func TestPrepareChatWorkspace_DefaultModel(t *testing.T) {
@@ -162,10 +169,11 @@ func TestPrepareChatWorkspace_DefaultModel(t *testing.T) {
 		ProjectRoot: tmpDir,
 	}
 
-	wsPath, err := PrepareChatWorkspace(ctx, ChatWorkspaceOptions{})
+	wsPath, restore, err := PrepareChatWorkspace(ctx, ChatWorkspaceOptions{})
 	if err != nil {
 		t.Fatalf("PrepareChatWorkspace failed: %v", err)
 	}
+	defer restore()
 
 	settingsData, err := os.ReadFile(filepath.Join(wsPath, ".claude", "settings.json"))
 	if err != nil {
@@ -197,10 +205,11 @@ func TestPrepareChatWorkspace_EmptyModel_OmittedFromJSON(t *testing.T) {
 	}
 
 	// Pass empty model — should be omitted from settings.json via omitempty tag
-	wsPath, err := PrepareChatWorkspace(ctx, ChatWorkspaceOptions{Model: ""})
+	wsPath, restore, err := PrepareChatWorkspace(ctx, ChatWorkspaceOptions{Model: ""})
 	if err != nil {
 		t.Fatalf("PrepareChatWorkspace failed: %v", err)
 	}
+	defer restore()
 
 	settingsData, err := os.ReadFile(filepath.Join(wsPath, ".claude", "settings.json"))
 	if err != nil {
@@ -242,13 +251,14 @@ func TestPrepareChatWorkspace_ManipulateMode(t *testing.T) {
 		ProjectRoot: tmpDir,
 	}
 
-	wsPath, err := PrepareChatWorkspace(ctx, ChatWorkspaceOptions{
+	wsPath, restore, err := PrepareChatWorkspace(ctx, ChatWorkspaceOptions{
 		Model: "sonnet",
 		Mode:  ChatModeManipulate,
 	})
 	if err != nil {
 		t.Fatalf("PrepareChatWorkspace failed: %v", err)
 	}
+	defer restore()
 
 	// Verify CLAUDE.md has write access section
 	claudeMd, err := os.ReadFile(filepath.Join(wsPath, adapter.InstructionFilename("claude")))
@@ -317,10 +327,11 @@ func TestPrepareChatWorkspace_WithFailures(t *testing.T) {
 		ProjectRoot: tmpDir,
 	}
 
-	wsPath, err := PrepareChatWorkspace(ctx, ChatWorkspaceOptions{})
+	wsPath, restore, err := PrepareChatWorkspace(ctx, ChatWorkspaceOptions{})
 	if err != nil {
 		t.Fatalf("PrepareChatWorkspace failed: %v", err)
 	}
+	defer restore()
 
 	claudeMd, err := os.ReadFile(filepath.Join(wsPath, adapter.InstructionFilename("claude")))
 	if err != nil {
@@ -662,13 +673,14 @@ func TestPrepareChatWorkspace_StepFilter(t *testing.T) {
 		ProjectRoot: tmpDir,
 	}
 
-	wsPath, err := PrepareChatWorkspace(ctx, ChatWorkspaceOptions{
+	wsPath, restore, err := PrepareChatWorkspace(ctx, ChatWorkspaceOptions{
 		Model:      "sonnet",
 		StepFilter: "implement",
 	})
 	if err != nil {
 		t.Fatalf("PrepareChatWorkspace failed: %v", err)
 	}
+	defer restore()
 
 	claudeMd, err := os.ReadFile(filepath.Join(wsPath, adapter.InstructionFilename("claude")))
 	if err != nil {
@@ -710,12 +722,13 @@ func TestPrepareChatWorkspace_ArtifactFocus(t *testing.T) {
 		ProjectRoot: tmpDir,
 	}
 
-	wsPath, err := PrepareChatWorkspace(ctx, ChatWorkspaceOptions{
+	wsPath, restore, err := PrepareChatWorkspace(ctx, ChatWorkspaceOptions{
 		ArtifactName: "plan.md",
 	})
 	if err != nil {
 		t.Fatalf("PrepareChatWorkspace failed: %v", err)
 	}
+	defer restore()
 
 	claudeMd, err := os.ReadFile(filepath.Join(wsPath, adapter.InstructionFilename("claude")))
 	if err != nil {
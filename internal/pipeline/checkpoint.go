@@ -10,16 +10,41 @@ import (
 	"github.com/recinq/wave/internal/state"
 )
 
-// CheckpointRecorder captures cumulative artifact snapshots and workspace git
-// commit SHAs after each step completes. Checkpoint data is persisted via the
-// StateStore and used by fork/rewind operations to restore pipeline state.
+// CheckpointRecorder captures cumulative artifact snapshots, workspace git
+// commit SHAs and step results after each step completes. Checkpoint data is
+// persisted via the StateStore and used by fork/rewind and by resume to
+// restore pipeline state after the process that ran it has exited.
 type CheckpointRecorder struct {
 	store state.RunStore
 }
 
+// recordCheckpoint records a checkpoint for a step that completed. Skipped
+// steps get none, so resume evaluates them again.
+func (e *DefaultPipelineExecutor) recordCheckpoint(execution *PipelineExecution, step *Step) {
+	if e.store == nil {
+		return
+	}
+	execution.mu.Lock()
+	completed := execution.States[step.ID] == stateCompleted
+	execution.mu.Unlock()
+	if !completed {
+		return
+	}
+
+	stepIndex := -1
+	for i, s := range execution.Pipeline.Steps {
+		if s.ID == step.ID {
+			stepIndex = execution.stepIndexBase + i
+			break
+		}
+	}
+	recorder := &CheckpointRecorder{store: e.store}
+	recorder.Record(execution, step, stepIndex)
+}
+
 // Record saves a checkpoint for the given step. It captures the current
-// artifact paths as a JSON snapshot and attempts to resolve the workspace's
-// git HEAD SHA. Recording is best-effort: errors are silently ignored so
+// artifact paths and the step's results as JSON and attempts to resolve the
+// workspace's git HEAD SHA. Recording is best-effort: errors are silently ignored so
 // that checkpoint failures never block pipeline execution.
 func (r *CheckpointRecorder) Record(execution *PipelineExecution, step *Step, stepIndex int) {
 	if r.store == nil {
@@ -34,6 +59,7 @@ func (r *CheckpointRecorder) Record(execution *PipelineExecution, step *Step, st
 	}
 	workspacePath := execution.WorkspacePaths[step.ID]
 	pipelineID := execution.Status.ID
+	results := execution.Results[step.ID]
 	execution.mu.Unlock()
 
 	// Marshal the cumulative artifact snapshot.
@@ -43,6 +69,16 @@ func (r *CheckpointRecorder) Record(execution *PipelineExecution, step *Step, st
 		return
 	}
 
+	// Later steps fall back to a step's results when injecting its output.
+	// Results that cannot be encoded are dropped rather than failing the
+	// checkpoint.
+	var resultsJSON string
+	if len(results) > 0 {
+		if data, err := json.Marshal(results); err == nil {
+			resultsJSON = string(data)
+		}
+	}
+
 	// Attempt to capture the workspace git commit SHA. This only succeeds
 	// for worktree-backed workspaces that have a .git directory.
 	var commitSHA string
@@ -62,10 +98,11 @@ func (r *CheckpointRecorder) Record(execution *PipelineExecution, step *Step, st
 		WorkspacePath:      workspacePath,
 		WorkspaceCommitSHA: commitSHA,
 		ArtifactSnapshot:   string(artifactJSON),
+		StepResults:        resultsJSON,
 		CreatedAt:          time.Now(),
 	}
 
-	// Best-effort save — log failures so fork/rewind diagnostics are possible.
+	// Best-effort save — log failures so fork/rewind and resume diagnostics are possible.
 	if err := r.store.SaveCheckpoint(record); err != nil {
 		log.Printf("Warning: failed to save checkpoint for step %q in run %s: %v", step.ID, pipelineID, err)
 	}
@@ -153,6 +153,8 @@ func (c *ConcurrencyExecutor) executeAgent(ctx context.Context, execution *Pipel
 		Input:          execution.Input,
 		Status:         execution.Status,
 		Context:        execution.Context,
+
+		adapterLogWorker: fmt.Sprintf("agent_%d", agentIndex),
 	}
 
 	// Copy artifact paths from parent execution
@@ -161,6 +163,7 @@ func (c *ConcurrencyExecutor) executeAgent(ctx context.Context, execution *Pipel
 		agentExecution.ArtifactPaths[k] = v
 	}
 	execution.mu.Unlock()
+	copyArtifactChecksums(agentExecution, execution)
 
 	// Run the step execution
 	err = c.executor.runStepExecution(ctx, agentExecution, step)
@@ -172,6 +175,7 @@ func (c *ConcurrencyExecutor) executeAgent(ctx context.Context, execution *Pipel
 			StepID:     step.ID,
 			State:      "concurrency_agent_failed",
 			Message:    fmt.Sprintf("Agent %d failed: %v", agentIndex, err),
+			LogPath:    adapterLogRef(agentExecution, step.ID),
 		})
 		return result
 	}
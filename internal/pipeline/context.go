@@ -123,9 +123,11 @@ func (ctx *PipelineContext) ResolvePlaceholders(template string) string {
 		result = replaceBoth(result, key, value)
 	}
 
-	// Strip unresolved {{ project.* }} placeholders so they don't leak into
-	// prompts or contract commands when a project field is not configured.
+	// Strip unresolved {{ project.* }} and {{ changelog.* }} placeholders so
+	// they don't leak into prompts or contract commands when a project field
+	// is not configured or no changelog was generated.
 	result = unresolvedProjectVarRe.ReplaceAllString(result, "")
+	result = unresolvedChangelogVarRe.ReplaceAllString(result, "")
 
 	return result
 }
@@ -375,11 +377,16 @@ func extractFeatureNumber(branchName string) string {
 }
 
 // SanitizeBranchName removes invalid characters from branch names for use in paths.
-// It replaces any character outside [a-zA-Z0-9_-] with a dash, collapses consecutive
-// dashes, trims leading/trailing dashes, and caps the result at 50 characters.
+// Accented letters are folded to ASCII, any other character outside [a-zA-Z0-9_-]
+// becomes a dash, consecutive dashes are collapsed, leading/trailing dashes are
+// trimmed, and the result is capped at 50 characters. When non-ASCII characters
+// such as emoji had to be dropped, or nothing usable remains, a short hash of the
+// original name is appended so distinct names map to distinct paths.
 func SanitizeBranchName(branchName string) string {
+	folded, lossy := foldToASCII(branchName)
+
 	// Replace invalid path characters
-	sanitized := invalidPathCharRe.ReplaceAllString(branchName, "-")
+	sanitized := invalidPathCharRe.ReplaceAllString(folded, "-")
 
 	// Remove consecutive dashes
 	sanitized = consecutiveDashRe.ReplaceAllString(sanitized, "-")
@@ -387,6 +394,10 @@ func SanitizeBranchName(branchName string) string {
 	// Trim leading/trailing dashes
 	sanitized = strings.Trim(sanitized, "-")
 
+	if lossy || (sanitized == "" && strings.TrimSpace(branchName) != "") {
+		return withNameHash(sanitized, branchName, 50)
+	}
+
 	// Limit length
 	if len(sanitized) > 50 {
 		sanitized = sanitized[:50]
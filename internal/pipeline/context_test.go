@@ -307,6 +307,11 @@ func TestSanitizeBranchName(t *testing.T) {
 			branchName: "feature/branch:name",
 			expected:   "feature-branch-name",
 		},
+		{
+			name:       "windows_reserved_chars",
+			branchName: `fix\issue<42>|"draft"?*`,
+			expected:   "fix-issue-42-draft",
+		},
 		{
 			name:       "consecutive_dashes",
 			branchName: "feature--branch---name",
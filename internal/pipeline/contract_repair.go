@@ -0,0 +1,251 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/contract"
+	"github.com/recinq/wave/internal/event"
+)
+
+const (
+	// defaultRepairTimeout bounds one persona repair attempt.
+	defaultRepairTimeout = 2 * time.Minute
+	// maxRepairPromptBytes caps the schema and broken document included in
+	// a repair prompt.
+	maxRepairPromptBytes = 32 << 10
+)
+
+// repairJSONContract runs the repair pass configured by cfg.Repair on a
+// json_schema artifact that failed to parse. The tolerant parser runs
+// first; when it cannot read the document and a repair persona is
+// configured, the persona is asked to rewrite it. A repaired artifact is
+// written back and validated again, and the result of that validation is
+// returned. valErr is returned unchanged when no repair succeeds.
+func (e *DefaultPipelineExecutor) repairJSONContract(
+	ctx context.Context,
+	execution *PipelineExecution,
+	step *Step,
+	cfg ContractConfig,
+	workspacePath string,
+	runner adapter.AdapterRunner,
+	valErr error,
+) error {
+	artifactFile := cfg.Source
+	if artifactFile == "" {
+		artifactFile = ".agents/artifact.json"
+	}
+	artifactPath := filepath.Join(workspacePath, artifactFile)
+	data, err := os.ReadFile(artifactPath)
+	if err != nil {
+		return valErr
+	}
+
+	repaired, fixes, parseErr := contract.RepairJSON(data)
+	if parseErr == nil {
+		if err := os.WriteFile(artifactPath, repaired, 0644); err != nil {
+			return valErr
+		}
+		e.emitContractRepair(execution, step, "parser", artifactFile, fixes, len(data), len(repaired))
+		return contract.Validate(cfg, workspacePath)
+	}
+
+	if cfg.Repair.Persona == "" || runner == nil {
+		return valErr
+	}
+
+	timeout := defaultRepairTimeout
+	if cfg.Repair.Timeout != "" {
+		if d, err := time.ParseDuration(cfg.Repair.Timeout); err == nil {
+			timeout = d
+		}
+	}
+	attempts := cfg.Repair.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+
+	lastErr := valErr
+	for attempt := 1; attempt <= attempts; attempt++ {
+		out, err := e.runRepairPersona(ctx, cfg, workspacePath, runner, data, lastErr, timeout)
+		if err != nil {
+			e.emit(event.Event{
+				Timestamp:  time.Now(),
+				PipelineID: execution.Status.ID,
+				StepID:     step.ID,
+				State:      "contract_repair",
+				Persona:    cfg.Repair.Persona,
+				Message:    fmt.Sprintf("repair attempt %d/%d for %s failed: %s", attempt, attempts, artifactFile, err),
+			})
+			continue
+		}
+		if err := os.WriteFile(artifactPath, out, 0644); err != nil {
+			return lastErr
+		}
+		e.emitContractRepair(execution, step, "persona:"+cfg.Repair.Persona, artifactFile, nil, len(data), len(out))
+		lastErr = contract.Validate(cfg, workspacePath)
+		if lastErr == nil || !contract.IsMalformedJSON(lastErr) {
+			return lastErr
+		}
+		data = out
+	}
+	return lastErr
+}
+
+// runRepairPersona asks the repair persona to rewrite a malformed document
+// and returns its answer when it is parseable JSON.
+func (e *DefaultPipelineExecutor) runRepairPersona(
+	ctx context.Context,
+	cfg ContractConfig,
+	workspacePath string,
+	runner adapter.AdapterRunner,
+	data []byte,
+	valErr error,
+	timeout time.Duration,
+) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := runner.Run(ctx, adapter.AdapterRunConfig{
+		Persona:       cfg.Repair.Persona,
+		WorkspacePath: workspacePath,
+		Prompt:        buildJSONRepairPrompt(repairSchemaText(cfg, workspacePath), valErr, data),
+		Timeout:       timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	answer := result.ResultContent
+	if answer == "" && result.Stdout != nil {
+		raw, err := io.ReadAll(io.LimitReader(result.Stdout, 1<<20))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read repair output: %w", err)
+		}
+		answer = string(raw)
+	}
+	doc := []byte(strings.TrimSpace(answer))
+	if !json.Valid(doc) {
+		normalized, _, ok := contract.NormalizeJSONArtifact(doc)
+		if !ok || !json.Valid(normalized) {
+			return nil, errors.New("repair output is not valid JSON")
+		}
+		return normalized, nil
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, doc, "", "  "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// emitContractRepair records a repaired artifact as a contract_repair event.
+func (e *DefaultPipelineExecutor) emitContractRepair(execution *PipelineExecution, step *Step, method, artifactFile string, fixes []string, originalSize, size int) {
+	msg := fmt.Sprintf("repaired malformed JSON in %s (%s)", artifactFile, method)
+	if len(fixes) > 0 {
+		msg = fmt.Sprintf("repaired malformed JSON in %s (%s: %s)", artifactFile, method, strings.Join(fixes, ", "))
+	}
+	e.emit(event.Event{
+		Timestamp:  time.Now(),
+		PipelineID: execution.Status.ID,
+		StepID:     step.ID,
+		State:      "contract_repair",
+		Message:    msg,
+	})
+	e.trace("contract_repair", step.ID, 0, map[string]string{
+		"method":        method,
+		"source":        artifactFile,
+		"fixes":         strings.Join(fixes, ","),
+		"original_size": fmt.Sprintf("%d", originalSize),
+		"size":          fmt.Sprintf("%d", size),
+	})
+}
+
+// repairSchemaText returns the contract schema for inclusion in a repair
+// prompt, or an empty string when it cannot be read.
+func repairSchemaText(cfg ContractConfig, workspacePath string) string {
+	if cfg.Schema != "" {
+		return cfg.Schema
+	}
+	if cfg.SchemaPath == "" {
+		return ""
+	}
+	path := cfg.SchemaPath
+	if !filepath.IsAbs(path) {
+		if _, err := os.Stat(path); err != nil {
+			path = filepath.Join(workspacePath, path)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// buildJSONRepairPrompt asks for the broken document rewritten as valid JSON
+// conforming to schema, without changing its content.
+func buildJSONRepairPrompt(schema string, valErr error, data []byte) string {
+	var b strings.Builder
+	b.WriteString("The JSON document below is malformed and fails to parse. ")
+	b.WriteString("Rewrite it as valid JSON. Fix only the syntax: keep every key and value, ")
+	b.WriteString("do not add, remove or invent content. ")
+	b.WriteString("Respond with the corrected JSON document only, without code fences or commentary.\n\n")
+	b.WriteString("## Parse error\n\n")
+	b.WriteString(valErr.Error())
+	b.WriteString("\n\n")
+	if schema != "" {
+		b.WriteString("## JSON schema\n\n")
+		b.WriteString(truncateRepairInput(schema))
+		b.WriteString("\n\n")
+	}
+	b.WriteString("## Malformed document\n\n")
+	b.WriteString(truncateRepairInput(string(data)))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func truncateRepairInput(s string) string {
+	if len(s) <= maxRepairPromptBytes {
+		return s
+	}
+	return s[:maxRepairPromptBytes] + "\n... (truncated)"
+}
+
+// validateContractRepairs checks the repair settings of json_schema contracts.
+func validateContractRepairs(p *Pipeline) error {
+	for _, step := range p.Steps {
+		for _, c := range step.Handover.EffectiveContracts() {
+			if c.Repair == nil {
+				continue
+			}
+			if c.Type != "json_schema" {
+				return fmt.Errorf("step %q: repair is only supported on json_schema contracts, not %q", step.ID, c.Type)
+			}
+			if c.Repair.MaxAttempts < 0 {
+				return fmt.Errorf("step %q: repair.max_attempts must not be negative, got %d", step.ID, c.Repair.MaxAttempts)
+			}
+			if c.Repair.Timeout == "" {
+				continue
+			}
+			d, err := time.ParseDuration(c.Repair.Timeout)
+			if err != nil {
+				return fmt.Errorf("step %q: invalid repair.timeout %q: %w", step.ID, c.Repair.Timeout, err)
+			}
+			if d <= 0 {
+				return fmt.Errorf("step %q: repair.timeout must be positive, got %q", step.ID, c.Repair.Timeout)
+			}
+		}
+	}
+	return nil
+}
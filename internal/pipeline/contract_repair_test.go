@@ -0,0 +1,128 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/contract"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairJSONContract(t *testing.T) {
+	execution := &PipelineExecution{Status: &PipelineStatus{ID: "run-1"}, Context: NewPipelineContext("run-1", "p", "s")}
+	step := &Step{ID: "analyze"}
+
+	setup := func(t *testing.T, content string) (string, string) {
+		ws := t.TempDir()
+		artPath := filepath.Join(ws, ".agents", "output", "findings.json")
+		require.NoError(t, os.MkdirAll(filepath.Dir(artPath), 0o755))
+		require.NoError(t, os.WriteFile(artPath, []byte(content), 0o644))
+		return ws, artPath
+	}
+	contractFor := func(repair *contract.JSONRepairConfig) ContractConfig {
+		return ContractConfig{
+			Type:   "json_schema",
+			Source: ".agents/output/findings.json",
+			Schema: `{"type":"object","required":["ok"],"properties":{"ok":{"type":"boolean"}}}`,
+			Repair: repair,
+		}
+	}
+
+	t.Run("parser repair", func(t *testing.T) {
+		ws, artPath := setup(t, `{"ok": True, "count": 2 "notes": ["a", "b"`)
+		c := contractFor(&contract.JSONRepairConfig{})
+		valErr := contract.Validate(c, ws)
+		require.True(t, contract.IsMalformedJSON(valErr), "precondition: %v", valErr)
+
+		collector := testutil.NewEventCollector()
+		executor := NewDefaultPipelineExecutor(nil, WithEmitter(collector))
+		err := executor.repairJSONContract(context.Background(), execution, step, c, ws, nil, valErr)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(artPath)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"ok": true, "count": 2, "notes": ["a", "b"]}`, string(data))
+		var recorded bool
+		for _, evt := range collector.GetEvents() {
+			if evt.State == "contract_repair" {
+				recorded = true
+				assert.Contains(t, evt.Message, "converted_python_literals")
+			}
+		}
+		assert.True(t, recorded, "expected a contract_repair event")
+	})
+
+	t.Run("schema violation after repair", func(t *testing.T) {
+		ws, _ := setup(t, `{"ok": None, "count": 1 "extra": 2}`)
+		c := contractFor(&contract.JSONRepairConfig{})
+		valErr := contract.Validate(c, ws)
+		require.True(t, contract.IsMalformedJSON(valErr))
+
+		executor := NewDefaultPipelineExecutor(nil)
+		err := executor.repairJSONContract(context.Background(), execution, step, c, ws, nil, valErr)
+		require.Error(t, err)
+		assert.False(t, contract.IsMalformedJSON(err), "repaired artifact should fail on the schema, got %v", err)
+	})
+
+	t.Run("persona repair", func(t *testing.T) {
+		ws, artPath := setup(t, "ok => yes")
+		c := contractFor(&contract.JSONRepairConfig{Persona: "summarizer"})
+		valErr := contract.Validate(c, ws)
+		require.True(t, contract.IsMalformedJSON(valErr))
+
+		runner := adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON("```json\n{\"ok\": true}\n```"))
+		executor := NewDefaultPipelineExecutor(nil)
+		err := executor.repairJSONContract(context.Background(), execution, step, c, ws, runner, valErr)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(artPath)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"ok": true}`, string(data))
+	})
+
+	t.Run("persona failure keeps the original error", func(t *testing.T) {
+		ws, artPath := setup(t, "ok => yes")
+		c := contractFor(&contract.JSONRepairConfig{Persona: "summarizer", MaxAttempts: 2})
+		valErr := contract.Validate(c, ws)
+
+		runner := adaptertest.NewMockAdapter(adaptertest.WithFailure(errors.New("adapter down")))
+		executor := NewDefaultPipelineExecutor(nil)
+		err := executor.repairJSONContract(context.Background(), execution, step, c, ws, runner, valErr)
+		assert.Equal(t, valErr, err)
+
+		data, err := os.ReadFile(artPath)
+		require.NoError(t, err)
+		assert.Equal(t, "ok => yes", string(data))
+	})
+}
+
+func TestValidateContractRepairs(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       ContractConfig
+		wantErr string
+	}{
+		{name: "valid", c: ContractConfig{Type: "json_schema", Repair: &contract.JSONRepairConfig{Persona: "summarizer", MaxAttempts: 2, Timeout: "30s"}}},
+		{name: "not json_schema", c: ContractConfig{Type: "test_suite", Repair: &contract.JSONRepairConfig{}}, wantErr: "only supported on json_schema"},
+		{name: "negative attempts", c: ContractConfig{Type: "json_schema", Repair: &contract.JSONRepairConfig{MaxAttempts: -1}}, wantErr: "max_attempts"},
+		{name: "bad timeout", c: ContractConfig{Type: "json_schema", Repair: &contract.JSONRepairConfig{Timeout: "soon"}}, wantErr: "invalid repair.timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Pipeline{Steps: []Step{{ID: "a", Handover: HandoverConfig{Contract: tt.c}}}}
+			err := validateContractRepairs(p)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
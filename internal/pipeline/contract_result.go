@@ -0,0 +1,69 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/recinq/wave/internal/contract"
+	"github.com/recinq/wave/internal/state"
+)
+
+// maxContractOutput caps the command output kept in a contract result; the
+// tail is kept, since test runners print their summary last.
+const maxContractOutput = 64 << 10
+
+// recordContractResult persists the structured result of one contract
+// validation: a JSON artifact in the step workspace, registered with the
+// run, and a contract_result row. Best-effort; errors are ignored.
+func (e *DefaultPipelineExecutor) recordContractResult(
+	execution *PipelineExecution,
+	step *Step,
+	c ContractConfig,
+	name string,
+	workspacePath string,
+	valErr error,
+	duration time.Duration,
+) {
+	if e.store == nil {
+		return
+	}
+	runID := execution.Status.ID
+	record := state.ContractResultRecord{
+		RunID:        runID,
+		StepID:       step.ID,
+		ContractType: c.Type,
+		Name:         name,
+		Passed:       valErr == nil,
+		DurationMs:   duration.Milliseconds(),
+		CreatedAt:    time.Now(),
+	}
+	if valErr != nil {
+		var ve *contract.ValidationError
+		if errors.As(valErr, &ve) {
+			record.Message = ve.Message
+			record.Violations = ve.Details
+			record.Output = ve.Output
+		} else {
+			record.Message = valErr.Error()
+		}
+		if len(record.Output) > maxContractOutput {
+			record.Output = record.Output[len(record.Output)-maxContractOutput:]
+		}
+	}
+
+	if workspacePath != "" {
+		prior, _ := e.store.GetContractResults(runID, step.ID)
+		artifactName := fmt.Sprintf("contract_%s_%d", step.ID, len(prior)+1)
+		path := filepath.Join(workspacePath, ".agents", "artifacts", artifactName+".json")
+		data, err := json.MarshalIndent(record, "", "  ")
+		if err == nil && os.MkdirAll(filepath.Dir(path), 0o755) == nil && os.WriteFile(path, data, 0o644) == nil {
+			record.ArtifactPath = path
+			e.registerArtifact(runID, step, artifactName, path, "contract_result", int64(len(data)))
+		}
+	}
+	_ = e.store.SaveContractResult(&record)
+}
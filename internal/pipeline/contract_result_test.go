@@ -0,0 +1,67 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/contract"
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type contractResultStore struct {
+	*testutil.MockStateStore
+	saved []state.ContractResultRecord
+}
+
+func (s *contractResultStore) SaveContractResult(record *state.ContractResultRecord) error {
+	s.saved = append(s.saved, *record)
+	return nil
+}
+
+func (s *contractResultStore) GetContractResults(runID, stepID string) ([]state.ContractResultRecord, error) {
+	return s.saved, nil
+}
+
+func TestRecordContractResult(t *testing.T) {
+	var registered []string
+	store := &contractResultStore{MockStateStore: testutil.NewMockStateStore(
+		testutil.WithRegisterArtifact(func(runID, stepID, name, path, artifactType string, sizeBytes int64) error {
+			registered = append(registered, name+":"+artifactType)
+			return nil
+		}),
+	)}
+	executor := NewDefaultPipelineExecutor(nil, WithStateStore(store))
+	execution := &PipelineExecution{Status: &PipelineStatus{ID: "run-1"}}
+	step := &Step{ID: "impl"}
+	ws := t.TempDir()
+
+	valErr := &contract.ValidationError{
+		ContractType: "json_schema",
+		Message:      "artifact does not match schema",
+		Details:      []string{"at '/summary': missing property 'summary'"},
+	}
+	executor.recordContractResult(execution, step, ContractConfig{Type: "json_schema"}, "impl.schema.json", ws, valErr, 40*time.Millisecond)
+	executor.recordContractResult(execution, step, ContractConfig{Type: "json_schema"}, "impl.schema.json", ws, nil, 10*time.Millisecond)
+
+	require.Len(t, store.saved, 2)
+	failed := store.saved[0]
+	assert.False(t, failed.Passed)
+	assert.Equal(t, "artifact does not match schema", failed.Message)
+	assert.Equal(t, valErr.Details, failed.Violations)
+	assert.Equal(t, int64(40), failed.DurationMs)
+	assert.True(t, store.saved[1].Passed)
+	assert.Equal(t, []string{"contract_impl_1:contract_result", "contract_impl_2:contract_result"}, registered)
+
+	data, err := os.ReadFile(filepath.Join(ws, ".agents", "artifacts", "contract_impl_1.json"))
+	require.NoError(t, err)
+	var artifact state.ContractResultRecord
+	require.NoError(t, json.Unmarshal(data, &artifact))
+	assert.Equal(t, valErr.Details, artifact.Violations)
+	assert.Equal(t, failed.ArtifactPath, filepath.Join(ws, ".agents", "artifacts", "contract_impl_1.json"))
+}
@@ -0,0 +1,91 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+)
+
+// conversationThread is the thread every turn of a conversation joins, so
+// each turn sees the messages and replies before it.
+const conversationThread = "conversation"
+
+// conversationUser attributes user messages in the conversation transcript.
+const conversationUser = "user"
+
+// Conversation runs an interactive session with one persona as a series of
+// tracked steps. Each message becomes a single-step pipeline turn
+// ("turn-1", "turn-2", ...) executed under the executor's run ID, so turns
+// get events, token accounting and, with a contract schema, output
+// validation like any other step. All turns work in the workspace the first
+// turn creates.
+type Conversation struct {
+	executor  *DefaultPipelineExecutor
+	opts      SingleStepOptions
+	threads   *ThreadManager
+	workspace string
+	turns     int
+}
+
+// NewConversation prepares a conversation run by executor. The executor
+// keeps its workspaces between turns and shares one thread transcript
+// across them.
+func NewConversation(executor *DefaultPipelineExecutor, opts SingleStepOptions) (*Conversation, error) {
+	if _, err := GenerateSingleStepPipeline(opts); err != nil {
+		return nil, err
+	}
+	threads := NewThreadManager(nil)
+	if executor.relayMonitor != nil {
+		threads = NewThreadManager(executor.relayMonitor.Adapter())
+	}
+	executor.threadManager = threads
+	executor.preserveWorkspace = true
+	return &Conversation{executor: executor, opts: opts, threads: threads}, nil
+}
+
+// Turns returns the number of messages sent so far.
+func (c *Conversation) Turns() int {
+	return c.turns
+}
+
+// Workspace returns the conversation's workspace, empty before the first
+// turn created it.
+func (c *Conversation) Workspace() string {
+	return c.workspace
+}
+
+// Send runs message as the next turn and returns the persona's reply. A
+// failed turn is recorded like a failed step; the conversation can go on.
+func (c *Conversation) Send(ctx context.Context, message string) (string, error) {
+	p, err := GenerateSingleStepPipeline(c.opts)
+	if err != nil {
+		return "", err
+	}
+	c.turns++
+	step := &p.Steps[0]
+	step.ID = fmt.Sprintf("turn-%d", c.turns)
+	step.Thread = conversationThread
+	if c.workspace != "" {
+		step.Workspace = WorkspaceConfig{Ref: "parent"}
+		c.executor.parentWorkspacePath = c.workspace
+	}
+	p.Metadata = PipelineMetadata{Name: "chat", Description: "Conversation with " + c.opts.Persona}
+
+	c.threads.AppendTranscript(conversationThread, conversationUser, message)
+	execErr := c.executor.Execute(ctx, p, c.opts.Manifest, message)
+	if c.workspace == "" {
+		if execution := c.executor.LastExecution(); execution != nil {
+			execution.mu.Lock()
+			c.workspace = execution.WorkspacePaths[step.ID]
+			execution.mu.Unlock()
+		}
+	}
+	if execErr != nil {
+		return "", execErr
+	}
+
+	entry, ok := c.threads.LastEntry(conversationThread)
+	if !ok || entry.StepID != step.ID {
+		return "", nil
+	}
+	return entry.Content, nil
+}
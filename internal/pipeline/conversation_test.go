@@ -0,0 +1,61 @@
+package pipeline
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversation_TurnsShareThreadAndWorkspace(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := state.NewStateStore(filepath.Join(tmpDir, "state.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	runID, err := store.CreateRun("chat", "hello")
+	require.NoError(t, err)
+
+	runner := &scriptedAdapter{result: adapter.AdapterResult{ResultContent: "the config lives in wave.yaml", TokensUsed: 100}}
+	executor := NewDefaultPipelineExecutor(runner,
+		WithEmitter(testutil.NewEventCollector()),
+		WithStateStore(store),
+		WithRunID(runID),
+	)
+	m := testutil.CreateTestManifest(tmpDir)
+	conv, err := NewConversation(executor, SingleStepOptions{Persona: "navigator", Manifest: m})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	reply, err := conv.Send(ctx, "where is the config?")
+	require.NoError(t, err)
+	assert.Equal(t, "the config lives in wave.yaml", reply)
+	workspace := conv.Workspace()
+	require.NotEmpty(t, workspace)
+
+	_, err = conv.Send(ctx, "show me the adapters section")
+	require.NoError(t, err)
+	assert.Equal(t, 2, conv.Turns())
+	assert.Equal(t, workspace, conv.Workspace())
+
+	require.Len(t, runner.runs, 2)
+	assert.Equal(t, workspace, runner.runs[1].WorkspacePath, "later turns work in the first turn's workspace")
+	second := runner.runs[1].Prompt
+	assert.Contains(t, second, "where is the config?", "the thread carries earlier messages")
+	assert.Contains(t, second, "the config lives in wave.yaml", "the thread carries earlier replies")
+
+	steps, err := store.GetStepStates(runID)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(steps))
+	for _, s := range steps {
+		ids = append(ids, s.StepID)
+	}
+	assert.ElementsMatch(t, []string{"turn-1", "turn-2"}, ids, "each message is a tracked step of the run")
+	assert.Equal(t, 200, executor.GetTotalTokens())
+}
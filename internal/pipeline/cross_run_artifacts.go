@@ -0,0 +1,62 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/recinq/wave/internal/state"
+)
+
+// resolvedRunArtifact is an artifact found in another pipeline's run.
+type resolvedRunArtifact struct {
+	RunID  string
+	Record state.ArtifactRecord
+	SHA256 string // checksum indexed at registration, "" if unknown
+}
+
+// resolveFromPipelineArtifact finds ref.Artifact in the latest completed run
+// of ref.FromPipeline. ref.Step, when set, names the producing step in that
+// pipeline. When the run registered the artifact more than once (retries),
+// the latest registration wins.
+func (e *DefaultPipelineExecutor) resolveFromPipelineArtifact(ref ArtifactRef) (*resolvedRunArtifact, error) {
+	if e.store == nil {
+		return nil, fmt.Errorf("artifact '%s' from pipeline '%s': from_pipeline requires a state store", ref.Artifact, ref.FromPipeline)
+	}
+	runs, err := e.store.ListRuns(state.ListRunsOptions{PipelineName: ref.FromPipeline, Status: stateCompleted, Limit: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up runs of pipeline '%s': %w", ref.FromPipeline, err)
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("artifact '%s' from pipeline '%s' not found: pipeline has no completed run", ref.Artifact, ref.FromPipeline)
+	}
+	runID := runs[0].RunID
+
+	records, err := e.store.GetArtifacts(runID, ref.Step)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up artifacts of run %s: %w", runID, err)
+	}
+	var found *state.ArtifactRecord
+	for i := range records {
+		if records[i].Name == ref.Artifact {
+			found = &records[i]
+		}
+	}
+	if found == nil {
+		where := ""
+		if ref.Step != "" {
+			where = fmt.Sprintf(" step '%s' of", ref.Step)
+		}
+		return nil, fmt.Errorf("artifact '%s' not found in%s run %s of pipeline '%s'", ref.Artifact, where, runID, ref.FromPipeline)
+	}
+
+	resolved := &resolvedRunArtifact{RunID: runID, Record: *found}
+	if meta, err := e.store.GetArtifactMetadata(found.ID); err == nil && meta != nil {
+		var fields struct {
+			SHA256 string `json:"sha256"`
+		}
+		if json.Unmarshal([]byte(meta.MetadataJSON), &fields) == nil {
+			resolved.SHA256 = fields.SHA256
+		}
+	}
+	return resolved, nil
+}
@@ -0,0 +1,94 @@
+package pipeline
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectArtifacts_FromPipeline(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := state.NewStateStore(filepath.Join(tmpDir, "state.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	// A completed spec-gen run that produced spec.json, and a newer failed
+	// run whose artifact must be ignored.
+	specPath := filepath.Join(tmpDir, "spec.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(`{"spec": "v1"}`), 0644))
+	doneRun, err := store.CreateRun("spec-gen", "")
+	require.NoError(t, err)
+	require.NoError(t, store.RegisterArtifact(doneRun, "write-spec", "spec.json", specPath, "json", 14))
+	require.NoError(t, store.UpdateRunStatus(doneRun, stateCompleted, "", 0))
+
+	failedPath := filepath.Join(tmpDir, "spec-failed.json")
+	require.NoError(t, os.WriteFile(failedPath, []byte(`{"spec": "broken"}`), 0644))
+	failedRun, err := store.CreateRun("spec-gen", "")
+	require.NoError(t, err)
+	require.NoError(t, store.RegisterArtifact(failedRun, "write-spec", "spec.json", failedPath, "json", 18))
+	require.NoError(t, store.UpdateRunStatus(failedRun, stateFailed, "", 0))
+
+	executor := NewDefaultPipelineExecutor(nil, WithEmitter(testutil.NewEventCollector()), WithStateStore(store))
+	newExecution := func() *PipelineExecution {
+		return &PipelineExecution{
+			Pipeline:      &Pipeline{Metadata: PipelineMetadata{Name: "implement"}},
+			States:        make(map[string]string),
+			Results:       make(map[string]map[string]interface{}),
+			ArtifactPaths: make(map[string]string),
+			Context:       NewPipelineContext("impl-run", "implement", "code"),
+			Status:        &PipelineStatus{ID: "impl-run"},
+		}
+	}
+	stepWith := func(ref ArtifactRef) *Step {
+		return &Step{ID: "code", Memory: MemoryConfig{InjectArtifacts: []ArtifactRef{ref}}}
+	}
+
+	t.Run("latest completed run", func(t *testing.T) {
+		ws := filepath.Join(tmpDir, "ws1")
+		execution := newExecution()
+		err := executor.injectArtifacts(execution, stepWith(ArtifactRef{FromPipeline: "spec-gen", Artifact: "spec.json", As: "spec"}), ws)
+		require.NoError(t, err)
+		data, err := os.ReadFile(filepath.Join(ws, ".agents", "artifacts", "spec"))
+		require.NoError(t, err)
+		assert.Equal(t, `{"spec": "v1"}`, string(data))
+		assert.Equal(t, filepath.Join(ws, ".agents", "artifacts", "spec"), execution.Context.GetArtifactPath("spec"))
+	})
+
+	t.Run("step narrows the lookup", func(t *testing.T) {
+		err := executor.injectArtifacts(newExecution(), stepWith(ArtifactRef{FromPipeline: "spec-gen", Step: "review", Artifact: "spec.json"}), filepath.Join(tmpDir, "ws2"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found in step 'review' of run "+doneRun)
+	})
+
+	t.Run("pipeline without completed run", func(t *testing.T) {
+		err := executor.injectArtifacts(newExecution(), stepWith(ArtifactRef{FromPipeline: "never-ran", Artifact: "spec.json"}), filepath.Join(tmpDir, "ws3"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "pipeline has no completed run")
+
+		err = executor.injectArtifacts(newExecution(), stepWith(ArtifactRef{FromPipeline: "never-ran", Artifact: "spec.json", Optional: true}), filepath.Join(tmpDir, "ws3"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("modified since registration", func(t *testing.T) {
+		records, err := store.GetArtifacts(doneRun, "write-spec")
+		require.NoError(t, err)
+		require.NoError(t, store.SaveArtifactMetadata(records[0].ID, doneRun, "write-spec", "", "application/json", "utf-8", `{"sha256":"0000"}`))
+
+		err = executor.injectArtifacts(newExecution(), stepWith(ArtifactRef{FromPipeline: "spec-gen", Artifact: "spec.json"}), filepath.Join(tmpDir, "ws4"))
+		var integrityErr *ArtifactIntegrityError
+		require.True(t, errors.As(err, &integrityErr), "expected ArtifactIntegrityError, got %v", err)
+	})
+}
+
+func TestArtifactRefValidate_FromPipeline(t *testing.T) {
+	assert.NoError(t, ArtifactRef{FromPipeline: "spec-gen", Step: "write-spec", Artifact: "spec"}.Validate("s", 0))
+	err := ArtifactRef{FromPipeline: "spec-gen", Pipeline: "other", Artifact: "spec"}.Validate("s", 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pipeline and from_pipeline are mutually exclusive")
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/recinq/wave/internal/manifest"
 	"github.com/recinq/wave/internal/skill"
 	"gopkg.in/yaml.v3"
 )
@@ -25,10 +27,22 @@ func (l *YAMLPipelineLoader) Load(path string) (*Pipeline, error) {
 }
 
 func (l *YAMLPipelineLoader) Unmarshal(data []byte) (*Pipeline, error) {
+	// Reject documents from another schema version before the strict decode
+	// turns their renamed or removed fields into unknown-field errors.
+	_, version := manifest.DocumentVersion(data)
+	if err := manifest.CheckAPIVersion(manifest.KindPipeline, version); err != nil {
+		return nil, err
+	}
+
+	expanded, err := expandStepTemplates(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand step templates: %w", err)
+	}
+
 	var pipeline Pipeline
 	// Use strict decoder that rejects unknown YAML fields.
 	// This catches hallucinated fields like allow_recovery, recovery_level, etc.
-	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder := yaml.NewDecoder(bytes.NewReader(expanded))
 	decoder.KnownFields(true)
 	if err := decoder.Decode(&pipeline); err != nil {
 		return nil, fmt.Errorf("failed to parse pipeline YAML: %w", err)
@@ -36,6 +50,18 @@ func (l *YAMLPipelineLoader) Unmarshal(data []byte) (*Pipeline, error) {
 
 	applyPipelineDefaults(&pipeline)
 
+	if err := applyStepGroups(&pipeline); err != nil {
+		return nil, err
+	}
+
+	if err := applyChangelog(&pipeline); err != nil {
+		return nil, err
+	}
+
+	if err := resolveSchemaRefs(&pipeline); err != nil {
+		return nil, err
+	}
+
 	// Type-check I/O protocol declarations (input.type, pipeline_outputs[*].type,
 	// step input_ref) against the shared schema registry. Catches misspelled
 	// type names before any step runs. See docs/adr/010-pipeline-io-protocol.md.
@@ -61,6 +87,7 @@ func (l *YAMLPipelineLoader) Unmarshal(data []byte) (*Pipeline, error) {
 		return nil, fmt.Errorf("WLP validation failed: %s", strings.Join(errs, "; "))
 	}
 
+	pipeline.Digest = manifest.Digest(data)
 	return &pipeline, nil
 }
 
@@ -83,6 +110,40 @@ type DAGValidator struct {
 }
 
 func (v *DAGValidator) ValidateDAG(p *Pipeline) error {
+	if err := validatePipelineVars(p); err != nil {
+		return err
+	}
+	if err := validateCanarySteps(p); err != nil {
+		return err
+	}
+	if err := validateTokenBudgets(p); err != nil {
+		return err
+	}
+	if err := validateWhenConditions(p, true); err != nil {
+		return err
+	}
+	if err := validateDeprecation(p); err != nil {
+		return err
+	}
+	if err := validateInputTemplates(p); err != nil {
+		return err
+	}
+	if err := validateStepCaches(p); err != nil {
+		return err
+	}
+	if err := validateStepFailurePolicies(p, false); err != nil {
+		return err
+	}
+	if err := validateContractRepairs(p); err != nil {
+		return err
+	}
+	if err := validatePublish(p); err != nil {
+		return err
+	}
+	if err := validateLocks(p); err != nil {
+		return err
+	}
+
 	stepMap := make(map[string]*Step)
 	for i := range p.Steps {
 		stepMap[p.Steps[i].ID] = &p.Steps[i]
@@ -102,11 +163,37 @@ func (v *DAGValidator) ValidateDAG(p *Pipeline) error {
 			}
 		}
 
+		if step.MaxTurns < 0 || step.MaxToolCalls < 0 {
+			return fmt.Errorf("step %q: max_turns and max_tool_calls must not be negative", step.ID)
+		}
+
+		for key := range step.Env {
+			if !manifest.ValidEnvKey(key) {
+				return fmt.Errorf("step %q: invalid env variable name %q", step.ID, key)
+			}
+		}
+
+		if err := step.Workspace.Validate(); err != nil {
+			return fmt.Errorf("step %q: %w", step.ID, err)
+		}
+
+		for _, art := range step.OutputArtifacts {
+			switch art.OnOverflow {
+			case "", OverflowFail, OverflowTruncate:
+			default:
+				return fmt.Errorf("step %q: output artifact %q has invalid on_overflow value %q (must be fail or truncate)", step.ID, art.Name, art.OnOverflow)
+			}
+		}
+
 		// Validate RetryConfig
 		if err := step.Retry.Validate(); err != nil {
 			return fmt.Errorf("step %q: %w", step.ID, err)
 		}
 
+		if err := v.validateTestImpact(&step, stepMap, true); err != nil {
+			return err
+		}
+
 		// Validate rework targets
 		if step.Retry.OnFailure == OnFailureRework {
 			if err := v.validateReworkTarget(step.ID, step.Retry.ReworkStep, stepMap); err != nil {
@@ -170,6 +257,14 @@ func (v *DAGValidator) ValidateDAG(p *Pipeline) error {
 		if step.Concurrency > 1 && step.Strategy != nil && step.Strategy.Type == "matrix" {
 			return fmt.Errorf("step %q sets both concurrency (%d) and matrix strategy — they are mutually exclusive", step.ID, step.Concurrency)
 		}
+		if step.Strategy != nil && step.Strategy.Type == "matrix" {
+			if err := validateMatrixSelection(step.ID, step.Strategy); err != nil {
+				return err
+			}
+			if err := validateMatrixWorkers(step.ID, step.Strategy); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Validate thread group constraints
@@ -188,7 +283,12 @@ func (v *DAGValidator) ValidateDAG(p *Pipeline) error {
 		}
 	}
 
-	return nil
+	// Reachability and artifact-flow checks walk dependencies, so they
+	// run only once the graph is known to be acyclic.
+	if err := v.validateReachability(p, stepMap); err != nil {
+		return err
+	}
+	return v.validateArtifactFlow(p, stepMap)
 }
 
 // validFidelityValues enumerates acceptable fidelity field values.
@@ -297,6 +397,31 @@ func (v *DAGValidator) validateReworkTarget(stepID, reworkStepID string, stepMap
 	return nil
 }
 
+// validateTestImpact checks a test_impact step's sources: it needs at least
+// one, and each must exist. In DAG mode a source must also be upstream of the
+// step, or its change summary would not exist yet when the step runs.
+func (v *DAGValidator) validateTestImpact(step *Step, stepMap map[string]*Step, dag bool) error {
+	if step.TestImpact != nil && step.Type != StepTypeTestImpact {
+		return fmt.Errorf("step %q sets test_impact but is not type=%s", step.ID, StepTypeTestImpact)
+	}
+	if step.Type != StepTypeTestImpact {
+		return nil
+	}
+	sources := testImpactSources(step)
+	if len(sources) == 0 {
+		return fmt.Errorf("step %q is type=%s but has no dependencies or test_impact.from to analyze", step.ID, StepTypeTestImpact)
+	}
+	for _, src := range sources {
+		if _, exists := stepMap[src]; !exists {
+			return fmt.Errorf("step %q test_impact.from references non-existent step %q", step.ID, src)
+		}
+		if dag && !v.isTransitiveDep(step.ID, src, stepMap) {
+			return fmt.Errorf("step %q test_impact.from references step %q, which is not one of its dependencies", step.ID, src)
+		}
+	}
+	return nil
+}
+
 // isTransitiveDep returns true if targetID is a direct or transitive dependency of stepID.
 func (v *DAGValidator) isTransitiveDep(stepID, targetID string, stepMap map[string]*Step) bool {
 	visited := make(map[string]bool)
@@ -421,7 +546,7 @@ func detectSubPipelineCyclesDFS(name, pipelinesDir string, loader *YAMLPipelineL
 	recStack[name] = true
 
 	// Try to load the pipeline to find its sub-pipeline references
-	path := pipelinesDir + "/" + name + ".yaml"
+	path := filepath.Join(pipelinesDir, name+".yaml")
 	p, err := loader.Load(path)
 	if err != nil {
 		// Pipeline file not found — can't trace further, not an error
@@ -452,9 +577,47 @@ func isGraphPipeline(p *Pipeline) bool {
 	return false
 }
 
+// Validate checks p in the mode the executor will run it in: graph
+// validation for pipelines with edges or conditional steps, DAG validation
+// otherwise.
+func (v *DAGValidator) Validate(p *Pipeline) error {
+	if isGraphPipeline(p) {
+		return v.ValidateGraph(p)
+	}
+	return v.ValidateDAG(p)
+}
+
 // ValidateGraph validates a graph-mode pipeline. Unlike ValidateDAG, it allows
 // backward edges (cycles) but enforces safety limits and structural requirements.
 func (v *DAGValidator) ValidateGraph(p *Pipeline) error {
+	if err := validatePipelineVars(p); err != nil {
+		return err
+	}
+	if err := validateCanarySteps(p); err != nil {
+		return err
+	}
+	if err := validateTokenBudgets(p); err != nil {
+		return err
+	}
+	if err := validateWhenConditions(p, false); err != nil {
+		return err
+	}
+	if err := validateStepCaches(p); err != nil {
+		return err
+	}
+	if err := validateStepFailurePolicies(p, true); err != nil {
+		return err
+	}
+	if err := validateContractRepairs(p); err != nil {
+		return err
+	}
+	if err := validatePublish(p); err != nil {
+		return err
+	}
+	if err := validateLocks(p); err != nil {
+		return err
+	}
+
 	stepMap := make(map[string]*Step)
 	for i := range p.Steps {
 		stepMap[p.Steps[i].ID] = &p.Steps[i]
@@ -470,6 +633,9 @@ func (v *DAGValidator) ValidateGraph(p *Pipeline) error {
 		if err := step.Retry.Validate(); err != nil {
 			return fmt.Errorf("step %q: %w", step.ID, err)
 		}
+		if err := v.validateTestImpact(&step, stepMap, false); err != nil {
+			return err
+		}
 	}
 
 	// Validate edge targets exist (allow _complete sentinel for pipeline termination)
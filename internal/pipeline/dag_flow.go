@@ -0,0 +1,261 @@
+package pipeline
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// stepArtifactUse is a reference from one step to another step's output.
+type stepArtifactUse struct {
+	Step     string // producing step
+	Artifact string // artifact name; empty when the whole step output is referenced
+	Field    string // where the reference appears, for error messages
+}
+
+// validateReachability rejects steps the DAG scheduler can never start.
+// A regular step only becomes ready once every dependency has completed, so
+// depending (directly or transitively) on a rework_only step deadlocks the
+// run whenever no rework happens. A rework_only step that no rework_step,
+// on_failure.run or gate choice targets never runs at all; it is dead but
+// harmless, so it is reported as a warning.
+func (v *DAGValidator) validateReachability(p *Pipeline, stepMap map[string]*Step) error {
+	triggered := make(map[string]bool)
+	for _, step := range p.Steps {
+		if step.Retry.ReworkStep != "" {
+			triggered[step.Retry.ReworkStep] = true
+		}
+		if h := step.failureHandler(); h != "" {
+			triggered[h] = true
+		}
+		for _, c := range step.Handover.EffectiveContracts() {
+			if c.ReworkStep != "" {
+				triggered[c.ReworkStep] = true
+			}
+		}
+		if step.Gate != nil {
+			for _, c := range step.Gate.Choices {
+				triggered[c.Target] = true
+			}
+		}
+	}
+
+	// blockedBy memoises, per step, the rework_only step that keeps it from
+	// being scheduled ("" when the step is reachable from a root).
+	blockedBy := make(map[string]string, len(p.Steps))
+	var blocker func(id string) string
+	blocker = func(id string) string {
+		if b, ok := blockedBy[id]; ok {
+			return b
+		}
+		b := ""
+		for _, dep := range stepMap[id].Dependencies {
+			if stepMap[dep].ReworkOnly {
+				b = dep
+			} else {
+				b = blocker(dep)
+			}
+			if b != "" {
+				break
+			}
+		}
+		blockedBy[id] = b
+		return b
+	}
+
+	for _, step := range p.Steps {
+		if step.ReworkOnly {
+			if !triggered[step.ID] {
+				v.Warnings = append(v.Warnings, fmt.Sprintf("step %q is unreachable: it is rework_only but no rework_step, on_failure.run or gate choice targets it", step.ID))
+			}
+			continue
+		}
+		if b := blocker(step.ID); b != "" {
+			return fmt.Errorf("step %q is unreachable: it depends on rework_only step %q, which never runs in normal scheduling", step.ID, b)
+		}
+	}
+	return nil
+}
+
+// validateArtifactFlow checks that every artifact a step reads comes from an
+// existing step it depends on, so the producer is guaranteed to have
+// finished before the reader starts. Output artifacts of intermediate steps
+// that nothing reads are reported as warnings.
+func (v *DAGValidator) validateArtifactFlow(p *Pipeline, stepMap map[string]*Step) error {
+	consumed := make(map[string]bool)     // "step:artifact"
+	consumedStep := make(map[string]bool) // legacy {{ step.output }} reads every artifact
+	consumedName := make(map[string]bool) // sub-pipeline config.inject matches by name
+
+	for _, step := range p.Steps {
+		for i, ref := range step.Memory.InjectArtifacts {
+			if ref.Step == "" || ref.Pipeline != "" || ref.FromPipeline != "" {
+				continue
+			}
+			if _, ok := stepMap[ref.Step]; !ok {
+				return fmt.Errorf("step %q inject_artifacts[%d] references non-existent step %q", step.ID, i, ref.Step)
+			}
+			consumed[ref.Step+":"+ref.Artifact] = true
+			if !v.isTransitiveDep(step.ID, ref.Step, stepMap) {
+				return fmt.Errorf("step %q injects artifact %q from step %q, which is not one of its dependencies — add %q to dependencies",
+					step.ID, ref.Artifact, ref.Step, ref.Step)
+			}
+		}
+
+		for _, use := range stepArtifactUses(&step, stepMap) {
+			if use.Artifact == "" {
+				consumedStep[use.Step] = true
+			} else {
+				consumed[use.Step+":"+use.Artifact] = true
+			}
+			if use.Step != step.ID && !v.isTransitiveDep(step.ID, use.Step, stepMap) {
+				return fmt.Errorf("step %q references step %q in %s but does not depend on it — add %q to dependencies",
+					step.ID, use.Step, use.Field, use.Step)
+			}
+		}
+
+		if step.Config != nil {
+			for _, name := range step.Config.Inject {
+				consumedName[name] = true
+			}
+		}
+	}
+	for _, out := range p.PipelineOutputs {
+		consumed[out.Step+":"+out.Artifact] = true
+	}
+	for _, def := range p.Publish {
+		for _, a := range def.Artifacts {
+			consumed[a.Step+":"+a.Artifact] = true
+		}
+	}
+
+	hasDependents := make(map[string]bool)
+	for _, step := range p.Steps {
+		for _, dep := range step.Dependencies {
+			hasDependents[dep] = true
+		}
+	}
+	for _, step := range p.Steps {
+		// Outputs of terminal steps are the pipeline's results.
+		if !hasDependents[step.ID] || consumedStep[step.ID] {
+			continue
+		}
+		for _, a := range step.OutputArtifacts {
+			if consumed[step.ID+":"+a.Name] || consumedName[a.Name] || publishedAsOutcome(&step, a) {
+				continue
+			}
+			v.Warnings = append(v.Warnings, fmt.Sprintf("step %q output artifact %q is not consumed by any step or pipeline output", step.ID, a.Name))
+		}
+	}
+	return nil
+}
+
+// publishedAsOutcome reports whether one of the step's outcomes is
+// extracted from the artifact.
+func publishedAsOutcome(step *Step, a ArtifactDef) bool {
+	if a.Path == "" {
+		return false
+	}
+	for _, o := range step.Outcomes {
+		if filepath.Clean(o.ExtractFrom) == filepath.Clean(a.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// stepArtifactUses collects the references a step makes to other steps'
+// outputs outside inject_artifacts: {{ steps.<id>.artifacts.<name> }},
+// {{ steps.<id>.output }}, {{ <id>.out.<name> }} and {{ <id>.output }}
+// templates in composition and workspace fields, matrix items_source
+// references, and mount subset_from.
+// Expressions naming unknown steps are left to the template validators.
+func stepArtifactUses(step *Step, stepMap map[string]*Step) []stepArtifactUse {
+	var uses []stepArtifactUse
+	scan := func(tmpl, field string) {
+		for _, match := range templatePattern.FindAllStringSubmatch(tmpl, -1) {
+			expr := strings.TrimPrefix(strings.TrimSpace(match[1]), "steps.")
+			parts := strings.SplitN(expr, ".", 4)
+			if len(parts) < 2 || stepMap[parts[0]] == nil {
+				continue
+			}
+			use := stepArtifactUse{Step: parts[0], Field: field}
+			switch parts[1] {
+			case "output":
+			case "out", "artifacts":
+				if len(parts) < 3 {
+					continue
+				}
+				use.Artifact = parts[2]
+			default:
+				continue
+			}
+			uses = append(uses, use)
+		}
+	}
+
+	if step.Iterate != nil {
+		scan(step.Iterate.Over, "iterate.over")
+	}
+	if step.Branch != nil {
+		scan(step.Branch.On, "branch.on")
+	}
+	scan(step.SubInput, "input")
+	if step.Loop != nil {
+		scan(step.Loop.Until, "loop.until")
+	}
+	if step.Aggregate != nil {
+		scan(step.Aggregate.From, "aggregate.from")
+	}
+	if step.Strategy != nil {
+		if id, artifact, isRef, err := itemsSourceRef(step.Strategy.ItemsSource); isRef && err == nil && stepMap[id] != nil {
+			uses = append(uses, stepArtifactUse{Step: id, Artifact: artifact, Field: "strategy.items_source"})
+		}
+	}
+	scan(step.Workspace.Branch, "workspace.branch")
+	scan(step.Workspace.Base, "workspace.base")
+	for i, m := range step.Workspace.Mount {
+		parts := strings.SplitN(m.SubsetFrom, ".", 3)
+		if len(parts) == 3 && stepMap[parts[0]] != nil {
+			uses = append(uses, stepArtifactUse{Step: parts[0], Artifact: parts[1], Field: fmt.Sprintf("workspace.mount[%d].subset_from", i)})
+		}
+	}
+	return uses
+}
+
+// MissingDependencies returns, per step ID, the steps whose artifacts the
+// step reads (via inject_artifacts, templates or subset_from) without
+// depending on them — the edges validateArtifactFlow rejects. Edges that
+// would close a cycle are omitted, so adding the result is always safe.
+func MissingDependencies(p *Pipeline) map[string][]string {
+	v := &DAGValidator{}
+	steps := make([]Step, len(p.Steps))
+	copy(steps, p.Steps)
+	stepMap := make(map[string]*Step, len(steps))
+	for i := range steps {
+		steps[i].Dependencies = append([]string(nil), steps[i].Dependencies...)
+		stepMap[steps[i].ID] = &steps[i]
+	}
+
+	missing := make(map[string][]string)
+	for i := range steps {
+		step := &steps[i]
+		var producers []string
+		for _, ref := range step.Memory.InjectArtifacts {
+			if ref.Step != "" && ref.Pipeline == "" && ref.FromPipeline == "" {
+				producers = append(producers, ref.Step)
+			}
+		}
+		for _, use := range stepArtifactUses(step, stepMap) {
+			producers = append(producers, use.Step)
+		}
+		for _, prod := range producers {
+			if prod == step.ID || stepMap[prod] == nil ||
+				v.isTransitiveDep(step.ID, prod, stepMap) || v.isTransitiveDep(prod, step.ID, stepMap) {
+				continue
+			}
+			step.Dependencies = append(step.Dependencies, prod)
+			missing[step.ID] = append(missing[step.ID], prod)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,146 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateDAG_DependsOnReworkOnlyStep(t *testing.T) {
+	p := &Pipeline{
+		Steps: []Step{
+			{ID: "implement", Persona: "craftsman", Retry: RetryConfig{OnFailure: "rework", ReworkStep: "fix"}},
+			{ID: "fix", Persona: "craftsman", ReworkOnly: true},
+			{ID: "publish", Persona: "craftsman", Dependencies: []string{"fix"}},
+		},
+	}
+
+	err := (&DAGValidator{}).ValidateDAG(p)
+	if err == nil || !strings.Contains(err.Error(), `step "publish" is unreachable`) {
+		t.Fatalf("expected unreachable error for publish, got: %v", err)
+	}
+}
+
+func TestValidateDAG_UntriggeredReworkOnlyStepWarns(t *testing.T) {
+	p := &Pipeline{
+		Steps: []Step{
+			{ID: "implement", Persona: "craftsman"},
+			{ID: "fix", Persona: "craftsman", ReworkOnly: true},
+		},
+	}
+
+	v := &DAGValidator{}
+	if err := v.ValidateDAG(p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(v.Warnings) != 1 || !strings.Contains(v.Warnings[0], `step "fix" is unreachable`) {
+		t.Errorf("expected unreachable warning for fix, got: %v", v.Warnings)
+	}
+}
+
+func TestValidateDAG_TemplateReferenceWithoutDependency(t *testing.T) {
+	p := &Pipeline{
+		Steps: []Step{
+			{ID: "plan", Persona: "navigator", OutputArtifacts: []ArtifactDef{{Name: "tasks", Path: "tasks.json"}}},
+			{ID: "run", Iterate: &IterateConfig{Over: "{{ plan.out.tasks }}"}},
+		},
+	}
+
+	err := (&DAGValidator{}).ValidateDAG(p)
+	if err == nil || !strings.Contains(err.Error(), `references step "plan" in iterate.over but does not depend on it`) {
+		t.Fatalf("expected missing dependency error, got: %v", err)
+	}
+}
+
+func TestValidateDAG_MatrixItemsSourceReference(t *testing.T) {
+	matrix := func(source string, deps ...string) *Pipeline {
+		return &Pipeline{
+			Steps: []Step{
+				{ID: "plan", Persona: "navigator", OutputArtifacts: []ArtifactDef{{Name: "tasks", Path: "tasks.json"}}},
+				{ID: "fanout", Persona: "craftsman", Dependencies: deps, Strategy: &MatrixStrategy{Type: "matrix", ItemsSource: source}},
+			},
+		}
+	}
+
+	err := (&DAGValidator{}).ValidateDAG(matrix("{{ steps.plan.artifacts.tasks }}"))
+	if err == nil || !strings.Contains(err.Error(), `references step "plan" in strategy.items_source but does not depend on it`) {
+		t.Fatalf("expected missing dependency error, got: %v", err)
+	}
+
+	v := &DAGValidator{}
+	if err := v.ValidateDAG(matrix("{{ steps.plan.artifacts.tasks }}", "plan")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(v.Warnings) != 0 {
+		t.Errorf("the reference should consume plan's artifact, got warnings: %v", v.Warnings)
+	}
+
+	err = (&DAGValidator{}).ValidateDAG(matrix("{{ plan.output }}", "plan"))
+	if err == nil || !strings.Contains(err.Error(), "strategy.items_source") {
+		t.Fatalf("expected items_source syntax error, got: %v", err)
+	}
+}
+
+func TestValidateDAG_TransitiveInjectIsAllowed(t *testing.T) {
+	p := &Pipeline{
+		Steps: []Step{
+			{ID: "plan", Persona: "navigator", OutputArtifacts: []ArtifactDef{{Name: "plan", Path: "plan.md"}}},
+			{ID: "build", Persona: "craftsman", Dependencies: []string{"plan"}},
+			{ID: "review", Persona: "reviewer", Dependencies: []string{"build"},
+				Memory: MemoryConfig{InjectArtifacts: []ArtifactRef{{Step: "plan", Artifact: "plan", As: "plan"}}}},
+		},
+	}
+
+	v := &DAGValidator{}
+	if err := v.ValidateDAG(p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(v.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", v.Warnings)
+	}
+}
+
+func TestValidateDAG_UnconsumedOutputWarns(t *testing.T) {
+	p := &Pipeline{
+		Steps: []Step{
+			{ID: "plan", Persona: "navigator", OutputArtifacts: []ArtifactDef{
+				{Name: "plan", Path: "plan.md"},
+				{Name: "notes", Path: "notes.md"},
+			}},
+			{ID: "build", Persona: "craftsman", Dependencies: []string{"plan"},
+				Memory:          MemoryConfig{InjectArtifacts: []ArtifactRef{{Step: "plan", Artifact: "plan", As: "plan"}}},
+				OutputArtifacts: []ArtifactDef{{Name: "diff", Path: "diff.patch"}}},
+		},
+	}
+
+	v := &DAGValidator{}
+	if err := v.ValidateDAG(p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Terminal step outputs are results; only the intermediate "notes" is orphaned.
+	if len(v.Warnings) != 1 || !strings.Contains(v.Warnings[0], `step "plan" output artifact "notes"`) {
+		t.Errorf("expected one orphan warning for plan/notes, got: %v", v.Warnings)
+	}
+}
+
+func TestMissingDependencies(t *testing.T) {
+	p := &Pipeline{
+		Steps: []Step{
+			{ID: "plan", Persona: "navigator"},
+			{ID: "build", Persona: "craftsman",
+				Memory: MemoryConfig{InjectArtifacts: []ArtifactRef{{Step: "plan", Artifact: "plan", As: "plan"}}}},
+			// Reads build's output while build already depends on it:
+			// adding the edge would close a cycle, so it is left out.
+			{ID: "lint", Persona: "reviewer", Dependencies: []string{"build"}},
+			{ID: "report", Persona: "summarizer", Dependencies: []string{"lint"}},
+		},
+	}
+	p.Steps[2].Iterate = &IterateConfig{Over: "{{ report.output }}"}
+
+	got := MissingDependencies(p)
+	if len(got) != 1 || len(got["build"]) != 1 || got["build"][0] != "plan" {
+		t.Errorf("MissingDependencies() = %v, want map[build:[plan]]", got)
+	}
+	if len(p.Steps[1].Dependencies) != 0 {
+		t.Error("MissingDependencies must not modify the pipeline")
+	}
+}
@@ -114,9 +114,11 @@ func TestValidateDAG_ArtifactRefStepAndPipelineMutuallyExclusive(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			pipeline := &Pipeline{
 				Steps: []Step{
+					{ID: "analyze", Persona: "agent1"},
 					{
-						ID:      "step1",
-						Persona: "agent1",
+						ID:           "step1",
+						Persona:      "agent1",
+						Dependencies: []string{"analyze"},
 						Memory: MemoryConfig{
 							Strategy:        "fresh",
 							InjectArtifacts: tt.refs,
@@ -201,6 +203,43 @@ func TestValidateDAG_ConcurrencyAndMatrixMutuallyExclusive(t *testing.T) {
 	}
 }
 
+func TestValidateDAG_WorkspaceCheckoutOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		workspace WorkspaceConfig
+		wantErr   string
+	}{
+		{"directories", WorkspaceConfig{Type: "worktree", Scope: []string{"internal/pipeline", "/docs/"}}, ""},
+		{"patterns", WorkspaceConfig{Type: "worktree", Scope: []string{"/*", "!/vendor/", "services/api/**"}}, ""},
+		{"requires worktree", WorkspaceConfig{Scope: []string{"internal"}}, "requires workspace.type"},
+		{"empty entry", WorkspaceConfig{Type: "worktree", Scope: []string{"internal", " "}}, "workspace.scope[1] is empty"},
+		{"parent escape", WorkspaceConfig{Type: "worktree", Scope: []string{"../other"}}, "must not contain '..'"},
+		{"submodules and lfs", WorkspaceConfig{Type: "worktree", Submodules: "recursive", LFS: true}, ""},
+		{"unknown submodules mode", WorkspaceConfig{Type: "worktree", Submodules: "deep"}, "unknown workspace.submodules"},
+		{"submodules require worktree", WorkspaceConfig{Submodules: "init"}, "workspace.submodules requires"},
+		{"lfs requires worktree", WorkspaceConfig{LFS: true}, "workspace.lfs requires"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := &Pipeline{
+				Steps: []Step{{ID: "step1", Persona: "agent1", Workspace: tt.workspace}},
+			}
+
+			err := (&DAGValidator{}).ValidateDAG(pipeline)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestTopologicalSort_SimplePipeline(t *testing.T) {
 	pipeline := &Pipeline{
 		Steps: []Step{
@@ -543,6 +582,25 @@ invalid: yaml: content:
 	}
 }
 
+func TestYAMLPipelineLoader_APIVersion(t *testing.T) {
+	loader := &YAMLPipelineLoader{}
+
+	p, err := loader.Unmarshal([]byte("apiVersion: v1\nkind: WavePipeline\nmetadata:\n  name: ok\nsteps: []\n"))
+	if err != nil {
+		t.Fatalf("current apiVersion rejected: %v", err)
+	}
+	if p.APIVersion != "v1" {
+		t.Errorf("APIVersion = %q, want v1", p.APIVersion)
+	}
+
+	// The version check must fire before the strict decode reports the
+	// v0-only field as unknown.
+	_, err = loader.Unmarshal([]byte("apiVersion: v0\nkind: WavePipeline\nmetadata:\n  name: old\nlegacy_field: true\n"))
+	if err == nil || !strings.Contains(err.Error(), "wave migrate-config") {
+		t.Errorf("expected migrate-config hint for v0 pipeline, got: %v", err)
+	}
+}
+
 func TestValidateDAG_DuplicateReworkTarget(t *testing.T) {
 	p := &Pipeline{
 		Steps: []Step{
@@ -720,6 +778,28 @@ func TestValidateDAG_InvalidFidelityValue(t *testing.T) {
 	}
 }
 
+func TestValidateDAG_InvalidOnOverflowValue(t *testing.T) {
+	p := &Pipeline{
+		Steps: []Step{
+			{ID: "step-a", Persona: "craftsman", OutputArtifacts: []ArtifactDef{
+				{Name: "out", Source: "stdout", OnOverflow: "truncate"},
+				{Name: "log", Source: "stdout", OnOverflow: "trunc"},
+			}},
+		},
+	}
+
+	v := &DAGValidator{}
+	err := v.ValidateDAG(p)
+	if err == nil || !contains(err.Error(), `invalid on_overflow value "trunc"`) {
+		t.Errorf("Expected invalid on_overflow error, got: %v", err)
+	}
+
+	p.Steps[0].OutputArtifacts[1].OnOverflow = OverflowFail
+	if err := v.ValidateDAG(p); err != nil {
+		t.Errorf("Expected fail and truncate to be valid, got: %v", err)
+	}
+}
+
 func TestValidateDAG_ValidFidelityValues(t *testing.T) {
 	for _, fidelity := range []string{"full", "compact", "summary", "fresh", ""} {
 		p := &Pipeline{
@@ -0,0 +1,80 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sunsetDateLayout is the format of Deprecation.Sunset.
+const sunsetDateLayout = "2006-01-02"
+
+// Deprecation marks a pipeline as on its way out. Runs of a deprecated
+// pipeline print a warning; from the sunset date on they are refused.
+type Deprecation struct {
+	// Since records when the pipeline was deprecated (a date or release).
+	Since string `yaml:"since,omitempty" json:"since,omitempty"`
+	// Replacement names the pipeline to use instead.
+	Replacement string `yaml:"replacement,omitempty" json:"replacement,omitempty"`
+	// Message explains the deprecation to the user.
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+	// Sunset is the YYYY-MM-DD date from which runs are refused.
+	Sunset string `yaml:"sunset,omitempty" json:"sunset,omitempty"`
+}
+
+// SunsetTime returns the parsed sunset date, or the zero time when none is
+// set. Validation rejects malformed dates at load time.
+func (d *Deprecation) SunsetTime() time.Time {
+	if d == nil || d.Sunset == "" {
+		return time.Time{}
+	}
+	t, err := time.ParseInLocation(sunsetDateLayout, d.Sunset, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// Sunsetted reports whether the sunset date has been reached at now.
+func (d *Deprecation) Sunsetted(now time.Time) bool {
+	sunset := d.SunsetTime()
+	return !sunset.IsZero() && !now.Before(sunset)
+}
+
+// Notice describes the deprecation of the named pipeline in one line, e.g.
+// `pipeline "fix" is deprecated since v0.9: superseded; use "impl-issue"
+// instead (sunset 2026-12-01)`.
+func (d *Deprecation) Notice(name string, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pipeline %q ", name)
+	if d.Sunsetted(now) {
+		fmt.Fprintf(&b, "was retired on %s", d.Sunset)
+	} else {
+		b.WriteString("is deprecated")
+		if d.Since != "" {
+			fmt.Fprintf(&b, " since %s", d.Since)
+		}
+	}
+	if d.Message != "" {
+		fmt.Fprintf(&b, ": %s", strings.TrimSpace(d.Message))
+	}
+	if d.Replacement != "" {
+		fmt.Fprintf(&b, "; use %q instead", d.Replacement)
+	}
+	if d.Sunset != "" && !d.Sunsetted(now) {
+		fmt.Fprintf(&b, " (runs refused from %s)", d.Sunset)
+	}
+	return b.String()
+}
+
+// validateDeprecation checks metadata.deprecated.
+func validateDeprecation(p *Pipeline) error {
+	d := p.Metadata.Deprecated
+	if d == nil || d.Sunset == "" {
+		return nil
+	}
+	if _, err := time.Parse(sunsetDateLayout, d.Sunset); err != nil {
+		return fmt.Errorf("metadata.deprecated.sunset must be a YYYY-MM-DD date, got %q", d.Sunset)
+	}
+	return nil
+}
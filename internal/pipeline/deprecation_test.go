@@ -0,0 +1,51 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecation(t *testing.T) {
+	d := &Deprecation{Since: "v0.9", Replacement: "impl-issue", Message: "superseded by the issue flow", Sunset: "2026-12-01"}
+	before := time.Date(2026, 11, 30, 23, 0, 0, 0, time.Local)
+	after := time.Date(2026, 12, 1, 0, 0, 0, 0, time.Local)
+
+	assert.False(t, d.Sunsetted(before))
+	assert.True(t, d.Sunsetted(after))
+	assert.Equal(t, `pipeline "fix" is deprecated since v0.9: superseded by the issue flow; use "impl-issue" instead (runs refused from 2026-12-01)`, d.Notice("fix", before))
+	assert.Equal(t, `pipeline "fix" was retired on 2026-12-01: superseded by the issue flow; use "impl-issue" instead`, d.Notice("fix", after))
+
+	bare := &Deprecation{}
+	assert.False(t, bare.Sunsetted(after))
+	assert.Equal(t, `pipeline "fix" is deprecated`, bare.Notice("fix", after))
+}
+
+func TestValidateDeprecation(t *testing.T) {
+	loader := &YAMLPipelineLoader{}
+	p, err := loader.Unmarshal([]byte(`kind: WavePipeline
+metadata:
+  name: fix
+  deprecated:
+    since: v0.9
+    replacement: impl-issue
+    sunset: 2026-12-01
+steps:
+  - id: a
+    persona: navigator
+    exec:
+      type: prompt
+      source: go
+`))
+	require.NoError(t, err)
+	require.NotNil(t, p.Metadata.Deprecated)
+	assert.Equal(t, "impl-issue", p.Metadata.Deprecated.Replacement)
+
+	v := &DAGValidator{}
+	require.NoError(t, v.ValidateDAG(p))
+
+	p.Metadata.Deprecated.Sunset = "December 1st"
+	assert.ErrorContains(t, v.ValidateDAG(p), "metadata.deprecated.sunset")
+}
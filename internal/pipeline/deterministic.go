@@ -0,0 +1,87 @@
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/recinq/wave/internal/manifest"
+)
+
+// DeterministicEpoch is the clock a deterministic run reports to templates.
+var DeterministicEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// WithDeterministic makes the run reproducible (CLI --deterministic): run
+// IDs, and the branch names derived from them, are hashed from seed instead
+// of drawn at random, {{ run.date }} and {{ run.timestamp }} are pinned to
+// DeterministicEpoch, and every step runs at temperature 0.
+func WithDeterministic(seed string) ExecutorOption {
+	return func(ex *DefaultPipelineExecutor) {
+		ex.deterministic = true
+		ex.seed = seed
+	}
+}
+
+// DeterministicRunID returns the run ID a deterministic run of name uses
+// for seed. It has the same "{name}-{hex_suffix}" shape as GenerateRunID.
+func DeterministicRunID(name, seed string, hashLength int) string {
+	return fmt.Sprintf("%s-%s", name, seededHexSuffix(hashLength, seed, name))
+}
+
+// seededHexSuffix derives a hex suffix of the given length from parts.
+func seededHexSuffix(length int, parts ...string) string {
+	if length <= 0 {
+		length = defaultHashLength
+	}
+	h := sha256.New()
+	for _, p := range parts {
+		// Length-prefix each part so ("ab", "c") and ("a", "bc") differ.
+		fmt.Fprintf(h, "%d:%s", len(p), p)
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	if length > len(sum) {
+		length = len(sum)
+	}
+	return sum[:length]
+}
+
+// newRunID returns a run ID for a run the executor starts itself.
+func (e *DefaultPipelineExecutor) newRunID(name string, rt manifest.Runtime) string {
+	if e.deterministic {
+		return DeterministicRunID(name, e.seed, rt.PipelineIDHashLength)
+	}
+	return NewRunID(rt, name)
+}
+
+// runClock returns the time reported to templates for a new run.
+func (e *DefaultPipelineExecutor) runClock() time.Time {
+	if e.deterministic {
+		return DeterministicEpoch
+	}
+	return time.Now().UTC()
+}
+
+// stepTemperature returns the sampling temperature for a persona's step.
+func (e *DefaultPipelineExecutor) stepTemperature(persona *manifest.Persona) float64 {
+	if e.deterministic {
+		return 0
+	}
+	return persona.Temperature
+}
+
+// stepTemperatureSet reports whether the step's temperature is explicit:
+// set on the persona, or pinned to 0 by a deterministic run.
+func (e *DefaultPipelineExecutor) stepTemperatureSet(persona *manifest.Persona) bool {
+	return e.deterministic || persona.TemperatureSet
+}
+
+// injectRunTimeVariables publishes the run's start time as {{ run.date }}
+// (YYYY-MM-DD) and {{ run.timestamp }} (RFC 3339, UTC).
+func injectRunTimeVariables(ctx *PipelineContext, t time.Time) {
+	t = t.UTC()
+	ctx.setCustomVariablesBatch(map[string]string{
+		"run.date":      t.Format(time.DateOnly),
+		"run.timestamp": t.Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,63 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeterministicRunID(t *testing.T) {
+	id := DeterministicRunID("impl-issue", "ci-42", 8)
+	assert.Regexp(t, `^impl-issue-[0-9a-f]{8}$`, id)
+	assert.Equal(t, id, DeterministicRunID("impl-issue", "ci-42", 8))
+	assert.NotEqual(t, id, DeterministicRunID("impl-issue", "ci-43", 8))
+	assert.NotEqual(t, id, DeterministicRunID("impl-pr", "ci-42", 8))
+	assert.Regexp(t, `^p-[0-9a-f]{8}$`, DeterministicRunID("p", "", 0), "zero length uses the default")
+	assert.Regexp(t, `^p-[0-9a-f]{12}$`, DeterministicRunID("p", "", 12))
+}
+
+func TestInjectRunTimeVariables(t *testing.T) {
+	ctx := NewPipelineContext("p-1", "p", "")
+	injectRunTimeVariables(ctx, time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600)))
+	assert.Equal(t, "2026-03-04 2026-03-04T04:06:07Z", ctx.ResolvePlaceholders("{{ run.date }} {{run.timestamp}}"))
+}
+
+func TestExecute_Deterministic(t *testing.T) {
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "det-test"},
+		Steps: []Step{{
+			ID:      "step",
+			Persona: "craftsman", // temperature 0.7 in the test manifest
+			Exec:    ExecConfig{Source: "id={{ pipeline_id }} date={{ run.date }} ts={{ run.timestamp }}"},
+		}},
+	}
+
+	run := func(opts ...ExecutorOption) (*configCapturingAdapter, error) {
+		capture := &configCapturingAdapter{MockAdapter: adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`))}
+		executor := NewDefaultPipelineExecutor(capture, append(opts, WithEmitter(testutil.NewEventCollector()))...)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return capture, executor.Execute(ctx, p, testutil.CreateTestManifest(t.TempDir()), "go")
+	}
+
+	first, err := run(WithDeterministic("ci-42"))
+	require.NoError(t, err)
+	second, err := run(WithDeterministic("ci-42"))
+	require.NoError(t, err)
+
+	cfg := first.getLastConfig()
+	assert.Equal(t, second.getLastConfig().Prompt, cfg.Prompt, "replays resolve identical prompts")
+	assert.Contains(t, cfg.Prompt, "id="+DeterministicRunID("det-test", "ci-42", 0))
+	assert.Contains(t, cfg.Prompt, "date=2000-01-01 ts=2000-01-01T00:00:00Z")
+	assert.Zero(t, cfg.Temperature)
+
+	normal, err := run()
+	require.NoError(t, err)
+	assert.NotContains(t, normal.getLastConfig().Prompt, "date=2000-01-01")
+	assert.InDelta(t, 0.7, normal.getLastConfig().Temperature, 1e-9)
+}
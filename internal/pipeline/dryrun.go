@@ -3,6 +3,7 @@ package pipeline
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/recinq/wave/internal/manifest"
@@ -171,6 +172,12 @@ func (v *DryRunValidator) validateStep(
 		v.validateInjectArtifacts(step, p, stepArtifacts, report)
 		return
 	}
+	if step.Type == StepTypeTestImpact {
+		// Test impact steps are built in and don't need persona
+		v.validateEdges(step, p, report)
+		v.validateInjectArtifacts(step, p, stepArtifacts, report)
+		return
+	}
 	if step.Type == StepTypeCommand {
 		// Command steps don't need persona
 		v.validateCommandStep(step, report)
@@ -205,6 +212,11 @@ func (v *DryRunValidator) validateStep(
 		}
 	}
 
+	// Adapter option passthrough.
+	if len(step.AdapterOptions) > 0 && m != nil {
+		v.validateAdapterOptions(step, m, report)
+	}
+
 	// Inject artifact references.
 	v.validateInjectArtifacts(step, p, stepArtifacts, report)
 
@@ -239,6 +251,28 @@ func (v *DryRunValidator) validateStep(
 
 // --- persona ---
 
+// validateAdapterOptions checks the step's adapter_options against the
+// options of the adapter it runs on. Unknown personas and adapters are
+// reported by the reference checks.
+func (v *DryRunValidator) validateAdapterOptions(step *Step, m *manifest.Manifest, report *DryRunReport) {
+	persona := m.GetPersona(step.Persona)
+	adapterName := step.Adapter
+	if adapterName == "" && persona != nil {
+		adapterName = persona.Adapter
+	}
+	if adapterName == "" || m.GetAdapter(adapterName) == nil {
+		return
+	}
+	if _, err := resolveAdapterOptions(m, adapterName, persona, step); err != nil {
+		report.Findings = append(report.Findings, ValidationFinding{
+			Severity: SeverityError,
+			StepID:   step.ID,
+			Field:    "adapter_options",
+			Message:  err.Error(),
+		})
+	}
+}
+
 func (v *DryRunValidator) validatePersonaRef(step *Step, m *manifest.Manifest, report *DryRunReport) {
 	if step.Persona == "" {
 		report.Findings = append(report.Findings, ValidationFinding{
@@ -336,7 +370,7 @@ func (v *DryRunValidator) validateInjectArtifacts(
 		// Step vs pipeline mutual exclusion is already checked by DAGValidator.
 		// Here we validate the semantic references.
 
-		if ref.Pipeline != "" {
+		if ref.Pipeline != "" || ref.FromPipeline != "" {
 			// Cross-pipeline reference — we cannot validate at static analysis time
 			// because the other pipeline's outputs are runtime-determined.
 			continue
@@ -376,16 +410,6 @@ func (v *DryRunValidator) validateInjectArtifacts(
 			})
 		}
 
-		// Check that the dependency is listed so the executor orders steps correctly.
-		if !hasDependency(step, ref.Step) {
-			report.Findings = append(report.Findings, ValidationFinding{
-				Severity: SeverityWarning,
-				StepID:   step.ID,
-				Field:    field,
-				Message:  fmt.Sprintf("injects artifact from step %q but %q is not listed in dependencies — execution order may be wrong", ref.Step, ref.Step),
-			})
-		}
-
 		// Validate schema path if provided.
 		if ref.SchemaPath != "" {
 			if _, err := os.Stat(ref.SchemaPath); os.IsNotExist(err) {
@@ -412,6 +436,8 @@ var validContractTypes = map[string]bool{
 	"non_empty_file":       true,
 	"llm_judge":            true,
 	"agent_review":         true,
+	"coverage":             true,
+	"dependency_policy":    true,
 }
 
 func (v *DryRunValidator) validateContract(step *Step, report *DryRunReport) {
@@ -693,8 +719,8 @@ func (v *DryRunValidator) validateSubPipeline(step *Step, name, field string, re
 		return
 	}
 	candidates := []string{
-		v.pipelinesDir + "/" + name + ".yaml",
-		v.pipelinesDir + "/" + name,
+		filepath.Join(v.pipelinesDir, name+".yaml"),
+		filepath.Join(v.pipelinesDir, name),
 		name,
 	}
 	for _, path := range candidates {
@@ -804,16 +830,6 @@ func buildStepArtifactMap(p *Pipeline) map[string]map[string]bool {
 	return m
 }
 
-// hasDependency checks whether targetID is listed in step.Dependencies.
-func hasDependency(step *Step, targetID string) bool {
-	for _, d := range step.Dependencies {
-		if d == targetID {
-			return true
-		}
-	}
-	return false
-}
-
 // isCompositionPipeline returns true if any step uses a composition primitive.
 func isCompositionPipeline(p *Pipeline) bool {
 	for _, step := range p.Steps {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/recinq/wave/internal/manifest"
@@ -153,15 +154,9 @@ func TestDryRunValidator_InjectArtifactMissingDependency(t *testing.T) {
 	p.Steps[1].Dependencies = nil
 
 	report := v.Validate(p, m)
-	// Should produce a warning, not an error.
-	found := false
-	for _, f := range report.Findings {
-		if f.Severity == SeverityWarning && f.StepID == "implement" {
-			found = true
-		}
-	}
-	if !found {
-		t.Fatalf("expected warning about missing dependency, got:\n%s", report.Format())
+	// The DAG validator rejects reads from steps that are not upstream.
+	if !report.HasErrors() || !strings.Contains(report.Format(), "not one of its dependencies") {
+		t.Fatalf("expected error about missing dependency, got:\n%s", report.Format())
 	}
 }
 
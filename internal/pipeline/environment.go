@@ -0,0 +1,97 @@
+package pipeline
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/recinq/wave/internal/attest"
+	"github.com/recinq/wave/internal/git"
+	"github.com/recinq/wave/internal/state"
+)
+
+// recordEnvironment stores a snapshot of the environment the run starts in
+// (see state.RunEnvironment), shown by `wave status <run-id>`. Nested runs
+// share their parent's environment and record none.
+func (e *DefaultPipelineExecutor) recordEnvironment(execution *PipelineExecution) {
+	if e.store == nil || e.nestedRun {
+		return
+	}
+	// Best-effort like provenance: the snapshot is diagnostic and must
+	// never fail a run.
+	_ = e.store.SetRunEnvironment(execution.Status.ID, e.captureEnvironment(execution))
+}
+
+// captureEnvironment snapshots the Wave build, the git state of the working
+// directory, the host and the versions of the adapters the pipeline uses.
+func (e *DefaultPipelineExecutor) captureEnvironment(execution *PipelineExecution) *state.RunEnvironment {
+	env := &state.RunEnvironment{
+		CapturedAt: time.Now().UTC(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+	if v := attest.BuilderVersion(); v != nil {
+		env.WaveVersion = v["wave"]
+		env.WaveRevision = v["revision"]
+	}
+	if sha, err := git.ResolveRef("HEAD"); err == nil {
+		env.GitSHA = sha
+		env.GitBranch, _ = git.Branch()
+		env.GitDirty, _ = git.IsDirty()
+	}
+	if execution.Manifest != nil {
+		env.ManifestDigest = execution.Manifest.Digest
+		env.AdapterVersions = e.adapterVersions(execution)
+	}
+	return env
+}
+
+// adapterVersions probes `--version` of every adapter the pipeline's steps
+// may run on, including persona failover adapters. The probes run in
+// parallel so the snapshot waits for the slowest binary only.
+func (e *DefaultPipelineExecutor) adapterVersions(execution *PipelineExecution) map[string]string {
+	binaries := make(map[string]string)
+	add := func(name string) {
+		if def := execution.Manifest.GetAdapter(name); def != nil {
+			binaries[name] = def.Binary
+		}
+	}
+	if e.adapterOverride != "" {
+		add(e.adapterOverride)
+	} else if execution.Pipeline != nil {
+		for _, step := range execution.Pipeline.Steps {
+			if step.Adapter != "" {
+				add(step.Adapter)
+				continue
+			}
+			personaName := step.Persona
+			if execution.Context != nil {
+				personaName = execution.Context.ResolvePlaceholders(personaName)
+			}
+			if persona := execution.Manifest.GetPersona(personaName); persona != nil {
+				for _, name := range persona.AdapterChain() {
+					add(name)
+				}
+			}
+		}
+	}
+	if len(binaries) == 0 {
+		return nil
+	}
+
+	versions := make(map[string]string, len(binaries))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, binary := range binaries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			version := attest.AdapterVersion(binary)
+			mu.Lock()
+			versions[name] = version
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return versions
+}
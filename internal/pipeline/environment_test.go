@@ -0,0 +1,70 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/recinq/wave/internal/git"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureEnvironment(t *testing.T) {
+	prev := git.SetRunner(func(args ...string) ([]byte, error) {
+		switch strings.Join(args, " ") {
+		case "rev-parse --verify HEAD^{commit}":
+			return []byte("0123456789abcdef0123456789abcdef01234567\n"), nil
+		case "rev-parse --abbrev-ref HEAD":
+			return []byte("main\n"), nil
+		case "status --porcelain":
+			return []byte(" M wave.yaml\n"), nil
+		}
+		return nil, os.ErrNotExist
+	})
+	t.Cleanup(func() { git.SetRunner(prev) })
+
+	dir := t.TempDir()
+	fakeCLI := func(name, version string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\necho '"+version+"'\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	m := testutil.CreateTestManifest(dir)
+	m.Digest = "manifest-digest"
+	m.Adapters["claude"] = manifest.Adapter{Binary: fakeCLI("claude", "2.1.0 (Claude Code)")}
+	m.Adapters["codex"] = manifest.Adapter{Binary: fakeCLI("codex", "codex-cli 0.50.0")}
+	m.Adapters["gemini"] = manifest.Adapter{Binary: fakeCLI("gemini", "0.9.0")}
+	m.Adapters["ollama"] = manifest.Adapter{Binary: filepath.Join(dir, "missing")}
+	navigator := m.Personas["navigator"]
+	navigator.AdapterFallbacks = []string{"ollama"}
+	m.Personas["navigator"] = navigator
+
+	execution := &PipelineExecution{
+		Manifest: m,
+		Pipeline: &Pipeline{Steps: []Step{
+			{ID: "plan", Persona: "navigator"},
+			{ID: "implement", Persona: "craftsman", Adapter: "codex"},
+		}},
+	}
+	env := NewDefaultPipelineExecutor(nil).captureEnvironment(execution)
+
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", env.GitSHA)
+	assert.Equal(t, "main", env.GitBranch)
+	assert.True(t, env.GitDirty)
+	assert.Equal(t, runtime.GOOS, env.OS)
+	assert.Equal(t, runtime.GOARCH, env.Arch)
+	assert.Equal(t, "manifest-digest", env.ManifestDigest)
+	assert.False(t, env.CapturedAt.IsZero())
+	assert.Equal(t, map[string]string{
+		"claude": "2.1.0 (Claude Code)",
+		"codex":  "codex-cli 0.50.0",
+		"ollama": "",
+	}, env.AdapterVersions, "only the adapters the pipeline uses are probed")
+}
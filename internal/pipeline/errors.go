@@ -1,6 +1,9 @@
 package pipeline
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // StepExecutionError wraps a step execution error with the step ID for programmatic access.
 // It preserves the same error message format as the previous fmt.Errorf pattern
@@ -18,6 +21,28 @@ func (e *StepExecutionError) Unwrap() error {
 	return e.Err
 }
 
+// PartialFailureError reports a run whose failed steps had on_failure:
+// continue or isolate, so the rest of the DAG ran to the end. Errors holds a
+// *StepExecutionError per failed step; errors.As and errors.Is see each of
+// them.
+type PartialFailureError struct {
+	CompletedSteps []string
+	Errors         []error
+}
+
+func (e *PartialFailureError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("partial success: %d steps completed, %d failed: %s",
+		len(e.CompletedSteps), len(e.Errors), strings.Join(msgs, "; "))
+}
+
+func (e *PartialFailureError) Unwrap() []error {
+	return e.Errors
+}
+
 // ContractRejectionError signals a *design rejection* — a contract with
 // on_failure: rejected fired because the persona output deliberately marked
 // the work as non-actionable (e.g. fetch-assess setting `implementable:
@@ -52,3 +77,46 @@ type gateAbortError struct {
 func (e *gateAbortError) Error() string {
 	return fmt.Sprintf("gate %q aborted with choice %q", e.StepID, e.Choice)
 }
+
+// approvalRejectedError is returned when an approval gate is rejected with
+// `wave approve --reject`, or by its timeout.
+type approvalRejectedError struct {
+	StepID string
+	By     string
+	Reason string
+}
+
+func (e *approvalRejectedError) Error() string {
+	msg := fmt.Sprintf("gate %q rejected by %s", e.StepID, e.By)
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+// ArtifactNotFoundError is returned when a required injected artifact is
+// registered but nothing exists at its path.
+type ArtifactNotFoundError struct {
+	Step     string
+	Artifact string
+	Path     string
+}
+
+func (e *ArtifactNotFoundError) Error() string {
+	return fmt.Sprintf("required artifact '%s' from step '%s' not found at %s", e.Artifact, e.Step, e.Path)
+}
+
+// ArtifactKindMismatchError is returned when an injected artifact is a
+// directory but was declared as a file type, or the reverse.
+type ArtifactKindMismatchError struct {
+	Step     string
+	Artifact string
+	Path     string
+	Declared string // declared artifact type, e.g. "json" or "directory"
+	Found    string // "file" or "directory"
+}
+
+func (e *ArtifactKindMismatchError) Error() string {
+	return fmt.Sprintf("artifact '%s' from step '%s' is declared as type %q but %s is a %s",
+		e.Artifact, e.Step, e.Declared, e.Path, e.Found)
+}
@@ -78,6 +78,10 @@ type DefaultPipelineExecutor struct {
 	etaCalculator *ETACalculator
 	// Preserve workspace from previous run (skip cleanup for debugging)
 	preserveWorkspace bool
+	// Nested runs (sub-pipelines, matrix children, sequence stages) keep their
+	// workspace regardless of runtime.workspace_cleanup: the caller reads
+	// their artifacts after Execute returns.
+	nestedRun bool
 	// Step filter for selective step execution (--steps / --exclude)
 	stepFilter *StepFilter
 	// Skill store for DirectoryStore-based skill provisioning
@@ -100,6 +104,9 @@ type DefaultPipelineExecutor struct {
 	parentArtifactPaths map[string]string
 	// Parent workspace path for workspace.ref: parent resolution
 	parentWorkspacePath string
+	// Thread transcripts shared by every execution of a Conversation; nil
+	// gives each execution its own.
+	threadManager *ThreadManager
 	// Parent env vars injected from a parent sub-pipeline step's Config.Env.
 	// Seeded into PipelineContext.CustomVariables as env.<key> so child
 	// templates resolve {{ env.<key> }} via ResolvePlaceholders.
@@ -112,6 +119,19 @@ type DefaultPipelineExecutor struct {
 	webhookRunner *hooks.WebhookRunner
 	// Task-level complexity from classifier (empty = no task-aware routing)
 	taskComplexity string
+	// Overrides for the pipeline's vars block (from CLI --var)
+	varOverrides map[string]string
+	// Reproducible run mode (from CLI --deterministic); see deterministic.go
+	deterministic bool
+	seed          string
+	// Bypass the adapter response cache (from CLI --no-adapter-cache)
+	noAdapterCache bool
+	// Ready steps executed at once (from CLI --max-parallel); 0 defers to
+	// runtime.max_parallel_steps
+	maxParallelSteps int
+	// Files tracking issues for repeated step failures; resolved from the
+	// detected forge when nil (see failure_issues.go)
+	forgeClient forge.Client
 	// Per-run EvalSignal collectors keyed by run ID. Populated by
 	// recordStepEval on terminal step transitions; drained by
 	// recordPipelineEval into a state.PipelineEvalRecord at run finalize.
@@ -215,6 +235,19 @@ func WithPreserveWorkspace(preserve bool) ExecutorOption {
 	return func(ex *DefaultPipelineExecutor) { ex.preserveWorkspace = preserve }
 }
 
+// WithMaxParallelSteps caps how many ready steps of a batch run at once
+// (CLI --max-parallel), overriding runtime.max_parallel_steps. 0 keeps the
+// manifest limit.
+func WithMaxParallelSteps(n int) ExecutorOption {
+	return func(ex *DefaultPipelineExecutor) { ex.maxParallelSteps = n }
+}
+
+// withNestedRun marks the executor as running on behalf of a parent
+// pipeline or sequence, exempting its workspace from outcome cleanup.
+func withNestedRun() ExecutorOption {
+	return func(ex *DefaultPipelineExecutor) { ex.nestedRun = true }
+}
+
 // WithStepFilter sets the step filter for selective step execution.
 func WithStepFilter(f *StepFilter) ExecutorOption {
 	return func(ex *DefaultPipelineExecutor) { ex.stepFilter = f }
@@ -293,16 +326,31 @@ func (e *DefaultPipelineExecutor) workspaceRunIDFor(pipelineID string) string {
 	return pipelineID
 }
 
+// threadsFor returns the thread manager of a new execution: the one a
+// Conversation shares across its turns, or a fresh one.
+func (e *DefaultPipelineExecutor) threadsFor(adapter relay.CompactionAdapter) *ThreadManager {
+	if e.threadManager != nil {
+		return e.threadManager
+	}
+	return NewThreadManager(adapter)
+}
+
 // createRunID generates a run ID, preferring the state store's CreateRun()
 // so the run appears in the dashboard. Falls back to GenerateRunID() if
-// the store is unavailable or the call fails.
-func (e *DefaultPipelineExecutor) createRunID(name string, hashLen int, input string) string {
+// the store is unavailable or the call fails. Deterministic runs derive the
+// ID from the seed and skip the store, whose IDs embed the wall clock.
+func (e *DefaultPipelineExecutor) createRunID(name string, rt manifest.Runtime, input string) string {
+	if e.deterministic {
+		// Children share the parent's seed; the input tells apart iterations
+		// of the same sub-pipeline.
+		return fmt.Sprintf("%s-%s", name, seededHexSuffix(rt.PipelineIDHashLength, e.seed, name, input))
+	}
 	if e.store != nil {
 		if id, err := e.store.CreateRun(name, input); err == nil {
 			return id
 		}
 	}
-	return GenerateRunID(name, hashLen)
+	return NewRunID(rt, name)
 }
 
 // WorktreeInfo tracks a shared worktree created for a specific branch.
@@ -319,6 +367,7 @@ type PipelineExecution struct {
 	States            map[string]string
 	Results           map[string]map[string]interface{}
 	ArtifactPaths     map[string]string        // "stepID:artifactName" -> filesystem path
+	ArtifactChecksums map[string]string        // "stepID:artifactName" -> SHA-256 at registration
 	WorkspacePaths    map[string]string        // stepID -> workspace path
 	WorktreePaths     map[string]*WorktreeInfo // resolved branch -> worktree info
 	Input             string
@@ -329,6 +378,33 @@ type PipelineExecution struct {
 	ThreadManager     *ThreadManager             // Thread conversation continuity manager
 	CircuitBreaker    *CircuitBreaker            // Failure fingerprint tracking for circuit breaking
 	Watchdog          *StallWatchdog             // Current step's stall watchdog (set during step execution)
+	StepAdapters      map[string]StepAdapter     // stepID -> adapter/model of the latest attempt
+	SkippedByDesign   map[string]bool            // stepID -> skipped by canary sampling or a false when: condition
+	FlakySteps        map[string]StepReliability // stepID -> history, for steps flagged by runtime.flaky_steps (loaded on first use)
+	StepFailures      []error                    // *StepExecutionError of failed steps whose on_failure kept the run going
+
+	adapterExits      adapter.ExitCounter // consecutive non-zero exits per adapter, for persona adapter failover
+	runBudgetReported bool                // pipeline token budget already reported as exceeded
+	stepIndexBase     int                 // index of Pipeline.Steps[0] in the full pipeline; non-zero for resumed runs
+	adapterLogWorker  string              // matrix worker or concurrency agent ("worker_2", "agent_0") whose adapter log this is
+}
+
+// StepAdapter is the persona, adapter and model a step was dispatched with.
+type StepAdapter struct {
+	Persona string
+	Adapter string
+	Model   string
+}
+
+// recordStepAdapter remembers what a step was dispatched with, for the
+// run attestation.
+func (ex *PipelineExecution) recordStepAdapter(stepID string, a StepAdapter) {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	if ex.StepAdapters == nil {
+		ex.StepAdapters = make(map[string]StepAdapter)
+	}
+	ex.StepAdapters[stepID] = a
 }
 
 // stepRunResources holds resolved values needed to dispatch a single step to an adapter.
@@ -344,6 +420,8 @@ type stepRunResources struct {
 	resolvedModel       string
 	configuredModel     string
 	prompt              string
+	promptTokens        int                     // tokenizer-measured size of the assembled prompt
+	changes             *state.StepChangeRecord // files the adapter run changed; nil when unknown
 }
 
 // pipelineSetup holds the results of pipeline preflight validation.
@@ -399,6 +477,7 @@ func (e *DefaultPipelineExecutor) NewChildExecutor() *DefaultPipelineExecutor {
 		outcomeTracker:         state.NewOutcomeTracker("", e.store),
 		crossPipelineArtifacts: e.crossPipelineArtifacts,
 		preserveWorkspace:      e.preserveWorkspace,
+		nestedRun:              true,
 		skillStore:             e.skillStore,
 		hookRunner:             e.hookRunner,
 		autoApprove:            e.autoApprove,
@@ -406,6 +485,11 @@ func (e *DefaultPipelineExecutor) NewChildExecutor() *DefaultPipelineExecutor {
 		retroGenerator:         e.retroGenerator,
 		evalCollectors:         make(map[string]*contract.SignalSet),
 		evolutionTrigger:       e.evolutionTrigger,
+		deterministic:          e.deterministic,
+		seed:                   e.seed,
+		noAdapterCache:         e.noAdapterCache,
+		maxParallelSteps:       e.maxParallelSteps,
+		forgeClient:            e.forgeClient,
 	}
 	// Share parent security layer's collaborators so child sees identical
 	// path/sanitization config but with its own back-pointer.
@@ -431,13 +515,7 @@ func (e *DefaultPipelineExecutor) LastExecution() *PipelineExecution {
 }
 
 func (e *DefaultPipelineExecutor) Execute(ctx context.Context, p *Pipeline, m *manifest.Manifest, input string) error {
-	// Initialize cost ledger from manifest config
-	if e.costLedger == nil {
-		costCfg := m.Runtime.Cost
-		if costCfg.Enabled || costCfg.BudgetCeiling > 0 {
-			e.costLedger = cost.NewLedger(costCfg.BudgetCeiling, costCfg.WarnAt)
-		}
-	}
+	e.initCostLedger(m)
 
 	// Detect graph-mode pipelines (edges or conditional steps present)
 	if isGraphPipeline(p) {
@@ -464,15 +542,26 @@ func (e *DefaultPipelineExecutor) Execute(ctx context.Context, p *Pipeline, m *m
 		return err
 	}
 
+	// Phase 4b: Take the pipeline's resource locks, waiting for other runs
+	releaseLocks, err := e.acquireLocks(runCtx, execution, nil)
+	if err != nil {
+		e.writeAttestation(execution, false)
+		e.applyWorkspaceCleanup(execution, false)
+		return err
+	}
+	defer releaseLocks()
+
 	// Phase 5: Schedule and execute steps
 	schedulableSteps, err := e.runSchedulingLoop(runCtx, execution, setup.sortedSteps)
 	if err != nil {
+		e.writeAttestation(execution, false)
+		e.applyWorkspaceCleanup(execution, false)
 		return err
 	}
 
 	// Phase 6: Finalize (status, terminal hooks, retro, cleanup)
 	e.finalizePipelineExecution(runCtx, execution, schedulableSteps)
-	return nil
+	return execution.partialFailure()
 }
 
 // validatePipelineAndCreateContext validates the pipeline structure (DAG, threads, sort,
@@ -516,6 +605,21 @@ func (e *DefaultPipelineExecutor) GetTotalTokens() int {
 	return e.totalTokens
 }
 
+// initCostLedger creates the run's cost ledger from runtime.cost. Every
+// run is priced, so step costs reach the state store; the budget ceiling
+// and warning threshold only apply when configured.
+func (e *DefaultPipelineExecutor) initCostLedger(m *manifest.Manifest) {
+	if e.costLedger != nil || m == nil {
+		return
+	}
+	costCfg := m.Runtime.Cost
+	overrides := make(map[string]cost.ModelPricing, len(costCfg.Pricing))
+	for model, price := range costCfg.Pricing {
+		overrides[model] = cost.ModelPricing{InputPerMillion: price.InputPerMillion, OutputPerMillion: price.OutputPerMillion}
+	}
+	e.costLedger = cost.NewLedgerWithPricing(costCfg.BudgetCeiling, costCfg.WarnAt, cost.NewPricing(overrides))
+}
+
 // GetCostSummary returns a human-readable cost summary for the run, or empty if no cost tracking.
 func (e *DefaultPipelineExecutor) GetCostSummary() string {
 	if e.costLedger == nil {
@@ -532,6 +636,10 @@ func (e *DefaultPipelineExecutor) GetTotalCost() float64 {
 	return e.costLedger.TotalCost()
 }
 
+// Resume re-runs an execution this executor still holds in memory, starting
+// at fromStep. Runs from another process, including one that has exited or
+// crashed, are resumed with ResumeWithValidation, which rebuilds the
+// execution from the checkpoints in the state store.
 func (e *DefaultPipelineExecutor) Resume(ctx context.Context, pipelineID string, fromStep string) error {
 	e.mu.RLock()
 	execution, exists := e.pipelines[pipelineID]
@@ -652,9 +760,11 @@ func (e *DefaultPipelineExecutor) cleanupCompletedPipeline(pipelineID string) {
 
 // ResumeWithValidation resumes a pipeline with full validation and error handling.
 // When force is true, phase validation and stale artifact checks are skipped.
-// When priorRunID is provided, artifact paths are resolved from that specific run's
-// workspace directory instead of scanning for the most recent match.
+// When priorRunID is provided, completed steps are restored from that run's
+// checkpoints, falling back to its workspace directory instead of scanning
+// for the most recent match.
 func (e *DefaultPipelineExecutor) ResumeWithValidation(ctx context.Context, p *Pipeline, m *manifest.Manifest, input string, fromStep string, force bool, priorRunID ...string) error {
+	e.initCostLedger(m)
 	manager := NewResumeManager(e)
 	return manager.ResumeFromStep(ctx, p, m, input, fromStep, force, priorRunID...)
 }
@@ -123,7 +123,12 @@ func (e *DefaultPipelineExecutor) buildStepPrompt(execution *PipelineExecution,
 
 	if attemptCtx != nil {
 		var sb strings.Builder
-		if attemptCtx.FailedStepID != "" {
+		if attemptCtx.ErrorArtifactPath != "" {
+			// Failure handler context — this step is the on_failure.run handler of a failed step
+			sb.WriteString("## FAILURE HANDLER CONTEXT\n\n")
+			fmt.Fprintf(&sb, "Step %q failed after %d attempt(s) and you are running as its failure handler.\n", attemptCtx.FailedStepID, attemptCtx.Attempt)
+			fmt.Fprintf(&sb, "The failure is recorded in `%s`.\n\n", attemptCtx.ErrorArtifactPath)
+		} else if attemptCtx.FailedStepID != "" {
 			// Rework context — this step is a rework target for a failed step
 			sb.WriteString("## REWORK CONTEXT\n\n")
 			fmt.Fprintf(&sb, "You are executing as a rework step for failed step %q.\n", attemptCtx.FailedStepID)
@@ -144,6 +149,14 @@ func (e *DefaultPipelineExecutor) buildStepPrompt(execution *PipelineExecution,
 			}
 			sb.WriteString("\n")
 		}
+		if len(attemptCtx.KnownRemediations) > 0 {
+			sb.WriteString("### Known Remediations\n")
+			sb.WriteString("This failure matches signatures recorded in the project's error knowledge base. Apply these notes:\n")
+			for _, note := range attemptCtx.KnownRemediations {
+				sb.WriteString(fmt.Sprintf("- %s\n", note))
+			}
+			sb.WriteString("\n")
+		}
 		if attemptCtx.PriorStdout != "" {
 			sb.WriteString(fmt.Sprintf("### Previous Output (last %d chars)\n```\n", maxStdoutTailChars))
 			sb.WriteString(attemptCtx.PriorStdout)
@@ -161,7 +174,9 @@ func (e *DefaultPipelineExecutor) buildStepPrompt(execution *PipelineExecution,
 			sb.WriteString(fmt.Sprintf("A review agent found issues with the previous implementation. Structured feedback is available at: `%s`\n", attemptCtx.ReviewFeedbackPath))
 			sb.WriteString("Read this file to understand the specific issues and suggestions before making changes.\n\n")
 		}
-		if len(attemptCtx.ContractErrors) > 0 {
+		if attemptCtx.ErrorArtifactPath != "" {
+			sb.WriteString("Clean up after the failed step as instructed below. Do not redo its work.\n\n---\n\n")
+		} else if len(attemptCtx.ContractErrors) > 0 {
 			sb.WriteString("Fix the specific failure above. Do not start from scratch.\n\n---\n\n")
 		} else {
 			sb.WriteString("Please address the issues from the previous attempt and try a different approach if needed.\n\n---\n\n")
@@ -197,12 +212,24 @@ func (e *DefaultPipelineExecutor) buildStepPrompt(execution *PipelineExecution,
 		var sb strings.Builder
 		sb.WriteString("\n## Input Artifacts\n\n")
 		sb.WriteString("Upstream artifacts have been placed in your workspace at these paths:\n\n")
+		artifactTypes := e.buildArtifactTypeMap(execution)
 		for _, ref := range step.Memory.InjectArtifacts {
 			name := ref.As
 			if name == "" {
 				name = ref.Artifact
 			}
-			sb.WriteString(fmt.Sprintf("- `.agents/artifacts/%s` (from step `%s`, artifact `%s`)\n", name, ref.Step, ref.Artifact))
+			kind := ""
+			switch artifactTypes[ref.Step+":"+ref.Artifact] {
+			case ArtifactTypeDirectory:
+				kind = " — directory"
+			case ArtifactTypeBinary:
+				kind = " — binary file, do not read as text"
+			}
+			if ref.FromPipeline != "" {
+				sb.WriteString(fmt.Sprintf("- `.agents/artifacts/%s` (from pipeline `%s`, artifact `%s`)%s\n", name, ref.FromPipeline, ref.Artifact, kind))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("- `.agents/artifacts/%s` (from step `%s`, artifact `%s`)%s\n", name, ref.Step, ref.Artifact, kind))
 		}
 		sb.WriteString("\nRead these files at the paths shown. They are guaranteed to exist before this step runs.\n\n")
 		sb.WriteString(prompt)
@@ -255,6 +282,49 @@ func (e *DefaultPipelineExecutor) injectArtifacts(execution *PipelineExecution,
 		}
 		destPath := filepath.Join(artifactsDir, artName)
 
+		// Cross-run artifact reference: resolve from the latest completed
+		// run of another pipeline via the state store
+		if ref.FromPipeline != "" {
+			resolved, err := e.resolveFromPipelineArtifact(ref)
+			if err == nil {
+				err = checkArtifactKind(ref, ref.Type, resolved.Record.Path)
+			}
+			if err != nil {
+				var notFound *ArtifactNotFoundError
+				if ref.Optional && (resolved == nil || errors.As(err, &notFound)) {
+					e.emit(event.Event{
+						Timestamp:  time.Now(),
+						PipelineID: pipelineID,
+						StepID:     step.ID,
+						State:      "step_progress",
+						Message:    fmt.Sprintf("optional artifact '%s' from pipeline '%s' unavailable, skipping: %v", ref.Artifact, ref.FromPipeline, err),
+					})
+					continue
+				}
+				return err
+			}
+			if err := checkArtifactSHA256(ref, resolved.Record.Path, resolved.SHA256); err != nil {
+				return err
+			}
+			if err := materializeArtifact(resolved.Record.Path, destPath); err != nil {
+				return fmt.Errorf("failed to inject artifact '%s' from pipeline '%s': %w", ref.Artifact, ref.FromPipeline, err)
+			}
+			execution.Context.SetArtifactPath(artName, destPath)
+			e.emit(event.Event{
+				Timestamp:  time.Now(),
+				PipelineID: pipelineID,
+				StepID:     step.ID,
+				State:      "step_progress",
+				Message:    fmt.Sprintf("injected artifact %s from pipeline %s run %s (%s)", artName, ref.FromPipeline, resolved.RunID, resolved.Record.Path),
+			})
+			if ref.SchemaPath != "" {
+				if err := e.validateInputArtifactSchema(pipelineID, step, artName, ref.SchemaPath, destPath); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
 		// Cross-pipeline artifact reference: look up from prior pipeline outputs
 		if ref.Pipeline != "" && e.crossPipelineArtifacts != nil {
 			pipelineArtifacts, hasPipeline := e.crossPipelineArtifacts[ref.Pipeline]
@@ -308,23 +378,9 @@ func (e *DefaultPipelineExecutor) injectArtifacts(execution *PipelineExecution,
 
 			// Schema validation for input artifacts (if schema_path is specified)
 			if ref.SchemaPath != "" {
-				schemaContent, err := e.sec.loadSchemaContent(step, ref.SchemaPath)
-				if err != nil {
-					return fmt.Errorf("input artifact '%s': %w", artName, err)
-				}
-				if schemaContent == "" {
-					return fmt.Errorf("input artifact '%s': schema %s produced no content", artName, ref.SchemaPath)
-				}
-				if err := contract.ValidateInputArtifactContent(artName, schemaContent, destPath); err != nil {
-					return fmt.Errorf("input artifact '%s' schema validation failed: %w", artName, err)
+				if err := e.validateInputArtifactSchema(pipelineID, step, artName, ref.SchemaPath, destPath); err != nil {
+					return err
 				}
-				e.emit(event.Event{
-					Timestamp:  time.Now(),
-					PipelineID: pipelineID,
-					StepID:     step.ID,
-					State:      "step_progress",
-					Message:    fmt.Sprintf("validated artifact %s against schema %s", artName, ref.SchemaPath),
-				})
 			}
 			continue
 		}
@@ -388,23 +444,41 @@ func (e *DefaultPipelineExecutor) injectArtifacts(execution *PipelineExecution,
 			}
 		}
 
-		srcData, err := os.ReadFile(artifactPath)
-		if err != nil {
-			if ref.Optional {
+		declared := artifactTypes[key]
+		if declared == "" {
+			declared = ref.Type
+		}
+		if err := checkArtifactKind(ref, declared, artifactPath); err != nil {
+			var notFound *ArtifactNotFoundError
+			if ref.Optional && errors.As(err, &notFound) {
 				e.emit(event.Event{
 					Timestamp:  time.Now(),
 					PipelineID: pipelineID,
 					StepID:     step.ID,
 					State:      "step_progress",
-					Message:    fmt.Sprintf("optional artifact '%s' could not be read, skipping: %v", ref.Artifact, err),
+					Message:    fmt.Sprintf("optional artifact '%s' from step '%s' not found at %s, skipping", ref.Artifact, ref.Step, artifactPath),
 				})
 				continue
 			}
-			return fmt.Errorf("failed to read required artifact '%s': %w", ref.Artifact, err)
+			return err
 		}
 
-		if err := os.WriteFile(destPath, srcData, 0644); err != nil {
-			return fmt.Errorf("failed to write artifact '%s': %w", artName, err)
+		if err := verifyArtifactChecksum(execution, ref, artifactPath); err != nil {
+			return err
+		}
+
+		if err := materializeArtifact(artifactPath, destPath); err != nil {
+			if ref.Optional {
+				e.emit(event.Event{
+					Timestamp:  time.Now(),
+					PipelineID: pipelineID,
+					StepID:     step.ID,
+					State:      "step_progress",
+					Message:    fmt.Sprintf("optional artifact '%s' could not be read, skipping: %v", ref.Artifact, err),
+				})
+				continue
+			}
+			return fmt.Errorf("failed to inject required artifact '%s': %w", ref.Artifact, err)
 		}
 		// Register artifact path in context for template resolution
 		execution.Context.SetArtifactPath(artName, destPath)
@@ -418,29 +492,38 @@ func (e *DefaultPipelineExecutor) injectArtifacts(execution *PipelineExecution,
 
 		// Schema validation for input artifacts (if schema_path is specified)
 		if ref.SchemaPath != "" {
-			schemaContent, err := e.sec.loadSchemaContent(step, ref.SchemaPath)
-			if err != nil {
-				return fmt.Errorf("input artifact '%s': %w", artName, err)
+			if err := e.validateInputArtifactSchema(pipelineID, step, artName, ref.SchemaPath, destPath); err != nil {
+				return err
 			}
-			if schemaContent == "" {
-				return fmt.Errorf("input artifact '%s': schema %s produced no content", artName, ref.SchemaPath)
-			}
-			if err := contract.ValidateInputArtifactContent(artName, schemaContent, destPath); err != nil {
-				return fmt.Errorf("input artifact '%s' schema validation failed: %w", artName, err)
-			}
-			e.emit(event.Event{
-				Timestamp:  time.Now(),
-				PipelineID: pipelineID,
-				StepID:     step.ID,
-				State:      "step_progress",
-				Message:    fmt.Sprintf("validated artifact %s against schema %s", artName, ref.SchemaPath),
-			})
 		}
 	}
 
 	return nil
 }
 
+// validateInputArtifactSchema validates an injected artifact against the
+// JSON schema at schemaPath.
+func (e *DefaultPipelineExecutor) validateInputArtifactSchema(pipelineID string, step *Step, artName, schemaPath, destPath string) error {
+	schemaContent, err := e.sec.loadSchemaContent(step, schemaPath)
+	if err != nil {
+		return fmt.Errorf("input artifact '%s': %w", artName, err)
+	}
+	if schemaContent == "" {
+		return fmt.Errorf("input artifact '%s': schema %s produced no content", artName, schemaPath)
+	}
+	if err := contract.ValidateInputArtifactContent(artName, schemaContent, destPath); err != nil {
+		return fmt.Errorf("input artifact '%s' schema validation failed: %w", artName, err)
+	}
+	e.emit(event.Event{
+		Timestamp:  time.Now(),
+		PipelineID: pipelineID,
+		StepID:     step.ID,
+		State:      "step_progress",
+		Message:    fmt.Sprintf("validated artifact %s against schema %s", artName, schemaPath),
+	})
+	return nil
+}
+
 // buildArtifactTypeMap builds a map of artifact keys to their declared types
 func (e *DefaultPipelineExecutor) buildArtifactTypeMap(execution *PipelineExecution) map[string]string {
 	types := make(map[string]string)
@@ -498,9 +581,10 @@ func (e *DefaultPipelineExecutor) writeOutputArtifacts(execution *PipelineExecut
 					"artifact": art.Name,
 					"path":     artPath,
 				})
-			} else if len(stdout) > 0 {
+			} else if len(stdout) > 0 && !art.IsOpaque() {
 				// Fall back to writing ResultContent (skip when nil/empty
-				// to avoid creating zero-byte files from empty adapter output)
+				// to avoid creating zero-byte files from empty adapter output,
+				// and for binary/directory artifacts where text is meaningless)
 				_ = os.MkdirAll(filepath.Dir(artPath), 0755)
 				_ = os.WriteFile(artPath, stdout, 0644)
 				execution.mu.Lock()
@@ -509,13 +593,37 @@ func (e *DefaultPipelineExecutor) writeOutputArtifacts(execution *PipelineExecut
 			}
 		}
 
+		if artPath != "" {
+			e.normalizeJSONArtifact(execution, step, art, workspacePath, artPath)
+		}
+
 		// Archive artifact to a step-specific path so shared-worktree steps
 		// don't all point at the same file in the DB. The injection system
 		// keeps using artPath (the workspace-relative location), but the DB
 		// gets the archived copy which survives subsequent steps overwriting
 		// the same relative path.
 		registeredPath := artPath
-		if !art.IsStdoutArtifact() {
+		var opaqueMeta *opaqueArtifactMetadata
+		if art.Type == ArtifactTypeDirectory && !art.IsStdoutArtifact() {
+			// Directories are archived as tar.zst; injection extracts the
+			// archive so downstream steps see the tree as it was produced.
+			if info, err := os.Stat(artPath); err == nil && info.IsDir() {
+				archiveDir := filepath.Join(workspacePath, ".agents", "artifacts", step.ID)
+				if archivePath, meta, err := archiveDirectoryArtifact(artPath, archiveDir, art.Name); err == nil {
+					registeredPath = archivePath
+					opaqueMeta = &meta
+					execution.mu.Lock()
+					execution.ArtifactPaths[key] = archivePath
+					execution.mu.Unlock()
+				} else {
+					e.trace(audit.TraceArtifactWrite, step.ID, 0, map[string]string{
+						"artifact": art.Name,
+						"path":     artPath,
+						"error":    err.Error(),
+					})
+				}
+			}
+		} else if !art.IsStdoutArtifact() {
 			archiveDir := filepath.Join(workspacePath, ".agents", "artifacts", step.ID)
 			archiveName := art.Name
 			if art.Type == "json" && !strings.HasSuffix(archiveName, ".json") {
@@ -537,7 +645,16 @@ func (e *DefaultPipelineExecutor) writeOutputArtifacts(execution *PipelineExecut
 			if info, err := os.Stat(registeredPath); err == nil {
 				size = info.Size()
 			}
-			_ = e.store.RegisterArtifact(execution.Status.ID, step.ID, art.Name, registeredPath, art.Type, size)
+			sum := e.registerArtifact(execution.Status.ID, step, art.Name, registeredPath, art.Type, size)
+			// The registered copy has the same bytes as the path injection
+			// reads, so its checksum detects later modification of either.
+			recordArtifactChecksum(execution, key, sum)
+			if art.Type == ArtifactTypeBinary && size > 0 {
+				opaqueMeta = &opaqueArtifactMetadata{Kind: ArtifactTypeBinary, Bytes: size, PreviewSuppressed: true}
+			}
+			if opaqueMeta != nil {
+				e.mergeArtifactMetadata(execution.Status.ID, step, map[string]bool{art.Name: true}, "", "", "", opaqueMeta)
+			}
 		}
 	}
 
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/hooks"
+	"github.com/recinq/wave/internal/manifest"
 	"github.com/recinq/wave/internal/state"
 	"golang.org/x/sync/errgroup"
 )
@@ -115,6 +117,9 @@ func (e *DefaultPipelineExecutor) runNamedSubPipeline(ctx context.Context, execu
 	if e.stepTimeoutOverride > 0 {
 		childOpts = append(childOpts, WithStepTimeout(e.stepTimeoutOverride))
 	}
+	if e.deterministic {
+		childOpts = append(childOpts, WithDeterministic(e.seed))
+	}
 	if e.debugTracer != nil {
 		childOpts = append(childOpts, WithDebugTracer(e.debugTracer))
 	}
@@ -233,7 +238,11 @@ func (e *DefaultPipelineExecutor) runNamedSubPipeline(ctx context.Context, execu
 	// Create a run record for the child pipeline so it appears in the dashboard.
 	// Link to parent immediately so the WebUI can nest it from the start.
 	if e.store != nil {
-		childRunID := e.createRunID(pipelineName, 4, input)
+		childRuntime := manifest.Runtime{PipelineIDHashLength: 4}
+		if execution.Manifest != nil {
+			childRuntime.Naming = execution.Manifest.Runtime.Naming
+		}
+		childRunID := e.createRunID(pipelineName, childRuntime, input)
 		childOpts = append(childOpts, WithRunID(childRunID))
 		_ = e.store.SetParentRun(childRunID, pipelineID, step.ID)
 		// Issue #1450 — record composition metadata so iterate progress
@@ -241,7 +250,7 @@ func (e *DefaultPipelineExecutor) runNamedSubPipeline(ctx context.Context, execu
 		_ = e.store.SetRunComposition(childRunID, info.effectiveKind(), pipelineName, info.iterateMode, info.iterateIndex, info.iterateTotal)
 	}
 
-	childOpts = append(childOpts, WithRegistry(e.registry))
+	childOpts = append(childOpts, WithRegistry(e.registry), withNestedRun())
 
 	// Propagate step-level adapter override to child sub-pipeline
 	if step.Adapter != "" {
@@ -577,7 +586,8 @@ func (e *DefaultPipelineExecutor) collectIterateOutputs(execution *PipelineExecu
 
 	// For each child pipeline name, find the first artifact path that was
 	// merged under its namespace (e.g. "audit-alpha.scan:output") and read
-	// its content. Order matches the original items array.
+	// its content, skipping the transcript and change summary Wave records
+	// for every step. Order matches the original items array.
 	collected := make([]json.RawMessage, 0, len(resolvedNames))
 	execution.Context.mu.Lock()
 	artifactSnapshot := make(map[string]string, len(execution.Context.ArtifactPaths))
@@ -590,7 +600,7 @@ func (e *DefaultPipelineExecutor) collectIterateOutputs(execution *PipelineExecu
 		prefix := name + "."
 		var artPath string
 		for key, path := range artifactSnapshot {
-			if strings.HasPrefix(key, prefix) {
+			if strings.HasPrefix(key, prefix) && !isStepRecordArtifact(key) {
 				artPath = path
 				break
 			}
@@ -648,7 +658,8 @@ func (e *DefaultPipelineExecutor) collectIterateOutputs(execution *PipelineExecu
 		if info, statErr := os.Stat(outputPath); statErr == nil {
 			size = info.Size()
 		}
-		_ = e.store.RegisterArtifact(execution.Status.ID, step.ID, "collected-output", outputPath, "json", size)
+		recordArtifactChecksum(execution, step.ID+":collected-output",
+			e.registerArtifact(execution.Status.ID, step, "collected-output", outputPath, "json", size))
 	}
 
 	return nil
@@ -714,7 +725,8 @@ func (e *DefaultPipelineExecutor) executeAggregateInDAG(_ context.Context, execu
 		if info, statErr := os.Stat(outputPath); statErr == nil {
 			size = info.Size()
 		}
-		_ = e.store.RegisterArtifact(pipelineID, step.ID, artifactName, outputPath, "json", size)
+		recordArtifactChecksum(execution, step.ID+":"+artifactName,
+			e.registerArtifact(pipelineID, step, artifactName, outputPath, "json", size))
 	}
 
 	execution.mu.Lock()
@@ -869,6 +881,28 @@ func (e *DefaultPipelineExecutor) executeLoopInDAG(ctx context.Context, executio
 	return nil
 }
 
+// fireGateRequested notifies hooks and webhooks that a gate is waiting for
+// a human decision, so chat integrations can offer the choices remotely.
+func (e *DefaultPipelineExecutor) fireGateRequested(ctx context.Context, pipelineID string, step *Step) {
+	req := &hooks.GateRequest{Message: step.Gate.Prompt}
+	if req.Message == "" {
+		req.Message = step.Gate.Message
+	}
+	for _, c := range step.Gate.Choices {
+		req.Choices = append(req.Choices, hooks.GateChoice{Key: c.Key, Label: c.Label, Target: c.Target})
+	}
+	evt := hooks.HookEvent{
+		Type:       hooks.EventGateRequested,
+		PipelineID: pipelineID,
+		StepID:     step.ID,
+		Gate:       req,
+	}
+	if e.hookRunner != nil {
+		e.hookRunner.RunHooks(ctx, evt)
+	}
+	e.fireWebhooks(ctx, evt)
+}
+
 // executeGateInDAG handles gate steps within a DAG pipeline.
 // It delegates to GateExecutor, stores the decision in PipelineContext,
 // writes freeform text as an artifact, and returns routing information via error type.
@@ -900,6 +934,11 @@ func (e *DefaultPipelineExecutor) executeGateInDAG(ctx context.Context, executio
 	// Annotate the gate config with the step ID so downstream handlers
 	// (e.g. WebUI) can associate the pending gate with a specific step.
 	step.Gate.RuntimeStepID = step.ID
+	step.Gate.RuntimeRunID = pipelineID
+
+	if step.Gate.Type == "approval" && len(step.Gate.Choices) > 0 && !step.Gate.Auto && !e.autoApprove {
+		e.fireGateRequested(ctx, pipelineID, step)
+	}
 
 	decision, err := gate.ExecuteWithDecision(ctx, step.Gate, nil)
 	if err != nil {
@@ -480,3 +480,63 @@ func TestSequencePipelineCrossArtifactsStillWork(t *testing.T) {
 	assert.Equal(t, []byte(`{"r":"ok"}`), captured[0]["upstream"]["report.json"])
 }
 
+// TestSubPipelineStep_ArtifactsReachDownstreamSteps runs a `pipeline:` step
+// with a mapped input and checks that a later parent step can inject the
+// child's output artifact as "<step-id>:<artifact>".
+func TestSubPipelineStep_ArtifactsReachDownstreamSteps(t *testing.T) {
+	capAdapter := &crossArtifactCapturingAdapter{
+		MockAdapter: adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status":"ok"}`)),
+	}
+	executor := NewDefaultPipelineExecutor(capAdapter)
+
+	tmpDir := t.TempDir()
+	m := testutil.CreateTestManifest(tmpDir)
+	pipelinesDir := filepath.Join(tmpDir, ".agents", "pipelines")
+	require.NoError(t, os.MkdirAll(pipelinesDir, 0755))
+	childYAML := `kind: WavePipeline
+metadata:
+  name: summarize
+input:
+  source: cli
+  type: string
+steps:
+  - id: write
+    type: command
+    script: "mkdir -p .agents/output && printf 'summary of %s' '{{ input }}' > .agents/output/summary.md"
+    output_artifacts:
+      - name: summary
+        path: .agents/output/summary.md
+        type: markdown
+`
+	require.NoError(t, os.WriteFile(filepath.Join(pipelinesDir, "summarize.yaml"), []byte(childYAML), 0644))
+
+	origDir, _ := os.Getwd()
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() { _ = os.Chdir(origDir) }()
+
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "release"},
+		Steps: []Step{
+			{ID: "summarize", SubPipeline: "summarize", SubInput: "release-{{ input }}"},
+			{
+				ID:           "publish",
+				Persona:      "navigator",
+				Dependencies: []string{"summarize"},
+				Memory: MemoryConfig{InjectArtifacts: []ArtifactRef{
+					{Step: "summarize", Artifact: "summary", As: "summary"},
+				}},
+				Exec: ExecConfig{Type: "prompt", Source: "publish the summary"},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, executor.Execute(ctx, p, m, "42"))
+
+	configs := capAdapter.snapshot()
+	require.Len(t, configs, 1, "only the downstream persona step calls the adapter")
+	injected, err := os.ReadFile(filepath.Join(configs[0].WorkspacePath, ".agents", "artifacts", "summary"))
+	require.NoError(t, err)
+	assert.Equal(t, "summary of release-42", string(injected))
+}
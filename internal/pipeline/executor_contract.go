@@ -348,7 +348,7 @@ func (e *DefaultPipelineExecutor) applyContractOnFailure(
 // runSingleContract validates one contract and emits lifecycle events.
 // For agent_review, it calls ValidateWithRunner; for all others, it calls contract.Validate.
 func (e *DefaultPipelineExecutor) runSingleContract(
-	_ context.Context,
+	ctx context.Context,
 	execution *PipelineExecution,
 	step *Step,
 	c ContractConfig,
@@ -365,15 +365,20 @@ func (e *DefaultPipelineExecutor) runSingleContract(
 	if c.Source != "" {
 		// Explicit source: use as-is
 		resolvedSource = execution.Context.ResolveContractSource(c)
-	} else if len(step.OutputArtifacts) > 0 {
-		// No explicit source: use output_artifacts[0].Path directly (root path)
+	} else if len(step.OutputArtifacts) > 0 && c.Type != "coverage" {
+		// No explicit source: use output_artifacts[0].Path directly (root path).
+		// A coverage contract's source is the coverage report instead.
 		resolvedSource = step.OutputArtifacts[0].Path
 	}
 
 	// Resolve {{ project.* }} placeholders in command
-	resolvedCommand := c.Command
+	command := c.Command
+	if c.Type == "coverage" && command == "" {
+		command = "{{ project.coverage_command }}"
+	}
+	resolvedCommand := command
 	if execution.Context != nil {
-		resolvedCommand = execution.Context.ResolvePlaceholders(c.Command)
+		resolvedCommand = execution.Context.ResolvePlaceholders(command)
 	}
 
 	// Display name for tracing
@@ -412,6 +417,12 @@ func (e *DefaultPipelineExecutor) runSingleContract(
 	contractCfg.Source = resolvedSource
 	contractCfg.Command = resolvedCommand
 	contractCfg.ArtifactPaths = artifactPaths
+	if c.Type == "coverage" || c.Type == "dependency_policy" {
+		if rec := loadStepChanges(execution, step.ID); rec != nil {
+			contractCfg.ChangedFiles = rec.ChangedPaths()
+			contractCfg.DiffBase = rec.BaseTree
+		}
+	}
 
 	var valErr error
 	switch c.Type {
@@ -498,8 +509,13 @@ func (e *DefaultPipelineExecutor) runSingleContract(
 		}
 	default:
 		valErr = contract.Validate(contractCfg, workspacePath)
+		if valErr != nil && c.Type == "json_schema" && c.Repair != nil && contract.IsMalformedJSON(valErr) {
+			valErr = e.repairJSONContract(ctx, execution, step, contractCfg, workspacePath, stepRunner, valErr)
+		}
 	}
 
+	e.recordContractResult(execution, step, c, contractDisplay, workspacePath, valErr, time.Since(contractStart))
+
 	if valErr != nil {
 		e.emit(event.Event{
 			Timestamp:  time.Now(),
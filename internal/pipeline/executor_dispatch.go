@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/recinq/wave/internal/adapter"
@@ -106,14 +107,14 @@ func (e *DefaultPipelineExecutor) runStepExecution(ctx context.Context, executio
 		StepID:        step.ID,
 		State:         "step_progress",
 		Persona:       res.resolvedPersona,
-		Progress:      25,
+		Progress:      progressExecuting,
 		CurrentAction: "Executing agent",
 	})
 
-	// Iron Rule: estimate prompt size and check against context window
-	promptBytes := len(cfg.Prompt)
-	if promptBytes > 0 && res.resolvedModel != "" {
-		ironStatus, ironMsg := cost.CheckIronRule(res.resolvedModel, promptBytes)
+	// Iron Rule: measure prompt size in the model's tokens and check against context window
+	res.promptTokens = cost.CountTokens(res.resolvedAdapterName, res.resolvedModel, cfg.Prompt)
+	if res.promptTokens > 0 && res.resolvedModel != "" {
+		ironStatus, ironMsg := cost.CheckIronRuleTokens(res.resolvedModel, res.promptTokens)
 		switch ironStatus {
 		case cost.IronRuleWarning:
 			e.emit(event.Event{
@@ -135,8 +136,42 @@ func (e *DefaultPipelineExecutor) runStepExecution(ctx context.Context, executio
 		"adapter": res.resolvedAdapterName,
 		"model":   res.resolvedModel,
 	})
-	result, adapterErr := res.stepRunner.Run(ctx, cfg)
+	rawLog := newAdapterLog(execution, step, res)
+	cfg.RawLog = rawLog
+	runCtx, pathViolation := e.enforcePathPolicy(ctx, step, res, &cfg)
+	runCtx, limitExceeded := e.enforceStepLimits(runCtx, step, res, &cfg, nativeMaxTurns(execution.Manifest, res.resolvedAdapterName))
+	runCtx, budgetExceeded := e.meterStepTokens(runCtx, execution, step, res, &cfg)
+	runCtx, tokenBudgetExceeded := e.enforceTokenBudgets(runCtx, execution, step, res, &cfg)
+	snapshot := snapshotWorkspace(res.workspacePath)
+	result, adapterErr := e.cachedStepRunner(execution, step, res.stepRunner).Run(runCtx, cfg)
+	if result != nil && result.ServedBy != "" {
+		// The persona's adapter failed over; account the step to the
+		// adapter and model that actually served it.
+		res.resolvedAdapterName = result.ServedBy
+		res.resolvedModel = e.failoverModel(execution, step, res.persona, result.ServedBy)
+		execution.recordStepAdapter(step.ID, StepAdapter{Persona: res.resolvedPersona, Adapter: res.resolvedAdapterName, Model: res.resolvedModel})
+	}
 	adapterDurationMs := time.Since(stepStart).Milliseconds()
+	res.changes = snapshot.changes()
+	e.recordStepChanges(execution, step, res.workspacePath, res.changes)
+	if err := budgetExceeded(); err != nil {
+		adapterErr = err
+	}
+	if err := pathViolation(); err != nil {
+		// The adapter was killed mid-run; whatever it returned is moot.
+		adapterErr = err
+	}
+	if err := limitExceeded(); err != nil {
+		adapterErr = err
+	}
+	finalTokens := 0
+	if result != nil {
+		finalTokens = result.TokensUsed
+	}
+	if err := tokenBudgetExceeded(finalTokens); err != nil {
+		adapterErr = err
+	}
+	rawLog.finish(result, adapterErr)
 
 	if adapterErr != nil {
 		e.trace("adapter_end", step.ID, adapterDurationMs, map[string]string{
@@ -149,15 +184,16 @@ func (e *DefaultPipelineExecutor) runStepExecution(ctx context.Context, executio
 		if e.metrics != nil {
 			completedAt := time.Now()
 			_ = e.metrics.RecordPerformanceMetric(&metrics.PerformanceMetricRecord{
-				RunID:        res.pipelineID,
-				StepID:       step.ID,
-				PipelineName: execution.Status.PipelineName,
-				Persona:      res.resolvedPersona,
-				StartedAt:    stepStart,
-				CompletedAt:  &completedAt,
-				DurationMs:   time.Since(stepStart).Milliseconds(),
-				Success:      false,
-				ErrorMessage: adapterErr.Error(),
+				RunID:         res.pipelineID,
+				StepID:        step.ID,
+				PipelineName:  execution.Status.PipelineName,
+				Persona:       res.resolvedPersona,
+				StartedAt:     stepStart,
+				CompletedAt:   &completedAt,
+				DurationMs:    time.Since(stepStart).Milliseconds(),
+				FilesModified: res.changes.FilesChanged(),
+				Success:       false,
+				ErrorMessage:  adapterErr.Error(),
 			})
 		}
 		return fmt.Errorf("adapter execution failed: %w", adapterErr)
@@ -172,36 +208,53 @@ func (e *DefaultPipelineExecutor) runStepExecution(ctx context.Context, executio
 			msg += ": " + result.FailureReason
 		}
 		e.emit(event.Event{
-			Timestamp:  time.Now(),
-			PipelineID: res.pipelineID,
-			StepID:     step.ID,
-			State:      "warning",
-			Message:    msg,
+			Timestamp:     time.Now(),
+			PipelineID:    res.pipelineID,
+			StepID:        step.ID,
+			State:         "warning",
+			Message:       msg,
+			LogPath:       rawLog.written(),
+			FailureReason: result.FailureReason,
 		})
 	}
 
-	// Fail immediately on rate limit — the result content is an error message,
-	// not useful work product. Proceeding would write the error as an artifact.
-	if result.FailureReason == adapter.FailureReasonRateLimit {
+	// Fail immediately when the provider rejected the run (rate limit, quota,
+	// credentials) — the result content is an error message, not useful work
+	// product. Proceeding would write the error as an artifact.
+	if adapter.IsTerminalFailure(result.FailureReason) {
+		stepErr := adapter.NewStepError(result.FailureReason, errors.New(result.ResultContent), result.TokensUsed, result.Subtype)
 		if e.logger != nil {
-			_ = e.logger.LogStepEnd(res.pipelineID, step.ID, stateFailed, time.Since(stepStart), result.ExitCode, 0, result.TokensUsed, "rate limited: "+result.ResultContent)
+			_ = e.logger.LogStepEnd(res.pipelineID, step.ID, stateFailed, time.Since(stepStart), result.ExitCode, 0, result.TokensUsed, stepErr.Error())
 		}
 		if e.metrics != nil {
 			completedAt := time.Now()
 			_ = e.metrics.RecordPerformanceMetric(&metrics.PerformanceMetricRecord{
-				RunID:        res.pipelineID,
-				StepID:       step.ID,
-				PipelineName: execution.Status.PipelineName,
-				Persona:      res.resolvedPersona,
-				StartedAt:    stepStart,
-				CompletedAt:  &completedAt,
-				DurationMs:   time.Since(stepStart).Milliseconds(),
-				TokensUsed:   result.TokensUsed,
-				Success:      false,
-				ErrorMessage: "rate limited: " + result.ResultContent,
+				RunID:         res.pipelineID,
+				StepID:        step.ID,
+				PipelineName:  execution.Status.PipelineName,
+				Persona:       res.resolvedPersona,
+				StartedAt:     stepStart,
+				CompletedAt:   &completedAt,
+				DurationMs:    time.Since(stepStart).Milliseconds(),
+				TokensUsed:    result.TokensUsed,
+				FilesModified: res.changes.FilesChanged(),
+				Success:       false,
+				ErrorMessage:  stepErr.Error(),
+
+				TokensEstimated: result.TokensEstimated,
 			})
 		}
-		return fmt.Errorf("adapter rate limited: %s", result.ResultContent)
+		return stepErr
+	}
+
+	if result.CacheHit {
+		e.emit(event.Event{
+			Timestamp:  time.Now(),
+			PipelineID: res.pipelineID,
+			StepID:     step.ID,
+			State:      "adapter_cache_hit",
+			Message:    "replayed cached adapter result; no tokens spent",
+		})
 	}
 
 	e.trace("adapter_end", step.ID, adapterDurationMs, map[string]string{
@@ -250,6 +303,9 @@ func (e *DefaultPipelineExecutor) resolveStepResources(ctx context.Context, exec
 
 	// Resolve adapter runner from registry for per-step dispatch
 	stepRunner := e.registry.ResolveWithFallback(resolvedAdapterName)
+	if resolvedAdapterName == persona.Adapter && len(persona.AdapterFallbacks) > 0 {
+		stepRunner = e.personaFailoverRunner(execution, step, persona, resolvedPersona, stepRunner)
+	}
 
 	// Create workspace under .agents/workspaces/<pipeline>/<step>/
 	workspacePath, err := e.createStepWorkspace(execution, step)
@@ -327,7 +383,7 @@ func (e *DefaultPipelineExecutor) resolveStepResources(ctx context.Context, exec
 		Model:           resolvedModel,
 		ConfiguredModel: configuredModel,
 		Adapter:         resolvedAdapterName,
-		Temperature:     persona.Temperature,
+		Temperature:     e.stepTemperature(persona),
 	})
 
 	// Record model routing decision
@@ -391,6 +447,8 @@ func (e *DefaultPipelineExecutor) resolveStepResources(ctx context.Context, exec
 		_ = e.logger.LogStepStartWithAdapter(pipelineID, step.ID, resolvedPersona, resolvedAdapterName, resolvedModel, artifactNames)
 	}
 
+	execution.recordStepAdapter(step.ID, StepAdapter{Persona: resolvedPersona, Adapter: resolvedAdapterName, Model: resolvedModel})
+
 	prompt := e.buildStepPrompt(execution, step)
 	if e.logger != nil {
 		_ = e.logger.LogToolCall(pipelineID, step.ID, "adapter.Run", fmt.Sprintf("persona=%s prompt_len=%d", resolvedPersona, len(prompt)))
@@ -413,7 +471,7 @@ func (e *DefaultPipelineExecutor) resolveStepResources(ctx context.Context, exec
 // buildStepAdapterConfig assembles the adapter.AdapterRunConfig for a step.
 // It resolves timeout, system prompt, sandbox settings, skills, and contract
 // prompt. It is Phase B of runStepExecution.
-func (e *DefaultPipelineExecutor) buildStepAdapterConfig(_ context.Context, execution *PipelineExecution, step *Step, res *stepRunResources) (adapter.AdapterRunConfig, error) {
+func (e *DefaultPipelineExecutor) buildStepAdapterConfig(ctx context.Context, execution *PipelineExecution, step *Step, res *stepRunResources) (adapter.AdapterRunConfig, error) {
 	pipelineID := res.pipelineID
 	prompt := res.prompt
 
@@ -444,7 +502,9 @@ func (e *DefaultPipelineExecutor) buildStepAdapterConfig(_ context.Context, exec
 	var sandboxDomains []string
 	var envPassthrough []string
 	if sandboxEnabled {
-		if res.persona.Sandbox != nil && len(res.persona.Sandbox.AllowedDomains) > 0 {
+		if step.Sandbox != nil {
+			sandboxDomains = step.Sandbox.AllowedDomains
+		} else if res.persona.Sandbox != nil && len(res.persona.Sandbox.AllowedDomains) > 0 {
 			sandboxDomains = res.persona.Sandbox.AllowedDomains
 		} else if len(execution.Manifest.Runtime.Sandbox.DefaultAllowedDomains) > 0 {
 			sandboxDomains = execution.Manifest.Runtime.Sandbox.DefaultAllowedDomains
@@ -540,6 +600,18 @@ func (e *DefaultPipelineExecutor) buildStepAdapterConfig(_ context.Context, exec
 	// because PermissionChecker enforces deny-first precedence.
 	effectivePerms := ResolveStepPermissions(step, res.persona, res.adapterDef)
 
+	// Merge runtime < persona < step env maps. Only redacted values reach the trace.
+	stepEnv := resolveStepEnv(execution, res.persona, step)
+	if len(stepEnv) > 0 {
+		e.trace(audit.TraceStepEnv, step.ID, 0, redactEnv(stepEnv))
+	}
+
+	adapterArgs, err := resolveAdapterOptions(execution.Manifest, res.resolvedAdapterName, res.persona, step)
+	if err != nil {
+		return adapter.AdapterRunConfig{}, err
+	}
+
+	plan := newPlanProgress(res.workspacePath)
 	cfg := adapter.AdapterRunConfig{
 		Adapter:             res.resolvedAdapterName,
 		Persona:             res.resolvedPersona,
@@ -547,8 +619,12 @@ func (e *DefaultPipelineExecutor) buildStepAdapterConfig(_ context.Context, exec
 		Prompt:              prompt,
 		SystemPrompt:        systemPrompt,
 		Timeout:             timeout,
-		Temperature:         res.persona.Temperature,
+		Env:                 append(runContextEnv(execution, step), stepEnv...),
+		Temperature:         e.stepTemperature(res.persona),
+		TemperatureSet:      e.stepTemperatureSet(res.persona),
+		WarmRetry:           retryPending(ctx),
 		Model:               res.resolvedModel,
+		ExtraArgs:           adapterArgs,
 		AllowedTools:        effectivePerms.AllowedTools,
 		DenyTools:           effectivePerms.Deny,
 		OutputFormat:        res.adapterDef.OutputFormat,
@@ -562,6 +638,18 @@ func (e *DefaultPipelineExecutor) buildStepAdapterConfig(_ context.Context, exec
 		ResolvedSkills:      resolvedSkillRefs,
 		MaxConcurrentAgents: step.MaxConcurrentAgents,
 		OnStreamEvent: func(evt adapter.StreamEvent) {
+			if progress, done, total, ok := plan.observe(evt); ok {
+				e.emit(event.Event{
+					Timestamp:     time.Now(),
+					PipelineID:    pipelineID,
+					StepID:        step.ID,
+					State:         "step_progress",
+					Persona:       res.resolvedPersona,
+					Progress:      progress,
+					CurrentAction: fmt.Sprintf("Plan %d/%d items done", done, total),
+				})
+			}
+
 			// Reset the activity timer on ANY stream event so a thinking-only
 			// loop (no tool_use yet) does not look identical to a wedged
 			// subprocess. Only progress events (tool_use on a writing tool)
@@ -616,7 +704,7 @@ func (e *DefaultPipelineExecutor) processAdapterResult(
 		StepID:        step.ID,
 		State:         "step_progress",
 		Persona:       res.resolvedPersona,
-		Progress:      75,
+		Progress:      progressProcessing,
 		CurrentAction: "Processing results",
 		TokensUsed:    result.TokensUsed,
 	})
@@ -656,9 +744,16 @@ func (e *DefaultPipelineExecutor) processAdapterResult(
 		e.mu.Unlock()
 	}
 
-	// Record cost and enforce budget
-	if e.costLedger != nil && (result.TokensIn > 0 || result.TokensOut > 0) {
-		_, budgetStatus := e.costLedger.Record(pipelineID, step.ID, res.resolvedModel, result.TokensIn, result.TokensOut, result.TokensUsed)
+	// Record cost and enforce budget. Adapters that report no input/output
+	// split are charged by tokenizer-measured prompt and result sizes.
+	tokensIn, tokensOut := result.TokensIn, result.TokensOut
+	if tokensIn == 0 && tokensOut == 0 && res.promptTokens > 0 && !result.CacheHit {
+		tokensIn = res.promptTokens
+		tokensOut = cost.CountTokens(res.resolvedAdapterName, res.resolvedModel, result.ResultContent)
+	}
+	if e.costLedger != nil && (tokensIn > 0 || tokensOut > 0) {
+		entry, budgetStatus := e.costLedger.Record(pipelineID, step.ID, res.resolvedModel, tokensIn, tokensOut, result.TokensUsed)
+		e.recordStepCost(execution, res, entry)
 		switch budgetStatus {
 		case cost.BudgetWarning:
 			e.emit(event.Event{
@@ -684,11 +779,31 @@ func (e *DefaultPipelineExecutor) processAdapterResult(
 
 	if hasStdoutArtifacts {
 		maxSize := execution.Manifest.Runtime.Artifacts.GetMaxStdoutSize()
+		var trunc StdoutTruncation
 		if int64(len(stdoutData)) > maxSize {
-			return fmt.Errorf("stdout artifact size (%d bytes) exceeds limit (%d bytes); consider reducing output or increasing runtime.artifacts.max_stdout_size",
-				len(stdoutData), maxSize)
+			if !stdoutOverflowTruncates(step, execution.Manifest) {
+				return fmt.Errorf("stdout artifact size (%d bytes) exceeds limit (%d bytes); consider reducing output, increasing runtime.artifacts.max_stdout_size, or setting on_overflow: truncate",
+					len(stdoutData), maxSize)
+			}
+			stdoutData, trunc = TruncateHeadTail(stdoutData, maxSize)
+			e.emit(event.Event{
+				Timestamp:  time.Now(),
+				PipelineID: pipelineID,
+				StepID:     step.ID,
+				State:      "artifact_truncated",
+				Message: fmt.Sprintf("stdout truncated from %d to %d bytes (kept %d head + %d tail, omitted %d)",
+					trunc.OriginalBytes, len(stdoutData), trunc.HeadBytes, trunc.TailBytes, trunc.OmittedBytes),
+			})
 		}
 		e.writeOutputArtifacts(execution, step, res.workspacePath, stdoutData)
+		for _, art := range step.OutputArtifacts {
+			if art.IsStdoutArtifact() && execution.Context != nil {
+				execution.Context.SetCustomVariable("artifacts."+art.Name+".truncated", strconv.FormatBool(trunc.Truncated))
+			}
+		}
+		if trunc.Truncated {
+			e.recordStdoutTruncation(execution, step, trunc)
+		}
 	}
 
 	// Write file-based output artifacts
@@ -701,9 +816,15 @@ func (e *DefaultPipelineExecutor) processAdapterResult(
 		// in ArtifactPaths and contract validation fails on missing files.
 		e.writeOutputArtifacts(execution, step, res.workspacePath, nil)
 	}
+	e.writeTranscriptArtifact(execution, step, res.workspacePath, result.Transcript)
 
-	// Check relay/compaction threshold (FR-009)
-	if cErr := e.checkRelayCompaction(ctx, execution, step, result.TokensUsed, res.workspacePath, string(stdoutData)); cErr != nil {
+	// Check relay/compaction threshold (FR-009). Without adapter-reported
+	// usage, the tokenizer-measured context size drives the threshold.
+	compactionTokens := result.TokensUsed
+	if compactionTokens == 0 {
+		compactionTokens = tokensIn + tokensOut
+	}
+	if cErr := e.checkRelayCompaction(ctx, execution, step, compactionTokens, res.workspacePath, string(stdoutData)); cErr != nil {
 		e.emit(event.Event{
 			Timestamp:  time.Now(),
 			PipelineID: pipelineID,
@@ -773,6 +894,8 @@ func (e *DefaultPipelineExecutor) processAdapterResult(
 		Artifacts:  stepArtifacts,
 		TokensIn:   result.TokensIn,
 		TokensOut:  result.TokensOut,
+
+		TokensEstimated: result.TokensEstimated,
 	})
 
 	if e.logger != nil {
@@ -791,8 +914,10 @@ func (e *DefaultPipelineExecutor) processAdapterResult(
 			CompletedAt:        &completedAt,
 			DurationMs:         stepDuration,
 			TokensUsed:         result.TokensUsed,
+			FilesModified:      res.changes.FilesChanged(),
 			ArtifactsGenerated: len(stepArtifacts),
 			Success:            true,
+			TokensEstimated:    result.TokensEstimated,
 		})
 	}
 
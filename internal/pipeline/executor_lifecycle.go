@@ -72,6 +72,9 @@ func (e *DefaultPipelineExecutor) validatePipelineAndCreateContext(p *Pipeline,
 		if len(sortedSteps) == 0 {
 			return nil, fmt.Errorf("step filter produced no runnable steps")
 		}
+		if err := e.stepFilter.ValidateArtifacts(sortedSteps, p); err != nil {
+			return nil, err
+		}
 	}
 
 	// Initialize ETA calculator from historical step performance data
@@ -89,7 +92,7 @@ func (e *DefaultPipelineExecutor) validatePipelineAndCreateContext(p *Pipeline,
 	pipelineName := p.Metadata.Name
 	pipelineID := e.runID
 	if pipelineID == "" {
-		pipelineID = GenerateRunID(pipelineName, m.Runtime.PipelineIDHashLength)
+		pipelineID = e.newRunID(pipelineName, m.Runtime)
 	}
 	pipelineContext := newContextWithProject(pipelineID, pipelineName, "", m)
 	pipelineContext.Input = input
@@ -97,6 +100,11 @@ func (e *DefaultPipelineExecutor) validatePipelineAndCreateContext(p *Pipeline,
 	// Inject forge variables for unified pipeline template resolution
 	forgeInfo := forge.DetectFromGitRemotesWithOverride(m.Metadata.Forge)
 	InjectForgeVariables(pipelineContext, forgeInfo)
+	injectRunTimeVariables(pipelineContext, e.runClock())
+
+	if err := injectPipelineVars(pipelineContext, p.Vars, e.varOverrides); err != nil {
+		return nil, err
+	}
 
 	// Resolve template placeholders in pipeline skills before validation.
 	// Skills like "{{ project.skill }}" must be resolved to their actual values
@@ -218,6 +226,16 @@ func (e *DefaultPipelineExecutor) checkPipelinePreflights(_ context.Context, set
 		}
 	}
 
+	// Worktree base preflight: fetch or reject missing base refs before any step runs
+	if werr := e.checkWorkspaceBases(setup.sortedSteps, setup.pipelineContext); werr != nil {
+		e.emit(event.Event{
+			Timestamp: time.Now(),
+			State:     "preflight",
+			Message:   werr.Error(),
+		})
+		return werr
+	}
+
 	// Token scope validation: check persona token requirements before execution
 	if setup.forgeInfo.Type != forge.ForgeUnknown {
 		resolver := scope.NewResolver(setup.forgeInfo.Type)
@@ -302,7 +320,7 @@ func (e *DefaultPipelineExecutor) initPipelineExecution(
 		WorktreePaths:     make(map[string]*WorktreeInfo),
 		AttemptContexts:   make(map[string]*AttemptContext),
 		ReworkTransitions: make(map[string]string),
-		ThreadManager:     NewThreadManager(threadCompactionAdapter),
+		ThreadManager:     e.threadsFor(threadCompactionAdapter),
 		CircuitBreaker:    NewCircuitBreaker(m.Runtime.CircuitBreaker.Limit, m.Runtime.CircuitBreaker.TrackedClasses),
 		Input:             input,
 		Context:           setup.pipelineContext,
@@ -319,6 +337,11 @@ func (e *DefaultPipelineExecutor) initPipelineExecution(
 	for _, step := range p.Steps {
 		execution.States[step.ID] = statePending
 	}
+	if e.stepFilter != nil {
+		for key, path := range e.stepFilter.Artifacts {
+			execution.ArtifactPaths[key] = path
+		}
+	}
 
 	e.mu.Lock()
 	e.pipelines[pipelineID] = execution
@@ -355,6 +378,8 @@ func (e *DefaultPipelineExecutor) setupPipelineRun(ctx context.Context, executio
 	if e.store != nil {
 		_ = e.store.SavePipelineState(pipelineID, stateRunning, input)
 	}
+	e.recordProvenance(execution)
+	e.recordEnvironment(execution)
 
 	execution.Status.State = stateRunning
 
@@ -375,7 +400,8 @@ func (e *DefaultPipelineExecutor) setupPipelineRun(ctx context.Context, executio
 		wsRoot = ".agents/workspaces"
 	}
 	pipelineWsPath := filepath.Join(wsRoot, pipelineID)
-	// Clean previous run artifacts to ensure fresh state (unless --preserve-workspace is set)
+	// Clean previous run artifacts to ensure fresh state (unless --preserve-workspace
+	// is set or runtime.workspace_cleanup.on_start is "reuse")
 	if e.preserveWorkspace {
 		e.emit(event.Event{
 			Timestamp:  time.Now(),
@@ -383,7 +409,7 @@ func (e *DefaultPipelineExecutor) setupPipelineRun(ctx context.Context, executio
 			State:      "warning",
 			Message:    "--preserve-workspace active: stale workspace state may cause non-reproducible results",
 		})
-	} else {
+	} else if !m.Runtime.WorkspaceCleanup.ReuseOnStart() {
 		if err := os.RemoveAll(pipelineWsPath); err != nil {
 			e.emit(event.Event{
 				Timestamp:  time.Now(),
@@ -426,19 +452,24 @@ func (e *DefaultPipelineExecutor) setupPipelineRun(ctx context.Context, executio
 			records := make([]hooks.WebhookRecord, len(webhooks))
 			for i, wh := range webhooks {
 				records[i] = hooks.WebhookRecord{
-					ID:      wh.ID,
-					Name:    wh.Name,
-					URL:     wh.URL,
-					Events:  wh.Events,
-					Matcher: wh.Matcher,
-					Headers: wh.Headers,
-					Secret:  wh.Secret,
-					Active:  wh.Active,
+					ID:       wh.ID,
+					Name:     wh.Name,
+					URL:      wh.URL,
+					Events:   wh.Events,
+					Matcher:  wh.Matcher,
+					Headers:  wh.Headers,
+					Secret:   wh.Secret,
+					Format:   wh.Format,
+					Template: wh.Template,
+					Active:   wh.Active,
 				}
 			}
 			e.webhookRunner = hooks.NewWebhookRunner(records, &webhookStoreAdapter{store: e.store})
 		}
 	}
+	if e.webhookRunner != nil && len(m.Runtime.Notifications.Routes) > 0 {
+		e.webhookRunner.SetRouter(e.notificationRouter(p, m, pipelineID))
+	}
 
 	// Run run_start hooks
 	startEvt := hooks.HookEvent{
@@ -465,6 +496,16 @@ func (e *DefaultPipelineExecutor) runSchedulingLoop(ctx context.Context, executi
 	completed := make(map[string]bool, len(sortedSteps))
 	completedCount := 0
 
+	// Steps the step filter skipped do not run; their dependents need only
+	// the artifacts the filter provides for them.
+	if e.stepFilter.IsActive() {
+		for _, step := range execution.Pipeline.Steps {
+			if !e.stepFilter.ShouldRun(step.ID) {
+				completed[step.ID] = true
+			}
+		}
+	}
+
 	// Count schedulable steps (excludes rework-only steps which run only via rework trigger)
 	schedulableSteps := 0
 	for _, step := range sortedSteps {
@@ -566,11 +607,13 @@ func (e *DefaultPipelineExecutor) runSchedulingLoop(ctx context.Context, executi
 				_ = e.store.SavePipelineState(pipelineID, stateFailed, execution.Input)
 			}
 			e.emit(event.Event{
-				Timestamp:  time.Now(),
-				PipelineID: pipelineID,
-				StepID:     failedStepID,
-				State:      stateFailed,
-				Message:    err.Error(),
+				Timestamp:     time.Now(),
+				PipelineID:    pipelineID,
+				StepID:        failedStepID,
+				State:         stateFailed,
+				Message:       err.Error(),
+				LogPath:       adapterLogRef(execution, failedStepID),
+				FailureReason: AdapterFailureReason(err),
 			})
 			// Generate retrospective for failed runs — these are the most valuable
 			if e.retroGenerator != nil {
@@ -589,8 +632,12 @@ func (e *DefaultPipelineExecutor) runSchedulingLoop(ctx context.Context, executi
 
 			execution.mu.Lock()
 			stepState := execution.States[step.ID]
+			sampledOut := execution.SkippedByDesign[step.ID]
 			execution.mu.Unlock()
 
+			if sampledOut {
+				continue
+			}
 			if stepState == stateFailed || stepState == stateSkipped {
 				execution.Status.FailedSteps = append(execution.Status.FailedSteps, step.ID)
 			} else {
@@ -630,7 +677,7 @@ func (e *DefaultPipelineExecutor) runSchedulingLoop(ctx context.Context, executi
 
 // finalizePipelineExecution records completion status, fires terminal hooks, generates
 // a retrospective, and cleans up in-memory state. It is the final phase of Execute.
-func (e *DefaultPipelineExecutor) finalizePipelineExecution(_ context.Context, execution *PipelineExecution, schedulableSteps int) {
+func (e *DefaultPipelineExecutor) finalizePipelineExecution(ctx context.Context, execution *PipelineExecution, schedulableSteps int) {
 	pipelineID := execution.Status.ID
 	input := execution.Input
 
@@ -676,6 +723,9 @@ func (e *DefaultPipelineExecutor) finalizePipelineExecution(_ context.Context, e
 		if e.store != nil {
 			_ = e.store.SavePipelineState(pipelineID, pipelineState, input)
 		}
+		// Publish before the terminal hooks and attestation so both see
+		// the published URLs among the run's outcomes.
+		e.publishDeliverables(ctx, execution)
 		// EvalSignal hook (issue #1606): persist run-level signals before
 		// terminal hooks fire and before in-memory state is dropped.
 		e.recordPipelineEval(execution)
@@ -688,12 +738,16 @@ func (e *DefaultPipelineExecutor) finalizePipelineExecution(_ context.Context, e
 	}
 
 	elapsed := time.Since(execution.Status.StartedAt).Milliseconds()
+	msg := fmt.Sprintf("%d steps completed", schedulableSteps)
+	if err := execution.partialFailure(); err != nil {
+		msg = err.Error()
+	}
 	e.emit(event.Event{
 		Timestamp:  time.Now(),
 		PipelineID: pipelineID,
 		State:      execution.Status.State,
 		DurationMs: elapsed,
-		Message:    fmt.Sprintf("%d steps completed", schedulableSteps),
+		Message:    msg,
 	})
 
 	// Generate retrospective (non-blocking)
@@ -701,6 +755,9 @@ func (e *DefaultPipelineExecutor) finalizePipelineExecution(_ context.Context, e
 		e.retroGenerator.Generate(pipelineID, execution.Pipeline.Metadata.Name)
 	}
 
+	e.writeAttestation(execution, execution.Status.State != stateFailed)
+	e.applyWorkspaceCleanup(execution, execution.Status.State != stateFailed)
+
 	// Clean up completed pipeline from in-memory storage to prevent memory leak
 	e.cleanupCompletedPipeline(pipelineID)
 }
@@ -717,7 +774,7 @@ func (e *DefaultPipelineExecutor) executeGraphPipeline(ctx context.Context, p *P
 	pipelineName := p.Metadata.Name
 	pipelineID := e.runID
 	if pipelineID == "" {
-		pipelineID = GenerateRunID(pipelineName, m.Runtime.PipelineIDHashLength)
+		pipelineID = e.newRunID(pipelineName, m.Runtime)
 	}
 	pipelineContext := newContextWithProject(pipelineID, pipelineName, "", m)
 	pipelineContext.Input = input
@@ -725,6 +782,11 @@ func (e *DefaultPipelineExecutor) executeGraphPipeline(ctx context.Context, p *P
 	// Inject forge variables
 	forgeInfo := forge.DetectFromGitRemotesWithOverride(m.Metadata.Forge)
 	InjectForgeVariables(pipelineContext, forgeInfo)
+	injectRunTimeVariables(pipelineContext, e.runClock())
+
+	if err := injectPipelineVars(pipelineContext, p.Vars, e.varOverrides); err != nil {
+		return err
+	}
 
 	// Forge preflight: block forge-dependent steps when no forge is configured
 	if forgeInfo.Type == forge.ForgeLocal {
@@ -778,7 +840,7 @@ func (e *DefaultPipelineExecutor) executeGraphPipeline(ctx context.Context, p *P
 		WorktreePaths:     make(map[string]*WorktreeInfo),
 		AttemptContexts:   make(map[string]*AttemptContext),
 		ReworkTransitions: make(map[string]string),
-		ThreadManager:     NewThreadManager(threadCompactionAdapter),
+		ThreadManager:     e.threadsFor(threadCompactionAdapter),
 		CircuitBreaker:    NewCircuitBreaker(m.Runtime.CircuitBreaker.Limit, m.Runtime.CircuitBreaker.TrackedClasses),
 		Input:             input,
 		Context:           pipelineContext,
@@ -821,6 +883,8 @@ func (e *DefaultPipelineExecutor) executeGraphPipeline(ctx context.Context, p *P
 	if e.store != nil {
 		_ = e.store.SavePipelineState(pipelineID, stateRunning, input)
 	}
+	e.recordProvenance(execution)
+	e.recordEnvironment(execution)
 
 	execution.Status.State = stateRunning
 
@@ -854,13 +918,19 @@ func (e *DefaultPipelineExecutor) executeGraphPipeline(ctx context.Context, p *P
 		wsRoot = ".agents/workspaces"
 	}
 	pipelineWsPath := filepath.Join(wsRoot, pipelineID)
-	if !e.preserveWorkspace {
+	if !e.preserveWorkspace && !m.Runtime.WorkspaceCleanup.ReuseOnStart() {
 		_ = os.RemoveAll(pipelineWsPath)
 	}
 	if err := os.MkdirAll(pipelineWsPath, 0755); err != nil {
 		return fmt.Errorf("failed to create workspace: %w", err)
 	}
 
+	releaseLocks, err := e.acquireLocks(ctx, execution, nil)
+	if err != nil {
+		return err
+	}
+	defer releaseLocks()
+
 	// Create and run graph walker
 	gw := NewGraphWalker(p)
 
@@ -880,6 +950,11 @@ func (e *DefaultPipelineExecutor) executeGraphPipeline(ctx context.Context, p *P
 	stepExecutor := func(ctx context.Context, step *Step) (*StepResult, error) {
 		// Handle command steps
 		if step.Type == StepTypeCommand || step.Script != "" {
+			releaseStepLocks, err := e.acquireLocks(ctx, execution, step)
+			if err != nil {
+				return nil, err
+			}
+			defer releaseStepLocks()
 			result, err := e.executeCommandStep(ctx, execution, step)
 			if err != nil {
 				return result, err
@@ -920,7 +995,7 @@ func (e *DefaultPipelineExecutor) executeGraphPipeline(ctx context.Context, p *P
 		return result, nil
 	}
 
-	err := gw.Walk(ctx, stepExecutor, initialVisitCounts)
+	err = gw.Walk(ctx, stepExecutor, initialVisitCounts)
 
 	// Persist final visit counts
 	if e.store != nil {
@@ -955,6 +1030,8 @@ func (e *DefaultPipelineExecutor) executeGraphPipeline(ctx context.Context, p *P
 		if e.retroGenerator != nil {
 			e.retroGenerator.Generate(pipelineID, execution.Pipeline.Metadata.Name)
 		}
+		e.writeAttestation(execution, false)
+		e.applyWorkspaceCleanup(execution, false)
 		e.cleanupCompletedPipeline(pipelineID)
 		return err
 	}
@@ -963,6 +1040,7 @@ func (e *DefaultPipelineExecutor) executeGraphPipeline(ctx context.Context, p *P
 	if e.store != nil {
 		_ = e.store.SavePipelineState(pipelineID, stateCompleted, input)
 	}
+	e.publishDeliverables(ctx, execution)
 
 	elapsed := time.Since(execution.Status.StartedAt).Milliseconds()
 	e.emit(event.Event{
@@ -978,6 +1056,8 @@ func (e *DefaultPipelineExecutor) executeGraphPipeline(ctx context.Context, p *P
 		e.retroGenerator.Generate(pipelineID, execution.Pipeline.Metadata.Name)
 	}
 
+	e.writeAttestation(execution, true)
+	e.applyWorkspaceCleanup(execution, true)
 	e.cleanupCompletedPipeline(pipelineID)
 	return nil
 }
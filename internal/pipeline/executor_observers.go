@@ -8,6 +8,7 @@ import (
 
 	"github.com/recinq/wave/internal/event"
 	"github.com/recinq/wave/internal/hooks"
+	"github.com/recinq/wave/internal/manifest"
 	"github.com/recinq/wave/internal/state"
 )
 
@@ -115,6 +116,21 @@ func (e *DefaultPipelineExecutor) fireWebhooks(ctx context.Context, evt hooks.Ho
 	}
 }
 
+// notificationRouter routes the run's webhook deliveries under
+// runtime.notifications. Routes match the pipeline's metadata tags and the
+// run's tags, and dedup across runs through the state store.
+func (e *DefaultPipelineExecutor) notificationRouter(p *Pipeline, m *manifest.Manifest, runID string) *hooks.Router {
+	tags := append([]string(nil), p.Metadata.Tags...)
+	var dedup hooks.NotificationDedupStore
+	if e.store != nil {
+		if runTags, err := e.store.GetRunTags(runID); err == nil {
+			tags = append(tags, runTags...)
+		}
+		dedup = e.store
+	}
+	return hooks.NewRouter(m.Runtime.Notifications, hooks.RouteSubject{Pipeline: p.Metadata.Name, Tags: tags}, dedup)
+}
+
 // webhookStoreAdapter bridges the hooks.WebhookStore interface to the state store,
 // avoiding a direct state→hooks import cycle.
 type webhookStoreAdapter struct {
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -13,6 +12,7 @@ import (
 	"github.com/recinq/wave/internal/adapter"
 	"github.com/recinq/wave/internal/event"
 	"github.com/recinq/wave/internal/hooks"
+	"github.com/recinq/wave/internal/procutil"
 	"github.com/recinq/wave/internal/security"
 	"github.com/recinq/wave/internal/state"
 	"golang.org/x/sync/errgroup"
@@ -112,7 +112,7 @@ func (e *DefaultPipelineExecutor) executeCommandStep(ctx context.Context, execut
 
 	// Execute the script
 	startTime := time.Now()
-	cmd := exec.CommandContext(ctx, "sh", "-c", script)
+	cmd := procutil.ShellCommand(ctx, script)
 	cmd.Dir = cmdDir
 
 	// SECURITY: Filter environment to only EnvPassthrough variables.
@@ -120,6 +120,11 @@ func (e *DefaultPipelineExecutor) executeCommandStep(ctx context.Context, execut
 	// variables into the command subprocess.
 	cmd.Env = filterEnvPassthrough(execution.Manifest.Runtime.Sandbox.EnvPassthrough)
 
+	// Run context first, so declared runtime and step env maps (no persona)
+	// can override it.
+	cmd.Env = append(cmd.Env, runContextEnv(execution, step)...)
+	cmd.Env = append(cmd.Env, resolveStepEnv(execution, nil, step)...)
+
 	// Append WAVE_DEP_<DEP>_<NAME>=<canonical path> + WAVE_DEPS_DIR for
 	// every auto-injected upstream artifact. Issue #1452 phase 3.
 	cmd.Env = append(cmd.Env, BuildDepEnvVars(depArtifacts, workspacePath)...)
@@ -228,20 +233,20 @@ func filterEnvPassthrough(passthrough []string) []string {
 		"XDG_DATA_HOME", "XDG_CONFIG_HOME", "XDG_CACHE_HOME"}
 	allowed := make(map[string]bool, len(passthrough)+len(essentials))
 	for _, name := range essentials {
-		allowed[name] = true
+		allowed[procutil.EnvKey(name)] = true
 	}
 	for _, name := range passthrough {
-		allowed[name] = true
+		allowed[procutil.EnvKey(name)] = true
 	}
 
 	var filtered []string
 	for _, entry := range os.Environ() {
 		name, _, ok := strings.Cut(entry, "=")
-		if ok && allowed[name] {
+		if ok && allowed[procutil.EnvKey(name)] {
 			filtered = append(filtered, entry)
 		}
 	}
-	return filtered
+	return append(filtered, procutil.PlatformEnv()...)
 }
 
 // findReadySteps returns all steps whose dependencies are satisfied (all deps in completed set).
@@ -271,6 +276,7 @@ func (e *DefaultPipelineExecutor) findReadySteps(steps []*Step, completed map[st
 
 // skipDependentSteps finds steps whose dependencies include a failed or skipped step
 // and marks them as skipped. Propagates transitively until no more steps are affected.
+// Steps skipped by canary sampling or a when: condition do not propagate.
 func (e *DefaultPipelineExecutor) skipDependentSteps(execution *PipelineExecution, allSteps []*Step, completed map[string]bool, completedCount *int) {
 	pipelineID := execution.Status.ID
 	changed := true
@@ -290,8 +296,9 @@ func (e *DefaultPipelineExecutor) skipDependentSteps(execution *PipelineExecutio
 				}
 				execution.mu.Lock()
 				depState := execution.States[dep]
+				sampledOut := execution.SkippedByDesign[dep]
 				execution.mu.Unlock()
-				if depState == stateFailed || depState == stateSkipped {
+				if (depState == stateFailed || (depState == stateSkipped && !sampledOut)) && failedDepBlocks(execution, dep) {
 					hasFailedDep = true
 				}
 			}
@@ -342,24 +349,73 @@ func (e *DefaultPipelineExecutor) hasRequiredFailures(execution *PipelineExecuti
 
 // executeStepBatch runs a batch of ready steps. If the batch has a single step,
 // it runs directly to avoid goroutine overhead. Otherwise, it launches concurrent
-// goroutines via errgroup and returns the first error (cancelling remaining steps).
+// goroutines via errgroup, at most maxParallelSteps at a time, and returns the
+// first error (cancelling remaining steps).
 func (e *DefaultPipelineExecutor) executeStepBatch(ctx context.Context, execution *PipelineExecution, steps []*Step) error {
 	if len(steps) == 1 {
-		return e.executeStep(ctx, execution, steps[0])
+		return e.executeStepWithPolicy(ctx, execution, steps[0])
 	}
 
 	g, gctx := errgroup.WithContext(ctx)
+	if limit := e.parallelStepLimit(execution); limit > 0 {
+		g.SetLimit(limit)
+	}
 	for _, step := range steps {
 		step := step
 		g.Go(func() error {
-			return e.executeStep(gctx, execution, step)
+			return e.executeStepWithPolicy(gctx, execution, step)
 		})
 	}
 	return g.Wait()
 }
 
+// parallelStepLimit returns how many steps of a batch may run at once: the
+// --max-parallel override, else runtime.max_parallel_steps. 0 = unlimited.
+func (e *DefaultPipelineExecutor) parallelStepLimit(execution *PipelineExecution) int {
+	if e.maxParallelSteps > 0 {
+		return e.maxParallelSteps
+	}
+	if execution.Manifest != nil {
+		return execution.Manifest.Runtime.MaxParallelSteps
+	}
+	return 0
+}
+
+// retryPendingKey is the context key marking an attempt that is not the
+// step's last.
+type retryPendingKey struct{}
+
+// withRetryPending marks ctx as running an attempt whose failure is retried.
+func withRetryPending(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryPendingKey{}, true)
+}
+
+// retryPending reports whether a failure of the attempt running in ctx is
+// retried in the same workspace.
+func retryPending(ctx context.Context) bool {
+	v, _ := ctx.Value(retryPendingKey{}).(bool)
+	return v
+}
+
 func (e *DefaultPipelineExecutor) executeStep(ctx context.Context, execution *PipelineExecution, step *Step) error {
 	pipelineID := execution.Status.ID
+	if !canarySampled(pipelineID, step) {
+		e.skipCanaryStep(execution, step)
+		return nil
+	}
+	if holds, err := e.whenHolds(execution, step); err != nil {
+		return fmt.Errorf("step %q: %w", step.ID, err)
+	} else if !holds {
+		e.skipStepByDesign(execution, step, "when: condition is false: "+step.When)
+		return nil
+	}
+
+	releaseLocks, err := e.acquireLocks(ctx, execution, step)
+	if err != nil {
+		return err
+	}
+	defer releaseLocks()
+
 	execution.mu.Lock()
 	execution.States[step.ID] = stateRunning
 	execution.Status.CurrentStep = step.ID
@@ -374,7 +430,11 @@ func (e *DefaultPipelineExecutor) executeStep(ctx context.Context, execution *Pi
 	// step is a regular persona / command step the registry returns nil and
 	// we fall through to the standard adapter pipeline.
 	if strategy := selectStrategy(e, step); strategy != nil {
-		return strategy.Execute(ctx, execution, step)
+		if err := strategy.Execute(ctx, execution, step); err != nil {
+			return err
+		}
+		e.recordCheckpoint(execution, step)
+		return nil
 	}
 
 	// Command step: execute shell script directly (no adapter/persona needed).
@@ -401,6 +461,7 @@ func (e *DefaultPipelineExecutor) executeStep(ctx context.Context, execution *Pi
 		if cErr := e.validateStepContracts(ctx, execution, step, contractDir, nil, pipelineID, "", time.Now(), adapterResult); cErr != nil {
 			return cErr
 		}
+		e.recordCheckpoint(execution, step)
 		return nil
 	}
 
@@ -424,7 +485,7 @@ func (e *DefaultPipelineExecutor) executeStep(ctx context.Context, execution *Pi
 	}
 	e.fireWebhooks(ctx, stepStartEvt)
 
-	maxAttempts := step.Retry.EffectiveMaxAttempts()
+	maxAttempts := e.applyFlakyStepPolicy(execution, step, step.Retry.EffectiveMaxAttempts())
 	stepStartTime := time.Now()
 
 	var lastErr error
@@ -505,6 +566,12 @@ func (e *DefaultPipelineExecutor) executeStep(ctx context.Context, execution *Pi
 		execution.Watchdog = watchdog
 		execution.mu.Unlock()
 
+		// Tell the adapter a failure will be retried, so it may keep a
+		// warm process ready for the next attempt.
+		if attempt < maxAttempts {
+			stepCtx = withRetryPending(stepCtx)
+		}
+
 		err := e.runStepExecution(stepCtx, execution, step)
 
 		// Stop stall watchdog and clear reference
@@ -543,6 +610,7 @@ func (e *DefaultPipelineExecutor) executeStep(ctx context.Context, execution *Pi
 			// Classify the failure for intelligent retry decisions.
 			// Use stepCtx (watchdog-derived) so stall cancellation is detected.
 			failureClass := ClassifyStepFailure(err, nil, stepCtx.Err())
+			failureCategory := CategorizeStepFailure(err, stepCtx.Err())
 
 			// Record failed attempt with pipeline-level failure class
 			if e.store != nil {
@@ -565,27 +633,30 @@ func (e *DefaultPipelineExecutor) executeStep(ctx context.Context, execution *Pi
 				fp := NormalizeFingerprint(step.ID, failureClass, err.Error())
 				if execution.CircuitBreaker.Record(fp, failureClass) {
 					e.emit(event.Event{
-						Timestamp:    time.Now(),
-						PipelineID:   pipelineID,
-						StepID:       step.ID,
-						State:        event.StateFailed,
-						FailureClass: failureClass,
-						Message:      fmt.Sprintf("circuit breaker tripped: same failure repeated %d times", execution.CircuitBreaker.Limit()),
+						Timestamp:     time.Now(),
+						PipelineID:    pipelineID,
+						StepID:        step.ID,
+						State:         event.StateFailed,
+						FailureClass:  failureClass,
+						FailureReason: AdapterFailureReason(err),
+						Message:       fmt.Sprintf("circuit breaker tripped: same failure repeated %d times", execution.CircuitBreaker.Limit()),
 					})
 					// Fall through to on_failure handling below by exhausting attempts
 					attempt = maxAttempts
 				}
 			}
 
-			// Skip remaining retries for non-retryable failure classes
-			if !IsRetryable(failureClass) && attempt < maxAttempts {
+			// Skip remaining retries for failures the step's retry policy
+			// excludes (retry_on / no_retry_on, else class retryability).
+			if !step.Retry.ShouldRetry(failureClass, failureCategory) && attempt < maxAttempts {
 				e.emit(event.Event{
-					Timestamp:    time.Now(),
-					PipelineID:   pipelineID,
-					StepID:       step.ID,
-					State:        event.StateFailed,
-					FailureClass: failureClass,
-					Message:      fmt.Sprintf("non-retryable failure class %q, skipping remaining retries", failureClass),
+					Timestamp:     time.Now(),
+					PipelineID:    pipelineID,
+					StepID:        step.ID,
+					State:         event.StateFailed,
+					FailureClass:  failureClass,
+					FailureReason: AdapterFailureReason(err),
+					Message:       fmt.Sprintf("non-retryable failure class %q (category %q), skipping remaining retries", failureClass, failureCategory),
 				})
 				attempt = maxAttempts
 			}
@@ -596,10 +667,11 @@ func (e *DefaultPipelineExecutor) executeStep(ctx context.Context, execution *Pi
 					fmt.Sprintf("retrying step %s (attempt %d/%d)", step.ID, attempt+1, maxAttempts),
 					fmt.Sprintf("failure class %q is retryable, attempts remaining", failureClass),
 					map[string]interface{}{
-						"attempt":       attempt,
-						"max_attempts":  maxAttempts,
-						"failure_class": failureClass,
-						"error":         err.Error(),
+						"attempt":          attempt,
+						"max_attempts":     maxAttempts,
+						"failure_class":    failureClass,
+						"failure_category": failureCategory,
+						"error":            err.Error(),
 					},
 				)
 				// Always inject failure context into the next retry attempt.
@@ -634,21 +706,42 @@ func (e *DefaultPipelineExecutor) executeStep(ctx context.Context, execution *Pi
 						contractErrors = append(contractErrors, inner.Error())
 					}
 
+					// Consult the error knowledge base for remediation
+					// notes recorded against this failure signature.
+					remediations := e.lookupKnownRemediations(execution, step, failureCategory, errMsg)
+					if len(remediations) > 0 {
+						e.emit(event.Event{
+							Timestamp:  time.Now(),
+							PipelineID: pipelineID,
+							StepID:     step.ID,
+							State:      stateRetrying,
+							Message:    fmt.Sprintf("injecting %d known remediation(s) from knowledge base", len(remediations)),
+						})
+					}
+
 					execution.mu.Lock()
 					execution.AttemptContexts[step.ID] = &AttemptContext{
-						Attempt:        attempt + 1,
-						MaxAttempts:    maxAttempts,
-						PriorError:     errMsg,
-						FailureClass:   failureClass,
-						PriorStdout:    stdoutTail,
-						ContractErrors: contractErrors,
+						Attempt:           attempt + 1,
+						MaxAttempts:       maxAttempts,
+						PriorError:        errMsg,
+						FailureClass:      failureClass,
+						PriorStdout:       stdoutTail,
+						ContractErrors:    contractErrors,
+						KnownRemediations: remediations,
 					}
 					execution.mu.Unlock()
 				}
 				continue
 			}
 
-			// All attempts exhausted — apply on_failure policy
+			// All attempts exhausted — record the triage category on the
+			// step state so `wave triage` can aggregate root causes.
+			if e.store != nil {
+				_ = e.store.SaveStepFailureCategory(pipelineID, step.ID, failureCategory)
+			}
+			e.reportRepeatedFailure(ctx, execution, step, failureCategory)
+
+			// Apply on_failure policy
 			e.recordDecision(pipelineID, step.ID, "retry",
 				fmt.Sprintf("all %d attempts exhausted for step %s", maxAttempts, step.ID),
 				fmt.Sprintf("applying on_failure policy after %d failed attempts", maxAttempts),
@@ -698,11 +791,13 @@ func (e *DefaultPipelineExecutor) executeStep(ctx context.Context, execution *Pi
 				// EvalSignal hook (issue #1606): step terminally failed.
 				e.recordStepEval(execution, step, stateFailed, err, time.Since(stepStartTime))
 				e.emit(event.Event{
-					Timestamp:  time.Now(),
-					PipelineID: pipelineID,
-					StepID:     step.ID,
-					State:      event.StateFailed,
-					Message:    fmt.Sprintf("step failed after %d attempts but pipeline continues: %s", maxAttempts, err.Error()),
+					Timestamp:     time.Now(),
+					PipelineID:    pipelineID,
+					StepID:        step.ID,
+					State:         event.StateFailed,
+					Message:       fmt.Sprintf("step failed after %d attempts but pipeline continues: %s", maxAttempts, err.Error()),
+					LogPath:       adapterLogRef(execution, step.ID),
+					FailureReason: AdapterFailureReason(err),
 				})
 				return nil
 
@@ -763,18 +858,8 @@ func (e *DefaultPipelineExecutor) executeStep(ctx context.Context, execution *Pi
 		// EvalSignal hook (issue #1606): step terminally succeeded.
 		e.recordStepEval(execution, step, stateCompleted, nil, time.Since(stepStartTime))
 
-		// Record checkpoint for fork/rewind support
-		if e.store != nil {
-			stepIndex := -1
-			for i, s := range execution.Pipeline.Steps {
-				if s.ID == step.ID {
-					stepIndex = i
-					break
-				}
-			}
-			recorder := &CheckpointRecorder{store: e.store}
-			recorder.Record(execution, step, stepIndex)
-		}
+		// Record checkpoint for fork/rewind and resume support
+		e.recordCheckpoint(execution, step)
 
 		// Record step completion for ETA calculation
 		if e.etaCalculator != nil {
@@ -794,6 +879,9 @@ func (e *DefaultPipelineExecutor) executeStep(ctx context.Context, execution *Pi
 		// Extract declared outcomes from step artifacts
 		e.processStepOutcomes(execution, step)
 
+		// Expose the generated commit message to the steps that follow
+		e.exposeChangelog(execution, step)
+
 
 		return nil
 	}
@@ -907,6 +995,7 @@ func (e *DefaultPipelineExecutor) executeReworkStep(ctx context.Context, executi
 			StepID:     reworkStepID,
 			State:      event.StateFailed,
 			Message:    fmt.Sprintf("rework step %q also failed: %s", reworkStepID, reworkErr.Error()),
+			LogPath:    adapterLogRef(execution, reworkStepID),
 		})
 		return reworkErr
 	}
@@ -923,6 +1012,9 @@ func (e *DefaultPipelineExecutor) executeReworkStep(ctx context.Context, executi
 		if artPath, ok := execution.ArtifactPaths[reworkKey]; ok {
 			originalKey := fmt.Sprintf("%s:%s", failedStep.ID, art.Name)
 			execution.ArtifactPaths[originalKey] = artPath
+			if sum, ok := execution.ArtifactChecksums[reworkKey]; ok {
+				execution.ArtifactChecksums[originalKey] = sum
+			}
 		}
 	}
 	execution.States[reworkStep.ID] = stateCompleted
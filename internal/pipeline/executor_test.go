@@ -208,6 +208,63 @@ func TestParallelStepExecution(t *testing.T) {
 	assert.True(t, posA < posD, "A must come before D")
 }
 
+// TestParallelStepExecution_MaxParallelSteps tests that a ready batch never
+// runs more steps at once than runtime.max_parallel_steps or --max-parallel.
+func TestParallelStepExecution_MaxParallelSteps(t *testing.T) {
+	tests := []struct {
+		name         string
+		manifestMax  int
+		overrideMax  int
+		wantMaxLimit int32
+	}{
+		{name: "manifest limit", manifestMax: 2, wantMaxLimit: 2},
+		{name: "override wins", manifestMax: 3, overrideMax: 1, wantMaxLimit: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var maxConcurrent, currentConcurrent int32
+			concurrentAdapter := &concurrencyTrackingAdapter{
+				MockAdapter: adaptertest.NewMockAdapter(
+					adaptertest.WithStdoutJSON(`{"status": "success"}`),
+					adaptertest.WithSimulatedDelay(100*time.Millisecond),
+				),
+				onStart: func() {
+					current := atomic.AddInt32(&currentConcurrent, 1)
+					for {
+						old := atomic.LoadInt32(&maxConcurrent)
+						if current <= old || atomic.CompareAndSwapInt32(&maxConcurrent, old, current) {
+							break
+						}
+					}
+				},
+				onEnd: func() {
+					atomic.AddInt32(&currentConcurrent, -1)
+				},
+			}
+
+			executor := NewDefaultPipelineExecutor(concurrentAdapter,
+				WithEmitter(testutil.NewEventCollector()),
+				WithMaxParallelSteps(tt.overrideMax),
+			)
+
+			m := testutil.CreateTestManifest(t.TempDir())
+			m.Runtime.MaxParallelSteps = tt.manifestMax
+
+			p := &Pipeline{Metadata: PipelineMetadata{Name: "max-parallel-test"}}
+			for _, id := range []string{"step-a", "step-b", "step-c", "step-d", "step-e"} {
+				p.Steps = append(p.Steps, Step{ID: id, Persona: "navigator", Exec: ExecConfig{Source: id}})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			require.NoError(t, executor.Execute(ctx, p, m, "test"))
+			assert.LessOrEqual(t, atomic.LoadInt32(&maxConcurrent), tt.wantMaxLimit)
+		})
+	}
+}
+
 // TestConcurrentStepFailure tests that when one concurrent step fails,
 // the batch returns an error and other steps get cancelled via context.
 func TestConcurrentStepFailure(t *testing.T) {
@@ -2032,8 +2089,8 @@ func TestMissingRequiredArtifactFailsBeforeStep(t *testing.T) {
 
 	err := executor.Execute(ctx, p, m, "test")
 	require.Error(t, err)
-	assert.Contains(t, err.Error(), "required artifact")
-	assert.Contains(t, err.Error(), "not found")
+	// Caught by DAG validation before any step runs.
+	assert.Contains(t, err.Error(), `references non-existent step "nonexistent-step"`)
 }
 
 // TestOptionalMissingArtifactProceeds tests that optional missing artifacts don't fail the step
@@ -3400,6 +3457,13 @@ func TestExecuteStep_RetryConfig_MaxAttempts(t *testing.T) {
 	assert.NoError(t, err, "should succeed on third attempt")
 	assert.Equal(t, 3, failAdapter.getCallCount(), "adapter should have been called 3 times")
 
+	// Only attempts with a retry left ask the adapter to keep a process warm.
+	var warmRetry []bool
+	for _, cfg := range failAdapter.getLastConfigs() {
+		warmRetry = append(warmRetry, cfg.WarmRetry)
+	}
+	assert.Equal(t, []bool{true, true, false}, warmRetry)
+
 	// Verify attempt records
 	attempts := store.getAttempts()
 	// We expect: running(1), failed(1), running(2), failed(2), running(3), succeeded(3)
@@ -4449,6 +4513,51 @@ func TestExecuteWithInvalidStepFilter(t *testing.T) {
 	assert.Contains(t, err.Error(), "unknown step")
 }
 
+// TestExecuteWithSkippedDependencyArtifact verifies that a step whose
+// dependency is filtered out runs with the artifact provided in its place.
+func TestExecuteWithSkippedDependencyArtifact(t *testing.T) {
+	collector := testutil.NewEventCollector()
+	mockAdapter := adaptertest.NewMockAdapter(
+		adaptertest.WithStdoutJSON(`{"status": "success"}`),
+		adaptertest.WithTokensUsed(100),
+	)
+
+	tmpDir := t.TempDir()
+	m := testutil.CreateTestManifest(tmpDir)
+	planPath := filepath.Join(tmpDir, "plan.md")
+	require.NoError(t, os.WriteFile(planPath, []byte("provided plan"), 0644))
+
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "skipped-dep-test"},
+		Steps: []Step{
+			{ID: "step-a", Persona: "navigator", Exec: ExecConfig{Source: "A"}},
+			{ID: "step-b", Persona: "navigator", Dependencies: []string{"step-a"}, Exec: ExecConfig{Source: "B"},
+				Memory: MemoryConfig{InjectArtifacts: []ArtifactRef{{Step: "step-a", Artifact: "plan"}}}},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Without the artifact the run is refused before any step starts.
+	executor := NewDefaultPipelineExecutor(mockAdapter, WithEmitter(collector),
+		WithStepFilter(&StepFilter{Include: []string{"step-b"}}))
+	err := executor.Execute(ctx, p, m, "test")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `needs artifact "plan" from skipped step "step-a"`)
+	assert.Empty(t, collector.GetStepExecutionOrder())
+
+	executor = NewDefaultPipelineExecutor(mockAdapter, WithEmitter(collector),
+		WithStepFilter(&StepFilter{Include: []string{"step-b"}, Artifacts: map[string]string{"step-a:plan": planPath}}))
+	require.NoError(t, executor.Execute(ctx, p, m, "test"))
+	assert.Equal(t, []string{"step-b"}, collector.GetStepExecutionOrder())
+
+	ws := executor.LastExecution().WorkspacePaths["step-b"]
+	data, err := os.ReadFile(filepath.Join(ws, ".agents", "artifacts", "plan"))
+	require.NoError(t, err)
+	assert.Equal(t, "provided plan", string(data))
+}
+
 // TestExecuteWithNilFilter verifies that nil filter runs all steps (no-op)
 func TestExecuteWithNilFilter(t *testing.T) {
 	collector := testutil.NewEventCollector()
@@ -5984,6 +6093,115 @@ func TestExecuteStep_FailureClassification_Deterministic_SkipsRetry(t *testing.T
 	assert.True(t, foundSkipMsg, "should emit event about non-retryable failure class")
 }
 
+// failureReasonAdapter returns a failed result carrying a structured failure
+// reason, the way CLI adapters report provider errors.
+type failureReasonAdapter struct {
+	reason string
+	mu     sync.Mutex
+	calls  int
+}
+
+func (a *failureReasonAdapter) Run(_ context.Context, _ adapter.AdapterRunConfig) (*adapter.AdapterResult, error) {
+	a.mu.Lock()
+	a.calls++
+	a.mu.Unlock()
+	return &adapter.AdapterResult{
+		ExitCode:      1,
+		Stdout:        strings.NewReader(""),
+		ResultContent: "Invalid API key · Please run /login",
+		FailureReason: a.reason,
+	}, nil
+}
+
+// TestExecuteStep_FailureClassification_AdapterReason verifies that a failure
+// reason reported in the adapter result fails the step without retries and
+// reaches the step's events and failure category.
+func TestExecuteStep_FailureClassification_AdapterReason(t *testing.T) {
+	collector := testutil.NewEventCollector()
+	store := newAttemptTrackingStore()
+	runner := &failureReasonAdapter{reason: adapter.FailureReasonAuth}
+
+	executor := NewDefaultPipelineExecutor(runner, WithEmitter(collector), WithStateStore(store))
+	m := testutil.CreateTestManifest(t.TempDir())
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "adapter-reason-test"},
+		Steps: []Step{{
+			ID:      "step-1",
+			Persona: "navigator",
+			Exec:    ExecConfig{Source: "do work"},
+			Retry:   RetryConfig{MaxAttempts: 3, BaseDelay: "1ms"},
+		}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := executor.Execute(ctx, p, m, "test")
+	require.Error(t, err)
+	assert.Equal(t, adapter.FailureReasonAuth, AdapterFailureReason(err))
+	assert.Equal(t, 1, runner.calls, "auth failures are not retried")
+
+	attempts := store.getAttempts()
+	require.NotEmpty(t, attempts)
+	assert.Equal(t, FailureClassDeterministic, attempts[len(attempts)-1].FailureClass)
+
+	var reasons []string
+	for _, e := range collector.GetEvents() {
+		if e.State == event.StateFailed && e.FailureReason != "" {
+			reasons = append(reasons, e.FailureReason)
+		}
+	}
+	assert.Contains(t, reasons, adapter.FailureReasonAuth)
+}
+
+// TestExecuteStep_RetryOn_OverridesClassRetryability verifies that retry_on
+// opts a normally non-retryable failure into retries and that no_retry_on
+// stops a normally retryable one.
+func TestExecuteStep_RetryOn_OverridesClassRetryability(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retry     RetryConfig
+		wantCalls int
+	}{
+		{
+			name:      "retry_on sandbox_denial retries deterministic failure",
+			err:       errors.New("bwrap: permission denied"),
+			retry:     RetryConfig{MaxAttempts: 3, BaseDelay: "1ms", RetryOn: []string{FailureCategorySandboxDenial}},
+			wantCalls: 3,
+		},
+		{
+			name:      "no_retry_on rate_limit stops transient retries",
+			err:       errors.New("too many requests"),
+			retry:     RetryConfig{MaxAttempts: 3, BaseDelay: "1ms", NoRetryOn: []string{FailureCategoryRateLimit}},
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failAdapter := newCountingFailAdapter(10, tt.err)
+			executor := NewDefaultPipelineExecutor(failAdapter,
+				WithEmitter(testutil.NewEventCollector()),
+				WithStateStore(newAttemptTrackingStore()),
+			)
+
+			m := testutil.CreateTestManifest(t.TempDir())
+			p := &Pipeline{
+				Metadata: PipelineMetadata{Name: "retry-on-test"},
+				Steps: []Step{
+					{ID: "step-1", Persona: "navigator", Exec: ExecConfig{Source: "do work"}, Retry: tt.retry},
+				},
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			require.Error(t, executor.Execute(ctx, p, m, "test"))
+			assert.Equal(t, tt.wantCalls, failAdapter.getCallCount())
+		})
+	}
+}
+
 // TestExecuteStep_CircuitBreaker_TripsOnRepeatedFailures verifies that the circuit
 // breaker trips when the same failure fingerprint repeats beyond the configured limit.
 func TestExecuteStep_CircuitBreaker_TripsOnRepeatedFailures(t *testing.T) {
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/procutil"
 	"github.com/recinq/wave/internal/workspace"
 	"github.com/recinq/wave/internal/worktree"
 )
@@ -87,7 +88,11 @@ func (e *DefaultPipelineExecutor) createStepWorkspace(execution *PipelineExecuti
 		// Resolve branch name from template variables.
 		// Step output references ({{ steps.X.artifacts.Y.field }}) are resolved first
 		// so that branch names can be derived from prior step outputs (e.g. PR head branch).
+		// Steps that name neither a branch nor a base use runtime.naming.branch.
 		branch := step.Workspace.Branch
+		if branch == "" && step.Workspace.Base == "" {
+			branch = execution.Manifest.Runtime.Naming.Branch
+		}
 		if branch != "" {
 			resolved, err := e.resolveWorkspaceStepRefs(branch, execution)
 			if err != nil {
@@ -124,6 +129,10 @@ func (e *DefaultPipelineExecutor) createStepWorkspace(execution *PipelineExecuti
 			}
 		}
 
+		// Branch names rendered from issue titles may carry emoji and
+		// punctuation git rejects; normalize before creating the worktree.
+		branch = SanitizeGitRef(branch)
+
 		// Reuse existing worktree for the same branch
 		execution.mu.Lock()
 		info, ok := execution.WorktreePaths[branch]
@@ -153,7 +162,22 @@ func (e *DefaultPipelineExecutor) createStepWorkspace(execution *PipelineExecuti
 			return "", fmt.Errorf("failed to create worktree manager: %w", err)
 		}
 
-		if err := mgr.Create(absPath, branch, base); err != nil {
+		// Sparse scope: check out only the listed paths. Steps sharing the
+		// branch reuse this worktree, so the first step's checkout options apply.
+		opts := worktree.CreateOptions{
+			Submodules: step.Workspace.Submodules,
+			LFS:        step.Workspace.LFS,
+		}
+		for _, entry := range step.Workspace.Scope {
+			if execution.Context != nil {
+				entry = execution.Context.ResolvePlaceholders(entry)
+			}
+			if entry = strings.TrimSpace(entry); entry != "" {
+				opts.Sparse = append(opts.Sparse, entry)
+			}
+		}
+
+		if err := mgr.CreateWithOptions(absPath, branch, base, opts); err != nil {
 			return "", fmt.Errorf("failed to create worktree workspace: %w", err)
 		}
 
@@ -180,10 +204,6 @@ func (e *DefaultPipelineExecutor) createStepWorkspace(execution *PipelineExecuti
 		// Record branch creation as a deliverable for outcome tracking
 		e.outcomeTracker.AddBranch(step.ID, branch, absPath, "Feature branch")
 
-		// Mark CLAUDE.md as skip-worktree so prepareWorkspace() changes
-		// don't get staged by git add -A in implement steps
-		_ = exec.Command("git", "-C", absPath, "update-index", "--skip-worktree", "AGENTS.md").Run()
-
 		// Run skill init commands inside the worktree (only on first creation)
 		if execution.Pipeline.Requires != nil {
 			for _, skillName := range execution.Pipeline.Requires.SkillNames() {
@@ -198,7 +218,8 @@ func (e *DefaultPipelineExecutor) createStepWorkspace(execution *PipelineExecuti
 					State:      "skill_init",
 					Message:    fmt.Sprintf("running init for skill %q in worktree", skillName),
 				})
-				initCmd := exec.Command("sh", "-c", cfg.Init)
+				sh := procutil.ShellArgs(cfg.Init)
+				initCmd := exec.Command(sh[0], sh[1:]...)
 				initCmd.Dir = absPath
 				if out, err := initCmd.CombinedOutput(); err != nil {
 					return "", fmt.Errorf("skill %q init failed in worktree: %w\noutput: %s", skillName, err, string(out))
@@ -540,3 +561,37 @@ func (e *DefaultPipelineExecutor) cleanupWorktrees(execution *PipelineExecution,
 // referenced pipeline YAML, resolves the step's input template, and delegates
 // execution to a fresh DefaultPipelineExecutor instance.
 // can be retrieved from persistent storage via GetStatus.
+
+// checkWorkspaceBases validates the literal base refs of worktree steps before
+// any step runs, so a base missing from a stale clone fails the run up front
+// rather than when its step creates the worktree. Bases referencing step
+// outputs are only known later and are checked by the worktree manager then.
+func (e *DefaultPipelineExecutor) checkWorkspaceBases(steps []*Step, pctx *PipelineContext) error {
+	var mgr *worktree.Manager
+	checked := make(map[string]bool)
+	for _, step := range steps {
+		if step.Workspace.Type != "worktree" || step.Workspace.Base == "" {
+			continue
+		}
+		base := step.Workspace.Base
+		if pctx != nil {
+			base = pctx.ResolvePlaceholders(base)
+		}
+		if base == "" || strings.Contains(base, "{{") || checked[base] {
+			continue
+		}
+		checked[base] = true
+		if mgr == nil {
+			m, err := worktree.NewManager("")
+			if err != nil {
+				// Not a git repository; worktree creation reports that itself.
+				return nil
+			}
+			mgr = m
+		}
+		if _, err := mgr.ResolveBase(base); err != nil {
+			return fmt.Errorf("step %s: workspace base: %w", step.ID, err)
+		}
+	}
+	return nil
+}
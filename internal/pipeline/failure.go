@@ -51,12 +51,13 @@ func ClassifyStepFailure(err error, contractErr error, ctxErr error) string {
 	var stepErr *adapter.StepError
 	if errors.As(err, &stepErr) {
 		switch stepErr.FailureReason {
-		case adapter.FailureReasonTimeout:
-			return FailureClassTransient
-		case adapter.FailureReasonRateLimit:
+		case adapter.FailureReasonTimeout, adapter.FailureReasonRateLimit,
+			adapter.FailureReasonCrash, adapter.FailureReasonParseError:
 			return FailureClassTransient
-		case adapter.FailureReasonContextExhaustion:
+		case adapter.FailureReasonContextExhaustion, adapter.FailureReasonQuota:
 			return FailureClassBudgetExhausted
+		case adapter.FailureReasonAuth, adapter.FailureReasonToolDenied:
+			return FailureClassDeterministic
 		}
 	}
 
@@ -66,6 +67,28 @@ func ClassifyStepFailure(err error, contractErr error, ctxErr error) string {
 		return FailureClassContractFailure
 	}
 
+	// A modified input artifact stays modified on retry.
+	var integrityErr *ArtifactIntegrityError
+	if errors.As(err, &integrityErr) {
+		return FailureClassDeterministic
+	}
+
+	// A runaway agent ran out of its turn or tool call allowance.
+	var limitErr *StepLimitError
+	if errors.As(err, &limitErr) {
+		return FailureClassBudgetExhausted
+	}
+	var tokenErr *TokenBudgetError
+	if errors.As(err, &tokenErr) {
+		return FailureClassBudgetExhausted
+	}
+
+	// The persona would go out of bounds again on retry.
+	var pathErr *PathPolicyViolationError
+	if errors.As(err, &pathErr) {
+		return FailureClassDeterministic
+	}
+
 	// Explicit contract error parameter.
 	if contractErr != nil {
 		return FailureClassContractFailure
@@ -137,6 +160,7 @@ func classifyByMessage(msg string) string {
 
 	// Budget exhaustion patterns.
 	budgetPatterns := []string{
+		"budget exceeded",
 		"context window",
 		"token limit",
 		"prompt is too long",
@@ -151,6 +175,174 @@ func classifyByMessage(msg string) string {
 	return FailureClassTransient
 }
 
+// Failure category constants for triage reporting. Categories are finer
+// grained than failure classes: a class drives retry decisions, while a
+// category names the root cause so `wave triage` can aggregate failures
+// into something actionable for reliability work.
+const (
+	FailureCategoryRateLimit         = "rate_limit"
+	FailureCategoryContractViolation = "contract_violation"
+	FailureCategoryTimeout           = "timeout"
+	FailureCategoryAdapterCrash      = "adapter_crash"
+	FailureCategorySandboxDenial     = "sandbox_denial"
+	FailureCategoryMissingArtifact   = "missing_artifact"
+	FailureCategoryContextExhausted  = "context_exhausted"
+	FailureCategoryStepLimit         = "step_limit"
+	FailureCategoryCanceled          = "canceled"
+	FailureCategoryUnknown           = "unknown"
+
+	// Categories only adapters report, via adapter.StepError.
+	FailureCategoryAuth       = "auth"
+	FailureCategoryQuota      = "quota"
+	FailureCategoryParseError = "parse_error"
+)
+
+// failureKindAliases maps alternative spellings accepted by retry_on and
+// no_retry_on to the failure class or category they stand for.
+var failureKindAliases = map[string]string{
+	"contract_failed": FailureClassContractFailure,
+}
+
+// canonicalFailureKind resolves an alias to its failure class or category.
+func canonicalFailureKind(name string) string {
+	if canonical, ok := failureKindAliases[name]; ok {
+		return canonical
+	}
+	return name
+}
+
+// IsKnownFailureKind reports whether name is a recognised failure class or
+// failure category, or an alias of one, the vocabulary accepted by retry_on
+// and no_retry_on.
+func IsKnownFailureKind(name string) bool {
+	switch name = canonicalFailureKind(name); name {
+	case FailureClassTransient, FailureClassDeterministic, FailureClassBudgetExhausted,
+		FailureClassContractFailure, FailureClassTestFailure, FailureClassCanceled:
+		return true
+	default:
+		return IsFailureCategory(name)
+	}
+}
+
+// IsFailureCategory reports whether name is a triage failure category, the
+// value knowledge base entries are matched on. Failure classes and aliases
+// are not categories.
+func IsFailureCategory(name string) bool {
+	switch name {
+	case FailureCategoryRateLimit, FailureCategoryContractViolation, FailureCategoryTimeout,
+		FailureCategoryAdapterCrash, FailureCategorySandboxDenial, FailureCategoryMissingArtifact,
+		FailureCategoryContextExhausted, FailureCategoryStepLimit, FailureCategoryCanceled,
+		FailureCategoryUnknown, FailureCategoryAuth, FailureCategoryQuota, FailureCategoryParseError:
+		return true
+	default:
+		return false
+	}
+}
+
+// AdapterFailureReason returns the adapter failure reason err carries, or ""
+// when the failure did not come from an adapter.StepError.
+func AdapterFailureReason(err error) string {
+	var stepErr *adapter.StepError
+	if errors.As(err, &stepErr) {
+		return stepErr.FailureReason
+	}
+	return ""
+}
+
+// CategorizeStepFailure maps a step failure onto a triage category. Like
+// ClassifyStepFailure it prefers typed errors (adapter.StepError,
+// contract.ValidationError) and falls back to message patterns. Returns ""
+// when both err and ctxErr are nil.
+func CategorizeStepFailure(err error, ctxErr error) string {
+	if ctxErr != nil {
+		if errors.Is(ctxErr, context.Canceled) {
+			return FailureCategoryCanceled
+		}
+		if errors.Is(ctxErr, context.DeadlineExceeded) {
+			return FailureCategoryTimeout
+		}
+	}
+	if err == nil {
+		return ""
+	}
+
+	var stepErr *adapter.StepError
+	if errors.As(err, &stepErr) {
+		switch stepErr.FailureReason {
+		case adapter.FailureReasonTimeout:
+			return FailureCategoryTimeout
+		case adapter.FailureReasonRateLimit:
+			return FailureCategoryRateLimit
+		case adapter.FailureReasonContextExhaustion:
+			return FailureCategoryContextExhausted
+		case adapter.FailureReasonQuota:
+			return FailureCategoryQuota
+		case adapter.FailureReasonAuth:
+			return FailureCategoryAuth
+		case adapter.FailureReasonToolDenied:
+			return FailureCategorySandboxDenial
+		case adapter.FailureReasonCrash:
+			return FailureCategoryAdapterCrash
+		case adapter.FailureReasonParseError:
+			return FailureCategoryParseError
+		}
+	}
+
+	var valErr *contract.ValidationError
+	if errors.As(err, &valErr) {
+		return FailureCategoryContractViolation
+	}
+
+	var limitErr *StepLimitError
+	if errors.As(err, &limitErr) {
+		return FailureCategoryStepLimit
+	}
+	var tokenErr *TokenBudgetError
+	if errors.As(err, &tokenErr) {
+		return FailureCategoryStepLimit
+	}
+
+	return categorizeByMessage(err.Error())
+}
+
+// categorizeByMessage performs case-insensitive pattern matching on the error
+// message to determine the triage category.
+func categorizeByMessage(msg string) string {
+	lower := strings.ToLower(msg)
+
+	// Missing artifacts surface from several call sites with differing
+	// wording; require both the noun and an absence marker to avoid
+	// matching unrelated artifact errors such as type mismatches.
+	if strings.Contains(lower, "artifact") {
+		for _, p := range []string{"not found", "no such file", "produced no content", "missing"} {
+			if strings.Contains(lower, p) {
+				return FailureCategoryMissingArtifact
+			}
+		}
+	}
+
+	categories := []struct {
+		category string
+		patterns []string
+	}{
+		{FailureCategoryContractViolation, []string{"contract validation failed", "schema validation failed", "contract failed"}},
+		{FailureCategoryRateLimit, []string{"rate limit", "too many requests", "status 429", "http 429", "error 429"}},
+		{FailureCategoryTimeout, []string{"deadline exceeded", "timed out", "timeout", "stall watchdog", "stalled"}},
+		{FailureCategoryContextExhausted, []string{"context window", "token limit", "prompt is too long", "context_exhaustion"}},
+		{FailureCategorySandboxDenial, []string{"sandbox", "permission denied", "operation not permitted", "access denied", "denied by"}},
+		{FailureCategoryAdapterCrash, []string{"adapter execution failed", "adapter exited with", "failed to start", "signal: ", "panic:", "exit status"}},
+	}
+	for _, c := range categories {
+		for _, p := range c.patterns {
+			if strings.Contains(lower, p) {
+				return c.category
+			}
+		}
+	}
+
+	return FailureCategoryUnknown
+}
+
 // Regexp patterns for fingerprint normalization.
 var (
 	reTimestamp = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}[.\d]*[Z]?`)
@@ -160,17 +352,23 @@ var (
 	reTempPaths = regexp.MustCompile(`/tmp/[^\s]+`)
 )
 
-// NormalizeFingerprint produces a stable fingerprint for a step failure by
-// stripping volatile substrings (timestamps, line numbers, hex addresses,
-// UUIDs, temp paths) from the error message.
-func NormalizeFingerprint(stepID, failureClass, errorMsg string) string {
+// NormalizeErrorSignature strips volatile substrings (timestamps, line
+// numbers, hex addresses, UUIDs, temp paths) from an error message and
+// lower-cases it, so recurring failures compare equal across runs.
+func NormalizeErrorSignature(errorMsg string) string {
 	normalized := errorMsg
 	normalized = reTimestamp.ReplaceAllString(normalized, "")
 	normalized = reLineNum.ReplaceAllString(normalized, ":")
 	normalized = reHexAddr.ReplaceAllString(normalized, "")
 	normalized = reUUID.ReplaceAllString(normalized, "")
 	normalized = reTempPaths.ReplaceAllString(normalized, "")
-	normalized = strings.ToLower(normalized)
+	return strings.ToLower(normalized)
+}
+
+// NormalizeFingerprint produces a stable fingerprint for a step failure from
+// the step ID, failure class, and normalized error signature.
+func NormalizeFingerprint(stepID, failureClass, errorMsg string) string {
+	normalized := NormalizeErrorSignature(errorMsg)
 
 	if len(normalized) > 200 {
 		normalized = normalized[:200]
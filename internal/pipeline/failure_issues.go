@@ -0,0 +1,181 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/forge"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/state"
+)
+
+// failureIssueRecentLimit caps the failures listed in a tracking issue.
+const failureIssueRecentLimit = 10
+
+// WithForgeClient sets the forge client tracking issues for repeated step
+// failures are filed with (runtime.failure_issues). Without one, a client
+// is resolved from the detected forge and its token when an issue is due.
+func WithForgeClient(c forge.Client) ExecutorOption {
+	return func(ex *DefaultPipelineExecutor) { ex.forgeClient = c }
+}
+
+// failureIssueTitle names the tracking issue of a pipeline step. An open
+// issue with this title is commented on instead of filing another.
+func failureIssueTitle(pipelineName, stepID string) string {
+	return fmt.Sprintf("Wave: %s/%s keeps failing", pipelineName, stepID)
+}
+
+// reportRepeatedFailure files or updates the tracking issue of a step that
+// just failed terminally, once its failures within runtime.failure_issues
+// reach the threshold. Cancelled runs are not counted. Filing is best
+// effort: problems surface as warnings and never change the step outcome.
+func (e *DefaultPipelineExecutor) reportRepeatedFailure(ctx context.Context, execution *PipelineExecution, step *Step, category string) {
+	m := execution.Manifest
+	if e.store == nil || m == nil || !m.Runtime.FailureIssues.Enabled || category == FailureCategoryCanceled {
+		return
+	}
+	cfg := m.Runtime.FailureIssues
+	pipelineName := execution.Pipeline.Metadata.Name
+
+	records, err := e.store.ListStepFailures(time.Now().Add(-cfg.Window()))
+	if err != nil {
+		e.warnFailureIssue(execution, step, err)
+		return
+	}
+	var failures []state.StepFailureRecord
+	for _, r := range records {
+		if r.PipelineName == pipelineName && r.StepID == step.ID && r.FailureCategory != FailureCategoryCanceled {
+			failures = append(failures, r)
+		}
+	}
+	if len(failures) < cfg.EffectiveThreshold() {
+		return
+	}
+
+	owner, repo, client, err := e.failureIssueTarget(m)
+	if err != nil {
+		e.warnFailureIssue(execution, step, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), m.Runtime.Timeouts.GetForgeAPI())
+	defer cancel()
+
+	title := failureIssueTitle(pipelineName, step.ID)
+	body := failureIssueBody(pipelineName, step.ID, failures, cfg.Window())
+	open, err := client.ListIssues(ctx, owner, repo, forge.ListIssuesOptions{State: "open", Labels: cfg.EffectiveLabels(), PerPage: 100})
+	if err != nil {
+		e.warnFailureIssue(execution, step, err)
+		return
+	}
+	for _, issue := range open {
+		if issue.IsPR || issue.Title != title {
+			continue
+		}
+		if err := client.CreateIssueComment(ctx, owner, repo, issue.Number, body); err != nil {
+			e.warnFailureIssue(execution, step, err)
+			return
+		}
+		e.emitFailureIssue(execution, step, fmt.Sprintf("updated failure issue #%d (%d failures)", issue.Number, len(failures)), issue)
+		return
+	}
+
+	issue, err := client.CreateIssue(ctx, owner, repo, forge.CreateIssueRequest{Title: title, Body: body, Labels: cfg.EffectiveLabels()})
+	if err != nil {
+		e.warnFailureIssue(execution, step, err)
+		return
+	}
+	e.emitFailureIssue(execution, step, fmt.Sprintf("filed failure issue #%d (%d failures)", issue.Number, len(failures)), issue)
+}
+
+// failureIssueTarget resolves the repository and client tracking issues are
+// filed with: runtime.failure_issues.repo, else the git remote.
+func (e *DefaultPipelineExecutor) failureIssueTarget(m *manifest.Manifest) (string, string, forge.Client, error) {
+	slug := m.Runtime.FailureIssues.Repo
+	client := e.forgeClient
+	if slug == "" || client == nil {
+		info := forge.DetectFromGitRemotesWithOverride(m.Metadata.Forge)
+		if slug == "" {
+			slug = info.Slug()
+		}
+		if client == nil {
+			c, err := forge.NewClient(info)
+			if err != nil {
+				return "", "", nil, err
+			}
+			if c == nil {
+				return "", "", nil, fmt.Errorf("no API token for forge %q", info.Type)
+			}
+			client = c
+		}
+	}
+	owner, repo, ok := strings.Cut(slug, "/")
+	if !ok || owner == "" || repo == "" {
+		return "", "", nil, errors.New("no repository detected; set runtime.failure_issues.repo")
+	}
+	return owner, repo, client, nil
+}
+
+// failureIssueBody renders the failures of a step, newest first, with their
+// counts per triage category.
+func failureIssueBody(pipelineName, stepID string, failures []state.StepFailureRecord, window time.Duration) string {
+	counts := make(map[string]int)
+	for _, f := range failures {
+		counts[f.FailureCategory]++
+	}
+	categories := make([]string, 0, len(counts))
+	for c := range counts {
+		categories = append(categories, c)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if counts[categories[i]] != counts[categories[j]] {
+			return counts[categories[i]] > counts[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Step `%s` of pipeline `%s` failed %d times in the last %s.\n\n", stepID, pipelineName, len(failures), window)
+	b.WriteString("| Category | Failures |\n|---|---|\n")
+	for _, c := range categories {
+		fmt.Fprintf(&b, "| %s | %d |\n", c, counts[c])
+	}
+	b.WriteString("\n### Recent failures\n\n| Run | Category | Failed at | Error |\n|---|---|---|---|\n")
+	for i, f := range failures {
+		if i == failureIssueRecentLimit {
+			fmt.Fprintf(&b, "\n%d older failures omitted.\n", len(failures)-i)
+			break
+		}
+		msg := strings.NewReplacer("\n", " ", "|", "\\|").Replace(strings.TrimSpace(f.ErrorMessage))
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", f.RunID, f.FailureCategory, f.FailedAt.UTC().Format(time.RFC3339), truncate(msg, 200))
+	}
+	b.WriteString("\nInspect with `wave triage --pipeline " + pipelineName + "` and `wave logs <run>`. Filed by runtime.failure_issues.\n")
+	return b.String()
+}
+
+func (e *DefaultPipelineExecutor) emitFailureIssue(execution *PipelineExecution, step *Step, msg string, issue *forge.Issue) {
+	if issue.HTMLURL != "" {
+		msg += ": " + issue.HTMLURL
+	}
+	e.emit(event.Event{
+		Timestamp:  time.Now(),
+		PipelineID: execution.Status.ID,
+		StepID:     step.ID,
+		State:      "warning",
+		Message:    msg,
+	})
+}
+
+func (e *DefaultPipelineExecutor) warnFailureIssue(execution *PipelineExecution, step *Step, err error) {
+	e.emit(event.Event{
+		Timestamp:  time.Now(),
+		PipelineID: execution.Status.ID,
+		StepID:     step.ID,
+		State:      "warning",
+		Message:    "failure issue not filed: " + err.Error(),
+	})
+}
@@ -0,0 +1,90 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/forge"
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// issueForge records the issues and comments filed through it.
+type issueForge struct {
+	forge.Client
+	issues   []*forge.Issue
+	labels   []string
+	comments map[int][]string
+}
+
+func (f *issueForge) ListIssues(_ context.Context, _, _ string, _ forge.ListIssuesOptions) ([]*forge.Issue, error) {
+	return f.issues, nil
+}
+
+func (f *issueForge) CreateIssue(_ context.Context, _, _ string, req forge.CreateIssueRequest) (*forge.Issue, error) {
+	issue := &forge.Issue{Number: len(f.issues) + 1, Title: req.Title, Body: req.Body, State: "open"}
+	f.issues = append(f.issues, issue)
+	f.labels = req.Labels
+	return issue, nil
+}
+
+func (f *issueForge) CreateIssueComment(_ context.Context, _, _ string, number int, body string) error {
+	if f.comments == nil {
+		f.comments = make(map[int][]string)
+	}
+	f.comments[number] = append(f.comments[number], body)
+	return nil
+}
+
+func TestReportRepeatedFailure_FilesThenUpdatesIssue(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := state.NewStateStore(filepath.Join(tmpDir, "state.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	m := testutil.CreateTestManifest(tmpDir)
+	m.Runtime.FailureIssues.Enabled = true
+	m.Runtime.FailureIssues.Threshold = 2
+	m.Runtime.FailureIssues.Repo = "acme/app"
+
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "nightly"},
+		Steps:    []Step{{ID: "build", Persona: "navigator", Exec: ExecConfig{Source: "build"}}},
+	}
+	fake := &issueForge{}
+	run := func() {
+		runID, err := store.CreateRun("nightly", "")
+		require.NoError(t, err)
+		executor := NewDefaultPipelineExecutor(
+			adaptertest.NewMockAdapter(adaptertest.WithFailure(errors.New("linker exploded"))),
+			WithEmitter(testutil.NewEventCollector()),
+			WithStateStore(store),
+			WithRunID(runID),
+			WithForgeClient(fake),
+		)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		require.Error(t, executor.Execute(ctx, p, m, ""))
+	}
+
+	run()
+	assert.Empty(t, fake.issues, "one failure stays below the threshold")
+
+	run()
+	require.Len(t, fake.issues, 1)
+	assert.Equal(t, "Wave: nightly/build keeps failing", fake.issues[0].Title)
+	assert.Contains(t, fake.issues[0].Body, "failed 2 times")
+	assert.Contains(t, fake.issues[0].Body, "linker exploded")
+	assert.Equal(t, []string{"wave-failure"}, fake.labels)
+
+	run()
+	require.Len(t, fake.issues, 1, "the open issue is updated, not duplicated")
+	require.Len(t, fake.comments[1], 1)
+	assert.Contains(t, fake.comments[1][0], "failed 3 times")
+}
@@ -230,10 +230,8 @@ func TestFailureMode_MissingArtifact(t *testing.T) {
 
 	err := tc.executor.Execute(ctx, p, tc.manifest, "test")
 	require.Error(t, err, "pipeline should fail when a required artifact is missing")
-	assert.Contains(t, err.Error(), "required artifact",
-		"error should mention 'required artifact'")
-	assert.Contains(t, err.Error(), "not found",
-		"error should mention 'not found'")
+	assert.Contains(t, err.Error(), `references non-existent step "nonexistent-step"`,
+		"DAG validation should reject the reference before any step runs")
 }
 
 // ============================================================================
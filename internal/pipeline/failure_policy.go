@@ -0,0 +1,301 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/state"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrorArtifactName is the artifact under which the error of a failed step
+// with an on_failure.run handler is registered ("<step-id>:error").
+const ErrorArtifactName = "error"
+
+// StepFailureConfig is a step's on_failure block: the policy deciding what
+// a failure does to the rest of the run, and the handler step run when the
+// step fails. `on_failure: isolate` is shorthand for the policy alone.
+type StepFailureConfig struct {
+	Policy string `yaml:"policy,omitempty"` // halt (default), continue or isolate
+	// Run is a rework_only step executed when this step fails, such as a
+	// cleanup or compensation step. It can read the failure from the
+	// "<step-id>:error" artifact.
+	Run string `yaml:"run,omitempty"`
+}
+
+// UnmarshalYAML accepts a policy name as well as the block.
+func (c *StepFailureConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*c = StepFailureConfig{Policy: node.Value}
+		return nil
+	}
+	// Decoding through a custom unmarshaler drops the strict field check.
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if key := node.Content[i]; key.Value != "policy" && key.Value != "run" {
+			return fmt.Errorf("line %d: field %s not found in type pipeline.StepFailureConfig", key.Line, key.Value)
+		}
+	}
+	type plain StepFailureConfig
+	return node.Decode((*plain)(c))
+}
+
+// MarshalYAML writes a block without a handler back as the policy name.
+func (c StepFailureConfig) MarshalYAML() (interface{}, error) {
+	if c.Run == "" {
+		return c.Policy, nil
+	}
+	type plain StepFailureConfig
+	return plain(c), nil
+}
+
+// failurePolicy returns the step's on_failure policy, "" meaning halt.
+func (s *Step) failurePolicy() string {
+	if s.OnFailure == nil {
+		return ""
+	}
+	return s.OnFailure.Policy
+}
+
+// failureHandler returns the step's on_failure.run handler, if any.
+func (s *Step) failureHandler() string {
+	if s.OnFailure == nil {
+		return ""
+	}
+	return s.OnFailure.Run
+}
+
+// keepsRunGoing reports whether a failure of step leaves the rest of the
+// run scheduled (on_failure: continue or isolate).
+func (s *Step) keepsRunGoing() bool {
+	return s.failurePolicy() == OnFailureContinue || s.failurePolicy() == OnFailureIsolate
+}
+
+// validateStepFailurePolicies checks step-level on_failure values and
+// handlers. Graph pipelines route failures along their edges, so only halt
+// applies there. A handler must be a rework_only step, so it runs only when
+// triggered, and serves a single step, like a rework target.
+func validateStepFailurePolicies(p *Pipeline, graph bool) error {
+	stepMap := make(map[string]*Step, len(p.Steps))
+	reworkTargets := make(map[string]bool)
+	for i := range p.Steps {
+		step := &p.Steps[i]
+		stepMap[step.ID] = step
+		if step.Retry.ReworkStep != "" {
+			reworkTargets[step.Retry.ReworkStep] = true
+		}
+		for _, c := range step.Handover.EffectiveContracts() {
+			if c.ReworkStep != "" {
+				reworkTargets[c.ReworkStep] = true
+			}
+		}
+	}
+
+	handlers := make(map[string]string) // handler -> failing step
+	for _, step := range p.Steps {
+		switch step.failurePolicy() {
+		case "", OnFailureHalt:
+		case OnFailureContinue, OnFailureIsolate:
+			if graph {
+				return fmt.Errorf("step %q: on_failure %q is not supported in graph pipelines; route failures with edges instead", step.ID, step.failurePolicy())
+			}
+		default:
+			return fmt.Errorf("step %q has invalid on_failure value %q (must be halt, continue, or isolate)", step.ID, step.failurePolicy())
+		}
+
+		handler := step.failureHandler()
+		if handler == "" {
+			continue
+		}
+		if graph {
+			return fmt.Errorf("step %q: on_failure.run is not supported in graph pipelines; add an edge to the handler instead", step.ID)
+		}
+		target, ok := stepMap[handler]
+		switch {
+		case !ok:
+			return fmt.Errorf("step %q has on_failure.run %q which does not exist in the pipeline", step.ID, handler)
+		case handler == step.ID:
+			return fmt.Errorf("step %q cannot run itself on failure", step.ID)
+		case !target.ReworkOnly:
+			return fmt.Errorf("failure handler %q must have rework_only: true (referenced by step %q)", handler, step.ID)
+		case reworkTargets[handler]:
+			return fmt.Errorf("failure handler %q is also a rework target", handler)
+		}
+		if existing, ok := handlers[handler]; ok {
+			return fmt.Errorf("failure handler %q is used by both step %q and step %q (each handler must be unique)", handler, existing, step.ID)
+		}
+		handlers[handler] = step.ID
+	}
+	return nil
+}
+
+// executeStepWithPolicy runs step and applies its on_failure block. When
+// the step fails, its handler runs first. With continue or isolate, the
+// failure is then recorded on the step and in execution.StepFailures
+// instead of being returned, so the other steps of the batch and the
+// independent branches of the DAG keep running. Cancellation, gate
+// re-queues and design rejections neither run the handler nor keep the
+// run going.
+func (e *DefaultPipelineExecutor) executeStepWithPolicy(ctx context.Context, execution *PipelineExecution, step *Step) error {
+	err := e.executeStep(ctx, execution, step)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	var reQueueErr *reQueueError
+	var rejectionErr *ContractRejectionError
+	if errors.As(err, &reQueueErr) || errors.As(err, &rejectionErr) {
+		return err
+	}
+
+	var handlerErr error
+	if step.failureHandler() != "" {
+		handlerErr = e.runFailureHandler(ctx, execution, step, err)
+	}
+	if !step.keepsRunGoing() {
+		if handlerErr != nil {
+			return errors.Join(err, handlerErr)
+		}
+		return err
+	}
+
+	pipelineID := execution.Status.ID
+	execution.mu.Lock()
+	execution.States[step.ID] = stateFailed
+	execution.StepFailures = append(execution.StepFailures, &StepExecutionError{StepID: step.ID, Err: err})
+	if handlerErr != nil {
+		execution.StepFailures = append(execution.StepFailures, handlerErr)
+	}
+	execution.mu.Unlock()
+	if e.store != nil {
+		_ = e.store.SaveStepState(pipelineID, step.ID, state.StateFailed, err.Error())
+	}
+	e.emit(event.Event{
+		Timestamp:     time.Now(),
+		PipelineID:    pipelineID,
+		StepID:        step.ID,
+		State:         event.StateFailed,
+		Message:       fmt.Sprintf("step failed, on_failure: %s keeps the run going: %s", step.failurePolicy(), err),
+		LogPath:       adapterLogRef(execution, step.ID),
+		FailureReason: AdapterFailureReason(err),
+	})
+	return nil
+}
+
+// stepErrorRecord is the JSON error artifact of a failed step.
+type stepErrorRecord struct {
+	StepID          string    `json:"step_id"`
+	Error           string    `json:"error"`
+	FailureClass    string    `json:"failure_class"`
+	FailureCategory string    `json:"failure_category"`
+	FailedAt        time.Time `json:"failed_at"`
+}
+
+// runFailureHandler records the error of failedStep as its error artifact
+// and executes its on_failure.run handler with the failure in its attempt
+// context. It returns the handler's error as a StepExecutionError.
+func (e *DefaultPipelineExecutor) runFailureHandler(ctx context.Context, execution *PipelineExecution, failedStep *Step, failErr error) error {
+	pipelineID := execution.Status.ID
+	handlerID := failedStep.failureHandler()
+	var handler *Step
+	for i := range execution.Pipeline.Steps {
+		if execution.Pipeline.Steps[i].ID == handlerID {
+			handler = &execution.Pipeline.Steps[i]
+			break
+		}
+	}
+	if handler == nil {
+		return &StepExecutionError{StepID: handlerID, Err: fmt.Errorf("failure handler not found in pipeline (referenced by step %q)", failedStep.ID)}
+	}
+
+	execution.mu.Lock()
+	workspacePath := execution.WorkspacePaths[failedStep.ID]
+	execution.mu.Unlock()
+	if workspacePath == "" {
+		wsRoot := execution.Manifest.Runtime.WorkspaceRoot
+		if wsRoot == "" {
+			wsRoot = ".agents/workspaces"
+		}
+		workspacePath = filepath.Join(wsRoot, pipelineID)
+	}
+	data, _ := json.MarshalIndent(stepErrorRecord{
+		StepID:          failedStep.ID,
+		Error:           failErr.Error(),
+		FailureClass:    ClassifyStepFailure(failErr, nil, nil),
+		FailureCategory: CategorizeStepFailure(failErr, nil),
+		FailedAt:        time.Now().UTC(),
+	}, "", "  ")
+	artPath := stepRecordArtifactPath(execution, workspacePath, failedStep.ID, ErrorArtifactName)
+	if abs, err := filepath.Abs(artPath); err == nil {
+		artPath = abs
+	}
+	if !e.writeStepRecordArtifact(execution, failedStep, ErrorArtifactName, artPath, data) {
+		artPath = ""
+	}
+
+	execution.mu.Lock()
+	execution.AttemptContexts[handler.ID] = &AttemptContext{
+		Attempt:           failedStep.Retry.EffectiveMaxAttempts(),
+		MaxAttempts:       failedStep.Retry.EffectiveMaxAttempts(),
+		PriorError:        failErr.Error(),
+		FailedStepID:      failedStep.ID,
+		ErrorArtifactPath: artPath,
+	}
+	execution.mu.Unlock()
+
+	e.emit(event.Event{
+		Timestamp:  time.Now(),
+		PipelineID: pipelineID,
+		StepID:     failedStep.ID,
+		State:      "compensating",
+		Message:    fmt.Sprintf("on_failure: running %q after %q failed", handler.ID, failedStep.ID),
+	})
+
+	if err := e.executeStep(ctx, execution, handler); err != nil {
+		execution.mu.Lock()
+		execution.States[handler.ID] = stateFailed
+		execution.mu.Unlock()
+		if e.store != nil {
+			_ = e.store.SaveStepState(pipelineID, handler.ID, state.StateFailed, err.Error())
+		}
+		e.emit(event.Event{
+			Timestamp:  time.Now(),
+			PipelineID: pipelineID,
+			StepID:     handler.ID,
+			State:      event.StateFailed,
+			Message:    fmt.Sprintf("failure handler of %q failed: %s", failedStep.ID, err),
+			LogPath:    adapterLogRef(execution, handler.ID),
+		})
+		return &StepExecutionError{StepID: handler.ID, Err: err}
+	}
+	return nil
+}
+
+// failedDepBlocks reports whether the failed or skipped dependency depID
+// stops the steps depending on it. Dependents of an on_failure: continue
+// step run anyway.
+func failedDepBlocks(execution *PipelineExecution, depID string) bool {
+	for i := range execution.Pipeline.Steps {
+		if s := &execution.Pipeline.Steps[i]; s.ID == depID {
+			return s.failurePolicy() != OnFailureContinue
+		}
+	}
+	return true
+}
+
+// partialFailure returns the aggregated failures of steps whose on_failure
+// kept the run going, or nil when there were none.
+func (ex *PipelineExecution) partialFailure() error {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	if len(ex.StepFailures) == 0 {
+		return nil
+	}
+	return &PartialFailureError{
+		CompletedSteps: append([]string(nil), ex.Status.CompletedSteps...),
+		Errors:         append([]error(nil), ex.StepFailures...),
+	}
+}
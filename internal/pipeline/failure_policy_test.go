@@ -0,0 +1,188 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateStepFailurePolicies(t *testing.T) {
+	steps := func(policy string) *Pipeline {
+		return &Pipeline{Steps: []Step{{ID: "a", OnFailure: &StepFailureConfig{Policy: policy}}}}
+	}
+	for _, policy := range []string{"", OnFailureHalt, OnFailureContinue, OnFailureIsolate} {
+		assert.NoError(t, validateStepFailurePolicies(steps(policy), false), policy)
+	}
+	assert.ErrorContains(t, validateStepFailurePolicies(steps("skip"), false), "invalid on_failure")
+	assert.ErrorContains(t, validateStepFailurePolicies(steps(OnFailureIsolate), true), "graph pipelines")
+	assert.NoError(t, validateStepFailurePolicies(steps(OnFailureHalt), true))
+
+	handled := func(handler Step) *Pipeline {
+		return &Pipeline{Steps: []Step{
+			{ID: "deploy", OnFailure: &StepFailureConfig{Run: handler.ID}},
+			handler,
+		}}
+	}
+	assert.NoError(t, validateStepFailurePolicies(handled(Step{ID: "rollback", ReworkOnly: true}), false))
+	assert.ErrorContains(t, validateStepFailurePolicies(handled(Step{ID: "rollback"}), false), "rework_only")
+	self := &Pipeline{Steps: []Step{{ID: "deploy", ReworkOnly: true, OnFailure: &StepFailureConfig{Run: "deploy"}}}}
+	assert.ErrorContains(t, validateStepFailurePolicies(self, false), "cannot run itself")
+	assert.ErrorContains(t, validateStepFailurePolicies(handled(Step{ID: "rollback", ReworkOnly: true}), true), "graph pipelines")
+	p := handled(Step{ID: "rollback", ReworkOnly: true})
+	p.Steps = append(p.Steps, Step{ID: "migrate", OnFailure: &StepFailureConfig{Run: "rollback"}})
+	assert.ErrorContains(t, validateStepFailurePolicies(p, false), "each handler must be unique")
+	missing := &Pipeline{Steps: []Step{{ID: "deploy", OnFailure: &StepFailureConfig{Run: "nope"}}}}
+	assert.ErrorContains(t, validateStepFailurePolicies(missing, false), "does not exist")
+}
+
+func TestStepFailureConfigYAML(t *testing.T) {
+	var steps []Step
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- id: a
+  on_failure: isolate
+- id: b
+  on_failure:
+    policy: continue
+    run: cleanup
+`), &steps))
+	assert.Equal(t, &StepFailureConfig{Policy: OnFailureIsolate}, steps[0].OnFailure)
+	assert.Equal(t, &StepFailureConfig{Policy: OnFailureContinue, Run: "cleanup"}, steps[1].OnFailure)
+
+	var bad []Step
+	assert.ErrorContains(t, yaml.Unmarshal([]byte("- id: a\n  on_failure:\n    step: cleanup\n"), &bad), "field step not found")
+
+	out, err := yaml.Marshal(steps[0].OnFailure)
+	require.NoError(t, err)
+	assert.Equal(t, "isolate\n", string(out))
+}
+
+// promptRecorder records the prompt each step's adapter run received.
+type promptRecorder struct {
+	adapter.AdapterRunner
+	mu      sync.Mutex
+	prompts map[string]string
+}
+
+func (r *promptRecorder) Run(ctx context.Context, cfg adapter.AdapterRunConfig) (*adapter.AdapterResult, error) {
+	r.mu.Lock()
+	r.prompts[filepath.Base(cfg.WorkspacePath)] = cfg.Prompt
+	r.mu.Unlock()
+	return r.AdapterRunner.Run(ctx, cfg)
+}
+
+func TestStepFailureHandler(t *testing.T) {
+	recorder := &promptRecorder{
+		AdapterRunner: &stepAwareAdapter{
+			defaultAdapter: adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`)),
+			stepAdapters: map[string]adapter.AdapterRunner{
+				"deploy": adaptertest.NewMockAdapter(adaptertest.WithFailure(errors.New("helm upgrade timed out"))),
+			},
+		},
+		prompts: make(map[string]string),
+	}
+	executor := NewDefaultPipelineExecutor(recorder, WithEmitter(testutil.NewEventCollector()))
+	m := testutil.CreateTestManifest(t.TempDir())
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "handler-test"},
+		Steps: []Step{
+			{ID: "deploy", Persona: "navigator", OnFailure: &StepFailureConfig{Run: "rollback"}, Exec: ExecConfig{Source: "deploy"}},
+			{ID: "rollback", Persona: "navigator", ReworkOnly: true, Exec: ExecConfig{Source: "roll back the release"}},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := executor.Execute(ctx, p, m, "test")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "helm upgrade timed out", "the step failure still fails the run")
+
+	execution := executor.LastExecution()
+	execution.mu.Lock()
+	defer execution.mu.Unlock()
+	assert.Equal(t, stateCompleted, execution.States["rollback"])
+
+	errPath := execution.ArtifactPaths["deploy:"+ErrorArtifactName]
+	require.NotEmpty(t, errPath)
+	data, readErr := os.ReadFile(errPath)
+	require.NoError(t, readErr)
+	assert.Contains(t, string(data), "helm upgrade timed out")
+
+	prompt := recorder.prompts["rollback"]
+	assert.Contains(t, prompt, "FAILURE HANDLER CONTEXT")
+	assert.Contains(t, prompt, errPath)
+	assert.Contains(t, prompt, "roll back the release")
+}
+
+// TestStepFailurePolicy runs a DAG where "flaky" fails, "report" depends on
+// it and "lint" is an independent branch.
+func TestStepFailurePolicy(t *testing.T) {
+	tests := []struct {
+		policy      string
+		wantReport  string
+		wantPartial bool
+	}{
+		{policy: OnFailureIsolate, wantReport: stateSkipped, wantPartial: true},
+		{policy: OnFailureContinue, wantReport: stateCompleted, wantPartial: true},
+		{policy: OnFailureHalt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			runner := &stepAwareAdapter{
+				defaultAdapter: adaptertest.NewMockAdapter(
+					adaptertest.WithStdoutJSON(`{"status": "success"}`),
+					adaptertest.WithSimulatedDelay(50*time.Millisecond),
+				),
+				stepAdapters: map[string]adapter.AdapterRunner{
+					"flaky": adaptertest.NewMockAdapter(adaptertest.WithFailure(errors.New("flaky broke"))),
+				},
+			}
+			executor := NewDefaultPipelineExecutor(runner, WithEmitter(testutil.NewEventCollector()))
+			m := testutil.CreateTestManifest(t.TempDir())
+			p := &Pipeline{
+				Metadata: PipelineMetadata{Name: "policy-test"},
+				Steps: []Step{
+					{ID: "flaky", Persona: "navigator", OnFailure: &StepFailureConfig{Policy: tt.policy}, Exec: ExecConfig{Source: "flaky"}},
+					{ID: "lint", Persona: "navigator", Exec: ExecConfig{Source: "lint"}},
+					{ID: "report", Persona: "navigator", Dependencies: []string{"flaky"}, Exec: ExecConfig{Source: "report"}},
+				},
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			err := executor.Execute(ctx, p, m, "test")
+			require.Error(t, err)
+
+			var stepErr *StepExecutionError
+			require.True(t, errors.As(err, &stepErr))
+			assert.Equal(t, "flaky", stepErr.StepID)
+
+			var partial *PartialFailureError
+			if !tt.wantPartial {
+				assert.False(t, errors.As(err, &partial), "halt fails the run with the step error")
+				return
+			}
+			require.True(t, errors.As(err, &partial))
+			assert.Len(t, partial.Errors, 1)
+			assert.Contains(t, partial.CompletedSteps, "lint", "the independent branch keeps running")
+
+			execution := executor.LastExecution()
+			assert.Equal(t, stateFailed, execution.Status.State)
+			execution.mu.Lock()
+			defer execution.mu.Unlock()
+			assert.Equal(t, tt.wantReport, execution.States["report"])
+			assert.Equal(t, stateCompleted, execution.States["lint"])
+		})
+	}
+}
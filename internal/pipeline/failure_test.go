@@ -94,6 +94,31 @@ func TestClassifyStepFailure_AdapterStepError(t *testing.T) {
 			reason:   adapter.FailureReasonContextExhaustion,
 			expected: FailureClassBudgetExhausted,
 		},
+		{
+			name:     "quota returns budget_exhausted",
+			reason:   adapter.FailureReasonQuota,
+			expected: FailureClassBudgetExhausted,
+		},
+		{
+			name:     "auth returns deterministic",
+			reason:   adapter.FailureReasonAuth,
+			expected: FailureClassDeterministic,
+		},
+		{
+			name:     "tool_denied returns deterministic",
+			reason:   adapter.FailureReasonToolDenied,
+			expected: FailureClassDeterministic,
+		},
+		{
+			name:     "crash returns transient",
+			reason:   adapter.FailureReasonCrash,
+			expected: FailureClassTransient,
+		},
+		{
+			name:     "parse_error returns transient",
+			reason:   adapter.FailureReasonParseError,
+			expected: FailureClassTransient,
+		},
 		{
 			name:     "general_error falls through to message matching",
 			reason:   adapter.FailureReasonGeneralError,
@@ -181,6 +206,7 @@ func TestClassifyStepFailure_MessagePatterns(t *testing.T) {
 		{"context window", "context window full", FailureClassBudgetExhausted},
 		{"token limit", "token limit reached", FailureClassBudgetExhausted},
 		{"prompt is too long", "prompt is too long for model", FailureClassBudgetExhausted},
+		{"budget exceeded", "budget exceeded: Cost: $1.2000 (3 steps)", FailureClassBudgetExhausted},
 
 		// Default / unrecognized
 		{"unknown error defaults to transient", "something unexpected happened", FailureClassTransient},
@@ -420,3 +446,38 @@ func TestCircuitBreaker_ConcurrentSafety(t *testing.T) {
 	}
 	assert.Equal(t, goroutines*recordsPerGoroutine, totalCount, "total records should match")
 }
+
+func TestCategorizeStepFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		ctxErr   error
+		expected string
+	}{
+		{"nil inputs", nil, nil, ""},
+		{"context canceled", errors.New("boom"), context.Canceled, FailureCategoryCanceled},
+		{"context deadline", errors.New("boom"), context.DeadlineExceeded, FailureCategoryTimeout},
+		{"adapter timeout", adapter.NewStepError(adapter.FailureReasonTimeout, nil, 0, ""), nil, FailureCategoryTimeout},
+		{"adapter rate limit", adapter.NewStepError(adapter.FailureReasonRateLimit, nil, 0, ""), nil, FailureCategoryRateLimit},
+		{"adapter context exhaustion", adapter.NewStepError(adapter.FailureReasonContextExhaustion, nil, 0, ""), nil, FailureCategoryContextExhausted},
+		{"adapter quota", adapter.NewStepError(adapter.FailureReasonQuota, nil, 0, ""), nil, FailureCategoryQuota},
+		{"adapter auth", adapter.NewStepError(adapter.FailureReasonAuth, errors.New("permission denied"), 0, ""), nil, FailureCategoryAuth},
+		{"adapter tool denied", adapter.NewStepError(adapter.FailureReasonToolDenied, nil, 0, ""), nil, FailureCategorySandboxDenial},
+		{"adapter crash reason", adapter.NewStepError(adapter.FailureReasonCrash, nil, 0, ""), nil, FailureCategoryAdapterCrash},
+		{"adapter parse error", adapter.NewStepError(adapter.FailureReasonParseError, nil, 0, ""), nil, FailureCategoryParseError},
+		{"typed validation error", fmt.Errorf("wrap: %w", &contract.ValidationError{ContractType: "json_schema", Message: "bad"}), nil, FailureCategoryContractViolation},
+		{"contract message", errors.New("contract validation failed: missing field 'title'"), nil, FailureCategoryContractViolation},
+		{"missing artifact", errors.New("required artifact 'plan' from step 'navigate' not found"), nil, FailureCategoryMissingArtifact},
+		{"rate limit message", errors.New("adapter rate limited: too many requests"), nil, FailureCategoryRateLimit},
+		{"sandbox denial", errors.New("bwrap: operation not permitted"), nil, FailureCategorySandboxDenial},
+		{"adapter crash", errors.New("adapter execution failed: failed to start claude: exec format error"), nil, FailureCategoryAdapterCrash},
+		{"stall", errors.New("step stalled: no activity for 5m"), nil, FailureCategoryTimeout},
+		{"unknown", errors.New("something odd happened"), nil, FailureCategoryUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CategorizeStepFailure(tt.err, tt.ctxErr))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package pipeline
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/state"
+)
+
+// StepReliability is a step's attempt history judged against
+// runtime.flaky_steps.
+type StepReliability struct {
+	state.StepReliabilityRecord
+	FailureRate float64 // Share of runs the step failed in
+	RetryRate   float64 // Share of runs the step needed a retry in
+	Flaky       bool
+}
+
+// AssessStepReliability computes failure and retry rates for each record
+// and flags the steps that reach the configured thresholds. Steps with
+// fewer than min_runs runs are never flagged. Flaky steps sort first, then
+// by failure rate, retry rate and name.
+func AssessStepReliability(records []state.StepReliabilityRecord, cfg manifest.FlakyStepsConfig) []StepReliability {
+	out := make([]StepReliability, 0, len(records))
+	for _, r := range records {
+		s := StepReliability{StepReliabilityRecord: r}
+		if r.Runs > 0 {
+			s.FailureRate = float64(r.FailedRuns) / float64(r.Runs)
+			s.RetryRate = float64(r.RetriedRuns) / float64(r.Runs)
+		}
+		s.Flaky = r.Runs >= cfg.EffectiveMinRuns() &&
+			(s.FailureRate >= cfg.EffectiveFailureRate() || s.RetryRate >= cfg.EffectiveRetryRate())
+		out = append(out, s)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Flaky != b.Flaky {
+			return a.Flaky
+		}
+		if a.FailureRate != b.FailureRate {
+			return a.FailureRate > b.FailureRate
+		}
+		if a.RetryRate != b.RetryRate {
+			return a.RetryRate > b.RetryRate
+		}
+		if a.PipelineName != b.PipelineName {
+			return a.PipelineName < b.PipelineName
+		}
+		return a.StepID < b.StepID
+	})
+	return out
+}
+
+// Summary describes the step's history, e.g. "failed in 2 of 8 recent runs
+// (25%), retried in 5 (62%)".
+func (s StepReliability) Summary() string {
+	return fmt.Sprintf("failed in %d of %d recent runs (%.0f%%), retried in %d (%.0f%%)",
+		s.FailedRuns, s.Runs, s.FailureRate*100, s.RetriedRuns, s.RetryRate*100)
+}
+
+// flakyStep returns the history of stepID when runtime.flaky_steps has an
+// action and the step is flaky. The pipeline's history is loaded on first
+// use and kept for the rest of the run, so steps are judged by the runs
+// before this one.
+func (e *DefaultPipelineExecutor) flakyStep(execution *PipelineExecution, stepID string) (StepReliability, bool) {
+	if e.store == nil || execution.Manifest == nil || execution.Manifest.Runtime.FlakySteps.Action == "" {
+		return StepReliability{}, false
+	}
+	cfg := execution.Manifest.Runtime.FlakySteps
+
+	execution.mu.Lock()
+	defer execution.mu.Unlock()
+	if execution.FlakySteps == nil {
+		execution.FlakySteps = make(map[string]StepReliability)
+		records, err := e.store.ListStepReliability(execution.Pipeline.Metadata.Name, time.Now().Add(-cfg.Window()))
+		if err != nil {
+			return StepReliability{}, false
+		}
+		for _, s := range AssessStepReliability(records, cfg) {
+			if s.Flaky {
+				execution.FlakySteps[s.StepID] = s
+			}
+		}
+	}
+	s, ok := execution.FlakySteps[stepID]
+	return s, ok
+}
+
+// applyFlakyStepPolicy labels a flaky step in run output and, with action
+// "retry", returns the raised attempt count. Steps that are not flaky keep
+// maxAttempts.
+func (e *DefaultPipelineExecutor) applyFlakyStepPolicy(execution *PipelineExecution, step *Step, maxAttempts int) int {
+	s, ok := e.flakyStep(execution, step.ID)
+	if !ok {
+		return maxAttempts
+	}
+	cfg := execution.Manifest.Runtime.FlakySteps
+	msg := "flaky step: " + s.Summary()
+	if cfg.Action == manifest.FlakyActionRetry && maxAttempts < cfg.EffectiveMaxAttempts() {
+		maxAttempts = cfg.EffectiveMaxAttempts()
+		msg += fmt.Sprintf("; allowing %d attempts", maxAttempts)
+	}
+	e.emit(event.Event{
+		Timestamp:  time.Now(),
+		PipelineID: execution.Status.ID,
+		StepID:     step.ID,
+		State:      "warning",
+		Message:    msg,
+	})
+	return maxAttempts
+}
@@ -0,0 +1,91 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssessStepReliability(t *testing.T) {
+	records := []state.StepReliabilityRecord{
+		{PipelineName: "impl-issue", StepID: "plan", Runs: 10},
+		{PipelineName: "impl-issue", StepID: "implement", Runs: 10, FailedRuns: 1, RetriedRuns: 4},
+		{PipelineName: "impl-issue", StepID: "test", Runs: 10, FailedRuns: 3},
+		{PipelineName: "impl-issue", StepID: "review", Runs: 2, FailedRuns: 2},
+	}
+
+	got := AssessStepReliability(records, manifest.FlakyStepsConfig{})
+	require.Len(t, got, 4)
+
+	// Flaky steps first, ordered by failure rate.
+	assert.Equal(t, "test", got[0].StepID)
+	assert.True(t, got[0].Flaky)
+	assert.Equal(t, "implement", got[1].StepID)
+	assert.True(t, got[1].Flaky, "retry rate 40%% is over the 30%% default")
+	// Too few runs to judge, despite failing every time.
+	assert.Equal(t, "review", got[2].StepID)
+	assert.False(t, got[2].Flaky)
+	assert.False(t, got[3].Flaky)
+
+	assert.Equal(t, "failed in 1 of 10 recent runs (10%), retried in 4 (40%)", got[1].Summary())
+
+	strict := AssessStepReliability(records, manifest.FlakyStepsConfig{MinRuns: 2, FailureRate: 0.5, RetryRate: 0.5})
+	assert.Equal(t, "review", strict[0].StepID)
+	assert.True(t, strict[0].Flaky)
+	assert.False(t, strict[1].Flaky)
+}
+
+func TestExecuteStep_FlakyStepRetry(t *testing.T) {
+	failAdapter := newCountingFailAdapter(1, errors.New("transient failure"))
+	collector := testutil.NewEventCollector()
+	var queried string
+	store := testutil.NewMockStateStore(testutil.WithListStepReliability(func(pipelineName string, since time.Time) ([]state.StepReliabilityRecord, error) {
+		queried = pipelineName
+		return []state.StepReliabilityRecord{
+			{PipelineName: pipelineName, StepID: "step-1", Runs: 6, FailedRuns: 1, RetriedRuns: 3},
+			{PipelineName: pipelineName, StepID: "step-2", Runs: 6},
+		}, nil
+	}))
+
+	executor := NewDefaultPipelineExecutor(failAdapter,
+		WithEmitter(collector),
+		WithStateStore(store),
+	)
+
+	m := testutil.CreateTestManifest(t.TempDir())
+	m.Runtime.FlakySteps = manifest.FlakyStepsConfig{Action: manifest.FlakyActionRetry, MaxAttempts: 2}
+
+	// step-1 has no retry policy of its own; the flaky-step policy gives it
+	// the second attempt it needs.
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "flaky-test"},
+		Steps: []Step{
+			{ID: "step-1", Persona: "navigator", Exec: ExecConfig{Source: "do something"}},
+			{ID: "step-2", Persona: "navigator", Exec: ExecConfig{Source: "do something else"}, Dependencies: []string{"step-1"}},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	require.NoError(t, executor.Execute(ctx, p, m, "test input"))
+	assert.Equal(t, 3, failAdapter.getCallCount())
+	assert.Equal(t, "flaky-test", queried)
+
+	var warnings []string
+	for _, e := range collector.GetEvents() {
+		if e.State == "warning" && strings.HasPrefix(e.Message, "flaky step") {
+			warnings = append(warnings, e.StepID+": "+e.Message)
+		}
+	}
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "step-1: flaky step: failed in 1 of 6 recent runs (17%), retried in 3 (50%); allowing 2 attempts", warnings[0])
+}
@@ -139,6 +139,7 @@ func (fm *ForkManager) copyCheckpoints(sourceRunID, newRunID string, maxStepInde
 			WorkspacePath:      cp.WorkspacePath,
 			WorkspaceCommitSHA: cp.WorkspaceCommitSHA,
 			ArtifactSnapshot:   cp.ArtifactSnapshot,
+			StepResults:        cp.StepResults,
 		}
 		if err := fm.store.SaveCheckpoint(newCP); err != nil {
 			return fmt.Errorf("failed to copy checkpoint for step %s: %w", cp.StepID, err)
@@ -128,10 +128,15 @@ func (g *GateExecutor) executeApproval(ctx context.Context, gate *GateConfig) (*
 				return nil, fmt.Errorf("gate prompt failed: %w", err)
 			}
 
+			msg := fmt.Sprintf("gate resolved: %s (%s)", decision.Label, decision.Choice)
+			if decision.Approver != "" {
+				msg += " by " + decision.Approver
+			}
 			g.emit(event.Event{
 				Timestamp: time.Now(),
+				StepID:    gate.RuntimeStepID,
 				State:     event.StateGateResolved,
-				Message:   fmt.Sprintf("gate resolved: %s (%s)", decision.Label, decision.Choice),
+				Message:   msg,
 			})
 
 			return decision, nil
@@ -173,6 +178,11 @@ func (g *GateExecutor) executeApproval(ctx context.Context, gate *GateConfig) (*
 		}
 	}
 
+	// Gates of a tracked run wait for `wave approve` instead.
+	if g.store != nil && gate.RuntimeRunID != "" && len(gate.Choices) == 0 {
+		return g.awaitApproval(ctx, gate, timeout)
+	}
+
 	// In non-interactive mode, wait for context cancellation or timeout.
 	select {
 	case <-ctx.Done():
@@ -19,6 +19,7 @@ type GateDecision struct {
 	Text      string    // Freeform text (empty if not provided)
 	Timestamp time.Time // When the decision was made
 	Target    string    // Resolved target step (from choice definition)
+	Approver  string    // Who decided, when the channel knows (e.g. "slack:alice")
 }
 
 // GateHandler is the interface for interaction channels that present gate
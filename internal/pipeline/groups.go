@@ -0,0 +1,112 @@
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/recinq/wave/internal/contract"
+	"github.com/recinq/wave/internal/manifest"
+)
+
+// StepGroup is one entry of the top-level groups section. Steps join a
+// group with `group: <id>` and inherit every field the group sets unless
+// they set it themselves:
+//
+//	groups:
+//	  - id: build
+//	    persona: craftsman
+//	    workspace: {ref: implement}
+//	    sandbox: {allowed_domains: [proxy.golang.org]}
+//	    contract: {type: test_suite, command: go test ./...}
+//	steps:
+//	  - id: implement
+//	    group: build
+//	    workspace: {type: worktree, branch: "{{ pipeline_id }}"}
+//	  - id: fix-lint
+//	    group: build
+//	    dependencies: [implement]
+//
+// A workspace ref is never applied to the step it points at. Groups also
+// render as one collapsible unit in the dashboard DAG and the TUI.
+type StepGroup struct {
+	ID          string                   `yaml:"id"`
+	Description string                   `yaml:"description,omitempty"`
+	Persona     string                   `yaml:"persona,omitempty"`
+	Workspace   *WorkspaceConfig         `yaml:"workspace,omitempty"`
+	Sandbox     *manifest.PersonaSandbox `yaml:"sandbox,omitempty"`
+	Contract    *contract.ContractConfig `yaml:"contract,omitempty"` // Default handover contract
+}
+
+// Group returns the group with the given ID, or nil.
+func (p *Pipeline) Group(id string) *StepGroup {
+	for i := range p.Groups {
+		if p.Groups[i].ID == id {
+			return &p.Groups[i]
+		}
+	}
+	return nil
+}
+
+// GroupSteps returns the IDs of the steps in group id, in pipeline order.
+func (p *Pipeline) GroupSteps(id string) []string {
+	var ids []string
+	for _, s := range p.Steps {
+		if s.Group == id {
+			ids = append(ids, s.ID)
+		}
+	}
+	return ids
+}
+
+// applyStepGroups copies each group's shared configuration onto its member
+// steps. It runs at load time, so the executor only ever sees plain steps.
+func applyStepGroups(p *Pipeline) error {
+	seen := make(map[string]bool, len(p.Groups))
+	for _, g := range p.Groups {
+		if g.ID == "" {
+			return fmt.Errorf("groups: every group needs an id")
+		}
+		if seen[g.ID] {
+			return fmt.Errorf("groups: duplicate group id %q", g.ID)
+		}
+		seen[g.ID] = true
+		if g.Workspace != nil {
+			if err := g.Workspace.Validate(); err != nil {
+				return fmt.Errorf("group %q: %w", g.ID, err)
+			}
+		}
+	}
+
+	for i := range p.Steps {
+		step := &p.Steps[i]
+		if step.Group == "" {
+			continue
+		}
+		g := p.Group(step.Group)
+		if g == nil {
+			return fmt.Errorf("step %q references unknown group %q", step.ID, step.Group)
+		}
+		if step.Persona == "" && !step.IsCompositionStep() && step.Type != StepTypeCommand && step.Type != StepTypeConditional && step.Type != StepTypeTestImpact {
+			step.Persona = g.Persona
+		}
+		if g.Workspace != nil && workspaceUnset(step.Workspace) && g.Workspace.Ref != step.ID {
+			ws := *g.Workspace
+			ws.Mount = append([]Mount(nil), g.Workspace.Mount...)
+			ws.Scope = append([]string(nil), g.Workspace.Scope...)
+			step.Workspace = ws
+		}
+		if g.Sandbox != nil && step.Sandbox == nil {
+			sb := manifest.PersonaSandbox{AllowedDomains: append([]string(nil), g.Sandbox.AllowedDomains...)}
+			step.Sandbox = &sb
+		}
+		if g.Contract != nil && len(step.Handover.EffectiveContracts()) == 0 {
+			step.Handover.Contract = *g.Contract
+		}
+	}
+	return nil
+}
+
+// workspaceUnset reports whether a step declared no workspace at all.
+func workspaceUnset(w WorkspaceConfig) bool {
+	return w.Root == "" && w.Type == "" && w.Ref == "" && w.Branch == "" && w.Base == "" &&
+		len(w.Mount) == 0 && len(w.Scope) == 0 && w.Submodules == "" && !w.LFS
+}
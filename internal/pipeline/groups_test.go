@@ -0,0 +1,141 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestYAMLPipelineLoader_StepGroups(t *testing.T) {
+	yamlContent := []byte(`kind: WavePipeline
+metadata:
+  name: grouped
+groups:
+  - id: build
+    persona: craftsman
+    workspace:
+      ref: implement
+    sandbox:
+      allowed_domains: [proxy.golang.org]
+    contract:
+      type: test_suite
+      command: go test ./...
+steps:
+  - id: implement
+    group: build
+    workspace:
+      type: worktree
+      branch: feature
+    exec: {type: prompt, source: implement}
+  - id: fix-lint
+    group: build
+    persona: linter
+    dependencies: [implement]
+    sandbox:
+      allowed_domains: []
+    handover:
+      contract: {type: json_schema, source: out.json}
+    exec: {type: prompt, source: lint}
+  - id: check
+    group: build
+    type: command
+    script: make check
+    dependencies: [fix-lint]
+  - id: review
+    persona: reviewer
+    dependencies: [check]
+    exec: {type: prompt, source: review}
+`)
+
+	p, err := (&YAMLPipelineLoader{}).Unmarshal(yamlContent)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	implement, lint, check, review := p.Steps[0], p.Steps[1], p.Steps[2], p.Steps[3]
+	if implement.Persona != "craftsman" {
+		t.Errorf("implement persona = %q, want group persona", implement.Persona)
+	}
+	if implement.Workspace.Type != "worktree" || implement.Workspace.Ref != "" {
+		t.Errorf("implement workspace = %+v, want its own worktree", implement.Workspace)
+	}
+	if implement.Sandbox == nil || len(implement.Sandbox.AllowedDomains) != 1 {
+		t.Errorf("implement sandbox = %+v, want group sandbox", implement.Sandbox)
+	}
+	if implement.Handover.Contract.Type != "test_suite" {
+		t.Errorf("implement contract = %q, want group contract", implement.Handover.Contract.Type)
+	}
+
+	if lint.Persona != "linter" || lint.Handover.Contract.Type != "json_schema" {
+		t.Errorf("fix-lint lost its own fields: persona=%q contract=%q", lint.Persona, lint.Handover.Contract.Type)
+	}
+	if lint.Workspace.Ref != "implement" {
+		t.Errorf("fix-lint workspace ref = %q, want implement", lint.Workspace.Ref)
+	}
+	if lint.Sandbox == nil || len(lint.Sandbox.AllowedDomains) != 0 {
+		t.Errorf("fix-lint sandbox = %+v, want its own empty allow list", lint.Sandbox)
+	}
+
+	if check.Persona != "" {
+		t.Errorf("command step got persona %q", check.Persona)
+	}
+	if review.Persona != "reviewer" || review.Handover.Contract.Type != "" || review.Workspace.Ref != "" {
+		t.Errorf("ungrouped step picked up group settings: %+v", review)
+	}
+
+	if got := strings.Join(p.GroupSteps("build"), ","); got != "implement,fix-lint,check" {
+		t.Errorf("GroupSteps(build) = %s", got)
+	}
+}
+
+func TestYAMLPipelineLoader_StepGroupErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "unknown group",
+			yaml: `groups:
+  - id: build
+steps:
+  - id: a
+    group: biuld
+    persona: p
+    exec: {type: prompt, source: x}
+`,
+			wantErr: `unknown group "biuld"`,
+		},
+		{
+			name: "duplicate group",
+			yaml: `groups:
+  - id: build
+  - id: build
+steps:
+  - id: a
+    persona: p
+    exec: {type: prompt, source: x}
+`,
+			wantErr: `duplicate group id "build"`,
+		},
+		{
+			name: "invalid workspace",
+			yaml: `groups:
+  - id: build
+    workspace: {scope: [internal]}
+steps:
+  - id: a
+    persona: p
+    exec: {type: prompt, source: x}
+`,
+			wantErr: `group "build"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&YAMLPipelineLoader{}).Unmarshal([]byte("kind: WavePipeline\nmetadata:\n  name: g\n" + tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Unmarshal() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// inputTemplateName matches names usable as `wave run` flags.
+var inputTemplateName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// inputTemplateValue matches the {{ value }} placeholder in an input template.
+var inputTemplateValue = regexp.MustCompile(`\{\{\s*value\s*\}\}`)
+
+// ExpandInputTemplate builds the run input from the named input template,
+// substituting value for {{ value }}. It reports false when the pipeline
+// declares no template of that name.
+func (p *Pipeline) ExpandInputTemplate(name, value string) (string, bool) {
+	tmpl, ok := p.InputTemplates[name]
+	if !ok {
+		return "", false
+	}
+	return inputTemplateValue.ReplaceAllLiteralString(tmpl, value), true
+}
+
+// IsInputTemplateName reports whether name can be used as an input
+// template, and so as a `wave run` flag.
+func IsInputTemplateName(name string) bool {
+	return inputTemplateName.MatchString(name)
+}
+
+// InputTemplateNames returns the declared input template names in order.
+func (p *Pipeline) InputTemplateNames() []string {
+	names := make([]string, 0, len(p.InputTemplates))
+	for name := range p.InputTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateInputTemplates checks that template names are valid flag names
+// and that every template uses its value.
+func validateInputTemplates(p *Pipeline) error {
+	for _, name := range p.InputTemplateNames() {
+		if !IsInputTemplateName(name) {
+			return fmt.Errorf("input_templates: %q must be lowercase letters, digits and dashes, starting with a letter", name)
+		}
+		if !inputTemplateValue.MatchString(p.InputTemplates[name]) {
+			return fmt.Errorf("input_templates.%s: template must contain {{ value }}", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandInputTemplate(t *testing.T) {
+	p := &Pipeline{InputTemplates: map[string]string{
+		"issue": "Fix GitHub issue #{{ value }}",
+		"pr":    "Address review on PR {{value}} ({{ value }})",
+	}}
+
+	got, ok := p.ExpandInputTemplate("issue", "123")
+	require.True(t, ok)
+	assert.Equal(t, "Fix GitHub issue #123", got)
+
+	got, ok = p.ExpandInputTemplate("pr", "$1")
+	require.True(t, ok)
+	assert.Equal(t, "Address review on PR $1 ($1)", got)
+
+	_, ok = p.ExpandInputTemplate("branch", "main")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"issue", "pr"}, p.InputTemplateNames())
+}
+
+func TestValidateInputTemplates(t *testing.T) {
+	loader := &YAMLPipelineLoader{}
+	p, err := loader.Unmarshal([]byte(`kind: WavePipeline
+metadata:
+  name: fix
+input_templates:
+  issue: "Fix GitHub issue #{{ value }}"
+steps:
+  - id: a
+    persona: navigator
+    exec:
+      type: prompt
+      source: go
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "Fix GitHub issue #{{ value }}", p.InputTemplates["issue"])
+
+	v := &DAGValidator{}
+	require.NoError(t, v.ValidateDAG(p))
+
+	p.InputTemplates = map[string]string{"issue": "Fix the bug"}
+	assert.ErrorContains(t, v.ValidateDAG(p), "input_templates.issue: template must contain {{ value }}")
+
+	p.InputTemplates = map[string]string{"Issue_ID": "#{{ value }}"}
+	assert.ErrorContains(t, v.ValidateDAG(p), `input_templates: "Issue_ID"`)
+}
@@ -0,0 +1,52 @@
+package pipeline
+
+import (
+	"strings"
+
+	"github.com/recinq/wave/internal/state"
+)
+
+// MatchKnowledge returns the remediation notes of every knowledge base entry
+// that applies to a failure. An entry applies when its normalized pattern is
+// a substring of the normalized error message and each of its optional
+// scope fields (category, pipeline, step) is either empty or equal to the
+// failure's value.
+func MatchKnowledge(entries []state.KnowledgeEntry, pipelineName, stepID, category, errMsg string) []string {
+	if len(entries) == 0 || errMsg == "" {
+		return nil
+	}
+	signature := NormalizeErrorSignature(errMsg)
+
+	var notes []string
+	for _, entry := range entries {
+		if entry.Category != "" && entry.Category != category {
+			continue
+		}
+		if entry.PipelineName != "" && entry.PipelineName != pipelineName {
+			continue
+		}
+		if entry.StepID != "" && entry.StepID != stepID {
+			continue
+		}
+		pattern := strings.TrimSpace(NormalizeErrorSignature(entry.Pattern))
+		if pattern == "" || !strings.Contains(signature, pattern) {
+			continue
+		}
+		notes = append(notes, entry.Remediation)
+	}
+	return notes
+}
+
+// lookupKnownRemediations consults the project's error knowledge base for
+// notes matching a failed attempt. Store errors are swallowed — the
+// knowledge base is advisory and must never block a retry.
+func (e *DefaultPipelineExecutor) lookupKnownRemediations(execution *PipelineExecution, step *Step, category, errMsg string) []string {
+	if e.store == nil {
+		return nil
+	}
+	entries, err := e.store.ListKnowledgeEntries()
+	if err != nil {
+		return nil
+	}
+	return MatchKnowledge(entries, execution.Status.PipelineName, step.ID, category, errMsg)
+}
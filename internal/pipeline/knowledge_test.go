@@ -0,0 +1,140 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchKnowledge(t *testing.T) {
+	entries := []state.KnowledgeEntry{
+		{Pattern: "Module Lookup Disabled", Remediation: "global"},
+		{Pattern: "429", Category: FailureCategoryRateLimit, Remediation: "rate-limit only"},
+		{Pattern: "module lookup", PipelineName: "other-pipeline", Remediation: "other pipeline"},
+		{Pattern: "module lookup", StepID: "implement", Remediation: "implement step"},
+		{Pattern: "   ", Remediation: "blank pattern never matches"},
+	}
+
+	tests := []struct {
+		name     string
+		pipeline string
+		step     string
+		category string
+		errMsg   string
+		want     []string
+	}{
+		{
+			name:     "case-insensitive match with scoped step",
+			pipeline: "impl-issue",
+			step:     "implement",
+			category: FailureCategoryUnknown,
+			errMsg:   "go: MODULE LOOKUP DISABLED by GOFLAGS=-mod=vendor",
+			want:     []string{"global", "implement step"},
+		},
+		{
+			name:     "step scope excludes other steps",
+			pipeline: "impl-issue",
+			step:     "review",
+			errMsg:   "module lookup disabled",
+			want:     []string{"global"},
+		},
+		{
+			name:     "category scope",
+			pipeline: "impl-issue",
+			step:     "fetch",
+			category: FailureCategoryRateLimit,
+			errMsg:   "HTTP 429 Too Many Requests",
+			want:     []string{"rate-limit only"},
+		},
+		{
+			name:     "category mismatch",
+			pipeline: "impl-issue",
+			step:     "fetch",
+			category: FailureCategoryTimeout,
+			errMsg:   "HTTP 429 Too Many Requests",
+			want:     nil,
+		},
+		{
+			name:     "volatile substrings are normalized away",
+			pipeline: "impl-issue",
+			step:     "fetch",
+			errMsg:   "2026-03-28T14:30:22Z module lookup disabled",
+			want:     []string{"global"},
+		},
+		{
+			name:   "empty error",
+			errMsg: "",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MatchKnowledge(entries, tt.pipeline, tt.step, tt.category, tt.errMsg)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// knowledgeStore serves a fixed set of knowledge base entries.
+type knowledgeStore struct {
+	*testutil.MockStateStore
+	entries []state.KnowledgeEntry
+}
+
+func (s *knowledgeStore) ListKnowledgeEntries() ([]state.KnowledgeEntry, error) {
+	return s.entries, nil
+}
+
+func TestExecuteStep_InjectsKnownRemediationsOnRetry(t *testing.T) {
+	failAdapter := newCountingFailAdapter(1, errors.New("go: module lookup disabled by GOFLAGS"))
+	store := &knowledgeStore{
+		MockStateStore: testutil.NewMockStateStore(),
+		entries: []state.KnowledgeEntry{
+			{Pattern: "module lookup disabled", Remediation: "Run go mod download before building"},
+			{Pattern: "module lookup disabled", StepID: "other-step", Remediation: "scoped elsewhere"},
+		},
+	}
+
+	executor := NewDefaultPipelineExecutor(failAdapter,
+		WithEmitter(testutil.NewEventCollector()),
+		WithStateStore(store),
+	)
+
+	tmpDir := t.TempDir()
+	m := testutil.CreateTestManifest(tmpDir)
+
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "kb-test"},
+		Steps: []Step{
+			{
+				ID:      "step-1",
+				Persona: "navigator",
+				Exec:    ExecConfig{Source: "build the project"},
+				Retry: RetryConfig{
+					MaxAttempts: 2,
+					BaseDelay:   "1ms",
+					AdaptPrompt: true,
+				},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	require.NoError(t, executor.Execute(ctx, p, m, "test input"))
+
+	configs := failAdapter.getLastConfigs()
+	require.Len(t, configs, 2)
+	assert.NotContains(t, configs[0].Prompt, "Known Remediations")
+	assert.Contains(t, configs[1].Prompt, "### Known Remediations")
+	assert.Contains(t, configs[1].Prompt, "Run go mod download before building")
+	assert.NotContains(t, configs[1].Prompt, "scoped elsewhere")
+}
@@ -0,0 +1,178 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/state"
+)
+
+// DefaultLockTimeout is how long a step or pipeline waits for its resource
+// locks when lock_timeout is not set.
+const DefaultLockTimeout = 30 * time.Minute
+
+// lockPollInterval is how often a waiting run retries its locks. A
+// variable so tests can shorten it.
+var lockPollInterval = 2 * time.Second
+
+// validateLocks checks the locks and lock_timeout fields of the pipeline
+// and its steps.
+func validateLocks(p *Pipeline) error {
+	check := func(where string, locks []string, timeout string) error {
+		for _, name := range locks {
+			if name == "" {
+				return fmt.Errorf("%s: lock names must not be empty", where)
+			}
+		}
+		if timeout != "" {
+			d, err := time.ParseDuration(timeout)
+			if err != nil {
+				return fmt.Errorf("%s: invalid lock_timeout %q: %w", where, timeout, err)
+			}
+			if d <= 0 {
+				return fmt.Errorf("%s: lock_timeout must be positive, got %q", where, timeout)
+			}
+		}
+		return nil
+	}
+	if err := check("pipeline", p.Locks, p.LockTimeout); err != nil {
+		return err
+	}
+	for _, s := range p.Steps {
+		if err := check(fmt.Sprintf("step %q", s.ID), s.Locks, s.LockTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockTimeout returns the lock wait timeout of a step (or of the pipeline
+// when step is nil): the step's lock_timeout, else the pipeline's, else
+// DefaultLockTimeout. The fields are checked by validateLocks.
+func lockTimeout(p *Pipeline, step *Step) time.Duration {
+	for _, v := range []string{stepLockTimeout(step), p.LockTimeout} {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultLockTimeout
+}
+
+func stepLockTimeout(step *Step) string {
+	if step == nil {
+		return ""
+	}
+	return step.LockTimeout
+}
+
+// acquireLocks takes the resource locks of a step, or of the whole run
+// when step is nil, through the state store, so that concurrent runs
+// sharing the store never hold the same lock at once. It waits in the
+// lock's queue, reporting the holder while it waits, and fails once the
+// lock timeout passes. Locks are taken in name order, so two runs needing
+// the same set cannot deadlock. The returned function releases them.
+//
+// Without a state store there are no other runs to exclude and the locks
+// are not enforced.
+func (e *DefaultPipelineExecutor) acquireLocks(ctx context.Context, execution *PipelineExecution, step *Step) (func(), error) {
+	locks := execution.Pipeline.Locks
+	stepID := ""
+	if step != nil {
+		locks = step.Locks
+		stepID = step.ID
+	}
+	if len(locks) == 0 || e.store == nil {
+		return func() {}, nil
+	}
+	runID := execution.Status.ID
+	names := append([]string(nil), locks...)
+	sort.Strings(names)
+
+	var held []string
+	release := func() {
+		for _, name := range held {
+			_ = e.store.ReleaseResourceLock(name, runID, stepID)
+		}
+	}
+	timeout := lockTimeout(execution.Pipeline, step)
+	deadline := time.Now().Add(timeout)
+	for i, name := range names {
+		if i > 0 && name == names[i-1] {
+			continue
+		}
+		if err := e.waitForLock(ctx, execution, name, stepID, timeout, deadline); err != nil {
+			release()
+			return nil, err
+		}
+		held = append(held, name)
+	}
+	return release, nil
+}
+
+// waitForLock retries one resource lock until it is acquired, ctx is done
+// or deadline passes. A wait that ends without the lock leaves the queue.
+func (e *DefaultPipelineExecutor) waitForLock(ctx context.Context, execution *PipelineExecution, name, stepID string, timeout time.Duration, deadline time.Time) error {
+	runID := execution.Status.ID
+	var lastHolder string
+	for {
+		status, err := e.store.TryAcquireResourceLock(name, runID, stepID)
+		if err != nil {
+			_ = e.store.ReleaseResourceLock(name, runID, stepID)
+			return fmt.Errorf("failed to acquire lock %q: %w", name, err)
+		}
+		if status.Acquired {
+			if lastHolder != "" {
+				e.emit(event.Event{
+					Timestamp:  time.Now(),
+					PipelineID: runID,
+					StepID:     stepID,
+					State:      event.StateLockWaiting,
+					Message:    fmt.Sprintf("acquired lock %q", name),
+				})
+			}
+			return nil
+		}
+
+		holder := describeLockHolder(status.Holder)
+		if holder != lastHolder {
+			e.emit(event.Event{
+				Timestamp:  time.Now(),
+				PipelineID: runID,
+				StepID:     stepID,
+				State:      event.StateLockWaiting,
+				Message:    fmt.Sprintf("waiting for lock %q %s (position %d in queue)", name, holder, status.Position),
+			})
+			lastHolder = holder
+		}
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			_ = e.store.ReleaseResourceLock(name, runID, stepID)
+			return fmt.Errorf("timed out after %s waiting for lock %q %s", timeout, name, holder)
+		}
+		if wait > lockPollInterval {
+			wait = lockPollInterval
+		}
+		select {
+		case <-ctx.Done():
+			_ = e.store.ReleaseResourceLock(name, runID, stepID)
+			return fmt.Errorf("stopped waiting for lock %q: %w", name, ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+// describeLockHolder names who holds a lock for wait and timeout messages.
+func describeLockHolder(holder *state.ResourceLockRecord) string {
+	switch {
+	case holder == nil:
+		return "behind earlier waiters"
+	case holder.StepID == "":
+		return fmt.Sprintf("held by run %s", holder.RunID)
+	default:
+		return fmt.Sprintf("held by run %s step %s", holder.RunID, holder.StepID)
+	}
+}
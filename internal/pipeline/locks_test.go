@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLocks(t *testing.T) {
+	ok := &Pipeline{
+		Locks:       []string{"deploy"},
+		LockTimeout: "10m",
+		Steps:       []Step{{ID: "migrate", Locks: []string{"database-migrations"}, LockTimeout: "90s"}},
+	}
+	require.NoError(t, validateLocks(ok))
+	assert.Equal(t, 90*time.Second, lockTimeout(ok, &ok.Steps[0]))
+	assert.Equal(t, 10*time.Minute, lockTimeout(ok, nil))
+	assert.Equal(t, DefaultLockTimeout, lockTimeout(&Pipeline{}, &Step{}))
+
+	assert.ErrorContains(t, validateLocks(&Pipeline{Locks: []string{""}}), "must not be empty")
+	assert.ErrorContains(t, validateLocks(&Pipeline{Steps: []Step{{ID: "a", LockTimeout: "soon"}}}), `step "a": invalid lock_timeout`)
+}
+
+// lockQueue is a TryAcquireResourceLock stub that reports the lock as held
+// by another run for the first busy attempts.
+type lockQueue struct {
+	mu       sync.Mutex
+	busy     int
+	attempts int
+}
+
+func (q *lockQueue) tryAcquire(name, runID, stepID string) (*state.ResourceLockStatus, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.attempts++
+	if q.attempts <= q.busy {
+		return &state.ResourceLockStatus{
+			Holder:   &state.ResourceLockRecord{Name: name, RunID: "other-run", StepID: "migrate"},
+			Position: 1,
+		}, nil
+	}
+	return &state.ResourceLockStatus{Acquired: true}, nil
+}
+
+func TestAcquireLocks(t *testing.T) {
+	defer func(d time.Duration) { lockPollInterval = d }(lockPollInterval)
+	lockPollInterval = time.Millisecond
+
+	execution := func(step Step) *PipelineExecution {
+		return &PipelineExecution{
+			Pipeline: &Pipeline{Steps: []Step{step}},
+			Status:   &PipelineStatus{ID: "run-1"},
+		}
+	}
+	step := Step{ID: "migrate", Locks: []string{"database-migrations"}, LockTimeout: "1s"}
+
+	t.Run("waits for the holder", func(t *testing.T) {
+		q := &lockQueue{busy: 3}
+		collector := testutil.NewEventCollector()
+		executor := NewDefaultPipelineExecutor(nil,
+			WithEmitter(collector),
+			WithStateStore(testutil.NewMockStateStore(testutil.WithTryAcquireResourceLock(q.tryAcquire))))
+
+		release, err := executor.acquireLocks(context.Background(), execution(step), &step)
+		require.NoError(t, err)
+		release()
+		assert.Equal(t, 4, q.attempts)
+
+		var messages []string
+		for _, evt := range collector.GetEvents() {
+			if evt.State == event.StateLockWaiting {
+				messages = append(messages, evt.Message)
+			}
+		}
+		assert.Equal(t, []string{
+			`waiting for lock "database-migrations" held by run other-run step migrate (position 1 in queue)`,
+			`acquired lock "database-migrations"`,
+		}, messages)
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		step := step
+		step.LockTimeout = "20ms"
+		q := &lockQueue{busy: 1 << 30}
+		executor := NewDefaultPipelineExecutor(nil,
+			WithEmitter(testutil.NewEventCollector()),
+			WithStateStore(testutil.NewMockStateStore(testutil.WithTryAcquireResourceLock(q.tryAcquire))))
+
+		_, err := executor.acquireLocks(context.Background(), execution(step), &step)
+		assert.EqualError(t, err, `timed out after 20ms waiting for lock "database-migrations" held by run other-run step migrate`)
+	})
+
+	t.Run("no locks without a store", func(t *testing.T) {
+		executor := NewDefaultPipelineExecutor(nil)
+		release, err := executor.acquireLocks(context.Background(), execution(step), &step)
+		require.NoError(t, err)
+		release()
+	})
+}
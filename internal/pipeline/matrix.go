@@ -30,6 +30,7 @@ type MatrixResult struct {
 	SkipReason    string
 	ItemID        string
 	OutputBranch  string
+	Attempts      int
 }
 
 // TierContext tracks accumulated branch state during stacked tier execution.
@@ -153,21 +154,26 @@ func (m *MatrixExecutor) Execute(ctx context.Context, execution *PipelineExecuti
 		})
 		worker = m.childPipelineWorker(childPipeline)
 	}
+	if strategy.ItemRetry != nil {
+		retry := *strategy.ItemRetry
+		if err := retry.ResolvePolicy(); err != nil {
+			return fmt.Errorf("step %q strategy.item_retry: %w", step.ID, err)
+		}
+		worker = m.withItemRetry(worker, retry)
+	}
 
 	// Branch to tiered execution when dependency_key is configured
 	if strategy.DependencyKey != "" {
 		return m.tieredExecution(ctx, execution, step, items, worker)
 	}
 
-	// Determine max concurrency
-	maxConcurrency := strategy.MaxConcurrency
-	if maxConcurrency <= 0 {
-		maxConcurrency = len(items) // No limit, run all in parallel
+	// Bound the worker pool. A fail-fast group cancels the running items
+	// once one fails; otherwise every item runs to completion.
+	g, gctx := &errgroup.Group{}, ctx
+	if strategy.failFast() {
+		g, gctx = errgroup.WithContext(ctx)
 	}
-
-	// Create errgroup with concurrency limit
-	g, gctx := errgroup.WithContext(ctx)
-	g.SetLimit(maxConcurrency)
+	g.SetLimit(strategy.workerLimit(len(items)))
 
 	// Channel to collect results
 	resultsChan := make(chan MatrixResult, len(items))
@@ -179,6 +185,9 @@ func (m *MatrixExecutor) Execute(ctx context.Context, execution *PipelineExecuti
 
 		g.Go(func() error {
 			result := worker(gctx, execution, step, itemIndex, itemValue)
+			if strategy.ItemIDKey != "" {
+				result.ItemID, _ = m.extractItemID(itemValue, strategy.ItemIDKey)
+			}
 			resultsChan <- result
 			if result.Error != nil {
 				return result.Error
@@ -211,6 +220,7 @@ func (m *MatrixExecutor) Execute(ctx context.Context, execution *PipelineExecuti
 
 	// Aggregate results into execution
 	m.aggregateResults(execution, step, results)
+	m.writeSummary(execution, step, results)
 
 	// T082: Improved partial failure reporting
 	if err != nil {
@@ -308,13 +318,45 @@ func (m *MatrixExecutor) formatFailedWorkerErrors(failed []FailedWorkerInfo) str
 	return result
 }
 
+// itemsSourceRef parses an items_source that names a prior step's output
+// artifact, {{ steps.<step-id>.artifacts.<name> }} (".artifact." is accepted
+// too). isRef is false for plain file paths.
+func itemsSourceRef(source string) (stepID, artifact string, isRef bool, err error) {
+	source = strings.TrimSpace(source)
+	if !strings.Contains(source, "{{") {
+		return "", "", false, nil
+	}
+	m := templatePattern.FindStringSubmatch(source)
+	if m == nil || m[0] != source {
+		return "", "", true, fmt.Errorf("items_source %q must be a single {{ steps.<step-id>.artifacts.<name> }} reference", source)
+	}
+	parts := strings.Split(strings.TrimSpace(m[1]), ".")
+	if len(parts) != 4 || parts[0] != "steps" || (parts[2] != "artifacts" && parts[2] != "artifact") || parts[1] == "" || parts[3] == "" {
+		return "", "", true, fmt.Errorf("items_source %q must be a single {{ steps.<step-id>.artifacts.<name> }} reference; use item_key to select a nested array", source)
+	}
+	return parts[1], parts[3], true, nil
+}
+
 // readItemsSource reads and parses the items_source JSON file.
-// The path format is "<step_id>/<artifact_path>" or an absolute path.
+// The source is a {{ steps.<step-id>.artifacts.<name> }} reference to a
+// prior step's output artifact, "<step_id>/<artifact_path>" or a path.
 func (m *MatrixExecutor) readItemsSource(execution *PipelineExecution, strategy *MatrixStrategy) ([]interface{}, error) {
 	itemsSourcePath := strategy.ItemsSource
 
-	// Check if it's a reference to a previous step's artifact (format: "step_id/artifact_path")
-	if !filepath.IsAbs(itemsSourcePath) && strings.Contains(itemsSourcePath, "/") {
+	stepID, artifact, isRef, err := itemsSourceRef(itemsSourcePath)
+	if err != nil {
+		return nil, err
+	}
+	if isRef {
+		execution.mu.Lock()
+		artPath, ok := execution.ArtifactPaths[stepID+":"+artifact]
+		execution.mu.Unlock()
+		if !ok {
+			return nil, fmt.Errorf("items_source: artifact %q from step %q not found (step may not have completed yet)", artifact, stepID)
+		}
+		itemsSourcePath = artPath
+	} else if !filepath.IsAbs(itemsSourcePath) && strings.Contains(itemsSourcePath, "/") {
+		// Reference to a previous step's artifact (format: "step_id/artifact_path")
 		parts := strings.SplitN(itemsSourcePath, "/", 2)
 		if len(parts) == 2 {
 			stepID := parts[0]
@@ -361,7 +403,58 @@ func (m *MatrixExecutor) readItemsSource(execution *PipelineExecution, strategy
 		return nil, fmt.Errorf("items_source must be a JSON array, got %T", rawData)
 	}
 
-	return items, nil
+	selected, err := selectMatrixItems(items, strategy)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) < len(items) && strategy.ItemIDKey != "" && strategy.DependencyKey != "" {
+		m.pruneUnselectedDependencies(items, selected, strategy)
+	}
+	return selected, nil
+}
+
+// pruneUnselectedDependencies drops dependency_key references to items the
+// filter or limit left out, so a subset's tiering treats them as handled
+// elsewhere instead of failing with a missing dependency. References to IDs
+// absent from the whole source are kept and still reported.
+func (m *MatrixExecutor) pruneUnselectedDependencies(all, selected []interface{}, strategy *MatrixStrategy) {
+	kept := make(map[string]bool, len(selected))
+	for _, item := range selected {
+		if id, err := m.extractItemID(item, strategy.ItemIDKey); err == nil {
+			kept[id] = true
+		}
+	}
+	dropped := make(map[string]bool)
+	for _, item := range all {
+		if id, err := m.extractItemID(item, strategy.ItemIDKey); err == nil && !kept[id] {
+			dropped[id] = true
+		}
+	}
+	parentKey, leaf := "", strategy.DependencyKey
+	if i := strings.LastIndex(leaf, "."); i >= 0 {
+		parentKey, leaf = leaf[:i], leaf[i+1:]
+	}
+	for _, item := range selected {
+		parent, err := m.extractByKey(item, parentKey)
+		if err != nil {
+			continue
+		}
+		obj, ok := parent.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		deps, ok := obj[leaf].([]interface{})
+		if !ok {
+			continue
+		}
+		remaining := make([]interface{}, 0, len(deps))
+		for _, d := range deps {
+			if !dropped[dependencyID(d)] {
+				remaining = append(remaining, d)
+			}
+		}
+		obj[leaf] = remaining
+	}
 }
 
 // extractByKey extracts a nested value from data using a dot-separated key path.
@@ -443,6 +536,8 @@ func (m *MatrixExecutor) executeWorker(ctx context.Context, execution *PipelineE
 		Input:          execution.Input,
 		Status:         execution.Status,
 		Context:        execution.Context, // Fix: Copy context to prevent nil pointer dereference
+
+		adapterLogWorker: fmt.Sprintf("worker_%d", itemIndex),
 	}
 
 	// Copy artifact paths from parent execution
@@ -451,6 +546,7 @@ func (m *MatrixExecutor) executeWorker(ctx context.Context, execution *PipelineE
 		workerExecution.ArtifactPaths[k] = v
 	}
 	execution.mu.Unlock()
+	copyArtifactChecksums(workerExecution, execution)
 
 	// Run the step execution
 	err = m.executor.runStepExecution(ctx, workerExecution, workerStep)
@@ -462,6 +558,7 @@ func (m *MatrixExecutor) executeWorker(ctx context.Context, execution *PipelineE
 			StepID:     step.ID,
 			State:      "matrix_worker_failed",
 			Message:    fmt.Sprintf("Worker %d failed: %v", itemIndex, err),
+			LogPath:    adapterLogRef(workerExecution, step.ID),
 		})
 		return result
 	}
@@ -611,18 +708,23 @@ func (m *MatrixExecutor) aggregateResults(execution *PipelineExecution, step *St
 	aggregated["worker_workspaces"] = workerPaths
 	aggregated["total_workers"] = len(results)
 
-	// Count successes and failures
+	// Count successes, failures and skipped items
 	successCount := 0
 	failCount := 0
+	skipCount := 0
 	for _, result := range results {
-		if result.Error == nil {
-			successCount++
-		} else {
+		switch {
+		case result.Skipped:
+			skipCount++
+		case result.Error != nil:
 			failCount++
+		default:
+			successCount++
 		}
 	}
 	aggregated["success_count"] = successCount
 	aggregated["fail_count"] = failCount
+	aggregated["skip_count"] = skipCount
 
 	execution.mu.Lock()
 	execution.Results[step.ID] = aggregated
@@ -705,10 +807,7 @@ func (m *MatrixExecutor) tieredExecution(ctx context.Context, execution *Pipelin
 		Message:    fmt.Sprintf("Computed %d execution tiers for %d items", len(tiers), len(items)),
 	})
 
-	maxConcurrency := strategy.MaxConcurrency
-	if maxConcurrency <= 0 {
-		maxConcurrency = len(items)
-	}
+	maxConcurrency := strategy.workerLimit(len(items))
 
 	allResults := make([]MatrixResult, 0, len(items))
 	failed := make(map[string]bool)    // IDs of items that failed
@@ -909,6 +1008,7 @@ func (m *MatrixExecutor) tieredExecution(ctx context.Context, execution *Pipelin
 
 	// Aggregate all results
 	m.aggregateResults(execution, step, allResults)
+	m.writeSummary(execution, step, allResults)
 
 	// Count outcomes
 	skipCount := 0
@@ -925,15 +1025,6 @@ func (m *MatrixExecutor) tieredExecution(ctx context.Context, execution *Pipelin
 		}
 	}
 
-	// Override counts in aggregated results — aggregateResults does not distinguish skipped items
-	execution.mu.Lock()
-	if results, ok := execution.Results[step.ID]; ok {
-		results["skip_count"] = skipCount
-		results["success_count"] = successCount
-		results["fail_count"] = failCount
-	}
-	execution.mu.Unlock()
-
 	if failCount > 0 {
 		failedWorkers := m.collectFailedWorkers(allResults)
 		failureMsg := m.buildPartialFailureMessage(failedWorkers, successCount, len(items))
@@ -996,22 +1087,26 @@ func (m *MatrixExecutor) extractDependencies(item interface{}, key string) ([]st
 	}
 	result := make([]string, 0, len(arr))
 	for _, v := range arr {
-		switch dv := v.(type) {
-		case string:
-			result = append(result, dv)
-		case float64:
-			if dv == float64(int(dv)) {
-				result = append(result, fmt.Sprintf("%d", int(dv)))
-			} else {
-				result = append(result, fmt.Sprintf("%g", dv))
-			}
-		default:
-			result = append(result, fmt.Sprintf("%v", v))
-		}
+		result = append(result, dependencyID(v))
 	}
 	return result, nil
 }
 
+// dependencyID renders a dependency_key entry as an item ID.
+func dependencyID(v interface{}) string {
+	switch dv := v.(type) {
+	case string:
+		return dv
+	case float64:
+		if dv == float64(int(dv)) {
+			return fmt.Sprintf("%d", int(dv))
+		}
+		return fmt.Sprintf("%g", dv)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
 // computeTiers uses Kahn's algorithm (BFS topological sort) to group items
 // into execution tiers. Returns an error if the graph has cycles.
 func (m *MatrixExecutor) computeTiers(idToIndex map[string]int, deps map[string][]string) ([][]string, error) {
@@ -1193,7 +1288,7 @@ func (m *MatrixExecutor) createIntegrationBranch(repoRoot, pipelineID, itemID st
 		return "", fmt.Errorf("no parent branches to merge")
 	}
 
-	branchName := fmt.Sprintf("integration/%s/%s", pipelineID, itemID)
+	branchName := SanitizeGitRef(fmt.Sprintf("integration/%s/%s", pipelineID, itemID))
 
 	// Create branch from first parent
 	cmd := exec.Command("git", "checkout", "-b", branchName, parentBranches[0])
@@ -0,0 +1,394 @@
+package pipeline
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// itemExpr is a parsed matrix item expression such as
+// `item.size != 'xl' && (item.priority >= 2 || item.urgent)`.
+//
+// Grammar:
+//
+//	or    := and ('||' and)*
+//	and   := unary ('&&' unary)*
+//	unary := '!' unary | cmp
+//	cmp   := value (('==' | '!=' | '<' | '<=' | '>' | '>=') value)?
+//	value := '(' or ')' | string | number | true | false | null | item[.key]*
+//
+// Missing keys evaluate to null. Numbers compare numerically, strings
+// lexically; ordering anything else is an error.
+type itemExpr interface {
+	eval(item interface{}) (interface{}, error)
+}
+
+type (
+	exprLiteral struct{ v interface{} }
+	exprPath    struct{ keys []string }
+	exprNot     struct{ x itemExpr }
+	exprLogic   struct {
+		op   string
+		l, r itemExpr
+	}
+	exprCompare struct {
+		op   string
+		l, r itemExpr
+	}
+)
+
+// parseItemExpr parses a matrix filter or sort_by expression.
+func parseItemExpr(src string) (itemExpr, error) {
+	toks, err := tokenizeItemExpr(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", src, err)
+	}
+	p := &itemExprParser{toks: toks}
+	e, err := p.parseOr()
+	if err == nil && p.pos < len(p.toks) {
+		err = fmt.Errorf("unexpected %q", p.toks[p.pos])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", src, err)
+	}
+	return e, nil
+}
+
+func tokenizeItemExpr(src string) ([]string, error) {
+	var toks []string
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			toks = append(toks, src[i:i+end+2])
+			i += end + 2
+		case strings.HasPrefix(src[i:], "&&"), strings.HasPrefix(src[i:], "||"),
+			strings.HasPrefix(src[i:], "=="), strings.HasPrefix(src[i:], "!="),
+			strings.HasPrefix(src[i:], "<="), strings.HasPrefix(src[i:], ">="):
+			toks = append(toks, src[i:i+2])
+			i += 2
+		case strings.ContainsRune("()!<>", rune(c)):
+			toks = append(toks, string(c))
+			i++
+		case c == '-' || c == '.' || c == '_' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)):
+			j := i + 1
+			for j < len(src) && (src[j] == '.' || src[j] == '_' || src[j] == '-' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			toks = append(toks, src[i:j])
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	return toks, nil
+}
+
+type itemExprParser struct {
+	toks []string
+	pos  int
+}
+
+func (p *itemExprParser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+func (p *itemExprParser) parseOr() (itemExpr, error) {
+	l, err := p.parseAnd()
+	for err == nil && p.peek() == "||" {
+		p.pos++
+		var r itemExpr
+		if r, err = p.parseAnd(); err == nil {
+			l = exprLogic{op: "||", l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *itemExprParser) parseAnd() (itemExpr, error) {
+	l, err := p.parseUnary()
+	for err == nil && p.peek() == "&&" {
+		p.pos++
+		var r itemExpr
+		if r, err = p.parseUnary(); err == nil {
+			l = exprLogic{op: "&&", l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *itemExprParser) parseUnary() (itemExpr, error) {
+	if p.peek() == "!" {
+		p.pos++
+		x, err := p.parseUnary()
+		return exprNot{x: x}, err
+	}
+	l, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	switch op := p.peek(); op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.pos++
+		r, err := p.parseValue()
+		return exprCompare{op: op, l: l, r: r}, err
+	}
+	return l, nil
+}
+
+func (p *itemExprParser) parseValue() (itemExpr, error) {
+	tok := p.peek()
+	if tok == "" {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	p.pos++
+	switch {
+	case tok == "(":
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		p.pos++
+		return e, nil
+	case tok[0] == '\'' || tok[0] == '"':
+		return exprLiteral{v: tok[1 : len(tok)-1]}, nil
+	case tok == "true", tok == "false":
+		return exprLiteral{v: tok == "true"}, nil
+	case tok == "null":
+		return exprLiteral{v: nil}, nil
+	case tok == "item" || strings.HasPrefix(tok, "item."):
+		var keys []string
+		if tok != "item" {
+			keys = strings.Split(strings.TrimPrefix(tok, "item."), ".")
+		}
+		return exprPath{keys: keys}, nil
+	}
+	if f, err := strconv.ParseFloat(tok, 64); err == nil {
+		return exprLiteral{v: f}, nil
+	}
+	return nil, fmt.Errorf("unknown identifier %q (paths must start with 'item', strings must be quoted)", tok)
+}
+
+func (e exprLiteral) eval(interface{}) (interface{}, error) { return e.v, nil }
+
+func (e exprPath) eval(item interface{}) (interface{}, error) {
+	cur := item
+	for _, k := range e.keys {
+		switch v := cur.(type) {
+		case map[string]interface{}:
+			cur = v[k]
+		case []interface{}:
+			i, err := strconv.Atoi(k)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, nil
+			}
+			cur = v[i]
+		default:
+			return nil, nil
+		}
+	}
+	return cur, nil
+}
+
+func (e exprNot) eval(item interface{}) (interface{}, error) {
+	v, err := e.x.eval(item)
+	return !truthy(v), err
+}
+
+func (e exprLogic) eval(item interface{}) (interface{}, error) {
+	l, err := e.l.eval(item)
+	if err != nil {
+		return nil, err
+	}
+	if truthy(l) == (e.op == "||") {
+		return e.op == "||", nil
+	}
+	r, err := e.r.eval(item)
+	return truthy(r), err
+}
+
+func (e exprCompare) eval(item interface{}) (interface{}, error) {
+	l, err := e.l.eval(item)
+	if err != nil {
+		return nil, err
+	}
+	r, err := e.r.eval(item)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "==":
+		return valuesEqual(l, r), nil
+	case "!=":
+		return !valuesEqual(l, r), nil
+	}
+	c, ok := compareValues(l, r)
+	if !ok {
+		return nil, fmt.Errorf("cannot order %T and %T with %s", l, r, e.op)
+	}
+	switch e.op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+func truthy(v interface{}) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case float64:
+		return x != 0
+	case string:
+		return x != ""
+	case []interface{}:
+		return len(x) > 0
+	case map[string]interface{}:
+		return len(x) > 0
+	}
+	return true
+}
+
+func valuesEqual(a, b interface{}) bool {
+	if c, ok := compareValues(a, b); ok {
+		return c == 0
+	}
+	switch a.(type) {
+	case nil, bool:
+		return a == b
+	}
+	return false
+}
+
+// compareValues orders two numbers or two strings.
+func compareValues(a, b interface{}) (int, bool) {
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	}
+	return 0, false
+}
+
+// selectMatrixItems applies the strategy's filter, sort_by/sort_order and
+// limit to items, in that order. Items whose filter fails to evaluate are an
+// error rather than silently dropped.
+func selectMatrixItems(items []interface{}, strategy *MatrixStrategy) ([]interface{}, error) {
+	if strategy.Filter != "" {
+		filter, err := parseItemExpr(strategy.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("filter: %w", err)
+		}
+		kept := items[:0:0]
+		for i, item := range items {
+			v, err := filter.eval(item)
+			if err != nil {
+				return nil, fmt.Errorf("filter on item %d: %w", i, err)
+			}
+			if truthy(v) {
+				kept = append(kept, item)
+			}
+		}
+		items = kept
+	}
+
+	if strategy.SortBy != "" {
+		key, err := parseItemExpr(strategy.SortBy)
+		if err != nil {
+			return nil, fmt.Errorf("sort_by: %w", err)
+		}
+		keys := make([]interface{}, len(items))
+		for i, item := range items {
+			if keys[i], err = key.eval(item); err != nil {
+				return nil, fmt.Errorf("sort_by on item %d: %w", i, err)
+			}
+		}
+		idx := make([]int, len(items))
+		for i := range idx {
+			idx[i] = i
+		}
+		desc := strategy.SortOrder == "desc"
+		sort.SliceStable(idx, func(a, b int) bool {
+			ka, kb := keys[idx[a]], keys[idx[b]]
+			// Items without a sortable key go last in either order.
+			if c, ok := compareValues(ka, kb); ok {
+				if desc {
+					return c > 0
+				}
+				return c < 0
+			}
+			_, aOK := compareValues(ka, ka)
+			_, bOK := compareValues(kb, kb)
+			return aOK && !bOK
+		})
+		sorted := make([]interface{}, len(items))
+		for i, j := range idx {
+			sorted[i] = items[j]
+		}
+		items = sorted
+	}
+
+	if strategy.Limit > 0 && len(items) > strategy.Limit {
+		items = items[:strategy.Limit]
+	}
+	return items, nil
+}
+
+// validateMatrixSelection checks the items_source reference and the
+// filter/sort/limit fields of a matrix strategy at load time.
+func validateMatrixSelection(stepID string, s *MatrixStrategy) error {
+	if _, _, _, err := itemsSourceRef(s.ItemsSource); err != nil {
+		return fmt.Errorf("step %q strategy.%w", stepID, err)
+	}
+	if s.Filter != "" {
+		if _, err := parseItemExpr(s.Filter); err != nil {
+			return fmt.Errorf("step %q strategy.filter: %w", stepID, err)
+		}
+	}
+	if s.SortBy != "" {
+		if _, err := parseItemExpr(s.SortBy); err != nil {
+			return fmt.Errorf("step %q strategy.sort_by: %w", stepID, err)
+		}
+	}
+	switch s.SortOrder {
+	case "", "asc", "desc":
+	default:
+		return fmt.Errorf("step %q strategy.sort_order must be asc or desc, got %q", stepID, s.SortOrder)
+	}
+	if s.Limit < 0 {
+		return fmt.Errorf("step %q strategy.limit must not be negative", stepID)
+	}
+	return nil
+}
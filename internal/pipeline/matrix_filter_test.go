@@ -0,0 +1,141 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestItemExpr_Eval(t *testing.T) {
+	var item interface{}
+	_ = json.Unmarshal([]byte(`{"size": "m", "priority": 3, "urgent": false, "labels": ["bug"], "owner": {"team": "core"}}`), &item)
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`item.size != 'xl'`, true},
+		{`item.size == "m"`, true},
+		{`item.priority >= 2 && item.priority < 5`, true},
+		{`item.priority > 3`, false},
+		{`item.urgent || item.owner.team == 'core'`, true},
+		{`!item.urgent`, true},
+		{`!(item.size == 'm' || item.urgent)`, false},
+		{`item.labels.0 == 'bug'`, true},
+		{`item.missing == null`, true},
+		{`item.missing`, false},
+		{`item.labels`, true},
+	}
+	for _, tt := range tests {
+		e, err := parseItemExpr(tt.expr)
+		if err != nil {
+			t.Errorf("parseItemExpr(%q): %v", tt.expr, err)
+			continue
+		}
+		v, err := e.eval(item)
+		if err != nil {
+			t.Errorf("eval(%q): %v", tt.expr, err)
+			continue
+		}
+		if truthy(v) != tt.want {
+			t.Errorf("eval(%q) = %v, want %v", tt.expr, v, tt.want)
+		}
+	}
+}
+
+func TestItemExpr_Errors(t *testing.T) {
+	for _, expr := range []string{`item.size != xl`, `item.size ==`, `(item.a`, `item.a == 'x`, `item.a # 1`} {
+		if _, err := parseItemExpr(expr); err == nil {
+			t.Errorf("parseItemExpr(%q) succeeded, want error", expr)
+		}
+	}
+	e, _ := parseItemExpr(`item.size > 2`)
+	if _, err := e.eval(map[string]interface{}{"size": "xl"}); err == nil {
+		t.Error("ordering a string against a number should fail")
+	}
+}
+
+func TestSelectMatrixItems(t *testing.T) {
+	var items []interface{}
+	_ = json.Unmarshal([]byte(`[
+		{"id": "a", "size": "s", "priority": 1},
+		{"id": "b", "size": "xl", "priority": 5},
+		{"id": "c", "size": "m", "priority": 3},
+		{"id": "d", "size": "m"},
+		{"id": "e", "size": "l", "priority": 4}
+	]`), &items)
+
+	got, err := selectMatrixItems(items, &MatrixStrategy{
+		Filter:    `item.size != 'xl'`,
+		SortBy:    "item.priority",
+		SortOrder: "desc",
+		Limit:     3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, it := range got {
+		ids = append(ids, it.(map[string]interface{})["id"].(string))
+	}
+	if strings.Join(ids, ",") != "e,c,a" {
+		t.Errorf("selected %v, want [e c a]", ids)
+	}
+
+	// Items without a sort key go last.
+	got, _ = selectMatrixItems(items, &MatrixStrategy{SortBy: "item.priority"})
+	if last := got[len(got)-1].(map[string]interface{})["id"]; last != "d" {
+		t.Errorf("item without priority sorted to %v, want last", got)
+	}
+}
+
+func TestValidateDAG_MatrixSelection(t *testing.T) {
+	for _, s := range []MatrixStrategy{
+		{Type: "matrix", Filter: "item.size = 'xl'"},
+		{Type: "matrix", SortBy: "priority"},
+		{Type: "matrix", SortOrder: "up"},
+		{Type: "matrix", Limit: -1},
+	} {
+		strategy := s
+		p := &Pipeline{Steps: []Step{{ID: "fanout", Persona: "craftsman", Strategy: &strategy}}}
+		if err := (&DAGValidator{}).ValidateDAG(p); err == nil {
+			t.Errorf("strategy %+v: expected validation error", s)
+		}
+	}
+}
+
+func TestMatrixExecutor_ReadItemsSource_PrunesFilteredDependencies(t *testing.T) {
+	itemsFile := filepath.Join(t.TempDir(), "items.json")
+	data := `[
+		{"id": "schema", "kind": "db", "deps": []},
+		{"id": "api", "kind": "code", "deps": ["schema"]},
+		{"id": "ui", "kind": "code", "deps": ["api", "design"]}
+	]`
+	if err := os.WriteFile(itemsFile, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMatrixExecutor(&DefaultPipelineExecutor{})
+	execution := &PipelineExecution{WorkspacePaths: map[string]string{}, ArtifactPaths: map[string]string{}}
+	items, err := m.readItemsSource(execution, &MatrixStrategy{
+		Type:          "matrix",
+		ItemsSource:   itemsFile,
+		ItemIDKey:     "id",
+		DependencyKey: "deps",
+		Filter:        "item.kind == 'code'",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+	apiDeps, _ := m.extractDependencies(items[0], "deps")
+	uiDeps, _ := m.extractDependencies(items[1], "deps")
+	// "schema" was filtered out; "design" never existed and must still be reported.
+	if len(apiDeps) != 0 || strings.Join(uiDeps, ",") != "api,design" {
+		t.Errorf("deps after pruning: api=%v ui=%v", apiDeps, uiDeps)
+	}
+}
@@ -0,0 +1,165 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/recinq/wave/internal/event"
+)
+
+// MatrixSummaryArtifactName is the artifact under which a matrix step's
+// per-item results are registered ("<step-id>:matrix_summary").
+const MatrixSummaryArtifactName = "matrix_summary"
+
+// workerLimit returns how many of n items may run at once: max_workers,
+// else max_concurrency, else all of them.
+func (s *MatrixStrategy) workerLimit(n int) int {
+	limit := s.MaxWorkers
+	if limit <= 0 {
+		limit = s.MaxConcurrency
+	}
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+	return limit
+}
+
+// failFast reports whether a failed item cancels the items still running.
+func (s *MatrixStrategy) failFast() bool {
+	return s.FailFast == nil || *s.FailFast
+}
+
+// validateMatrixWorkers checks the worker pool fields of a matrix strategy
+// at load time.
+func validateMatrixWorkers(stepID string, s *MatrixStrategy) error {
+	if s.MaxWorkers < 0 {
+		return fmt.Errorf("step %q strategy.max_workers must not be negative", stepID)
+	}
+	if s.MaxWorkers > 0 && s.MaxConcurrency > 0 {
+		return fmt.Errorf("step %q strategy sets both max_workers and max_concurrency; use max_workers", stepID)
+	}
+	if r := s.ItemRetry; r != nil {
+		if r.OnFailure != "" || r.ReworkStep != "" {
+			return fmt.Errorf("step %q strategy.item_retry: on_failure and rework_step apply to the whole step, set them under retry", stepID)
+		}
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("step %q strategy.item_retry: %w", stepID, err)
+		}
+	}
+	return nil
+}
+
+// withItemRetry wraps worker so a failed item runs again, in the same
+// worker workspace, until it succeeds or the retry config gives up.
+func (m *MatrixExecutor) withItemRetry(worker matrixWorkerFunc, retry RetryConfig) matrixWorkerFunc {
+	maxAttempts := retry.EffectiveMaxAttempts()
+	return func(ctx context.Context, execution *PipelineExecution, step *Step, itemIndex int, item interface{}) MatrixResult {
+		for attempt := 1; ; attempt++ {
+			result := worker(ctx, execution, step, itemIndex, item)
+			result.Attempts = attempt
+			if result.Error == nil || attempt >= maxAttempts {
+				return result
+			}
+			class := ClassifyStepFailure(result.Error, nil, ctx.Err())
+			if !retry.ShouldRetry(class, CategorizeStepFailure(result.Error, ctx.Err())) {
+				return result
+			}
+
+			delay := retry.ComputeDelay(attempt)
+			m.emit(event.Event{
+				Timestamp:  time.Now(),
+				PipelineID: execution.Status.ID,
+				StepID:     step.ID,
+				State:      "matrix_item_retrying",
+				Message:    fmt.Sprintf("Worker %d attempt %d/%d failed (%s), retrying in %s: %v", itemIndex, attempt, maxAttempts, class, delay, result.Error),
+			})
+			select {
+			case <-ctx.Done():
+				return result
+			case <-time.After(delay):
+			}
+		}
+	}
+}
+
+// matrixSummary is the "<step-id>:matrix_summary" artifact: the outcome of
+// every item of a matrix step, in item order.
+type matrixSummary struct {
+	StepID    string              `json:"step_id"`
+	Total     int                 `json:"total"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Skipped   int                 `json:"skipped"`
+	Items     []matrixItemSummary `json:"items"`
+}
+
+type matrixItemSummary struct {
+	Index      int         `json:"index"`
+	ID         string      `json:"id,omitempty"`
+	Item       interface{} `json:"item"`
+	Status     string      `json:"status"` // succeeded, failed or skipped
+	Attempts   int         `json:"attempts"`
+	Error      string      `json:"error,omitempty"`
+	Class      string      `json:"failure_class,omitempty"`
+	SkipReason string      `json:"skip_reason,omitempty"`
+	Workspace  string      `json:"workspace,omitempty"`
+}
+
+// buildMatrixSummary summarizes worker results in item order.
+func buildMatrixSummary(stepID string, results []MatrixResult) matrixSummary {
+	summary := matrixSummary{StepID: stepID, Total: len(results), Items: make([]matrixItemSummary, 0, len(results))}
+	for _, r := range results {
+		item := matrixItemSummary{
+			Index:     r.ItemIndex,
+			ID:        r.ItemID,
+			Item:      r.Item,
+			Attempts:  r.Attempts,
+			Workspace: r.WorkspacePath,
+		}
+		switch {
+		case r.Skipped:
+			item.Status = "skipped"
+			item.Attempts = 0
+			item.SkipReason = r.SkipReason
+			summary.Skipped++
+		case r.Error != nil:
+			item.Status = "failed"
+			item.Error = r.Error.Error()
+			item.Class = ClassifyStepFailure(r.Error, nil, nil)
+			summary.Failed++
+		default:
+			item.Status = "succeeded"
+			summary.Succeeded++
+		}
+		if !r.Skipped && item.Attempts == 0 {
+			item.Attempts = 1
+		}
+		summary.Items = append(summary.Items, item)
+	}
+	sort.Slice(summary.Items, func(i, j int) bool { return summary.Items[i].Index < summary.Items[j].Index })
+	return summary
+}
+
+// writeSummary writes the matrix summary artifact into the step directory
+// holding the worker workspaces and registers it as
+// "<step-id>:matrix_summary".
+func (m *MatrixExecutor) writeSummary(execution *PipelineExecution, step *Step, results []MatrixResult) {
+	wsRoot := execution.Manifest.Runtime.WorkspaceRoot
+	if wsRoot == "" {
+		wsRoot = ".agents/workspaces"
+	}
+	workspacePath := filepath.Join(wsRoot, m.executor.workspaceRunIDFor(execution.Status.ID), step.ID)
+	data, err := json.MarshalIndent(buildMatrixSummary(step.ID, results), "", "  ")
+	if err != nil {
+		return
+	}
+	artPath := stepRecordArtifactPath(execution, workspacePath, step.ID, MatrixSummaryArtifactName)
+	if abs, err := filepath.Abs(artPath); err == nil {
+		artPath = abs
+	}
+	m.executor.writeStepRecordArtifact(execution, step, MatrixSummaryArtifactName, artPath, data)
+}
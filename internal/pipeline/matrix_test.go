@@ -147,6 +147,29 @@ func TestMatrixExecutor_ReadItemsSource_FromPreviousStep(t *testing.T) {
 	}
 }
 
+func TestMatrixExecutor_ReadItemsSource_ArtifactReference(t *testing.T) {
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, os.WriteFile(planPath, []byte(`{"tasks": [{"id": "a"}, {"id": "b"}]}`), 0644))
+
+	matrixExecutor := NewMatrixExecutor(&DefaultPipelineExecutor{})
+	execution := &PipelineExecution{
+		WorkspacePaths: map[string]string{},
+		ArtifactPaths:  map[string]string{"plan:tasks": planPath},
+	}
+
+	for _, source := range []string{"{{ steps.plan.artifacts.tasks }}", "{{steps.plan.artifact.tasks}}"} {
+		result, err := matrixExecutor.readItemsSource(execution, &MatrixStrategy{Type: "matrix", ItemsSource: source, ItemKey: "tasks"})
+		require.NoError(t, err, source)
+		require.Len(t, result, 2, source)
+	}
+
+	_, err := matrixExecutor.readItemsSource(execution, &MatrixStrategy{Type: "matrix", ItemsSource: "{{ steps.plan.artifacts.missing }}"})
+	require.ErrorContains(t, err, `artifact "missing" from step "plan" not found`)
+
+	_, err = matrixExecutor.readItemsSource(execution, &MatrixStrategy{Type: "matrix", ItemsSource: "{{ steps.plan.artifacts.tasks.items }}"})
+	require.ErrorContains(t, err, "item_key")
+}
+
 func TestMatrixExecutor_ExtractByKey(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -2469,3 +2492,246 @@ func (a *stackedBranchTrackingAdapter) Run(ctx context.Context, cfg adapter.Adap
 
 	return a.baseAdapter.Run(ctx, cfg)
 }
+
+// flakyItemAdapter crashes for items whose prompt contains a pattern until
+// that pattern has failed failures times.
+type flakyItemAdapter struct {
+	failures    map[string]int // prompt substring -> failing attempts (-1 = always)
+	mu          sync.Mutex
+	calls       map[string]int
+	baseAdapter adapter.AdapterRunner
+}
+
+func (a *flakyItemAdapter) Run(ctx context.Context, cfg adapter.AdapterRunConfig) (*adapter.AdapterResult, error) {
+	a.mu.Lock()
+	shouldFail := false
+	for pattern, n := range a.failures {
+		if strings.Contains(cfg.Prompt, pattern) {
+			a.calls[pattern]++
+			shouldFail = n < 0 || a.calls[pattern] <= n
+		}
+	}
+	a.mu.Unlock()
+
+	if shouldFail {
+		return nil, adapter.NewStepError(adapter.FailureReasonCrash, fmt.Errorf("simulated crash"), 0, "")
+	}
+	return a.baseAdapter.Run(ctx, cfg)
+}
+
+func newFlakyItemAdapter(failures map[string]int) *flakyItemAdapter {
+	return &flakyItemAdapter{
+		failures: failures,
+		calls:    make(map[string]int),
+		baseAdapter: adaptertest.NewMockAdapter(
+			adaptertest.WithStdoutJSON(`{"status": "success"}`),
+			adaptertest.WithTokensUsed(100),
+		),
+	}
+}
+
+// readMatrixSummary loads the matrix_summary artifact of a step.
+func readMatrixSummary(t *testing.T, execution *PipelineExecution, stepID string) matrixSummary {
+	t.Helper()
+	path, ok := execution.ArtifactPaths[stepID+":"+MatrixSummaryArtifactName]
+	require.True(t, ok, "matrix_summary artifact not registered")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var summary matrixSummary
+	require.NoError(t, json.Unmarshal(data, &summary))
+	return summary
+}
+
+func TestMatrixExecutor_ItemRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		retry        *RetryConfig
+		expectErr    bool
+		expectStatus string
+		expectTries  int
+		expectEvents int
+	}{
+		{
+			name:         "flaky item recovers",
+			retry:        &RetryConfig{MaxAttempts: 3, Backoff: "fixed", BaseDelay: "1ms"},
+			expectStatus: "succeeded",
+			expectTries:  3,
+			expectEvents: 2,
+		},
+		{
+			name:         "attempts exhausted",
+			retry:        &RetryConfig{MaxAttempts: 2, Backoff: "fixed", BaseDelay: "1ms"},
+			expectErr:    true,
+			expectStatus: "failed",
+			expectTries:  2,
+			expectEvents: 1,
+		},
+		{
+			name:         "no_retry_on stops retries",
+			retry:        &RetryConfig{MaxAttempts: 3, BaseDelay: "1ms", NoRetryOn: []string{FailureCategoryAdapterCrash}},
+			expectErr:    true,
+			expectStatus: "failed",
+			expectTries:  1,
+		},
+		{
+			name:         "no item_retry",
+			expectErr:    true,
+			expectStatus: "failed",
+			expectTries:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			itemsFile := createTieredItemsFile(t, tmpDir, []map[string]interface{}{
+				{"id": "A"}, {"id": "B"}, {"id": "C"},
+			})
+
+			eventCollector := testutil.NewEventCollector()
+			executor := NewDefaultPipelineExecutor(newFlakyItemAdapter(map[string]int{`"id":"B"`: 2}), WithEmitter(eventCollector))
+			execution := createTieredExecution(t, tmpDir, "matrix-item-retry")
+
+			step := &Step{
+				ID:      "matrix_step",
+				Persona: "worker",
+				Strategy: &MatrixStrategy{
+					Type:        "matrix",
+					ItemsSource: itemsFile,
+					ItemIDKey:   "id",
+					ItemRetry:   tt.retry,
+				},
+				Exec: ExecConfig{Type: "prompt", Source: "Process: {{ task }}"},
+			}
+
+			err := NewMatrixExecutor(executor).Execute(context.Background(), execution, step)
+			if tt.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			summary := readMatrixSummary(t, execution, step.ID)
+			require.Len(t, summary.Items, 3)
+			item := summary.Items[1]
+			require.Equal(t, "B", item.ID)
+			require.Equal(t, tt.expectStatus, item.Status)
+			require.Equal(t, tt.expectTries, item.Attempts)
+			require.Equal(t, 1, summary.Items[0].Attempts)
+
+			retries := 0
+			for _, e := range eventCollector.GetEvents() {
+				if e.State == "matrix_item_retrying" {
+					retries++
+				}
+			}
+			require.Equal(t, tt.expectEvents, retries)
+		})
+	}
+}
+
+func TestMatrixExecutor_FailFastDisabled(t *testing.T) {
+	tmpDir := t.TempDir()
+	itemsFile := createTieredItemsFile(t, tmpDir, []map[string]interface{}{
+		{"id": "A"}, {"id": "B"}, {"id": "C"}, {"id": "D"},
+	})
+
+	executor := NewDefaultPipelineExecutor(newFlakyItemAdapter(map[string]int{`"id":"A"`: -1}))
+	execution := createTieredExecution(t, tmpDir, "matrix-fail-fast")
+
+	failFast := false
+	step := &Step{
+		ID:      "matrix_step",
+		Persona: "worker",
+		Strategy: &MatrixStrategy{
+			Type:        "matrix",
+			ItemsSource: itemsFile,
+			ItemIDKey:   "id",
+			MaxWorkers:  1,
+			FailFast:    &failFast,
+		},
+		Exec: ExecConfig{Type: "prompt", Source: "Process: {{ task }}"},
+	}
+
+	err := NewMatrixExecutor(executor).Execute(context.Background(), execution, step)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "1/4 workers failed")
+
+	results := execution.Results[step.ID]
+	require.Equal(t, 3, results["success_count"])
+	require.Equal(t, 1, results["fail_count"])
+
+	summary := readMatrixSummary(t, execution, step.ID)
+	require.Equal(t, 4, summary.Total)
+	require.Equal(t, 3, summary.Succeeded)
+	require.Equal(t, 1, summary.Failed)
+	var statuses []string
+	for _, item := range summary.Items {
+		statuses = append(statuses, item.ID+"="+item.Status)
+	}
+	require.Equal(t, []string{"A=failed", "B=succeeded", "C=succeeded", "D=succeeded"}, statuses)
+	require.Equal(t, FailureClassTransient, summary.Items[0].Class)
+	require.Contains(t, summary.Items[0].Error, "simulated crash")
+}
+
+func TestMatrixExecutor_MaxWorkersLimit(t *testing.T) {
+	tmpDir := t.TempDir()
+	items := make([]map[string]interface{}, 6)
+	for i := range items {
+		items[i] = map[string]interface{}{"id": i}
+	}
+	itemsFile := createTieredItemsFile(t, tmpDir, items)
+
+	tracker := &concurrencyLimitTracker{}
+	executor := NewDefaultPipelineExecutor(&concurrencyTrackingMatrixAdapter{
+		tracker: tracker,
+		baseAdapter: adaptertest.NewMockAdapter(
+			adaptertest.WithStdoutJSON(`{"status": "success"}`),
+			adaptertest.WithSimulatedDelay(30*time.Millisecond),
+		),
+	})
+	execution := createTieredExecution(t, tmpDir, "matrix-max-workers")
+
+	step := &Step{
+		ID:      "matrix_step",
+		Persona: "worker",
+		Strategy: &MatrixStrategy{
+			Type:        "matrix",
+			ItemsSource: itemsFile,
+			MaxWorkers:  2,
+		},
+		Exec: ExecConfig{Type: "prompt", Source: "Process: {{ task }}"},
+	}
+
+	require.NoError(t, NewMatrixExecutor(executor).Execute(context.Background(), execution, step))
+	if tracker.maxObserved > 2 {
+		t.Errorf("Expected at most 2 concurrent workers, observed %d", tracker.maxObserved)
+	}
+	require.Equal(t, 6, readMatrixSummary(t, execution, step.ID).Succeeded)
+}
+
+func TestValidateMatrixWorkers(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       MatrixStrategy
+		wantErr string
+	}{
+		{name: "defaults", s: MatrixStrategy{}},
+		{name: "max_workers", s: MatrixStrategy{MaxWorkers: 4}},
+		{name: "negative max_workers", s: MatrixStrategy{MaxWorkers: -1}, wantErr: "must not be negative"},
+		{name: "both limits", s: MatrixStrategy{MaxWorkers: 2, MaxConcurrency: 2}, wantErr: "both max_workers and max_concurrency"},
+		{name: "item_retry", s: MatrixStrategy{ItemRetry: &RetryConfig{Policy: "standard", RetryOn: []string{FailureCategoryAdapterCrash}}}},
+		{name: "item_retry on_failure", s: MatrixStrategy{ItemRetry: &RetryConfig{OnFailure: "skip"}}, wantErr: "on_failure and rework_step"},
+		{name: "item_retry unknown kind", s: MatrixStrategy{ItemRetry: &RetryConfig{RetryOn: []string{"nope"}}}, wantErr: "retry_on"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMatrixWorkers("fan", &tt.s)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
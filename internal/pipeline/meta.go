@@ -298,16 +298,17 @@ func (e *MetaPipelineExecutor) invokePhilosopherWithSchemas(ctx context.Context,
 	}
 
 	cfg := adapter.AdapterRunConfig{
-		Adapter:       persona.Adapter,
-		Persona:       philosopherPersona,
-		WorkspacePath: metaWorkspace,
-		Prompt:        prompt,
-		Timeout:       e.getTimeout(m),
-		Temperature:   persona.Temperature,
-		Model:         persona.Model,
-		AllowedTools:  persona.Permissions.AllowedTools,
-		DenyTools:     persona.Permissions.Deny,
-		OutputFormat:  "yaml",
+		Adapter:        persona.Adapter,
+		Persona:        philosopherPersona,
+		WorkspacePath:  metaWorkspace,
+		Prompt:         prompt,
+		Timeout:        e.getTimeout(m),
+		Temperature:    persona.Temperature,
+		TemperatureSet: persona.TemperatureSet,
+		Model:          persona.Model,
+		AllowedTools:   persona.Permissions.AllowedTools,
+		DenyTools:      persona.Permissions.Deny,
+		OutputFormat:   "yaml",
 	}
 
 	e.emit(event.Event{
@@ -1047,6 +1048,10 @@ func (e *MetaPipelineExecutor) SyncFromChild(child *MetaPipelineExecutor) {
 
 // ValidatePipelineYAML validates raw YAML as a valid WavePipeline structure.
 func ValidatePipelineYAML(data []byte) (*Pipeline, error) {
+	data, err := expandStepTemplates(data)
+	if err != nil {
+		return nil, err
+	}
 	var pipeline Pipeline
 	if err := yaml.Unmarshal(data, &pipeline); err != nil {
 		return nil, fmt.Errorf("invalid YAML syntax: %w", err)
@@ -1088,5 +1093,9 @@ func ValidatePipelineYAML(data []byte) (*Pipeline, error) {
 		}
 	}
 
+	if err := resolveSchemaRefs(&pipeline); err != nil {
+		return nil, err
+	}
+
 	return &pipeline, nil
 }
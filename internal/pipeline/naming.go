@@ -0,0 +1,107 @@
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	invalidRefCharRe     = regexp.MustCompile(`[^a-zA-Z0-9._+\-]`)
+	consecutiveDotRe     = regexp.MustCompile(`\.{2,}`)
+	asciiTransliteration = map[rune]string{
+		'ß': "ss", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE",
+		'ø': "o", 'Ø': "O", 'ł': "l", 'Ł': "L", 'đ': "d", 'Đ': "D",
+		'þ': "th", 'Þ': "TH", 'ı': "i",
+	}
+)
+
+// nameHashLen is the number of hex digits of the fallback hash appended to
+// names that could not be represented faithfully.
+const nameHashLen = 8
+
+// maxGitRefLen caps sanitized ref names well below the per-component file
+// name limit git's loose refs are subject to.
+const maxGitRefLen = 200
+
+// foldToASCII maps s onto ASCII: accented letters lose their accents, a few
+// letters without a decomposition are transliterated, and anything else
+// (emoji, CJK, symbols) becomes a dash. lossy reports whether any rune had
+// to be replaced, in which case distinct inputs may fold to the same string.
+func foldToASCII(s string) (folded string, lossy bool) {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(s) {
+		switch {
+		case r <= unicode.MaxASCII:
+			b.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// Combining mark split off by NFKD; drop it.
+		case asciiTransliteration[r] != "":
+			b.WriteString(asciiTransliteration[r])
+		case unicode.IsSpace(r):
+			b.WriteByte(' ')
+		default:
+			b.WriteByte('-')
+			lossy = true
+		}
+	}
+	return b.String(), lossy
+}
+
+// withNameHash appends a short hash of original to prefix, truncating prefix
+// so the result fits in maxLen. It keeps names derived from distinct inputs
+// distinct when sanitization had to discard information.
+func withNameHash(prefix, original string, maxLen int) string {
+	sum := sha256.Sum256([]byte(original))
+	hash := hex.EncodeToString(sum[:])[:nameHashLen]
+	if len(prefix) > maxLen-nameHashLen-1 {
+		prefix = prefix[:maxLen-nameHashLen-1]
+	}
+	prefix = strings.TrimRight(prefix, "-./")
+	if prefix == "" {
+		return hash
+	}
+	return prefix + "-" + hash
+}
+
+// SanitizeGitRef turns an arbitrary string, typically a branch name rendered
+// from an issue title, into a valid git branch name. Slashes are kept as
+// hierarchy separators; within each component, characters outside
+// [a-zA-Z0-9._+-] become dashes and the sequences git-check-ref-format
+// rejects (leading dots or dashes, "..", a trailing dot or ".lock") are
+// removed. Names that lost non-ASCII characters, came out empty, or were
+// truncated get a hash of the original appended so they stay unique.
+// Well-formed ASCII branch names are returned unchanged.
+func SanitizeGitRef(ref string) string {
+	folded, lossy := foldToASCII(ref)
+
+	var parts []string
+	for _, comp := range strings.Split(folded, "/") {
+		comp = invalidRefCharRe.ReplaceAllString(comp, "-")
+		comp = consecutiveDashRe.ReplaceAllString(comp, "-")
+		comp = consecutiveDotRe.ReplaceAllString(comp, ".")
+		for {
+			trimmed := strings.TrimSuffix(strings.Trim(comp, ".-"), ".lock")
+			if trimmed == comp {
+				break
+			}
+			comp = trimmed
+		}
+		if comp != "" {
+			parts = append(parts, comp)
+		}
+	}
+	sanitized := strings.Join(parts, "/")
+
+	switch {
+	case sanitized == "" && strings.TrimSpace(ref) != "":
+		return "branch-" + withNameHash("", ref, maxGitRefLen)
+	case lossy || len(sanitized) > maxGitRefLen:
+		return withNameHash(sanitized, ref, maxGitRefLen)
+	}
+	return sanitized
+}
@@ -0,0 +1,92 @@
+package pipeline
+
+import (
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var hashSuffixRe = regexp.MustCompile(`-[0-9a-f]{8}$`)
+
+func TestSanitizeBranchName_Unicode(t *testing.T) {
+	// Accents fold to ASCII without a hash: nothing was lost.
+	assert.Equal(t, "cafe-creme-fix", SanitizeBranchName("café crème fix"))
+	assert.Equal(t, "strasse-umbau", SanitizeBranchName("straße umbau"))
+
+	// Emoji are dropped and a hash keeps the result unique.
+	a := SanitizeBranchName("fix: login 🎉")
+	b := SanitizeBranchName("fix: login 🚀")
+	assert.Regexp(t, `^fix-login-[0-9a-f]{8}$`, a)
+	assert.NotEqual(t, a, b)
+	assert.Equal(t, a, SanitizeBranchName("fix: login 🎉"), "hashing is deterministic")
+
+	// Names with nothing representable still produce a usable path.
+	assert.Regexp(t, `^[0-9a-f]{8}$`, SanitizeBranchName("修复登录"))
+	assert.Regexp(t, `^[0-9a-f]{8}$`, SanitizeBranchName("???"))
+	assert.Equal(t, "", SanitizeBranchName(""))
+
+	long := SanitizeBranchName(strings.Repeat("word ", 20) + "🔥")
+	assert.LessOrEqual(t, len(long), 50)
+	assert.Regexp(t, hashSuffixRe, long)
+}
+
+func TestSanitizeGitRef(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      string
+		expected string
+	}{
+		{"valid_unchanged", "feature/add-login", "feature/add-login"},
+		{"dots_and_plus_kept", "release/v1.2+build_3", "release/v1.2+build_3"},
+		{"spaces_and_punctuation", "feat/Fix: can't log in (SSO)!", "feat/Fix-can-t-log-in-SSO"},
+		{"git_special_chars", `fix/a~b^c:d?e*f[g\h`, "fix/a-b-c-d-e-f-g-h"},
+		{"double_dot", "fix/a..b", "fix/a.b"},
+		{"reflog_syntax", "fix/head@{1}", "fix/head-1"},
+		{"leading_dot_and_dash", "feat/.hidden/-flag", "feat/hidden/flag"},
+		{"lock_suffix", "feat/refs.lock", "feat/refs"},
+		{"trailing_slash_and_dot", "feat/name./", "feat/name"},
+		{"consecutive_slashes", "feat//nested///name", "feat/nested/name"},
+		{"accents_folded", "feat/résumé-über", "feat/resume-uber"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SanitizeGitRef(tt.ref))
+		})
+	}
+}
+
+func TestSanitizeGitRef_LossyInputsHashed(t *testing.T) {
+	a := SanitizeGitRef("wave/42-🐛 crash on save")
+	b := SanitizeGitRef("wave/42-🐞 crash on save")
+	assert.Regexp(t, `^wave/42-crash-on-save-[0-9a-f]{8}$`, a)
+	assert.NotEqual(t, a, b)
+
+	assert.Regexp(t, `^branch-[0-9a-f]{8}$`, SanitizeGitRef("🎉/🚀"))
+
+	long := SanitizeGitRef("feat/" + strings.Repeat("x", 300))
+	assert.LessOrEqual(t, len(long), maxGitRefLen)
+	assert.Regexp(t, hashSuffixRe, long)
+}
+
+func TestSanitizeGitRef_AcceptedByGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	inputs := []string{
+		"feat/Fix: can't log in (SSO)!",
+		"wave/42-🐛 crash on save",
+		"..//.lock/@{-1}/x.lock.",
+		"-leading/trailing.",
+		"修复/登录",
+		"a/b/c\x00\x7f",
+	}
+	for _, in := range inputs {
+		ref := SanitizeGitRef(in)
+		out, err := exec.Command("git", "check-ref-format", "--branch", ref).CombinedOutput()
+		assert.NoError(t, err, "input %q sanitized to %q: %s", in, ref, out)
+	}
+}
@@ -0,0 +1,218 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/manifest"
+)
+
+// pathScopedTools are the tools whose stream event target is a file path,
+// keyed by lower-cased name so every adapter's spelling matches. Shell
+// tools report commands, which cannot be checked reliably; the sandbox
+// covers those.
+var pathScopedTools = map[string]bool{
+	"read": true, "write": true, "edit": true, "multiedit": true, "notebookedit": true,
+}
+
+// searchScopedTools are the search tools whose `path` argument names the
+// directory searched. Their target is a pattern, so only the reported
+// ToolPaths are checked and patterns are left to the sandbox.
+var searchScopedTools = map[string]bool{
+	"glob": true, "grep": true,
+}
+
+// PathPolicyViolationError reports a tool call outside the filesystem roots
+// of a persona with filesystem.strict set.
+type PathPolicyViolationError struct {
+	Step    string
+	Persona string
+	Tool    string
+	Path    string
+}
+
+func (e *PathPolicyViolationError) Error() string {
+	return fmt.Sprintf("persona '%s' in step '%s' used %s on %s, outside its filesystem roots", e.Persona, e.Step, e.Tool, e.Path)
+}
+
+// pathPolicy is a persona's filesystem scope resolved for one step run: the
+// workspace, the mount sources and the persona's extra roots.
+type pathPolicy struct {
+	workspace string
+	roots     []string
+	strict    bool
+}
+
+// newPathPolicy resolves fs against the step's workspace and mounts. It
+// returns nil when the persona declares no filesystem policy.
+func newPathPolicy(fs *manifest.PersonaFilesystem, workspacePath string, mounts []Mount) *pathPolicy {
+	if fs == nil {
+		return nil
+	}
+	p := &pathPolicy{workspace: absClean(workspacePath), strict: fs.Strict}
+	add := func(dir string) {
+		dir = absClean(dir)
+		p.roots = append(p.roots, dir)
+		// Also accept the canonical form so tools reporting resolved paths
+		// (e.g. /private/var on macOS) are not flagged.
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil && resolved != dir {
+			p.roots = append(p.roots, resolved)
+		}
+	}
+	add(workspacePath)
+	for _, m := range mounts {
+		add(m.Source)
+	}
+	for _, r := range fs.Roots {
+		add(r)
+	}
+	return p
+}
+
+// check returns the offending path when evt is a file or search tool call
+// outside the policy roots, or "" when the call is allowed or not
+// path-based. Every path in ToolPaths is checked; ToolInput, a display
+// summary that adapters truncate, is only used for file tools that
+// reported no paths. Relative paths are resolved against the workspace,
+// the adapter's working directory.
+func (p *pathPolicy) check(evt adapter.StreamEvent) string {
+	if evt.Type != "tool_use" {
+		return ""
+	}
+	tool := strings.ToLower(evt.ToolName)
+	paths := evt.ToolPaths
+	switch {
+	case pathScopedTools[tool]:
+		if len(paths) == 0 {
+			paths = []string{evt.ToolInput}
+		}
+	case searchScopedTools[tool]:
+	default:
+		return ""
+	}
+	for _, path := range paths {
+		if target := p.checkPath(path); target != "" {
+			return target
+		}
+	}
+	return ""
+}
+
+// checkPath returns path, cleaned and made absolute, when it lies outside
+// the policy roots.
+func (p *pathPolicy) checkPath(path string) string {
+	target := strings.TrimSpace(path)
+	if target == "" || strings.HasPrefix(target, "{") {
+		return "" // raw JSON arguments, not a path
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(p.workspace, target)
+	}
+	target = filepath.Clean(target)
+	if !p.contains(target) {
+		return target
+	}
+	// A symlink inside a root can still lead outside all of them.
+	if resolved, ok := resolveExistingPrefix(target); ok && !p.contains(resolved) {
+		return target
+	}
+	return ""
+}
+
+func (p *pathPolicy) contains(path string) bool {
+	for _, root := range p.roots {
+		if path == root || strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// enforcePathPolicy wraps cfg.OnStreamEvent so every tool call is checked
+// against the persona's filesystem policy. Each violation emits a warning; in
+// strict mode the first one also cancels the returned context, killing the
+// adapter. The returned function, called once the adapter has returned,
+// reports that violation.
+func (e *DefaultPipelineExecutor) enforcePathPolicy(ctx context.Context, step *Step, res *stepRunResources, cfg *adapter.AdapterRunConfig) (context.Context, func() error) {
+	policy := newPathPolicy(res.persona.Filesystem, res.workspacePath, step.Workspace.Mount)
+	if policy == nil {
+		return ctx, func() error { return nil }
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var mu sync.Mutex
+	var violation *PathPolicyViolationError
+	next := cfg.OnStreamEvent
+	cfg.OnStreamEvent = func(evt adapter.StreamEvent) {
+		if next != nil {
+			next(evt)
+		}
+		path := policy.check(evt)
+		if path == "" {
+			return
+		}
+		v := &PathPolicyViolationError{Step: step.ID, Persona: res.resolvedPersona, Tool: evt.ToolName, Path: path}
+		msg := "filesystem policy: " + v.Error()
+		if policy.strict {
+			msg += " (strict: killing step)"
+		}
+		e.emit(event.Event{
+			Timestamp:  time.Now(),
+			PipelineID: res.pipelineID,
+			StepID:     step.ID,
+			State:      "warning",
+			Persona:    res.resolvedPersona,
+			ToolName:   evt.ToolName,
+			ToolTarget: path,
+			Message:    msg,
+		})
+		if policy.strict {
+			mu.Lock()
+			if violation == nil {
+				violation = v
+				cancel()
+			}
+			mu.Unlock()
+		}
+	}
+
+	return ctx, func() error {
+		cancel()
+		mu.Lock()
+		defer mu.Unlock()
+		if violation != nil {
+			return violation
+		}
+		return nil
+	}
+}
+
+func absClean(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// resolveExistingPrefix resolves symlinks in the longest existing prefix of
+// path, so paths of files about to be created can be checked too.
+func resolveExistingPrefix(path string) (string, bool) {
+	rest := ""
+	for dir := path; ; {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(resolved, rest), true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+	}
+}
@@ -0,0 +1,117 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathPolicy_Check(t *testing.T) {
+	tmp := t.TempDir()
+	ws := filepath.Join(tmp, "ws")
+	src := filepath.Join(tmp, "src")
+	extra := filepath.Join(tmp, "extra")
+	outside := filepath.Join(tmp, "outside")
+	for _, dir := range []string{ws, src, extra, outside} {
+		require.NoError(t, os.MkdirAll(dir, 0755))
+	}
+	require.NoError(t, os.Symlink(outside, filepath.Join(ws, "escape")))
+
+	policy := newPathPolicy(&manifest.PersonaFilesystem{Roots: []string{extra}}, ws, []Mount{{Source: src, Target: "/project"}})
+	// Longer than the 100-byte ToolInput summary; the escape is in the tail.
+	longPath := filepath.Join(ws, strings.Repeat("d", 120)) + "/../../outside/x"
+
+	tests := []struct {
+		name string
+		evt  adapter.StreamEvent
+		want string
+	}{
+		{"workspace file", adapter.StreamEvent{Type: "tool_use", ToolName: "Write", ToolInput: filepath.Join(ws, "out.json")}, ""},
+		{"relative path", adapter.StreamEvent{Type: "tool_use", ToolName: "Read", ToolInput: "notes/plan.md"}, ""},
+		{"mount source", adapter.StreamEvent{Type: "tool_use", ToolName: "Read", ToolInput: filepath.Join(src, "main.go")}, ""},
+		{"extra root", adapter.StreamEvent{Type: "tool_use", ToolName: "edit", ToolInput: filepath.Join(extra, "a.txt")}, ""},
+		{"absolute outside", adapter.StreamEvent{Type: "tool_use", ToolName: "Read", ToolInput: "/etc/passwd"}, "/etc/passwd"},
+		{"relative escape", adapter.StreamEvent{Type: "tool_use", ToolName: "Write", ToolInput: "../outside/x"}, filepath.Join(outside, "x")},
+		{"symlink escape", adapter.StreamEvent{Type: "tool_use", ToolName: "Write", ToolInput: filepath.Join(ws, "escape", "new.txt")}, filepath.Join(ws, "escape", "new.txt")},
+		{"root prefix is not a root", adapter.StreamEvent{Type: "tool_use", ToolName: "Read", ToolInput: ws + "-other/file"}, ws + "-other/file"},
+		{"shell tool ignored", adapter.StreamEvent{Type: "tool_use", ToolName: "Bash", ToolInput: "cat /etc/passwd"}, ""},
+		{"json arguments ignored", adapter.StreamEvent{Type: "tool_use", ToolName: "write", ToolInput: `{"path":"/etc/passwd"`}, ""},
+		{"non tool event ignored", adapter.StreamEvent{Type: "text", ToolName: "Read", ToolInput: "/etc/passwd"}, ""},
+		{"multi-path change checks every path", adapter.StreamEvent{Type: "tool_use", ToolName: "Edit", ToolInput: "src/a.go /etc/passwd", ToolPaths: []string{"src/a.go", "/etc/passwd"}}, "/etc/passwd"},
+		{"search path outside", adapter.StreamEvent{Type: "tool_use", ToolName: "Grep", ToolInput: "password", ToolPaths: []string{"/etc"}}, "/etc"},
+		{"search path inside", adapter.StreamEvent{Type: "tool_use", ToolName: "glob", ToolInput: "**/*.go", ToolPaths: []string{src}}, ""},
+		{"search pattern not a path", adapter.StreamEvent{Type: "tool_use", ToolName: "Grep", ToolInput: "^/usr/bin"}, ""},
+		{"long path checked untruncated", adapter.StreamEvent{Type: "tool_use", ToolName: "Write", ToolInput: longPath[:100], ToolPaths: []string{longPath}}, filepath.Join(outside, "x")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.check(tt.evt))
+		})
+	}
+
+	assert.Nil(t, newPathPolicy(nil, ws, nil), "no filesystem config means no policy")
+}
+
+func runPathPolicyPipeline(t *testing.T, fs *manifest.PersonaFilesystem) ([]string, error) {
+	t.Helper()
+	collector := testutil.NewEventCollector()
+	streamAdapter := &streamEventAdapter{
+		MockAdapter: adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`)),
+		streamEvents: []adapter.StreamEvent{
+			{Type: "tool_use", ToolName: "Read", ToolInput: "/etc/passwd"},
+		},
+	}
+	executor := NewDefaultPipelineExecutor(streamAdapter, WithEmitter(collector))
+
+	m := testutil.CreateTestManifest(t.TempDir())
+	persona := m.Personas["craftsman"]
+	persona.Filesystem = fs
+	m.Personas["craftsman"] = persona
+
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "path-policy-test"},
+		Steps:    []Step{{ID: "work", Persona: "craftsman", Exec: ExecConfig{Source: "do work"}}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := executor.Execute(ctx, p, m, "test")
+
+	var warnings []string
+	for _, e := range collector.GetEventsByStep("work") {
+		if e.State == "warning" && strings.HasPrefix(e.Message, "filesystem policy:") {
+			warnings = append(warnings, e.Message)
+		}
+	}
+	return warnings, err
+}
+
+func TestPathPolicy_StrictKillsStep(t *testing.T) {
+	warnings, err := runPathPolicyPipeline(t, &manifest.PersonaFilesystem{Strict: true})
+	require.Error(t, err)
+	var violation *PathPolicyViolationError
+	require.True(t, errors.As(err, &violation), "expected PathPolicyViolationError, got %v", err)
+	assert.Equal(t, "work", violation.Step)
+	assert.Equal(t, "Read", violation.Tool)
+	assert.Equal(t, "/etc/passwd", violation.Path)
+	assert.Equal(t, FailureClassDeterministic, ClassifyStepFailure(err, nil, nil))
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "strict: killing step")
+}
+
+func TestPathPolicy_NonStrictWarns(t *testing.T) {
+	warnings, err := runPathPolicyPipeline(t, &manifest.PersonaFilesystem{})
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "/etc/passwd")
+}
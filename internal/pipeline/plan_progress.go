@@ -0,0 +1,107 @@
+package pipeline
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/recinq/wave/internal/adapter"
+)
+
+// Step progress milestones. Agent execution spans the band between them;
+// a plan checklist, when the persona writes one, moves progress through it.
+const (
+	progressExecuting  = 25
+	progressProcessing = 75
+)
+
+// planWriteTools are the tools whose target may be a plan file, keyed by
+// lower-cased name.
+var planWriteTools = map[string]bool{"write": true, "edit": true, "multiedit": true}
+
+// checklistItemRe matches a markdown task-list item and captures its mark.
+var checklistItemRe = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]`)
+
+// maxPlanFileSize bounds how much of a file is read looking for a plan.
+const maxPlanFileSize = 1 << 20
+
+// planProgress derives step progress from the first markdown checklist the
+// persona writes. Stream events report a write when the tool is called, not
+// when it completes, so written files are re-read on the next event.
+type planProgress struct {
+	mu        sync.Mutex
+	workspace string
+	plan      string          // path of the plan file once one is found
+	pending   map[string]bool // files written since the last event
+	done      int             // checked items at the last report
+	total     int             // all items at the last report
+}
+
+func newPlanProgress(workspacePath string) *planProgress {
+	return &planProgress{workspace: workspacePath, pending: make(map[string]bool)}
+}
+
+// observe feeds one stream event to the tracker. It returns the progress and
+// the done/total item counts whenever the plan's checklist has changed.
+func (p *planProgress) observe(evt adapter.StreamEvent) (progress, done, total int, changed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for path := range p.pending {
+		delete(p.pending, path)
+		d, t := countChecklist(path)
+		if t == 0 || (p.plan != "" && path != p.plan) {
+			continue
+		}
+		p.plan = path
+		if d != p.done || t != p.total {
+			p.done, p.total = d, t
+			pct := progressExecuting + (progressProcessing-progressExecuting)*d/t
+			progress, done, total, changed = pct, d, t, true
+		}
+	}
+
+	if evt.Type == "tool_use" && planWriteTools[strings.ToLower(evt.ToolName)] {
+		target := strings.TrimSpace(evt.ToolInput)
+		if strings.EqualFold(filepath.Ext(target), ".md") {
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(p.workspace, target)
+			}
+			target = filepath.Clean(target)
+			if p.plan == "" || target == p.plan {
+				p.pending[target] = true
+			}
+		}
+	}
+	return progress, done, total, changed
+}
+
+// countChecklist returns the number of checked and total task-list items in
+// the markdown file at path. Unreadable or oversized files count as none.
+func countChecklist(path string) (done, total int) {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Size() > maxPlanFileSize {
+		return 0, 0
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxPlanFileSize)
+	for scanner.Scan() {
+		m := checklistItemRe.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		total++
+		if m[1] != " " {
+			done++
+		}
+	}
+	return done, total
+}
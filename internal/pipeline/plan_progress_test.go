@@ -0,0 +1,117 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountChecklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.md")
+	require.NoError(t, os.WriteFile(path, []byte(`# Plan
+
+- [x] read the code
+* [X] write the test
+  - [ ] nested item
+1. [ ] numbered item
+- not a task
+- [] malformed
+`), 0644))
+
+	done, total := countChecklist(path)
+	assert.Equal(t, 2, done)
+	assert.Equal(t, 4, total)
+
+	done, total = countChecklist(filepath.Join(t.TempDir(), "missing.md"))
+	assert.Zero(t, done)
+	assert.Zero(t, total)
+}
+
+func TestPlanProgress_Observe(t *testing.T) {
+	ws := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(ws, name), []byte(content), 0644))
+	}
+	p := newPlanProgress(ws)
+	tick := adapter.StreamEvent{Type: "text"}
+
+	// Files without a checklist, or not markdown, are never the plan.
+	write("notes.md", "just notes\n")
+	_, _, _, changed := p.observe(adapter.StreamEvent{Type: "tool_use", ToolName: "Write", ToolInput: "notes.md"})
+	assert.False(t, changed)
+	_, _, _, changed = p.observe(tick)
+	assert.False(t, changed)
+
+	// The write is only visible on the next event.
+	write("plan.md", "- [ ] a\n- [ ] b\n- [ ] c\n- [ ] d\n")
+	_, _, _, changed = p.observe(adapter.StreamEvent{Type: "tool_use", ToolName: "Write", ToolInput: "plan.md"})
+	assert.False(t, changed)
+	progress, done, total, changed := p.observe(tick)
+	require.True(t, changed)
+	assert.Equal(t, []int{25, 0, 4}, []int{progress, done, total})
+
+	// Once chosen, other checklists do not move progress.
+	write("other.md", "- [x] a\n")
+	p.observe(adapter.StreamEvent{Type: "tool_use", ToolName: "Write", ToolInput: filepath.Join(ws, "other.md")})
+	_, _, _, changed = p.observe(tick)
+	assert.False(t, changed)
+
+	write("plan.md", "- [x] a\n- [x] b\n- [x] c\n- [ ] d\n")
+	p.observe(adapter.StreamEvent{Type: "tool_use", ToolName: "Edit", ToolInput: "plan.md"})
+	progress, done, total, changed = p.observe(tick)
+	require.True(t, changed)
+	assert.Equal(t, []int{62, 3, 4}, []int{progress, done, total})
+
+	// Rewriting with the same counts reports nothing new.
+	p.observe(adapter.StreamEvent{Type: "tool_use", ToolName: "edit", ToolInput: "plan.md"})
+	_, _, _, changed = p.observe(tick)
+	assert.False(t, changed)
+}
+
+// planWritingAdapter ticks a plan file off item by item, reporting each write
+// as a stream event the way a real adapter would.
+type planWritingAdapter struct{}
+
+func (planWritingAdapter) Run(_ context.Context, cfg adapter.AdapterRunConfig) (*adapter.AdapterResult, error) {
+	plan := filepath.Join(cfg.WorkspacePath, "plan.md")
+	for _, content := range []string{"- [ ] a\n- [ ] b\n", "- [x] a\n- [ ] b\n", "- [x] a\n- [x] b\n"} {
+		cfg.OnStreamEvent(adapter.StreamEvent{Type: "tool_use", ToolName: "Write", ToolInput: "plan.md"})
+		if err := os.WriteFile(plan, []byte(content), 0644); err != nil {
+			return nil, err
+		}
+	}
+	cfg.OnStreamEvent(adapter.StreamEvent{Type: "text", Content: "done"})
+	return &adapter.AdapterResult{Stdout: strings.NewReader(`{"status":"success"}`), ResultContent: `{"status":"success"}`}, nil
+}
+
+func TestPlanProgress_EmitsStepProgress(t *testing.T) {
+	collector := testutil.NewEventCollector()
+	executor := NewDefaultPipelineExecutor(planWritingAdapter{}, WithEmitter(collector))
+	m := testutil.CreateTestManifest(t.TempDir())
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "plan-progress-test"},
+		Steps:    []Step{{ID: "work", Persona: "craftsman", Exec: ExecConfig{Source: "do work"}}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, executor.Execute(ctx, p, m, "test"))
+
+	var progress []int
+	var actions []string
+	for _, e := range collector.GetEventsByStep("work") {
+		if e.State == "step_progress" {
+			progress = append(progress, e.Progress)
+			actions = append(actions, e.CurrentAction)
+		}
+	}
+	assert.Equal(t, []int{25, 25, 50, 75, 75}, progress)
+	assert.Equal(t, []string{"Executing agent", "Plan 0/2 items done", "Plan 1/2 items done", "Plan 2/2 items done", "Processing results"}, actions)
+}
@@ -0,0 +1,77 @@
+package pipeline
+
+import (
+	"os"
+	"sort"
+
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/state"
+)
+
+// DefinitionDigests returns the content hashes of the definitions a run of
+// p starts from: the manifest, the pipeline YAML, and the system prompt of
+// every persona a step names. Definitions without a known digest (pipelines
+// or manifests built in code, unreadable prompt files, templated persona
+// names) are omitted.
+func DefinitionDigests(p *Pipeline, m *manifest.Manifest) []state.DefinitionDigest {
+	var defs []state.DefinitionDigest
+	if m != nil && m.Digest != "" {
+		defs = append(defs, state.DefinitionDigest{Kind: state.DefinitionManifest, Name: "wave.yaml", Digest: m.Digest})
+	}
+	if p != nil && p.Digest != "" {
+		defs = append(defs, state.DefinitionDigest{Kind: state.DefinitionPipeline, Name: p.Metadata.Name, Digest: p.Digest})
+	}
+	if p == nil || m == nil {
+		return defs
+	}
+
+	seen := make(map[string]bool)
+	var personas []state.DefinitionDigest
+	for _, step := range p.Steps {
+		if step.Persona == "" || seen[step.Persona] {
+			continue
+		}
+		seen[step.Persona] = true
+		persona := m.GetPersona(step.Persona)
+		if persona == nil || persona.SystemPromptFile == "" {
+			continue
+		}
+		// Read the prompt the same way step dispatch does so the digest
+		// matches what the adapter actually received.
+		data, err := os.ReadFile(persona.SystemPromptFile)
+		if err != nil {
+			continue
+		}
+		personas = append(personas, state.DefinitionDigest{
+			Kind:   state.DefinitionPersona,
+			Name:   step.Persona,
+			Path:   persona.SystemPromptFile,
+			Digest: manifest.Digest(data),
+		})
+	}
+	sort.Slice(personas, func(i, j int) bool { return personas[i].Name < personas[j].Name })
+	return append(defs, personas...)
+}
+
+// recordProvenance stores the run's definition digests so `wave compare`
+// can correlate behaviour changes with definition changes.
+func (e *DefaultPipelineExecutor) recordProvenance(execution *PipelineExecution) {
+	if e.store == nil {
+		return
+	}
+	// Best-effort like the other run-start writes: provenance is diagnostic
+	// and must never fail a run.
+	_ = e.store.SaveRunProvenance(execution.Status.ID, DefinitionDigests(execution.Pipeline, execution.Manifest))
+}
+
+// IdempotencyKey identifies a launch of p with input: the SHA-256 of the
+// pipeline definition digest and the input. Two launches with the same key
+// would do the same work, so a webhook delivered twice can be detected.
+// Pipelines built in code have no digest and fall back to their name.
+func IdempotencyKey(p *Pipeline, input string) string {
+	definition := p.Digest
+	if definition == "" {
+		definition = p.Metadata.Name
+	}
+	return manifest.Digest([]byte(definition + "\n" + input))
+}
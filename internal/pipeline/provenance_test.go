@@ -0,0 +1,114 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefinitionDigests(t *testing.T) {
+	tmpDir := t.TempDir()
+	promptPath := filepath.Join(tmpDir, "navigator.md")
+	require.NoError(t, os.WriteFile(promptPath, []byte("You are the navigator."), 0644))
+
+	m := testutil.CreateTestManifest(tmpDir)
+	m.Digest = manifest.Digest([]byte("manifest"))
+	nav := m.Personas["navigator"]
+	nav.SystemPromptFile = promptPath
+	m.Personas["navigator"] = nav
+
+	loaded, err := (&YAMLPipelineLoader{}).Unmarshal([]byte(`kind: WavePipeline
+metadata:
+  name: provenance
+steps:
+  - id: explore
+    persona: navigator
+    exec:
+      type: prompt
+      source: look around
+  - id: again
+    persona: navigator
+    exec:
+      type: prompt
+      source: look again
+  - id: templated
+    persona: "{{ input }}"
+    exec:
+      type: prompt
+      source: whoever
+`))
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded.Digest)
+
+	defs := DefinitionDigests(loaded, m)
+	assert.Equal(t, []state.DefinitionDigest{
+		{Kind: state.DefinitionManifest, Name: "wave.yaml", Digest: m.Digest},
+		{Kind: state.DefinitionPipeline, Name: "provenance", Digest: loaded.Digest},
+		{Kind: state.DefinitionPersona, Name: "navigator", Path: promptPath, Digest: manifest.Digest([]byte("You are the navigator."))},
+	}, defs)
+
+	// Editing the prompt changes its digest.
+	require.NoError(t, os.WriteFile(promptPath, []byte("You are a terse navigator."), 0644))
+	assert.NotEqual(t, defs[2].Digest, DefinitionDigests(loaded, m)[2].Digest)
+}
+
+func TestExecute_RecordsProvenance(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := state.NewStateStore(filepath.Join(tmpDir, "state.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	runID, err := store.CreateRun("provenance", "test")
+	require.NoError(t, err)
+
+	executor := NewDefaultPipelineExecutor(
+		adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`)),
+		WithEmitter(testutil.NewEventCollector()),
+		WithStateStore(store),
+		WithRunID(runID),
+	)
+	m := testutil.CreateTestManifest(tmpDir)
+	m.Digest = "manifest-digest"
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "provenance"},
+		Steps:    []Step{{ID: "step1", Persona: "navigator", Exec: ExecConfig{Source: "test"}}},
+		Digest:   "pipeline-digest",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, executor.Execute(ctx, p, m, "test"))
+
+	defs, err := store.GetRunProvenance(runID)
+	require.NoError(t, err)
+	assert.Equal(t, []state.DefinitionDigest{
+		{Kind: state.DefinitionManifest, Name: "wave.yaml", Digest: "manifest-digest"},
+		{Kind: state.DefinitionPipeline, Name: "provenance", Digest: "pipeline-digest"},
+	}, defs)
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	p := &Pipeline{Metadata: PipelineMetadata{Name: "impl-issue"}, Digest: "def-v1"}
+
+	key := IdempotencyKey(p, "https://github.com/org/repo/issues/42")
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, IdempotencyKey(p, "https://github.com/org/repo/issues/42"))
+	assert.NotEqual(t, key, IdempotencyKey(p, "https://github.com/org/repo/issues/43"))
+
+	// An edited pipeline definition is a different launch.
+	edited := &Pipeline{Metadata: p.Metadata, Digest: "def-v2"}
+	assert.NotEqual(t, key, IdempotencyKey(edited, "https://github.com/org/repo/issues/42"))
+
+	// Pipelines without a digest fall back to their name.
+	inCode := &Pipeline{Metadata: PipelineMetadata{Name: "impl-issue"}}
+	assert.Equal(t, IdempotencyKey(inCode, "x"), IdempotencyKey(&Pipeline{Metadata: PipelineMetadata{Name: "impl-issue"}}, "x"))
+	assert.NotEqual(t, IdempotencyKey(inCode, "x"), IdempotencyKey(&Pipeline{Metadata: PipelineMetadata{Name: "other"}}, "x"))
+}
@@ -0,0 +1,288 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/forge"
+	"github.com/recinq/wave/internal/github"
+	"github.com/recinq/wave/internal/httpx"
+)
+
+// PublishDef uploads step artifacts to one target once the pipeline
+// succeeds. Exactly one of GitHubRelease and HTTP is set. The URL of every
+// uploaded file is registered as an outcome of the step that produced it.
+type PublishDef struct {
+	Artifacts     []PublishArtifact    `yaml:"artifacts"`
+	GitHubRelease *GitHubReleaseTarget `yaml:"github_release,omitempty"`
+	HTTP          *HTTPUploadTarget    `yaml:"http,omitempty"`
+}
+
+// PublishArtifact selects a step output artifact to publish.
+type PublishArtifact struct {
+	Step     string `yaml:"step"`
+	Artifact string `yaml:"artifact"`
+	Name     string `yaml:"name,omitempty"` // Uploaded file name (templated); default the artifact file's base name
+}
+
+// GitHubReleaseTarget uploads artifacts as assets of the release for Tag,
+// creating the release when it does not exist. Assets with the same name
+// are replaced. Tag, Name and Repo accept template variables.
+type GitHubReleaseTarget struct {
+	Tag        string `yaml:"tag"`
+	Repo       string `yaml:"repo,omitempty"`   // owner/name; default {{ forge.owner }}/{{ forge.repo }}
+	Name       string `yaml:"name,omitempty"`   // Release title when creating; default Tag
+	Target     string `yaml:"target,omitempty"` // Commitish to tag when creating; default the repository's default branch
+	Draft      bool   `yaml:"draft,omitempty"`  // Create the release as a draft
+	Prerelease bool   `yaml:"prerelease,omitempty"`
+	APIURL     string `yaml:"api_url,omitempty"` // GitHub Enterprise API root; default https://api.github.com
+}
+
+// HTTPUploadTarget sends each artifact as the body of a request to URL. A
+// URL ending in "/" gets the file name appended. URL and header values
+// accept template variables and ${ENV} references, so credentials stay out
+// of the pipeline file.
+type HTTPUploadTarget struct {
+	URL      string            `yaml:"url"`
+	Method   string            `yaml:"method,omitempty"` // PUT (default) or POST
+	Headers  map[string]string `yaml:"headers,omitempty"`
+	URLField string            `yaml:"url_field,omitempty"` // JSON path of the published URL in the response; default the request URL
+}
+
+// publishFile is an artifact resolved for upload.
+type publishFile struct {
+	step string
+	name string
+	data []byte
+}
+
+// publishHTTPClient sends generic HTTP uploads. Artifacts can be large, so
+// the timeout is longer than for API calls.
+var publishHTTPClient = httpx.New(httpx.Config{
+	Timeout:    5 * time.Minute,
+	MaxRetries: 2,
+})
+
+// validatePublish checks the pipeline's publish targets and that every
+// published artifact is declared by its step.
+func validatePublish(p *Pipeline) error {
+	stepMap := make(map[string]*Step, len(p.Steps))
+	for i := range p.Steps {
+		stepMap[p.Steps[i].ID] = &p.Steps[i]
+	}
+	for i, def := range p.Publish {
+		if (def.GitHubRelease == nil) == (def.HTTP == nil) {
+			return fmt.Errorf("publish[%d]: exactly one of github_release and http is required", i)
+		}
+		if len(def.Artifacts) == 0 {
+			return fmt.Errorf("publish[%d]: artifacts is required", i)
+		}
+		for j, a := range def.Artifacts {
+			step, ok := stepMap[a.Step]
+			if !ok {
+				return fmt.Errorf("publish[%d].artifacts[%d] references non-existent step %q", i, j, a.Step)
+			}
+			found := false
+			for _, out := range step.OutputArtifacts {
+				found = found || out.Name == a.Artifact
+			}
+			if !found {
+				return fmt.Errorf("publish[%d].artifacts[%d]: step %q has no output artifact %q", i, j, a.Step, a.Artifact)
+			}
+		}
+		if r := def.GitHubRelease; r != nil {
+			if r.Tag == "" {
+				return fmt.Errorf("publish[%d]: github_release.tag is required", i)
+			}
+			if r.Repo != "" && !strings.Contains(r.Repo, "{{") && strings.Count(r.Repo, "/") != 1 {
+				return fmt.Errorf("publish[%d]: github_release.repo %q must be owner/name", i, r.Repo)
+			}
+		}
+		if h := def.HTTP; h != nil {
+			if h.URL == "" {
+				return fmt.Errorf("publish[%d]: http.url is required", i)
+			}
+			if h.Method != "" && h.Method != http.MethodPut && h.Method != http.MethodPost {
+				return fmt.Errorf("publish[%d]: http.method must be PUT or POST, got %q", i, h.Method)
+			}
+		}
+	}
+	return nil
+}
+
+// publishDeliverables runs the pipeline's publish targets after a
+// successful run. Failures are reported as warnings: the run's steps have
+// already succeeded and their artifacts remain in the workspace.
+func (e *DefaultPipelineExecutor) publishDeliverables(ctx context.Context, execution *PipelineExecution) {
+	if execution.Pipeline == nil || len(execution.Pipeline.Publish) == 0 {
+		return
+	}
+	pipelineID := execution.Status.ID
+	warn := func(msg string) {
+		e.emit(event.Event{
+			Timestamp:  time.Now(),
+			PipelineID: pipelineID,
+			State:      "warning",
+			Message:    msg,
+		})
+		if e.outcomeTracker != nil {
+			e.outcomeTracker.AddOutcomeWarning(msg)
+		}
+	}
+
+	for i, def := range execution.Pipeline.Publish {
+		files := make([]publishFile, 0, len(def.Artifacts))
+		for _, a := range def.Artifacts {
+			execution.mu.Lock()
+			path := execution.ArtifactPaths[a.Step+":"+a.Artifact]
+			execution.mu.Unlock()
+			if path == "" {
+				warn(fmt.Sprintf("publish[%d]: artifact %s:%s was not produced", i, a.Step, a.Artifact))
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				warn(fmt.Sprintf("publish[%d]: failed to read artifact %s:%s: %v", i, a.Step, a.Artifact, err))
+				continue
+			}
+			name := execution.Context.ResolvePlaceholders(a.Name)
+			if name == "" {
+				name = filepath.Base(path)
+			}
+			files = append(files, publishFile{step: a.Step, name: name, data: data})
+		}
+		if len(files) == 0 {
+			continue
+		}
+
+		var err error
+		switch {
+		case def.GitHubRelease != nil:
+			err = e.publishGitHubRelease(ctx, execution, def.GitHubRelease, files)
+		case def.HTTP != nil:
+			err = e.publishHTTP(ctx, execution, def.HTTP, files)
+		}
+		if err != nil {
+			warn(fmt.Sprintf("publish[%d]: %v", i, err))
+		}
+	}
+}
+
+// publishGitHubRelease uploads files as assets of the target's release.
+func (e *DefaultPipelineExecutor) publishGitHubRelease(ctx context.Context, execution *PipelineExecution, target *GitHubReleaseTarget, files []publishFile) error {
+	resolve := execution.Context.ResolvePlaceholders
+	tag := resolve(target.Tag)
+	repo := target.Repo
+	if repo == "" {
+		repo = "{{ forge.owner }}/{{ forge.repo }}"
+	}
+	owner, name, ok := strings.Cut(resolve(repo), "/")
+	if !ok || owner == "" || name == "" {
+		return fmt.Errorf("github release: no repository (set github_release.repo or a GitHub remote)")
+	}
+	client := github.NewClient(github.ClientConfig{Token: forge.ResolveToken(forge.ForgeGitHub), BaseURL: target.APIURL})
+
+	release, err := client.GetReleaseByTag(ctx, owner, name, tag)
+	var apiErr *github.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		title := resolve(target.Name)
+		if title == "" {
+			title = tag
+		}
+		release, err = client.CreateRelease(ctx, owner, name, github.CreateReleaseRequest{
+			TagName:         tag,
+			TargetCommitish: resolve(target.Target),
+			Name:            title,
+			Draft:           target.Draft,
+			Prerelease:      target.Prerelease,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("github release %s: %w", tag, err)
+	}
+
+	for _, f := range files {
+		for _, existing := range release.Assets {
+			if existing.Name == f.name {
+				if err := client.DeleteReleaseAsset(ctx, owner, name, existing.ID); err != nil {
+					return fmt.Errorf("github release %s: replace asset %s: %w", tag, f.name, err)
+				}
+			}
+		}
+		asset, err := client.UploadReleaseAsset(ctx, release, f.name, publishContentType(f.name), f.data)
+		if err != nil {
+			return fmt.Errorf("github release %s: upload %s: %w", tag, f.name, err)
+		}
+		e.recordPublished(f, asset.BrowserDownloadURL, fmt.Sprintf("Asset of release %s", tag))
+	}
+	return nil
+}
+
+// publishHTTP uploads each file to the target URL.
+func (e *DefaultPipelineExecutor) publishHTTP(ctx context.Context, execution *PipelineExecution, target *HTTPUploadTarget, files []publishFile) error {
+	method := target.Method
+	if method == "" {
+		method = http.MethodPut
+	}
+	baseURL := os.ExpandEnv(execution.Context.ResolvePlaceholders(target.URL))
+
+	for _, f := range files {
+		uploadURL := baseURL
+		if strings.HasSuffix(uploadURL, "/") {
+			uploadURL += f.name
+		}
+		req, err := http.NewRequestWithContext(ctx, method, uploadURL, bytes.NewReader(f.data))
+		if err != nil {
+			return fmt.Errorf("upload %s: %w", f.name, err)
+		}
+		req.Header.Set("Content-Type", publishContentType(f.name))
+		for k, v := range target.Headers {
+			req.Header.Set(k, os.ExpandEnv(execution.Context.ResolvePlaceholders(v)))
+		}
+
+		resp, err := publishHTTPClient.Do(ctx, req)
+		if err != nil {
+			return fmt.Errorf("upload %s: %w", f.name, err)
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("upload %s: %s returned status %d", f.name, req.URL.Host, resp.StatusCode)
+		}
+
+		published := uploadURL
+		if target.URLField != "" {
+			if published, err = ExtractJSONPath(body, target.URLField); err != nil {
+				return fmt.Errorf("upload %s: url_field %s: %w", f.name, target.URLField, err)
+			}
+		}
+		e.recordPublished(f, published, fmt.Sprintf("Uploaded to %s", req.URL.Host))
+	}
+	return nil
+}
+
+// recordPublished registers the URL of an uploaded file as an outcome of
+// the step that produced it.
+func (e *DefaultPipelineExecutor) recordPublished(f publishFile, url, description string) {
+	if e.outcomeTracker != nil {
+		e.outcomeTracker.AddURL(f.step, f.name, url, description)
+	}
+}
+
+// publishContentType returns the MIME type for an uploaded file name.
+func publishContentType(name string) string {
+	if t := mime.TypeByExtension(filepath.Ext(name)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
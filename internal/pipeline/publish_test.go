@@ -0,0 +1,231 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePublish(t *testing.T) {
+	steps := []Step{{ID: "build", Persona: "craftsman", OutputArtifacts: []ArtifactDef{{Name: "binary", Path: "dist/app"}}}}
+	artifacts := []PublishArtifact{{Step: "build", Artifact: "binary"}}
+	tests := []struct {
+		name    string
+		publish []PublishDef
+		wantErr string
+	}{
+		{
+			name:    "github release",
+			publish: []PublishDef{{Artifacts: artifacts, GitHubRelease: &GitHubReleaseTarget{Tag: "v1.0.0", Repo: "acme/app"}}},
+		},
+		{
+			name:    "http",
+			publish: []PublishDef{{Artifacts: artifacts, HTTP: &HTTPUploadTarget{URL: "https://files.example.com/", Method: "POST"}}},
+		},
+		{
+			name:    "no target",
+			publish: []PublishDef{{Artifacts: artifacts}},
+			wantErr: "exactly one of github_release and http is required",
+		},
+		{
+			name: "two targets",
+			publish: []PublishDef{{
+				Artifacts:     artifacts,
+				GitHubRelease: &GitHubReleaseTarget{Tag: "v1"},
+				HTTP:          &HTTPUploadTarget{URL: "https://files.example.com/"},
+			}},
+			wantErr: "exactly one of github_release and http is required",
+		},
+		{
+			name:    "unknown step",
+			publish: []PublishDef{{Artifacts: []PublishArtifact{{Step: "ship", Artifact: "binary"}}, HTTP: &HTTPUploadTarget{URL: "https://x"}}},
+			wantErr: `references non-existent step "ship"`,
+		},
+		{
+			name:    "undeclared artifact",
+			publish: []PublishDef{{Artifacts: []PublishArtifact{{Step: "build", Artifact: "docs"}}, HTTP: &HTTPUploadTarget{URL: "https://x"}}},
+			wantErr: `step "build" has no output artifact "docs"`,
+		},
+		{
+			name:    "missing tag",
+			publish: []PublishDef{{Artifacts: artifacts, GitHubRelease: &GitHubReleaseTarget{}}},
+			wantErr: "github_release.tag is required",
+		},
+		{
+			name:    "malformed repo",
+			publish: []PublishDef{{Artifacts: artifacts, GitHubRelease: &GitHubReleaseTarget{Tag: "v1", Repo: "app"}}},
+			wantErr: "must be owner/name",
+		},
+		{
+			name:    "bad method",
+			publish: []PublishDef{{Artifacts: artifacts, HTTP: &HTTPUploadTarget{URL: "https://x", Method: "DELETE"}}},
+			wantErr: "http.method must be PUT or POST",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &DAGValidator{}
+			err := v.ValidateDAG(&Pipeline{Metadata: PipelineMetadata{Name: "p"}, Steps: steps, Publish: tt.publish})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// newPublishExecution returns an execution whose build step produced
+// dist/app, with publish as the pipeline's publish targets.
+func newPublishExecution(t *testing.T, publish ...PublishDef) *PipelineExecution {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "dist", "app")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("binary"), 0644))
+
+	ctx := NewPipelineContext("run-1", "release", "build")
+	ctx.SetCustomVariable("forge.owner", "acme")
+	ctx.SetCustomVariable("forge.repo", "app")
+	ctx.SetCustomVariable("version", "1.2.0")
+	return &PipelineExecution{
+		Pipeline: &Pipeline{
+			Steps:   []Step{{ID: "build", OutputArtifacts: []ArtifactDef{{Name: "binary", Path: "dist/app"}}}},
+			Publish: publish,
+		},
+		States:        make(map[string]string),
+		ArtifactPaths: map[string]string{"build:binary": path},
+		Context:       ctx,
+		Status:        &PipelineStatus{ID: "run-1"},
+	}
+}
+
+func TestPublishDeliverables_HTTP(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received[r.Method+" "+r.URL.Path] = r.Header.Get("Authorization") + "|" + string(body)
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]string{"location": "https://cdn.example.com" + r.URL.Path})
+	}))
+	defer server.Close()
+	t.Setenv("UPLOAD_TOKEN", "s3cret")
+
+	executor := NewDefaultPipelineExecutor(nil, WithEmitter(testutil.NewEventCollector()))
+	executor.outcomeTracker = state.NewOutcomeTracker("run-1", nil)
+	execution := newPublishExecution(t, PublishDef{
+		Artifacts: []PublishArtifact{{Step: "build", Artifact: "binary", Name: "app-{{ version }}"}},
+		HTTP: &HTTPUploadTarget{
+			URL:      server.URL + "/releases/",
+			Headers:  map[string]string{"Authorization": "Bearer ${UPLOAD_TOKEN}"},
+			URLField: ".location",
+		},
+	})
+
+	executor.publishDeliverables(context.Background(), execution)
+
+	assert.Equal(t, map[string]string{"PUT /releases/app-1.2.0": "Bearer s3cret|binary"}, received)
+	outcomes := executor.outcomeTracker.GetAll()
+	require.Len(t, outcomes, 1)
+	assert.Equal(t, state.OutcomeTypeURL, outcomes[0].Type)
+	assert.Equal(t, "build", outcomes[0].StepID)
+	assert.Equal(t, "https://cdn.example.com/releases/app-1.2.0", outcomes[0].Value)
+	assert.Empty(t, executor.outcomeTracker.OutcomeWarnings())
+}
+
+func TestPublishDeliverables_GitHubRelease(t *testing.T) {
+	t.Setenv("GH_TOKEN", "gh-token")
+	var mu sync.Mutex
+	var calls []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		assert.Equal(t, "Bearer gh-token", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/app/releases/tags/v1.2.0":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/app/releases":
+			var req map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			assert.Equal(t, "v1.2.0", req["tag_name"])
+			assert.Equal(t, "Release 1.2.0", req["name"])
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 7, "tag_name": "v1.2.0", "upload_url": "` + server.URL + `/uploads/7/assets{?name,label}"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/uploads/7/assets":
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, "binary", string(body))
+			name := r.URL.Query().Get("name")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 9, "name": "` + name + `", "browser_download_url": "https://github.com/acme/app/releases/download/v1.2.0/` + name + `"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusTeapot)
+		}
+	}))
+	defer server.Close()
+
+	executor := NewDefaultPipelineExecutor(nil, WithEmitter(testutil.NewEventCollector()))
+	executor.outcomeTracker = state.NewOutcomeTracker("run-1", nil)
+	execution := newPublishExecution(t, PublishDef{
+		Artifacts: []PublishArtifact{{Step: "build", Artifact: "binary"}},
+		GitHubRelease: &GitHubReleaseTarget{
+			Tag:    "v{{ version }}",
+			Name:   "Release {{ version }}",
+			APIURL: server.URL,
+		},
+	})
+
+	executor.publishDeliverables(context.Background(), execution)
+
+	assert.Empty(t, executor.outcomeTracker.OutcomeWarnings())
+	assert.Equal(t, []string{
+		"GET /repos/acme/app/releases/tags/v1.2.0",
+		"POST /repos/acme/app/releases",
+		"POST /uploads/7/assets",
+	}, calls)
+	outcomes := executor.outcomeTracker.GetAll()
+	require.Len(t, outcomes, 1)
+	assert.Equal(t, "app", outcomes[0].Label)
+	assert.Equal(t, "https://github.com/acme/app/releases/download/v1.2.0/app", outcomes[0].Value)
+}
+
+func TestPublishDeliverables_FailureIsWarning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	collector := testutil.NewEventCollector()
+	executor := NewDefaultPipelineExecutor(nil, WithEmitter(collector))
+	executor.outcomeTracker = state.NewOutcomeTracker("run-1", nil)
+	execution := newPublishExecution(t,
+		PublishDef{Artifacts: []PublishArtifact{{Step: "build", Artifact: "binary"}}, HTTP: &HTTPUploadTarget{URL: server.URL + "/"}},
+		PublishDef{Artifacts: []PublishArtifact{{Step: "build", Artifact: "missing"}}, HTTP: &HTTPUploadTarget{URL: server.URL + "/"}},
+	)
+
+	executor.publishDeliverables(context.Background(), execution)
+
+	warnings := executor.outcomeTracker.OutcomeWarnings()
+	require.Len(t, warnings, 2)
+	assert.True(t, strings.HasPrefix(warnings[0], "publish[0]: upload app:"), warnings[0])
+	assert.Contains(t, warnings[0], "returned status 403")
+	assert.Equal(t, "publish[1]: artifact build:missing was not produced", warnings[1])
+	assert.Zero(t, executor.outcomeTracker.Count())
+}
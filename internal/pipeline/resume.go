@@ -2,6 +2,7 @@ package pipeline
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +12,7 @@ import (
 	"github.com/recinq/wave/internal/event"
 	"github.com/recinq/wave/internal/forge"
 	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/state"
 )
 
 // ResumeManager handles pipeline resumption from specific steps
@@ -159,7 +161,7 @@ func (r *ResumeManager) ResumeFromStep(ctx context.Context, p *Pipeline, m *mani
 	// resume operation. Only fall back to creating a new ID when running without a store.
 	pipelineID := r.executor.runID
 	if pipelineID == "" {
-		pipelineID = r.executor.createRunID(pipelineName, m.Runtime.PipelineIDHashLength, input)
+		pipelineID = r.executor.createRunID(pipelineName, m.Runtime, input)
 	}
 
 	// Create new execution with preserved artifacts, state, and failure context
@@ -173,6 +175,10 @@ func (r *ResumeManager) ResumeFromStep(ctx context.Context, p *Pipeline, m *mani
 	pipelineContext := newContextWithProject(pipelineID, pipelineName, fromStep, m)
 	forgeInfo := forge.DetectFromGitRemotesWithOverride(m.Metadata.Forge)
 	InjectForgeVariables(pipelineContext, forgeInfo)
+	injectRunTimeVariables(pipelineContext, r.executor.runClock())
+	if err := injectPipelineVars(pipelineContext, p.Vars, r.executor.varOverrides); err != nil {
+		return err
+	}
 
 	// Re-publish bare-name artifact paths from the prior run so
 	// sub-pipeline injection lookups (executor.go:5752 — uses
@@ -214,6 +220,9 @@ func (r *ResumeManager) ResumeFromStep(ctx context.Context, p *Pipeline, m *mani
 				continue
 			}
 			branch := step.Workspace.Branch
+			if branch == "" && step.Workspace.Base == "" {
+				branch = m.Runtime.Naming.Branch
+			}
 			if branch != "" {
 				branch = pipelineContext.ResolvePlaceholders(branch)
 			}
@@ -235,6 +244,24 @@ func (r *ResumeManager) ResumeFromStep(ctx context.Context, p *Pipeline, m *mani
 		}
 	}
 
+	// Checkpoints of resumed runs index steps in the full pipeline, not the
+	// subpipeline, so they line up with the ones carried over below.
+	for i, step := range p.Steps {
+		if step.ID == fromStep {
+			execution.stepIndexBase = i
+			break
+		}
+	}
+
+	// Carry the restored checkpoints into the resumed run so that it can in
+	// turn be resumed, or forked, without the run it was resumed from.
+	if r.executor.store != nil && pipelineID != runIDForResume {
+		for _, cp := range resumeState.Checkpoints {
+			cp.RunID = pipelineID
+			_ = r.executor.store.SaveCheckpoint(&cp)
+		}
+	}
+
 	// Store execution state
 	r.executor.mu.Lock()
 	r.executor.pipelines[pipelineID] = execution
@@ -255,6 +282,7 @@ type ResumeState struct {
 	FailureContexts     map[string]*AttemptContext // stepID -> failure context from prior run
 	ReworkTransitions   map[string]string          // failedStepID -> reworkStepID
 	DiscoveredWorktrees []string                   // absolute paths of worktrees found from prior run
+	Checkpoints         []state.CheckpointRecord   // prior run checkpoints of the restored steps, carried into the resumed run
 }
 
 // lookupStepPersona finds the persona for a step by ID in the full pipeline.
@@ -268,9 +296,11 @@ func (r *ResumeManager) lookupStepPersona(p *Pipeline, stepID string) string {
 }
 
 // loadResumeState loads state from previous execution for resumption.
-// When priorRunID is non-empty, only that run's workspace directory is searched
-// for artifact paths. Otherwise, all matching run directories are scanned and
-// the most recent match is used.
+// When priorRunID is non-empty, steps checkpointed in that run are restored
+// from the state store — workspace, artifacts and results — so a run can be
+// resumed after the process that ran it has exited. Remaining steps are
+// found by searching only that run's workspace directory. Otherwise, all
+// matching run directories are scanned and the most recent match is used.
 func (r *ResumeManager) loadResumeState(p *Pipeline, fromStep string, priorRunID ...string) (*ResumeState, error) { //nolint:unparam // error return kept for future use
 	state := &ResumeState{
 		States:            make(map[string]string),
@@ -312,12 +342,19 @@ func (r *ResumeManager) loadResumeState(p *Pipeline, fromStep string, priorRunID
 		}
 	}
 
-	// Load completed steps state from workspace
+	checkpoints := r.loadCheckpoints(resolvedRunID)
+
+	// Load completed steps state from checkpoints, then from workspace
 	for _, step := range p.Steps {
 		if step.ID == fromStep {
 			break // Don't include the target step in completed steps
 		}
 
+		if cp, ok := checkpoints[step.ID]; ok {
+			restoreCheckpoint(state, step, cp)
+			continue
+		}
+
 		// Resolve workspace path for this step
 		stepWorkspace := ""
 		if step.Workspace.Ref != "" {
@@ -434,6 +471,59 @@ func (r *ResumeManager) loadResumeState(p *Pipeline, fromStep string, priorRunID
 	return state, nil
 }
 
+// loadCheckpoints returns the prior run's checkpoints keyed by step ID. It
+// returns nil when there is no prior run or state store to read them from.
+func (r *ResumeManager) loadCheckpoints(runID string) map[string]state.CheckpointRecord {
+	if runID == "" || r.executor.store == nil {
+		return nil
+	}
+	records, err := r.executor.store.GetCheckpoints(runID)
+	if err != nil {
+		return nil
+	}
+	checkpoints := make(map[string]state.CheckpointRecord, len(records))
+	for _, cp := range records {
+		checkpoints[cp.StepID] = cp
+	}
+	return checkpoints
+}
+
+// restoreCheckpoint marks step completed in rs with the workspace, artifacts
+// and results its checkpoint recorded.
+func restoreCheckpoint(rs *ResumeState, step Step, cp state.CheckpointRecord) {
+	rs.States[step.ID] = stateCompleted
+	rs.CompletedSteps = append(rs.CompletedSteps, step.ID)
+	rs.Checkpoints = append(rs.Checkpoints, cp)
+
+	if cp.WorkspacePath != "" {
+		rs.WorkspacePaths[step.ID] = cp.WorkspacePath
+		if step.Workspace.Type == "worktree" {
+			if absWt, _ := filepath.Abs(cp.WorkspacePath); absWt != "" {
+				rs.DiscoveredWorktrees = append(rs.DiscoveredWorktrees, absWt)
+			}
+		}
+	}
+
+	// The snapshot is cumulative; take only the entries this step produced.
+	var artifacts map[string]string
+	if err := json.Unmarshal([]byte(cp.ArtifactSnapshot), &artifacts); err == nil {
+		prefix := step.ID + ":"
+		for key, path := range artifacts {
+			if name, ok := strings.CutPrefix(key, prefix); ok {
+				rs.ArtifactPaths[key] = path
+				rs.BareArtifactPaths[name] = path
+			}
+		}
+	}
+
+	if cp.StepResults != "" {
+		var results map[string]interface{}
+		if err := json.Unmarshal([]byte(cp.StepResults), &results); err == nil {
+			rs.Results[step.ID] = results
+		}
+	}
+}
+
 // hasStepArtifacts checks if a workspace directory contains the output artifacts for a step.
 func hasStepArtifacts(wsPath string, step Step) bool {
 	if len(step.OutputArtifacts) == 0 {
@@ -497,6 +587,7 @@ func (r *ResumeManager) createResumeSubpipeline(p *Pipeline, fromStep string) *P
 		Kind:     p.Kind,
 		Metadata: p.Metadata,
 		Input:    p.Input,
+		Vars:     p.Vars,
 		Steps:    subSteps,
 	}
 
@@ -560,6 +651,7 @@ func (r *ResumeManager) executeResumedPipeline(ctx context.Context, execution *P
 						StepID:     step.ID,
 						State:      stateFailed,
 						Message:    err.Error(),
+						LogPath:    adapterLogRef(execution, step.ID),
 					})
 				}
 
@@ -1788,3 +1788,80 @@ func TestResumeFromStep_ReusesWorktree(t *testing.T) {
 		t.Errorf("WorktreePaths AbsPath = %q, want %q", wtInfo.AbsPath, absWt)
 	}
 }
+
+// TestResumeFromStep_RebuildsFromCheckpoints resumes a run with an executor
+// that never saw it, as `wave resume` does after the original process exits:
+// the completed step comes back from the state store's checkpoints even
+// though its workspace is not where a directory scan would look.
+func TestResumeFromStep_RebuildsFromCheckpoints(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	_ = os.Chdir(tmpDir)
+	defer func() { _ = os.Chdir(origDir) }()
+
+	store, err := state.NewStateStore(filepath.Join(tmpDir, "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	m := testutil.CreateTestManifest(filepath.Join(tmpDir, "ws"))
+	full := &Pipeline{
+		Metadata: PipelineMetadata{Name: "restart-test"},
+		Steps: []Step{
+			{ID: "build", Type: StepTypeCommand, Script: "echo built"},
+			{ID: "ship", Type: StepTypeCommand, Script: "echo shipped", Dependencies: []string{"build"}},
+		},
+	}
+
+	// First process: only "build" ran before it exited.
+	priorRunID, err := store.CreateRun("restart-test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := NewDefaultPipelineExecutor(adaptertest.NewMockAdapter(), WithRunID(priorRunID), WithStateStore(store))
+	partial := &Pipeline{Metadata: full.Metadata, Steps: full.Steps[:1]}
+	if err := first.Execute(context.Background(), partial, m, ""); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+
+	// Second process: a fresh executor resumes from "ship".
+	resumeRunID, err := store.CreateRun("restart-test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := NewDefaultPipelineExecutor(adaptertest.NewMockAdapter(), WithRunID(resumeRunID), WithStateStore(store))
+	manager := NewResumeManager(second)
+
+	rs, err := manager.loadResumeState(full, "ship", priorRunID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.States["build"] != stateCompleted {
+		t.Errorf("build state = %q, want completed", rs.States["build"])
+	}
+	if !strings.HasPrefix(rs.WorkspacePaths["build"], m.Runtime.WorkspaceRoot) {
+		t.Errorf("build workspace = %q, want one under %s", rs.WorkspacePaths["build"], m.Runtime.WorkspaceRoot)
+	}
+	if got, _ := rs.Results["build"]["stdout"].(string); got != "built\n" {
+		t.Errorf("build stdout = %q, want %q", got, "built\n")
+	}
+
+	if err := manager.ResumeFromStep(context.Background(), full, m, "", "ship", true, priorRunID); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+
+	// The resumed run carries the restored checkpoint, so it can itself be
+	// resumed, and indexes its own steps in the full pipeline.
+	checkpoints, err := store.GetCheckpoints(resumeRunID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, cp := range checkpoints {
+		got = append(got, fmt.Sprintf("%s@%d", cp.StepID, cp.StepIndex))
+	}
+	if want := []string{"build@0", "ship@1"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("resumed run checkpoints = %v, want %v", got, want)
+	}
+}
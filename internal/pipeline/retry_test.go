@@ -268,6 +268,24 @@ func TestRetryConfig_Validate(t *testing.T) {
 			},
 			wantErr: "rework_step is set but on_failure is \"\" (must be \"rework\")",
 		},
+		{
+			name:   "retry_on with known kinds is valid",
+			config: RetryConfig{RetryOn: []string{"rate_limit", "transient"}, NoRetryOn: []string{"contract_violation"}},
+		},
+		{
+			name:    "retry_on with unknown kind is invalid",
+			config:  RetryConfig{RetryOn: []string{"flaky"}},
+			wantErr: "retry_on: unknown failure class or category \"flaky\"",
+		},
+		{
+			name:    "no_retry_on with unknown kind is invalid",
+			config:  RetryConfig{NoRetryOn: []string{"schema_failed"}},
+			wantErr: "no_retry_on: unknown failure class or category \"schema_failed\"",
+		},
+		{
+			name:   "contract_failed alias is valid",
+			config: RetryConfig{RetryOn: []string{"rate_limit", "adapter_crash"}, NoRetryOn: []string{"contract_failed"}},
+		},
 	}
 
 	for _, tt := range tests {
@@ -351,3 +369,29 @@ func TestRetryConfig_Validate_UnknownPolicy(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unknown retry policy")
 }
+
+func TestRetryConfig_ShouldRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   RetryConfig
+		class    string
+		category string
+		expected bool
+	}{
+		{"default retries transient", RetryConfig{}, FailureClassTransient, FailureCategoryTimeout, true},
+		{"default skips deterministic", RetryConfig{}, FailureClassDeterministic, FailureCategorySandboxDenial, false},
+		{"default retries contract failure", RetryConfig{}, FailureClassContractFailure, FailureCategoryContractViolation, true},
+		{"no_retry_on category blocks default", RetryConfig{NoRetryOn: []string{FailureCategoryContractViolation}}, FailureClassContractFailure, FailureCategoryContractViolation, false},
+		{"no_retry_on class blocks default", RetryConfig{NoRetryOn: []string{FailureClassTransient}}, FailureClassTransient, FailureCategoryRateLimit, false},
+		{"retry_on category allows deterministic", RetryConfig{RetryOn: []string{FailureCategorySandboxDenial}}, FailureClassDeterministic, FailureCategorySandboxDenial, true},
+		{"retry_on excludes unlisted", RetryConfig{RetryOn: []string{FailureCategoryRateLimit}}, FailureClassTransient, FailureCategoryTimeout, false},
+		{"no_retry_on wins over retry_on", RetryConfig{RetryOn: []string{FailureCategoryRateLimit}, NoRetryOn: []string{FailureClassTransient}}, FailureClassTransient, FailureCategoryRateLimit, false},
+		{"no_retry_on contract_failed alias blocks contract failure", RetryConfig{NoRetryOn: []string{"contract_failed"}}, FailureClassContractFailure, FailureCategoryContractViolation, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.config.ShouldRetry(tt.class, tt.category))
+		})
+	}
+}
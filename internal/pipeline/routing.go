@@ -39,7 +39,7 @@ var strongestPersonaKeywords = []string{
 // The tier is one of TierCheapest, TierBalanced, or TierStrongest.
 //
 // Classification heuristics (evaluated in order):
-//   - cheapest: persona name contains a lightweight keyword, OR step type is "command"/"conditional"
+//   - cheapest: persona name contains a lightweight keyword, OR step type is "command"/"conditional"/"test_impact"
 //   - strongest: persona name contains a complex keyword, OR step uses sub_pipeline/loop/branch/aggregate
 //   - balanced: fallthrough for everything else (balance of cost and capability)
 func ClassifyStepComplexity(step *Step, persona *manifest.Persona, personaName string) string {
@@ -47,7 +47,7 @@ func ClassifyStepComplexity(step *Step, persona *manifest.Persona, personaName s
 	lowerName := strings.ToLower(personaName)
 
 	// Check cheapest signals — lightweight operations route to cheaper models.
-	if step != nil && (step.Type == StepTypeCommand || step.Type == StepTypeConditional) {
+	if step != nil && (step.Type == StepTypeCommand || step.Type == StepTypeConditional || step.Type == StepTypeTestImpact) {
 		return TierCheapest
 	}
 	for _, kw := range cheapestPersonaKeywords {
@@ -5,6 +5,9 @@ import (
 	"encoding/hex"
 	"fmt"
 	"time"
+
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/state"
 )
 
 const defaultHashLength = 8
@@ -21,6 +24,32 @@ func GenerateRunID(name string, hashLength int) string {
 	return fmt.Sprintf("%s-%s", name, suffix)
 }
 
+// NewRunID generates a run ID for name in the format configured by
+// runtime.naming.run_id, or with GenerateRunID when none is configured.
+func NewRunID(rt manifest.Runtime, name string) string {
+	if f := RunIDFormatter(rt); f != nil {
+		return f(name, time.Now())
+	}
+	return GenerateRunID(name, rt.PipelineIDHashLength)
+}
+
+// RunIDFormatter returns the state.RunIDFormatter for runtime.naming.run_id,
+// or nil when the manifest keeps the built-in formats. {{ hash }} is
+// pipeline_id_hash_length random hex characters (default 8).
+func RunIDFormatter(rt manifest.Runtime) state.RunIDFormatter {
+	if rt.Naming.RunID == "" {
+		return nil
+	}
+	hashLength := rt.PipelineIDHashLength
+	if hashLength <= 0 {
+		hashLength = defaultHashLength
+	}
+	naming := rt.Naming
+	return func(name string, now time.Time) string {
+		return naming.FormatRunID(name, generateHexSuffix(hashLength), now)
+	}
+}
+
 // generateHexSuffix generates a random hex string of the specified length.
 // Falls back to timestamp-based entropy if crypto/rand fails.
 func generateHexSuffix(length int) string {
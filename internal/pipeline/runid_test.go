@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/recinq/wave/internal/manifest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -97,3 +98,11 @@ func TestTimestampFallback(t *testing.T) {
 	re := regexp.MustCompile(`^[0-9a-f]+$`)
 	assert.Regexp(t, re, result, "fallback should return valid hex")
 }
+
+func TestNewRunID_Naming(t *testing.T) {
+	rt := manifest.Runtime{PipelineIDHashLength: 6}
+	assert.Regexp(t, regexp.MustCompile(`^build-[0-9a-f]{6}$`), NewRunID(rt, "build"))
+
+	rt.Naming = manifest.NamingConfig{RunID: "acme-{{ pipeline }}-{{ date }}-{{ hash }}", DateLayout: "20060102"}
+	assert.Regexp(t, regexp.MustCompile(`^acme-build-\d{8}-[0-9a-f]{6}$`), NewRunID(rt, "build"))
+}
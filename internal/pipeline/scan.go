@@ -18,10 +18,16 @@ import (
 // fail to parse. Strict validation belongs to the executor's load path
 // (YAMLPipelineLoader), not to discovery scans.
 func LoadPipelineLenient(data []byte) (*Pipeline, error) {
+	// Templated steps would otherwise show up without persona or exec.
+	if expanded, err := expandStepTemplates(data); err == nil {
+		data = expanded
+	}
 	var p Pipeline
 	if err := yaml.Unmarshal(data, &p); err != nil {
 		return nil, fmt.Errorf("parse pipeline: %w", err)
 	}
+	// Best-effort, so scanners see the schema_path a schema_ref names.
+	_ = resolveSchemaRefs(&p)
 	return &p, nil
 }
 
@@ -0,0 +1,39 @@
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/recinq/wave/internal/contract"
+)
+
+// resolveSchemaRefs turns every contract schema_ref into the schema_path of
+// the registered version it names, so the rest of Wave sees an ordinary
+// schema_path. Unpinned references resolve to the latest version.
+func resolveSchemaRefs(p *Pipeline) error {
+	resolve := func(step *Step, c *ContractConfig) error {
+		if c.SchemaRef == "" {
+			return nil
+		}
+		if c.SchemaPath != "" || c.Schema != "" {
+			return fmt.Errorf("step %q: contract sets schema_ref together with schema_path or schema", step.ID)
+		}
+		path, err := contract.ResolveSchemaRef(contract.SchemaRegistryDir, c.SchemaRef)
+		if err != nil {
+			return fmt.Errorf("step %q: %w", step.ID, err)
+		}
+		c.SchemaPath = path
+		return nil
+	}
+	for i := range p.Steps {
+		step := &p.Steps[i]
+		if err := resolve(step, &step.Handover.Contract); err != nil {
+			return err
+		}
+		for j := range step.Handover.Contracts {
+			if err := resolve(step, &step.Handover.Contracts[j]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recinq/wave/internal/contract"
+)
+
+func TestResolveSchemaRefs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, v := range []int{1, 2} {
+		path := contract.SchemaVersionPath(contract.SchemaRegistryDir, "issue-content", v)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(`{"type":"object"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := &Pipeline{Steps: []Step{
+		{ID: "pinned", Handover: HandoverConfig{Contract: ContractConfig{Type: "json_schema", SchemaRef: "issue-content@v1"}}},
+		{ID: "latest", Handover: HandoverConfig{Contracts: []ContractConfig{{Type: "json_schema", SchemaRef: "issue-content"}}}},
+	}}
+	if err := resolveSchemaRefs(p); err != nil {
+		t.Fatalf("resolveSchemaRefs() error = %v", err)
+	}
+	if got, want := p.Steps[0].Handover.Contract.SchemaPath, filepath.Join(".agents", "schemas", "issue-content", "v1.schema.json"); got != want {
+		t.Errorf("pinned schema_path = %q, want %q", got, want)
+	}
+	if got, want := p.Steps[1].Handover.Contracts[0].SchemaPath, filepath.Join(".agents", "schemas", "issue-content", "v2.schema.json"); got != want {
+		t.Errorf("latest schema_path = %q, want %q", got, want)
+	}
+
+	for name, c := range map[string]ContractConfig{
+		"missing version": {Type: "json_schema", SchemaRef: "issue-content@v3"},
+		"both set":        {Type: "json_schema", SchemaRef: "issue-content@v1", SchemaPath: "x.json"},
+	} {
+		p := &Pipeline{Steps: []Step{{ID: "s", Handover: HandoverConfig{Contract: c}}}}
+		if err := resolveSchemaRefs(p); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
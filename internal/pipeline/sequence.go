@@ -39,6 +39,7 @@ type SequenceExecutor struct {
 	newExecutor     func(opts ...ExecutorOption) *DefaultPipelineExecutor
 	baseOpts        []ExecutorOption
 	store           state.StateStore
+	parentRunID     string                       // group run the pipeline runs are recorded under
 	mu              sync.Mutex                   // protects pipelineOutputs
 	pipelineOutputs map[string]map[string][]byte // pipelineName -> artifactName -> data
 }
@@ -63,6 +64,23 @@ func NewSequenceExecutor(
 	}
 }
 
+// SetParentRunID records every pipeline run the sequence starts as a child
+// of runID, so `wave status` and the dashboard show the sequence as one run
+// tree instead of unrelated top-level runs.
+func (s *SequenceExecutor) SetParentRunID(runID string) {
+	s.parentRunID = runID
+}
+
+// linkChildRun attaches a pipeline run to the sequence's group run.
+// Best-effort: linkage only affects how runs are grouped for display.
+func (s *SequenceExecutor) linkChildRun(runID, pipelineName string) {
+	if s.store == nil || s.parentRunID == "" || runID == s.parentRunID {
+		return
+	}
+	_ = s.store.SetParentRun(runID, s.parentRunID, "")
+	_ = s.store.SetRunComposition(runID, state.RunKindSubPipelineChild, pipelineName, "", nil, nil)
+}
+
 // Execute runs the given pipelines in sequence. Each pipeline gets the same
 // input string. If any pipeline fails, execution stops and an error is
 // returned along with the partial result.
@@ -104,9 +122,10 @@ func (s *SequenceExecutor) Execute(ctx context.Context, pipelines []*Pipeline, m
 		if s.store != nil {
 			if storeRunID, err := s.store.CreateRun(pipelineName, input); err == nil {
 				runID = storeRunID
+				s.linkChildRun(runID, pipelineName)
 			}
 		}
-		opts = append(opts, WithRunID(runID))
+		opts = append(opts, WithRunID(runID), withNestedRun())
 		if s.emitter != nil {
 			opts = append(opts, WithEmitter(s.emitter))
 		}
@@ -346,9 +365,10 @@ func (s *SequenceExecutor) executeSinglePipeline(ctx context.Context, p *Pipelin
 	if s.store != nil {
 		if storeRunID, err := s.store.CreateRun(pipelineName, input); err == nil {
 			runID = storeRunID
+			s.linkChildRun(runID, pipelineName)
 		}
 	}
-	opts = append(opts, WithRunID(runID))
+	opts = append(opts, WithRunID(runID), withNestedRun())
 	if s.emitter != nil {
 		opts = append(opts, WithEmitter(s.emitter))
 	}
@@ -14,6 +14,7 @@ import (
 	"github.com/recinq/wave/internal/adapter"
 	"github.com/recinq/wave/internal/adapter/adaptertest"
 	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/state"
 	"github.com/recinq/wave/internal/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -127,6 +128,42 @@ func TestSequenceExecutor_MultiplePipelines(t *testing.T) {
 	assert.True(t, collector.HasEventWithState(event.StateSequenceCompleted))
 }
 
+func TestSequenceExecutor_LinksRunsToParent(t *testing.T) {
+	mockAdapter := adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`))
+
+	tmpDir := t.TempDir()
+	m := testutil.CreateTestManifest(tmpDir)
+	store, err := state.NewStateStore(filepath.Join(tmpDir, "state.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	groupRunID, err := store.CreateRun("compose:first+second", "group input")
+	require.NoError(t, err)
+
+	seq := NewSequenceExecutor(
+		newSequenceTestExecutorFactory(mockAdapter),
+		nil,
+		testutil.NewEventCollector(),
+		store,
+	)
+	seq.SetParentRunID(groupRunID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := seq.Execute(ctx, []*Pipeline{newMinimalPipeline("first"), newMinimalPipeline("second")}, m, "group input")
+	require.NoError(t, err)
+	require.Len(t, result.PipelineResults, 2)
+
+	children, err := store.GetChildRuns(groupRunID)
+	require.NoError(t, err)
+	require.Len(t, children, 2)
+	for i, child := range children {
+		assert.Equal(t, result.PipelineResults[i].RunID, child.RunID)
+		assert.Equal(t, state.RunKindSubPipelineChild, child.RunKind)
+		assert.Equal(t, result.PipelineResults[i].PipelineName, child.SubPipelineRef)
+	}
+}
+
 func TestSequenceExecutor_FailureStopsSequence(t *testing.T) {
 	collector := testutil.NewEventCollector()
 
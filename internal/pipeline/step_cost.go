@@ -0,0 +1,34 @@
+package pipeline
+
+import (
+	"github.com/recinq/wave/internal/cost"
+	"github.com/recinq/wave/internal/state"
+)
+
+// stepCost prices token usage of model with the run's pricing table.
+func (e *DefaultPipelineExecutor) stepCost(model string, tokensIn, tokensOut int) float64 {
+	if e.costLedger == nil {
+		return cost.ComputeCost(model, tokensIn, tokensOut)
+	}
+	return e.costLedger.Cost(model, tokensIn, tokensOut)
+}
+
+// recordStepCost stores a step's priced usage in the state store, from
+// which `wave list` and the run summary total it.
+func (e *DefaultPipelineExecutor) recordStepCost(execution *PipelineExecution, res *stepRunResources, entry cost.Entry) {
+	if e.store == nil {
+		return
+	}
+	// Best-effort: cost records are reporting data and must never fail a
+	// step.
+	_ = e.store.RecordStepCost(&state.StepCostRecord{
+		RunID:        entry.RunID,
+		StepID:       entry.StepID,
+		PipelineName: execution.Status.PipelineName,
+		Adapter:      res.resolvedAdapterName,
+		Model:        entry.Model,
+		TokensIn:     entry.InputTokens,
+		TokensOut:    entry.OutputTokens,
+		CostUSD:      entry.Cost,
+	})
+}
@@ -0,0 +1,52 @@
+package pipeline
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecute_RecordsStepCost(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := state.NewStateStore(filepath.Join(tmpDir, "state.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	runID, err := store.CreateRun("priced", "test")
+	require.NoError(t, err)
+
+	runner := &scriptedAdapter{result: adapter.AdapterResult{ResultContent: "done", TokensIn: 1_000_000, TokensOut: 500_000, TokensUsed: 1_500_000}}
+	executor := NewDefaultPipelineExecutor(runner,
+		WithEmitter(testutil.NewEventCollector()),
+		WithStateStore(store),
+		WithRunID(runID),
+	)
+	m := testutil.CreateTestManifest(tmpDir)
+	m.Runtime.Cost.Pricing = map[string]manifest.ModelPrice{"qwen3": {InputPerMillion: 1, OutputPerMillion: 2}}
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "priced"},
+		Steps:    []Step{{ID: "work", Persona: "navigator", Model: "qwen3:32b", Exec: ExecConfig{Source: "do work"}}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, executor.Execute(ctx, p, m, "test"))
+
+	costs, err := store.GetStepCosts(runID)
+	require.NoError(t, err)
+	require.Len(t, costs, 1)
+	assert.Equal(t, "work", costs[0].StepID)
+	assert.Equal(t, "priced", costs[0].PipelineName)
+	assert.Equal(t, "qwen3:32b", costs[0].Model)
+	assert.Equal(t, 1_000_000, costs[0].TokensIn)
+	assert.Equal(t, 500_000, costs[0].TokensOut)
+	assert.InDelta(t, 2.0, costs[0].CostUSD, 1e-9, "priced from runtime.cost.pricing")
+	assert.InDelta(t, 2.0, executor.GetTotalCost(), 1e-9)
+}
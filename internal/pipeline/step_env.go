@@ -0,0 +1,100 @@
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/redact"
+)
+
+// resolveStepEnv merges the runtime, persona and step env maps (later scopes
+// win per key), resolves {{ }} placeholders in the values and returns sorted
+// KEY=VALUE entries for the adapter process. persona may be nil for steps
+// that do not run a persona (command steps).
+func resolveStepEnv(execution *PipelineExecution, persona *manifest.Persona, step *Step) []string {
+	merged := make(map[string]string)
+	if execution.Manifest != nil {
+		for k, v := range execution.Manifest.Runtime.Env {
+			merged[k] = v
+		}
+	}
+	if persona != nil {
+		for k, v := range persona.Env {
+			merged[k] = v
+		}
+	}
+	for k, v := range step.Env {
+		merged[k] = v
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	env := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := merged[k]
+		if execution.Context != nil {
+			v = execution.Context.ResolvePlaceholders(v)
+		}
+		env = append(env, k+"="+v)
+	}
+	return env
+}
+
+// traceparentPattern matches a W3C Trace Context traceparent header value.
+var traceparentPattern = regexp.MustCompile(`^00-([0-9a-f]{32})-[0-9a-f]{16}-([0-9a-f]{2})$`)
+
+// runContextEnv returns the variables that tie a step's processes to the
+// orchestrating run, so tests and scripts the agent runs can tag their own
+// telemetry: WAVE_RUN_ID, WAVE_STEP_ID and a W3C TRACEPARENT. The step is
+// the parent span. When Wave itself was started with a TRACEPARENT, the run
+// joins that trace; otherwise the trace ID is derived from the run ID, so
+// every step of a run shares one trace.
+func runContextEnv(execution *PipelineExecution, step *Step) []string {
+	runID := execution.Status.ID
+	traceID, flags := "", "01"
+	if m := traceparentPattern.FindStringSubmatch(os.Getenv("TRACEPARENT")); m != nil && m[1] != strings.Repeat("0", 32) {
+		traceID, flags = m[1], m[2]
+	} else {
+		sum := sha256.Sum256([]byte(runID))
+		traceID = hex.EncodeToString(sum[:16])
+	}
+	span := sha256.Sum256([]byte(runID + "\x00" + step.ID))
+	return []string{
+		"WAVE_RUN_ID=" + runID,
+		"WAVE_STEP_ID=" + step.ID,
+		"TRACEPARENT=00-" + traceID + "-" + hex.EncodeToString(span[:8]) + "-" + flags,
+	}
+}
+
+// redactEnv returns env as a map safe for traces and logs: values of
+// credential-looking names are masked entirely, URL passwords (proxy
+// settings) are masked, and other values are scanned for embedded
+// credentials.
+func redactEnv(env []string) map[string]string {
+	out := make(map[string]string, len(env))
+	for _, kv := range env {
+		k, v, _ := strings.Cut(kv, "=")
+		switch {
+		case redact.IsSecretName(k):
+			v = redact.Placeholder
+		case strings.Contains(v, "@"):
+			if u, err := url.Parse(v); err == nil && u.User != nil {
+				v = u.Redacted()
+			}
+		}
+		out[k] = redact.Redact(v)
+	}
+	return out
+}
@@ -2,14 +2,21 @@ package pipeline
 
 import (
 	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 )
 
 // StepFilter controls which steps are included or excluded from pipeline execution.
-// Include and Exclude are mutually exclusive — setting both is an error.
+// When both are set, Exclude is applied to the steps Include selected.
 type StepFilter struct {
-	Include []string // Run only these steps (--steps flag)
-	Exclude []string // Skip these steps (-x/--exclude flag)
+	Include []string // Run only these steps (--steps/--only-steps flag)
+	Exclude []string // Skip these steps (-x/--exclude/--skip-step flag)
+	// Artifacts stands in for the outputs of steps that do not run, keyed
+	// "<step>:<artifact>" with absolute file paths (--artifact flag).
+	Artifacts map[string]string
 }
 
 // IsActive returns true if any filter criteria are set.
@@ -25,11 +32,6 @@ func (f *StepFilter) Validate(p *Pipeline) error {
 		return nil
 	}
 
-	// Mutual exclusivity check
-	if len(f.Include) > 0 && len(f.Exclude) > 0 {
-		return fmt.Errorf("--steps and --exclude are mutually exclusive; use one or the other")
-	}
-
 	// Build step name lookup
 	validSteps := make(map[string]bool, len(p.Steps))
 	for _, step := range p.Steps {
@@ -52,6 +54,14 @@ func (f *StepFilter) Validate(p *Pipeline) error {
 		}
 	}
 
+	// Validate the steps provided artifacts stand in for
+	for _, key := range slices.Sorted(maps.Keys(f.Artifacts)) {
+		stepID, _, _ := strings.Cut(key, ":")
+		if !validSteps[stepID] {
+			return fmt.Errorf("unknown step %q in --artifact %s; available steps: %s", stepID, key, available)
+		}
+	}
+
 	return nil
 }
 
@@ -79,35 +89,78 @@ func (f *StepFilter) Apply(steps []*Step) []*Step {
 		return steps
 	}
 
-	if len(f.Include) > 0 {
-		include := make(map[string]bool, len(f.Include))
-		for _, name := range f.Include {
-			include[name] = true
+	var result []*Step
+	for _, step := range steps {
+		if f.ShouldRun(step.ID) {
+			result = append(result, step)
 		}
-		var result []*Step
-		for _, step := range steps {
-			if include[step.ID] {
-				result = append(result, step)
-			}
+	}
+	return result
+}
+
+// ValidateArtifacts checks that every artifact a filtered step requires from
+// a step that does not run is provided through Artifacts, and that each
+// provided file exists. Required artifacts are the skipped dependency's
+// required output artifacts and the step's non-optional inject_artifacts
+// references to it.
+func (f *StepFilter) ValidateArtifacts(filtered []*Step, p *Pipeline) error {
+	if f == nil {
+		return nil
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(f.Artifacts)) {
+		if _, err := os.Stat(f.Artifacts[key]); err != nil {
+			return fmt.Errorf("--artifact %s: %w", key, err)
 		}
-		return result
+	}
+	if !f.IsActive() {
+		return nil
 	}
 
-	if len(f.Exclude) > 0 {
-		exclude := make(map[string]bool, len(f.Exclude))
-		for _, name := range f.Exclude {
-			exclude[name] = true
+	runs := make(map[string]bool, len(filtered))
+	for _, step := range filtered {
+		runs[step.ID] = true
+	}
+	require := func(step *Step, depID, name string) error {
+		if runs[depID] {
+			return nil
+		}
+		if _, ok := f.Artifacts[depID+":"+name]; ok {
+			return nil
 		}
-		var result []*Step
-		for _, step := range steps {
-			if !exclude[step.ID] {
-				result = append(result, step)
+		return fmt.Errorf("step %q needs artifact %q from skipped step %q; run %q too or provide it with --artifact %s:%s=<path>",
+			step.ID, name, depID, depID, depID, name)
+	}
+
+	for _, step := range filtered {
+		for _, depID := range step.Dependencies {
+			if runs[depID] {
+				continue
+			}
+			dep := findStepByID(p, depID)
+			if dep == nil {
+				continue
+			}
+			for _, art := range dep.OutputArtifacts {
+				if !art.Required {
+					continue
+				}
+				if err := require(step, depID, art.Name); err != nil {
+					return err
+				}
+			}
+		}
+		for _, ref := range step.Memory.InjectArtifacts {
+			if ref.Step == "" || ref.Pipeline != "" || ref.FromPipeline != "" || ref.Optional {
+				continue
+			}
+			if err := require(step, ref.Step, ref.Artifact); err != nil {
+				return err
 			}
 		}
-		return result
 	}
 
-	return steps
+	return nil
 }
 
 // ValidateDependencies checks that every step in the filtered set has its
@@ -159,25 +212,10 @@ func (f *StepFilter) ShouldRun(stepID string) bool {
 		return true
 	}
 
-	if len(f.Include) > 0 {
-		for _, name := range f.Include {
-			if name == stepID {
-				return true
-			}
-		}
+	if len(f.Include) > 0 && !slices.Contains(f.Include, stepID) {
 		return false
 	}
-
-	if len(f.Exclude) > 0 {
-		for _, name := range f.Exclude {
-			if name == stepID {
-				return false
-			}
-		}
-		return true
-	}
-
-	return true
+	return !slices.Contains(f.Exclude, stepID)
 }
 
 // ParseStepFilter creates a StepFilter from comma-separated step name strings.
@@ -196,6 +234,29 @@ func ParseStepFilter(steps, exclude string) *StepFilter {
 	return f
 }
 
+// ParseArtifactOverrides parses --artifact values of the form
+// "<step>:<artifact>=<path>" into a map keyed "<step>:<artifact>" with
+// absolute paths.
+func ParseArtifactOverrides(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	artifacts := make(map[string]string, len(values))
+	for _, v := range values {
+		key, path, ok := strings.Cut(v, "=")
+		stepID, name, hasName := strings.Cut(key, ":")
+		if !ok || !hasName || stepID == "" || name == "" || path == "" {
+			return nil, fmt.Errorf("invalid --artifact %q: expected <step>:<artifact>=<path>", v)
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --artifact %q: %w", v, err)
+		}
+		artifacts[key] = abs
+	}
+	return artifacts, nil
+}
+
 // splitAndTrim splits a comma-separated string and trims whitespace from each element.
 func splitAndTrim(s string) []string {
 	parts := strings.Split(s, ",")
@@ -1,6 +1,8 @@
 package pipeline
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -63,10 +65,14 @@ func TestStepFilter_Validate(t *testing.T) {
 			filter: &StepFilter{Exclude: []string{"implement", "create-pr"}},
 		},
 		{
-			name:          "include and exclude are mutually exclusive",
-			filter:        &StepFilter{Include: []string{"plan"}, Exclude: []string{"implement"}},
+			name:   "include and exclude combine",
+			filter: &StepFilter{Include: []string{"plan"}, Exclude: []string{"implement"}},
+		},
+		{
+			name:          "artifact for unknown step",
+			filter:        &StepFilter{Include: []string{"plan"}, Artifacts: map[string]string{"bogus:spec": "/tmp/spec.md"}},
 			expectError:   true,
-			errorContains: "mutually exclusive",
+			errorContains: `unknown step "bogus" in --artifact`,
 		},
 		{
 			name:          "invalid include step name",
@@ -206,6 +212,11 @@ func TestStepFilter_Apply(t *testing.T) {
 			filter:   &StepFilter{Exclude: []string{"create-pr"}},
 			expected: []string{"specify", "clarify", "plan", "tasks", "implement"},
 		},
+		{
+			name:     "exclude applies to included steps",
+			filter:   &StepFilter{Include: []string{"plan", "tasks", "implement"}, Exclude: []string{"tasks"}},
+			expected: []string{"plan", "implement"},
+		},
 		{
 			name:     "exclude all steps returns empty",
 			filter:   &StepFilter{Exclude: []string{"specify", "clarify", "plan", "tasks", "implement", "create-pr"}},
@@ -278,6 +289,74 @@ func TestStepFilter_ValidateDependencies(t *testing.T) {
 	}
 }
 
+func TestStepFilter_ValidateArtifacts(t *testing.T) {
+	provided := filepath.Join(t.TempDir(), "tasks.md")
+	require.NoError(t, os.WriteFile(provided, []byte("tasks"), 0644))
+
+	p := &Pipeline{Steps: []Step{
+		{ID: "plan", OutputArtifacts: []ArtifactDef{{Name: "plan", Required: true}, {Name: "notes"}}},
+		{ID: "tasks", Dependencies: []string{"plan"}},
+		{ID: "implement", Dependencies: []string{"plan", "tasks"}, Memory: MemoryConfig{InjectArtifacts: []ArtifactRef{
+			{Step: "tasks", Artifact: "tasks"},
+			{Step: "tasks", Artifact: "hints", Optional: true},
+		}}},
+	}}
+
+	tests := []struct {
+		name          string
+		filter        *StepFilter
+		errorContains string
+	}{
+		{
+			name:   "dependencies run",
+			filter: &StepFilter{Exclude: []string{"implement"}},
+		},
+		{
+			name:          "required output of skipped dependency",
+			filter:        &StepFilter{Include: []string{"tasks"}},
+			errorContains: `step "tasks" needs artifact "plan" from skipped step "plan"`,
+		},
+		{
+			name:          "injected artifact of skipped step",
+			filter:        &StepFilter{Exclude: []string{"tasks"}},
+			errorContains: "--artifact tasks:tasks=<path>",
+		},
+		{
+			name:   "provided artifacts satisfy skipped steps",
+			filter: &StepFilter{Include: []string{"implement"}, Artifacts: map[string]string{"plan:plan": provided, "tasks:tasks": provided}},
+		},
+		{
+			name:          "provided artifact must exist",
+			filter:        &StepFilter{Exclude: []string{"tasks"}, Artifacts: map[string]string{"tasks:tasks": provided + ".missing"}},
+			errorContains: "--artifact tasks:tasks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.ValidateArtifacts(tt.filter.Apply(stepPtrs(p)), p)
+			if tt.errorContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
+
+func TestParseArtifactOverrides(t *testing.T) {
+	got, err := ParseArtifactOverrides([]string{"build:dist=dist.tar.gz", "plan:plan=/tmp/plan.md"})
+	require.NoError(t, err)
+	abs, _ := filepath.Abs("dist.tar.gz")
+	assert.Equal(t, map[string]string{"build:dist": abs, "plan:plan": "/tmp/plan.md"}, got)
+
+	for _, bad := range []string{"build=dist.tar.gz", "build:dist", ":dist=x", "build:=x", "build:dist="} {
+		_, err := ParseArtifactOverrides([]string{bad})
+		assert.Error(t, err, bad)
+	}
+}
+
 func TestStepFilter_ShouldRun(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -315,6 +394,12 @@ func TestStepFilter_ShouldRun(t *testing.T) {
 			stepID:   "implement",
 			expected: false,
 		},
+		{
+			name:     "exclude skips an included step",
+			filter:   &StepFilter{Include: []string{"plan", "tasks"}, Exclude: []string{"tasks"}},
+			stepID:   "tasks",
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
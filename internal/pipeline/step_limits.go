@@ -0,0 +1,129 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/manifest"
+)
+
+// StepLimitError reports a step killed for exceeding max_turns or
+// max_tool_calls.
+type StepLimitError struct {
+	Step  string
+	Limit string // "max_turns" or "max_tool_calls"
+	Max   int
+}
+
+func (e *StepLimitError) Error() string {
+	what := "turns"
+	if e.Limit == "max_tool_calls" {
+		what = "tool calls"
+	}
+	return fmt.Sprintf("step '%s' exceeded %s (%d): agent killed after more than %d %s", e.Step, e.Limit, e.Max, e.Max, what)
+}
+
+// nativeMaxTurns reports whether adapterName enforces max_turns itself,
+// which it does when it accepts an integer max-turns adapter option.
+func nativeMaxTurns(m *manifest.Manifest, adapterName string) bool {
+	return m.AdapterOptions(adapterName)["max-turns"].Type == manifest.OptionTypeInt
+}
+
+// stepLimitCounter counts a step's agent turns and tool calls from adapter
+// stream events. A turn is one model response: events of one message share
+// a MessageID when the adapter reports it; otherwise a turn is the output
+// between two tool results.
+type stepLimitCounter struct {
+	turns, toolCalls int
+	messages         map[string]bool
+	inTurn           bool
+}
+
+func (c *stepLimitCounter) observe(evt adapter.StreamEvent) {
+	switch evt.Type {
+	case "tool_result":
+		c.inTurn = false
+	case "text", "tool_use":
+		if evt.MessageID != "" {
+			if c.messages == nil {
+				c.messages = make(map[string]bool)
+			}
+			if !c.messages[evt.MessageID] {
+				c.messages[evt.MessageID] = true
+				c.turns++
+			}
+		} else if !c.inTurn {
+			c.inTurn = true
+			c.turns++
+		}
+		if evt.Type == "tool_use" {
+			c.toolCalls++
+		}
+	}
+}
+
+// enforceStepLimits wraps cfg.OnStreamEvent to count the step's turns and
+// tool calls. Crossing max_tool_calls, or max_turns when the adapter does not
+// enforce it through a flag, emits a warning and cancels the returned
+// context, killing the adapter. The returned function, called once the
+// adapter has returned, reports the exceeded limit.
+func (e *DefaultPipelineExecutor) enforceStepLimits(ctx context.Context, step *Step, res *stepRunResources, cfg *adapter.AdapterRunConfig, nativeTurns bool) (context.Context, func() error) {
+	maxTurns := step.MaxTurns
+	if nativeTurns {
+		maxTurns = 0
+	}
+	if maxTurns <= 0 && step.MaxToolCalls <= 0 {
+		return ctx, func() error { return nil }
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var mu sync.Mutex
+	var counter stepLimitCounter
+	var exceeded *StepLimitError
+	next := cfg.OnStreamEvent
+	cfg.OnStreamEvent = func(evt adapter.StreamEvent) {
+		if next != nil {
+			next(evt)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if exceeded != nil {
+			return
+		}
+		counter.observe(evt)
+		switch {
+		case maxTurns > 0 && counter.turns > maxTurns:
+			exceeded = &StepLimitError{Step: step.ID, Limit: "max_turns", Max: maxTurns}
+		case step.MaxToolCalls > 0 && counter.toolCalls > step.MaxToolCalls:
+			exceeded = &StepLimitError{Step: step.ID, Limit: "max_tool_calls", Max: step.MaxToolCalls}
+		default:
+			return
+		}
+		e.emit(event.Event{
+			Timestamp:  time.Now(),
+			PipelineID: res.pipelineID,
+			StepID:     step.ID,
+			State:      "warning",
+			Persona:    res.resolvedPersona,
+			ToolName:   evt.ToolName,
+			ToolTarget: evt.ToolInput,
+			Message:    exceeded.Error(),
+		})
+		cancel()
+	}
+
+	return ctx, func() error {
+		cancel()
+		mu.Lock()
+		defer mu.Unlock()
+		if exceeded != nil {
+			return exceeded
+		}
+		return nil
+	}
+}
@@ -0,0 +1,118 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepLimitCounter(t *testing.T) {
+	var c stepLimitCounter
+	for _, evt := range []adapter.StreamEvent{
+		{Type: "text", Content: "looking"},
+		{Type: "tool_use", ToolName: "Bash"},
+		{Type: "tool_result"},
+		{Type: "tool_use", ToolName: "Bash"},
+		{Type: "tool_use", ToolName: "Read"},
+		{Type: "tool_result"},
+		{Type: "tool_result"},
+		{Type: "text", Content: "done"},
+	} {
+		c.observe(evt)
+	}
+	assert.Equal(t, 3, c.turns)
+	assert.Equal(t, 3, c.toolCalls)
+
+	// With message IDs, each model message is one turn.
+	c = stepLimitCounter{}
+	for _, evt := range []adapter.StreamEvent{
+		{Type: "text", MessageID: "m1"},
+		{Type: "tool_use", ToolName: "Bash", MessageID: "m1"},
+		{Type: "tool_result"},
+		{Type: "tool_use", ToolName: "Bash", MessageID: "m2"},
+	} {
+		c.observe(evt)
+	}
+	assert.Equal(t, 2, c.turns)
+	assert.Equal(t, 2, c.toolCalls)
+}
+
+func runStepLimitPipeline(t *testing.T, adapterName string, step Step) (*testutil.EventCollector, error) {
+	t.Helper()
+	collector := testutil.NewEventCollector()
+	streamAdapter := &streamEventAdapter{
+		MockAdapter: adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`)),
+		streamEvents: []adapter.StreamEvent{
+			{Type: "tool_use", ToolName: "Bash", ToolInput: "go test ./..."},
+			{Type: "tool_result"},
+			{Type: "tool_use", ToolName: "Bash", ToolInput: "go test ./..."},
+			{Type: "tool_result"},
+			{Type: "tool_use", ToolName: "Bash", ToolInput: "go test ./..."},
+		},
+	}
+	executor := NewDefaultPipelineExecutor(streamAdapter, WithEmitter(collector))
+
+	m := testutil.CreateTestManifest(t.TempDir())
+	if adapterName != "claude" {
+		m.Adapters[adapterName] = manifest.Adapter{Binary: adapterName, Mode: "headless"}
+		persona := m.Personas["craftsman"]
+		persona.Adapter = adapterName
+		m.Personas["craftsman"] = persona
+	}
+
+	step.ID, step.Persona, step.Exec = "work", "craftsman", ExecConfig{Source: "do work"}
+	p := &Pipeline{Metadata: PipelineMetadata{Name: "step-limit-test"}, Steps: []Step{step}}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return collector, executor.Execute(ctx, p, m, "test")
+}
+
+func TestEnforceStepLimits_ToolCallsKillStep(t *testing.T) {
+	collector, err := runStepLimitPipeline(t, "claude", Step{MaxToolCalls: 2})
+	require.Error(t, err)
+	var limitErr *StepLimitError
+	require.True(t, errors.As(err, &limitErr), "expected StepLimitError, got %v", err)
+	assert.Equal(t, "max_tool_calls", limitErr.Limit)
+	assert.Equal(t, FailureClassBudgetExhausted, ClassifyStepFailure(err, nil, nil))
+	assert.Equal(t, FailureCategoryStepLimit, CategorizeStepFailure(err, nil))
+
+	var warned bool
+	for _, e := range collector.GetEventsByStep("work") {
+		if e.State == "warning" && e.Message == limitErr.Error() {
+			warned = true
+		}
+	}
+	assert.True(t, warned, "expected a warning event for the exceeded limit")
+}
+
+func TestEnforceStepLimits_TurnsCountedWithoutNativeFlag(t *testing.T) {
+	_, err := runStepLimitPipeline(t, "custom", Step{MaxTurns: 2})
+	var limitErr *StepLimitError
+	require.True(t, errors.As(err, &limitErr), "expected StepLimitError, got %v", err)
+	assert.Equal(t, "max_turns", limitErr.Limit)
+
+	// Claude enforces --max-turns itself, so the stream is not policed.
+	_, err = runStepLimitPipeline(t, "claude", Step{MaxTurns: 2})
+	require.NoError(t, err)
+}
+
+func TestResolveAdapterOptions_NativeMaxTurns(t *testing.T) {
+	m := &manifest.Manifest{Adapters: map[string]manifest.Adapter{"claude": {Binary: "claude"}, "custom": {Binary: "custom"}}}
+	step := &Step{ID: "work", MaxTurns: 12, AdapterOptions: map[string]string{"max-turns": "99"}}
+
+	args, err := resolveAdapterOptions(m, "claude", nil, step)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--max-turns", "12"}, args)
+
+	args, err = resolveAdapterOptions(m, "custom", nil, &Step{ID: "work", MaxTurns: 12})
+	require.NoError(t, err)
+	assert.Nil(t, args)
+}
@@ -0,0 +1,297 @@
+package pipeline
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// templateParamPattern matches {{ params.<name> }} placeholders in step
+// templates. They are substituted at load time, before the runtime template
+// namespaces ({{ input }}, {{ steps.* }}, ...) are ever seen.
+var templateParamPattern = regexp.MustCompile(`\{\{\s*params\.([A-Za-z0-9_-]+)\s*\}\}`)
+
+// stepTemplate is one entry of the top-level step_templates section.
+type stepTemplate struct {
+	params map[string]*yaml.Node // default values; nil = required
+	step   *yaml.Node
+}
+
+// expandStepTemplates instantiates steps that reference a step_templates
+// entry and removes the section, so the strict decoder only ever sees plain
+// steps. A templated step looks like:
+//
+//	step_templates:
+//	  implement-with-tests:
+//	    params:
+//	      focus: ~          # required
+//	      persona: craftsman
+//	    step:
+//	      persona: "{{ params.persona }}"
+//	      exec: {type: prompt, source: "Implement {{ params.focus }} with tests"}
+//	steps:
+//	  - id: implement
+//	    template: implement-with-tests
+//	    with: {focus: the parser}
+//	    dependencies: [plan]
+//
+// Fields set on the step are merged over the template (mappings merge
+// recursively, everything else is replaced). YAML anchors defined inside
+// step_templates may be aliased from steps; aliases are expanded before the
+// section is dropped. Documents without step_templates are returned as is.
+func expandStepTemplates(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return data, nil // let the strict decoder report syntax errors
+	}
+	root := doc.Content[0]
+	idx := mappingIndex(root, "step_templates")
+	if idx < 0 {
+		return data, nil
+	}
+
+	resolveAliases(root)
+	templates, err := parseStepTemplates(root.Content[idx+1])
+	if err != nil {
+		return nil, err
+	}
+	root.Content = append(root.Content[:idx], root.Content[idx+2:]...)
+
+	if steps := mappingValue(root, "steps"); steps != nil && steps.Kind == yaml.SequenceNode {
+		for i, step := range steps.Content {
+			expanded, err := instantiateStep(step, templates)
+			if err != nil {
+				return nil, err
+			}
+			steps.Content[i] = expanded
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to expand step templates: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to expand step templates: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func parseStepTemplates(section *yaml.Node) (map[string]*stepTemplate, error) {
+	if section.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("step_templates must be a mapping of template name to template")
+	}
+	templates := make(map[string]*stepTemplate, len(section.Content)/2)
+	for i := 0; i+1 < len(section.Content); i += 2 {
+		name, body := section.Content[i].Value, section.Content[i+1]
+		if body.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("step_templates.%s must be a mapping with params and step", name)
+		}
+		tmpl := &stepTemplate{params: map[string]*yaml.Node{}}
+		for j := 0; j+1 < len(body.Content); j += 2 {
+			key, value := body.Content[j].Value, body.Content[j+1]
+			switch key {
+			case "params":
+				if value.Kind != yaml.MappingNode {
+					return nil, fmt.Errorf("step_templates.%s.params must be a mapping of parameter name to default", name)
+				}
+				for k := 0; k+1 < len(value.Content); k += 2 {
+					def := value.Content[k+1]
+					if def.Tag == "!!null" {
+						def = nil
+					}
+					tmpl.params[value.Content[k].Value] = def
+				}
+			case "step":
+				if value.Kind != yaml.MappingNode {
+					return nil, fmt.Errorf("step_templates.%s.step must be a mapping of step fields", name)
+				}
+				if mappingValue(value, "template") != nil {
+					return nil, fmt.Errorf("step_templates.%s: templates cannot reference other templates", name)
+				}
+				tmpl.step = value
+			default:
+				return nil, fmt.Errorf("step_templates.%s: unknown field %q (expected params, step)", name, key)
+			}
+		}
+		if tmpl.step == nil {
+			return nil, fmt.Errorf("step_templates.%s: step is required", name)
+		}
+		templates[name] = tmpl
+	}
+	return templates, nil
+}
+
+// instantiateStep returns step with its template applied, or step itself
+// when it does not reference one.
+func instantiateStep(step *yaml.Node, templates map[string]*stepTemplate) (*yaml.Node, error) {
+	ref := mappingValue(step, "template")
+	if ref == nil {
+		return step, nil
+	}
+	id := "<unnamed>"
+	if n := mappingValue(step, "id"); n != nil {
+		id = n.Value
+	}
+	tmpl, ok := templates[ref.Value]
+	if !ok {
+		return nil, fmt.Errorf("step %q references unknown step template %q", id, ref.Value)
+	}
+
+	args := make(map[string]*yaml.Node, len(tmpl.params))
+	for name, def := range tmpl.params {
+		args[name] = def
+	}
+	if with := mappingValue(step, "with"); with != nil {
+		if with.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("step %q: with must be a mapping of parameter name to value", id)
+		}
+		for i := 0; i+1 < len(with.Content); i += 2 {
+			name := with.Content[i].Value
+			if _, declared := tmpl.params[name]; !declared && !referencesParam(tmpl.step, name) {
+				return nil, fmt.Errorf("step %q: step template %q has no parameter %q", id, ref.Value, name)
+			}
+			args[name] = with.Content[i+1]
+		}
+	}
+
+	out := deepCopyNode(tmpl.step)
+	if err := substituteParams(out, args); err != nil {
+		return nil, fmt.Errorf("step %q: step template %q: %w", id, ref.Value, err)
+	}
+	for i := 0; i+1 < len(step.Content); i += 2 {
+		switch step.Content[i].Value {
+		case "template", "with":
+			continue
+		}
+		mergeMappingKey(out, step.Content[i], step.Content[i+1])
+	}
+	return out, nil
+}
+
+// substituteParams replaces {{ params.<name> }} in every scalar under n. A
+// scalar that is exactly one placeholder takes the argument's node, so
+// numbers, booleans and lists keep their type.
+func substituteParams(n *yaml.Node, args map[string]*yaml.Node) error {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		matches := templateParamPattern.FindAllStringSubmatchIndex(n.Value, -1)
+		if len(matches) == 0 {
+			return nil
+		}
+		for _, m := range matches {
+			name := n.Value[m[2]:m[3]]
+			if args[name] == nil {
+				return fmt.Errorf("parameter %q is not set", name)
+			}
+		}
+		if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(n.Value) {
+			*n = *deepCopyNode(args[n.Value[matches[0][2]:matches[0][3]]])
+			return nil
+		}
+		var err error
+		n.Value = templateParamPattern.ReplaceAllStringFunc(n.Value, func(s string) string {
+			arg := args[templateParamPattern.FindStringSubmatch(s)[1]]
+			if arg.Kind != yaml.ScalarNode {
+				err = fmt.Errorf("parameter in %q must be a scalar to be embedded in text", n.Value)
+			}
+			return arg.Value
+		})
+		return err
+	default:
+		for _, c := range n.Content {
+			if err := substituteParams(c, args); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// referencesParam reports whether any scalar under n uses {{ params.<name> }}.
+func referencesParam(n *yaml.Node, name string) bool {
+	if n.Kind == yaml.ScalarNode {
+		for _, m := range templateParamPattern.FindAllStringSubmatch(n.Value, -1) {
+			if m[1] == name {
+				return true
+			}
+		}
+		return false
+	}
+	for _, c := range n.Content {
+		if referencesParam(c, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeMappingKey sets key: value on mapping m, merging recursively when
+// both the existing and the new value are mappings.
+func mergeMappingKey(m, key, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value != key.Value {
+			continue
+		}
+		existing := m.Content[i+1]
+		if existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode {
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				mergeMappingKey(existing, value.Content[j], value.Content[j+1])
+			}
+			return
+		}
+		m.Content[i+1] = value
+		return
+	}
+	m.Content = append(m.Content, key, value)
+}
+
+// resolveAliases replaces alias nodes with copies of their anchors so the
+// tree can be re-encoded after the anchors' section is removed.
+func resolveAliases(n *yaml.Node) {
+	for i, c := range n.Content {
+		if c.Kind == yaml.AliasNode && c.Alias != nil {
+			n.Content[i] = deepCopyNode(c.Alias)
+			n.Content[i].Anchor = ""
+		}
+		resolveAliases(n.Content[i])
+	}
+	n.Anchor = ""
+}
+
+func deepCopyNode(n *yaml.Node) *yaml.Node {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	if n.Content != nil {
+		c.Content = make([]*yaml.Node, len(n.Content))
+		for i, child := range n.Content {
+			c.Content[i] = deepCopyNode(child)
+		}
+	}
+	return &c
+}
+
+func mappingIndex(m *yaml.Node, key string) int {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	if i := mappingIndex(m, key); i >= 0 {
+		return m.Content[i+1]
+	}
+	return nil
+}
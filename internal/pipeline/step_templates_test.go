@@ -0,0 +1,102 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestYAMLPipelineLoader_StepTemplates(t *testing.T) {
+	yamlContent := []byte(`kind: WavePipeline
+metadata:
+  name: templated
+step_templates:
+  implement-with-tests:
+    params:
+      focus: ~
+      persona: craftsman
+      attempts: 2
+    step:
+      persona: "{{ params.persona }}"
+      retry:
+        max_attempts: "{{ params.attempts }}"
+      exec:
+        type: prompt
+        source: "Implement {{ params.focus }} and add tests for {{ input }}."
+      handover: &tested
+        contract:
+          type: test_suite
+          command: go test ./...
+steps:
+  - id: parser
+    template: implement-with-tests
+    with:
+      focus: the parser
+  - id: lexer
+    template: implement-with-tests
+    with:
+      focus: the lexer
+      persona: implementer
+    dependencies: [parser]
+    exec:
+      source: "Rewrite the lexer."
+  - id: verify
+    persona: reviewer
+    exec:
+      type: prompt
+      source: verify
+    handover: *tested
+`)
+
+	p, err := (&YAMLPipelineLoader{}).Unmarshal(yamlContent)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(p.Steps) != 3 {
+		t.Fatalf("got %d steps, want 3", len(p.Steps))
+	}
+
+	parser := p.Steps[0]
+	if parser.Persona != "craftsman" || parser.Retry.MaxAttempts != 2 {
+		t.Errorf("parser: persona=%q max_attempts=%d, want defaults craftsman/2", parser.Persona, parser.Retry.MaxAttempts)
+	}
+	if parser.Exec.Source != "Implement the parser and add tests for {{ input }}." {
+		t.Errorf("parser source = %q", parser.Exec.Source)
+	}
+
+	lexer := p.Steps[1]
+	if lexer.Persona != "implementer" {
+		t.Errorf("lexer persona = %q, want the with override", lexer.Persona)
+	}
+	// Step fields merge over the template: source replaced, type kept.
+	if lexer.Exec.Source != "Rewrite the lexer." || lexer.Exec.Type != "prompt" {
+		t.Errorf("lexer exec = %+v", lexer.Exec)
+	}
+	if len(lexer.Dependencies) != 1 || lexer.Handover.Contract.Type != "test_suite" {
+		t.Errorf("lexer deps=%v contract=%q", lexer.Dependencies, lexer.Handover.Contract.Type)
+	}
+
+	if p.Steps[2].Handover.Contract.Command != "go test ./..." {
+		t.Errorf("anchor from step_templates not resolved: %+v", p.Steps[2].Handover)
+	}
+}
+
+func TestYAMLPipelineLoader_StepTemplateErrors(t *testing.T) {
+	header := "kind: WavePipeline\nmetadata:\n  name: t\nstep_templates:\n  impl:\n    params:\n      focus: ~\n    step:\n      persona: craftsman\n      exec: {type: prompt, source: \"do {{ params.focus }}\"}\nsteps:\n"
+	tests := []struct {
+		name  string
+		steps string
+		want  string
+	}{
+		{"unknown template", "  - id: a\n    template: nope\n", `unknown step template "nope"`},
+		{"missing required param", "  - id: a\n    template: impl\n", `parameter "focus" is not set`},
+		{"unknown param", "  - id: a\n    template: impl\n    with: {focus: x, colour: red}\n", `has no parameter "colour"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&YAMLPipelineLoader{}).Unmarshal([]byte(header + tt.steps))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
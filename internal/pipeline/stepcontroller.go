@@ -54,11 +54,11 @@ func (c *DefaultStepController) ContinueStep(ctx context.Context, chatCtx *ChatC
 	}
 
 	instructions := buildContinueInstructions(chatCtx, step)
-	instructionFile := adapter.InstructionFilename(step.Adapter)
-	mdPath := filepath.Join(step.WorkspacePath, instructionFile)
-	if err := os.WriteFile(mdPath, []byte(instructions), 0644); err != nil {
-		return fmt.Errorf("failed to write %s: %w", instructionFile, err)
+	restore, err := adapter.WriteInstructions(step.WorkspacePath, adapter.InstructionFilename(step.Adapter), []byte(instructions))
+	if err != nil {
+		return err
 	}
+	defer restore()
 
 	// Launch interactive session with write permissions
 	_, err = adapter.LaunchInteractive(step.WorkspacePath, adapter.InteractiveOptions{
@@ -83,11 +83,11 @@ func (c *DefaultStepController) ExtendStep(ctx context.Context, chatCtx *ChatCon
 	}
 
 	extendMd := buildExtendInstructions(chatCtx, step, instructions)
-	instructionFile := adapter.InstructionFilename(step.Adapter)
-	mdPath := filepath.Join(step.WorkspacePath, instructionFile)
-	if err := os.WriteFile(mdPath, []byte(extendMd), 0644); err != nil {
-		return fmt.Errorf("failed to write %s: %w", instructionFile, err)
+	restore, err := adapter.WriteInstructions(step.WorkspacePath, adapter.InstructionFilename(step.Adapter), []byte(extendMd))
+	if err != nil {
+		return err
 	}
+	defer restore()
 
 	// Launch interactive session with write permissions
 	_, err = adapter.LaunchInteractive(step.WorkspacePath, adapter.InteractiveOptions{
@@ -219,11 +219,11 @@ func (c *DefaultStepController) RewriteStep(ctx context.Context, chatCtx *ChatCo
 	}
 
 	rewriteMd := buildRewriteInstructions(chatCtx, step, newPrompt)
-	instructionFile := adapter.InstructionFilename(step.Adapter)
-	mdPath := filepath.Join(wsPath, instructionFile)
-	if err := os.WriteFile(mdPath, []byte(rewriteMd), 0644); err != nil {
-		return fmt.Errorf("failed to write %s: %w", instructionFile, err)
+	restore, err := adapter.WriteInstructions(wsPath, adapter.InstructionFilename(step.Adapter), []byte(rewriteMd))
+	if err != nil {
+		return err
 	}
+	defer restore()
 
 	// Log the rewrite event
 	if err := c.store.LogEvent(
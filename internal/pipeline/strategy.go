@@ -26,7 +26,7 @@ type strategyKind int
 
 const (
 	// strategyKindStepLevel applies at the executeStep dispatch boundary
-	// only — concurrency and matrix expansion.
+	// only — concurrency, matrix expansion and test_impact.
 	strategyKindStepLevel strategyKind = iota
 	// strategyKindComposition applies inside executeCompositionStep too —
 	// gate, iterate, aggregate, branch, loop, sub-pipeline.
@@ -57,6 +57,11 @@ var strategyRegistry = []strategyEntry{
 		match: func(step *Step) bool { return step.Strategy != nil && step.Strategy.Type == "matrix" },
 		build: func(e *DefaultPipelineExecutor) StrategyExecutor { return matrixStrategy{e: e} },
 	},
+	{
+		kind:  strategyKindStepLevel,
+		match: func(step *Step) bool { return step.Type == StepTypeTestImpact },
+		build: func(e *DefaultPipelineExecutor) StrategyExecutor { return testImpactStrategy{e: e} },
+	},
 	{
 		kind:  strategyKindComposition,
 		match: func(step *Step) bool { return step.Gate != nil },
@@ -168,6 +173,13 @@ func (s gateStrategy) Execute(ctx context.Context, execution *PipelineExecution,
 	return s.e.executeGateInDAG(ctx, execution, step)
 }
 
+// testImpactStrategy dispatches to executeTestImpactStep.
+type testImpactStrategy struct{ e *DefaultPipelineExecutor }
+
+func (s testImpactStrategy) Execute(ctx context.Context, execution *PipelineExecution, step *Step) error {
+	return s.e.executeTestImpactStep(ctx, execution, step)
+}
+
 // subPipelineStrategy launches a bare sub-pipeline step.
 type subPipelineStrategy struct{ e *DefaultPipelineExecutor }
 
@@ -0,0 +1,403 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/state"
+)
+
+// TestTargetsArtifactName is the artifact under which a test_impact step
+// registers its result ("<step-id>:test_targets").
+const TestTargetsArtifactName = "test_targets"
+
+// TestImpactConfig configures a test_impact step.
+type TestImpactConfig struct {
+	// From lists the steps whose change summaries are analyzed. Defaults
+	// to the step's dependencies.
+	From []string `yaml:"from,omitempty"`
+}
+
+// TestImpact is the result of a test_impact step: the files upstream steps
+// changed and, per language, the test targets to run.
+type TestImpact struct {
+	ChangedFiles []string                        `json:"changed_files"`
+	Languages    map[string]*LanguageTestTargets `json:"languages"`
+}
+
+// LanguageTestTargets are the test targets of one language. FullSuite is
+// set when a change affects every test, e.g. an edited go.mod, or when a
+// changed source file maps to no test; Targets is then the language's
+// whole-suite target and Reason says why.
+type LanguageTestTargets struct {
+	FullSuite bool     `json:"full_suite"`
+	Reason    string   `json:"reason,omitempty"`
+	Targets   []string `json:"targets"`
+}
+
+// testImpactLanguage holds the heuristics that map changed files of one
+// language to test targets.
+type testImpactLanguage struct {
+	name string
+	// all is the target that selects the whole suite.
+	all string
+	// fullSuite reports whether a change to the file invalidates every
+	// test: dependency manifests, lock files and test runner config.
+	fullSuite func(file string) bool
+	owns      func(file string) bool
+	// targets returns the test targets of a changed file. root is the
+	// workspace the file was changed in; deleted files no longer exist
+	// there. ok is false when no test could be found for the file.
+	targets func(root, file string, deleted bool) (targets []string, ok bool)
+}
+
+// testImpactLanguages are the languages a test_impact step understands.
+var testImpactLanguages = []testImpactLanguage{
+	{
+		name:      "go",
+		all:       "./...",
+		fullSuite: baseNameIn("go.mod", "go.sum", "go.work", "go.work.sum"),
+		owns: func(file string) bool {
+			return path.Ext(file) == ".go" || testdataPackage(file) != ""
+		},
+		targets: goTestTargets,
+	},
+	{
+		name: "python",
+		all:  ".",
+		fullSuite: func(file string) bool {
+			base := path.Base(file)
+			return baseNameIn("pyproject.toml", "setup.py", "setup.cfg", "tox.ini", "pytest.ini", "Pipfile", "Pipfile.lock", "poetry.lock")(file) ||
+				strings.HasPrefix(base, "requirements") && path.Ext(base) == ".txt"
+		},
+		owns:    func(file string) bool { return path.Ext(file) == ".py" },
+		targets: pythonTestTargets,
+	},
+	{
+		name: "javascript",
+		all:  ".",
+		fullSuite: func(file string) bool {
+			base := path.Base(file)
+			if baseNameIn("package.json", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "tsconfig.json", ".babelrc")(file) {
+				return true
+			}
+			for _, prefix := range []string{"jest.config.", "vitest.config.", "babel.config."} {
+				if strings.HasPrefix(base, prefix) {
+					return true
+				}
+			}
+			return false
+		},
+		owns:    func(file string) bool { return slices.Contains(jsExtensions, path.Ext(file)) },
+		targets: jsTestTargets,
+	},
+}
+
+var jsExtensions = []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs"}
+
+func baseNameIn(names ...string) func(string) bool {
+	return func(file string) bool { return slices.Contains(names, path.Base(file)) }
+}
+
+// testdataPackage returns the directory of the package owning a file under
+// a testdata directory, or "".
+func testdataPackage(file string) string {
+	dir := path.Dir(file)
+	for dir != "." && dir != "/" {
+		if path.Base(dir) == "testdata" {
+			return path.Dir(dir)
+		}
+		dir = path.Dir(dir)
+	}
+	return ""
+}
+
+// goTestTargets maps a changed Go file, or a file under testdata, to its
+// package. A package whose last file was deleted has nothing left to test.
+func goTestTargets(root, file string, _ bool) ([]string, bool) {
+	dir := testdataPackage(file)
+	if dir == "" {
+		dir = path.Dir(file)
+	}
+	goFiles, _ := filepath.Glob(filepath.Join(root, filepath.FromSlash(dir), "*.go"))
+	if len(goFiles) == 0 {
+		return nil, true
+	}
+	if dir == "." {
+		return []string{"."}, true
+	}
+	return []string{"./" + dir}, true
+}
+
+// pythonTestTargets maps a changed test file to itself, a conftest.py to
+// its directory, and a module to the test_<module>.py or <module>_test.py
+// files found beside it, in a tests/ or test/ directory beside it, or
+// mirrored under a top-level tests/ or test/ directory.
+func pythonTestTargets(root, file string, deleted bool) ([]string, bool) {
+	dir, base := path.Split(file)
+	dir = path.Clean(dir)
+	stem := strings.TrimSuffix(base, ".py")
+	switch {
+	case base == "conftest.py":
+		return []string{dir}, true
+	case strings.HasPrefix(base, "test_") || strings.HasSuffix(stem, "_test"):
+		if deleted {
+			return nil, true
+		}
+		return []string{file}, true
+	}
+
+	names := []string{"test_" + base, stem + "_test.py"}
+	var dirs []string
+	dirs = append(dirs, dir, path.Join(dir, "tests"), path.Join(dir, "test"))
+	for _, top := range []string{"tests", "test"} {
+		dirs = append(dirs, top, path.Join(top, dir))
+		if rest, ok := strings.CutPrefix(dir, "src/"); ok {
+			dirs = append(dirs, path.Join(top, rest))
+		}
+	}
+	found := existingFiles(root, dirs, names)
+	return found, len(found) > 0
+}
+
+// jsTestTargets maps a changed test file to itself and a module to the
+// <name>.test.* and <name>.spec.* files beside it, in a __tests__
+// directory beside it, or mirrored under a top-level test/ or tests/
+// directory.
+func jsTestTargets(root, file string, deleted bool) ([]string, bool) {
+	dir, base := path.Split(file)
+	dir = path.Clean(dir)
+	stem := strings.TrimSuffix(base, path.Ext(base))
+	if strings.HasSuffix(stem, ".test") || strings.HasSuffix(stem, ".spec") || path.Base(dir) == "__tests__" {
+		if deleted {
+			return nil, true
+		}
+		return []string{file}, true
+	}
+
+	var names []string
+	for _, ext := range jsExtensions {
+		names = append(names, stem+".test"+ext, stem+".spec"+ext)
+	}
+	dirs := []string{dir, path.Join(dir, "__tests__")}
+	for _, top := range []string{"test", "tests"} {
+		dirs = append(dirs, top, path.Join(top, dir))
+		if rest, ok := strings.CutPrefix(dir, "src/"); ok {
+			dirs = append(dirs, path.Join(top, rest))
+		}
+	}
+	found := existingFiles(root, dirs, names)
+	return found, len(found) > 0
+}
+
+// existingFiles returns the slash-separated paths dir/name that exist under
+// root, for every combination of dirs and names.
+func existingFiles(root string, dirs, names []string) []string {
+	var found []string
+	for _, dir := range dirs {
+		for _, name := range names {
+			rel := path.Join(dir, name)
+			if info, err := os.Stat(filepath.Join(root, filepath.FromSlash(rel))); err == nil && !info.IsDir() && !slices.Contains(found, rel) {
+				found = append(found, rel)
+			}
+		}
+	}
+	return found
+}
+
+// testImpactBuilder accumulates the targets of changed files across one or
+// more workspaces.
+type testImpactBuilder struct {
+	impact  TestImpact
+	targets map[string]map[string]bool
+}
+
+func newTestImpactBuilder() *testImpactBuilder {
+	return &testImpactBuilder{
+		impact:  TestImpact{ChangedFiles: []string{}, Languages: make(map[string]*LanguageTestTargets)},
+		targets: make(map[string]map[string]bool),
+	}
+}
+
+func (b *testImpactBuilder) language(name string) *LanguageTestTargets {
+	lt := b.impact.Languages[name]
+	if lt == nil {
+		lt = &LanguageTestTargets{}
+		b.impact.Languages[name] = lt
+		b.targets[name] = make(map[string]bool)
+	}
+	return lt
+}
+
+// runAll selects the whole suite of a language. The first reason is kept.
+func (b *testImpactBuilder) runAll(lang testImpactLanguage, reason string) {
+	lt := b.language(lang.name)
+	if !lt.FullSuite {
+		lt.FullSuite = true
+		lt.Reason = reason
+	}
+}
+
+// add maps the files changed in the workspace at root to test targets.
+func (b *testImpactBuilder) add(root string, files []state.FileChange) {
+	for _, f := range files {
+		if !slices.Contains(b.impact.ChangedFiles, f.Path) {
+			b.impact.ChangedFiles = append(b.impact.ChangedFiles, f.Path)
+		}
+		for _, lang := range testImpactLanguages {
+			if lang.fullSuite(f.Path) {
+				b.runAll(lang, f.Path+" changed")
+				continue
+			}
+			if !lang.owns(f.Path) {
+				continue
+			}
+			targets, ok := lang.targets(root, f.Path, f.Status == state.FileDeleted)
+			if !ok {
+				b.runAll(lang, "no tests found for "+f.Path)
+				continue
+			}
+			b.language(lang.name)
+			for _, t := range targets {
+				b.targets[lang.name][t] = true
+			}
+		}
+	}
+}
+
+// build returns the accumulated impact with sorted targets.
+func (b *testImpactBuilder) build() *TestImpact {
+	slices.Sort(b.impact.ChangedFiles)
+	for _, lang := range testImpactLanguages {
+		lt := b.impact.Languages[lang.name]
+		if lt == nil {
+			continue
+		}
+		if lt.FullSuite {
+			lt.Targets = []string{lang.all}
+			continue
+		}
+		lt.Targets = make([]string, 0, len(b.targets[lang.name]))
+		for t := range b.targets[lang.name] {
+			lt.Targets = append(lt.Targets, t)
+		}
+		slices.Sort(lt.Targets)
+	}
+	return &b.impact
+}
+
+// testImpactSources returns the steps whose change summaries a test_impact
+// step analyzes.
+func testImpactSources(step *Step) []string {
+	if step.TestImpact != nil && len(step.TestImpact.From) > 0 {
+		return step.TestImpact.From
+	}
+	return step.Dependencies
+}
+
+// executeTestImpactStep maps the change summaries of upstream steps to the
+// test targets they affect. The result is registered as the
+// "<step-id>:test_targets" artifact and each language's targets are
+// exposed, space-separated, as {{ <step-id>.test_targets.<language> }}
+// (empty when the language has none), so a later command step can run a
+// focused suite. A source step without a change summary selects every
+// language's whole suite: its changes are unknown.
+func (e *DefaultPipelineExecutor) executeTestImpactStep(_ context.Context, execution *PipelineExecution, step *Step) error {
+	pipelineID := execution.Status.ID
+	e.emit(event.Event{
+		Timestamp:  time.Now(),
+		PipelineID: pipelineID,
+		StepID:     step.ID,
+		State:      event.StateRunning,
+		Message:    "mapping changed files to test targets",
+	})
+
+	b := newTestImpactBuilder()
+	for _, src := range testImpactSources(step) {
+		rec := loadStepChanges(execution, src)
+		execution.mu.Lock()
+		root := execution.WorkspacePaths[src]
+		execution.mu.Unlock()
+		if rec == nil {
+			e.emit(event.Event{
+				Timestamp:  time.Now(),
+				PipelineID: pipelineID,
+				StepID:     step.ID,
+				State:      "warning",
+				Message:    fmt.Sprintf("no change summary for step %q; selecting the full test suite", src),
+			})
+			for _, lang := range testImpactLanguages {
+				b.runAll(lang, fmt.Sprintf("no change summary for step %q", src))
+			}
+			continue
+		}
+		b.add(root, rec.Files)
+	}
+	impact := b.build()
+
+	data, err := json.MarshalIndent(impact, "", "  ")
+	if err != nil {
+		return e.failTestImpactStep(execution, step, err)
+	}
+	wsRoot := execution.Manifest.Runtime.WorkspaceRoot
+	if wsRoot == "" {
+		wsRoot = ".agents/workspaces"
+	}
+	workspacePath := filepath.Join(wsRoot, e.workspaceRunIDFor(pipelineID), step.ID)
+	artPath := stepRecordArtifactPath(execution, workspacePath, step.ID, TestTargetsArtifactName)
+	if !e.writeStepRecordArtifact(execution, step, TestTargetsArtifactName, artPath, data) {
+		return e.failTestImpactStep(execution, step, fmt.Errorf("failed to write %s", artPath))
+	}
+	execution.mu.Lock()
+	execution.WorkspacePaths[step.ID] = workspacePath
+	execution.mu.Unlock()
+
+	var summary []string
+	for _, lang := range testImpactLanguages {
+		var targets []string
+		if lt := impact.Languages[lang.name]; lt != nil {
+			targets = lt.Targets
+			summary = append(summary, fmt.Sprintf("%s: %s", lang.name, strings.Join(targets, " ")))
+		}
+		if execution.Context != nil {
+			execution.Context.SetCustomVariable(step.ID+".test_targets."+lang.name, strings.Join(targets, " "))
+		}
+	}
+	if len(summary) == 0 {
+		summary = append(summary, "no test targets")
+	}
+
+	execution.mu.Lock()
+	execution.States[step.ID] = stateCompleted
+	execution.mu.Unlock()
+	if e.store != nil {
+		_ = e.store.SaveStepState(pipelineID, step.ID, state.StateCompleted, "")
+	}
+	e.emit(event.Event{
+		Timestamp:  time.Now(),
+		PipelineID: pipelineID,
+		StepID:     step.ID,
+		State:      event.StateCompleted,
+		Message:    fmt.Sprintf("%d changed files; %s", len(impact.ChangedFiles), strings.Join(summary, "; ")),
+		Artifacts:  []string{artPath},
+	})
+	return nil
+}
+
+func (e *DefaultPipelineExecutor) failTestImpactStep(execution *PipelineExecution, step *Step, err error) error {
+	execution.mu.Lock()
+	execution.States[step.ID] = stateFailed
+	execution.mu.Unlock()
+	if e.store != nil {
+		_ = e.store.SaveStepState(execution.Status.ID, step.ID, state.StateFailed, err.Error())
+	}
+	return fmt.Errorf("test_impact step %q: %w", step.ID, err)
+}
@@ -0,0 +1,216 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func analyzeChanges(root string, files ...state.FileChange) *TestImpact {
+	b := newTestImpactBuilder()
+	b.add(root, files)
+	return b.build()
+}
+
+func modified(path string) state.FileChange {
+	return state.FileChange{Path: path, Status: state.FileModified}
+}
+
+func TestTestImpact_Go(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspaceFile(t, filepath.Join(root, "main.go"), "package main\n")
+	writeWorkspaceFile(t, filepath.Join(root, "internal", "api", "handler.go"), "package api\n")
+	writeWorkspaceFile(t, filepath.Join(root, "internal", "api", "handler_test.go"), "package api\n")
+	writeWorkspaceFile(t, filepath.Join(root, "internal", "parse", "parse.go"), "package parse\n")
+	writeWorkspaceFile(t, filepath.Join(root, "internal", "parse", "testdata", "golden", "in.txt"), "x\n")
+
+	impact := analyzeChanges(root,
+		modified("internal/api/handler.go"),
+		modified("internal/api/handler_test.go"),
+		modified("internal/parse/testdata/golden/in.txt"),
+		modified("main.go"),
+		state.FileChange{Path: "internal/old/old.go", Status: state.FileDeleted},
+		modified("README.md"),
+	)
+
+	require.Contains(t, impact.Languages, "go")
+	assert.Equal(t, &LanguageTestTargets{Targets: []string{".", "./internal/api", "./internal/parse"}}, impact.Languages["go"])
+	assert.NotContains(t, impact.Languages, "python")
+	assert.NotContains(t, impact.Languages, "javascript")
+	assert.Len(t, impact.ChangedFiles, 6)
+}
+
+func TestTestImpact_GoModuleChangeRunsFullSuite(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspaceFile(t, filepath.Join(root, "internal", "api", "handler.go"), "package api\n")
+
+	impact := analyzeChanges(root, modified("internal/api/handler.go"), modified("go.mod"))
+
+	assert.Equal(t, &LanguageTestTargets{FullSuite: true, Reason: "go.mod changed", Targets: []string{"./..."}}, impact.Languages["go"])
+}
+
+func TestTestImpact_Python(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspaceFile(t, filepath.Join(root, "src", "app", "models.py"), "")
+	writeWorkspaceFile(t, filepath.Join(root, "tests", "app", "test_models.py"), "")
+	writeWorkspaceFile(t, filepath.Join(root, "pkg", "util.py"), "")
+	writeWorkspaceFile(t, filepath.Join(root, "pkg", "util_test.py"), "")
+	writeWorkspaceFile(t, filepath.Join(root, "tests", "test_cli.py"), "")
+
+	impact := analyzeChanges(root,
+		modified("src/app/models.py"),
+		modified("pkg/util.py"),
+		modified("tests/test_cli.py"),
+		modified("tests/integration/conftest.py"),
+	)
+
+	assert.Equal(t, &LanguageTestTargets{
+		Targets: []string{"pkg/util_test.py", "tests/app/test_models.py", "tests/integration", "tests/test_cli.py"},
+	}, impact.Languages["python"])
+}
+
+func TestTestImpact_PythonUntestedModuleRunsFullSuite(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspaceFile(t, filepath.Join(root, "pkg", "util.py"), "")
+
+	impact := analyzeChanges(root, modified("pkg/util.py"))
+
+	assert.Equal(t, &LanguageTestTargets{FullSuite: true, Reason: "no tests found for pkg/util.py", Targets: []string{"."}}, impact.Languages["python"])
+}
+
+func TestTestImpact_JavaScript(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspaceFile(t, filepath.Join(root, "web", "src", "api.ts"), "")
+	writeWorkspaceFile(t, filepath.Join(root, "web", "src", "api.test.ts"), "")
+	writeWorkspaceFile(t, filepath.Join(root, "web", "src", "Button.tsx"), "")
+	writeWorkspaceFile(t, filepath.Join(root, "web", "src", "__tests__", "Button.spec.tsx"), "")
+
+	impact := analyzeChanges(root,
+		modified("web/src/api.ts"),
+		modified("web/src/Button.tsx"),
+		modified("web/src/__tests__/helpers.js"),
+	)
+
+	assert.Equal(t, &LanguageTestTargets{
+		Targets: []string{"web/src/__tests__/Button.spec.tsx", "web/src/__tests__/helpers.js", "web/src/api.test.ts"},
+	}, impact.Languages["javascript"])
+
+	impact = analyzeChanges(root, modified("web/src/api.ts"), modified("web/package.json"))
+	assert.True(t, impact.Languages["javascript"].FullSuite)
+	assert.Equal(t, "web/package.json changed", impact.Languages["javascript"].Reason)
+}
+
+func TestDAGValidator_TestImpact(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []Step
+		wantErr string
+	}{
+		{
+			name: "defaults to dependencies",
+			steps: []Step{
+				{ID: "implement", Persona: "craftsman"},
+				{ID: "impact", Type: StepTypeTestImpact, Dependencies: []string{"implement"}},
+			},
+		},
+		{
+			name: "from transitive dependency",
+			steps: []Step{
+				{ID: "implement", Persona: "craftsman"},
+				{ID: "review", Persona: "reviewer", Dependencies: []string{"implement"}},
+				{ID: "impact", Type: StepTypeTestImpact, Dependencies: []string{"review"}, TestImpact: &TestImpactConfig{From: []string{"implement"}}},
+			},
+		},
+		{
+			name:    "no sources",
+			steps:   []Step{{ID: "impact", Type: StepTypeTestImpact}},
+			wantErr: "has no dependencies or test_impact.from",
+		},
+		{
+			name: "from unknown step",
+			steps: []Step{
+				{ID: "implement", Persona: "craftsman"},
+				{ID: "impact", Type: StepTypeTestImpact, Dependencies: []string{"implement"}, TestImpact: &TestImpactConfig{From: []string{"missing"}}},
+			},
+			wantErr: `non-existent step "missing"`,
+		},
+		{
+			name: "from step not upstream",
+			steps: []Step{
+				{ID: "implement", Persona: "craftsman"},
+				{ID: "docs", Persona: "writer"},
+				{ID: "impact", Type: StepTypeTestImpact, Dependencies: []string{"implement"}, TestImpact: &TestImpactConfig{From: []string{"docs"}}},
+			},
+			wantErr: "not one of its dependencies",
+		},
+		{
+			name: "config on another step type",
+			steps: []Step{
+				{ID: "implement", Persona: "craftsman", TestImpact: &TestImpactConfig{}},
+			},
+			wantErr: "sets test_impact but is not type=test_impact",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &DAGValidator{}
+			err := v.ValidateDAG(&Pipeline{Metadata: PipelineMetadata{Name: "p"}, Steps: tt.steps})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestExecute_TestImpactStep(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tmpDir := t.TempDir()
+	executor := NewDefaultPipelineExecutor(
+		&fileWritingAdapter{
+			MockAdapter: adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`)),
+			files:       map[string]string{"internal/api/handler.go": "package api\n", "README.md": "# Hi\n"},
+		},
+		WithEmitter(testutil.NewEventCollector()),
+	)
+	m := testutil.CreateTestManifest(tmpDir)
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "impact"},
+		Steps: []Step{
+			{ID: "implement", Persona: "navigator", Exec: ExecConfig{Source: "write code"}},
+			{ID: "impact", Type: StepTypeTestImpact, Dependencies: []string{"implement"}},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, executor.Execute(ctx, p, m, "test"))
+
+	execution := executor.LastExecution()
+	artPath := execution.ArtifactPaths["impact:"+TestTargetsArtifactName]
+	require.NotEmpty(t, artPath)
+	data, err := os.ReadFile(artPath)
+	require.NoError(t, err)
+	var impact TestImpact
+	require.NoError(t, json.Unmarshal(data, &impact))
+	assert.Equal(t, []string{"README.md", "internal/api/handler.go"}, impact.ChangedFiles)
+	assert.Equal(t, []string{"./internal/api"}, impact.Languages["go"].Targets)
+
+	assert.Equal(t, "go test ./internal/api", execution.Context.ResolvePlaceholders("go test {{ impact.test_targets.go }}"))
+	assert.Equal(t, "pytest ", execution.Context.ResolvePlaceholders("pytest {{ impact.test_targets.python }}"))
+	assert.Equal(t, stateCompleted, execution.States["impact"])
+}
@@ -87,6 +87,17 @@ func (tm *ThreadManager) GetTranscript(ctx context.Context, threadID, fidelity s
 	}
 }
 
+// LastEntry returns the most recent entry of a thread's transcript.
+func (tm *ThreadManager) LastEntry(threadID string) (ThreadEntry, bool) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	entries := tm.transcripts[threadID]
+	if len(entries) == 0 {
+		return ThreadEntry{}, false
+	}
+	return entries[len(entries)-1], true
+}
+
 // ThreadSize returns the total character count for a thread's transcript.
 func (tm *ThreadManager) ThreadSize(threadID string) int {
 	tm.mu.RLock()
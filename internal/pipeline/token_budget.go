@@ -0,0 +1,177 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/event"
+)
+
+// On-exceed actions for a TokenBudget.
+const (
+	BudgetOnExceedAbort = "abort" // fail the step, killing its adapter (default)
+	BudgetOnExceedWarn  = "warn"  // emit budget_exceeded and keep going
+)
+
+// TokenBudget caps the tokens a step, or all steps of a run together, may
+// use. Usage is metered from the adapter's stream while the step runs and
+// settled from its final token count.
+type TokenBudget struct {
+	MaxTokens int    `yaml:"max_tokens"`
+	OnExceed  string `yaml:"on_exceed,omitempty"` // abort (default) or warn
+}
+
+// aborts reports whether crossing the budget fails the step.
+func (b *TokenBudget) aborts() bool {
+	return b.OnExceed != BudgetOnExceedWarn
+}
+
+// TokenBudgetError reports a step failed because it, or the run as a whole,
+// used more tokens than its budget allows.
+type TokenBudgetError struct {
+	Step  string
+	Scope string // "step" or "pipeline"
+	Max   int
+	Used  int
+}
+
+func (e *TokenBudgetError) Error() string {
+	if e.Scope == "pipeline" {
+		return fmt.Sprintf("pipeline token budget exceeded: %d tokens used of budget.max_tokens %d (step '%s')", e.Used, e.Max, e.Step)
+	}
+	return fmt.Sprintf("step '%s' token budget exceeded: %d tokens used of budget.max_tokens %d", e.Step, e.Used, e.Max)
+}
+
+// validateTokenBudgets checks the pipeline and step token budgets.
+func validateTokenBudgets(p *Pipeline) error {
+	check := func(scope string, b *TokenBudget) error {
+		if b == nil {
+			return nil
+		}
+		if b.MaxTokens <= 0 {
+			return fmt.Errorf("%s: budget.max_tokens must be positive, got %d", scope, b.MaxTokens)
+		}
+		switch b.OnExceed {
+		case "", BudgetOnExceedAbort, BudgetOnExceedWarn:
+			return nil
+		}
+		return fmt.Errorf("%s: budget.on_exceed must be %q or %q, got %q", scope, BudgetOnExceedAbort, BudgetOnExceedWarn, b.OnExceed)
+	}
+	if err := check("pipeline", p.Budget); err != nil {
+		return err
+	}
+	for i := range p.Steps {
+		if err := check(fmt.Sprintf("step %q", p.Steps[i].ID), p.Steps[i].Budget); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enforceTokenBudgets wraps cfg.OnStreamEvent to hold the step to its own
+// token budget and the run to the pipeline's. Crossing a budget emits a
+// budget_exceeded event and records it on the run; with on_exceed: abort it
+// also cancels the returned context, killing the adapter. The returned
+// function, called once the adapter has returned with its final token count
+// (0 when unknown), settles the budgets and reports the exceeded one.
+func (e *DefaultPipelineExecutor) enforceTokenBudgets(ctx context.Context, execution *PipelineExecution, step *Step, res *stepRunResources, cfg *adapter.AdapterRunConfig) (context.Context, func(finalTokens int) error) {
+	runBudget := execution.Pipeline.Budget
+	if step.Budget == nil && runBudget == nil {
+		return ctx, func(int) error { return nil }
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	e.mu.RLock()
+	runBefore := e.totalTokens
+	e.mu.RUnlock()
+
+	var mu sync.Mutex
+	meter := newTokenMeter()
+	var stepReported bool
+	var exceeded *TokenBudgetError
+
+	// check must be called with mu held.
+	check := func(used int) {
+		if exceeded != nil {
+			return
+		}
+		if b := step.Budget; b != nil && !stepReported && used > b.MaxTokens {
+			stepReported = true
+			err := &TokenBudgetError{Step: step.ID, Scope: "step", Max: b.MaxTokens, Used: used}
+			e.reportTokenBudget(execution, step, res, err, b.aborts())
+			if b.aborts() {
+				exceeded = err
+				cancel()
+				return
+			}
+		}
+		if b := runBudget; b != nil && runBefore+used > b.MaxTokens && execution.markRunBudgetReported() {
+			err := &TokenBudgetError{Step: step.ID, Scope: "pipeline", Max: b.MaxTokens, Used: runBefore + used}
+			e.reportTokenBudget(execution, step, res, err, b.aborts())
+			if b.aborts() {
+				exceeded = err
+				cancel()
+			}
+		}
+	}
+
+	next := cfg.OnStreamEvent
+	cfg.OnStreamEvent = func(evt adapter.StreamEvent) {
+		if next != nil {
+			next(evt)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if in, out, changed := meter.observe(evt); changed {
+			check(in + out)
+		}
+	}
+
+	return ctx, func(finalTokens int) error {
+		cancel()
+		mu.Lock()
+		defer mu.Unlock()
+		if finalTokens > 0 {
+			check(finalTokens)
+		}
+		if exceeded != nil {
+			return exceeded
+		}
+		return nil
+	}
+}
+
+// reportTokenBudget emits budget_exceeded for err and records it on the run.
+func (e *DefaultPipelineExecutor) reportTokenBudget(execution *PipelineExecution, step *Step, res *stepRunResources, err *TokenBudgetError, aborts bool) {
+	msg := err.Error()
+	if !aborts {
+		msg += "; continuing (on_exceed: warn)"
+	}
+	e.emit(event.Event{
+		Timestamp:  time.Now(),
+		PipelineID: res.pipelineID,
+		StepID:     step.ID,
+		State:      "budget_exceeded",
+		Persona:    res.resolvedPersona,
+		TokensUsed: err.Used,
+		Message:    msg,
+	})
+	if e.store != nil {
+		_ = e.store.SetRunBudgetExceeded(execution.Status.ID, msg)
+	}
+}
+
+// markRunBudgetReported records that the pipeline token budget has been
+// reported as exceeded, returning false when it already was.
+func (ex *PipelineExecution) markRunBudgetReported() bool {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	if ex.runBudgetReported {
+		return false
+	}
+	ex.runBudgetReported = true
+	return true
+}
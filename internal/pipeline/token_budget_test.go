@@ -0,0 +1,120 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTokenBudgets(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       Pipeline
+		wantErr string
+	}{
+		{name: "no budgets", p: Pipeline{Steps: []Step{{ID: "a"}}}},
+		{
+			name: "valid budgets",
+			p: Pipeline{
+				Budget: &TokenBudget{MaxTokens: 100000, OnExceed: "warn"},
+				Steps:  []Step{{ID: "a", Budget: &TokenBudget{MaxTokens: 5000}}},
+			},
+		},
+		{
+			name:    "non-positive pipeline max",
+			p:       Pipeline{Budget: &TokenBudget{}, Steps: []Step{{ID: "a"}}},
+			wantErr: "pipeline: budget.max_tokens must be positive",
+		},
+		{
+			name:    "unknown on_exceed",
+			p:       Pipeline{Steps: []Step{{ID: "a", Budget: &TokenBudget{MaxTokens: 10, OnExceed: "ignore"}}}},
+			wantErr: `step "a": budget.on_exceed must be "abort" or "warn"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTokenBudgets(&tt.p)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// runTokenBudgetPipeline runs p with an adapter streaming events and
+// reporting tokens per step, returning every emitted event.
+func runTokenBudgetPipeline(t *testing.T, p *Pipeline, tokens int, events []adapter.StreamEvent) ([]event.Event, error) {
+	t.Helper()
+	collector := testutil.NewEventCollector()
+	streamAdapter := &streamEventAdapter{
+		MockAdapter:  adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`), adaptertest.WithTokensUsed(tokens)),
+		streamEvents: events,
+	}
+	executor := NewDefaultPipelineExecutor(streamAdapter, WithEmitter(collector))
+
+	m := testutil.CreateTestManifest(t.TempDir())
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := executor.Execute(ctx, p, m, "test")
+	return collector.GetEvents(), err
+}
+
+func budgetEvents(events []event.Event) []event.Event {
+	var out []event.Event
+	for _, e := range events {
+		if e.State == "budget_exceeded" {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func TestEnforceTokenBudgets_StepBudgetAborts(t *testing.T) {
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "token-budget-test"},
+		Steps: []Step{{ID: "work", Persona: "craftsman", Exec: ExecConfig{Source: "do work"},
+			Budget: &TokenBudget{MaxTokens: 1000}}},
+	}
+	events, err := runTokenBudgetPipeline(t, p, 500, []adapter.StreamEvent{
+		{Type: "text", MessageID: "m1", TokensIn: 800},
+		{Type: "text", MessageID: "m2", TokensIn: 400},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "step 'work' token budget exceeded: 1200 tokens used of budget.max_tokens 1000")
+	assert.Equal(t, FailureClassBudgetExhausted, ClassifyStepFailure(err, nil, nil))
+
+	exceeded := budgetEvents(events)
+	require.Len(t, exceeded, 1)
+	assert.Equal(t, "work", exceeded[0].StepID)
+	assert.Equal(t, 1200, exceeded[0].TokensUsed)
+}
+
+func TestEnforceTokenBudgets_PipelineBudgetWarns(t *testing.T) {
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "token-budget-test"},
+		Budget:   &TokenBudget{MaxTokens: 5000, OnExceed: "warn"},
+		Steps: []Step{
+			{ID: "one", Persona: "craftsman", Exec: ExecConfig{Source: "do work"}},
+			{ID: "two", Persona: "craftsman", Dependencies: []string{"one"}, Exec: ExecConfig{Source: "do work"}},
+			{ID: "three", Persona: "craftsman", Dependencies: []string{"two"}, Exec: ExecConfig{Source: "do work"}},
+		},
+	}
+	events, err := runTokenBudgetPipeline(t, p, 3000, nil)
+	require.NoError(t, err, "on_exceed: warn keeps the run going")
+
+	exceeded := budgetEvents(events)
+	require.Len(t, exceeded, 1, "the pipeline budget is reported once")
+	assert.Equal(t, "two", exceeded[0].StepID)
+	assert.Equal(t, 6000, exceeded[0].TokensUsed)
+	assert.Contains(t, exceeded[0].Message, "continuing (on_exceed: warn)")
+}
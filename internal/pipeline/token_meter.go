@@ -0,0 +1,157 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/cost"
+	"github.com/recinq/wave/internal/event"
+)
+
+// tokenProgressInterval bounds how often running token counts are emitted.
+const tokenProgressInterval = time.Second
+
+// burnRateMinElapsed is how long a step must run before its burn rate is
+// judged, so the first expensive prompt does not look like a runaway step.
+const burnRateMinElapsed = time.Minute
+
+// tokenMeter accumulates a step's token usage from adapter stream events.
+// Adapters report usage either per model message (MessageID set, repeated on
+// every event of the message) or as a running total; the final result event
+// is authoritative.
+type tokenMeter struct {
+	messages      map[string][2]int
+	cumIn, cumOut int
+	final         bool
+	in, out       int
+}
+
+func newTokenMeter() *tokenMeter {
+	return &tokenMeter{messages: make(map[string][2]int)}
+}
+
+// observe folds evt into the running usage and reports whether it changed.
+func (m *tokenMeter) observe(evt adapter.StreamEvent) (in, out int, changed bool) {
+	if m.final || (evt.TokensIn == 0 && evt.TokensOut == 0) {
+		return m.in, m.out, false
+	}
+	switch {
+	case evt.Type == "result":
+		m.final = true
+		in, out = evt.TokensIn, evt.TokensOut
+	case evt.MessageID != "":
+		m.messages[evt.MessageID] = [2]int{evt.TokensIn, evt.TokensOut}
+		in, out = m.cumIn, m.cumOut
+		for _, u := range m.messages {
+			in += u[0]
+			out += u[1]
+		}
+	default:
+		m.cumIn = max(m.cumIn, evt.TokensIn)
+		m.cumOut = max(m.cumOut, evt.TokensOut)
+		in, out = m.cumIn, m.cumOut
+		for _, u := range m.messages {
+			in += u[0]
+			out += u[1]
+		}
+	}
+	changed = in != m.in || out != m.out
+	m.in, m.out = in, out
+	return in, out, changed
+}
+
+// meterStepTokens wraps cfg.OnStreamEvent to track the step's token usage
+// while the adapter runs. Running totals are emitted as step_progress events
+// (at most once per tokenProgressInterval). With a cost ledger, the run's
+// projected cost is checked against the budget: crossing warn_at emits a
+// budget_warning, crossing budget_ceiling cancels the returned context,
+// killing the adapter. A spend rate above runtime.cost.burn_rate_warn emits a
+// one-off warning. The returned function, called once the adapter has
+// returned, reports the budget error.
+func (e *DefaultPipelineExecutor) meterStepTokens(ctx context.Context, execution *PipelineExecution, step *Step, res *stepRunResources, cfg *adapter.AdapterRunConfig) (context.Context, func() error) {
+	ctx, cancel := context.WithCancel(ctx)
+	costCfg := execution.Manifest.Runtime.Cost
+	meter := newTokenMeter()
+	start := time.Now()
+
+	var mu sync.Mutex
+	var lastEmit time.Time
+	var burnWarned bool
+	var budgetErr error
+
+	next := cfg.OnStreamEvent
+	cfg.OnStreamEvent = func(evt adapter.StreamEvent) {
+		if next != nil {
+			next(evt)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		in, out, changed := meter.observe(evt)
+		if !changed || budgetErr != nil {
+			return
+		}
+
+		if now := time.Now(); now.Sub(lastEmit) >= tokenProgressInterval || evt.Type == "result" {
+			lastEmit = now
+			e.emit(event.Event{
+				Timestamp:  now,
+				PipelineID: res.pipelineID,
+				StepID:     step.ID,
+				State:      "step_progress",
+				Persona:    res.resolvedPersona,
+				TokensIn:   in,
+				TokensOut:  out,
+				TokensUsed: in + out,
+			})
+		}
+
+		if e.costLedger != nil {
+			total, status := e.costLedger.Project(res.resolvedModel, in, out)
+			switch status {
+			case cost.BudgetWarning:
+				e.emit(event.Event{
+					Timestamp:  time.Now(),
+					PipelineID: res.pipelineID,
+					StepID:     step.ID,
+					State:      "budget_warning",
+					Message:    fmt.Sprintf("Cost warning: run at $%.4f with step %s still running (warn_at $%.2f)", total, step.ID, costCfg.WarnAt),
+				})
+			case cost.BudgetExceeded:
+				budgetErr = fmt.Errorf("budget exceeded: run reached $%.4f of the $%.2f ceiling while step %s was running (%d in / %d out tokens)",
+					total, costCfg.BudgetCeiling, step.ID, in, out)
+				cancel()
+				return
+			}
+		}
+
+		if costCfg.BurnRateWarn > 0 && !burnWarned {
+			if elapsed := time.Since(start); elapsed >= burnRateMinElapsed {
+				rate := e.stepCost(res.resolvedModel, in, out) / elapsed.Minutes()
+				if rate >= costCfg.BurnRateWarn {
+					burnWarned = true
+					e.emit(event.Event{
+						Timestamp:  time.Now(),
+						PipelineID: res.pipelineID,
+						StepID:     step.ID,
+						State:      "budget_warning",
+						Persona:    res.resolvedPersona,
+						TokensIn:   in,
+						TokensOut:  out,
+						Message:    fmt.Sprintf("Burn rate warning: step %s is spending $%.2f/min (burn_rate_warn $%.2f/min)", step.ID, rate, costCfg.BurnRateWarn),
+					})
+				}
+			}
+		}
+	}
+
+	return ctx, func() error {
+		cancel()
+		mu.Lock()
+		defer mu.Unlock()
+		return budgetErr
+	}
+}
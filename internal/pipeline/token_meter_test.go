@@ -0,0 +1,107 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/event"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenMeter_Observe(t *testing.T) {
+	m := newTokenMeter()
+	steps := []struct {
+		evt             adapter.StreamEvent
+		wantIn, wantOut int
+		wantChanged     bool
+	}{
+		{adapter.StreamEvent{Type: "text"}, 0, 0, false},
+		{adapter.StreamEvent{Type: "text", MessageID: "m1", TokensIn: 100, TokensOut: 10}, 100, 10, true},
+		// Later blocks of the same message repeat its usage.
+		{adapter.StreamEvent{Type: "tool_use", MessageID: "m1", TokensIn: 100, TokensOut: 10}, 100, 10, false},
+		{adapter.StreamEvent{Type: "tool_use", MessageID: "m2", TokensIn: 150, TokensOut: 20}, 250, 30, true},
+		// The final result replaces the running estimate and freezes it.
+		{adapter.StreamEvent{Type: "result", TokensIn: 240, TokensOut: 31}, 240, 31, true},
+		{adapter.StreamEvent{Type: "tool_use", MessageID: "m3", TokensIn: 500, TokensOut: 5}, 240, 31, false},
+	}
+	for i, s := range steps {
+		in, out, changed := m.observe(s.evt)
+		assert.Equal(t, []int{s.wantIn, s.wantOut}, []int{in, out}, "event %d", i)
+		assert.Equal(t, s.wantChanged, changed, "event %d", i)
+	}
+
+	// Adapters without message ids report running totals.
+	m = newTokenMeter()
+	m.observe(adapter.StreamEvent{Type: "tool_use", TokensIn: 100, TokensOut: 10})
+	in, out, _ := m.observe(adapter.StreamEvent{Type: "tool_use", TokensIn: 180, TokensOut: 25})
+	assert.Equal(t, []int{180, 25}, []int{in, out})
+}
+
+func runTokenMeterPipeline(t *testing.T, costCfg manifest.CostConfig, events []adapter.StreamEvent) ([]event.Event, error) {
+	t.Helper()
+	collector := testutil.NewEventCollector()
+	streamAdapter := &streamEventAdapter{
+		MockAdapter:  adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`)),
+		streamEvents: events,
+	}
+	executor := NewDefaultPipelineExecutor(streamAdapter, WithEmitter(collector))
+
+	m := testutil.CreateTestManifest(t.TempDir())
+	m.Runtime.Cost = costCfg
+	persona := m.Personas["craftsman"]
+	persona.Model = "claude-opus-4"
+	m.Personas["craftsman"] = persona
+
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "token-meter-test"},
+		Steps:    []Step{{ID: "work", Persona: "craftsman", Exec: ExecConfig{Source: "do work"}}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := executor.Execute(ctx, p, m, "test")
+	return collector.GetEventsByStep("work"), err
+}
+
+func TestMeterStepTokens_EmitsRunningTotals(t *testing.T) {
+	events, err := runTokenMeterPipeline(t, manifest.CostConfig{}, []adapter.StreamEvent{
+		{Type: "tool_use", ToolName: "Read", ToolInput: "a.go", MessageID: "m1", TokensIn: 1000, TokensOut: 50},
+		{Type: "tool_use", ToolName: "Edit", ToolInput: "a.go", MessageID: "m2", TokensIn: 1200, TokensOut: 80},
+		{Type: "result", TokensIn: 2300, TokensOut: 140},
+	})
+	require.NoError(t, err)
+
+	var used []int
+	for _, e := range events {
+		if e.State == "step_progress" && e.CurrentAction == "" && e.TokensUsed > 0 {
+			used = append(used, e.TokensUsed)
+		}
+	}
+	// The second message falls inside the emit interval; the result is always reported.
+	assert.Equal(t, []int{1050, 2440}, used)
+}
+
+func TestMeterStepTokens_BudgetExceededKillsStep(t *testing.T) {
+	events, err := runTokenMeterPipeline(t, manifest.CostConfig{BudgetCeiling: 1.0, WarnAt: 0.5}, []adapter.StreamEvent{
+		{Type: "text", MessageID: "m1", TokensIn: 40_000}, // $0.60
+		{Type: "text", MessageID: "m2", TokensIn: 40_000}, // $1.20
+		{Type: "tool_use", ToolName: "Write", ToolInput: "late.txt", MessageID: "m3", TokensIn: 40_000},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "budget exceeded")
+	assert.Contains(t, err.Error(), "$1.00 ceiling")
+	assert.Equal(t, FailureClassBudgetExhausted, ClassifyStepFailure(err, nil, nil))
+
+	var warnings int
+	for _, e := range events {
+		if e.State == "budget_warning" {
+			warnings++
+		}
+	}
+	assert.Equal(t, 1, warnings, "crossing warn_at mid-step warns once")
+}
@@ -0,0 +1,64 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/audit"
+)
+
+// TranscriptArtifactName is the artifact under which a step's normalized
+// adapter conversation is registered ("<step-id>:transcript").
+const TranscriptArtifactName = "transcript"
+
+// writeTranscriptArtifact persists the adapter's conversation transcript to
+// <artifact-dir>/<step-id>/transcript.json and registers it like any other
+// step artifact, so audits can replay every tool call and its result after
+// the workspace is gone. Adapters that cannot reconstruct a transcript leave
+// result.Transcript nil and no artifact is written. A step that declares its
+// own "transcript" output keeps it.
+func (e *DefaultPipelineExecutor) writeTranscriptArtifact(execution *PipelineExecution, step *Step, workspacePath string, transcript *adapter.Transcript) {
+	if transcript == nil {
+		return
+	}
+	for _, art := range step.OutputArtifacts {
+		if art.Name == TranscriptArtifactName {
+			return
+		}
+	}
+
+	data, err := json.MarshalIndent(transcript, "", "  ")
+	if err != nil {
+		return
+	}
+	artPath := filepath.Join(workspacePath, execution.Manifest.Runtime.Artifacts.GetDefaultArtifactDir(), step.ID, TranscriptArtifactName+".json")
+	if err := os.MkdirAll(filepath.Dir(artPath), 0755); err == nil {
+		err = os.WriteFile(artPath, data, 0644)
+	}
+	if err != nil {
+		e.trace(audit.TraceArtifactWrite, step.ID, 0, map[string]string{
+			"artifact": TranscriptArtifactName,
+			"path":     artPath,
+			"error":    err.Error(),
+		})
+		return
+	}
+
+	key := step.ID + ":" + TranscriptArtifactName
+	execution.mu.Lock()
+	execution.ArtifactPaths[key] = artPath
+	execution.mu.Unlock()
+	e.trace(audit.TraceArtifactWrite, step.ID, 0, map[string]string{
+		"artifact": TranscriptArtifactName,
+		"path":     artPath,
+		"size":     fmt.Sprintf("%d", len(data)),
+	})
+
+	if e.store != nil {
+		sum := e.registerArtifact(execution.Status.ID, step, TranscriptArtifactName, artPath, "json", int64(len(data)))
+		recordArtifactChecksum(execution, key, sum)
+	}
+}
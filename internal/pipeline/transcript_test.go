@@ -0,0 +1,97 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transcriptAdapter succeeds and reports a fixed conversation transcript.
+type transcriptAdapter struct {
+	transcript *adapter.Transcript
+}
+
+func (a transcriptAdapter) Run(_ context.Context, _ adapter.AdapterRunConfig) (*adapter.AdapterResult, error) {
+	return &adapter.AdapterResult{
+		Stdout:        strings.NewReader(`{"status":"success"}`),
+		ResultContent: `{"status":"success"}`,
+		Transcript:    a.transcript,
+	}, nil
+}
+
+// runTranscriptPipeline runs a one-step pipeline and returns the paths of
+// every transcript.json left in its workspaces.
+func runTranscriptPipeline(t *testing.T, a adapter.AdapterRunner, outputs []ArtifactDef) []string {
+	t.Helper()
+	tmp := t.TempDir()
+	executor := NewDefaultPipelineExecutor(a, WithEmitter(testutil.NewEventCollector()))
+	m := testutil.CreateTestManifest(tmp)
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "transcript-test"},
+		Steps:    []Step{{ID: "work", Persona: "craftsman", Exec: ExecConfig{Source: "do work"}, OutputArtifacts: outputs}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, executor.Execute(ctx, p, m, "test"))
+
+	var found []string
+	_ = filepath.Walk(tmp, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && info.Name() == TranscriptArtifactName+".json" {
+			found = append(found, path)
+		}
+		return nil
+	})
+	return found
+}
+
+func testTranscript() *adapter.Transcript {
+	return &adapter.Transcript{
+		Version: adapter.TranscriptVersion,
+		Adapter: "claude",
+		Turns: []adapter.TranscriptTurn{{
+			Role:   "assistant",
+			Blocks: []adapter.TranscriptBlock{{Type: "tool_use", ToolUseID: "tu_1", ToolName: "Read", Input: json.RawMessage(`{"file_path":"a.go"}`)}},
+			Usage:  &adapter.TranscriptUsage{InputTokens: 10, OutputTokens: 5},
+		}},
+	}
+}
+
+func TestTranscriptArtifact_Registered(t *testing.T) {
+	m := testutil.CreateTestManifest(t.TempDir())
+	execution := &PipelineExecution{
+		Manifest:      m,
+		ArtifactPaths: make(map[string]string),
+		Status:        &PipelineStatus{ID: "run-1"},
+	}
+	executor := NewDefaultPipelineExecutor(transcriptAdapter{})
+	ws := t.TempDir()
+	executor.writeTranscriptArtifact(execution, &Step{ID: "work"}, ws, testTranscript())
+
+	path := execution.ArtifactPaths["work:"+TranscriptArtifactName]
+	require.NotEmpty(t, path, "transcript must be registered as work:transcript")
+	assert.Equal(t, filepath.Join(ws, m.Runtime.Artifacts.GetDefaultArtifactDir(), "work", "transcript.json"), path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var got adapter.Transcript
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "claude", got.Adapter)
+	require.Len(t, got.Turns, 1)
+	assert.Equal(t, "Read", got.Turns[0].Blocks[0].ToolName)
+	assert.Equal(t, 5, got.Turns[0].Usage.OutputTokens)
+}
+
+func TestTranscriptArtifact_WrittenByStep(t *testing.T) {
+	assert.Len(t, runTranscriptPipeline(t, transcriptAdapter{transcript: testTranscript()}, nil), 1)
+	assert.Empty(t, runTranscriptPipeline(t, transcriptAdapter{}, nil), "no transcript from the adapter means no artifact")
+	assert.Empty(t, runTranscriptPipeline(t, transcriptAdapter{transcript: testTranscript()},
+		[]ArtifactDef{{Name: "transcript", Path: "transcript.md"}}), "a declared transcript output takes precedence")
+}
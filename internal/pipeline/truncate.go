@@ -0,0 +1,113 @@
+package pipeline
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/recinq/wave/internal/manifest"
+)
+
+// Stdout overflow policies for artifacts captured from stdout.
+const (
+	OverflowFail     = "fail"
+	OverflowTruncate = "truncate"
+)
+
+// StdoutTruncation describes how an oversized stdout capture was cut down.
+type StdoutTruncation struct {
+	Truncated     bool  `json:"truncated"`
+	OriginalBytes int64 `json:"original_bytes"`
+	HeadBytes     int64 `json:"head_bytes"`
+	TailBytes     int64 `json:"tail_bytes"`
+	OmittedBytes  int64 `json:"omitted_bytes"`
+}
+
+// stdoutOverflowTruncates reports whether an oversized stdout capture for
+// step should be truncated rather than failing the step. Every stdout
+// artifact must opt in — via its own on_overflow or the manifest-wide
+// runtime.artifacts.stdout_overflow — since they share one capture.
+func stdoutOverflowTruncates(step *Step, m *manifest.Manifest) bool {
+	fallback := OverflowFail
+	if m != nil && m.Runtime.Artifacts.StdoutOverflow != "" {
+		fallback = m.Runtime.Artifacts.StdoutOverflow
+	}
+	seen := false
+	for _, art := range step.OutputArtifacts {
+		if !art.IsStdoutArtifact() {
+			continue
+		}
+		seen = true
+		policy := art.OnOverflow
+		if policy == "" {
+			policy = fallback
+		}
+		if policy != OverflowTruncate {
+			return false
+		}
+	}
+	return seen
+}
+
+// TruncateHeadTail shrinks data to at most limit bytes, keeping the start and
+// end of the output around a marker line that records how much was dropped.
+// The head gets the larger share since it usually carries the structure
+// (headers, opening JSON) and the tail the final result or error. Cuts fall
+// on UTF-8 rune boundaries. When limit cannot fit the marker, only the head
+// is kept.
+func TruncateHeadTail(data []byte, limit int64) ([]byte, StdoutTruncation) {
+	original := int64(len(data))
+	if limit <= 0 || original <= limit {
+		return data, StdoutTruncation{OriginalBytes: original}
+	}
+
+	// Size the marker for the worst case so the result never exceeds limit.
+	markerLen := int64(len(truncationMarker(original)))
+	var head, tail int64
+	if markerLen < limit {
+		budget := limit - markerLen
+		head = budget * 2 / 3
+		tail = budget - head
+	} else {
+		head = limit
+	}
+	for head > 0 && !utf8.RuneStart(data[head]) {
+		head--
+	}
+	for tail > 0 && !utf8.RuneStart(data[original-tail]) {
+		tail--
+	}
+	omitted := original - head - tail
+
+	out := make([]byte, 0, limit)
+	out = append(out, data[:head]...)
+	if markerLen < limit {
+		out = append(out, truncationMarker(omitted)...)
+	}
+	out = append(out, data[original-tail:]...)
+
+	return out, StdoutTruncation{
+		Truncated:     true,
+		OriginalBytes: original,
+		HeadBytes:     head,
+		TailBytes:     tail,
+		OmittedBytes:  omitted,
+	}
+}
+
+func truncationMarker(omitted int64) string {
+	return fmt.Sprintf("\n\n... [wave: %d bytes truncated from stdout] ...\n\n", omitted)
+}
+
+// recordStdoutTruncation attaches the truncation details to the step's
+// registered stdout artifacts so downstream consumers (dashboard, later
+// steps, `wave artifacts`) can tell the content is incomplete. Best-effort:
+// store errors are ignored.
+func (e *DefaultPipelineExecutor) recordStdoutTruncation(execution *PipelineExecution, step *Step, trunc StdoutTruncation) {
+	stdoutNames := make(map[string]bool)
+	for _, art := range step.OutputArtifacts {
+		if art.IsStdoutArtifact() {
+			stdoutNames[art.Name] = true
+		}
+	}
+	e.mergeArtifactMetadata(execution.Status.ID, step, stdoutNames, "", "", "", trunc)
+}
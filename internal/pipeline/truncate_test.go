@@ -0,0 +1,203 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateHeadTail(t *testing.T) {
+	data := []byte(strings.Repeat("h", 500) + strings.Repeat("m", 1000) + strings.Repeat("t", 500))
+
+	out, trunc := TruncateHeadTail(data, 300)
+
+	assert.True(t, trunc.Truncated)
+	assert.LessOrEqual(t, len(out), 300)
+	assert.Equal(t, int64(len(data)), trunc.OriginalBytes)
+	assert.Equal(t, trunc.OriginalBytes, trunc.HeadBytes+trunc.TailBytes+trunc.OmittedBytes)
+	assert.Greater(t, trunc.HeadBytes, trunc.TailBytes, "head keeps the larger share")
+	assert.True(t, strings.HasPrefix(string(out), "hhh"))
+	assert.True(t, strings.HasSuffix(string(out), "ttt"))
+	assert.Contains(t, string(out), "bytes truncated from stdout")
+}
+
+func TestTruncateHeadTail_LimitSmallerThanMarker(t *testing.T) {
+	data := []byte(strings.Repeat("x", 500))
+
+	out, trunc := TruncateHeadTail(data, 10)
+
+	assert.Len(t, out, 10)
+	assert.Equal(t, strings.Repeat("x", 10), string(out))
+	assert.Equal(t, trunc.OriginalBytes, trunc.HeadBytes+trunc.TailBytes+trunc.OmittedBytes)
+}
+
+func TestTruncateHeadTail_RuneBoundaries(t *testing.T) {
+	data := []byte(strings.Repeat("é", 300) + strings.Repeat("世", 300))
+
+	for _, limit := range []int64{9, 100, 101, 102, 250} {
+		out, trunc := TruncateHeadTail(data, limit)
+		assert.LessOrEqual(t, int64(len(out)), limit)
+		assert.True(t, utf8.Valid(out), "limit %d cut a rune: %q", limit, out)
+		assert.Equal(t, trunc.OriginalBytes, trunc.HeadBytes+trunc.TailBytes+trunc.OmittedBytes)
+	}
+}
+
+func TestTruncateHeadTail_UnderLimit(t *testing.T) {
+	data := []byte("short")
+	out, trunc := TruncateHeadTail(data, 100)
+	assert.Equal(t, data, out)
+	assert.False(t, trunc.Truncated)
+	assert.Equal(t, int64(5), trunc.OriginalBytes)
+}
+
+func TestStdoutOverflowTruncates(t *testing.T) {
+	stdout := func(policy string) ArtifactDef {
+		return ArtifactDef{Name: "out", Source: "stdout", OnOverflow: policy}
+	}
+	truncating := &manifest.Manifest{Runtime: manifest.Runtime{Artifacts: manifest.RuntimeArtifactsConfig{StdoutOverflow: OverflowTruncate}}}
+
+	tests := []struct {
+		name string
+		arts []ArtifactDef
+		m    *manifest.Manifest
+		want bool
+	}{
+		{"default fails", []ArtifactDef{stdout("")}, &manifest.Manifest{}, false},
+		{"artifact opts in", []ArtifactDef{stdout(OverflowTruncate)}, &manifest.Manifest{}, true},
+		{"manifest default", []ArtifactDef{stdout("")}, truncating, true},
+		{"artifact overrides manifest", []ArtifactDef{stdout(OverflowFail)}, truncating, false},
+		{"all stdout artifacts must opt in", []ArtifactDef{stdout(OverflowTruncate), {Name: "b", Source: "stdout"}}, &manifest.Manifest{}, false},
+		{"file artifacts are ignored", []ArtifactDef{stdout(OverflowTruncate), {Name: "f", Path: "f.json"}}, &manifest.Manifest{}, true},
+		{"no stdout artifacts", []ArtifactDef{{Name: "f", Path: "f.json"}}, truncating, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, stdoutOverflowTruncates(&Step{OutputArtifacts: tt.arts}, tt.m))
+		})
+	}
+}
+
+// artifactMetadataStore records registered artifacts and their metadata.
+type artifactMetadataStore struct {
+	*testutil.MockStateStore
+	mu       sync.Mutex
+	records  []state.ArtifactRecord
+	metadata map[int64]*state.ArtifactMetadataRecord
+}
+
+func (s *artifactMetadataStore) RegisterArtifact(runID, stepID, name, path, artifactType string, sizeBytes int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, state.ArtifactRecord{
+		ID: int64(len(s.records) + 1), RunID: runID, StepID: stepID, Name: name, Path: path, Type: artifactType, SizeBytes: sizeBytes,
+	})
+	return nil
+}
+
+func (s *artifactMetadataStore) GetArtifacts(runID, stepID string) ([]state.ArtifactRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []state.ArtifactRecord
+	for _, r := range s.records {
+		if r.RunID == runID && (stepID == "" || r.StepID == stepID) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (s *artifactMetadataStore) SaveArtifactMetadata(artifactID int64, runID, stepID, previewText, mimeType, encoding, metadataJSON string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metadata[artifactID] = &state.ArtifactMetadataRecord{
+		ArtifactID: artifactID, RunID: runID, StepID: stepID,
+		PreviewText: previewText, MimeType: mimeType, Encoding: encoding, MetadataJSON: metadataJSON,
+	}
+	return nil
+}
+
+func (s *artifactMetadataStore) GetArtifactMetadata(artifactID int64) (*state.ArtifactMetadataRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.metadata[artifactID], nil
+}
+
+// metadataFor returns the metadata saved for the artifact registered as name.
+func (s *artifactMetadataStore) metadataFor(name string) *state.ArtifactMetadataRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.records {
+		if r.Name == name {
+			return s.metadata[r.ID]
+		}
+	}
+	return nil
+}
+
+func TestStdoutArtifactTruncatedOnOverflow(t *testing.T) {
+	collector := testutil.NewEventCollector()
+	largeContent := strings.Repeat("x", 1000)
+	mockAdapter := adaptertest.NewMockAdapter(
+		adaptertest.WithStdoutJSON(largeContent),
+		adaptertest.WithTokensUsed(100),
+	)
+	store := &artifactMetadataStore{MockStateStore: testutil.NewMockStateStore(), metadata: map[int64]*state.ArtifactMetadataRecord{}}
+
+	executor := NewDefaultPipelineExecutor(mockAdapter,
+		WithEmitter(collector),
+		WithStateStore(store),
+	)
+
+	tmpDir := t.TempDir()
+	m := testutil.CreateTestManifest(tmpDir)
+	m.Runtime.Artifacts.MaxStdoutSize = 200
+
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "truncate-test"},
+		Steps: []Step{
+			{
+				ID:      "produce",
+				Persona: "navigator",
+				Exec:    ExecConfig{Source: "produce output"},
+				OutputArtifacts: []ArtifactDef{
+					{Name: "large-output", Source: "stdout", Type: "text", OnOverflow: OverflowTruncate},
+				},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	require.NoError(t, executor.Execute(ctx, p, m, "test"))
+
+	pipelineID := collector.GetPipelineID()
+	artifactPath := filepath.Join(tmpDir, pipelineID, "produce", ".agents", "artifacts", "produce", "large-output")
+	content, err := os.ReadFile(artifactPath)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(content), 200)
+	assert.Contains(t, string(content), "bytes truncated from stdout")
+
+	assert.True(t, collector.HasEventWithState("artifact_truncated"), "should emit artifact_truncated event")
+
+	rec := store.metadataFor("large-output")
+	require.NotNil(t, rec)
+	var trunc StdoutTruncation
+	require.NoError(t, json.Unmarshal([]byte(rec.MetadataJSON), &trunc))
+	assert.Contains(t, rec.MetadataJSON, `"sha256"`, "registration index should be preserved")
+	assert.True(t, trunc.Truncated)
+	assert.Greater(t, trunc.OriginalBytes, int64(200))
+}
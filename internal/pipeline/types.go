@@ -2,7 +2,9 @@ package pipeline
 
 import (
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/recinq/wave/internal/contract"
@@ -34,6 +36,11 @@ const (
 	OnFailureRework   = "rework"
 	OnFailureWarn     = "warn"
 	OnFailureRetry    = "retry"
+	// Step-level on_failure: whether a failed step stops the run (halt, the
+	// default), lets its dependents run anyway (continue), or only skips its
+	// dependents (isolate). See failure_policy.go.
+	OnFailureHalt    = "halt"
+	OnFailureIsolate = "isolate"
 	// OnFailureRejected marks a contract failure as an *intentional design
 	// rejection* rather than a runtime error. The pipeline halts and the run
 	// terminates in the dedicated `rejected` state (distinct from `failed`).
@@ -55,6 +62,9 @@ const (
 const (
 	StepTypeConditional = "conditional"
 	StepTypeCommand     = "command"
+	// StepTypeTestImpact maps upstream change summaries to the test
+	// targets they affect. See executeTestImpactStep.
+	StepTypeTestImpact = "test_impact"
 )
 
 // EdgeTargetComplete is a sentinel edge target that signals pipeline completion.
@@ -62,22 +72,35 @@ const (
 const EdgeTargetComplete = "_complete"
 
 type Pipeline struct {
+	APIVersion      string                    `yaml:"apiVersion,omitempty"` // Schema version; empty = manifest.CurrentAPIVersion
 	Kind            string                    `yaml:"kind"`
 	Metadata        PipelineMetadata          `yaml:"metadata"`
 	Requires        *Requires                 `yaml:"requires,omitempty"`
 	Input           InputConfig               `yaml:"input"`
+	InputTemplates  map[string]string         `yaml:"input_templates,omitempty"` // Named input shortcuts, exposed as `wave run --<name> <value>`
+	Vars            map[string]string         `yaml:"vars,omitempty"`            // Named constants, referenced as {{ vars.<name> }}
+	Groups          []StepGroup               `yaml:"groups,omitempty"`          // Shared configuration for sets of steps
 	Steps           []Step                    `yaml:"steps"`
 	Hooks           []hooks.LifecycleHookDef  `yaml:"hooks,omitempty"`            // Pipeline-scoped lifecycle hooks
 	PipelineOutputs map[string]PipelineOutput `yaml:"pipeline_outputs,omitempty"` // Named output aliases
+	Publish         []PublishDef              `yaml:"publish,omitempty"`          // Artifact uploads run after a successful run
+	Changelog       *ChangelogConfig          `yaml:"changelog,omitempty"`        // Generated commit message, changelog entry and PR body
+	Locks           []string                  `yaml:"locks,omitempty"`            // Resource locks held for the whole run
+	LockTimeout     string                    `yaml:"lock_timeout,omitempty"`     // How long to wait for locks (default 30m)
 	ChatContext     *ChatContextConfig        `yaml:"chat_context,omitempty"`     // Chat session context injection
 	Skills          []string                  `yaml:"skills,omitempty"`           // Declarative skill references
 	MaxStepVisits   int                       `yaml:"max_step_visits,omitempty"`  // Graph-level max total visits across all steps (default 50)
+	Budget          *TokenBudget              `yaml:"budget,omitempty"`           // Token budget across all steps of a run
 
 	// Warnings is a runtime-only list of non-fatal load-time messages (e.g.
 	// WLP deprecation notices). Populated by YAMLPipelineLoader.Unmarshal and
 	// drained by the executor at startup. Not serialized.
 	// See docs/adr/011-wave-lego-protocol.md.
 	Warnings []string `yaml:"-" json:"-"`
+
+	// Digest is the SHA-256 of the raw pipeline YAML, set by
+	// YAMLPipelineLoader.Unmarshal. Empty for pipelines built in code.
+	Digest string `yaml:"-" json:"-"`
 }
 
 // ChatContextConfig configures what context to inject into post-pipeline chat sessions.
@@ -143,6 +166,10 @@ type PipelineMetadata struct {
 	Release     bool   `yaml:"release,omitempty"`
 	Category    string `yaml:"category,omitempty"`
 	Disabled    bool   `yaml:"disabled,omitempty"`
+	// Tags label the pipeline for notification routing (e.g. the owning team).
+	Tags []string `yaml:"tags,omitempty"`
+	// Deprecated steers users off the pipeline; see Deprecation.
+	Deprecated *Deprecation `yaml:"deprecated,omitempty"`
 }
 
 type InputConfig struct {
@@ -190,6 +217,35 @@ type RetryConfig struct {
 	// names (e.g. "claude-opus-4") never escalate regardless of this flag —
 	// they are user-pinned overrides and are preserved verbatim.
 	NoEscalate bool `yaml:"no_escalate,omitempty"`
+	// RetryOn restricts retries to failures matching one of the listed
+	// failure categories (rate_limit, adapter_crash, ...) or failure
+	// classes (transient, contract_failure, ...). When empty, the default
+	// class-based retryability applies.
+	RetryOn []string `yaml:"retry_on,omitempty"`
+	// NoRetryOn lists failure categories or classes that are never retried,
+	// even if they would otherwise qualify. Takes precedence over RetryOn.
+	NoRetryOn []string `yaml:"no_retry_on,omitempty"`
+}
+
+// ShouldRetry reports whether a failure with the given class and category
+// is eligible for another attempt under this config. no_retry_on wins over
+// retry_on; with neither set the decision falls back to IsRetryable.
+func (r RetryConfig) ShouldRetry(failureClass, failureCategory string) bool {
+	matches := func(kinds []string) bool {
+		for _, k := range kinds {
+			if k = canonicalFailureKind(k); k == failureClass || k == failureCategory {
+				return true
+			}
+		}
+		return false
+	}
+	if matches(r.NoRetryOn) {
+		return false
+	}
+	if len(r.RetryOn) > 0 {
+		return matches(r.RetryOn)
+	}
+	return IsRetryable(failureClass)
 }
 
 // Validate checks that the RetryConfig is well-formed.
@@ -206,6 +262,16 @@ func (r RetryConfig) Validate() error {
 	if r.ReworkStep != "" && r.OnFailure != OnFailureRework {
 		return fmt.Errorf("rework_step is set but on_failure is %q (must be %q)", r.OnFailure, OnFailureRework)
 	}
+	for _, kind := range r.RetryOn {
+		if !IsKnownFailureKind(kind) {
+			return fmt.Errorf("retry_on: unknown failure class or category %q", kind)
+		}
+	}
+	for _, kind := range r.NoRetryOn {
+		if !IsKnownFailureKind(kind) {
+			return fmt.Errorf("no_retry_on: unknown failure class or category %q", kind)
+		}
+	}
 	return nil
 }
 
@@ -317,6 +383,8 @@ type AttemptContext struct {
 	PartialArtifacts   map[string]string // Partial artifact paths (name -> path)
 	FailedStepID       string            // ID of the step that triggered rework
 	ReviewFeedbackPath string            // Path to review_feedback.json written by agent_review on_failure: rework
+	ErrorArtifactPath  string            // Path to the failed step's error record, set for on_failure.run handlers
+	KnownRemediations  []string          // Remediation notes from the error knowledge base matching PriorError
 }
 
 // EdgeConfig defines an edge from a step to a target step with an optional condition.
@@ -336,28 +404,35 @@ type Step struct {
 	// steps (needed to satisfy DAGValidator). The auto-injector reads
 	// this list as a fallback so it can still resolve upstream artifacts
 	// after resume. Not serialized.
-	ResumeOriginalDeps  []string         `yaml:"-" json:"-"`
-	TimeoutMinutes      int              `yaml:"timeout_minutes,omitempty"`
-	Optional            bool             `yaml:"optional,omitempty"`
-	Memory              MemoryConfig     `yaml:"memory"`
-	Workspace           WorkspaceConfig  `yaml:"workspace"`
-	Exec                ExecConfig       `yaml:"exec"`
-	OutputArtifacts     []ArtifactDef    `yaml:"output_artifacts,omitempty"`
-	Outcomes            []OutcomeDef     `yaml:"outcomes,omitempty"`
-	Handover            HandoverConfig   `yaml:"handover,omitempty"`
-	Retry               RetryConfig      `yaml:"retry,omitempty"`
-	ReworkOnly          bool             `yaml:"rework_only,omitempty"` // Only runs via rework trigger, not normal DAG scheduling
-	Strategy            *MatrixStrategy  `yaml:"strategy,omitempty"`
-	Validation          []ValidationRule `yaml:"validation,omitempty"`
-	MaxConcurrentAgents int              `yaml:"max_concurrent_agents,omitempty"`
-	Concurrency         int              `yaml:"concurrency,omitempty"`
+	ResumeOriginalDeps  []string           `yaml:"-" json:"-"`
+	TimeoutMinutes      int                `yaml:"timeout_minutes,omitempty"`
+	Optional            bool               `yaml:"optional,omitempty"`
+	Memory              MemoryConfig       `yaml:"memory"`
+	Workspace           WorkspaceConfig    `yaml:"workspace"`
+	Exec                ExecConfig         `yaml:"exec"`
+	OutputArtifacts     []ArtifactDef      `yaml:"output_artifacts,omitempty"`
+	Outcomes            []OutcomeDef       `yaml:"outcomes,omitempty"`
+	Handover            HandoverConfig     `yaml:"handover,omitempty"`
+	Retry               RetryConfig        `yaml:"retry,omitempty"`
+	ReworkOnly          bool               `yaml:"rework_only,omitempty"` // Only runs via rework trigger, not normal DAG scheduling
+	Strategy            *MatrixStrategy    `yaml:"strategy,omitempty"`
+	Validation          []ValidationRule   `yaml:"validation,omitempty"`
+	MaxConcurrentAgents int                `yaml:"max_concurrent_agents,omitempty"`
+	Concurrency         int                `yaml:"concurrency,omitempty"`
+	Canary              *CanaryConfig      `yaml:"canary,omitempty"`     // Run only for a sampled fraction of runs
+	When                string             `yaml:"when,omitempty"`       // Run only when this expression over earlier steps holds
+	Cache               *StepCacheConfig   `yaml:"cache,omitempty"`      // Replay identical temperature-0 adapter runs
+	OnFailure           *StepFailureConfig `yaml:"on_failure,omitempty"` // What a failure does to the run, and the handler step it runs
 
 	// Graph-mode fields
-	Type      string       `yaml:"type,omitempty"`       // "conditional", "command", or empty (default prompt)
+	Type      string       `yaml:"type,omitempty"`       // "conditional", "command", "test_impact", or empty (default prompt)
 	Edges     []EdgeConfig `yaml:"edges,omitempty"`      // Outgoing edges for graph-mode routing
 	MaxVisits int          `yaml:"max_visits,omitempty"` // Max times this step can be visited in a loop (default 10)
 	Script    string       `yaml:"script,omitempty"`     // Shell script for command steps
 
+	// TestImpact configures a test_impact step.
+	TestImpact *TestImpactConfig `yaml:"test_impact,omitempty"`
+
 	// Thread conversation continuity — steps sharing the same thread value
 	// participate in a conversation thread, receiving prior step transcripts.
 	Thread   string `yaml:"thread,omitempty"`   // Thread group ID (opt-in; empty = fresh memory)
@@ -376,6 +451,43 @@ type Step struct {
 	// See ResolveStepPermissions for the merge semantics.
 	Permissions manifest.Permissions `yaml:"permissions,omitempty"`
 
+	// Env is set in the step's adapter (or command) process, overriding
+	// runtime.env and the persona's env. Values support {{ }} placeholders.
+	// See resolveStepEnv for the merge semantics.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// AdapterOptions are extra CLI flags for the step's adapter, keyed by
+	// flag name without dashes, overriding the persona's per key. See
+	// resolveAdapterOptions for the merge semantics.
+	AdapterOptions map[string]string `yaml:"adapter_options,omitempty"`
+
+	// MaxTurns and MaxToolCalls kill the step's agent once it takes more
+	// model turns or tool calls than allowed; 0 means unlimited. MaxTurns
+	// is passed as --max-turns to adapters that accept it. See
+	// enforceStepLimits.
+	MaxTurns     int `yaml:"max_turns,omitempty"`
+	MaxToolCalls int `yaml:"max_tool_calls,omitempty"`
+
+	// Budget caps the tokens the step may use; the pipeline-level budget
+	// additionally caps the run's total. See enforceTokenBudgets.
+	Budget *TokenBudget `yaml:"budget,omitempty"`
+
+	// Locks are named resources the step holds while it runs. Runs sharing
+	// a state store queue for a lock, so two of them never run steps
+	// holding it at once. LockTimeout bounds the wait (default: the
+	// pipeline's lock_timeout, else 30m). See acquireLocks.
+	Locks       []string `yaml:"locks,omitempty"`
+	LockTimeout string   `yaml:"lock_timeout,omitempty"`
+
+	// Sandbox replaces the persona's sandbox settings for this step when the
+	// runtime sandbox is enabled.
+	Sandbox *manifest.PersonaSandbox `yaml:"sandbox,omitempty"`
+
+	// Group is the ID of the groups entry this step belongs to. The group's
+	// persona, workspace, sandbox and contract apply unless the step sets
+	// its own. See StepGroup.
+	Group string `yaml:"group,omitempty"`
+
 	// Composition primitives
 	SubPipeline string             `yaml:"pipeline,omitempty"`  // Child pipeline to execute
 	SubInput    string             `yaml:"input,omitempty"`     // Input template for child pipeline (legacy, string-typed children)
@@ -422,17 +534,26 @@ type ArtifactRef struct {
 	SchemaPath string `yaml:"schema_path,omitempty"` // JSON schema path for input validation
 	Optional   bool   `yaml:"optional,omitempty"`    // If true, missing artifact doesn't fail
 	Pipeline   string `yaml:"pipeline,omitempty"`    // Cross-pipeline artifact source (pipeline name)
+	// FromPipeline resolves the artifact from the latest completed run of
+	// the named pipeline via the state store. Step, if set, names the
+	// producing step in that pipeline.
+	FromPipeline string `yaml:"from_pipeline,omitempty"`
 }
 
 // Validate checks that the ArtifactRef is well-formed.
 // Step and Pipeline are mutually exclusive: Step references an artifact from
 // another step in the same pipeline, while Pipeline references an artifact
-// from a different pipeline's outputs.
+// from a different pipeline's outputs. FromPipeline excludes Pipeline and
+// narrows Step to a step of the referenced pipeline.
 func (r ArtifactRef) Validate(stepID string, idx int) error {
 	if r.Step != "" && r.Pipeline != "" {
 		return fmt.Errorf("step %q inject_artifacts[%d]: step and pipeline are mutually exclusive (got step=%q, pipeline=%q)",
 			stepID, idx, r.Step, r.Pipeline)
 	}
+	if r.FromPipeline != "" && r.Pipeline != "" {
+		return fmt.Errorf("step %q inject_artifacts[%d]: pipeline and from_pipeline are mutually exclusive (got pipeline=%q, from_pipeline=%q)",
+			stepID, idx, r.Pipeline, r.FromPipeline)
+	}
 	return nil
 }
 
@@ -443,6 +564,45 @@ type WorkspaceConfig struct {
 	Branch string  `yaml:"branch,omitempty"` // Branch name for worktree workspaces
 	Base   string  `yaml:"base,omitempty"`   // Start point for worktree (e.g. "main")
 	Ref    string  `yaml:"ref,omitempty"`    // Reference another step's workspace (shared worktree)
+	// Scope limits a worktree checkout to these repository paths using git
+	// sparse-checkout. Directories are checked out in full; entries with glob
+	// characters switch to gitignore-style patterns. Empty checks out everything.
+	Scope []string `yaml:"scope,omitempty"`
+	// Submodules checks out git submodules in the worktree: "none" (default),
+	// "init" for top-level submodules or "recursive" for nested ones too.
+	Submodules string `yaml:"submodules,omitempty"`
+	// LFS pulls Git LFS objects into the worktree after checkout.
+	LFS bool `yaml:"lfs,omitempty"`
+}
+
+// Validate checks that the WorkspaceConfig is well-formed.
+func (w WorkspaceConfig) Validate() error {
+	switch w.Submodules {
+	case "", "none", "init", "recursive":
+	default:
+		return fmt.Errorf("unknown workspace.submodules %q (valid: none, init, recursive)", w.Submodules)
+	}
+	if w.Type != "worktree" {
+		switch {
+		case len(w.Scope) > 0:
+			return fmt.Errorf("workspace.scope requires workspace.type \"worktree\"")
+		case w.Submodules != "" && w.Submodules != "none":
+			return fmt.Errorf("workspace.submodules requires workspace.type \"worktree\"")
+		case w.LFS:
+			return fmt.Errorf("workspace.lfs requires workspace.type \"worktree\"")
+		}
+		return nil
+	}
+	for i, entry := range w.Scope {
+		entry = strings.TrimPrefix(entry, "!")
+		switch {
+		case strings.TrimSpace(entry) == "":
+			return fmt.Errorf("workspace.scope[%d] is empty", i)
+		case slices.Contains(strings.Split(entry, "/"), ".."):
+			return fmt.Errorf("workspace.scope[%d] %q must not contain '..'", i, entry)
+		}
+	}
+	return nil
 }
 
 type Mount struct {
@@ -474,6 +634,10 @@ type ArtifactDef struct {
 	Type     string `yaml:"type,omitempty"` // "json", "text", "markdown", "binary"
 	Required bool   `yaml:"required,omitempty"`
 	Source   string `yaml:"source,omitempty"` // "file" (default) or "stdout"
+	// OnOverflow controls stdout artifacts larger than max_stdout_size:
+	// "fail" (default) or "truncate" (keep head and tail). Overrides
+	// runtime.artifacts.stdout_overflow.
+	OnOverflow string `yaml:"on_overflow,omitempty"`
 }
 
 // IsStdoutArtifact returns true if this artifact is captured from stdout.
@@ -533,6 +697,22 @@ type MatrixStrategy struct {
 	ChildPipeline  string `yaml:"child_pipeline,omitempty"`
 	InputTemplate  string `yaml:"input_template,omitempty"`
 	Stacked        bool   `yaml:"stacked,omitempty"`
+	// Filter, SortBy/SortOrder and Limit select the items this step runs,
+	// in that order, so one items_source can feed several steps. Filter and
+	// SortBy are item expressions, e.g. "item.size != 'xl'" and "item.priority".
+	Filter    string `yaml:"filter,omitempty"`
+	SortBy    string `yaml:"sort_by,omitempty"`
+	SortOrder string `yaml:"sort_order,omitempty"` // "asc" (default) or "desc"
+	Limit     int    `yaml:"limit,omitempty"`      // 0 = no limit
+	// MaxWorkers bounds how many items run at once. It replaces
+	// max_concurrency; 0 runs every item in parallel.
+	MaxWorkers int `yaml:"max_workers,omitempty"`
+	// ItemRetry retries a failed item on its own before it counts as
+	// failed. on_failure and rework_step do not apply per item.
+	ItemRetry *RetryConfig `yaml:"item_retry,omitempty"`
+	// FailFast cancels the items still running once one fails. Default
+	// true; false runs every item to completion and the step fails after.
+	FailFast *bool `yaml:"fail_fast,omitempty"`
 }
 
 type ValidationRule struct {
@@ -644,8 +824,10 @@ type GateConfig struct {
 
 	// Runtime-only field set by the executor before invoking the gate handler.
 	// Not serialized to YAML. Used by the WebUI gate handler to track which
-	// step a pending gate belongs to.
+	// step a pending gate belongs to. RuntimeRunID lets approval gates
+	// persist their wait for `wave approve`.
 	RuntimeStepID string `yaml:"-" json:"-"`
+	RuntimeRunID  string `yaml:"-" json:"-"`
 }
 
 // Validate checks that the GateConfig is well-formed when it uses choices.
@@ -0,0 +1,112 @@
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// pipelineVarNameRe restricts var names to what a {{ vars.<name> }}
+// placeholder can spell.
+var pipelineVarNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// pipelineVarPrefix namespaces pipeline vars among the context's custom
+// variables.
+const pipelineVarPrefix = "vars."
+
+// WithVars overrides values declared in the pipeline's vars block
+// (from CLI --var key=value). Overriding an undeclared var fails the run.
+func WithVars(vars map[string]string) ExecutorOption {
+	return func(ex *DefaultPipelineExecutor) { ex.varOverrides = vars }
+}
+
+// ParseVarAssignments parses repeated --var key=value flags. A later
+// assignment to the same key wins.
+func ParseVarAssignments(assignments []string) (map[string]string, error) {
+	if len(assignments) == 0 {
+		return nil, nil
+	}
+	vars := make(map[string]string, len(assignments))
+	for _, a := range assignments {
+		key, value, ok := strings.Cut(a, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q: expected key=value", a)
+		}
+		if !pipelineVarNameRe.MatchString(key) {
+			return nil, fmt.Errorf("invalid --var name %q: use letters, digits, '_' and '-'", key)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// validatePipelineVars checks that every declared var name can be
+// referenced from a template.
+func validatePipelineVars(p *Pipeline) error {
+	for name := range p.Vars {
+		if !pipelineVarNameRe.MatchString(name) {
+			return fmt.Errorf("invalid var name %q: use letters, digits, '_' and '-', starting with a letter or '_'", name)
+		}
+	}
+	return nil
+}
+
+// injectPipelineVars publishes the pipeline's vars, with overrides applied,
+// as {{ vars.<name> }}. Declared values may use other template variables
+// (project, forge, pipeline_id, other vars, ...), which are resolved here;
+// overrides are taken literally.
+func injectPipelineVars(ctx *PipelineContext, declared, overrides map[string]string) error {
+	var unknown []string
+	for name := range overrides {
+		if _, ok := declared[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		known := make([]string, 0, len(declared))
+		for name := range declared {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+		if len(known) == 0 {
+			return fmt.Errorf("unknown var(s) %s: pipeline declares no vars", strings.Join(unknown, ", "))
+		}
+		return fmt.Errorf("unknown var(s) %s (declared: %s)", strings.Join(unknown, ", "), strings.Join(known, ", "))
+	}
+
+	if len(declared) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(declared))
+	for name, value := range declared {
+		if override, ok := overrides[name]; ok {
+			value = override
+		}
+		values[pipelineVarPrefix+name] = value
+	}
+	ctx.setCustomVariablesBatch(values)
+
+	// A var may reference another; one pass per var resolves any acyclic
+	// chain regardless of map order.
+	for range declared {
+		changed := false
+		for name := range declared {
+			if _, ok := overrides[name]; ok {
+				continue
+			}
+			key := pipelineVarPrefix + name
+			if resolved := ctx.ResolvePlaceholders(values[key]); resolved != values[key] {
+				values[key] = resolved
+				changed = true
+			}
+		}
+		ctx.setCustomVariablesBatch(values)
+		if !changed {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVarAssignments(t *testing.T) {
+	vars, err := ParseVarAssignments([]string{"service=api", "target_dir=services/api", "query=a=b", "empty=", "service=web"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"service":    "web", // last assignment wins
+		"target_dir": "services/api",
+		"query":      "a=b",
+		"empty":      "",
+	}, vars)
+
+	vars, err = ParseVarAssignments(nil)
+	require.NoError(t, err)
+	assert.Nil(t, vars)
+
+	for _, bad := range []string{"novalue", "=x", "bad name=x", "9lives=x"} {
+		_, err := ParseVarAssignments([]string{bad})
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestInjectPipelineVars(t *testing.T) {
+	ctx := NewPipelineContext("deploy-abc123", "deploy", "")
+	declared := map[string]string{
+		"service":    "api",
+		"target_dir": "services/{{ vars.service }}",
+		"run_dir":    "out/{{ pipeline_id }}",
+	}
+	require.NoError(t, injectPipelineVars(ctx, declared, map[string]string{"service": "web"}))
+
+	assert.Equal(t, "deploy web to out/deploy-abc123",
+		ctx.ResolvePlaceholders("deploy {{ vars.service }} to {{vars.run_dir}}"))
+	// Vars referencing vars see the overridden value.
+	assert.Equal(t, "web", ctx.CustomVariables["vars.service"])
+	assert.Equal(t, "services/web", ctx.CustomVariables["vars.target_dir"])
+
+	err := injectPipelineVars(NewPipelineContext("p", "p", ""), declared, map[string]string{"servce": "x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown var(s) servce")
+	assert.Contains(t, err.Error(), "declared: run_dir, service, target_dir")
+
+	err = injectPipelineVars(NewPipelineContext("p", "p", ""), nil, map[string]string{"service": "x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pipeline declares no vars")
+}
+
+func TestValidateDAG_PipelineVarNames(t *testing.T) {
+	p := &Pipeline{
+		Vars:  map[string]string{"ok_name": "1", "also-ok": "2"},
+		Steps: []Step{{ID: "a", Persona: "p"}},
+	}
+	require.NoError(t, (&DAGValidator{}).ValidateDAG(p))
+
+	p.Vars["has space"] = "3"
+	err := (&DAGValidator{}).ValidateDAG(p)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid var name "has space"`)
+}
+
+func TestExecute_PipelineVarsInPromptAndOverride(t *testing.T) {
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "vars-test"},
+		Vars:     map[string]string{"service": "api", "env": "staging"},
+		Steps: []Step{{
+			ID:      "deploy",
+			Persona: "navigator",
+			Exec:    ExecConfig{Source: "deploy {{ vars.service }} to {{ vars.env }}"},
+		}},
+	}
+
+	run := func(opts ...ExecutorOption) (string, error) {
+		capture := &configCapturingAdapter{MockAdapter: adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`))}
+		executor := NewDefaultPipelineExecutor(capture, append(opts, WithEmitter(testutil.NewEventCollector()))...)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		err := executor.Execute(ctx, p, testutil.CreateTestManifest(t.TempDir()), "go")
+		return capture.getLastConfig().Prompt, err
+	}
+
+	prompt, err := run()
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "deploy api to staging")
+
+	prompt, err = run(WithVars(map[string]string{"env": "prod"}))
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "deploy api to prod")
+
+	_, err = run(WithVars(map[string]string{"region": "eu"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown var(s) region")
+}
@@ -0,0 +1,422 @@
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// A when: expression decides whether a step runs, from the results of the
+// steps before it:
+//
+//	when: steps.analyze.outputs.severity == "high" && steps.lint.state != "failed"
+//
+// Operands are string, number and boolean literals and references:
+//
+//	steps.<id>.state                       the step's state (completed, failed, skipped)
+//	steps.<id>.outputs.<json-path>         a field of the step's first output artifact
+//	steps.<id>.artifacts.<name>[.<path>]   a named artifact, or a field of it
+//
+// Operators are == != < <= > >= (numeric when both sides are numbers),
+// && || ! and parentheses. A lone operand is true unless it is empty,
+// "false" or "0". A reference that cannot be resolved, because the step did
+// not run or the field is absent, compares unequal to everything.
+
+// whenRef is a steps.<id>... reference in a when: expression.
+type whenRef struct {
+	Step     string
+	Kind     string // "state", "outputs" or "artifacts"
+	Artifact string // for "artifacts"
+	Path     string // JSON path into the artifact, if any
+}
+
+// whenValue is an operand's value; ok is false for unresolved references.
+type whenValue struct {
+	s  string
+	ok bool
+}
+
+func (v whenValue) truthy() bool {
+	return v.ok && v.s != "" && v.s != "false" && v.s != "0"
+}
+
+// whenResolver looks up the value of a reference.
+type whenResolver func(ref whenRef) (string, bool)
+
+type whenNode interface {
+	eval(resolve whenResolver) whenValue
+}
+
+type whenLiteral string
+
+type whenRefNode whenRef
+
+type whenNot struct{ x whenNode }
+
+type whenBinary struct {
+	op   string
+	l, r whenNode
+}
+
+func (n whenLiteral) eval(whenResolver) whenValue { return whenValue{s: string(n), ok: true} }
+
+func (n whenRefNode) eval(resolve whenResolver) whenValue {
+	s, ok := resolve(whenRef(n))
+	return whenValue{s: s, ok: ok}
+}
+
+func (n whenNot) eval(resolve whenResolver) whenValue {
+	return whenBool(!n.x.eval(resolve).truthy())
+}
+
+func (n whenBinary) eval(resolve whenResolver) whenValue {
+	switch n.op {
+	case "&&":
+		return whenBool(n.l.eval(resolve).truthy() && n.r.eval(resolve).truthy())
+	case "||":
+		return whenBool(n.l.eval(resolve).truthy() || n.r.eval(resolve).truthy())
+	}
+	l, r := n.l.eval(resolve), n.r.eval(resolve)
+	if !l.ok || !r.ok {
+		return whenBool(n.op == "!=")
+	}
+	cmp := strings.Compare(l.s, r.s)
+	lf, lerr := strconv.ParseFloat(l.s, 64)
+	rf, rerr := strconv.ParseFloat(r.s, 64)
+	if lerr == nil && rerr == nil {
+		switch {
+		case lf < rf:
+			cmp = -1
+		case lf > rf:
+			cmp = 1
+		default:
+			cmp = 0
+		}
+	}
+	switch n.op {
+	case "==":
+		return whenBool(cmp == 0)
+	case "!=":
+		return whenBool(cmp != 0)
+	case "<":
+		return whenBool(cmp < 0)
+	case "<=":
+		return whenBool(cmp <= 0)
+	case ">":
+		return whenBool(cmp > 0)
+	default: // ">="
+		return whenBool(cmp >= 0)
+	}
+}
+
+func whenBool(b bool) whenValue {
+	return whenValue{s: strconv.FormatBool(b), ok: true}
+}
+
+// WhenExpr is a parsed when: expression.
+type WhenExpr struct {
+	root whenNode
+	refs []whenRef
+}
+
+// Eval evaluates the expression, resolving references with resolve.
+func (w *WhenExpr) Eval(resolve whenResolver) bool {
+	return w.root.eval(resolve).truthy()
+}
+
+// ParseWhen parses a when: expression.
+func ParseWhen(expr string) (*WhenExpr, error) {
+	toks, err := lexWhen(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid when expression %q: %w", expr, err)
+	}
+	p := &whenParser{toks: toks}
+	root, err := p.or()
+	if err == nil && p.pos < len(p.toks) {
+		err = fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid when expression %q: %w", expr, err)
+	}
+	return &WhenExpr{root: root, refs: p.refs}, nil
+}
+
+type whenToken struct {
+	kind string // "op", "str", "num", "ident"
+	text string
+}
+
+func lexWhen(expr string) ([]whenToken, error) {
+	var toks []whenToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			toks = append(toks, whenToken{"str", expr[i+1 : i+1+end]})
+			i += end + 2
+		case strings.HasPrefix(expr[i:], "&&"), strings.HasPrefix(expr[i:], "||"),
+			strings.HasPrefix(expr[i:], "=="), strings.HasPrefix(expr[i:], "!="),
+			strings.HasPrefix(expr[i:], "<="), strings.HasPrefix(expr[i:], ">="):
+			toks = append(toks, whenToken{"op", expr[i : i+2]})
+			i += 2
+		case strings.IndexByte("!<>()", c) >= 0:
+			toks = append(toks, whenToken{"op", string(c)})
+			i++
+		case c == '-' || c >= '0' && c <= '9':
+			j := i + 1
+			for j < len(expr) && (expr[j] >= '0' && expr[j] <= '9' || expr[j] == '.') {
+				j++
+			}
+			if _, err := strconv.ParseFloat(expr[i:j], 64); err != nil {
+				return nil, fmt.Errorf("invalid number %q", expr[i:j])
+			}
+			toks = append(toks, whenToken{"num", expr[i:j]})
+			i = j
+		case isWhenIdentByte(c):
+			j := i
+			for j < len(expr) && isWhenIdentByte(expr[j]) {
+				j++
+			}
+			toks = append(toks, whenToken{"ident", expr[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	return toks, nil
+}
+
+func isWhenIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '-' || c == '.' || c == '[' || c == ']'
+}
+
+type whenParser struct {
+	toks []whenToken
+	pos  int
+	refs []whenRef
+}
+
+func (p *whenParser) peekOp(ops ...string) string {
+	if p.pos < len(p.toks) && p.toks[p.pos].kind == "op" {
+		for _, op := range ops {
+			if p.toks[p.pos].text == op {
+				return op
+			}
+		}
+	}
+	return ""
+}
+
+func (p *whenParser) or() (whenNode, error) {
+	l, err := p.and()
+	for err == nil && p.peekOp("||") != "" {
+		p.pos++
+		var r whenNode
+		if r, err = p.and(); err == nil {
+			l = whenBinary{op: "||", l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *whenParser) and() (whenNode, error) {
+	l, err := p.unary()
+	for err == nil && p.peekOp("&&") != "" {
+		p.pos++
+		var r whenNode
+		if r, err = p.unary(); err == nil {
+			l = whenBinary{op: "&&", l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *whenParser) unary() (whenNode, error) {
+	if p.peekOp("!") != "" {
+		p.pos++
+		x, err := p.unary()
+		return whenNot{x: x}, err
+	}
+	l, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	if op := p.peekOp("==", "!=", "<", "<=", ">", ">="); op != "" {
+		p.pos++
+		r, err := p.primary()
+		if err != nil {
+			return nil, err
+		}
+		return whenBinary{op: op, l: l, r: r}, nil
+	}
+	return l, nil
+}
+
+func (p *whenParser) primary() (whenNode, error) {
+	if p.pos >= len(p.toks) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.toks[p.pos]
+	p.pos++
+	switch tok.kind {
+	case "str", "num":
+		return whenLiteral(tok.text), nil
+	case "ident":
+		if tok.text == "true" || tok.text == "false" {
+			return whenLiteral(tok.text), nil
+		}
+		ref, err := parseWhenRef(tok.text)
+		if err != nil {
+			return nil, err
+		}
+		p.refs = append(p.refs, ref)
+		return whenRefNode(ref), nil
+	}
+	if tok.text == "(" {
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peekOp(")") == "" {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		p.pos++
+		return x, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", tok.text)
+}
+
+func parseWhenRef(text string) (whenRef, error) {
+	parts := strings.SplitN(text, ".", 4)
+	if len(parts) < 3 || parts[0] != "steps" || parts[1] == "" {
+		return whenRef{}, fmt.Errorf("unknown reference %q (expected steps.<id>.state, steps.<id>.outputs.<field> or steps.<id>.artifacts.<name>)", text)
+	}
+	ref := whenRef{Step: parts[1], Kind: parts[2]}
+	switch ref.Kind {
+	case "state":
+		if len(parts) == 4 {
+			return whenRef{}, fmt.Errorf("reference %q: state has no fields", text)
+		}
+	case "outputs":
+		if len(parts) < 4 || parts[3] == "" {
+			return whenRef{}, fmt.Errorf("reference %q: missing field after outputs", text)
+		}
+		ref.Path = parts[3]
+	case "artifacts":
+		if len(parts) < 4 || parts[3] == "" {
+			return whenRef{}, fmt.Errorf("reference %q: missing artifact name", text)
+		}
+		ref.Artifact, ref.Path, _ = strings.Cut(parts[3], ".")
+	default:
+		return whenRef{}, fmt.Errorf("reference %q: unknown field %q (expected state, outputs or artifacts)", text, ref.Kind)
+	}
+	return ref, nil
+}
+
+// validateWhenConditions parses every when: expression and checks that it
+// references known steps. In a DAG, the referenced steps must also be
+// upstream of the step, so they have finished when it is evaluated. Like a
+// canary step, a step with when: may not run, so artifacts injected from it
+// must be optional.
+func validateWhenConditions(p *Pipeline, dag bool) error {
+	stepMap := make(map[string]*Step, len(p.Steps))
+	for i := range p.Steps {
+		stepMap[p.Steps[i].ID] = &p.Steps[i]
+	}
+	v := &DAGValidator{}
+	conditional := make(map[string]bool)
+	for i := range p.Steps {
+		step := &p.Steps[i]
+		if step.When == "" {
+			continue
+		}
+		conditional[step.ID] = true
+		expr, err := ParseWhen(step.When)
+		if err != nil {
+			return fmt.Errorf("step %q: %w", step.ID, err)
+		}
+		for _, ref := range expr.refs {
+			if stepMap[ref.Step] == nil {
+				return fmt.Errorf("step %q: when references unknown step %q", step.ID, ref.Step)
+			}
+			if dag && !v.isTransitiveDep(step.ID, ref.Step, stepMap) {
+				return fmt.Errorf("step %q: when references step %q, which it does not depend on; add it to dependencies", step.ID, ref.Step)
+			}
+		}
+	}
+	for _, step := range p.Steps {
+		for _, ref := range step.Memory.InjectArtifacts {
+			if ref.Pipeline == "" && ref.FromPipeline == "" && conditional[ref.Step] && !ref.Optional {
+				return fmt.Errorf("step %q injects artifact %q from conditional step %q: mark the injection optional, since the step runs only when its when condition holds", step.ID, ref.Artifact, ref.Step)
+			}
+		}
+	}
+	return nil
+}
+
+// whenHolds evaluates step's when: condition against the run so far.
+func (e *DefaultPipelineExecutor) whenHolds(execution *PipelineExecution, step *Step) (bool, error) {
+	if step.When == "" {
+		return true, nil
+	}
+	expr, err := ParseWhen(step.When)
+	if err != nil {
+		return false, err
+	}
+	return expr.Eval(func(ref whenRef) (string, bool) {
+		return resolveWhenRef(execution, ref)
+	}), nil
+}
+
+// resolveWhenRef looks up ref in the run's step states and artifacts.
+func resolveWhenRef(execution *PipelineExecution, ref whenRef) (string, bool) {
+	execution.mu.Lock()
+	stepState, ran := execution.States[ref.Step]
+	artifacts := make(map[string]string, len(execution.ArtifactPaths))
+	for k, v := range execution.ArtifactPaths {
+		artifacts[k] = v
+	}
+	execution.mu.Unlock()
+
+	if ref.Kind == "state" {
+		return stepState, ran
+	}
+
+	name := ref.Artifact
+	if ref.Kind == "outputs" {
+		for i := range execution.Pipeline.Steps {
+			s := &execution.Pipeline.Steps[i]
+			if s.ID == ref.Step && len(s.OutputArtifacts) > 0 {
+				name = s.OutputArtifacts[0].Name
+				break
+			}
+		}
+	}
+	path, ok := artifacts[ref.Step+":"+name]
+	if !ok {
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	if ref.Path == "" {
+		return strings.TrimSpace(string(data)), true
+	}
+	val, err := ExtractJSONPath(data, "."+ref.Path)
+	if err != nil {
+		return "", false
+	}
+	return val, true
+}
@@ -0,0 +1,150 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWhen_Eval(t *testing.T) {
+	values := map[string]string{
+		"analyze.outputs.severity": "high",
+		"analyze.outputs.count":    "12",
+		"analyze.state":            "completed",
+		"lint.artifacts.report":    "clean",
+	}
+	resolve := func(ref whenRef) (string, bool) {
+		key := ref.Step + "." + ref.Kind
+		switch ref.Kind {
+		case "outputs":
+			key += "." + ref.Path
+		case "artifacts":
+			key += "." + ref.Artifact
+		}
+		v, ok := values[key]
+		return v, ok
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`steps.analyze.outputs.severity == "high"`, true},
+		{`steps.analyze.outputs.severity != 'high'`, false},
+		{`steps.analyze.outputs.count > 9`, true}, // numeric, not lexical
+		{`steps.analyze.outputs.count <= 9`, false},
+		{`steps.analyze.state == "completed" && steps.lint.artifacts.report == "clean"`, true},
+		{`steps.analyze.outputs.severity == "low" || !(steps.analyze.outputs.count < 10)`, true},
+		{`steps.analyze.outputs.missing == "high"`, false},
+		{`steps.analyze.outputs.missing != "high"`, true},
+		{`steps.analyze.outputs.severity`, true},
+		{`steps.analyze.outputs.missing`, false},
+		{`false || true`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := ParseWhen(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, expr.Eval(resolve))
+		})
+	}
+}
+
+func TestParseWhen_Errors(t *testing.T) {
+	for expr, want := range map[string]string{
+		``:                               "empty expression",
+		`steps.a.outputs.x == "high`:     "unterminated string",
+		`steps.a.outputs.x ==`:           "unexpected end of expression",
+		`(steps.a.state == "completed"`:  "missing ')'",
+		`context.severity == "high"`:     "unknown reference",
+		`steps.a.outputs == "high"`:      "missing field after outputs",
+		`steps.a.result == "ok"`:         `unknown field "result"`,
+		`steps.a.state == "ok" "extra"`:  `unexpected "extra"`,
+		`steps.a.state = "completed"`:    "unexpected character '='",
+		`steps.a.artifacts == "present"`: "missing artifact name",
+	} {
+		_, err := ParseWhen(expr)
+		require.Error(t, err, expr)
+		assert.Contains(t, err.Error(), want, expr)
+	}
+}
+
+func TestValidateWhenConditions(t *testing.T) {
+	analyze := Step{ID: "analyze"}
+	fix := Step{ID: "fix", Dependencies: []string{"analyze"}, When: `steps.analyze.outputs.severity == "high"`}
+	report := func(optional bool) Step {
+		return Step{ID: "report", Dependencies: []string{"fix"},
+			Memory: MemoryConfig{InjectArtifacts: []ArtifactRef{{Step: "fix", Artifact: "patch", As: "patch", Optional: optional}}}}
+	}
+
+	assert.NoError(t, validateWhenConditions(&Pipeline{Steps: []Step{analyze, fix, report(true)}}, true))
+
+	err := validateWhenConditions(&Pipeline{Steps: []Step{analyze, fix, report(false)}}, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `from conditional step "fix"`)
+
+	unordered := Step{ID: "fix", When: `steps.analyze.state == "completed"`}
+	err = validateWhenConditions(&Pipeline{Steps: []Step{analyze, unordered}}, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "which it does not depend on")
+	assert.NoError(t, validateWhenConditions(&Pipeline{Steps: []Step{analyze, unordered}}, false), "graph mode only needs the step to exist")
+
+	unknown := Step{ID: "fix", When: `steps.ghost.state == "completed"`}
+	err = validateWhenConditions(&Pipeline{Steps: []Step{unknown}}, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown step "ghost"`)
+}
+
+func TestExecute_WhenSkipsStep(t *testing.T) {
+	run := func(severity string) *testutil.EventCollector {
+		collector := testutil.NewEventCollector()
+		executor := NewDefaultPipelineExecutor(
+			adaptertest.NewMockAdapter(adaptertest.WithStdoutJSON(`{"status": "success"}`)),
+			WithEmitter(collector),
+		)
+		m := testutil.CreateTestManifest(t.TempDir())
+		p := &Pipeline{
+			Metadata: PipelineMetadata{Name: "when-test"},
+			Steps: []Step{
+				{
+					ID:     "analyze",
+					Type:   StepTypeCommand,
+					Script: `mkdir -p .agents/output && printf '{"severity":"` + severity + `"}' > .agents/output/analysis.json`,
+					OutputArtifacts: []ArtifactDef{
+						{Name: "analysis", Path: ".agents/output/analysis.json", Type: "json"},
+					},
+				},
+				{ID: "fix", Persona: "navigator", Exec: ExecConfig{Source: "fix"}, Dependencies: []string{"analyze"},
+					When: `steps.analyze.outputs.severity == "high"`},
+				{ID: "report", Persona: "navigator", Exec: ExecConfig{Source: "report"}, Dependencies: []string{"fix"}},
+			},
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		require.NoError(t, (&DAGValidator{}).ValidateDAG(p))
+		require.NoError(t, executor.Execute(ctx, p, m, "input"))
+		return collector
+	}
+
+	stepStates := func(c *testutil.EventCollector, stepID string) []string {
+		var states []string
+		for _, evt := range c.GetEventsByStep(stepID) {
+			states = append(states, evt.State)
+		}
+		return states
+	}
+
+	high := run("high")
+	assert.Contains(t, stepStates(high, "fix"), stateCompleted)
+	assert.NotContains(t, stepStates(high, "fix"), stateSkipped)
+
+	low := run("low")
+	assert.Contains(t, stepStates(low, "fix"), stateSkipped)
+	assert.NotContains(t, stepStates(low, "fix"), stateCompleted)
+	assert.Contains(t, stepStates(low, "report"), stateCompleted, "dependents of a step skipped by when still run")
+}
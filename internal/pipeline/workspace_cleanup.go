@@ -0,0 +1,46 @@
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/recinq/wave/internal/event"
+)
+
+// applyWorkspaceCleanup removes the run's workspace tree when
+// runtime.workspace_cleanup asks for it for this outcome. Git worktrees are
+// removed through git first so the repository does not keep dangling
+// entries; their branches are left in place. Nested runs, runs pinned
+// with `wave pin` and --preserve-workspace keep the workspace.
+func (e *DefaultPipelineExecutor) applyWorkspaceCleanup(execution *PipelineExecution, succeeded bool) {
+	if e.nestedRun || e.preserveWorkspace || execution.Manifest == nil {
+		return
+	}
+	if !execution.Manifest.Runtime.WorkspaceCleanup.RemoveAfter(succeeded) {
+		return
+	}
+
+	pipelineID := execution.Status.ID
+	if e.store != nil {
+		if run, err := e.store.GetRun(pipelineID); err == nil && run != nil && run.Pinned {
+			return
+		}
+	}
+	wsRoot := execution.Manifest.Runtime.WorkspaceRoot
+	if wsRoot == "" {
+		wsRoot = ".agents/workspaces"
+	}
+	pipelineWsPath := filepath.Join(wsRoot, e.workspaceRunIDFor(pipelineID))
+
+	e.cleanupWorktrees(execution, pipelineID)
+	if err := os.RemoveAll(pipelineWsPath); err != nil {
+		e.emit(event.Event{
+			Timestamp:  time.Now(),
+			PipelineID: pipelineID,
+			State:      "warning",
+			Message:    fmt.Sprintf("failed to remove workspace: %v", err),
+		})
+	}
+}
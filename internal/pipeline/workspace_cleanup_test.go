@@ -0,0 +1,92 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/adapter/adaptertest"
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/state"
+	"github.com/recinq/wave/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runWithCleanupPolicy executes a single-step pipeline under the given
+// workspace_cleanup policy with a pre-existing marker file in the run's
+// workspace, and returns the workspace path.
+func runWithCleanupPolicy(t *testing.T, policy manifest.WorkspaceCleanupConfig, fail bool, opts ...ExecutorOption) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	runID := "cleanup-policy-run"
+	pipelineWsPath := filepath.Join(tmpDir, runID)
+	require.NoError(t, os.MkdirAll(pipelineWsPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pipelineWsPath, "marker.txt"), []byte("previous run"), 0644))
+
+	adapterOpts := []adaptertest.MockOption{adaptertest.WithStdoutJSON(`{"status": "success"}`)}
+	if fail {
+		adapterOpts = []adaptertest.MockOption{adaptertest.WithFailure(errors.New("step failed"))}
+	}
+	opts = append([]ExecutorOption{WithEmitter(testutil.NewEventCollector()), WithRunID(runID)}, opts...)
+	executor := NewDefaultPipelineExecutor(adaptertest.NewMockAdapter(adapterOpts...), opts...)
+
+	m := testutil.CreateTestManifest(tmpDir)
+	m.Runtime.WorkspaceCleanup = policy
+	p := &Pipeline{
+		Metadata: PipelineMetadata{Name: "cleanup-policy"},
+		Steps:    []Step{{ID: "step1", Persona: "navigator", Exec: ExecConfig{Source: "test"}}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := executor.Execute(ctx, p, m, "test")
+	if fail {
+		require.Error(t, err)
+	} else {
+		require.NoError(t, err)
+	}
+	return pipelineWsPath
+}
+
+func TestWorkspaceCleanup_ReuseOnStartKeepsPreviousContent(t *testing.T) {
+	ws := runWithCleanupPolicy(t, manifest.WorkspaceCleanupConfig{OnStart: "reuse"}, false)
+	assert.FileExists(t, filepath.Join(ws, "marker.txt"))
+}
+
+func TestWorkspaceCleanup_DefaultKeepsWorkspaceAfterRun(t *testing.T) {
+	ws := runWithCleanupPolicy(t, manifest.WorkspaceCleanupConfig{}, false)
+	assert.NoFileExists(t, filepath.Join(ws, "marker.txt"), "on_start defaults to clean")
+	assert.DirExists(t, ws, "on_success defaults to keep")
+}
+
+func TestWorkspaceCleanup_RemoveOnSuccess(t *testing.T) {
+	ws := runWithCleanupPolicy(t, manifest.WorkspaceCleanupConfig{OnSuccess: "remove"}, false)
+	assert.NoDirExists(t, ws)
+}
+
+func TestWorkspaceCleanup_FailureKeepsWorkspaceByDefault(t *testing.T) {
+	ws := runWithCleanupPolicy(t, manifest.WorkspaceCleanupConfig{OnSuccess: "remove"}, true)
+	assert.DirExists(t, ws)
+}
+
+func TestWorkspaceCleanup_RemoveOnFailure(t *testing.T) {
+	ws := runWithCleanupPolicy(t, manifest.WorkspaceCleanupConfig{OnFailure: "remove"}, true)
+	assert.NoDirExists(t, ws)
+}
+
+func TestWorkspaceCleanup_NestedRunKeepsWorkspace(t *testing.T) {
+	ws := runWithCleanupPolicy(t, manifest.WorkspaceCleanupConfig{OnSuccess: "remove"}, false, withNestedRun())
+	assert.DirExists(t, ws)
+}
+
+func TestWorkspaceCleanup_PinnedRunKeepsWorkspace(t *testing.T) {
+	store := testutil.NewMockStateStore(testutil.WithGetRun(func(runID string) (*state.RunRecord, error) {
+		return &state.RunRecord{RunID: runID, Pinned: true}, nil
+	}))
+	ws := runWithCleanupPolicy(t, manifest.WorkspaceCleanupConfig{OnSuccess: "remove"}, false, WithStateStore(store))
+	assert.DirExists(t, ws)
+}
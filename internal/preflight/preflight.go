@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/recinq/wave/internal/checks"
+	"github.com/recinq/wave/internal/procutil"
 	"github.com/recinq/wave/internal/skill"
 	"github.com/recinq/wave/internal/tools"
 )
@@ -269,7 +270,8 @@ func (c *Checker) CheckSkills(skills []string) ([]Result, error) {
 				continue
 			}
 			// Capture output for diagnostics on failure
-			installOutput, _ := runCmdWithOutput("sh", "-c", cfg.Install)
+			sh := procutil.ShellArgs(cfg.Install)
+			installOutput, _ := runCmdWithOutput(sh[0], sh[1:]...)
 			msg := fmt.Sprintf("skill %q install failed: %v", name, err)
 			if installOutput != "" {
 				msg += "; output: " + truncateOutput(installOutput, 200)
@@ -306,7 +308,8 @@ func (c *Checker) CheckSkills(skills []string) ([]Result, error) {
 				continue
 			}
 			// Capture check output for diagnostics
-			checkOutput, checkErr := runCmdWithOutput("sh", "-c", cfg.Check)
+			sh := procutil.ShellArgs(cfg.Check)
+			checkOutput, checkErr := runCmdWithOutput(sh[0], sh[1:]...)
 			msg := fmt.Sprintf("skill %q still not detected after install", name)
 			if checkErr != nil {
 				msg += fmt.Sprintf(" (check error: %v)", checkErr)
@@ -345,9 +348,11 @@ func (c *Checker) isSkillInstalledWithToolBin(cfg skill.SkillConfig) bool {
 	return checks.SkillInstalledWithToolBin(c.runCmd, nil, cfg.Check)
 }
 
-// runShellCommand executes a shell command string via sh -c.
+// runShellCommand executes a shell command string via the platform shell
+// (sh -c on Unix).
 func (c *Checker) runShellCommand(command string) error {
-	return c.runCmd("sh", "-c", command)
+	sh := procutil.ShellArgs(command)
+	return c.runCmd(sh[0], sh[1:]...)
 }
 
 // truncateOutput trims output to maxLen characters, adding ellipsis if truncated.
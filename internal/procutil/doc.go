@@ -0,0 +1,6 @@
+// Package procutil isolates the platform-specific parts of subprocess
+// control: process groups, detaching, group termination, liveness checks,
+// and the shell used for inline scripts (hooks, command steps, skill
+// checks). Unix uses process groups and signals; Windows uses
+// CREATE_NEW_PROCESS_GROUP and taskkill.
+package procutil
@@ -0,0 +1,13 @@
+package procutil
+
+import (
+	"context"
+	"os/exec"
+)
+
+// ShellCommand returns a command that runs script through the platform
+// shell (see ShellArgs).
+func ShellCommand(ctx context.Context, script string) *exec.Cmd {
+	args := ShellArgs(script)
+	return exec.CommandContext(ctx, args[0], args[1:]...)
+}
@@ -0,0 +1,27 @@
+package procutil
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestAlive(t *testing.T) {
+	if !Alive(os.Getpid()) {
+		t.Error("expected current process to be alive")
+	}
+	if Alive(0) || Alive(-1) {
+		t.Error("non-positive pids must not be reported alive")
+	}
+}
+
+func TestShellCommand(t *testing.T) {
+	out, err := ShellCommand(context.Background(), "echo wave").Output()
+	if err != nil {
+		t.Fatalf("ShellCommand failed: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "wave" {
+		t.Errorf("unexpected output %q", out)
+	}
+}
@@ -0,0 +1,70 @@
+//go:build !windows
+
+package procutil
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// SetProcessGroup starts cmd in its own process group so the whole tree
+// can be terminated with TerminateGroup/KillGroup.
+func SetProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Pgid: 0}
+}
+
+// SetDetached starts cmd in a new session so it survives the parent's
+// terminal closing.
+func SetDetached(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// TerminateGroup asks the process group led by pid to exit (SIGTERM).
+// Returns os.ErrProcessDone when the group no longer exists.
+func TerminateGroup(pid int) error {
+	return signalGroup(pid, syscall.SIGTERM)
+}
+
+// KillGroup forcibly kills the process group led by pid (SIGKILL).
+// Returns os.ErrProcessDone when the group no longer exists.
+func KillGroup(pid int) error {
+	return signalGroup(pid, syscall.SIGKILL)
+}
+
+// GroupAlive reports whether any process in the group led by pid exists.
+func GroupAlive(pid int) bool {
+	return pid > 0 && !errors.Is(syscall.Kill(-pid, 0), syscall.ESRCH)
+}
+
+// Alive reports whether a process with the given pid exists. A process
+// owned by another user counts as alive.
+func Alive(pid int) bool {
+	return pid > 0 && !errors.Is(syscall.Kill(pid, 0), syscall.ESRCH)
+}
+
+// ShellArgs returns the argv that runs script through sh.
+func ShellArgs(script string) []string {
+	return []string{"sh", "-c", script}
+}
+
+// PlatformEnv returns KEY=VALUE entries the platform needs in any curated
+// subprocess environment beyond HOME/PATH. Unix needs none.
+func PlatformEnv() []string {
+	return nil
+}
+
+// EnvKey normalizes an environment variable name for comparison.
+// Names are case-sensitive on Unix.
+func EnvKey(name string) string {
+	return name
+}
+
+func signalGroup(pid int, sig syscall.Signal) error {
+	err := syscall.Kill(-pid, sig)
+	if errors.Is(err, syscall.ESRCH) {
+		return os.ErrProcessDone
+	}
+	return err
+}
@@ -0,0 +1,32 @@
+//go:build !windows
+
+package procutil
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestTerminateGroup(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+	SetProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	pid := cmd.Process.Pid
+	if !GroupAlive(pid) {
+		t.Fatal("expected process group to be alive")
+	}
+	if err := TerminateGroup(pid); err != nil {
+		t.Fatalf("TerminateGroup: %v", err)
+	}
+	_ = cmd.Wait()
+	if GroupAlive(pid) {
+		t.Error("expected process group to be gone after SIGTERM")
+	}
+	if err := KillGroup(pid); !errors.Is(err, os.ErrProcessDone) {
+		t.Errorf("KillGroup on exited group: got %v, want os.ErrProcessDone", err)
+	}
+}
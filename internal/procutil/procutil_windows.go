@@ -0,0 +1,113 @@
+//go:build windows
+
+package procutil
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const (
+	detachedProcess                = 0x00000008
+	processQueryLimitedInformation = 0x00001000
+	stillActive                    = 259
+)
+
+// SetProcessGroup starts cmd in a new process group so the whole tree can
+// be terminated with TerminateGroup/KillGroup.
+func SetProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// SetDetached starts cmd without a console in a new process group so it
+// survives the parent's console closing.
+func SetDetached(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP | detachedProcess}
+}
+
+// TerminateGroup asks the process tree rooted at pid to exit. Console
+// programs usually ignore the request; KillGroup is the reliable fallback.
+// Returns os.ErrProcessDone when the process no longer exists.
+func TerminateGroup(pid int) error {
+	return taskkill(pid, false)
+}
+
+// KillGroup forcibly kills the process tree rooted at pid.
+// Returns os.ErrProcessDone when the process no longer exists.
+func KillGroup(pid int) error {
+	return taskkill(pid, true)
+}
+
+// GroupAlive reports whether the process tree root pid is still running.
+// Windows has no process-group liveness probe, so only the root is checked.
+func GroupAlive(pid int) bool {
+	return Alive(pid)
+}
+
+// Alive reports whether a process with the given pid is still running.
+func Alive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		// Access denied means the process exists but belongs to someone else.
+		return err == syscall.ERROR_ACCESS_DENIED
+	}
+	defer syscall.CloseHandle(h)
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// ShellArgs returns the argv that runs script through sh when one is on
+// PATH (Git for Windows, MSYS2), falling back to cmd.exe. Pipeline scripts
+// are written for sh, so installing Git for Windows is recommended.
+func ShellArgs(script string) []string {
+	if sh, err := exec.LookPath("sh"); err == nil {
+		return []string{sh, "-c", script}
+	}
+	return []string{"cmd.exe", "/C", script}
+}
+
+// platformEnvKeys are variables Windows programs fail without (SYSTEMROOT
+// for Winsock and crypto, COMSPEC/PATHEXT for command lookup, profile and
+// temp directories).
+var platformEnvKeys = []string{
+	"SYSTEMROOT", "WINDIR", "COMSPEC", "PATHEXT",
+	"USERPROFILE", "APPDATA", "LOCALAPPDATA", "TEMP", "TMP",
+}
+
+// PlatformEnv returns KEY=VALUE entries the platform needs in any curated
+// subprocess environment beyond HOME/PATH.
+func PlatformEnv() []string {
+	var env []string
+	for _, k := range platformEnvKeys {
+		if v := os.Getenv(k); v != "" {
+			env = append(env, k+"="+v)
+		}
+	}
+	return env
+}
+
+// EnvKey normalizes an environment variable name for comparison.
+// Names are case-insensitive on Windows ("Path" and "PATH" are the same).
+func EnvKey(name string) string {
+	return strings.ToUpper(name)
+}
+
+func taskkill(pid int, force bool) error {
+	if !Alive(pid) {
+		return os.ErrProcessDone
+	}
+	args := []string{"/T", "/PID", strconv.Itoa(pid)}
+	if force {
+		args = append([]string{"/F"}, args...)
+	}
+	return exec.Command("taskkill", args...).Run()
+}
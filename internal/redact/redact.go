@@ -70,6 +70,16 @@ var credentialPatterns = func() []*regexp.Regexp {
 	return patterns
 }()
 
+// secretNamePattern matches variable names containing a credential keyword.
+var secretNamePattern = regexp.MustCompile(`(?i)(` + strings.Join(genericKeywords, `|`) + `)`)
+
+// IsSecretName reports whether name (an environment variable or config key)
+// looks like it holds a credential, e.g. GITHUB_TOKEN or db_password. Values
+// of such names should be masked entirely rather than pattern-scanned.
+func IsSecretName(name string) bool {
+	return secretNamePattern.MatchString(name)
+}
+
 // Redact returns content with all known credential patterns replaced by
 // Placeholder. Inputs larger than 256 KB are returned unchanged to bound
 // scanning cost; no other transformation is applied.
@@ -99,3 +99,22 @@ func TestRedact_LargeInputUnchanged(t *testing.T) {
 		t.Errorf("Redact should return inputs over %d bytes unchanged", maxRedactSize)
 	}
 }
+
+// TestIsSecretName covers credential-looking variable names.
+func TestIsSecretName(t *testing.T) {
+	cases := map[string]bool{
+		"GITHUB_TOKEN":      true,
+		"OPENAI_API_KEY":    true,
+		"db_password":       true,
+		"AWS_ACCESS_KEY_ID": true,
+		"CLIENT_SECRET":     true,
+		"HTTP_PROXY":        false,
+		"LANG":              false,
+		"FEATURE_FLAGS":     false,
+	}
+	for name, want := range cases {
+		if got := IsSecretName(name); got != want {
+			t.Errorf("IsSecretName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
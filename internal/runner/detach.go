@@ -6,11 +6,12 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/recinq/wave/internal/config"
+	"github.com/recinq/wave/internal/procutil"
 	"github.com/recinq/wave/internal/state"
 )
 
@@ -38,11 +39,16 @@ func (s detachFlagSpec) FlagSpecFlag() string { return s.flag }
 // NOT flow through to the detached subprocess. Update this list (with a
 // reason) when adding a new field that should not be mirrored.
 var DetachFlagSkippedFields = map[string]string{
-	"Pipeline": "always emitted explicitly as --pipeline before spec processing",
-	"RunID":    "always emitted explicitly as --run with the freshly created runID",
-	"Detach":   "subprocess must not recurse into detached mode",
-	"DryRun":   "Detach is unreachable when --dry-run is set (handled in runRun)",
-	"Output":   "OutputConfig is a struct — Verbose handled outside the spec list",
+	"Pipeline":              "always emitted explicitly as --pipeline before spec processing",
+	"RunID":                 "always emitted explicitly as --run with the freshly created runID",
+	"Detach":                "subprocess must not recurse into detached mode",
+	"DryRun":                "Detach is unreachable when --dry-run is set (handled in runRun)",
+	"Output":                "OutputConfig is a struct — Verbose handled outside the spec list",
+	"IfNotAlreadySucceeded": "duplicate check runs in the parent before detaching; the child would match its own run",
+	"Deterministic":         "rejected with --detach: the parent mints the run ID before the subprocess starts",
+	"Seed":                  "only meaningful with Deterministic, which is rejected with --detach",
+	"TemplateInputs":        "expanded into Input when the pipeline loads, before detaching",
+	"Priority":              "orders the parent's wait for a worker slot; the child starts with its slot taken",
 }
 
 // boolFlag emits "--<flag>" when get(o) is true.
@@ -76,6 +82,23 @@ func intFlag(field, flag string, get func(config.RuntimeConfig) int) detachFlagS
 	}}
 }
 
+// mapFlag emits "--<flag> key=value" for each entry of get(o), sorted by key
+// so the argv is deterministic.
+func mapFlag(field, flag string, get func(config.RuntimeConfig) map[string]string) detachFlagSpec {
+	return detachFlagSpec{field: field, flag: flag, emit: func(o config.RuntimeConfig, a []string) []string {
+		m := get(o)
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			a = append(a, "--"+flag, k+"="+m[k])
+		}
+		return a
+	}}
+}
+
 // DetachFlagSpecs is the single source of truth for argv mirroring.
 // Adding a new pass-through flag means adding ONE entry here.
 var DetachFlagSpecs = []detachFlagSpec{
@@ -90,6 +113,7 @@ var DetachFlagSpecs = []detachFlagSpec{
 	boolFlag("PreserveWorkspace", "preserve-workspace", func(o config.RuntimeConfig) bool { return o.PreserveWorkspace }),
 	strFlag("Steps", "steps", "", func(o config.RuntimeConfig) string { return o.Steps }),
 	strFlag("Exclude", "exclude", "", func(o config.RuntimeConfig) string { return o.Exclude }),
+	mapFlag("Artifacts", "artifact", func(o config.RuntimeConfig) map[string]string { return o.Artifacts }),
 	boolFlag("Continuous", "continuous", func(o config.RuntimeConfig) bool { return o.Continuous }),
 	strFlag("Source", "source", "", func(o config.RuntimeConfig) string { return o.Source }),
 	intFlag("MaxIterations", "max-iterations", func(o config.RuntimeConfig) int { return o.MaxIterations }),
@@ -98,6 +122,9 @@ var DetachFlagSpecs = []detachFlagSpec{
 	boolFlag("AutoApprove", "auto-approve", func(o config.RuntimeConfig) bool { return o.AutoApprove }),
 	boolFlag("NoRetro", "no-retro", func(o config.RuntimeConfig) bool { return o.NoRetro }),
 	boolFlag("ForceModel", "force-model", func(o config.RuntimeConfig) bool { return o.ForceModel }),
+	boolFlag("NoAdapterCache", "no-adapter-cache", func(o config.RuntimeConfig) bool { return o.NoAdapterCache }),
+	intFlag("MaxParallel", "max-parallel", func(o config.RuntimeConfig) int { return o.MaxParallel }),
+	mapFlag("Vars", "var", func(o config.RuntimeConfig) map[string]string { return o.Vars }),
 }
 
 // BuildDetachedArgs constructs argv for a detached `wave run` subprocess from
@@ -180,8 +207,9 @@ type PIDRecorder interface {
 // Behaviour mirrored from Detach:
 //   - argv[0] is the wave binary resolved via os.Executable() (with argv[0]
 //     fallback for tests and unusual environments).
-//   - Setsid is set so the child becomes its own session leader and survives
-//     the parent process exit.
+//   - procutil.SetDetached makes the child its own session leader (a
+//     console-less process group on Windows) so it survives the parent
+//     process exit.
 //   - cfg.WorkDir, cfg.LogsDir, and cfg.ExtraEnv are honoured exactly the
 //     same way as in Detach.
 //   - cmd.Stdout / cmd.Stderr are redirected to <logsDir>/<runID>.log.
@@ -198,7 +226,7 @@ func SpawnDetached(args []string, runID string, recorder PIDRecorder, cfg Detach
 	}
 
 	cmd := exec.Command(waveBin, args...)
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	procutil.SetDetached(cmd)
 	cmd.Env = BuildDetachEnv(cfg.ExtraEnv...)
 	if cfg.WorkDir != "" {
 		cmd.Dir = cfg.WorkDir
@@ -234,20 +262,26 @@ func SpawnDetached(args []string, runID string, recorder PIDRecorder, cfg Detach
 	return nil
 }
 
+// queuePollInterval is how often a queued Detach retries for a worker slot.
+var queuePollInterval = 5 * time.Second
+
 // Detach spawns a fully-detached `wave run` subprocess that survives the
 // parent process exit. The subprocess writes to the shared state DB so
 // `wave status`, `wave logs`, and the webui dashboard can all observe it.
 //
 // When opts.RunID is empty Detach reserves a fresh run ID via the supplied
-// state store, respecting maxConcurrentWorkers via CreateRunWithLimit. When
-// opts.RunID is non-empty it is reused (the resume-in-place path used by
-// `--from-step`, see issue #1452).
+// state store, respecting maxConcurrentWorkers via CreateQueuedRun: while
+// every worker slot is busy it waits in the run queue, where opts.Priority
+// decides its place. When opts.RunID is non-empty it is reused (the
+// resume-in-place path used by `--from-step`, see issue #1452).
 //
 // detachStore is the narrow run-lifecycle surface Detach needs: pre-create
-// or reuse a run row, mark it running, and stamp the spawned PID.
+// (queueing for a slot) or reuse a run row, mark it running, and stamp the
+// spawned PID.
 type detachStore interface {
 	GetRun(runID string) (*state.RunRecord, error)
-	CreateRunWithLimit(pipelineName string, input string, maxConcurrent int) (string, error)
+	CreateQueuedRun(ticket, pipelineName, input, priority string, maxConcurrent int) (string, int, error)
+	LeaveRunQueue(ticket string) error
 	UpdateRunStatus(runID string, status string, currentStep string, tokens int) error
 	UpdateRunPID(runID string, pid int) error
 }
@@ -281,21 +315,28 @@ func Detach(opts config.RuntimeConfig, store detachStore, maxConcurrentWorkers i
 		}
 	}
 	if runID == "" {
-		notified := false
+		priority := opts.Priority
+		if priority == "" {
+			priority = state.RunPriorityInteractive
+		}
+		ticket := fmt.Sprintf("%d-%d", os.Getpid(), time.Now().UnixNano())
+		lastPosition := 0
 		for {
 			var createErr error
-			runID, createErr = store.CreateRunWithLimit(opts.Pipeline, opts.Input, maxConcurrentWorkers)
+			var position int
+			runID, position, createErr = store.CreateQueuedRun(ticket, opts.Pipeline, opts.Input, priority, maxConcurrentWorkers)
 			if createErr == nil {
 				break
 			}
 			if !errors.Is(createErr, state.ErrConcurrencyLimit) {
+				_ = store.LeaveRunQueue(ticket)
 				return "", fmt.Errorf("failed to create run record: %w", createErr)
 			}
-			if !notified {
-				fmt.Fprintf(os.Stderr, "  Queued: %d/%d workers busy, waiting for a slot...\n", maxConcurrentWorkers, maxConcurrentWorkers)
-				notified = true
+			if position != lastPosition {
+				fmt.Fprintf(os.Stderr, "  Queued (%s): %d workers busy, position %d in queue, waiting for a slot...\n", priority, maxConcurrentWorkers, position)
+				lastPosition = position
 			}
-			time.Sleep(5 * time.Second)
+			time.Sleep(queuePollInterval)
 		}
 	}
 	// Mark as running so wave status picks it up immediately.
@@ -28,6 +28,7 @@ func TestBuildDetachedArgsAllFlagsPresent(t *testing.T) {
 		PreserveWorkspace: true,
 		Steps:             "plan,implement",
 		Exclude:           "create-pr",
+		Artifacts:         map[string]string{"plan:plan": "/tmp/plan.md"},
 		Continuous:        true,
 		Source:            "github:label=bug",
 		MaxIterations:     7,
@@ -35,6 +36,9 @@ func TestBuildDetachedArgsAllFlagsPresent(t *testing.T) {
 		OnFailure:         "skip",
 		AutoApprove:       true,
 		NoRetro:           true,
+		NoAdapterCache:    true,
+		MaxParallel:       4,
+		Vars:              map[string]string{"service": "api"},
 	}
 	opts.Output.Verbose = true
 
@@ -89,6 +93,17 @@ func TestBuildDetachedArgsZeroValuesOmitted(t *testing.T) {
 	}
 }
 
+// TestBuildDetachedArgsVarsSorted verifies that each --var override is
+// forwarded as its own key=value pair in a stable order.
+func TestBuildDetachedArgsVarsSorted(t *testing.T) {
+	opts := config.RuntimeConfig{Pipeline: "p", Vars: map[string]string{"zone": "eu", "app": "a=b"}}
+	args := BuildDetachedArgs(opts, "rid")
+	want := []string{"--var", "app=a=b", "--var", "zone=eu"}
+	if got := args[len(args)-len(want):]; !reflect.DeepEqual(got, want) {
+		t.Errorf("vars argv = %v, want %v", got, want)
+	}
+}
+
 // TestBuildDetachedArgsManifestDefaultOmitted verifies that a manifest set to
 // the default "wave.yaml" is not forwarded — matches the legacy behaviour.
 func TestBuildDetachedArgsManifestDefaultOmitted(t *testing.T) {
@@ -60,6 +60,10 @@ type InProcessConfig struct {
 	// Skills configures the on-disk skill store. When zero-valued the runner
 	// falls back to the default ("skills" + ".agents/skills") layout.
 	Skills SkillStoreConfig
+
+	// WarmPool optionally supplies pre-started adapter processes for step
+	// retries.
+	WarmPool *adapter.WarmPool
 }
 
 // SkillStoreConfig overrides the directory layout used to discover skills.
@@ -119,6 +123,7 @@ func LaunchInProcess(cfg InProcessConfig) context.CancelFunc {
 		Runtime:          cfg.Options,
 		Runner:           runner,
 		SkillStore:       skill.NewDirectoryStore(primary, fallback),
+		WarmPool:         cfg.WarmPool,
 		Debug:            true,
 	})
 
@@ -58,6 +58,10 @@ type ExecutorBuildConfig struct {
 	Runner        adapter.AdapterRunner
 	MockOverride  bool
 
+	// WarmPool, when set, supplies pre-started adapter processes. Only
+	// long-lived callers (wave serve) keep one.
+	WarmPool *adapter.WarmPool
+
 	// CLI extras — webui leaves these nil.
 	RetroGenerator   *retro.Generator
 	RelayMonitor     *relay.RelayMonitor
@@ -129,25 +133,44 @@ func BuildExecutorOptions(cfg ExecutorBuildConfig) []pipeline.ExecutorOption {
 	if cfg.Runtime.AutoApprove {
 		opts = append(opts, pipeline.WithAutoApprove(true))
 	}
+	if len(cfg.Runtime.Vars) > 0 {
+		opts = append(opts, pipeline.WithVars(cfg.Runtime.Vars))
+	}
+	if cfg.Runtime.Deterministic {
+		opts = append(opts, pipeline.WithDeterministic(cfg.Runtime.Seed))
+	}
+	if cfg.Runtime.NoAdapterCache {
+		opts = append(opts, pipeline.WithNoAdapterCache())
+	}
+	if cfg.Runtime.MaxParallel > 0 {
+		opts = append(opts, pipeline.WithMaxParallelSteps(cfg.Runtime.MaxParallel))
+	}
 
 	// Step filter: prefer an explicitly-supplied filter (CLI parses + validates
 	// before calling), otherwise derive one from Runtime.Steps/Exclude.
 	if cfg.StepFilter != nil {
 		opts = append(opts, pipeline.WithStepFilter(cfg.StepFilter))
 	} else if cfg.Runtime.Steps != "" || cfg.Runtime.Exclude != "" {
-		opts = append(opts, pipeline.WithStepFilter(pipeline.ParseStepFilter(cfg.Runtime.Steps, cfg.Runtime.Exclude)))
+		filter := pipeline.ParseStepFilter(cfg.Runtime.Steps, cfg.Runtime.Exclude)
+		filter.Artifacts = cfg.Runtime.Artifacts
+		opts = append(opts, pipeline.WithStepFilter(filter))
 	}
 
 	// Adapter registry: always attached, seeded from the manifest's adapter
 	// binaries so manifests declaring forks (e.g. opencode-patched) resolve
 	// correctly. Before this fix, LaunchInProcess silently dropped these.
+	// The registry's limiter enforces each adapter's max_concurrent and
+	// requests_per_minute across every step and matrix worker of the run.
 	registry := adapter.NewAdapterRegistry(nil)
 	if cfg.Manifest != nil {
+		limits := make(map[string]adapter.AdapterLimits)
 		for name, a := range cfg.Manifest.Adapters {
 			if a.Binary != "" {
 				registry.SetBinary(name, a.Binary)
 			}
+			limits[name] = adapter.AdapterLimits{MaxConcurrent: a.MaxConcurrent, RequestsPerMinute: a.RequestsPerMinute}
 		}
+		registry.SetLimiter(adapter.NewLimiter(limits))
 	}
 	if cfg.MockOverride && cfg.Runner != nil && cfg.Manifest != nil {
 		// Route every adapter declared in the manifest through the mock runner.
@@ -158,6 +181,9 @@ func BuildExecutorOptions(cfg ExecutorBuildConfig) []pipeline.ExecutorOption {
 			registry.RegisterOverride(name, cfg.Runner)
 		}
 	}
+	if cfg.WarmPool != nil {
+		registry.SetWarmPool(cfg.WarmPool)
+	}
 	opts = append(opts, pipeline.WithRegistry(registry))
 
 	if cfg.SkillStore != nil {
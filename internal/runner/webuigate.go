@@ -67,10 +67,11 @@ func (g *WebUIGate) FindChoiceByKey(key string) *WebUIGateChoice {
 // to the pipeline executor. NewWebUIGateHandler converts this into a
 // pipeline.GateDecision before returning it from Prompt.
 type WebUIGateDecision struct {
-	Choice string
-	Label  string
-	Text   string
-	Target string
+	Choice   string
+	Label    string
+	Text     string
+	Target   string
+	Approver string
 }
 
 // GateChannelRegistrar is the narrow interface webui's GateRegistry exposes
@@ -117,6 +118,7 @@ func (h *WebUIGateHandler) Prompt(ctx context.Context, gate *pipeline.GateConfig
 			Label:     decision.Label,
 			Text:      decision.Text,
 			Target:    decision.Target,
+			Approver:  decision.Approver,
 			Timestamp: time.Now(),
 		}, nil
 	}
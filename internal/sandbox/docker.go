@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"runtime"
 	"strconv"
+	"strings"
 )
 
 // DockerSandbox executes adapter subprocesses inside Docker containers.
@@ -59,7 +61,11 @@ func (d *DockerSandbox) Wrap(ctx context.Context, cmd *exec.Cmd, cfg Config) (*e
 	if gid == 0 {
 		gid = os.Getgid()
 	}
-	args = append(args, "--user", strconv.Itoa(uid)+":"+strconv.Itoa(gid))
+	// Windows has no POSIX ids (Getuid returns -1); Docker Desktop maps
+	// bind-mount ownership itself.
+	if uid >= 0 && gid >= 0 {
+		args = append(args, "--user", strconv.Itoa(uid)+":"+strconv.Itoa(gid))
+	}
 
 	// Environment: HOME and standard vars
 	args = append(args, "-e", "HOME=/home/wave")
@@ -73,23 +79,28 @@ func (d *DockerSandbox) Wrap(ctx context.Context, cmd *exec.Cmd, cfg Config) (*e
 		}
 	}
 
+	// Explicit runtime/persona/step env entries
+	for _, kv := range cfg.Env {
+		args = append(args, "-e", kv)
+	}
+
 	// Workspace bind mount
 	if cfg.WorkspacePath != "" {
-		args = append(args, "-v", cfg.WorkspacePath+":"+cfg.WorkspacePath+":rw")
-		args = append(args, "-w", cfg.WorkspacePath)
+		args = append(args, "-v", bindMount(cfg.WorkspacePath, "rw"))
+		args = append(args, "-w", containerPath(cfg.WorkspacePath))
 	}
 
 	// Artifact directories
 	if cfg.ArtifactDir != "" {
-		args = append(args, "-v", cfg.ArtifactDir+":"+cfg.ArtifactDir+":ro")
+		args = append(args, "-v", bindMount(cfg.ArtifactDir, "ro"))
 	}
 	if cfg.OutputDir != "" {
-		args = append(args, "-v", cfg.OutputDir+":"+cfg.OutputDir+":rw")
+		args = append(args, "-v", bindMount(cfg.OutputDir, "rw"))
 	}
 
 	// Adapter binary bind mount
 	if cfg.AdapterBinary != "" {
-		args = append(args, "-v", cfg.AdapterBinary+":"+cfg.AdapterBinary+":ro")
+		args = append(args, "-v", bindMount(cfg.AdapterBinary, "ro"))
 	}
 
 	// Image
@@ -100,7 +111,7 @@ func (d *DockerSandbox) Wrap(ctx context.Context, cmd *exec.Cmd, cfg Config) (*e
 	args = append(args, image)
 
 	// Original command and arguments
-	args = append(args, cmd.Path)
+	args = append(args, containerPath(cmd.Path))
 	args = append(args, cmd.Args[1:]...)
 
 	dockerCmd := exec.CommandContext(ctx, d.dockerPath, args...)
@@ -113,6 +124,30 @@ func (d *DockerSandbox) Wrap(ctx context.Context, cmd *exec.Cmd, cfg Config) (*e
 	return dockerCmd, nil
 }
 
+// bindMount returns a -v value mounting hostPath at its container path.
+func bindMount(hostPath, mode string) string {
+	return hostPath + ":" + containerPath(hostPath) + ":" + mode
+}
+
+// containerPath maps a host path to the path it is mounted at inside the
+// Linux container: unchanged on Unix, drive-letter form on Windows.
+func containerPath(hostPath string) string {
+	if runtime.GOOS == "windows" {
+		return windowsContainerPath(hostPath)
+	}
+	return hostPath
+}
+
+// windowsContainerPath converts a Windows path to the form Docker Desktop
+// uses for drive mounts: C:\Users\me\repo becomes /c/Users/me/repo.
+func windowsContainerPath(p string) string {
+	p = strings.ReplaceAll(p, `\`, "/")
+	if len(p) >= 2 && p[1] == ':' {
+		p = "/" + strings.ToLower(p[:1]) + p[2:]
+	}
+	return p
+}
+
 func (d *DockerSandbox) Cleanup(_ context.Context) error {
 	// Container is --rm, so no cleanup needed for basic case.
 	// Future: clean up Docker networks for proxy sidecar.
@@ -142,6 +142,27 @@ func TestDockerSandbox_Wrap_EnvPassthrough(t *testing.T) {
 	}
 }
 
+func TestDockerSandbox_Wrap_ExplicitEnv(t *testing.T) {
+	d := newTestDockerSandbox()
+	cmd := exec.Command("/usr/bin/claude")
+
+	result, err := d.Wrap(context.Background(), cmd, Config{
+		HostUID: 1000,
+		HostGID: 1000,
+		Env:     []string{"LANG=C.UTF-8", "HTTPS_PROXY=http://proxy:3128"},
+	})
+	if err != nil {
+		t.Fatalf("Wrap returned error: %v", err)
+	}
+
+	args := strings.Join(result.Args, " ")
+	for _, want := range []string{"-e LANG=C.UTF-8", "-e HTTPS_PROXY=http://proxy:3128"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("expected %q in docker args, got %s", want, args)
+		}
+	}
+}
+
 func TestDockerSandbox_Wrap_StandardEnv(t *testing.T) {
 	d := newTestDockerSandbox()
 	cmd := exec.Command("/usr/bin/claude")
@@ -404,3 +425,17 @@ func TestDockerSandbox_Wrap_NoMountsWhenEmpty(t *testing.T) {
 		}
 	}
 }
+
+func TestWindowsContainerPath(t *testing.T) {
+	cases := map[string]string{
+		`C:\Users\me\repo`:     "/c/Users/me/repo",
+		`D:\a\wave\.agents\ws`: "/d/a/wave/.agents/ws",
+		"/home/me/repo":        "/home/me/repo",
+		`relative\dir`:         "relative/dir",
+	}
+	for in, want := range cases {
+		if got := windowsContainerPath(in); got != want {
+			t.Errorf("windowsContainerPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
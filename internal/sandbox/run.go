@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"os/exec"
+
+	"github.com/recinq/wave/internal/procutil"
 )
 
 // RunShell executes a shell command line through the configured sandbox
@@ -12,7 +14,8 @@ import (
 // dashboard, future admin tooling) inherit the same isolation policy as
 // pipeline-driven execution.
 //
-// The command is invoked as `sh -c <cmdLine>`. The chosen backend (None,
+// The command is invoked as `sh -c <cmdLine>` (the platform shell from
+// procutil.ShellArgs outside Docker). The chosen backend (None,
 // Docker, or Bubblewrap) wraps the underlying exec.Cmd; the wrapper may add
 // a Docker run prefix, FS bind mounts, or a passthrough as appropriate. A
 // short audit log line is emitted regardless of backend so operators can
@@ -30,7 +33,14 @@ func RunShell(ctx context.Context, cmdLine string, cfg Config) ([]byte, error) {
 		return nil, fmt.Errorf("sandbox: %w", err)
 	}
 
-	cmd := exec.CommandContext(ctx, "sh", "-c", cmdLine)
+	// Docker runs the line inside a Linux container; other backends use the
+	// host's shell.
+	var cmd *exec.Cmd
+	if cfg.Backend == SandboxBackendDocker {
+		cmd = exec.CommandContext(ctx, "sh", "-c", cmdLine)
+	} else {
+		cmd = procutil.ShellCommand(ctx, cmdLine)
+	}
 	wrapped, err := sb.Wrap(ctx, cmd, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("sandbox: wrap: %w", err)
@@ -15,6 +15,7 @@ type Config struct {
 	DockerImage    string
 	AllowedDomains []string
 	EnvPassthrough []string
+	Env            []string // Explicit KEY=VALUE entries set inside the sandbox
 	WorkspacePath  string
 	ArtifactDir    string
 	OutputDir      string
@@ -0,0 +1,181 @@
+package state
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AdapterCacheEntry is a stored adapter result, replayed for an identical
+// prompt against an unchanged workspace until ExpiresAt.
+type AdapterCacheEntry struct {
+	Key           string
+	PipelineName  string
+	StepID        string
+	Persona       string
+	Model         string
+	Stdout        []byte
+	ResultContent string
+	Subtype       string
+	TokensIn      int
+	TokensOut     int
+	// Files are the output files replayed with the result, keyed by
+	// workspace-relative path.
+	Files     map[string][]byte
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// AdapterCacheFilter selects adapter cache entries. Empty fields match
+// every entry.
+type AdapterCacheFilter struct {
+	PipelineName string
+	StepID       string
+	// ExpiredBy, when set, selects only entries expired at that time.
+	ExpiredBy time.Time
+}
+
+// AdapterCacheStore is the domain-scoped persistence surface for the
+// adapter response cache.
+type AdapterCacheStore interface {
+	// GetAdapterCacheEntry returns the unexpired entry for key, or nil.
+	GetAdapterCacheEntry(key string, now time.Time) (*AdapterCacheEntry, error)
+	// PutAdapterCacheEntry stores entry, replacing any entry with its key,
+	// and drops expired entries.
+	PutAdapterCacheEntry(entry AdapterCacheEntry) error
+	// ListAdapterCacheEntries returns the entries matching filter, newest
+	// first, expired ones included.
+	ListAdapterCacheEntries(filter AdapterCacheFilter) ([]AdapterCacheEntry, error)
+	// DeleteAdapterCacheEntries removes the entries matching filter and
+	// returns how many it removed.
+	DeleteAdapterCacheEntries(filter AdapterCacheFilter) (int, error)
+}
+
+const adapterCacheColumns = `cache_key, pipeline_name, step_id, persona, model, stdout, result_content,
+		        subtype, tokens_in, tokens_out, files, created_at, expires_at`
+
+func (s *stateStore) GetAdapterCacheEntry(key string, now time.Time) (*AdapterCacheEntry, error) {
+	row := s.db.QueryRow(
+		`SELECT `+adapterCacheColumns+` FROM adapter_cache WHERE cache_key = ? AND expires_at > ?`,
+		key, now.Unix(),
+	)
+	e, err := scanAdapterCacheEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read adapter cache entry: %w", err)
+	}
+	return e, nil
+}
+
+func (s *stateStore) PutAdapterCacheEntry(entry AdapterCacheEntry) error {
+	if entry.Key == "" {
+		return errors.New("PutAdapterCacheEntry: key required")
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	files := ""
+	if len(entry.Files) > 0 {
+		data, err := json.Marshal(entry.Files)
+		if err != nil {
+			return fmt.Errorf("failed to encode adapter cache files: %w", err)
+		}
+		files = string(data)
+	}
+	if _, err := s.db.Exec(`DELETE FROM adapter_cache WHERE expires_at <= ?`, entry.CreatedAt.Unix()); err != nil {
+		return fmt.Errorf("failed to prune adapter cache: %w", err)
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO adapter_cache (`+adapterCacheColumns+`)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(cache_key) DO UPDATE SET
+		     pipeline_name = excluded.pipeline_name, step_id = excluded.step_id,
+		     persona = excluded.persona, model = excluded.model,
+		     stdout = excluded.stdout,
+		     result_content = excluded.result_content, subtype = excluded.subtype,
+		     tokens_in = excluded.tokens_in, tokens_out = excluded.tokens_out,
+		     files = excluded.files,
+		     created_at = excluded.created_at, expires_at = excluded.expires_at`,
+		entry.Key, entry.PipelineName, entry.StepID, entry.Persona, entry.Model,
+		entry.Stdout, entry.ResultContent, entry.Subtype, entry.TokensIn, entry.TokensOut,
+		files, entry.CreatedAt.Unix(), entry.ExpiresAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store adapter cache entry: %w", err)
+	}
+	return nil
+}
+
+func (s *stateStore) ListAdapterCacheEntries(filter AdapterCacheFilter) ([]AdapterCacheEntry, error) {
+	where, args := filter.where()
+	rows, err := s.db.Query(`SELECT `+adapterCacheColumns+` FROM adapter_cache`+where+` ORDER BY created_at DESC, cache_key`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list adapter cache entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []AdapterCacheEntry
+	for rows.Next() {
+		e, err := scanAdapterCacheEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan adapter cache entry: %w", err)
+		}
+		entries = append(entries, *e)
+	}
+	return entries, rows.Err()
+}
+
+func (s *stateStore) DeleteAdapterCacheEntries(filter AdapterCacheFilter) (int, error) {
+	where, args := filter.where()
+	res, err := s.db.Exec(`DELETE FROM adapter_cache`+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete adapter cache entries: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+// where returns the WHERE clause selecting the filter's entries.
+func (f AdapterCacheFilter) where() (string, []any) {
+	var conds []string
+	var args []any
+	if f.PipelineName != "" {
+		conds = append(conds, "pipeline_name = ?")
+		args = append(args, f.PipelineName)
+	}
+	if f.StepID != "" {
+		conds = append(conds, "step_id = ?")
+		args = append(args, f.StepID)
+	}
+	if !f.ExpiredBy.IsZero() {
+		conds = append(conds, "expires_at <= ?")
+		args = append(args, f.ExpiredBy.Unix())
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+func scanAdapterCacheEntry(row rowScanner) (*AdapterCacheEntry, error) {
+	var e AdapterCacheEntry
+	var files string
+	var createdAt, expiresAt int64
+	if err := row.Scan(&e.Key, &e.PipelineName, &e.StepID, &e.Persona, &e.Model, &e.Stdout, &e.ResultContent,
+		&e.Subtype, &e.TokensIn, &e.TokensOut, &files, &createdAt, &expiresAt); err != nil {
+		return nil, err
+	}
+	if files != "" {
+		if err := json.Unmarshal([]byte(files), &e.Files); err != nil {
+			return nil, fmt.Errorf("failed to decode adapter cache files: %w", err)
+		}
+	}
+	e.CreatedAt = time.Unix(createdAt, 0)
+	e.ExpiresAt = time.Unix(expiresAt, 0)
+	return &e, nil
+}
@@ -0,0 +1,90 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdapterCache_PutGetExpire(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	now := time.Now()
+	require.NoError(t, store.PutAdapterCacheEntry(AdapterCacheEntry{
+		Key:           "k1",
+		Stdout:        []byte(`{"type":"result"}`),
+		ResultContent: "findings",
+		TokensIn:      100,
+		TokensOut:     20,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(time.Hour),
+	}))
+
+	got, err := store.GetAdapterCacheEntry("k1", now)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "findings", got.ResultContent)
+	assert.Equal(t, `{"type":"result"}`, string(got.Stdout))
+	assert.Equal(t, 100, got.TokensIn)
+
+	got, err = store.GetAdapterCacheEntry("k1", now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, got, "expired entries are not returned")
+
+	got, err = store.GetAdapterCacheEntry("missing", now)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	// Re-putting a key replaces the entry.
+	require.NoError(t, store.PutAdapterCacheEntry(AdapterCacheEntry{
+		Key:           "k1",
+		ResultContent: "newer",
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(time.Hour),
+	}))
+	got, err = store.GetAdapterCacheEntry("k1", now)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "newer", got.ResultContent)
+}
+
+func TestAdapterCache_ListDelete(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	now := time.Now()
+	for _, e := range []AdapterCacheEntry{
+		{Key: "a", PipelineName: "audit", StepID: "scan", Files: map[string][]byte{"out/a.md": []byte("a")}, ExpiresAt: now.Add(time.Hour)},
+		{Key: "b", PipelineName: "audit", StepID: "summarize", ExpiresAt: now.Add(2 * time.Hour)},
+		{Key: "c", PipelineName: "review", StepID: "scan", ExpiresAt: now.Add(3 * time.Hour)},
+	} {
+		e.CreatedAt = now
+		require.NoError(t, store.PutAdapterCacheEntry(e))
+	}
+
+	all, err := store.ListAdapterCacheEntries(AdapterCacheFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "a", all[0].Key)
+	assert.Equal(t, "a", string(all[0].Files["out/a.md"]))
+
+	scans, err := store.ListAdapterCacheEntries(AdapterCacheFilter{StepID: "scan"})
+	require.NoError(t, err)
+	assert.Len(t, scans, 2)
+
+	expired, err := store.ListAdapterCacheEntries(AdapterCacheFilter{ExpiredBy: now.Add(90 * time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, "a", expired[0].Key)
+
+	n, err := store.DeleteAdapterCacheEntries(AdapterCacheFilter{PipelineName: "audit"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	all, err = store.ListAdapterCacheEntries(AdapterCacheFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, "c", all[0].Key)
+}
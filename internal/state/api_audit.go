@@ -0,0 +1,101 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// APIAuditRecord is one control action taken through the dashboard API:
+// who started, cancelled or approved which run, and when.
+type APIAuditRecord struct {
+	ID           int64     `json:"id"`
+	Actor        string    `json:"actor"`          // Caller identity, e.g. "token:ci" or an OIDC email
+	Role         string    `json:"role,omitempty"` // Caller role at the time of the action
+	Action       string    `json:"action"`         // e.g. "run_started", "run_cancelled", "gate_resolved"
+	RunID        string    `json:"run_id,omitempty"`
+	PipelineName string    `json:"pipeline,omitempty"`
+	StepID       string    `json:"step_id,omitempty"`
+	Detail       string    `json:"detail,omitempty"`
+	RemoteAddr   string    `json:"remote_addr,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// APIAuditQueryOptions filters ListAPIAudit. Zero values match everything;
+// Limit 0 returns all matching records.
+type APIAuditQueryOptions struct {
+	Actor  string
+	Action string
+	RunID  string
+	Since  time.Time
+	Limit  int
+	Offset int
+}
+
+// RecordAPIAudit appends a control action to the audit trail and sets its ID.
+func (s *stateStore) RecordAPIAudit(record *APIAuditRecord) error {
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = s.now()
+	}
+	res, err := s.db.Exec(
+		`INSERT INTO api_audit (actor, role, action, run_id, pipeline_name, step_id, detail, remote_addr, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		record.Actor, record.Role, record.Action, record.RunID, record.PipelineName,
+		record.StepID, record.Detail, record.RemoteAddr, record.CreatedAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record API audit entry: %w", err)
+	}
+	record.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// ListAPIAudit returns audit records matching opts, newest first.
+func (s *stateStore) ListAPIAudit(opts APIAuditQueryOptions) ([]APIAuditRecord, error) {
+	query := `SELECT id, actor, role, action, run_id, pipeline_name, step_id, detail, remote_addr, created_at FROM api_audit`
+	var conds []string
+	var args []any
+	if opts.Actor != "" {
+		conds = append(conds, "actor = ?")
+		args = append(args, opts.Actor)
+	}
+	if opts.Action != "" {
+		conds = append(conds, "action = ?")
+		args = append(args, opts.Action)
+	}
+	if opts.RunID != "" {
+		conds = append(conds, "run_id = ?")
+		args = append(args, opts.RunID)
+	}
+	if !opts.Since.IsZero() {
+		conds = append(conds, "created_at >= ?")
+		args = append(args, opts.Since.Unix())
+	}
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if opts.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, opts.Limit, opts.Offset)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var records []APIAuditRecord
+	for rows.Next() {
+		var r APIAuditRecord
+		var createdAt int64
+		if err := rows.Scan(&r.ID, &r.Actor, &r.Role, &r.Action, &r.RunID, &r.PipelineName,
+			&r.StepID, &r.Detail, &r.RemoteAddr, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan API audit entry: %w", err)
+		}
+		r.CreatedAt = time.Unix(createdAt, 0)
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
@@ -0,0 +1,50 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIAudit_RecordList(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	base := time.Unix(1_700_000_000, 0)
+	records := []*APIAuditRecord{
+		{Actor: "token:ci", Role: "operator", Action: "run_started", RunID: "run-1", PipelineName: "impl-issue", CreatedAt: base},
+		{Actor: "alice@example.com", Role: "approver", Action: "gate_resolved", RunID: "run-1", StepID: "review", Detail: "approve", CreatedAt: base.Add(time.Minute)},
+		{Actor: "token:ci", Role: "operator", Action: "run_cancelled", RunID: "run-2", RemoteAddr: "10.0.0.5:4242", CreatedAt: base.Add(2 * time.Minute)},
+	}
+	for _, r := range records {
+		require.NoError(t, store.RecordAPIAudit(r))
+		assert.NotZero(t, r.ID)
+	}
+
+	all, err := store.ListAPIAudit(APIAuditQueryOptions{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "run_cancelled", all[0].Action, "newest first")
+	assert.Equal(t, "10.0.0.5:4242", all[0].RemoteAddr)
+
+	byActor, err := store.ListAPIAudit(APIAuditQueryOptions{Actor: "token:ci"})
+	require.NoError(t, err)
+	assert.Len(t, byActor, 2)
+
+	byRun, err := store.ListAPIAudit(APIAuditQueryOptions{RunID: "run-1", Action: "gate_resolved"})
+	require.NoError(t, err)
+	require.Len(t, byRun, 1)
+	assert.Equal(t, "review", byRun[0].StepID)
+	assert.Equal(t, "approve", byRun[0].Detail)
+
+	since, err := store.ListAPIAudit(APIAuditQueryOptions{Since: base.Add(30 * time.Second)})
+	require.NoError(t, err)
+	assert.Len(t, since, 2)
+
+	limited, err := store.ListAPIAudit(APIAuditQueryOptions{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+	assert.Equal(t, "gate_resolved", limited[0].Action)
+}
@@ -0,0 +1,97 @@
+package state
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Approval statuses stored in gate_approval.status.
+const (
+	ApprovalWaiting  = "waiting"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+)
+
+// ErrApprovalNotWaiting is returned by DecideApproval when the step has no
+// approval waiting for a decision.
+var ErrApprovalNotWaiting = errors.New("no approval is waiting for this step")
+
+// ApprovalRecord is an approval gate of a run, waiting for or holding a
+// decision made with `wave approve`.
+type ApprovalRecord struct {
+	RunID       string
+	StepID      string
+	Prompt      string
+	Status      string
+	DecidedBy   string
+	Reason      string
+	RequestedAt time.Time
+	Deadline    time.Time
+	DecidedAt   *time.Time
+}
+
+// RequestApproval records that stepID of runID waits for approval until
+// deadline. Asking again, e.g. when a rework loop revisits the gate,
+// discards the earlier decision.
+func (s *stateStore) RequestApproval(runID, stepID, prompt string, deadline time.Time) error {
+	query := `INSERT INTO gate_approval (run_id, step_id, prompt, status, requested_at, deadline)
+	          VALUES (?, ?, ?, ?, ?, ?)
+	          ON CONFLICT(run_id, step_id) DO UPDATE SET
+	              prompt = excluded.prompt,
+	              status = excluded.status,
+	              decided_by = '',
+	              reason = '',
+	              requested_at = excluded.requested_at,
+	              deadline = excluded.deadline,
+	              decided_at = NULL`
+	if _, err := s.db.Exec(query, runID, stepID, prompt, ApprovalWaiting, s.now().Unix(), deadline.Unix()); err != nil {
+		return fmt.Errorf("failed to request approval: %w", err)
+	}
+	return nil
+}
+
+// DecideApproval approves or rejects the approval stepID of runID is
+// waiting for. It returns ErrApprovalNotWaiting when there is none, so
+// two deciders cannot both win.
+func (s *stateStore) DecideApproval(runID, stepID string, approved bool, decidedBy, reason string) error {
+	status := ApprovalRejected
+	if approved {
+		status = ApprovalApproved
+	}
+	res, err := s.db.Exec(`UPDATE gate_approval SET status = ?, decided_by = ?, reason = ?, decided_at = ?
+	                       WHERE run_id = ? AND step_id = ? AND status = ?`,
+		status, decidedBy, reason, s.now().Unix(), runID, stepID, ApprovalWaiting)
+	if err != nil {
+		return fmt.Errorf("failed to record approval decision: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrApprovalNotWaiting
+	}
+	return nil
+}
+
+// GetApproval returns the approval of stepID in runID, or nil if the step
+// never asked for one.
+func (s *stateStore) GetApproval(runID, stepID string) (*ApprovalRecord, error) {
+	var rec ApprovalRecord
+	var requestedAt, deadline int64
+	var decidedAt sql.NullInt64
+	err := s.db.QueryRow(`SELECT run_id, step_id, prompt, status, decided_by, reason, requested_at, deadline, decided_at
+	                      FROM gate_approval WHERE run_id = ? AND step_id = ?`, runID, stepID).
+		Scan(&rec.RunID, &rec.StepID, &rec.Prompt, &rec.Status, &rec.DecidedBy, &rec.Reason, &requestedAt, &deadline, &decidedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get approval: %w", err)
+	}
+	rec.RequestedAt = time.Unix(requestedAt, 0)
+	rec.Deadline = time.Unix(deadline, 0)
+	if decidedAt.Valid {
+		t := time.Unix(decidedAt.Int64, 0)
+		rec.DecidedAt = &t
+	}
+	return &rec, nil
+}
@@ -0,0 +1,51 @@
+package state
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestApproval_RequestAndDecide(t *testing.T) {
+	store := newReconcileStore(t)
+	runID := newRunningRun(t, store)
+
+	if rec, err := store.GetApproval(runID, "review"); err != nil || rec != nil {
+		t.Fatalf("GetApproval before request = %+v, %v; want nil, nil", rec, err)
+	}
+	if err := store.DecideApproval(runID, "review", true, "alice", ""); !errors.Is(err, ErrApprovalNotWaiting) {
+		t.Fatalf("DecideApproval without a request: got %v, want ErrApprovalNotWaiting", err)
+	}
+
+	deadline := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := store.RequestApproval(runID, "review", "Ship the plan?", deadline); err != nil {
+		t.Fatalf("RequestApproval: %v", err)
+	}
+	rec, err := store.GetApproval(runID, "review")
+	if err != nil || rec == nil {
+		t.Fatalf("GetApproval: %+v, %v", rec, err)
+	}
+	if rec.Status != ApprovalWaiting || rec.Prompt != "Ship the plan?" || !rec.Deadline.Equal(deadline) || rec.DecidedAt != nil {
+		t.Errorf("waiting approval = %+v", rec)
+	}
+
+	if err := store.DecideApproval(runID, "review", false, "alice", "plan skips the migration"); err != nil {
+		t.Fatalf("DecideApproval: %v", err)
+	}
+	if err := store.DecideApproval(runID, "review", true, "bob", ""); !errors.Is(err, ErrApprovalNotWaiting) {
+		t.Errorf("second decision: got %v, want ErrApprovalNotWaiting", err)
+	}
+	rec, _ = store.GetApproval(runID, "review")
+	if rec.Status != ApprovalRejected || rec.DecidedBy != "alice" || rec.Reason != "plan skips the migration" || rec.DecidedAt == nil {
+		t.Errorf("decided approval = %+v", rec)
+	}
+
+	// Revisiting the gate asks again from scratch.
+	if err := store.RequestApproval(runID, "review", "Ship the revised plan?", deadline); err != nil {
+		t.Fatalf("RequestApproval again: %v", err)
+	}
+	rec, _ = store.GetApproval(runID, "review")
+	if rec.Status != ApprovalWaiting || rec.DecidedBy != "" || rec.Reason != "" || rec.DecidedAt != nil {
+		t.Errorf("re-requested approval = %+v", rec)
+	}
+}
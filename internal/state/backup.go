@@ -0,0 +1,213 @@
+package state
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// preMigrationBackupKeep is how many automatic pre-migration backups are
+// retained per database; older ones are pruned after each new backup.
+const preMigrationBackupKeep = 3
+
+// BackupResult describes a completed database backup.
+type BackupResult struct {
+	Path string
+	Size int64
+	// SourceProblems lists what PRAGMA integrity_check reported for the live
+	// database. Empty means the source checked out as "ok".
+	SourceProblems []string
+}
+
+// Backup writes a consistent snapshot of the database at dbPath to dest
+// while other processes may still be using it, then runs
+// PRAGMA integrity_check on both the source and the copy. The backup fails
+// when the copy does not check out; problems in the source are reported in
+// the result so callers can warn without losing the snapshot.
+func Backup(dbPath, dest string) (*BackupResult, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("database not found: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec("PRAGMA busy_timeout=5000"); err != nil {
+		return nil, fmt.Errorf("failed to set busy timeout: %w", err)
+	}
+
+	problems, err := CheckIntegrity(db)
+	if err != nil {
+		return nil, fmt.Errorf("integrity check of %s failed: %w", dbPath, err)
+	}
+
+	if err := backupDB(db, dest); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat backup: %w", err)
+	}
+
+	return &BackupResult{Path: dest, Size: info.Size(), SourceProblems: problems}, nil
+}
+
+// CheckIntegrity runs PRAGMA integrity_check and returns the problems it
+// reports. A healthy database yields an empty slice.
+func CheckIntegrity(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("PRAGMA integrity_check")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	return problems, rows.Err()
+}
+
+// backupDB snapshots db into dest with VACUUM INTO, which reads inside a
+// single transaction and so is safe against concurrent writers, then
+// verifies the copy. A copy that fails verification is removed.
+func backupDB(db *sql.DB, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("backup destination %s already exists", dest)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	if _, err := db.Exec("VACUUM INTO ?", dest); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+
+	if err := verifyBackup(dest); err != nil {
+		os.Remove(dest)
+		return err
+	}
+	return nil
+}
+
+func verifyBackup(path string) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer db.Close()
+
+	problems, err := CheckIntegrity(db)
+	if err != nil {
+		return fmt.Errorf("integrity check of backup failed: %w", err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("backup failed integrity check: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// backupBeforeMigrate snapshots an existing on-disk database into a
+// backups/ directory beside it before pending migrations run, keeping the
+// newest preMigrationBackupKeep snapshots. In-memory databases are skipped.
+// It returns the backup path, or "" when nothing was written.
+func backupBeforeMigrate(db *sql.DB, currentVersion int) (string, error) {
+	dbPath, err := mainDatabaseFile(db)
+	if err != nil || dbPath == "" {
+		return "", err
+	}
+
+	base := strings.TrimSuffix(filepath.Base(dbPath), filepath.Ext(dbPath))
+	dir := filepath.Join(filepath.Dir(dbPath), "backups")
+	prefix := base + "-pre-migrate-"
+	dest := filepath.Join(dir, fmt.Sprintf("%s%s-v%d.db", prefix, time.Now().UTC().Format("20060102T150405.000Z"), currentVersion))
+
+	if err := backupDB(db, dest); err != nil {
+		return "", err
+	}
+	pruneBackups(dir, prefix, preMigrationBackupKeep)
+	return dest, nil
+}
+
+// backupIfPending takes a pre-migration backup when MigrateUp would apply at
+// least one migration up to targetVersion (0 = all).
+func backupIfPending(manager *MigrationManager, db *sql.DB, allMigrations []Migration, currentVersion, targetVersion int) error {
+	pending, err := manager.PendingMigrations(allMigrations)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 || (targetVersion > 0 && pending[0].Version > targetVersion) {
+		return nil
+	}
+
+	path, err := backupBeforeMigrate(db, currentVersion)
+	if err != nil {
+		return fmt.Errorf("pre-migration backup failed (set WAVE_SKIP_MIGRATION_BACKUP=true to migrate without one): %w", err)
+	}
+	if path != "" {
+		fmt.Fprintf(os.Stderr, "Backed up database to %s before migrating\n", path)
+	}
+	return nil
+}
+
+// mainDatabaseFile returns the file backing db's main schema, or "" for
+// in-memory and temporary databases.
+func mainDatabaseFile(db *sql.DB) (string, error) {
+	rows, err := db.Query("PRAGMA database_list")
+	if err != nil {
+		return "", fmt.Errorf("failed to list databases: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var seq int
+		var name, file string
+		if err := rows.Scan(&seq, &name, &file); err != nil {
+			return "", err
+		}
+		if name == "main" {
+			return file, nil
+		}
+	}
+	return "", rows.Err()
+}
+
+// pruneBackups removes all but the newest keep files in dir starting with
+// prefix. The timestamp in the name makes lexical order chronological.
+// Removal errors are ignored; a leftover backup is harmless.
+func pruneBackups(dir, prefix string, keep int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), prefix) {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) <= keep {
+		return
+	}
+
+	sort.Strings(names)
+	for _, name := range names[:len(names)-keep] {
+		os.Remove(filepath.Join(dir, name))
+	}
+}
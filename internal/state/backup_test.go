@@ -0,0 +1,86 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackup(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "state.db")
+
+	store, err := NewStateStore(dbPath)
+	require.NoError(t, err)
+	defer store.Close()
+	runID, err := store.CreateRun("impl-issue", "fix the bug")
+	require.NoError(t, err)
+
+	// The store stays open: the backup must work against a live database.
+	dest := filepath.Join(dir, "backups", "copy.db")
+	result, err := Backup(dbPath, dest)
+	require.NoError(t, err)
+	assert.Equal(t, dest, result.Path)
+	assert.Positive(t, result.Size)
+	assert.Empty(t, result.SourceProblems)
+
+	copied, err := NewReadOnlyStateStore(dest)
+	require.NoError(t, err)
+	defer copied.Close()
+	run, err := copied.GetRun(runID)
+	require.NoError(t, err)
+	assert.Equal(t, "fix the bug", run.Input)
+
+	_, err = Backup(dbPath, dest)
+	assert.ErrorContains(t, err, "already exists")
+
+	_, err = Backup(filepath.Join(dir, "missing.db"), filepath.Join(dir, "other.db"))
+	assert.ErrorContains(t, err, "database not found")
+}
+
+func TestBackupIfPending(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "state.db")
+	store, err := NewStateStore(dbPath)
+	require.NoError(t, err)
+	defer store.Close()
+
+	db := UnderlyingDB(store)
+	manager := NewMigrationManager(db)
+	current, err := manager.GetCurrentVersion()
+	require.NoError(t, err)
+	backups := func() []os.DirEntry {
+		entries, _ := os.ReadDir(filepath.Join(dir, "backups"))
+		return entries
+	}
+
+	// Fully migrated: nothing to back up.
+	require.NoError(t, backupIfPending(manager, db, GetAllMigrations(), current, 0))
+	assert.Empty(t, backups())
+
+	next := Migration{Version: current + 1, Description: "future", Up: "SELECT 1"}
+	withNext := append(GetAllMigrations(), next)
+
+	// Pending migration beyond the target version: still nothing.
+	require.NoError(t, backupIfPending(manager, db, withNext, current, current))
+	assert.Empty(t, backups())
+
+	for i := 0; i < preMigrationBackupKeep+2; i++ {
+		require.NoError(t, backupIfPending(manager, db, withNext, current, 0))
+	}
+	entries := backups()
+	require.Len(t, entries, preMigrationBackupKeep)
+	assert.Contains(t, entries[0].Name(), "state-pre-migrate-")
+}
+
+func TestBackupBeforeMigrate_InMemory(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	path, err := backupBeforeMigrate(UnderlyingDB(store), 1)
+	require.NoError(t, err)
+	assert.Empty(t, path)
+}
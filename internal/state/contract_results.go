@@ -0,0 +1,128 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ContractResultRecord is the structured outcome of one contract
+// validation, so failures can be inspected field by field instead of
+// through an event message.
+type ContractResultRecord struct {
+	ID           int64     `json:"id"`
+	RunID        string    `json:"run_id"`
+	StepID       string    `json:"step_id"`
+	ContractType string    `json:"contract_type"`
+	Name         string    `json:"name,omitempty"` // Schema file name or contract type
+	Passed       bool      `json:"passed"`
+	Message      string    `json:"message,omitempty"`
+	Violations   []string  `json:"violations,omitempty"`
+	Output       string    `json:"output,omitempty"` // Command stdout (test_suite)
+	DurationMs   int64     `json:"duration_ms"`
+	ArtifactPath string    `json:"artifact_path,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// PipelineName is the run's pipeline; only ListContractResults sets it.
+	PipelineName string `json:"pipeline_name,omitempty"`
+}
+
+// SaveContractResult records a contract validation result and sets its ID.
+func (s *stateStore) SaveContractResult(record *ContractResultRecord) error {
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = s.now()
+	}
+	violations, err := json.Marshal(record.Violations)
+	if err != nil {
+		return fmt.Errorf("failed to encode contract violations: %w", err)
+	}
+	passed := 0
+	if record.Passed {
+		passed = 1
+	}
+	res, err := s.db.Exec(
+		`INSERT INTO contract_result (run_id, step_id, contract_type, name, passed, message, violations, output, duration_ms, artifact_path, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		record.RunID, record.StepID, record.ContractType, record.Name, passed, record.Message,
+		string(violations), record.Output, record.DurationMs, record.ArtifactPath, record.CreatedAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save contract result: %w", err)
+	}
+	record.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// GetContractResults returns the contract results of a run in the order
+// they were recorded. A non-empty stepID limits them to that step.
+func (s *stateStore) GetContractResults(runID string, stepID string) ([]ContractResultRecord, error) {
+	query := `SELECT id, run_id, step_id, contract_type, name, passed, message, violations, output, duration_ms, artifact_path, created_at
+	          FROM contract_result WHERE run_id = ?`
+	args := []any{runID}
+	if stepID != "" {
+		query += ` AND step_id = ?`
+		args = append(args, stepID)
+	}
+	query += ` ORDER BY id`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contract results: %w", err)
+	}
+	defer rows.Close()
+
+	var records []ContractResultRecord
+	for rows.Next() {
+		var r ContractResultRecord
+		var passed int
+		var violations string
+		var createdAt int64
+		if err := rows.Scan(&r.ID, &r.RunID, &r.StepID, &r.ContractType, &r.Name, &passed, &r.Message,
+			&violations, &r.Output, &r.DurationMs, &r.ArtifactPath, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan contract result: %w", err)
+		}
+		r.Passed = passed != 0
+		_ = json.Unmarshal([]byte(violations), &r.Violations)
+		r.CreatedAt = time.Unix(createdAt, 0)
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// ListContractResults returns the contract results recorded at or after
+// since across all runs, oldest first, joined with each run's pipeline
+// name. A zero since returns the full history.
+func (s *stateStore) ListContractResults(since time.Time) ([]ContractResultRecord, error) {
+	var sinceUnix int64
+	if !since.IsZero() {
+		sinceUnix = since.Unix()
+	}
+	rows, err := s.db.Query(
+		`SELECT cr.id, cr.run_id, cr.step_id, cr.contract_type, cr.name, cr.passed, cr.message, cr.duration_ms,
+		        cr.created_at, COALESCE(pr.pipeline_name, '')
+		 FROM contract_result cr
+		 LEFT JOIN pipeline_run pr ON pr.run_id = cr.run_id
+		 WHERE cr.created_at >= ?
+		 ORDER BY cr.id`,
+		sinceUnix,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contract results: %w", err)
+	}
+	defer rows.Close()
+
+	var records []ContractResultRecord
+	for rows.Next() {
+		var r ContractResultRecord
+		var passed int
+		var createdAt int64
+		if err := rows.Scan(&r.ID, &r.RunID, &r.StepID, &r.ContractType, &r.Name, &passed, &r.Message,
+			&r.DurationMs, &createdAt, &r.PipelineName); err != nil {
+			return nil, fmt.Errorf("failed to scan contract result: %w", err)
+		}
+		r.Passed = passed != 0
+		r.CreatedAt = time.Unix(createdAt, 0)
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
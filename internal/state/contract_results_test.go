@@ -0,0 +1,76 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractResults_SaveGet(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	pass := &ContractResultRecord{RunID: "run-1", StepID: "plan", ContractType: "json_schema", Name: "plan.schema.json", Passed: true, DurationMs: 5}
+	fail := &ContractResultRecord{
+		RunID:        "run-1",
+		StepID:       "test",
+		ContractType: "test_suite",
+		Message:      "test suite failed",
+		Violations:   []string{"exit code 1"},
+		Output:       "--- FAIL: TestX\nFAIL\n",
+		DurationMs:   1200,
+		ArtifactPath: "/ws/.agents/artifacts/contract_test_1.json",
+	}
+	require.NoError(t, store.SaveContractResult(pass))
+	require.NoError(t, store.SaveContractResult(fail))
+	assert.NotZero(t, fail.ID)
+
+	all, err := store.GetContractResults("run-1", "")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.True(t, all[0].Passed)
+	assert.Empty(t, all[0].Violations)
+
+	steps, err := store.GetContractResults("run-1", "test")
+	require.NoError(t, err)
+	require.Len(t, steps, 1)
+	got := steps[0]
+	assert.False(t, got.Passed)
+	assert.Equal(t, []string{"exit code 1"}, got.Violations)
+	assert.Equal(t, "--- FAIL: TestX\nFAIL\n", got.Output)
+	assert.Equal(t, int64(1200), got.DurationMs)
+	assert.Equal(t, fail.ArtifactPath, got.ArtifactPath)
+
+	none, err := store.GetContractResults("run-2", "")
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestContractResults_List(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	runID, err := store.CreateRun("impl-issue", "")
+	require.NoError(t, err)
+
+	old := &ContractResultRecord{RunID: runID, StepID: "plan", ContractType: "json_schema", CreatedAt: time.Now().Add(-48 * time.Hour)}
+	recent := &ContractResultRecord{RunID: runID, StepID: "plan", ContractType: "json_schema", Passed: true}
+	orphan := &ContractResultRecord{RunID: "gone", StepID: "test", ContractType: "test_suite"}
+	for _, r := range []*ContractResultRecord{old, recent, orphan} {
+		require.NoError(t, store.SaveContractResult(r))
+	}
+
+	all, err := store.ListContractResults(time.Time{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "impl-issue", all[0].PipelineName)
+	assert.Empty(t, all[2].PipelineName)
+
+	since, err := store.ListContractResults(time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, since, 2)
+	assert.True(t, since[0].Passed)
+	assert.Equal(t, "gone", since[1].RunID)
+}
@@ -0,0 +1,114 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StepCostRecord is the estimated USD cost of one step execution, priced
+// from the adapter's input/output token counts by the run's pricing table.
+type StepCostRecord struct {
+	RunID        string
+	StepID       string
+	PipelineName string
+	Adapter      string
+	Model        string
+	TokensIn     int
+	TokensOut    int
+	CostUSD      float64
+	RecordedAt   time.Time
+}
+
+// RecordStepCost records the cost of a step execution. A retried step
+// records one row per attempt, so run totals include the retries' spend.
+func (s *stateStore) RecordStepCost(rec *StepCostRecord) error {
+	recordedAt := rec.RecordedAt
+	if recordedAt.IsZero() {
+		recordedAt = time.Now()
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO step_cost (run_id, step_id, pipeline_name, adapter, model, tokens_in, tokens_out, cost_usd, recorded_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rec.RunID, rec.StepID, rec.PipelineName, rec.Adapter, rec.Model,
+		rec.TokensIn, rec.TokensOut, rec.CostUSD, recordedAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record step cost: %w", err)
+	}
+	return nil
+}
+
+// GetStepCosts returns the step costs recorded for a run, oldest first.
+func (s *stateStore) GetStepCosts(runID string) ([]StepCostRecord, error) {
+	rows, err := s.db.Query(
+		`SELECT run_id, step_id, pipeline_name, adapter, model, tokens_in, tokens_out, cost_usd, recorded_at
+		 FROM step_cost WHERE run_id = ? ORDER BY recorded_at, id`,
+		runID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query step costs: %w", err)
+	}
+	defer rows.Close()
+
+	records := []StepCostRecord{}
+	for rows.Next() {
+		var r StepCostRecord
+		var recordedAt int64
+		if err := rows.Scan(&r.RunID, &r.StepID, &r.PipelineName, &r.Adapter, &r.Model,
+			&r.TokensIn, &r.TokensOut, &r.CostUSD, &recordedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan step cost: %w", err)
+		}
+		r.RecordedAt = time.Unix(recordedAt, 0)
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating step costs: %w", err)
+	}
+	return records, nil
+}
+
+// GetRunCosts returns the total recorded cost of each of the given runs.
+// Runs without recorded costs are absent from the map.
+func (s *stateStore) GetRunCosts(runIDs []string) (map[string]float64, error) {
+	costs := make(map[string]float64)
+	if len(runIDs) == 0 {
+		return costs, nil
+	}
+	args := make([]any, len(runIDs))
+	for i, id := range runIDs {
+		args[i] = id
+	}
+	query := `SELECT run_id, SUM(cost_usd) FROM step_cost WHERE run_id IN (` +
+		strings.TrimSuffix(strings.Repeat("?,", len(runIDs)), ",") + `) GROUP BY run_id`
+	return s.queryCostTotals(query, args...)
+}
+
+// GetPipelineCosts returns the total recorded cost of every pipeline that
+// has run, across all its runs.
+func (s *stateStore) GetPipelineCosts() (map[string]float64, error) {
+	return s.queryCostTotals(`SELECT pipeline_name, SUM(cost_usd) FROM step_cost GROUP BY pipeline_name`)
+}
+
+// queryCostTotals collects (key, total) rows into a map.
+func (s *stateStore) queryCostTotals(query string, args ...any) (map[string]float64, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query costs: %w", err)
+	}
+	defer rows.Close()
+
+	costs := make(map[string]float64)
+	for rows.Next() {
+		var key string
+		var total float64
+		if err := rows.Scan(&key, &total); err != nil {
+			return nil, fmt.Errorf("failed to scan cost total: %w", err)
+		}
+		costs[key] = total
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cost totals: %w", err)
+	}
+	return costs, nil
+}
@@ -0,0 +1,52 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepCosts(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	first, err := store.CreateRun("impl-issue", "first")
+	require.NoError(t, err)
+	second, err := store.CreateRun("impl-issue", "second")
+	require.NoError(t, err)
+	other, err := store.CreateRun("ops-pr-review", "other")
+	require.NoError(t, err)
+
+	at := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+	for _, rec := range []StepCostRecord{
+		{RunID: first, StepID: "plan", PipelineName: "impl-issue", Adapter: "claude", Model: "claude-sonnet-4-6", TokensIn: 1000, TokensOut: 200, CostUSD: 0.25, RecordedAt: at},
+		{RunID: first, StepID: "implement", PipelineName: "impl-issue", Adapter: "codex", Model: "gpt-5", TokensIn: 4000, TokensOut: 900, CostUSD: 1.5, RecordedAt: at.Add(time.Minute)},
+		{RunID: second, StepID: "plan", PipelineName: "impl-issue", Model: "claude-sonnet-4-6", CostUSD: 0.5, RecordedAt: at},
+		{RunID: other, StepID: "review", PipelineName: "ops-pr-review", Model: "claude-haiku-4-5", CostUSD: 0.125, RecordedAt: at},
+	} {
+		require.NoError(t, store.RecordStepCost(&rec))
+	}
+
+	steps, err := store.GetStepCosts(first)
+	require.NoError(t, err)
+	require.Len(t, steps, 2)
+	assert.Equal(t, "plan", steps[0].StepID)
+	assert.Equal(t, "codex", steps[1].Adapter)
+	assert.Equal(t, 4000, steps[1].TokensIn)
+	assert.Equal(t, 900, steps[1].TokensOut)
+	assert.True(t, steps[1].RecordedAt.Equal(at.Add(time.Minute)))
+
+	runs, err := store.GetRunCosts([]string{first, second, "no-costs"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{first: 1.75, second: 0.5}, runs)
+
+	pipelines, err := store.GetPipelineCosts()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"impl-issue": 2.25, "ops-pr-review": 0.125}, pipelines)
+
+	empty, err := store.GetRunCosts(nil)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
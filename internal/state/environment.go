@@ -0,0 +1,70 @@
+package state
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RunEnvironment is a snapshot of the environment a run started in: the
+// Wave build, the project's git state, the host and the adapter CLI
+// versions. It is the context needed to debug a failure days later, when
+// the checkout, the binaries or the machine may have moved on.
+type RunEnvironment struct {
+	CapturedAt   time.Time `json:"captured_at"`
+	WaveVersion  string    `json:"wave_version,omitempty"`
+	WaveRevision string    `json:"wave_revision,omitempty"`
+	GitSHA       string    `json:"git_sha,omitempty"`
+	GitBranch    string    `json:"git_branch,omitempty"`
+	GitDirty     bool      `json:"git_dirty,omitempty"`
+	OS           string    `json:"os"`
+	Arch         string    `json:"arch"`
+	// AdapterVersions maps the adapters the pipeline uses to the first
+	// line of their `--version` output. Empty when the binary was missing.
+	AdapterVersions map[string]string `json:"adapter_versions,omitempty"`
+	// ManifestDigest is the SHA-256 of wave.yaml as loaded for the run.
+	ManifestDigest string `json:"manifest_digest,omitempty"`
+}
+
+// SetRunEnvironment records the environment snapshot of a run, replacing
+// any earlier one (a resumed run records the environment it resumed in).
+func (s *stateStore) SetRunEnvironment(runID string, env *RunEnvironment) error {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("failed to encode run environment: %w", err)
+	}
+	result, err := s.db.Exec(`UPDATE pipeline_run SET environment = ? WHERE run_id = ?`, string(data), runID)
+	if err != nil {
+		return fmt.Errorf("failed to set run environment: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("run not found: %s", runID)
+	}
+	return nil
+}
+
+// GetRunEnvironment returns the environment snapshot of a run, or nil for
+// runs started before snapshots were recorded.
+func (s *stateStore) GetRunEnvironment(runID string) (*RunEnvironment, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT environment FROM pipeline_run WHERE run_id = ?`, runID).Scan(&data)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("run not found: %s", runID)
+		}
+		return nil, fmt.Errorf("failed to get run environment: %w", err)
+	}
+	if data == "" {
+		return nil, nil
+	}
+	var env RunEnvironment
+	if err := json.Unmarshal([]byte(data), &env); err != nil {
+		return nil, fmt.Errorf("failed to decode run environment: %w", err)
+	}
+	return &env, nil
+}
@@ -0,0 +1,41 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunEnvironment(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	runID, err := store.CreateRun("impl-issue", "input")
+	require.NoError(t, err)
+
+	env, err := store.GetRunEnvironment(runID)
+	require.NoError(t, err)
+	assert.Nil(t, env, "a run without a snapshot has no environment")
+
+	want := &RunEnvironment{
+		CapturedAt:      time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC),
+		WaveVersion:     "v0.40.0",
+		GitSHA:          "0123456789abcdef0123456789abcdef01234567",
+		GitBranch:       "main",
+		GitDirty:        true,
+		OS:              "linux",
+		Arch:            "amd64",
+		AdapterVersions: map[string]string{"claude": "2.1.0 (Claude Code)", "codex": ""},
+		ManifestDigest:  "bbb",
+	}
+	require.NoError(t, store.SetRunEnvironment(runID, want))
+	env, err = store.GetRunEnvironment(runID)
+	require.NoError(t, err)
+	assert.Equal(t, want, env)
+
+	assert.ErrorContains(t, store.SetRunEnvironment("missing", want), "run not found")
+	_, err = store.GetRunEnvironment("missing")
+	assert.ErrorContains(t, err, "run not found")
+}
@@ -1,5 +1,7 @@
 package state
 
+import "time"
+
 // EventStore is the domain-scoped persistence surface for event log entries,
 // audit-log queries, and artifact registration/metadata. Consumers that only
 // emit or query events/artifacts should depend on this interface rather than
@@ -13,9 +15,22 @@ type EventStore interface {
 	// Audit log (cross-run event queries)
 	GetAuditEvents(states []string, limit, offset int) ([]LogRecord, error)
 
+	// API audit trail (who started, cancelled and approved runs)
+	RecordAPIAudit(record *APIAuditRecord) error
+	ListAPIAudit(opts APIAuditQueryOptions) ([]APIAuditRecord, error)
+
 	// Artifact tracking
 	RegisterArtifact(runID string, stepID string, name string, path string, artifactType string, sizeBytes int64) error
 	GetArtifacts(runID string, stepID string) ([]ArtifactRecord, error)
 	SaveArtifactMetadata(artifactID int64, runID string, stepID string, previewText string, mimeType string, encoding string, metadataJSON string) error
 	GetArtifactMetadata(artifactID int64) (*ArtifactMetadataRecord, error)
+
+	// Contract validation results
+	SaveContractResult(record *ContractResultRecord) error
+	GetContractResults(runID string, stepID string) ([]ContractResultRecord, error)
+	ListContractResults(since time.Time) ([]ContractResultRecord, error)
+
+	// Per-step filesystem change summaries
+	SaveStepChanges(record *StepChangeRecord) error
+	GetStepChanges(runID string, stepID string) ([]StepChangeRecord, error)
 }
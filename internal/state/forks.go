@@ -1,9 +1,7 @@
 package state
 
 import (
-	"crypto/rand"
 	"database/sql"
-	"encoding/hex"
 	"fmt"
 	"time"
 )
@@ -14,16 +12,17 @@ func (s *stateStore) SaveCheckpoint(record *CheckpointRecord) error {
 	now := time.Now().Unix()
 
 	// Upsert: replace existing checkpoint for same run+step
-	query := `INSERT INTO checkpoint (run_id, step_id, step_index, workspace_path, workspace_commit_sha, artifact_snapshot, created_at)
-	          VALUES (?, ?, ?, ?, ?, ?, ?)
+	query := `INSERT INTO checkpoint (run_id, step_id, step_index, workspace_path, workspace_commit_sha, artifact_snapshot, step_results, created_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	          ON CONFLICT(run_id, step_id) DO UPDATE SET
 	              step_index = excluded.step_index,
 	              workspace_path = excluded.workspace_path,
 	              workspace_commit_sha = excluded.workspace_commit_sha,
 	              artifact_snapshot = excluded.artifact_snapshot,
+	              step_results = excluded.step_results,
 	              created_at = excluded.created_at`
 
-	_, err := s.db.Exec(query, record.RunID, record.StepID, record.StepIndex, record.WorkspacePath, record.WorkspaceCommitSHA, record.ArtifactSnapshot, now)
+	_, err := s.db.Exec(query, record.RunID, record.StepID, record.StepIndex, record.WorkspacePath, record.WorkspaceCommitSHA, record.ArtifactSnapshot, record.StepResults, now)
 	if err != nil {
 		return fmt.Errorf("failed to save checkpoint: %w", err)
 	}
@@ -31,7 +30,7 @@ func (s *stateStore) SaveCheckpoint(record *CheckpointRecord) error {
 }
 
 func (s *stateStore) GetCheckpoint(runID, stepID string) (*CheckpointRecord, error) {
-	query := `SELECT id, run_id, step_id, step_index, workspace_path, workspace_commit_sha, artifact_snapshot, created_at
+	query := `SELECT id, run_id, step_id, step_index, workspace_path, workspace_commit_sha, artifact_snapshot, step_results, created_at
 	          FROM checkpoint
 	          WHERE run_id = ? AND step_id = ?`
 
@@ -41,7 +40,7 @@ func (s *stateStore) GetCheckpoint(runID, stepID string) (*CheckpointRecord, err
 
 	err := s.db.QueryRow(query, runID, stepID).Scan(
 		&record.ID, &record.RunID, &record.StepID, &record.StepIndex,
-		&record.WorkspacePath, &sha, &record.ArtifactSnapshot, &createdAt,
+		&record.WorkspacePath, &sha, &record.ArtifactSnapshot, &record.StepResults, &createdAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -58,7 +57,7 @@ func (s *stateStore) GetCheckpoint(runID, stepID string) (*CheckpointRecord, err
 }
 
 func (s *stateStore) GetCheckpoints(runID string) ([]CheckpointRecord, error) {
-	query := `SELECT id, run_id, step_id, step_index, workspace_path, workspace_commit_sha, artifact_snapshot, created_at
+	query := `SELECT id, run_id, step_id, step_index, workspace_path, workspace_commit_sha, artifact_snapshot, step_results, created_at
 	          FROM checkpoint
 	          WHERE run_id = ?
 	          ORDER BY step_index ASC`
@@ -77,7 +76,7 @@ func (s *stateStore) GetCheckpoints(runID string) ([]CheckpointRecord, error) {
 
 		err := rows.Scan(
 			&record.ID, &record.RunID, &record.StepID, &record.StepIndex,
-			&record.WorkspacePath, &sha, &record.ArtifactSnapshot, &createdAt,
+			&record.WorkspacePath, &sha, &record.ArtifactSnapshot, &record.StepResults, &createdAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan checkpoint: %w", err)
@@ -107,12 +106,7 @@ func (s *stateStore) DeleteCheckpointsAfterStep(runID string, stepIndex int) err
 
 func (s *stateStore) CreateRunWithFork(pipelineName, input, forkedFromRunID string) (string, error) {
 	now := time.Now()
-	randBytes := make([]byte, 2)
-	if _, err := rand.Read(randBytes); err != nil {
-		randBytes = []byte{byte(now.Nanosecond() >> 8), byte(now.Nanosecond())}
-	}
-	suffix := hex.EncodeToString(randBytes)
-	runID := fmt.Sprintf("%s-%s-%s", pipelineName, now.Format("20060102-150405"), suffix)
+	runID := s.newRunID(pipelineName, now)
 
 	query := `INSERT INTO pipeline_run (run_id, pipeline_name, status, input, started_at, forked_from_run_id)
 	          VALUES (?, ?, 'pending', ?, ?, ?)`
@@ -222,7 +216,7 @@ func (s *stateStore) GetChildRuns(parentRunID string) ([]RunRecord, error) {
 	query := `SELECT run_id, pipeline_name, status, input, current_step, total_tokens,
 	                 started_at, completed_at, cancelled_at, error_message, tags_json, branch_name, pid,
 	                 parent_run_id, parent_step_id, forked_from_run_id, last_heartbeat,
-	                 iterate_index, iterate_total, iterate_mode, run_kind, sub_pipeline_ref
+	                 iterate_index, iterate_total, iterate_mode, run_kind, sub_pipeline_ref, pinned
 	          FROM pipeline_run
 	          WHERE parent_run_id = ?
 	          ORDER BY COALESCE(iterate_index, 1<<30) ASC, started_at ASC`
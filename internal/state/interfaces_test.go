@@ -5,9 +5,10 @@ package state
 // removing a method on any interface without updating the concrete type will
 // fail the build at this site.
 var (
-	_ RunStore     = (*stateStore)(nil)
-	_ EventStore   = (*stateStore)(nil)
-	_ WebhookStore = (*stateStore)(nil)
-	_ ChatStore    = (*stateStore)(nil)
-	_ StateStore   = (*stateStore)(nil)
+	_ RunStore       = (*stateStore)(nil)
+	_ EventStore     = (*stateStore)(nil)
+	_ WebhookStore   = (*stateStore)(nil)
+	_ ChatStore      = (*stateStore)(nil)
+	_ KnowledgeStore = (*stateStore)(nil)
+	_ StateStore     = (*stateStore)(nil)
 )
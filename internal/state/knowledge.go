@@ -0,0 +1,94 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Knowledge entry sources.
+const (
+	KnowledgeSourceManual = "manual"
+	KnowledgeSourceTriage = "triage"
+)
+
+// KnowledgeEntry is one failure signature and its known remediation in the
+// per-project error knowledge base. Pattern is a normalized error signature
+// matched as a substring; Category, PipelineName and StepID optionally
+// narrow the match and are ignored when empty.
+type KnowledgeEntry struct {
+	ID           int64
+	Pattern      string
+	Category     string
+	PipelineName string
+	StepID       string
+	Remediation  string
+	Source       string
+	CreatedAt    time.Time
+}
+
+// KnowledgeStore is the domain-scoped persistence surface for the error
+// knowledge base consulted when a failed step is retried.
+type KnowledgeStore interface {
+	AddKnowledgeEntry(entry KnowledgeEntry) (int64, error)
+	ListKnowledgeEntries() ([]KnowledgeEntry, error)
+	DeleteKnowledgeEntry(id int64) error
+}
+
+func (s *stateStore) AddKnowledgeEntry(entry KnowledgeEntry) (int64, error) {
+	if entry.Pattern == "" || entry.Remediation == "" {
+		return 0, errors.New("AddKnowledgeEntry: pattern and remediation required")
+	}
+	if entry.Source == "" {
+		entry.Source = KnowledgeSourceManual
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	res, err := s.db.Exec(
+		`INSERT INTO failure_kb (pattern, category, pipeline_name, step_id, remediation, source, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		entry.Pattern, entry.Category, entry.PipelineName, entry.StepID,
+		entry.Remediation, entry.Source, entry.CreatedAt.Unix(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add knowledge entry: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// ListKnowledgeEntries returns every knowledge base entry, oldest first.
+func (s *stateStore) ListKnowledgeEntries() ([]KnowledgeEntry, error) {
+	rows, err := s.db.Query(
+		`SELECT id, pattern, category, pipeline_name, step_id, remediation, source, created_at
+		 FROM failure_kb ORDER BY id ASC`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query knowledge entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []KnowledgeEntry
+	for rows.Next() {
+		var e KnowledgeEntry
+		var createdAt int64
+		if err := rows.Scan(&e.ID, &e.Pattern, &e.Category, &e.PipelineName, &e.StepID, &e.Remediation, &e.Source, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan knowledge entry: %w", err)
+		}
+		e.CreatedAt = time.Unix(createdAt, 0)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (s *stateStore) DeleteKnowledgeEntry(id int64) error {
+	res, err := s.db.Exec(`DELETE FROM failure_kb WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete knowledge entry: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return fmt.Errorf("DeleteKnowledgeEntry: id %d not found", id)
+	}
+	return nil
+}
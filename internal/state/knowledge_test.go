@@ -0,0 +1,58 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKnowledgeEntries_AddListDelete(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	id1, err := store.AddKnowledgeEntry(KnowledgeEntry{
+		Pattern:     "module lookup disabled",
+		Remediation: "Run go mod download first",
+	})
+	require.NoError(t, err)
+	id2, err := store.AddKnowledgeEntry(KnowledgeEntry{
+		Pattern:      "429",
+		Category:     "rate_limit",
+		PipelineName: "impl-issue",
+		StepID:       "implement",
+		Remediation:  "Lower concurrency",
+		Source:       KnowledgeSourceTriage,
+	})
+	require.NoError(t, err)
+
+	entries, err := store.ListKnowledgeEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, id1, entries[0].ID)
+	assert.Equal(t, KnowledgeSourceManual, entries[0].Source)
+	assert.False(t, entries[0].CreatedAt.IsZero())
+	assert.Equal(t, id2, entries[1].ID)
+	assert.Equal(t, "rate_limit", entries[1].Category)
+	assert.Equal(t, "impl-issue", entries[1].PipelineName)
+	assert.Equal(t, "implement", entries[1].StepID)
+	assert.Equal(t, KnowledgeSourceTriage, entries[1].Source)
+
+	require.NoError(t, store.DeleteKnowledgeEntry(id1))
+	entries, err = store.ListKnowledgeEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, id2, entries[0].ID)
+
+	assert.Error(t, store.DeleteKnowledgeEntry(id1))
+}
+
+func TestAddKnowledgeEntry_RequiresPatternAndRemediation(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	_, err := store.AddKnowledgeEntry(KnowledgeEntry{Pattern: "boom"})
+	assert.Error(t, err)
+	_, err = store.AddKnowledgeEntry(KnowledgeEntry{Remediation: "fix it"})
+	assert.Error(t, err)
+}
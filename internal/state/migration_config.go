@@ -17,13 +17,17 @@ type MigrationConfig struct {
 	// SkipMigrationValidation skips checksum validation for development
 	SkipMigrationValidation bool
 
+	// SkipMigrationBackup disables the database snapshot taken before
+	// pending migrations are applied to an existing database
+	SkipMigrationBackup bool
+
 	// MaxMigrationVersion limits which migrations can be applied (0 = all)
 	MaxMigrationVersion int
 }
 
 // LoadMigrationConfigFromEnv loads migration configuration from environment variables.
 //
-// The four WAVE_MIGRATION_* env vars are read through internal/config so the
+// The WAVE_*MIGRATION* env vars are read through internal/config so the
 // process-wide env-reader contract stays in one place. Boolean fields accept
 // "true", "1", or "yes" (case-insensitive); any other non-empty value is
 // treated as false. WAVE_MAX_MIGRATION_VERSION must parse as a positive
@@ -48,6 +52,9 @@ func LoadMigrationConfigFromEnv() *MigrationConfig {
 	if envCfg.SkipValidation != nil {
 		cfg.SkipMigrationValidation = *envCfg.SkipValidation
 	}
+	if envCfg.SkipBackup != nil {
+		cfg.SkipMigrationBackup = *envCfg.SkipBackup
+	}
 	if envCfg.MaxVersion != nil && *envCfg.MaxVersion > 0 {
 		cfg.MaxMigrationVersion = *envCfg.MaxVersion
 	}
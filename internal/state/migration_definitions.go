@@ -664,5 +664,269 @@ CREATE INDEX IF NOT EXISTS idx_schedule_due ON schedule(next_fire_at) WHERE acti
 			Down: `DROP INDEX IF EXISTS idx_schedule_due;
 DROP TABLE IF EXISTS schedule;`,
 		},
+		{
+			Version:     34,
+			Description: "Add failure_category column to step_state for failure triage reporting",
+			Up: `ALTER TABLE step_state ADD COLUMN failure_category TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_step_failure_category ON step_state(failure_category) WHERE failure_category != '';`,
+			Down: `DROP INDEX IF EXISTS idx_step_failure_category;
+ALTER TABLE step_state DROP COLUMN failure_category;`,
+		},
+		{
+			Version:     35,
+			Description: "Add failure_kb table for the per-project error knowledge base",
+			Up: `CREATE TABLE IF NOT EXISTS failure_kb (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pattern TEXT NOT NULL,
+    category TEXT NOT NULL DEFAULT '',
+    pipeline_name TEXT NOT NULL DEFAULT '',
+    step_id TEXT NOT NULL DEFAULT '',
+    remediation TEXT NOT NULL,
+    source TEXT NOT NULL CHECK (source IN ('manual','triage')),
+    created_at INTEGER NOT NULL
+);`,
+			Down: `DROP TABLE IF EXISTS failure_kb;`,
+		},
+		{
+			Version:     36,
+			Description: "Add run_provenance table recording definition digests per run",
+			Up: `CREATE TABLE IF NOT EXISTS run_provenance (
+    run_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    name TEXT NOT NULL,
+    path TEXT NOT NULL DEFAULT '',
+    digest TEXT NOT NULL,
+    PRIMARY KEY (run_id, kind, name),
+    FOREIGN KEY (run_id) REFERENCES pipeline_run(run_id) ON DELETE CASCADE
+);`,
+			Down: `DROP TABLE IF EXISTS run_provenance;`,
+		},
+		{
+			Version:     37,
+			Description: "Add idempotency_key column to pipeline_run for duplicate-run detection",
+			Up: `ALTER TABLE pipeline_run ADD COLUMN idempotency_key TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_run_idempotency_key ON pipeline_run(idempotency_key) WHERE idempotency_key != '';`,
+			Down: `DROP INDEX IF EXISTS idx_run_idempotency_key;
+ALTER TABLE pipeline_run DROP COLUMN idempotency_key;`,
+		},
+		{
+			Version:     38,
+			Description: "Add format and template columns to webhooks for chat notification payloads",
+			Up: `ALTER TABLE webhooks ADD COLUMN format TEXT NOT NULL DEFAULT 'json';
+ALTER TABLE webhooks ADD COLUMN template TEXT NOT NULL DEFAULT '';`,
+			Down: `ALTER TABLE webhooks DROP COLUMN template;
+ALTER TABLE webhooks DROP COLUMN format;`,
+		},
+		{
+			Version:     39,
+			Description: "Add adapter_cache table for replaying identical adapter prompts",
+			Up: `CREATE TABLE IF NOT EXISTS adapter_cache (
+    cache_key TEXT PRIMARY KEY,
+    stdout BLOB,
+    result_content TEXT NOT NULL DEFAULT '',
+    subtype TEXT NOT NULL DEFAULT '',
+    tokens_in INTEGER NOT NULL DEFAULT 0,
+    tokens_out INTEGER NOT NULL DEFAULT 0,
+    created_at INTEGER NOT NULL,
+    expires_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_adapter_cache_expires ON adapter_cache(expires_at);`,
+			Down: `DROP INDEX IF EXISTS idx_adapter_cache_expires;
+DROP TABLE IF EXISTS adapter_cache;`,
+		},
+		{
+			Version:     40,
+			Description: "Add contract_result table for structured contract validation results",
+			Up: `CREATE TABLE IF NOT EXISTS contract_result (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    run_id TEXT NOT NULL,
+    step_id TEXT NOT NULL,
+    contract_type TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    passed INTEGER NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    violations TEXT NOT NULL DEFAULT '[]',
+    output TEXT NOT NULL DEFAULT '',
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    artifact_path TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_contract_result_run ON contract_result(run_id, step_id);`,
+			Down: `DROP INDEX IF EXISTS idx_contract_result_run;
+DROP TABLE IF EXISTS contract_result;`,
+		},
+		{
+			Version:     41,
+			Description: "Add api_audit table recording who triggered, cancelled and approved runs",
+			Up: `CREATE TABLE IF NOT EXISTS api_audit (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT '',
+    action TEXT NOT NULL,
+    run_id TEXT NOT NULL DEFAULT '',
+    pipeline_name TEXT NOT NULL DEFAULT '',
+    step_id TEXT NOT NULL DEFAULT '',
+    detail TEXT NOT NULL DEFAULT '',
+    remote_addr TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_api_audit_created ON api_audit(created_at);
+CREATE INDEX IF NOT EXISTS idx_api_audit_actor ON api_audit(actor);
+CREATE INDEX IF NOT EXISTS idx_api_audit_run ON api_audit(run_id);`,
+			Down: `DROP INDEX IF EXISTS idx_api_audit_run;
+DROP INDEX IF EXISTS idx_api_audit_actor;
+DROP INDEX IF EXISTS idx_api_audit_created;
+DROP TABLE IF EXISTS api_audit;`,
+		},
+		{
+			Version:     42,
+			Description: "Add step_change table recording the files each step added, modified and deleted",
+			Up: `CREATE TABLE IF NOT EXISTS step_change (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    run_id TEXT NOT NULL,
+    step_id TEXT NOT NULL,
+    files_added INTEGER NOT NULL DEFAULT 0,
+    files_modified INTEGER NOT NULL DEFAULT 0,
+    files_deleted INTEGER NOT NULL DEFAULT 0,
+    insertions INTEGER NOT NULL DEFAULT 0,
+    deletions INTEGER NOT NULL DEFAULT 0,
+    files TEXT NOT NULL DEFAULT '[]',
+    artifact_path TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_step_change_run ON step_change(run_id, step_id);`,
+			Down: `DROP INDEX IF EXISTS idx_step_change_run;
+DROP TABLE IF EXISTS step_change;`,
+		},
+		{
+			Version:     43,
+			Description: "Add resource_lock and resource_lock_waiter tables for cross-run resource locks",
+			Up: `CREATE TABLE IF NOT EXISTS resource_lock (
+    name TEXT PRIMARY KEY,
+    run_id TEXT NOT NULL,
+    step_id TEXT NOT NULL DEFAULT '',
+    acquired_at INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS resource_lock_waiter (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    run_id TEXT NOT NULL,
+    step_id TEXT NOT NULL DEFAULT '',
+    queued_at INTEGER NOT NULL,
+    UNIQUE(name, run_id, step_id)
+);
+CREATE INDEX IF NOT EXISTS idx_resource_lock_waiter_name ON resource_lock_waiter(name, id);`,
+			Down: `DROP INDEX IF EXISTS idx_resource_lock_waiter_name;
+DROP TABLE IF EXISTS resource_lock_waiter;
+DROP TABLE IF EXISTS resource_lock;`,
+		},
+		{
+			Version:     44,
+			Description: "Add run_queue table ordering detached runs waiting for a worker slot by priority",
+			Up: `CREATE TABLE IF NOT EXISTS run_queue (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    ticket TEXT NOT NULL UNIQUE,
+    pipeline_name TEXT NOT NULL,
+    priority INTEGER NOT NULL,
+    queued_at INTEGER NOT NULL,
+    polled_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_run_queue_order ON run_queue(priority DESC, id);`,
+			Down: `DROP INDEX IF EXISTS idx_run_queue_order;
+DROP TABLE IF EXISTS run_queue;`,
+		},
+		{
+			Version:     45,
+			Description: "Add environment column to pipeline_run recording the environment snapshot taken at run start",
+			Up:          `ALTER TABLE pipeline_run ADD COLUMN environment TEXT NOT NULL DEFAULT '';`,
+			Down:        `ALTER TABLE pipeline_run DROP COLUMN environment;`,
+		},
+		{
+			Version:     46,
+			Description: "Add step_cost table recording the estimated USD cost of each step",
+			Up: `CREATE TABLE IF NOT EXISTS step_cost (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    run_id TEXT NOT NULL,
+    step_id TEXT NOT NULL,
+    pipeline_name TEXT NOT NULL,
+    adapter TEXT NOT NULL DEFAULT '',
+    model TEXT NOT NULL DEFAULT '',
+    tokens_in INTEGER NOT NULL DEFAULT 0,
+    tokens_out INTEGER NOT NULL DEFAULT 0,
+    cost_usd REAL NOT NULL DEFAULT 0,
+    recorded_at INTEGER NOT NULL,
+    FOREIGN KEY (run_id) REFERENCES pipeline_run(run_id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_step_cost_run ON step_cost(run_id);
+CREATE INDEX IF NOT EXISTS idx_step_cost_pipeline ON step_cost(pipeline_name);`,
+			Down: `DROP INDEX IF EXISTS idx_step_cost_pipeline;
+DROP INDEX IF EXISTS idx_step_cost_run;
+DROP TABLE IF EXISTS step_cost;`,
+		},
+		{
+			Version:     47,
+			Description: "Add budget_exceeded column to pipeline_run recording the token budget a run crossed",
+			Up:          `ALTER TABLE pipeline_run ADD COLUMN budget_exceeded TEXT NOT NULL DEFAULT '';`,
+			Down:        `ALTER TABLE pipeline_run DROP COLUMN budget_exceeded;`,
+		},
+		{
+			Version:     48,
+			Description: "Add gate_approval table persisting approval gates waiting for wave approve",
+			Up: `CREATE TABLE IF NOT EXISTS gate_approval (
+    run_id TEXT NOT NULL,
+    step_id TEXT NOT NULL,
+    prompt TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    decided_by TEXT NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    requested_at INTEGER NOT NULL,
+    deadline INTEGER NOT NULL,
+    decided_at INTEGER,
+    PRIMARY KEY (run_id, step_id),
+    FOREIGN KEY (run_id) REFERENCES pipeline_run(run_id) ON DELETE CASCADE
+);`,
+			Down: `DROP TABLE IF EXISTS gate_approval;`,
+		},
+		{
+			Version:     49,
+			Description: "Add step identity and output files to adapter_cache for wave cache and artifact replay",
+			Up: `ALTER TABLE adapter_cache ADD COLUMN pipeline_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE adapter_cache ADD COLUMN step_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE adapter_cache ADD COLUMN persona TEXT NOT NULL DEFAULT '';
+ALTER TABLE adapter_cache ADD COLUMN model TEXT NOT NULL DEFAULT '';
+ALTER TABLE adapter_cache ADD COLUMN files TEXT NOT NULL DEFAULT '';`,
+			Down: `ALTER TABLE adapter_cache DROP COLUMN files;
+ALTER TABLE adapter_cache DROP COLUMN model;
+ALTER TABLE adapter_cache DROP COLUMN persona;
+ALTER TABLE adapter_cache DROP COLUMN step_id;
+ALTER TABLE adapter_cache DROP COLUMN pipeline_name;`,
+		},
+		{
+			Version:     50,
+			Description: "Add pinned column to pipeline_run exempting runs from cleanup",
+			Up:          `ALTER TABLE pipeline_run ADD COLUMN pinned INTEGER NOT NULL DEFAULT 0;`,
+			Down:        `ALTER TABLE pipeline_run DROP COLUMN pinned;`,
+		},
+		{
+			Version:     51,
+			Description: "Add notification_dedup table suppressing repeated routed notifications across runs",
+			Up: `CREATE TABLE IF NOT EXISTS notification_dedup (
+    dedup_key TEXT PRIMARY KEY,
+    sent_at INTEGER NOT NULL
+);`,
+			Down: `DROP TABLE IF EXISTS notification_dedup;`,
+		},
+		{
+			Version:     52,
+			Description: "Add tokens_estimated to performance_metric flagging token counts the adapter did not report",
+			Up:          `ALTER TABLE performance_metric ADD COLUMN tokens_estimated INTEGER NOT NULL DEFAULT 0;`,
+			Down:        `ALTER TABLE performance_metric DROP COLUMN tokens_estimated;`,
+		},
+		{
+			Version:     53,
+			Description: "Add step_results to checkpoint so resume can rebuild a run from the state store",
+			Up:          `ALTER TABLE checkpoint ADD COLUMN step_results TEXT NOT NULL DEFAULT '';`,
+			Down:        `ALTER TABLE checkpoint DROP COLUMN step_results;`,
+		},
 	}
 }
@@ -72,9 +72,21 @@ func (r *MigrationRunner) Close() error {
 	return r.db.Close()
 }
 
-// MigrateUp applies all pending migrations up to the target version
+// MigrateUp applies all pending migrations up to the target version,
+// backing up the database first unless WAVE_SKIP_MIGRATION_BACKUP is set
 func (r *MigrationRunner) MigrateUp(targetVersion int) error {
 	allMigrations := GetAllMigrations()
+	if !LoadMigrationConfigFromEnv().SkipMigrationBackup {
+		currentVersion, err := r.manager.GetCurrentVersion()
+		if err != nil {
+			return err
+		}
+		if currentVersion > 0 {
+			if err := backupIfPending(r.manager, r.db, allMigrations, currentVersion, targetVersion); err != nil {
+				return err
+			}
+		}
+	}
 	return r.manager.MigrateUp(allMigrations, targetVersion)
 }
 
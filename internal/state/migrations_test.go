@@ -345,6 +345,7 @@ func TestMigrationConfig_LoadFromEnv(t *testing.T) {
 		"WAVE_MIGRATION_ENABLED":         os.Getenv("WAVE_MIGRATION_ENABLED"),
 		"WAVE_AUTO_MIGRATE":              os.Getenv("WAVE_AUTO_MIGRATE"),
 		"WAVE_SKIP_MIGRATION_VALIDATION": os.Getenv("WAVE_SKIP_MIGRATION_VALIDATION"),
+		"WAVE_SKIP_MIGRATION_BACKUP":     os.Getenv("WAVE_SKIP_MIGRATION_BACKUP"),
 		"WAVE_MAX_MIGRATION_VERSION":     os.Getenv("WAVE_MAX_MIGRATION_VERSION"),
 	}
 	defer func() {
@@ -378,12 +379,14 @@ func TestMigrationConfig_LoadFromEnv(t *testing.T) {
 				"WAVE_MIGRATION_ENABLED":         "false",
 				"WAVE_AUTO_MIGRATE":              "false",
 				"WAVE_SKIP_MIGRATION_VALIDATION": "true",
+				"WAVE_SKIP_MIGRATION_BACKUP":     "yes",
 				"WAVE_MAX_MIGRATION_VERSION":     "3",
 			},
 			expected: &MigrationConfig{
 				EnableMigrations:        false,
 				AutoMigrate:             false,
 				SkipMigrationValidation: true,
+				SkipMigrationBackup:     true,
 				MaxMigrationVersion:     3,
 			},
 		},
@@ -465,7 +468,7 @@ func TestInitializeWithMigrations_ExistingDatabase(t *testing.T) {
 	manager := NewMigrationManager(db)
 	applied, err := manager.GetAppliedMigrations()
 	assert.NoError(t, err)
-	assert.Len(t, applied, 33) // All 33 defined migrations
+	assert.Len(t, applied, 53) // All 53 defined migrations
 }
 
 func TestInitializeWithMigrations_NoAutoMigrate(t *testing.T) {
@@ -496,11 +499,11 @@ func TestInitializeWithMigrations_NoAutoMigrate(t *testing.T) {
 func TestMigrationDefinitions(t *testing.T) {
 	migrations := GetAllMigrations()
 
-	// Should have 33 migrations based on our definition
-	assert.Len(t, migrations, 33)
+	// Should have 53 migrations based on our definition
+	assert.Len(t, migrations, 53)
 
 	// Check version sequence
-	expectedVersions := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33}
+	expectedVersions := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44, 45, 46, 47, 48, 49, 50, 51, 52, 53}
 	for i, migration := range migrations {
 		assert.Equal(t, expectedVersions[i], migration.Version)
 		assert.NotEmpty(t, migration.Description)
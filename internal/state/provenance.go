@@ -0,0 +1,76 @@
+package state
+
+import (
+	"fmt"
+)
+
+// Definition kinds recorded in run_provenance.
+const (
+	DefinitionManifest = "manifest"
+	DefinitionPipeline = "pipeline"
+	DefinitionPersona  = "persona"
+)
+
+// DefinitionDigest is the content hash of one definition file a run was
+// started from: wave.yaml, the pipeline YAML, or a persona's system prompt.
+// Comparing digests across runs shows whether a behaviour change coincides
+// with a definition change.
+type DefinitionDigest struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Path   string `json:"path,omitempty"`
+	Digest string `json:"digest"`
+}
+
+// SaveRunProvenance records the definition digests for a run. Saving again
+// for the same run replaces digests with the same kind and name.
+func (s *stateStore) SaveRunProvenance(runID string, defs []DefinitionDigest) error {
+	if len(defs) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, d := range defs {
+		if _, err := tx.Exec(
+			`INSERT OR REPLACE INTO run_provenance (run_id, kind, name, path, digest) VALUES (?, ?, ?, ?, ?)`,
+			runID, d.Kind, d.Name, d.Path, d.Digest,
+		); err != nil {
+			return fmt.Errorf("failed to save run provenance: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit run provenance: %w", err)
+	}
+	return nil
+}
+
+// GetRunProvenance returns the definition digests recorded for a run,
+// ordered by kind then name. Runs started before provenance was recorded
+// return an empty slice.
+func (s *stateStore) GetRunProvenance(runID string) ([]DefinitionDigest, error) {
+	rows, err := s.db.Query(
+		`SELECT kind, name, path, digest FROM run_provenance WHERE run_id = ? ORDER BY kind, name`,
+		runID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query run provenance: %w", err)
+	}
+	defer rows.Close()
+
+	defs := []DefinitionDigest{}
+	for rows.Next() {
+		var d DefinitionDigest
+		if err := rows.Scan(&d.Kind, &d.Name, &d.Path, &d.Digest); err != nil {
+			return nil, fmt.Errorf("failed to scan run provenance: %w", err)
+		}
+		defs = append(defs, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating run provenance: %w", err)
+	}
+	return defs, nil
+}
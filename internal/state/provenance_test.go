@@ -0,0 +1,43 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunProvenance(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	runID, err := store.CreateRun("impl-issue", "input")
+	require.NoError(t, err)
+
+	empty, err := store.GetRunProvenance(runID)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	require.NoError(t, store.SaveRunProvenance(runID, []DefinitionDigest{
+		{Kind: DefinitionPersona, Name: "navigator", Path: ".agents/personas/navigator.md", Digest: "aaa"},
+		{Kind: DefinitionManifest, Name: "wave.yaml", Digest: "bbb"},
+		{Kind: DefinitionPipeline, Name: "impl-issue", Digest: "ccc"},
+	}))
+	// Re-saving replaces the digest for the same definition.
+	require.NoError(t, store.SaveRunProvenance(runID, []DefinitionDigest{
+		{Kind: DefinitionPipeline, Name: "impl-issue", Digest: "ddd"},
+	}))
+
+	defs, err := store.GetRunProvenance(runID)
+	require.NoError(t, err)
+	assert.Equal(t, []DefinitionDigest{
+		{Kind: DefinitionManifest, Name: "wave.yaml", Digest: "bbb"},
+		{Kind: DefinitionPersona, Name: "navigator", Path: ".agents/personas/navigator.md", Digest: "aaa"},
+		{Kind: DefinitionPipeline, Name: "impl-issue", Digest: "ddd"},
+	}, defs)
+
+	require.NoError(t, store.DeleteRun(runID))
+	defs, err = store.GetRunProvenance(runID)
+	require.NoError(t, err)
+	assert.Empty(t, defs)
+}
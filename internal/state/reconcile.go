@@ -1,8 +1,9 @@
 package state
 
 import (
-	"syscall"
 	"time"
+
+	"github.com/recinq/wave/internal/procutil"
 )
 
 // ZombieAgeThreshold is the default age beyond which a "running" run with no
@@ -59,6 +60,12 @@ func ReconcileZombies(store ZombieReconciler, ageThreshold time.Duration) int {
 	return reclaimed
 }
 
+// RunOrphaned reports whether a "running" run has lost its owning process,
+// by the same signals ReconcileZombies uses with the default age threshold.
+func RunOrphaned(r RunRecord) bool {
+	return isZombie(r, ZombieAgeThreshold)
+}
+
 // isZombie reports whether a "running" record has lost its owning process.
 // Heartbeat freshness is the primary signal; PID and age are fallbacks for
 // runs that have not yet started writing heartbeats (legacy data, or a run
@@ -79,10 +86,7 @@ func isZombie(r RunRecord, ageThreshold time.Duration) bool {
 		return time.Since(r.LastHeartbeat) > HeartbeatStaleThreshold
 	}
 	if r.PID > 0 {
-		if err := syscall.Kill(r.PID, 0); err == syscall.ESRCH {
-			return true
-		}
-		return false
+		return !procutil.Alive(r.PID)
 	}
 	return time.Since(r.StartedAt) > ageThreshold
 }
@@ -0,0 +1,73 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// StepReliabilityRecord aggregates the attempt history of one pipeline step
+// across runs, as consumed by flaky-step detection and `wave stats`.
+type StepReliabilityRecord struct {
+	PipelineName   string
+	StepID         string
+	Runs           int // Runs the step was attempted in
+	FailedRuns     int // Runs whose last attempt of the step failed
+	RetriedRuns    int // Runs that needed more than one attempt
+	Attempts       int
+	FailedAttempts int
+	LastRunAt      time.Time
+}
+
+// ListStepReliability aggregates step_attempt rows started at or after
+// since per pipeline and step, ordered by pipeline then step. A non-empty
+// pipelineName limits the result to that pipeline; a zero since covers the
+// full history.
+func (s *stateStore) ListStepReliability(pipelineName string, since time.Time) ([]StepReliabilityRecord, error) {
+	var sinceUnix int64
+	if !since.IsZero() {
+		sinceUnix = since.Unix()
+	}
+
+	// The inner query reduces each run to one row per step: how many
+	// attempts it took, how many failed, and whether any succeeded.
+	query := `SELECT pipeline_name, step_id, COUNT(*),
+	                 SUM(CASE WHEN succeeded = 0 AND failed > 0 THEN 1 ELSE 0 END),
+	                 SUM(CASE WHEN attempts > 1 THEN 1 ELSE 0 END),
+	                 SUM(attempts), SUM(failed), MAX(last_at)
+	          FROM (
+	              SELECT pr.pipeline_name AS pipeline_name, sa.step_id AS step_id,
+	                     MAX(sa.attempt) AS attempts,
+	                     SUM(CASE WHEN sa.state = 'failed' THEN 1 ELSE 0 END) AS failed,
+	                     MAX(CASE WHEN sa.state = 'succeeded' THEN 1 ELSE 0 END) AS succeeded,
+	                     MAX(sa.started_at) AS last_at
+	              FROM step_attempt sa
+	              JOIN pipeline_run pr ON pr.run_id = sa.run_id
+	              WHERE sa.started_at >= ? AND (? = '' OR pr.pipeline_name = ?)
+	              GROUP BY sa.run_id, sa.step_id
+	          )
+	          GROUP BY pipeline_name, step_id
+	          ORDER BY pipeline_name, step_id`
+
+	rows, err := s.db.Query(query, sinceUnix, pipelineName, pipelineName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query step reliability: %w", err)
+	}
+	defer rows.Close()
+
+	var records []StepReliabilityRecord
+	for rows.Next() {
+		var r StepReliabilityRecord
+		var lastAt int64
+		if err := rows.Scan(&r.PipelineName, &r.StepID, &r.Runs, &r.FailedRuns, &r.RetriedRuns,
+			&r.Attempts, &r.FailedAttempts, &lastAt); err != nil {
+			return nil, fmt.Errorf("failed to scan step reliability: %w", err)
+		}
+		r.LastRunAt = time.Unix(lastAt, 0)
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating step reliability: %w", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,64 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListStepReliability(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	now := time.Now()
+	attempt := func(runID, stepID string, n int, st string, started time.Time) {
+		require.NoError(t, store.RecordStepAttempt(&StepAttemptRecord{
+			RunID: runID, StepID: stepID, Attempt: n, State: st, StartedAt: started,
+		}))
+	}
+
+	// run1: implement fails once, then succeeds.
+	run1, err := store.CreateRun("impl-issue", "")
+	require.NoError(t, err)
+	attempt(run1, "implement", 1, "failed", now)
+	attempt(run1, "implement", 2, "succeeded", now)
+	attempt(run1, "plan", 1, "succeeded", now)
+
+	// run2: implement exhausts its retries.
+	run2, err := store.CreateRun("impl-issue", "")
+	require.NoError(t, err)
+	attempt(run2, "implement", 1, "failed", now)
+	attempt(run2, "implement", 2, "failed", now)
+
+	// run3: too old for the window.
+	run3, err := store.CreateRun("impl-issue", "")
+	require.NoError(t, err)
+	attempt(run3, "implement", 1, "failed", now.Add(-30*24*time.Hour))
+
+	other, err := store.CreateRun("audit", "")
+	require.NoError(t, err)
+	attempt(other, "scan", 1, "succeeded", now)
+
+	records, err := store.ListStepReliability("impl-issue", now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	impl := records[0]
+	assert.Equal(t, "implement", impl.StepID)
+	assert.Equal(t, 2, impl.Runs)
+	assert.Equal(t, 1, impl.FailedRuns)
+	assert.Equal(t, 2, impl.RetriedRuns)
+	assert.Equal(t, 4, impl.Attempts)
+	assert.Equal(t, 3, impl.FailedAttempts)
+	assert.Equal(t, now.Unix(), impl.LastRunAt.Unix())
+
+	assert.Equal(t, StepReliabilityRecord{PipelineName: "impl-issue", StepID: "plan", Runs: 1, Attempts: 1, LastRunAt: impl.LastRunAt}, records[1])
+
+	all, err := store.ListStepReliability("", time.Time{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "audit", all[0].PipelineName)
+	assert.Equal(t, 3, all[1].Runs)
+}
@@ -0,0 +1,134 @@
+package state
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ResourceLockRecord is a named resource lock held by a run, or by one step
+// of a run when StepID is set.
+type ResourceLockRecord struct {
+	Name       string
+	RunID      string
+	StepID     string
+	AcquiredAt time.Time
+}
+
+// ResourceLockStatus is the outcome of one attempt to take a resource lock.
+type ResourceLockStatus struct {
+	Acquired bool
+	// Holder is the current holder when the lock was not acquired.
+	Holder *ResourceLockRecord
+	// Position is the caller's 1-based place in the queue of waiters when
+	// the lock was not acquired.
+	Position int
+}
+
+// TryAcquireResourceLock takes the lock name for runID (and stepID, empty
+// for a run-wide lock) if it is free, without blocking. A caller that does
+// not get the lock is queued: the lock goes to waiters in the order they
+// first asked, so retrying callers cannot starve each other. A lock held
+// by the run itself, run-wide or by the same step, counts as acquired.
+//
+// Locks and queue entries of runs that have finished, or whose heartbeat
+// is older than HeartbeatStaleThreshold, are dropped, so a crashed process
+// never wedges a lock.
+func (s *stateStore) TryAcquireResourceLock(name, runID, stepID string) (*ResourceLockStatus, error) {
+	now := s.now()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire resource lock %q: %w", name, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stale := `SELECT run_id FROM pipeline_run
+	          WHERE status NOT IN ('pending', 'running')
+	             OR (last_heartbeat > 0 AND last_heartbeat < ?)`
+	cutoff := now.Add(-HeartbeatStaleThreshold).Unix()
+	if _, err := tx.Exec(`DELETE FROM resource_lock WHERE name = ? AND run_id IN (`+stale+`)`, name, cutoff); err != nil {
+		return nil, fmt.Errorf("failed to release stale resource lock %q: %w", name, err)
+	}
+	if _, err := tx.Exec(`DELETE FROM resource_lock_waiter WHERE name = ? AND run_id IN (`+stale+`)`, name, cutoff); err != nil {
+		return nil, fmt.Errorf("failed to drop stale resource lock waiters %q: %w", name, err)
+	}
+
+	var holder ResourceLockRecord
+	var acquiredAt int64
+	err = tx.QueryRow(`SELECT name, run_id, step_id, acquired_at FROM resource_lock WHERE name = ?`, name).
+		Scan(&holder.Name, &holder.RunID, &holder.StepID, &acquiredAt)
+	switch {
+	case err == nil:
+		if holder.RunID == runID && (holder.StepID == "" || holder.StepID == stepID) {
+			return &ResourceLockStatus{Acquired: true}, tx.Commit()
+		}
+		holder.AcquiredAt = time.Unix(acquiredAt, 0)
+	case errors.Is(err, sql.ErrNoRows):
+	default:
+		return nil, fmt.Errorf("failed to read resource lock %q: %w", name, err)
+	}
+
+	if _, err := tx.Exec(`INSERT INTO resource_lock_waiter (name, run_id, step_id, queued_at)
+	          VALUES (?, ?, ?, ?) ON CONFLICT(name, run_id, step_id) DO NOTHING`,
+		name, runID, stepID, now.Unix()); err != nil {
+		return nil, fmt.Errorf("failed to queue for resource lock %q: %w", name, err)
+	}
+	var position int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM resource_lock_waiter
+	          WHERE name = ? AND id <= (SELECT id FROM resource_lock_waiter WHERE name = ? AND run_id = ? AND step_id = ?)`,
+		name, name, runID, stepID).Scan(&position); err != nil {
+		return nil, fmt.Errorf("failed to read resource lock queue %q: %w", name, err)
+	}
+
+	if holder.RunID != "" || position > 1 {
+		status := &ResourceLockStatus{Position: position}
+		if holder.RunID != "" {
+			status.Holder = &holder
+		}
+		return status, tx.Commit()
+	}
+
+	if _, err := tx.Exec(`INSERT INTO resource_lock (name, run_id, step_id, acquired_at) VALUES (?, ?, ?, ?)`,
+		name, runID, stepID, now.Unix()); err != nil {
+		return nil, fmt.Errorf("failed to take resource lock %q: %w", name, err)
+	}
+	if _, err := tx.Exec(`DELETE FROM resource_lock_waiter WHERE name = ? AND run_id = ? AND step_id = ?`,
+		name, runID, stepID); err != nil {
+		return nil, fmt.Errorf("failed to dequeue from resource lock %q: %w", name, err)
+	}
+	return &ResourceLockStatus{Acquired: true}, tx.Commit()
+}
+
+// ReleaseResourceLock releases the lock name held by runID and stepID and
+// leaves its queue, so it also abandons a wait that timed out.
+func (s *stateStore) ReleaseResourceLock(name, runID, stepID string) error {
+	if _, err := s.db.Exec(`DELETE FROM resource_lock WHERE name = ? AND run_id = ? AND step_id = ?`, name, runID, stepID); err != nil {
+		return fmt.Errorf("failed to release resource lock %q: %w", name, err)
+	}
+	if _, err := s.db.Exec(`DELETE FROM resource_lock_waiter WHERE name = ? AND run_id = ? AND step_id = ?`, name, runID, stepID); err != nil {
+		return fmt.Errorf("failed to leave resource lock queue %q: %w", name, err)
+	}
+	return nil
+}
+
+// ListResourceLocks returns the resource locks currently held, by name.
+func (s *stateStore) ListResourceLocks() ([]ResourceLockRecord, error) {
+	rows, err := s.db.Query(`SELECT name, run_id, step_id, acquired_at FROM resource_lock ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource locks: %w", err)
+	}
+	defer rows.Close()
+
+	var locks []ResourceLockRecord
+	for rows.Next() {
+		var rec ResourceLockRecord
+		var acquiredAt int64
+		if err := rows.Scan(&rec.Name, &rec.RunID, &rec.StepID, &acquiredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan resource lock: %w", err)
+		}
+		rec.AcquiredAt = time.Unix(acquiredAt, 0)
+		locks = append(locks, rec)
+	}
+	return locks, rows.Err()
+}
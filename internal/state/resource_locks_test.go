@@ -0,0 +1,109 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func newRunningRun(t *testing.T, store *stateStore) string {
+	t.Helper()
+	runID, err := store.CreateRun("p", "in")
+	if err != nil {
+		t.Fatalf("CreateRun: %v", err)
+	}
+	if err := store.UpdateRunStatus(runID, "running", "", 0); err != nil {
+		t.Fatalf("UpdateRunStatus: %v", err)
+	}
+	return runID
+}
+
+func tryAcquire(t *testing.T, store *stateStore, name, runID, stepID string) *ResourceLockStatus {
+	t.Helper()
+	status, err := store.TryAcquireResourceLock(name, runID, stepID)
+	if err != nil {
+		t.Fatalf("TryAcquireResourceLock(%s, %s, %s): %v", name, runID, stepID, err)
+	}
+	return status
+}
+
+func TestResourceLock_QueuesWaitersInOrder(t *testing.T) {
+	store := newReconcileStore(t)
+	a, b, c := newRunningRun(t, store), newRunningRun(t, store), newRunningRun(t, store)
+
+	if !tryAcquire(t, store, "db", a, "migrate").Acquired {
+		t.Fatal("first caller should acquire a free lock")
+	}
+	status := tryAcquire(t, store, "db", b, "migrate")
+	if status.Acquired || status.Holder == nil || status.Holder.RunID != a || status.Holder.StepID != "migrate" {
+		t.Fatalf("second caller: got %+v, want holder %s/migrate", status, a)
+	}
+	if status.Position != 1 {
+		t.Errorf("second caller position = %d, want 1", status.Position)
+	}
+	if got := tryAcquire(t, store, "db", c, "migrate").Position; got != 2 {
+		t.Errorf("third caller position = %d, want 2", got)
+	}
+
+	if err := store.ReleaseResourceLock("db", a, "migrate"); err != nil {
+		t.Fatalf("ReleaseResourceLock: %v", err)
+	}
+	// The lock goes to the earliest waiter, not the first to retry.
+	if status := tryAcquire(t, store, "db", c, "migrate"); status.Acquired || status.Position != 2 {
+		t.Errorf("later waiter jumped the queue: %+v", status)
+	}
+	if !tryAcquire(t, store, "db", b, "migrate").Acquired {
+		t.Error("earliest waiter should acquire the released lock")
+	}
+	if status := tryAcquire(t, store, "db", c, "migrate"); status.Acquired || status.Position != 1 {
+		t.Errorf("remaining waiter: got %+v, want position 1", status)
+	}
+
+	locks, err := store.ListResourceLocks()
+	if err != nil {
+		t.Fatalf("ListResourceLocks: %v", err)
+	}
+	if len(locks) != 1 || locks[0].RunID != b {
+		t.Errorf("ListResourceLocks = %+v, want one lock held by %s", locks, b)
+	}
+}
+
+func TestResourceLock_RunWideLockIsReentrant(t *testing.T) {
+	store := newReconcileStore(t)
+	a := newRunningRun(t, store)
+
+	if !tryAcquire(t, store, "db", a, "").Acquired {
+		t.Fatal("run-wide acquire failed")
+	}
+	if !tryAcquire(t, store, "db", a, "migrate").Acquired {
+		t.Error("a step of the run holding the run-wide lock should acquire it")
+	}
+	if !tryAcquire(t, store, "other", a, "one").Acquired {
+		t.Fatal("step acquire failed")
+	}
+	if tryAcquire(t, store, "other", a, "two").Acquired {
+		t.Error("another step of the same run should wait for a step lock")
+	}
+}
+
+func TestResourceLock_DropsStaleHolders(t *testing.T) {
+	store := newReconcileStore(t)
+
+	finished, waiter := newRunningRun(t, store), newRunningRun(t, store)
+	tryAcquire(t, store, "db", finished, "migrate")
+	if err := store.UpdateRunStatus(finished, "failed", "", 0); err != nil {
+		t.Fatalf("UpdateRunStatus: %v", err)
+	}
+	if !tryAcquire(t, store, "db", waiter, "migrate").Acquired {
+		t.Error("lock of a finished run should be released")
+	}
+
+	crashed, waiter := newRunningRun(t, store), newRunningRun(t, store)
+	tryAcquire(t, store, "cache", crashed, "warm")
+	if _, err := store.db.Exec(`UPDATE pipeline_run SET last_heartbeat = ? WHERE run_id = ?`,
+		time.Now().Add(-2*HeartbeatStaleThreshold).Unix(), crashed); err != nil {
+		t.Fatalf("seed stale heartbeat: %v", err)
+	}
+	if !tryAcquire(t, store, "cache", waiter, "warm").Acquired {
+		t.Error("lock of a run with a stale heartbeat should be released")
+	}
+}
@@ -0,0 +1,116 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// Priority classes for runs queued for a worker slot, highest first.
+const (
+	RunPriorityInteractive = "interactive"
+	RunPriorityScheduled   = "scheduled"
+	RunPriorityBatch       = "batch"
+)
+
+// runPriorityRanks orders the priority classes; a higher rank is served
+// first.
+var runPriorityRanks = map[string]int{
+	RunPriorityInteractive: 3,
+	RunPriorityScheduled:   2,
+	RunPriorityBatch:       1,
+}
+
+// ValidateRunPriority reports an error unless priority is a known class or
+// empty (interactive).
+func ValidateRunPriority(priority string) error {
+	if priority == "" {
+		return nil
+	}
+	if _, ok := runPriorityRanks[priority]; !ok {
+		return fmt.Errorf("invalid priority %q: must be %s, %s or %s",
+			priority, RunPriorityInteractive, RunPriorityScheduled, RunPriorityBatch)
+	}
+	return nil
+}
+
+// runQueueAbandonAfter is how long a queue entry survives without its
+// waiter retrying before another waiter drops it. Waiters retry every few
+// seconds, so an entry this old belongs to a process that went away.
+const runQueueAbandonAfter = 30 * time.Second
+
+// CreateQueuedRun creates a run like CreateRunWithLimit, queueing the
+// caller when maxConcurrent runs are already active. ticket identifies the
+// caller across retries. Waiters are served by priority class, then in the
+// order they queued: a queued run is passed over by every later run of a
+// higher class, but runs that already started are never preempted.
+//
+// When the run cannot start yet CreateQueuedRun returns ErrConcurrencyLimit
+// with the caller's 1-based position in the queue. The caller retries with
+// the same ticket, or calls LeaveRunQueue to give up.
+func (s *stateStore) CreateQueuedRun(ticket, pipelineName, input, priority string, maxConcurrent int) (string, int, error) {
+	if err := ValidateRunPriority(priority); err != nil {
+		return "", 0, err
+	}
+	rank := runPriorityRanks[priority]
+	if rank == 0 {
+		rank = runPriorityRanks[RunPriorityInteractive]
+	}
+	now := s.now()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM run_queue WHERE polled_at < ?`, now.Add(-runQueueAbandonAfter).Unix()); err != nil {
+		return "", 0, fmt.Errorf("failed to drop abandoned queue entries: %w", err)
+	}
+	if _, err := tx.Exec(`INSERT INTO run_queue (ticket, pipeline_name, priority, queued_at, polled_at)
+	                      VALUES (?, ?, ?, ?, ?)
+	                      ON CONFLICT(ticket) DO UPDATE SET polled_at = excluded.polled_at`,
+		ticket, pipelineName, rank, now.Unix(), now.Unix()); err != nil {
+		return "", 0, fmt.Errorf("failed to queue run: %w", err)
+	}
+
+	var position int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM run_queue q, run_queue me
+	                       WHERE me.ticket = ?
+	                         AND (q.priority > me.priority OR (q.priority = me.priority AND q.id <= me.id))`,
+		ticket).Scan(&position); err != nil {
+		return "", 0, fmt.Errorf("failed to read run queue: %w", err)
+	}
+	if maxConcurrent > 0 {
+		var active int
+		if err := tx.QueryRow(activeRunCountQuery).Scan(&active); err != nil {
+			return "", 0, fmt.Errorf("failed to count running runs: %w", err)
+		}
+		if position > maxConcurrent-active {
+			if err := tx.Commit(); err != nil {
+				return "", 0, fmt.Errorf("failed to commit run queue: %w", err)
+			}
+			return "", position, ErrConcurrencyLimit
+		}
+	}
+
+	runID := s.newRunID(pipelineName, now)
+	if _, err := tx.Exec(`INSERT INTO pipeline_run (run_id, pipeline_name, status, input, started_at)
+	                      VALUES (?, ?, 'pending', ?, ?)`, runID, pipelineName, input, now.Unix()); err != nil {
+		return "", 0, fmt.Errorf("failed to create run: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM run_queue WHERE ticket = ?`, ticket); err != nil {
+		return "", 0, fmt.Errorf("failed to dequeue run: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", 0, fmt.Errorf("failed to commit run: %w", err)
+	}
+	return runID, 0, nil
+}
+
+// LeaveRunQueue removes the queue entry of ticket, if any.
+func (s *stateStore) LeaveRunQueue(ticket string) error {
+	if _, err := s.db.Exec(`DELETE FROM run_queue WHERE ticket = ?`, ticket); err != nil {
+		return fmt.Errorf("failed to leave run queue: %w", err)
+	}
+	return nil
+}
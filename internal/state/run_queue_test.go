@@ -0,0 +1,83 @@
+package state
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func queueRun(t *testing.T, store *stateStore, ticket, priority string) (string, int) {
+	t.Helper()
+	runID, position, err := store.CreateQueuedRun(ticket, "p", "in", priority, 1)
+	if err != nil && !errors.Is(err, ErrConcurrencyLimit) {
+		t.Fatalf("CreateQueuedRun(%s): %v", ticket, err)
+	}
+	return runID, position
+}
+
+func TestCreateQueuedRun_ServesHigherPriorityFirst(t *testing.T) {
+	store := newReconcileStore(t)
+	active, err := store.CreateRun("p", "in")
+	if err != nil {
+		t.Fatalf("CreateRun: %v", err)
+	}
+
+	if _, pos := queueRun(t, store, "nightly", RunPriorityBatch); pos != 1 {
+		t.Errorf("batch position = %d, want 1", pos)
+	}
+	if _, pos := queueRun(t, store, "cron", RunPriorityScheduled); pos != 1 {
+		t.Errorf("scheduled run should queue ahead of batch, position = %d", pos)
+	}
+	if _, pos := queueRun(t, store, "dev", ""); pos != 1 {
+		t.Errorf("interactive run should queue ahead of all, position = %d", pos)
+	}
+	if _, pos := queueRun(t, store, "nightly", RunPriorityBatch); pos != 3 {
+		t.Errorf("batch position after being passed over = %d, want 3", pos)
+	}
+
+	if err := store.UpdateRunStatus(active, "completed", "", 0); err != nil {
+		t.Fatalf("UpdateRunStatus: %v", err)
+	}
+	if runID, _ := queueRun(t, store, "nightly", RunPriorityBatch); runID != "" {
+		t.Error("batch run took the free slot ahead of queued higher-priority runs")
+	}
+	runID, _ := queueRun(t, store, "dev", RunPriorityInteractive)
+	if runID == "" {
+		t.Fatal("interactive run should take the free slot")
+	}
+	if _, pos := queueRun(t, store, "cron", RunPriorityScheduled); pos != 1 {
+		t.Errorf("scheduled position after the interactive run started = %d, want 1", pos)
+	}
+}
+
+func TestCreateQueuedRun_DropsAbandonedWaiters(t *testing.T) {
+	store := newReconcileStore(t)
+	active, err := store.CreateRun("p", "in")
+	if err != nil {
+		t.Fatalf("CreateRun: %v", err)
+	}
+	queueRun(t, store, "gone", RunPriorityInteractive)
+	queueRun(t, store, "nightly", RunPriorityBatch)
+
+	if _, err := store.db.Exec(`UPDATE run_queue SET polled_at = ? WHERE ticket = 'gone'`,
+		time.Now().Add(-2*runQueueAbandonAfter).Unix()); err != nil {
+		t.Fatalf("seed abandoned entry: %v", err)
+	}
+	if err := store.UpdateRunStatus(active, "completed", "", 0); err != nil {
+		t.Fatalf("UpdateRunStatus: %v", err)
+	}
+	if runID, _ := queueRun(t, store, "nightly", RunPriorityBatch); runID == "" {
+		t.Error("an abandoned waiter should not hold back the queue")
+	}
+}
+
+func TestValidateRunPriority(t *testing.T) {
+	for _, p := range []string{"", RunPriorityInteractive, RunPriorityScheduled, RunPriorityBatch} {
+		if err := ValidateRunPriority(p); err != nil {
+			t.Errorf("ValidateRunPriority(%q): %v", p, err)
+		}
+	}
+	if err := ValidateRunPriority("urgent"); err == nil {
+		t.Error("ValidateRunPriority(urgent) should fail")
+	}
+}
@@ -1,9 +1,7 @@
 package state
 
 import (
-	"crypto/rand"
 	"database/sql"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -18,12 +16,7 @@ func (s *stateStore) CreateRun(pipelineName string, input string) (string, error
 // Returns ErrConcurrencyLimit when the limit is hit.
 func (s *stateStore) CreateRunWithLimit(pipelineName string, input string, maxConcurrent int) (string, error) {
 	now := s.now()
-	randBytes := make([]byte, 2)
-	if _, err := rand.Read(randBytes); err != nil {
-		randBytes = []byte{byte(now.Nanosecond() >> 8), byte(now.Nanosecond())}
-	}
-	suffix := hex.EncodeToString(randBytes)
-	runID := fmt.Sprintf("%s-%s-%s", pipelineName, now.Format("20060102-150405"), suffix)
+	runID := s.newRunID(pipelineName, now)
 
 	if maxConcurrent > 0 {
 		// Atomic check-and-insert within a transaction
@@ -34,7 +27,7 @@ func (s *stateStore) CreateRunWithLimit(pipelineName string, input string, maxCo
 		defer func() { _ = tx.Rollback() }()
 
 		var count int
-		err = tx.QueryRow(`SELECT COUNT(*) FROM pipeline_run WHERE status IN ('running', 'pending') AND started_at > unixepoch() - 300`).Scan(&count)
+		err = tx.QueryRow(activeRunCountQuery).Scan(&count)
 		if err != nil {
 			return "", fmt.Errorf("failed to count running runs: %w", err)
 		}
@@ -62,6 +55,10 @@ func (s *stateStore) CreateRunWithLimit(pipelineName string, input string, maxCo
 	return runID, nil
 }
 
+// activeRunCountQuery counts the runs that occupy a worker slot under
+// max_concurrent_workers.
+const activeRunCountQuery = `SELECT COUNT(*) FROM pipeline_run WHERE status IN ('running', 'pending') AND started_at > unixepoch() - 300`
+
 // ErrConcurrencyLimit is returned when max_concurrent_workers is reached.
 var ErrConcurrencyLimit = fmt.Errorf("concurrency limit reached")
 
@@ -117,6 +114,44 @@ func (s *stateStore) UpdateRunBranch(runID string, branch string) error {
 	return nil
 }
 
+// SetRunIdempotencyKey records the idempotency key of a run: a hash of the
+// pipeline definition and input that identifies duplicate launches.
+func (s *stateStore) SetRunIdempotencyKey(runID string, key string) error {
+	result, err := s.db.Exec(`UPDATE pipeline_run SET idempotency_key = ? WHERE run_id = ?`, key, runID)
+	if err != nil {
+		return fmt.Errorf("failed to set run idempotency key: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("run not found: %s", runID)
+	}
+	return nil
+}
+
+// SetRunBudgetExceeded records that a run crossed a token budget. A run
+// crossing several budgets keeps every report, in order.
+func (s *stateStore) SetRunBudgetExceeded(runID string, detail string) error {
+	result, err := s.db.Exec(
+		`UPDATE pipeline_run SET budget_exceeded = CASE WHEN budget_exceeded = '' THEN ? ELSE budget_exceeded || '; ' || ? END
+		 WHERE run_id = ?`,
+		detail, detail, runID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set run budget exceeded: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("run not found: %s", runID)
+	}
+	return nil
+}
+
 // UpdateRunPID sets the OS process ID for a detached pipeline run.
 func (s *stateStore) UpdateRunPID(runID string, pid int) error {
 	query := `UPDATE pipeline_run SET pid = ? WHERE run_id = ?`
@@ -176,7 +211,7 @@ func (s *stateStore) GetRun(runID string) (*RunRecord, error) {
 	query := `SELECT run_id, pipeline_name, status, input, current_step, total_tokens,
 	                 started_at, completed_at, cancelled_at, error_message, tags_json, branch_name, pid,
 	                 parent_run_id, parent_step_id, forked_from_run_id, last_heartbeat,
-	                 iterate_index, iterate_total, iterate_mode, run_kind, sub_pipeline_ref
+	                 iterate_index, iterate_total, iterate_mode, run_kind, sub_pipeline_ref, budget_exceeded, pinned
 	          FROM pipeline_run
 	          WHERE run_id = ?`
 
@@ -213,6 +248,8 @@ func (s *stateStore) GetRun(runID string) (*RunRecord, error) {
 		&iterateMode,
 		&runKind,
 		&subPipelineRef,
+		&record.BudgetExceeded,
+		&record.Pinned,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -289,7 +326,7 @@ func (s *stateStore) GetRunningRuns() ([]RunRecord, error) {
 	query := `SELECT run_id, pipeline_name, status, input, current_step, total_tokens,
 	                 started_at, completed_at, cancelled_at, error_message, tags_json, branch_name, pid,
 	                 parent_run_id, parent_step_id, forked_from_run_id, last_heartbeat,
-	                 iterate_index, iterate_total, iterate_mode, run_kind, sub_pipeline_ref
+	                 iterate_index, iterate_total, iterate_mode, run_kind, sub_pipeline_ref, pinned
 	          FROM pipeline_run
 	          WHERE (status = 'running' OR (status = 'pending' AND started_at > unixepoch() - 300))
 	          ORDER BY started_at DESC`
@@ -302,7 +339,7 @@ func (s *stateStore) ListRuns(opts ListRunsOptions) ([]RunRecord, error) {
 	query := `SELECT run_id, pipeline_name, status, input, current_step, total_tokens,
 	                 started_at, completed_at, cancelled_at, error_message, tags_json, branch_name, pid,
 	                 parent_run_id, parent_step_id, forked_from_run_id, last_heartbeat,
-	                 iterate_index, iterate_total, iterate_mode, run_kind, sub_pipeline_ref
+	                 iterate_index, iterate_total, iterate_mode, run_kind, sub_pipeline_ref, pinned
 	          FROM pipeline_run
 	          WHERE 1=1`
 	args := []any{}
@@ -343,6 +380,15 @@ func (s *stateStore) ListRuns(opts ListRunsOptions) ([]RunRecord, error) {
 		query += " AND (parent_run_id IS NULL OR parent_run_id = '')"
 	}
 
+	if opts.IdempotencyKey != "" {
+		query += " AND idempotency_key = ?"
+		args = append(args, opts.IdempotencyKey)
+	}
+
+	if opts.PinnedOnly {
+		query += " AND pinned = 1"
+	}
+
 	// Cursor-based pagination: return runs before the cursor position
 	if opts.BeforeUnix > 0 {
 		if opts.BeforeRunID != "" {
@@ -541,6 +587,7 @@ func (s *stateStore) queryRunsWithArgs(query string, args ...any) ([]RunRecord,
 			&iterateMode,
 			&runKind,
 			&subPipelineRef,
+			&record.Pinned,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan run: %w", err)
@@ -645,6 +692,23 @@ func (s *stateStore) SetRunTags(runID string, tags []string) error {
 	return nil
 }
 
+// SetRunPinned pins or unpins a run. Pinned runs and their workspaces are
+// skipped by wave clean, wave cleanup and runtime.workspace_cleanup.
+func (s *stateStore) SetRunPinned(runID string, pinned bool) error {
+	result, err := s.db.Exec(`UPDATE pipeline_run SET pinned = ? WHERE run_id = ?`, pinned, runID)
+	if err != nil {
+		return fmt.Errorf("failed to set run pinned: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("run not found: %s", runID)
+	}
+	return nil
+}
+
 // GetRunTags retrieves the tags for a pipeline run.
 func (s *stateStore) GetRunTags(runID string) ([]string, error) {
 	query := `SELECT tags_json FROM pipeline_run WHERE run_id = ?`
@@ -3,9 +3,9 @@ package state
 import "time"
 
 // RunStore is the domain-scoped persistence surface for pipeline + step
-// lifecycle: runs, cancellation, tags, parent/child linkage, checkpoints,
-// decisions, outcomes, orchestration decisions, progress snapshots, step
-// attempts, and visit counts.
+// lifecycle: runs, resource locks, approval gates, cancellation, tags,
+// parent/child linkage, checkpoints, decisions, outcomes, orchestration
+// decisions, progress snapshots, step attempts, and visit counts.
 //
 // Consumers that only touch run/step lifecycle data should depend on this
 // interface rather than the aggregate StateStore. Performance metric and
@@ -25,12 +25,33 @@ type RunStore interface {
 	RecordStepAttempt(record *StepAttemptRecord) error
 	GetStepAttempts(runID string, stepID string) ([]StepAttemptRecord, error)
 
+	// Definition provenance
+	SaveRunProvenance(runID string, defs []DefinitionDigest) error
+	GetRunProvenance(runID string) ([]DefinitionDigest, error)
+
+	// Cost tracking
+	RecordStepCost(rec *StepCostRecord) error
+	GetStepCosts(runID string) ([]StepCostRecord, error)
+	GetRunCosts(runIDs []string) (map[string]float64, error)
+	GetPipelineCosts() (map[string]float64, error)
+
+	// Failure triage
+	SaveStepFailureCategory(pipelineID string, stepID string, category string) error
+	ListStepFailures(since time.Time) ([]StepFailureRecord, error)
+	ListStepReliability(pipelineName string, since time.Time) ([]StepReliabilityRecord, error)
+
 	// Run tracking
 	CreateRun(pipelineName string, input string) (string, error)
 	CreateRunWithLimit(pipelineName string, input string, maxConcurrent int) (string, error)
 	CreateRunWithFork(pipelineName, input, forkedFromRunID string) (string, error)
+	CreateQueuedRun(ticket, pipelineName, input, priority string, maxConcurrent int) (string, int, error)
+	LeaveRunQueue(ticket string) error
 	UpdateRunStatus(runID string, status string, currentStep string, tokens int) error
 	UpdateRunBranch(runID string, branch string) error
+	SetRunIdempotencyKey(runID string, key string) error
+	SetRunBudgetExceeded(runID string, detail string) error
+	SetRunEnvironment(runID string, env *RunEnvironment) error
+	GetRunEnvironment(runID string) (*RunEnvironment, error)
 	UpdateRunPID(runID string, pid int) error
 	UpdateRunHeartbeat(runID string) error
 	ReapOrphans(staleAfter time.Duration) (int, error)
@@ -44,6 +65,16 @@ type RunStore interface {
 	ListPipelineNamesByStatus(status string) ([]string, error)
 	BackfillRunTokens() (int64, error)
 
+	// Resource locks
+	TryAcquireResourceLock(name, runID, stepID string) (*ResourceLockStatus, error)
+	ReleaseResourceLock(name, runID, stepID string) error
+	ListResourceLocks() ([]ResourceLockRecord, error)
+
+	// Approval gates
+	RequestApproval(runID, stepID, prompt string, deadline time.Time) error
+	DecideApproval(runID, stepID string, approved bool, decidedBy, reason string) error
+	GetApproval(runID, stepID string) (*ApprovalRecord, error)
+
 	// Cancellation
 	RequestCancellation(runID string, force bool) error
 	CheckCancellation(runID string) (*CancellationRecord, error)
@@ -51,6 +82,7 @@ type RunStore interface {
 
 	// Tags
 	SetRunTags(runID string, tags []string) error
+	SetRunPinned(runID string, pinned bool) error
 	GetRunTags(runID string) ([]string, error)
 	AddRunTag(runID string, tag string) error
 	RemoveRunTag(runID string, tag string) error
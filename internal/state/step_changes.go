@@ -0,0 +1,128 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// File change statuses.
+const (
+	FileAdded    = "added"
+	FileModified = "modified"
+	FileDeleted  = "deleted"
+)
+
+// FileChange is one file a step added, modified or deleted, with its line
+// counts. Binary files count no lines.
+type FileChange struct {
+	Path       string `json:"path"`
+	Status     string `json:"status"`
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+	Binary     bool   `json:"binary,omitempty"`
+}
+
+// StepChangeRecord summarizes the files one step attempt changed in its
+// workspace.
+type StepChangeRecord struct {
+	ID            int64        `json:"id"`
+	RunID         string       `json:"run_id"`
+	StepID        string       `json:"step_id"`
+	FilesAdded    int          `json:"files_added"`
+	FilesModified int          `json:"files_modified"`
+	FilesDeleted  int          `json:"files_deleted"`
+	Insertions    int          `json:"insertions"`
+	Deletions     int          `json:"deletions"`
+	Files         []FileChange `json:"files"`
+	ArtifactPath  string       `json:"artifact_path,omitempty"`
+	// BaseTree is the git tree of the workspace before the step ran. It is
+	// kept in the changes artifact only, not in the database.
+	BaseTree  string    `json:"base_tree,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FilesChanged returns the number of files added, modified or deleted. A
+// nil record changed none.
+func (r *StepChangeRecord) FilesChanged() int {
+	if r == nil {
+		return 0
+	}
+	return r.FilesAdded + r.FilesModified + r.FilesDeleted
+}
+
+// ChangedPaths returns the paths of the files added or modified, in record
+// order. Deleted files are left out.
+func (r *StepChangeRecord) ChangedPaths() []string {
+	paths := []string{}
+	if r == nil {
+		return paths
+	}
+	for _, f := range r.Files {
+		if f.Status != FileDeleted {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+// SaveStepChanges records a step's change summary and sets its ID.
+func (s *stateStore) SaveStepChanges(record *StepChangeRecord) error {
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = s.now()
+	}
+	files := record.Files
+	if files == nil {
+		files = []FileChange{}
+	}
+	filesJSON, err := json.Marshal(files)
+	if err != nil {
+		return fmt.Errorf("failed to encode changed files: %w", err)
+	}
+	res, err := s.db.Exec(
+		`INSERT INTO step_change (run_id, step_id, files_added, files_modified, files_deleted, insertions, deletions, files, artifact_path, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		record.RunID, record.StepID, record.FilesAdded, record.FilesModified, record.FilesDeleted,
+		record.Insertions, record.Deletions, string(filesJSON), record.ArtifactPath, record.CreatedAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save step changes: %w", err)
+	}
+	record.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// GetStepChanges returns the change summaries of a run in the order they
+// were recorded, one per step attempt. A non-empty stepID limits them to
+// that step.
+func (s *stateStore) GetStepChanges(runID string, stepID string) ([]StepChangeRecord, error) {
+	query := `SELECT id, run_id, step_id, files_added, files_modified, files_deleted, insertions, deletions, files, artifact_path, created_at
+	          FROM step_change WHERE run_id = ?`
+	args := []any{runID}
+	if stepID != "" {
+		query += ` AND step_id = ?`
+		args = append(args, stepID)
+	}
+	query += ` ORDER BY id`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query step changes: %w", err)
+	}
+	defer rows.Close()
+
+	var records []StepChangeRecord
+	for rows.Next() {
+		var r StepChangeRecord
+		var files string
+		var createdAt int64
+		if err := rows.Scan(&r.ID, &r.RunID, &r.StepID, &r.FilesAdded, &r.FilesModified, &r.FilesDeleted,
+			&r.Insertions, &r.Deletions, &files, &r.ArtifactPath, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan step changes: %w", err)
+		}
+		_ = json.Unmarshal([]byte(files), &r.Files)
+		r.CreatedAt = time.Unix(createdAt, 0)
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
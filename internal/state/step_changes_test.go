@@ -0,0 +1,52 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepChanges_SaveGet(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	impl := &StepChangeRecord{
+		RunID:         "run-1",
+		StepID:        "implement",
+		FilesAdded:    1,
+		FilesModified: 1,
+		Insertions:    12,
+		Deletions:     3,
+		Files: []FileChange{
+			{Path: "main.go", Status: FileModified, Insertions: 2, Deletions: 3},
+			{Path: "util.go", Status: FileAdded, Insertions: 10},
+		},
+		ArtifactPath: "/ws/.agents/artifacts/implement/changes.json",
+	}
+	review := &StepChangeRecord{RunID: "run-1", StepID: "review"}
+	require.NoError(t, store.SaveStepChanges(impl))
+	require.NoError(t, store.SaveStepChanges(review))
+	assert.NotZero(t, impl.ID)
+
+	all, err := store.GetStepChanges("run-1", "")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Empty(t, all[1].Files)
+	assert.Zero(t, all[1].FilesChanged())
+
+	steps, err := store.GetStepChanges("run-1", "implement")
+	require.NoError(t, err)
+	require.Len(t, steps, 1)
+	got := steps[0]
+	assert.Equal(t, 2, got.FilesChanged())
+	assert.Equal(t, []string{"main.go", "util.go"}, got.ChangedPaths())
+	assert.Equal(t, 12, got.Insertions)
+	assert.Equal(t, 3, got.Deletions)
+	assert.Equal(t, impl.Files, got.Files)
+	assert.Equal(t, impl.ArtifactPath, got.ArtifactPath)
+
+	var none *StepChangeRecord
+	assert.Zero(t, none.FilesChanged())
+	assert.Empty(t, none.ChangedPaths())
+}
@@ -2,7 +2,9 @@ package state
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -28,6 +30,8 @@ const (
 	StateRetrying       StepState = event.StateRetrying
 	StateSkipped        StepState = event.StateSkipped
 	StateReworking      StepState = event.StateReworking
+	// StateWaitingApproval marks an approval gate waiting for `wave approve`.
+	StateWaitingApproval StepState = event.StateWaitingApproval
 	// StateRejected marks a terminal "design rejection" — a contract with
 	// on_failure: rejected fired because the persona output deliberately
 	// signalled the work is non-actionable (e.g. issue already implemented).
@@ -57,6 +61,10 @@ type StepStateRecord struct {
 	WorkspacePath string
 	ErrorMessage  string
 	VisitCount    int
+	// FailureCategory is the triage category recorded when the step
+	// terminally failed (rate_limit, contract_violation, timeout, ...).
+	// Empty for steps that never failed.
+	FailureCategory string
 }
 
 // StateStore is the aggregate persistence surface — the union of every
@@ -76,6 +84,8 @@ type StateStore interface {
 	EvolutionStore
 	WorksourceStore
 	ScheduleStore
+	KnowledgeStore
+	AdapterCacheStore
 
 	Close() error
 }
@@ -112,6 +122,31 @@ func WaitForConcurrencySlot(ctx context.Context, store runningRunsLister, maxWor
 type stateStore struct {
 	db    *sql.DB
 	clock func() time.Time
+	runID RunIDFormatter
+}
+
+// RunIDFormatter mints the ID of a new run of pipelineName created at now.
+type RunIDFormatter func(pipelineName string, now time.Time) string
+
+// SetRunIDFormatter makes s mint run IDs with f (runtime.naming.run_id).
+// A nil f restores the built-in "{name}-{YYYYMMDD-HHMMSS}-{hex4}" format.
+// It is a no-op for stores other than the sqlite-backed implementation.
+func SetRunIDFormatter(s StateStore, f RunIDFormatter) {
+	if ss, ok := s.(*stateStore); ok {
+		ss.runID = f
+	}
+}
+
+// newRunID mints the ID for a new run of pipelineName.
+func (s *stateStore) newRunID(pipelineName string, now time.Time) string {
+	if s.runID != nil {
+		return s.runID(pipelineName, now)
+	}
+	randBytes := make([]byte, 2)
+	if _, err := rand.Read(randBytes); err != nil {
+		randBytes = []byte{byte(now.Nanosecond() >> 8), byte(now.Nanosecond())}
+	}
+	return fmt.Sprintf("%s-%s-%s", pipelineName, now.Format("20060102-150405"), hex.EncodeToString(randBytes))
 }
 
 func (s *stateStore) now() time.Time {
@@ -251,6 +286,11 @@ func initializeWithMigrations(db *sql.DB, config *MigrationConfig) error {
 	} else {
 		// Apply any pending migrations up to the max version
 		maxVersion := config.GetMaxVersion()
+		if !config.SkipMigrationBackup {
+			if err := backupIfPending(migrationManager, db, allMigrations, currentVersion, maxVersion); err != nil {
+				return err
+			}
+		}
 		if err := migrationManager.MigrateUp(allMigrations, maxVersion); err != nil {
 			return fmt.Errorf("failed to apply pending migrations: %w", err)
 		}
@@ -287,7 +327,8 @@ func (s *stateStore) SaveStepState(pipelineID string, stepID string, state StepS
 	              retry_count = CASE WHEN excluded.state = 'retrying' THEN retry_count + 1 ELSE retry_count END,
 	              started_at = COALESCE(started_at, excluded.started_at),
 	              completed_at = excluded.completed_at,
-	              error_message = excluded.error_message`
+	              error_message = excluded.error_message,
+	              failure_category = CASE WHEN excluded.state = 'running' THEN '' ELSE failure_category END`
 
 	var startedAt, completedAt *int64
 	if state == StateRunning || state == StateRetrying {
@@ -372,7 +413,7 @@ func (s *stateStore) GetPipelineState(id string) (*PipelineStateRecord, error) {
 }
 
 func (s *stateStore) GetStepStates(pipelineID string) ([]StepStateRecord, error) {
-	query := `SELECT step_id, pipeline_id, state, retry_count, started_at, completed_at, workspace_path, error_message, visit_count
+	query := `SELECT step_id, pipeline_id, state, retry_count, started_at, completed_at, workspace_path, error_message, visit_count, failure_category
 	          FROM step_state
 	          WHERE pipeline_id = ?
 	          ORDER BY step_id`
@@ -399,6 +440,7 @@ func (s *stateStore) GetStepStates(pipelineID string) ([]StepStateRecord, error)
 			&workspacePath,
 			&errMsg,
 			&record.VisitCount,
+			&record.FailureCategory,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan step state: %w", err)
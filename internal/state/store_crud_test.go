@@ -268,3 +268,60 @@ func TestGetEvents_StepFilter(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, step2Events, 1)
 }
+
+func TestSetRunIdempotencyKey_ListRunsFilter(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	first, err := store.CreateRun("test-pipeline", "issue 42")
+	require.NoError(t, err)
+	second, err := store.CreateRun("test-pipeline", "issue 42")
+	require.NoError(t, err)
+	other, err := store.CreateRun("test-pipeline", "issue 43")
+	require.NoError(t, err)
+
+	require.NoError(t, store.SetRunIdempotencyKey(first, "key-42"))
+	require.NoError(t, store.SetRunIdempotencyKey(second, "key-42"))
+	require.NoError(t, store.SetRunIdempotencyKey(other, "key-43"))
+
+	runs, err := store.ListRuns(ListRunsOptions{IdempotencyKey: "key-42"})
+	require.NoError(t, err)
+	ids := make([]string, len(runs))
+	for i, r := range runs {
+		ids[i] = r.RunID
+	}
+	assert.ElementsMatch(t, []string{first, second}, ids)
+
+	runs, err = store.ListRuns(ListRunsOptions{IdempotencyKey: "key-none"})
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+}
+
+func TestSetRunBudgetExceeded(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	runID, err := store.CreateRun("test-pipeline", "input")
+	require.NoError(t, err)
+
+	run, err := store.GetRun(runID)
+	require.NoError(t, err)
+	assert.Empty(t, run.BudgetExceeded)
+
+	require.NoError(t, store.SetRunBudgetExceeded(runID, "step 'plan' token budget exceeded"))
+	require.NoError(t, store.SetRunBudgetExceeded(runID, "pipeline token budget exceeded"))
+	run, err = store.GetRun(runID)
+	require.NoError(t, err)
+	assert.Equal(t, "step 'plan' token budget exceeded; pipeline token budget exceeded", run.BudgetExceeded)
+
+	assert.ErrorContains(t, store.SetRunBudgetExceeded("missing", "x"), "run not found")
+}
+
+func TestSetRunIdempotencyKey_NonExistentRun(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	err := store.SetRunIdempotencyKey("nonexistent-run-id", "key")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "run not found")
+}
@@ -932,6 +932,21 @@ func verifyStepState(t *testing.T, store StateStore, pipelineID, stepID string,
 // =============================================================================
 
 // TestCreateRun tests run creation and ID generation.
+func TestCreateRun_RunIDFormatter(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	SetRunIDFormatter(store, func(name string, _ time.Time) string { return "acme-" + name + "-1" })
+	runID, err := store.CreateRun("build", "")
+	require.NoError(t, err)
+	assert.Equal(t, "acme-build-1", runID)
+
+	SetRunIDFormatter(store, nil)
+	runID, err = store.CreateRun("build", "")
+	require.NoError(t, err)
+	assert.Regexp(t, `^build-\d{8}-\d{6}-[0-9a-f]{4}$`, runID)
+}
+
 func TestCreateRun(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -2821,3 +2836,34 @@ func TestGetEventsExtendedOptions(t *testing.T) {
 	require.Len(t, sinceEvents, 3)
 	assert.Equal(t, all[3].ID, sinceEvents[0].ID)
 }
+
+func TestSetRunPinned(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	pinnedID, err := store.CreateRun("audit", "")
+	require.NoError(t, err)
+	_, err = store.CreateRun("audit", "")
+	require.NoError(t, err)
+
+	require.NoError(t, store.SetRunPinned(pinnedID, true))
+	run, err := store.GetRun(pinnedID)
+	require.NoError(t, err)
+	assert.True(t, run.Pinned)
+
+	pinned, err := store.ListRuns(ListRunsOptions{PinnedOnly: true})
+	require.NoError(t, err)
+	require.Len(t, pinned, 1)
+	assert.Equal(t, pinnedID, pinned[0].RunID)
+	assert.True(t, pinned[0].Pinned)
+
+	all, err := store.ListRuns(ListRunsOptions{})
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	require.NoError(t, store.SetRunPinned(pinnedID, false))
+	pinned, err = store.ListRuns(ListRunsOptions{PinnedOnly: true})
+	require.NoError(t, err)
+	assert.Empty(t, pinned)
+	assert.Error(t, store.SetRunPinned("missing-run", true))
+}
@@ -0,0 +1,74 @@
+package state
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// StepFailureRecord is one terminally failed step joined with its run's
+// pipeline name, as consumed by `wave triage`.
+type StepFailureRecord struct {
+	RunID           string
+	PipelineName    string
+	StepID          string
+	FailureCategory string
+	ErrorMessage    string
+	FailedAt        time.Time
+}
+
+// SaveStepFailureCategory records the triage category for a step that
+// terminally failed. The step_state row must already exist (it is created by
+// SaveStepState when the step starts); a missing row is a no-op.
+func (s *stateStore) SaveStepFailureCategory(pipelineID string, stepID string, category string) error {
+	_, err := s.db.Exec(
+		`UPDATE step_state SET failure_category = ? WHERE step_id = ? AND pipeline_id = ?`,
+		category, stepID, pipelineID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save step failure category: %w", err)
+	}
+	return nil
+}
+
+// ListStepFailures returns every categorized step failure whose step
+// completed (or, lacking a completion time, started) at or after since,
+// newest first. A zero since returns the full history.
+func (s *stateStore) ListStepFailures(since time.Time) ([]StepFailureRecord, error) {
+	query := `SELECT ss.pipeline_id, COALESCE(pr.pipeline_name, ''), ss.step_id, ss.failure_category,
+	                 ss.error_message, COALESCE(ss.completed_at, ss.started_at, 0) AS failed_at
+	          FROM step_state ss
+	          LEFT JOIN pipeline_run pr ON pr.run_id = ss.pipeline_id
+	          WHERE ss.failure_category != ''
+	            AND COALESCE(ss.completed_at, ss.started_at, 0) >= ?
+	          ORDER BY failed_at DESC`
+
+	var sinceUnix int64
+	if !since.IsZero() {
+		sinceUnix = since.Unix()
+	}
+
+	rows, err := s.db.Query(query, sinceUnix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query step failures: %w", err)
+	}
+	defer rows.Close()
+
+	var records []StepFailureRecord
+	for rows.Next() {
+		var r StepFailureRecord
+		var errMsg sql.NullString
+		var failedAt int64
+		if err := rows.Scan(&r.RunID, &r.PipelineName, &r.StepID, &r.FailureCategory, &errMsg, &failedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan step failure: %w", err)
+		}
+		r.ErrorMessage = errMsg.String
+		r.FailedAt = time.Unix(failedAt, 0)
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating step failures: %w", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,66 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveStepFailureCategory(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	require.NoError(t, store.SavePipelineState("run-1", "running", ""))
+	require.NoError(t, store.SaveStepState("run-1", "implement", StateRunning, ""))
+	require.NoError(t, store.SaveStepFailureCategory("run-1", "implement", "timeout"))
+	require.NoError(t, store.SaveStepState("run-1", "implement", StateFailed, "deadline exceeded"))
+
+	steps, err := store.GetStepStates("run-1")
+	require.NoError(t, err)
+	require.Len(t, steps, 1)
+	assert.Equal(t, "timeout", steps[0].FailureCategory)
+
+	// A fresh start of the same step clears the stale category.
+	require.NoError(t, store.SaveStepState("run-1", "implement", StateRunning, ""))
+	steps, err = store.GetStepStates("run-1")
+	require.NoError(t, err)
+	assert.Empty(t, steps[0].FailureCategory)
+}
+
+func TestSaveStepFailureCategory_MissingRowIsNoop(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	assert.NoError(t, store.SaveStepFailureCategory("no-run", "no-step", "timeout"))
+}
+
+func TestListStepFailures(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	runID, err := store.CreateRun("impl-issue", "")
+	require.NoError(t, err)
+	require.NoError(t, store.SavePipelineState(runID, "failed", ""))
+
+	require.NoError(t, store.SaveStepState(runID, "fetch", StateRunning, ""))
+	require.NoError(t, store.SaveStepState(runID, "fetch", StateCompleted, ""))
+
+	require.NoError(t, store.SaveStepState(runID, "implement", StateRunning, ""))
+	require.NoError(t, store.SaveStepFailureCategory(runID, "implement", "contract_violation"))
+	require.NoError(t, store.SaveStepState(runID, "implement", StateFailed, "contract validation failed"))
+
+	failures, err := store.ListStepFailures(time.Time{})
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+	assert.Equal(t, runID, failures[0].RunID)
+	assert.Equal(t, "impl-issue", failures[0].PipelineName)
+	assert.Equal(t, "implement", failures[0].StepID)
+	assert.Equal(t, "contract_violation", failures[0].FailureCategory)
+	assert.Equal(t, "contract validation failed", failures[0].ErrorMessage)
+
+	failures, err = store.ListStepFailures(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+}
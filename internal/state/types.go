@@ -23,6 +23,8 @@ type RunRecord struct {
 	ParentRunID     string   // Parent pipeline run ID (empty for top-level runs)
 	ParentStepID    string   // Step ID in parent pipeline that launched this child run
 	ForkedFromRunID string   // Run ID this was forked from (empty if not a fork)
+	BudgetExceeded  string   // Token budget the run crossed, as reported by the executor (empty = none); set by GetRun only
+	Pinned          bool     // Exempt from cleanup and workspace removal (wave pin)
 
 	// Composition metadata (issue #1450). Set when a parent composition
 	// step (iterate, aggregate, sub_pipeline, branch, loop) launches
@@ -59,6 +61,7 @@ type CheckpointRecord struct {
 	WorkspacePath      string
 	WorkspaceCommitSHA string // Git HEAD SHA for worktree workspaces (empty for non-worktree)
 	ArtifactSnapshot   string // JSON map of "stepID:name" -> path for all artifacts at this point
+	StepResults        string // JSON of the step's output results, empty when it produced none
 	CreatedAt          time.Time
 }
 
@@ -73,6 +76,10 @@ type ListRunsOptions struct {
 	BeforeRunID  string   // Cursor: tie-break for runs at the same timestamp
 	SinceUnix    int64    // Only return runs started after this unix timestamp
 	TopLevelOnly bool     // Only return top-level runs (parent_run_id IS NULL OR ''). Issue #1450 — keeps composition children out of pipeline detail recent-runs lists.
+	// IdempotencyKey only returns runs recorded with this key (see SetRunIdempotencyKey).
+	IdempotencyKey string
+	// PinnedOnly only returns runs pinned with SetRunPinned.
+	PinnedOnly bool
 }
 
 // LogRecord holds an event log entry.
@@ -227,6 +234,8 @@ type Webhook struct {
 	Matcher   string            // regex pattern for step name filtering (empty = all)
 	Headers   map[string]string // custom headers to include
 	Secret    string            // HMAC signing secret
+	Format    string            // payload format: "json" (raw event), "slack", "discord", "teams"
+	Template  string            // message template for chat formats (empty = default)
 	Active    bool
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -2,6 +2,7 @@ package state
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,6 +30,36 @@ func TestCreateWebhook(t *testing.T) {
 	assert.False(t, wh.UpdatedAt.IsZero())
 }
 
+func TestWebhook_FormatAndTemplate(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	// An unset format is stored as the raw JSON event.
+	raw := &Webhook{Name: "raw", URL: "https://example.com/raw", Active: true}
+	rawID, err := store.CreateWebhook(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "json", raw.Format)
+
+	got, err := store.GetWebhook(rawID)
+	require.NoError(t, err)
+	assert.Equal(t, "json", got.Format)
+	assert.Empty(t, got.Template)
+
+	chat := &Webhook{Name: "team-chat", URL: "https://discord.com/api/webhooks/1/x", Format: "discord", Template: "{{.Title}}: {{.PipelineID}}", Active: true}
+	chatID, err := store.CreateWebhook(chat)
+	require.NoError(t, err)
+
+	chat.Format = "teams"
+	require.NoError(t, store.UpdateWebhook(chat))
+
+	all, err := store.ListWebhooks()
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, chatID, all[1].ID)
+	assert.Equal(t, "teams", all[1].Format)
+	assert.Equal(t, "{{.Title}}: {{.PipelineID}}", all[1].Template)
+}
+
 func TestCreateWebhook_MinimalFields(t *testing.T) {
 	store, cleanup := setupTestStore(t)
 	defer cleanup()
@@ -556,3 +587,24 @@ func TestWebhook_IsolatedByID(t *testing.T) {
 	require.Len(t, d2, 1)
 	assert.Equal(t, "step_failed", d2[0].Event)
 }
+
+func TestClaimNotification(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ok, err := store.ClaimNotification("route/sink/step_failed", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, ok, "first claim")
+
+	ok, err = store.ClaimNotification("route/sink/step_failed", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, ok, "repeat within the window")
+
+	ok, err = store.ClaimNotification("route/sink/run_failed", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, ok, "other keys are independent")
+
+	ok, err = store.ClaimNotification("route/sink/step_failed", time.Nanosecond)
+	require.NoError(t, err)
+	assert.True(t, ok, "claim older than the window")
+}
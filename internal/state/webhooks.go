@@ -20,10 +20,10 @@ func (s *stateStore) CreateWebhook(webhook *Webhook) (int64, error) {
 
 	now := time.Now()
 	result, err := s.db.Exec(
-		`INSERT INTO webhooks (name, url, events, matcher, headers, secret, active, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO webhooks (name, url, events, matcher, headers, secret, format, template, active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		webhook.Name, webhook.URL, string(eventsJSON), webhook.Matcher,
-		string(headersJSON), webhook.Secret, webhook.Active, now, now,
+		string(headersJSON), webhook.Secret, webhookFormat(webhook.Format), webhook.Template, webhook.Active, now, now,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create webhook: %w", err)
@@ -35,6 +35,7 @@ func (s *stateStore) CreateWebhook(webhook *Webhook) (int64, error) {
 	}
 
 	webhook.ID = id
+	webhook.Format = webhookFormat(webhook.Format)
 	webhook.CreatedAt = now
 	webhook.UpdatedAt = now
 	return id, nil
@@ -43,7 +44,7 @@ func (s *stateStore) CreateWebhook(webhook *Webhook) (int64, error) {
 // ListWebhooks returns all registered webhooks.
 func (s *stateStore) ListWebhooks() ([]*Webhook, error) {
 	rows, err := s.db.Query(
-		`SELECT id, name, url, events, matcher, headers, secret, active, created_at, updated_at
+		`SELECT id, name, url, events, matcher, headers, secret, format, template, active, created_at, updated_at
 		FROM webhooks ORDER BY id ASC`,
 	)
 	if err != nil {
@@ -57,7 +58,7 @@ func (s *stateStore) ListWebhooks() ([]*Webhook, error) {
 // GetWebhook retrieves a webhook by ID.
 func (s *stateStore) GetWebhook(id int64) (*Webhook, error) {
 	row := s.db.QueryRow(
-		`SELECT id, name, url, events, matcher, headers, secret, active, created_at, updated_at
+		`SELECT id, name, url, events, matcher, headers, secret, format, template, active, created_at, updated_at
 		FROM webhooks WHERE id = ?`,
 		id,
 	)
@@ -82,10 +83,10 @@ func (s *stateStore) UpdateWebhook(webhook *Webhook) error {
 
 	now := time.Now()
 	result, err := s.db.Exec(
-		`UPDATE webhooks SET name = ?, url = ?, events = ?, matcher = ?, headers = ?, secret = ?, active = ?, updated_at = ?
+		`UPDATE webhooks SET name = ?, url = ?, events = ?, matcher = ?, headers = ?, secret = ?, format = ?, template = ?, active = ?, updated_at = ?
 		WHERE id = ?`,
 		webhook.Name, webhook.URL, string(eventsJSON), webhook.Matcher,
-		string(headersJSON), webhook.Secret, webhook.Active, now, webhook.ID,
+		string(headersJSON), webhook.Secret, webhookFormat(webhook.Format), webhook.Template, webhook.Active, now, webhook.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update webhook: %w", err)
@@ -193,6 +194,35 @@ func (s *stateStore) GetWebhookDeliveries(webhookID int64, limit int) ([]*Webhoo
 	return deliveries, nil
 }
 
+// ClaimNotification claims key unless it was claimed within window. The
+// upsert only overwrites a claim older than the window, so concurrent runs
+// deliver a notification once.
+func (s *stateStore) ClaimNotification(key string, window time.Duration) (bool, error) {
+	now := time.Now()
+	result, err := s.db.Exec(
+		`INSERT INTO notification_dedup (dedup_key, sent_at) VALUES (?, ?)
+		ON CONFLICT(dedup_key) DO UPDATE SET sent_at = excluded.sent_at
+		WHERE notification_dedup.sent_at <= ?`,
+		key, now.UnixNano(), now.Add(-window).UnixNano(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim notification: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check notification claim: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// webhookFormat stores an unset payload format as "json", the raw event.
+func webhookFormat(format string) string {
+	if format == "" {
+		return "json"
+	}
+	return format
+}
+
 // scanWebhookRow scans a single webhook row.
 func scanWebhookRow(row *sql.Row) (*Webhook, error) {
 	var w Webhook
@@ -200,7 +230,7 @@ func scanWebhookRow(row *sql.Row) (*Webhook, error) {
 	var active int
 
 	err := row.Scan(&w.ID, &w.Name, &w.URL, &eventsJSON, &w.Matcher,
-		&headersJSON, &w.Secret, &active, &w.CreatedAt, &w.UpdatedAt)
+		&headersJSON, &w.Secret, &w.Format, &w.Template, &active, &w.CreatedAt, &w.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -233,7 +263,7 @@ func scanWebhookRows(rows *sql.Rows) ([]*Webhook, error) {
 		var active int
 
 		err := rows.Scan(&w.ID, &w.Name, &w.URL, &eventsJSON, &w.Matcher,
-			&headersJSON, &w.Secret, &active, &w.CreatedAt, &w.UpdatedAt)
+			&headersJSON, &w.Secret, &w.Format, &w.Template, &active, &w.CreatedAt, &w.UpdatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan webhook row: %w", err)
 		}
@@ -1,5 +1,7 @@
 package state
 
+import "time"
+
 // WebhookStore is the domain-scoped persistence surface for webhook
 // definitions and delivery records. Consumers that only manage webhooks
 // should depend on this interface rather than the aggregate StateStore.
@@ -12,4 +14,8 @@ type WebhookStore interface {
 
 	RecordWebhookDelivery(delivery *WebhookDelivery) error
 	GetWebhookDeliveries(webhookID int64, limit int) ([]*WebhookDelivery, error)
+
+	// ClaimNotification claims the dedup key of a routed notification.
+	// It reports false when the key was claimed within window.
+	ClaimNotification(key string, window time.Duration) (bool, error)
 }
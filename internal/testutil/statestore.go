@@ -49,8 +49,10 @@ type MockStateStore struct {
 	addRunTag                    func(runID, tag string) error
 	removeRunTag                 func(runID, tag string) error
 	updateRunPID                 func(runID string, pid int) error
+	tryAcquireResourceLock       func(name, runID, stepID string) (*state.ResourceLockStatus, error)
 	recordStepAttempt            func(record *state.StepAttemptRecord) error
 	getStepAttempts              func(runID, stepID string) ([]state.StepAttemptRecord, error)
+	listStepReliability          func(pipelineName string, since time.Time) ([]state.StepReliabilityRecord, error)
 	saveChatSession              func(session *state.ChatSession) error
 	getChatSession               func(sessionID string) (*state.ChatSession, error)
 	listChatSessions             func(runID string) ([]state.ChatSession, error)
@@ -170,6 +172,15 @@ func (m *MockStateStore) CreateRunWithLimit(pipelineName, input string, maxConcu
 	return "", nil
 }
 
+func (m *MockStateStore) CreateQueuedRun(ticket, pipelineName, input, priority string, maxConcurrent int) (string, int, error) {
+	runID, err := m.CreateRunWithLimit(pipelineName, input, maxConcurrent)
+	return runID, 0, err
+}
+
+func (m *MockStateStore) LeaveRunQueue(ticket string) error {
+	return nil
+}
+
 func (m *MockStateStore) UpdateRunStatus(runID, status, currentStep string, tokens int) error {
 	if m.updateRunStatus != nil {
 		return m.updateRunStatus(runID, status, currentStep, tokens)
@@ -177,6 +188,22 @@ func (m *MockStateStore) UpdateRunStatus(runID, status, currentStep string, toke
 	return nil
 }
 
+func (m *MockStateStore) SetRunIdempotencyKey(runID, key string) error {
+	return nil
+}
+
+func (m *MockStateStore) SetRunBudgetExceeded(runID, detail string) error {
+	return nil
+}
+
+func (m *MockStateStore) SetRunEnvironment(runID string, env *state.RunEnvironment) error {
+	return nil
+}
+
+func (m *MockStateStore) GetRunEnvironment(runID string) (*state.RunEnvironment, error) {
+	return nil, nil
+}
+
 func (m *MockStateStore) UpdateRunBranch(runID, branch string) error {
 	if m.updateRunBranch != nil {
 		return m.updateRunBranch(runID, branch)
@@ -240,6 +267,18 @@ func (m *MockStateStore) GetArtifacts(runID, stepID string) ([]state.ArtifactRec
 	return nil, nil
 }
 
+func (m *MockStateStore) RequestApproval(runID, stepID, prompt string, deadline time.Time) error {
+	return nil
+}
+
+func (m *MockStateStore) DecideApproval(runID, stepID string, approved bool, decidedBy, reason string) error {
+	return nil
+}
+
+func (m *MockStateStore) GetApproval(runID, stepID string) (*state.ApprovalRecord, error) {
+	return nil, nil
+}
+
 func (m *MockStateStore) RequestCancellation(runID string, force bool) error {
 	if m.requestCancellation != nil {
 		return m.requestCancellation(runID, force)
@@ -324,6 +363,34 @@ func (m *MockStateStore) GetArtifactMetadata(artifactID int64) (*state.ArtifactM
 	return nil, nil
 }
 
+func (m *MockStateStore) SaveContractResult(_ *state.ContractResultRecord) error {
+	return nil
+}
+
+func (m *MockStateStore) GetContractResults(_, _ string) ([]state.ContractResultRecord, error) {
+	return nil, nil
+}
+
+func (m *MockStateStore) ListContractResults(_ time.Time) ([]state.ContractResultRecord, error) {
+	return nil, nil
+}
+
+func (m *MockStateStore) SaveStepChanges(_ *state.StepChangeRecord) error {
+	return nil
+}
+
+func (m *MockStateStore) GetStepChanges(_, _ string) ([]state.StepChangeRecord, error) {
+	return nil, nil
+}
+
+func (m *MockStateStore) RecordAPIAudit(_ *state.APIAuditRecord) error {
+	return nil
+}
+
+func (m *MockStateStore) ListAPIAudit(_ state.APIAuditQueryOptions) ([]state.APIAuditRecord, error) {
+	return nil, nil
+}
+
 func (m *MockStateStore) SetRunTags(runID string, tags []string) error {
 	if m.setRunTags != nil {
 		return m.setRunTags(runID, tags)
@@ -331,6 +398,10 @@ func (m *MockStateStore) SetRunTags(runID string, tags []string) error {
 	return nil
 }
 
+func (m *MockStateStore) SetRunPinned(_ string, _ bool) error {
+	return nil
+}
+
 func (m *MockStateStore) GetRunTags(runID string) ([]string, error) {
 	if m.getRunTags != nil {
 		return m.getRunTags(runID)
@@ -367,6 +438,21 @@ func (m *MockStateStore) ReapOrphans(staleAfter time.Duration) (int, error) {
 	return 0, nil
 }
 
+func (m *MockStateStore) TryAcquireResourceLock(name, runID, stepID string) (*state.ResourceLockStatus, error) {
+	if m.tryAcquireResourceLock != nil {
+		return m.tryAcquireResourceLock(name, runID, stepID)
+	}
+	return &state.ResourceLockStatus{Acquired: true}, nil
+}
+
+func (m *MockStateStore) ReleaseResourceLock(name, runID, stepID string) error {
+	return nil
+}
+
+func (m *MockStateStore) ListResourceLocks() ([]state.ResourceLockRecord, error) {
+	return nil, nil
+}
+
 func (m *MockStateStore) RecordStepAttempt(record *state.StepAttemptRecord) error {
 	if m.recordStepAttempt != nil {
 		return m.recordStepAttempt(record)
@@ -381,6 +467,45 @@ func (m *MockStateStore) GetStepAttempts(runID, stepID string) ([]state.StepAtte
 	return nil, nil
 }
 
+func (m *MockStateStore) SaveStepFailureCategory(pipelineID, stepID, category string) error {
+	return nil
+}
+
+func (m *MockStateStore) ListStepFailures(since time.Time) ([]state.StepFailureRecord, error) {
+	return nil, nil
+}
+
+func (m *MockStateStore) ListStepReliability(pipelineName string, since time.Time) ([]state.StepReliabilityRecord, error) {
+	if m.listStepReliability != nil {
+		return m.listStepReliability(pipelineName, since)
+	}
+	return nil, nil
+}
+
+func (m *MockStateStore) SaveRunProvenance(runID string, defs []state.DefinitionDigest) error {
+	return nil
+}
+
+func (m *MockStateStore) GetRunProvenance(runID string) ([]state.DefinitionDigest, error) {
+	return nil, nil
+}
+
+func (m *MockStateStore) RecordStepCost(rec *state.StepCostRecord) error {
+	return nil
+}
+
+func (m *MockStateStore) GetStepCosts(runID string) ([]state.StepCostRecord, error) {
+	return nil, nil
+}
+
+func (m *MockStateStore) GetRunCosts(runIDs []string) (map[string]float64, error) {
+	return nil, nil
+}
+
+func (m *MockStateStore) GetPipelineCosts() (map[string]float64, error) {
+	return nil, nil
+}
+
 func (m *MockStateStore) SaveChatSession(session *state.ChatSession) error {
 	if m.saveChatSession != nil {
 		return m.saveChatSession(session)
@@ -545,6 +670,10 @@ func (m *MockStateStore) GetWebhookDeliveries(webhookID int64, limit int) ([]*st
 	return nil, nil
 }
 
+func (m *MockStateStore) ClaimNotification(key string, window time.Duration) (bool, error) {
+	return true, nil
+}
+
 func (m *MockStateStore) RecordOutcome(runID, stepID, outcomeType, label, value, description string, metadata map[string]any) error {
 	return nil
 }
@@ -563,10 +692,18 @@ func WithSavePipelineState(fn func(id, status, input string) error) MockStateSto
 	return func(m *MockStateStore) { m.savePipelineState = fn }
 }
 
+func WithTryAcquireResourceLock(fn func(name, runID, stepID string) (*state.ResourceLockStatus, error)) MockStateStoreOption {
+	return func(m *MockStateStore) { m.tryAcquireResourceLock = fn }
+}
+
 func WithCreateRun(fn func(pipelineName, input string) (string, error)) MockStateStoreOption {
 	return func(m *MockStateStore) { m.createRun = fn }
 }
 
+func WithGetRun(fn func(runID string) (*state.RunRecord, error)) MockStateStoreOption {
+	return func(m *MockStateStore) { m.getRun = fn }
+}
+
 // WithRegisterArtifact installs a custom RegisterArtifact handler. Useful in
 // tests that need to assert composition steps register their outputs in the
 // artifact table.
@@ -574,6 +711,12 @@ func WithRegisterArtifact(fn func(runID, stepID, name, path, artifactType string
 	return func(m *MockStateStore) { m.registerArtifact = fn }
 }
 
+// WithListStepReliability installs a custom ListStepReliability handler so
+// tests can feed flaky-step detection a step history.
+func WithListStepReliability(fn func(pipelineName string, since time.Time) ([]state.StepReliabilityRecord, error)) MockStateStoreOption {
+	return func(m *MockStateStore) { m.listStepReliability = fn }
+}
+
 // Orchestration decision stubs
 func (m *MockStateStore) RecordOrchestrationDecision(_ *state.OrchestrationDecision) error {
 	return nil
@@ -667,16 +810,43 @@ func (m *MockStateStore) ListDueSchedules(_ time.Time) ([]state.ScheduleRecord,
 	return nil, nil
 }
 
+// KnowledgeStore stubs.
+func (m *MockStateStore) AddKnowledgeEntry(_ state.KnowledgeEntry) (int64, error) {
+	return 0, nil
+}
+func (m *MockStateStore) ListKnowledgeEntries() ([]state.KnowledgeEntry, error) {
+	return nil, nil
+}
+func (m *MockStateStore) DeleteKnowledgeEntry(_ int64) error {
+	return nil
+}
+
+// AdapterCacheStore stubs.
+func (m *MockStateStore) GetAdapterCacheEntry(_ string, _ time.Time) (*state.AdapterCacheEntry, error) {
+	return nil, nil
+}
+func (m *MockStateStore) PutAdapterCacheEntry(_ state.AdapterCacheEntry) error {
+	return nil
+}
+func (m *MockStateStore) ListAdapterCacheEntries(_ state.AdapterCacheFilter) ([]state.AdapterCacheEntry, error) {
+	return nil, nil
+}
+func (m *MockStateStore) DeleteAdapterCacheEntries(_ state.AdapterCacheFilter) (int, error) {
+	return 0, nil
+}
+
 // Compile-time assertions that *MockStateStore satisfies every domain-scoped
 // state interface as well as the aggregate StateStore. These guard against
 // drift if a method is added to one of the narrow interfaces and missed here.
 var (
-	_ state.RunStore        = (*MockStateStore)(nil)
-	_ state.EventStore      = (*MockStateStore)(nil)
-	_ state.WebhookStore    = (*MockStateStore)(nil)
-	_ state.ChatStore       = (*MockStateStore)(nil)
-	_ state.EvolutionStore  = (*MockStateStore)(nil)
-	_ state.WorksourceStore = (*MockStateStore)(nil)
-	_ state.ScheduleStore   = (*MockStateStore)(nil)
-	_ state.StateStore      = (*MockStateStore)(nil)
+	_ state.RunStore          = (*MockStateStore)(nil)
+	_ state.EventStore        = (*MockStateStore)(nil)
+	_ state.WebhookStore      = (*MockStateStore)(nil)
+	_ state.ChatStore         = (*MockStateStore)(nil)
+	_ state.EvolutionStore    = (*MockStateStore)(nil)
+	_ state.WorksourceStore   = (*MockStateStore)(nil)
+	_ state.ScheduleStore     = (*MockStateStore)(nil)
+	_ state.KnowledgeStore    = (*MockStateStore)(nil)
+	_ state.AdapterCacheStore = (*MockStateStore)(nil)
+	_ state.StateStore        = (*MockStateStore)(nil)
 )
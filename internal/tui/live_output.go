@@ -206,6 +206,10 @@ func formatErrorBlock(evt event.Event) string {
 		sb.WriteString(fmt.Sprintf("  Remediation: %s\n", evt.Remediation))
 	}
 
+	if evt.LogPath != "" {
+		sb.WriteString(fmt.Sprintf("  Adapter log: %s\n", evt.LogPath))
+	}
+
 	if len(evt.RecoveryHints) > 0 {
 		sb.WriteString("  Recovery hints:\n")
 		for _, hint := range evt.RecoveryHints {
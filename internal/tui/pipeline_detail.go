@@ -601,11 +601,21 @@ func renderAvailableDetail(detail *AvailableDetail, width int) string {
 		sb.WriteString("\n")
 		sb.WriteString(sectionStyle.Render(fmt.Sprintf("Steps (%d):", detail.StepCount)))
 		sb.WriteString("\n")
+		lastGroup := ""
 		for i, step := range detail.Steps {
+			// Consecutive steps of a group are listed under one header.
+			indent := "  "
+			if step.Group != "" {
+				if step.Group != lastGroup {
+					fmt.Fprintf(&sb, "  %s\n", labelStyle.Render("["+step.Group+"]"))
+				}
+				indent = "    "
+			}
+			lastGroup = step.Group
 			if step.Persona != "" {
-				fmt.Fprintf(&sb, "  %d. %s (%s)\n", i+1, step.ID, step.Persona)
+				fmt.Fprintf(&sb, "%s%d. %s (%s)\n", indent, i+1, step.ID, step.Persona)
 			} else {
-				fmt.Fprintf(&sb, "  %d. %s\n", i+1, step.ID)
+				fmt.Fprintf(&sb, "%s%d. %s\n", indent, i+1, step.ID)
 			}
 		}
 	}
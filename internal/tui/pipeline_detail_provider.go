@@ -16,6 +16,7 @@ import (
 type StepSummary struct {
 	ID      string
 	Persona string
+	Group   string // Step group ID, empty when ungrouped
 }
 
 // stepTypeLabel returns a descriptive label for composition steps that have no persona.
@@ -152,7 +153,7 @@ func (d *DefaultDetailDataProvider) FetchAvailableDetail(name string) (*Availabl
 			if persona == "" {
 				persona = stepTypeLabel(s)
 			}
-			steps[i] = StepSummary{ID: s.ID, Persona: persona}
+			steps[i] = StepSummary{ID: s.ID, Persona: persona, Group: s.Group}
 		}
 
 		// Collect output artifact names across all steps.
@@ -145,9 +145,11 @@ func (l *PipelineLauncher) LaunchSequence(names []string, input string, parallel
 		_ = l.deps.Store.UpdateRunStatus(groupRunID, "running", "", 0)
 	}
 
-	// Build args: wave compose [--parallel] [--input <input>] <names...>
+	// Build args: wave compose --run <group> [--parallel] [--input <input>] <names...>
 	// With stages: wave compose --parallel A B -- C D
-	args := []string{"compose"}
+	// --run makes the compose process record each pipeline run as a child of
+	// the group run and finalize its status.
+	args := []string{"compose", "--run", groupRunID}
 	if parallel {
 		args = append(args, "--parallel")
 	}
@@ -35,6 +35,12 @@ func (b baseStateStore) CreateRun(string, string) (string, error)
 func (b baseStateStore) CreateRunWithLimit(string, string, int) (string, error) { return "", nil }
 func (b baseStateStore) UpdateRunStatus(string, string, string, int) error      { return nil }
 func (b baseStateStore) UpdateRunBranch(string, string) error                   { return nil }
+func (b baseStateStore) SetRunIdempotencyKey(string, string) error              { return nil }
+func (b baseStateStore) SetRunEnvironment(string, *state.RunEnvironment) error  { return nil }
+func (b baseStateStore) SetRunBudgetExceeded(string, string) error               { return nil }
+func (b baseStateStore) GetRunEnvironment(string) (*state.RunEnvironment, error) {
+	return nil, nil
+}
 func (b baseStateStore) GetRun(string) (*state.RunRecord, error)                { return nil, nil }
 func (b baseStateStore) GetRunningRuns() ([]state.RunRecord, error)             { return nil, nil }
 func (b baseStateStore) ListRuns(state.ListRunsOptions) ([]state.RunRecord, error) {
@@ -53,6 +59,11 @@ func (b baseStateStore) RegisterArtifact(string, string, string, string, string,
 func (b baseStateStore) GetArtifacts(string, string) ([]state.ArtifactRecord, error) {
 	return nil, nil
 }
+func (b baseStateStore) RequestApproval(string, string, string, time.Time) error { return nil }
+func (b baseStateStore) DecideApproval(string, string, bool, string, string) error { return nil }
+func (b baseStateStore) GetApproval(string, string) (*state.ApprovalRecord, error) {
+	return nil, nil
+}
 func (b baseStateStore) RequestCancellation(string, bool) error { return nil }
 func (b baseStateStore) CheckCancellation(string) (*state.CancellationRecord, error) {
 	return nil, nil
@@ -85,20 +96,62 @@ func (b baseStateStore) SaveArtifactMetadata(int64, string, string, string, stri
 func (b baseStateStore) GetArtifactMetadata(int64) (*state.ArtifactMetadataRecord, error) {
 	return nil, nil
 }
+func (b baseStateStore) SaveContractResult(*state.ContractResultRecord) error { return nil }
+func (b baseStateStore) GetContractResults(string, string) ([]state.ContractResultRecord, error) {
+	return nil, nil
+}
+func (b baseStateStore) ListContractResults(time.Time) ([]state.ContractResultRecord, error) {
+	return nil, nil
+}
+func (b baseStateStore) SaveStepChanges(*state.StepChangeRecord) error { return nil }
+func (b baseStateStore) GetStepChanges(string, string) ([]state.StepChangeRecord, error) {
+	return nil, nil
+}
+func (b baseStateStore) RecordAPIAudit(*state.APIAuditRecord) error { return nil }
+func (b baseStateStore) ListAPIAudit(state.APIAuditQueryOptions) ([]state.APIAuditRecord, error) {
+	return nil, nil
+}
 func (b baseStateStore) SetRunTags(string, []string) error   { return nil }
 func (b baseStateStore) GetRunTags(string) ([]string, error) { return nil, nil }
+func (b baseStateStore) SetRunPinned(string, bool) error     { return nil }
 func (b baseStateStore) AddRunTag(string, string) error      { return nil }
 func (b baseStateStore) RemoveRunTag(string, string) error   { return nil }
 func (b baseStateStore) UpdateRunPID(string, int) error      { return nil }
 func (b baseStateStore) UpdateRunHeartbeat(string) error                { return nil }
 func (b baseStateStore) ReapOrphans(time.Duration) (int, error)         { return 0, nil }
+func (b baseStateStore) CreateQueuedRun(string, string, string, string, int) (string, int, error) {
+	return "", 0, nil
+}
+func (b baseStateStore) LeaveRunQueue(string) error { return nil }
+func (b baseStateStore) TryAcquireResourceLock(string, string, string) (*state.ResourceLockStatus, error) {
+	return &state.ResourceLockStatus{Acquired: true}, nil
+}
+func (b baseStateStore) ReleaseResourceLock(string, string, string) error { return nil }
+func (b baseStateStore) ListResourceLocks() ([]state.ResourceLockRecord, error) {
+	return nil, nil
+}
 func (b baseStateStore) RecordStepAttempt(*state.StepAttemptRecord) error {
 	return nil
 }
 func (b baseStateStore) GetStepAttempts(string, string) ([]state.StepAttemptRecord, error) {
 	return nil, nil
 }
-func (b baseStateStore) SaveChatSession(*state.ChatSession) error { return nil }
+func (b baseStateStore) SaveStepFailureCategory(string, string, string) error { return nil }
+func (b baseStateStore) ListStepReliability(string, time.Time) ([]state.StepReliabilityRecord, error) {
+	return nil, nil
+}
+func (b baseStateStore) ListStepFailures(time.Time) ([]state.StepFailureRecord, error) {
+	return nil, nil
+}
+func (b baseStateStore) SaveRunProvenance(string, []state.DefinitionDigest) error { return nil }
+func (b baseStateStore) GetRunProvenance(string) ([]state.DefinitionDigest, error) {
+	return nil, nil
+}
+func (b baseStateStore) RecordStepCost(*state.StepCostRecord) error          { return nil }
+func (b baseStateStore) GetStepCosts(string) ([]state.StepCostRecord, error) { return nil, nil }
+func (b baseStateStore) GetRunCosts([]string) (map[string]float64, error)    { return nil, nil }
+func (b baseStateStore) GetPipelineCosts() (map[string]float64, error)       { return nil, nil }
+func (b baseStateStore) SaveChatSession(*state.ChatSession) error            { return nil }
 func (b baseStateStore) GetChatSession(string) (*state.ChatSession, error) {
 	return nil, errors.New("not found")
 }
@@ -150,6 +203,7 @@ func (b baseStateStore) RecordWebhookDelivery(*state.WebhookDelivery) error { re
 func (b baseStateStore) GetWebhookDeliveries(int64, int) ([]*state.WebhookDelivery, error) {
 	return nil, nil
 }
+func (b baseStateStore) ClaimNotification(string, time.Duration) (bool, error) { return true, nil }
 func (b baseStateStore) RecordOutcome(string, string, string, string, string, string, map[string]any) error {
 	return nil
 }
@@ -220,6 +274,23 @@ func (b baseStateStore) ListDueSchedules(time.Time) ([]state.ScheduleRecord, err
 	return nil, nil
 }
 
+// KnowledgeStore stubs.
+func (b baseStateStore) AddKnowledgeEntry(state.KnowledgeEntry) (int64, error) { return 0, nil }
+func (b baseStateStore) ListKnowledgeEntries() ([]state.KnowledgeEntry, error) { return nil, nil }
+func (b baseStateStore) DeleteKnowledgeEntry(int64) error                      { return nil }
+
+// AdapterCacheStore stubs.
+func (b baseStateStore) GetAdapterCacheEntry(string, time.Time) (*state.AdapterCacheEntry, error) {
+	return nil, nil
+}
+func (b baseStateStore) PutAdapterCacheEntry(state.AdapterCacheEntry) error { return nil }
+func (b baseStateStore) ListAdapterCacheEntries(state.AdapterCacheFilter) ([]state.AdapterCacheEntry, error) {
+	return nil, nil
+}
+func (b baseStateStore) DeleteAdapterCacheEntries(state.AdapterCacheFilter) (int, error) {
+	return 0, nil
+}
+
 // Compile-time check: baseStateStore must satisfy state.StateStore.
 var _ state.StateStore = baseStateStore{}
 
@@ -1,19 +1,8 @@
 package tui
 
-import (
-	"os"
-	"syscall"
-)
+import "github.com/recinq/wave/internal/procutil"
 
 // IsProcessAlive checks whether a process with the given PID is still running.
 func IsProcessAlive(pid int) bool {
-	if pid <= 0 {
-		return false
-	}
-	proc, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-	err = proc.Signal(syscall.Signal(0))
-	return err == nil
+	return procutil.Alive(pid)
 }
@@ -0,0 +1,44 @@
+package webui
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/recinq/wave/internal/state"
+)
+
+// API audit actions recorded in the api_audit table.
+const (
+	auditRunStarted      = "run_started"
+	auditRunRetried      = "run_retried"
+	auditRunResumed      = "run_resumed"
+	auditRunForked       = "run_forked"
+	auditRunRewound      = "run_rewound"
+	auditRunCancelled    = "run_cancelled"
+	auditGateResolved    = "gate_resolved"
+	auditProposalApprove = "proposal_approved"
+	auditProposalReject  = "proposal_rejected"
+	auditPipelineRevert  = "pipeline_rolled_back"
+	auditEmergencyStop   = "emergency_stop"
+	auditPipelineDisable = "pipeline_disabled"
+	auditPipelineEnable  = "pipeline_enabled"
+)
+
+// recordAudit appends a control action by the caller of r to the API audit
+// trail. Actor defaults to the request principal; callers authenticated
+// outside the middleware (Slack) set it themselves. Failures are logged
+// and never fail the request.
+func (s *Server) recordAudit(r *http.Request, rec state.APIAuditRecord) {
+	if s.runtime.rwStore == nil {
+		return
+	}
+	p := requestPrincipal(r)
+	if rec.Actor == "" {
+		rec.Actor = p.Subject
+		rec.Role = string(p.Role)
+	}
+	rec.RemoteAddr = r.RemoteAddr
+	if err := s.runtime.rwStore.RecordAPIAudit(&rec); err != nil {
+		log.Printf("[webui] failed to record %s audit entry: %v", rec.Action, err)
+	}
+}
@@ -2,15 +2,58 @@ package webui
 
 import (
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/hex"
 	"net/http"
+	"strings"
 )
 
+// APIToken is a bearer token bound to a role. Name identifies the caller
+// on gate decisions and cancellations as "token:<name>".
+type APIToken struct {
+	Name      string
+	Token     string
+	Role      Role
+	RateLimit int // mutating requests per minute; 0 uses the server limit
+}
+
 // requiresAuth returns true if the server binding requires authentication.
 func (s *Server) requiresAuth() bool {
 	return s.transport.bind != "127.0.0.1" && s.transport.bind != "localhost" && s.transport.bind != ""
 }
 
+// requestToken returns the bearer token from the Authorization header, or
+// from the token query parameter (for SSE and browser access).
+func requestToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return r.URL.Query().Get("token")
+}
+
+// tokenPrincipal returns the caller a bearer token identifies in bearer
+// mode, or nil. The server token grants admin.
+func (s *Server) tokenPrincipal(token string) *Principal {
+	if token != "" && s.auth.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.auth.token)) == 1 {
+		return localPrincipal
+	}
+	return s.apiTokenPrincipal(token)
+}
+
+// apiTokenPrincipal returns the caller an API token identifies, or nil.
+// API tokens are accepted in every auth mode that reads bearer tokens.
+func (s *Server) apiTokenPrincipal(token string) *Principal {
+	if token == "" {
+		return nil
+	}
+	for _, t := range s.auth.apiTokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t.Token)) == 1 {
+			return &Principal{Subject: "token:" + t.Name, Role: t.Role, RateLimit: t.RateLimit}
+		}
+	}
+	return nil
+}
+
 // GenerateToken generates a random 32-byte hex token.
 func GenerateToken() (string, error) {
 	bytes := make([]byte, 32)
@@ -0,0 +1,168 @@
+package webui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/recinq/wave/internal/manifest"
+	"github.com/recinq/wave/internal/pipeline"
+)
+
+// configPollInterval is how often the server checks wave.yaml and the
+// pipeline directory for edits.
+const configPollInterval = 2 * time.Second
+
+// configWatcher tracks the files the server's configuration is read from so
+// edits can be detected by polling, without a platform-specific notifier.
+type configWatcher struct {
+	manifestPath string
+	pipelinesDir string
+	// fingerprint is the size and mtime of every watched file at the last
+	// reload attempt, valid or not, so a broken edit is reported once.
+	fingerprint string
+}
+
+func newConfigWatcher(manifestPath, pipelinesDir string) *configWatcher {
+	w := &configWatcher{manifestPath: manifestPath, pipelinesDir: pipelinesDir}
+	w.fingerprint = w.scan()
+	return w
+}
+
+// scan fingerprints the manifest and every pipeline file. A missing file
+// contributes nothing, so deleting one counts as a change.
+func (w *configWatcher) scan() string {
+	paths := []string{w.manifestPath}
+	if entries, err := os.ReadDir(w.pipelinesDir); err == nil {
+		for _, e := range entries {
+			if !e.IsDir() && strings.HasSuffix(e.Name(), ".yaml") {
+				paths = append(paths, filepath.Join(w.pipelinesDir, e.Name()))
+			}
+		}
+	}
+	sort.Strings(paths[1:])
+
+	var b strings.Builder
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d\n", p, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String()
+}
+
+// changed reports whether any watched file was added, removed or modified
+// since the last call.
+func (w *configWatcher) changed() bool {
+	fp := w.scan()
+	if fp == w.fingerprint {
+		return false
+	}
+	w.fingerprint = fp
+	return true
+}
+
+// currentManifest returns the manifest the server is running with. Runs
+// launched in-process keep the manifest they started with across reloads.
+func (s *Server) currentManifest() *manifest.Manifest {
+	s.runtime.manifestMu.RLock()
+	defer s.runtime.manifestMu.RUnlock()
+	return s.runtime.manifest
+}
+
+// watchConfig reloads configuration whenever the watched files change or
+// the process receives SIGHUP.
+func (s *Server) watchConfig(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if s.runtime.configWatcher.changed() {
+				s.logReload(s.reloadConfig())
+			}
+		case <-hup:
+			s.runtime.configWatcher.changed()
+			s.logReload(s.reloadConfig())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Server) logReload(err error) {
+	if err != nil {
+		log.Printf("[webui] config reload rejected, keeping previous configuration: %v", err)
+		return
+	}
+	log.Printf("[webui] reloaded %s and pipelines", s.runtime.configWatcher.manifestPath)
+}
+
+// reloadConfig validates the manifest and every pipeline file and, when all
+// of them are valid, swaps in the new manifest. On any error the running
+// configuration is left untouched. Pipelines are read from disk when a run
+// starts, so validating them here surfaces a broken edit immediately
+// instead of at the next launch. Server settings (bind address, auth, TLS)
+// and the run ID format are only read at startup.
+func (s *Server) reloadConfig() error {
+	w := s.runtime.configWatcher
+	m, err := manifest.Load(w.manifestPath)
+	if err != nil {
+		return fmt.Errorf("%s: %w", w.manifestPath, err)
+	}
+	if err := validatePipelineDir(w.pipelinesDir); err != nil {
+		return err
+	}
+
+	s.runtime.manifestMu.Lock()
+	s.runtime.manifest = m
+	s.runtime.manifestMu.Unlock()
+
+	// Cached API responses may embed manifest-derived data.
+	s.assets.cache.Clear()
+	return nil
+}
+
+// validatePipelineDir parses and validates every pipeline file in dir,
+// joining the errors of all invalid ones.
+func validatePipelineDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	loader := &pipeline.YAMLPipelineLoader{}
+	var errs []error
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".yaml") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		if _, err := loader.Unmarshal(data); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
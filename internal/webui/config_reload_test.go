@@ -0,0 +1,107 @@
+package webui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const reloadManifest = `apiVersion: v1
+kind: WaveManifest
+metadata:
+  name: reload-test
+adapters:
+  claude:
+    binary: claude
+    mode: headless
+runtime:
+  workspace_root: ./workspace
+  stall_timeout: %s
+`
+
+const reloadPipeline = `kind: WavePipeline
+metadata:
+  name: hello
+steps:
+  - id: greet
+    persona: navigator
+    exec:
+      type: prompt
+      source: say hello
+`
+
+func TestReloadConfig(t *testing.T) {
+	srv, _ := testServer(t)
+	srv.assets.cache = newAPICache(time.Minute)
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "wave.yaml")
+	pipelinesDir := filepath.Join(dir, "pipelines")
+	if err := os.MkdirAll(pipelinesDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(manifestPath, fmt.Sprintf(reloadManifest, "5m"))
+	write(filepath.Join(pipelinesDir, "hello.yaml"), reloadPipeline)
+
+	srv.runtime.configWatcher = newConfigWatcher(manifestPath, pipelinesDir)
+	if srv.runtime.configWatcher.changed() {
+		t.Fatal("watcher reported a change before any edit")
+	}
+
+	if err := srv.reloadConfig(); err != nil {
+		t.Fatalf("reloadConfig() = %v", err)
+	}
+	if got := srv.currentManifest().Runtime.StallTimeout; got != "5m" {
+		t.Fatalf("stall timeout = %q, want 5m", got)
+	}
+	srv.assets.cache.Set("issues:1", "cached")
+
+	// An edited manifest is swapped in and cached responses dropped.
+	write(manifestPath, fmt.Sprintf(reloadManifest, "10m"))
+	if !srv.runtime.configWatcher.changed() {
+		t.Fatal("watcher missed the manifest edit")
+	}
+	if err := srv.reloadConfig(); err != nil {
+		t.Fatalf("reloadConfig() = %v", err)
+	}
+	if got := srv.currentManifest().Runtime.StallTimeout; got != "10m" {
+		t.Errorf("stall timeout = %q, want 10m", got)
+	}
+	if _, ok := srv.assets.cache.Get("issues:1"); ok {
+		t.Error("cache was not cleared on reload")
+	}
+
+	// A broken pipeline rejects the reload, even alongside a valid manifest.
+	write(manifestPath, fmt.Sprintf(reloadManifest, "20m"))
+	write(filepath.Join(pipelinesDir, "broken.yaml"), "kind: WavePipeline\nsteps: [\n")
+	if !srv.runtime.configWatcher.changed() {
+		t.Fatal("watcher missed the new pipeline file")
+	}
+	err := srv.reloadConfig()
+	if err == nil || !strings.Contains(err.Error(), "broken.yaml") {
+		t.Fatalf("reloadConfig() = %v, want error naming broken.yaml", err)
+	}
+	if got := srv.currentManifest().Runtime.StallTimeout; got != "10m" {
+		t.Errorf("stall timeout = %q after rejected reload, want 10m", got)
+	}
+
+	// An invalid manifest is rejected too.
+	if err := os.Remove(filepath.Join(pipelinesDir, "broken.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	write(manifestPath, "apiVersion: v1\nkind: WaveManifest\nmetadata: [\n")
+	if err := srv.reloadConfig(); err == nil {
+		t.Fatal("reloadConfig() accepted an invalid manifest")
+	}
+	if got := srv.currentManifest().Runtime.StallTimeout; got != "10m" {
+		t.Errorf("stall timeout = %q after rejected reload, want 10m", got)
+	}
+}
@@ -6,6 +6,7 @@ import "strings"
 type DAGLayout struct {
 	Nodes  []DAGLayoutNode
 	Edges  []DAGLayoutEdge
+	Groups []DAGLayoutGroup
 	Width  int
 	Height int
 }
@@ -27,10 +28,21 @@ type DAGLayoutNode struct {
 	GateChoices string // Comma-separated gate choice labels
 	EdgeInfo    string // Serialized edge conditions for conditional steps
 	Thread      string // Thread group ID for conversation continuity
+	Group       string // Step group ID; members render as one collapsible unit
 	X           int
 	Y           int
 }
 
+// DAGLayoutGroup is the bounding box drawn around the nodes of a step group.
+type DAGLayoutGroup struct {
+	ID        string
+	StepCount int
+	X         int
+	Y         int
+	Width     int
+	Height    int
+}
+
 // DAGLayoutEdge is an edge with computed bezier curve points for SVG.
 type DAGLayoutEdge struct {
 	From       string
@@ -54,6 +66,8 @@ const (
 	nodeGapY   = 80  // vertical gap between nodes in the same layer (rows)
 	paddingX   = 20
 	paddingY   = 20
+	groupPad   = 8  // gap between a group box and its member nodes
+	groupLabel = 12 // extra space above a group box's nodes for its label
 )
 
 // ComputeDAGLayout takes pipeline step definitions and step progress,
@@ -118,6 +132,7 @@ func ComputeDAGLayout(steps []DAGStepInput) *DAGLayout {
 				GateChoices: s.GateChoices,
 				EdgeInfo:    s.EdgeInfo,
 				Thread:      s.Thread,
+				Group:       s.Group,
 				X:           x,
 				Y:           y,
 			})
@@ -133,6 +148,8 @@ func ComputeDAGLayout(steps []DAGStepInput) *DAGLayout {
 		layout.Height = nodeHeight + paddingY*2
 	}
 
+	layout.Groups = computeDAGGroups(layout.Nodes)
+
 	// Build node position map and layer index for edge computation
 	nodePos := make(map[string][2]int)
 	nodeLayerIdx := make(map[string]int)
@@ -245,6 +262,32 @@ func ComputeDAGLayout(steps []DAGStepInput) *DAGLayout {
 	return layout
 }
 
+// computeDAGGroups returns one bounding box per step group, in order of
+// first appearance. Boxes stay inside the layout padding.
+func computeDAGGroups(nodes []DAGLayoutNode) []DAGLayoutGroup {
+	var groups []DAGLayoutGroup
+	index := make(map[string]int)
+	for _, n := range nodes {
+		if n.Group == "" {
+			continue
+		}
+		x0, y0 := n.X-groupPad, n.Y-groupPad-groupLabel
+		x1, y1 := n.X+nodeWidth+groupPad, n.Y+nodeHeight+groupPad
+		i, ok := index[n.Group]
+		if !ok {
+			index[n.Group] = len(groups)
+			groups = append(groups, DAGLayoutGroup{ID: n.Group, StepCount: 1, X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0})
+			continue
+		}
+		g := &groups[i]
+		g.StepCount++
+		gx1, gy1 := g.X+g.Width, g.Y+g.Height
+		g.X, g.Y = min(g.X, x0), min(g.Y, y0)
+		g.Width, g.Height = max(gx1, x1)-g.X, max(gy1, y1)-g.Y
+	}
+	return groups
+}
+
 // DAGStepInput is the input for DAG layout computation.
 type DAGStepInput struct {
 	ID           string
@@ -262,6 +305,7 @@ type DAGStepInput struct {
 	GateChoices  string
 	EdgeInfo     string
 	Thread       string         // Thread group ID for conversation continuity
+	Group        string         // Step group ID
 	Edges        []DAGEdgeInput // Outgoing edges for graph-mode routing
 }
 
@@ -312,3 +312,30 @@ func TestAssignLayers(t *testing.T) {
 		t.Errorf("expected third layer to contain only 'd', got %v", layers[2])
 	}
 }
+
+func TestComputeDAGLayout_Groups(t *testing.T) {
+	steps := []DAGStepInput{
+		{ID: "plan", Persona: "navigator"},
+		{ID: "implement", Persona: "craftsman", Group: "build", Dependencies: []string{"plan"}},
+		{ID: "fix-lint", Persona: "craftsman", Group: "build", Dependencies: []string{"implement"}},
+		{ID: "review", Persona: "reviewer", Dependencies: []string{"fix-lint"}},
+	}
+
+	layout := ComputeDAGLayout(steps)
+	if len(layout.Groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(layout.Groups))
+	}
+	g := layout.Groups[0]
+	if g.ID != "build" || g.StepCount != 2 {
+		t.Errorf("group = %+v, want build with 2 steps", g)
+	}
+	for _, n := range layout.Nodes {
+		inside := n.X >= g.X && n.Y >= g.Y && n.X+nodeWidth <= g.X+g.Width && n.Y+nodeHeight <= g.Y+g.Height
+		if (n.Group == "build") != inside {
+			t.Errorf("node %s (group %q) inside group box = %v", n.ID, n.Group, inside)
+		}
+	}
+	if g.Y < 0 || g.X < 0 {
+		t.Errorf("group box %+v extends outside the layout", g)
+	}
+}
@@ -12,10 +12,10 @@ func registerWebhooks(r *FeatureRegistry) {
 		mux.HandleFunc("GET /webhooks/{id}", s.handleWebhookDetailPage)
 		// API
 		mux.HandleFunc("GET /api/webhooks", s.handleAPIWebhooks)
-		mux.HandleFunc("POST /api/webhooks", s.handleAPICreateWebhook)
+		mux.HandleFunc("POST /api/webhooks", s.requireRole(RoleAdmin, s.handleAPICreateWebhook))
 		mux.HandleFunc("GET /api/webhooks/{id}", s.handleAPIWebhookDetail)
-		mux.HandleFunc("PUT /api/webhooks/{id}", s.handleAPIUpdateWebhook)
-		mux.HandleFunc("DELETE /api/webhooks/{id}", s.handleAPIDeleteWebhook)
+		mux.HandleFunc("PUT /api/webhooks/{id}", s.requireRole(RoleAdmin, s.handleAPIUpdateWebhook))
+		mux.HandleFunc("DELETE /api/webhooks/{id}", s.requireRole(RoleAdmin, s.handleAPIDeleteWebhook))
 		mux.HandleFunc("POST /api/webhooks/{id}/test", s.handleAPITestWebhook)
 	})
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/recinq/wave/internal/config"
+	"github.com/recinq/wave/internal/state"
 )
 
 // --- Page Handler ---
@@ -55,8 +56,9 @@ func (s *Server) handleAPIAdminConfig(w http.ResponseWriter, _ *http.Request) {
 	}
 
 	wsRoot := ".agents/workspaces"
-	if s.runtime.manifest != nil && s.runtime.manifest.Runtime.WorkspaceRoot != "" {
-		wsRoot = s.runtime.manifest.Runtime.WorkspaceRoot
+	m := s.currentManifest()
+	if m != nil && m.Runtime.WorkspaceRoot != "" {
+		wsRoot = m.Runtime.WorkspaceRoot
 	}
 
 	resp := adminConfigResponse{
@@ -104,7 +106,7 @@ type emergencyStopResponse struct {
 }
 
 // handleAPIEmergencyStop handles POST /api/admin/emergency-stop.
-func (s *Server) handleAPIEmergencyStop(w http.ResponseWriter, _ *http.Request) {
+func (s *Server) handleAPIEmergencyStop(w http.ResponseWriter, r *http.Request) {
 	runs, err := s.runtime.store.GetRunningRuns()
 	if err != nil {
 		writeJSONError(w, http.StatusInternalServerError, "failed to get running runs: "+err.Error())
@@ -123,6 +125,8 @@ func (s *Server) handleAPIEmergencyStop(w http.ResponseWriter, _ *http.Request)
 		if err := s.runtime.rwStore.RequestCancellation(run.RunID, true); err != nil {
 			continue // best-effort: skip runs that fail to cancel
 		}
+		_ = s.runtime.rwStore.LogEvent(run.RunID, "", "cancel_requested", "", "emergency stop by "+s.requestIdentity(r), 0, 0, "", "", "")
+		s.recordAudit(r, state.APIAuditRecord{Action: auditEmergencyStop, RunID: run.RunID, PipelineName: run.PipelineName})
 		cancelledIDs = append(cancelledIDs, run.RunID)
 	}
 
@@ -153,6 +157,7 @@ func (s *Server) handleDisablePipeline(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	s.realtime.disabledPipelines[name] = true
 	s.mu.Unlock()
+	s.recordAudit(r, state.APIAuditRecord{Action: auditPipelineDisable, PipelineName: name})
 
 	writeJSON(w, http.StatusOK, pipelineToggleResponse{Name: name, Disabled: true})
 }
@@ -168,6 +173,7 @@ func (s *Server) handleEnablePipeline(w http.ResponseWriter, r *http.Request) {
 	s.mu.Lock()
 	delete(s.realtime.disabledPipelines, name)
 	s.mu.Unlock()
+	s.recordAudit(r, state.APIAuditRecord{Action: auditPipelineEnable, PipelineName: name})
 
 	writeJSON(w, http.StatusOK, pipelineToggleResponse{Name: name, Disabled: false})
 }
@@ -205,6 +211,8 @@ var auditEventStates = []string{
 	"run_failed",
 	"step_failed",
 	"gate_requested",
+	"gate_resolved",
+	"cancel_requested",
 }
 
 // handleAPIAdminAudit handles GET /api/admin/audit.
@@ -1,10 +1,12 @@
 package webui
 
 import (
+	"encoding/json"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/recinq/wave/internal/redact"
 )
@@ -30,10 +32,12 @@ func (s *Server) handleArtifact(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var found *ArtifactSummary
+	var artifactID int64
 	for _, a := range artifacts {
 		if a.Name == name {
 			as := artifactToSummary(a)
 			found = &as
+			artifactID = a.ID
 			break
 		}
 	}
@@ -61,16 +65,57 @@ func (s *Server) handleArtifact(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Binary and directory artifacts (and anything that is not valid text)
+	// are only offered as a download, never rendered as a preview.
+	opaque := found.Type == "binary" || found.Type == "directory" || !utf8.Valid(content)
+
 	// Check for raw download
 	if r.URL.Query().Get("raw") == "true" {
-		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-		w.Header().Set("Content-Disposition", "attachment; filename=\""+name+"\"")
+		filename := name
+		if opaque {
+			w.Header().Set("Content-Type", "application/octet-stream")
+			if strings.HasSuffix(cleanPath, ".tar.zst") {
+				filename += ".tar.zst"
+			}
+		} else {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		}
+		w.Header().Set("Content-Disposition", "attachment; filename=\""+filename+"\"")
 		if _, err := w.Write(content); err != nil {
 			return
 		}
 		return
 	}
 
+	// Prefer the MIME type and checksum indexed at registration time.
+	mimeType, checksum := "", ""
+	if meta, err := s.runtime.store.GetArtifactMetadata(artifactID); err == nil && meta != nil {
+		mimeType = meta.MimeType
+		var fields struct {
+			SHA256 string `json:"sha256"`
+		}
+		if json.Unmarshal([]byte(meta.MetadataJSON), &fields) == nil {
+			checksum = fields.SHA256
+		}
+	}
+
+	if opaque {
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		writeJSON(w, http.StatusOK, ArtifactContentResponse{
+			Metadata: ArtifactMetadata{
+				Name:              name,
+				Type:              found.Type,
+				SizeBytes:         found.SizeBytes,
+				MimeType:          mimeType,
+				SHA256:            checksum,
+				PreviewSuppressed: true,
+			},
+		})
+		return
+	}
+
 	// Truncate if too large
 	truncated := false
 	if len(content) > maxArtifactSize {
@@ -81,8 +126,10 @@ func (s *Server) handleArtifact(w http.ResponseWriter, r *http.Request) {
 	// Redact credentials (no HTML escaping — this is a JSON API, not HTML template)
 	redacted := redact.Redact(string(content))
 
-	// Determine MIME type
-	mimeType := detectMimeType(name)
+	// Fall back to guessing the MIME type from the name
+	if mimeType == "" {
+		mimeType = detectMimeType(name)
+	}
 
 	resp := ArtifactContentResponse{
 		Content: redacted,
@@ -92,6 +139,7 @@ func (s *Server) handleArtifact(w http.ResponseWriter, r *http.Request) {
 			SizeBytes: found.SizeBytes,
 			Truncated: truncated,
 			MimeType:  mimeType,
+			SHA256:    checksum,
 		},
 	}
 
@@ -403,3 +403,106 @@ func TestHandleArtifact_HTMLEscaping(t *testing.T) {
 		t.Errorf("expected raw content %q, got %q", content, resp.Content)
 	}
 }
+
+func TestHandleArtifact_BinaryPreviewSuppressed(t *testing.T) {
+	srv, rwStore := testServer(t)
+
+	dir := t.TempDir()
+	artifactPath := filepath.Join(dir, "build.tar.zst")
+	data := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 0xff, 0xfe}
+	if err := os.WriteFile(artifactPath, data, 0600); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+
+	runID, err := rwStore.CreateRun("test-pipeline", "input")
+	if err != nil {
+		t.Fatalf("failed to create run: %v", err)
+	}
+	if err := rwStore.RegisterArtifact(runID, "step-1", "build", artifactPath, "directory", int64(len(data))); err != nil {
+		t.Fatalf("failed to register artifact: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/runs/"+runID+"/artifacts/step-1/build", nil)
+	req.SetPathValue("id", runID)
+	req.SetPathValue("step", "step-1")
+	req.SetPathValue("name", "build")
+	rec := httptest.NewRecorder()
+	srv.handleArtifact(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: body=%s", rec.Code, rec.Body.String())
+	}
+	var resp ArtifactContentResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Content != "" {
+		t.Errorf("expected no preview content, got %q", resp.Content)
+	}
+	if !resp.Metadata.PreviewSuppressed {
+		t.Error("expected PreviewSuppressed=true")
+	}
+
+	// Raw download still serves the archive bytes.
+	req = httptest.NewRequest("GET", "/api/runs/"+runID+"/artifacts/step-1/build?raw=true", nil)
+	req.SetPathValue("id", runID)
+	req.SetPathValue("step", "step-1")
+	req.SetPathValue("name", "build")
+	rec = httptest.NewRecorder()
+	srv.handleArtifact(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/octet-stream" {
+		t.Errorf("expected Content-Type application/octet-stream, got %q", ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, "build.tar.zst") {
+		t.Errorf("expected archive filename in Content-Disposition, got %q", cd)
+	}
+	if rec.Body.String() != string(data) {
+		t.Error("expected raw archive bytes")
+	}
+}
+
+func TestHandleArtifact_UsesIndexedMetadata(t *testing.T) {
+	srv, rwStore := testServer(t)
+
+	dir := t.TempDir()
+	artifactPath := filepath.Join(dir, "stdout")
+	content := `{"ok": true}`
+	if err := os.WriteFile(artifactPath, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+
+	runID, err := rwStore.CreateRun("test-pipeline", "input")
+	if err != nil {
+		t.Fatalf("failed to create run: %v", err)
+	}
+	if err := rwStore.RegisterArtifact(runID, "step-1", "result", artifactPath, "json", int64(len(content))); err != nil {
+		t.Fatalf("failed to register artifact: %v", err)
+	}
+	records, err := rwStore.GetArtifacts(runID, "step-1")
+	if err != nil || len(records) != 1 {
+		t.Fatalf("failed to get artifacts: %v", err)
+	}
+	if err := rwStore.SaveArtifactMetadata(records[0].ID, runID, "step-1", content, "application/json", "utf-8", `{"sha256":"abc123"}`); err != nil {
+		t.Fatalf("failed to save metadata: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/runs/"+runID+"/artifacts/step-1/result", nil)
+	req.SetPathValue("id", runID)
+	req.SetPathValue("step", "step-1")
+	req.SetPathValue("name", "result")
+	rec := httptest.NewRecorder()
+	srv.handleArtifact(rec, req)
+
+	var resp ArtifactContentResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	// The name has no extension, so only the index knows this is JSON.
+	if resp.Metadata.MimeType != "application/json" {
+		t.Errorf("expected indexed mime type application/json, got %q", resp.Metadata.MimeType)
+	}
+	if resp.Metadata.SHA256 != "abc123" {
+		t.Errorf("expected sha256 abc123, got %q", resp.Metadata.SHA256)
+	}
+}
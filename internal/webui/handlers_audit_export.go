@@ -0,0 +1,98 @@
+package webui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+)
+
+// handleExportAPIAudit handles GET /api/admin/audit/export - exports the API
+// audit trail (who started, cancelled and approved runs) as CSV or JSON.
+// Filters: actor, action, run, and since (RFC 3339 time or a duration such
+// as "24h").
+func (s *Server) handleExportAPIAudit(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format != "csv" && format != "json" {
+		writeJSONError(w, http.StatusBadRequest, "format must be 'csv' or 'json'")
+		return
+	}
+
+	opts := state.APIAuditQueryOptions{
+		Actor:  q.Get("actor"),
+		Action: q.Get("action"),
+		RunID:  q.Get("run"),
+		Limit:  10000, // reasonable upper bound for export
+	}
+	if v := q.Get("since"); v != "" {
+		since, err := parseAuditSince(v, time.Now())
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "since must be an RFC 3339 time or a duration such as 24h")
+			return
+		}
+		opts.Since = since
+	}
+
+	records, err := s.runtime.store.ListAPIAudit(opts)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to query API audit log")
+		return
+	}
+
+	switch format {
+	case "csv":
+		exportAPIAuditCSV(w, records)
+	case "json":
+		exportAPIAuditJSON(w, records)
+	}
+}
+
+// parseAuditSince accepts an absolute RFC 3339 time or a duration before now.
+func parseAuditSince(v string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(v); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// exportAPIAuditCSV writes audit records as a CSV download.
+func exportAPIAuditCSV(w http.ResponseWriter, records []state.APIAuditRecord) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=wave-audit.csv")
+
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	_ = writer.Write([]string{"id", "created_at", "actor", "role", "action", "run_id", "pipeline", "step_id", "detail", "remote_addr"})
+	for _, rec := range records {
+		_ = writer.Write([]string{
+			strconv.FormatInt(rec.ID, 10),
+			rec.CreatedAt.UTC().Format(time.RFC3339),
+			rec.Actor,
+			rec.Role,
+			rec.Action,
+			rec.RunID,
+			rec.PipelineName,
+			rec.StepID,
+			rec.Detail,
+			rec.RemoteAddr,
+		})
+	}
+}
+
+// exportAPIAuditJSON writes audit records as a JSON array download.
+func exportAPIAuditJSON(w http.ResponseWriter, records []state.APIAuditRecord) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=wave-audit.json")
+
+	if records == nil {
+		records = []state.APIAuditRecord{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(records)
+}
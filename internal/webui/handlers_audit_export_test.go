@@ -0,0 +1,86 @@
+package webui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+)
+
+func TestHandleExportAPIAudit(t *testing.T) {
+	srv, rwStore := testServer(t)
+
+	runID, _ := rwStore.CreateRun("test-pipeline", "input")
+	if err := rwStore.UpdateRunStatus(runID, "running", "", 0); err != nil {
+		t.Fatalf("failed to update run status: %v", err)
+	}
+	req := httptest.NewRequest("POST", "/api/runs/"+runID+"/cancel", strings.NewReader(`{}`))
+	req.SetPathValue("id", runID)
+	req = withPrincipal(req, &Principal{Subject: "token:ci", Role: RoleOperator})
+	srv.handleCancelRun(httptest.NewRecorder(), req)
+	if err := rwStore.RecordAPIAudit(&state.APIAuditRecord{Actor: "alice@example.com", Role: "admin", Action: auditPipelineDisable, PipelineName: "test-pipeline"}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("json filtered by actor", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		srv.handleExportAPIAudit(rec, httptest.NewRequest("GET", "/api/admin/audit/export?format=json&actor=token:ci", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+		}
+		var records []state.APIAuditRecord
+		if err := json.Unmarshal(rec.Body.Bytes(), &records); err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 1 {
+			t.Fatalf("got %d records, want 1: %+v", len(records), records)
+		}
+		got := records[0]
+		if got.Action != auditRunCancelled || got.RunID != runID || got.Role != "operator" || got.PipelineName != "test-pipeline" {
+			t.Errorf("record = %+v", got)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		srv.handleExportAPIAudit(rec, httptest.NewRequest("GET", "/api/admin/audit/export?format=csv&since=1h", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+		}
+		if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, "wave-audit.csv") {
+			t.Errorf("Content-Disposition = %q", cd)
+		}
+		rows, err := csv.NewReader(rec.Body).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 3 || rows[0][2] != "actor" {
+			t.Fatalf("rows = %v, want header plus 2 records", rows)
+		}
+	})
+
+	t.Run("bad input", func(t *testing.T) {
+		for _, q := range []string{"format=xml", "format=json&since=yesterday"} {
+			rec := httptest.NewRecorder()
+			srv.handleExportAPIAudit(rec, httptest.NewRequest("GET", "/api/admin/audit/export?"+q, nil))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s: got %d, want 400", q, rec.Code)
+			}
+		}
+	})
+}
+
+func TestParseAuditSince(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	if got, err := parseAuditSince("24h", now); err != nil || !got.Equal(now.Add(-24*time.Hour)) {
+		t.Errorf("parseAuditSince(24h) = %v, %v", got, err)
+	}
+	if got, err := parseAuditSince("2026-01-01T00:00:00Z", now); err != nil || got.Day() != 1 {
+		t.Errorf("parseAuditSince(RFC3339) = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,153 @@
+package webui
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/state"
+)
+
+// pipelineStatus is the last top-level run of a pipeline, as served by
+// GET /api/status and rendered by the /badge endpoint.
+type pipelineStatus struct {
+	Pipeline    string     `json:"pipeline"`
+	Status      string     `json:"status"`                 // passing, failing, running, cancelled or unknown
+	RunStatus   string     `json:"run_status,omitempty"`   // raw status of the last run
+	RunID       string     `json:"run_id,omitempty"`       // empty when the pipeline has never run
+	StartedAt   *time.Time `json:"started_at,omitempty"`   // nil when the pipeline has never run
+	CompletedAt *time.Time `json:"completed_at,omitempty"` // nil while running
+}
+
+// badgeColors maps a badge status to its shields.io-style color.
+var badgeColors = map[string]string{
+	"passing":   "#4c1",
+	"failing":   "#e05d44",
+	"running":   "#007ec6",
+	"cancelled": "#9f9f9f",
+	"unknown":   "#9f9f9f",
+}
+
+// isStatusPath reports whether path is a badge or status endpoint, which
+// ServerConfig.PublicStatus exposes without authentication.
+func isStatusPath(path string) bool {
+	return strings.HasPrefix(path, "/badge/") || path == "/api/status" || strings.HasPrefix(path, "/api/status/")
+}
+
+// lastPipelineStatus looks up the most recent top-level run of pipeline.
+func (s *Server) lastPipelineStatus(pipeline string) (pipelineStatus, error) {
+	st := pipelineStatus{Pipeline: pipeline, Status: "unknown"}
+	runs, err := s.runtime.store.ListRuns(state.ListRunsOptions{PipelineName: pipeline, TopLevelOnly: true, Limit: 1})
+	if err != nil {
+		return st, err
+	}
+	if len(runs) == 0 {
+		return st, nil
+	}
+	run := runs[0]
+	st.RunID = run.RunID
+	st.RunStatus = run.Status
+	st.StartedAt = &run.StartedAt
+	st.CompletedAt = run.CompletedAt
+	switch run.Status {
+	case "completed":
+		st.Status = "passing"
+	case "failed":
+		st.Status = "failing"
+	case "running", "pending":
+		st.Status = "running"
+	case "cancelled":
+		st.Status = "cancelled"
+	}
+	return st, nil
+}
+
+// handleAPIStatus handles GET /api/status - the last run of every pipeline.
+func (s *Server) handleAPIStatus(w http.ResponseWriter, r *http.Request) {
+	names := listPipelineNames()
+	sort.Strings(names)
+	statuses := make([]pipelineStatus, 0, len(names))
+	for _, name := range names {
+		st, err := s.lastPipelineStatus(name)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "failed to query runs")
+			return
+		}
+		statuses = append(statuses, st)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"pipelines": statuses})
+}
+
+// handleAPIPipelineStatus handles GET /api/status/{name} - the last run of
+// one pipeline.
+func (s *Server) handleAPIPipelineStatus(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if name == "" {
+		writeJSONError(w, http.StatusBadRequest, "missing pipeline name")
+		return
+	}
+	st, err := s.lastPipelineStatus(name)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "failed to query runs")
+		return
+	}
+	writeJSON(w, http.StatusOK, st)
+}
+
+// handleBadge handles GET /badge/{file} - an SVG status badge for the
+// pipeline named by file, e.g. /badge/nightly-audit.svg. The label defaults
+// to the pipeline name and can be overridden with ?label=.
+func (s *Server) handleBadge(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("file"), ".svg")
+	if !ok || name == "" {
+		http.NotFound(w, r)
+		return
+	}
+	st, err := s.lastPipelineStatus(name)
+	if err != nil {
+		http.Error(w, "failed to query runs", http.StatusInternalServerError)
+		return
+	}
+	label := r.URL.Query().Get("label")
+	if label == "" {
+		label = name
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	// Badge proxies such as GitHub's camo cache aggressively; ask them not to.
+	w.Header().Set("Cache-Control", "no-cache, max-age=0")
+	_, _ = w.Write([]byte(renderBadge(label, st.Status)))
+}
+
+// renderBadge draws a flat two-part badge in the style of shields.io. Text
+// widths are estimated from the character count, which is close enough for
+// the 11px sans-serif font badges use.
+func renderBadge(label, status string) string {
+	color := badgeColors[status]
+	if color == "" {
+		color = badgeColors["unknown"]
+	}
+	lw := badgeTextWidth(label)
+	sw := badgeTextWidth(status)
+	total := lw + sw
+	label, status = html.EscapeString(label), html.EscapeString(status)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`+
+		`<title>%s: %s</title>`+
+		`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`+
+		`<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+
+		`<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%d" y="14">%s</text><text x="%d" y="14">%s</text></g></svg>`,
+		total, label, status,
+		label, status,
+		total,
+		lw, lw, sw, color, total,
+		lw/2, label, lw+sw/2, status)
+}
+
+func badgeTextWidth(s string) int {
+	return len([]rune(s))*7 + 10
+}
@@ -0,0 +1,95 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleBadge(t *testing.T) {
+	srv, rwStore := testServer(t)
+
+	runID, _ := rwStore.CreateRun("nightly-audit", "")
+	if err := rwStore.UpdateRunStatus(runID, "completed", "", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path       string
+		wantCode   int
+		wantInBody []string
+	}{
+		{"/badge/nightly-audit.svg", http.StatusOK, []string{"<svg", "nightly-audit", "passing", "#4c1"}},
+		{"/badge/nightly-audit.svg?label=nightly%20%3Caudit%3E", http.StatusOK, []string{"nightly &lt;audit&gt;: passing"}},
+		{"/badge/never-run.svg", http.StatusOK, []string{"unknown"}},
+		{"/badge/nightly-audit.png", http.StatusNotFound, nil},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		req.SetPathValue("file", strings.TrimPrefix(req.URL.Path, "/badge/"))
+		rec := httptest.NewRecorder()
+		srv.handleBadge(rec, req)
+		if rec.Code != tt.wantCode {
+			t.Errorf("%s: got %d, want %d", tt.path, rec.Code, tt.wantCode)
+			continue
+		}
+		for _, want := range tt.wantInBody {
+			if !strings.Contains(rec.Body.String(), want) {
+				t.Errorf("%s: body missing %q", tt.path, want)
+			}
+		}
+	}
+}
+
+func TestHandleAPIPipelineStatus(t *testing.T) {
+	srv, rwStore := testServer(t)
+
+	runID, _ := rwStore.CreateRun("nightly-audit", "")
+	if err := rwStore.UpdateRunStatus(runID, "failed", "boom", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/status/nightly-audit", nil)
+	req.SetPathValue("name", "nightly-audit")
+	rec := httptest.NewRecorder()
+	srv.handleAPIPipelineStatus(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+	var st pipelineStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Status != "failing" || st.RunStatus != "failed" || st.RunID != runID || st.StartedAt == nil {
+		t.Errorf("status = %+v, want the failed run %s", st, runID)
+	}
+}
+
+func TestPublicStatus(t *testing.T) {
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	for _, public := range []bool{false, true} {
+		s := &Server{
+			auth:      serverAuth{token: "secret", publicStatus: public},
+			transport: serverTransport{bind: "0.0.0.0"},
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /badge/{file}", ok)
+		mux.HandleFunc("GET /api/status", ok)
+		mux.HandleFunc("GET /api/runs", ok)
+		handler := s.applyMiddleware(mux)
+
+		for path, wantPublic := range map[string]bool{"/badge/x.svg": true, "/api/status": true, "/api/runs": false} {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+			want := http.StatusUnauthorized
+			if public && wantPublic {
+				want = http.StatusOK
+			}
+			if rec.Code != want {
+				t.Errorf("public_status=%v %s: got %d, want %d", public, path, rec.Code, want)
+			}
+		}
+	}
+}
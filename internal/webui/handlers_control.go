@@ -57,6 +57,7 @@ func (s *Server) handleStartPipeline(w http.ResponseWriter, r *http.Request) {
 	} else {
 		s.launchPipelineExecution(runID, name, req.Input, opts)
 	}
+	s.recordAudit(r, state.APIAuditRecord{Action: auditRunStarted, RunID: runID, PipelineName: name})
 
 	writeJSON(w, http.StatusCreated, StartPipelineResponse{
 		RunID:        runID,
@@ -100,6 +101,13 @@ func (s *Server) handleCancelRun(w http.ResponseWriter, r *http.Request) {
 		writeJSONError(w, http.StatusInternalServerError, "failed to request cancellation")
 		return
 	}
+	// Record who cancelled in the run's event log, shown in the audit log.
+	_ = s.runtime.rwStore.LogEvent(runID, "", "cancel_requested", "", "cancellation requested by "+s.requestIdentity(r), 0, 0, "", "", "")
+	detail := ""
+	if req.Force {
+		detail = "force"
+	}
+	s.recordAudit(r, state.APIAuditRecord{Action: auditRunCancelled, RunID: runID, PipelineName: run.PipelineName, Detail: detail})
 
 	status := "cancelling"
 	if req.Force {
@@ -139,6 +147,7 @@ func (s *Server) handleRetryRun(w http.ResponseWriter, r *http.Request) {
 	}
 
 	s.launchPipelineExecution(newRunID, originalRun.PipelineName, originalRun.Input, config.RuntimeConfig{})
+	s.recordAudit(r, state.APIAuditRecord{Action: auditRunRetried, RunID: newRunID, PipelineName: originalRun.PipelineName, Detail: "retry of " + runID})
 
 	writeJSON(w, http.StatusCreated, RetryRunResponse{
 		RunID:         newRunID,
@@ -206,6 +215,7 @@ func (s *Server) handleResumeRun(w http.ResponseWriter, r *http.Request) {
 	}
 
 	s.launchPipelineExecution(newRunID, originalRun.PipelineName, originalRun.Input, config.RuntimeConfig{}, req.FromStep)
+	s.recordAudit(r, state.APIAuditRecord{Action: auditRunResumed, RunID: newRunID, PipelineName: originalRun.PipelineName, StepID: req.FromStep, Detail: "resume of " + runID})
 
 	writeJSON(w, http.StatusCreated, ResumeRunResponse{
 		RunID:         newRunID,
@@ -257,6 +267,7 @@ func (s *Server) handleSubmitRun(w http.ResponseWriter, r *http.Request) {
 	} else {
 		s.launchPipelineExecution(runID, req.Pipeline, req.Input, opts)
 	}
+	s.recordAudit(r, state.APIAuditRecord{Action: auditRunStarted, RunID: runID, PipelineName: req.Pipeline})
 
 	writeJSON(w, http.StatusCreated, SubmitRunResponse{
 		RunID:        runID,
@@ -335,46 +346,60 @@ func (s *Server) handleGateApprove(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if s.realtime.gateRegistry == nil {
-		writeJSONError(w, http.StatusServiceUnavailable, "gate registry not initialized")
+	choice, status, err := s.resolveGate(runID, stepID, req.Choice, req.Text, s.requestIdentity(r))
+	if err != nil {
+		writeJSONError(w, status, err.Error())
 		return
 	}
+	s.recordAudit(r, state.APIAuditRecord{Action: auditGateResolved, RunID: runID, StepID: stepID, Detail: choice.Key})
+
+	writeJSON(w, http.StatusOK, GateApproveResponse{
+		RunID:  runID,
+		StepID: stepID,
+		Choice: choice.Key,
+		Label:  choice.Label,
+	})
+}
+
+// resolveGate validates a decision against the run's pending gate and hands
+// it to the waiting executor. On failure it returns the HTTP status that
+// fits the error. Shared by the dashboard and Slack interaction endpoints.
+func (s *Server) resolveGate(runID, stepID, choiceKey, text, approver string) (*runner.WebUIGateChoice, int, error) {
+	if s.realtime.gateRegistry == nil {
+		return nil, http.StatusServiceUnavailable, fmt.Errorf("gate registry not initialized")
+	}
 
 	gate := s.realtime.gateRegistry.GetPending(runID)
 	if gate == nil {
-		writeJSONError(w, http.StatusNotFound, "no pending gate for this run")
-		return
+		return nil, http.StatusNotFound, fmt.Errorf("no pending gate for this run")
 	}
 
 	pendingStepID := s.realtime.gateRegistry.GetPendingStepID(runID)
 	if pendingStepID != "" && pendingStepID != stepID {
-		writeJSONError(w, http.StatusConflict,
-			fmt.Sprintf("step mismatch: pending gate is for step %q, not %q", pendingStepID, stepID))
-		return
+		return nil, http.StatusConflict, fmt.Errorf("step mismatch: pending gate is for step %q, not %q", pendingStepID, stepID)
 	}
 
-	choice := gate.FindChoiceByKey(req.Choice)
+	choice := gate.FindChoiceByKey(choiceKey)
 	if choice == nil {
-		writeJSONError(w, http.StatusBadRequest, "invalid choice key: "+req.Choice)
-		return
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid choice key: %s", choiceKey)
 	}
 
 	decision := &runner.WebUIGateDecision{
-		Choice: choice.Key,
-		Label:  choice.Label,
-		Text:   req.Text,
-		Target: choice.Target,
+		Choice:   choice.Key,
+		Label:    choice.Label,
+		Text:     text,
+		Target:   choice.Target,
+		Approver: approver,
 	}
-
 	if err := s.realtime.gateRegistry.Resolve(runID, decision); err != nil {
-		writeJSONError(w, http.StatusConflict, err.Error())
-		return
+		return nil, http.StatusConflict, err
 	}
+	return choice, http.StatusOK, nil
+}
 
-	writeJSON(w, http.StatusOK, GateApproveResponse{
-		RunID:  runID,
-		StepID: stepID,
-		Choice: choice.Key,
-		Label:  choice.Label,
-	})
+// requestIdentity names the caller behind a request for the audit log:
+// the token, JWT or OIDC subject the auth middleware identified, or
+// "dashboard" when authentication is off.
+func (s *Server) requestIdentity(r *http.Request) string {
+	return requestPrincipal(r).Subject
 }
@@ -175,6 +175,38 @@ func TestHandleCancelRun_WrongState(t *testing.T) {
 	}
 }
 
+func TestHandleCancelRun_RecordsRequester(t *testing.T) {
+	srv, rwStore := testServer(t)
+
+	runID, _ := rwStore.CreateRun("test-pipeline", "input")
+	if err := rwStore.UpdateRunStatus(runID, "running", "", 0); err != nil {
+		t.Fatalf("failed to update run status: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/api/runs/"+runID+"/cancel", strings.NewReader(`{}`))
+	req.SetPathValue("id", runID)
+	req = withPrincipal(req, &Principal{Subject: "token:ci", Role: RoleOperator})
+	rec := httptest.NewRecorder()
+	srv.handleCancelRun(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	events, err := rwStore.GetEvents(runID, state.EventQueryOptions{})
+	if err != nil {
+		t.Fatalf("failed to get events: %v", err)
+	}
+	var recorded bool
+	for _, ev := range events {
+		if ev.State == "cancel_requested" && strings.Contains(ev.Message, "token:ci") {
+			recorded = true
+		}
+	}
+	if !recorded {
+		t.Errorf("expected a cancel_requested event naming token:ci, got %+v", events)
+	}
+}
+
 func TestHandleRetryRun_WrongState(t *testing.T) {
 	srv, rwStore := testServer(t)
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/recinq/wave/internal/config"
 	"github.com/recinq/wave/internal/runner"
+	"github.com/recinq/wave/internal/state"
 )
 
 // handleForkRun handles POST /api/runs/{id}/fork
@@ -49,6 +50,7 @@ func (s *Server) handleForkRun(w http.ResponseWriter, r *http.Request) {
 		writeJSONError(w, http.StatusBadRequest, "fork failed: "+err.Error())
 		return
 	}
+	s.recordAudit(r, state.APIAuditRecord{Action: auditRunForked, RunID: newRunID, PipelineName: originalRun.PipelineName, StepID: req.FromStep, Detail: "fork of " + runID})
 
 	resumeStep, err := fc.ResumeStepAfter(originalRun.PipelineName, req.FromStep)
 	if err != nil {
@@ -140,6 +142,8 @@ func (s *Server) handleRewindRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordAudit(r, state.APIAuditRecord{Action: auditRunRewound, RunID: runID, PipelineName: run.PipelineName, StepID: req.ToStep})
+
 	writeJSON(w, http.StatusOK, RewindRunResponse{
 		RunID:        runID,
 		ToStep:       req.ToStep,
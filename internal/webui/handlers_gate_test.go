@@ -192,7 +192,7 @@ func TestHandleGateApprove_Success(t *testing.T) {
 	// The channel should have received the decision
 	select {
 	case d := <-ch:
-		if d.Choice != "a" || d.Target != "implement" {
+		if d.Choice != "a" || d.Target != "implement" || d.Approver != "dashboard" {
 			t.Errorf("unexpected decision: %+v", d)
 		}
 	default:
@@ -31,7 +31,7 @@ func (s *Server) handleAPIHealth(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) getHealthListData() HealthListResponse {
-	provider := health.NewDefaultDataProvider(s.runtime.manifest, s.runtime.store, ".agents/pipelines")
+	provider := health.NewDefaultDataProvider(s.currentManifest(), s.runtime.store, ".agents/pipelines")
 
 	var checks []HealthCheckResult
 	for _, name := range provider.CheckNames() {
@@ -99,6 +99,7 @@ func (s *Server) handleAPIStartFromIssue(w http.ResponseWriter, r *http.Request)
 	} else {
 		s.launchPipelineExecution(runID, req.PipelineName, req.IssueURL, opts)
 	}
+	s.recordAudit(r, state.APIAuditRecord{Action: auditRunStarted, RunID: runID, PipelineName: req.PipelineName, Detail: req.IssueURL})
 
 	writeJSON(w, http.StatusCreated, StartPipelineResponse{
 		RunID:        runID,
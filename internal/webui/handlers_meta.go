@@ -5,8 +5,9 @@ import "net/http"
 // handleAPIAdapters handles GET /api/adapters — returns available adapter names.
 func (s *Server) handleAPIAdapters(w http.ResponseWriter, r *http.Request) {
 	var names []string
-	if s.runtime.manifest != nil {
-		for name := range s.runtime.manifest.Adapters {
+	m := s.currentManifest()
+	if m != nil {
+		for name := range m.Adapters {
 			names = append(names, name)
 		}
 	}
@@ -29,8 +30,8 @@ func (s *Server) handleAPIModels(w http.ResponseWriter, r *http.Request) {
 	add("cheapest")
 	add("balanced")
 	add("strongest")
-	if s.runtime.manifest != nil {
-		for _, a := range s.runtime.manifest.Adapters {
+	if mf := s.currentManifest(); mf != nil {
+		for _, a := range mf.Adapters {
 			add(a.DefaultModel)
 			for _, m := range a.TierModels {
 				add(m)
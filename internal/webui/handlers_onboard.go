@@ -358,8 +358,9 @@ func (s *Server) runOnboardSession(sess *webOnboardSession, factory func() onboa
 		OutputPath: "wave.yaml",
 		UI:         bridge,
 	}
-	if s.runtime.manifest != nil && s.runtime.manifest.Runtime.WorkspaceRoot != "" {
-		opts.Workspace = s.runtime.manifest.Runtime.WorkspaceRoot
+	m := s.currentManifest()
+	if m != nil && m.Runtime.WorkspaceRoot != "" {
+		opts.Workspace = m.Runtime.WorkspaceRoot
 	}
 
 	_, err := svc.StartSession(sess.ctx, projectDir, opts)
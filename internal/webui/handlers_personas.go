@@ -39,12 +39,13 @@ func (s *Server) handlePersonaDetailPage(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if s.runtime.manifest == nil || s.runtime.manifest.Personas == nil {
+	m := s.currentManifest()
+	if m == nil || m.Personas == nil {
 		http.Error(w, "persona not found", http.StatusNotFound)
 		return
 	}
 
-	p, ok := s.runtime.manifest.Personas[name]
+	p, ok := m.Personas[name]
 	if !ok {
 		http.Error(w, "persona not found", http.StatusNotFound)
 		return
@@ -110,12 +111,13 @@ func (s *Server) handlePersonaDetailPage(w http.ResponseWriter, r *http.Request)
 
 // getPersonaSummaries returns persona summaries from the manifest.
 func (s *Server) getPersonaSummaries() []PersonaSummary {
-	if s.runtime.manifest == nil || s.runtime.manifest.Personas == nil {
+	m := s.currentManifest()
+	if m == nil || m.Personas == nil {
 		return nil
 	}
 
 	var personas []PersonaSummary
-	for name, p := range s.runtime.manifest.Personas {
+	for name, p := range m.Personas {
 		var prompt string
 		if p.SystemPromptFile != "" {
 			promptPath := p.GetSystemPromptPath(s.runtime.repoDir)
@@ -132,6 +132,7 @@ type PipelineDetailStep struct {
 	Prompt             string   `json:"prompt,omitempty"`
 	SubPipeline        string   `json:"sub_pipeline,omitempty"`
 	Thread             string   `json:"thread,omitempty"`
+	Group              string   `json:"group,omitempty"`
 	Depth              int      `json:"depth,omitempty"` // DAG depth for indentation
 	Script             string   `json:"script,omitempty"`
 	GatePrompt         string   `json:"gate_prompt,omitempty"`
@@ -187,6 +188,9 @@ func buildPipelineDetail(name string, p *pipeline.Pipeline) PipelineDetail {
 		var inputArtifacts []string
 		for _, ia := range step.Memory.InjectArtifacts {
 			ref := ia.Step + "/" + ia.Artifact
+			if ia.FromPipeline != "" {
+				ref = ia.FromPipeline + ":" + ia.Artifact
+			}
 			if ia.As != "" {
 				ref += " as " + ia.As
 			}
@@ -311,6 +315,7 @@ func buildPipelineDetail(name string, p *pipeline.Pipeline) PipelineDetail {
 			Prompt:             prompt,
 			SubPipeline:        stripUnresolvedVars(resolveForgeVars(step.SubPipeline)),
 			Thread:             step.Thread,
+			Group:              step.Group,
 			Script:             script,
 			GatePrompt:         gatePrompt,
 			GateType:           gateType,
@@ -437,6 +442,7 @@ func (s *Server) handlePipelineDetailPage(w http.ResponseWriter, r *http.Request
 			Contract:     contract,
 			Artifacts:    strings.Join(artifactNames, ", "),
 			Dependencies: step.Dependencies,
+			Group:        step.Group,
 		})
 	}
 	stripExcludedDeps(dagSteps, excludedSteps)
@@ -214,6 +214,8 @@ func (s *Server) handleProposalApprove(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordAudit(r, state.APIAuditRecord{Action: auditProposalApprove, PipelineName: rec.PipelineName, Detail: fmt.Sprintf("proposal %d as %s", rec.ID, decidedBy)})
+
 	writeJSON(w, http.StatusOK, proposalDecisionResponse{
 		ID:          rec.ID,
 		Status:      string(state.ProposalApproved),
@@ -250,6 +252,8 @@ func (s *Server) handleProposalReject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordAudit(r, state.APIAuditRecord{Action: auditProposalReject, Detail: fmt.Sprintf("proposal %d as %s", id, decidedBy)})
+
 	writeJSON(w, http.StatusOK, proposalDecisionResponse{
 		ID:        id,
 		Status:    string(state.ProposalRejected),
@@ -293,6 +297,7 @@ func (s *Server) handleProposalRollback(w http.ResponseWriter, r *http.Request)
 	if decidedBy == "" {
 		decidedBy = "webui"
 	}
+	s.recordAudit(r, state.APIAuditRecord{Action: auditPipelineRevert, PipelineName: pipelineName, Detail: fmt.Sprintf("v%d -> v%d as %s", current.Version, prior.Version, decidedBy)})
 	writeJSON(w, http.StatusOK, map[string]any{
 		"pipeline_name":    pipelineName,
 		"rolled_back_from": current.Version,
@@ -529,6 +529,7 @@ func (s *Server) handleAPIStartFromPR(w http.ResponseWriter, r *http.Request) {
 	} else {
 		s.launchPipelineExecution(runID, req.PipelineName, req.PRURL, opts)
 	}
+	s.recordAudit(r, state.APIAuditRecord{Action: auditRunStarted, RunID: runID, PipelineName: req.PipelineName, Detail: req.PRURL})
 
 	writeJSON(w, http.StatusCreated, StartPipelineResponse{
 		RunID:        runID,
@@ -58,6 +58,14 @@ func (m *mockForgeClient) CreatePullRequestReview(context.Context, string, strin
 	return forge.ErrNotSupported
 }
 
+func (m *mockForgeClient) CreateIssue(context.Context, string, string, forge.CreateIssueRequest) (*forge.Issue, error) {
+	return nil, forge.ErrNotSupported
+}
+
+func (m *mockForgeClient) CreateIssueComment(context.Context, string, string, int, string) error {
+	return forge.ErrNotSupported
+}
+
 func (m *mockForgeClient) ForgeType() forge.ForgeType {
 	return forge.ForgeGitHub
 }
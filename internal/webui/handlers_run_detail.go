@@ -297,8 +297,9 @@ func (s *Server) buildTemplateVars(input string) map[string]string {
 	templateVars["forge.type"] = string(forgeInfo.Type)
 	templateVars["forge.pr_term"] = forgeInfo.PRTerm
 	templateVars["forge.pr_command"] = forgeInfo.PRCommand
-	if s.runtime.manifest != nil && s.runtime.manifest.Project != nil {
-		for k, v := range s.runtime.manifest.Project.ProjectVars() {
+	m := s.currentManifest()
+	if m != nil && m.Project != nil {
+		for k, v := range m.Project.ProjectVars() {
 			templateVars["project."+k] = v
 		}
 	}
@@ -309,14 +310,15 @@ func (s *Server) buildTemplateVars(input string) map[string]string {
 // (timeout, stall timeout) for the run-detail page.
 func (s *Server) buildRunConfigItems() []struct{ Label, Value, Tooltip string } {
 	var items []struct{ Label, Value, Tooltip string }
-	if s.runtime.manifest == nil {
+	m := s.currentManifest()
+	if m == nil {
 		return items
 	}
-	if timeout := s.runtime.manifest.Runtime.GetDefaultTimeout(); timeout > 0 {
+	if timeout := m.Runtime.GetDefaultTimeout(); timeout > 0 {
 		items = append(items, struct{ Label, Value, Tooltip string }{"Timeout", timeout.String(), "Maximum duration per step before it is cancelled"})
 	}
-	if s.runtime.manifest.Runtime.StallTimeout != "" {
-		items = append(items, struct{ Label, Value, Tooltip string }{"Stall timeout", s.runtime.manifest.Runtime.StallTimeout, "Step is cancelled if no tool activity for this duration"})
+	if m.Runtime.StallTimeout != "" {
+		items = append(items, struct{ Label, Value, Tooltip string }{"Stall timeout", m.Runtime.StallTimeout, "Step is cancelled if no tool activity for this duration"})
 	}
 	return items
 }
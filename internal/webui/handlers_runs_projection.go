@@ -329,6 +329,11 @@ func (s *Server) buildStepDetails(runID, pipelineName string, runStatus ...strin
 			injectRefs := make([]InputArtifactRef, 0, len(step.Memory.InjectArtifacts))
 			pairs := make([]string, 0, len(step.Memory.InjectArtifacts))
 			for _, ia := range step.Memory.InjectArtifacts {
+				if ia.FromPipeline != "" {
+					// Lives in another run, so there is no step chip to link.
+					pairs = append(pairs, ia.FromPipeline+":"+ia.Artifact)
+					continue
+				}
 				injectRefs = append(injectRefs, InputArtifactRef{Step: ia.Step, Name: ia.Artifact})
 				pairs = append(pairs, ia.Step+"/"+ia.Artifact)
 			}
@@ -428,6 +433,21 @@ func (s *Server) buildStepDetails(runID, pipelineName string, runStatus ...strin
 		}
 		sd.Artifacts = deduplicateArtifacts(arts)
 
+		contractResults, crErr := s.runtime.store.GetContractResults(runID, step.ID)
+		if crErr != nil {
+			log.Printf("[webui] failed to get contract results for run %s step %s: %v", runID, step.ID, crErr)
+		}
+		for _, cr := range contractResults {
+			sd.ContractResults = append(sd.ContractResults, ContractOutcome{
+				Type:       cr.ContractType,
+				Name:       cr.Name,
+				Passed:     cr.Passed,
+				Message:    cr.Message,
+				Violations: cr.Violations,
+				DurationMs: cr.DurationMs,
+			})
+		}
+
 		// If the run is terminal but step still shows running, override to cancelled
 		rs := ""
 		if len(runStatus) > 0 {
@@ -322,11 +322,12 @@ func (s *Server) handleAPISkillRunInstall(w http.ResponseWriter, r *http.Request
 	// that pipeline-driven shellouts use. The webui historically called
 	// `exec.CommandContext("sh", "-c", ...)` directly, bypassing both.
 	cfg := sandbox.Config{}
-	if s.runtime.manifest != nil {
-		cfg.Backend = sandbox.SandboxBackendType(s.runtime.manifest.Runtime.Sandbox.ResolveBackend())
-		cfg.DockerImage = s.runtime.manifest.Runtime.Sandbox.DockerImage
-		cfg.AllowedDomains = s.runtime.manifest.Runtime.Sandbox.DefaultAllowedDomains
-		cfg.EnvPassthrough = s.runtime.manifest.Runtime.Sandbox.EnvPassthrough
+	m := s.currentManifest()
+	if m != nil {
+		cfg.Backend = sandbox.SandboxBackendType(m.Runtime.Sandbox.ResolveBackend())
+		cfg.DockerImage = m.Runtime.Sandbox.DockerImage
+		cfg.AllowedDomains = m.Runtime.Sandbox.DefaultAllowedDomains
+		cfg.EnvPassthrough = m.Runtime.Sandbox.EnvPassthrough
 	}
 	out, err := sandbox.RunShell(ctx, installCmd, cfg)
 	if err != nil {
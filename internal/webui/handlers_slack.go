@@ -0,0 +1,176 @@
+package webui
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/recinq/wave/internal/hooks"
+	"github.com/recinq/wave/internal/httpx"
+	"github.com/recinq/wave/internal/state"
+)
+
+// slackInteractionsPath receives Slack interactivity callbacks. Slack cannot
+// send the dashboard's bearer token or CSRF header, so the auth middleware
+// lets this path through and the handler verifies Slack's request signature
+// instead.
+const slackInteractionsPath = "/api/slack/interactions"
+
+// slackMaxSkew bounds the age of a signed Slack request to prevent replays.
+const slackMaxSkew = 5 * time.Minute
+
+// slackHTTPClient posts decision updates back to Slack's response_url.
+// Package variable so tests can swap it.
+var slackHTTPClient = httpx.New(httpx.Config{
+	Timeout:    10 * time.Second,
+	MaxRetries: 1,
+})
+
+// slackInteraction is the subset of a Slack block_actions payload the gate
+// buttons need.
+type slackInteraction struct {
+	Type string `json:"type"`
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Name     string `json:"name"`
+	} `json:"user"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+	ResponseURL string `json:"response_url"`
+}
+
+// approver names the Slack user for the audit log, e.g. "slack:alice".
+func (i *slackInteraction) approver() string {
+	name := i.User.Username
+	if name == "" {
+		name = i.User.Name
+	}
+	if name == "" {
+		name = i.User.ID
+	}
+	return "slack:" + name
+}
+
+// handleSlackInteraction handles POST /api/slack/interactions: a click on
+// an Approve/Reject button of a Slack gate_requested message. The button
+// value names the run, step and choice; the clicking Slack user is recorded
+// as the approver.
+func (s *Server) handleSlackInteraction(w http.ResponseWriter, r *http.Request) {
+	if s.auth.slackSigningSecret == "" {
+		writeJSONError(w, http.StatusNotFound, "slack interactions are not configured")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	if err := verifySlackSignature(s.auth.slackSigningSecret, r.Header, body, time.Now()); err != nil {
+		writeJSONError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid form body")
+		return
+	}
+	var payload slackInteraction
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid interaction payload")
+		return
+	}
+
+	// Acknowledge anything that is not a gate button so Slack does not
+	// show the user an error.
+	var action *hooks.SlackGateAction
+	for _, a := range payload.Actions {
+		if !strings.HasPrefix(a.ActionID, hooks.SlackGateActionID) {
+			continue
+		}
+		var v hooks.SlackGateAction
+		if err := json.Unmarshal([]byte(a.Value), &v); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid gate action value")
+			return
+		}
+		action = &v
+		break
+	}
+	if payload.Type != "block_actions" || action == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	approver := payload.approver()
+	choice, _, err := s.resolveGate(action.RunID, action.StepID, action.Choice, "", approver)
+	var reply map[string]any
+	if err != nil {
+		reply = map[string]any{
+			"response_type":    "ephemeral",
+			"replace_original": false,
+			"text":             fmt.Sprintf("Could not resolve gate %s / %s: %s", action.RunID, action.StepID, err),
+		}
+	} else {
+		log.Printf("Gate %s/%s resolved from Slack: %s by %s", action.RunID, action.StepID, choice.Key, approver)
+		s.recordAudit(r, state.APIAuditRecord{Actor: approver, Action: auditGateResolved, RunID: action.RunID, StepID: action.StepID, Detail: choice.Key})
+		reply = map[string]any{
+			"replace_original": true,
+			"text":             fmt.Sprintf("Gate %s / %s: *%s* by <@%s>", action.RunID, action.StepID, choice.Label, payload.User.ID),
+		}
+	}
+	if payload.ResponseURL != "" {
+		go postSlackResponse(payload.ResponseURL, reply)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// verifySlackSignature checks the X-Slack-Signature header, an HMAC-SHA256
+// of "v0:<timestamp>:<body>" keyed with the app's signing secret.
+func verifySlackSignature(secret string, h http.Header, body []byte, now time.Time) error {
+	ts := h.Get("X-Slack-Request-Timestamp")
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing or invalid X-Slack-Request-Timestamp")
+	}
+	if d := now.Sub(time.Unix(sec, 0)); d > slackMaxSkew || d < -slackMaxSkew {
+		return fmt.Errorf("stale slack request")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", ts)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(h.Get("X-Slack-Signature"))) {
+		return fmt.Errorf("invalid slack signature")
+	}
+	return nil
+}
+
+// postSlackResponse updates the original Slack message via its response_url.
+func postSlackResponse(responseURL string, reply map[string]any) {
+	data, err := json.Marshal(reply)
+	if err != nil {
+		return
+	}
+	resp, err := slackHTTPClient.Post(context.Background(), responseURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		log.Printf("Warning: failed to update slack message: %v", err)
+		return
+	}
+	resp.Body.Close()
+}
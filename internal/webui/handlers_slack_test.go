@@ -0,0 +1,136 @@
+package webui
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/recinq/wave/internal/hooks"
+	"github.com/recinq/wave/internal/httpx"
+	"github.com/recinq/wave/internal/runner"
+)
+
+const testSlackSecret = "slack-signing-secret"
+
+// slackRequest builds a signed Slack interaction request for a gate button.
+func slackRequest(t *testing.T, secret string, action hooks.SlackGateAction, responseURL string) *http.Request {
+	t.Helper()
+	value, _ := json.Marshal(action)
+	payload, _ := json.Marshal(map[string]any{
+		"type":         "block_actions",
+		"user":         map[string]string{"id": "U123", "username": "alice"},
+		"actions":      []map[string]string{{"action_id": hooks.SlackGateActionID + ":" + action.Choice, "value": string(value)}},
+		"response_url": responseURL,
+	})
+	body := url.Values{"payload": {string(payload)}}.Encode()
+
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + ts + ":" + body))
+
+	req := httptest.NewRequest("POST", slackInteractionsPath, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", ts)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestHandleSlackInteraction_ResolvesGate(t *testing.T) {
+	srv, _ := testServer(t)
+	srv.auth.slackSigningSecret = testSlackSecret
+
+	replies := make(chan string, 1)
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		replies <- string(b)
+	}))
+	defer slack.Close()
+	orig := slackHTTPClient
+	slackHTTPClient = httpx.New(httpx.Config{Timeout: time.Second})
+	defer func() { slackHTTPClient = orig }()
+
+	ch := srv.realtime.gateRegistry.Register("run-1", "review", &runner.WebUIGate{
+		Type: "approval",
+		Choices: []runner.WebUIGateChoice{
+			{Key: "approve", Label: "Approve", Target: "implement"},
+			{Key: "reject", Label: "Reject", Target: "_fail"},
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	srv.handleSlackInteraction(rec, slackRequest(t, testSlackSecret,
+		hooks.SlackGateAction{RunID: "run-1", StepID: "review", Choice: "reject"}, slack.URL))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	select {
+	case d := <-ch:
+		if d.Choice != "reject" || d.Target != "_fail" || d.Approver != "slack:alice" {
+			t.Errorf("unexpected decision: %+v", d)
+		}
+	default:
+		t.Fatal("expected decision on channel")
+	}
+
+	select {
+	case reply := <-replies:
+		var msg struct {
+			Text string `json:"text"`
+		}
+		_ = json.Unmarshal([]byte(reply), &msg)
+		if !strings.Contains(msg.Text, "Reject") || !strings.Contains(msg.Text, "<@U123>") {
+			t.Errorf("unexpected slack reply: %s", reply)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the slack message to be updated")
+	}
+}
+
+func TestHandleSlackInteraction_RejectsBadSignature(t *testing.T) {
+	srv, _ := testServer(t)
+	srv.auth.slackSigningSecret = testSlackSecret
+	ch := srv.realtime.gateRegistry.Register("run-1", "review", &runner.WebUIGate{
+		Choices: []runner.WebUIGateChoice{{Key: "approve", Label: "Approve"}},
+	})
+
+	rec := httptest.NewRecorder()
+	srv.handleSlackInteraction(rec, slackRequest(t, "wrong-secret",
+		hooks.SlackGateAction{RunID: "run-1", StepID: "review", Choice: "approve"}, ""))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+	select {
+	case <-ch:
+		t.Fatal("gate must not resolve on a forged request")
+	default:
+	}
+}
+
+func TestHandleSlackInteraction_NotConfigured(t *testing.T) {
+	srv, _ := testServer(t)
+	rec := httptest.NewRecorder()
+	srv.handleSlackInteraction(rec, slackRequest(t, testSlackSecret,
+		hooks.SlackGateAction{RunID: "run-1", StepID: "review", Choice: "approve"}, ""))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestVerifySlackSignature_Stale(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-Slack-Request-Timestamp", strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10))
+	h.Set("X-Slack-Signature", "v0=00")
+	if err := verifySlackSignature(testSlackSecret, h, nil, time.Now()); err == nil {
+		t.Fatal("expected stale request to be rejected")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/recinq/wave/internal/hooks"
 	"github.com/recinq/wave/internal/state"
 )
 
@@ -113,13 +114,15 @@ func (s *Server) handleAPIWebhooks(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleAPICreateWebhook(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Name    string            `json:"name"`
-		URL     string            `json:"url"`
-		Events  []string          `json:"events"`
-		Matcher string            `json:"matcher"`
-		Headers map[string]string `json:"headers"`
-		Secret  string            `json:"secret"`
-		Active  *bool             `json:"active"`
+		Name     string            `json:"name"`
+		URL      string            `json:"url"`
+		Events   []string          `json:"events"`
+		Matcher  string            `json:"matcher"`
+		Headers  map[string]string `json:"headers"`
+		Secret   string            `json:"secret"`
+		Format   string            `json:"format"`
+		Template string            `json:"template"`
+		Active   *bool             `json:"active"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, fmt.Sprintf("invalid JSON: %s", err), http.StatusBadRequest)
@@ -134,6 +137,10 @@ func (s *Server) handleAPICreateWebhook(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "webhook URL must be a public HTTP(S) endpoint (localhost and private IPs are blocked)", http.StatusBadRequest)
 		return
 	}
+	if err := hooks.ValidateWebhookFormat(req.Format, req.Template); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	active := true
 	if req.Active != nil {
@@ -147,6 +154,8 @@ func (s *Server) handleAPICreateWebhook(w http.ResponseWriter, r *http.Request)
 		Matcher:   req.Matcher,
 		Headers:   req.Headers,
 		Secret:    req.Secret,
+		Format:    req.Format,
+		Template:  req.Template,
 		Active:    active,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -207,13 +216,15 @@ func (s *Server) handleAPIUpdateWebhook(w http.ResponseWriter, r *http.Request)
 	}
 
 	var req struct {
-		Name    *string           `json:"name"`
-		URL     *string           `json:"url"`
-		Events  []string          `json:"events"`
-		Matcher *string           `json:"matcher"`
-		Headers map[string]string `json:"headers"`
-		Secret  *string           `json:"secret"`
-		Active  *bool             `json:"active"`
+		Name     *string           `json:"name"`
+		URL      *string           `json:"url"`
+		Events   []string          `json:"events"`
+		Matcher  *string           `json:"matcher"`
+		Headers  map[string]string `json:"headers"`
+		Secret   *string           `json:"secret"`
+		Format   *string           `json:"format"`
+		Template *string           `json:"template"`
+		Active   *bool             `json:"active"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, fmt.Sprintf("invalid JSON: %s", err), http.StatusBadRequest)
@@ -238,9 +249,19 @@ func (s *Server) handleAPIUpdateWebhook(w http.ResponseWriter, r *http.Request)
 	if req.Secret != nil {
 		existing.Secret = *req.Secret
 	}
+	if req.Format != nil {
+		existing.Format = *req.Format
+	}
+	if req.Template != nil {
+		existing.Template = *req.Template
+	}
 	if req.Active != nil {
 		existing.Active = *req.Active
 	}
+	if err := hooks.ValidateWebhookFormat(existing.Format, existing.Template); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	existing.UpdatedAt = time.Now()
 
 	if err := s.runtime.rwStore.UpdateWebhook(existing); err != nil {
@@ -283,16 +304,29 @@ func (s *Server) handleAPITestWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Fire a test event
-	testPayload := map[string]interface{}{
-		"type":         "test",
-		"webhook_id":   webhook.ID,
-		"webhook_name": webhook.Name,
-		"timestamp":    time.Now().Format(time.RFC3339),
-		"message":      "Test webhook delivery from Wave",
+	var payload []byte
+	if webhook.Format == "" || webhook.Format == hooks.WebhookFormatJSON {
+		testPayload := map[string]interface{}{
+			"type":         "test",
+			"webhook_id":   webhook.ID,
+			"webhook_name": webhook.Name,
+			"timestamp":    time.Now().Format(time.RFC3339),
+			"message":      "Test webhook delivery from Wave",
+		}
+		payload, _ = json.Marshal(testPayload)
+	} else {
+		// Chat formats render the webhook's own template so the preview
+		// matches what a real run would post.
+		payload, err = hooks.WebhookPayload(webhook.Format, webhook.Template, hooks.HookEvent{
+			Type:       "test",
+			PipelineID: "test-run",
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 	}
 
-	payload, _ := json.Marshal(testPayload)
-
 	resp := struct {
 		Success bool   `json:"success"`
 		Message string `json:"message"`
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/recinq/wave/internal/forge"
+	"github.com/recinq/wave/internal/state"
 	"github.com/recinq/wave/internal/timeouts"
 	"github.com/recinq/wave/internal/worksource"
 )
@@ -87,6 +88,7 @@ func (s *Server) handleWorkDispatch(w http.ResponseWriter, r *http.Request) {
 	}
 
 	s.launchPipelineExecution(runID, pipelineName, input, RunOptions{})
+	s.recordAudit(r, state.APIAuditRecord{Action: auditRunStarted, RunID: runID, PipelineName: pipelineName, Detail: ref.URL})
 
 	http.Redirect(w, r, "/runs/"+runID, http.StatusFound)
 }
@@ -22,6 +22,7 @@ type JWTClaims struct {
 	Subject   string `json:"sub"`
 	IssuedAt  int64  `json:"iat"`
 	ExpiresAt int64  `json:"exp"`
+	Role      string `json:"role,omitempty"` // viewer, operator, approver or admin
 }
 
 // ValidateJWT validates a JWT token string with the given secret.
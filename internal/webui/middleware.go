@@ -3,7 +3,6 @@ package webui
 import (
 	"log"
 	"net/http"
-	"strings"
 	"time"
 )
 
@@ -13,24 +12,28 @@ func (s *Server) applyMiddleware(handler http.Handler) http.Handler {
 	if s.auth.csrfToken != "" {
 		h = s.csrfMiddleware(h)
 	}
+	h = s.authorizeMiddleware(h)
+	if s.auth.limiter != nil {
+		h = s.rateLimitMiddleware(h)
+	}
 	h = securityHeaders(h)
 	h = s.loggingMiddleware(h)
 
 	// Apply auth middleware based on resolved auth mode
 	switch s.auth.authMode {
-	case AuthModeBearer:
-		if s.auth.token != "" && s.requiresAuth() {
-			h = s.bearerAuthMiddleware(h)
-		}
 	case AuthModeJWT:
 		h = s.jwtAuthMiddleware(h)
+	case AuthModeOIDC:
+		h = s.oidcAuthMiddleware(h)
 	case AuthModeMTLS:
-		// mTLS is handled at the TLS layer — no HTTP middleware needed
+		// mTLS is verified at the TLS layer; the middleware only names the caller
+		h = s.mtlsIdentityMiddleware(h)
 	case AuthModeNone:
 		// No auth
 	default:
-		// Backward compatibility: use bearer auth if token is set
-		if s.auth.token != "" && s.requiresAuth() {
+		// Bearer, and backward compatibility when a token is set. API
+		// tokens are enforced on any binding.
+		if (s.auth.token != "" && s.requiresAuth()) || len(s.auth.apiTokens) > 0 {
 			h = s.bearerAuthMiddleware(h)
 		}
 	}
@@ -47,56 +50,88 @@ func (s *Server) authMiddleware(next http.Handler) http.Handler {
 // bearerAuthMiddleware validates a bearer token for non-static requests.
 func (s *Server) bearerAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Allow static assets without auth
-		if len(r.URL.Path) >= 8 && r.URL.Path[:8] == "/static/" {
+		if s.isPublicPath(r.URL.Path) {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Check Authorization header
-		auth := r.Header.Get("Authorization")
-		if auth == "Bearer "+s.auth.token {
-			next.ServeHTTP(w, r)
+		p := s.tokenPrincipal(requestToken(r))
+		if p == nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
+		next.ServeHTTP(w, withPrincipal(r, p))
+	})
+}
 
-		// Check query parameter as fallback (for SSE and browser access)
-		if r.URL.Query().Get("token") == s.auth.token {
+// jwtAuthMiddleware validates JWT tokens for non-static requests.
+func (s *Server) jwtAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.isPublicPath(r.URL.Path) {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		tokenStr := requestToken(r)
+		if tokenStr == "" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if p := s.apiTokenPrincipal(tokenStr); p != nil {
+			next.ServeHTTP(w, withPrincipal(r, p))
+			return
+		}
+		claims, err := ValidateJWT(tokenStr, s.auth.jwtSecret)
+		if err != nil {
+			http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		fallback := s.auth.defaultRole
+		if fallback == "" {
+			fallback = RoleAdmin
+		}
+		next.ServeHTTP(w, withPrincipal(r, &Principal{Subject: claims.Subject, Role: highestRole(claims.Role, fallback)}))
 	})
 }
 
-// jwtAuthMiddleware validates JWT tokens for non-static requests.
-func (s *Server) jwtAuthMiddleware(next http.Handler) http.Handler {
+// oidcAuthMiddleware validates ID tokens from the configured OpenID Connect
+// provider for non-static requests. API tokens are accepted as well, for
+// automation that cannot obtain an ID token.
+func (s *Server) oidcAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Allow static assets without auth
-		if len(r.URL.Path) >= 8 && r.URL.Path[:8] == "/static/" {
+		if s.isPublicPath(r.URL.Path) {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Extract token from Authorization header
-		auth := r.Header.Get("Authorization")
-		if !strings.HasPrefix(auth, "Bearer ") {
-			// Fallback: check query parameter (for SSE)
-			tokenStr := r.URL.Query().Get("token")
-			if tokenStr == "" {
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
-				return
-			}
-			auth = "Bearer " + tokenStr
+		tokenStr := requestToken(r)
+		if tokenStr == "" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
 		}
-
-		tokenStr := strings.TrimPrefix(auth, "Bearer ")
-		if _, err := ValidateJWT(tokenStr, s.auth.jwtSecret); err != nil {
+		if p := s.apiTokenPrincipal(tokenStr); p != nil {
+			next.ServeHTTP(w, withPrincipal(r, p))
+			return
+		}
+		p, err := s.auth.oidc.Verify(r.Context(), tokenStr)
+		if err != nil {
 			http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
 			return
 		}
+		next.ServeHTTP(w, withPrincipal(r, p))
+	})
+}
 
+// mtlsIdentityMiddleware names the caller after the common name of its
+// verified client certificate. Certificate holders are admins.
+func (s *Server) mtlsIdentityMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			if cn := r.TLS.PeerCertificates[0].Subject.CommonName; cn != "" {
+				r = withPrincipal(r, &Principal{Subject: "mtls:" + cn, Role: RoleAdmin})
+			}
+		}
 		next.ServeHTTP(w, r)
 	})
 }
@@ -105,6 +140,10 @@ func (s *Server) jwtAuthMiddleware(next http.Handler) http.Handler {
 // (POST, PUT, DELETE, PATCH). GET/HEAD/OPTIONS are allowed through.
 func (s *Server) csrfMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == slackInteractionsPath {
+			next.ServeHTTP(w, r)
+			return
+		}
 		switch r.Method {
 		case http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPatch:
 			token := r.Header.Get("X-CSRF-Token")
@@ -0,0 +1,231 @@
+package webui
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OIDCConfig configures validation of ID tokens issued by an OpenID Connect
+// provider.
+type OIDCConfig struct {
+	Issuer    string // provider URL; must match the token's iss claim
+	Audience  string // expected aud claim, usually the client ID
+	RoleClaim string // claim holding the role or list of roles (default "roles")
+}
+
+const (
+	oidcKeyCacheTTL     = time.Hour
+	oidcKeyRefreshFloor = time.Minute // minimum interval between refetches on an unknown kid
+	oidcClockSkew       = time.Minute
+)
+
+// oidcVerifier validates RS256 ID tokens against the signing keys the
+// provider publishes at its discovery document's jwks_uri. Keys are cached
+// and refetched when a token names a key the cache does not hold.
+type oidcVerifier struct {
+	cfg         OIDCConfig
+	defaultRole Role
+	client      *http.Client
+
+	mu        sync.Mutex
+	jwksURI   string
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+func newOIDCVerifier(cfg OIDCConfig, defaultRole Role) *oidcVerifier {
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+	if cfg.RoleClaim == "" {
+		cfg.RoleClaim = "roles"
+	}
+	return &oidcVerifier{
+		cfg:         cfg,
+		defaultRole: defaultRole,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Verify checks the token's signature, issuer, audience and validity window
+// and returns the caller it identifies. The subject is the email claim when
+// present, otherwise sub.
+func (v *oidcVerifier) Verify(ctx context.Context, token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	headerJSON, err := base64URLDecode(parts[0])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil || header.Alg != "RS256" {
+		return nil, ErrInvalidToken
+	}
+	sig, err := base64URLDecode(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	claimsJSON, err := base64URLDecode(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if iss, _ := claims["iss"].(string); strings.TrimSuffix(iss, "/") != v.cfg.Issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, iss)
+	}
+	if v.cfg.Audience != "" && !audienceContains(claims["aud"], v.cfg.Audience) {
+		return nil, fmt.Errorf("%w: audience does not include %q", ErrInvalidToken, v.cfg.Audience)
+	}
+	now := time.Now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, ErrMissingClaims
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(oidcClockSkew)) {
+		return nil, ErrTokenExpired
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(oidcClockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return nil, fmt.Errorf("%w: token not yet valid", ErrInvalidToken)
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, ErrMissingClaims
+	}
+	if email, _ := claims["email"].(string); email != "" {
+		subject = email
+	}
+	return &Principal{Subject: subject, Role: highestRole(claims[v.cfg.RoleClaim], v.defaultRole)}, nil
+}
+
+func audienceContains(aud any, want string) bool {
+	switch a := aud.(type) {
+	case string:
+		return a == want
+	case []any:
+		for _, item := range a {
+			if s, ok := item.(string); ok && s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// key returns the signing key named kid, fetching the provider's key set
+// when the cache is stale or does not hold it. An empty kid selects the
+// only key of a single-key set.
+func (v *oidcVerifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	stale := time.Since(v.fetchedAt) > oidcKeyCacheTTL
+	if k := v.cachedKey(kid); k != nil && !stale {
+		return k, nil
+	}
+	if stale || time.Since(v.fetchedAt) > oidcKeyRefreshFloor {
+		if err := v.fetchKeys(ctx); err != nil {
+			return nil, fmt.Errorf("failed to fetch OIDC signing keys: %w", err)
+		}
+	}
+	if k := v.cachedKey(kid); k != nil {
+		return k, nil
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidSignature, kid)
+}
+
+func (v *oidcVerifier) cachedKey(kid string) *rsa.PublicKey {
+	if kid == "" && len(v.keys) == 1 {
+		for _, k := range v.keys {
+			return k
+		}
+	}
+	return v.keys[kid]
+}
+
+func (v *oidcVerifier) fetchKeys(ctx context.Context) error {
+	if v.jwksURI == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := v.getJSON(ctx, v.cfg.Issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+			return err
+		}
+		if strings.TrimSuffix(discovery.Issuer, "/") != v.cfg.Issuer {
+			return fmt.Errorf("discovery document issuer %q does not match %q", discovery.Issuer, v.cfg.Issuer)
+		}
+		if discovery.JWKSURI == "" {
+			return errors.New("discovery document has no jwks_uri")
+		}
+		v.jwksURI = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := v.getJSON(ctx, v.jwksURI, &set); err != nil {
+		return err
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, errN := base64URLDecode(k.N)
+		e, errE := base64URLDecode(k.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	return nil
+}
+
+func (v *oidcVerifier) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
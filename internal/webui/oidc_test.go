@@ -0,0 +1,117 @@
+package webui
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testOIDCProvider serves a discovery document and a one-key JWKS.
+func testOIDCProvider(t *testing.T) (*httptest.Server, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": srv.URL, "jwks_uri": srv.URL + "/keys"})
+	})
+	mux.HandleFunc("GET /keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"use": "sig",
+			"n":   base64URLEncode(key.N.Bytes()),
+			"e":   base64URLEncode(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, key
+}
+
+func signTestIDToken(t *testing.T, key *rsa.PrivateKey, claims map[string]any) string {
+	t.Helper()
+	header := base64URLEncode([]byte(`{"alg":"RS256","typ":"JWT","kid":"k1"}`))
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := header + "." + base64URLEncode(payload)
+	digest := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input + "." + base64URLEncode(sig)
+}
+
+func TestOIDCVerifier(t *testing.T) {
+	provider, key := testOIDCProvider(t)
+	v := newOIDCVerifier(OIDCConfig{Issuer: provider.URL, Audience: "wave"}, RoleViewer)
+	valid := func() map[string]any {
+		return map[string]any{
+			"iss":   provider.URL,
+			"aud":   []string{"wave", "other"},
+			"sub":   "1234",
+			"email": "alice@example.com",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"roles": []string{"engineering", "approver"},
+		}
+	}
+
+	p, err := v.Verify(context.Background(), signTestIDToken(t, key, valid()))
+	if err != nil {
+		t.Fatalf("Verify() error: %v", err)
+	}
+	if p.Subject != "alice@example.com" || p.Role != RoleApprover {
+		t.Errorf("principal = %+v, want alice@example.com/approver", p)
+	}
+
+	noRole := valid()
+	delete(noRole, "roles")
+	if p, err := v.Verify(context.Background(), signTestIDToken(t, key, noRole)); err != nil || p.Role != RoleViewer {
+		t.Errorf("token without roles: principal %+v, err %v; want default viewer", p, err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(map[string]any)
+		want   error
+	}{
+		{"wrong audience", func(c map[string]any) { c["aud"] = "someone-else" }, ErrInvalidToken},
+		{"wrong issuer", func(c map[string]any) { c["iss"] = "https://evil.example.com" }, ErrInvalidToken},
+		{"expired", func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() }, ErrTokenExpired},
+		{"missing subject", func(c map[string]any) { delete(c, "sub") }, ErrMissingClaims},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			tt.mutate(claims)
+			if _, err := v.Verify(context.Background(), signTestIDToken(t, key, claims)); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("foreign key", func(t *testing.T) {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := v.Verify(context.Background(), signTestIDToken(t, other, valid())); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("Verify() error = %v, want %v", err, ErrInvalidSignature)
+		}
+	})
+}
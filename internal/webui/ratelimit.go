@@ -0,0 +1,109 @@
+package webui
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitConfig bounds how fast a single caller may issue mutating API
+// requests (starting, cancelling, approving runs). Reads are not limited.
+type RateLimitConfig struct {
+	RequestsPerMinute int // 0 disables the limit
+	Burst             int // requests allowed back to back; defaults to RequestsPerMinute
+}
+
+// rateLimiter keeps one token bucket per caller subject.
+type rateLimiter struct {
+	cfg RateLimitConfig
+	now func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens   float64
+	capacity float64
+	perSec   float64
+	last     time.Time
+}
+
+func hasTokenRateLimit(tokens []APIToken) bool {
+	for _, t := range tokens {
+		if t.RateLimit > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func newRateLimiter(cfg RateLimitConfig) *rateLimiter {
+	return &rateLimiter{cfg: cfg, now: time.Now, buckets: make(map[string]*tokenBucket)}
+}
+
+// allow takes a token from the caller's bucket. A positive perMinute
+// overrides the configured rate and burst for this caller. When the bucket
+// is empty it returns false and how long until the next token is available.
+func (l *rateLimiter) allow(subject string, perMinute int) (bool, time.Duration) {
+	rate, burst := l.cfg.RequestsPerMinute, l.cfg.Burst
+	if perMinute > 0 {
+		rate, burst = perMinute, perMinute
+	}
+	if rate <= 0 {
+		return true, 0
+	}
+	if burst <= 0 {
+		burst = rate
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	b, ok := l.buckets[subject]
+	if !ok {
+		b = &tokenBucket{tokens: float64(burst), capacity: float64(burst), perSec: float64(rate) / 60, last: now}
+		l.buckets[subject] = b
+	}
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.perSec)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / b.perSec * float64(time.Second))
+	return false, wait
+}
+
+// rateLimitMiddleware rejects mutating requests from callers that exceed
+// their rate limit with 429 Too Many Requests. It runs after authentication
+// so that each API token and identity has its own budget.
+func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+		if s.isPublicPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		p := requestPrincipal(r)
+		ok, wait := s.auth.limiter.allow(p.Subject, p.RateLimit)
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			msg := "rate limit exceeded for " + p.Subject
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				writeJSONError(w, http.StatusTooManyRequests, msg)
+			} else {
+				http.Error(w, msg, http.StatusTooManyRequests)
+			}
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
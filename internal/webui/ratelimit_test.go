@@ -0,0 +1,93 @@
+package webui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l := newRateLimiter(RateLimitConfig{RequestsPerMinute: 60, Burst: 2})
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.allow("token:ci", 0); !ok {
+			t.Fatalf("request %d within burst was rejected", i+1)
+		}
+	}
+	ok, wait := l.allow("token:ci", 0)
+	if ok {
+		t.Fatal("request beyond burst was allowed")
+	}
+	if wait <= 0 || wait > time.Second {
+		t.Errorf("wait = %v, want (0, 1s]", wait)
+	}
+	if ok, _ := l.allow("alice@example.com", 0); !ok {
+		t.Error("a different caller shares the exhausted bucket")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := l.allow("token:ci", 0); !ok {
+		t.Error("bucket did not refill after one second at 60/min")
+	}
+
+	// A per-caller override replaces the server rate and burst.
+	if ok, _ := l.allow("token:bulk", 1); !ok {
+		t.Error("first request under a 1/min override was rejected")
+	}
+	if ok, wait := l.allow("token:bulk", 1); ok || wait < 59*time.Second {
+		t.Errorf("second request under a 1/min override: ok=%v wait=%v", ok, wait)
+	}
+
+	off := newRateLimiter(RateLimitConfig{})
+	for i := 0; i < 100; i++ {
+		if ok, _ := off.allow("anyone", 0); !ok {
+			t.Fatal("disabled limiter rejected a request")
+		}
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	s := &Server{
+		auth: serverAuth{
+			apiTokens: []APIToken{
+				{Name: "ci", Token: "tok-ci", Role: RoleOperator, RateLimit: 1},
+				{Name: "ops", Token: "tok-ops", Role: RoleOperator},
+			},
+			limiter: newRateLimiter(RateLimitConfig{RequestsPerMinute: 100}),
+		},
+		transport: serverTransport{bind: "127.0.0.1"},
+	}
+	mux := http.NewServeMux()
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	mux.HandleFunc("GET /api/runs", ok)
+	mux.HandleFunc("POST /api/runs/{id}/cancel", ok)
+	handler := s.applyMiddleware(mux)
+
+	do := func(token, method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do("tok-ci", "POST", "/api/runs/r1/cancel"); rec.Code != http.StatusOK {
+		t.Fatalf("first mutation: got %d", rec.Code)
+	}
+	rec := do("tok-ci", "POST", "/api/runs/r2/cancel")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second mutation: got %d, want 429", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("429 response has no Retry-After header")
+	}
+	if rec := do("tok-ci", "GET", "/api/runs"); rec.Code != http.StatusOK {
+		t.Errorf("reads must not be rate limited: got %d", rec.Code)
+	}
+	if rec := do("tok-ops", "POST", "/api/runs/r3/cancel"); rec.Code != http.StatusOK {
+		t.Errorf("other token shares the limited budget: got %d", rec.Code)
+	}
+}
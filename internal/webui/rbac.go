@@ -0,0 +1,156 @@
+package webui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Role is a dashboard permission level. Each role holds the permissions of
+// the roles ranked below it.
+type Role string
+
+const (
+	// RoleViewer can read runs, pipelines and artifacts.
+	RoleViewer Role = "viewer"
+	// RoleOperator can also start, cancel, retry and resume runs.
+	RoleOperator Role = "operator"
+	// RoleApprover can also resolve approval gates and evolution proposals.
+	RoleApprover Role = "approver"
+	// RoleAdmin can also use the admin console, manage webhooks and
+	// install skills.
+	RoleAdmin Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleApprover: 3,
+	RoleAdmin:    4,
+}
+
+// ParseRole parses a role name. The empty string is not a role.
+func ParseRole(s string) (Role, error) {
+	r := Role(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := roleRanks[r]; !ok {
+		return "", fmt.Errorf("invalid role %q (valid: viewer, operator, approver, admin)", s)
+	}
+	return r, nil
+}
+
+// Allows reports whether r holds the permissions of required.
+func (r Role) Allows(required Role) bool {
+	return roleRanks[r] >= roleRanks[required]
+}
+
+// highestRole returns the highest-ranked role named by a token claim, which
+// may be a single string or a list of strings. Unrecognised names, such as
+// unrelated provider groups, are ignored; fallback is returned when the
+// claim names no role.
+func highestRole(claim any, fallback Role) Role {
+	var names []string
+	switch v := claim.(type) {
+	case string:
+		names = []string{v}
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				names = append(names, s)
+			}
+		}
+	case []string:
+		names = v
+	}
+	best := Role("")
+	for _, name := range names {
+		if r, err := ParseRole(name); err == nil && roleRanks[r] > roleRanks[best] {
+			best = r
+		}
+	}
+	if best == "" {
+		return fallback
+	}
+	return best
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject   string // recorded on gate decisions and cancellations
+	Role      Role
+	RateLimit int // mutating requests per minute; 0 uses the server limit
+}
+
+// localPrincipal is the caller when the server does not authenticate
+// requests, e.g. on a localhost binding.
+var localPrincipal = &Principal{Subject: "dashboard", Role: RoleAdmin}
+
+type principalKey struct{}
+
+func withPrincipal(r *http.Request, p *Principal) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), principalKey{}, p))
+}
+
+// requestPrincipal returns the caller the auth middleware attached to r,
+// or localPrincipal when authentication is off.
+func requestPrincipal(r *http.Request) *Principal {
+	if p, ok := r.Context().Value(principalKey{}).(*Principal); ok && p != nil {
+		return p
+	}
+	return localPrincipal
+}
+
+// authorizeMiddleware applies the default policy: reads require viewer and
+// mutations operator. Routes that need more are wrapped with requireRole.
+func (s *Server) authorizeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.isPublicPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		required := RoleOperator
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			required = RoleViewer
+		}
+		if !s.authorized(w, r, required) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireRole restricts a route to callers holding role.
+func (s *Server) requireRole(role Role, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.authorized(w, r, role) {
+			return
+		}
+		h(w, r)
+	}
+}
+
+// authorized writes a 403 and returns false when the caller lacks role.
+func (s *Server) authorized(w http.ResponseWriter, r *http.Request, role Role) bool {
+	p := requestPrincipal(r)
+	if p.Role.Allows(role) {
+		return true
+	}
+	msg := fmt.Sprintf("forbidden: %s role required (%s has %s)", role, p.Subject, p.Role)
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		writeJSONError(w, http.StatusForbidden, msg)
+	} else {
+		http.Error(w, msg, http.StatusForbidden)
+	}
+	return false
+}
+
+// isPublicPath reports whether path bypasses authentication: static assets,
+// Slack callbacks, which carry their own signature verified by the handler,
+// and status badges when the server publishes them.
+func (s *Server) isPublicPath(path string) bool {
+	if strings.HasPrefix(path, "/static/") || path == slackInteractionsPath {
+		return true
+	}
+	return s.auth.publicStatus && isStatusPath(path)
+}
@@ -0,0 +1,161 @@
+package webui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRole(t *testing.T) {
+	for _, in := range []string{"viewer", "operator", "approver", "admin", " Admin "} {
+		if _, err := ParseRole(in); err != nil {
+			t.Errorf("ParseRole(%q) error: %v", in, err)
+		}
+	}
+	for _, in := range []string{"", "root", "owner"} {
+		if _, err := ParseRole(in); err == nil {
+			t.Errorf("ParseRole(%q) should fail", in)
+		}
+	}
+}
+
+func TestNormalizeAuthConfig_MixedCaseRoles(t *testing.T) {
+	cfg := ServerConfig{
+		APITokens:   []APIToken{{Name: "ci", Token: "tok", Role: "Operator"}},
+		DefaultRole: " viewer",
+	}
+	if err := normalizeAuthConfig(&cfg); err != nil {
+		t.Fatalf("normalizeAuthConfig: %v", err)
+	}
+	if got := cfg.APITokens[0].Role; got != RoleOperator {
+		t.Errorf("token role = %q, want %q", got, RoleOperator)
+	}
+	if !cfg.APITokens[0].Role.Allows(RoleOperator) {
+		t.Error("mixed-case operator token must be allowed operator actions")
+	}
+	if cfg.DefaultRole != RoleViewer {
+		t.Errorf("default role = %q, want %q", cfg.DefaultRole, RoleViewer)
+	}
+
+	bad := ServerConfig{APITokens: []APIToken{{Name: "ci", Token: "tok", Role: "root"}}}
+	if err := normalizeAuthConfig(&bad); err == nil {
+		t.Error("unknown role should fail")
+	}
+}
+
+func TestHighestRole(t *testing.T) {
+	tests := []struct {
+		name  string
+		claim any
+		want  Role
+	}{
+		{"string", "operator", RoleOperator},
+		{"list", []any{"viewer", "engineering", "approver"}, RoleApprover},
+		{"unrelated groups", []any{"engineering", "oncall"}, RoleViewer},
+		{"missing", nil, RoleViewer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highestRole(tt.claim, RoleViewer); got != tt.want {
+				t.Errorf("highestRole(%v) = %q, want %q", tt.claim, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorization_APITokenRoles(t *testing.T) {
+	s := &Server{
+		auth: serverAuth{apiTokens: []APIToken{
+			{Name: "reader", Token: "tok-viewer", Role: RoleViewer},
+			{Name: "ci", Token: "tok-operator", Role: RoleOperator},
+			{Name: "lead", Token: "tok-approver", Role: RoleApprover},
+			{Name: "ops", Token: "tok-admin", Role: RoleAdmin},
+		}},
+		transport: serverTransport{bind: "127.0.0.1"},
+	}
+	var identity string
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		identity = s.requestIdentity(r)
+		w.WriteHeader(http.StatusOK)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/runs", ok)
+	mux.HandleFunc("POST /api/runs/{id}/cancel", ok)
+	mux.HandleFunc("POST /api/runs/{id}/gates/{step}/approve", s.requireRole(RoleApprover, ok))
+	mux.HandleFunc("GET /api/admin/audit", s.requireRole(RoleAdmin, ok))
+	handler := s.applyMiddleware(mux)
+
+	tests := []struct {
+		token, method, path string
+		want                int
+	}{
+		{"", "GET", "/api/runs", http.StatusUnauthorized},
+		{"wrong", "GET", "/api/runs", http.StatusUnauthorized},
+		{"tok-viewer", "GET", "/api/runs", http.StatusOK},
+		{"tok-viewer", "POST", "/api/runs/r1/cancel", http.StatusForbidden},
+		{"tok-operator", "POST", "/api/runs/r1/cancel", http.StatusOK},
+		{"tok-operator", "POST", "/api/runs/r1/gates/review/approve", http.StatusForbidden},
+		{"tok-approver", "POST", "/api/runs/r1/gates/review/approve", http.StatusOK},
+		{"tok-approver", "GET", "/api/admin/audit", http.StatusForbidden},
+		{"tok-admin", "GET", "/api/admin/audit", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s %s with %q: got %d, want %d", tt.method, tt.path, tt.token, rec.Code, tt.want)
+		}
+	}
+
+	req := httptest.NewRequest("POST", "/api/runs/r1/gates/review/approve", nil)
+	req.Header.Set("Authorization", "Bearer tok-approver")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if identity != "token:lead" {
+		t.Errorf("identity = %q, want token:lead", identity)
+	}
+}
+
+func TestAuthorization_NoAuthIsAdmin(t *testing.T) {
+	s := &Server{transport: serverTransport{bind: "127.0.0.1"}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/admin/audit", s.requireRole(RoleAdmin, func(w http.ResponseWriter, r *http.Request) {
+		if got := s.requestIdentity(r); got != "dashboard" {
+			t.Errorf("identity = %q, want dashboard", got)
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	s.applyMiddleware(mux).ServeHTTP(rec, httptest.NewRequest("GET", "/api/admin/audit", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("got %d, want 200 on an unauthenticated localhost server", rec.Code)
+	}
+}
+
+func TestJWTAuth_RoleClaim(t *testing.T) {
+	secret := "test-jwt-secret"
+	s := &Server{
+		auth:      serverAuth{authMode: AuthModeJWT, jwtSecret: secret, defaultRole: RoleViewer},
+		transport: serverTransport{bind: "0.0.0.0"},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/runs/{id}/cancel", func(w http.ResponseWriter, r *http.Request) {})
+	handler := s.applyMiddleware(mux)
+
+	for role, want := range map[string]int{"operator": http.StatusOK, "": http.StatusForbidden} {
+		token, err := GenerateJWT(secret, JWTClaims{Subject: "alice", ExpiresAt: 9999999999, Role: role})
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest("POST", "/api/runs/r1/cancel", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("role %q: got %d, want %d", role, rec.Code, want)
+		}
+	}
+}
@@ -39,16 +39,17 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /compare", s.handleComparePage)
 	// Analytics and Webhooks are optional — registered via build tags.
 	// See features_analytics.go and features_webhooks.go.
-	mux.HandleFunc("GET /admin", s.handleAdminPage)
+	mux.HandleFunc("GET /admin", s.requireRole(RoleAdmin, s.handleAdminPage))
 
 	// Evolution proposal approval gate (#1613)
 	mux.HandleFunc("GET /proposals", s.handleProposalsPage)
 	mux.HandleFunc("GET /proposals/{id}", s.handleProposalDetailPage)
-	mux.HandleFunc("POST /proposals/{id}/approve", s.handleProposalApprove)
-	mux.HandleFunc("POST /proposals/{id}/reject", s.handleProposalReject)
-	mux.HandleFunc("POST /pipelines/{pipelineName}/rollback", s.handleProposalRollback)
+	mux.HandleFunc("POST /proposals/{id}/approve", s.requireRole(RoleApprover, s.handleProposalApprove))
+	mux.HandleFunc("POST /proposals/{id}/reject", s.requireRole(RoleApprover, s.handleProposalReject))
+	mux.HandleFunc("POST /pipelines/{pipelineName}/rollback", s.requireRole(RoleApprover, s.handleProposalRollback))
 
-	// API endpoints (JSON)
+	// API endpoints (JSON). Reads require the viewer role and mutations
+	// operator (see authorizeMiddleware); stricter routes use requireRole.
 	mux.HandleFunc("GET /api/runs", s.handleAPIRuns)
 	mux.HandleFunc("GET /api/runs/export", s.handleExportRuns)
 	mux.HandleFunc("POST /api/runs", s.handleSubmitRun)
@@ -65,12 +66,13 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /api/runs/{id}/fork", s.handleForkRun)
 	mux.HandleFunc("POST /api/runs/{id}/rewind", s.handleRewindRun)
 	mux.HandleFunc("GET /api/runs/{id}/fork-points", s.handleForkPoints)
-	mux.HandleFunc("POST /api/runs/{id}/gates/{step}/approve", s.handleGateApprove)
+	mux.HandleFunc("POST /api/runs/{id}/gates/{step}/approve", s.requireRole(RoleApprover, s.handleGateApprove))
+	mux.HandleFunc("POST "+slackInteractionsPath, s.handleSlackInteraction)
 	mux.HandleFunc("GET /api/personas", s.handleAPIPersonas)
 	mux.HandleFunc("GET /api/contracts", s.handleAPIContracts)
 	mux.HandleFunc("GET /api/skills", s.handleAPISkills)
-	mux.HandleFunc("POST /api/skills/{name}/install", s.handleAPISkillInstall)
-	mux.HandleFunc("POST /api/skills/{name}/run-install", s.handleAPISkillRunInstall)
+	mux.HandleFunc("POST /api/skills/{name}/install", s.requireRole(RoleAdmin, s.handleAPISkillInstall))
+	mux.HandleFunc("POST /api/skills/{name}/run-install", s.requireRole(RoleAdmin, s.handleAPISkillRunInstall))
 	mux.HandleFunc("GET /api/contracts/{name}", s.handleAPIContractDetail)
 	mux.HandleFunc("GET /api/compose", s.handleAPICompose)
 	mux.HandleFunc("GET /api/pipelines/info", s.handleAPIPipelineInfo)
@@ -88,18 +90,22 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /api/prs/start", s.handleAPIStartFromPR)
 	mux.HandleFunc("POST /api/cache/refresh", s.handleAPICacheRefresh)
 	mux.HandleFunc("GET /api/health", s.handleAPIHealth)
+	mux.HandleFunc("GET /api/status", s.handleAPIStatus)
+	mux.HandleFunc("GET /api/status/{name}", s.handleAPIPipelineStatus)
+	mux.HandleFunc("GET /badge/{file}", s.handleBadge)
 	mux.HandleFunc("GET /api/attention", s.handleAttentionSummary)
 	mux.HandleFunc("GET /api/attention/events", s.handleAttentionSSE)
 	mux.HandleFunc("GET /api/compare", s.handleAPICompare)
 	// Retrospective API — see features_retros.go
 
 	// Admin API
-	mux.HandleFunc("GET /api/admin/config", s.handleAPIAdminConfig)
-	mux.HandleFunc("GET /api/admin/credentials", s.handleAPIAdminCredentials)
-	mux.HandleFunc("POST /api/admin/emergency-stop", s.handleAPIEmergencyStop)
-	mux.HandleFunc("POST /api/admin/pipelines/{name}/disable", s.handleDisablePipeline)
-	mux.HandleFunc("POST /api/admin/pipelines/{name}/enable", s.handleEnablePipeline)
-	mux.HandleFunc("GET /api/admin/audit", s.handleAPIAdminAudit)
+	mux.HandleFunc("GET /api/admin/config", s.requireRole(RoleAdmin, s.handleAPIAdminConfig))
+	mux.HandleFunc("GET /api/admin/credentials", s.requireRole(RoleAdmin, s.handleAPIAdminCredentials))
+	mux.HandleFunc("POST /api/admin/emergency-stop", s.requireRole(RoleAdmin, s.handleAPIEmergencyStop))
+	mux.HandleFunc("POST /api/admin/pipelines/{name}/disable", s.requireRole(RoleAdmin, s.handleDisablePipeline))
+	mux.HandleFunc("POST /api/admin/pipelines/{name}/enable", s.requireRole(RoleAdmin, s.handleEnablePipeline))
+	mux.HandleFunc("GET /api/admin/audit", s.requireRole(RoleAdmin, s.handleAPIAdminAudit))
+	mux.HandleFunc("GET /api/admin/audit/export", s.requireRole(RoleAdmin, s.handleExportAPIAudit))
 
 	// Optional feature routes (analytics, webhooks, etc.)
 	for _, fn := range s.assets.features.routeFns {
@@ -14,15 +14,18 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/recinq/wave/internal/adapter"
 	"github.com/recinq/wave/internal/attention"
 	"github.com/recinq/wave/internal/event"
 	"github.com/recinq/wave/internal/forge"
 	"github.com/recinq/wave/internal/manifest"
 	"github.com/recinq/wave/internal/onboarding"
+	"github.com/recinq/wave/internal/pipeline"
 	"github.com/recinq/wave/internal/state"
 	"github.com/recinq/wave/internal/worksource"
 	"github.com/recinq/wave/internal/workspace"
@@ -36,6 +39,7 @@ const (
 	AuthModeBearer AuthMode = "bearer"
 	AuthModeJWT    AuthMode = "jwt"
 	AuthModeMTLS   AuthMode = "mtls"
+	AuthModeOIDC   AuthMode = "oidc"
 )
 
 // serverTransport groups HTTP-only fields: the underlying server and its
@@ -56,14 +60,24 @@ type serverAuth struct {
 	tlsKey    string
 	tlsCA     string
 	csrfToken string
+
+	apiTokens   []APIToken    // role-bound bearer tokens
+	oidc        *oidcVerifier // set in oidc mode
+	defaultRole Role          // role of jwt/oidc identities whose token names none
+	limiter     *rateLimiter  // per-caller limit on mutating requests; nil when off
+
+	publicStatus bool // serve /badge and /api/status without authentication
+
+	slackSigningSecret string // verifies /api/slack/interactions callbacks
 }
 
 // serverRuntime groups the long-lived runtime collaborators: state stores,
 // manifest, workspace manager, forge client, and pipeline scheduler.
 type serverRuntime struct {
 	store       state.StateStore
-	rwStore     state.StateStore // read-write store for execution control
-	manifest    *manifest.Manifest
+	rwStore     state.StateStore   // read-write store for execution control
+	manifest    *manifest.Manifest // read via currentManifest; swapped on reload
+	manifestMu  sync.RWMutex
 	wsManager   workspace.WorkspaceManager
 	forgeClient forge.Client
 	repoSlug    string // "owner/repo"
@@ -71,6 +85,9 @@ type serverRuntime struct {
 	repoDir     string // git repository root directory
 	scheduler   *Scheduler
 	worksource  worksource.Service
+	warmPool    *adapter.WarmPool // nil unless server.warm_pool is enabled
+
+	configWatcher *configWatcher // nil when the server was not given a manifest path
 }
 
 // serverRealtime groups the realtime/eventing collaborators: SSE broker,
@@ -114,25 +131,89 @@ type Server struct {
 
 // ServerConfig holds configuration for the dashboard server.
 type ServerConfig struct {
-	Bind          string
-	Port          int
-	DBPath        string
-	Manifest      *manifest.Manifest
-	Token         string
-	AuthMode      AuthMode
-	JWTSecret     string
-	MaxConcurrent int
-	TLSCert       string
-	TLSKey        string
-	TLSCA         string // CA cert for mTLS client verification
+	Bind      string
+	Port      int
+	DBPath    string
+	Manifest  *manifest.Manifest
+	Token     string
+	AuthMode  AuthMode
+	JWTSecret string
+	// SlackSigningSecret enables POST /api/slack/interactions, which
+	// resolves gates from Slack buttons. Empty disables the endpoint.
+	SlackSigningSecret string
+	MaxConcurrent      int
+	TLSCert            string
+	TLSKey             string
+	TLSCA              string // CA cert for mTLS client verification
+	// APITokens are bearer tokens accepted alongside Token, each bound to
+	// a role. Token itself grants admin.
+	APITokens []APIToken
+	// OIDC configures ID token validation in oidc mode.
+	OIDC *OIDCConfig
+	// DefaultRole is granted to jwt and oidc identities whose token names
+	// no role. Defaults to admin in jwt mode and viewer in oidc mode.
+	DefaultRole Role
+	// RateLimit bounds mutating requests per caller. API tokens may set
+	// their own limit.
+	RateLimit RateLimitConfig
+	// PublicStatus serves the /badge/<pipeline>.svg badges and the
+	// /api/status endpoints without authentication, so READMEs can embed
+	// them.
+	PublicStatus bool
+	// ManifestPath enables hot reload: wave.yaml and .agents/pipelines are
+	// watched and a validated manifest replaces Manifest on change.
+	ManifestPath string
+	// WarmPool, when set, keeps adapter processes warm for step retries
+	// and runs pipelines inside the server process so they can use them.
+	WarmPool *adapter.WarmPoolConfig
 	// Features is the optional feature registry. When nil, NewServer
 	// constructs one via NewFeatureRegistry(), which selects the appropriate
 	// per-feature implementations based on build tags.
 	Features *FeatureRegistry
 }
 
+// normalizeAuthConfig validates the API tokens and default role and stores
+// each role in its canonical form, so "Operator" ranks as operator in
+// Role.Allows.
+func normalizeAuthConfig(cfg *ServerConfig) error {
+	apiTokens := make([]APIToken, len(cfg.APITokens))
+	for i, t := range cfg.APITokens {
+		if t.Name == "" || t.Token == "" {
+			return fmt.Errorf("API token %q: name and token are required", t.Name)
+		}
+		role, err := ParseRole(string(t.Role))
+		if err != nil {
+			return fmt.Errorf("API token %q: %w", t.Name, err)
+		}
+		if t.RateLimit < 0 {
+			return fmt.Errorf("API token %q: rate limit must not be negative", t.Name)
+		}
+		t.Role = role
+		apiTokens[i] = t
+	}
+	cfg.APITokens = apiTokens
+	if cfg.DefaultRole != "" {
+		role, err := ParseRole(string(cfg.DefaultRole))
+		if err != nil {
+			return fmt.Errorf("default role: %w", err)
+		}
+		cfg.DefaultRole = role
+	}
+	return nil
+}
+
 // NewServer creates a new dashboard server instance.
 func NewServer(cfg ServerConfig) (*Server, error) {
+	if err := normalizeAuthConfig(&cfg); err != nil {
+		return nil, err
+	}
+	if cfg.RateLimit.RequestsPerMinute < 0 || cfg.RateLimit.Burst < 0 {
+		return nil, fmt.Errorf("rate limit must not be negative")
+	}
+	if cfg.AuthMode == AuthModeOIDC && (cfg.OIDC == nil || cfg.OIDC.Issuer == "") {
+		return nil, fmt.Errorf("oidc auth mode requires an issuer")
+	}
+
 	roStore, err := state.NewReadOnlyStateStore(cfg.DBPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open read-only state store: %w", err)
@@ -144,6 +225,9 @@ func NewServer(cfg ServerConfig) (*Server, error) {
 		roStore.Close()
 		return nil, fmt.Errorf("failed to open read-write state store: %w", err)
 	}
+	if cfg.Manifest != nil {
+		state.SetRunIDFormatter(rwStore, pipeline.RunIDFormatter(cfg.Manifest.Runtime))
+	}
 
 	// Reclaim zombie runs left over from previously-killed wave run processes
 	// (terminal close, OOM, signal). Without this, the run list accumulates
@@ -213,7 +297,7 @@ func NewServer(cfg ServerConfig) (*Server, error) {
 	// Resolve git repo root for safe subprocess execution
 	repoDir := "."
 	if out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
-		repoDir = strings.TrimSpace(string(out))
+		repoDir = filepath.FromSlash(strings.TrimSpace(string(out)))
 	}
 
 	// Resolve auth mode
@@ -225,6 +309,21 @@ func NewServer(cfg ServerConfig) (*Server, error) {
 			authMode = AuthModeNone
 		}
 	}
+	defaultRole := cfg.DefaultRole
+	if defaultRole == "" {
+		defaultRole = RoleAdmin // JWTs predating roles keep full access
+		if authMode == AuthModeOIDC {
+			defaultRole = RoleViewer
+		}
+	}
+	var oidc *oidcVerifier
+	if authMode == AuthModeOIDC {
+		oidc = newOIDCVerifier(*cfg.OIDC, defaultRole)
+	}
+	var limiter *rateLimiter
+	if cfg.RateLimit.RequestsPerMinute > 0 || hasTokenRateLimit(cfg.APITokens) {
+		limiter = newRateLimiter(cfg.RateLimit)
+	}
 
 	s := &Server{
 		transport: serverTransport{
@@ -239,6 +338,15 @@ func NewServer(cfg ServerConfig) (*Server, error) {
 			tlsKey:    cfg.TLSKey,
 			tlsCA:     cfg.TLSCA,
 			csrfToken: csrfToken,
+
+			apiTokens:   cfg.APITokens,
+			oidc:        oidc,
+			defaultRole: defaultRole,
+			limiter:     limiter,
+
+			publicStatus: cfg.PublicStatus,
+
+			slackSigningSecret: cfg.SlackSigningSecret,
 		},
 		runtime: serverRuntime{
 			store:       roStore,
@@ -270,6 +378,13 @@ func NewServer(cfg ServerConfig) (*Server, error) {
 		},
 	}
 
+	if cfg.ManifestPath != "" {
+		s.runtime.configWatcher = newConfigWatcher(cfg.ManifestPath, ".agents/pipelines")
+	}
+	if cfg.WarmPool != nil {
+		s.runtime.warmPool = adapter.NewWarmPool(*cfg.WarmPool)
+	}
+
 	// Wire attention broker into the SSE broker so pipeline events
 	// are automatically forwarded to the attention classifier.
 	s.realtime.broker.attentionSink = s.realtime.attention
@@ -368,6 +483,9 @@ func (s *Server) Start() error {
 	defer attentionCancel()
 	go s.pollAttention(attentionCtx)
 	go s.reconcileZombiesLoop(attentionCtx)
+	if s.runtime.configWatcher != nil {
+		go s.watchConfig(attentionCtx)
+	}
 
 	addr := fmt.Sprintf("%s:%d", s.transport.bind, s.transport.port)
 	listener, err := net.Listen("tcp", addr)
@@ -449,6 +567,10 @@ func (s *Server) Start() error {
 		}
 		s.mu.Unlock()
 
+		if s.runtime.warmPool != nil {
+			s.runtime.warmPool.Close()
+		}
+
 		// Drain scheduler queue
 		drainCtx, drainCancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer drainCancel()
@@ -19,6 +19,8 @@ type RunOptions = config.RuntimeConfig
 // via internal/runner. The subprocess is fully independent of the server
 // process — server shutdown does not cancel runs. Dry-run mode short-circuits
 // to a synchronous status update because validation completes instantly.
+// With a warm pool the run stays in-process instead, since warm adapter
+// processes cannot be handed to a subprocess.
 //
 // This helper is shared by handleStartPipeline, handleRetryRun, handleResumeRun,
 // and handleForkRun. When fromStep is non-empty the subprocess resumes from
@@ -32,6 +34,11 @@ func (s *Server) launchPipelineExecution(runID, pipelineName, input string, opts
 		return
 	}
 
+	if s.runtime.warmPool != nil {
+		s.launchInProcess(runID, pipelineName, input, opts, fromStep...)
+		return
+	}
+
 	// Spawn a detached subprocess via the shared runner. Concurrency is
 	// enforced atomically at CreateRunWithLimit by the calling handler.
 	if err := s.spawnDetachedRun(runID, pipelineName, input, opts, fromStep...); err != nil {
@@ -71,14 +78,24 @@ func (s *Server) spawnDetachedRun(runID, pipelineName, input string, opts RunOpt
 }
 
 // launchInProcess runs the pipeline inside the server process via
-// internal/runner. This is the fallback path when subprocess spawning fails;
-// the server-shutdown path will cancel these via activeRuns.
+// internal/runner. This is the path for servers with a warm pool and the
+// fallback when subprocess spawning fails; the server-shutdown path will
+// cancel these via activeRuns.
 func (s *Server) launchInProcess(runID, pipelineName, input string, opts RunOptions, fromStep ...string) {
 	resolvedFromStep := ""
 	if len(fromStep) > 0 {
 		resolvedFromStep = fromStep[0]
 	}
 
+	p, err := loadPipelineYAML(pipelineName)
+	if err != nil {
+		log.Printf("Error: failed to load pipeline %s for run %s: %v", pipelineName, runID, err)
+		if err := s.runtime.rwStore.UpdateRunStatus(runID, "failed", "failed to load pipeline: "+err.Error(), 0); err != nil {
+			log.Printf("Warning: failed to update run %s status: %v", runID, err)
+		}
+		return
+	}
+
 	emitter := &event.DBLoggingEmitter{
 		Inner: s.realtime.broker,
 		Store: s.runtime.rwStore,
@@ -97,13 +114,15 @@ func (s *Server) launchInProcess(runID, pipelineName, input string, opts RunOpti
 		RunID:            runID,
 		PipelineName:     pipelineName,
 		Input:            input,
-		Manifest:         s.runtime.manifest,
+		Pipeline:         p,
+		Manifest:         s.currentManifest(),
 		Store:            s.runtime.rwStore,
 		Emitter:          emitter,
 		WorkspaceManager: s.runtime.wsManager,
 		GateHandler:      gateHandler,
 		FromStep:         resolvedFromStep,
 		Options:          opts,
+		WarmPool:         s.runtime.warmPool,
 		OnComplete: func(string, error) {
 			// Invalidate issue/PR caches so fresh data shows after pipeline completion.
 			s.assets.cache.InvalidatePrefix("issues:")
@@ -26,9 +26,44 @@ function applyThreadColors() {
     });
 }
 
+// Collapse or expand a step group: member nodes and every edge touching
+// them are hidden, leaving the group box as a single unit.
+function toggleDagGroup(group) {
+    var svg = group.closest('svg');
+    var name = group.getAttribute('data-group');
+    var collapsed = group.classList.toggle('collapsed');
+    var label = group.querySelector('.dag-group-label');
+    svg.querySelectorAll('.dag-node').forEach(function(node) {
+        if (node.getAttribute('data-group') === name) {
+            node.classList.toggle('dag-collapsed-hidden', collapsed);
+        }
+    });
+    var hiddenNodes = {};
+    svg.querySelectorAll('.dag-node.dag-collapsed-hidden').forEach(function(node) {
+        hiddenNodes[node.getAttribute('data-id')] = true;
+    });
+    svg.querySelectorAll('.dag-edge').forEach(function(edge) {
+        var hidden = hiddenNodes[edge.getAttribute('data-from')] || hiddenNodes[edge.getAttribute('data-to')];
+        edge.classList.toggle('dag-collapsed-hidden', !!hidden);
+    });
+    label.textContent = (collapsed ? '▸ ' : '▾ ') + name +
+        (collapsed ? ' (' + label.getAttribute('data-step-count') + ' steps)' : '');
+}
+
 document.addEventListener('DOMContentLoaded', function() {
     applyThreadColors();
 
+    document.querySelectorAll('.dag-group-label').forEach(function(label) {
+        var group = label.closest('.dag-group');
+        label.addEventListener('click', function() { toggleDagGroup(group); });
+        label.addEventListener('keydown', function(e) {
+            if (e.key === 'Enter' || e.key === ' ') {
+                e.preventDefault();
+                toggleDagGroup(group);
+            }
+        });
+    });
+
     var nodes = document.querySelectorAll('.dag-node');
     nodes.forEach(function(node) {
         node.addEventListener('mouseenter', function(e) {
@@ -76,6 +76,7 @@ type StepDetail struct {
 	Error              string                `json:"error,omitempty"`
 	FailureClass       string                `json:"failure_class,omitempty"`
 	Artifacts          []ArtifactSummary     `json:"artifacts,omitempty"`
+	ContractResults    []ContractOutcome     `json:"contract_results,omitempty"`
 	StepType           string                `json:"step_type,omitempty"`            // "conditional", "command", "gate", "pipeline", or ""
 	Script             string                `json:"script,omitempty"`               // Shell script for command steps
 	SubPipeline        string                `json:"sub_pipeline,omitempty"`         // Referenced pipeline for pipeline steps
@@ -143,6 +144,16 @@ type ArtifactSummary struct {
 	Preview   string `json:"preview,omitempty"`
 }
 
+// ContractOutcome is the result of one contract validation of a step.
+type ContractOutcome struct {
+	Type       string   `json:"type"`
+	Name       string   `json:"name,omitempty"`
+	Passed     bool     `json:"passed"`
+	Message    string   `json:"message,omitempty"`
+	Violations []string `json:"violations,omitempty"`
+	DurationMs int64    `json:"duration_ms"`
+}
+
 // ArtifactContentResponse is the JSON response for artifact content.
 type ArtifactContentResponse struct {
 	Content  string           `json:"content"`
@@ -151,11 +162,13 @@ type ArtifactContentResponse struct {
 
 // ArtifactMetadata holds metadata about an artifact file.
 type ArtifactMetadata struct {
-	Name      string `json:"name"`
-	Type      string `json:"type"`
-	SizeBytes int64  `json:"size_bytes"`
-	Truncated bool   `json:"truncated"`
-	MimeType  string `json:"mime_type"`
+	Name              string `json:"name"`
+	Type              string `json:"type"`
+	SizeBytes         int64  `json:"size_bytes"`
+	Truncated         bool   `json:"truncated"`
+	MimeType          string `json:"mime_type"`
+	SHA256            string `json:"sha256,omitempty"`
+	PreviewSuppressed bool   `json:"preview_suppressed,omitempty"`
 }
 
 // DAGData holds the data for rendering a pipeline DAG.
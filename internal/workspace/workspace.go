@@ -47,7 +47,7 @@ func resolveGitRoot() (string, error) {
 	if err != nil {
 		return os.Getwd()
 	}
-	return strings.TrimSpace(string(out)), nil
+	return filepath.FromSlash(strings.TrimSpace(string(out))), nil
 }
 
 func NewWorkspaceManager(baseDir string) (WorkspaceManager, error) {
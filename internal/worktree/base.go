@@ -0,0 +1,198 @@
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// fetchTimeout bounds the fetch of a missing base ref from origin.
+const fetchTimeout = 60 * time.Second
+
+// maxBaseCandidates caps the refs suggested when a base ref is missing.
+const maxBaseCandidates = 5
+
+// defaultBaseNames are suggested whenever they exist, since a base ref that
+// is missing is most often a default branch under another name.
+var defaultBaseNames = []string{"main", "master", "develop", "trunk"}
+
+// BaseRefError reports a worktree base ref that exists neither locally nor
+// on origin. It lists existing refs close to the requested one.
+type BaseRefError struct {
+	Base       string
+	Candidates []string
+	FetchErr   error // set when fetching from origin failed
+}
+
+// Error implements the error interface.
+func (e *BaseRefError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "base ref %q not found", e.Base)
+	if e.FetchErr != nil {
+		fmt.Fprintf(&b, " (fetch from origin failed: %v)", e.FetchErr)
+	} else {
+		b.WriteString(" locally or on origin")
+	}
+	if len(e.Candidates) > 0 {
+		fmt.Fprintf(&b, "; set workspace.base to an existing ref, e.g. %s", strings.Join(e.Candidates, ", "))
+	}
+	return b.String()
+}
+
+// Unwrap returns the fetch error for errors.Is/As support.
+func (e *BaseRefError) Unwrap() error {
+	return e.FetchErr
+}
+
+// ResolveBase checks that base names a commit. A ref missing from the local
+// clone is fetched from origin; if it then exists only as a remote-tracking
+// branch, that name is returned. Otherwise a *BaseRefError lists candidates.
+func (m *Manager) ResolveBase(base string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.resolveBase(base)
+}
+
+func (m *Manager) resolveBase(base string) (string, error) {
+	if m.commitExists(base) {
+		return base, nil
+	}
+
+	var fetchErr error
+	if m.hasOrigin() {
+		fetchErr = m.fetchFromOrigin(strings.TrimPrefix(base, "origin/"))
+		if m.commitExists(base) {
+			return base, nil
+		}
+		if remote := "origin/" + base; !strings.HasPrefix(base, "origin/") && m.commitExists(remote) {
+			return remote, nil
+		}
+	}
+
+	return "", &BaseRefError{Base: base, Candidates: m.baseCandidates(base), FetchErr: fetchErr}
+}
+
+// commitExists reports whether ref resolves to a commit.
+func (m *Manager) commitExists(ref string) bool {
+	cmd := exec.Command("git", "-C", m.repoRoot, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	return cmd.Run() == nil
+}
+
+// isShallow reports whether the repository is a shallow clone.
+func (m *Manager) isShallow() bool {
+	out, err := exec.Command("git", "-C", m.repoRoot, "rev-parse", "--is-shallow-repository").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// hasOrigin reports whether the repository has an origin remote.
+func (m *Manager) hasOrigin() bool {
+	cmd := exec.Command("git", "-C", m.repoRoot, "remote", "get-url", "origin")
+	return cmd.Run() == nil
+}
+
+// fetchFromOrigin fetches ref from origin as a branch into its
+// remote-tracking ref, or failing that as a tag. Refspecs are explicit
+// because single-branch and shallow clones only track one branch. In a
+// shallow clone only the tip is fetched, so resolving a base does not pull
+// in history the clone deliberately left out. Credential prompts are
+// disabled so an unattended run cannot hang.
+func (m *Manager) fetchFromOrigin(ref string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	args := []string{"-C", m.repoRoot, "fetch", "--quiet", "--no-tags"}
+	if m.isShallow() {
+		args = append(args, "--depth=1")
+	}
+
+	var err error
+	for _, refspec := range []string{
+		"+refs/heads/" + ref + ":refs/remotes/origin/" + ref,
+		"+refs/tags/" + ref + ":refs/tags/" + ref,
+	} {
+		cmd := exec.CommandContext(ctx, "git", append(args, "origin", refspec)...)
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		out, runErr := cmd.CombinedOutput()
+		if runErr == nil {
+			return nil
+		}
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			err = fmt.Errorf("%s", msg)
+		} else {
+			err = runErr
+		}
+	}
+	return err
+}
+
+// baseCandidates returns existing branches and tags resembling base,
+// followed by any default branches.
+func (m *Manager) baseCandidates(base string) []string {
+	out, err := exec.Command("git", "-C", m.repoRoot, "for-each-ref", "--format=%(refname:short)",
+		"refs/heads", "refs/remotes", "refs/tags").Output()
+	if err != nil {
+		return nil
+	}
+	refs := make(map[string]bool)
+	var ordered []string
+	for _, ref := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if ref == "" || ref == "origin" || strings.HasSuffix(ref, "/HEAD") {
+			continue
+		}
+		refs[ref] = true
+		ordered = append(ordered, ref)
+	}
+
+	var candidates []string
+	seen := make(map[string]bool)
+	add := func(ref string) {
+		if !seen[ref] && len(candidates) < maxBaseCandidates {
+			seen[ref] = true
+			candidates = append(candidates, ref)
+		}
+	}
+
+	want := strings.ToLower(refLeaf(base))
+	for _, ref := range ordered {
+		leaf := strings.ToLower(refLeaf(ref))
+		if strings.Contains(leaf, want) || (len(leaf) >= 3 && strings.Contains(want, leaf)) || editDistance(leaf, want) <= 2 {
+			add(ref)
+		}
+	}
+	for _, name := range defaultBaseNames {
+		for _, ref := range []string{name, "origin/" + name} {
+			if refs[ref] {
+				add(ref)
+			}
+		}
+	}
+	return candidates
+}
+
+// refLeaf returns the last slash-separated component of ref.
+func refLeaf(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,167 @@
+package worktree
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runGit(t *testing.T, args ...string) {
+	t.Helper()
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+func TestResolveBase_Existing(t *testing.T) {
+	dir := initTestRepo(t)
+	runGit(t, "-C", dir, "branch", "release-1.0")
+	mgr, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, base := range []string{"HEAD", "release-1.0"} {
+		got, err := mgr.ResolveBase(base)
+		if err != nil {
+			t.Fatalf("ResolveBase(%q) failed: %v", base, err)
+		}
+		if got != base {
+			t.Errorf("ResolveBase(%q) = %q, want it unchanged", base, got)
+		}
+	}
+}
+
+func TestResolveBase_FetchesFromOrigin(t *testing.T) {
+	origin := initTestRepo(t)
+	runGit(t, "-C", origin, "branch", "feature-x")
+
+	dir := initTestRepo(t)
+	runGit(t, "-C", dir, "remote", "add", "origin", origin)
+	mgr, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := mgr.ResolveBase("feature-x")
+	if err != nil {
+		t.Fatalf("ResolveBase failed: %v", err)
+	}
+	if got != "origin/feature-x" {
+		t.Errorf("expected the fetched remote-tracking branch, got %q", got)
+	}
+
+	// A worktree can now be created from the branch missing before the fetch.
+	worktreePath := filepath.Join(t.TempDir(), "fetched-wt")
+	if err := mgr.Create(worktreePath, "from-fetched", "feature-x"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	_ = mgr.Remove(worktreePath)
+}
+
+func TestResolveBase_MissingSuggestsCandidates(t *testing.T) {
+	dir := initTestRepo(t)
+	runGit(t, "-C", dir, "branch", "main")
+	runGit(t, "-C", dir, "branch", "release-2.0")
+	runGit(t, "-C", dir, "branch", "unrelated")
+	mgr, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = mgr.ResolveBase("relase-2.0")
+	var baseErr *BaseRefError
+	if !errors.As(err, &baseErr) {
+		t.Fatalf("expected *BaseRefError, got %v", err)
+	}
+	if len(baseErr.Candidates) == 0 || baseErr.Candidates[0] != "release-2.0" {
+		t.Errorf("expected release-2.0 as the first candidate, got %v", baseErr.Candidates)
+	}
+	for _, c := range baseErr.Candidates {
+		if c == "unrelated" {
+			t.Errorf("unrelated branch should not be suggested: %v", baseErr.Candidates)
+		}
+	}
+	if !strings.Contains(err.Error(), "main") || !strings.Contains(err.Error(), "workspace.base") {
+		t.Errorf("error should suggest default branches and the workspace.base field: %v", err)
+	}
+
+	// Create reports the same error instead of a raw git failure.
+	err = mgr.Create(filepath.Join(t.TempDir(), "wt"), "new-branch", "relase-2.0")
+	if !errors.As(err, &baseErr) {
+		t.Fatalf("Create: expected *BaseRefError, got %v", err)
+	}
+}
+
+func TestResolveBase_OriginWithoutRef(t *testing.T) {
+	origin := initTestRepo(t)
+	dir := initTestRepo(t)
+	runGit(t, "-C", dir, "remote", "add", "origin", origin)
+	mgr, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = mgr.ResolveBase("no-such-branch")
+	var baseErr *BaseRefError
+	if !errors.As(err, &baseErr) {
+		t.Fatalf("expected *BaseRefError, got %v", err)
+	}
+	if baseErr.FetchErr == nil {
+		t.Error("expected the failed fetch to be reported")
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"main", "main", 0},
+		{"mian", "main", 2},
+		{"master", "mater", 1},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestResolveBase_ShallowCloneStaysShallow(t *testing.T) {
+	origin := initTestRepo(t)
+	runGit(t, "-C", origin, "commit", "--allow-empty", "-m", "second")
+	dir := filepath.Join(t.TempDir(), "shallow")
+	runGit(t, "clone", "--quiet", "--depth=1", "file://"+origin, dir)
+	runGit(t, "-C", origin, "checkout", "--quiet", "-b", "late-branch")
+	runGit(t, "-C", origin, "commit", "--allow-empty", "-m", "third")
+
+	mgr, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mgr.isShallow() {
+		t.Fatal("expected a shallow clone")
+	}
+
+	got, err := mgr.ResolveBase("late-branch")
+	if err != nil {
+		t.Fatalf("ResolveBase failed: %v", err)
+	}
+	if got != "origin/late-branch" {
+		t.Errorf("expected origin/late-branch, got %q", got)
+	}
+	if !mgr.isShallow() {
+		t.Error("fetching a base should not unshallow the clone")
+	}
+
+	worktreePath := filepath.Join(t.TempDir(), "shallow-wt")
+	if err := mgr.Create(worktreePath, "work", got); err != nil {
+		t.Fatalf("Create in shallow clone failed: %v", err)
+	}
+	_ = mgr.Remove(worktreePath)
+}
@@ -24,7 +24,9 @@ func NewManager(repoRoot string) (*Manager, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to detect git repository root: %w", err)
 		}
-		repoRoot = strings.TrimSpace(string(out))
+		// git prints forward slashes on Windows ("C:/src/repo"); convert so
+		// paths joined from the root compare equal to filepath results.
+		repoRoot = filepath.FromSlash(strings.TrimSpace(string(out)))
 	}
 
 	// Verify it's a git repo
@@ -36,10 +38,43 @@ func NewManager(repoRoot string) (*Manager, error) {
 	return &Manager{repoRoot: repoRoot}, nil
 }
 
+// CreateOptions tunes how a worktree is checked out.
+type CreateOptions struct {
+	// Sparse limits the checkout to these paths. Plain paths are treated as
+	// directories relative to the repository root (cone mode); if any entry
+	// contains a glob character or a leading '!', all entries are used as
+	// gitignore-style patterns instead.
+	Sparse []string
+	// Submodules controls submodule checkout: "" or SubmodulesNone leaves
+	// them uninitialized, SubmodulesInit checks out top-level submodules and
+	// SubmodulesRecursive checks out nested ones too.
+	Submodules string
+	// LFS pulls Git LFS objects for the checked-out files, replacing pointer
+	// files with their content. Requires git-lfs.
+	LFS bool
+}
+
+// Submodule checkout modes for CreateOptions.Submodules.
+const (
+	SubmodulesNone      = "none"
+	SubmodulesInit      = "init"
+	SubmodulesRecursive = "recursive"
+)
+
 // Create creates a new git worktree at the given path.
 // If branch is specified and doesn't exist, it creates a new branch (from base if set, otherwise HEAD).
 // If branch is empty and base is set, creates a detached HEAD worktree at the base ref.
+// A base missing from the local clone is fetched from origin first; see ResolveBase.
 func (m *Manager) Create(worktreePath, branch, base string) error {
+	return m.CreateWithOptions(worktreePath, branch, base, CreateOptions{})
+}
+
+// CreateWithOptions creates a worktree like Create. With opts.Sparse set, the
+// worktree is added without a checkout, restricted with git sparse-checkout
+// (scoped to this worktree only), and then checked out, so files outside the
+// patterns are never written to disk. Submodules and LFS objects are then
+// fetched as opts requests.
+func (m *Manager) CreateWithOptions(worktreePath, branch, base string, opts CreateOptions) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -79,25 +114,106 @@ func (m *Manager) Create(worktreePath, branch, base string) error {
 		branchExists = m.branchExists(branch)
 	}
 
-	var cmd *exec.Cmd
+	// Validate the base before git does, so a stale clone gets a fetch and,
+	// failing that, an error naming usable refs.
+	if base != "" && !branchExists {
+		resolved, err := m.resolveBase(base)
+		if err != nil {
+			return err
+		}
+		base = resolved
+	}
+
+	args := []string{"-C", m.repoRoot, "worktree", "add"}
+	if len(opts.Sparse) > 0 {
+		args = append(args, "--no-checkout")
+	}
 	switch {
 	case branch == "" && base != "":
 		// Detached HEAD at base ref
-		cmd = exec.Command("git", "-C", m.repoRoot, "worktree", "add", "--detach", worktreePath, base)
+		args = append(args, "--detach", worktreePath, base)
 	case branchExists:
-		cmd = exec.Command("git", "-C", m.repoRoot, "worktree", "add", worktreePath, branch)
+		args = append(args, worktreePath, branch)
 	case base != "":
 		// New branch from specific base
-		cmd = exec.Command("git", "-C", m.repoRoot, "worktree", "add", "-b", branch, worktreePath, base)
+		args = append(args, "-b", branch, worktreePath, base)
 	default:
 		// New branch from HEAD (default behavior)
-		cmd = exec.Command("git", "-C", m.repoRoot, "worktree", "add", "-b", branch, worktreePath)
+		args = append(args, "-b", branch, worktreePath)
 	}
 
-	if out, err := cmd.CombinedOutput(); err != nil {
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
 		return fmt.Errorf("git worktree add failed: %w\noutput: %s", err, string(out))
 	}
 
+	if len(opts.Sparse) > 0 {
+		if err := sparseCheckout(worktreePath, opts.Sparse); err != nil {
+			return err
+		}
+	}
+
+	switch opts.Submodules {
+	case "", SubmodulesNone:
+	case SubmodulesInit, SubmodulesRecursive:
+		args := []string{"-C", worktreePath, "submodule", "update", "--init"}
+		if opts.Submodules == SubmodulesRecursive {
+			args = append(args, "--recursive")
+		}
+		if out, err := gitNoPrompt(args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("git submodule update failed: %w\noutput: %s", err, string(out))
+		}
+	default:
+		return fmt.Errorf("unknown submodules mode %q (valid: none, init, recursive)", opts.Submodules)
+	}
+
+	if opts.LFS {
+		if err := exec.Command("git", "lfs", "version").Run(); err != nil {
+			return fmt.Errorf("LFS pull requested but git-lfs is not installed: %w", err)
+		}
+		if out, err := gitNoPrompt("-C", worktreePath, "lfs", "pull").CombinedOutput(); err != nil {
+			return fmt.Errorf("git lfs pull failed: %w\noutput: %s", err, string(out))
+		}
+	}
+
+	return nil
+}
+
+// gitNoPrompt returns a git command that fails instead of prompting for
+// credentials, so an unattended run cannot hang on a private remote.
+func gitNoPrompt(args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	return cmd
+}
+
+// sparseCheckout restricts the freshly added, unpopulated worktree at
+// worktreePath to patterns and then populates it.
+func sparseCheckout(worktreePath string, patterns []string) error {
+	cone := true
+	for _, p := range patterns {
+		if strings.ContainsAny(p, "*?[") || strings.HasPrefix(p, "!") {
+			cone = false
+			break
+		}
+	}
+	mode := "--no-cone"
+	if cone {
+		mode = "--cone"
+	}
+	args := []string{"-C", worktreePath, "sparse-checkout", "set", mode, "--"}
+	for _, p := range patterns {
+		if cone {
+			// Cone mode takes directories relative to the root.
+			p = strings.Trim(p, "/")
+		}
+		args = append(args, p)
+	}
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("git sparse-checkout set failed: %w\noutput: %s", err, string(out))
+	}
+	if out, err := exec.Command("git", "-C", worktreePath, "checkout").CombinedOutput(); err != nil {
+		return fmt.Errorf("sparse worktree checkout failed: %w\noutput: %s", err, string(out))
+	}
 	return nil
 }
 
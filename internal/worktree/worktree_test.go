@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -335,3 +336,175 @@ func TestConcurrentWorktreeCreation(t *testing.T) {
 		_ = mgr.Remove(p)
 	}
 }
+
+// initLayeredRepo creates a repository with files in several directories.
+func initLayeredRepo(t *testing.T) string {
+	t.Helper()
+	dir := initTestRepo(t)
+	for _, f := range []string{"services/api/main.go", "services/web/app.js", "docs/guide.md", "vendor/lib/lib.go"} {
+		path := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", "layout"}} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestCreateWithOptions_Sparse(t *testing.T) {
+	tests := []struct {
+		name    string
+		sparse  []string
+		present []string
+		absent  []string
+	}{
+		{
+			name:    "cone directories",
+			sparse:  []string{"services/api", "/docs/"},
+			present: []string{"README.md", "services/api/main.go", "docs/guide.md"},
+			absent:  []string{"services/web/app.js", "vendor/lib/lib.go"},
+		},
+		{
+			name:    "gitignore patterns",
+			sparse:  []string{"/*", "!/*/", "/services/web/"},
+			present: []string{"README.md", "services/web/app.js"},
+			absent:  []string{"services/api/main.go", "docs/guide.md", "vendor/lib/lib.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := initLayeredRepo(t)
+			mgr, err := NewManager(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			worktreePath := filepath.Join(t.TempDir(), "sparse-wt")
+			if err := mgr.CreateWithOptions(worktreePath, "sparse", "", CreateOptions{Sparse: tt.sparse}); err != nil {
+				t.Fatalf("CreateWithOptions failed: %v", err)
+			}
+			defer func() { _ = mgr.Remove(worktreePath) }()
+
+			for _, f := range tt.present {
+				if _, err := os.Stat(filepath.Join(worktreePath, filepath.FromSlash(f))); err != nil {
+					t.Errorf("expected %s to be checked out: %v", f, err)
+				}
+			}
+			for _, f := range tt.absent {
+				if _, err := os.Stat(filepath.Join(worktreePath, filepath.FromSlash(f))); err == nil {
+					t.Errorf("expected %s to be outside the sparse checkout", f)
+				}
+			}
+
+			// Excluded files are not reported as deleted.
+			out, err := exec.Command("git", "-C", worktreePath, "status", "--porcelain").Output()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(out) != 0 {
+				t.Errorf("expected a clean sparse worktree, got:\n%s", out)
+			}
+
+			// The main checkout is not made sparse.
+			if _, err := os.Stat(filepath.Join(dir, "vendor", "lib", "lib.go")); err != nil {
+				t.Errorf("main worktree lost files: %v", err)
+			}
+		})
+	}
+}
+
+// addSubmodule commits sub as a submodule of dir at path.
+func addSubmodule(t *testing.T, dir, sub, path string) {
+	t.Helper()
+	for _, args := range [][]string{
+		{"-c", "protocol.file.allow=always", "submodule", "add", "--quiet", sub, path},
+		{"commit", "-m", "add " + path},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestCreateWithOptions_Submodules(t *testing.T) {
+	// Local submodule URLs use the file transport, which git disables for
+	// submodules by default.
+	t.Setenv("GIT_ALLOW_PROTOCOL", "file")
+
+	nested := initTestRepo(t)
+	lib := initTestRepo(t)
+	addSubmodule(t, lib, nested, "nested")
+	dir := initTestRepo(t)
+	addSubmodule(t, dir, lib, "lib")
+
+	tests := []struct {
+		mode       string
+		wantLib    bool
+		wantNested bool
+	}{
+		{SubmodulesNone, false, false},
+		{SubmodulesInit, true, false},
+		{SubmodulesRecursive, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			mgr, err := NewManager(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			worktreePath := filepath.Join(t.TempDir(), "sub-wt")
+			if err := mgr.CreateWithOptions(worktreePath, "sub-"+tt.mode, "", CreateOptions{Submodules: tt.mode}); err != nil {
+				t.Fatalf("CreateWithOptions failed: %v", err)
+			}
+			defer func() { _ = mgr.Remove(worktreePath) }()
+
+			_, err = os.Stat(filepath.Join(worktreePath, "lib", "README.md"))
+			if got := err == nil; got != tt.wantLib {
+				t.Errorf("lib checked out = %v, want %v", got, tt.wantLib)
+			}
+			_, err = os.Stat(filepath.Join(worktreePath, "lib", "nested", "README.md"))
+			if got := err == nil; got != tt.wantNested {
+				t.Errorf("nested checked out = %v, want %v", got, tt.wantNested)
+			}
+		})
+	}
+
+	mgr, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = mgr.CreateWithOptions(filepath.Join(t.TempDir(), "bad-wt"), "sub-bad", "", CreateOptions{Submodules: "deep"})
+	if err == nil || !strings.Contains(err.Error(), "unknown submodules mode") {
+		t.Errorf("expected unknown mode error, got %v", err)
+	}
+}
+
+func TestCreateWithOptions_LFS(t *testing.T) {
+	dir := initTestRepo(t)
+	mgr, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktreePath := filepath.Join(t.TempDir(), "lfs-wt")
+	err = mgr.CreateWithOptions(worktreePath, "lfs", "", CreateOptions{LFS: true})
+	defer func() { _ = mgr.Remove(worktreePath) }()
+
+	if exec.Command("git", "lfs", "version").Run() != nil {
+		if err == nil || !strings.Contains(err.Error(), "git-lfs is not installed") {
+			t.Errorf("expected a missing git-lfs error, got %v", err)
+		}
+		return
+	}
+	// A repository without LFS objects pulls nothing.
+	if err != nil {
+		t.Errorf("CreateWithOptions with LFS failed: %v", err)
+	}
+}